@@ -87,6 +87,26 @@ func NewBabylonController(
 		return nil, fmt.Errorf("failed to create babylon client: %w", err)
 	}
 
+	if cfg.KeyArmor != "" {
+		if err := importArmoredKey(bc.GetKeyring(), cfg.Key, cfg.KeyArmor, cfg.KeyArmorPassphrase); err != nil {
+			return nil, fmt.Errorf("failed to import babylon key: %w", err)
+		}
+	}
+
+	if cfg.RemoteSignerURL != "" {
+		kr, err := newRemoteSignerKeyring(cfg)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create remote signer keyring: %w", err)
+		}
+		// every babylon message and pop is signed through provider keybase
+		bc.Provider().Keybase = kr
+
+		logger.WithFields(logrus.Fields{
+			"url":   cfg.RemoteSignerURL,
+			"keyID": cfg.RemoteSignerKeyID,
+		}).Info("Using remote signer for babylon key")
+	}
+
 	// wrap to our type
 	client := &BabylonController{
 		bc,
@@ -113,6 +133,9 @@ type StakingTrackerResponse struct {
 	MinStakingValue           btcutil.Amount
 	MaxStakingValue           btcutil.Amount
 	AllowListExpirationHeight uint64
+	// Version is set only for params queried by version or btc height
+	Version             uint32
+	BtcActivationHeight uint32
 }
 
 // FinalityProviderInfo is a response from the finality provider tracker
@@ -294,6 +317,35 @@ func (bc *BabylonController) ParamsByVersion(version uint32) (*BtcStakingParams,
 	return &p, nil
 }
 
+// ParamsVersions is a helper function to query the babylon client for all
+// versions of the staking parameters
+func (bc *BabylonController) ParamsVersions() ([]BtcStakingParams, error) {
+	var versions []*StakingTrackerResponse
+	if err := retry.Do(func() error {
+		v, err := bc.QueryStakingTrackerVersions()
+		if err != nil {
+			return err
+		}
+		versions = v
+		return nil
+	}, RtyAtt, RtyDel, RtyErr, retry.OnRetry(func(n uint, err error) {
+		bc.logger.WithFields(logrus.Fields{
+			"attempt":      n + 1,
+			"max_attempts": RtyAttNum,
+			"error":        err,
+		}).Error("Failed to query babylon client for staking params versions")
+	})); err != nil {
+		return nil, fmt.Errorf("failed to get staking params versions after multiple retries: %w", err)
+	}
+
+	params := make([]BtcStakingParams, 0, len(versions))
+	for _, v := range versions {
+		params = append(params, BtcStakingParamsFromStakingTracker(v))
+	}
+
+	return params, nil
+}
+
 // GetKeyAddress is a helper function to get the key address
 func (bc *BabylonController) GetKeyAddress() sdk.AccAddress {
 	// get key address, retrieves address based on key name which is configured in
@@ -590,6 +642,43 @@ func delegationDataToMsgBtcStakeExpand(dg *DelegationData) (*btcstypes.MsgBtcSta
 	}, nil
 }
 
+// EncodedMsg is protobuf encoded Babylon message
+type EncodedMsg struct {
+	TypeURL string
+	Bytes   []byte
+}
+
+// EncodeDelegationMsg returns the exact message Delegate or ExpandDelegation
+// sends for the delegation data
+func EncodeDelegationMsg(dg *DelegationData) (*EncodedMsg, error) {
+	var (
+		msg interface {
+			sdk.Msg
+			Marshal() ([]byte, error)
+		}
+		err error
+	)
+
+	if dg != nil && dg.StakeExpansion != nil {
+		msg, err = delegationDataToMsgBtcStakeExpand(dg)
+	} else {
+		msg, err = delegationDataToMsg(dg)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	encoded, err := msg.Marshal()
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode delegation message: %w", err)
+	}
+
+	return &EncodedMsg{
+		TypeURL: sdk.MsgTypeURL(msg),
+		Bytes:   encoded,
+	}, nil
+}
+
 // ReliablySendMsgs sends a batch of messages to the Babylon node
 func (bc *BabylonController) reliablySendMsgs(
 	msgs []sdk.Msg,
@@ -695,6 +784,7 @@ func parseParams(params *btcstypes.Params) (*StakingTrackerResponse, error) {
 		MinStakingValue:           btcutil.Amount(params.MinStakingValueSat),
 		MaxStakingValue:           btcutil.Amount(params.MaxStakingValueSat),
 		AllowListExpirationHeight: params.AllowListExpirationHeight,
+		BtcActivationHeight:       params.BtcActivationHeight,
 	}, nil
 }
 
@@ -730,7 +820,13 @@ func (bc *BabylonController) QueryStakingTrackerByBtcHeight(btcHeight uint32) (*
 		return nil, fmt.Errorf("failed to query babylon params by btc height: %w", err)
 	}
 
-	return parseParams(&response.Params)
+	params, err := parseParams(&response.Params)
+	if err != nil {
+		return nil, err
+	}
+	params.Version = response.Version
+
+	return params, nil
 }
 
 // QueryStakingTrackerByVersion queries the staking tracker from the Babylon node
@@ -749,7 +845,48 @@ func (bc *BabylonController) QueryStakingTrackerByVersion(version uint32) (*Stak
 		return nil, fmt.Errorf("failed to query babylon params by version: %w", err)
 	}
 
-	return parseParams(&response.Params)
+	params, err := parseParams(&response.Params)
+	if err != nil {
+		return nil, err
+	}
+	params.Version = version
+
+	return params, nil
+}
+
+// QueryStakingTrackerVersions queries all params versions from the Babylon node
+func (bc *BabylonController) QueryStakingTrackerVersions() ([]*StakingTrackerResponse, error) {
+	clientCtx := client.Context{Client: bc.bbnClient.RPCClient}
+	queryClient := btcstypes.NewQueryClient(clientCtx)
+
+	var (
+		versions []*StakingTrackerResponse
+		nextKey  []byte
+	)
+	for {
+		ctx, cancel := getQueryContext(bc.cfg.Timeout)
+		response, err := queryClient.ParamsVersions(ctx, &btcstypes.QueryParamsVersionsRequest{
+			Pagination: &bq.PageRequest{Key: nextKey},
+		})
+		cancel()
+		if err != nil {
+			return nil, fmt.Errorf("failed to query babylon params versions: %w", err)
+		}
+
+		for i := range response.Params {
+			params, err := parseParams(&response.Params[i].Params)
+			if err != nil {
+				return nil, fmt.Errorf("invalid params version %d: %w", response.Params[i].Version, err)
+			}
+			params.Version = response.Params[i].Version
+			versions = append(versions, params)
+		}
+
+		if response.Pagination == nil || len(response.Pagination.NextKey) == 0 {
+			return versions, nil
+		}
+		nextKey = response.Pagination.NextKey
+	}
 }
 
 // QueryFinalityProviders queries the finality providers from the Babylon node
@@ -1005,6 +1142,12 @@ func (bc *BabylonController) QueryBTCDelegation(stakingTxHash *chainhash.Hash) (
 
 // GetUndelegationInfo returns the undelegation info from the response
 func (bc *BabylonController) GetUndelegationInfo(resp *btcstypes.QueryBTCDelegationResponse) (*UndelegationInfo, error) {
+	return UndelegationInfoFromResponse(resp)
+}
+
+// UndelegationInfoFromResponse parses undelegation info from the delegation
+// query response
+func UndelegationInfoFromResponse(resp *btcstypes.QueryBTCDelegationResponse) (*UndelegationInfo, error) {
 	if resp.BtcDelegation.GetUndelegationResponse() == nil {
 		return nil, fmt.Errorf("failed to get undelegation info from empty response")
 	}
@@ -1163,6 +1306,48 @@ func (bc *BabylonController) QueryBtcLightClientTipHeight() (uint32, error) {
 	return res.Header.Height, nil
 }
 
+// IsStakingTxAllowed checks whether staking transaction is in the allow list
+// of the btcstaking module. Allow list is not exposed through grpc queries, so
+// module store is queried directly.
+func (bc *BabylonController) IsStakingTxAllowed(stakingTxHash *chainhash.Hash) (bool, error) {
+	ctx, cancel := getQueryContext(bc.cfg.Timeout)
+	defer cancel()
+
+	prefix := btcstypes.AllowedStakingTxHashesKey.Bytes()
+	key := make([]byte, 0, len(prefix)+chainhash.HashSize)
+	key = append(key, prefix...)
+	key = append(key, stakingTxHash[:]...)
+
+	// allow list entries have empty values, so key query cannot tell missing key
+	// from existing one. Subspace query returns encoded list of matching pairs,
+	// which is empty only if there are no pairs.
+	res, err := bc.bbnClient.RPCClient.ABCIQuery(ctx, "/store/"+btcstypes.StoreKey+"/subspace", key)
+	if err != nil {
+		return false, fmt.Errorf("failed to query allow list: %w", err)
+	}
+
+	if !res.Response.IsOK() {
+		return false, fmt.Errorf("failed to query allow list: %s", res.Response.Log)
+	}
+
+	return len(res.Response.Value) > 0, nil
+}
+
+// SubmitInclusionProof submits proof of inclusion of the staking transaction in
+// the btc block, which activates verified delegation registered without it
+func (bc *BabylonController) SubmitInclusionProof(
+	stakingTxHash *chainhash.Hash,
+	inclusionBlock *wire.MsgBlock,
+	txIdx uint32,
+) (*bct.RelayerTxResponse, error) {
+	proof, err := generateSpvProof(inclusionBlock, txIdx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate inclusion proof: %w", err)
+	}
+
+	return bc.ActivateDelegation(*stakingTxHash, proof)
+}
+
 // ActivateDelegation activates a delegation
 // Test methods for e2e testing
 func (bc *BabylonController) ActivateDelegation(
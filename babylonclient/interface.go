@@ -13,6 +13,7 @@ import (
 	"github.com/btcsuite/btcd/chaincfg"
 	"github.com/btcsuite/btcd/chaincfg/chainhash"
 	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
 	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
 	sdk "github.com/cosmos/cosmos-sdk/types"
 )
@@ -67,6 +68,12 @@ type BtcStakingParams struct {
 
 	// AllowList expiration height
 	AllowListExpirationHeight uint64
+
+	// Version of the params, zero for current params returned by Params
+	Version uint32
+
+	// Btc height from which the params apply
+	BtcActivationHeight uint32
 }
 
 // SingleKeyCosmosKeyring represents a keyring that supports only one pritvate/public key pair
@@ -76,12 +83,15 @@ type SingleKeyKeyring interface {
 	GetPubKey() *secp256k1.PubKey
 }
 
+// BabylonClient is the babylon node client used by the staker app. Mock is
+// available in testutil/mocks.
 type BabylonClient interface {
 	SingleKeyKeyring
 	BTCCheckpointParams() (*BTCCheckpointParams, error)
 	Params() (*StakingParams, error)
 	ParamsByBtcHeight(btcHeight uint32) (*StakingParams, error)
 	ParamsByVersion(version uint32) (*BtcStakingParams, error)
+	ParamsVersions() ([]BtcStakingParams, error)
 	Delegate(dg *DelegationData) (*bct.RelayerTxResponse, error)
 	ExpandDelegation(dg *DelegationData) (*bct.RelayerTxResponse, error)
 	QueryFinalityProviders(limit uint64, offset uint64) (*FinalityProvidersClientResponse, error)
@@ -92,6 +102,8 @@ type BabylonClient interface {
 	GetUndelegationInfo(resp *btcstypes.QueryBTCDelegationResponse) (*UndelegationInfo, error)
 	GetLatestBlockHeight() (uint64, error)
 	QueryBtcLightClientTipHeight() (uint32, error)
+	IsStakingTxAllowed(stakingTxHash *chainhash.Hash) (bool, error)
+	SubmitInclusionProof(stakingTxHash *chainhash.Hash, inclusionBlock *wire.MsgBlock, txIdx uint32) (*bct.RelayerTxResponse, error)
 }
 
 func BtcStakingParamsFromStakingTracker(stakingTrackerParams *StakingTrackerResponse) BtcStakingParams {
@@ -108,6 +120,8 @@ func BtcStakingParamsFromStakingTracker(stakingTrackerParams *StakingTrackerResp
 		MinStakingValue:           stakingTrackerParams.MinStakingValue,
 		MaxStakingValue:           stakingTrackerParams.MaxStakingValue,
 		AllowListExpirationHeight: stakingTrackerParams.AllowListExpirationHeight,
+		Version:                   stakingTrackerParams.Version,
+		BtcActivationHeight:       stakingTrackerParams.BtcActivationHeight,
 	}
 }
 
@@ -132,6 +146,10 @@ func (m *MockBabylonClient) ParamsByVersion(_ uint32) (*BtcStakingParams, error)
 	return &m.ClientParams.BtcStakingParams, nil
 }
 
+func (m *MockBabylonClient) ParamsVersions() ([]BtcStakingParams, error) {
+	return []BtcStakingParams{m.ClientParams.BtcStakingParams}, nil
+}
+
 func (m *MockBabylonClient) BTCCheckpointParams() (*BTCCheckpointParams, error) {
 	return &BTCCheckpointParams{
 		ConfirmationTimeBlocks:    m.ClientParams.ConfirmationTimeBlocks,
@@ -278,3 +296,11 @@ func GetMockClient() *MockBabylonClient {
 func (m *MockBabylonClient) QueryBtcLightClientTipHeight() (uint32, error) {
 	return 0, nil
 }
+
+func (m *MockBabylonClient) IsStakingTxAllowed(_ *chainhash.Hash) (bool, error) {
+	return true, nil
+}
+
+func (m *MockBabylonClient) SubmitInclusionProof(_ *chainhash.Hash, _ *wire.MsgBlock, _ uint32) (*bct.RelayerTxResponse, error) {
+	return &bct.RelayerTxResponse{Code: 0}, nil
+}
@@ -0,0 +1,52 @@
+package babylonclient
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/babylonlabs-io/btc-staker/babylonclient/keyringcontroller"
+	"github.com/babylonlabs-io/btc-staker/babylonclient/remotesigner"
+	"github.com/babylonlabs-io/btc-staker/stakercfg"
+	"github.com/cosmos/cosmos-sdk/crypto/keyring"
+)
+
+// importArmoredKey imports ASCII armored private key into the keyring under
+// given name, unless key with this name is already there. It allows to keep
+// babylon key in secret store and use memory keyring, so that the key is
+// never written to disk.
+func importArmoredKey(kr keyring.Keyring, name, armor, passphrase string) error {
+	if _, err := kr.Key(name); err == nil {
+		return nil
+	}
+
+	if err := kr.ImportPrivKey(name, armor, passphrase); err != nil {
+		return fmt.Errorf("failed to import key %s: %w", name, err)
+	}
+
+	return nil
+}
+
+// newRemoteSignerKeyring creates keyring which exposes babylon key held by
+// remote signer under configured key name
+func newRemoteSignerKeyring(cfg *stakercfg.BBNConfig) (*remotesigner.Keyring, error) {
+	signer, err := remotesigner.NewHTTPSigner(&remotesigner.HTTPSignerConfig{
+		URL:        cfg.RemoteSignerURL,
+		KeyID:      cfg.RemoteSignerKeyID,
+		Token:      cfg.RemoteSignerToken,
+		CACert:     cfg.RemoteSignerCACert,
+		ClientCert: cfg.RemoteSignerClientCert,
+		ClientKey:  cfg.RemoteSignerClientKey,
+		Timeout:    cfg.RemoteSignerTimeout,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return remotesigner.NewKeyring(
+		context.Background(),
+		signer,
+		cfg.Key,
+		keyringcontroller.MakeCodec(),
+		cfg.RemoteSignerTimeout,
+	)
+}
@@ -7,9 +7,12 @@ import (
 	"sync"
 
 	bct "github.com/babylonlabs-io/babylon/v4/client/babylonclient"
+	btclctypes "github.com/babylonlabs-io/babylon/v4/x/btclightclient/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
 	"golang.org/x/sync/semaphore"
 
 	"github.com/babylonlabs-io/btc-staker/utils"
+	"github.com/babylonlabs-io/btc-staker/utils/faults"
 	"github.com/btcsuite/btcd/chaincfg/chainhash"
 	"github.com/sirupsen/logrus"
 )
@@ -18,6 +21,28 @@ var (
 	ErrBabylonBtcLightClientNotReady = errors.New("babylon btc light client is not ready to receive delegation")
 )
 
+// retryableErrors are errors of messages rejected by Babylon, which are
+// expected to be accepted when sent again later
+var retryableErrors = []error{
+	ErrBabylonBtcLightClientNotReady,
+	ErrHeaderNotKnownToBabylon,
+	ErrHeaderOnBabylonLCFork,
+	btclctypes.ErrHeaderDoesNotExist,
+	sdkerrors.ErrMempoolIsFull,
+	sdkerrors.ErrWrongSequence,
+}
+
+// IsRetryableError returns true if message rejected by Babylon with given
+// error can be sent again later
+func IsRetryableError(err error) bool {
+	for _, retryable := range retryableErrors {
+		if errors.Is(err, retryable) {
+			return true
+		}
+	}
+	return false
+}
+
 type sendDelegationRequest struct {
 	utils.Request[*bct.RelayerTxResponse]
 	dg                          *DelegationData
@@ -126,11 +151,16 @@ func (m *BabylonMsgSender) sendDelegationAsync(stakingTxHash *chainhash.Hash, re
 		)
 		// Route to appropriate bbn client method based on whether this is a stake expansion
 		// or a regular delegation.
-		if req.dg.StakeExpansion != nil {
-			txResp, err = m.cl.ExpandDelegation(req.dg)
+		switch {
+		case req.dg.StakeExpansion != nil:
 			useCase = "stake expansion delegation"
-		} else {
-			txResp, err = m.cl.Delegate(req.dg)
+			if err = faults.Inject(faults.BabylonBroadcast); err == nil {
+				txResp, err = m.cl.ExpandDelegation(req.dg)
+			}
+		default:
+			if err = faults.Inject(faults.BabylonBroadcast); err == nil {
+				txResp, err = m.cl.Delegate(req.dg)
+			}
 		}
 
 		if err != nil {
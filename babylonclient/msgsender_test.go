@@ -0,0 +1,58 @@
+package babylonclient_test
+
+import (
+	"testing"
+
+	bct "github.com/babylonlabs-io/babylon/v4/client/babylonclient"
+	"github.com/babylonlabs-io/btc-staker/babylonclient"
+	"github.com/babylonlabs-io/btc-staker/testutil/mocks"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func delegationWithInclusionProof() *babylonclient.DelegationData {
+	stakingTx := wire.NewMsgTx(2)
+	stakingTx.AddTxOut(wire.NewTxOut(10000, []byte{0x51}))
+
+	return &babylonclient.DelegationData{
+		StakingTransaction: stakingTx,
+		StakingTransactionInclusionInfo: &babylonclient.StakingTransactionInclusionInfo{
+			StakingTransactionInclusionBlockHash: &chainhash.Hash{1},
+		},
+	}
+}
+
+func startSender(t *testing.T, cl babylonclient.BabylonClient) *babylonclient.BabylonMsgSender {
+	sender := babylonclient.NewBabylonMsgSender(cl, logrus.New(), 1)
+	sender.Start()
+	t.Cleanup(sender.Stop)
+	return sender
+}
+
+func TestSendDelegationWaitsForBtcLightClient(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	cl := mocks.NewMockBabylonClient(ctrl)
+	dg := delegationWithInclusionProof()
+
+	cl.EXPECT().QueryHeaderDepth(dg.StakingTransactionInclusionInfo.StakingTransactionInclusionBlockHash).Return(uint32(1), nil)
+	// Delegate must not be called while light client is behind
+
+	_, err := startSender(t, cl).SendDelegation(dg, 2)
+	require.ErrorIs(t, err, babylonclient.ErrBabylonBtcLightClientNotReady)
+}
+
+func TestSendDelegationWhenInclusionBlockIsDeepEnough(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	cl := mocks.NewMockBabylonClient(ctrl)
+	dg := delegationWithInclusionProof()
+
+	cl.EXPECT().QueryHeaderDepth(gomock.Any()).Return(uint32(2), nil)
+	cl.EXPECT().Delegate(dg).Return(&bct.RelayerTxResponse{TxHash: "hash"}, nil)
+
+	resp, err := startSender(t, cl).SendDelegation(dg, 2)
+	require.NoError(t, err)
+	require.Equal(t, "hash", resp.TxHash)
+}
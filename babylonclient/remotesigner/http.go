@@ -0,0 +1,180 @@
+package remotesigner
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+)
+
+// HTTPSignerConfig is configuration of HTTPSigner
+type HTTPSignerConfig struct {
+	// URL is base url of the signing service
+	URL string
+	// KeyID is id of the key within signing service
+	KeyID string
+	// Token if not empty is sent as bearer token
+	Token string
+	// CACert is path to PEM encoded CA certificate of the signing service
+	CACert string
+	// ClientCert and ClientKey are paths to PEM encoded client certificate
+	// and key used for mutual tls
+	ClientCert string
+	ClientKey  string
+	// Timeout of a single request
+	Timeout time.Duration
+}
+
+// HTTPSigner is a Signer talking to signing service over http api:
+//
+//	GET  <url>/v1/keys/<key id>       -> {"pub_key": "<base64 compressed key>"}
+//	POST <url>/v1/keys/<key id>/sign  {"msg": "<base64>"} -> {"signature": "<base64 r||s>"}
+//
+// Errors are reported with non 2xx status and {"error": "<message>"} body.
+type HTTPSigner struct {
+	baseURL *url.URL
+	keyID   string
+	token   string
+	client  *http.Client
+}
+
+var _ Signer = (*HTTPSigner)(nil)
+
+// NewHTTPSigner returns signer for the given config
+func NewHTTPSigner(cfg *HTTPSignerConfig) (*HTTPSigner, error) {
+	baseURL, err := url.Parse(cfg.URL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid remote signer url: %w", err)
+	}
+
+	if cfg.KeyID == "" {
+		return nil, fmt.Errorf("remote signer key id must be set")
+	}
+
+	tlsCfg := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+	}
+
+	if cfg.CACert != "" {
+		pem, err := os.ReadFile(cfg.CACert)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read remote signer ca certificate: %w", err)
+		}
+
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in %s", cfg.CACert)
+		}
+		tlsCfg.RootCAs = pool
+	}
+
+	if cfg.ClientCert != "" || cfg.ClientKey != "" {
+		cert, err := tls.LoadX509KeyPair(cfg.ClientCert, cfg.ClientKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load remote signer client certificate: %w", err)
+		}
+		tlsCfg.Certificates = []tls.Certificate{cert}
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = tlsCfg
+
+	return &HTTPSigner{
+		baseURL: baseURL,
+		keyID:   cfg.KeyID,
+		token:   cfg.Token,
+		client: &http.Client{
+			Transport: transport,
+			Timeout:   cfg.Timeout,
+		},
+	}, nil
+}
+
+type pubKeyResponse struct {
+	PubKey []byte `json:"pub_key"`
+}
+
+type signRequest struct {
+	Msg []byte `json:"msg"`
+}
+
+type signResponse struct {
+	Signature []byte `json:"signature"`
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+// PubKey returns public key of the configured key
+func (s *HTTPSigner) PubKey(ctx context.Context) ([]byte, error) {
+	var resp pubKeyResponse
+	if err := s.call(ctx, http.MethodGet, s.baseURL.JoinPath("v1", "keys", s.keyID), nil, &resp); err != nil {
+		return nil, fmt.Errorf("failed to get public key: %w", err)
+	}
+
+	return resp.PubKey, nil
+}
+
+// Sign signs the message with the configured key
+func (s *HTTPSigner) Sign(ctx context.Context, msg []byte) ([]byte, error) {
+	var resp signResponse
+	if err := s.call(ctx, http.MethodPost, s.baseURL.JoinPath("v1", "keys", s.keyID, "sign"), &signRequest{Msg: msg}, &resp); err != nil {
+		return nil, fmt.Errorf("failed to sign message: %w", err)
+	}
+
+	return resp.Signature, nil
+}
+
+func (s *HTTPSigner) call(ctx context.Context, method string, u *url.URL, reqBody, respBody interface{}) error {
+	var body io.Reader
+	if reqBody != nil {
+		encoded, err := json.Marshal(reqBody)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(encoded)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), body)
+	if err != nil {
+		return err
+	}
+
+	if reqBody != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if s.token != "" {
+		req.Header.Set("Authorization", "Bearer "+s.token)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	respBytes, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		var errResp errorResponse
+		_ = json.Unmarshal(respBytes, &errResp)
+		return fmt.Errorf("remote signer returned status %d: %s", resp.StatusCode, errResp.Error)
+	}
+
+	if err := json.Unmarshal(respBytes, respBody); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,103 @@
+package remotesigner
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/cosmos/cosmos-sdk/codec"
+	"github.com/cosmos/cosmos-sdk/crypto/keyring"
+	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
+	"github.com/cosmos/cosmos-sdk/crypto/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/tx/signing"
+)
+
+// Keyring is a keyring holding only public key of the remote key under the
+// configured name. All other keyring operations are served by in memory
+// keyring, signing with the remote key is delegated to the remote signer.
+type Keyring struct {
+	keyring.Keyring
+
+	signer  Signer
+	keyName string
+	pubKey  *secp256k1.PubKey
+	timeout time.Duration
+}
+
+var _ keyring.Keyring = (*Keyring)(nil)
+
+// NewKeyring fetches public key from the remote signer and returns keyring
+// exposing it under keyName
+func NewKeyring(
+	ctx context.Context,
+	signer Signer,
+	keyName string,
+	cdc codec.Codec,
+	timeout time.Duration,
+) (*Keyring, error) {
+	fetchCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	pubKeyBytes, err := signer.PubKey(fetchCtx)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(pubKeyBytes) != secp256k1.PubKeySize {
+		return nil, fmt.Errorf("remote signer returned public key of invalid length %d", len(pubKeyBytes))
+	}
+
+	pubKey := &secp256k1.PubKey{Key: pubKeyBytes}
+
+	inner := keyring.NewInMemory(cdc)
+	if _, err := inner.SaveOfflineKey(keyName, pubKey); err != nil {
+		return nil, fmt.Errorf("failed to store remote public key: %w", err)
+	}
+
+	return &Keyring{
+		Keyring: inner,
+		signer:  signer,
+		keyName: keyName,
+		pubKey:  pubKey,
+		timeout: timeout,
+	}, nil
+}
+
+// Sign signs the message with the remote key. Only the remote key can be used
+// for signing.
+func (k *Keyring) Sign(uid string, msg []byte, _ signing.SignMode) ([]byte, types.PubKey, error) {
+	if uid != k.keyName {
+		return nil, nil, fmt.Errorf("key %s is not held by remote signer", uid)
+	}
+
+	return k.sign(msg)
+}
+
+// SignByAddress signs the message with the remote key if address matches it
+func (k *Keyring) SignByAddress(address sdk.Address, msg []byte, _ signing.SignMode) ([]byte, types.PubKey, error) {
+	if !bytes.Equal(k.pubKey.Address(), address.Bytes()) {
+		return nil, nil, fmt.Errorf("address %s is not held by remote signer", address)
+	}
+
+	return k.sign(msg)
+}
+
+func (k *Keyring) sign(msg []byte) ([]byte, types.PubKey, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), k.timeout)
+	defer cancel()
+
+	sig, err := k.signer.Sign(ctx, msg)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// broadcasting transaction with invalid signature would only waste fees,
+	// so misbehaving signer is caught here
+	if !k.pubKey.VerifySignature(msg, sig) {
+		return nil, nil, ErrInvalidSignature
+	}
+
+	return sig, k.pubKey, nil
+}
@@ -0,0 +1,103 @@
+package remotesigner_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/tx/signing"
+	"github.com/stretchr/testify/require"
+
+	"github.com/babylonlabs-io/btc-staker/babylonclient/keyringcontroller"
+	"github.com/babylonlabs-io/btc-staker/babylonclient/remotesigner"
+)
+
+// newTestSigner starts signing service holding privKey. If corrupt is true,
+// returned signatures are invalid.
+func newTestSigner(t *testing.T, privKey *secp256k1.PrivKey, corrupt bool) *remotesigner.HTTPSigner {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			_ = json.NewEncoder(w).Encode(map[string]string{"error": "unauthorized"})
+			return
+		}
+
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/v1/keys/staker":
+			_ = json.NewEncoder(w).Encode(map[string][]byte{"pub_key": privKey.PubKey().Bytes()})
+		case r.Method == http.MethodPost && r.URL.Path == "/v1/keys/staker/sign":
+			var req struct {
+				Msg []byte `json:"msg"`
+			}
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+
+			sig, err := privKey.Sign(req.Msg)
+			require.NoError(t, err)
+			if corrupt {
+				sig[0] ^= 0xff
+			}
+			_ = json.NewEncoder(w).Encode(map[string][]byte{"signature": sig})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	signer, err := remotesigner.NewHTTPSigner(&remotesigner.HTTPSignerConfig{
+		URL:     server.URL,
+		KeyID:   "staker",
+		Token:   "token",
+		Timeout: time.Second,
+	})
+	require.NoError(t, err)
+
+	return signer
+}
+
+func TestRemoteSignerKeyring(t *testing.T) {
+	privKey := secp256k1.GenPrivKey()
+	signer := newTestSigner(t, privKey, false)
+
+	kr, err := remotesigner.NewKeyring(context.Background(), signer, "staker-key", keyringcontroller.MakeCodec(), time.Second)
+	require.NoError(t, err)
+
+	record, err := kr.Key("staker-key")
+	require.NoError(t, err)
+	pubKey, err := record.GetPubKey()
+	require.NoError(t, err)
+	require.True(t, privKey.PubKey().Equals(pubKey))
+
+	msg := []byte("babylon message")
+	sig, signPubKey, err := kr.Sign("staker-key", msg, signing.SignMode_SIGN_MODE_DIRECT)
+	require.NoError(t, err)
+	require.True(t, privKey.PubKey().Equals(signPubKey))
+	require.True(t, privKey.PubKey().VerifySignature(msg, sig))
+
+	addr := sdk.AccAddress(privKey.PubKey().Address())
+	sig, _, err = kr.SignByAddress(addr, msg, signing.SignMode_SIGN_MODE_DIRECT)
+	require.NoError(t, err)
+	require.True(t, privKey.PubKey().VerifySignature(msg, sig))
+
+	_, _, err = kr.Sign("other-key", msg, signing.SignMode_SIGN_MODE_DIRECT)
+	require.Error(t, err)
+
+	otherAddr := sdk.AccAddress(secp256k1.GenPrivKey().PubKey().Address())
+	_, _, err = kr.SignByAddress(otherAddr, msg, signing.SignMode_SIGN_MODE_DIRECT)
+	require.Error(t, err)
+}
+
+func TestRemoteSignerInvalidSignature(t *testing.T) {
+	privKey := secp256k1.GenPrivKey()
+	signer := newTestSigner(t, privKey, true)
+
+	kr, err := remotesigner.NewKeyring(context.Background(), signer, "staker-key", keyringcontroller.MakeCodec(), time.Second)
+	require.NoError(t, err)
+
+	_, _, err = kr.Sign("staker-key", []byte("babylon message"), signing.SignMode_SIGN_MODE_DIRECT)
+	require.ErrorIs(t, err, remotesigner.ErrInvalidSignature)
+}
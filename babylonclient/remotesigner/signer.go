@@ -0,0 +1,22 @@
+// Package remotesigner allows to sign Babylon messages with a key held by an
+// external signing service, so that the key never touches the staker host.
+package remotesigner
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrInvalidSignature is returned when signature returned by remote signer
+// does not verify against its public key
+var ErrInvalidSignature = errors.New("remote signer returned invalid signature")
+
+// Signer is an external service holding secp256k1 Babylon key
+type Signer interface {
+	// PubKey returns 33 bytes compressed public key of the signing key
+	PubKey(ctx context.Context) ([]byte, error)
+
+	// Sign signs sha256 hash of the message and returns 64 bytes r||s
+	// signature in low-s form, as expected by cosmos secp256k1 keys
+	Sign(ctx context.Context, msg []byte) ([]byte, error)
+}
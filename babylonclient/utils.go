@@ -8,6 +8,17 @@ import (
 )
 
 func GenerateProof(block *wire.MsgBlock, txIdx uint32) ([]byte, error) {
+	proof, err := generateSpvProof(block, txIdx)
+	if err != nil {
+		return nil, err
+	}
+
+	return proof.MerkleNodes, nil
+}
+
+// generateSpvProof builds proof of inclusion of the transaction with given
+// index in the block
+func generateSpvProof(block *wire.MsgBlock, txIdx uint32) (*btcctypes.BTCSpvProof, error) {
 	headerBytes := babylontypes.NewBTCHeaderBytesFromBlockHeader(&block.Header)
 
 	var txsBytes [][]byte
@@ -21,11 +32,5 @@ func GenerateProof(block *wire.MsgBlock, txIdx uint32) ([]byte, error) {
 		txsBytes = append(txsBytes, bytes)
 	}
 
-	proof, err := btcctypes.SpvProofFromHeaderAndTransactions(&headerBytes, txsBytes, uint(txIdx))
-
-	if err != nil {
-		return nil, err
-	}
-
-	return proof.MerkleNodes, nil
+	return btcctypes.SpvProofFromHeaderAndTransactions(&headerBytes, txsBytes, uint(txIdx))
 }
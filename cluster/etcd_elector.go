@@ -0,0 +1,147 @@
+//go:build kvdb_etcd
+
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/lightningnetwork/lnd/kvdb/etcd"
+	"go.etcd.io/etcd/client/pkg/v3/transport"
+	clientv3 "go.etcd.io/etcd/client/v3"
+	"go.etcd.io/etcd/client/v3/concurrency"
+	"go.etcd.io/etcd/client/v3/namespace"
+)
+
+const etcdConnectionTimeout = 10 * time.Second
+
+// etcdLeaderElector is LeaderElector implemented using etcd election API
+type etcdLeaderElector struct {
+	id       string
+	cli      *clientv3.Client
+	session  *concurrency.Session
+	election *concurrency.Election
+}
+
+func newEtcdLeaderElector(
+	ctx context.Context,
+	id string,
+	electionPrefix string,
+	leaderSessionTTL int,
+	cfg *etcd.Config,
+) (*etcdLeaderElector, error) {
+	clientCfg := clientv3.Config{
+		Context:            ctx,
+		Endpoints:          strings.Split(cfg.Host, ","),
+		DialTimeout:        etcdConnectionTimeout,
+		Username:           cfg.User,
+		Password:           cfg.Pass,
+		MaxCallSendMsgSize: cfg.MaxMsgSize,
+	}
+
+	if !cfg.DisableTLS {
+		tlsInfo := transport.TLSInfo{
+			CertFile:           cfg.CertFile,
+			KeyFile:            cfg.KeyFile,
+			InsecureSkipVerify: cfg.InsecureSkipVerify,
+		}
+
+		tlsConfig, err := tlsInfo.ClientConfig()
+		if err != nil {
+			return nil, fmt.Errorf("failed to create etcd tls config: %w", err)
+		}
+
+		clientCfg.TLS = tlsConfig
+	}
+
+	cli, err := clientv3.New(clientCfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to etcd: %w", err)
+	}
+
+	// keep election keys in the same namespace as the database
+	cli.KV = namespace.NewKV(cli.KV, cfg.Namespace)
+	cli.Watcher = namespace.NewWatcher(cli.Watcher, cfg.Namespace)
+	cli.Lease = namespace.NewLease(cli.Lease, cfg.Namespace)
+
+	session, err := concurrency.NewSession(cli, concurrency.WithTTL(leaderSessionTTL))
+	if err != nil {
+		_ = cli.Close()
+		return nil, fmt.Errorf("failed to create etcd session: %w", err)
+	}
+
+	return &etcdLeaderElector{
+		id:       id,
+		cli:      cli,
+		session:  session,
+		election: concurrency.NewElection(session, electionPrefix),
+	}, nil
+}
+
+func (e *etcdLeaderElector) Campaign(ctx context.Context) error {
+	return e.election.Campaign(ctx, e.id)
+}
+
+func (e *etcdLeaderElector) Resign(ctx context.Context) error {
+	return e.election.Resign(ctx)
+}
+
+func (e *etcdLeaderElector) Leader(ctx context.Context) (string, error) {
+	resp, err := e.election.Leader(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	return string(resp.Kvs[0].Value), nil
+}
+
+// Term returns revision at which leader key of this instance was created.
+// Etcd revisions are strictly increasing, so every new leader has greater one.
+func (e *etcdLeaderElector) Term() uint64 {
+	return uint64(e.election.Rev())
+}
+
+func (e *etcdLeaderElector) Done() <-chan struct{} {
+	return e.session.Done()
+}
+
+func (e *etcdLeaderElector) Close() error {
+	if err := e.session.Close(); err != nil {
+		_ = e.cli.Close()
+		return err
+	}
+
+	return e.cli.Close()
+}
+
+// makeEtcdLeaderElector expects election prefix, leader session ttl in seconds
+// and etcd config as arguments
+func makeEtcdLeaderElector(ctx context.Context, id string, args ...interface{}) (LeaderElector, error) {
+	if len(args) != 3 {
+		return nil, fmt.Errorf("invalid number of arguments to etcd leader elector, expected: " +
+			"election prefix, leader session ttl, etcd config")
+	}
+
+	electionPrefix, ok := args[0].(string)
+	if !ok {
+		return nil, fmt.Errorf("invalid argument (0) to etcd leader elector, expected election prefix")
+	}
+
+	leaderSessionTTL, ok := args[1].(int)
+	if !ok {
+		return nil, fmt.Errorf("invalid argument (1) to etcd leader elector, expected leader session ttl")
+	}
+
+	cfg, ok := args[2].(*etcd.Config)
+	if !ok {
+		return nil, fmt.Errorf("invalid argument (2) to etcd leader elector, expected etcd config")
+	}
+
+	return newEtcdLeaderElector(ctx, id, electionPrefix, leaderSessionTTL, cfg)
+}
+
+func init() {
+	RegisterLeaderElectorFactory(EtcdLeaderElector, makeEtcdLeaderElector)
+}
@@ -0,0 +1,132 @@
+//go:build kvdb_etcd
+
+package cluster
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/lightningnetwork/lnd/kvdb/etcd"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testElectionPrefix = "/leader/"
+	testSessionTTL     = 2
+)
+
+func newTestEtcd(t *testing.T) *etcd.Config {
+	cfg, cleanup := etcd.NewTestEtcdInstance(t, t.TempDir())
+	t.Cleanup(cleanup)
+	cfg.DisableTLS = true
+
+	return cfg
+}
+
+func newTestEtcdElector(t *testing.T, cfg *etcd.Config, id string) *etcdLeaderElector {
+	elector, err := MakeLeaderElector(context.Background(), EtcdLeaderElector, id, testElectionPrefix, testSessionTTL, cfg)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = elector.Close()
+	})
+
+	return elector.(*etcdLeaderElector)
+}
+
+// campaign campaigns in background and returns channel receiving its result
+func campaign(elector LeaderElector) <-chan error {
+	errChan := make(chan error, 1)
+	go func() {
+		errChan <- elector.Campaign(context.Background())
+	}()
+
+	return errChan
+}
+
+func TestEtcdLeaderElectorElectAndResign(t *testing.T) {
+	cfg := newTestEtcd(t)
+	first := newTestEtcdElector(t, cfg, "first")
+	second := newTestEtcdElector(t, cfg, "second")
+
+	ctx := context.Background()
+	require.NoError(t, first.Campaign(ctx))
+
+	leader, err := second.Leader(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "first", leader)
+
+	// only one instance is elected at a time
+	secondElected := campaign(second)
+	select {
+	case err := <-secondElected:
+		t.Fatalf("second instance elected while first is leader: %v", err)
+	case <-time.After(time.Second):
+	}
+
+	require.NoError(t, first.Resign(ctx))
+
+	select {
+	case err := <-secondElected:
+		require.NoError(t, err)
+	case <-time.After(10 * time.Second):
+		t.Fatal("second instance not elected after first resigned")
+	}
+
+	leader, err = first.Leader(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "second", leader)
+	require.Greater(t, second.Term(), first.Term())
+}
+
+func TestEtcdLeaderElectorLosesLease(t *testing.T) {
+	cfg := newTestEtcd(t)
+	first := newTestEtcdElector(t, cfg, "first")
+	second := newTestEtcdElector(t, cfg, "second")
+
+	ctx := context.Background()
+	require.NoError(t, first.Campaign(ctx))
+	firstTerm := first.Term()
+
+	secondElected := campaign(second)
+
+	// lease of the leader session expires, e.g. because leader could not
+	// reach etcd in time
+	_, err := first.cli.Revoke(ctx, first.session.Lease())
+	require.NoError(t, err)
+
+	select {
+	case <-first.Done():
+	case <-time.After(10 * time.Second):
+		t.Fatal("leader not notified about lost lease")
+	}
+
+	select {
+	case err := <-secondElected:
+		require.NoError(t, err)
+	case <-time.After(10 * time.Second):
+		t.Fatal("second instance not elected after leader lost lease")
+	}
+
+	leader, err := second.Leader(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "second", leader)
+	require.Greater(t, second.Term(), firstTerm)
+}
+
+func TestMakeEtcdLeaderElectorArgs(t *testing.T) {
+	cfg := newTestEtcd(t)
+	ctx := context.Background()
+
+	_, err := MakeLeaderElector(ctx, EtcdLeaderElector, "id", testElectionPrefix, testSessionTTL)
+	require.ErrorContains(t, err, "invalid number of arguments")
+
+	_, err = MakeLeaderElector(ctx, EtcdLeaderElector, "id", testSessionTTL, testSessionTTL, cfg)
+	require.ErrorContains(t, err, "expected election prefix")
+
+	_, err = MakeLeaderElector(ctx, EtcdLeaderElector, "id", testElectionPrefix, "60", cfg)
+	require.ErrorContains(t, err, "expected leader session ttl")
+
+	_, err = MakeLeaderElector(ctx, EtcdLeaderElector, "id", testElectionPrefix, testSessionTTL, *cfg)
+	require.ErrorContains(t, err, "expected etcd config")
+}
@@ -0,0 +1,67 @@
+// Package cluster provides leader election, so that several staker instances
+// sharing replicated database can run with only one of them active.
+package cluster
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+const (
+	// EtcdLeaderElector is the type of leader elector backed by etcd
+	EtcdLeaderElector = "etcd"
+	// PostgresLeaderElector is the type of leader elector backed by postgres
+	// advisory lock
+	PostgresLeaderElector = "postgres"
+)
+
+// ErrLeaderElectorNotAvailable is returned when leader elector of given type
+// is not compiled in
+var ErrLeaderElectorNotAvailable = errors.New("leader elector not available")
+
+// LeaderElector is used to elect single active instance of staker among all
+// instances sharing the same database
+type LeaderElector interface {
+	// Campaign blocks until this instance becomes the leader or context is
+	// cancelled
+	Campaign(ctx context.Context) error
+
+	// Resign gives up leadership
+	Resign(ctx context.Context) error
+
+	// Leader returns id of the current leader
+	Leader(ctx context.Context) (string, error)
+
+	// Term returns fencing token of the won election. Every election won
+	// by any instance has greater term than the previous one.
+	Term() uint64
+
+	// Done is closed when leadership can no longer be held, e.g. because
+	// session with the backend expired
+	Done() <-chan struct{}
+
+	// Close releases resources of the elector
+	Close() error
+}
+
+type leaderElectorFactoryFunc func(ctx context.Context, id string, args ...interface{}) (LeaderElector, error)
+
+var leaderElectorFactories = make(map[string]leaderElectorFactoryFunc)
+
+// RegisterLeaderElectorFactory makes leader elector of given type available
+// for MakeLeaderElector
+func RegisterLeaderElectorFactory(electorType string, factory leaderElectorFactoryFunc) {
+	leaderElectorFactories[electorType] = factory
+}
+
+// MakeLeaderElector creates leader elector of given type. Arguments are
+// specific to the elector type.
+func MakeLeaderElector(ctx context.Context, electorType, id string, args ...interface{}) (LeaderElector, error) {
+	factory, ok := leaderElectorFactories[electorType]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrLeaderElectorNotAvailable, electorType)
+	}
+
+	return factory(ctx, id, args...)
+}
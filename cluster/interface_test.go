@@ -0,0 +1,15 @@
+package cluster
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMakeLeaderElectorNotAvailable(t *testing.T) {
+	t.Parallel()
+
+	_, err := MakeLeaderElector(context.Background(), "zookeeper", "id")
+	require.ErrorIs(t, err, ErrLeaderElectorNotAvailable)
+}
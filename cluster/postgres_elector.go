@@ -0,0 +1,224 @@
+//go:build kvdb_postgres
+
+package cluster
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"sync"
+	"time"
+
+	// registers pgx database/sql driver
+	_ "github.com/jackc/pgx/v4/stdlib"
+	"github.com/lightningnetwork/lnd/kvdb/postgres"
+)
+
+const (
+	postgresLockPollInterval = time.Second
+	postgresLivenessInterval = 5 * time.Second
+
+	createLeaderTableQuery = `CREATE TABLE IF NOT EXISTS staker_leader (
+		name TEXT PRIMARY KEY,
+		leader TEXT NOT NULL,
+		term BIGINT NOT NULL
+	)`
+
+	advanceTermQuery = `INSERT INTO staker_leader (name, leader, term) VALUES ($1, $2, 1)
+		ON CONFLICT (name) DO UPDATE SET leader = EXCLUDED.leader, term = staker_leader.term + 1
+		RETURNING term`
+)
+
+// postgresLeaderElector is LeaderElector implemented using postgres session
+// level advisory lock. Lock is held as long as the session lives, so elector
+// keeps one dedicated connection and gives up leadership once it breaks.
+type postgresLeaderElector struct {
+	id       string
+	lockName string
+	lockKey  int64
+	db       *sql.DB
+	conn     *sql.Conn
+	term     uint64
+
+	done      chan struct{}
+	doneOnce  sync.Once
+	quit      chan struct{}
+	closeOnce sync.Once
+	wg        sync.WaitGroup
+}
+
+func newPostgresLeaderElector(
+	ctx context.Context,
+	id string,
+	lockName string,
+	cfg *postgres.Config,
+) (*postgresLeaderElector, error) {
+	db, err := sql.Open("pgx", cfg.Dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open postgres connection: %w", err)
+	}
+
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("failed to connect to postgres: %w", err)
+	}
+
+	if _, err := conn.ExecContext(ctx, createLeaderTableQuery); err != nil {
+		_ = conn.Close()
+		_ = db.Close()
+		return nil, fmt.Errorf("failed to create leader table: %w", err)
+	}
+
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(lockName))
+
+	return &postgresLeaderElector{
+		id:       id,
+		lockName: lockName,
+		lockKey:  int64(h.Sum64()),
+		db:       db,
+		conn:     conn,
+		done:     make(chan struct{}),
+		quit:     make(chan struct{}),
+	}, nil
+}
+
+func (e *postgresLeaderElector) Campaign(ctx context.Context) error {
+	ticker := time.NewTicker(postgresLockPollInterval)
+	defer ticker.Stop()
+
+	for {
+		var acquired bool
+		err := e.conn.QueryRowContext(ctx, "SELECT pg_try_advisory_lock($1)", e.lockKey).Scan(&acquired)
+		if err != nil {
+			return fmt.Errorf("failed to acquire leader lock: %w", err)
+		}
+
+		if acquired {
+			break
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	var term int64
+	err := e.conn.QueryRowContext(ctx, advanceTermQuery, e.lockName, e.id).Scan(&term)
+	if err != nil {
+		_, _ = e.conn.ExecContext(ctx, "SELECT pg_advisory_unlock($1)", e.lockKey)
+		return fmt.Errorf("failed to advance leader term: %w", err)
+	}
+	e.term = uint64(term)
+
+	e.wg.Add(1)
+	go e.checkLiveness()
+
+	return nil
+}
+
+// checkLiveness signals Done once connection holding the lock breaks, as then
+// the lock may be acquired by other instance
+func (e *postgresLeaderElector) checkLiveness() {
+	defer e.wg.Done()
+
+	ticker := time.NewTicker(postgresLivenessInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			ctx, cancel := context.WithTimeout(context.Background(), postgresLivenessInterval)
+			err := e.conn.PingContext(ctx)
+			cancel()
+			if err != nil {
+				e.doneOnce.Do(func() { close(e.done) })
+				return
+			}
+		case <-e.quit:
+			return
+		}
+	}
+}
+
+func (e *postgresLeaderElector) stopLivenessCheck() {
+	e.closeOnce.Do(func() { close(e.quit) })
+	e.wg.Wait()
+}
+
+func (e *postgresLeaderElector) Resign(ctx context.Context) error {
+	e.stopLivenessCheck()
+
+	var released bool
+	err := e.conn.QueryRowContext(ctx, "SELECT pg_advisory_unlock($1)", e.lockKey).Scan(&released)
+	if err != nil {
+		return fmt.Errorf("failed to release leader lock: %w", err)
+	}
+
+	if !released {
+		return errors.New("leader lock was not held")
+	}
+
+	return nil
+}
+
+func (e *postgresLeaderElector) Leader(ctx context.Context) (string, error) {
+	var leader string
+	err := e.conn.QueryRowContext(ctx, "SELECT leader FROM staker_leader WHERE name = $1", e.lockName).Scan(&leader)
+	if err != nil {
+		return "", err
+	}
+
+	return leader, nil
+}
+
+// Term returns term stored in leader table, which is incremented on every
+// won election
+func (e *postgresLeaderElector) Term() uint64 {
+	return e.term
+}
+
+func (e *postgresLeaderElector) Done() <-chan struct{} {
+	return e.done
+}
+
+func (e *postgresLeaderElector) Close() error {
+	e.stopLivenessCheck()
+
+	// closing the session releases the lock if it is still held
+	if err := e.conn.Close(); err != nil {
+		_ = e.db.Close()
+		return err
+	}
+
+	return e.db.Close()
+}
+
+// makePostgresLeaderElector expects lock name and postgres config as arguments
+func makePostgresLeaderElector(ctx context.Context, id string, args ...interface{}) (LeaderElector, error) {
+	if len(args) != 2 {
+		return nil, fmt.Errorf("invalid number of arguments to postgres leader elector, expected: " +
+			"lock name, postgres config")
+	}
+
+	lockName, ok := args[0].(string)
+	if !ok {
+		return nil, fmt.Errorf("invalid argument (0) to postgres leader elector, expected lock name")
+	}
+
+	cfg, ok := args[1].(*postgres.Config)
+	if !ok {
+		return nil, fmt.Errorf("invalid argument (1) to postgres leader elector, expected postgres config")
+	}
+
+	return newPostgresLeaderElector(ctx, id, lockName, cfg)
+}
+
+func init() {
+	RegisterLeaderElectorFactory(PostgresLeaderElector, makePostgresLeaderElector)
+}
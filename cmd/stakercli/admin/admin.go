@@ -6,8 +6,10 @@ import (
 	"path"
 
 	babylonApp "github.com/babylonlabs-io/babylon/v4/app"
+	cl "github.com/babylonlabs-io/btc-staker/babylonclient"
 	"github.com/babylonlabs-io/btc-staker/stakercfg"
 	"github.com/babylonlabs-io/btc-staker/stakerdb"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
 	"github.com/cosmos/cosmos-sdk/crypto/hd"
 	"github.com/cosmos/cosmos-sdk/crypto/keyring"
 	"github.com/cosmos/go-bip39"
@@ -25,6 +27,12 @@ var AdminCommands = []cli.Command{
 			dumpCfgCommand,
 			createCosmosKeyringCommand,
 			migrateTrackedTransactionsCommand,
+			migrateStakerAddressesCommand,
+			cleanUnbondingSignaturesCommand,
+			dbCommand,
+			mergeDBCommand,
+			exportSnapshotCommand,
+			bootstrapCommand,
 		},
 	},
 }
@@ -244,6 +252,10 @@ func migrateTrackedTransactions(*cli.Context) error {
 		return fmt.Errorf("failed to create tracked transaction store: %w", err)
 	}
 
+	if err := setIntegrityKey(store, config); err != nil {
+		return err
+	}
+
 	// Perform migration
 	result, err := store.MigrateTrackedTransactions()
 	if err != nil {
@@ -255,3 +267,130 @@ func migrateTrackedTransactions(*cli.Context) error {
 
 	return nil
 }
+
+// setIntegrityKey sets integrity key from configuration, so that records of
+// database signed by the daemon can be rewritten
+func setIntegrityKey(store *stakerdb.TrackedTransactionStore, config *stakercfg.Config) error {
+	key, err := config.DBConfig.IntegrityPrivateKey()
+	if err != nil {
+		return err
+	}
+
+	if key == nil {
+		return nil
+	}
+
+	return store.SetIntegrityKey(key)
+}
+
+var migrateStakerAddressesCommand = cli.Command{
+	Name:      "migrate-staker-addresses",
+	ShortName: "msa",
+	Usage:     "Convert staker addresses of tracked transactions to the network from staker configuration",
+	Description: "Staker refuses to spend or unbond delegations whose staker address does not belong to the configured network. " +
+		"This command converts addresses stored while staker ran on other network to the configured one, " +
+		"keeping the same output script, and binds the database to the configured network. " +
+		"Run it only if the database was deliberately moved between networks.",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  configFileDirFlag,
+			Usage: "Path to staker configuration file",
+			Value: defaultConfigPath,
+		},
+	},
+	Action: migrateStakerAddresses,
+}
+
+func migrateStakerAddresses(*cli.Context) error {
+	config, _, _, err := stakercfg.LoadConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	db, err := stakercfg.GetDBBackend(config.DBConfig)
+	if err != nil {
+		return fmt.Errorf("failed to open database: %w", err)
+	}
+	defer db.Close()
+
+	store, err := stakerdb.NewTrackedTransactionStore(db)
+	if err != nil {
+		return fmt.Errorf("failed to create tracked transaction store: %w", err)
+	}
+
+	if err := setIntegrityKey(store, config); err != nil {
+		return err
+	}
+
+	result, err := store.MigrateStakerAddressesToNet(&config.ActiveNetParams)
+	if err != nil {
+		return fmt.Errorf("migration failed: %w", err)
+	}
+
+	fmt.Printf("Migration to %s complete. %s\n", config.ActiveNetParams.Name, result.String())
+
+	return nil
+}
+
+var cleanUnbondingSignaturesCommand = cli.Command{
+	Name:      "clean-unbonding-signatures",
+	ShortName: "cus",
+	Usage:     "Remove duplicate and non-committee covenant signatures from stored unbonding signatures",
+	Description: "Stored covenant signatures of unbonding transactions must contain at most one signature per covenant " +
+		"committee member, and only members of the committee of the params version of the delegation. " +
+		"This command removes signatures violating it from signatures stored by older versions of staker. " +
+		"Covenant committees of signatures stored without them are queried from Babylon node from staker configuration. " +
+		"Signatures which no longer meet covenant quorum are removed, unbonding always uses signatures queried from Babylon.",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  configFileDirFlag,
+			Usage: "Path to staker configuration file",
+			Value: defaultConfigPath,
+		},
+	},
+	Action: cleanUnbondingSignatures,
+}
+
+func cleanUnbondingSignatures(*cli.Context) error {
+	config, logger, zapLogger, err := stakercfg.LoadConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	babylonClient, err := cl.NewBabylonController(config.BabylonConfig, &config.ActiveNetParams, logger, zapLogger)
+	if err != nil {
+		return fmt.Errorf("failed to create babylon client: %w", err)
+	}
+
+	db, err := stakercfg.GetDBBackend(config.DBConfig)
+	if err != nil {
+		return fmt.Errorf("failed to open database: %w", err)
+	}
+	defer db.Close()
+
+	store, err := stakerdb.NewTrackedTransactionStore(db)
+	if err != nil {
+		return fmt.Errorf("failed to create tracked transaction store: %w", err)
+	}
+
+	result, err := store.CleanUnbondingSignatures(func(paramsVersion uint32) ([][]byte, error) {
+		params, err := babylonClient.ParamsByVersion(paramsVersion)
+		if err != nil {
+			return nil, err
+		}
+
+		committee := make([][]byte, 0, len(params.CovenantPks))
+		for _, pk := range params.CovenantPks {
+			committee = append(committee, schnorr.SerializePubKey(pk))
+		}
+
+		return committee, nil
+	})
+	if err != nil {
+		return fmt.Errorf("migration failed: %w", err)
+	}
+
+	fmt.Printf("Cleaning of unbonding signatures complete. %s\n", result.String())
+
+	return nil
+}
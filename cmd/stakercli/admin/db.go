@@ -0,0 +1,356 @@
+package admin
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math"
+	"path/filepath"
+	"time"
+
+	"github.com/babylonlabs-io/btc-staker/cmd/stakercli/helpers"
+	"github.com/babylonlabs-io/btc-staker/stakercfg"
+	"github.com/babylonlabs-io/btc-staker/stakerdb"
+	"github.com/babylonlabs-io/btc-staker/utils"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/lightningnetwork/lnd/kvdb"
+	"github.com/urfave/cli"
+)
+
+const (
+	dbPathFlag     = "db-path"
+	dbFileNameFlag = "db-file-name"
+	dbTimeoutFlag  = "db-timeout"
+	offsetFlag     = "offset"
+	limitFlag      = "limit"
+	txHashFlag     = "staking-transaction-hash"
+)
+
+var (
+	defaultDBConfig = stakercfg.DefaultDBConfig()
+
+	dbFlags = []cli.Flag{
+		cli.StringFlag{
+			Name:  dbPathFlag,
+			Usage: "Directory of the staker database",
+			Value: defaultDBConfig.DBPath,
+		},
+		cli.StringFlag{
+			Name:  dbFileNameFlag,
+			Usage: "Name of the staker database file",
+			Value: defaultDBConfig.DBFileName,
+		},
+		cli.DurationFlag{
+			Name:  dbTimeoutFlag,
+			Usage: "How long to wait for the database lock, which is held by running stakerd",
+			Value: 5 * time.Second,
+		},
+	}
+)
+
+var dbCommand = cli.Command{
+	Name:  "db",
+	Usage: "Inspect staker database directly. Staker daemon must be stopped.",
+	Subcommands: []cli.Command{
+		{
+			Name:  "dump",
+			Usage: "Print decoded tracked transactions",
+			Flags: append([]cli.Flag{
+				cli.Uint64Flag{
+					Name:  offsetFlag,
+					Usage: "index of the first transaction to print",
+				},
+				cli.Uint64Flag{
+					Name:  limitFlag,
+					Usage: "maximum number of transactions to print, all if 0",
+				},
+			}, dbFlags...),
+			Action: dbDump,
+		},
+		{
+			Name:  "get",
+			Usage: "Print decoded tracked transaction with its staking transaction",
+			Flags: append([]cli.Flag{
+				cli.StringFlag{
+					Name:     txHashFlag,
+					Usage:    "Hash of the staking transaction",
+					Required: true,
+				},
+			}, dbFlags...),
+			Action: dbGet,
+		},
+		{
+			Name:        "stats",
+			Usage:       "Print bucket sizes and index health",
+			Description: "Exits with non-zero code if index inconsistencies are found.",
+			Flags:       dbFlags,
+			Action:      dbStats,
+		},
+	},
+}
+
+type DBTxFailure struct {
+	Reason    string `json:"reason"`
+	Timestamp string `json:"timestamp"`
+}
+
+type DBTxFees struct {
+	StakingFee    string `json:"staking_fee"`
+	UnbondingFee  string `json:"unbonding_fee"`
+	WithdrawalFee string `json:"withdrawal_fee"`
+}
+
+type DBWatchedTx struct {
+	TxHash       string `json:"tx_hash"`
+	Kind         string `json:"kind"`
+	BroadcastAt  string `json:"broadcast_at"`
+	Rebroadcasts uint32 `json:"rebroadcasts"`
+}
+
+type DBTransaction struct {
+	Index          uint64        `json:"index"`
+	StakingTxHash  string        `json:"staking_tx_hash"`
+	StakerAddress  string        `json:"staker_address"`
+	StakingTxHex   string        `json:"staking_tx_hex,omitempty"`
+	CreationHeight *uint32       `json:"creation_height,omitempty"`
+	Failure        *DBTxFailure  `json:"failure,omitempty"`
+	Fees           DBTxFees      `json:"fees"`
+	Watched        []DBWatchedTx `json:"watched_transactions,omitempty"`
+}
+
+type DBDumpResponse struct {
+	Total        uint64          `json:"total"`
+	Transactions []DBTransaction `json:"transactions"`
+}
+
+type DBBucketStats struct {
+	Name  string `json:"name"`
+	Keys  int    `json:"keys"`
+	Bytes int    `json:"bytes"`
+}
+
+type DBIndexHealth struct {
+	Healthy                 bool     `json:"healthy"`
+	TrackedTransactions     uint64   `json:"tracked_transactions"`
+	IndexEntries            uint64   `json:"index_entries"`
+	QuarantinedTransactions uint64   `json:"quarantined_transactions"`
+	NumTxCounter            uint64   `json:"num_tx_counter"`
+	MissingTransactions     []string `json:"missing_transactions,omitempty"`
+	UndecodableTransactions []string `json:"undecodable_transactions,omitempty"`
+	UnindexedTransactions   []string `json:"unindexed_transactions,omitempty"`
+	DanglingInputs          []string `json:"dangling_inputs,omitempty"`
+	IntegrityFailures       []string `json:"integrity_failures,omitempty"`
+}
+
+type DBStatsResponse struct {
+	Path        string          `json:"path"`
+	Buckets     []DBBucketStats `json:"buckets"`
+	IndexHealth DBIndexHealth   `json:"index_health"`
+}
+
+// openDBForInspection opens existing database file. Unlike stakerd it never
+// creates the file, and fails fast if database is locked by running daemon.
+func openDBForInspection(c *cli.Context) (kvdb.Backend, string, error) {
+	dbFilePath := filepath.Join(c.String(dbPathFlag), c.String(dbFileNameFlag))
+
+	if !stakercfg.FileExists(dbFilePath) {
+		return nil, "", cli.NewExitError(fmt.Sprintf("database file %s does not exist", dbFilePath), helpers.ExitCodeInvalidArgs)
+	}
+
+	db, err := kvdb.Open(
+		kvdb.BoltBackendName, dbFilePath,
+		defaultDBConfig.NoFreelistSync, c.Duration(dbTimeoutFlag),
+	)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to open database %s, make sure stakerd is not running: %w", dbFilePath, err)
+	}
+
+	return db, dbFilePath, nil
+}
+
+func toDBTransaction(
+	store *stakerdb.TrackedTransactionStore,
+	tx *stakerdb.StoredTransaction,
+	withTxHex bool,
+) (*DBTransaction, error) {
+	txHash := tx.StakingTx.TxHash()
+
+	dbTx := &DBTransaction{
+		Index:         tx.StoredTransactionIdx,
+		StakingTxHash: txHash.String(),
+		StakerAddress: tx.StakerAddress,
+	}
+
+	if withTxHex {
+		serialized, err := utils.SerializeBtcTransaction(tx.StakingTx)
+		if err != nil {
+			return nil, err
+		}
+		dbTx.StakingTxHex = hex.EncodeToString(serialized)
+	}
+
+	height, found, err := store.GetTransactionCreationHeight(&txHash)
+	if err != nil {
+		return nil, err
+	}
+	if found {
+		dbTx.CreationHeight = &height
+	}
+
+	failure, err := store.GetTransactionFailure(&txHash)
+	if err != nil {
+		return nil, err
+	}
+	if failure != nil {
+		dbTx.Failure = &DBTxFailure{
+			Reason:    failure.Reason,
+			Timestamp: failure.Timestamp.UTC().Format(time.RFC3339),
+		}
+	}
+
+	fees, err := store.GetDelegationFees(&txHash)
+	if err != nil {
+		return nil, err
+	}
+	dbTx.Fees = DBTxFees{
+		StakingFee:    fees.StakingFee.String(),
+		UnbondingFee:  fees.UnbondingFee.String(),
+		WithdrawalFee: fees.WithdrawalFee.String(),
+	}
+
+	watched, err := store.WatchedTransactionsOf(&txHash)
+	if err != nil {
+		return nil, err
+	}
+	for _, w := range watched {
+		dbTx.Watched = append(dbTx.Watched, DBWatchedTx{
+			TxHash:       w.Tx.TxHash().String(),
+			Kind:         w.Kind.String(),
+			BroadcastAt:  w.BroadcastAt.UTC().Format(time.RFC3339),
+			Rebroadcasts: w.Rebroadcasts,
+		})
+	}
+
+	return dbTx, nil
+}
+
+func dbDump(c *cli.Context) error {
+	db, _, err := openDBForInspection(c)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	store := stakerdb.NewInspectionStore(db)
+
+	query := stakerdb.DefaultStoredTransactionQuery()
+	query.IndexOffset = c.Uint64(offsetFlag)
+	query.NumMaxTransactions = c.Uint64(limitFlag)
+	if query.NumMaxTransactions == 0 {
+		query.NumMaxTransactions = math.MaxUint64
+	}
+
+	result, err := store.QueryStoredTransactions(query)
+	if err != nil {
+		return err
+	}
+
+	resp := DBDumpResponse{
+		Total:        result.Total,
+		Transactions: []DBTransaction{},
+	}
+
+	for i := range result.Transactions {
+		dbTx, err := toDBTransaction(store, &result.Transactions[i], false)
+		if err != nil {
+			return err
+		}
+		resp.Transactions = append(resp.Transactions, *dbTx)
+	}
+
+	helpers.PrintRespJSON(resp)
+	return nil
+}
+
+func dbGet(c *cli.Context) error {
+	txHash, err := chainhash.NewHashFromStr(c.String(txHashFlag))
+	if err != nil {
+		return cli.NewExitError(fmt.Sprintf("invalid staking transaction hash: %v", err), helpers.ExitCodeInvalidArgs)
+	}
+
+	db, _, err := openDBForInspection(c)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	store := stakerdb.NewInspectionStore(db)
+
+	storedTx, err := store.GetTransaction(txHash)
+	if errors.Is(err, stakerdb.ErrTransactionNotFound) {
+		return cli.NewExitError(fmt.Sprintf("transaction %s is not tracked", txHash), helpers.ExitCodeError)
+	}
+	if err != nil {
+		return err
+	}
+
+	dbTx, err := toDBTransaction(store, storedTx, true)
+	if err != nil {
+		return err
+	}
+
+	helpers.PrintRespJSON(dbTx)
+	return nil
+}
+
+func dbStats(c *cli.Context) error {
+	db, dbFilePath, err := openDBForInspection(c)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	store := stakerdb.NewInspectionStore(db)
+
+	buckets, err := store.BucketStats()
+	if err != nil {
+		return err
+	}
+
+	health, err := store.CheckIndexHealth()
+	if err != nil {
+		return err
+	}
+
+	resp := DBStatsResponse{
+		Path: dbFilePath,
+		IndexHealth: DBIndexHealth{
+			Healthy:                 health.Healthy(),
+			TrackedTransactions:     health.TrackedTransactions,
+			IndexEntries:            health.IndexEntries,
+			QuarantinedTransactions: health.QuarantinedTransactions,
+			NumTxCounter:            health.NumTxCounter,
+			MissingTransactions:     health.MissingTransactions,
+			UndecodableTransactions: health.UndecodableTransactions,
+			UnindexedTransactions:   health.UnindexedTransactions,
+			DanglingInputs:          health.DanglingInputs,
+			IntegrityFailures:       health.IntegrityFailures,
+		},
+	}
+
+	for _, b := range buckets {
+		resp.Buckets = append(resp.Buckets, DBBucketStats{
+			Name:  b.Name,
+			Keys:  b.Keys,
+			Bytes: b.Bytes,
+		})
+	}
+
+	helpers.PrintRespJSON(resp)
+
+	if !health.Healthy() {
+		return cli.NewExitError("database index is not healthy", helpers.ExitCodeError)
+	}
+
+	return nil
+}
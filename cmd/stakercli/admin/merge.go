@@ -0,0 +1,106 @@
+package admin
+
+import (
+	"fmt"
+
+	"github.com/babylonlabs-io/btc-staker/cmd/stakercli/helpers"
+	"github.com/babylonlabs-io/btc-staker/stakercfg"
+	"github.com/babylonlabs-io/btc-staker/stakerdb"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/lightningnetwork/lnd/kvdb"
+	"github.com/urfave/cli"
+)
+
+const (
+	sourceDBFileFlag = "source-db-file"
+	dryRunFlag       = "dry-run"
+)
+
+var mergeDBCommand = cli.Command{
+	Name:      "merge-db",
+	ShortName: "mdb",
+	Usage:     "Merge tracked transactions of other staker database into the primary one",
+	Description: "Transactions of the source database are added under new indexes of the primary database, " +
+		"together with their fees, tenants, labels, history and watched transactions. Transactions tracked by " +
+		"both databases keep data of the primary one, transactions spending inputs reserved by the primary " +
+		"database are skipped. Both staker daemons must be stopped.",
+	Flags: append([]cli.Flag{
+		cli.StringFlag{
+			Name:     sourceDBFileFlag,
+			Usage:    "Path to the database file to merge from",
+			Required: true,
+		},
+		cli.BoolFlag{
+			Name:  dryRunFlag,
+			Usage: "Only print what would be merged, without modifying the primary database",
+		},
+	}, dbFlags...),
+	Action: mergeDB,
+}
+
+type DBMergeResponse struct {
+	Path           string   `json:"path"`
+	SourcePath     string   `json:"source_path"`
+	DryRun         bool     `json:"dry_run"`
+	Merged         []string `json:"merged"`
+	AlreadyTracked []string `json:"already_tracked"`
+	Conflicting    []string `json:"conflicting"`
+	Undecodable    []string `json:"undecodable,omitempty"`
+}
+
+func mergeDB(c *cli.Context) error {
+	sourcePath := c.String(sourceDBFileFlag)
+	if !stakercfg.FileExists(sourcePath) {
+		return cli.NewExitError(fmt.Sprintf("database file %s does not exist", sourcePath), helpers.ExitCodeInvalidArgs)
+	}
+
+	db, dbFilePath, err := openDBForInspection(c)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	if dbFilePath == sourcePath {
+		return cli.NewExitError("source database must be different from the primary one", helpers.ExitCodeInvalidArgs)
+	}
+
+	srcDB, err := kvdb.Open(
+		kvdb.BoltBackendName, sourcePath,
+		defaultDBConfig.NoFreelistSync, c.Duration(dbTimeoutFlag),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to open database %s, make sure stakerd is not running: %w", sourcePath, err)
+	}
+	defer srcDB.Close()
+
+	store, err := stakerdb.NewTrackedTransactionStore(db)
+	if err != nil {
+		return err
+	}
+
+	dryRun := c.Bool(dryRunFlag)
+	result, err := store.MergeFrom(stakerdb.NewInspectionStore(srcDB), dryRun)
+	if err != nil {
+		return err
+	}
+
+	helpers.PrintRespJSON(DBMergeResponse{
+		Path:           dbFilePath,
+		SourcePath:     sourcePath,
+		DryRun:         dryRun,
+		Merged:         hashesToStrings(result.Merged),
+		AlreadyTracked: hashesToStrings(result.AlreadyTracked),
+		Conflicting:    hashesToStrings(result.Conflicting),
+		Undecodable:    result.Undecodable,
+	})
+
+	return nil
+}
+
+func hashesToStrings(hashes []chainhash.Hash) []string {
+	strs := make([]string, 0, len(hashes))
+	for _, h := range hashes {
+		strs = append(strs, h.String())
+	}
+	return strs
+}
@@ -0,0 +1,410 @@
+package admin
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/babylonlabs-io/btc-staker/cmd/stakercli/helpers"
+	"github.com/babylonlabs-io/btc-staker/stakercfg"
+	"github.com/babylonlabs-io/btc-staker/stakerdb"
+	"github.com/babylonlabs-io/btc-staker/walletcontroller"
+	"github.com/lightningnetwork/lnd/kvdb"
+	"github.com/urfave/cli"
+)
+
+const (
+	snapshotDirFlag             = "snapshot-dir"
+	outDirFlag                  = "out-dir"
+	manifestSha256Flag          = "manifest-sha256"
+	skipWalletFlag              = "skip-wallet"
+	importWalletDescriptorsFlag = "import-wallet-descriptors"
+)
+
+var exportSnapshotCommand = cli.Command{
+	Name:      "export-snapshot",
+	ShortName: "es",
+	Usage:     "Export snapshot of staker database, params archive and wallet descriptors",
+	Description: "Snapshot is written into a new directory and can initialize a new daemon with bootstrap. " +
+		"Manifest of the snapshot lists sha256 hashes of its files, its own sha256 hash is printed and should be " +
+		"kept separately to verify the snapshot on bootstrap. Wallet descriptors are public, private keys " +
+		"must be backed up with the wallet. Staker daemon must be stopped.",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  configFileDirFlag,
+			Usage: "Path to staker configuration file",
+			Value: defaultConfigPath,
+		},
+		cli.StringFlag{
+			Name:     outDirFlag,
+			Usage:    "Directory to create the snapshot in, must not exist",
+			Required: true,
+		},
+		cli.BoolFlag{
+			Name:  skipWalletFlag,
+			Usage: "Do not include wallet descriptors, e.g. for wallets which do not support listing them",
+		},
+		cli.DurationFlag{
+			Name:  dbTimeoutFlag,
+			Usage: "How long to wait for the database lock, which is held by running stakerd",
+			Value: 5 * time.Second,
+		},
+	},
+	Action: exportSnapshot,
+}
+
+var bootstrapCommand = cli.Command{
+	Name:      "bootstrap",
+	ShortName: "bs",
+	Usage:     "Initialize database of a new staker daemon from a snapshot",
+	Description: "Integrity of every snapshot file is verified against its manifest, and the manifest against " +
+		"the given sha256 hash. Snapshot must be of the configured network and the configured database must not exist. " +
+		"Wallet of the configuration must hold descriptors of the snapshot, they can be imported into watch-only " +
+		"wallets. Database is restored only after all checks pass.",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  configFileDirFlag,
+			Usage: "Path to staker configuration file",
+			Value: defaultConfigPath,
+		},
+		cli.StringFlag{
+			Name:     snapshotDirFlag,
+			Usage:    "Directory of the snapshot created by export-snapshot",
+			Required: true,
+		},
+		cli.StringFlag{
+			Name:  manifestSha256Flag,
+			Usage: "Expected sha256 hash of the snapshot manifest, printed by export-snapshot",
+		},
+		cli.BoolFlag{
+			Name:  skipWalletFlag,
+			Usage: "Do not check wallet descriptors of the snapshot",
+		},
+		cli.BoolFlag{
+			Name:  importWalletDescriptorsFlag,
+			Usage: "Import wallet descriptors missing from the wallet, only for watch-only wallets",
+		},
+	},
+	Action: bootstrap,
+}
+
+type SnapshotResponse struct {
+	Path           string                     `json:"path"`
+	ManifestSha256 string                     `json:"manifest_sha256"`
+	Manifest       *stakerdb.SnapshotManifest `json:"manifest"`
+}
+
+type BootstrapResponse struct {
+	DBPath              string `json:"db_path"`
+	ManifestSha256      string `json:"manifest_sha256"`
+	TrackedTransactions uint64 `json:"tracked_transactions"`
+	RestoredParams      int    `json:"restored_params"`
+	ImportedDescriptors int    `json:"imported_descriptors"`
+}
+
+// boltDBFile returns path of the database file of the config, snapshots are
+// supported only for bolt databases
+func boltDBFile(config *stakercfg.Config) (string, error) {
+	switch config.DBConfig.Backend {
+	case stakercfg.EtcdBackend, stakercfg.PostgresBackend:
+		return "", cli.NewExitError(
+			fmt.Sprintf("snapshots are supported only by bolt database, configured backend is %s", config.DBConfig.Backend),
+			helpers.ExitCodeInvalidArgs,
+		)
+	}
+
+	return filepath.Join(config.DBConfig.DBPath, config.DBConfig.DBFileName), nil
+}
+
+func exportSnapshot(c *cli.Context) error {
+	config, _, _, err := stakercfg.LoadConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	dbFilePath, err := boltDBFile(config)
+	if err != nil {
+		return err
+	}
+
+	extra := make(map[string][]byte)
+	if !c.Bool(skipWalletFlag) {
+		wc, err := walletcontroller.NewRPCWalletController(config)
+		if err != nil {
+			return fmt.Errorf("failed to create wallet controller: %w", err)
+		}
+
+		descriptors, err := wc.ListDescriptors()
+		if err != nil {
+			return err
+		}
+
+		encoded, err := json.MarshalIndent(descriptors, "", "  ")
+		if err != nil {
+			return err
+		}
+		extra[stakerdb.SnapshotDescriptorsFile] = encoded
+	}
+
+	db, err := kvdb.Open(
+		kvdb.BoltBackendName, dbFilePath,
+		config.DBConfig.NoFreelistSync, c.Duration(dbTimeoutFlag),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to open database %s, make sure stakerd is not running: %w", dbFilePath, err)
+	}
+	defer db.Close()
+
+	store, err := stakerdb.NewTrackedTransactionStore(db)
+	if err != nil {
+		return err
+	}
+
+	outDir := c.String(outDirFlag)
+	if _, err := store.ExportSnapshot(outDir, extra); err != nil {
+		return fmt.Errorf("failed to export snapshot: %w", err)
+	}
+
+	manifest, manifestHash, err := stakerdb.ReadSnapshotManifest(outDir)
+	if err != nil {
+		return err
+	}
+
+	helpers.PrintRespJSON(SnapshotResponse{
+		Path:           outDir,
+		ManifestSha256: manifestHash,
+		Manifest:       manifest,
+	})
+
+	return nil
+}
+
+// checkSnapshotWallet checks that wallet holds every descriptor of the
+// snapshot, missing ones are imported if requested. Returns number of
+// imported descriptors.
+func checkSnapshotWallet(config *stakercfg.Config, snapshotDir string, importMissing bool) (int, error) {
+	raw, err := os.ReadFile(filepath.Join(snapshotDir, stakerdb.SnapshotDescriptorsFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return 0, cli.NewExitError(
+				"snapshot has no wallet descriptors, restore the wallet manually and bootstrap with --"+skipWalletFlag,
+				helpers.ExitCodeInvalidArgs,
+			)
+		}
+		return 0, err
+	}
+
+	var snapshotDescriptors walletcontroller.WalletDescriptors
+	if err := json.Unmarshal(raw, &snapshotDescriptors); err != nil {
+		return 0, fmt.Errorf("%w: invalid wallet descriptors: %w", stakerdb.ErrSnapshotCorrupted, err)
+	}
+
+	wc, err := walletcontroller.NewRPCWalletController(config)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create wallet controller: %w", err)
+	}
+
+	walletDescriptors, err := wc.ListDescriptors()
+	if err != nil {
+		return 0, err
+	}
+
+	held := make(map[string]struct{}, len(walletDescriptors.Descriptors))
+	for _, d := range walletDescriptors.Descriptors {
+		held[d.Desc] = struct{}{}
+	}
+
+	var missing []walletcontroller.WalletDescriptor
+	for _, d := range snapshotDescriptors.Descriptors {
+		if _, ok := held[d.Desc]; !ok {
+			missing = append(missing, d)
+		}
+	}
+
+	if len(missing) == 0 {
+		return 0, nil
+	}
+
+	if !importMissing {
+		return 0, cli.NewExitError(
+			fmt.Sprintf("wallet %s does not hold %d descriptors of the snapshot, restore the wallet or import them with --%s",
+				config.WalletConfig.WalletName, len(missing), importWalletDescriptorsFlag),
+			helpers.ExitCodeError,
+		)
+	}
+
+	if err := wc.ImportDescriptors(missing); err != nil {
+		return 0, err
+	}
+
+	return len(missing), nil
+}
+
+// restoreSnapshotDB restores database of the snapshot into a temporary file
+// next to the database file, checks it and moves it in place
+func restoreSnapshotDB(
+	config *stakercfg.Config,
+	snapshotDir string,
+	manifest *stakerdb.SnapshotManifest,
+	dbFilePath string,
+) (*BootstrapResponse, error) {
+	if err := os.MkdirAll(config.DBConfig.DBPath, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create database directory: %w", err)
+	}
+
+	tmpFile, err := os.CreateTemp(config.DBConfig.DBPath, config.DBConfig.DBFileName+".bootstrap-*")
+	if err != nil {
+		return nil, err
+	}
+	tmpPath := tmpFile.Name()
+	defer os.Remove(tmpPath)
+
+	src, err := os.Open(filepath.Join(snapshotDir, stakerdb.SnapshotDBFile))
+	if err != nil {
+		tmpFile.Close()
+		return nil, err
+	}
+	_, err = io.Copy(tmpFile, src)
+	src.Close()
+	if err == nil {
+		err = tmpFile.Sync()
+	}
+	if closeErr := tmpFile.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to copy snapshot database: %w", err)
+	}
+
+	db, err := kvdb.Open(kvdb.BoltBackendName, tmpPath, config.DBConfig.NoFreelistSync, config.DBConfig.DBTimeout)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open snapshot database: %w", err)
+	}
+
+	resp, err := checkRestoredDB(db, config, snapshotDir, manifest)
+	if closeErr := db.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// database of a running daemon could have been created meanwhile
+	if stakercfg.FileExists(dbFilePath) {
+		return nil, cli.NewExitError(fmt.Sprintf("database file %s already exists", dbFilePath), helpers.ExitCodeInvalidArgs)
+	}
+
+	if err := os.Rename(tmpPath, dbFilePath); err != nil {
+		return nil, fmt.Errorf("failed to move restored database in place: %w", err)
+	}
+
+	resp.DBPath = dbFilePath
+	return resp, nil
+}
+
+// checkRestoredDB checks that restored database matches the snapshot manifest
+// and the configured network, and restores params archive of the snapshot
+func checkRestoredDB(
+	db kvdb.Backend,
+	config *stakercfg.Config,
+	snapshotDir string,
+	manifest *stakerdb.SnapshotManifest,
+) (*BootstrapResponse, error) {
+	store, err := stakerdb.NewTrackedTransactionStore(db)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := store.BindNetwork(&config.ActiveNetParams); err != nil {
+		return nil, err
+	}
+
+	health, err := store.CheckIndexHealth()
+	if err != nil {
+		return nil, err
+	}
+	if !health.Healthy() {
+		return nil, fmt.Errorf("%w: database index is not healthy", stakerdb.ErrSnapshotCorrupted)
+	}
+
+	changelog, err := store.ChangelogStats()
+	if err != nil {
+		return nil, err
+	}
+
+	if health.TrackedTransactions != manifest.TrackedTransactions || changelog.LastSeq != manifest.LastChangeSeq {
+		return nil, fmt.Errorf("%w: database does not match manifest", stakerdb.ErrSnapshotCorrupted)
+	}
+
+	restoredParams, err := store.RestoreSnapshotParams(snapshotDir)
+	if err != nil {
+		return nil, err
+	}
+
+	return &BootstrapResponse{
+		TrackedTransactions: health.TrackedTransactions,
+		RestoredParams:      restoredParams,
+	}, nil
+}
+
+func bootstrap(c *cli.Context) error {
+	config, _, _, err := stakercfg.LoadConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	dbFilePath, err := boltDBFile(config)
+	if err != nil {
+		return err
+	}
+
+	if stakercfg.FileExists(dbFilePath) {
+		return cli.NewExitError(
+			fmt.Sprintf("database file %s already exists, bootstrap initializes only new daemons", dbFilePath),
+			helpers.ExitCodeInvalidArgs,
+		)
+	}
+
+	snapshotDir := c.String(snapshotDirFlag)
+	manifest, manifestHash, err := stakerdb.ReadSnapshotManifest(snapshotDir)
+	if err != nil {
+		return err
+	}
+
+	if expected := c.String(manifestSha256Flag); expected != "" && expected != manifestHash {
+		return fmt.Errorf("%w: manifest hash %s does not match expected %s", stakerdb.ErrSnapshotCorrupted, manifestHash, expected)
+	}
+
+	if manifest.Network != config.ActiveNetParams.Name {
+		return cli.NewExitError(
+			fmt.Sprintf("snapshot is of network %s, configured network is %s", manifest.Network, config.ActiveNetParams.Name),
+			helpers.ExitCodeInvalidArgs,
+		)
+	}
+
+	if err := stakerdb.VerifySnapshot(snapshotDir, manifest); err != nil {
+		return err
+	}
+
+	imported := 0
+	if !c.Bool(skipWalletFlag) {
+		imported, err = checkSnapshotWallet(config, snapshotDir, c.Bool(importWalletDescriptorsFlag))
+		if err != nil {
+			return err
+		}
+	}
+
+	resp, err := restoreSnapshotDB(config, snapshotDir, manifest, dbFilePath)
+	if err != nil {
+		return err
+	}
+	resp.ManifestSha256 = manifestHash
+	resp.ImportedDescriptors = imported
+
+	helpers.PrintRespJSON(resp)
+
+	return nil
+}
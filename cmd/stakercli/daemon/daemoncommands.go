@@ -2,14 +2,18 @@ package daemon
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"math"
 	"net/url"
+	"os"
 
 	"github.com/babylonlabs-io/btc-staker/cmd"
 	"github.com/babylonlabs-io/btc-staker/cmd/stakercli/helpers"
 	dc "github.com/babylonlabs-io/btc-staker/stakerservice/client"
 	"github.com/urfave/cli"
+	"golang.org/x/term"
 )
 
 var DaemonCommands = []cli.Command{
@@ -18,21 +22,68 @@ var DaemonCommands = []cli.Command{
 		ShortName: "dn",
 		Usage:     "More advanced commands which require staker daemon to be running.",
 		Category:  "Daemon commands",
-		Subcommands: []cli.Command{
+		Subcommands: helpers.WithOutputAndExitCodes(
 			checkDaemonHealthCmd,
+			versionCmd,
+			statsCmd,
 			listOutputsCmd,
+			listReservedOutpointsCmd,
+			unreserveOutpointCmd,
+			unlockWalletCmd,
+			newStakerAddressCmd,
+			listStakerAddressesCmd,
+			signMessageCmd,
+			verifyMessageCmd,
 			babylonFinalityProvidersCmd,
 			stakeCmd,
+			estimateStakeCmd,
 			stakeExpansionCmd,
 			consolidateUtxosCmd,
 			unstakeCmd,
+			restakeFromUnbondedCmd,
 			stakingDetailsCmd,
+			stakingDetailsBatchCmd,
+			findDelegationByTxCmd,
+			delegationHistoryCmd,
+			activationLatencyCmd,
+			listStuckDelegationsCmd,
+			expiringDelegationsCmd,
+			setAutoRenewCmd,
+			renewalJobsCmd,
+			chainSafetyCmd,
+			maintenanceStatusCmd,
+			setChainSafetyOverrideCmd,
+			exportDelegationCmd,
+			importDelegationCmd,
+			musig2Cmd,
 			listStakingTransactionsCmd,
 			withdrawableTransactionsCmd,
+			stakingActivityCmd,
+			dbChangesCmd,
+			quarantinedRecordsCmd,
+			replayEventsCmd,
+			cancelStakeCmd,
 			unbondCmd,
+			signSpendTxCmd,
+			listOperationsCmd,
+			approveOperationCmd,
+			listTemplatesCmd,
+			setTemplateCmd,
+			deleteTemplateCmd,
+			listQueuedStakesCmd,
+			cancelQueuedStakeCmd,
 			stakeFromPhase1Cmd,
-		},
+			retryDelegationRegistrationCmd,
+			renotifyCovenantCmd,
+			btcStakingParamsCmd,
+			archivedParamsCmd,
+			replicaStatusCmd,
+			fpQuotasCmd,
+			btcTxDetailsCmd,
+			waitForCmd,
+		),
 	},
+	topCmd,
 }
 
 const (
@@ -42,8 +93,53 @@ const (
 	stakingTransactionHashFlag = "staking-transaction-hash"
 	stakerAddressFlag          = "staker-address"
 	targetAmountFlag           = "target-amount"
+	fieldsFlag                 = "fields"
+	sortByFlag                 = "sort-by"
+	sortDirectionFlag          = "sort-direction"
+	periodFlag                 = "period"
+	outpointFlag               = "outpoint"
+	addressTypeFlag            = "address-type"
+	timeoutFlag                = "timeout"
+	btcHeightFlag              = "btc-height"
+	txHashFlag                 = "tx-hash"
+	tenantFlag                 = "tenant"
+	addressFlag                = "address"
+	messageFlag                = "message"
+	signatureFlag              = "signature"
+	kindFlag                   = "kind"
+	txHexFlag                  = "tx-hex"
+	operationIDFlag            = "operation-id"
+	templateFlag               = "template"
+	referenceFlag              = "reference"
+	nameFlag                   = "name"
+	maxFeeRateFlag             = "max-fee-rate"
+	labelFlag                  = "label"
+	queuedStakeIDFlag          = "queued-stake-id"
+	clearFlag                  = "clear"
+	outFileFlag                = "out-file"
+	bundleFileFlag             = "bundle-file"
+	feeRateFlag                = "fee-rate"
+	targetConfFlag             = "target-conf"
+	covenantPksFlag            = "covenant-pks"
+	covenantQuorumFlag         = "covenant-quorum"
+	unbondingTimeFlag          = "unbonding-time"
+	withinBlocksFlag           = "within-blocks"
+	disableFlag                = "disable"
 )
 
+// feeSelectionFlags select fee rate of transaction sent for the request, fee
+// rate estimated for the next block is used if neither is set
+var feeSelectionFlags = []cli.Flag{
+	cli.Int64Flag{
+		Name:  feeRateFlag,
+		Usage: "Explicit fee rate in sat/vbyte, must be within fee rate bounds of the daemon config",
+	},
+	cli.Int64Flag{
+		Name:  targetConfFlag,
+		Usage: "Number of blocks within which transaction should confirm, fee rate is estimated for it by the btc node",
+	},
+}
+
 var checkDaemonHealthCmd = cli.Command{
 	Name:      "check-health",
 	ShortName: "ch",
@@ -58,6 +154,36 @@ var checkDaemonHealthCmd = cli.Command{
 	Action: checkHealth,
 }
 
+var versionCmd = cli.Command{
+	Name:  "version",
+	Usage: "Show version, API version, network and enabled optional features of the staker daemon.",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  helpers.StakingDaemonAddressFlag,
+			Usage: "Full address of the staker daemon in format tcp:://<host>:<port>",
+			Value: helpers.DefaultStakingDaemonAddress,
+		},
+	},
+	Action: daemonVersion,
+}
+
+var statsCmd = cli.Command{
+	Name:  "stats",
+	Usage: "Show fees paid by the staker daemon per delegation and in total.",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  helpers.StakingDaemonAddressFlag,
+			Usage: "Full address of the staker daemon in format tcp:://<host>:<port>",
+			Value: helpers.DefaultStakingDaemonAddress,
+		},
+		cli.StringFlag{
+			Name:  tenantFlag,
+			Usage: "Show fees of delegations of this tenant only",
+		},
+	},
+	Action: stats,
+}
+
 var listOutputsCmd = cli.Command{
 	Name:      "list-outputs",
 	ShortName: "lo",
@@ -72,6 +198,143 @@ var listOutputsCmd = cli.Command{
 	Action: listOutputs,
 }
 
+var listReservedOutpointsCmd = cli.Command{
+	Name:      "list-reserved-outpoints",
+	ShortName: "lro",
+	Usage:     "List wallet outpoints already used as inputs by tracked staking transactions.",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  helpers.StakingDaemonAddressFlag,
+			Usage: "Full address of the staker daemon in format tcp:://<host>:<port>",
+			Value: helpers.DefaultStakingDaemonAddress,
+		},
+	},
+	Action: listReservedOutpoints,
+}
+
+var unreserveOutpointCmd = cli.Command{
+	Name:      "unreserve-outpoint",
+	ShortName: "uo",
+	Usage:     "Release wallet outpoint reserved by a tracked staking transaction so it can be used again.",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  helpers.StakingDaemonAddressFlag,
+			Usage: "Full address of the staker daemon in format tcp:://<host>:<port>",
+			Value: helpers.DefaultStakingDaemonAddress,
+		},
+		cli.StringFlag{
+			Name:     outpointFlag,
+			Usage:    "Outpoint to release in format <tx_hash>:<output_index>",
+			Required: true,
+		},
+	},
+	Action: unreserveOutpoint,
+}
+
+var unlockWalletCmd = cli.Command{
+	Name:      "unlock-wallet",
+	ShortName: "uw",
+	Usage:     "Provide passphrase used by staker daemon to unlock the wallet. Passphrase is read from the terminal.",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  helpers.StakingDaemonAddressFlag,
+			Usage: "Full address of the staker daemon in format tcp:://<host>:<port>",
+			Value: helpers.DefaultStakingDaemonAddress,
+		},
+		cli.IntFlag{
+			Name:  timeoutFlag,
+			Usage: "Number of seconds after which staker daemon forgets the passphrase, 0 keeps it until restart",
+		},
+	},
+	Action: unlockWallet,
+}
+
+var signMessageCmd = cli.Command{
+	Name:      "sign-message",
+	ShortName: "sm",
+	Usage:     "Sign message with the key of the wallet address using BIP-322, e.g. to prove ownership of the staker address.",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  helpers.StakingDaemonAddressFlag,
+			Usage: "Full address of the staker daemon in format tcp:://<host>:<port>",
+			Value: helpers.DefaultStakingDaemonAddress,
+		},
+		cli.StringFlag{
+			Name:     addressFlag,
+			Usage:    "Wallet address to sign with, only native segwit and taproot addresses are supported",
+			Required: true,
+		},
+		cli.StringFlag{
+			Name:     messageFlag,
+			Usage:    "Message to sign",
+			Required: true,
+		},
+	},
+	Action: signMessage,
+}
+
+var verifyMessageCmd = cli.Command{
+	Name:      "verify-message",
+	ShortName: "vm",
+	Usage:     "Verify BIP-322 signature of the message. Exits with code 7 if signature is not valid.",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  helpers.StakingDaemonAddressFlag,
+			Usage: "Full address of the staker daemon in format tcp:://<host>:<port>",
+			Value: helpers.DefaultStakingDaemonAddress,
+		},
+		cli.StringFlag{
+			Name:     addressFlag,
+			Usage:    "Address which signed the message",
+			Required: true,
+		},
+		cli.StringFlag{
+			Name:     messageFlag,
+			Usage:    "Signed message",
+			Required: true,
+		},
+		cli.StringFlag{
+			Name:     signatureFlag,
+			Usage:    "Base64 encoded BIP-322 simple signature",
+			Required: true,
+		},
+	},
+	Action: verifyMessage,
+}
+
+var newStakerAddressCmd = cli.Command{
+	Name:      "new-staker-address",
+	ShortName: "nsa",
+	Usage:     "Derive new wallet address compatible with Babylon staking and register it as staker address.",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  helpers.StakingDaemonAddressFlag,
+			Usage: "Full address of the staker daemon in format tcp:://<host>:<port>",
+			Value: helpers.DefaultStakingDaemonAddress,
+		},
+		cli.StringFlag{
+			Name:  addressTypeFlag,
+			Usage: "Type of the address {taproot, segwit}",
+			Value: "taproot",
+		},
+	},
+	Action: newStakerAddress,
+}
+
+var listStakerAddressesCmd = cli.Command{
+	Name:      "list-staker-addresses",
+	ShortName: "lsa",
+	Usage:     "List staker addresses registered by the staker daemon.",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  helpers.StakingDaemonAddressFlag,
+			Usage: "Full address of the staker daemon in format tcp:://<host>:<port>",
+			Value: helpers.DefaultStakingDaemonAddress,
+		},
+	},
+	Action: listStakerAddresses,
+}
+
 var babylonFinalityProvidersCmd = cli.Command{
 	Name:      "babylon-finality-providers",
 	ShortName: "bfp",
@@ -100,7 +363,56 @@ var stakeCmd = cli.Command{
 	Name:      "stake",
 	ShortName: "st",
 	Usage:     "Stake an amount of BTC to Babylon",
-	Flags: []cli.Flag{
+	Flags: append([]cli.Flag{
+		cli.StringFlag{
+			Name:  helpers.StakingDaemonAddressFlag,
+			Usage: "full address of the staker daemon in format tcp:://<host>:<port>",
+			Value: helpers.DefaultStakingDaemonAddress,
+		},
+		cli.StringFlag{
+			Name:  stakerAddressFlag,
+			Usage: "BTC address of the staker in hex. Required unless template defines it",
+		},
+		cli.Int64Flag{
+			Name:     helpers.StakingAmountFlag,
+			Usage:    "Staking amount in satoshis",
+			Required: true,
+		},
+		cli.StringSliceFlag{
+			Name:  fpPksFlag,
+			Usage: "BTC public keys of the finality providers in hex. Required unless template is used",
+		},
+		cli.Int64Flag{
+			Name:  helpers.StakingTimeBlocksFlag,
+			Usage: "Staking time in BTC blocks. Required unless template is used",
+		},
+		cli.StringFlag{
+			Name:  tenantFlag,
+			Usage: "Tenant the delegation is assigned to, default tenant is used if not set",
+		},
+		cli.StringFlag{
+			Name:  templateFlag,
+			Usage: "Name of delegation template defining finality providers, staking time, fee rate cap, tenant and labels",
+		},
+		cli.StringFlag{
+			Name:  referenceFlag,
+			Usage: "Internal reference, e.g. customer or batch id, whose sha256 hash is committed to in OP_RETURN output of the staking transaction",
+		},
+		cli.Int64Flag{
+			Name:  unbondingTimeFlag,
+			Usage: "Expected unbonding time in blocks, stake is rejected if babylon params require different unbonding time",
+		},
+	}, feeSelectionFlags...),
+	Action: stake,
+}
+
+var estimateStakeCmd = cli.Command{
+	Name:      "estimate-stake",
+	ShortName: "est",
+	Usage:     "Preview vsize, weight, input and output counts and fees of staking and unbonding transactions of a stake",
+	Description: "Staking transaction is funded from the wallet the same way as by the stake command, but it is not " +
+		"signed nor sent and its inputs are not reserved.",
+	Flags: append([]cli.Flag{
 		cli.StringFlag{
 			Name:  helpers.StakingDaemonAddressFlag,
 			Usage: "full address of the staker daemon in format tcp:://<host>:<port>",
@@ -126,14 +438,20 @@ var stakeCmd = cli.Command{
 			Usage:    "Staking time in BTC blocks",
 			Required: true,
 		},
-	},
-	Action: stake,
+		cli.StringFlag{
+			Name:  referenceFlag,
+			Usage: "Internal reference whose commitment is added to the staking transaction",
+		},
+	}, feeSelectionFlags...),
+	Action: estimateStake,
 }
 
 var stakeExpansionCmd = cli.Command{
 	Name:      "stake-expand",
 	ShortName: "stxp",
 	Usage:     "Stakes an amount of BTC to Babylon and uses a previous active BTC staking tx as input",
+	Description: "Experimental, the daemon must be started with stake-expansion feature enabled " +
+		"(featureflags.enable = stake-expansion).",
 	Flags: []cli.Flag{
 		cli.StringFlag{
 			Name:  helpers.StakingDaemonAddressFlag,
@@ -165,6 +483,10 @@ var stakeExpansionCmd = cli.Command{
 			Usage:    "Hash of previous staking transaction in bitcoin hex format which is currently an active BTC delegation",
 			Required: true,
 		},
+		cli.StringFlag{
+			Name:  tenantFlag,
+			Usage: "Tenant the expanded delegation is assigned to, tenant of the previous delegation is used if not set",
+		},
 	},
 	Action: stakeExpand,
 }
@@ -223,10 +545,112 @@ var stakeFromPhase1Cmd = cli.Command{
 	Action: stakeFromPhase1TxBTC,
 }
 
+var retryDelegationRegistrationCmd = cli.Command{
+	Name:      "retry-delegation-registration",
+	ShortName: "rdr",
+	Usage: "\nstakercli daemon retry-delegation-registration" +
+		" --staking-transaction-hash [txHashHex] [--covenant-pks [pkHex]... --covenant-quorum [quorum]]",
+	Description: "Registers tracked delegation, whose registration failed, on Babylon again. " +
+		"Covenant set of Babylon params for the staking transaction inclusion height is used unless it is overridden.",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  helpers.StakingDaemonAddressFlag,
+			Usage: "full address of the staker daemon in format tcp:://<host>:<port>",
+			Value: helpers.DefaultStakingDaemonAddress,
+		},
+		cli.StringFlag{
+			Name:     stakingTransactionHashFlag,
+			Usage:    "Hash of tracked staking transaction in bitcoin hex format",
+			Required: true,
+		},
+		cli.StringSliceFlag{
+			Name:  covenantPksFlag,
+			Usage: "Covenant public keys in hex format used to parse the staking transaction, requires covenant-quorum",
+		},
+		cli.Uint64Flag{
+			Name:  covenantQuorumFlag,
+			Usage: "Covenant quorum used to parse the staking transaction",
+		},
+	},
+	Action: retryDelegationRegistration,
+}
+
+var renotifyCovenantCmd = cli.Command{
+	Name:      "renotify-covenant",
+	ShortName: "rnc",
+	Usage:     "Re-checks registration of delegation whose covenant signatures did not arrive",
+	Description: "Queries Babylon for the delegation. If Babylon does not know it, e.g. because the registration " +
+		"message was lost, delegation with confirmed staking transaction is registered again. Otherwise watching " +
+		"of covenant signatures and activation of the delegation is restarted if it is not running.",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  helpers.StakingDaemonAddressFlag,
+			Usage: "full address of the staker daemon in format tcp:://<host>:<port>",
+			Value: helpers.DefaultStakingDaemonAddress,
+		},
+		cli.StringFlag{
+			Name:     stakingTransactionHashFlag,
+			Usage:    "Hash of tracked staking transaction in bitcoin hex format",
+			Required: true,
+		},
+	},
+	Action: renotifyCovenant,
+}
+
 var unstakeCmd = cli.Command{
 	Name:      "unstake",
 	ShortName: "ust",
 	Usage:     "Spends staking transaction and sends funds back to staker; this can only be done after timelock of staking transaction expires",
+	Flags: append([]cli.Flag{
+		cli.StringFlag{
+			Name:  helpers.StakingDaemonAddressFlag,
+			Usage: "full address of the staker daemon in format tcp:://<host>:<port>",
+			Value: helpers.DefaultStakingDaemonAddress,
+		},
+		cli.StringFlag{
+			Name:     stakingTransactionHashFlag,
+			Usage:    "Hash of original staking transaction in bitcoin hex format",
+			Required: true,
+		},
+		cli.StringFlag{
+			Name:  tenantFlag,
+			Usage: "spend stake only if it belongs to this tenant",
+		},
+	}, feeSelectionFlags...),
+	Action: unstake,
+}
+
+var restakeFromUnbondedCmd = cli.Command{
+	Name:  "restake-from-unbonded",
+	Usage: "Withdraws funds of unbonded or expired stake to the staker address and immediately stakes them again, the new staking transaction spends the withdrawal output",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  helpers.StakingDaemonAddressFlag,
+			Usage: "full address of the staker daemon in format tcp:://<host>:<port>",
+			Value: helpers.DefaultStakingDaemonAddress,
+		},
+		cli.StringFlag{
+			Name:     stakingTransactionHashFlag,
+			Usage:    "Hash of original staking transaction in bitcoin hex format",
+			Required: true,
+		},
+		cli.StringSliceFlag{
+			Name:     fpPksFlag,
+			Usage:    "BTC public keys of the finality providers of the new stake in hex",
+			Required: true,
+		},
+		cli.Int64Flag{
+			Name:     helpers.StakingTimeBlocksFlag,
+			Usage:    "Staking time of the new stake in BTC blocks",
+			Required: true,
+		},
+	},
+	Action: restakeFromUnbonded,
+}
+
+var cancelStakeCmd = cli.Command{
+	Name:  "cancel-stake",
+	Usage: "abandons staking transaction not yet confirmed on bitcoin, replacing it in mempool with transaction sending its inputs back to the staker address",
 	Flags: []cli.Flag{
 		cli.StringFlag{
 			Name:  helpers.StakingDaemonAddressFlag,
@@ -239,7 +663,7 @@ var unstakeCmd = cli.Command{
 			Required: true,
 		},
 	},
-	Action: unstake,
+	Action: cancelStake,
 }
 
 var unbondCmd = cli.Command{
@@ -257,14 +681,26 @@ var unbondCmd = cli.Command{
 			Usage:    "Hash of original staking transaction in bitcoin hex format",
 			Required: true,
 		},
+		cli.Int64Flag{
+			Name:  feeRateFlag,
+			Usage: "Minimum fee rate in sat/vbyte. Fee of unbonding transaction is fixed by Babylon, unbonding paying lower fee rate is rejected",
+		},
+		cli.Int64Flag{
+			Name:  targetConfFlag,
+			Usage: "Number of blocks within which unbonding transaction should confirm. Unbonding paying lower fee rate than estimated for it is rejected",
+		},
+		cli.StringFlag{
+			Name:  tenantFlag,
+			Usage: "unbond stake only if it belongs to this tenant",
+		},
 	},
 	Action: unbond,
 }
 
-var stakingDetailsCmd = cli.Command{
-	Name:      "staking-details",
-	ShortName: "sds",
-	Usage:     "Displays details of staking transaction with given hash",
+var signSpendTxCmd = cli.Command{
+	Name:      "sign-spend-tx",
+	ShortName: "sst",
+	Usage:     "Produce staker Schnorr signature over unbonding or slashing transaction of the delegation, e.g. for external coordinators assembling the transaction. The transaction must match the one registered on Babylon.",
 	Flags: []cli.Flag{
 		cli.StringFlag{
 			Name:  helpers.StakingDaemonAddressFlag,
@@ -276,38 +712,442 @@ var stakingDetailsCmd = cli.Command{
 			Usage:    "Hash of original staking transaction in bitcoin hex format",
 			Required: true,
 		},
+		cli.StringFlag{
+			Name:     kindFlag,
+			Usage:    "Kind of the transaction: unbonding, slashing or unbonding_slashing",
+			Required: true,
+		},
+		cli.StringFlag{
+			Name:     txHexFlag,
+			Usage:    "Hex encoded transaction to sign",
+			Required: true,
+		},
 	},
-	Action: stakingDetails,
+	Action: signSpendTx,
 }
 
-var listStakingTransactionsCmd = cli.Command{
-	Name:      "list-staking-transactions",
-	ShortName: "lst",
-	Usage:     "List current staking transactions in db",
+var listOperationsCmd = cli.Command{
+	Name:      "list-operations",
+	ShortName: "lops",
+	Usage:     "List stake and spend operations which required approval of second principal",
 	Flags: []cli.Flag{
 		cli.StringFlag{
 			Name:  helpers.StakingDaemonAddressFlag,
 			Usage: "full address of the staker daemon in format tcp:://<host>:<port>",
 			Value: helpers.DefaultStakingDaemonAddress,
 		},
-		cli.IntFlag{
-			Name:  offsetFlag,
-			Usage: "offset of the first transactions to return",
-			Value: 0,
+	},
+	Action: listOperations,
+}
+
+var approveOperationCmd = cli.Command{
+	Name:      "approve-operation",
+	ShortName: "ao",
+	Usage:     "Approve and execute operation waiting for approval. Credentials in BTCSTAKER_USERNAME and BTCSTAKER_PASSWORD must belong to principal other than the requester.",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  helpers.StakingDaemonAddressFlag,
+			Usage: "full address of the staker daemon in format tcp:://<host>:<port>",
+			Value: helpers.DefaultStakingDaemonAddress,
 		},
-		cli.IntFlag{
-			Name:  limitFlag,
-			Usage: "maximum number of transactions to return",
-			Value: 100,
+		cli.StringFlag{
+			Name:     operationIDFlag,
+			Usage:    "Id of the operation returned by the request",
+			Required: true,
 		},
 	},
-	Action: listStakingTransactions,
+	Action: approveOperation,
 }
 
-var withdrawableTransactionsCmd = cli.Command{
-	Name:      "withdrawable-transactions",
-	ShortName: "wt",
-	Usage:     "List current tranactions that can be withdrawn i.e funds can be transferred back to staker address",
+var listTemplatesCmd = cli.Command{
+	Name:      "list-templates",
+	ShortName: "lt",
+	Usage:     "List delegation templates defined in config and created through the API",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  helpers.StakingDaemonAddressFlag,
+			Usage: "full address of the staker daemon in format tcp:://<host>:<port>",
+			Value: helpers.DefaultStakingDaemonAddress,
+		},
+	},
+	Action: listTemplates,
+}
+
+var setTemplateCmd = cli.Command{
+	Name:      "set-template",
+	ShortName: "stpl",
+	Usage:     "Create or replace delegation template usable by stake command. Templates defined in config cannot be replaced.",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  helpers.StakingDaemonAddressFlag,
+			Usage: "full address of the staker daemon in format tcp:://<host>:<port>",
+			Value: helpers.DefaultStakingDaemonAddress,
+		},
+		cli.StringFlag{
+			Name:     nameFlag,
+			Usage:    "Name of the template",
+			Required: true,
+		},
+		cli.StringFlag{
+			Name:  stakerAddressFlag,
+			Usage: "BTC address of the staker, stake requests must provide it if not set",
+		},
+		cli.StringSliceFlag{
+			Name:     fpPksFlag,
+			Usage:    "BTC public keys of the finality providers in hex",
+			Required: true,
+		},
+		cli.Int64Flag{
+			Name:     helpers.StakingTimeBlocksFlag,
+			Usage:    "Staking time in BTC blocks",
+			Required: true,
+		},
+		cli.Int64Flag{
+			Name:  maxFeeRateFlag,
+			Usage: "Cap of estimated fee rate of staking transactions in sat/vbyte, 0 means no cap",
+		},
+		cli.StringFlag{
+			Name:  tenantFlag,
+			Usage: "Tenant delegations are assigned to, default tenant is used if not set",
+		},
+		cli.StringSliceFlag{
+			Name:  labelFlag,
+			Usage: "Label in format key:value recorded with created delegations. Can be specified multiple times",
+		},
+	},
+	Action: setTemplate,
+}
+
+var deleteTemplateCmd = cli.Command{
+	Name:      "delete-template",
+	ShortName: "dtpl",
+	Usage:     "Delete delegation template created through the API",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  helpers.StakingDaemonAddressFlag,
+			Usage: "full address of the staker daemon in format tcp:://<host>:<port>",
+			Value: helpers.DefaultStakingDaemonAddress,
+		},
+		cli.StringFlag{
+			Name:     nameFlag,
+			Usage:    "Name of the template",
+			Required: true,
+		},
+	},
+	Action: deleteTemplate,
+}
+
+var listQueuedStakesCmd = cli.Command{
+	Name:      "list-queued-stakes",
+	ShortName: "lqs",
+	Usage:     "List stake requests waiting for Babylon to accept new delegations",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  helpers.StakingDaemonAddressFlag,
+			Usage: "full address of the staker daemon in format tcp:://<host>:<port>",
+			Value: helpers.DefaultStakingDaemonAddress,
+		},
+	},
+	Action: listQueuedStakes,
+}
+
+var cancelQueuedStakeCmd = cli.Command{
+	Name:      "cancel-queued-stake",
+	ShortName: "cqs",
+	Usage:     "Remove stake request from the queue before it is submitted",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  helpers.StakingDaemonAddressFlag,
+			Usage: "full address of the staker daemon in format tcp:://<host>:<port>",
+			Value: helpers.DefaultStakingDaemonAddress,
+		},
+		cli.StringFlag{
+			Name:     queuedStakeIDFlag,
+			Usage:    "Id of the queued stake request",
+			Required: true,
+		},
+	},
+	Action: cancelQueuedStake,
+}
+
+var stakingDetailsCmd = cli.Command{
+	Name:      "staking-details",
+	ShortName: "sds",
+	Usage:     "Displays details of staking transaction with given hash",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  helpers.StakingDaemonAddressFlag,
+			Usage: "full address of the staker daemon in format tcp:://<host>:<port>",
+			Value: helpers.DefaultStakingDaemonAddress,
+		},
+		cli.StringFlag{
+			Name:     stakingTransactionHashFlag,
+			Usage:    "Hash of original staking transaction in bitcoin hex format",
+			Required: true,
+		},
+		cli.StringFlag{
+			Name:  tenantFlag,
+			Usage: "return details only if staking transaction belongs to this tenant",
+		},
+	},
+	Action: stakingDetails,
+}
+
+var stakingDetailsBatchCmd = cli.Command{
+	Name:      "staking-details-batch",
+	ShortName: "sdb",
+	Usage:     "Displays details of multiple staking transactions, listing transactions whose details can't be returned as failed",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  helpers.StakingDaemonAddressFlag,
+			Usage: "full address of the staker daemon in format tcp:://<host>:<port>",
+			Value: helpers.DefaultStakingDaemonAddress,
+		},
+		cli.StringSliceFlag{
+			Name:     stakingTransactionHashFlag,
+			Usage:    "Hash of original staking transaction in bitcoin hex format, can be repeated",
+			Required: true,
+		},
+		cli.StringFlag{
+			Name:  tenantFlag,
+			Usage: "report transactions of other tenants as failed",
+		},
+	},
+	Action: stakingDetailsBatch,
+}
+
+var findDelegationByTxCmd = cli.Command{
+	Name:      "find-delegation-by-tx",
+	ShortName: "fdt",
+	Usage:     "Displays tracked delegation to which staking, unbonding or withdrawal transaction belongs",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  helpers.StakingDaemonAddressFlag,
+			Usage: "full address of the staker daemon in format tcp:://<host>:<port>",
+			Value: helpers.DefaultStakingDaemonAddress,
+		},
+		cli.StringFlag{
+			Name:     txHashFlag,
+			Usage:    "Hash of staking, unbonding or withdrawal transaction in bitcoin hex format",
+			Required: true,
+		},
+		cli.StringFlag{
+			Name:  tenantFlag,
+			Usage: "return delegation only if it belongs to this tenant",
+		},
+	},
+	Action: findDelegationByTx,
+}
+
+var delegationHistoryCmd = cli.Command{
+	Name:      "delegation-history",
+	ShortName: "dh",
+	Usage:     "Displays Babylon statuses of the delegation and when they were entered",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  helpers.StakingDaemonAddressFlag,
+			Usage: "full address of the staker daemon in format tcp:://<host>:<port>",
+			Value: helpers.DefaultStakingDaemonAddress,
+		},
+		cli.StringFlag{
+			Name:     stakingTransactionHashFlag,
+			Usage:    "Hash of original staking transaction in bitcoin hex format",
+			Required: true,
+		},
+	},
+	Action: delegationHistory,
+}
+
+var activationLatencyCmd = cli.Command{
+	Name:      "activation-latency",
+	ShortName: "actl",
+	Usage:     "Displays time spent in each phase of delegation activation",
+	Description: "With staking transaction hash, displays observed activation phases of the delegation " +
+		"(broadcast, confirmed, registered, covenant_quorum, active) and time between them. Without it, " +
+		"displays latency statistics across all active delegations.",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  helpers.StakingDaemonAddressFlag,
+			Usage: "full address of the staker daemon in format tcp:://<host>:<port>",
+			Value: helpers.DefaultStakingDaemonAddress,
+		},
+		cli.StringFlag{
+			Name:  stakingTransactionHashFlag,
+			Usage: "Hash of original staking transaction in bitcoin hex format",
+		},
+	},
+	Action: activationLatency,
+}
+
+var listStuckDelegationsCmd = cli.Command{
+	Name:      "list-stuck-delegations",
+	ShortName: "lsd",
+	Usage:     "List delegations stuck in intermediate states for longer than configured thresholds",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  helpers.StakingDaemonAddressFlag,
+			Usage: "full address of the staker daemon in format tcp:://<host>:<port>",
+			Value: helpers.DefaultStakingDaemonAddress,
+		},
+	},
+	Action: listStuckDelegations,
+}
+
+var expiringDelegationsCmd = cli.Command{
+	Name:      "expiring-delegations",
+	ShortName: "exd",
+	Usage:     "List active delegations whose staking timelock expires within the given number of blocks",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  helpers.StakingDaemonAddressFlag,
+			Usage: "full address of the staker daemon in format tcp:://<host>:<port>",
+			Value: helpers.DefaultStakingDaemonAddress,
+		},
+		cli.Int64Flag{
+			Name:  withinBlocksFlag,
+			Usage: "Number of BTC blocks within which staking timelock expires",
+			Value: 1008,
+		},
+	},
+	Action: expiringDelegations,
+}
+
+var setAutoRenewCmd = cli.Command{
+	Name:      "set-auto-renew",
+	ShortName: "sar",
+	Usage:     "Restake delegation to the same finality providers once its staking timelock expires",
+	Description: "Once staking timelock of the delegation expires, its funds are withdrawn to the staker " +
+		"address and staked again as with restake-from-unbonded. Renewal runs as a job persisted in the " +
+		"database, see renewal-jobs. Delegations which are unbonded are not renewed.",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  helpers.StakingDaemonAddressFlag,
+			Usage: "full address of the staker daemon in format tcp:://<host>:<port>",
+			Value: helpers.DefaultStakingDaemonAddress,
+		},
+		cli.StringFlag{
+			Name:     stakingTransactionHashFlag,
+			Usage:    "Hash of staking transaction in bitcoin hex format",
+			Required: true,
+		},
+		cli.Int64Flag{
+			Name:  helpers.StakingTimeBlocksFlag,
+			Usage: "Staking time of the renewed stake in BTC blocks, staking time of the delegation is kept if not set",
+		},
+		cli.BoolFlag{
+			Name:  disableFlag,
+			Usage: "disable auto renew of the delegation",
+		},
+	},
+	Action: setAutoRenew,
+}
+
+var renewalJobsCmd = cli.Command{
+	Name:      "renewal-jobs",
+	ShortName: "rj",
+	Usage:     "List renewal jobs of expired delegations with enabled auto renew",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  helpers.StakingDaemonAddressFlag,
+			Usage: "full address of the staker daemon in format tcp:://<host>:<port>",
+			Value: helpers.DefaultStakingDaemonAddress,
+		},
+	},
+	Action: renewalJobs,
+}
+
+var chainSafetyCmd = cli.Command{
+	Name:      "chain-safety",
+	ShortName: "cs",
+	Usage:     "Show whether params sensitive operations are paused because Babylon is halted or diverges from btc chain",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  helpers.StakingDaemonAddressFlag,
+			Usage: "full address of the staker daemon in format tcp:://<host>:<port>",
+			Value: helpers.DefaultStakingDaemonAddress,
+		},
+	},
+	Action: chainSafety,
+}
+
+var maintenanceStatusCmd = cli.Command{
+	Name:      "maintenance-status",
+	ShortName: "ms",
+	Usage:     "Show whether maintenance window is active and when the next one starts",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  helpers.StakingDaemonAddressFlag,
+			Usage: "full address of the staker daemon in format tcp:://<host>:<port>",
+			Value: helpers.DefaultStakingDaemonAddress,
+		},
+	},
+	Action: maintenanceStatus,
+}
+
+var setChainSafetyOverrideCmd = cli.Command{
+	Name:      "set-chain-safety-override",
+	ShortName: "scso",
+	Usage:     "Allow params sensitive operations while chain safety checks fail, until --clear is given or daemon restarts",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  helpers.StakingDaemonAddressFlag,
+			Usage: "full address of the staker daemon in format tcp:://<host>:<port>",
+			Value: helpers.DefaultStakingDaemonAddress,
+		},
+		cli.BoolFlag{
+			Name:  clearFlag,
+			Usage: "remove the override, pausing params sensitive operations again while checks fail",
+		},
+	},
+	Action: setChainSafetyOverride,
+}
+
+var exportDelegationCmd = cli.Command{
+	Name:      "export-delegation",
+	ShortName: "ed",
+	Usage:     "Export everything staker knows about the delegation as a single JSON bundle, to be attached to support tickets",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  helpers.StakingDaemonAddressFlag,
+			Usage: "full address of the staker daemon in format tcp:://<host>:<port>",
+			Value: helpers.DefaultStakingDaemonAddress,
+		},
+		cli.StringFlag{
+			Name:     stakingTransactionHashFlag,
+			Usage:    "Hash of original staking transaction in bitcoin hex format",
+			Required: true,
+		},
+		cli.StringFlag{
+			Name:  outFileFlag,
+			Usage: "write the bundle to given file instead of printing it",
+		},
+	},
+	Action: exportDelegation,
+}
+
+var importDelegationCmd = cli.Command{
+	Name:      "import-delegation",
+	ShortName: "id",
+	Usage:     "Start tracking delegation exported by export-delegation of other staker daemon",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  helpers.StakingDaemonAddressFlag,
+			Usage: "full address of the staker daemon in format tcp:://<host>:<port>",
+			Value: helpers.DefaultStakingDaemonAddress,
+		},
+		cli.StringFlag{
+			Name:     bundleFileFlag,
+			Usage:    "file with the bundle written by export-delegation",
+			Required: true,
+		},
+	},
+	Action: importDelegation,
+}
+
+var listStakingTransactionsCmd = cli.Command{
+	Name:      "list-staking-transactions",
+	ShortName: "lst",
+	Usage:     "List current staking transactions in db",
 	Flags: []cli.Flag{
 		cli.StringFlag{
 			Name:  helpers.StakingDaemonAddressFlag,
@@ -324,10 +1164,173 @@ var withdrawableTransactionsCmd = cli.Command{
 			Usage: "maximum number of transactions to return",
 			Value: 100,
 		},
-	},
-	Action: withdrawableTransactions,
-}
-
+		cli.StringFlag{
+			Name:  fieldsFlag,
+			Usage: "comma separated list of fields to return (stakingTxHash,stakerAddress,state,transactionIdx,stakingAmount,fee,unbondingFee,spendableHeight), all fields are returned if not set",
+		},
+		cli.StringFlag{
+			Name:  sortByFlag,
+			Usage: "key by which transactions are sorted (index,amount,confirmationHeight,timeRemaining)",
+			Value: "index",
+		},
+		cli.StringFlag{
+			Name:  sortDirectionFlag,
+			Usage: "direction in which transactions are sorted (asc,desc)",
+			Value: "asc",
+		},
+		cli.StringFlag{
+			Name:  tenantFlag,
+			Usage: "list only transactions of this tenant, transactions of all tenants are listed if not set",
+		},
+	},
+	Action: listStakingTransactions,
+}
+
+var withdrawableTransactionsCmd = cli.Command{
+	Name:      "withdrawable-transactions",
+	ShortName: "wt",
+	Usage:     "List current tranactions that can be withdrawn i.e funds can be transferred back to staker address",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  helpers.StakingDaemonAddressFlag,
+			Usage: "full address of the staker daemon in format tcp:://<host>:<port>",
+			Value: helpers.DefaultStakingDaemonAddress,
+		},
+		cli.IntFlag{
+			Name:  offsetFlag,
+			Usage: "offset of the first transactions to return",
+			Value: 0,
+		},
+		cli.IntFlag{
+			Name:  limitFlag,
+			Usage: "maximum number of transactions to return",
+			Value: 100,
+		},
+		cli.StringFlag{
+			Name:  fieldsFlag,
+			Usage: "comma separated list of fields to return (stakingTxHash,stakerAddress,state,transactionIdx,stakingAmount,fee,unbondingFee,spendableHeight), all fields are returned if not set",
+		},
+		cli.StringFlag{
+			Name:  tenantFlag,
+			Usage: "list only transactions of this tenant, transactions of all tenants are listed if not set",
+		},
+	},
+	Action: withdrawableTransactions,
+}
+
+var stakingActivityCmd = cli.Command{
+	Name:      "staking-activity",
+	ShortName: "sa",
+	Usage:     "Displays delegations and withdrawals grouped by time period",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  helpers.StakingDaemonAddressFlag,
+			Usage: "full address of the staker daemon in format tcp:://<host>:<port>",
+			Value: helpers.DefaultStakingDaemonAddress,
+		},
+		cli.StringFlag{
+			Name:  periodFlag,
+			Usage: "period by which activity is grouped (day,week,month)",
+			Value: "month",
+		},
+	},
+	Action: stakingActivity,
+}
+
+var btcStakingParamsCmd = cli.Command{
+	Name:      "btc-staking-params",
+	ShortName: "bsp",
+	Usage:     "Get Babylon btc staking parameters valid at given BTC block height",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  helpers.StakingDaemonAddressFlag,
+			Usage: "full address of the staker daemon in format tcp:://<host>:<port>",
+			Value: helpers.DefaultStakingDaemonAddress,
+		},
+		cli.Uint64Flag{
+			Name:     btcHeightFlag,
+			Usage:    "BTC block height",
+			Required: true,
+		},
+	},
+	Action: btcStakingParams,
+}
+
+var archivedParamsCmd = cli.Command{
+	Name:      "archived-params",
+	ShortName: "arp",
+	Usage:     "List Babylon btc staking parameters versions archived by the daemon",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  helpers.StakingDaemonAddressFlag,
+			Usage: "full address of the staker daemon in format tcp:://<host>:<port>",
+			Value: helpers.DefaultStakingDaemonAddress,
+		},
+	},
+	Action: archivedParams,
+}
+
+var replicaStatusCmd = cli.Command{
+	Name:      "replica-status",
+	ShortName: "rs",
+	Usage:     "Show progress of replication of daemon running as read only replica",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  helpers.StakingDaemonAddressFlag,
+			Usage: "full address of the staker daemon in format tcp:://<host>:<port>",
+			Value: helpers.DefaultStakingDaemonAddress,
+		},
+	},
+	Action: replicaStatus,
+}
+
+var fpQuotasCmd = cli.Command{
+	Name:      "fp-quotas",
+	ShortName: "fpq",
+	Usage:     "Show delegations of the staker per finality provider and their quotas",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  helpers.StakingDaemonAddressFlag,
+			Usage: "full address of the staker daemon in format tcp:://<host>:<port>",
+			Value: helpers.DefaultStakingDaemonAddress,
+		},
+	},
+	Action: fpQuotas,
+}
+
+var quarantinedRecordsCmd = cli.Command{
+	Name:      "quarantined-records",
+	ShortName: "qr",
+	Usage:     "List stored transaction records which failed to decode or validate and were moved to quarantine",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  helpers.StakingDaemonAddressFlag,
+			Usage: "full address of the staker daemon in format tcp:://<host>:<port>",
+			Value: helpers.DefaultStakingDaemonAddress,
+		},
+	},
+	Action: quarantinedRecords,
+}
+
+var btcTxDetailsCmd = cli.Command{
+	Name:      "btc-tx-details",
+	ShortName: "btd",
+	Usage:     "Get BTC transaction and details of the block which includes it",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  helpers.StakingDaemonAddressFlag,
+			Usage: "full address of the staker daemon in format tcp:://<host>:<port>",
+			Value: helpers.DefaultStakingDaemonAddress,
+		},
+		cli.StringFlag{
+			Name:     txHashFlag,
+			Usage:    "Hash of the BTC transaction in hex",
+			Required: true,
+		},
+	},
+	Action: btcTxDetails,
+}
+
 // checkHealth checks if staker daemon is running.
 func checkHealth(ctx *cli.Context) error {
 	daemonAddress := ctx.String(helpers.StakingDaemonAddressFlag)
@@ -338,19 +1341,659 @@ func checkHealth(ctx *cli.Context) error {
 
 	sctx := context.Background()
 
-	health, err := client.Health(sctx)
-
+	health, err := client.Health(sctx)
+
+	if err != nil {
+		return fmt.Errorf("failed to check health: %w", err)
+	}
+
+	return helpers.PrintResp(ctx, health)
+}
+
+func daemonVersion(ctx *cli.Context) error {
+	daemonAddress := ctx.String(helpers.StakingDaemonAddressFlag)
+	client, err := NewStakerServiceJSONRPCClient(daemonAddress)
+	if err != nil {
+		return fmt.Errorf("failed to create staker service JSON-RPC client: %w", err)
+	}
+
+	result, err := client.Version(context.Background())
+	if err != nil {
+		return fmt.Errorf("failed to get daemon version: %w", err)
+	}
+
+	return helpers.PrintResp(ctx, result)
+}
+
+func stats(ctx *cli.Context) error {
+	daemonAddress := ctx.String(helpers.StakingDaemonAddressFlag)
+	client, err := NewStakerServiceJSONRPCClient(daemonAddress)
+	if err != nil {
+		return fmt.Errorf("failed to create staker service JSON-RPC client: %w", err)
+	}
+
+	sctx := context.Background()
+
+	result, err := client.Stats(sctx, ctx.String(tenantFlag))
+	if err != nil {
+		return fmt.Errorf("failed to get stats: %w", err)
+	}
+
+	return helpers.PrintResp(ctx, result)
+}
+
+// listOutputs lists current unspent outputs in connected wallet.
+func listOutputs(ctx *cli.Context) error {
+	daemonAddress := ctx.String(helpers.StakingDaemonAddressFlag)
+	client, err := NewStakerServiceJSONRPCClient(daemonAddress)
+	if err != nil {
+		return fmt.Errorf("failed to create staker service JSON-RPC client: %w", err)
+	}
+
+	sctx := context.Background()
+
+	outputs, err := client.ListOutputs(sctx)
+
+	if err != nil {
+		return fmt.Errorf("failed to list outputs: %w", err)
+	}
+
+	return helpers.PrintResp(ctx, outputs)
+}
+
+// listReservedOutpoints lists wallet outpoints used by tracked staking transactions.
+func listReservedOutpoints(ctx *cli.Context) error {
+	daemonAddress := ctx.String(helpers.StakingDaemonAddressFlag)
+	client, err := NewStakerServiceJSONRPCClient(daemonAddress)
+	if err != nil {
+		return fmt.Errorf("failed to create staker service JSON-RPC client: %w", err)
+	}
+
+	sctx := context.Background()
+
+	outpoints, err := client.ListReservedOutpoints(sctx)
+	if err != nil {
+		return fmt.Errorf("failed to list reserved outpoints: %w", err)
+	}
+
+	return helpers.PrintResp(ctx, outpoints)
+}
+
+// unreserveOutpoint releases wallet outpoint reserved by a tracked staking transaction.
+func unreserveOutpoint(ctx *cli.Context) error {
+	daemonAddress := ctx.String(helpers.StakingDaemonAddressFlag)
+	client, err := NewStakerServiceJSONRPCClient(daemonAddress)
+	if err != nil {
+		return fmt.Errorf("failed to create staker service JSON-RPC client: %w", err)
+	}
+
+	sctx := context.Background()
+
+	result, err := client.UnreserveOutpoint(sctx, ctx.String(outpointFlag))
+	if err != nil {
+		return fmt.Errorf("failed to unreserve outpoint: %w", err)
+	}
+
+	return helpers.PrintResp(ctx, result)
+}
+
+func unlockWallet(ctx *cli.Context) error {
+	daemonAddress := ctx.String(helpers.StakingDaemonAddressFlag)
+	client, err := NewStakerServiceJSONRPCClient(daemonAddress)
+	if err != nil {
+		return fmt.Errorf("failed to create staker service JSON-RPC client: %w", err)
+	}
+
+	fmt.Fprint(os.Stderr, "Wallet passphrase: ")
+	passphrase, err := term.ReadPassword(int(os.Stdin.Fd()))
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return fmt.Errorf("failed to read passphrase: %w", err)
+	}
+
+	sctx := context.Background()
+
+	timeoutSecs := ctx.Int(timeoutFlag)
+
+	result, err := client.UnlockWallet(sctx, string(passphrase), &timeoutSecs)
+	if err != nil {
+		return fmt.Errorf("failed to unlock wallet: %w", err)
+	}
+
+	return helpers.PrintResp(ctx, result)
+}
+
+func signMessage(ctx *cli.Context) error {
+	daemonAddress := ctx.String(helpers.StakingDaemonAddressFlag)
+	client, err := NewStakerServiceJSONRPCClient(daemonAddress)
+	if err != nil {
+		return fmt.Errorf("failed to create staker service JSON-RPC client: %w", err)
+	}
+
+	sctx := context.Background()
+
+	result, err := client.SignMessage(sctx, ctx.String(addressFlag), ctx.String(messageFlag))
+	if err != nil {
+		return fmt.Errorf("failed to sign message: %w", err)
+	}
+
+	return helpers.PrintResp(ctx, result)
+}
+
+func verifyMessage(ctx *cli.Context) error {
+	daemonAddress := ctx.String(helpers.StakingDaemonAddressFlag)
+	client, err := NewStakerServiceJSONRPCClient(daemonAddress)
+	if err != nil {
+		return fmt.Errorf("failed to create staker service JSON-RPC client: %w", err)
+	}
+
+	sctx := context.Background()
+
+	result, err := client.VerifyMessage(sctx, ctx.String(addressFlag), ctx.String(messageFlag), ctx.String(signatureFlag))
+	if err != nil {
+		return fmt.Errorf("failed to verify message: %w", err)
+	}
+
+	if err := helpers.PrintResp(ctx, result); err != nil {
+		return err
+	}
+
+	if !result.Valid {
+		return cli.NewExitError("signature is not valid", helpers.ExitCodeInvalidSignature)
+	}
+
+	return nil
+}
+
+func newStakerAddress(ctx *cli.Context) error {
+	daemonAddress := ctx.String(helpers.StakingDaemonAddressFlag)
+	client, err := NewStakerServiceJSONRPCClient(daemonAddress)
+	if err != nil {
+		return fmt.Errorf("failed to create staker service JSON-RPC client: %w", err)
+	}
+
+	sctx := context.Background()
+
+	addressType := ctx.String(addressTypeFlag)
+
+	result, err := client.NewStakerAddress(sctx, &addressType)
+	if err != nil {
+		return fmt.Errorf("failed to create new staker address: %w", err)
+	}
+
+	return helpers.PrintResp(ctx, result)
+}
+
+func listStakerAddresses(ctx *cli.Context) error {
+	daemonAddress := ctx.String(helpers.StakingDaemonAddressFlag)
+	client, err := NewStakerServiceJSONRPCClient(daemonAddress)
+	if err != nil {
+		return fmt.Errorf("failed to create staker service JSON-RPC client: %w", err)
+	}
+
+	sctx := context.Background()
+
+	result, err := client.ListStakerAddresses(sctx)
+	if err != nil {
+		return fmt.Errorf("failed to list staker addresses: %w", err)
+	}
+
+	return helpers.PrintResp(ctx, result)
+}
+
+// babylonFinalityProviders lists current finality providers.
+func babylonFinalityProviders(ctx *cli.Context) error {
+	daemonAddress := ctx.String(helpers.StakingDaemonAddressFlag)
+	client, err := NewStakerServiceJSONRPCClient(daemonAddress)
+	if err != nil {
+		return fmt.Errorf("failed to create staker service JSON-RPC client: %w", err)
+	}
+
+	sctx := context.Background()
+
+	offset := ctx.Int(offsetFlag)
+
+	if offset < 0 {
+		return cli.NewExitError("Offset must be non-negative", helpers.ExitCodeInvalidArgs)
+	}
+
+	limit := ctx.Int(limitFlag)
+
+	if limit < 0 {
+		return cli.NewExitError("Limit must be non-negative", helpers.ExitCodeInvalidArgs)
+	}
+
+	finalityProviders, err := client.BabylonFinalityProviders(sctx, &offset, &limit)
+
+	if err != nil {
+		return fmt.Errorf("failed to get finality providers: %w", err)
+	}
+
+	return helpers.PrintResp(ctx, finalityProviders)
+}
+
+// stake stakes a BTC.
+func stake(ctx *cli.Context) error {
+	daemonAddress := ctx.String(helpers.StakingDaemonAddressFlag)
+	client, err := NewStakerServiceJSONRPCClient(daemonAddress)
+	if err != nil {
+		return fmt.Errorf("failed to create staker service JSON-RPC client: %w", err)
+	}
+
+	sctx := context.Background()
+
+	stakerAddress := ctx.String(stakerAddressFlag)
+	stakingAmount := ctx.Int64(helpers.StakingAmountFlag)
+	fpPks := ctx.StringSlice(fpPksFlag)
+	stakingTimeBlocks := ctx.Int64(helpers.StakingTimeBlocksFlag)
+	template := ctx.String(templateFlag)
+
+	if template == "" && (stakerAddress == "" || len(fpPks) == 0 || stakingTimeBlocks == 0) {
+		return cli.NewExitError(
+			fmt.Sprintf("%s, %s and %s are required when %s is not set",
+				stakerAddressFlag, fpPksFlag, helpers.StakingTimeBlocksFlag, templateFlag),
+			helpers.ExitCodeInvalidArgs,
+		)
+	}
+
+	results, err := client.Stake(sctx, stakerAddress, stakingAmount, fpPks, stakingTimeBlocks, ctx.String(tenantFlag), template, ctx.String(referenceFlag),
+		ctx.Int64(feeRateFlag), ctx.Int64(targetConfFlag), ctx.Int64(unbondingTimeFlag))
+	if err != nil {
+		return fmt.Errorf("failed to stake: %w", err)
+	}
+
+	return helpers.PrintResp(ctx, results)
+}
+
+func estimateStake(ctx *cli.Context) error {
+	daemonAddress := ctx.String(helpers.StakingDaemonAddressFlag)
+	client, err := NewStakerServiceJSONRPCClient(daemonAddress)
+	if err != nil {
+		return fmt.Errorf("failed to create staker service JSON-RPC client: %w", err)
+	}
+
+	sctx := context.Background()
+
+	results, err := client.EstimateStake(
+		sctx,
+		ctx.String(stakerAddressFlag),
+		ctx.Int64(helpers.StakingAmountFlag),
+		ctx.StringSlice(fpPksFlag),
+		ctx.Int64(helpers.StakingTimeBlocksFlag),
+		ctx.String(referenceFlag),
+		ctx.Int64(feeRateFlag),
+		ctx.Int64(targetConfFlag),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to estimate stake: %w", err)
+	}
+
+	return helpers.PrintResp(ctx, results)
+}
+
+// stakeExpand creates a new btc staking transaction from an previous
+// active BTC staking delegation and another new input.
+func stakeExpand(ctx *cli.Context) error {
+	daemonAddress := ctx.String(helpers.StakingDaemonAddressFlag)
+	client, err := NewStakerServiceJSONRPCClient(daemonAddress)
+	if err != nil {
+		return fmt.Errorf("failed to create staker service JSON-RPC client: %w", err)
+	}
+
+	sctx := context.Background()
+
+	stakerAddress := ctx.String(stakerAddressFlag)
+	stakingAmount := ctx.Int64(helpers.StakingAmountFlag)
+	fpPks := ctx.StringSlice(fpPksFlag)
+	stakingTimeBlocks := ctx.Int64(helpers.StakingTimeBlocksFlag)
+
+	prevActiveStkTxHashHex := ctx.String(stakingTransactionHashFlag)
+	if len(prevActiveStkTxHashHex) == 0 {
+		return errors.New("previous active staking tx hash hex for stake expansion is empty")
+	}
+
+	results, err := client.StakeExpand(sctx, stakerAddress, stakingAmount, fpPks, stakingTimeBlocks, prevActiveStkTxHashHex, ctx.String(tenantFlag))
+	if err != nil {
+		return fmt.Errorf("failed to stake expand: %w", err)
+	}
+
+	return helpers.PrintResp(ctx, results)
+}
+
+// consolidateUtxos consolidates small UTXOs into a single larger UTXO
+func consolidateUtxos(ctx *cli.Context) error {
+	daemonAddress := ctx.String(helpers.StakingDaemonAddressFlag)
+	client, err := NewStakerServiceJSONRPCClient(daemonAddress)
+	if err != nil {
+		return fmt.Errorf("failed to create staker service JSON-RPC client: %w", err)
+	}
+
+	sctx := context.Background()
+
+	stakerAddress := ctx.String(stakerAddressFlag)
+	targetAmount := ctx.Int64(targetAmountFlag)
+
+	result, err := client.ConsolidateUTXOs(sctx, stakerAddress, targetAmount)
+	if err != nil {
+		return fmt.Errorf("failed to consolidate UTXOs: %w", err)
+	}
+
+	return helpers.PrintResp(ctx, result)
+}
+
+// stakeFromPhase1TxBTC delegates a staking transaction from a phase 1 tx BTC.
+func stakeFromPhase1TxBTC(ctx *cli.Context) error {
+	daemonAddress := ctx.String(helpers.StakingDaemonAddressFlag)
+	client, err := NewStakerServiceJSONRPCClient(daemonAddress)
+	if err != nil {
+		return fmt.Errorf("failed to create staker service JSON-RPC client: %w", err)
+	}
+
+	sctx := context.Background()
+	stakingTransactionHash := ctx.String(stakingTransactionHashFlag)
+	if len(stakingTransactionHash) == 0 {
+		return errors.New("staking tx hash hex is empty")
+	}
+
+	blockHeighTxInclusion := ctx.Uint64(helpers.TxInclusionHeightFlag)
+	if blockHeighTxInclusion == 0 {
+		resp, err := client.BtcTxDetails(sctx, stakingTransactionHash)
+		if err != nil {
+			return fmt.Errorf("error to get btc tx and block data from staking tx %s: %w", stakingTransactionHash, err)
+		}
+
+		blockHeighTxInclusion = uint64(resp.Blk.Height)
+	}
+
+	respParamsByHeight, err := client.BtcStakingParamByBtcHeight(sctx, uint32(blockHeighTxInclusion))
+	if err != nil {
+		return fmt.Errorf("failed to get btc staking parameters: %w", err)
+	}
+	btcStakingParams := respParamsByHeight.StakingParams
+
+	stakerAddress := ctx.String(stakerAddressFlag)
+	result, err := client.BtcDelegationFromBtcStakingTx(sctx, stakerAddress, stakingTransactionHash, btcStakingParams.CovenantPkHex, btcStakingParams.CovenantQuorum)
+	if err != nil {
+		return fmt.Errorf("failed to delegate from btc staking tx: %w", err)
+	}
+
+	return helpers.PrintResp(ctx, result)
+}
+
+// retryDelegationRegistration registers tracked delegation on Babylon again.
+func retryDelegationRegistration(ctx *cli.Context) error {
+	daemonAddress := ctx.String(helpers.StakingDaemonAddressFlag)
+	client, err := NewStakerServiceJSONRPCClient(daemonAddress)
+	if err != nil {
+		return fmt.Errorf("failed to create staker service JSON-RPC client: %w", err)
+	}
+
+	sctx := context.Background()
+	stakingTransactionHash := ctx.String(stakingTransactionHashFlag)
+
+	covenantPks := ctx.StringSlice(covenantPksFlag)
+	covenantQuorum := ctx.Uint64(covenantQuorumFlag)
+	if len(covenantPks) > 0 && covenantQuorum == 0 {
+		return fmt.Errorf("%s must be set together with %s", covenantQuorumFlag, covenantPksFlag)
+	}
+
+	if len(covenantPks) == 0 {
+		resp, err := client.BtcTxDetails(sctx, stakingTransactionHash)
+		if err != nil {
+			return fmt.Errorf("error to get btc tx and block data from staking tx %s: %w", stakingTransactionHash, err)
+		}
+
+		respParamsByHeight, err := client.BtcStakingParamByBtcHeight(sctx, uint32(resp.Blk.Height))
+		if err != nil {
+			return fmt.Errorf("failed to get btc staking parameters: %w", err)
+		}
+
+		covenantPks = respParamsByHeight.StakingParams.CovenantPkHex
+		if covenantQuorum == 0 {
+			covenantQuorum = uint64(respParamsByHeight.StakingParams.CovenantQuorum)
+		}
+	}
+
+	result, err := client.RetryDelegationRegistration(sctx, stakingTransactionHash, covenantPks, uint32(covenantQuorum))
+	if err != nil {
+		return fmt.Errorf("failed to retry delegation registration: %w", err)
+	}
+
+	return helpers.PrintResp(ctx, result)
+}
+
+func renotifyCovenant(ctx *cli.Context) error {
+	daemonAddress := ctx.String(helpers.StakingDaemonAddressFlag)
+	client, err := NewStakerServiceJSONRPCClient(daemonAddress)
+	if err != nil {
+		return fmt.Errorf("failed to create staker service JSON-RPC client: %w", err)
+	}
+
+	sctx := context.Background()
+
+	result, err := client.RenotifyCovenant(sctx, ctx.String(stakingTransactionHashFlag))
+	if err != nil {
+		return fmt.Errorf("failed to renotify covenant: %w", err)
+	}
+
+	return helpers.PrintResp(ctx, result)
+}
+
+func unstake(ctx *cli.Context) error {
+	daemonAddress := ctx.String(helpers.StakingDaemonAddressFlag)
+	client, err := NewStakerServiceJSONRPCClient(daemonAddress)
+	if err != nil {
+		return fmt.Errorf("failed to create staker service JSON-RPC client: %w", err)
+	}
+
+	sctx := context.Background()
+
+	stakingTransactionHash := ctx.String(stakingTransactionHashFlag)
+
+	result, err := client.SpendStakingTransaction(sctx, stakingTransactionHash, ctx.Int64(feeRateFlag), ctx.Int64(targetConfFlag), ctx.String(tenantFlag))
+	if err != nil {
+		return fmt.Errorf("failed to spend staking transaction: %w", err)
+	}
+
+	return helpers.PrintResp(ctx, result)
+}
+
+// restakeFromUnbonded withdraws unbonded funds and stakes them again.
+func restakeFromUnbonded(ctx *cli.Context) error {
+	daemonAddress := ctx.String(helpers.StakingDaemonAddressFlag)
+	client, err := NewStakerServiceJSONRPCClient(daemonAddress)
+	if err != nil {
+		return fmt.Errorf("failed to create staker service JSON-RPC client: %w", err)
+	}
+
+	sctx := context.Background()
+
+	stakingTransactionHash := ctx.String(stakingTransactionHashFlag)
+	fpPks := ctx.StringSlice(fpPksFlag)
+	stakingTimeBlocks := ctx.Int64(helpers.StakingTimeBlocksFlag)
+
+	result, err := client.RestakeFromUnbonded(sctx, stakingTransactionHash, fpPks, stakingTimeBlocks)
+	if err != nil {
+		return fmt.Errorf("failed to restake: %w", err)
+	}
+
+	return helpers.PrintResp(ctx, result)
+}
+
+// cancelStake abandons staking transaction not yet confirmed on btc.
+func cancelStake(ctx *cli.Context) error {
+	daemonAddress := ctx.String(helpers.StakingDaemonAddressFlag)
+	client, err := NewStakerServiceJSONRPCClient(daemonAddress)
+	if err != nil {
+		return fmt.Errorf("failed to create staker service JSON-RPC client: %w", err)
+	}
+
+	sctx := context.Background()
+
+	stakingTransactionHash := ctx.String(stakingTransactionHashFlag)
+
+	result, err := client.CancelStake(sctx, stakingTransactionHash)
+	if err != nil {
+		return fmt.Errorf("failed to cancel stake: %w", err)
+	}
+
+	return helpers.PrintResp(ctx, result)
+}
+
+// unbond unbonds a staking transaction.
+func unbond(ctx *cli.Context) error {
+	daemonAddress := ctx.String(helpers.StakingDaemonAddressFlag)
+	client, err := NewStakerServiceJSONRPCClient(daemonAddress)
+	if err != nil {
+		return fmt.Errorf("failed to create staker service JSON-RPC client: %w", err)
+	}
+
+	sctx := context.Background()
+
+	stakingTransactionHash := ctx.String(stakingTransactionHashFlag)
+
+	result, err := client.UnbondStaking(sctx, stakingTransactionHash, ctx.Int64(feeRateFlag), ctx.Int64(targetConfFlag), ctx.String(tenantFlag))
+	if err != nil {
+		return fmt.Errorf("failed to unbond staking: %w", err)
+	}
+
+	return helpers.PrintResp(ctx, result)
+}
+
+func signSpendTx(ctx *cli.Context) error {
+	daemonAddress := ctx.String(helpers.StakingDaemonAddressFlag)
+	client, err := NewStakerServiceJSONRPCClient(daemonAddress)
+	if err != nil {
+		return fmt.Errorf("failed to create staker service JSON-RPC client: %w", err)
+	}
+
+	sctx := context.Background()
+
+	result, err := client.SignSpendTx(
+		sctx,
+		ctx.String(stakingTransactionHashFlag),
+		ctx.String(kindFlag),
+		ctx.String(txHexFlag),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to sign spend transaction: %w", err)
+	}
+
+	return helpers.PrintResp(ctx, result)
+}
+
+func listOperations(ctx *cli.Context) error {
+	daemonAddress := ctx.String(helpers.StakingDaemonAddressFlag)
+	client, err := NewStakerServiceJSONRPCClient(daemonAddress)
+	if err != nil {
+		return fmt.Errorf("failed to create staker service JSON-RPC client: %w", err)
+	}
+
+	sctx := context.Background()
+
+	result, err := client.ListOperations(sctx)
+	if err != nil {
+		return fmt.Errorf("failed to list operations: %w", err)
+	}
+
+	return helpers.PrintResp(ctx, result)
+}
+
+func approveOperation(ctx *cli.Context) error {
+	daemonAddress := ctx.String(helpers.StakingDaemonAddressFlag)
+	client, err := NewStakerServiceJSONRPCClient(daemonAddress)
+	if err != nil {
+		return fmt.Errorf("failed to create staker service JSON-RPC client: %w", err)
+	}
+
+	sctx := context.Background()
+
+	result, err := client.ApproveOperation(sctx, ctx.String(operationIDFlag))
+	if err != nil {
+		return fmt.Errorf("failed to approve operation: %w", err)
+	}
+
+	return helpers.PrintResp(ctx, result)
+}
+
+func listTemplates(ctx *cli.Context) error {
+	daemonAddress := ctx.String(helpers.StakingDaemonAddressFlag)
+	client, err := NewStakerServiceJSONRPCClient(daemonAddress)
+	if err != nil {
+		return fmt.Errorf("failed to create staker service JSON-RPC client: %w", err)
+	}
+
+	sctx := context.Background()
+
+	result, err := client.DelegationTemplates(sctx)
+	if err != nil {
+		return fmt.Errorf("failed to list templates: %w", err)
+	}
+
+	return helpers.PrintResp(ctx, result)
+}
+
+func setTemplate(ctx *cli.Context) error {
+	daemonAddress := ctx.String(helpers.StakingDaemonAddressFlag)
+	client, err := NewStakerServiceJSONRPCClient(daemonAddress)
+	if err != nil {
+		return fmt.Errorf("failed to create staker service JSON-RPC client: %w", err)
+	}
+
+	sctx := context.Background()
+
+	result, err := client.SetDelegationTemplate(
+		sctx,
+		ctx.String(nameFlag),
+		ctx.String(stakerAddressFlag),
+		ctx.StringSlice(fpPksFlag),
+		ctx.Int64(helpers.StakingTimeBlocksFlag),
+		ctx.Int64(maxFeeRateFlag),
+		ctx.String(tenantFlag),
+		ctx.StringSlice(labelFlag),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to set template: %w", err)
+	}
+
+	return helpers.PrintResp(ctx, result)
+}
+
+func deleteTemplate(ctx *cli.Context) error {
+	daemonAddress := ctx.String(helpers.StakingDaemonAddressFlag)
+	client, err := NewStakerServiceJSONRPCClient(daemonAddress)
+	if err != nil {
+		return fmt.Errorf("failed to create staker service JSON-RPC client: %w", err)
+	}
+
+	sctx := context.Background()
+
+	result, err := client.DeleteDelegationTemplate(sctx, ctx.String(nameFlag))
+	if err != nil {
+		return fmt.Errorf("failed to delete template: %w", err)
+	}
+
+	return helpers.PrintResp(ctx, result)
+}
+
+func listQueuedStakes(ctx *cli.Context) error {
+	daemonAddress := ctx.String(helpers.StakingDaemonAddressFlag)
+	client, err := NewStakerServiceJSONRPCClient(daemonAddress)
+	if err != nil {
+		return fmt.Errorf("failed to create staker service JSON-RPC client: %w", err)
+	}
+
+	sctx := context.Background()
+
+	result, err := client.QueuedStakes(sctx)
 	if err != nil {
-		return fmt.Errorf("failed to check health: %w", err)
+		return fmt.Errorf("failed to list queued stakes: %w", err)
 	}
 
-	helpers.PrintRespJSON(health)
-
-	return nil
+	return helpers.PrintResp(ctx, result)
 }
 
-// listOutputs lists current unspent outputs in connected wallet.
-func listOutputs(ctx *cli.Context) error {
+func cancelQueuedStake(ctx *cli.Context) error {
 	daemonAddress := ctx.String(helpers.StakingDaemonAddressFlag)
 	client, err := NewStakerServiceJSONRPCClient(daemonAddress)
 	if err != nil {
@@ -359,19 +2002,15 @@ func listOutputs(ctx *cli.Context) error {
 
 	sctx := context.Background()
 
-	outputs, err := client.ListOutputs(sctx)
-
+	result, err := client.CancelQueuedStake(sctx, ctx.String(queuedStakeIDFlag))
 	if err != nil {
-		return fmt.Errorf("failed to list outputs: %w", err)
+		return fmt.Errorf("failed to cancel queued stake: %w", err)
 	}
 
-	helpers.PrintRespJSON(outputs)
-
-	return nil
+	return helpers.PrintResp(ctx, result)
 }
 
-// babylonFinalityProviders lists current finality providers.
-func babylonFinalityProviders(ctx *cli.Context) error {
+func delegationHistory(ctx *cli.Context) error {
 	daemonAddress := ctx.String(helpers.StakingDaemonAddressFlag)
 	client, err := NewStakerServiceJSONRPCClient(daemonAddress)
 	if err != nil {
@@ -380,31 +2019,32 @@ func babylonFinalityProviders(ctx *cli.Context) error {
 
 	sctx := context.Background()
 
-	offset := ctx.Int(offsetFlag)
-
-	if offset < 0 {
-		return cli.NewExitError("Offset must be non-negative", 1)
+	result, err := client.DelegationHistory(sctx, ctx.String(stakingTransactionHashFlag))
+	if err != nil {
+		return fmt.Errorf("failed to get delegation history: %w", err)
 	}
 
-	limit := ctx.Int(limitFlag)
+	return helpers.PrintResp(ctx, result)
+}
 
-	if limit < 0 {
-		return cli.NewExitError("Limit must be non-negative", 1)
+func activationLatency(ctx *cli.Context) error {
+	daemonAddress := ctx.String(helpers.StakingDaemonAddressFlag)
+	client, err := NewStakerServiceJSONRPCClient(daemonAddress)
+	if err != nil {
+		return fmt.Errorf("failed to create staker service JSON-RPC client: %w", err)
 	}
 
-	finalityProviders, err := client.BabylonFinalityProviders(sctx, &offset, &limit)
+	sctx := context.Background()
 
+	result, err := client.ActivationLatency(sctx, ctx.String(stakingTransactionHashFlag))
 	if err != nil {
-		return fmt.Errorf("failed to get finality providers: %w", err)
+		return fmt.Errorf("failed to get activation latency: %w", err)
 	}
 
-	helpers.PrintRespJSON(finalityProviders)
-
-	return nil
+	return helpers.PrintResp(ctx, result)
 }
 
-// stake stakes a BTC.
-func stake(ctx *cli.Context) error {
+func exportDelegation(ctx *cli.Context) error {
 	daemonAddress := ctx.String(helpers.StakingDaemonAddressFlag)
 	client, err := NewStakerServiceJSONRPCClient(daemonAddress)
 	if err != nil {
@@ -413,54 +2053,69 @@ func stake(ctx *cli.Context) error {
 
 	sctx := context.Background()
 
-	stakerAddress := ctx.String(stakerAddressFlag)
-	stakingAmount := ctx.Int64(helpers.StakingAmountFlag)
-	fpPks := ctx.StringSlice(fpPksFlag)
-	stakingTimeBlocks := ctx.Int64(helpers.StakingTimeBlocksFlag)
+	result, err := client.ExportDelegation(sctx, ctx.String(stakingTransactionHashFlag))
+	if err != nil {
+		return fmt.Errorf("failed to export delegation: %w", err)
+	}
+
+	outFile := ctx.String(outFileFlag)
+	if outFile == "" {
+		return helpers.PrintResp(ctx, result)
+	}
 
-	results, err := client.Stake(sctx, stakerAddress, stakingAmount, fpPks, stakingTimeBlocks)
+	bundle, err := json.MarshalIndent(result, "", "    ")
 	if err != nil {
-		return fmt.Errorf("failed to stake: %w", err)
+		return fmt.Errorf("failed to encode delegation export: %w", err)
 	}
 
-	helpers.PrintRespJSON(results)
+	if err := os.WriteFile(outFile, bundle, 0600); err != nil {
+		return fmt.Errorf("failed to write delegation export: %w", err)
+	}
 
+	fmt.Printf("Delegation export written to %s\n", outFile)
 	return nil
 }
 
-// stakeExpand creates a new btc staking transaction from an previous
-// active BTC staking delegation and another new input.
-func stakeExpand(ctx *cli.Context) error {
+func importDelegation(ctx *cli.Context) error {
 	daemonAddress := ctx.String(helpers.StakingDaemonAddressFlag)
 	client, err := NewStakerServiceJSONRPCClient(daemonAddress)
 	if err != nil {
 		return fmt.Errorf("failed to create staker service JSON-RPC client: %w", err)
 	}
 
-	sctx := context.Background()
+	bundle, err := os.ReadFile(ctx.String(bundleFileFlag))
+	if err != nil {
+		return fmt.Errorf("failed to read delegation bundle: %w", err)
+	}
 
-	stakerAddress := ctx.String(stakerAddressFlag)
-	stakingAmount := ctx.Int64(helpers.StakingAmountFlag)
-	fpPks := ctx.StringSlice(fpPksFlag)
-	stakingTimeBlocks := ctx.Int64(helpers.StakingTimeBlocksFlag)
+	sctx := context.Background()
 
-	prevActiveStkTxHashHex := ctx.String(stakingTransactionHashFlag)
-	if len(prevActiveStkTxHashHex) == 0 {
-		return errors.New("previous active staking tx hash hex for stake expansion is empty")
+	result, err := client.ImportDelegation(sctx, string(bundle))
+	if err != nil {
+		return fmt.Errorf("failed to import delegation: %w", err)
 	}
 
-	results, err := client.StakeExpand(sctx, stakerAddress, stakingAmount, fpPks, stakingTimeBlocks, prevActiveStkTxHashHex)
+	return helpers.PrintResp(ctx, result)
+}
+
+func listStuckDelegations(ctx *cli.Context) error {
+	daemonAddress := ctx.String(helpers.StakingDaemonAddressFlag)
+	client, err := NewStakerServiceJSONRPCClient(daemonAddress)
 	if err != nil {
-		return fmt.Errorf("failed to stake expand: %w", err)
+		return fmt.Errorf("failed to create staker service JSON-RPC client: %w", err)
 	}
 
-	helpers.PrintRespJSON(results)
+	sctx := context.Background()
+
+	result, err := client.ListStuckDelegations(sctx)
+	if err != nil {
+		return fmt.Errorf("failed to list stuck delegations: %w", err)
+	}
 
-	return nil
+	return helpers.PrintResp(ctx, result)
 }
 
-// consolidateUtxos consolidates small UTXOs into a single larger UTXO
-func consolidateUtxos(ctx *cli.Context) error {
+func expiringDelegations(ctx *cli.Context) error {
 	daemonAddress := ctx.String(helpers.StakingDaemonAddressFlag)
 	client, err := NewStakerServiceJSONRPCClient(daemonAddress)
 	if err != nil {
@@ -469,21 +2124,15 @@ func consolidateUtxos(ctx *cli.Context) error {
 
 	sctx := context.Background()
 
-	stakerAddress := ctx.String(stakerAddressFlag)
-	targetAmount := ctx.Int64(targetAmountFlag)
-
-	result, err := client.ConsolidateUTXOs(sctx, stakerAddress, targetAmount)
+	result, err := client.ExpiringDelegations(sctx, ctx.Int64(withinBlocksFlag))
 	if err != nil {
-		return fmt.Errorf("failed to consolidate UTXOs: %w", err)
+		return fmt.Errorf("failed to list expiring delegations: %w", err)
 	}
 
-	helpers.PrintRespJSON(result)
-
-	return nil
+	return helpers.PrintResp(ctx, result)
 }
 
-// stakeFromPhase1TxBTC delegates a staking transaction from a phase 1 tx BTC.
-func stakeFromPhase1TxBTC(ctx *cli.Context) error {
+func setAutoRenew(ctx *cli.Context) error {
 	daemonAddress := ctx.String(helpers.StakingDaemonAddressFlag)
 	client, err := NewStakerServiceJSONRPCClient(daemonAddress)
 	if err != nil {
@@ -491,36 +2140,38 @@ func stakeFromPhase1TxBTC(ctx *cli.Context) error {
 	}
 
 	sctx := context.Background()
-	stakingTransactionHash := ctx.String(stakingTransactionHashFlag)
-	if len(stakingTransactionHash) == 0 {
-		return errors.New("staking tx hash hex is empty")
-	}
-
-	blockHeighTxInclusion := ctx.Uint64(helpers.TxInclusionHeightFlag)
-	if blockHeighTxInclusion == 0 {
-		resp, err := client.BtcTxDetails(sctx, stakingTransactionHash)
-		if err != nil {
-			return fmt.Errorf("error to get btc tx and block data from staking tx %s: %w", stakingTransactionHash, err)
-		}
 
-		blockHeighTxInclusion = uint64(resp.Blk.Height)
+	result, err := client.SetAutoRenew(
+		sctx,
+		ctx.String(stakingTransactionHashFlag),
+		!ctx.Bool(disableFlag),
+		ctx.Int64(helpers.StakingTimeBlocksFlag),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to set auto renew: %w", err)
 	}
 
-	respParamsByHeight, err := client.BtcStakingParamByBtcHeight(sctx, uint32(blockHeighTxInclusion))
+	return helpers.PrintResp(ctx, result)
+}
+
+func renewalJobs(ctx *cli.Context) error {
+	daemonAddress := ctx.String(helpers.StakingDaemonAddressFlag)
+	client, err := NewStakerServiceJSONRPCClient(daemonAddress)
 	if err != nil {
-		return fmt.Errorf("failed to get btc staking parameters: %w", err)
+		return fmt.Errorf("failed to create staker service JSON-RPC client: %w", err)
 	}
-	btcStakingParams := respParamsByHeight.StakingParams
 
-	stakerAddress := ctx.String(stakerAddressFlag)
-	_, err = client.BtcDelegationFromBtcStakingTx(sctx, stakerAddress, stakingTransactionHash, btcStakingParams.CovenantPkHex, btcStakingParams.CovenantQuorum)
+	sctx := context.Background()
+
+	result, err := client.RenewalJobs(sctx)
 	if err != nil {
-		return fmt.Errorf("failed to delegate from btc staking tx: %w", err)
+		return fmt.Errorf("failed to list renewal jobs: %w", err)
 	}
-	return nil
+
+	return helpers.PrintResp(ctx, result)
 }
 
-func unstake(ctx *cli.Context) error {
+func chainSafety(ctx *cli.Context) error {
 	daemonAddress := ctx.String(helpers.StakingDaemonAddressFlag)
 	client, err := NewStakerServiceJSONRPCClient(daemonAddress)
 	if err != nil {
@@ -529,20 +2180,15 @@ func unstake(ctx *cli.Context) error {
 
 	sctx := context.Background()
 
-	stakingTransactionHash := ctx.String(stakingTransactionHashFlag)
-
-	result, err := client.SpendStakingTransaction(sctx, stakingTransactionHash)
+	result, err := client.ChainSafety(sctx)
 	if err != nil {
-		return fmt.Errorf("failed to spend staking transaction: %w", err)
+		return fmt.Errorf("failed to get chain safety status: %w", err)
 	}
 
-	helpers.PrintRespJSON(result)
-
-	return nil
+	return helpers.PrintResp(ctx, result)
 }
 
-// unbondStaking unbonds a staking transaction.
-func unbond(ctx *cli.Context) error {
+func maintenanceStatus(ctx *cli.Context) error {
 	daemonAddress := ctx.String(helpers.StakingDaemonAddressFlag)
 	client, err := NewStakerServiceJSONRPCClient(daemonAddress)
 	if err != nil {
@@ -551,16 +2197,29 @@ func unbond(ctx *cli.Context) error {
 
 	sctx := context.Background()
 
-	stakingTransactionHash := ctx.String(stakingTransactionHashFlag)
+	result, err := client.MaintenanceStatus(sctx)
+	if err != nil {
+		return fmt.Errorf("failed to get maintenance status: %w", err)
+	}
 
-	result, err := client.UnbondStaking(sctx, stakingTransactionHash)
+	return helpers.PrintResp(ctx, result)
+}
+
+func setChainSafetyOverride(ctx *cli.Context) error {
+	daemonAddress := ctx.String(helpers.StakingDaemonAddressFlag)
+	client, err := NewStakerServiceJSONRPCClient(daemonAddress)
 	if err != nil {
-		return fmt.Errorf("failed to unbond staking: %w", err)
+		return fmt.Errorf("failed to create staker service JSON-RPC client: %w", err)
 	}
 
-	helpers.PrintRespJSON(result)
+	sctx := context.Background()
 
-	return nil
+	result, err := client.SetChainSafetyOverride(sctx, !ctx.Bool(clearFlag))
+	if err != nil {
+		return fmt.Errorf("failed to set chain safety override: %w", err)
+	}
+
+	return helpers.PrintResp(ctx, result)
 }
 
 // stakingDetails gets the details of a staking transaction.
@@ -575,14 +2234,46 @@ func stakingDetails(ctx *cli.Context) error {
 
 	stakingTransactionHash := ctx.String(stakingTransactionHashFlag)
 
-	result, err := client.StakingDetails(sctx, stakingTransactionHash)
+	result, err := client.StakingDetails(sctx, stakingTransactionHash, ctx.String(tenantFlag))
+	if err != nil {
+		return fmt.Errorf("failed to get staking details: %w", err)
+	}
+
+	return helpers.PrintResp(ctx, result)
+}
+
+func stakingDetailsBatch(ctx *cli.Context) error {
+	daemonAddress := ctx.String(helpers.StakingDaemonAddressFlag)
+	client, err := NewStakerServiceJSONRPCClient(daemonAddress)
+	if err != nil {
+		return fmt.Errorf("failed to create staker service JSON-RPC client: %w", err)
+	}
+
+	sctx := context.Background()
+
+	result, err := client.StakingDetailsBatch(sctx, ctx.StringSlice(stakingTransactionHashFlag), ctx.String(tenantFlag))
 	if err != nil {
 		return fmt.Errorf("failed to get staking details: %w", err)
 	}
 
-	helpers.PrintRespJSON(result)
+	return helpers.PrintResp(ctx, result)
+}
 
-	return nil
+func findDelegationByTx(ctx *cli.Context) error {
+	daemonAddress := ctx.String(helpers.StakingDaemonAddressFlag)
+	client, err := NewStakerServiceJSONRPCClient(daemonAddress)
+	if err != nil {
+		return fmt.Errorf("failed to create staker service JSON-RPC client: %w", err)
+	}
+
+	sctx := context.Background()
+
+	result, err := client.FindDelegationByTx(sctx, ctx.String(txHashFlag), ctx.String(tenantFlag))
+	if err != nil {
+		return fmt.Errorf("failed to find delegation: %w", err)
+	}
+
+	return helpers.PrintResp(ctx, result)
 }
 
 // listStakingTransactions lists all the staking transactions.
@@ -598,24 +2289,33 @@ func listStakingTransactions(ctx *cli.Context) error {
 	offset := ctx.Int(offsetFlag)
 
 	if offset < 0 {
-		return cli.NewExitError("Offset must be non-negative", 1)
+		return cli.NewExitError("Offset must be non-negative", helpers.ExitCodeInvalidArgs)
 	}
 
 	limit := ctx.Int(limitFlag)
 
 	if limit < 0 {
-		return cli.NewExitError("Limit must be non-negative", 1)
+		return cli.NewExitError("Limit must be non-negative", helpers.ExitCodeInvalidArgs)
 	}
 
-	transactions, err := client.ListStakingTransactions(sctx, &offset, &limit)
+	sortBy := ctx.String(sortByFlag)
+	sortDirection := ctx.String(sortDirectionFlag)
+
+	transactions, err := client.ListStakingTransactions(
+		sctx,
+		&offset,
+		&limit,
+		optionalStringFlag(ctx, fieldsFlag),
+		&sortBy,
+		&sortDirection,
+		optionalStringFlag(ctx, tenantFlag),
+	)
 
 	if err != nil {
 		return fmt.Errorf("failed to get staking transactions: %w", err)
 	}
 
-	helpers.PrintRespJSON(transactions)
-
-	return nil
+	return helpers.PrintResp(ctx, transactions)
 }
 
 // withdrawableTransactions lists all the withdrawable staking transactions.
@@ -630,23 +2330,163 @@ func withdrawableTransactions(ctx *cli.Context) error {
 
 	offset := ctx.Int(offsetFlag)
 	if offset < 0 {
-		return cli.NewExitError("Offset must be non-negative", 1)
+		return cli.NewExitError("Offset must be non-negative", helpers.ExitCodeInvalidArgs)
 	}
 
 	limit := ctx.Int(limitFlag)
 	if limit < 0 {
-		return cli.NewExitError("Limit must be non-negative", 1)
+		return cli.NewExitError("Limit must be non-negative", helpers.ExitCodeInvalidArgs)
 	}
 
-	transactions, err := client.WithdrawableTransactions(sctx, &offset, &limit)
+	transactions, err := client.WithdrawableTransactions(sctx, &offset, &limit, optionalStringFlag(ctx, fieldsFlag), ctx.String(tenantFlag))
 
 	if err != nil {
 		return err
 	}
 
-	helpers.PrintRespJSON(transactions)
+	return helpers.PrintResp(ctx, transactions)
+}
 
-	return nil
+// stakingActivity displays delegations and withdrawals grouped by time period.
+func stakingActivity(ctx *cli.Context) error {
+	daemonAddress := ctx.String(helpers.StakingDaemonAddressFlag)
+	client, err := NewStakerServiceJSONRPCClient(daemonAddress)
+	if err != nil {
+		return fmt.Errorf("failed to create staker service JSON-RPC client: %w", err)
+	}
+
+	sctx := context.Background()
+
+	period := ctx.String(periodFlag)
+
+	result, err := client.StakingActivity(sctx, &period)
+	if err != nil {
+		return fmt.Errorf("failed to get staking activity: %w", err)
+	}
+
+	return helpers.PrintResp(ctx, result)
+}
+
+// btcStakingParams gets btc staking parameters valid at given BTC height.
+func btcStakingParams(ctx *cli.Context) error {
+	daemonAddress := ctx.String(helpers.StakingDaemonAddressFlag)
+	client, err := NewStakerServiceJSONRPCClient(daemonAddress)
+	if err != nil {
+		return fmt.Errorf("failed to create staker service JSON-RPC client: %w", err)
+	}
+
+	sctx := context.Background()
+
+	btcHeight := ctx.Uint64(btcHeightFlag)
+	if btcHeight > math.MaxUint32 {
+		return cli.NewExitError(fmt.Sprintf("BTC height must be less or equal to %d", uint32(math.MaxUint32)), helpers.ExitCodeInvalidArgs)
+	}
+
+	result, err := client.BtcStakingParamByBtcHeight(sctx, uint32(btcHeight))
+	if err != nil {
+		return fmt.Errorf("failed to get btc staking parameters: %w", err)
+	}
+
+	return helpers.PrintResp(ctx, result)
+}
+
+// archivedParams lists btc staking parameters versions archived by the daemon.
+func archivedParams(ctx *cli.Context) error {
+	daemonAddress := ctx.String(helpers.StakingDaemonAddressFlag)
+	client, err := NewStakerServiceJSONRPCClient(daemonAddress)
+	if err != nil {
+		return fmt.Errorf("failed to create staker service JSON-RPC client: %w", err)
+	}
+
+	sctx := context.Background()
+
+	result, err := client.ArchivedParams(sctx)
+	if err != nil {
+		return fmt.Errorf("failed to get archived params: %w", err)
+	}
+
+	return helpers.PrintResp(ctx, result)
+}
+
+// replicaStatus shows progress of replication of the primary.
+func replicaStatus(ctx *cli.Context) error {
+	daemonAddress := ctx.String(helpers.StakingDaemonAddressFlag)
+	client, err := NewStakerServiceJSONRPCClient(daemonAddress)
+	if err != nil {
+		return fmt.Errorf("failed to create staker service JSON-RPC client: %w", err)
+	}
+
+	sctx := context.Background()
+
+	result, err := client.ReplicaStatus(sctx)
+	if err != nil {
+		return fmt.Errorf("failed to get replica status: %w", err)
+	}
+
+	return helpers.PrintResp(ctx, result)
+}
+
+// fpQuotas shows usage and quotas of finality providers.
+func fpQuotas(ctx *cli.Context) error {
+	daemonAddress := ctx.String(helpers.StakingDaemonAddressFlag)
+	client, err := NewStakerServiceJSONRPCClient(daemonAddress)
+	if err != nil {
+		return fmt.Errorf("failed to create staker service JSON-RPC client: %w", err)
+	}
+
+	sctx := context.Background()
+
+	result, err := client.FinalityProviderQuotas(sctx)
+	if err != nil {
+		return fmt.Errorf("failed to get finality provider quotas: %w", err)
+	}
+
+	return helpers.PrintResp(ctx, result)
+}
+
+func quarantinedRecords(ctx *cli.Context) error {
+	daemonAddress := ctx.String(helpers.StakingDaemonAddressFlag)
+	client, err := NewStakerServiceJSONRPCClient(daemonAddress)
+	if err != nil {
+		return fmt.Errorf("failed to create staker service JSON-RPC client: %w", err)
+	}
+
+	sctx := context.Background()
+
+	result, err := client.QuarantinedRecords(sctx)
+	if err != nil {
+		return fmt.Errorf("failed to get quarantined records: %w", err)
+	}
+
+	return helpers.PrintResp(ctx, result)
+}
+
+// btcTxDetails gets BTC transaction and block details.
+func btcTxDetails(ctx *cli.Context) error {
+	daemonAddress := ctx.String(helpers.StakingDaemonAddressFlag)
+	client, err := NewStakerServiceJSONRPCClient(daemonAddress)
+	if err != nil {
+		return fmt.Errorf("failed to create staker service JSON-RPC client: %w", err)
+	}
+
+	sctx := context.Background()
+
+	result, err := client.BtcTxDetails(sctx, ctx.String(txHashFlag))
+	if err != nil {
+		return fmt.Errorf("failed to get btc tx details: %w", err)
+	}
+
+	return helpers.PrintResp(ctx, result)
+}
+
+// optionalStringFlag returns nil if flag was not set by the user
+func optionalStringFlag(ctx *cli.Context, name string) *string {
+	if !ctx.IsSet(name) {
+		return nil
+	}
+
+	value := ctx.String(name)
+	return &value
 }
 
 // NewStakerServiceJSONRPCClient creates a client connection with basic auth
@@ -654,12 +2494,12 @@ func withdrawableTransactions(ctx *cli.Context) error {
 func NewStakerServiceJSONRPCClient(remoteAddressWithoutAuth string) (*dc.StakerServiceJSONRPCClient, error) {
 	parsedURL, err := url.Parse(remoteAddressWithoutAuth)
 	if err != nil {
-		return nil, err
+		return nil, cli.NewExitError(fmt.Sprintf("invalid daemon address: %v", err), helpers.ExitCodeInvalidArgs)
 	}
 
 	user, pwd, err := cmd.GetEnvBasicAuth()
 	if err != nil {
-		return nil, err
+		return nil, cli.NewExitError(err.Error(), helpers.ExitCodeInvalidArgs)
 	}
 	parsedURL.User = url.UserPassword(user, pwd)
 
@@ -0,0 +1,130 @@
+package daemon
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/babylonlabs-io/btc-staker/cmd/stakercli/helpers"
+	"github.com/urfave/cli"
+)
+
+const (
+	resumeTokenFlag = "resume-token"
+	followFlag      = "follow"
+	waitSecsFlag    = "wait-secs"
+	afterEventFlag  = "after-event-id"
+)
+
+var dbChangesCmd = cli.Command{
+	Name:  "db-changes",
+	Usage: "Prints changes of the staker database in the order in which they were recorded",
+	Description: "Prints changes recorded after the resume token together with the token of the last " +
+		"printed change, which can be used to resume. With --follow, keeps waiting for new changes " +
+		"and prints every received batch until interrupted.",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  helpers.StakingDaemonAddressFlag,
+			Usage: "full address of the staker daemon in format tcp:://<host>:<port>",
+			Value: helpers.DefaultStakingDaemonAddress,
+		},
+		cli.StringFlag{
+			Name:  resumeTokenFlag,
+			Usage: "resume token returned by previous call, changes are printed from the start if not set",
+		},
+		cli.IntFlag{
+			Name:  limitFlag,
+			Usage: "maximum number of changes returned in one batch",
+			Value: 100,
+		},
+		cli.BoolFlag{
+			Name:  followFlag,
+			Usage: "keep waiting for new changes",
+		},
+		cli.IntFlag{
+			Name:  waitSecsFlag,
+			Usage: "how long daemon waits for new changes in one call in follow mode",
+			Value: 30,
+		},
+	},
+	Action: dbChanges,
+}
+
+var replayEventsCmd = cli.Command{
+	Name:  "replay-events",
+	Usage: "Sends webhook events with id greater than the given one to the webhook again",
+	Description: "Replays events already delivered to the configured webhook, e.g. after the webhook " +
+		"consumer lost events during downtime. Replayed events keep their ids and are marked as replay.",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  helpers.StakingDaemonAddressFlag,
+			Usage: "full address of the staker daemon in format tcp:://<host>:<port>",
+			Value: helpers.DefaultStakingDaemonAddress,
+		},
+		cli.Uint64Flag{
+			Name:     afterEventFlag,
+			Usage:    "id of the last event processed by the consumer",
+			Required: true,
+		},
+		cli.IntFlag{
+			Name:  limitFlag,
+			Usage: "maximum number of replayed events",
+			Value: 100,
+		},
+	},
+	Action: replayEvents,
+}
+
+func replayEvents(ctx *cli.Context) error {
+	client, err := NewStakerServiceJSONRPCClient(ctx.String(helpers.StakingDaemonAddressFlag))
+	if err != nil {
+		return fmt.Errorf("failed to create staker service JSON-RPC client: %w", err)
+	}
+
+	result, err := client.ReplayEvents(context.Background(), ctx.Uint64(afterEventFlag), ctx.Int(limitFlag))
+	if err != nil {
+		return fmt.Errorf("failed to replay events: %w", err)
+	}
+
+	return helpers.PrintResp(ctx, result)
+}
+
+func dbChanges(ctx *cli.Context) error {
+	client, err := NewStakerServiceJSONRPCClient(ctx.String(helpers.StakingDaemonAddressFlag))
+	if err != nil {
+		return fmt.Errorf("failed to create staker service JSON-RPC client: %w", err)
+	}
+
+	sctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	resumeToken := ctx.String(resumeTokenFlag)
+	limit := ctx.Int(limitFlag)
+
+	if !ctx.Bool(followFlag) {
+		result, err := client.SubscribeDBChanges(sctx, resumeToken, limit, 0)
+		if err != nil {
+			return fmt.Errorf("failed to get db changes: %w", err)
+		}
+
+		return helpers.PrintResp(ctx, result)
+	}
+
+	for {
+		result, err := client.SubscribeDBChanges(sctx, resumeToken, limit, ctx.Int(waitSecsFlag))
+		if sctx.Err() != nil {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to get db changes: %w", err)
+		}
+
+		if len(result.Changes) > 0 {
+			helpers.PrintRespJSON(result)
+		}
+
+		resumeToken = result.ResumeToken
+	}
+}
@@ -0,0 +1,328 @@
+package daemon
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/babylonlabs-io/btc-staker/cmd/stakercli/helpers"
+	"github.com/urfave/cli"
+)
+
+const (
+	signerPksFlag   = "signer-pks"
+	keyNameFlag     = "key-name"
+	sessionIDFlag   = "session-id"
+	signerPkFlag    = "signer-pk"
+	nonceFlag       = "nonce"
+	partialSigFlag  = "partial-sig"
+	destAddressFlag = "dest-address"
+)
+
+var musig2Cmd = cli.Command{
+	Name:  "musig2",
+	Usage: "Manage MuSig2 staker keys shared by several signers and coordinate their signing sessions",
+	Description: "Staker key of a delegation can be a MuSig2 aggregate of keys of several signers. " +
+		"Every signature of such key is produced in a session: each signer submits a public nonce, " +
+		"then a partial signature over the session message. Once all partial signatures are " +
+		"submitted, they are combined and signed staking, unbonding or withdrawal transaction is sent to btc.",
+	Subcommands: []cli.Command{
+		registerMuSig2KeyCmd,
+		listMuSig2KeysCmd,
+		startMuSig2SessionCmd,
+		listMuSig2SessionsCmd,
+		muSig2SessionCmd,
+		submitMuSig2NonceCmd,
+		submitMuSig2PartialSigCmd,
+	},
+}
+
+var registerMuSig2KeyCmd = cli.Command{
+	Name:  "register-key",
+	Usage: "Aggregate public keys of signers into MuSig2 staker key",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  helpers.StakingDaemonAddressFlag,
+			Usage: "full address of the staker daemon in format tcp:://<host>:<port>",
+			Value: helpers.DefaultStakingDaemonAddress,
+		},
+		cli.StringFlag{
+			Name:     nameFlag,
+			Usage:    "name of the key",
+			Required: true,
+		},
+		cli.StringSliceFlag{
+			Name:     signerPksFlag,
+			Usage:    "compressed public keys of signers in hex, at least two",
+			Required: true,
+		},
+	},
+	Action: registerMuSig2Key,
+}
+
+var listMuSig2KeysCmd = cli.Command{
+	Name:  "keys",
+	Usage: "List registered MuSig2 staker keys",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  helpers.StakingDaemonAddressFlag,
+			Usage: "full address of the staker daemon in format tcp:://<host>:<port>",
+			Value: helpers.DefaultStakingDaemonAddress,
+		},
+	},
+	Action: listMuSig2Keys,
+}
+
+var startMuSig2SessionCmd = cli.Command{
+	Name:  "start-session",
+	Usage: "Start signing session of MuSig2 staker key",
+	Description: "Kind pop signs proof of possession over babylon address of the staker daemon. " +
+		"Kind staking signs staking transaction given by --tx-hex, whose single input spends taproot " +
+		"output of the MuSig2 key. Kind unbonding signs unbonding transaction registered on babylon, " +
+		"kind withdrawal signs transaction sending expired stake to destination address. Both require " +
+		"staking transaction hash.",
+	Flags: append([]cli.Flag{
+		cli.StringFlag{
+			Name:  helpers.StakingDaemonAddressFlag,
+			Usage: "full address of the staker daemon in format tcp:://<host>:<port>",
+			Value: helpers.DefaultStakingDaemonAddress,
+		},
+		cli.StringFlag{
+			Name:     keyNameFlag,
+			Usage:    "name of the MuSig2 key",
+			Required: true,
+		},
+		cli.StringFlag{
+			Name:     kindFlag,
+			Usage:    "kind of the session, one of pop, staking, unbonding, withdrawal",
+			Required: true,
+		},
+		cli.StringFlag{
+			Name:  stakingTransactionHashFlag,
+			Usage: "hash of the staking transaction of delegation of the MuSig2 key",
+		},
+		cli.StringFlag{
+			Name:  txHexFlag,
+			Usage: "unsigned staking transaction in hex, required for staking session",
+		},
+		cli.StringFlag{
+			Name:  destAddressFlag,
+			Usage: "address to which withdrawn funds are sent",
+		},
+	}, feeSelectionFlags...),
+	Action: startMuSig2Session,
+}
+
+var listMuSig2SessionsCmd = cli.Command{
+	Name:  "sessions",
+	Usage: "List MuSig2 signing sessions",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  helpers.StakingDaemonAddressFlag,
+			Usage: "full address of the staker daemon in format tcp:://<host>:<port>",
+			Value: helpers.DefaultStakingDaemonAddress,
+		},
+	},
+	Action: listMuSig2Sessions,
+}
+
+var muSig2SessionCmd = cli.Command{
+	Name:  "session",
+	Usage: "Show MuSig2 signing session",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  helpers.StakingDaemonAddressFlag,
+			Usage: "full address of the staker daemon in format tcp:://<host>:<port>",
+			Value: helpers.DefaultStakingDaemonAddress,
+		},
+		cli.Uint64Flag{
+			Name:     sessionIDFlag,
+			Usage:    "id of the session",
+			Required: true,
+		},
+	},
+	Action: muSig2Session,
+}
+
+var submitMuSig2NonceCmd = cli.Command{
+	Name:  "submit-nonce",
+	Usage: "Submit public nonce of signer of MuSig2 signing session",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  helpers.StakingDaemonAddressFlag,
+			Usage: "full address of the staker daemon in format tcp:://<host>:<port>",
+			Value: helpers.DefaultStakingDaemonAddress,
+		},
+		cli.Uint64Flag{
+			Name:     sessionIDFlag,
+			Usage:    "id of the session",
+			Required: true,
+		},
+		cli.StringFlag{
+			Name:     signerPkFlag,
+			Usage:    "compressed public key of the signer in hex",
+			Required: true,
+		},
+		cli.StringFlag{
+			Name:     nonceFlag,
+			Usage:    "66 byte public nonce of the signer in hex",
+			Required: true,
+		},
+	},
+	Action: submitMuSig2Nonce,
+}
+
+var submitMuSig2PartialSigCmd = cli.Command{
+	Name:  "submit-partial-sig",
+	Usage: "Submit partial signature of signer of MuSig2 signing session",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  helpers.StakingDaemonAddressFlag,
+			Usage: "full address of the staker daemon in format tcp:://<host>:<port>",
+			Value: helpers.DefaultStakingDaemonAddress,
+		},
+		cli.Uint64Flag{
+			Name:     sessionIDFlag,
+			Usage:    "id of the session",
+			Required: true,
+		},
+		cli.StringFlag{
+			Name:     signerPkFlag,
+			Usage:    "compressed public key of the signer in hex",
+			Required: true,
+		},
+		cli.StringFlag{
+			Name:     partialSigFlag,
+			Usage:    "32 byte partial signature of the signer in hex",
+			Required: true,
+		},
+	},
+	Action: submitMuSig2PartialSig,
+}
+
+func registerMuSig2Key(ctx *cli.Context) error {
+	daemonAddress := ctx.String(helpers.StakingDaemonAddressFlag)
+	client, err := NewStakerServiceJSONRPCClient(daemonAddress)
+	if err != nil {
+		return fmt.Errorf("failed to create staker service JSON-RPC client: %w", err)
+	}
+
+	sctx := context.Background()
+
+	result, err := client.RegisterMuSig2Key(sctx, ctx.String(nameFlag), ctx.StringSlice(signerPksFlag))
+	if err != nil {
+		return fmt.Errorf("failed to register musig2 key: %w", err)
+	}
+
+	return helpers.PrintResp(ctx, result)
+}
+
+func listMuSig2Keys(ctx *cli.Context) error {
+	daemonAddress := ctx.String(helpers.StakingDaemonAddressFlag)
+	client, err := NewStakerServiceJSONRPCClient(daemonAddress)
+	if err != nil {
+		return fmt.Errorf("failed to create staker service JSON-RPC client: %w", err)
+	}
+
+	sctx := context.Background()
+
+	result, err := client.MuSig2Keys(sctx)
+	if err != nil {
+		return fmt.Errorf("failed to list musig2 keys: %w", err)
+	}
+
+	return helpers.PrintResp(ctx, result)
+}
+
+func startMuSig2Session(ctx *cli.Context) error {
+	daemonAddress := ctx.String(helpers.StakingDaemonAddressFlag)
+	client, err := NewStakerServiceJSONRPCClient(daemonAddress)
+	if err != nil {
+		return fmt.Errorf("failed to create staker service JSON-RPC client: %w", err)
+	}
+
+	sctx := context.Background()
+
+	result, err := client.StartMuSig2Session(
+		sctx,
+		ctx.String(keyNameFlag),
+		ctx.String(kindFlag),
+		ctx.String(stakingTransactionHashFlag),
+		ctx.String(txHexFlag),
+		ctx.String(destAddressFlag),
+		ctx.Int64(feeRateFlag),
+		ctx.Int64(targetConfFlag),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to start musig2 session: %w", err)
+	}
+
+	return helpers.PrintResp(ctx, result)
+}
+
+func listMuSig2Sessions(ctx *cli.Context) error {
+	daemonAddress := ctx.String(helpers.StakingDaemonAddressFlag)
+	client, err := NewStakerServiceJSONRPCClient(daemonAddress)
+	if err != nil {
+		return fmt.Errorf("failed to create staker service JSON-RPC client: %w", err)
+	}
+
+	sctx := context.Background()
+
+	result, err := client.MuSig2Sessions(sctx)
+	if err != nil {
+		return fmt.Errorf("failed to list musig2 sessions: %w", err)
+	}
+
+	return helpers.PrintResp(ctx, result)
+}
+
+func muSig2Session(ctx *cli.Context) error {
+	daemonAddress := ctx.String(helpers.StakingDaemonAddressFlag)
+	client, err := NewStakerServiceJSONRPCClient(daemonAddress)
+	if err != nil {
+		return fmt.Errorf("failed to create staker service JSON-RPC client: %w", err)
+	}
+
+	sctx := context.Background()
+
+	result, err := client.MuSig2Session(sctx, ctx.Uint64(sessionIDFlag))
+	if err != nil {
+		return fmt.Errorf("failed to get musig2 session: %w", err)
+	}
+
+	return helpers.PrintResp(ctx, result)
+}
+
+func submitMuSig2Nonce(ctx *cli.Context) error {
+	daemonAddress := ctx.String(helpers.StakingDaemonAddressFlag)
+	client, err := NewStakerServiceJSONRPCClient(daemonAddress)
+	if err != nil {
+		return fmt.Errorf("failed to create staker service JSON-RPC client: %w", err)
+	}
+
+	sctx := context.Background()
+
+	result, err := client.SubmitMuSig2Nonce(sctx, ctx.Uint64(sessionIDFlag), ctx.String(signerPkFlag), ctx.String(nonceFlag))
+	if err != nil {
+		return fmt.Errorf("failed to submit musig2 nonce: %w", err)
+	}
+
+	return helpers.PrintResp(ctx, result)
+}
+
+func submitMuSig2PartialSig(ctx *cli.Context) error {
+	daemonAddress := ctx.String(helpers.StakingDaemonAddressFlag)
+	client, err := NewStakerServiceJSONRPCClient(daemonAddress)
+	if err != nil {
+		return fmt.Errorf("failed to create staker service JSON-RPC client: %w", err)
+	}
+
+	sctx := context.Background()
+
+	result, err := client.SubmitMuSig2PartialSig(sctx, ctx.Uint64(sessionIDFlag), ctx.String(signerPkFlag), ctx.String(partialSigFlag))
+	if err != nil {
+		return fmt.Errorf("failed to submit musig2 partial signature: %w", err)
+	}
+
+	return helpers.PrintResp(ctx, result)
+}
@@ -0,0 +1,336 @@
+package daemon
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"sort"
+	"strconv"
+	"strings"
+	"syscall"
+	"text/tabwriter"
+	"time"
+
+	"github.com/babylonlabs-io/btc-staker/cmd/stakercli/helpers"
+	service "github.com/babylonlabs-io/btc-staker/stakerservice"
+	dc "github.com/babylonlabs-io/btc-staker/stakerservice/client"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/urfave/cli"
+	"golang.org/x/term"
+)
+
+const (
+	intervalFlag = "interval"
+	onceFlag     = "once"
+
+	// topMaxEvents is number of recent events kept by dashboard
+	topMaxEvents = 10
+	// topMaxRows is maximum number of rows displayed in dashboard lists
+	topMaxRows = 10
+
+	clearScreen = "\033[H\033[2J"
+)
+
+// pendingStates are delegation states in which staker waits for btc
+// confirmations or covenant signatures
+var pendingStates = map[string]bool{
+	"PENDING":  true,
+	"VERIFIED": true,
+}
+
+var topCmd = cli.Command{
+	Name:     "top",
+	Category: "Daemon commands",
+	Usage:    "Live dashboard of delegations, wallet balance and recent events",
+	Description: "Periodically polls staker daemon and displays delegations by state, delegations " +
+		"waiting for confirmations, withdrawable amounts, wallet balance and state changes observed " +
+		"since the dashboard was started. Press Ctrl+C to exit.",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  helpers.StakingDaemonAddressFlag,
+			Usage: "full address of the staker daemon in format tcp:://<host>:<port>",
+			Value: helpers.DefaultStakingDaemonAddress,
+		},
+		cli.DurationFlag{
+			Name:  intervalFlag,
+			Usage: "interval in which daemon is polled",
+			Value: 5 * time.Second,
+		},
+		cli.IntFlag{
+			Name:  limitFlag,
+			Usage: "maximum number of staking transactions fetched in one poll",
+			Value: 1000,
+		},
+		cli.BoolFlag{
+			Name:  onceFlag,
+			Usage: "print dashboard once and exit, implied when output is not a terminal",
+		},
+	},
+	Action: helpers.WithExitCodes(top),
+}
+
+// topSnapshot is state of the staker observed in a single poll
+type topSnapshot struct {
+	at                 time.Time
+	txStates           map[string]string
+	stakingAmounts     map[string]string
+	withdrawableCount  int
+	withdrawableAmount btcutil.Amount
+	walletBalance      btcutil.Amount
+	walletOutputs      int
+	totalFees          string
+}
+
+type topEvent struct {
+	at      time.Time
+	message string
+}
+
+type topDashboard struct {
+	daemonAddress string
+	interval      time.Duration
+	last          *topSnapshot
+	lastErr       error
+	events        []topEvent
+}
+
+func top(ctx *cli.Context) error {
+	daemonAddress := ctx.String(helpers.StakingDaemonAddressFlag)
+	client, err := NewStakerServiceJSONRPCClient(daemonAddress)
+	if err != nil {
+		return fmt.Errorf("failed to create staker service JSON-RPC client: %w", err)
+	}
+
+	limit := ctx.Int(limitFlag)
+	if limit <= 0 {
+		return cli.NewExitError("Limit must be positive", helpers.ExitCodeInvalidArgs)
+	}
+
+	interval := ctx.Duration(intervalFlag)
+	if interval <= 0 {
+		return cli.NewExitError("Interval must be positive", helpers.ExitCodeInvalidArgs)
+	}
+
+	sctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	dashboard := &topDashboard{
+		daemonAddress: daemonAddress,
+		interval:      interval,
+	}
+
+	if ctx.Bool(onceFlag) || !term.IsTerminal(int(os.Stdout.Fd())) {
+		snapshot, err := fetchTopSnapshot(sctx, client, limit)
+		if err != nil {
+			return err
+		}
+		dashboard.update(snapshot, nil)
+		dashboard.render(os.Stdout)
+		return nil
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		snapshot, err := fetchTopSnapshot(sctx, client, limit)
+		dashboard.update(snapshot, err)
+
+		fmt.Fprint(os.Stdout, clearScreen)
+		dashboard.render(os.Stdout)
+
+		select {
+		case <-sctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+func fetchTopSnapshot(
+	ctx context.Context,
+	client *dc.StakerServiceJSONRPCClient,
+	limit int,
+) (*topSnapshot, error) {
+	offset := 0
+	fields := "stakingTxHash,state,stakingAmount"
+	sortBy := "index"
+	sortDirection := "desc"
+
+	transactions, err := client.ListStakingTransactions(ctx, &offset, &limit, &fields, &sortBy, &sortDirection, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get staking transactions: %w", err)
+	}
+
+	withdrawable, err := client.WithdrawableTransactions(ctx, &offset, &limit, &fields, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get withdrawable transactions: %w", err)
+	}
+
+	outputs, err := client.ListOutputs(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list outputs: %w", err)
+	}
+
+	stats, err := client.Stats(ctx, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get stats: %w", err)
+	}
+
+	return newTopSnapshot(time.Now(), transactions, withdrawable, outputs, stats), nil
+}
+
+func newTopSnapshot(
+	at time.Time,
+	transactions *service.ListStakingTransactionsResponse,
+	withdrawable *service.WithdrawableTransactionsResponse,
+	outputs *service.OutputsResponse,
+	stats *service.StatsResponse,
+) *topSnapshot {
+	snapshot := &topSnapshot{
+		at:             at,
+		txStates:       make(map[string]string),
+		stakingAmounts: make(map[string]string),
+		walletOutputs:  len(outputs.Outputs),
+		totalFees:      stats.TotalFees,
+	}
+
+	for _, tx := range transactions.Transactions {
+		snapshot.txStates[tx.StakingTxHash] = tx.StakingState
+		snapshot.stakingAmounts[tx.StakingTxHash] = tx.StakingAmount
+	}
+
+	snapshot.withdrawableCount = len(withdrawable.Transactions)
+	for _, tx := range withdrawable.Transactions {
+		snapshot.withdrawableAmount += parseBtcAmount(tx.StakingAmount)
+	}
+
+	for _, out := range outputs.Outputs {
+		snapshot.walletBalance += parseBtcAmount(out.Amount)
+	}
+
+	return snapshot
+}
+
+// parseBtcAmount parses amount formatted by btcutil.Amount.String, returning
+// zero for unparsable values
+func parseBtcAmount(s string) btcutil.Amount {
+	value, err := strconv.ParseFloat(strings.TrimSuffix(s, " BTC"), 64)
+	if err != nil {
+		return 0
+	}
+
+	amount, err := btcutil.NewAmount(value)
+	if err != nil {
+		return 0
+	}
+
+	return amount
+}
+
+// update records changes between last and given snapshot as events. On
+// error last snapshot is kept so that dashboard still displays latest known
+// state.
+func (d *topDashboard) update(snapshot *topSnapshot, err error) {
+	d.lastErr = err
+	if err != nil {
+		return
+	}
+
+	if d.last != nil {
+		for _, hash := range sortedTxHashes(snapshot.txStates) {
+			state := snapshot.txStates[hash]
+			prevState, ok := d.last.txStates[hash]
+
+			switch {
+			case !ok:
+				d.addEvent(snapshot.at, fmt.Sprintf("%s tracked in state %s", hash, state))
+			case prevState != state:
+				d.addEvent(snapshot.at, fmt.Sprintf("%s %s -> %s", hash, prevState, state))
+			}
+		}
+
+		if snapshot.walletBalance != d.last.walletBalance {
+			d.addEvent(snapshot.at, fmt.Sprintf("wallet balance %s -> %s", d.last.walletBalance, snapshot.walletBalance))
+		}
+	}
+
+	d.last = snapshot
+}
+
+func (d *topDashboard) addEvent(at time.Time, message string) {
+	d.events = append(d.events, topEvent{at: at, message: message})
+	if len(d.events) > topMaxEvents {
+		d.events = d.events[len(d.events)-topMaxEvents:]
+	}
+}
+
+func (d *topDashboard) render(out io.Writer) {
+	w := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	defer w.Flush()
+
+	fmt.Fprintf(w, "stakercli top - %s - polling every %s\n", d.daemonAddress, d.interval)
+
+	if d.lastErr != nil {
+		fmt.Fprintf(w, "error: %v\n", d.lastErr)
+	}
+
+	if d.last == nil {
+		return
+	}
+
+	s := d.last
+	fmt.Fprintf(w, "updated at %s\n", s.at.Format(time.DateTime))
+
+	fmt.Fprintln(w, "\nWALLET")
+	fmt.Fprintf(w, "  balance\t%s in %d outputs\n", s.walletBalance, s.walletOutputs)
+	fmt.Fprintf(w, "  total fees paid\t%s\n", s.totalFees)
+
+	byState := make(map[string]int)
+	var pending []string
+	for hash, state := range s.txStates {
+		byState[state]++
+		if pendingStates[state] {
+			pending = append(pending, hash)
+		}
+	}
+	sort.Strings(pending)
+
+	fmt.Fprintf(w, "\nDELEGATIONS (%d)\n", len(s.txStates))
+	states := make([]string, 0, len(byState))
+	for state := range byState {
+		states = append(states, state)
+	}
+	sort.Strings(states)
+	for _, state := range states {
+		fmt.Fprintf(w, "  %s\t%d\n", state, byState[state])
+	}
+
+	fmt.Fprintf(w, "\nPENDING CONFIRMATION (%d)\n", len(pending))
+	for i, hash := range pending {
+		if i == topMaxRows {
+			fmt.Fprintf(w, "  ... and %d more\n", len(pending)-topMaxRows)
+			break
+		}
+		fmt.Fprintf(w, "  %s\t%s\t%s\n", hash, s.txStates[hash], s.stakingAmounts[hash])
+	}
+
+	fmt.Fprintln(w, "\nWITHDRAWABLE")
+	fmt.Fprintf(w, "  %d transactions\t%s\n", s.withdrawableCount, s.withdrawableAmount)
+
+	fmt.Fprintln(w, "\nRECENT EVENTS")
+	for i := len(d.events) - 1; i >= 0; i-- {
+		fmt.Fprintf(w, "  %s\t%s\n", d.events[i].at.Format(time.TimeOnly), d.events[i].message)
+	}
+}
+
+func sortedTxHashes(txStates map[string]string) []string {
+	hashes := make([]string, 0, len(txStates))
+	for hash := range txStates {
+		hashes = append(hashes, hash)
+	}
+	sort.Strings(hashes)
+	return hashes
+}
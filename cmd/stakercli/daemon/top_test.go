@@ -0,0 +1,58 @@
+package daemon
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	service "github.com/babylonlabs-io/btc-staker/stakerservice"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/stretchr/testify/require"
+)
+
+func testTopSnapshot(at time.Time, states map[string]string, balance string) *topSnapshot {
+	transactions := &service.ListStakingTransactionsResponse{}
+	for hash, state := range states {
+		transactions.Transactions = append(transactions.Transactions, service.StakingDetails{
+			StakingTxHash: hash,
+			StakingState:  state,
+			StakingAmount: "0.001 BTC",
+		})
+	}
+
+	return newTopSnapshot(
+		at,
+		transactions,
+		&service.WithdrawableTransactionsResponse{
+			Transactions: []service.StakingDetails{{StakingTxHash: "cc", StakingAmount: "0.002 BTC"}},
+		},
+		&service.OutputsResponse{Outputs: []service.OutputDetail{{Amount: balance}}},
+		&service.StatsResponse{TotalFees: "0.0001 BTC"},
+	)
+}
+
+func TestTopDashboardRecordsStateChanges(t *testing.T) {
+	now := time.Now()
+	d := &topDashboard{daemonAddress: "tcp://127.0.0.1:15812", interval: time.Second}
+
+	d.update(testTopSnapshot(now, map[string]string{"aa": "PENDING"}, "1 BTC"), nil)
+	require.Empty(t, d.events)
+
+	d.update(testTopSnapshot(now, map[string]string{"aa": "ACTIVE", "bb": "PENDING"}, "0.5 BTC"), nil)
+	require.Len(t, d.events, 3)
+	require.Equal(t, "aa PENDING -> ACTIVE", d.events[0].message)
+	require.Equal(t, "bb tracked in state PENDING", d.events[1].message)
+	require.Equal(t, "wallet balance 1 BTC -> 0.50000000 BTC", d.events[2].message)
+
+	// failed poll keeps last known state
+	d.update(nil, errors.New("connection refused"))
+	require.NotNil(t, d.last)
+	require.Equal(t, btcutil.Amount(200000), d.last.withdrawableAmount)
+
+	var buf bytes.Buffer
+	d.render(&buf)
+	require.Contains(t, buf.String(), "error: connection refused")
+	require.Contains(t, buf.String(), "PENDING CONFIRMATION (1)")
+	require.Contains(t, buf.String(), "0.50000000 BTC in 1 outputs")
+}
@@ -0,0 +1,158 @@
+package daemon
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/babylonlabs-io/btc-staker/cmd/stakercli/helpers"
+	service "github.com/babylonlabs-io/btc-staker/stakerservice"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/urfave/cli"
+)
+
+const (
+	waitTxFlag    = "tx"
+	waitStateFlag = "state"
+)
+
+// delegationStateOrder is position of Babylon delegation states in delegation
+// lifecycle. Delegation never moves to a state with lower or equal position.
+var delegationStateOrder = map[string]int{
+	"PENDING":  0,
+	"VERIFIED": 1,
+	"ACTIVE":   2,
+	"UNBONDED": 3,
+	"EXPIRED":  3,
+}
+
+var waitForCmd = cli.Command{
+	Name:  "wait-for",
+	Usage: "Waits until staking transaction reaches given delegation state",
+	Description: "Polls staker daemon until delegation of the staking transaction reaches given state " +
+		"(pending, verified, active, unbonded or expired) and prints its details. Exits with code 0 on " +
+		"success, 5 on timeout and 6 when delegation moved past the state, so that it can no longer be reached. " +
+		"Errors returned by daemon while waiting are retried until timeout.",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  helpers.StakingDaemonAddressFlag,
+			Usage: "full address of the staker daemon in format tcp:://<host>:<port>",
+			Value: helpers.DefaultStakingDaemonAddress,
+		},
+		cli.StringFlag{
+			Name:     waitTxFlag,
+			Usage:    "Hash of original staking transaction in bitcoin hex format",
+			Required: true,
+		},
+		cli.StringFlag{
+			Name:  waitStateFlag,
+			Usage: "Delegation state to wait for",
+			Value: "active",
+		},
+		cli.DurationFlag{
+			Name:  timeoutFlag,
+			Usage: "Maximum time to wait",
+			Value: time.Hour,
+		},
+		cli.DurationFlag{
+			Name:  intervalFlag,
+			Usage: "interval in which daemon is polled",
+			Value: 10 * time.Second,
+		},
+	},
+	Action: waitFor,
+}
+
+// checkWaitState returns true if current state is the target state, and
+// error if target state can no longer be reached from the current state
+func checkWaitState(current, target string) (bool, error) {
+	if current == target {
+		return true, nil
+	}
+
+	currentOrder, ok := delegationStateOrder[current]
+	if !ok {
+		// unknown state, keep waiting
+		return false, nil
+	}
+
+	if currentOrder >= delegationStateOrder[target] {
+		return false, fmt.Errorf("delegation is in state %s, state %s can no longer be reached", current, target)
+	}
+
+	return false, nil
+}
+
+func waitFor(ctx *cli.Context) error {
+	client, err := NewStakerServiceJSONRPCClient(ctx.String(helpers.StakingDaemonAddressFlag))
+	if err != nil {
+		return err
+	}
+
+	txHash := ctx.String(waitTxFlag)
+	if _, err := chainhash.NewHashFromStr(txHash); err != nil {
+		return cli.NewExitError(fmt.Sprintf("Invalid staking transaction hash: %v", err), helpers.ExitCodeInvalidArgs)
+	}
+
+	target := strings.ToUpper(ctx.String(waitStateFlag))
+	if _, ok := delegationStateOrder[target]; !ok {
+		return cli.NewExitError(fmt.Sprintf("Unknown delegation state %s", ctx.String(waitStateFlag)), helpers.ExitCodeInvalidArgs)
+	}
+
+	timeout := ctx.Duration(timeoutFlag)
+	interval := ctx.Duration(intervalFlag)
+	if timeout <= 0 || interval <= 0 {
+		return cli.NewExitError("Timeout and interval must be positive", helpers.ExitCodeInvalidArgs)
+	}
+
+	sctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	deadline := time.After(timeout)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var (
+		details *service.StakingDetails
+		lastErr error
+	)
+
+	for {
+		details, lastErr = client.StakingDetails(sctx, txHash, "")
+		if lastErr == nil {
+			reached, err := checkWaitState(details.StakingState, target)
+			if err != nil {
+				return cli.NewExitError(err.Error(), helpers.ExitCodeWaitFailed)
+			}
+			if reached {
+				return helpers.PrintResp(ctx, details)
+			}
+		}
+
+		select {
+		case <-sctx.Done():
+			return fmt.Errorf("interrupted while waiting for state %s", target)
+		case <-deadline:
+			return cli.NewExitError(waitTimeoutMessage(target, timeout, details, lastErr), helpers.ExitCodeWaitTimeout)
+		case <-ticker.C:
+		}
+	}
+}
+
+func waitTimeoutMessage(target string, timeout time.Duration, details *service.StakingDetails, lastErr error) string {
+	msg := fmt.Sprintf("delegation did not reach state %s in %s", target, timeout)
+
+	if lastErr != nil {
+		return fmt.Sprintf("%s, last error: %v", msg, lastErr)
+	}
+
+	if details != nil {
+		return fmt.Sprintf("%s, current state: %s", msg, details.StakingState)
+	}
+
+	return msg
+}
@@ -0,0 +1,28 @@
+package daemon
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckWaitState(t *testing.T) {
+	reached, err := checkWaitState("ACTIVE", "ACTIVE")
+	require.NoError(t, err)
+	require.True(t, reached)
+
+	reached, err = checkWaitState("PENDING", "ACTIVE")
+	require.NoError(t, err)
+	require.False(t, reached)
+
+	// unknown states are not treated as failure
+	reached, err = checkWaitState("", "ACTIVE")
+	require.NoError(t, err)
+	require.False(t, reached)
+
+	_, err = checkWaitState("UNBONDED", "ACTIVE")
+	require.Error(t, err)
+
+	_, err = checkWaitState("EXPIRED", "UNBONDED")
+	require.Error(t, err)
+}
@@ -0,0 +1,85 @@
+package helpers
+
+import (
+	"errors"
+	"net"
+	"net/url"
+
+	rpctypes "github.com/cometbft/cometbft/rpc/jsonrpc/types"
+	"github.com/urfave/cli"
+)
+
+// Exit codes of stakercli commands, so that scripts can tell failure causes
+// apart without parsing error messages
+const (
+	// ExitCodeError is returned for errors not covered by other codes
+	ExitCodeError = 1
+	// ExitCodeInvalidArgs is returned when command arguments are invalid
+	ExitCodeInvalidArgs = 2
+	// ExitCodeDaemonUnavailable is returned when staker daemon can't be reached
+	ExitCodeDaemonUnavailable = 3
+	// ExitCodeRPCError is returned when staker daemon rejected the request
+	ExitCodeRPCError = 4
+	// ExitCodeWaitTimeout is returned when awaited condition was not met in time
+	ExitCodeWaitTimeout = 5
+	// ExitCodeWaitFailed is returned when awaited condition can no longer be met
+	ExitCodeWaitFailed = 6
+	// ExitCodeInvalidSignature is returned when verified signature is not valid
+	ExitCodeInvalidSignature = 7
+)
+
+// ExitError converts error returned by daemon command to cli exit error with
+// exit code matching its cause
+func ExitError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	// cli only recognizes exit errors which are not wrapped
+	var exitCoder cli.ExitCoder
+	if errors.As(err, &exitCoder) {
+		return cli.NewExitError(err.Error(), exitCoder.ExitCode())
+	}
+
+	return cli.NewExitError(err.Error(), exitCode(err))
+}
+
+func exitCode(err error) int {
+	var rpcErr *rpctypes.RPCError
+	if errors.As(err, &rpcErr) {
+		return ExitCodeRPCError
+	}
+
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		return ExitCodeDaemonUnavailable
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return ExitCodeDaemonUnavailable
+	}
+
+	return ExitCodeError
+}
+
+// WithOutputAndExitCodes adds output flag to the commands and converts errors
+// returned by their actions to exit errors
+func WithOutputAndExitCodes(cmds ...cli.Command) []cli.Command {
+	for i := range cmds {
+		cmds[i].Flags = append(cmds[i].Flags, OutputFormatFlag)
+
+		if action, ok := cmds[i].Action.(func(*cli.Context) error); ok {
+			cmds[i].Action = WithExitCodes(action)
+		}
+	}
+
+	return cmds
+}
+
+// WithExitCodes converts errors returned by the action to exit errors
+func WithExitCodes(action func(*cli.Context) error) func(*cli.Context) error {
+	return func(ctx *cli.Context) error {
+		return ExitError(action(ctx))
+	}
+}
@@ -0,0 +1,165 @@
+package helpers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/urfave/cli"
+)
+
+const (
+	OutputFlag  = "output"
+	OutputJSON  = "json"
+	OutputTable = "table"
+)
+
+// OutputFormatFlag selects format in which command prints its result
+var OutputFormatFlag = cli.StringFlag{
+	Name:  OutputFlag,
+	Usage: "format of the command output (json, table)",
+	Value: OutputJSON,
+}
+
+// PrintResp prints response in format selected by output flag
+func PrintResp(ctx *cli.Context, resp interface{}) error {
+	switch format := ctx.String(OutputFlag); format {
+	case "", OutputJSON:
+		PrintRespJSON(resp)
+		return nil
+	case OutputTable:
+		if err := PrintRespTable(os.Stdout, resp); err != nil {
+			return cli.NewExitError(fmt.Sprintf("unable to print response: %v", err), ExitCodeError)
+		}
+		return nil
+	default:
+		return cli.NewExitError(fmt.Sprintf("unknown output format %q, expected one of (json, table)", format), ExitCodeInvalidArgs)
+	}
+}
+
+// PrintRespTable prints response as a table. Scalar fields are printed as
+// key value pairs, and lists of objects as tables with a column per field.
+func PrintRespTable(w io.Writer, resp interface{}) error {
+	jsonBytes, err := json.Marshal(resp)
+	if err != nil {
+		return err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(jsonBytes))
+	decoder.UseNumber()
+
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return err
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		writeObject(tw, v)
+	case []interface{}:
+		writeList(tw, v)
+	default:
+		fmt.Fprintln(tw, formatCell(v))
+	}
+
+	return tw.Flush()
+}
+
+func writeObject(tw *tabwriter.Writer, obj map[string]interface{}) {
+	var lists []string
+
+	for _, key := range sortedKeys(obj) {
+		if list, ok := obj[key].([]interface{}); ok && isObjectList(list) {
+			lists = append(lists, key)
+			continue
+		}
+		fmt.Fprintf(tw, "%s\t%s\n", strings.ToUpper(key), formatCell(obj[key]))
+	}
+
+	for _, key := range lists {
+		fmt.Fprintf(tw, "\n%s\n", strings.ToUpper(key))
+		writeList(tw, obj[key].([]interface{}))
+	}
+}
+
+func writeList(tw *tabwriter.Writer, list []interface{}) {
+	if !isObjectList(list) {
+		for _, item := range list {
+			fmt.Fprintln(tw, formatCell(item))
+		}
+		return
+	}
+
+	columns := make(map[string]interface{})
+	for _, item := range list {
+		for key := range item.(map[string]interface{}) {
+			columns[key] = nil
+		}
+	}
+	header := sortedKeys(columns)
+
+	upper := make([]string, len(header))
+	for i, key := range header {
+		upper[i] = strings.ToUpper(key)
+	}
+	fmt.Fprintln(tw, strings.Join(upper, "\t"))
+
+	for _, item := range list {
+		obj := item.(map[string]interface{})
+		row := make([]string, len(header))
+		for i, key := range header {
+			row[i] = formatCell(obj[key])
+		}
+		fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+}
+
+func isObjectList(list []interface{}) bool {
+	if len(list) == 0 {
+		return false
+	}
+
+	for _, item := range list {
+		if _, ok := item.(map[string]interface{}); !ok {
+			return false
+		}
+	}
+
+	return true
+}
+
+func formatCell(v interface{}) string {
+	switch c := v.(type) {
+	case nil:
+		return "-"
+	case string:
+		return c
+	case json.Number:
+		return c.String()
+	case bool:
+		return fmt.Sprintf("%t", c)
+	default:
+		// nested values are printed in compact json
+		b, err := json.Marshal(c)
+		if err != nil {
+			return fmt.Sprintf("%v", c)
+		}
+		return string(b)
+	}
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
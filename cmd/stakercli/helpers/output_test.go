@@ -0,0 +1,64 @@
+package helpers_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	rpctypes "github.com/cometbft/cometbft/rpc/jsonrpc/types"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli"
+
+	"github.com/babylonlabs-io/btc-staker/cmd/stakercli/helpers"
+)
+
+type testTx struct {
+	Hash   string `json:"hash"`
+	Amount int64  `json:"amount"`
+}
+
+type testResp struct {
+	Total        int      `json:"total"`
+	Transactions []testTx `json:"transactions"`
+}
+
+func TestPrintRespTable(t *testing.T) {
+	var buf bytes.Buffer
+	err := helpers.PrintRespTable(&buf, testResp{
+		Total: 2,
+		Transactions: []testTx{
+			{Hash: "aa", Amount: 1000},
+			{Hash: "bb", Amount: 20000},
+		},
+	})
+	require.NoError(t, err)
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Equal(t, []string{
+		"TOTAL  2",
+		"",
+		"TRANSACTIONS",
+		"AMOUNT  HASH",
+		"1000    aa",
+		"20000   bb",
+	}, lines)
+}
+
+func TestExitError(t *testing.T) {
+	exitCode := func(err error) int {
+		var exitCoder cli.ExitCoder
+		require.True(t, errors.As(helpers.ExitError(err), &exitCoder))
+		return exitCoder.ExitCode()
+	}
+
+	require.NoError(t, helpers.ExitError(nil))
+	require.Equal(t, helpers.ExitCodeError, exitCode(errors.New("failure")))
+	require.Equal(t, helpers.ExitCodeRPCError, exitCode(
+		fmt.Errorf("failed to stake: %w", &rpctypes.RPCError{Code: -32603, Message: "Internal error"}),
+	))
+	require.Equal(t, helpers.ExitCodeInvalidArgs, exitCode(
+		fmt.Errorf("failed to list: %w", cli.NewExitError("Limit must be non-negative", helpers.ExitCodeInvalidArgs)),
+	))
+}
@@ -74,7 +74,7 @@ var GenerateCreatePopCmd = cli.Command{
 		},
 		cli.StringFlag{
 			Name:  btcNetworkFlag,
-			Usage: "Bitcoin network on which staking should take place (testnet3, mainnet, regtest, simnet, signet)",
+			Usage: "Bitcoin network on which staking should take place (testnet3, testnet4, mainnet, regtest, simnet, signet)",
 			Value: "testnet3",
 		},
 		cli.StringFlag{
@@ -201,7 +201,7 @@ var ValidatePopCmd = cli.Command{
 	Flags: []cli.Flag{
 		cli.StringFlag{
 			Name:  btcNetworkFlag,
-			Usage: "Bitcoin network (testnet3, mainnet, regtest, simnet, signet)",
+			Usage: "Bitcoin network (testnet3, testnet4, mainnet, regtest, simnet, signet)",
 			Value: "testnet3",
 		},
 		cli.StringFlag{
@@ -359,7 +359,7 @@ var generateDeletePopCmd = cli.Command{
 		},
 		cli.StringFlag{
 			Name:  btcNetworkFlag,
-			Usage: "Bitcoin network on which staking should take place (testnet3, mainnet, regtest, simnet, signet)",
+			Usage: "Bitcoin network on which staking should take place (testnet3, testnet4, mainnet, regtest, simnet, signet)",
 			Value: "testnet3",
 		},
 		cli.StringFlag{
@@ -8,8 +8,8 @@ import (
 
 	"github.com/stretchr/testify/require"
 
-	"github.com/babylonlabs-io/btc-staker/itest/testutil"
 	"github.com/babylonlabs-io/btc-staker/staker"
+	"github.com/babylonlabs-io/btc-staker/testutil"
 )
 
 var popsToVerify = []staker.Response{
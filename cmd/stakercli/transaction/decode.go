@@ -0,0 +1,293 @@
+package transaction
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"github.com/babylonlabs-io/babylon/v4/btcstaking"
+	bbn "github.com/babylonlabs-io/babylon/v4/types"
+	"github.com/babylonlabs-io/networks/parameters/parser"
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/cometbft/cometbft/libs/os"
+	"github.com/urfave/cli"
+
+	"github.com/babylonlabs-io/btc-staker/cmd/stakercli/helpers"
+	"github.com/babylonlabs-io/btc-staker/utils"
+)
+
+const (
+	txHexFlag = "tx"
+
+	TxTypeStaking   = "staking"
+	TxTypeUnbonding = "unbonding"
+	TxTypeSlashing  = "slashing"
+	TxTypeUnknown   = "unknown"
+)
+
+var decodeTransactionCmd = cli.Command{
+	Name:      "decode",
+	ShortName: "dec",
+	Usage:     "stakercli transaction decode [fullpath/to/parameters.json]",
+	Description: "Decodes raw transaction and prints whether it is staking, unbonding or slashing " +
+		"transaction, its script parameters and which parameters version it matches. Unbonding " +
+		"and slashing transactions can only be recognized if the staking transaction they spend is provided.",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:     txHexFlag,
+			Usage:    "Transaction to decode in hex",
+			Required: true,
+		},
+		cli.StringFlag{
+			Name:  stakingTransactionFlag,
+			Usage: "Staking transaction in hex, spent by decoded unbonding or slashing transaction",
+		},
+		cli.StringFlag{
+			Name:     networkNameFlag,
+			Usage:    "Bitcoin network on which transaction was created one of (mainnet, testnet3, testnet4, regtest, simnet, signet)",
+			Required: true,
+		},
+	},
+	Action: decodeTransaction,
+}
+
+type DecodedStakingOutput struct {
+	StakingTxHash    string         `json:"staking_tx_hash"`
+	StakingOutputIdx uint32         `json:"staking_output_idx"`
+	StakingData      *StakingTxData `json:"staking_data"`
+}
+
+type DecodedSlashingOutputs struct {
+	SlashingAmount int64 `json:"slashing_amount"`
+	ChangeAmount   int64 `json:"change_amount"`
+}
+
+type DecodeTxResponse struct {
+	TxHash string `json:"tx_hash"`
+	TxType string `json:"tx_type"`
+	// versions of parameters which decoded transaction matches, newest first
+	ParametersVersions []uint64 `json:"parameters_versions,omitempty"`
+	// staking output created by staking transaction or spent by unbonding
+	// or slashing transaction
+	StakingOutput   *DecodedStakingOutput   `json:"staking_output,omitempty"`
+	UnbondingAmount int64                   `json:"unbonding_amount,omitempty"`
+	UnbondingTime   uint16                  `json:"unbonding_time_blocks,omitempty"`
+	Slashing        *DecodedSlashingOutputs `json:"slashing,omitempty"`
+	// validity of staking transaction against each parameters version
+	ValidityInfo []*ValidityInfo `json:"validity_info,omitempty"`
+}
+
+func decodeTransaction(ctx *cli.Context) error {
+	inputFilePath := ctx.Args().First()
+	if len(inputFilePath) == 0 {
+		return errors.New("json file input is empty")
+	}
+
+	if !os.FileExists(inputFilePath) {
+		return fmt.Errorf("json file input %s does not exist", inputFilePath)
+	}
+
+	globalParams, err := parser.NewParsedGlobalParamsFromFile(inputFilePath)
+	if err != nil {
+		return fmt.Errorf("error parsing file %s: %w", inputFilePath, err)
+	}
+
+	net, err := utils.GetBtcNetworkParams(ctx.String(networkNameFlag))
+	if err != nil {
+		return err
+	}
+
+	tx, _, err := bbn.NewBTCTxFromHex(ctx.String(txHexFlag))
+	if err != nil {
+		return fmt.Errorf("error parsing transaction: %w", err)
+	}
+
+	var stakingTx *wire.MsgTx
+	if stakingTxHex := ctx.String(stakingTransactionFlag); len(stakingTxHex) > 0 {
+		stakingTx, _, err = bbn.NewBTCTxFromHex(stakingTxHex)
+		if err != nil {
+			return fmt.Errorf("error parsing staking transaction: %w", err)
+		}
+	}
+
+	helpers.PrintRespJSON(DecodeTx(tx, stakingTx, globalParams, net))
+	return nil
+}
+
+// DecodeTx recognizes type of the transaction by trying to parse it against
+// every parameters version. stakingTx is optional, and is necessary to
+// recognize unbonding and slashing transactions spending it.
+func DecodeTx(
+	tx *wire.MsgTx,
+	stakingTx *wire.MsgTx,
+	globalParams *parser.ParsedGlobalParams,
+	net *chaincfg.Params,
+) *DecodeTxResponse {
+	resp := &DecodeTxResponse{
+		TxHash: tx.TxHash().String(),
+		TxType: TxTypeUnknown,
+	}
+
+	if staking := decodeStakingTx(tx, globalParams, net); staking != nil {
+		resp.TxType = TxTypeStaking
+		for _, params := range staking.params {
+			resp.ParametersVersions = append(resp.ParametersVersions, params.Version)
+		}
+		resp.StakingOutput = staking.output
+		resp.ValidityInfo = ValidateTxAgainstParams(tx, globalParams, net).ValidityInfo
+		return resp
+	}
+
+	if stakingTx == nil {
+		return resp
+	}
+
+	staking := decodeStakingTx(stakingTx, globalParams, net)
+	if staking == nil {
+		return resp
+	}
+
+	stakingTxHash := stakingTx.TxHash()
+	stakingOutpoint := wire.NewOutPoint(&stakingTxHash, staking.output.StakingOutputIdx)
+
+	if len(tx.TxIn) != 1 || tx.TxIn[0].PreviousOutPoint != *stakingOutpoint {
+		return resp
+	}
+
+	resp.StakingOutput = staking.output
+
+	for _, params := range staking.params {
+		if isUnbondingTx(tx, staking.parsed, params, net) {
+			resp.TxType = TxTypeUnbonding
+			resp.ParametersVersions = append(resp.ParametersVersions, params.Version)
+			resp.UnbondingAmount = tx.TxOut[0].Value
+			resp.UnbondingTime = params.UnbondingTime
+			continue
+		}
+
+		if isSlashingTx(tx, staking.parsed, params, net) {
+			resp.TxType = TxTypeSlashing
+			resp.ParametersVersions = append(resp.ParametersVersions, params.Version)
+			resp.Slashing = &DecodedSlashingOutputs{
+				SlashingAmount: tx.TxOut[0].Value,
+				ChangeAmount:   tx.TxOut[1].Value,
+			}
+		}
+	}
+
+	return resp
+}
+
+type decodedStakingTx struct {
+	parsed *btcstaking.ParsedV0StakingTx
+	output *DecodedStakingOutput
+	params []*parser.ParsedVersionedGlobalParams
+}
+
+// decodeStakingTx parses transaction as staking transaction against every
+// parameters version, newest first. It returns nil if transaction does not
+// match any version.
+func decodeStakingTx(
+	tx *wire.MsgTx,
+	globalParams *parser.ParsedGlobalParams,
+	net *chaincfg.Params,
+) *decodedStakingTx {
+	var decoded *decodedStakingTx
+
+	for i := len(globalParams.Versions) - 1; i >= 0; i-- {
+		params := globalParams.Versions[i]
+
+		parsed, err := btcstaking.ParseV0StakingTx(
+			tx,
+			params.Tag,
+			params.CovenantPks,
+			params.CovenantQuorum,
+			net,
+		)
+		if err != nil {
+			continue
+		}
+
+		if decoded == nil {
+			decoded = &decodedStakingTx{
+				parsed: parsed,
+				output: &DecodedStakingOutput{
+					StakingTxHash:    tx.TxHash().String(),
+					StakingOutputIdx: uint32(parsed.StakingOutputIdx),
+					StakingData: &StakingTxData{
+						StakerPublicKeyHex:           hex.EncodeToString(schnorr.SerializePubKey(parsed.OpReturnData.StakerPublicKey.PubKey)),
+						FinalityProviderPublicKeyHex: hex.EncodeToString(schnorr.SerializePubKey(parsed.OpReturnData.FinalityProviderPublicKey.PubKey)),
+						StakingAmount:                parsed.StakingOutput.Value,
+						StakingTimeBlocks:            int64(parsed.OpReturnData.StakingTime),
+					},
+				},
+			}
+		}
+
+		decoded.params = append(decoded.params, params)
+	}
+
+	return decoded
+}
+
+func isUnbondingTx(
+	tx *wire.MsgTx,
+	staking *btcstaking.ParsedV0StakingTx,
+	params *parser.ParsedVersionedGlobalParams,
+	net *chaincfg.Params,
+) bool {
+	if err := btcstaking.CheckPreSignedUnbondingTxSanity(tx); err != nil {
+		return false
+	}
+
+	unbondingAmount := btcutil.Amount(staking.StakingOutput.Value) - params.UnbondingFee
+	if unbondingAmount <= 0 {
+		return false
+	}
+
+	unbondingInfo, err := btcstaking.BuildUnbondingInfo(
+		staking.OpReturnData.StakerPublicKey.PubKey,
+		[]*btcec.PublicKey{staking.OpReturnData.FinalityProviderPublicKey.PubKey},
+		params.CovenantPks,
+		params.CovenantQuorum,
+		params.UnbondingTime,
+		unbondingAmount,
+		net,
+	)
+	if err != nil {
+		return false
+	}
+
+	return outputsAreEqual(unbondingInfo.UnbondingOutput, tx.TxOut[0])
+}
+
+// isSlashingTx checks whether transaction is a slashing transaction, i.e its
+// change output is locked to the staker for unbonding time
+func isSlashingTx(
+	tx *wire.MsgTx,
+	staking *btcstaking.ParsedV0StakingTx,
+	params *parser.ParsedVersionedGlobalParams,
+	net *chaincfg.Params,
+) bool {
+	if err := btcstaking.CheckPreSignedSlashingTxSanity(tx); err != nil {
+		return false
+	}
+
+	changeScript, err := btcstaking.BuildRelativeTimelockTaprootScript(
+		staking.OpReturnData.StakerPublicKey.PubKey,
+		params.UnbondingTime,
+		net,
+	)
+	if err != nil {
+		return false
+	}
+
+	return outputsAreEqual(
+		wire.NewTxOut(tx.TxOut[1].Value, changeScript.PkScript),
+		tx.TxOut[1],
+	)
+}
@@ -0,0 +1,86 @@
+package transaction_test
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/babylonlabs-io/babylon/v4/btcstaking"
+	"github.com/babylonlabs-io/babylon/v4/testutil/datagen"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/stretchr/testify/require"
+
+	"github.com/babylonlabs-io/btc-staker/cmd/stakercli/transaction"
+	"github.com/babylonlabs-io/btc-staker/testutil"
+	"github.com/babylonlabs-io/btc-staker/utils"
+)
+
+func FuzzDecodeTx(f *testing.F) {
+	paramsFilePath := testutil.CreateTempFileWithParams(f)
+
+	datagen.AddRandomSeedsToFuzzer(f, 10)
+	f.Fuzz(func(t *testing.T, seed int64) {
+		r := rand.New(rand.NewSource(seed))
+		net := &chaincfg.RegressionNetParams
+
+		stakerParams, _ := createCustomValidStakeParams(t, r, &globalParams, net)
+
+		_, stakingTx, err := btcstaking.BuildV0IdentifiableStakingOutputsAndTx(
+			lastParams.Tag,
+			stakerParams.StakerPk,
+			stakerParams.FinalityProviderPk,
+			lastParams.CovenantPks,
+			lastParams.CovenantQuorum,
+			stakerParams.StakingTime,
+			stakerParams.StakingAmount,
+			net,
+		)
+		require.NoError(t, err)
+
+		fakeInputHash := sha256.Sum256([]byte{0x01})
+		stakingTx.AddTxIn(wire.NewTxIn(&wire.OutPoint{Hash: fakeInputHash, Index: 0}, nil, nil))
+
+		serializedStakingTx, err := utils.SerializeBtcTransaction(stakingTx)
+		require.NoError(t, err)
+		stakingTxHex := hex.EncodeToString(serializedStakingTx)
+
+		app := testutil.TestApp()
+		stakingResp := appRunOffline[transaction.DecodeTxResponse](r, t, app, "decode", []string{
+			paramsFilePath,
+			fmt.Sprintf("--tx=%s", stakingTxHex),
+			fmt.Sprintf("--network=%s", net.Name),
+		})
+		require.Equal(t, transaction.TxTypeStaking, stakingResp.TxType)
+		require.Equal(t, []uint64{lastParams.Version}, stakingResp.ParametersVersions)
+		require.Equal(t, keyToSchnorrHex(stakerParams.StakerPk), stakingResp.StakingOutput.StakingData.StakerPublicKeyHex)
+		require.Equal(t, int64(stakerParams.StakingTime), stakingResp.StakingOutput.StakingData.StakingTimeBlocks)
+
+		unbondingResp := appRunCreatePhase1UnbondingTx(r, t, app, []string{
+			paramsFilePath,
+			fmt.Sprintf("--staking-transaction=%s", stakingTxHex),
+			fmt.Sprintf("--tx-inclusion-height=%d", stakerParams.InclusionHeight),
+			fmt.Sprintf("--network=%s", net.Name),
+		})
+
+		decodedUnbonding := appRunOffline[transaction.DecodeTxResponse](r, t, app, "decode", []string{
+			paramsFilePath,
+			fmt.Sprintf("--tx=%s", unbondingResp.UnbondingTxHex),
+			fmt.Sprintf("--staking-transaction=%s", stakingTxHex),
+			fmt.Sprintf("--network=%s", net.Name),
+		})
+		require.Equal(t, transaction.TxTypeUnbonding, decodedUnbonding.TxType)
+		require.Equal(t, lastParams.UnbondingTime, decodedUnbonding.UnbondingTime)
+		require.Equal(t, int64(stakerParams.StakingAmount-lastParams.UnbondingFee), decodedUnbonding.UnbondingAmount)
+
+		// without staking transaction unbonding transaction can't be recognized
+		unknown := appRunOffline[transaction.DecodeTxResponse](r, t, app, "decode", []string{
+			paramsFilePath,
+			fmt.Sprintf("--tx=%s", unbondingResp.UnbondingTxHex),
+			fmt.Sprintf("--network=%s", net.Name),
+		})
+		require.Equal(t, transaction.TxTypeUnknown, unknown.TxType)
+	})
+}
@@ -0,0 +1,665 @@
+package transaction
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/babylonlabs-io/babylon/v4/btcstaking"
+	bbn "github.com/babylonlabs-io/babylon/v4/types"
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/btcutil/psbt"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/urfave/cli"
+
+	"github.com/babylonlabs-io/btc-staker/cmd/stakercli/helpers"
+	"github.com/babylonlabs-io/btc-staker/utils"
+)
+
+const (
+	fundingInputFlag          = "funding-input"
+	changeAddressFlag         = "change-address"
+	stakingTransactionFeeFlag = "staking-fee"
+	unbondingTimeBlocksFlag   = "unbonding-time"
+	unbondingFeeFlag          = "unbonding-fee"
+)
+
+// stakingScriptFlags are flags describing staking scripts, shared by all
+// offline commands
+var stakingScriptFlags = []cli.Flag{
+	cli.StringFlag{
+		Name:     stakerPublicKeyFlag,
+		Usage:    "Staker public key in Schnorr format (32 byte) in hex",
+		Required: true,
+	},
+	cli.StringSliceFlag{
+		Name:     finalityProviderKeyFlag,
+		Usage:    "Finality provider public key in Schnorr format (32 byte) in hex. Can be repeated",
+		Required: true,
+	},
+	cli.Int64Flag{
+		Name:     helpers.StakingTimeBlocksFlag,
+		Usage:    "Staking time in BTC blocks",
+		Required: true,
+	},
+	cli.StringSliceFlag{
+		Name:     covenantMembersPksFlag,
+		Usage:    "BTC public keys of the covenant committee members",
+		Required: true,
+	},
+	cli.Uint64Flag{
+		Name:     covenantQuorumFlag,
+		Usage:    "Required quorum for the covenant members",
+		Required: true,
+	},
+	cli.StringFlag{
+		Name:     networkNameFlag,
+		Usage:    "Bitcoin network on which staking should take place one of (mainnet, testnet3, testnet4, regtest, simnet, signet)",
+		Required: true,
+	},
+}
+
+var unbondingScriptFlags = []cli.Flag{
+	cli.Int64Flag{
+		Name:     unbondingTimeBlocksFlag,
+		Usage:    "Unbonding time in BTC blocks, as defined in Babylon staking parameters",
+		Required: true,
+	},
+	cli.Int64Flag{
+		Name:     unbondingFeeFlag,
+		Usage:    "Unbonding fee in satoshis, as defined in Babylon staking parameters",
+		Required: true,
+	},
+}
+
+// offlineScriptParams are parameters necessary to re-build staking and
+// unbonding scripts without access to Babylon node
+type offlineScriptParams struct {
+	stakerPk       *btcec.PublicKey
+	fpPks          []*btcec.PublicKey
+	covenantPks    []*btcec.PublicKey
+	covenantQuorum uint32
+	stakingTime    uint16
+	net            *chaincfg.Params
+}
+
+func parseOfflineScriptParams(ctx *cli.Context) (*offlineScriptParams, error) {
+	net, err := utils.GetBtcNetworkParams(ctx.String(networkNameFlag))
+	if err != nil {
+		return nil, err
+	}
+
+	stakerPk, err := parseSchnorPubKeyFromCliCtx(ctx, stakerPublicKeyFlag)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing staker public key: %w", err)
+	}
+
+	// finality provider keys have the same format as covenant keys
+	fpPks, err := parseCovenantKeysFromSlice(ctx.StringSlice(finalityProviderKeyFlag))
+	if err != nil {
+		return nil, fmt.Errorf("error parsing finality provider public keys: %w", err)
+	}
+
+	stakingTime, err := parseLockTimeBlocksFromCliCtx(ctx, helpers.StakingTimeBlocksFlag)
+	if err != nil {
+		return nil, err
+	}
+
+	covenantPks, err := parseCovenantKeysFromCliCtx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing covenant public keys: %w", err)
+	}
+
+	covenantQuorum, err := parseCovenantQuorumFromCliCtx(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if int(covenantQuorum) > len(covenantPks) {
+		return nil, fmt.Errorf("covenant quorum %d is greater than number of covenant keys %d", covenantQuorum, len(covenantPks))
+	}
+
+	return &offlineScriptParams{
+		stakerPk:       stakerPk,
+		fpPks:          fpPks,
+		covenantPks:    covenantPks,
+		covenantQuorum: covenantQuorum,
+		stakingTime:    stakingTime,
+		net:            net,
+	}, nil
+}
+
+func (p *offlineScriptParams) stakingInfo(amount btcutil.Amount) (*btcstaking.StakingInfo, error) {
+	return btcstaking.BuildStakingInfo(
+		p.stakerPk,
+		p.fpPks,
+		p.covenantPks,
+		p.covenantQuorum,
+		p.stakingTime,
+		amount,
+		p.net,
+	)
+}
+
+func (p *offlineScriptParams) unbondingInfo(unbondingTime uint16, amount btcutil.Amount) (*btcstaking.UnbondingInfo, error) {
+	return btcstaking.BuildUnbondingInfo(
+		p.stakerPk,
+		p.fpPks,
+		p.covenantPks,
+		p.covenantQuorum,
+		unbondingTime,
+		amount,
+		p.net,
+	)
+}
+
+// fundingInput is an utxo provided by the user to fund staking transaction
+type fundingInput struct {
+	outpoint *wire.OutPoint
+	utxo     *wire.TxOut
+}
+
+// parseFundingInput parses funding input in format
+// <txid>:<vout>:<amount_sat>:<pk_script_hex>
+func parseFundingInput(input string) (*fundingInput, error) {
+	parts := strings.Split(input, ":")
+	if len(parts) != 4 {
+		return nil, fmt.Errorf("funding input %s should be in format <txid>:<vout>:<amount_sat>:<pk_script_hex>", input)
+	}
+
+	hash, err := chainhash.NewHashFromStr(parts[0])
+	if err != nil {
+		return nil, fmt.Errorf("invalid funding input txid %s: %w", parts[0], err)
+	}
+
+	vout, err := strconv.ParseUint(parts[1], 10, 32)
+	if err != nil {
+		return nil, fmt.Errorf("invalid funding input vout %s: %w", parts[1], err)
+	}
+
+	amount, err := strconv.ParseInt(parts[2], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid funding input amount %s: %w", parts[2], err)
+	}
+
+	if amount <= 0 {
+		return nil, fmt.Errorf("funding input amount should be greater than 0")
+	}
+
+	pkScript, err := hex.DecodeString(parts[3])
+	if err != nil {
+		return nil, fmt.Errorf("invalid funding input pk script %s: %w", parts[3], err)
+	}
+
+	return &fundingInput{
+		outpoint: wire.NewOutPoint(hash, uint32(vout)),
+		utxo:     wire.NewTxOut(amount, pkScript),
+	}, nil
+}
+
+// fillTaprootSpendInput fills psbt input with data which will make it possible
+// for staker to sign it using his bitcoind wallet
+func fillTaprootSpendInput(
+	input *psbt.PInput,
+	stakerPk *btcec.PublicKey,
+	utxo *wire.TxOut,
+	spendInfo *btcstaking.SpendInfo,
+) error {
+	ctrlBlock, err := spendInfo.ControlBlock.ToBytes()
+	if err != nil {
+		return err
+	}
+
+	input.SighashType = txscript.SigHashDefault
+	input.WitnessUtxo = utxo
+	input.TaprootBip32Derivation = []*psbt.TaprootBip32Derivation{
+		{
+			XOnlyPubKey: schnorr.SerializePubKey(stakerPk),
+		},
+	}
+	input.TaprootLeafScript = []*psbt.TaprootTapLeafScript{
+		{
+			ControlBlock: ctrlBlock,
+			Script:       spendInfo.RevealedLeaf.Script,
+			LeafVersion:  spendInfo.RevealedLeaf.LeafVersion,
+		},
+	}
+
+	return nil
+}
+
+func psbtResponse(packet *psbt.Packet) (string, string, error) {
+	txBytes, err := utils.SerializeBtcTransaction(packet.UnsignedTx)
+	if err != nil {
+		return "", "", err
+	}
+
+	encoded, err := packet.B64Encode()
+	if err != nil {
+		return "", "", err
+	}
+
+	return hex.EncodeToString(txBytes), encoded, nil
+}
+
+// findOutput returns index of the output in the transaction or error if
+// transaction does not contain it
+func findOutput(tx *wire.MsgTx, out *wire.TxOut) (uint32, error) {
+	for i, txOut := range tx.TxOut {
+		if outputsAreEqual(txOut, out) {
+			return uint32(i), nil
+		}
+	}
+
+	return 0, fmt.Errorf("transaction %s does not contain expected staking output", tx.TxHash())
+}
+
+var createStakingTxCmd = cli.Command{
+	Name:      "create-staking-tx",
+	ShortName: "cst",
+	Usage:     "Creates unsigned Babylon staking transaction from provided parameters without connecting to any node",
+	Description: "Creates unsigned Babylon staking transaction. If funding inputs are provided, " +
+		"transaction is funded from them and returned also as psbt packet which can be signed " +
+		"by an offline wallet. Otherwise only unfunded transaction with staking output is returned.",
+	Flags: append([]cli.Flag{
+		cli.Int64Flag{
+			Name:     helpers.StakingAmountFlag,
+			Usage:    "Staking amount in satoshis",
+			Required: true,
+		},
+		cli.StringSliceFlag{
+			Name:  fundingInputFlag,
+			Usage: "Utxo funding the transaction in format <txid>:<vout>:<amount_sat>:<pk_script_hex>. Can be repeated",
+		},
+		cli.StringFlag{
+			Name:  changeAddressFlag,
+			Usage: "Address receiving change from funding inputs",
+		},
+		cli.Int64Flag{
+			Name:  stakingTransactionFeeFlag,
+			Usage: "Fee in satoshis paid by staking transaction. Only used with funding inputs",
+		},
+	}, stakingScriptFlags...),
+	Action: createStakingTx,
+}
+
+type CreateStakingTxResponse struct {
+	StakingTxHex     string `json:"staking_tx_hex"`
+	StakingOutputIdx uint32 `json:"staking_output_idx"`
+	// base64 encoded psbt packet spending funding inputs, present only if
+	// funding inputs were provided
+	StakingPsbtPacketBase64 string `json:"staking_psbt_packet_base64,omitempty"`
+}
+
+func createStakingTx(ctx *cli.Context) error {
+	params, err := parseOfflineScriptParams(ctx)
+	if err != nil {
+		return err
+	}
+
+	stakingAmount, err := parseAmountFromCliCtx(ctx, helpers.StakingAmountFlag)
+	if err != nil {
+		return err
+	}
+
+	stakingInfo, err := params.stakingInfo(stakingAmount)
+	if err != nil {
+		return fmt.Errorf("error building staking info: %w", err)
+	}
+
+	fundingInputs := ctx.StringSlice(fundingInputFlag)
+
+	if len(fundingInputs) == 0 {
+		tx := wire.NewMsgTx(2)
+		tx.AddTxOut(stakingInfo.StakingOutput)
+
+		serializedTx, err := utils.SerializeBtcTransaction(tx)
+		if err != nil {
+			return err
+		}
+
+		helpers.PrintRespJSON(&CreateStakingTxResponse{
+			StakingTxHex:     hex.EncodeToString(serializedTx),
+			StakingOutputIdx: 0,
+		})
+		return nil
+	}
+
+	var (
+		outpoints []*wire.OutPoint
+		utxos     []*wire.TxOut
+		sequences []uint32
+		total     btcutil.Amount
+	)
+
+	for _, in := range fundingInputs {
+		fi, err := parseFundingInput(in)
+		if err != nil {
+			return err
+		}
+		outpoints = append(outpoints, fi.outpoint)
+		utxos = append(utxos, fi.utxo)
+		sequences = append(sequences, wire.MaxTxInSequenceNum)
+		total += btcutil.Amount(fi.utxo.Value)
+	}
+
+	fee := btcutil.Amount(ctx.Int64(stakingTransactionFeeFlag))
+	if fee < 0 {
+		return fmt.Errorf("staking fee should not be negative")
+	}
+
+	change := total - stakingAmount - fee
+	if change < 0 {
+		return fmt.Errorf("funding inputs value %d is lower than staking amount %d plus fee %d", total, stakingAmount, fee)
+	}
+
+	outputs := []*wire.TxOut{stakingInfo.StakingOutput}
+
+	if change > 0 {
+		changeAddressString := ctx.String(changeAddressFlag)
+		if changeAddressString == "" {
+			return fmt.Errorf("change address is required as funding inputs exceed staking amount plus fee by %d", change)
+		}
+
+		changeAddress, err := btcutil.DecodeAddress(changeAddressString, params.net)
+		if err != nil {
+			return fmt.Errorf("error decoding change address: %w", err)
+		}
+
+		changePkScript, err := txscript.PayToAddrScript(changeAddress)
+		if err != nil {
+			return fmt.Errorf("error creating pk script for change address: %w", err)
+		}
+
+		outputs = append(outputs, wire.NewTxOut(int64(change), changePkScript))
+	}
+
+	packet, err := psbt.New(outpoints, outputs, 2, 0, sequences)
+	if err != nil {
+		return err
+	}
+
+	for i, utxo := range utxos {
+		packet.Inputs[i].WitnessUtxo = utxo
+	}
+
+	txHex, encodedPacket, err := psbtResponse(packet)
+	if err != nil {
+		return err
+	}
+
+	helpers.PrintRespJSON(&CreateStakingTxResponse{
+		StakingTxHex:            txHex,
+		StakingOutputIdx:        0,
+		StakingPsbtPacketBase64: encodedPacket,
+	})
+	return nil
+}
+
+var createUnbondingTxCmd = cli.Command{
+	Name:      "create-unbonding-tx",
+	ShortName: "cut",
+	Usage:     "Creates unsigned Babylon unbonding transaction from provided parameters without connecting to any node",
+	Flags: append(append([]cli.Flag{
+		cli.StringFlag{
+			Name:     stakingTransactionFlag,
+			Usage:    "Staking transaction in hex",
+			Required: true,
+		},
+	}, stakingScriptFlags...), unbondingScriptFlags...),
+	Action: createUnbondingTx,
+}
+
+type CreateUnbondingTxResponse struct {
+	// bare hex of created unbonding transaction
+	UnbondingTxHex string `json:"unbonding_tx_hex"`
+	// base64 encoded psbt packet which can be used to sign the transaction using
+	// staker bitcoind wallet using `walletprocesspsbt` rpc call
+	UnbondingPsbtPacketBase64 string `json:"unbonding_psbt_packet_base64"`
+}
+
+// stakingOutputFromTx re-builds staking output from params and finds it in
+// the staking transaction
+func stakingOutputFromTx(
+	ctx *cli.Context,
+	params *offlineScriptParams,
+) (*wire.MsgTx, *btcstaking.StakingInfo, uint32, error) {
+	stakingTx, _, err := bbn.NewBTCTxFromHex(ctx.String(stakingTransactionFlag))
+	if err != nil {
+		return nil, nil, 0, fmt.Errorf("error parsing staking transaction: %w", err)
+	}
+
+	// staking amount is not known upfront, so every output value is tried
+	// until re-built staking output matches
+	for _, out := range stakingTx.TxOut {
+		stakingInfo, err := params.stakingInfo(btcutil.Amount(out.Value))
+		if err != nil {
+			continue
+		}
+
+		idx, err := findOutput(stakingTx, stakingInfo.StakingOutput)
+		if err == nil {
+			return stakingTx, stakingInfo, idx, nil
+		}
+	}
+
+	return nil, nil, 0, fmt.Errorf("staking transaction %s does not contain staking output matching provided parameters", stakingTx.TxHash())
+}
+
+func createUnbondingTx(ctx *cli.Context) error {
+	params, err := parseOfflineScriptParams(ctx)
+	if err != nil {
+		return err
+	}
+
+	unbondingTime, err := parseLockTimeBlocksFromCliCtx(ctx, unbondingTimeBlocksFlag)
+	if err != nil {
+		return err
+	}
+
+	unbondingFee, err := parseAmountFromCliCtx(ctx, unbondingFeeFlag)
+	if err != nil {
+		return err
+	}
+
+	stakingTx, stakingInfo, stakingOutputIdx, err := stakingOutputFromTx(ctx, params)
+	if err != nil {
+		return err
+	}
+
+	unbondingAmount := btcutil.Amount(stakingInfo.StakingOutput.Value) - unbondingFee
+	if unbondingAmount <= 0 {
+		return fmt.Errorf("too low staking output value to create unbonding transaction. Staking amount: %d, Unbonding fee: %d", stakingInfo.StakingOutput.Value, unbondingFee)
+	}
+
+	unbondingInfo, err := params.unbondingInfo(unbondingTime, unbondingAmount)
+	if err != nil {
+		return fmt.Errorf("error building unbonding info: %w", err)
+	}
+
+	stakingTxHash := stakingTx.TxHash()
+
+	packet, err := psbt.New(
+		[]*wire.OutPoint{wire.NewOutPoint(&stakingTxHash, stakingOutputIdx)},
+		[]*wire.TxOut{unbondingInfo.UnbondingOutput},
+		2,
+		0,
+		[]uint32{wire.MaxTxInSequenceNum},
+	)
+	if err != nil {
+		return err
+	}
+
+	unbondingPathInfo, err := stakingInfo.UnbondingPathSpendInfo()
+	if err != nil {
+		return err
+	}
+
+	if err := fillTaprootSpendInput(&packet.Inputs[0], params.stakerPk, stakingInfo.StakingOutput, unbondingPathInfo); err != nil {
+		return err
+	}
+
+	txHex, encodedPacket, err := psbtResponse(packet)
+	if err != nil {
+		return err
+	}
+
+	helpers.PrintRespJSON(&CreateUnbondingTxResponse{
+		UnbondingTxHex:            txHex,
+		UnbondingPsbtPacketBase64: encodedPacket,
+	})
+	return nil
+}
+
+var createWithdrawalTxCmd = cli.Command{
+	Name:      "create-withdrawal-tx",
+	ShortName: "cwt",
+	Usage:     "Creates unsigned Babylon withdrawal transaction from provided parameters without connecting to any node",
+	Flags: append([]cli.Flag{
+		cli.StringFlag{
+			Name:     stakingTransactionFlag,
+			Usage:    "Staking transaction in hex",
+			Required: true,
+		},
+		cli.StringFlag{
+			Name:  unbondingTransactionFlag,
+			Usage: "Unbonding transaction in hex. This should only be provided, if withdrawal is being done from unbonding output",
+		},
+		cli.Int64Flag{
+			Name:  unbondingTimeBlocksFlag,
+			Usage: "Unbonding time in BTC blocks. Required if withdrawing from unbonding output",
+		},
+		cli.StringFlag{
+			Name:     withdrawalAddressFlag,
+			Usage:    "btc address to which send the withdrawed funds",
+			Required: true,
+		},
+		cli.Int64Flag{
+			Name:     withdrawalTransactionFeeFlag,
+			Usage:    "fee to pay for withdrawal transaction",
+			Required: true,
+		},
+	}, stakingScriptFlags...),
+	Action: createWithdrawalTx,
+}
+
+func createWithdrawalTx(ctx *cli.Context) error {
+	params, err := parseOfflineScriptParams(ctx)
+	if err != nil {
+		return err
+	}
+
+	withdrawalFee, err := parseAmountFromCliCtx(ctx, withdrawalTransactionFeeFlag)
+	if err != nil {
+		return err
+	}
+
+	withdrawalAddress, err := btcutil.DecodeAddress(ctx.String(withdrawalAddressFlag), params.net)
+	if err != nil {
+		return fmt.Errorf("error decoding withdrawal address: %w", err)
+	}
+
+	withdrawalPkScript, err := txscript.PayToAddrScript(withdrawalAddress)
+	if err != nil {
+		return fmt.Errorf("error creating pk script for withdrawal address: %w", err)
+	}
+
+	stakingTx, stakingInfo, stakingOutputIdx, err := stakingOutputFromTx(ctx, params)
+	if err != nil {
+		return err
+	}
+
+	stakingTxHash := stakingTx.TxHash()
+
+	var (
+		input     *wire.OutPoint
+		utxo      *wire.TxOut
+		sequence  uint32
+		spendInfo *btcstaking.SpendInfo
+	)
+
+	if unbondingTxHex := ctx.String(unbondingTransactionFlag); len(unbondingTxHex) > 0 {
+		unbondingTx, _, err := bbn.NewBTCTxFromHex(unbondingTxHex)
+		if err != nil {
+			return fmt.Errorf("error parsing unbonding transaction: %w", err)
+		}
+
+		if err := isSimpleTransfer(unbondingTx); err != nil {
+			return fmt.Errorf("unbonding transaction is not valid: %w", err)
+		}
+
+		if unbondingTx.TxIn[0].PreviousOutPoint != *wire.NewOutPoint(&stakingTxHash, stakingOutputIdx) {
+			return fmt.Errorf("unbonding transaction does not spend staking output")
+		}
+
+		unbondingTime, err := parseLockTimeBlocksFromCliCtx(ctx, unbondingTimeBlocksFlag)
+		if err != nil {
+			return err
+		}
+
+		unbondingInfo, err := params.unbondingInfo(unbondingTime, btcutil.Amount(unbondingTx.TxOut[0].Value))
+		if err != nil {
+			return fmt.Errorf("error building unbonding info: %w", err)
+		}
+
+		if !outputsAreEqual(unbondingInfo.UnbondingOutput, unbondingTx.TxOut[0]) {
+			return fmt.Errorf("unbonding transaction output does not match with expected output")
+		}
+
+		spendInfo, err = unbondingInfo.TimeLockPathSpendInfo()
+		if err != nil {
+			return fmt.Errorf("error building time lock path spend info: %w", err)
+		}
+
+		unbondingTxHash := unbondingTx.TxHash()
+		input = wire.NewOutPoint(&unbondingTxHash, 0)
+		utxo = unbondingTx.TxOut[0]
+		sequence = uint32(unbondingTime)
+	} else {
+		spendInfo, err = stakingInfo.TimeLockPathSpendInfo()
+		if err != nil {
+			return fmt.Errorf("error building time lock path spend info: %w", err)
+		}
+
+		input = wire.NewOutPoint(&stakingTxHash, stakingOutputIdx)
+		utxo = stakingInfo.StakingOutput
+		sequence = uint32(params.stakingTime)
+	}
+
+	withdrawalValue := utxo.Value - int64(withdrawalFee)
+	if withdrawalValue <= 0 {
+		return fmt.Errorf("too low output value to create withdrawal transaction. Output amount: %d, Withdrawal fee: %d", utxo.Value, withdrawalFee)
+	}
+
+	packet, err := psbt.New(
+		[]*wire.OutPoint{input},
+		[]*wire.TxOut{wire.NewTxOut(withdrawalValue, withdrawalPkScript)},
+		2,
+		0,
+		[]uint32{sequence},
+	)
+	if err != nil {
+		return err
+	}
+
+	if err := fillTaprootSpendInput(&packet.Inputs[0], params.stakerPk, utxo, spendInfo); err != nil {
+		return err
+	}
+
+	txHex, encodedPacket, err := psbtResponse(packet)
+	if err != nil {
+		return err
+	}
+
+	helpers.PrintRespJSON(&CreateWithdrawalTxResponse{
+		WithdrawalTxHex:            txHex,
+		WithdrawalPsbtPacketBase64: encodedPacket,
+	})
+	return nil
+}
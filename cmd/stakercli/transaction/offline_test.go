@@ -0,0 +1,123 @@
+package transaction_test
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/babylonlabs-io/babylon/v4/btcstaking"
+	"github.com/babylonlabs-io/babylon/v4/testutil/datagen"
+	bbn "github.com/babylonlabs-io/babylon/v4/types"
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli"
+
+	"github.com/babylonlabs-io/btc-staker/cmd/stakercli/transaction"
+	"github.com/babylonlabs-io/btc-staker/testutil"
+)
+
+func appRunOffline[T any](r *rand.Rand, t *testing.T, app *cli.App, cmd string, arguments []string) T {
+	args := []string{"stakercli", "transaction", cmd}
+	args = append(args, arguments...)
+	output := appRunWithOutput(r, t, app, args)
+
+	var data T
+	err := json.Unmarshal([]byte(output), &data)
+	require.NoError(t, err)
+	return data
+}
+
+func genRandomTaprootAddress(t *testing.T, net *chaincfg.Params) btcutil.Address {
+	addr, err := btcutil.NewAddressTaproot(
+		schnorr.SerializePubKey(txscript.ComputeTaprootKeyNoScript(genRandomPubKey(t))),
+		net,
+	)
+	require.NoError(t, err)
+	return addr
+}
+
+func FuzzOfflineStakingUnbondingWithdrawal(f *testing.F) {
+	datagen.AddRandomSeedsToFuzzer(f, 10)
+	f.Fuzz(func(t *testing.T, seed int64) {
+		r := rand.New(rand.NewSource(seed))
+		net := &chaincfg.RegressionNetParams
+
+		stakerParams, _ := createCustomValidStakeParams(t, r, &globalParams, net)
+
+		scriptArgs := []string{
+			fmt.Sprintf("--staker-pk=%s", keyToSchnorrHex(stakerParams.StakerPk)),
+			fmt.Sprintf("--finality-provider-pk=%s", keyToSchnorrHex(stakerParams.FinalityProviderPk)),
+			fmt.Sprintf("--staking-time=%d", stakerParams.StakingTime),
+			fmt.Sprintf("--covenant-quorum=%d", lastParams.CovenantQuorum),
+			fmt.Sprintf("--network=%s", net.Name),
+		}
+		for _, pk := range lastParams.CovenantPks {
+			scriptArgs = append(scriptArgs, fmt.Sprintf("--covenant-committee-pks=%s", keyToSchnorrHex(pk)))
+		}
+
+		fundingAmount := int64(stakerParams.StakingAmount) + 10000
+		fundingHash := datagen.GenRandomBtcdHash(r)
+		fundingPkScript, err := txscript.PayToAddrScript(genRandomTaprootAddress(t, net))
+		require.NoError(t, err)
+
+		changeAddr := genRandomTaprootAddress(t, net)
+
+		app := testutil.TestApp()
+		stakingResp := appRunOffline[transaction.CreateStakingTxResponse](r, t, app, "create-staking-tx", append([]string{
+			fmt.Sprintf("--staking-amount=%d", stakerParams.StakingAmount),
+			fmt.Sprintf("--funding-input=%s:0:%d:%s", fundingHash.String(), fundingAmount, hex.EncodeToString(fundingPkScript)),
+			fmt.Sprintf("--change-address=%s", changeAddr.EncodeAddress()),
+			"--staking-fee=1000",
+		}, scriptArgs...))
+		require.NotEmpty(t, stakingResp.StakingPsbtPacketBase64)
+
+		stakingTx, _, err := bbn.NewBTCTxFromHex(stakingResp.StakingTxHex)
+		require.NoError(t, err)
+		require.Len(t, stakingTx.TxIn, 1)
+		require.Len(t, stakingTx.TxOut, 2)
+		require.Equal(t, int64(9000), stakingTx.TxOut[1].Value)
+
+		stakingInfo, err := btcstaking.BuildStakingInfo(
+			stakerParams.StakerPk,
+			[]*btcec.PublicKey{stakerParams.FinalityProviderPk},
+			lastParams.CovenantPks,
+			lastParams.CovenantQuorum,
+			stakerParams.StakingTime,
+			stakerParams.StakingAmount,
+			net,
+		)
+		require.NoError(t, err)
+		require.Equal(t, stakingInfo.StakingOutput, stakingTx.TxOut[stakingResp.StakingOutputIdx])
+
+		unbondingResp := appRunOffline[transaction.CreateUnbondingTxResponse](r, t, app, "create-unbonding-tx", append([]string{
+			fmt.Sprintf("--staking-transaction=%s", stakingResp.StakingTxHex),
+			fmt.Sprintf("--unbonding-time=%d", lastParams.UnbondingTime),
+			fmt.Sprintf("--unbonding-fee=%d", lastParams.UnbondingFee),
+		}, scriptArgs...))
+
+		unbondingTx, _, err := bbn.NewBTCTxFromHex(unbondingResp.UnbondingTxHex)
+		require.NoError(t, err)
+		require.Equal(t, stakingTx.TxHash(), unbondingTx.TxIn[0].PreviousOutPoint.Hash)
+		require.Equal(t, int64(stakerParams.StakingAmount-lastParams.UnbondingFee), unbondingTx.TxOut[0].Value)
+
+		withdrawalResp := appRunOffline[transaction.CreateWithdrawalTxResponse](r, t, app, "create-withdrawal-tx", append([]string{
+			fmt.Sprintf("--staking-transaction=%s", stakingResp.StakingTxHex),
+			fmt.Sprintf("--unbonding-transaction=%s", unbondingResp.UnbondingTxHex),
+			fmt.Sprintf("--unbonding-time=%d", lastParams.UnbondingTime),
+			fmt.Sprintf("--withdrawal-address=%s", changeAddr.EncodeAddress()),
+			"--withdrawal-fee=500",
+		}, scriptArgs...))
+
+		withdrawalTx, _, err := bbn.NewBTCTxFromHex(withdrawalResp.WithdrawalTxHex)
+		require.NoError(t, err)
+		require.Equal(t, unbondingTx.TxHash(), withdrawalTx.TxIn[0].PreviousOutPoint.Hash)
+		require.Equal(t, uint32(lastParams.UnbondingTime), withdrawalTx.TxIn[0].Sequence)
+		require.Equal(t, unbondingTx.TxOut[0].Value-500, withdrawalTx.TxOut[0].Value)
+	})
+}
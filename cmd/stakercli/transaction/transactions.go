@@ -55,6 +55,10 @@ var TransactionCommands = []cli.Command{
 			createPhase1StakingTransactionWithParamsCmd,
 			createPhase1UnbondingTransactionCmd,
 			createPhase1WithdrawalTransactionCmd,
+			createStakingTxCmd,
+			createUnbondingTxCmd,
+			createWithdrawalTxCmd,
+			decodeTransactionCmd,
 		},
 	},
 }
@@ -71,7 +75,7 @@ var checkPhase1StakingTransactionParamsCmd = cli.Command{
 		},
 		cli.StringFlag{
 			Name:     networkNameFlag,
-			Usage:    "Bitcoin network on which staking should take place one of (mainnet, testnet3, regtest, simnet, signet)",
+			Usage:    "Bitcoin network on which staking should take place one of (mainnet, testnet3, testnet4, regtest, simnet, signet)",
 			Required: true,
 		},
 	},
@@ -242,7 +246,7 @@ var createPhase1StakingTransactionCmd = cli.Command{
 		},
 		cli.StringFlag{
 			Name:     networkNameFlag,
-			Usage:    "Bitcoin network on which staking should take place one of (mainnet, testnet3, regtest, simnet, signet)",
+			Usage:    "Bitcoin network on which staking should take place one of (mainnet, testnet3, testnet4, regtest, simnet, signet)",
 			Required: true,
 		},
 	},
@@ -355,7 +359,7 @@ var checkPhase1StakingTransactionCmd = cli.Command{
 		},
 		cli.StringFlag{
 			Name:     networkNameFlag,
-			Usage:    "Bitcoin network on which staking should take place one of (mainnet, testnet3, regtest, simnet, signet)",
+			Usage:    "Bitcoin network on which staking should take place one of (mainnet, testnet3, testnet4, regtest, simnet, signet)",
 			Required: true,
 		},
 		cli.StringFlag{
@@ -503,7 +507,7 @@ var createPhase1StakingTransactionWithParamsCmd = cli.Command{
 		},
 		cli.StringFlag{
 			Name:     networkNameFlag,
-			Usage:    "Bitcoin network on which staking should take place one of (mainnet, testnet3, regtest, simnet, signet)",
+			Usage:    "Bitcoin network on which staking should take place one of (mainnet, testnet3, testnet4, regtest, simnet, signet)",
 			Required: true,
 		},
 	},
@@ -645,7 +649,7 @@ var createPhase1UnbondingTransactionCmd = cli.Command{
 		},
 		cli.StringFlag{
 			Name:     networkNameFlag,
-			Usage:    "Bitcoin network on which staking should take place one of (mainnet, testnet3, regtest, simnet, signet)",
+			Usage:    "Bitcoin network on which staking should take place one of (mainnet, testnet3, testnet4, regtest, simnet, signet)",
 			Required: true,
 		},
 		cli.StringFlag{
@@ -867,7 +871,7 @@ var createPhase1WithdrawalTransactionCmd = cli.Command{
 		},
 		cli.StringFlag{
 			Name:     networkNameFlag,
-			Usage:    "Bitcoin network on which withdrawal should take place one of (mainnet, testnet3, regtest, simnet, signet)",
+			Usage:    "Bitcoin network on which withdrawal should take place one of (mainnet, testnet3, testnet4, regtest, simnet, signet)",
 			Required: true,
 		},
 		cli.StringFlag{
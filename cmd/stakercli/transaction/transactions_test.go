@@ -29,7 +29,7 @@ import (
 	"github.com/urfave/cli"
 
 	"github.com/babylonlabs-io/btc-staker/cmd/stakercli/transaction"
-	"github.com/babylonlabs-io/btc-staker/itest/testutil"
+	"github.com/babylonlabs-io/btc-staker/testutil"
 	"github.com/babylonlabs-io/btc-staker/utils"
 )
 
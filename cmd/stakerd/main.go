@@ -9,12 +9,16 @@ import (
 	"os/signal"
 	"runtime/pprof"
 	"syscall"
+	"time"
 
+	"github.com/babylonlabs-io/btc-staker/cluster"
 	"github.com/babylonlabs-io/btc-staker/cmd"
 	"github.com/babylonlabs-io/btc-staker/metrics"
+	str "github.com/babylonlabs-io/btc-staker/staker"
 	scfg "github.com/babylonlabs-io/btc-staker/stakercfg"
 	service "github.com/babylonlabs-io/btc-staker/stakerservice"
 	"github.com/joho/godotenv"
+	"github.com/sirupsen/logrus"
 
 	"github.com/jessevdk/go-flags"
 )
@@ -63,6 +67,40 @@ func main() {
 		defer pprof.StopCPUProfile()
 	}
 
+	var stakerOpts []str.Option
+	if cfg.ClusterConfig.EnableLeaderElection {
+		cfgLogger.Infof("Waiting to be elected as leader with id %s", cfg.ClusterConfig.ID)
+		elector, err := campaignForLeadership(ctx, cfg)
+		if err != nil {
+			cfgLogger.Errorf("failed to become leader: %v", err)
+			os.Exit(1)
+		}
+		defer func() {
+			resignCtx, resignCancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer resignCancel()
+			if err := elector.Resign(resignCtx); err != nil {
+				cfgLogger.Errorf("failed to resign leadership: %v", err)
+			}
+			_ = elector.Close()
+		}()
+
+		cfgLogger.Infof("Elected as leader with id %s, term %d", cfg.ClusterConfig.ID, elector.Term())
+		stakerOpts = append(stakerOpts, str.WithLeaderElector(elector))
+
+		// stop staker once leadership is lost, as other instance takes over
+		var leaderCancel context.CancelFunc
+		ctx, leaderCancel = context.WithCancel(ctx)
+		defer leaderCancel()
+		go func() {
+			select {
+			case <-elector.Done():
+				cfgLogger.Error("Lost leadership, shutting down")
+				leaderCancel()
+			case <-ctx.Done():
+			}
+		}()
+	}
+
 	dbBackend, err := scfg.GetDBBackend(cfg.DBConfig)
 
 	if err != nil {
@@ -72,6 +110,12 @@ func main() {
 	}
 
 	stakerMetrics := metrics.NewStakerMetrics()
+	if cfg.DBConfig.Backend == scfg.BoltBackend {
+		if err := stakerMetrics.RegisterDBStats(dbBackend); err != nil {
+			cfgLogger.Errorf("failed to register db stats metrics: %v", err)
+			os.Exit(1)
+		}
+	}
 
 	s, err := service.NewStakerServiceFromConfig(
 		cfg,
@@ -79,6 +123,7 @@ func main() {
 		zapLogger,
 		dbBackend,
 		stakerMetrics,
+		stakerOpts...,
 	)
 
 	if err != nil {
@@ -107,8 +152,68 @@ func main() {
 		os.Exit(1)
 	}
 
+	// SIGHUP is registered before starting service, as by default it
+	// terminates the process
+	sighup := make(chan os.Signal, 1)
+	signal.Notify(sighup, syscall.SIGHUP)
+	go reloadOnSighup(ctx, sighup, s, cfgLogger)
+
 	if err = s.RunUntilShutdown(ctx, expUsername, expPwd); err != nil {
 		_, _ = fmt.Fprintln(os.Stderr, err)
 		os.Exit(1)
 	}
 }
+
+// reloadOnSighup reloads rpc listen addresses from config on SIGHUP, without
+// restarting staker
+func reloadOnSighup(ctx context.Context, sighup <-chan os.Signal, s *service.StakerService, logger *logrus.Logger) {
+	for {
+		select {
+		case <-sighup:
+			logger.Info("Received SIGHUP, reloading rpc listeners")
+
+			addrs, err := scfg.ReloadRPCListeners()
+			if err != nil {
+				logger.Errorf("failed to reload config: %v", err)
+				continue
+			}
+
+			if err := s.ReloadRPCListeners(addrs); err != nil {
+				logger.Errorf("failed to reload rpc listeners: %v", err)
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// campaignForLeadership blocks until this instance is elected as leader
+func campaignForLeadership(ctx context.Context, cfg *scfg.Config) (cluster.LeaderElector, error) {
+	clusterCfg := cfg.ClusterConfig
+
+	var electorArgs []interface{}
+	switch clusterCfg.LeaderElector {
+	case cluster.EtcdLeaderElector:
+		electorArgs = []interface{}{
+			clusterCfg.EtcdElectionPrefix, clusterCfg.LeaderSessionTTL, cfg.DBConfig.Etcd,
+		}
+	case cluster.PostgresLeaderElector:
+		electorArgs = []interface{}{
+			clusterCfg.PostgresLockName, cfg.DBConfig.Postgres,
+		}
+	}
+
+	elector, err := cluster.MakeLeaderElector(
+		ctx, clusterCfg.LeaderElector, clusterCfg.ID, electorArgs...,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create leader elector: %w", err)
+	}
+
+	if err := elector.Campaign(ctx); err != nil {
+		_ = elector.Close()
+		return nil, fmt.Errorf("failed to campaign for leadership: %w", err)
+	}
+
+	return elector, nil
+}
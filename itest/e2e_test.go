@@ -9,12 +9,14 @@ import (
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"math/rand"
 	"testing"
 	"time"
 
 	"github.com/babylonlabs-io/btc-staker/babylonclient/keyringcontroller"
-	"github.com/babylonlabs-io/btc-staker/itest/containers"
-	"github.com/babylonlabs-io/btc-staker/itest/testutil"
+	"github.com/babylonlabs-io/btc-staker/testutil"
+	"github.com/babylonlabs-io/btc-staker/testutil/e2e"
+	"github.com/babylonlabs-io/btc-staker/testutil/e2e/containers"
 	"github.com/babylonlabs-io/networks/parameters/parser"
 
 	"github.com/babylonlabs-io/babylon/v4/btcstaking"
@@ -38,22 +40,26 @@ import (
 	"github.com/stretchr/testify/require"
 )
 
+var (
+	r = rand.New(rand.NewSource(time.Now().Unix()))
+)
+
 func TestStakingFailures(t *testing.T) {
 	t.Parallel()
 	numMatureOutputs := uint32(200)
 	ctx, cancel := context.WithCancel(context.Background())
-	tm := StartManager(t, ctx, numMatureOutputs)
+	tm := e2e.StartManager(t, ctx, numMatureOutputs)
 	defer tm.Stop(t, cancel)
-	tm.insertAllMinedBlocksToBabylon(t)
+	tm.InsertAllMinedBlocksToBabylon(t)
 
 	cl := tm.Sa.BabylonController()
 	params, err := cl.Params()
 	require.NoError(t, err)
 
-	testStakingData := tm.getTestStakingData(t, tm.WalletPubKey, params.MinStakingTime, 10000, 1)
+	testStakingData := tm.GetTestStakingData(t, tm.WalletPubKey, params.MinStakingTime, 10000, 1)
 	fpKey := hex.EncodeToString(schnorr.SerializePubKey(testStakingData.FinalityProviderBtcKeys[0]))
 
-	tm.createAndRegisterFinalityProviders(t, testStakingData)
+	tm.CreateAndRegisterFinalityProviders(t, testStakingData)
 
 	// Duplicated provider key
 	_, err = tm.StakerClient.Stake(
@@ -62,6 +68,12 @@ func TestStakingFailures(t *testing.T) {
 		testStakingData.StakingAmount,
 		[]string{fpKey, fpKey},
 		int64(testStakingData.StakingTime),
+		"",
+		"",
+		"",
+		0,
+		0,
+		0,
 	)
 	require.Error(t, err)
 
@@ -72,6 +84,12 @@ func TestStakingFailures(t *testing.T) {
 		testStakingData.StakingAmount,
 		[]string{},
 		int64(testStakingData.StakingTime),
+		"",
+		"",
+		"",
+		0,
+		0,
+		0,
 	)
 	require.Error(t, err)
 }
@@ -83,15 +101,15 @@ func TestSendingStakingTransactionWithPreApproval(t *testing.T) {
 	// will generate 300 blocks
 	numMatureOutputs := uint32(200)
 	ctx, cancel := context.WithCancel(context.Background())
-	tm := StartManager(t, ctx, numMatureOutputs)
+	tm := e2e.StartManager(t, ctx, numMatureOutputs)
 	defer tm.Stop(t, cancel)
-	tm.insertAllMinedBlocksToBabylon(t)
+	tm.InsertAllMinedBlocksToBabylon(t)
 
 	cl := tm.Sa.BabylonController()
 	params, err := cl.Params()
 	require.NoError(t, err)
 
-	testStakingData := tm.getTestStakingData(t, tm.WalletPubKey, params.MinStakingTime, 100000, 1)
+	testStakingData := tm.GetTestStakingData(t, tm.WalletPubKey, params.MinStakingTime, 100000, 1)
 
 	// since transaction never sent to bitcoin,
 	// tx is not found
@@ -104,51 +122,51 @@ func TestSendingStakingTransactionWithPreApproval(t *testing.T) {
 	require.NoError(t, erro)
 	require.Equal(t, st, walletcontroller.TxNotFound)
 
-	tm.createAndRegisterFinalityProviders(t, testStakingData)
+	tm.CreateAndRegisterFinalityProviders(t, testStakingData)
 
-	txHash := tm.sendStakingTxBTC(t, testStakingData)
+	txHash := tm.SendStakingTxBTC(t, testStakingData)
 
-	go tm.mineNEmptyBlocks(t, params.ConfirmationTimeBlocks, true)
-	tm.waitForStakingTxState(t, txHash, staker.BabylonPendingStatus)
+	go tm.MineNEmptyBlocks(t, params.ConfirmationTimeBlocks, true)
+	tm.WaitForStakingTxState(t, txHash, staker.BabylonPendingStatus)
 
 	pend, err := tm.BabylonClient.QueryPendingBTCDelegations()
 	require.NoError(t, err)
 	require.Len(t, pend, 1)
 	// need to activate delegation to unbond
-	tm.insertCovenantSigForDelegation(t, pend[0])
-	tm.waitForStakingTxState(t, txHash, staker.BabylonVerifiedStatus)
+	tm.InsertCovenantSigForDelegation(t, pend[0])
+	tm.WaitForStakingTxState(t, txHash, staker.BabylonVerifiedStatus)
 
 	require.Eventually(t, func() bool {
-		txFromMempool := retrieveTransactionFromMempool(t, tm.TestRpcBtcClient, []*chainhash.Hash{txHash})
+		txFromMempool := e2e.RetrieveTransactionFromMempool(t, tm.TestRpcBtcClient, []*chainhash.Hash{txHash})
 		return len(txFromMempool) == 1
-	}, eventuallyWaitTimeOut, eventuallyPollTime)
+	}, e2e.EventuallyWaitTimeOut, e2e.EventuallyPollTime)
 
-	mBlock := tm.mineBlock(t)
+	mBlock := tm.MineBlock(t)
 	require.Equal(t, 2, len(mBlock.Transactions))
 
 	headerBytes := bbntypes.NewBTCHeaderBytesFromBlockHeader(&mBlock.Header)
-	proof, err := btcctypes.SpvProofFromHeaderAndTransactions(&headerBytes, txsToBytes(mBlock.Transactions), 1)
+	proof, err := btcctypes.SpvProofFromHeaderAndTransactions(&headerBytes, e2e.TxsToBytes(mBlock.Transactions), 1)
 	require.NoError(t, err)
 
 	_, err = tm.BabylonClient.InsertBtcBlockHeaders([]*wire.BlockHeader{&mBlock.Header})
 	require.NoError(t, err)
 
-	tm.mineNEmptyBlocks(t, params.ConfirmationTimeBlocks, true)
+	tm.MineNEmptyBlocks(t, params.ConfirmationTimeBlocks, true)
 
 	_, err = tm.BabylonClient.ActivateDelegation(
 		*txHash,
 		proof,
 	)
 	require.NoError(t, err)
-	tm.waitForStakingTxState(t, txHash, staker.BabylonActiveStatus)
+	tm.WaitForStakingTxState(t, txHash, staker.BabylonActiveStatus)
 
 	// check that there is not error when qury for withdrawable transactions
-	withdrawableTransactionsResp, err := tm.StakerClient.WithdrawableTransactions(context.Background(), nil, nil)
+	withdrawableTransactionsResp, err := tm.StakerClient.WithdrawableTransactions(context.Background(), nil, nil, nil, "")
 	require.NoError(t, err)
 	require.Len(t, withdrawableTransactionsResp.Transactions, 0)
 
 	//  Unbond pre-approval stake
-	resp, err := tm.StakerClient.UnbondStaking(context.Background(), txHash.String())
+	resp, err := tm.StakerClient.UnbondStaking(context.Background(), txHash.String(), 0, 0, "")
 	require.NoError(t, err)
 
 	unbondingTxHash, err := chainhash.NewHashFromStr(resp.UnbondingTxHash)
@@ -165,17 +183,17 @@ func TestSendingStakingTransactionWithPreApproval(t *testing.T) {
 
 		}
 		return true
-	}, 1*time.Minute, eventuallyPollTime)
+	}, 1*time.Minute, e2e.EventuallyPollTime)
 
-	block := tm.mineBlock(t)
+	block := tm.MineBlock(t)
 	require.Equal(t, 2, len(block.Transactions))
 	require.Equal(t, block.Transactions[1].TxHash(), *unbondingTxHash)
-	tm.mineNEmptyBlocks(t, staker.UnbondingTxConfirmations, false)
-	tm.waitForUnbondingTxConfirmedOnBtc(t, txHash, unbondingTxHash)
+	tm.MineNEmptyBlocks(t, staker.UnbondingTxConfirmations, false)
+	tm.WaitForUnbondingTxConfirmedOnBtc(t, txHash, unbondingTxHash)
 
 	// Spend unbonding tx of pre-approval stake
 	require.Eventually(t, func() bool {
-		withdrawableTransactionsResp, err = tm.StakerClient.WithdrawableTransactions(context.Background(), nil, nil)
+		withdrawableTransactionsResp, err = tm.StakerClient.WithdrawableTransactions(context.Background(), nil, nil, nil, "")
 		if err != nil {
 			return false
 		}
@@ -184,14 +202,14 @@ func TestSendingStakingTransactionWithPreApproval(t *testing.T) {
 		}
 
 		return true
-	}, 1*time.Minute, eventuallyPollTime)
+	}, 1*time.Minute, e2e.EventuallyPollTime)
 
 	// We can spend unbonding tx immediately as in e2e test, min unbonding time is 5 blocks and we locked it
 	// for 5 blocks, but to consider unbonding tx as confirmed we need to wait for 6 blocks
 	// so at this point time lock should already have passed
-	tm.spendStakingTxWithHash(t, txHash)
-	tm.mineNEmptyBlocks(t, staker.UnbondingTxConfirmations, false)
-	tm.waitForTxOutputSpent(t, unbondingTxHash)
+	tm.SpendStakingTxWithHash(t, txHash)
+	tm.MineNEmptyBlocks(t, staker.UnbondingTxConfirmations, false)
+	tm.WaitForTxOutputSpent(t, unbondingTxHash)
 }
 
 func TestMultiplePreApprovalTransactions(t *testing.T) {
@@ -201,9 +219,9 @@ func TestMultiplePreApprovalTransactions(t *testing.T) {
 	// will generate 300 blocks
 	numMatureOutputs := uint32(200)
 	ctx, cancel := context.WithCancel(context.Background())
-	tm := StartManager(t, ctx, numMatureOutputs)
+	tm := e2e.StartManager(t, ctx, numMatureOutputs)
 	defer tm.Stop(t, cancel)
-	tm.insertAllMinedBlocksToBabylon(t)
+	tm.InsertAllMinedBlocksToBabylon(t)
 
 	cl := tm.Sa.BabylonController()
 	params, err := cl.Params()
@@ -213,12 +231,12 @@ func TestMultiplePreApprovalTransactions(t *testing.T) {
 	stakingTime2 := minStakingTime + 4
 	stakingTime3 := minStakingTime + 1
 
-	testStakingData1 := tm.getTestStakingData(t, tm.WalletPubKey, stakingTime1, 10000, 1)
-	testStakingData2 := testStakingData1.withStakingTime(stakingTime2)
-	testStakingData3 := testStakingData1.withStakingTime(stakingTime3)
+	testStakingData1 := tm.GetTestStakingData(t, tm.WalletPubKey, stakingTime1, 10000, 1)
+	testStakingData2 := testStakingData1.WithStakingTime(stakingTime2)
+	testStakingData3 := testStakingData1.WithStakingTime(stakingTime3)
 
-	tm.createAndRegisterFinalityProviders(t, testStakingData1)
-	txHashes := tm.sendMultipleStakingTxBTC(t, []*testStakingData{
+	tm.CreateAndRegisterFinalityProviders(t, testStakingData1)
+	txHashes := tm.SendMultipleStakingTxBTC(t, []*e2e.TestStakingData{
 		testStakingData1,
 		testStakingData2,
 		testStakingData3,
@@ -226,34 +244,34 @@ func TestMultiplePreApprovalTransactions(t *testing.T) {
 
 	for _, txHash := range txHashes {
 		txHash := txHash
-		tm.waitForStakingTxState(t, txHash, staker.BabylonPendingStatus)
+		tm.WaitForStakingTxState(t, txHash, staker.BabylonPendingStatus)
 	}
 
 	pend, err := tm.BabylonClient.QueryPendingBTCDelegations()
 	require.NoError(t, err)
 	require.Len(t, pend, 3)
-	tm.insertCovenantSigForDelegation(t, pend[0])
-	tm.insertCovenantSigForDelegation(t, pend[1])
-	tm.insertCovenantSigForDelegation(t, pend[2])
+	tm.InsertCovenantSigForDelegation(t, pend[0])
+	tm.InsertCovenantSigForDelegation(t, pend[1])
+	tm.InsertCovenantSigForDelegation(t, pend[2])
 
 	for _, txHash := range txHashes {
 		txHash := txHash
-		tm.waitForStakingTxState(t, txHash, staker.BabylonVerifiedStatus)
+		tm.WaitForStakingTxState(t, txHash, staker.BabylonVerifiedStatus)
 	}
 
 	// Ultimately we will get 3 tx in the mempool meaning all staking transactions
 	// use valid inputs
 	require.Eventually(t, func() bool {
-		txFromMempool := retrieveTransactionFromMempool(t, tm.TestRpcBtcClient, txHashes)
+		txFromMempool := e2e.RetrieveTransactionFromMempool(t, tm.TestRpcBtcClient, txHashes)
 		return len(txFromMempool) == 3
-	}, eventuallyWaitTimeOut, eventuallyPollTime)
+	}, e2e.EventuallyWaitTimeOut, e2e.EventuallyPollTime)
 }
 
 func TestBitcoindWalletRpcApi(t *testing.T) {
 	t.Parallel()
 	manager, err := containers.NewManager(t)
 	require.NoError(t, err)
-	h := NewBitcoindHandler(t, manager)
+	h := e2e.NewBitcoindHandler(t, manager)
 	bitcoind := h.Start()
 	passphrase := "pass"
 	numMatureOutputs := 1
@@ -330,14 +348,14 @@ func TestBitcoindWalletBip322Signing(t *testing.T) {
 	t.Parallel()
 	manager, err := containers.NewManager(t)
 	require.NoError(t, err)
-	h := NewBitcoindHandler(t, manager)
+	h := e2e.NewBitcoindHandler(t, manager)
 	bitcoind := h.Start()
 	passphrase := "pass"
 	walletName := "test-wallet"
 	_ = h.CreateWallet(walletName, passphrase)
 
 	rpcHost := fmt.Sprintf("127.0.0.1:%s", bitcoind.GetPort("18443/tcp"))
-	cfg, c := defaultStakerConfigAndBtc(t, walletName, passphrase, rpcHost)
+	cfg, c := e2e.DefaultStakerConfigAndBtc(t, walletName, passphrase, rpcHost)
 
 	segwitAddress, err := c.GetNewAddress("")
 	require.NoError(t, err)
@@ -353,7 +371,7 @@ func TestBitcoindWalletBip322Signing(t *testing.T) {
 	bip322Signature, err := controller.SignBip322Signature(msg, segwitAddress)
 	require.NoError(t, err)
 
-	err = bip322.Verify(msg, bip322Signature, segwitAddress, regtestParams)
+	err = bip322.Verify(msg, bip322Signature, segwitAddress, e2e.RegtestParams)
 	require.NoError(t, err)
 }
 
@@ -364,18 +382,18 @@ func TestStakeFromPhase1(t *testing.T) {
 	manager, err := containers.NewManager(t)
 	require.NoError(t, err)
 
-	tmBTC := StartManagerBtc(t, ctx, numMatureOutputsInWallet, manager)
+	tmBTC := e2e.StartManagerBtc(t, ctx, numMatureOutputsInWallet, manager)
 
 	minStakingTime := uint16(100)
 	stakerAddr := datagen.GenRandomAccount().GetAddress()
-	testStakingData := GetTestStakingData(t, tmBTC.WalletPubKey, minStakingTime, 10000, 1, stakerAddr)
+	testStakingData := e2e.GetTestStakingData(t, tmBTC.WalletPubKey, minStakingTime, 10000, 1, stakerAddr)
 
 	fpPkHex := hex.EncodeToString(schnorr.SerializePubKey(testStakingData.FinalityProviderBtcKeys[0]))
 	btcStakerPkHex := hex.EncodeToString(schnorr.SerializePubKey(testStakingData.StakerKey))
 
 	appCli := testutil.TestApp()
 
-	coventantPrivKeys := genCovenants(t, 1)
+	coventantPrivKeys := e2e.GenCovenants(t, 1)
 	covenantPkSerializedHex := hex.EncodeToString(schnorr.SerializePubKey(coventantPrivKeys[0].PubKey()))
 	covenantPkHex := hex.EncodeToString(coventantPrivKeys[0].PubKey().SerializeCompressed())
 
@@ -476,7 +494,7 @@ func TestStakeFromPhase1(t *testing.T) {
 		lastParamsVersioned.Tag,
 		lastParamsVersioned.CovenantPks,
 		lastParamsVersioned.CovenantQuorum,
-		regtestParams,
+		e2e.RegtestParams,
 	)
 	require.NoError(t, err)
 	require.NotNil(t, paserdStkTx)
@@ -484,16 +502,16 @@ func TestStakeFromPhase1(t *testing.T) {
 	// at this point the BTC staking transaction is confirmed and was mined in BTC
 	// so the babylon chain can start and try to transition this staking BTC tx
 	// into a babylon BTC delegation in the cosmos side.
-	tmStakerApp := StartManagerStakerApp(t, ctx, tmBTC, manager, 1, coventantPrivKeys)
+	tmStakerApp := e2e.StartManagerStakerApp(t, ctx, tmBTC, manager, 1, coventantPrivKeys)
 
-	tm := &TestManager{
-		manager:              manager,
+	tm := &e2e.TestManager{
+		Manager:              manager,
 		TestManagerStakerApp: *tmStakerApp,
 		TestManagerBTC:       *tmBTC,
 	}
 	defer tm.Stop(t, cancel)
 
-	tm.manager.WaitForNextBabylonBlock(t)
+	tm.Manager.WaitForNextBabylonBlock(t)
 
 	// verify that the chain is healthy
 	require.Eventually(t, func() bool {
@@ -502,17 +520,17 @@ func TestStakeFromPhase1(t *testing.T) {
 	}, time.Minute, 200*time.Millisecond)
 
 	// funds the fpd
-	_, _, err = tm.manager.BabylondTxBankMultiSend(t, "node0", "1000000ubbn", testStakingData.FinalityProviderBabylonAddrs[0].String())
+	_, _, err = tm.Manager.BabylondTxBankMultiSend(t, "node0", "1000000ubbn", testStakingData.FinalityProviderBabylonAddrs[0].String())
 	require.NoError(t, err)
 
-	tm.insertAllMinedBlocksToBabylon(t)
-	tm.createAndRegisterFinalityProviders(t, testStakingData)
+	tm.InsertAllMinedBlocksToBabylon(t)
+	tm.CreateAndRegisterFinalityProviders(t, testStakingData)
 
 	stakerAddrStr := tmBTC.MinerAddr.String()
 	stkTxHash := signedStkTx.TxHash().String()
 
 	argsStkFromPhase1 := []string{
-		fmt.Sprintf("--daemon-address=tcp://%s", tm.serviceAddress),
+		fmt.Sprintf("--daemon-address=tcp://%s", tm.ServiceAddress),
 		fmt.Sprintf("--staker-address=%s", stakerAddrStr),
 		fmt.Sprintf("--staking-transaction-hash=%s", stkTxHash),
 		fmt.Sprintf("--tx-inclusion-height=%d", inclusionHeight),
@@ -525,13 +543,13 @@ func TestStakeFromPhase1(t *testing.T) {
 	params, err := cl.Params()
 	require.NoError(t, err)
 
-	tm.mineNEmptyBlocks(t, params.ConfirmationTimeBlocks+1, true)
+	tm.MineNEmptyBlocks(t, params.ConfirmationTimeBlocks+1, true)
 
 	pend, err := tm.BabylonClient.QueryPendingBTCDelegations()
 	require.NoError(t, err)
 	require.Len(t, pend, 1)
 
-	tm.insertCovenantSigForDelegation(t, pend[0])
+	tm.InsertCovenantSigForDelegation(t, pend[0])
 
 	delInfo, err := tm.BabylonClient.QueryBTCDelegation(txHash)
 	require.NoError(t, err)
@@ -542,14 +560,14 @@ func TestPopCreation(t *testing.T) {
 	t.Parallel()
 	manager, err := containers.NewManager(t)
 	require.NoError(t, err)
-	h := NewBitcoindHandler(t, manager)
+	h := e2e.NewBitcoindHandler(t, manager)
 	bitcoind := h.Start()
 	passphrase := "pass"
 	walletName := "test-wallet"
 	_ = h.CreateWallet(walletName, passphrase)
 
 	rpcHost := fmt.Sprintf("127.0.0.1:%s", bitcoind.GetPort("18443/tcp"))
-	cfg, c := defaultStakerConfigAndBtc(t, walletName, passphrase, rpcHost)
+	cfg, c := e2e.DefaultStakerConfigAndBtc(t, walletName, passphrase, rpcHost)
 
 	segwitAddress, err := c.GetNewAddress("")
 	require.NoError(t, err)
@@ -594,14 +612,14 @@ func TestPopCreationTaprootAddress(t *testing.T) {
 	t.Parallel()
 	manager, err := containers.NewManager(t)
 	require.NoError(t, err)
-	h := NewBitcoindHandler(t, manager)
+	h := e2e.NewBitcoindHandler(t, manager)
 	bitcoind := h.Start()
 	passphrase := "pass"
 	walletName := "test-wallet"
 	_ = h.CreateWallet(walletName, passphrase)
 
 	rpcHost := fmt.Sprintf("127.0.0.1:%s", bitcoind.GetPort("18443/tcp"))
-	cfg, c := defaultStakerConfigAndBtc(t, walletName, passphrase, rpcHost)
+	cfg, c := e2e.DefaultStakerConfigAndBtc(t, walletName, passphrase, rpcHost)
 
 	// 'bech32m' is taproot address as defined in bip86: https://github.com/bitcoin/bips/blob/master/bip-0086.mediawiki
 	taprootAddress, err := c.GetNewAddressType("", "bech32m")
@@ -659,15 +677,15 @@ func TestRecoverAfterRestartDuringWithdrawal(t *testing.T) {
 	// will generate 300 blocks
 	numMatureOutputs := uint32(200)
 	ctx, cancel := context.WithCancel(context.Background())
-	tm := StartManager(t, ctx, numMatureOutputs)
+	tm := e2e.StartManager(t, ctx, numMatureOutputs)
 	defer tm.Stop(t, cancel)
-	tm.insertAllMinedBlocksToBabylon(t)
+	tm.InsertAllMinedBlocksToBabylon(t)
 
 	cl := tm.Sa.BabylonController()
 	params, err := cl.Params()
 	require.NoError(t, err)
 
-	testStakingData := tm.getTestStakingData(t, tm.WalletPubKey, params.MinStakingTime, 100000, 1)
+	testStakingData := tm.GetTestStakingData(t, tm.WalletPubKey, params.MinStakingTime, 100000, 1)
 
 	hashed, err := chainhash.NewHash(datagen.GenRandomByteArray(r, 32))
 	require.NoError(t, err)
@@ -678,47 +696,47 @@ func TestRecoverAfterRestartDuringWithdrawal(t *testing.T) {
 	require.NoError(t, erro)
 	require.Equal(t, st, walletcontroller.TxNotFound)
 
-	tm.createAndRegisterFinalityProviders(t, testStakingData)
+	tm.CreateAndRegisterFinalityProviders(t, testStakingData)
 
-	txHash := tm.sendStakingTxBTC(t, testStakingData)
+	txHash := tm.SendStakingTxBTC(t, testStakingData)
 
-	go tm.mineNEmptyBlocks(t, params.ConfirmationTimeBlocks, true)
+	go tm.MineNEmptyBlocks(t, params.ConfirmationTimeBlocks, true)
 	// must wait for all covenant signatures to be received, to be able to unbond
-	tm.waitForStakingTxState(t, txHash, staker.BabylonPendingStatus)
+	tm.WaitForStakingTxState(t, txHash, staker.BabylonPendingStatus)
 
 	pend, err := tm.BabylonClient.QueryPendingBTCDelegations()
 	require.NoError(t, err)
 	require.Len(t, pend, 1)
 	// need to activate delegation to unbond
-	tm.insertCovenantSigForDelegation(t, pend[0])
-	tm.waitForStakingTxState(t, txHash, staker.BabylonVerifiedStatus)
+	tm.InsertCovenantSigForDelegation(t, pend[0])
+	tm.WaitForStakingTxState(t, txHash, staker.BabylonVerifiedStatus)
 
 	require.Eventually(t, func() bool {
-		txFromMempool := retrieveTransactionFromMempool(t, tm.TestRpcBtcClient, []*chainhash.Hash{txHash})
+		txFromMempool := e2e.RetrieveTransactionFromMempool(t, tm.TestRpcBtcClient, []*chainhash.Hash{txHash})
 		return len(txFromMempool) == 1
-	}, eventuallyWaitTimeOut, eventuallyPollTime)
+	}, e2e.EventuallyWaitTimeOut, e2e.EventuallyPollTime)
 
-	mBlock := tm.mineBlock(t)
+	mBlock := tm.MineBlock(t)
 	require.Equal(t, 2, len(mBlock.Transactions))
 
 	headerBytes := bbntypes.NewBTCHeaderBytesFromBlockHeader(&mBlock.Header)
-	proof, err := btcctypes.SpvProofFromHeaderAndTransactions(&headerBytes, txsToBytes(mBlock.Transactions), 1)
+	proof, err := btcctypes.SpvProofFromHeaderAndTransactions(&headerBytes, e2e.TxsToBytes(mBlock.Transactions), 1)
 	require.NoError(t, err)
 
 	_, err = tm.BabylonClient.InsertBtcBlockHeaders([]*wire.BlockHeader{&mBlock.Header})
 	require.NoError(t, err)
 
-	tm.mineNEmptyBlocks(t, params.ConfirmationTimeBlocks, true)
+	tm.MineNEmptyBlocks(t, params.ConfirmationTimeBlocks, true)
 
 	_, err = tm.BabylonClient.ActivateDelegation(
 		*txHash,
 		proof,
 	)
 	require.NoError(t, err)
-	tm.waitForStakingTxState(t, txHash, staker.BabylonActiveStatus)
+	tm.WaitForStakingTxState(t, txHash, staker.BabylonActiveStatus)
 
 	// Unbond staking transaction and wait for it to be included in mempool
-	unbondResponse, err := tm.StakerClient.UnbondStaking(context.Background(), txHash.String())
+	unbondResponse, err := tm.StakerClient.UnbondStaking(context.Background(), txHash.String(), 0, 0, "")
 	require.NoError(t, err)
 	unbondingTxHash, err := chainhash.NewHashFromStr(unbondResponse.UnbondingTxHash)
 	require.NoError(t, err)
@@ -733,19 +751,19 @@ func TestRecoverAfterRestartDuringWithdrawal(t *testing.T) {
 		}
 
 		return true
-	}, 1*time.Minute, eventuallyPollTime)
+	}, 1*time.Minute, e2e.EventuallyPollTime)
 
 	ctxAfter, cancelAfter := context.WithCancel(context.Background())
 	defer cancelAfter()
 
 	tm.RestartAppWithAction(t, ctxAfter, cancel, func(t *testing.T) {
 		// unbodning tx got confirmed during the stop period
-		_ = tm.mineNEmptyBlocks(t, staker.UnbondingTxConfirmations+1, false)
+		_ = tm.MineNEmptyBlocks(t, staker.UnbondingTxConfirmations+1, false)
 	})
 
-	tm.waitForUnbondingTxConfirmedOnBtc(t, txHash, unbondingTxHash)
+	tm.WaitForUnbondingTxConfirmedOnBtc(t, txHash, unbondingTxHash)
 	// it should be possible ot spend from unbonding tx
-	tm.spendStakingTxWithHash(t, txHash)
+	tm.SpendStakingTxWithHash(t, txHash)
 }
 
 func TestStakingUnbonding(t *testing.T) {
@@ -755,57 +773,57 @@ func TestStakingUnbonding(t *testing.T) {
 	// will generate 300 blocks
 	numMatureOutputs := uint32(200)
 	ctx, cancel := context.WithCancel(context.Background())
-	tm := StartManager(t, ctx, numMatureOutputs)
+	tm := e2e.StartManager(t, ctx, numMatureOutputs)
 	defer tm.Stop(t, cancel)
-	tm.insertAllMinedBlocksToBabylon(t)
+	tm.InsertAllMinedBlocksToBabylon(t)
 
 	cl := tm.Sa.BabylonController()
 	params, err := cl.Params()
 	require.NoError(t, err)
 	// large staking time
 	stakingTime := uint16(1000)
-	testStakingData := tm.getTestStakingData(t, tm.WalletPubKey, stakingTime, 50000, 1)
+	testStakingData := tm.GetTestStakingData(t, tm.WalletPubKey, stakingTime, 50000, 1)
 
-	tm.createAndRegisterFinalityProviders(t, testStakingData)
+	tm.CreateAndRegisterFinalityProviders(t, testStakingData)
 
-	txHash := tm.sendStakingTxBTC(t, testStakingData)
+	txHash := tm.SendStakingTxBTC(t, testStakingData)
 
-	go tm.mineNEmptyBlocks(t, params.ConfirmationTimeBlocks, true)
-	tm.waitForStakingTxState(t, txHash, staker.BabylonPendingStatus)
+	go tm.MineNEmptyBlocks(t, params.ConfirmationTimeBlocks, true)
+	tm.WaitForStakingTxState(t, txHash, staker.BabylonPendingStatus)
 	require.NoError(t, err)
 
 	pend, err := tm.BabylonClient.QueryPendingBTCDelegations()
 	require.NoError(t, err)
 	require.Len(t, pend, 1)
 	// need to activate delegation to unbond
-	tm.insertCovenantSigForDelegation(t, pend[0])
-	tm.waitForStakingTxState(t, txHash, staker.BabylonVerifiedStatus)
+	tm.InsertCovenantSigForDelegation(t, pend[0])
+	tm.WaitForStakingTxState(t, txHash, staker.BabylonVerifiedStatus)
 
 	require.Eventually(t, func() bool {
-		txFromMempool := retrieveTransactionFromMempool(t, tm.TestRpcBtcClient, []*chainhash.Hash{txHash})
+		txFromMempool := e2e.RetrieveTransactionFromMempool(t, tm.TestRpcBtcClient, []*chainhash.Hash{txHash})
 		return len(txFromMempool) == 1
-	}, eventuallyWaitTimeOut, eventuallyPollTime)
+	}, e2e.EventuallyWaitTimeOut, e2e.EventuallyPollTime)
 
-	mBlock := tm.mineBlock(t)
+	mBlock := tm.MineBlock(t)
 	require.Equal(t, 2, len(mBlock.Transactions))
 
 	headerBytes := bbntypes.NewBTCHeaderBytesFromBlockHeader(&mBlock.Header)
-	proof, err := btcctypes.SpvProofFromHeaderAndTransactions(&headerBytes, txsToBytes(mBlock.Transactions), 1)
+	proof, err := btcctypes.SpvProofFromHeaderAndTransactions(&headerBytes, e2e.TxsToBytes(mBlock.Transactions), 1)
 	require.NoError(t, err)
 
 	_, err = tm.BabylonClient.InsertBtcBlockHeaders([]*wire.BlockHeader{&mBlock.Header})
 	require.NoError(t, err)
 
-	tm.mineNEmptyBlocks(t, params.ConfirmationTimeBlocks, true)
+	tm.MineNEmptyBlocks(t, params.ConfirmationTimeBlocks, true)
 
 	_, err = tm.BabylonClient.ActivateDelegation(
 		*txHash,
 		proof,
 	)
 	require.NoError(t, err)
-	tm.waitForStakingTxState(t, txHash, staker.BabylonActiveStatus)
+	tm.WaitForStakingTxState(t, txHash, staker.BabylonActiveStatus)
 
-	resp, err := tm.StakerClient.UnbondStaking(context.Background(), txHash.String())
+	resp, err := tm.StakerClient.UnbondStaking(context.Background(), txHash.String(), 0, 0, "")
 	require.NoError(t, err)
 
 	unbondingTxHash, err := chainhash.NewHashFromStr(resp.UnbondingTxHash)
@@ -823,17 +841,17 @@ func TestStakingUnbonding(t *testing.T) {
 		}
 
 		return true
-	}, 1*time.Minute, eventuallyPollTime)
+	}, 1*time.Minute, e2e.EventuallyPollTime)
 
-	block := tm.mineBlock(t)
+	block := tm.MineBlock(t)
 	require.Equal(t, 2, len(block.Transactions))
 	require.Equal(t, block.Transactions[1].TxHash(), *unbondingTxHash)
 
-	tm.mineNEmptyBlocks(t, staker.UnbondingTxConfirmations, false)
-	tm.waitForUnbondingTxConfirmedOnBtc(t, txHash, unbondingTxHash)
+	tm.MineNEmptyBlocks(t, staker.UnbondingTxConfirmations, false)
+	tm.WaitForUnbondingTxConfirmedOnBtc(t, txHash, unbondingTxHash)
 
 	require.Eventually(t, func() bool {
-		withdrawableTransactionsResp, err := tm.StakerClient.WithdrawableTransactions(context.Background(), nil, nil)
+		withdrawableTransactionsResp, err := tm.StakerClient.WithdrawableTransactions(context.Background(), nil, nil, nil, "")
 		if err != nil {
 			return false
 		}
@@ -842,14 +860,14 @@ func TestStakingUnbonding(t *testing.T) {
 		}
 
 		return true
-	}, 1*time.Minute, eventuallyPollTime)
+	}, 1*time.Minute, e2e.EventuallyPollTime)
 
 	// We can spend unbonding tx immediately as in e2e test, min unbonding time is 5 blocks and we locked it
 	// for 5 blocks, but to consider unbonding tx as confirmed we need to wait for 6 blocks
 	// so at this point time lock should already have passed
-	tm.spendStakingTxWithHash(t, txHash)
-	tm.mineNEmptyBlocks(t, staker.SpendStakeTxConfirmations, false)
-	tm.waitForTxOutputSpent(t, unbondingTxHash)
+	tm.SpendStakingTxWithHash(t, txHash)
+	tm.MineNEmptyBlocks(t, staker.SpendStakeTxConfirmations, false)
+	tm.WaitForTxOutputSpent(t, unbondingTxHash)
 }
 
 func TestStakeExpansion(t *testing.T) {
@@ -859,9 +877,9 @@ func TestStakeExpansion(t *testing.T) {
 	// will generate 300 blocks
 	numMatureOutputs := uint32(200)
 	ctx, cancel := context.WithCancel(context.Background())
-	tm := StartManager(t, ctx, numMatureOutputs)
+	tm := e2e.StartManager(t, ctx, numMatureOutputs)
 	defer tm.Stop(t, cancel)
-	tm.insertAllMinedBlocksToBabylon(t)
+	tm.InsertAllMinedBlocksToBabylon(t)
 
 	cl := tm.Sa.BabylonController()
 	params, err := cl.Params()
@@ -872,46 +890,46 @@ func TestStakeExpansion(t *testing.T) {
 	expandedStakingAmount := int64(100000)
 
 	// Create test data for original staking
-	testStakingData := tm.getTestStakingData(t, tm.WalletPubKey, stakingTime, originalStakingAmount, 1)
-	tm.createAndRegisterFinalityProviders(t, testStakingData)
+	testStakingData := tm.GetTestStakingData(t, tm.WalletPubKey, stakingTime, originalStakingAmount, 1)
+	tm.CreateAndRegisterFinalityProviders(t, testStakingData)
 
 	// Step 1: Create and activate initial BTC delegation
-	originalTxHash := tm.sendStakingTxBTC(t, testStakingData)
+	originalTxHash := tm.SendStakingTxBTC(t, testStakingData)
 
-	go tm.mineNEmptyBlocks(t, params.ConfirmationTimeBlocks, true)
-	tm.waitForStakingTxState(t, originalTxHash, staker.BabylonPendingStatus)
+	go tm.MineNEmptyBlocks(t, params.ConfirmationTimeBlocks, true)
+	tm.WaitForStakingTxState(t, originalTxHash, staker.BabylonPendingStatus)
 
 	pend, err := tm.BabylonClient.QueryPendingBTCDelegations()
 	require.NoError(t, err)
 	require.Len(t, pend, 1)
 
 	// Need to activate delegation before expansion
-	tm.insertCovenantSigForDelegation(t, pend[0])
-	tm.waitForStakingTxState(t, originalTxHash, staker.BabylonVerifiedStatus)
+	tm.InsertCovenantSigForDelegation(t, pend[0])
+	tm.WaitForStakingTxState(t, originalTxHash, staker.BabylonVerifiedStatus)
 
 	require.Eventually(t, func() bool {
-		txFromMempool := retrieveTransactionFromMempool(t, tm.TestRpcBtcClient, []*chainhash.Hash{originalTxHash})
+		txFromMempool := e2e.RetrieveTransactionFromMempool(t, tm.TestRpcBtcClient, []*chainhash.Hash{originalTxHash})
 		return len(txFromMempool) == 1
-	}, eventuallyWaitTimeOut, eventuallyPollTime)
+	}, e2e.EventuallyWaitTimeOut, e2e.EventuallyPollTime)
 
-	mBlock := tm.mineBlock(t)
+	mBlock := tm.MineBlock(t)
 	require.Equal(t, 2, len(mBlock.Transactions))
 
 	headerBytes := bbntypes.NewBTCHeaderBytesFromBlockHeader(&mBlock.Header)
-	proof, err := btcctypes.SpvProofFromHeaderAndTransactions(&headerBytes, txsToBytes(mBlock.Transactions), 1)
+	proof, err := btcctypes.SpvProofFromHeaderAndTransactions(&headerBytes, e2e.TxsToBytes(mBlock.Transactions), 1)
 	require.NoError(t, err)
 
 	_, err = tm.BabylonClient.InsertBtcBlockHeaders([]*wire.BlockHeader{&mBlock.Header})
 	require.NoError(t, err)
 
-	tm.mineNEmptyBlocks(t, params.ConfirmationTimeBlocks, true)
+	tm.MineNEmptyBlocks(t, params.ConfirmationTimeBlocks, true)
 
 	_, err = tm.BabylonClient.ActivateDelegation(
 		*originalTxHash,
 		proof,
 	)
 	require.NoError(t, err)
-	tm.waitForStakingTxState(t, originalTxHash, staker.BabylonActiveStatus)
+	tm.WaitForStakingTxState(t, originalTxHash, staker.BabylonActiveStatus)
 
 	// Step 2: Send MsgBtcStakeExpand
 	fpKeys := make([]string, len(testStakingData.FinalityProviderBtcKeys))
@@ -926,6 +944,7 @@ func TestStakeExpansion(t *testing.T) {
 		fpKeys,
 		int64(stakingTime),
 		originalTxHash.String(),
+		"",
 	)
 	require.NoError(t, err)
 
@@ -933,7 +952,7 @@ func TestStakeExpansion(t *testing.T) {
 	require.NoError(t, err)
 
 	// Step 3: Verify new BTC delegation is pending
-	tm.waitForStakingTxState(t, expansionTxHash, staker.BabylonPendingStatus)
+	tm.WaitForStakingTxState(t, expansionTxHash, staker.BabylonPendingStatus)
 
 	pendingDel, err := tm.BabylonClient.QueryPendingBTCDelegations()
 	require.NoError(t, err)
@@ -941,8 +960,8 @@ func TestStakeExpansion(t *testing.T) {
 	require.NotNil(t, pendingDel[0].StkExp)
 
 	// Step 4: Covenant signatures for expansion
-	tm.insertCovenantSigForDelegation(t, pendingDel[0])
-	tm.waitForStakingTxState(t, expansionTxHash, staker.BabylonVerifiedStatus)
+	tm.InsertCovenantSigForDelegation(t, pendingDel[0])
+	tm.WaitForStakingTxState(t, expansionTxHash, staker.BabylonVerifiedStatus)
 
 	// stake expansion delegation should be in verified sate
 	verifiedDel, err := tm.BabylonClient.QueryVerifiedBTCDelegations()
@@ -952,23 +971,23 @@ func TestStakeExpansion(t *testing.T) {
 
 	// Step 5: Wait for expansion transaction to be submitted to Bitcoin mempool
 	require.Eventually(t, func() bool {
-		txFromMempool := retrieveTransactionFromMempool(t, tm.TestRpcBtcClient, []*chainhash.Hash{expansionTxHash})
+		txFromMempool := e2e.RetrieveTransactionFromMempool(t, tm.TestRpcBtcClient, []*chainhash.Hash{expansionTxHash})
 		return len(txFromMempool) == 1
-	}, eventuallyWaitTimeOut, eventuallyPollTime)
+	}, e2e.EventuallyWaitTimeOut, e2e.EventuallyPollTime)
 
 	// Step 6: Mine the expansion transaction
-	expansionBlock := tm.mineBlock(t)
+	expansionBlock := tm.MineBlock(t)
 	require.Equal(t, 2, len(expansionBlock.Transactions))
 
 	expansionHeaderBytes := bbntypes.NewBTCHeaderBytesFromBlockHeader(&expansionBlock.Header)
-	expansionTxInclProof, err := btcctypes.SpvProofFromHeaderAndTransactions(&expansionHeaderBytes, txsToBytes(expansionBlock.Transactions), 1)
+	expansionTxInclProof, err := btcctypes.SpvProofFromHeaderAndTransactions(&expansionHeaderBytes, e2e.TxsToBytes(expansionBlock.Transactions), 1)
 	require.NoError(t, err)
 
 	_, err = tm.BabylonClient.InsertBtcBlockHeaders([]*wire.BlockHeader{&expansionBlock.Header})
 	require.NoError(t, err)
 
 	// Step 7: Wait for the expansion transaction to be k-deep on Bitcoin
-	tm.mineNEmptyBlocks(t, staker.UnbondingTxConfirmations, true)
+	tm.MineNEmptyBlocks(t, staker.UnbondingTxConfirmations, true)
 	require.Eventually(t, func() bool {
 		// Get transaction details and verify confirmations
 		res, err := tm.Sa.Wallet().TxVerbose(expansionTxHash)
@@ -977,7 +996,7 @@ func TestStakeExpansion(t *testing.T) {
 		}
 		// Check if we have the required number of confirmations
 		return res.Confirmations >= staker.UnbondingTxConfirmations
-	}, eventuallyWaitTimeOut, eventuallyPollTime)
+	}, e2e.EventuallyWaitTimeOut, e2e.EventuallyPollTime)
 
 	// Step 8: Report expansion transaction via MsgBTCUndelegate for the original delegation
 	rawStkExpTransaction, err := tm.TestRpcBtcClient.GetRawTransaction(expansionTxHash)
@@ -1007,7 +1026,7 @@ func TestStakeExpansion(t *testing.T) {
 	// Step 9: Wait for expansion to be active
 	// Verify the original delegation is no longer active
 	// and the expansion delegation is active
-	tm.waitForStakingTxState(t, expansionTxHash, staker.BabylonActiveStatus)
+	tm.WaitForStakingTxState(t, expansionTxHash, staker.BabylonActiveStatus)
 
 	originalDelegation, err := tm.BabylonClient.QueryBTCDelegation(originalTxHash)
 	require.NoError(t, err)
@@ -1028,9 +1047,9 @@ func TestMultipleWithdrawableStakingTransactions(t *testing.T) {
 	// will generate 300 blocks
 	numMatureOutputs := uint32(200)
 	ctx, cancel := context.WithCancel(context.Background())
-	tm := StartManager(t, ctx, numMatureOutputs)
+	tm := e2e.StartManager(t, ctx, numMatureOutputs)
 	defer tm.Stop(t, cancel)
-	tm.insertAllMinedBlocksToBabylon(t)
+	tm.InsertAllMinedBlocksToBabylon(t)
 
 	cl := tm.Sa.BabylonController()
 	params, err := cl.Params()
@@ -1042,14 +1061,14 @@ func TestMultipleWithdrawableStakingTransactions(t *testing.T) {
 	stakingTime4 := minStakingTime + 2
 	stakingTime5 := minStakingTime + 3
 
-	testStakingData1 := tm.getTestStakingData(t, tm.WalletPubKey, stakingTime1, 100000, 1)
-	testStakingData2 := testStakingData1.withStakingTime(stakingTime2)
-	testStakingData3 := testStakingData1.withStakingTime(stakingTime3)
-	testStakingData4 := testStakingData1.withStakingTime(stakingTime4)
-	testStakingData5 := testStakingData1.withStakingTime(stakingTime5)
+	testStakingData1 := tm.GetTestStakingData(t, tm.WalletPubKey, stakingTime1, 100000, 1)
+	testStakingData2 := testStakingData1.WithStakingTime(stakingTime2)
+	testStakingData3 := testStakingData1.WithStakingTime(stakingTime3)
+	testStakingData4 := testStakingData1.WithStakingTime(stakingTime4)
+	testStakingData5 := testStakingData1.WithStakingTime(stakingTime5)
 
-	tm.createAndRegisterFinalityProviders(t, testStakingData1)
-	txHashes := tm.sendMultipleStakingTxBTC(t, []*testStakingData{
+	tm.CreateAndRegisterFinalityProviders(t, testStakingData1)
+	txHashes := tm.SendMultipleStakingTxBTC(t, []*e2e.TestStakingData{
 		testStakingData1,
 		testStakingData2,
 		testStakingData3,
@@ -1057,10 +1076,10 @@ func TestMultipleWithdrawableStakingTransactions(t *testing.T) {
 		testStakingData5,
 	})
 
-	go tm.mineNEmptyBlocks(t, params.ConfirmationTimeBlocks, true)
+	go tm.MineNEmptyBlocks(t, params.ConfirmationTimeBlocks, true)
 
 	for _, txHash := range txHashes {
-		tm.waitForStakingTxState(t, txHash, staker.BabylonPendingStatus)
+		tm.WaitForStakingTxState(t, txHash, staker.BabylonPendingStatus)
 	}
 
 	pends, err := tm.BabylonClient.QueryPendingBTCDelegations()
@@ -1068,19 +1087,19 @@ func TestMultipleWithdrawableStakingTransactions(t *testing.T) {
 
 	// need to activate delegation
 	for _, pend := range pends {
-		tm.insertCovenantSigForDelegation(t, pend)
+		tm.InsertCovenantSigForDelegation(t, pend)
 	}
 
 	for _, txHash := range txHashes {
-		tm.waitForStakingTxState(t, txHash, staker.BabylonVerifiedStatus)
+		tm.WaitForStakingTxState(t, txHash, staker.BabylonVerifiedStatus)
 	}
 
 	require.Eventually(t, func() bool {
-		txFromMempool := retrieveTransactionFromMempool(t, tm.TestRpcBtcClient, txHashes)
+		txFromMempool := e2e.RetrieveTransactionFromMempool(t, tm.TestRpcBtcClient, txHashes)
 		return len(txFromMempool) == 5
-	}, eventuallyWaitTimeOut, eventuallyPollTime)
+	}, e2e.EventuallyWaitTimeOut, e2e.EventuallyPollTime)
 
-	mBlock := tm.mineBlock(t)
+	mBlock := tm.MineBlock(t)
 	// more than 1 transaction is mined (coinbase tx and staking txs)
 	require.Equal(t, 1+len(txHashes), len(mBlock.Transactions))
 
@@ -1093,7 +1112,7 @@ func TestMultipleWithdrawableStakingTransactions(t *testing.T) {
 		}
 		proof, err := btcctypes.SpvProofFromHeaderAndTransactions(
 			&headerBytes,
-			txsToBytes(mBlock.Transactions),
+			e2e.TxsToBytes(mBlock.Transactions),
 			uint(i),
 		)
 		require.NoError(t, err)
@@ -1103,7 +1122,7 @@ func TestMultipleWithdrawableStakingTransactions(t *testing.T) {
 	_, err = tm.BabylonClient.InsertBtcBlockHeaders([]*wire.BlockHeader{&mBlock.Header})
 	require.NoError(t, err)
 
-	tm.mineNEmptyBlocks(t, params.ConfirmationTimeBlocks, true)
+	tm.MineNEmptyBlocks(t, params.ConfirmationTimeBlocks, true)
 
 	for i, tx := range mBlock.Transactions {
 		if i == 0 { // skip coinbase
@@ -1117,21 +1136,21 @@ func TestMultipleWithdrawableStakingTransactions(t *testing.T) {
 	}
 
 	for _, txHash := range txHashes {
-		tm.waitForStakingTxState(t, txHash, staker.BabylonActiveStatus)
+		tm.WaitForStakingTxState(t, txHash, staker.BabylonActiveStatus)
 	}
 
 	// mine enough block so that:
 	// stakingTime1, stakingTime3, stakingTime4 are spendable
 	blockForStakingToExpire := uint32(testStakingData4.StakingTime) - params.ConfirmationTimeBlocks - 1
-	tm.mineNEmptyBlocks(t, blockForStakingToExpire, false)
+	tm.MineNEmptyBlocks(t, blockForStakingToExpire, false)
 
 	require.Eventually(t, func() bool {
-		withdrawableTransactionsResp, err := tm.StakerClient.WithdrawableTransactions(context.Background(), nil, nil)
+		withdrawableTransactionsResp, err := tm.StakerClient.WithdrawableTransactions(context.Background(), nil, nil, nil, "")
 		require.NoError(t, err)
 		return len(withdrawableTransactionsResp.Transactions) == 3
-	}, 5*time.Minute, eventuallyPollTime)
+	}, 5*time.Minute, e2e.EventuallyPollTime)
 
-	withdrawableTransactionsResp, err := tm.StakerClient.WithdrawableTransactions(context.Background(), nil, nil)
+	withdrawableTransactionsResp, err := tm.StakerClient.WithdrawableTransactions(context.Background(), nil, nil, nil, "")
 	require.NoError(t, err)
 	require.Len(t, withdrawableTransactionsResp.Transactions, 3)
 	require.Equal(t, withdrawableTransactionsResp.LastWithdrawableTransactionIndex, "4")
@@ -1152,9 +1171,9 @@ func TestStakeExpansionWithConsolidation(t *testing.T) {
 	// will generate 300 blocks
 	numMatureOutputs := uint32(200)
 	ctx, cancel := context.WithCancel(context.Background())
-	tm := StartManager(t, ctx, numMatureOutputs)
+	tm := e2e.StartManager(t, ctx, numMatureOutputs)
 	defer tm.Stop(t, cancel)
-	tm.insertAllMinedBlocksToBabylon(t)
+	tm.InsertAllMinedBlocksToBabylon(t)
 
 	cl := tm.Sa.BabylonController()
 	params, err := cl.Params()
@@ -1166,44 +1185,44 @@ func TestStakeExpansionWithConsolidation(t *testing.T) {
 	expandedStakingAmount := int64(10000000000) // use a high value to ensure consolidation is needed
 
 	// Create test data for original staking
-	testStakingData := tm.getTestStakingData(t, tm.WalletPubKey, stakingTime, originalStakingAmount, 1)
-	tm.createAndRegisterFinalityProviders(t, testStakingData)
+	testStakingData := tm.GetTestStakingData(t, tm.WalletPubKey, stakingTime, originalStakingAmount, 1)
+	tm.CreateAndRegisterFinalityProviders(t, testStakingData)
 
 	// Step 1: Create and activate initial BTC delegation
-	originalTxHash := tm.sendStakingTxBTC(t, testStakingData)
-	go tm.mineNEmptyBlocks(t, params.ConfirmationTimeBlocks, true)
-	tm.waitForStakingTxState(t, originalTxHash, staker.BabylonPendingStatus)
+	originalTxHash := tm.SendStakingTxBTC(t, testStakingData)
+	go tm.MineNEmptyBlocks(t, params.ConfirmationTimeBlocks, true)
+	tm.WaitForStakingTxState(t, originalTxHash, staker.BabylonPendingStatus)
 
 	pend, err := tm.BabylonClient.QueryPendingBTCDelegations()
 	require.NoError(t, err)
 	require.Len(t, pend, 1)
 
 	// Need to activate delegation before expansion
-	tm.insertCovenantSigForDelegation(t, pend[0])
-	tm.waitForStakingTxState(t, originalTxHash, staker.BabylonVerifiedStatus)
+	tm.InsertCovenantSigForDelegation(t, pend[0])
+	tm.WaitForStakingTxState(t, originalTxHash, staker.BabylonVerifiedStatus)
 
 	require.Eventually(t, func() bool {
-		txFromMempool := retrieveTransactionFromMempool(t, tm.TestRpcBtcClient, []*chainhash.Hash{originalTxHash})
+		txFromMempool := e2e.RetrieveTransactionFromMempool(t, tm.TestRpcBtcClient, []*chainhash.Hash{originalTxHash})
 		return len(txFromMempool) == 1
-	}, eventuallyWaitTimeOut, eventuallyPollTime)
+	}, e2e.EventuallyWaitTimeOut, e2e.EventuallyPollTime)
 
-	mBlock := tm.mineBlock(t)
+	mBlock := tm.MineBlock(t)
 	require.Equal(t, 2, len(mBlock.Transactions))
 	headerBytes := bbntypes.NewBTCHeaderBytesFromBlockHeader(&mBlock.Header)
-	proof, err := btcctypes.SpvProofFromHeaderAndTransactions(&headerBytes, txsToBytes(mBlock.Transactions), 1)
+	proof, err := btcctypes.SpvProofFromHeaderAndTransactions(&headerBytes, e2e.TxsToBytes(mBlock.Transactions), 1)
 	require.NoError(t, err)
 
 	_, err = tm.BabylonClient.InsertBtcBlockHeaders([]*wire.BlockHeader{&mBlock.Header})
 	require.NoError(t, err)
 
-	tm.mineNEmptyBlocks(t, params.ConfirmationTimeBlocks, true)
+	tm.MineNEmptyBlocks(t, params.ConfirmationTimeBlocks, true)
 
 	_, err = tm.BabylonClient.ActivateDelegation(
 		*originalTxHash,
 		proof,
 	)
 	require.NoError(t, err)
-	tm.waitForStakingTxState(t, originalTxHash, staker.BabylonActiveStatus)
+	tm.WaitForStakingTxState(t, originalTxHash, staker.BabylonActiveStatus)
 
 	// Step 2: Try stake expansion without having a consolidated UTXO - this should fail with insufficient funds error
 	fpKeys := make([]string, len(testStakingData.FinalityProviderBtcKeys))
@@ -1219,6 +1238,7 @@ func TestStakeExpansionWithConsolidation(t *testing.T) {
 		fpKeys,
 		int64(stakingTime),
 		originalTxHash.String(),
+		"",
 	)
 	require.Error(t, err)
 	require.Contains(t, err.Error(), "insufficient funds") // Expected error message
@@ -1238,12 +1258,12 @@ func TestStakeExpansionWithConsolidation(t *testing.T) {
 
 	// Mine the consolidation transaction
 	require.Eventually(t, func() bool {
-		txFromMempool := retrieveTransactionFromMempool(t, tm.TestRpcBtcClient, []*chainhash.Hash{consolidationTxHash})
+		txFromMempool := e2e.RetrieveTransactionFromMempool(t, tm.TestRpcBtcClient, []*chainhash.Hash{consolidationTxHash})
 		return len(txFromMempool) == 1
-	}, eventuallyWaitTimeOut, eventuallyPollTime)
+	}, e2e.EventuallyWaitTimeOut, e2e.EventuallyPollTime)
 
 	// report block to babylon
-	mBlock = tm.mineBlock(t)
+	mBlock = tm.MineBlock(t)
 	_, err = tm.BabylonClient.InsertBtcBlockHeaders([]*wire.BlockHeader{&mBlock.Header})
 	require.NoError(t, err)
 
@@ -1254,6 +1274,7 @@ func TestStakeExpansionWithConsolidation(t *testing.T) {
 		fpKeys,
 		int64(stakingTime),
 		originalTxHash.String(),
+		"",
 	)
 	require.NoError(t, err)
 
@@ -1261,7 +1282,7 @@ func TestStakeExpansionWithConsolidation(t *testing.T) {
 	require.NoError(t, err)
 
 	// Step 4: Verify new BTC delegation is pending
-	tm.waitForStakingTxState(t, expansionTxHash, staker.BabylonPendingStatus)
+	tm.WaitForStakingTxState(t, expansionTxHash, staker.BabylonPendingStatus)
 
 	pendingDel, err := tm.BabylonClient.QueryPendingBTCDelegations()
 	require.NoError(t, err)
@@ -1269,8 +1290,8 @@ func TestStakeExpansionWithConsolidation(t *testing.T) {
 	require.NotNil(t, pendingDel[0].StkExp)
 
 	// Step 5: Covenant signatures for expansion
-	tm.insertCovenantSigForDelegation(t, pendingDel[0])
-	tm.waitForStakingTxState(t, expansionTxHash, staker.BabylonVerifiedStatus)
+	tm.InsertCovenantSigForDelegation(t, pendingDel[0])
+	tm.WaitForStakingTxState(t, expansionTxHash, staker.BabylonVerifiedStatus)
 
 	// stake expansion delegation should be in verified state
 	verifiedDel, err := tm.BabylonClient.QueryVerifiedBTCDelegations()
@@ -1280,23 +1301,23 @@ func TestStakeExpansionWithConsolidation(t *testing.T) {
 
 	// Step 6: Wait for expansion transaction to be submitted to Bitcoin mempool
 	require.Eventually(t, func() bool {
-		txFromMempool := retrieveTransactionFromMempool(t, tm.TestRpcBtcClient, []*chainhash.Hash{expansionTxHash})
+		txFromMempool := e2e.RetrieveTransactionFromMempool(t, tm.TestRpcBtcClient, []*chainhash.Hash{expansionTxHash})
 		return len(txFromMempool) == 1
-	}, eventuallyWaitTimeOut, eventuallyPollTime)
+	}, e2e.EventuallyWaitTimeOut, e2e.EventuallyPollTime)
 
 	// Step 7: Mine the expansion transaction
-	expansionBlock := tm.mineBlock(t)
+	expansionBlock := tm.MineBlock(t)
 	require.Equal(t, 2, len(expansionBlock.Transactions))
 
 	expansionHeaderBytes := bbntypes.NewBTCHeaderBytesFromBlockHeader(&expansionBlock.Header)
-	expansionTxInclProof, err := btcctypes.SpvProofFromHeaderAndTransactions(&expansionHeaderBytes, txsToBytes(expansionBlock.Transactions), 1)
+	expansionTxInclProof, err := btcctypes.SpvProofFromHeaderAndTransactions(&expansionHeaderBytes, e2e.TxsToBytes(expansionBlock.Transactions), 1)
 	require.NoError(t, err)
 
 	_, err = tm.BabylonClient.InsertBtcBlockHeaders([]*wire.BlockHeader{&expansionBlock.Header})
 	require.NoError(t, err)
 
 	// Step 8: Wait for the expansion transaction to be k-deep on Bitcoin
-	tm.mineNEmptyBlocks(t, staker.UnbondingTxConfirmations, true)
+	tm.MineNEmptyBlocks(t, staker.UnbondingTxConfirmations, true)
 	require.Eventually(t, func() bool {
 		// Get transaction details and verify confirmations
 		res, err := tm.Sa.Wallet().TxVerbose(expansionTxHash)
@@ -1305,7 +1326,7 @@ func TestStakeExpansionWithConsolidation(t *testing.T) {
 		}
 		// Check if we have the required number of confirmations
 		return res.Confirmations >= staker.UnbondingTxConfirmations
-	}, eventuallyWaitTimeOut, eventuallyPollTime)
+	}, e2e.EventuallyWaitTimeOut, e2e.EventuallyPollTime)
 
 	// Step 9: Report expansion transaction via MsgBTCUndelegate for the original delegation
 	rawStkExpTransaction, err := tm.TestRpcBtcClient.GetRawTransaction(expansionTxHash)
@@ -1335,7 +1356,7 @@ func TestStakeExpansionWithConsolidation(t *testing.T) {
 	// Step 10: Wait for expansion to be active
 	// Verify the original delegation is no longer active
 	// and the expansion delegation is active
-	tm.waitForStakingTxState(t, expansionTxHash, staker.BabylonActiveStatus)
+	tm.WaitForStakingTxState(t, expansionTxHash, staker.BabylonActiveStatus)
 
 	originalDelegation, err := tm.BabylonClient.QueryBTCDelegation(originalTxHash)
 	require.NoError(t, err)
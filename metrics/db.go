@@ -0,0 +1,80 @@
+package metrics
+
+import (
+	"github.com/babylonlabs-io/btc-staker/stakerdb/boltdb"
+	"github.com/btcsuite/btcwallet/walletdb"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// dbStatsCollector reads database stats on every scrape, so that they are
+// never stale
+type dbStatsCollector struct {
+	db walletdb.DB
+
+	fileSize      *prometheus.Desc
+	pageSize      *prometheus.Desc
+	freePages     *prometheus.Desc
+	pendingPages  *prometheus.Desc
+	freeAlloc     *prometheus.Desc
+	freelistInuse *prometheus.Desc
+	openReadTxs   *prometheus.Desc
+}
+
+func newDBStatsCollector(db walletdb.DB) *dbStatsCollector {
+	return &dbStatsCollector{
+		db: db,
+		fileSize: prometheus.NewDesc(
+			"staker_db_file_size_bytes", "Size of the database file", nil, nil,
+		),
+		pageSize: prometheus.NewDesc(
+			"staker_db_page_size_bytes", "Size of the database page", nil, nil,
+		),
+		freePages: prometheus.NewDesc(
+			"staker_db_free_pages", "Number of free pages on the database freelist", nil, nil,
+		),
+		pendingPages: prometheus.NewDesc(
+			"staker_db_pending_pages", "Number of pages freed by transactions still used by readers", nil, nil,
+		),
+		freeAlloc: prometheus.NewDesc(
+			"staker_db_free_alloc_bytes", "Total bytes allocated in free database pages", nil, nil,
+		),
+		freelistInuse: prometheus.NewDesc(
+			"staker_db_freelist_inuse_bytes", "Total bytes used by the database freelist", nil, nil,
+		),
+		openReadTxs: prometheus.NewDesc(
+			"staker_db_open_read_transactions", "Number of currently open database read transactions", nil, nil,
+		),
+	}
+}
+
+func (c *dbStatsCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.fileSize
+	ch <- c.pageSize
+	ch <- c.freePages
+	ch <- c.pendingPages
+	ch <- c.freeAlloc
+	ch <- c.freelistInuse
+	ch <- c.openReadTxs
+}
+
+func (c *dbStatsCollector) Collect(ch chan<- prometheus.Metric) {
+	stats, err := boltdb.GetStats(c.db)
+	if err != nil {
+		ch <- prometheus.NewInvalidMetric(c.fileSize, err)
+		return
+	}
+
+	ch <- prometheus.MustNewConstMetric(c.fileSize, prometheus.GaugeValue, float64(stats.FileSize))
+	ch <- prometheus.MustNewConstMetric(c.pageSize, prometheus.GaugeValue, float64(stats.PageSize))
+	ch <- prometheus.MustNewConstMetric(c.freePages, prometheus.GaugeValue, float64(stats.FreePages))
+	ch <- prometheus.MustNewConstMetric(c.pendingPages, prometheus.GaugeValue, float64(stats.PendingPages))
+	ch <- prometheus.MustNewConstMetric(c.freeAlloc, prometheus.GaugeValue, float64(stats.FreeAlloc))
+	ch <- prometheus.MustNewConstMetric(c.freelistInuse, prometheus.GaugeValue, float64(stats.FreelistInuse))
+	ch <- prometheus.MustNewConstMetric(c.openReadTxs, prometheus.GaugeValue, float64(stats.OpenReadTxs))
+}
+
+// RegisterDBStats exports size and page stats of the database. Database must
+// be opened by stakercfg.GetDBBackend.
+func (m *StakerMetrics) RegisterDBStats(db walletdb.DB) error {
+	return m.Registry.Register(newDBStatsCollector(db))
+}
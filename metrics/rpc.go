@@ -0,0 +1,59 @@
+package metrics
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// RPCMetrics instrument the JSON-RPC server itself, separately from staker
+// business metrics. All metrics are labeled by the rpc method.
+type RPCMetrics struct {
+	Requests *prometheus.CounterVec
+	Errors   *prometheus.CounterVec
+	Latency  *prometheus.HistogramVec
+}
+
+func newRPCMetrics(registerer promauto.Factory) *RPCMetrics {
+	return &RPCMetrics{
+		Requests: registerer.NewCounterVec(prometheus.CounterOpts{
+			Name: "staker_rpc_requests_total",
+			Help: "Total number of JSON-RPC requests by method",
+		}, []string{"method"}),
+		Errors: registerer.NewCounterVec(prometheus.CounterOpts{
+			Name: "staker_rpc_errors_total",
+			Help: "Total number of JSON-RPC requests by method which returned an error, including invalid params",
+		}, []string{"method"}),
+		Latency: registerer.NewHistogramVec(prometheus.HistogramOpts{
+			Name: "staker_rpc_request_duration_seconds",
+			Help: "Time spent handling JSON-RPC requests by method",
+			// requests range from cache reads to stakes waiting for btc and babylon nodes
+			Buckets: prometheus.ExponentialBuckets(0.005, 2, 14),
+		}, []string{"method"}),
+	}
+}
+
+// Observe records handled request. It is a no-op on nil metrics.
+func (m *RPCMetrics) Observe(method string, duration time.Duration, failed bool) {
+	if m == nil {
+		return
+	}
+
+	m.Requests.WithLabelValues(method).Inc()
+	m.Latency.WithLabelValues(method).Observe(duration.Seconds())
+	if failed {
+		m.Errors.WithLabelValues(method).Inc()
+	}
+}
+
+// ObserveRejected records request rejected before it was handled, e.g. with
+// invalid params. Rejected requests are not part of latency.
+func (m *RPCMetrics) ObserveRejected(method string) {
+	if m == nil {
+		return
+	}
+
+	m.Requests.WithLabelValues(method).Inc()
+	m.Errors.WithLabelValues(method).Inc()
+}
@@ -1,6 +1,8 @@
 package metrics
 
 import (
+	"time"
+
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
 )
@@ -12,7 +14,29 @@ type StakerMetrics struct {
 	DelegationsSentToBabylon        prometheus.Counter
 	DelegationsActivatedOnBabylon   prometheus.Counter
 	NumberOfFatalErrors             prometheus.Counter
+	DelegationsDoubleSpent          prometheus.Counter
+	TransactionsEvictedFromMempool  prometheus.Counter
 	CurrentBtcBlockHeight           prometheus.Gauge
+	QueuedRequests                  prometheus.Gauge
+	InFlightRequests                prometheus.Gauge
+	RejectedRequests                prometheus.Counter
+	CovenantQuorumLatency           prometheus.Summary
+	ActivationLatency               prometheus.Summary
+	ActivationPhaseLatency          *prometheus.SummaryVec
+	DelegationsAwaitingCovenants    prometheus.Gauge
+	StuckDelegations                prometheus.Gauge
+	RemediationAttempts             *prometheus.CounterVec
+	ChainSafetyPaused               prometheus.Gauge
+	StartupSyncTotal                prometheus.Gauge
+	StartupSyncReconciled           prometheus.Gauge
+	WorkerPanics                    *prometheus.CounterVec
+	EventLogEvents                  prometheus.Gauge
+	EventLogSizeBytes               prometheus.Gauge
+	EventLogOldestEventAge          prometheus.Gauge
+	EventLogPrunedEvents            prometheus.Counter
+	WebhookPendingEvents            prometheus.Gauge
+	// RPC instruments the JSON-RPC server
+	RPC *RPCMetrics
 }
 
 func NewStakerMetrics() *StakerMetrics {
@@ -41,10 +65,97 @@ func NewStakerMetrics() *StakerMetrics {
 			Name: "staker_number_of_fatal_errors",
 			Help: "Total number of fatal errors received",
 		}),
+		DelegationsDoubleSpent: registerer.NewCounter(prometheus.CounterOpts{
+			Name: "staker_delegations_double_spent",
+			Help: "Total number of delegations whose staking transaction inputs were spent by another transaction",
+		}),
+		TransactionsEvictedFromMempool: registerer.NewCounter(prometheus.CounterOpts{
+			Name: "staker_transactions_evicted_from_mempool",
+			Help: "Total number of transactions broadcast by staker which disappeared from btc node mempool before confirmation",
+		}),
 		CurrentBtcBlockHeight: registerer.NewGauge(prometheus.GaugeOpts{
 			Name: "staker_current_btc_block_height",
 			Help: "Current block height of the btc chain",
 		}),
+		QueuedRequests: registerer.NewGauge(prometheus.GaugeOpts{
+			Name: "staker_queued_requests",
+			Help: "Number of stake, unbond and spend requests waiting for a free worker",
+		}),
+		InFlightRequests: registerer.NewGauge(prometheus.GaugeOpts{
+			Name: "staker_in_flight_requests",
+			Help: "Number of stake, unbond and spend requests being processed",
+		}),
+		RejectedRequests: registerer.NewCounter(prometheus.CounterOpts{
+			Name: "staker_rejected_requests",
+			Help: "Total number of stake, unbond and spend requests rejected because request queue was full",
+		}),
+		CovenantQuorumLatency: registerer.NewSummary(prometheus.SummaryOpts{
+			Name:       "staker_covenant_quorum_latency_seconds",
+			Help:       "Time between registration of delegation on babylon and quorum of covenant signatures",
+			Objectives: map[float64]float64{0.5: 0.05, 0.9: 0.01, 0.99: 0.001},
+			MaxAge:     24 * time.Hour,
+		}),
+		ActivationLatency: registerer.NewSummary(prometheus.SummaryOpts{
+			Name:       "staker_activation_latency_seconds",
+			Help:       "Time between the first observed phase of delegation activation and activation on babylon",
+			Objectives: map[float64]float64{0.5: 0.05, 0.9: 0.01, 0.99: 0.001},
+			MaxAge:     24 * time.Hour,
+		}),
+		ActivationPhaseLatency: registerer.NewSummaryVec(prometheus.SummaryOpts{
+			Name:       "staker_activation_phase_latency_seconds",
+			Help:       "Time between consecutive observed phases of delegation activation, e.g. registered_to_covenant_quorum",
+			Objectives: map[float64]float64{0.5: 0.05, 0.9: 0.01, 0.99: 0.001},
+			MaxAge:     24 * time.Hour,
+		}, []string{"transition"}),
+		DelegationsAwaitingCovenants: registerer.NewGauge(prometheus.GaugeOpts{
+			Name: "staker_delegations_awaiting_covenant_quorum",
+			Help: "Number of delegations registered on babylon which do not have quorum of covenant signatures yet",
+		}),
+		StuckDelegations: registerer.NewGauge(prometheus.GaugeOpts{
+			Name: "staker_stuck_delegations",
+			Help: "Number of delegations stuck in intermediate states for longer than configured thresholds",
+		}),
+		RemediationAttempts: registerer.NewCounterVec(prometheus.CounterOpts{
+			Name: "staker_remediation_attempts",
+			Help: "Number of automatic remediation attempts of stuck delegations and rejected delegations, by condition and result",
+		}, []string{"condition", "result"}),
+		ChainSafetyPaused: registerer.NewGauge(prometheus.GaugeOpts{
+			Name: "staker_chain_safety_paused",
+			Help: "1 if params sensitive operations are paused because Babylon is halted or its btc light client diverges from btc chain, 0 otherwise",
+		}),
+		StartupSyncTotal: registerer.NewGauge(prometheus.GaugeOpts{
+			Name: "staker_startup_sync_delegations_total",
+			Help: "Number of delegations to reconcile during startup",
+		}),
+		StartupSyncReconciled: registerer.NewGauge(prometheus.GaugeOpts{
+			Name: "staker_startup_sync_delegations_reconciled",
+			Help: "Number of delegations already reconciled during startup",
+		}),
+		WorkerPanics: registerer.NewCounterVec(prometheus.CounterOpts{
+			Name: "staker_worker_panics_total",
+			Help: "Number of recovered panics of background workers, by worker",
+		}, []string{"worker"}),
+		EventLogEvents: registerer.NewGauge(prometheus.GaugeOpts{
+			Name: "staker_event_log_events",
+			Help: "Number of events kept in the persisted event log",
+		}),
+		EventLogSizeBytes: registerer.NewGauge(prometheus.GaugeOpts{
+			Name: "staker_event_log_size_bytes",
+			Help: "Total size of keys and values of events kept in the persisted event log",
+		}),
+		EventLogOldestEventAge: registerer.NewGauge(prometheus.GaugeOpts{
+			Name: "staker_event_log_oldest_event_age_seconds",
+			Help: "Age of the oldest event kept in the persisted event log",
+		}),
+		EventLogPrunedEvents: registerer.NewCounter(prometheus.CounterOpts{
+			Name: "staker_event_log_pruned_events_total",
+			Help: "Total number of events removed from the persisted event log by retention",
+		}),
+		WebhookPendingEvents: registerer.NewGauge(prometheus.GaugeOpts{
+			Name: "staker_webhook_pending_events",
+			Help: "Number of events not delivered to the webhook yet, they are kept regardless of event log retention",
+		}),
+		RPC: newRPCMetrics(registerer),
 	}
 	return metrics
 }
@@ -0,0 +1,81 @@
+package secrets
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/kms"
+)
+
+// AWSKMSConfig is configuration of AWS KMS provider. Credentials are taken
+// from the default AWS credential chain.
+type AWSKMSConfig struct {
+	// Region of the KMS key, taken from AWS config if empty
+	Region string
+	// Profile of AWS shared config, default profile is used if empty
+	Profile string
+	// Endpoint overrides KMS endpoint, e.g. for vpc endpoints
+	Endpoint string
+	// KeyID of the key used to encrypt secrets. Required for asymmetric keys,
+	// optional for symmetric ones.
+	KeyID string
+}
+
+// AWSKMSProvider decrypts secrets encrypted with AWS KMS. Reference is base64
+// encoded ciphertext, as returned by `aws kms encrypt`.
+type AWSKMSProvider struct {
+	client *kms.KMS
+	keyID  string
+}
+
+var _ Provider = (*AWSKMSProvider)(nil)
+
+// NewAWSKMSProvider returns AWS KMS provider for the given config
+func NewAWSKMSProvider(cfg *AWSKMSConfig) (*AWSKMSProvider, error) {
+	awsCfg := aws.NewConfig()
+	if cfg.Region != "" {
+		awsCfg = awsCfg.WithRegion(cfg.Region)
+	}
+	if cfg.Endpoint != "" {
+		awsCfg = awsCfg.WithEndpoint(cfg.Endpoint)
+	}
+
+	sess, err := session.NewSessionWithOptions(session.Options{
+		Config:            *awsCfg,
+		Profile:           cfg.Profile,
+		SharedConfigState: session.SharedConfigEnable,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create aws session: %w", err)
+	}
+
+	return &AWSKMSProvider{
+		client: kms.New(sess),
+		keyID:  cfg.KeyID,
+	}, nil
+}
+
+// Fetch decrypts base64 encoded ciphertext
+func (p *AWSKMSProvider) Fetch(ctx context.Context, ref string) (string, error) {
+	ciphertext, err := base64.StdEncoding.DecodeString(ref)
+	if err != nil {
+		return "", fmt.Errorf("invalid kms ciphertext: %w", err)
+	}
+
+	input := &kms.DecryptInput{
+		CiphertextBlob: ciphertext,
+	}
+	if p.keyID != "" {
+		input.KeyId = aws.String(p.keyID)
+	}
+
+	out, err := p.client.DecryptWithContext(ctx, input)
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt secret: %w", err)
+	}
+
+	return string(out.Plaintext), nil
+}
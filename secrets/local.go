@@ -0,0 +1,38 @@
+package secrets
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// EnvProvider reads secrets from environment variables
+type EnvProvider struct{}
+
+var _ Provider = EnvProvider{}
+
+// Fetch returns value of the environment variable named ref
+func (EnvProvider) Fetch(_ context.Context, ref string) (string, error) {
+	value, ok := os.LookupEnv(ref)
+	if !ok {
+		return "", fmt.Errorf("environment variable %s is not set", ref)
+	}
+
+	return value, nil
+}
+
+// FileProvider reads secrets from files, e.g. mounted by container runtime
+type FileProvider struct{}
+
+var _ Provider = FileProvider{}
+
+// Fetch returns content of the file at path ref without trailing newline
+func (FileProvider) Fetch(_ context.Context, ref string) (string, error) {
+	content, err := os.ReadFile(ref)
+	if err != nil {
+		return "", fmt.Errorf("failed to read secret file: %w", err)
+	}
+
+	return strings.TrimRight(string(content), "\r\n"), nil
+}
@@ -0,0 +1,139 @@
+// Package secrets resolves secret references found in configuration, so that
+// production configs do not have to contain plaintext secrets.
+//
+// Reference has form <scheme>://<ref>, e.g. env://BITCOIND_RPC_PASS or
+// vault://secret/data/btcstaker#rpcpass. Values which do not start with
+// scheme of any registered provider are returned as they are.
+package secrets
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+const (
+	// EnvScheme references environment variable
+	EnvScheme = "env"
+	// FileScheme references file, trailing newline is trimmed
+	FileScheme = "file"
+	// VaultScheme references key of HashiCorp Vault kv secret
+	VaultScheme = "vault"
+	// AWSKMSScheme references base64 encoded ciphertext decrypted by AWS KMS
+	AWSKMSScheme = "awskms"
+)
+
+const schemeSeparator = "://"
+
+// ErrProviderNotConfigured is returned when value references secret of
+// provider which was not configured
+var ErrProviderNotConfigured = errors.New("secrets provider not configured")
+
+// Provider fetches secrets of a single scheme
+type Provider interface {
+	// Fetch returns secret for the reference, without the scheme prefix
+	Fetch(ctx context.Context, ref string) (string, error)
+}
+
+// ParseRef splits secret reference into scheme and reference. Returns false
+// if value does not look like a reference.
+func ParseRef(value string) (string, string, bool) {
+	scheme, ref, found := strings.Cut(value, schemeSeparator)
+	if !found || scheme == "" {
+		return "", "", false
+	}
+
+	return scheme, ref, true
+}
+
+// Resolver resolves secret references using registered providers. Resolved
+// secrets are cached, so every secret is fetched at most once.
+type Resolver struct {
+	mu        sync.Mutex
+	providers map[string]Provider
+	cache     map[string]string
+}
+
+// NewResolver returns resolver with env and file providers registered
+func NewResolver() *Resolver {
+	r := &Resolver{
+		providers: make(map[string]Provider),
+		cache:     make(map[string]string),
+	}
+
+	r.Register(EnvScheme, EnvProvider{})
+	r.Register(FileScheme, FileProvider{})
+
+	return r
+}
+
+// Register makes provider available for references with given scheme
+func (r *Resolver) Register(scheme string, provider Provider) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.providers[scheme] = provider
+}
+
+// IsRef returns true if value references secret of a known scheme
+func (r *Resolver) IsRef(value string) bool {
+	scheme, _, ok := ParseRef(value)
+	if !ok {
+		return false
+	}
+
+	return isKnownScheme(scheme)
+}
+
+// Resolve returns secret referenced by value. Values which are not references
+// are returned unchanged.
+func (r *Resolver) Resolve(ctx context.Context, value string) (string, error) {
+	scheme, ref, ok := ParseRef(value)
+	if !ok || !isKnownScheme(scheme) {
+		return value, nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if secret, ok := r.cache[value]; ok {
+		return secret, nil
+	}
+
+	provider, ok := r.providers[scheme]
+	if !ok {
+		return "", fmt.Errorf("%w: %s", ErrProviderNotConfigured, scheme)
+	}
+
+	secret, err := provider.Fetch(ctx, ref)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch %s secret: %w", scheme, err)
+	}
+
+	r.cache[value] = secret
+	return secret, nil
+}
+
+// ResolveAll resolves every value in place
+func (r *Resolver) ResolveAll(ctx context.Context, values ...*string) error {
+	for _, v := range values {
+		secret, err := r.Resolve(ctx, *v)
+		if err != nil {
+			return err
+		}
+		*v = secret
+	}
+
+	return nil
+}
+
+func isKnownScheme(scheme string) bool {
+	switch scheme {
+	case EnvScheme, FileScheme, VaultScheme, AWSKMSScheme:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,97 @@
+package secrets_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/babylonlabs-io/btc-staker/secrets"
+)
+
+func TestResolveLocalSecrets(t *testing.T) {
+	t.Setenv("BTCSTAKER_TEST_SECRET", "from-env")
+
+	secretFile := filepath.Join(t.TempDir(), "secret")
+	require.NoError(t, os.WriteFile(secretFile, []byte("from-file\n"), 0600))
+
+	r := secrets.NewResolver()
+	ctx := context.Background()
+
+	value, err := r.Resolve(ctx, "env://BTCSTAKER_TEST_SECRET")
+	require.NoError(t, err)
+	require.Equal(t, "from-env", value)
+
+	value, err = r.Resolve(ctx, "file://"+secretFile)
+	require.NoError(t, err)
+	require.Equal(t, "from-file", value)
+
+	// plaintext values and unknown schemes are kept as they are
+	value, err = r.Resolve(ctx, "plaintext")
+	require.NoError(t, err)
+	require.Equal(t, "plaintext", value)
+
+	value, err = r.Resolve(ctx, "https://example.com")
+	require.NoError(t, err)
+	require.Equal(t, "https://example.com", value)
+
+	_, err = r.Resolve(ctx, "env://BTCSTAKER_TEST_SECRET_NOT_SET")
+	require.Error(t, err)
+
+	_, err = r.Resolve(ctx, "vault://secret/data/btcstaker#rpcpass")
+	require.ErrorIs(t, err, secrets.ErrProviderNotConfigured)
+}
+
+func TestVaultProvider(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "token" {
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`{"errors":["permission denied"]}`))
+			return
+		}
+
+		switch r.URL.Path {
+		case "/v1/secret/data/btcstaker":
+			_, _ = w.Write([]byte(`{"data":{"data":{"rpcpass":"v2-pass"},"metadata":{"version":1}}}`))
+		case "/v1/kv/btcstaker":
+			_, _ = w.Write([]byte(`{"data":{"rpcpass":"v1-pass"}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"errors":[]}`))
+		}
+	}))
+	defer server.Close()
+
+	vault, err := secrets.NewVaultProvider(&secrets.VaultConfig{
+		Address: server.URL,
+		Token:   "token",
+		Timeout: time.Second,
+	})
+	require.NoError(t, err)
+
+	r := secrets.NewResolver()
+	r.Register(secrets.VaultScheme, vault)
+	ctx := context.Background()
+
+	value, err := r.Resolve(ctx, "vault://secret/data/btcstaker#rpcpass")
+	require.NoError(t, err)
+	require.Equal(t, "v2-pass", value)
+
+	value, err = r.Resolve(ctx, "vault://kv/btcstaker#rpcpass")
+	require.NoError(t, err)
+	require.Equal(t, "v1-pass", value)
+
+	_, err = r.Resolve(ctx, "vault://kv/btcstaker#missing")
+	require.Error(t, err)
+
+	_, err = r.Resolve(ctx, "vault://kv/unknown#rpcpass")
+	require.Error(t, err)
+
+	_, err = r.Resolve(ctx, "vault://kv/btcstaker")
+	require.Error(t, err)
+}
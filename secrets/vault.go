@@ -0,0 +1,152 @@
+package secrets
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+// VaultConfig is configuration of HashiCorp Vault provider
+type VaultConfig struct {
+	// Address of vault server, e.g. https://vault.example.com:8200
+	Address string
+	// Token used to authenticate to vault
+	Token string
+	// Namespace of vault enterprise, empty for root namespace
+	Namespace string
+	// CACert is path to PEM encoded CA certificate of vault server
+	CACert string
+	// Timeout of a single request
+	Timeout time.Duration
+}
+
+// VaultProvider reads secrets from kv secrets engine of HashiCorp Vault.
+// Reference has form <path>#<key>, where path is full api path of the secret
+// without /v1 prefix, e.g. secret/data/btcstaker#rpcpass for kv v2 engine
+// mounted at secret/. Both kv v1 and kv v2 engines are supported.
+type VaultProvider struct {
+	address   *url.URL
+	token     string
+	namespace string
+	client    *http.Client
+}
+
+var _ Provider = (*VaultProvider)(nil)
+
+// NewVaultProvider returns vault provider for the given config
+func NewVaultProvider(cfg *VaultConfig) (*VaultProvider, error) {
+	if cfg.Address == "" {
+		return nil, fmt.Errorf("vault address must be set")
+	}
+
+	address, err := url.Parse(cfg.Address)
+	if err != nil {
+		return nil, fmt.Errorf("invalid vault address: %w", err)
+	}
+
+	if cfg.Token == "" {
+		return nil, fmt.Errorf("vault token must be set")
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if cfg.CACert != "" {
+		pem, err := os.ReadFile(cfg.CACert)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read vault ca certificate: %w", err)
+		}
+
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in %s", cfg.CACert)
+		}
+
+		transport.TLSClientConfig = &tls.Config{
+			RootCAs:    pool,
+			MinVersion: tls.VersionTLS12,
+		}
+	}
+
+	return &VaultProvider{
+		address:   address,
+		token:     cfg.Token,
+		namespace: cfg.Namespace,
+		client: &http.Client{
+			Transport: transport,
+			Timeout:   cfg.Timeout,
+		},
+	}, nil
+}
+
+// vaultSecretResponse is response of vault read secret api
+type vaultSecretResponse struct {
+	Data   map[string]interface{} `json:"data"`
+	Errors []string               `json:"errors"`
+}
+
+// Fetch returns value of the key of vault secret
+func (p *VaultProvider) Fetch(ctx context.Context, ref string) (string, error) {
+	path, key, found := strings.Cut(ref, "#")
+	if !found || path == "" || key == "" {
+		return "", fmt.Errorf("invalid vault reference %q, expected <path>#<key>", ref)
+	}
+
+	reqURL := p.address.JoinPath("v1", strings.TrimPrefix(path, "/"))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL.String(), nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("X-Vault-Token", p.token)
+	if p.namespace != "" {
+		req.Header.Set("X-Vault-Namespace", p.namespace)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to read vault secret %s: %w", path, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read vault response: %w", err)
+	}
+
+	var secret vaultSecretResponse
+	if err := json.Unmarshal(body, &secret); err != nil {
+		return "", fmt.Errorf("failed to decode vault response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("vault returned status %d for secret %s: %s",
+			resp.StatusCode, path, strings.Join(secret.Errors, "; "))
+	}
+
+	data := secret.Data
+	// kv v2 engine wraps secret data together with its metadata
+	if nested, ok := data["data"].(map[string]interface{}); ok {
+		if _, ok := data["metadata"]; ok {
+			data = nested
+		}
+	}
+
+	value, ok := data[key]
+	if !ok {
+		return "", fmt.Errorf("key %s not found in vault secret %s", key, path)
+	}
+
+	str, ok := value.(string)
+	if !ok {
+		return "", fmt.Errorf("key %s of vault secret %s is not a string", key, path)
+	}
+
+	return str, nil
+}
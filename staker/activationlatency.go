@@ -0,0 +1,320 @@
+package staker
+
+import (
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/babylonlabs-io/btc-staker/stakerdb"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/sirupsen/logrus"
+)
+
+// Names of activation phases, listed in the order used to break ties of
+// phases observed at the same time
+const (
+	PhaseBroadcast      = "broadcast"
+	PhaseConfirmed      = "confirmed"
+	PhaseRegistered     = "registered"
+	PhaseCovenantQuorum = "covenant_quorum"
+	PhaseActive         = "active"
+)
+
+var activationPhaseOrder = []string{
+	PhaseBroadcast,
+	PhaseConfirmed,
+	PhaseRegistered,
+	PhaseCovenantQuorum,
+	PhaseActive,
+}
+
+// ActivationPhaseTime is the time at which delegation reached activation phase
+type ActivationPhaseTime struct {
+	Phase string
+	At    time.Time
+}
+
+// ActivationPhaseLatency is the time delegation spent between two consecutive
+// observed activation phases
+type ActivationPhaseLatency struct {
+	From    string
+	To      string
+	Latency time.Duration
+}
+
+// Name returns name of the transition, e.g. registered_to_covenant_quorum
+func (l *ActivationPhaseLatency) Name() string {
+	return l.From + "_to_" + l.To
+}
+
+// ActivationLatency is the breakdown of delegation activation latency
+type ActivationLatency struct {
+	// Phases are observed phases in chronological order. Order depends on the
+	// staking flow, e.g. delegations staked with pre-approval are registered
+	// before staking transaction is broadcast.
+	Phases    []ActivationPhaseTime
+	Breakdown []ActivationPhaseLatency
+	// Total is time between the first observed phase and activation, zero if
+	// delegation is not active yet
+	Total time.Duration
+}
+
+// newActivationLatency orders observed phases of the timeline and computes
+// latencies between them
+func newActivationLatency(timeline *stakerdb.ActivationTimeline) *ActivationLatency {
+	phaseTimes := map[string]time.Time{
+		PhaseBroadcast:      timeline.BroadcastAt,
+		PhaseConfirmed:      timeline.ConfirmedAt,
+		PhaseRegistered:     timeline.RegisteredAt,
+		PhaseCovenantQuorum: timeline.CovenantQuorumAt,
+		PhaseActive:         timeline.ActiveAt,
+	}
+
+	latency := &ActivationLatency{}
+	for _, phase := range activationPhaseOrder {
+		if at := phaseTimes[phase]; !at.IsZero() {
+			latency.Phases = append(latency.Phases, ActivationPhaseTime{Phase: phase, At: at})
+		}
+	}
+
+	sort.SliceStable(latency.Phases, func(i, j int) bool {
+		return latency.Phases[i].At.Before(latency.Phases[j].At)
+	})
+
+	for i := 1; i < len(latency.Phases); i++ {
+		latency.Breakdown = append(latency.Breakdown, ActivationPhaseLatency{
+			From:    latency.Phases[i-1].Phase,
+			To:      latency.Phases[i].Phase,
+			Latency: latency.Phases[i].At.Sub(latency.Phases[i-1].At),
+		})
+	}
+
+	if !timeline.ActiveAt.IsZero() && len(latency.Phases) > 0 {
+		latency.Total = timeline.ActiveAt.Sub(latency.Phases[0].At)
+	}
+
+	return latency
+}
+
+// ActivationLatencyStats are statistics of latency of one activation
+// transition or of the whole activation across delegations
+type ActivationLatencyStats struct {
+	// Name is the transition name, or total for the whole activation
+	Name  string
+	Count int
+	Mean  time.Duration
+	P50   time.Duration
+	P90   time.Duration
+	Max   time.Duration
+}
+
+func newActivationLatencyStats(name string, latencies []time.Duration) ActivationLatencyStats {
+	slices.Sort(latencies)
+
+	var sum time.Duration
+	for _, l := range latencies {
+		sum += l
+	}
+
+	percentile := func(p int) time.Duration {
+		return latencies[(len(latencies)-1)*p/100]
+	}
+
+	return ActivationLatencyStats{
+		Name:  name,
+		Count: len(latencies),
+		Mean:  sum / time.Duration(len(latencies)),
+		P50:   percentile(50),
+		P90:   percentile(90),
+		Max:   latencies[len(latencies)-1],
+	}
+}
+
+// ActivationLatencySummary aggregates activation latencies of active
+// delegations
+type ActivationLatencySummary struct {
+	// Delegations is the number of active delegations with observed
+	// activation
+	Delegations int
+	Total       *ActivationLatencyStats
+	// Transitions are sorted by transition name
+	Transitions []ActivationLatencyStats
+}
+
+// DelegationActivationLatency returns activation latency breakdown of the
+// delegation, nil if none of its phases was observed
+func (app *App) DelegationActivationLatency(stakingTxHash *chainhash.Hash) (*ActivationLatency, error) {
+	if _, err := app.txTracker.GetTransaction(stakingTxHash); err != nil {
+		return nil, err
+	}
+
+	timeline, err := app.txTracker.GetActivationTimeline(stakingTxHash)
+	if err != nil {
+		return nil, err
+	}
+
+	if timeline == nil {
+		return nil, nil
+	}
+
+	return newActivationLatency(timeline), nil
+}
+
+// ActivationLatencySummary returns activation latency statistics of tracked
+// delegations which became active
+func (app *App) ActivationLatencySummary() (*ActivationLatencySummary, error) {
+	timelines, err := app.txTracker.ListActivationTimelines()
+	if err != nil {
+		return nil, err
+	}
+
+	var totals []time.Duration
+	transitions := make(map[string][]time.Duration)
+	for _, timeline := range timelines {
+		if timeline.ActiveAt.IsZero() {
+			continue
+		}
+
+		latency := newActivationLatency(timeline)
+		totals = append(totals, latency.Total)
+		for i := range latency.Breakdown {
+			name := latency.Breakdown[i].Name()
+			transitions[name] = append(transitions[name], latency.Breakdown[i].Latency)
+		}
+	}
+
+	summary := &ActivationLatencySummary{
+		Delegations: len(totals),
+	}
+
+	if len(totals) == 0 {
+		return summary, nil
+	}
+
+	total := newActivationLatencyStats("total", totals)
+	summary.Total = &total
+
+	for name, latencies := range transitions {
+		summary.Transitions = append(summary.Transitions, newActivationLatencyStats(name, latencies))
+	}
+	sort.Slice(summary.Transitions, func(i, j int) bool {
+		return summary.Transitions[i].Name < summary.Transitions[j].Name
+	})
+
+	return summary, nil
+}
+
+// recordActivationPhase stores time at which delegation reached activation
+// phase. Times are used only to measure latency, so failure is only logged.
+// Returns true if the time was stored now.
+func (app *App) recordActivationPhase(
+	stakingTxHash *chainhash.Hash,
+	phase stakerdb.ActivationPhase,
+	reachedAt time.Time,
+) bool {
+	stored, err := app.txTracker.SetActivationPhaseAt(stakingTxHash, phase, reachedAt)
+	if err != nil {
+		app.logger.WithFields(logrus.Fields{
+			"stakingTxHash": stakingTxHash,
+			"phase":         phase,
+			"err":           err,
+		}).Warn("Failed to record activation phase time")
+		return false
+	}
+
+	return stored
+}
+
+// recordDelegationActivated stores activation time of the delegation and
+// exports its activation latencies
+func (app *App) recordDelegationActivated(stakingTxHash *chainhash.Hash, activatedAt time.Time) {
+	if !app.recordActivationPhase(stakingTxHash, stakerdb.ActivationPhaseActive, activatedAt) {
+		return
+	}
+
+	app.observeActivationLatency(stakingTxHash)
+}
+
+// observeActivationLatency exports activation latency breakdown of just
+// activated delegation
+func (app *App) observeActivationLatency(stakingTxHash *chainhash.Hash) {
+	timeline, err := app.txTracker.GetActivationTimeline(stakingTxHash)
+	if err != nil || timeline == nil {
+		return
+	}
+
+	latency := newActivationLatency(timeline)
+	for i := range latency.Breakdown {
+		app.m.ActivationPhaseLatency.
+			WithLabelValues(latency.Breakdown[i].Name()).
+			Observe(latency.Breakdown[i].Latency.Seconds())
+	}
+
+	if len(latency.Phases) > 1 {
+		app.m.ActivationLatency.Observe(latency.Total.Seconds())
+	}
+
+	app.logger.WithFields(logrus.Fields{
+		"stakingTxHash": stakingTxHash,
+		"latency":       latency.Total,
+	}).Debug("Delegation activated")
+}
+
+// observeActivationPhases records times of btc confirmation and activation
+// observed by status refresh, which covers delegations whose activation is
+// not awaited by staker, e.g. registered phase-1 delegations. Latencies are
+// exported only if delegation was seen inactive by the previous refresh, as
+// otherwise it could have been activated any time while daemon was offline.
+// Confirmation depth is zero if it is not known.
+func (app *App) observeActivationPhases(
+	stakingTxHash *chainhash.Hash,
+	status, prev *DelegationStatus,
+	confirmationDepth uint32,
+) {
+	if prev != nil && prev.confirmedRecorded {
+		status.confirmedRecorded = true
+	}
+	if prev != nil && prev.activeRecorded {
+		status.activeRecorded = true
+	}
+
+	if !status.confirmedRecorded && confirmationDepth > 0 && status.ConfirmationHeight > 0 &&
+		app.currentBestBlockHeight.Load() >= status.ConfirmationHeight+confirmationDepth {
+		app.recordActivationPhase(stakingTxHash, stakerdb.ActivationPhaseConfirmed, status.RefreshedAt)
+		status.confirmedRecorded = true
+	}
+
+	if status.activeRecorded || status.State() != BabylonActiveStatus {
+		return
+	}
+
+	stored, err := app.txTracker.SetActivationPhaseAt(stakingTxHash, stakerdb.ActivationPhaseActive, status.RefreshedAt)
+	if err != nil {
+		// retried on the next refresh
+		app.logger.WithFields(logrus.Fields{
+			"stakingTxHash": stakingTxHash,
+			"err":           err,
+		}).Warn("Failed to record activation time")
+		return
+	}
+
+	status.activeRecorded = true
+
+	if stored && prev != nil && prev.State() != BabylonActiveStatus {
+		app.observeActivationLatency(stakingTxHash)
+	}
+}
+
+// activationConfirmationDepth returns number of confirmations staking
+// transaction needs to be accepted by Babylon, zero if it is not known
+func (app *App) activationConfirmationDepth() uint32 {
+	params, err := app.babylonClient.BTCCheckpointParams()
+	if err != nil {
+		app.logger.WithFields(logrus.Fields{
+			"err": err,
+		}).Debug("Failed to get btc checkpoint params, confirmation of staking transactions is not observed")
+		return 0
+	}
+
+	return params.ConfirmationTimeBlocks
+}
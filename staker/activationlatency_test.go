@@ -0,0 +1,63 @@
+package staker
+
+import (
+	"testing"
+	"time"
+
+	"github.com/babylonlabs-io/btc-staker/stakerdb"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewActivationLatency(t *testing.T) {
+	t.Parallel()
+
+	start := time.Unix(1_700_000_000, 0)
+
+	// pre-approval flow, registered before broadcast, confirmation observed at
+	// the same time as activation
+	latency := newActivationLatency(&stakerdb.ActivationTimeline{
+		RegisteredAt:     start,
+		CovenantQuorumAt: start.Add(10 * time.Minute),
+		BroadcastAt:      start.Add(11 * time.Minute),
+		ConfirmedAt:      start.Add(time.Hour),
+		ActiveAt:         start.Add(time.Hour),
+	})
+
+	var phases []string
+	for _, p := range latency.Phases {
+		phases = append(phases, p.Phase)
+	}
+	require.Equal(t, []string{PhaseRegistered, PhaseCovenantQuorum, PhaseBroadcast, PhaseConfirmed, PhaseActive}, phases)
+
+	require.Len(t, latency.Breakdown, 4)
+	require.Equal(t, "registered_to_covenant_quorum", latency.Breakdown[0].Name())
+	require.Equal(t, 10*time.Minute, latency.Breakdown[0].Latency)
+	require.Equal(t, "confirmed_to_active", latency.Breakdown[3].Name())
+	require.Zero(t, latency.Breakdown[3].Latency)
+	require.Equal(t, time.Hour, latency.Total)
+
+	// not active yet
+	latency = newActivationLatency(&stakerdb.ActivationTimeline{
+		RegisteredAt: start,
+	})
+	require.Len(t, latency.Phases, 1)
+	require.Empty(t, latency.Breakdown)
+	require.Zero(t, latency.Total)
+}
+
+func TestActivationLatencyStats(t *testing.T) {
+	t.Parallel()
+
+	var latencies []time.Duration
+	for i := 10; i > 0; i-- {
+		latencies = append(latencies, time.Duration(i)*time.Minute)
+	}
+
+	stats := newActivationLatencyStats("total", latencies)
+	require.Equal(t, "total", stats.Name)
+	require.Equal(t, 10, stats.Count)
+	require.Equal(t, 5*time.Minute+30*time.Second, stats.Mean)
+	require.Equal(t, 5*time.Minute, stats.P50)
+	require.Equal(t, 9*time.Minute, stats.P90)
+	require.Equal(t, 10*time.Minute, stats.Max)
+}
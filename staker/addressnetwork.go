@@ -0,0 +1,29 @@
+package staker
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/babylonlabs-io/btc-staker/stakerdb"
+	"github.com/babylonlabs-io/btc-staker/utils"
+	"github.com/btcsuite/btcd/btcutil"
+)
+
+// ErrStakerAddressNetwork is returned when staker address of tracked delegation
+// does not belong to the network staker runs on
+var ErrStakerAddressNetwork = errors.New("staker address of tracked transaction does not belong to active network")
+
+// decodeStakerAddress decodes staker address of tracked delegation and checks
+// it belongs to the active network. Delegation stored while staker ran on other
+// network must be migrated with `stakercli admin migrate-staker-addresses`
+// before its funds are moved, so they are never sent to address meant for other
+// network.
+func (app *App) decodeStakerAddress(storedTx *stakerdb.StoredTransaction) (btcutil.Address, error) {
+	stakerAddress, err := utils.DecodeAddressForNet(storedTx.StakerAddress, app.network)
+	if err != nil {
+		return nil, fmt.Errorf("%w: address %s, network %s: %w",
+			ErrStakerAddressNetwork, storedTx.StakerAddress, app.network.Name, err)
+	}
+
+	return stakerAddress, nil
+}
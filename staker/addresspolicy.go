@@ -0,0 +1,58 @@
+package staker
+
+import (
+	"fmt"
+
+	scfg "github.com/babylonlabs-io/btc-staker/stakercfg"
+	"github.com/babylonlabs-io/btc-staker/walletcontroller"
+	"github.com/btcsuite/btcd/btcutil"
+)
+
+// changeAddress returns address which should receive change of the staking
+// transaction funded by given staker address
+func (app *App) changeAddress(stakerAddress btcutil.Address) (btcutil.Address, error) {
+	addr, err := app.policyAddress(
+		app.config.StakerConfig.ChangeAddressType,
+		app.config.StakerConfig.ChangeAddress,
+		stakerAddress,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get change address: %w", err)
+	}
+
+	return addr, nil
+}
+
+// withdrawalAddress returns address which should receive funds withdrawn from
+// staking or unbonding output locked by given staker address
+func (app *App) withdrawalAddress(stakerAddress btcutil.Address) (btcutil.Address, error) {
+	addr, err := app.policyAddress(
+		app.config.StakerConfig.WithdrawalAddressType,
+		app.config.StakerConfig.WithdrawalAddress,
+		stakerAddress,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get withdrawal address: %w", err)
+	}
+
+	return addr, nil
+}
+
+func (app *App) policyAddress(
+	addressType string,
+	fixedAddress string,
+	stakerAddress btcutil.Address,
+) (btcutil.Address, error) {
+	switch addressType {
+	case scfg.AddressTypeStaker, "":
+		return stakerAddress, nil
+	case scfg.AddressTypeTaproot:
+		return app.wc.NewAddress(walletcontroller.AddressTypeTaproot)
+	case scfg.AddressTypeSegwit:
+		return app.wc.NewAddress(walletcontroller.AddressTypeSegwit)
+	case scfg.AddressTypeExternal:
+		return btcutil.DecodeAddress(fixedAddress, app.network)
+	default:
+		return nil, fmt.Errorf("unknown address type: %s", addressType)
+	}
+}
@@ -0,0 +1,63 @@
+package staker_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/babylonlabs-io/btc-staker/staker"
+	"github.com/babylonlabs-io/btc-staker/stakercfg"
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChangeAndWithdrawalAddressPolicy(t *testing.T) {
+	t.Parallel()
+
+	key, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+	coldAddr, err := btcutil.NewAddressTaproot(
+		schnorr.SerializePubKey(key.PubKey()), &chaincfg.RegressionNetParams,
+	)
+	require.NoError(t, err)
+
+	sim, app, addr := startSimulatedApp(t, func(cfg *stakercfg.Config) {
+		cfg.StakerConfig.ChangeAddressType = stakercfg.AddressTypeSegwit
+		cfg.StakerConfig.WithdrawalAddressType = stakercfg.AddressTypeExternal
+		cfg.StakerConfig.WithdrawalAddress = coldAddr.EncodeAddress()
+	})
+	stakerScript, err := txscript.PayToAddrScript(addr)
+	require.NoError(t, err)
+
+	stakingTxHash, confirmationHeight := activeDelegation(t, sim, app, addr)
+
+	// change goes to fresh segwit address of the wallet instead of the staker
+	// address
+	stakingTx, err := sim.Chain.Tx(stakingTxHash)
+	require.NoError(t, err)
+	stakingOutputIdx := stakingOutPoint(t, sim, stakingTxHash).Index
+	require.Len(t, stakingTx.TxOut, 2)
+	change := stakingTx.TxOut[1-stakingOutputIdx]
+	require.NotEqual(t, stakerScript, change.PkScript)
+	require.Equal(t, txscript.WitnessV0PubKeyHashTy, txscript.GetScriptClass(change.PkScript))
+
+	// withdrawal is signed by staker key, but sends funds to the cold address
+	mineToHeight(t, sim, confirmationHeight+withdrawableTestStakingTime-1)
+	require.Eventually(t, func() bool {
+		return isWithdrawable(t, app, stakingTxHash)
+	}, 10*time.Second, 50*time.Millisecond)
+
+	spendTxHash, _, err := app.SpendStake(stakingTxHash, staker.FeeSelection{})
+	require.NoError(t, err)
+	require.True(t, sim.Chain.InMempool(spendTxHash))
+
+	spendTx, err := sim.Chain.Tx(spendTxHash)
+	require.NoError(t, err)
+	coldScript, err := txscript.PayToAddrScript(coldAddr)
+	require.NoError(t, err)
+	require.Len(t, spendTx.TxOut, 1)
+	require.Equal(t, coldScript, spendTx.TxOut[0].PkScript)
+}
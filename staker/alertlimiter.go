@@ -0,0 +1,176 @@
+package staker
+
+import (
+	"fmt"
+	"time"
+
+	scfg "github.com/babylonlabs-io/btc-staker/stakercfg"
+	"github.com/babylonlabs-io/btc-staker/stakerdb"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+)
+
+// anyAlertKind selects rate limit of alert kinds without own limit
+const anyAlertKind = "*"
+
+// maxDedupEntries bounds number of remembered alerts, expired ones are removed
+// once it is reached
+const maxDedupEntries = 1024
+
+// alertKinds are changes which report state of staker and its dependencies
+// rather than lifecycle of delegations. They repeat whenever a dependency
+// flaps, so their delivery is deduplicated and rate limited.
+var alertKinds = map[string]stakerdb.ChangeKind{
+	stakerdb.ChangeStakingGateClosed.String(): stakerdb.ChangeStakingGateClosed,
+	stakerdb.ChangeStakingGateOpened.String(): stakerdb.ChangeStakingGateOpened,
+	stakerdb.ChangeDelegationStuck.String():   stakerdb.ChangeDelegationStuck,
+	stakerdb.ChangeDelegationUnstuck.String(): stakerdb.ChangeDelegationUnstuck,
+	stakerdb.ChangeRemediationFailed.String(): stakerdb.ChangeRemediationFailed,
+	stakerdb.ChangeOperationsPaused.String():  stakerdb.ChangeOperationsPaused,
+	stakerdb.ChangeOperationsResumed.String(): stakerdb.ChangeOperationsResumed,
+	stakerdb.ChangeWorkerPanicked.String():    stakerdb.ChangeWorkerPanicked,
+}
+
+type alertKey struct {
+	kind          stakerdb.ChangeKind
+	stakingTxHash chainhash.Hash
+	detail        string
+}
+
+type rateWindow struct {
+	start time.Time
+	count uint64
+}
+
+// alertLimiter decides which alert events are delivered. Identical alerts
+// within dedup window are delivered once and number of alerts of each kind
+// delivered per period is limited. Alerts are timed by time of the change, so
+// that backlog delivered after webhook outage is limited the same way. It is
+// used only by the webhook worker, so it is not synchronized.
+type alertLimiter struct {
+	dedupWindow time.Duration
+	limits      map[stakerdb.ChangeKind]scfg.AlertRateLimit
+	anyLimit    *scfg.AlertRateLimit
+
+	lastSent   map[alertKey]time.Time
+	suppressed map[alertKey]uint64
+	windows    map[stakerdb.ChangeKind]*rateWindow
+}
+
+func newAlertLimiter(cfg *scfg.WebhookConfig) (*alertLimiter, error) {
+	limits, err := cfg.AlertRateLimits()
+	if err != nil {
+		return nil, err
+	}
+
+	l := &alertLimiter{
+		dedupWindow: cfg.AlertDedupWindow,
+		limits:      make(map[stakerdb.ChangeKind]scfg.AlertRateLimit),
+		lastSent:    make(map[alertKey]time.Time),
+		suppressed:  make(map[alertKey]uint64),
+		windows:     make(map[stakerdb.ChangeKind]*rateWindow),
+	}
+
+	for name, limit := range limits {
+		if name == anyAlertKind {
+			l.anyLimit = &limit
+			continue
+		}
+
+		kind, ok := alertKinds[name]
+		if !ok {
+			return nil, fmt.Errorf("invalid alert rate limit: %s is not an alert event kind", name)
+		}
+		l.limits[kind] = limit
+	}
+
+	return l, nil
+}
+
+func isAlert(ch *stakerdb.Change) bool {
+	_, ok := alertKinds[ch.Kind.String()]
+	return ok
+}
+
+func newAlertKey(ch *stakerdb.Change) alertKey {
+	return alertKey{kind: ch.Kind, stakingTxHash: ch.StakingTxHash, detail: ch.Detail}
+}
+
+// allow returns true if the change should be delivered. Changes which are not
+// alerts are always delivered. Delivery is accounted by delivered, so that
+// alert whose delivery failed is allowed again on retry.
+func (l *alertLimiter) allow(ch *stakerdb.Change) bool {
+	if l == nil || !isAlert(ch) {
+		return true
+	}
+
+	key := newAlertKey(ch)
+	at := ch.Timestamp
+
+	if last, ok := l.lastSent[key]; ok && at.Sub(last) < l.dedupWindow {
+		l.suppressed[key]++
+		return false
+	}
+
+	if limit := l.limitOf(ch.Kind); limit != nil {
+		w, ok := l.windows[ch.Kind]
+		if !ok || at.Sub(w.start) >= limit.Period {
+			w = &rateWindow{start: at}
+			l.windows[ch.Kind] = w
+		}
+
+		if w.count >= limit.Count {
+			l.suppressed[key]++
+			return false
+		}
+	}
+
+	return true
+}
+
+// delivered accounts delivery of allowed change
+func (l *alertLimiter) delivered(ch *stakerdb.Change) {
+	if l == nil || !isAlert(ch) {
+		return
+	}
+
+	key := newAlertKey(ch)
+	at := ch.Timestamp
+
+	if w, ok := l.windows[ch.Kind]; ok {
+		w.count++
+	}
+
+	if len(l.lastSent) >= maxDedupEntries {
+		l.prune(at)
+	}
+
+	l.lastSent[key] = at
+	delete(l.suppressed, key)
+}
+
+// pendingSuppressed returns number of identical alerts suppressed since the
+// last delivered one
+func (l *alertLimiter) pendingSuppressed(ch *stakerdb.Change) uint64 {
+	if l == nil {
+		return 0
+	}
+
+	return l.suppressed[newAlertKey(ch)]
+}
+
+func (l *alertLimiter) limitOf(kind stakerdb.ChangeKind) *scfg.AlertRateLimit {
+	if limit, ok := l.limits[kind]; ok {
+		return &limit
+	}
+
+	return l.anyLimit
+}
+
+// prune removes alerts whose dedup window passed
+func (l *alertLimiter) prune(now time.Time) {
+	for key, last := range l.lastSent {
+		if now.Sub(last) >= l.dedupWindow {
+			delete(l.lastSent, key)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package staker
+
+import (
+	"testing"
+	"time"
+
+	scfg "github.com/babylonlabs-io/btc-staker/stakercfg"
+	"github.com/babylonlabs-io/btc-staker/stakerdb"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAlertLimiter(t *testing.T) {
+	t.Parallel()
+
+	start := time.Unix(1_700_000_000, 0)
+	change := func(kind stakerdb.ChangeKind, detail string, after time.Duration) *stakerdb.Change {
+		return &stakerdb.Change{Kind: kind, Detail: detail, Timestamp: start.Add(after)}
+	}
+
+	cfg := scfg.DefaultWebhookConfig()
+	cfg.AlertDedupWindow = 10 * time.Minute
+	cfg.AlertRateLimit = []string{"*=2/1h", "operations_resumed=1/1h"}
+
+	l, err := newAlertLimiter(&cfg)
+	require.NoError(t, err)
+
+	// send delivers the change if it is allowed, returning number of
+	// suppressed alerts reported with it
+	send := func(ch *stakerdb.Change) (bool, uint64) {
+		if !l.allow(ch) {
+			return false, 0
+		}
+		suppressed := l.pendingSuppressed(ch)
+		l.delivered(ch)
+		return true, suppressed
+	}
+
+	tests := []struct {
+		name       string
+		change     *stakerdb.Change
+		allowed    bool
+		suppressed uint64
+	}{
+		{"first alert", change(stakerdb.ChangeOperationsPaused, "babylon halted", 0), true, 0},
+		{"duplicate within window", change(stakerdb.ChangeOperationsPaused, "babylon halted", time.Minute), false, 0},
+		{"other detail", change(stakerdb.ChangeOperationsPaused, "btc diverged", 2*time.Minute), true, 0},
+		{"rate limit of kind", change(stakerdb.ChangeOperationsPaused, "light client lagging", 3*time.Minute), false, 0},
+		{"own rate limit", change(stakerdb.ChangeOperationsResumed, "", 4*time.Minute), true, 0},
+		{"own rate limit reached", change(stakerdb.ChangeOperationsResumed, "x", 5*time.Minute), false, 0},
+		{"lifecycle events are not limited", change(stakerdb.ChangeTransactionAdded, "", 5*time.Minute), true, 0},
+		{"lifecycle duplicates are not limited", change(stakerdb.ChangeTransactionAdded, "", 5*time.Minute), true, 0},
+		// window of the kind and dedup window passed, suppressed duplicate
+		// is reported
+		{"after windows", change(stakerdb.ChangeOperationsPaused, "babylon halted", 2*time.Hour), true, 1},
+	}
+
+	for _, tc := range tests {
+		allowed, suppressed := send(tc.change)
+		require.Equal(t, tc.allowed, allowed, tc.name)
+		require.Equal(t, tc.suppressed, suppressed, tc.name)
+	}
+}
+
+func TestAlertLimiterRetry(t *testing.T) {
+	t.Parallel()
+
+	cfg := scfg.DefaultWebhookConfig()
+	l, err := newAlertLimiter(&cfg)
+	require.NoError(t, err)
+
+	ch := &stakerdb.Change{Kind: stakerdb.ChangeWorkerPanicked, Detail: "webhook: boom", Timestamp: time.Now()}
+
+	// alert whose delivery failed is allowed again
+	require.True(t, l.allow(ch))
+	require.True(t, l.allow(ch))
+	l.delivered(ch)
+	require.False(t, l.allow(ch))
+}
+
+func TestAlertLimiterInvalidKind(t *testing.T) {
+	t.Parallel()
+
+	cfg := scfg.DefaultWebhookConfig()
+	cfg.AlertRateLimit = []string{"transaction_added=1/1h"}
+
+	_, err := newAlertLimiter(&cfg)
+	require.Error(t, err)
+}
@@ -0,0 +1,434 @@
+package staker
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	cl "github.com/babylonlabs-io/btc-staker/babylonclient"
+	"github.com/babylonlabs-io/btc-staker/stakerdb"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/sirupsen/logrus"
+)
+
+// maxRenewalAttempts is number of attempts to renew expired delegation before
+// its renewal job fails
+const maxRenewalAttempts = 3
+
+// withdrawnRemediation is remediation of renewal which failed after funds were
+// withdrawn from the staking output
+const withdrawnRemediation = "funds were withdrawn to the staker address, stake them manually"
+
+// ErrDelegationUnbonded is returned when renewal is enabled for delegation
+// which was already unbonded
+var ErrDelegationUnbonded = errors.New("delegation is unbonded")
+
+// ExpiringDelegation is active delegation whose staking timelock expires soon
+type ExpiringDelegation struct {
+	StakingTxHash chainhash.Hash
+	State         string
+	// ExpiryHeight is btc height of the first block which can include
+	// withdrawal of the staking output
+	ExpiryHeight uint32
+	// BlocksLeft is zero if staking output can be withdrawn in the next block
+	BlocksLeft uint32
+	AutoRenew  bool
+	// RenewStakingTime is staking time of the renewed delegation, set only if
+	// auto renew is enabled
+	RenewStakingTime uint16
+}
+
+// stakingExpiryHeight returns btc height at which staking timelock of confirmed
+// delegation expires
+func stakingExpiryHeight(status *DelegationStatus) uint32 {
+	return status.ConfirmationHeight + status.Delegation.BtcDelegation.StakingTime
+}
+
+// ExpiringDelegations returns delegations whose staking output is not spent and
+// whose staking timelock expires within the given number of blocks. Delegations
+// are expected to reach natural expiry, so unbonded delegations are skipped.
+func (app *App) ExpiringDelegations(withinBlocks uint32) ([]ExpiringDelegation, error) {
+	query := stakerdb.DefaultStoredTransactionQuery()
+	query.NumMaxTransactions = math.MaxUint64
+	query.StakingTxHashOnly = true
+
+	result, err := app.txTracker.QueryStoredTransactions(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query stored transactions: %w", err)
+	}
+
+	renewals, err := app.txTracker.ListAutoRenew()
+	if err != nil {
+		return nil, err
+	}
+
+	nextBlockHeight := app.currentBestBlockHeight.Load() + 1
+
+	var (
+		expiring  []ExpiringDelegation
+		outpoints []wire.OutPoint
+	)
+	for i := range result.Transactions {
+		storedTx := &result.Transactions[i]
+
+		status, err := app.DelegationStatus(storedTx)
+		if errors.Is(err, cl.ErrDelegationNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		state := status.State()
+		if state != BabylonActiveStatus && state != BabylonExpiredStatus {
+			continue
+		}
+
+		if status.ConfirmationHeight == 0 {
+			continue
+		}
+
+		expiryHeight := stakingExpiryHeight(status)
+
+		var blocksLeft uint32
+		if expiryHeight > nextBlockHeight {
+			blocksLeft = expiryHeight - nextBlockHeight
+		}
+
+		if blocksLeft > withinBlocks {
+			continue
+		}
+
+		delegation := ExpiringDelegation{
+			StakingTxHash: storedTx.StakingTxHash,
+			State:         state,
+			ExpiryHeight:  expiryHeight,
+			BlocksLeft:    blocksLeft,
+		}
+
+		if stakingTime, ok := renewals[storedTx.StakingTxHash]; ok {
+			if stakingTime == 0 {
+				stakingTime = uint16(status.Delegation.BtcDelegation.StakingTime)
+			}
+			delegation.AutoRenew = true
+			delegation.RenewStakingTime = stakingTime
+		}
+
+		expiring = append(expiring, delegation)
+		outpoints = append(outpoints, *wire.NewOutPoint(
+			&delegation.StakingTxHash,
+			status.Delegation.BtcDelegation.StakingOutputIdx,
+		))
+	}
+
+	if len(expiring) == 0 {
+		return expiring, nil
+	}
+
+	// Babylon keeps status of withdrawn delegations and reports unbonding only
+	// after it is observed, so spent staking outputs are checked on btc
+	spent, err := app.wc.OutputsSpent(outpoints)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check staking outputs: %w", err)
+	}
+
+	unspent := expiring[:0]
+	for i, delegation := range expiring {
+		if !spent[i] {
+			unspent = append(unspent, delegation)
+		}
+	}
+
+	return unspent, nil
+}
+
+// SetAutoRenew enables restaking of the delegation once its staking timelock
+// expires. Withdrawn funds are staked to the same finality providers for the
+// given staking time, zero staking time keeps staking time of the delegation.
+func (app *App) SetAutoRenew(stakingTxHash *chainhash.Hash, stakingTime uint16) error {
+	if _, err := app.txTracker.GetTransaction(stakingTxHash); err != nil {
+		return err
+	}
+
+	status, err := app.DelegationStatus(&stakerdb.StoredTransaction{StakingTxHash: *stakingTxHash})
+	if err != nil && !errors.Is(err, cl.ErrDelegationNotFound) {
+		return err
+	}
+	if err == nil && status.State() == BabylonUnbondedStatus {
+		return fmt.Errorf("cannot enable auto renew of %s: %w", stakingTxHash, ErrDelegationUnbonded)
+	}
+
+	if stakingTime != 0 {
+		params, err := app.babylonClient.Params()
+		if err != nil {
+			return fmt.Errorf("failed to get params: %w", err)
+		}
+
+		if stakingTime < params.MinStakingTime || stakingTime > params.MaxStakingTime {
+			return fmt.Errorf("staking time %d is not in range [%d, %d]",
+				stakingTime, params.MinStakingTime, params.MaxStakingTime)
+		}
+
+		if err := app.policy.checkStakingTime(stakingTime); err != nil {
+			return err
+		}
+	}
+
+	return app.txTracker.SetAutoRenew(stakingTxHash, stakingTime)
+}
+
+// DisableAutoRenew disables restaking of the delegation on expiry. Returns
+// false if auto renew was not enabled.
+func (app *App) DisableAutoRenew(stakingTxHash *chainhash.Hash) (bool, error) {
+	return app.txTracker.ClearAutoRenew(stakingTxHash)
+}
+
+// renewExpiredDelegations starts renewal jobs of delegations with enabled auto
+// renew whose staking timelock expired and runs pending jobs. Jobs interrupted
+// by restart are resumed first.
+func (app *App) renewExpiredDelegations() error {
+	if app.checkStartupSync() != nil {
+		return nil
+	}
+
+	if !app.renewalJobsResumed.Load() {
+		if err := app.resumeRenewalJobs(); err != nil {
+			return err
+		}
+		app.renewalJobsResumed.Store(true)
+	}
+
+	renewals, err := app.txTracker.ListAutoRenew()
+	if err != nil {
+		return err
+	}
+
+	nextBlockHeight := app.currentBestBlockHeight.Load() + 1
+
+	for txHash := range renewals {
+		stakingTxHash := txHash
+
+		status, err := app.DelegationStatus(&stakerdb.StoredTransaction{StakingTxHash: stakingTxHash})
+		if errors.Is(err, cl.ErrDelegationNotFound) {
+			continue
+		}
+		if err != nil {
+			app.logger.WithFields(logrus.Fields{
+				"stakingTxHash": stakingTxHash,
+				"err":           err,
+			}).Warn("Failed to get status of delegation with auto renew")
+			continue
+		}
+
+		state := status.State()
+		if state == BabylonUnbondedStatus {
+			// delegation did not reach natural expiry
+			if _, err := app.txTracker.ClearAutoRenew(&stakingTxHash); err != nil {
+				return err
+			}
+
+			app.logger.WithFields(logrus.Fields{
+				"stakingTxHash": stakingTxHash,
+			}).Info("Delegation was unbonded, auto renew disabled")
+			continue
+		}
+
+		if state != BabylonExpiredStatus || status.ConfirmationHeight == 0 ||
+			stakingExpiryHeight(status) > nextBlockHeight {
+			continue
+		}
+
+		if _, _, err := app.txTracker.CreateRenewalJob(&stakingTxHash, time.Now()); err != nil {
+			return err
+		}
+	}
+
+	// pending jobs are started once maintenance window ends
+	if app.checkMaintenanceWindow() != nil {
+		return nil
+	}
+
+	jobs, err := app.txTracker.ListRenewalJobs()
+	if err != nil {
+		return err
+	}
+
+	for i := range jobs {
+		job := &jobs[i]
+		if job.State != stakerdb.RenewalJobPending {
+			continue
+		}
+
+		if err := app.startRenewalJob(job); err != nil {
+			app.logger.WithFields(logrus.Fields{
+				"stakingTxHash": job.StakingTxHash,
+				"err":           err,
+			}).Error("Failed to start renewal of expired delegation")
+		}
+	}
+
+	return nil
+}
+
+// resumeRenewalJobs returns jobs which were running when staker stopped back
+// to pending, if staking output was not withdrawn yet. Funds of jobs stopped
+// after withdrawal are in the staker wallet and have to be restaked manually.
+func (app *App) resumeRenewalJobs() error {
+	// pending jobs are started once maintenance window ends
+	if app.checkMaintenanceWindow() != nil {
+		return nil
+	}
+
+	jobs, err := app.txTracker.ListRenewalJobs()
+	if err != nil {
+		return err
+	}
+
+	for i := range jobs {
+		job := &jobs[i]
+		if job.State != stakerdb.RenewalJobRunning {
+			continue
+		}
+
+		status, err := app.DelegationStatus(&stakerdb.StoredTransaction{StakingTxHash: job.StakingTxHash})
+		if err != nil {
+			return fmt.Errorf("failed to get status of renewed delegation %s: %w", job.StakingTxHash, err)
+		}
+
+		spent, err := app.wc.OutputSpent(&job.StakingTxHash, status.Delegation.BtcDelegation.StakingOutputIdx)
+		if err != nil {
+			return fmt.Errorf("failed to check staking output of renewed delegation %s: %w", job.StakingTxHash, err)
+		}
+
+		if spent {
+			app.failRenewalJob(job, "renewal was interrupted after staking output was withdrawn", withdrawnRemediation)
+			continue
+		}
+
+		job.State = stakerdb.RenewalJobPending
+		job.UpdatedAt = time.Now()
+		if err := app.txTracker.UpdateRenewalJob(job); err != nil {
+			return err
+		}
+
+		app.logger.WithFields(logrus.Fields{
+			"stakingTxHash": job.StakingTxHash,
+		}).Info("Resuming renewal of expired delegation interrupted by restart")
+	}
+
+	return nil
+}
+
+// startRenewalJob marks the job as running and restakes funds of the expired
+// delegation in background. Failed attempt which did not withdraw funds is
+// retried on the next block, up to maxRenewalAttempts.
+func (app *App) startRenewalJob(job *stakerdb.RenewalJob) error {
+	status, err := app.DelegationStatus(&stakerdb.StoredTransaction{StakingTxHash: job.StakingTxHash})
+	if err != nil {
+		return fmt.Errorf("failed to get status of renewed delegation %s: %w", job.StakingTxHash, err)
+	}
+
+	fpPks, err := convertFpBtcPkToBtcPk(status.Delegation.BtcDelegation.FpBtcPkList)
+	if err != nil {
+		return err
+	}
+
+	stakingTime := job.StakingTime
+	if stakingTime == 0 {
+		stakingTime = uint16(status.Delegation.BtcDelegation.StakingTime)
+	}
+
+	job.State = stakerdb.RenewalJobRunning
+	job.Attempts++
+	job.UpdatedAt = time.Now()
+	if err := app.txTracker.UpdateRenewalJob(job); err != nil {
+		return err
+	}
+
+	app.startTask("auto_renew", func() {
+		withdrawalTxHash, newStakingTxHash, err := app.RestakeFromUnbonded(&job.StakingTxHash, fpPks, stakingTime)
+		if withdrawalTxHash != nil {
+			job.WithdrawalTxHash = withdrawalTxHash.String()
+		}
+
+		switch {
+		case err == nil && newStakingTxHash == nil:
+			// staker is shutting down, job is resumed on restart
+			return
+		case err == nil:
+			job.State = stakerdb.RenewalJobDone
+			job.NewStakingTxHash = newStakingTxHash.String()
+			job.Error = ""
+		case withdrawalTxHash != nil:
+			app.failRenewalJob(job, err.Error(), withdrawnRemediation)
+			return
+		case job.Attempts >= maxRenewalAttempts:
+			app.failRenewalJob(job, err.Error(), "restake funds manually with restake-from-unbonded")
+			return
+		default:
+			job.State = stakerdb.RenewalJobPending
+			job.Error = err.Error()
+		}
+
+		job.UpdatedAt = time.Now()
+		if err := app.txTracker.UpdateRenewalJob(job); err != nil {
+			app.logger.WithFields(logrus.Fields{
+				"stakingTxHash": job.StakingTxHash,
+				"err":           err,
+			}).Error("Failed to store state of renewal job")
+			return
+		}
+
+		if job.State == stakerdb.RenewalJobPending {
+			app.logger.WithFields(logrus.Fields{
+				"stakingTxHash": job.StakingTxHash,
+				"attempts":      job.Attempts,
+				"err":           job.Error,
+			}).Warn("Failed to renew expired delegation, retrying on next block")
+			return
+		}
+
+		app.logger.WithFields(logrus.Fields{
+			"stakingTxHash":    job.StakingTxHash,
+			"newStakingTxHash": job.NewStakingTxHash,
+			"stakingTime":      stakingTime,
+		}).Info("Expired delegation renewed")
+	})
+
+	return nil
+}
+
+// failRenewalJob marks the job as failed and notes the failure, funds of the
+// delegation have to be restaked manually
+func (app *App) failRenewalJob(job *stakerdb.RenewalJob, reason, remediation string) {
+	job.State = stakerdb.RenewalJobFailed
+	job.Error = reason
+	job.UpdatedAt = time.Now()
+
+	app.logger.WithFields(logrus.Fields{
+		"stakingTxHash":    job.StakingTxHash,
+		"withdrawalTxHash": job.WithdrawalTxHash,
+		"attempts":         job.Attempts,
+		"err":              reason,
+	}).Error("Failed to renew expired delegation")
+
+	if err := app.txTracker.UpdateRenewalJob(job); err != nil {
+		app.logger.WithFields(logrus.Fields{
+			"stakingTxHash": job.StakingTxHash,
+			"err":           err,
+		}).Error("Failed to store state of renewal job")
+	}
+
+	app.noteFailure(&job.StakingTxHash, newFailureNote(
+		stakerdb.FailureSourceStaker,
+		fmt.Sprintf("auto renew failed: %s", reason),
+		remediation,
+	))
+}
+
+// RenewalJobs returns renewal jobs of expired delegations
+func (app *App) RenewalJobs() ([]stakerdb.RenewalJob, error) {
+	return app.txTracker.ListRenewalJobs()
+}
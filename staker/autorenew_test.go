@@ -0,0 +1,143 @@
+package staker_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/babylonlabs-io/btc-staker/staker"
+	"github.com/babylonlabs-io/btc-staker/stakerdb"
+	"github.com/babylonlabs-io/btc-staker/testutil/simulation"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/stretchr/testify/require"
+)
+
+// renewalJob returns renewal job of the delegation, nil if there is none
+func renewalJob(t *testing.T, app *staker.App, stakingTxHash *chainhash.Hash) *stakerdb.RenewalJob {
+	jobs, err := app.RenewalJobs()
+	require.NoError(t, err)
+
+	for i := range jobs {
+		if jobs[i].StakingTxHash == *stakingTxHash {
+			return &jobs[i]
+		}
+	}
+	return nil
+}
+
+// stakingOutPoint returns staking output of the delegation registered on
+// simulated babylon
+func stakingOutPoint(t *testing.T, sim *simulation.Simulation, stakingTxHash *chainhash.Hash) wire.OutPoint {
+	di, err := sim.Babylon.QueryBTCDelegation(stakingTxHash)
+	require.NoError(t, err)
+	return *wire.NewOutPoint(stakingTxHash, di.BtcDelegation.StakingOutputIdx)
+}
+
+// mineUntil mines blocks until condition holds. Renewal is checked on new
+// blocks against delegation status refreshed in background, so it may take a
+// block more than the staking timelock.
+func mineUntil(t *testing.T, sim *simulation.Simulation, condition func() bool) {
+	require.Eventually(t, func() bool {
+		if condition() {
+			return true
+		}
+		sim.Chain.MineBlocks(1)
+		return false
+	}, 10*time.Second, 200*time.Millisecond)
+}
+
+// renewalJobInState returns condition satisfied once renewal job of the
+// delegation is in given state
+func renewalJobInState(t *testing.T, app *staker.App, stakingTxHash *chainhash.Hash, state string) func() bool {
+	return func() bool {
+		job := renewalJob(t, app, stakingTxHash)
+		return job != nil && job.State == state
+	}
+}
+
+func TestAutoRenewIsOptIn(t *testing.T) {
+	t.Parallel()
+
+	sim, app, addr := startSimulatedApp(t)
+	renewed, confirmationHeight := activeDelegation(t, sim, app, addr)
+	notRenewed, _ := activeDelegation(t, sim, app, addr)
+	require.NoError(t, app.SetAutoRenew(renewed, 0))
+
+	// nothing is renewed before staking timelock expires
+	mineToHeight(t, sim, confirmationHeight+withdrawableTestStakingTime-2)
+	require.Nil(t, renewalJob(t, app, renewed))
+
+	mineUntil(t, sim, renewalJobInState(t, app, renewed, stakerdb.RenewalJobDone))
+
+	job := renewalJob(t, app, renewed)
+	require.Equal(t, uint32(1), job.Attempts)
+	withdrawalTxHash, err := chainhash.NewHashFromStr(job.WithdrawalTxHash)
+	require.NoError(t, err)
+	require.True(t, sim.Chain.InMempool(withdrawalTxHash))
+
+	// renewed delegation is tracked and registered on babylon with the
+	// staking time of the expired one
+	newStakingTxHash, err := chainhash.NewHashFromStr(job.NewStakingTxHash)
+	require.NoError(t, err)
+	_, err = app.GetStoredTransaction(newStakingTxHash)
+	require.NoError(t, err)
+	di, err := sim.Babylon.QueryBTCDelegation(newStakingTxHash)
+	require.NoError(t, err)
+	require.Equal(t, uint32(withdrawableTestStakingTime), di.BtcDelegation.StakingTime)
+
+	// delegation without auto renew only becomes withdrawable
+	require.Nil(t, renewalJob(t, app, notRenewed))
+	outpoint := stakingOutPoint(t, sim, notRenewed)
+	require.False(t, sim.Chain.OutputSpent(outpoint))
+	require.Nil(t, sim.Chain.MempoolSpender(outpoint))
+}
+
+func TestAutoRenewRenewsOnlyOnce(t *testing.T) {
+	t.Parallel()
+
+	sim, app, addr := startSimulatedApp(t)
+	stakingTxHash, confirmationHeight := activeDelegation(t, sim, app, addr)
+	require.NoError(t, app.SetAutoRenew(stakingTxHash, 0))
+
+	mineToHeight(t, sim, confirmationHeight+withdrawableTestStakingTime-1)
+	mineUntil(t, sim, renewalJobInState(t, app, stakingTxHash, stakerdb.RenewalJobDone))
+	job := renewalJob(t, app, stakingTxHash)
+	newStakingTxHash, err := chainhash.NewHashFromStr(job.NewStakingTxHash)
+	require.NoError(t, err)
+	require.Equal(t, []chainhash.Hash{*newStakingTxHash}, sim.Babylon.PendingDelegations())
+
+	// withdrawal confirms and further blocks do not start another renewal
+	sim.Chain.MineBlocks(3)
+	time.Sleep(500 * time.Millisecond)
+
+	require.Equal(t, []chainhash.Hash{*newStakingTxHash}, sim.Babylon.PendingDelegations())
+	require.Empty(t, sim.Chain.MempoolTxs())
+}
+
+func TestAutoRenewRespectsStakingLimits(t *testing.T) {
+	t.Parallel()
+
+	sim, app, addr := startSimulatedApp(t)
+	stakingTxHash, confirmationHeight := activeDelegation(t, sim, app, addr)
+	require.NoError(t, app.SetAutoRenew(stakingTxHash, 0))
+
+	// withdrawn funds no longer reach minimal staking value
+	params, err := sim.Babylon.Params()
+	require.NoError(t, err)
+	params.MinStakingValue = btcutil.Amount(200_000)
+	sim.Babylon.SetParams(*params)
+
+	mineToHeight(t, sim, confirmationHeight+withdrawableTestStakingTime-1)
+	mineUntil(t, sim, renewalJobInState(t, app, stakingTxHash, stakerdb.RenewalJobFailed))
+
+	// failed attempts are retried on next blocks up to the limit, funds are
+	// not withdrawn
+	job := renewalJob(t, app, stakingTxHash)
+	require.Equal(t, uint32(3), job.Attempts)
+	require.Contains(t, job.Error, "is not in range")
+	require.Empty(t, job.WithdrawalTxHash)
+	outpoint := stakingOutPoint(t, sim, stakingTxHash)
+	require.False(t, sim.Chain.OutputSpent(outpoint))
+	require.Nil(t, sim.Chain.MempoolSpender(outpoint))
+}
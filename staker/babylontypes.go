@@ -104,12 +104,32 @@ func (app *App) buildDelegation(
 		return nil, fmt.Errorf("error creating undelegation data: %w", err)
 	}
 
+	if err := app.checkUnbondingParams(
+		req.inclusionInfo,
+		storedTx.StakingTx.TxOut[stakingOutputIndex],
+		undelegationDesc,
+		externalData.babylonParams,
+	); err != nil {
+		return nil, err
+	}
+
+	slashingOutputScripts, err := slashingScripts(
+		externalData.stakerPublicKey,
+		externalData.babylonParams.SlashingPkScript,
+		externalData.babylonParams.UnbondingTime,
+		app.network,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error building slashing scripts: %w", err)
+	}
+
 	stakingSlashingSig, err := app.signTaprootScriptSpendUsingWallet(
 		stakingSlashingTx,
 		storedTx.StakingTx.TxOut[stakingOutputIndex],
 		stakerAddress,
 		&stakingSlashingSpendInfo.RevealedLeaf,
 		&stakingSlashingSpendInfo.ControlBlock,
+		slashingOutputScripts...,
 	)
 
 	if err != nil {
@@ -126,6 +146,7 @@ func (app *App) buildDelegation(
 		stakerAddress,
 		&undelegationDesc.SlashUnbondingTransactionSpendInfo.RevealedLeaf,
 		&undelegationDesc.SlashUnbondingTransactionSpendInfo.ControlBlock,
+		slashingOutputScripts...,
 	)
 
 	if err != nil {
@@ -175,7 +196,6 @@ func (app *App) buildDelegation(
 func (app *App) checkForUnbondingTxSignaturesOnBabylon(stakingTxHash *chainhash.Hash) {
 	checkSigTicker := time.NewTicker(app.config.StakerConfig.UnbondingTxCheckInterval)
 	defer checkSigTicker.Stop()
-	defer app.wg.Done()
 
 	for {
 		select {
@@ -245,6 +265,28 @@ func (app *App) checkForUnbondingTxSignaturesOnBabylon(stakingTxHash *chainhash.
 					continue
 				}
 
+				// invalid signatures do not count towards quorum, keep waiting
+				// for valid ones
+				validSigs, delParams, err := app.verifiedUnbondingSignatures(stakingTxHash, di, undelegationInfo)
+				if err != nil {
+					app.logger.WithFields(logrus.Fields{
+						"stakingTxHash": stakingTxHash,
+						"err":           err,
+					}).Warn("Covenant unbonding signatures on babylon failed verification")
+					continue
+				}
+
+				if err := app.storeUnbondingSignatures(stakingTxHash, di.BtcDelegation.ParamsVersion, delParams, validSigs); err != nil {
+					app.logger.WithFields(logrus.Fields{
+						"stakingTxHash": stakingTxHash,
+						"err":           err,
+					}).Error("Failed to store covenant unbonding signatures")
+					continue
+				}
+
+				unbondingTxHash := undelegationInfo.UnbondingTransaction.TxHash()
+				app.indexSpendTx(stakingTxHash, stakerdb.WatchedUnbondingTx, &unbondingTxHash)
+
 				req := &unbondingTxSignaturesConfirmedOnBabylonEvent{
 					stakingTxHash:      *stakingTxHash,
 					stakingOutputIndex: di.BtcDelegation.StakingOutputIdx,
@@ -294,11 +336,34 @@ func (app *App) activateVerifiedDelegation(
 	stakingTxHash *chainhash.Hash) {
 	checkSigTicker := time.NewTicker(app.config.StakerConfig.CheckActiveInterval)
 	defer checkSigTicker.Stop()
-	defer app.wg.Done()
+
+	// babylon is polled at fixed interval, but if btc node fails or lags behind
+	// babylon, back off to not hammer it
+	btcBackoff := newPollBackoff(
+		app.config.StakerConfig.CheckActiveInterval,
+		app.config.StakerConfig.BtcMaxBackoff,
+	)
 
 	for {
 		select {
 		case <-checkSigTicker.C:
+			failure, err := app.stakingTxFailure(stakingTxHash)
+			if err != nil {
+				app.logger.WithFields(logrus.Fields{
+					"stakingTxHash": stakingTxHash,
+					"err":           err,
+				}).Error("Error checking whether staking transaction failed")
+				continue
+			}
+
+			if failure != nil {
+				app.logger.WithFields(logrus.Fields{
+					"stakingTxHash": stakingTxHash,
+					"reason":        failure.Reason,
+				}).Error("Staking transaction permanently failed, stop waiting for activation")
+				return
+			}
+
 			di, err := app.babylonClient.QueryBTCDelegation(stakingTxHash)
 			if err != nil {
 				if errors.Is(err, cl.ErrDelegationNotFound) {
@@ -348,6 +413,7 @@ func (app *App) activateVerifiedDelegation(
 
 					// failed to retrieve transaction details from bitcoind node, most probably
 					// connection error, we will try again in next iteration
+					checkSigTicker.Reset(btcBackoff.failure())
 					continue
 				}
 
@@ -355,6 +421,7 @@ func (app *App) activateVerifiedDelegation(
 					app.logger.WithFields(logrus.Fields{
 						"stakingTxHash": stakingTxHash,
 					}).Debug("Staking transaction active on babylon, but not on btc chain. Waiting for btc node to catch up")
+					checkSigTicker.Reset(btcBackoff.failure())
 					continue
 				}
 
@@ -396,9 +463,14 @@ func (app *App) activateVerifiedDelegation(
 					"stakingTxHash": stakingTxHash,
 					"err":           err,
 				}).Error("Error checking existence of staking transaction on btc chain")
+				checkSigTicker.Reset(btcBackoff.failure())
 				continue
 			}
 
+			if btcBackoff.backingOff() {
+				checkSigTicker.Reset(btcBackoff.success())
+			}
+
 			if status != walletcontroller.TxNotFound {
 				app.logger.WithFields(logrus.Fields{
 					"status":        status,
@@ -411,6 +483,15 @@ func (app *App) activateVerifiedDelegation(
 			// - delegation is not active and already have quorum of covenant signatures
 			// - staking transaction is not on btc chain
 
+			// broadcast is retried once maintenance window ends
+			if err := app.checkMaintenanceWindow(); err != nil {
+				app.logger.WithFields(logrus.Fields{
+					"stakingTxHash": stakingTxHash,
+					"err":           err,
+				}).Debug("Deferring broadcast of staking transaction of verified delegation")
+				continue
+			}
+
 			// check if staking transaction is fully signed
 			isSigned, err := isTransacionFullySigned(stakingTransaction)
 
@@ -431,6 +512,12 @@ func (app *App) activateVerifiedDelegation(
 						"err":           err,
 						"stakingTxHash": stakingTxHash,
 					}).Error("failed to send staking transaction to btc chain to activate verified delegation")
+					app.noteFailure(stakingTxHash, btcFailureNote(err))
+				} else {
+					app.watchMempoolTx(stakingTxHash, stakerdb.WatchedStakingTx, stakingTransaction)
+					app.recordActivationPhase(stakingTxHash, stakerdb.ActivationPhaseBroadcast, time.Now())
+					app.recordBroadcastHeight(stakingTxHash)
+					app.recordStakingTxFee(stakingTxHash, stakingTransaction)
 				}
 
 				continue
@@ -471,6 +558,12 @@ func (app *App) activateVerifiedDelegation(
 					"err":           err,
 					"stakingTxHash": stakingTxHash,
 				}).Error("failed to send staking transaction to btc chain to activate verified delegation")
+				app.noteFailure(stakingTxHash, btcFailureNote(err))
+			} else {
+				app.watchMempoolTx(stakingTxHash, stakerdb.WatchedStakingTx, signedTx)
+				app.recordActivationPhase(stakingTxHash, stakerdb.ActivationPhaseBroadcast, time.Now())
+				app.recordBroadcastHeight(stakingTxHash)
+				app.recordStakingTxFee(stakingTxHash, signedTx)
 			}
 			// at this point we send signed staking transaction to BTC chain, we will
 			// still wait for its activation
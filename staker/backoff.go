@@ -0,0 +1,37 @@
+package staker
+
+import "time"
+
+// pollBackoff tracks interval of polling btc node. Interval doubles on every
+// consecutive failure, up to the maximum, and goes back to the base interval
+// on success.
+type pollBackoff struct {
+	base    time.Duration
+	max     time.Duration
+	current time.Duration
+}
+
+func newPollBackoff(base, maxInterval time.Duration) *pollBackoff {
+	return &pollBackoff{
+		base:    base,
+		max:     max(base, maxInterval),
+		current: base,
+	}
+}
+
+// failure returns next polling interval after failed poll
+func (b *pollBackoff) failure() time.Duration {
+	b.current = min(b.current*2, b.max)
+	return b.current
+}
+
+// success returns next polling interval after successful poll
+func (b *pollBackoff) success() time.Duration {
+	b.current = b.base
+	return b.current
+}
+
+// backingOff returns true if polling interval is above the base interval
+func (b *pollBackoff) backingOff() bool {
+	return b.current != b.base
+}
@@ -0,0 +1,62 @@
+package staker
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPollBackoff(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		base time.Duration
+		max  time.Duration
+		// polls holds result of consecutive polls, true for success
+		polls    []bool
+		expected []time.Duration
+	}{
+		{
+			name:     "doubles up to max",
+			base:     time.Second,
+			max:      5 * time.Second,
+			polls:    []bool{false, false, false, false},
+			expected: []time.Duration{2 * time.Second, 4 * time.Second, 5 * time.Second, 5 * time.Second},
+		},
+		{
+			name:     "success resets to base",
+			base:     time.Second,
+			max:      time.Minute,
+			polls:    []bool{false, false, true, false},
+			expected: []time.Duration{2 * time.Second, 4 * time.Second, time.Second, 2 * time.Second},
+		},
+		{
+			name:     "max below base never backs off",
+			base:     time.Minute,
+			max:      time.Second,
+			polls:    []bool{false, false},
+			expected: []time.Duration{time.Minute, time.Minute},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			b := newPollBackoff(tc.base, tc.max)
+			require.False(t, b.backingOff())
+
+			for i, success := range tc.polls {
+				var next time.Duration
+				if success {
+					next = b.success()
+				} else {
+					next = b.failure()
+				}
+
+				require.Equal(t, tc.expected[i], next, "poll %d", i)
+				require.Equal(t, next != tc.base, b.backingOff(), "poll %d", i)
+			}
+		})
+	}
+}
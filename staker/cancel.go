@@ -0,0 +1,205 @@
+package staker
+
+import (
+	"fmt"
+
+	"github.com/babylonlabs-io/btc-staker/stakerdb"
+	"github.com/babylonlabs-io/btc-staker/utils"
+	"github.com/babylonlabs-io/btc-staker/walletcontroller"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/mempool"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/btcsuite/btcwallet/wallet/txrules"
+	"github.com/btcsuite/btcwallet/wallet/txsizes"
+	"github.com/sirupsen/logrus"
+)
+
+// buildCancelTx builds transaction which spends all inputs of the staking
+// transaction back to the staker address. Transaction pays enough fee to
+// replace staking transaction in the mempool.
+func (app *App) buildCancelTx(storedTx *stakerdb.StoredTransaction) (*wire.MsgTx, btcutil.Amount, error) {
+	stakingTx := storedTx.StakingTx
+
+	stakerAddress, err := btcutil.DecodeAddress(storedTx.StakerAddress, app.network)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to decode staker address: %w", err)
+	}
+
+	destinationScript, err := txscript.PayToAddrScript(stakerAddress)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to build destination script: %w", err)
+	}
+
+	cancelTx := wire.NewMsgTx(2)
+
+	var (
+		inputsValue  btcutil.Amount
+		numP2TR      int
+		numP2WPKH    int
+		numNestedP2W int
+	)
+
+	prevOuts, err := app.prevOutputs(stakingTx)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	for i, in := range stakingTx.TxIn {
+		prevOut := prevOuts[i]
+		switch {
+		case txscript.IsPayToTaproot(prevOut.PkScript):
+			numP2TR++
+		case txscript.IsPayToWitnessPubKeyHash(prevOut.PkScript):
+			numP2WPKH++
+		case txscript.IsPayToScriptHash(prevOut.PkScript):
+			numNestedP2W++
+		default:
+			return nil, 0, fmt.Errorf("unsupported input script type of %s", in.PreviousOutPoint)
+		}
+
+		inputsValue += btcutil.Amount(prevOut.Value)
+
+		// signal replaceability, so that cancel tx itself can be bumped
+		cancelInput := wire.NewTxIn(&in.PreviousOutPoint, nil, nil)
+		cancelInput.Sequence = wire.MaxTxInSequenceNum - 2
+		cancelTx.AddTxIn(cancelInput)
+	}
+
+	var outputsValue btcutil.Amount
+	for _, out := range stakingTx.TxOut {
+		outputsValue += btcutil.Amount(out.Value)
+	}
+	originalFee := inputsValue - outputsValue
+
+	cancelOutput := wire.NewTxOut(int64(inputsValue), destinationScript)
+	cancelTx.AddTxOut(cancelOutput)
+
+	txSize := txsizes.EstimateVirtualSize(0, numP2TR, numP2WPKH, numNestedP2W, []*wire.TxOut{cancelOutput}, 0)
+
+	// replacement transaction must pay at least original fee plus incremental
+	// relay fee for its own size
+	feeRate := btcutil.Amount(app.estimatedFeeRate())
+	fee := max(
+		txrules.FeeForSerializeSize(feeRate, txSize),
+		originalFee+txrules.FeeForSerializeSize(app.relayFees().IncrementalRelayFeePerKb, txSize),
+	)
+
+	cancelTx.TxOut[0].Value -= int64(fee)
+
+	if cancelTx.TxOut[0].Value <= 0 {
+		return nil, 0, fmt.Errorf("inputs value %d too low to pay cancel tx fee %d", inputsValue, fee)
+	}
+
+	// sanity check that transaction is standard
+	if err := utils.CheckTransaction(cancelTx); err != nil {
+		return nil, 0, fmt.Errorf("failed to build cancel tx: %w", err)
+	}
+
+	return cancelTx, fee, nil
+}
+
+// sendCancelTx builds, signs and broadcasts transaction replacing the staking
+// transaction in the mempool
+func (app *App) sendCancelTx(storedTx *stakerdb.StoredTransaction) (*chainhash.Hash, btcutil.Amount, error) {
+	cancelTx, fee, err := app.buildCancelTx(storedTx)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if err := app.checkSigningPolicy(cancelTx); err != nil {
+		return nil, 0, err
+	}
+
+	if err := app.wc.UnlockWallet(defaultWalletUnlockTimeout); err != nil {
+		return nil, 0, fmt.Errorf("failed to unlock wallet: %w", err)
+	}
+
+	signedTx, fullySigned, err := app.wc.SignRawTransaction(cancelTx)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to sign cancel tx: %w", err)
+	}
+
+	if !fullySigned {
+		return nil, 0, fmt.Errorf("failed to fully sign cancel tx")
+	}
+
+	if err := app.checkSigningPolicy(signedTx); err != nil {
+		return nil, 0, err
+	}
+
+	// sanity check that our size estimation was not off
+	if vsize := mempool.GetTxVirtualSize(btcutil.NewTx(signedTx)); btcutil.Amount(vsize) > fee {
+		app.logger.Warnf("Cancel tx pays less than 1 sat/vB, vsize: %d, fee: %d", vsize, fee)
+	}
+
+	cancelTxHash, err := app.wc.SendRawTransaction(signedTx, true)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to send cancel tx: %w", err)
+	}
+
+	return cancelTxHash, fee, nil
+}
+
+// CancelStake abandons tracked staking transaction which is not yet confirmed
+// on btc. If staking transaction is in mempool, it is replaced by transaction
+// sending its inputs back to the staker address, and hash of the replacement
+// is returned. If staking transaction was never broadcast, returned hash is nil.
+func (app *App) CancelStake(stakingTxHash *chainhash.Hash) (*chainhash.Hash, btcutil.Amount, error) {
+	if err := app.checkStartupSync(); err != nil {
+		return nil, 0, err
+	}
+
+	storedTx, err := app.txTracker.GetTransaction(stakingTxHash)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get tracked transaction: %w", err)
+	}
+
+	failure, err := app.stakingTxFailure(stakingTxHash)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if failure != nil {
+		return nil, 0, fmt.Errorf("staking transaction %s already failed: %s", stakingTxHash, failure.Reason)
+	}
+
+	if len(storedTx.StakingTx.TxOut) == 0 {
+		return nil, 0, fmt.Errorf("staking transaction %s has no outputs", stakingTxHash)
+	}
+
+	_, status, err := app.wc.TxDetails(stakingTxHash, storedTx.StakingTx.TxOut[0].PkScript)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get staking tx details: %w", err)
+	}
+
+	var (
+		cancelTxHash *chainhash.Hash
+		fee          btcutil.Amount
+		reason       = "cancelled by user before staking transaction was sent to btc"
+	)
+
+	switch status {
+	case walletcontroller.TxInChain:
+		return nil, 0, fmt.Errorf("staking transaction %s is already confirmed on btc, use unbonding instead", stakingTxHash)
+	case walletcontroller.TxInMemPool:
+		cancelTxHash, fee, err = app.sendCancelTx(storedTx)
+		if err != nil {
+			return nil, 0, err
+		}
+		reason = fmt.Sprintf("cancelled by user with transaction %s", cancelTxHash)
+	}
+
+	if err := app.failStakingTx(stakingTxHash, newFailureNote(stakerdb.FailureSourceStaker, reason, "")); err != nil {
+		return nil, 0, err
+	}
+
+	app.logger.WithFields(logrus.Fields{
+		"stakingTxHash": stakingTxHash,
+		"cancelTxHash":  cancelTxHash,
+		"fee":           fee,
+	}).Info("Staking transaction cancelled")
+
+	return cancelTxHash, fee, nil
+}
@@ -0,0 +1,156 @@
+package staker
+
+import (
+	"testing"
+
+	"github.com/babylonlabs-io/btc-staker/stakercfg"
+	"github.com/babylonlabs-io/btc-staker/stakerdb"
+	"github.com/babylonlabs-io/btc-staker/testutil/mocks"
+	"github.com/babylonlabs-io/btc-staker/walletcontroller"
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/btcsuite/btcwallet/wallet/txrules"
+	"github.com/btcsuite/btcwallet/wallet/txsizes"
+	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func newCancelTestApp(t *testing.T, wc *mocks.MockWalletController, estimated chainfee.SatPerKVByte) *App {
+	cfg := stakercfg.DefaultConfig()
+
+	feeEstimator := mocks.NewMockFeeEstimator(gomock.NewController(t))
+	feeEstimator.EXPECT().EstimateFeePerKb().Return(estimated).AnyTimes()
+
+	wc.EXPECT().RelayFees().Return(&walletcontroller.RelayFees{
+		MinRelayFeePerKb:         btcutil.Amount(1000),
+		IncrementalRelayFeePerKb: btcutil.Amount(1000),
+	}, nil).AnyTimes()
+
+	return &App{
+		config:       &cfg,
+		logger:       logrus.New(),
+		network:      &chaincfg.RegressionNetParams,
+		wc:           wc,
+		feeEstimator: feeEstimator,
+		statuses:     newDelegationStatusCache(),
+		startup:      &startupSync{status: StartupSyncStatus{Done: true}},
+	}
+}
+
+func TestBuildCancelTx(t *testing.T) {
+	t.Parallel()
+
+	key, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+	stakerAddr, err := btcutil.NewAddressTaproot(schnorr.SerializePubKey(key.PubKey()), &chaincfg.RegressionNetParams)
+	require.NoError(t, err)
+	stakerScript, err := txscript.PayToAddrScript(stakerAddr)
+	require.NoError(t, err)
+
+	cancelSize := txsizes.EstimateVirtualSize(0, 1, 0, 0, []*wire.TxOut{wire.NewTxOut(0, stakerScript)}, 0)
+
+	tests := []struct {
+		name        string
+		inputValue  int64
+		stakedValue int64
+		estimated   chainfee.SatPerKVByte
+		expectedFee btcutil.Amount
+		wantErr     string
+	}{
+		{
+			// replacement pays original fee plus incremental relay fee for
+			// its own size
+			name:        "original fee plus incremental relay fee",
+			inputValue:  100_000,
+			stakedValue: 90_000,
+			estimated:   1000,
+			expectedFee: 10_000 + txrules.FeeForSerializeSize(1000, cancelSize),
+		},
+		{
+			name:        "estimated fee rate above replacement minimum",
+			inputValue:  100_000,
+			stakedValue: 99_900,
+			estimated:   100_000,
+			expectedFee: txrules.FeeForSerializeSize(100_000, cancelSize),
+		},
+		{
+			name:        "inputs too low to pay fee",
+			inputValue:  1000,
+			stakedValue: 900,
+			estimated:   100_000,
+			wantErr:     "too low to pay cancel tx fee",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			prevTx := wire.NewMsgTx(2)
+			prevTx.AddTxIn(wire.NewTxIn(wire.NewOutPoint(&chainhash.Hash{1}, 0), nil, nil))
+			prevTx.AddTxOut(wire.NewTxOut(tc.inputValue, policyTestScript(t)))
+
+			prevTxHash := prevTx.TxHash()
+			stakingTx := wire.NewMsgTx(2)
+			stakingTx.AddTxIn(wire.NewTxIn(wire.NewOutPoint(&prevTxHash, 0), nil, nil))
+			stakingTx.AddTxOut(wire.NewTxOut(tc.stakedValue, policyTestScript(t)))
+
+			wc := mocks.NewMockWalletController(gomock.NewController(t))
+			wc.EXPECT().Txs(gomock.Any()).Return([]*btcutil.Tx{btcutil.NewTx(prevTx)}, nil)
+			app := newCancelTestApp(t, wc, tc.estimated)
+
+			cancelTx, fee, err := app.buildCancelTx(&stakerdb.StoredTransaction{
+				StakingTx:     stakingTx,
+				StakingTxHash: stakingTx.TxHash(),
+				StakerAddress: stakerAddr.EncodeAddress(),
+			})
+			if tc.wantErr != "" {
+				require.ErrorContains(t, err, tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+
+			require.Equal(t, tc.expectedFee, fee)
+			originalFee := btcutil.Amount(tc.inputValue - tc.stakedValue)
+			require.Greater(t, fee, originalFee)
+
+			require.Len(t, cancelTx.TxIn, 1)
+			require.Equal(t, stakingTx.TxIn[0].PreviousOutPoint, cancelTx.TxIn[0].PreviousOutPoint)
+			// cancel tx signals replaceability itself
+			require.Less(t, cancelTx.TxIn[0].Sequence, wire.MaxTxInSequenceNum-1)
+
+			require.Len(t, cancelTx.TxOut, 1)
+			require.Equal(t, stakerScript, cancelTx.TxOut[0].PkScript)
+			require.Equal(t, tc.inputValue-int64(fee), cancelTx.TxOut[0].Value)
+		})
+	}
+}
+
+func TestCancelStakeRejectsConfirmedTx(t *testing.T) {
+	t.Parallel()
+
+	wc := mocks.NewMockWalletController(gomock.NewController(t))
+	app := newCancelTestApp(t, wc, 1000)
+	store := newArchiveTestStore(t)
+	app.txTracker = store
+
+	confirmed := genReplicaTestTransaction(t, 10_000)
+	require.NoError(t, store.AddTransactionSentToBabylon(confirmed.StakingTx, confirmed.StakerAddress))
+	stakingTxHash := confirmed.StakingTx.TxHash()
+
+	wc.EXPECT().TxDetails(&stakingTxHash, confirmed.StakingTx.TxOut[0].PkScript).
+		Return(nil, walletcontroller.TxInChain, nil)
+
+	_, _, err := app.CancelStake(&stakingTxHash)
+	require.ErrorContains(t, err, "already confirmed on btc")
+
+	// confirmed staking transaction is not marked as failed
+	failure, err := store.GetTransactionFailure(&stakingTxHash)
+	require.NoError(t, err)
+	require.Nil(t, failure)
+}
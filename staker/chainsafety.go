@@ -0,0 +1,210 @@
+package staker
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// ErrChainUnsafe is returned when params sensitive operations are paused,
+// because Babylon or btc chain is not in a state in which params can be
+// trusted
+var ErrChainUnsafe = errors.New("params sensitive operations are paused")
+
+// ChainSafetyStatus is the result of the last chain safety check
+type ChainSafetyStatus struct {
+	// Paused is true if any of the checks failed, Reasons describe them
+	Paused  bool
+	Reasons []string
+	// Override is set by operator to run params sensitive operations even
+	// while paused
+	Override bool
+	// BabylonHeight is the latest Babylon height and BabylonHeightChangedAt
+	// the time staker first observed it
+	BabylonHeight          uint64
+	BabylonHeightChangedAt time.Time
+	BtcHeight              uint32
+	// BtcLightClientHeight is the tip of Babylon btc light client
+	BtcLightClientHeight uint32
+	CheckedAt            time.Time
+}
+
+type chainSafety struct {
+	mu     sync.RWMutex
+	status ChainSafetyStatus
+}
+
+// ChainSafetyStatus returns the result of the last chain safety check
+func (app *App) ChainSafetyStatus() ChainSafetyStatus {
+	app.safety.mu.RLock()
+	defer app.safety.mu.RUnlock()
+
+	return app.safety.status
+}
+
+// SetChainSafetyOverride allows or disallows params sensitive operations while
+// chain safety checks fail
+func (app *App) SetChainSafetyOverride(override bool) error {
+	app.safety.mu.Lock()
+	app.safety.status.Override = override
+	app.safety.mu.Unlock()
+
+	app.logger.WithFields(logrus.Fields{
+		"override": override,
+	}).Warn("Chain safety override changed")
+
+	return app.txTracker.RecordChainSafetyOverride(override)
+}
+
+// checkChainSafety returns ErrChainUnsafe if params sensitive operations are
+// paused and operator did not override it
+func (app *App) checkChainSafety() error {
+	app.safety.mu.RLock()
+	defer app.safety.mu.RUnlock()
+
+	if !app.safety.status.Paused || app.safety.status.Override {
+		return nil
+	}
+
+	return fmt.Errorf("%w: %s", ErrChainUnsafe, strings.Join(app.safety.status.Reasons, "; "))
+}
+
+// handleChainSafety periodically checks that Babylon produces blocks and that
+// Babylon btc light client follows btc chain
+func (app *App) handleChainSafety() {
+	ticker := time.NewTicker(app.config.StakerConfig.ChainSafetyCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		app.updateChainSafety()
+
+		select {
+		case <-ticker.C:
+		case <-app.quit:
+			return
+		}
+	}
+}
+
+func (app *App) updateChainSafety() {
+	cfg := app.config.StakerConfig
+	now := time.Now()
+
+	app.safety.mu.RLock()
+	next := app.safety.status
+	app.safety.mu.RUnlock()
+
+	wasPaused := next.Paused
+	next.CheckedAt = now
+
+	// query errors are not reasons to pause on their own, unreachable
+	// Babylon is detected as halted once the threshold passes
+	babylonHeight, err := app.babylonClient.GetLatestBlockHeight()
+	if err != nil {
+		app.logger.WithFields(logrus.Fields{
+			"err": err,
+		}).Warn("Failed to get babylon height for chain safety check")
+	} else if babylonHeight != next.BabylonHeight {
+		next.BabylonHeight = babylonHeight
+		next.BabylonHeightChangedAt = now
+	}
+
+	lcHeight, err := app.babylonClient.QueryBtcLightClientTipHeight()
+	if err != nil {
+		app.logger.WithFields(logrus.Fields{
+			"err": err,
+		}).Warn("Failed to get babylon btc light client tip for chain safety check")
+	} else {
+		next.BtcLightClientHeight = lcHeight
+	}
+
+	next.BtcHeight = app.currentBestBlockHeight.Load()
+
+	var reasons []string
+
+	if cfg.BabylonHaltThreshold > 0 && !next.BabylonHeightChangedAt.IsZero() {
+		if stalled := now.Sub(next.BabylonHeightChangedAt); stalled > cfg.BabylonHaltThreshold {
+			reasons = append(reasons, fmt.Sprintf("babylon did not produce a block after height %d for %s",
+				next.BabylonHeight, stalled.Round(time.Second)))
+		}
+	}
+
+	if cfg.MaxBtcHeightDivergence > 0 && next.BtcHeight > 0 && next.BtcLightClientHeight > 0 {
+		divergence := next.BtcHeight - next.BtcLightClientHeight
+		if next.BtcLightClientHeight > next.BtcHeight {
+			divergence = next.BtcLightClientHeight - next.BtcHeight
+		}
+
+		if divergence > cfg.MaxBtcHeightDivergence {
+			reasons = append(reasons, fmt.Sprintf("babylon btc light client tip %d diverges from btc tip %d by %d blocks",
+				next.BtcLightClientHeight, next.BtcHeight, divergence))
+		}
+	}
+
+	if reason := app.halvingReason(next.BtcHeight); reason != "" {
+		reasons = append(reasons, reason)
+	}
+
+	next.Reasons = reasons
+	next.Paused = len(reasons) > 0
+
+	app.safety.mu.Lock()
+	// override may have changed while checking
+	next.Override = app.safety.status.Override
+	app.safety.status = next
+	app.safety.mu.Unlock()
+
+	if next.Paused {
+		app.m.ChainSafetyPaused.Set(1)
+	} else {
+		app.m.ChainSafetyPaused.Set(0)
+	}
+
+	if next.Paused == wasPaused {
+		return
+	}
+
+	reason := strings.Join(reasons, "; ")
+	if next.Paused {
+		app.logger.WithFields(logrus.Fields{
+			"reason":   reason,
+			"override": next.Override,
+		}).Warn("Chain is not safe, pausing params sensitive operations")
+	} else {
+		app.logger.Info("Chain is safe again, resuming params sensitive operations")
+	}
+
+	if err := app.txTracker.RecordChainSafetyChange(next.Paused, reason); err != nil {
+		app.logger.WithFields(logrus.Fields{
+			"err": err,
+		}).Error("Failed to record chain safety change")
+	}
+}
+
+// halvingReason returns why operations are paused if btc height is within
+// configured number of blocks of a subsidy halving, during which fee market
+// and fee estimates are unstable
+func (app *App) halvingReason(btcHeight uint32) string {
+	window := app.config.StakerConfig.HalvingPauseBlocks
+	interval := uint32(app.network.SubsidyReductionInterval)
+	if window == 0 || interval == 0 || btcHeight == 0 {
+		return ""
+	}
+
+	previous := btcHeight / interval * interval
+	next := previous + interval
+
+	if next-btcHeight <= window {
+		return fmt.Sprintf("btc halving at height %d is in %d blocks", next, next-btcHeight)
+	}
+
+	if previous > 0 && btcHeight-previous < window {
+		return fmt.Sprintf("btc halving at height %d was %d blocks ago", previous, btcHeight-previous)
+	}
+
+	return ""
+}
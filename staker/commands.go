@@ -28,6 +28,14 @@ type stakingRequestCmd struct {
 	successChan             chan *chainhash.Hash
 	// Expansion-specific fields for Babylon integration
 	stakeExpansion *stakeExpansionReqFields
+	// fundingOutpoint, if set, is the only input of the staking transaction.
+	// Whole value of the outpoint except the fee goes to the staking output.
+	fundingOutpoint *wire.OutPoint
+	// tenant the staking transaction is assigned to, empty means default tenant
+	tenant string
+	// reference committed to in OP_RETURN output of the staking transaction,
+	// empty means no commitment
+	reference string
 }
 
 type stakeExpansionReqFields struct {
@@ -82,6 +90,21 @@ func (req *stakingRequestCmd) WithStakeExpansion(
 	return req
 }
 
+func (req *stakingRequestCmd) WithFundingOutpoint(fundingOutpoint wire.OutPoint) *stakingRequestCmd {
+	req.fundingOutpoint = &fundingOutpoint
+	return req
+}
+
+func (req *stakingRequestCmd) WithTenant(tenant string) *stakingRequestCmd {
+	req.tenant = tenant
+	return req
+}
+
+func (req *stakingRequestCmd) WithReference(reference string) *stakingRequestCmd {
+	req.reference = reference
+	return req
+}
+
 // migrateStakingCmd represents a command to migrate a staking transaction
 type migrateStakingCmd struct {
 	stakerAddr        btcutil.Address
@@ -90,6 +113,9 @@ type migrateStakingCmd struct {
 	pop               *cl.BabylonPop
 	errChan           chan error
 	successChanTxHash chan string
+	// retry is set if delegation is already tracked and its registration on
+	// Babylon is retried
+	retry bool
 }
 
 // newMigrateStakingCmd builds a new migrate staking command
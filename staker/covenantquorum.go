@@ -0,0 +1,81 @@
+package staker
+
+import (
+	"time"
+
+	"github.com/babylonlabs-io/btc-staker/stakerdb"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/sirupsen/logrus"
+)
+
+// recordRegistration stores time delegation was registered on Babylon. It is
+// used only to measure covenant committee responsiveness, so failure is only
+// logged.
+func (app *App) recordRegistration(stakingTxHash *chainhash.Hash) {
+	if err := app.txTracker.SetDelegationRegisteredAt(stakingTxHash, time.Now()); err != nil {
+		app.logger.WithFields(logrus.Fields{
+			"stakingTxHash": stakingTxHash,
+			"err":           err,
+		}).Error("Failed to record delegation registration time")
+	}
+}
+
+// hasCovenantQuorum returns true if delegation in given Babylon state has
+// quorum of covenant signatures
+func hasCovenantQuorum(state string) bool {
+	return state == BabylonVerifiedStatus || state == BabylonActiveStatus
+}
+
+// observeCovenantQuorum records time at which quorum of covenant signatures
+// was first observed. Latency is exported only if delegation was seen pending
+// by the previous refresh, as otherwise quorum could have been reached any
+// time while daemon was offline.
+func (app *App) observeCovenantQuorum(stakingTxHash *chainhash.Hash, status, prev *DelegationStatus) {
+	if prev != nil && prev.quorumRecorded {
+		status.quorumRecorded = true
+		return
+	}
+
+	if !hasCovenantQuorum(status.State()) {
+		return
+	}
+
+	stored, err := app.txTracker.SetCovenantQuorumReachedAt(stakingTxHash, status.RefreshedAt)
+	if err != nil {
+		// retried on the next refresh
+		app.logger.WithFields(logrus.Fields{
+			"stakingTxHash": stakingTxHash,
+			"err":           err,
+		}).Warn("Failed to record covenant quorum time")
+		return
+	}
+
+	status.quorumRecorded = true
+
+	if !stored || prev == nil || prev.State() != BabylonPendingStatus {
+		return
+	}
+
+	timing, err := app.txTracker.GetCovenantQuorumTiming(stakingTxHash)
+	if err != nil || timing == nil {
+		return
+	}
+
+	latency, ok := timing.Latency()
+	if !ok {
+		return
+	}
+
+	app.m.CovenantQuorumLatency.Observe(latency.Seconds())
+
+	app.logger.WithFields(logrus.Fields{
+		"stakingTxHash": stakingTxHash,
+		"latency":       latency,
+	}).Debug("Delegation received quorum of covenant signatures")
+}
+
+// CovenantQuorumTiming returns registration and covenant quorum times of the
+// delegation, nil if they were not recorded
+func (app *App) CovenantQuorumTiming(stakingTxHash *chainhash.Hash) (*stakerdb.CovenantQuorumTiming, error) {
+	return app.txTracker.GetCovenantQuorumTiming(stakingTxHash)
+}
@@ -0,0 +1,236 @@
+package staker
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"time"
+
+	staking "github.com/babylonlabs-io/babylon/v4/btcstaking"
+	bbn "github.com/babylonlabs-io/babylon/v4/types"
+	btcstktypes "github.com/babylonlabs-io/babylon/v4/x/btcstaking/types"
+	cl "github.com/babylonlabs-io/btc-staker/babylonclient"
+	"github.com/babylonlabs-io/btc-staker/stakerdb"
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/sirupsen/logrus"
+)
+
+// ErrCovenantQuorumNotMet is returned when delegation does not have quorum of
+// valid covenant signatures of its unbonding transaction
+var ErrCovenantQuorumNotMet = errors.New("not enough valid covenant unbonding signatures")
+
+// rejectedCovenantSignature is a covenant signature which did not pass
+// verification
+type rejectedCovenantSignature struct {
+	pubKey *btcec.PublicKey
+	reason string
+}
+
+// verifyCovenantUnbondingSignatures verifies covenant signatures of unbonding
+// transaction against sighash of unbonding path spend of the staking output.
+// Signatures of keys outside of the covenant committee, invalid signatures and
+// further signatures of committee member which already has valid one are
+// rejected. Returns valid signatures, or ErrCovenantQuorumNotMet if there
+// are fewer than quorum of them.
+func verifyCovenantUnbondingSignatures(
+	unbondingTx *wire.MsgTx,
+	stakingOutput *wire.TxOut,
+	unbondingPath *staking.SpendInfo,
+	covenantPks []*btcec.PublicKey,
+	quorum uint32,
+	sigs []cl.CovenantSignatureInfo,
+) ([]cl.CovenantSignatureInfo, []rejectedCovenantSignature, error) {
+	if len(unbondingTx.TxIn) != 1 {
+		return nil, nil, fmt.Errorf("unbonding transaction must have exactly one input, has %d", len(unbondingTx.TxIn))
+	}
+
+	fetcher := txscript.NewCannedPrevOutputFetcher(stakingOutput.PkScript, stakingOutput.Value)
+	sigHash, err := txscript.CalcTapscriptSignaturehash(
+		txscript.NewTxSigHashes(unbondingTx, fetcher),
+		txscript.SigHashDefault,
+		unbondingTx,
+		0,
+		fetcher,
+		unbondingPath.RevealedLeaf,
+	)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to calculate unbonding sighash: %w", err)
+	}
+
+	committee := make(map[string]struct{}, len(covenantPks))
+	for _, pk := range covenantPks {
+		committee[pubKeyToString(pk)] = struct{}{}
+	}
+
+	var (
+		valid    []cl.CovenantSignatureInfo
+		rejected []rejectedCovenantSignature
+		signed   = make(map[string]struct{}, len(sigs))
+	)
+	for _, sig := range sigs {
+		if sig.PubKey == nil || sig.Signature == nil {
+			rejected = append(rejected, rejectedCovenantSignature{pubKey: sig.PubKey, reason: "missing key or signature"})
+			continue
+		}
+
+		if _, ok := committee[pubKeyToString(sig.PubKey)]; !ok {
+			rejected = append(rejected, rejectedCovenantSignature{pubKey: sig.PubKey, reason: "key is not in covenant committee"})
+			continue
+		}
+
+		if _, ok := signed[pubKeyToString(sig.PubKey)]; ok {
+			rejected = append(rejected, rejectedCovenantSignature{pubKey: sig.PubKey, reason: "duplicate signature of committee member"})
+			continue
+		}
+
+		if !sig.Signature.Verify(sigHash, sig.PubKey) {
+			rejected = append(rejected, rejectedCovenantSignature{pubKey: sig.PubKey, reason: "invalid signature of unbonding transaction"})
+			continue
+		}
+
+		signed[pubKeyToString(sig.PubKey)] = struct{}{}
+		valid = append(valid, sig)
+	}
+
+	if len(valid) < int(quorum) {
+		return valid, rejected, fmt.Errorf("%w: have %d, need %d", ErrCovenantQuorumNotMet, len(valid), quorum)
+	}
+
+	return valid, rejected, nil
+}
+
+// verifiedUnbondingSignatures returns covenant signatures of unbonding
+// transaction of the delegation which are valid signatures of covenant
+// committee of its params version. Unbonding path is rebuilt from delegation
+// data on Babylon.
+func (app *App) verifiedUnbondingSignatures(
+	stakingTxHash *chainhash.Hash,
+	di *btcstktypes.QueryBTCDelegationResponse,
+	udi *cl.UndelegationInfo,
+) ([]cl.CovenantSignatureInfo, *cl.BtcStakingParams, error) {
+	del := di.BtcDelegation
+	if del == nil {
+		return nil, nil, fmt.Errorf("delegation %s has no btc delegation data", stakingTxHash)
+	}
+
+	if udi.UnbondingTransaction == nil {
+		return nil, nil, fmt.Errorf("delegation %s has no unbonding transaction", stakingTxHash)
+	}
+
+	params, err := app.babylonClient.ParamsByVersion(del.ParamsVersion)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error getting params version %d: %w", del.ParamsVersion, err)
+	}
+
+	stakingTx, _, err := bbn.NewBTCTxFromHex(del.StakingTxHex)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to decode staking transaction: %w", err)
+	}
+
+	if int(del.StakingOutputIdx) >= len(stakingTx.TxOut) {
+		return nil, nil, fmt.Errorf("staking output index %d out of range", del.StakingOutputIdx)
+	}
+	stakingOutput := stakingTx.TxOut[del.StakingOutputIdx]
+
+	stakerPk, err := del.BtcPk.ToBTCPK()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to parse staker public key: %w", err)
+	}
+
+	fpBtcPubkeys, err := convertFpBtcPkToBtcPk(del.FpBtcPkList)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error converting fpBtcPkList to btcPkList: %w", err)
+	}
+
+	stakingInfo, err := staking.BuildStakingInfo(
+		stakerPk,
+		fpBtcPubkeys,
+		params.CovenantPks,
+		params.CovenantQuruomThreshold,
+		uint16(del.StakingTime),
+		btcutil.Amount(stakingOutput.Value),
+		app.network,
+	)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to build staking info: %w", err)
+	}
+
+	if !bytes.Equal(stakingInfo.StakingOutput.PkScript, stakingOutput.PkScript) {
+		return nil, nil, fmt.Errorf("staking output script does not match delegation data")
+	}
+
+	unbondingPath, err := stakingInfo.UnbondingPathSpendInfo()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to build unbonding path spend info: %w", err)
+	}
+
+	valid, rejected, err := verifyCovenantUnbondingSignatures(
+		udi.UnbondingTransaction,
+		stakingOutput,
+		unbondingPath,
+		params.CovenantPks,
+		params.CovenantQuruomThreshold,
+		udi.CovenantUnbondingSignatures,
+	)
+
+	app.logRejectedCovenantSignatures(stakingTxHash, rejected)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return valid, params, nil
+}
+
+func (app *App) logRejectedCovenantSignatures(stakingTxHash *chainhash.Hash, rejected []rejectedCovenantSignature) {
+	for _, r := range rejected {
+		var pk string
+		if r.pubKey != nil {
+			pk = pubKeyToString(r.pubKey)
+		}
+
+		app.logger.WithFields(logrus.Fields{
+			"stakingTxHash": stakingTxHash,
+			"covenantPk":    pk,
+			"reason":        r.reason,
+		}).Warn("Rejected covenant unbonding signature")
+	}
+}
+
+// storeUnbondingSignatures stores verified covenant signatures of unbonding
+// transaction together with keys of covenant committee of its params version
+func (app *App) storeUnbondingSignatures(
+	stakingTxHash *chainhash.Hash,
+	paramsVersion uint32,
+	params *cl.BtcStakingParams,
+	sigs []cl.CovenantSignatureInfo,
+) error {
+	stored := &stakerdb.UnbondingSignatures{
+		ParamsVersion: paramsVersion,
+		Quorum:        params.CovenantQuruomThreshold,
+		ReceivedAt:    time.Now(),
+	}
+
+	for _, pk := range params.CovenantPks {
+		stored.Committee = append(stored.Committee, schnorr.SerializePubKey(pk))
+	}
+
+	for _, sig := range sigs {
+		stored.Signatures = append(stored.Signatures, stakerdb.CovenantSignature{
+			CovenantPk: schnorr.SerializePubKey(sig.PubKey),
+			Signature:  sig.Signature.Serialize(),
+		})
+	}
+
+	return app.txTracker.SetTxUnbondingSignaturesReceived(stakingTxHash, stored)
+}
+
+// UnbondingSignatures returns verified covenant signatures of unbonding
+// transaction of the delegation, nil if quorum of them was not received yet
+func (app *App) UnbondingSignatures(stakingTxHash *chainhash.Hash) (*stakerdb.UnbondingSignatures, error) {
+	return app.txTracker.GetTxUnbondingSignatures(stakingTxHash)
+}
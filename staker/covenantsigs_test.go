@@ -0,0 +1,95 @@
+package staker
+
+import (
+	"testing"
+
+	staking "github.com/babylonlabs-io/babylon/v4/btcstaking"
+	cl "github.com/babylonlabs-io/btc-staker/babylonclient"
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifyCovenantUnbondingSignatures(t *testing.T) {
+	t.Parallel()
+
+	newKey := func() *btcec.PrivateKey {
+		key, err := btcec.NewPrivateKey()
+		require.NoError(t, err)
+		return key
+	}
+
+	stakerKey, fpKey, outsiderKey := newKey(), newKey(), newKey()
+	covenantKeys := []*btcec.PrivateKey{newKey(), newKey(), newKey()}
+	covenantPks := make([]*btcec.PublicKey, len(covenantKeys))
+	for i, key := range covenantKeys {
+		covenantPks[i] = key.PubKey()
+	}
+
+	stakingInfo, err := staking.BuildStakingInfo(
+		stakerKey.PubKey(),
+		[]*btcec.PublicKey{fpKey.PubKey()},
+		covenantPks,
+		2,
+		1000,
+		100_000,
+		&chaincfg.SimNetParams,
+	)
+	require.NoError(t, err)
+
+	unbondingPath, err := stakingInfo.UnbondingPathSpendInfo()
+	require.NoError(t, err)
+
+	unbondingTx := wire.NewMsgTx(2)
+	unbondingTx.AddTxIn(wire.NewTxIn(wire.NewOutPoint(&chainhash.Hash{1}, 0), nil, nil))
+	unbondingTx.AddTxOut(wire.NewTxOut(99_000, stakingInfo.StakingOutput.PkScript))
+
+	sign := func(key *btcec.PrivateKey, tx *wire.MsgTx) cl.CovenantSignatureInfo {
+		sig, err := staking.SignTxWithOneScriptSpendInputFromTapLeaf(
+			tx, stakingInfo.StakingOutput, key, unbondingPath.RevealedLeaf,
+		)
+		require.NoError(t, err)
+		return cl.CovenantSignatureInfo{Signature: sig, PubKey: key.PubKey()}
+	}
+
+	otherTx := unbondingTx.Copy()
+	otherTx.TxOut[0].Value = 98_000
+
+	// signature of other transaction, by key outside of committee, and signature
+	// assigned to other committee member
+	wrongTxSig := sign(covenantKeys[1], otherTx)
+	outsiderSig := sign(outsiderKey, unbondingTx)
+	swappedSig := sign(covenantKeys[2], unbondingTx)
+	swappedSig.PubKey = covenantKeys[1].PubKey()
+
+	valid, rejected, err := verifyCovenantUnbondingSignatures(
+		unbondingTx, stakingInfo.StakingOutput, unbondingPath, covenantPks, 2,
+		[]cl.CovenantSignatureInfo{
+			sign(covenantKeys[0], unbondingTx),
+			wrongTxSig,
+			outsiderSig,
+			swappedSig,
+			sign(covenantKeys[0], unbondingTx),
+			sign(covenantKeys[2], unbondingTx),
+		},
+	)
+	require.NoError(t, err)
+	require.Len(t, valid, 2)
+	require.Equal(t, covenantPks[0], valid[0].PubKey)
+	require.Equal(t, covenantPks[2], valid[1].PubKey)
+	require.Len(t, rejected, 4)
+	require.Equal(t, "key is not in covenant committee", rejected[1].reason)
+	require.Equal(t, "duplicate signature of committee member", rejected[3].reason)
+
+	// invalid and duplicate signatures do not count towards quorum
+	valid, _, err = verifyCovenantUnbondingSignatures(
+		unbondingTx, stakingInfo.StakingOutput, unbondingPath, covenantPks, 2,
+		[]cl.CovenantSignatureInfo{
+			sign(covenantKeys[0], unbondingTx), sign(covenantKeys[0], unbondingTx), wrongTxSig, outsiderSig,
+		},
+	)
+	require.ErrorIs(t, err, ErrCovenantQuorumNotMet)
+	require.Len(t, valid, 1)
+}
@@ -0,0 +1,244 @@
+package staker
+
+import (
+	"fmt"
+
+	staking "github.com/babylonlabs-io/babylon/v4/btcstaking"
+	"github.com/btcsuite/btcd/blockchain"
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/mempool"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
+)
+
+const (
+	// sizes of witness elements used in place of signatures of estimated
+	// transactions, ecdsa signatures are counted with maximal length
+	estimateEcdsaSigLen   = 73
+	estimateCompressedLen = 33
+	// nested p2wpkh signature script pushes 22 bytes witness program
+	estimateNestedSigScriptLen = 23
+)
+
+// TxSizeEstimate is the expected size of signed transaction
+type TxSizeEstimate struct {
+	VSize      int64
+	Weight     int64
+	NumInputs  int
+	NumOutputs int
+	Fee        btcutil.Amount
+}
+
+// FeeRate returns fee rate of the transaction in sat/vB
+func (e *TxSizeEstimate) FeeRate() float64 {
+	if e.VSize == 0 {
+		return 0
+	}
+	return float64(e.Fee) / float64(e.VSize)
+}
+
+// StakeEstimate previews transactions of a stake request without funding
+// or sending them
+type StakeEstimate struct {
+	FeeRate     chainfee.SatPerKVByte
+	StakingTx   TxSizeEstimate
+	UnbondingTx TxSizeEstimate
+}
+
+// EstimateStake builds staking transaction the way StakeFunds would and
+// returns expected sizes and fees of staking and unbonding transactions.
+// Inputs of the staking transaction are not reserved, so they can differ from
+// inputs selected by later stake request.
+func (app *App) EstimateStake(
+	stakerAddress btcutil.Address,
+	stakingAmount btcutil.Amount,
+	fpPks []*btcec.PublicKey,
+	stakingTimeBlocks uint16,
+	reference string,
+	fee FeeSelection,
+) (*StakeEstimate, error) {
+	if err := app.validateReference(reference); err != nil {
+		return nil, err
+	}
+
+	if len(fpPks) == 0 {
+		return nil, fmt.Errorf("no finality providers public keys provided")
+	}
+
+	if haveDuplicates(fpPks) {
+		return nil, fmt.Errorf("duplicate finality provider public keys provided")
+	}
+
+	params, err := app.babylonClient.Params()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get params: %w", err)
+	}
+
+	if stakingTimeBlocks < params.MinStakingTime || stakingTimeBlocks > params.MaxStakingTime {
+		return nil, fmt.Errorf("staking time %d is not in range [%d, %d]",
+			stakingTimeBlocks, params.MinStakingTime, params.MaxStakingTime)
+	}
+
+	if stakingAmount < params.MinStakingValue || stakingAmount > params.MaxStakingValue {
+		return nil, fmt.Errorf("staking amount %d is not in range [%d, %d]",
+			stakingAmount, params.MinStakingValue, params.MaxStakingValue)
+	}
+
+	if stakingAmount <= params.UnbondingFee {
+		return nil, fmt.Errorf("staking amount %d is not above unbonding fee %d", stakingAmount, params.UnbondingFee)
+	}
+
+	stakerPubKey, err := app.wc.AddressPublicKey(stakerAddress)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get staker public key: %w", err)
+	}
+
+	stakingInfo, err := staking.BuildStakingInfo(
+		stakerPubKey,
+		fpPks,
+		params.CovenantPks,
+		params.CovenantQuruomThreshold,
+		stakingTimeBlocks,
+		stakingAmount,
+		app.network,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build staking info: %w", err)
+	}
+
+	feeRate, err := app.resolveFeeRate(fee)
+	if err != nil {
+		return nil, err
+	}
+
+	stakingTx, stakingOutputIdx, err := app.createStakingTx(
+		stakingInfo.StakingOutput, btcutil.Amount(feeRate), stakerAddress, reference,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build staking transaction: %w", err)
+	}
+
+	stakingEstimate, err := app.estimateStakingTxSize(stakingTx)
+	if err != nil {
+		return nil, err
+	}
+
+	unbondingEstimate, err := estimateUnbondingTxSize(
+		stakingTx, stakingOutputIdx, stakingInfo, params.CovenantQuruomThreshold,
+		len(params.CovenantPks), params.UnbondingFee,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &StakeEstimate{
+		FeeRate:     feeRate,
+		StakingTx:   *stakingEstimate,
+		UnbondingTx: *unbondingEstimate,
+	}, nil
+}
+
+// estimateStakingTxSize estimates size of unsigned staking transaction funded
+// by the wallet, by adding placeholder witnesses of wallet inputs
+func (app *App) estimateStakingTxSize(stakingTx *wire.MsgTx) (*TxSizeEstimate, error) {
+	utxos, err := app.wc.ListOutputs(false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list wallet outputs: %w", err)
+	}
+
+	prevOuts := make(map[wire.OutPoint]*wire.TxOut, len(utxos))
+	for _, u := range utxos {
+		prevOuts[u.OutPoint] = wire.NewTxOut(int64(u.Amount), u.PkScript)
+	}
+
+	tx := stakingTx.Copy()
+
+	var inputsValue btcutil.Amount
+	for _, in := range tx.TxIn {
+		prevOut, ok := prevOuts[in.PreviousOutPoint]
+		if !ok {
+			return nil, fmt.Errorf("input %s is not a wallet output", in.PreviousOutPoint)
+		}
+		inputsValue += btcutil.Amount(prevOut.Value)
+
+		switch {
+		case txscript.IsPayToTaproot(prevOut.PkScript):
+			in.Witness = wire.TxWitness{make([]byte, schnorr.SignatureSize)}
+		case txscript.IsPayToWitnessPubKeyHash(prevOut.PkScript):
+			in.Witness = wire.TxWitness{make([]byte, estimateEcdsaSigLen), make([]byte, estimateCompressedLen)}
+		case txscript.IsPayToScriptHash(prevOut.PkScript):
+			in.SignatureScript = make([]byte, estimateNestedSigScriptLen)
+			in.Witness = wire.TxWitness{make([]byte, estimateEcdsaSigLen), make([]byte, estimateCompressedLen)}
+		default:
+			return nil, fmt.Errorf("unsupported input script type of %s", in.PreviousOutPoint)
+		}
+	}
+
+	var outputsValue btcutil.Amount
+	for _, out := range tx.TxOut {
+		outputsValue += btcutil.Amount(out.Value)
+	}
+
+	return txSizeEstimate(tx, inputsValue-outputsValue), nil
+}
+
+// estimateUnbondingTxSize estimates size of unbonding transaction spending
+// staking output through unbonding path, signed by staker and covenant quorum
+func estimateUnbondingTxSize(
+	stakingTx *wire.MsgTx,
+	stakingOutputIdx uint32,
+	stakingInfo *staking.StakingInfo,
+	covenantQuorum uint32,
+	numCovenants int,
+	unbondingFee btcutil.Amount,
+) (*TxSizeEstimate, error) {
+	spendInfo, err := stakingInfo.UnbondingPathSpendInfo()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build unbonding path spend info: %w", err)
+	}
+
+	controlBlock, err := spendInfo.ControlBlock.ToBytes()
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize control block: %w", err)
+	}
+
+	// covenants which do not sign put empty element to the witness
+	witness := make(wire.TxWitness, 0, numCovenants+3)
+	for i := 0; i < numCovenants; i++ {
+		if i < int(covenantQuorum) {
+			witness = append(witness, make([]byte, schnorr.SignatureSize))
+		} else {
+			witness = append(witness, []byte{})
+		}
+	}
+	witness = append(witness,
+		make([]byte, schnorr.SignatureSize),
+		spendInfo.RevealedLeaf.Script,
+		controlBlock,
+	)
+
+	stakingTxHash := stakingTx.TxHash()
+	unbondingIn := wire.NewTxIn(wire.NewOutPoint(&stakingTxHash, stakingOutputIdx), nil, witness)
+
+	// unbonding output has the same script type and size as staking output
+	stakingOutput := stakingTx.TxOut[stakingOutputIdx]
+	unbondingTx := wire.NewMsgTx(2)
+	unbondingTx.AddTxIn(unbondingIn)
+	unbondingTx.AddTxOut(wire.NewTxOut(stakingOutput.Value-int64(unbondingFee), stakingOutput.PkScript))
+
+	return txSizeEstimate(unbondingTx, unbondingFee), nil
+}
+
+func txSizeEstimate(tx *wire.MsgTx, fee btcutil.Amount) *TxSizeEstimate {
+	btcTx := btcutil.NewTx(tx)
+	return &TxSizeEstimate{
+		VSize:      mempool.GetTxVirtualSize(btcTx),
+		Weight:     blockchain.GetTransactionWeight(btcTx),
+		NumInputs:  len(tx.TxIn),
+		NumOutputs: len(tx.TxOut),
+		Fee:        fee,
+	}
+}
@@ -0,0 +1,71 @@
+package staker_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/babylonlabs-io/btc-staker/staker"
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/mempool"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/stretchr/testify/require"
+)
+
+func vsize(tx *wire.MsgTx) int64 {
+	return mempool.GetTxVirtualSize(btcutil.NewTx(tx))
+}
+
+func TestEstimateStakeMatchesSentTransactions(t *testing.T) {
+	t.Parallel()
+
+	sim, app, addr := startSimulatedApp(t)
+	fp := sim.Babylon.AddFinalityProvider()
+	fee := staker.FeeSelection{FeeRate: 2}
+
+	estimate, err := app.EstimateStake(
+		addr, 100_000, []*btcec.PublicKey{fp}, withdrawableTestStakingTime, "", fee,
+	)
+	require.NoError(t, err)
+	require.Equal(t, 1, estimate.StakingTx.NumInputs)
+	require.Equal(t, 2, estimate.StakingTx.NumOutputs)
+	require.Equal(t, 1, estimate.UnbondingTx.NumInputs)
+	require.Equal(t, 1, estimate.UnbondingTx.NumOutputs)
+
+	// estimate does not reserve or send anything
+	require.Empty(t, sim.Chain.MempoolTxs())
+
+	stakingTxHash, err := app.StakeFunds(
+		addr, 100_000, []*btcec.PublicKey{fp}, withdrawableTestStakingTime, "", "", fee,
+	)
+	require.NoError(t, err)
+	require.NoError(t, sim.Babylon.SignDelegation(stakingTxHash))
+	require.Eventually(t, func() bool {
+		return sim.Chain.InMempool(stakingTxHash)
+	}, 10*time.Second, 50*time.Millisecond)
+
+	stakingTx, err := sim.Chain.Tx(stakingTxHash)
+	require.NoError(t, err)
+	require.Equal(t, vsize(stakingTx), estimate.StakingTx.VSize)
+	require.Equal(t, estimate.StakingTx.NumInputs, len(stakingTx.TxIn))
+	require.Equal(t, estimate.StakingTx.NumOutputs, len(stakingTx.TxOut))
+	stakingFee, ok := sim.Chain.MempoolFee(stakingTxHash)
+	require.True(t, ok)
+	require.Equal(t, stakingFee, estimate.StakingTx.Fee)
+
+	sim.Chain.MineBlocks(3)
+	require.NoError(t, sim.Babylon.ActivateDelegation(stakingTxHash))
+
+	unbondingTxHash, err := app.UnbondStaking(*stakingTxHash, staker.FeeSelection{})
+	require.NoError(t, err)
+	require.Eventually(t, func() bool {
+		return sim.Chain.InMempool(unbondingTxHash)
+	}, 10*time.Second, 50*time.Millisecond)
+
+	unbondingTx, err := sim.Chain.Tx(unbondingTxHash)
+	require.NoError(t, err)
+	require.Equal(t, vsize(unbondingTx), estimate.UnbondingTx.VSize)
+	unbondingFee, ok := sim.Chain.MempoolFee(unbondingTxHash)
+	require.True(t, ok)
+	require.Equal(t, unbondingFee, estimate.UnbondingTx.Fee)
+}
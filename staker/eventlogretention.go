@@ -0,0 +1,91 @@
+package staker
+
+import (
+	"time"
+
+	"github.com/babylonlabs-io/btc-staker/stakerdb"
+	"github.com/sirupsen/logrus"
+)
+
+// handleEventLogRetention periodically removes old events from the persisted
+// event log and updates its size metrics
+func (app *App) handleEventLogRetention() {
+	cfg := app.config.EventLogConfig
+
+	ticker := time.NewTicker(cfg.PruneInterval)
+	defer ticker.Stop()
+
+	for {
+		app.applyEventLogRetention()
+
+		select {
+		case <-ticker.C:
+		case <-app.quit:
+			return
+		}
+	}
+}
+
+// applyEventLogRetention removes events selected by configured retention.
+// Events not delivered to the webhook yet are kept, so that slow or
+// unavailable webhook never loses events. Failure is only logged and
+// retention is retried in the next interval.
+func (app *App) applyEventLogRetention() {
+	cfg := app.config.EventLogConfig
+
+	retention := stakerdb.ChangelogRetention{
+		MaxChanges: cfg.MaxEvents,
+		MaxAge:     cfg.MaxAge,
+		CompactAge: cfg.CompactAge,
+	}
+
+	var webhookCursor uint64
+	if app.webhookEnabled() {
+		cursor, err := app.txTracker.WebhookCursor()
+		if err != nil {
+			app.logger.WithField("err", err).Error("Failed to get webhook cursor, event log retention skipped")
+			return
+		}
+		webhookCursor = cursor
+		retention.KeepFromSeq = cursor + 1
+	}
+
+	removed, stats, err := app.txTracker.PruneChangelog(retention, time.Now())
+	if removed > 0 {
+		app.m.EventLogPrunedEvents.Add(float64(removed))
+	}
+	if err != nil {
+		app.logger.WithField("err", err).Error("Failed to apply event log retention")
+		return
+	}
+
+	app.m.EventLogEvents.Set(float64(stats.Changes))
+	app.m.EventLogSizeBytes.Set(float64(stats.SizeBytes))
+	if stats.Changes > 0 {
+		app.m.EventLogOldestEventAge.Set(time.Since(stats.OldestTimestamp).Seconds())
+	} else {
+		app.m.EventLogOldestEventAge.Set(0)
+	}
+
+	if app.webhookEnabled() {
+		pending := stats.LastSeq - min(webhookCursor, stats.LastSeq)
+		app.m.WebhookPendingEvents.Set(float64(pending))
+
+		if cfg.MaxEvents > 0 && stats.Changes > cfg.MaxEvents {
+			app.logger.WithFields(logrus.Fields{
+				"events":        stats.Changes,
+				"maxEvents":     cfg.MaxEvents,
+				"pendingEvents": pending,
+			}).Warn("Event log exceeds retention limit, events not delivered to webhook are kept")
+		}
+	}
+
+	if removed > 0 {
+		app.logger.WithFields(logrus.Fields{
+			"removed":   removed,
+			"events":    stats.Changes,
+			"sizeBytes": stats.SizeBytes,
+			"oldestSeq": stats.OldestSeq,
+		}).Info("Removed old events from event log")
+	}
+}
@@ -1,6 +1,7 @@
 package staker
 
 import (
+	"github.com/btcsuite/btcd/btcutil"
 	"github.com/btcsuite/btcd/chaincfg/chainhash"
 	"github.com/sirupsen/logrus"
 )
@@ -53,6 +54,8 @@ type unbondingTxConfirmedOnBtcEvent struct {
 	stakingTxHash chainhash.Hash
 	blockHash     chainhash.Hash
 	blockHeight   uint32
+	// unbondingTime is the timelock of the unbonding output in blocks
+	unbondingTime uint16
 }
 
 func (event *unbondingTxConfirmedOnBtcEvent) EventID() chainhash.Hash {
@@ -67,6 +70,7 @@ func (event *unbondingTxConfirmedOnBtcEvent) EventDesc() string {
 // spend stake tx is confirmed on Bitcoin
 type spendStakeTxConfirmedOnBtcEvent struct {
 	stakingTxHash chainhash.Hash
+	spendTxValue  btcutil.Amount
 }
 
 func (event *spendStakeTxConfirmedOnBtcEvent) EventID() chainhash.Hash {
@@ -0,0 +1,145 @@
+package staker
+
+import (
+	"fmt"
+
+	"github.com/babylonlabs-io/btc-staker/stakerdb"
+	"github.com/babylonlabs-io/btc-staker/walletcontroller"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/sirupsen/logrus"
+)
+
+// recordCreationHeight stores current btc height as creation height of the
+// tracked transaction. It is used only for expiry, so failure is only logged.
+func (app *App) recordCreationHeight(stakingTxHash *chainhash.Hash) {
+	height := app.currentBestBlockHeight.Load()
+	if err := app.txTracker.SetTransactionCreationHeight(stakingTxHash, height); err != nil {
+		app.logger.WithFields(logrus.Fields{
+			"stakingTxHash": stakingTxHash,
+			"err":           err,
+		}).Error("Failed to record staking transaction creation height")
+	}
+}
+
+// recordBroadcastHeight stores current btc height as broadcast height of the
+// staking transaction, unless it was broadcast before. Expiry counts from it,
+// so failure is only logged.
+func (app *App) recordBroadcastHeight(stakingTxHash *chainhash.Hash) {
+	height := app.currentBestBlockHeight.Load()
+	if _, err := app.txTracker.SetTransactionBroadcastHeight(stakingTxHash, height); err != nil {
+		app.logger.WithFields(logrus.Fields{
+			"stakingTxHash": stakingTxHash,
+			"err":           err,
+		}).Error("Failed to record staking transaction broadcast height")
+	}
+}
+
+// expireUnconfirmedTransactions abandons tracked staking transactions which were
+// not confirmed on btc within configured number of blocks after broadcast.
+// Delegations registered on Babylon before broadcast do not expire while they
+// wait for covenant signatures.
+func (app *App) expireUnconfirmedTransactions() error {
+	expiryBlocks := app.config.StakerConfig.UnconfirmedTxExpiryBlocks
+	if expiryBlocks == 0 {
+		return nil
+	}
+
+	txs, err := app.reservedStakingTransactions()
+	if err != nil {
+		return err
+	}
+
+	currentHeight := app.currentBestBlockHeight.Load()
+
+	for txHash, storedTx := range txs {
+		txHash := txHash
+
+		if err := app.maybeExpireTransaction(&txHash, storedTx, currentHeight, expiryBlocks); err != nil {
+			app.logger.WithFields(logrus.Fields{
+				"stakingTxHash": txHash,
+				"err":           err,
+			}).Warn("Failed to check staking transaction expiry")
+		}
+	}
+
+	return nil
+}
+
+func (app *App) maybeExpireTransaction(
+	txHash *chainhash.Hash,
+	storedTx *stakerdb.StoredTransaction,
+	currentHeight uint32,
+	expiryBlocks uint32,
+) error {
+	broadcastAt, found, err := app.txTracker.GetTransactionBroadcastHeight(txHash)
+	if err != nil {
+		return err
+	}
+
+	if found && (currentHeight < broadcastAt || currentHeight-broadcastAt < expiryBlocks) {
+		return nil
+	}
+
+	if len(storedTx.StakingTx.TxOut) == 0 {
+		return fmt.Errorf("staking transaction has no outputs")
+	}
+
+	_, status, err := app.wc.TxDetails(txHash, storedTx.StakingTx.TxOut[0].PkScript)
+	if err != nil {
+		return fmt.Errorf("failed to get staking tx details: %w", err)
+	}
+
+	if status == walletcontroller.TxInChain {
+		return nil
+	}
+
+	if !found {
+		if status == walletcontroller.TxNotFound {
+			// not broadcast yet, delegation waits for covenant signatures
+			return nil
+		}
+
+		// broadcast before broadcast heights were recorded, start counting
+		// from now
+		_, err := app.txTracker.SetTransactionBroadcastHeight(txHash, currentHeight)
+		return err
+	}
+
+	reason := fmt.Sprintf("staking transaction not confirmed within %d blocks", expiryBlocks)
+	remediation := "fee rate was likely too low, stake again with higher fee rate"
+	if status == walletcontroller.TxInMemPool {
+		remediation = "fee rate was likely too low, cancel staking transaction still in mempool with cancel-stake and stake again with higher fee rate"
+	}
+
+	if status == walletcontroller.TxInMemPool && app.config.StakerConfig.CancelExpiredTransactions {
+		cancelTxHash, fee, err := app.sendCancelTx(storedTx)
+		if err != nil {
+			// still abandon the transaction, if staking transaction confirms
+			// after all it will be visible on babylon
+			app.logger.WithFields(logrus.Fields{
+				"stakingTxHash": txHash,
+				"err":           err,
+			}).Error("Failed to cancel expired staking transaction")
+		} else {
+			reason = fmt.Sprintf("%s, cancelled by transaction %s", reason, cancelTxHash)
+			remediation = "fee rate was likely too low, stake again with higher fee rate"
+			app.logger.WithFields(logrus.Fields{
+				"stakingTxHash": txHash,
+				"cancelTxHash":  cancelTxHash,
+				"fee":           fee,
+			}).Info("Sent transaction cancelling expired staking transaction")
+		}
+	}
+
+	if err := app.failStakingTx(txHash, newFailureNote(stakerdb.FailureSourceStaker, reason, remediation)); err != nil {
+		return err
+	}
+
+	app.logger.WithFields(logrus.Fields{
+		"stakingTxHash": txHash,
+		"broadcastAt":   broadcastAt,
+		"currentHeight": currentHeight,
+	}).Warn("Staking transaction expired, abandoning it")
+
+	return nil
+}
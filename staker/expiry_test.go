@@ -0,0 +1,126 @@
+package staker
+
+import (
+	"testing"
+
+	"github.com/babylonlabs-io/btc-staker/stakercfg"
+	"github.com/babylonlabs-io/btc-staker/stakerdb"
+	"github.com/babylonlabs-io/btc-staker/testutil/mocks"
+	"github.com/babylonlabs-io/btc-staker/walletcontroller"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+const testExpiryBlocks = 10
+
+func newExpiryTestApp(t *testing.T, wc *mocks.MockWalletController) (*App, *stakerdb.TrackedTransactionStore) {
+	cfg := stakercfg.DefaultConfig()
+	cfg.StakerConfig.UnconfirmedTxExpiryBlocks = testExpiryBlocks
+	store := newArchiveTestStore(t)
+
+	return &App{
+		config:    &cfg,
+		logger:    logrus.New(),
+		txTracker: store,
+		wc:        wc,
+		statuses:  newDelegationStatusCache(),
+	}, store
+}
+
+func TestPreApprovalDelegationDoesNotExpireBeforeBroadcast(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	wc := mocks.NewMockWalletController(ctrl)
+	app, store := newExpiryTestApp(t, wc)
+
+	// delegation registered on Babylon waits for covenant signatures, its
+	// staking transaction is not broadcast
+	tx := genReplicaTestTransaction(t, 10_000)
+	require.NoError(t, store.AddTransactionSentToBabylon(tx.StakingTx, tx.StakerAddress))
+	txHash := tx.StakingTx.TxHash()
+	app.currentBestBlockHeight.Store(100)
+	app.recordCreationHeight(&txHash)
+
+	wc.EXPECT().TxDetails(&txHash, gomock.Any()).Return(nil, walletcontroller.TxNotFound, nil)
+
+	app.currentBestBlockHeight.Store(100 + 2*testExpiryBlocks)
+	require.NoError(t, app.expireUnconfirmedTransactions())
+
+	failure, err := store.GetTransactionFailure(&txHash)
+	require.NoError(t, err)
+	require.Nil(t, failure)
+	reserved, err := store.ListReservedOutpoints()
+	require.NoError(t, err)
+	require.NotEmpty(t, reserved)
+}
+
+func TestBroadcastDelegationExpiresCountingFromBroadcast(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	wc := mocks.NewMockWalletController(ctrl)
+	app, store := newExpiryTestApp(t, wc)
+
+	tx := genReplicaTestTransaction(t, 10_000)
+	require.NoError(t, store.AddTransactionSentToBabylon(tx.StakingTx, tx.StakerAddress))
+	txHash := tx.StakingTx.TxHash()
+	app.currentBestBlockHeight.Store(100)
+	app.recordCreationHeight(&txHash)
+
+	// covenant signatures took longer than expiry, broadcast happens after it
+	app.currentBestBlockHeight.Store(100 + 2*testExpiryBlocks)
+	app.recordBroadcastHeight(&txHash)
+	// height of the first broadcast is kept
+	app.currentBestBlockHeight.Store(100 + 3*testExpiryBlocks)
+	app.recordBroadcastHeight(&txHash)
+
+	// not expired yet, wallet is not queried
+	app.currentBestBlockHeight.Store(100 + 3*testExpiryBlocks - 1)
+	require.NoError(t, app.expireUnconfirmedTransactions())
+
+	failure, err := store.GetTransactionFailure(&txHash)
+	require.NoError(t, err)
+	require.Nil(t, failure)
+
+	wc.EXPECT().TxDetails(&txHash, gomock.Any()).Return(nil, walletcontroller.TxNotFound, nil)
+
+	app.currentBestBlockHeight.Store(100 + 3*testExpiryBlocks)
+	require.NoError(t, app.expireUnconfirmedTransactions())
+
+	failure, err = store.GetTransactionFailure(&txHash)
+	require.NoError(t, err)
+	require.NotNil(t, failure)
+	require.Contains(t, failure.Reason, "not confirmed within")
+	reserved, err := store.ListReservedOutpoints()
+	require.NoError(t, err)
+	require.Empty(t, reserved)
+}
+
+func TestBroadcastWithoutHeightStartsCountingFromNow(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	wc := mocks.NewMockWalletController(ctrl)
+	app, store := newExpiryTestApp(t, wc)
+
+	// broadcast before broadcast heights were recorded
+	tx := genReplicaTestTransaction(t, 10_000)
+	require.NoError(t, store.AddTransactionSentToBabylon(tx.StakingTx, tx.StakerAddress))
+	txHash := tx.StakingTx.TxHash()
+
+	wc.EXPECT().TxDetails(&txHash, gomock.Any()).Return(nil, walletcontroller.TxInMemPool, nil)
+
+	app.currentBestBlockHeight.Store(200)
+	require.NoError(t, app.expireUnconfirmedTransactions())
+
+	height, found, err := store.GetTransactionBroadcastHeight(&txHash)
+	require.NoError(t, err)
+	require.True(t, found)
+	require.Equal(t, uint32(200), height)
+
+	failure, err := store.GetTransactionFailure(&txHash)
+	require.NoError(t, err)
+	require.Nil(t, failure)
+}
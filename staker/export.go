@@ -0,0 +1,192 @@
+package staker
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	bct "github.com/babylonlabs-io/babylon/v4/client/babylonclient"
+	cl "github.com/babylonlabs-io/btc-staker/babylonclient"
+	"github.com/babylonlabs-io/btc-staker/stakerdb"
+	"github.com/babylonlabs-io/btc-staker/walletcontroller"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/sirupsen/logrus"
+)
+
+// Kinds of Babylon transactions sent for the delegation
+const (
+	BabylonTxCreateDelegation = "create_delegation"
+	BabylonTxStakeExpansion   = "stake_expansion"
+	BabylonTxInclusionProof   = "inclusion_proof"
+)
+
+// StakingTxConfirmation is the btc block which includes staking transaction
+// together with proof of inclusion
+type StakingTxConfirmation struct {
+	BlockHash   chainhash.Hash
+	BlockHeight uint32
+	TxIndex     uint32
+	// InclusionProof are merkle nodes proving inclusion of staking
+	// transaction in the block
+	InclusionProof []byte
+}
+
+// DelegationExport holds all data staker knows about single delegation
+type DelegationExport struct {
+	StoredTx      *stakerdb.StoredTransaction
+	Tenant        string
+	Labels        map[string]string
+	CreatedHeight uint32
+	Failure       *stakerdb.TransactionFailure
+	Fees          *stakerdb.DelegationFees
+	Timing        *stakerdb.CovenantQuorumTiming
+	// time unbonding was requested, zero if there is no pending request
+	UnbondRequestedAt time.Time
+	WatchedTxs        []*stakerdb.WatchedTransaction
+	BabylonTxs        []stakerdb.BabylonTx
+	StatusHistory     []stakerdb.StatusSnapshot
+	Changes           []stakerdb.Change
+
+	// StakingTxConfirmation is nil if staking transaction is not confirmed
+	StakingTxConfirmation *StakingTxConfirmation
+	// ConfirmationErr is set if confirmation could not be queried from btc
+	ConfirmationErr error
+
+	// BabylonDelegation is json encoded delegation as returned by Babylon,
+	// including covenant signatures. Nil if delegation is not on Babylon.
+	BabylonDelegation json.RawMessage
+	// BabylonErr is set if delegation could not be queried from Babylon
+	BabylonErr error
+}
+
+// ExportDelegation collects everything staker knows about the delegation, so
+// that it can be attached to support tickets and audits. Failures of btc and
+// Babylon queries are reported in the export instead of failing it, as export
+// is most useful when something does not work.
+func (app *App) ExportDelegation(stakingTxHash *chainhash.Hash) (*DelegationExport, error) {
+	storedTx, err := app.txTracker.GetTransaction(stakingTxHash)
+	if err != nil {
+		return nil, err
+	}
+
+	export := &DelegationExport{StoredTx: storedTx}
+
+	if export.Tenant, err = app.txTracker.GetTransactionTenant(stakingTxHash); err != nil {
+		return nil, err
+	}
+
+	if export.Labels, err = app.txTracker.GetTransactionLabels(stakingTxHash); err != nil {
+		return nil, err
+	}
+
+	if export.CreatedHeight, _, err = app.txTracker.GetTransactionCreationHeight(stakingTxHash); err != nil {
+		return nil, err
+	}
+
+	if export.Failure, err = app.txTracker.GetTransactionFailure(stakingTxHash); err != nil {
+		return nil, err
+	}
+
+	if export.Fees, err = app.txTracker.GetDelegationFees(stakingTxHash); err != nil {
+		return nil, err
+	}
+
+	if export.Timing, err = app.txTracker.GetCovenantQuorumTiming(stakingTxHash); err != nil {
+		return nil, err
+	}
+
+	unbondRequests, err := app.txTracker.ListUnbondRequests()
+	if err != nil {
+		return nil, err
+	}
+	export.UnbondRequestedAt = unbondRequests[*stakingTxHash]
+
+	if export.WatchedTxs, err = app.txTracker.WatchedTransactionsOf(stakingTxHash); err != nil {
+		return nil, err
+	}
+
+	if export.BabylonTxs, err = app.txTracker.GetBabylonTxs(stakingTxHash); err != nil {
+		return nil, err
+	}
+
+	if export.StatusHistory, err = app.txTracker.GetStatusHistory(stakingTxHash); err != nil {
+		return nil, err
+	}
+
+	if export.Changes, err = app.txTracker.ChangesOf(stakingTxHash); err != nil {
+		return nil, err
+	}
+
+	export.StakingTxConfirmation, export.ConfirmationErr = app.stakingTxConfirmation(storedTx)
+	export.BabylonDelegation, export.BabylonErr = app.babylonDelegationJSON(stakingTxHash)
+
+	return export, nil
+}
+
+// stakingTxConfirmation returns block including staking transaction with
+// proof of inclusion, or nil if transaction is not confirmed
+func (app *App) stakingTxConfirmation(storedTx *stakerdb.StoredTransaction) (*StakingTxConfirmation, error) {
+	conf, status, err := app.wc.TxDetails(&storedTx.StakingTxHash, storedTx.StakingTx.TxOut[0].PkScript)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get staking transaction details: %w", err)
+	}
+
+	if status != walletcontroller.TxInChain {
+		return nil, nil
+	}
+
+	proof, err := cl.GenerateProof(conf.Block, conf.TxIndex)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate inclusion proof: %w", err)
+	}
+
+	return &StakingTxConfirmation{
+		BlockHash:      *conf.BlockHash,
+		BlockHeight:    conf.BlockHeight,
+		TxIndex:        conf.TxIndex,
+		InclusionProof: proof,
+	}, nil
+}
+
+func (app *App) babylonDelegationJSON(stakingTxHash *chainhash.Hash) (json.RawMessage, error) {
+	resp, err := app.babylonClient.QueryBTCDelegation(stakingTxHash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query babylon delegation: %w", err)
+	}
+
+	encoded, err := json.Marshal(resp.BtcDelegation)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode babylon delegation: %w", err)
+	}
+
+	return encoded, nil
+}
+
+// recordBabylonTx stores Babylon transaction sent for the delegation together
+// with the sent message if given, so that it can be exported later.
+// Transaction is already sent at this point, so failure is only logged.
+func (app *App) recordBabylonTx(stakingTxHash *chainhash.Hash, kind string, resp *bct.RelayerTxResponse, msg *cl.EncodedMsg) {
+	if resp == nil {
+		return
+	}
+
+	babylonTx := &stakerdb.BabylonTx{
+		Kind:   kind,
+		TxHash: resp.TxHash,
+		Height: resp.Height,
+		SentAt: time.Now(),
+	}
+	if msg != nil {
+		babylonTx.MsgTypeURL = msg.TypeURL
+		babylonTx.Msg = msg.Bytes
+	}
+
+	err := app.txTracker.RecordBabylonTx(stakingTxHash, babylonTx)
+	if err != nil {
+		app.logger.WithFields(logrus.Fields{
+			"stakingTxHash": stakingTxHash,
+			"babylonTxHash": resp.TxHash,
+			"err":           err,
+		}).Error("Failed to record babylon transaction")
+	}
+}
@@ -0,0 +1,140 @@
+package staker
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	errorsmod "cosmossdk.io/errors"
+	btcstypes "github.com/babylonlabs-io/babylon/v4/x/btcstaking/types"
+	cl "github.com/babylonlabs-io/btc-staker/babylonclient"
+	"github.com/babylonlabs-io/btc-staker/stakerdb"
+	"github.com/btcsuite/btcd/btcjson"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// babylonRemediations are suggested actions for errors of Babylon, checked in
+// order
+var babylonRemediations = []struct {
+	err         error
+	remediation string
+}{
+	{btcstypes.ErrReusedStakingTx, "staking transaction is already registered on babylon, check its status with staking-details"},
+	{btcstypes.ErrFpNotFound, "finality provider is not registered on babylon, choose one from babylon-finality-providers"},
+	{btcstypes.ErrFpAlreadySlashed, "finality provider was slashed, stake to other finality provider"},
+	{btcstypes.ErrInvalidProofOfPossession, "proof of possession does not match staker address and babylon key, check configured babylon key"},
+	{btcstypes.ErrInvalidStakingTx, "staking transaction does not match babylon staking parameters, check them with btc-staking-params and stake again"},
+	{btcstypes.ErrInvalidUnbondingTx, "unbonding transaction does not match babylon unbonding fee or time, check them with btc-staking-params"},
+	{btcstypes.ErrStakingTxIncludedTooEarly, "staking transaction was included before babylon accepts delegations, wait for staking to be enabled and stake again"},
+	{btcstypes.ErrParamsNotFound, "babylon has no staking parameters for the staking transaction height, wait for babylon to sync"},
+	{btcstypes.ErrInvalidStakeExpansion, "stake expansion does not match previous delegation, check it with staking-details"},
+	{sdkerrors.ErrInsufficientFunds, "babylon account can not pay transaction fees, fund it and retry"},
+	{sdkerrors.ErrInsufficientFee, "babylon transaction fee is too low, raise gas price of babylon client"},
+}
+
+// btcRemediations are suggested actions for rejections of btc node, matched by
+// reject reason
+var btcRemediations = []struct {
+	reason      string
+	remediation string
+}{
+	{"min relay fee not met", "fee rate is below minimum of btc node, retry with higher fee rate"},
+	{"mempool min fee not met", "fee rate is below minimum of btc node mempool, retry with higher fee rate"},
+	{"insufficient fee", "replacement fee is too low, retry with higher fee rate"},
+	{"non-bip68-final", "timelock has not expired yet, retry once spendable height is reached"},
+	{"non-final", "timelock has not expired yet, retry later"},
+	{"missingorspent", "inputs were spent by other transaction or are not known to btc node, check for double spends and cancel the delegation"},
+	{"missing-inputs", "inputs were spent by other transaction or are not known to btc node, check for double spends and cancel the delegation"},
+	{"txn-mempool-conflict", "conflicting transaction is in mempool, wait until it confirms or replace it with cancel-stake"},
+	{"too-long-mempool-chain", "too many unconfirmed ancestors, wait for their confirmation and retry"},
+	{"dust", "output value is below dust limit, increase staked amount"},
+}
+
+// newFailureNote returns failure note with suggested remediation
+func newFailureNote(source, reason, remediation string) *stakerdb.FailureNote {
+	return &stakerdb.FailureNote{
+		Source:      source,
+		Reason:      reason,
+		Remediation: remediation,
+		RecordedAt:  time.Now(),
+	}
+}
+
+// babylonFailureNote returns failure note of error returned by Babylon, with
+// its code if the error was registered by Babylon or cosmos modules
+func babylonFailureNote(err error) *stakerdb.FailureNote {
+	note := newFailureNote(stakerdb.FailureSourceBabylon, err.Error(), "")
+
+	if codespace, code, _ := errorsmod.ABCIInfo(err, false); codespace != errorsmod.UndefinedCodespace {
+		note.Codespace = codespace
+		note.Code = int64(code)
+	}
+
+	for _, r := range babylonRemediations {
+		if errors.Is(err, r.err) {
+			note.Remediation = r.remediation
+			return note
+		}
+	}
+
+	if cl.IsRetryableError(err) {
+		note.Remediation = "babylon is congested, staker retries automatically"
+	}
+
+	return note
+}
+
+// btcFailureNote returns failure note of transaction rejected by btc node,
+// with rpc error code if the node returned one
+func btcFailureNote(err error) *stakerdb.FailureNote {
+	note := newFailureNote(stakerdb.FailureSourceBtc, err.Error(), "")
+
+	var rpcErr *btcjson.RPCError
+	if errors.As(err, &rpcErr) {
+		note.Code = int64(rpcErr.Code)
+	}
+
+	reason := strings.ToLower(err.Error())
+	for _, r := range btcRemediations {
+		if strings.Contains(reason, r.reason) {
+			note.Remediation = r.remediation
+			break
+		}
+	}
+
+	return note
+}
+
+// noteFailure stores failure note of the delegation. Notes are informational,
+// so failure is only logged. Delegations which are not tracked yet are
+// ignored, their errors are returned to the caller.
+func (app *App) noteFailure(stakingTxHash *chainhash.Hash, note *stakerdb.FailureNote) {
+	err := app.txTracker.SetFailureNote(stakingTxHash, note)
+	if err == nil || errors.Is(err, stakerdb.ErrTransactionNotFound) {
+		return
+	}
+
+	app.logger.WithFields(logrus.Fields{
+		"stakingTxHash": stakingTxHash,
+		"err":           err,
+	}).Error("Failed to store failure note")
+}
+
+// clearFailureNote removes failure note of delegation which progressed past
+// the noted problem
+func (app *App) clearFailureNote(stakingTxHash *chainhash.Hash) {
+	if err := app.txTracker.ClearFailureNote(stakingTxHash); err != nil {
+		app.logger.WithFields(logrus.Fields{
+			"stakingTxHash": stakingTxHash,
+			"err":           err,
+		}).Error("Failed to clear failure note")
+	}
+}
+
+// FailureNote returns the last failure or problem noted for the delegation,
+// or nil if there is none
+func (app *App) FailureNote(stakingTxHash *chainhash.Hash) (*stakerdb.FailureNote, error) {
+	return app.txTracker.GetFailureNote(stakingTxHash)
+}
@@ -0,0 +1,135 @@
+//go:build faults
+
+package staker_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/babylonlabs-io/btc-staker/staker"
+	"github.com/babylonlabs-io/btc-staker/stakerdb"
+	"github.com/babylonlabs-io/btc-staker/testutil/simulation"
+	"github.com/babylonlabs-io/btc-staker/utils/faults"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr/musig2"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/stretchr/testify/require"
+)
+
+// startStakingSession starts musig2 session signing staking transaction
+// funded by output of the key and submits nonces of all signers
+func startStakingSession(
+	t *testing.T,
+	sim *simulation.Simulation,
+	app *staker.App,
+	signers []*muSig2Signer,
+) (*stakerdb.MuSig2Session, *wire.MsgTx) {
+	pubKeys := signerPubKeys(signers)
+
+	_, err := app.RegisterMuSig2Key("custody", pubKeys)
+	require.NoError(t, err)
+
+	aggKey, _, _, err := musig2.AggregateKeys(pubKeys, true)
+	require.NoError(t, err)
+	custodyScript, err := txscript.PayToTaprootScript(txscript.ComputeTaprootKeyNoScript(aggKey.PreTweakedKey))
+	require.NoError(t, err)
+
+	const fundedAmount = btcutil.Amount(1000000)
+	sim.Chain.Fund(custodyScript, fundedAmount)
+	coinbase := sim.Chain.MineBlocks(1)[0].Transactions[0]
+
+	coinbaseHash := coinbase.TxHash()
+	stakingTx := wire.NewMsgTx(2)
+	for i, out := range coinbase.TxOut {
+		if bytes.Equal(out.PkScript, custodyScript) {
+			stakingTx.AddTxIn(wire.NewTxIn(wire.NewOutPoint(&coinbaseHash, uint32(i)), nil, nil))
+		}
+	}
+	require.Len(t, stakingTx.TxIn, 1)
+	stakingTx.AddTxOut(wire.NewTxOut(int64(fundedAmount)-2000, custodyScript))
+
+	session, err := app.StartMuSig2Session(&staker.MuSig2SessionRequest{
+		KeyName:   "custody",
+		Kind:      staker.MuSig2SessionStaking,
+		StakingTx: stakingTx,
+	})
+	require.NoError(t, err)
+
+	return submitNonces(t, app, session.ID, signers), stakingTx
+}
+
+// keyPathPartialSigs returns partial signatures of all signers of the session
+func keyPathPartialSigs(t *testing.T, session *stakerdb.MuSig2Session, signers []*muSig2Signer) [][]byte {
+	pubKeys := signerPubKeys(signers)
+	sigs := make([][]byte, len(signers))
+	for i, s := range signers {
+		sigs[i] = partialSig(t, s, session, pubKeys, musig2.WithBip86SignTweak())
+	}
+	return sigs
+}
+
+func TestDBWriteFaultKeepsMuSig2SessionUnsigned(t *testing.T) {
+	faults.Reset()
+	t.Cleanup(faults.Reset)
+
+	sim, app := newSimulatedApp(t)
+	signers := newMuSig2Signers(t, 2)
+	session, stakingTx := startStakingSession(t, sim, app, signers)
+	sigs := keyPathPartialSigs(t, session, signers)
+
+	_, err := app.SubmitMuSig2PartialSig(session.ID, signers[0].privKey.PubKey(), sigs[0])
+	require.NoError(t, err)
+
+	// last partial signature is lost before it is stored
+	faults.Arm(faults.DBWrite, 1)
+	_, err = app.SubmitMuSig2PartialSig(session.ID, signers[1].privKey.PubKey(), sigs[1])
+	require.ErrorIs(t, err, faults.ErrInjected)
+
+	stored, err := app.MuSig2Session(session.ID)
+	require.NoError(t, err)
+	require.Len(t, stored.PartialSigs, 1)
+	require.Empty(t, stored.Signature)
+	require.Empty(t, stored.BroadcastTxHash)
+	require.Empty(t, sim.Chain.MempoolTxs())
+
+	// signer resubmits and session completes as if nothing happened
+	stored, err = app.SubmitMuSig2PartialSig(session.ID, signers[1].privKey.PubKey(), sigs[1])
+	require.NoError(t, err)
+	require.Len(t, stored.PartialSigs, 2)
+	require.Empty(t, stored.Error)
+	require.Equal(t, stakingTx.TxHash().String(), stored.BroadcastTxHash)
+	require.Len(t, sim.Chain.MempoolTxs(), 1)
+	require.Equal(t, 1, faults.Triggered(faults.DBWrite))
+}
+
+func TestBtcNodeTimeoutRecordsMuSig2BroadcastFailure(t *testing.T) {
+	faults.Reset()
+	t.Cleanup(faults.Reset)
+
+	sim, app := newSimulatedApp(t)
+	signers := newMuSig2Signers(t, 2)
+	session, _ := startStakingSession(t, sim, app, signers)
+	sigs := keyPathPartialSigs(t, session, signers)
+
+	_, err := app.SubmitMuSig2PartialSig(session.ID, signers[0].privKey.PubKey(), sigs[0])
+	require.NoError(t, err)
+
+	// signature is combined, but node times out when it is broadcast
+	faults.Arm(faults.BtcNodeTimeout, 1)
+	_, err = app.SubmitMuSig2PartialSig(session.ID, signers[1].privKey.PubKey(), sigs[1])
+	require.NoError(t, err)
+
+	stored, err := app.MuSig2Session(session.ID)
+	require.NoError(t, err)
+	require.NotEmpty(t, stored.Signature)
+	require.False(t, stored.CompletedAt.IsZero())
+	require.Contains(t, stored.Error, faults.ErrInjected.Error())
+	require.Empty(t, stored.BroadcastTxHash)
+	require.Empty(t, sim.Chain.MempoolTxs())
+	require.Equal(t, 1, faults.Triggered(faults.BtcNodeTimeout))
+
+	// completed session does not accept signatures anymore
+	_, err = app.SubmitMuSig2PartialSig(session.ID, signers[1].privKey.PubKey(), sigs[1])
+	require.ErrorContains(t, err, "session is already completed")
+}
@@ -0,0 +1,20 @@
+package staker
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrFeatureDisabled is returned when experimental feature is requested, but
+// it was not enabled in feature flags config
+var ErrFeatureDisabled = errors.New("experimental feature is disabled")
+
+// checkFeatureEnabled returns ErrFeatureDisabled if the experimental feature
+// was not enabled by the operator
+func (app *App) checkFeatureEnabled(feature string) error {
+	if !app.config.FeatureFlagsConfig.Enabled(feature) {
+		return fmt.Errorf("%w: %s, enable it with featureflags.enable", ErrFeatureDisabled, feature)
+	}
+
+	return nil
+}
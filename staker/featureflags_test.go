@@ -0,0 +1,37 @@
+package staker_test
+
+import (
+	"testing"
+
+	"github.com/babylonlabs-io/btc-staker/staker"
+	"github.com/babylonlabs-io/btc-staker/stakercfg"
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStakeExpansionRequiresFeatureFlag(t *testing.T) {
+	t.Parallel()
+
+	sim, app, addr := startSimulatedApp(t)
+	stakingTxHash, _ := activeDelegation(t, sim, app, addr)
+	fp := sim.Babylon.AddFinalityProvider()
+
+	_, err := app.StakeExpand(
+		addr, 200_000, []*btcec.PublicKey{fp}, withdrawableTestStakingTime, stakingTxHash, "",
+	)
+	require.ErrorIs(t, err, staker.ErrFeatureDisabled)
+	require.ErrorContains(t, err, stakercfg.FeatureStakeExpansion)
+
+	sim, app, addr = startSimulatedApp(t, func(cfg *stakercfg.Config) {
+		cfg.FeatureFlagsConfig.Enable = []string{stakercfg.FeatureStakeExpansion}
+	})
+	stakingTxHash, _ = activeDelegation(t, sim, app, addr)
+	fp = sim.Babylon.AddFinalityProvider()
+
+	_, err = app.StakeExpand(
+		addr, 200_000, []*btcec.PublicKey{fp}, withdrawableTestStakingTime, stakingTxHash, "",
+	)
+	// enabled expansion is sent to babylon, which does not support it in
+	// simulation
+	require.ErrorContains(t, err, "stake expansion is not supported in simulation")
+}
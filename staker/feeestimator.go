@@ -15,14 +15,19 @@ import (
 const (
 	// DefaultNumBlockForEstimation Default number of blocks to use for fee estimation.
 	// 1 means we want our transactions to be confirmed in the next block.
-	// TODO: make this configurable ?
+	// Requests can select other confirmation target, see FeeSelection.
 	DefaultNumBlockForEstimation = 1
 )
 
+// FeeEstimator estimates fee rate of transactions sent by the staker app.
+// Mock is available in testutil/mocks.
 type FeeEstimator interface {
 	Start() error
 	Stop() error
 	EstimateFeePerKb() chainfee.SatPerKVByte
+	// EstimateFeePerKbForTarget estimates fee rate of transaction which should
+	// confirm within numBlocks blocks
+	EstimateFeePerKbForTarget(numBlocks uint32) chainfee.SatPerKVByte
 }
 
 type DynamicBtcFeeEstimator struct {
@@ -51,6 +56,14 @@ func NewDynamicBtcFeeEstimator(
 			HTTPPostMode:         true,
 		}
 
+		if cfg.Bitcoind.RPCCookie != "" {
+			// rpcclient uses cookie only if user and password are empty,
+			// and re-reads it when bitcoind restarts
+			rpcConfig.User = ""
+			rpcConfig.Pass = ""
+			rpcConfig.CookiePath = cfg.Bitcoind.RPCCookie
+		}
+
 		// TODO: we should probably create our own estimator backend, as those from lnd
 		// have hardcoded loggers, so we do not log stuff to file as we want
 		est, err := chainfee.NewBitcoindEstimator(
@@ -116,12 +129,17 @@ func (e *DynamicBtcFeeEstimator) Stop() error {
 }
 
 func (e *DynamicBtcFeeEstimator) EstimateFeePerKb() chainfee.SatPerKVByte {
-	fee, err := e.estimator.EstimateFeePerKW(DefaultNumBlockForEstimation)
+	return e.EstimateFeePerKbForTarget(DefaultNumBlockForEstimation)
+}
+
+func (e *DynamicBtcFeeEstimator) EstimateFeePerKbForTarget(numBlocks uint32) chainfee.SatPerKVByte {
+	fee, err := e.estimator.EstimateFeePerKW(numBlocks)
 
 	if err != nil {
 		e.logger.WithFields(logrus.Fields{
-			"err":     err,
-			"default": e.MaxFeeRate,
+			"err":       err,
+			"numBlocks": numBlocks,
+			"default":   e.MaxFeeRate,
 		}).Error("Failed to estimate transaction fee using connected btc node. Using max fee from config")
 		return e.MaxFeeRate
 	}
@@ -146,6 +164,7 @@ func (e *DynamicBtcFeeEstimator) EstimateFeePerKb() chainfee.SatPerKVByte {
 
 	e.logger.WithFields(logrus.Fields{
 		"fee":        estimatedFee,
+		"numBlocks":  numBlocks,
 		"maxFeeRate": e.MaxFeeRate,
 		"minFeeRate": e.MinFeeRate,
 	}).Debug("Using fee rate estimated by connected btc node")
@@ -176,3 +195,7 @@ func (e *StaticFeeEstimator) Stop() error {
 func (e *StaticFeeEstimator) EstimateFeePerKb() chainfee.SatPerKVByte {
 	return e.DefaultFee
 }
+
+func (e *StaticFeeEstimator) EstimateFeePerKbForTarget(_ uint32) chainfee.SatPerKVByte {
+	return e.DefaultFee
+}
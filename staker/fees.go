@@ -0,0 +1,113 @@
+package staker
+
+import (
+	"fmt"
+
+	"github.com/babylonlabs-io/btc-staker/stakerdb"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/sirupsen/logrus"
+)
+
+// FeeStats are fees paid by the staker daemon for all delegations
+type FeeStats struct {
+	TotalStakingFees    btcutil.Amount
+	TotalUnbondingFees  btcutil.Amount
+	TotalWithdrawalFees btcutil.Amount
+	Delegations         []stakerdb.DelegationFees
+}
+
+// TotalFees returns sum of all fees paid by the staker daemon
+func (s *FeeStats) TotalFees() btcutil.Amount {
+	return s.TotalStakingFees + s.TotalUnbondingFees + s.TotalWithdrawalFees
+}
+
+// txFee calculates fee paid by the transaction, inputs are looked up on btc
+func (app *App) txFee(tx *wire.MsgTx) (btcutil.Amount, error) {
+	prevOuts, err := app.prevOutputs(tx)
+	if err != nil {
+		return 0, err
+	}
+
+	var inputsValue btcutil.Amount
+	for _, prevOut := range prevOuts {
+		inputsValue += btcutil.Amount(prevOut.Value)
+	}
+
+	var outputsValue btcutil.Amount
+	for _, out := range tx.TxOut {
+		outputsValue += btcutil.Amount(out.Value)
+	}
+
+	if inputsValue < outputsValue {
+		return 0, fmt.Errorf("transaction %s outputs value exceeds inputs value", tx.TxHash())
+	}
+
+	return inputsValue - outputsValue, nil
+}
+
+// recordPaidFee stores fee paid by transaction broadcast for the delegation.
+// Fees are used only for accounting, so failure is only logged.
+func (app *App) recordPaidFee(stakingTxHash *chainhash.Hash, kind stakerdb.WatchedTxKind, fee btcutil.Amount) {
+	if err := app.txTracker.RecordPaidFee(stakingTxHash, kind, fee); err != nil {
+		app.logger.WithFields(logrus.Fields{
+			"stakingTxHash": stakingTxHash,
+			"kind":          kind,
+			"fee":           fee,
+			"err":           err,
+		}).Error("Failed to record paid fee")
+	}
+}
+
+// recordStakingTxFee stores fee paid by broadcast staking transaction
+func (app *App) recordStakingTxFee(stakingTxHash *chainhash.Hash, stakingTx *wire.MsgTx) {
+	fee, err := app.txFee(stakingTx)
+	if err != nil {
+		app.logger.WithFields(logrus.Fields{
+			"stakingTxHash": stakingTxHash,
+			"err":           err,
+		}).Error("Failed to calculate staking transaction fee")
+		return
+	}
+
+	app.recordPaidFee(stakingTxHash, stakerdb.WatchedStakingTx, fee)
+}
+
+// DelegationFees returns fees paid by btc transactions of the delegation
+func (app *App) DelegationFees(stakingTxHash *chainhash.Hash) (*stakerdb.DelegationFees, error) {
+	return app.txTracker.GetDelegationFees(stakingTxHash)
+}
+
+// FeeStats returns fees paid for every delegation together with daemon wide
+// totals. If tenant is not empty, only delegations of this tenant are counted.
+func (app *App) FeeStats(tenant string) (*FeeStats, error) {
+	delegations, err := app.txTracker.ListDelegationFees()
+	if err != nil {
+		return nil, err
+	}
+
+	if tenant != "" {
+		tenantDelegations := make([]stakerdb.DelegationFees, 0, len(delegations))
+		for _, d := range delegations {
+			delegationTenant, err := app.txTracker.GetTransactionTenant(&d.StakingTxHash)
+			if err != nil {
+				return nil, err
+			}
+
+			if delegationTenant == tenant {
+				tenantDelegations = append(tenantDelegations, d)
+			}
+		}
+		delegations = tenantDelegations
+	}
+
+	stats := &FeeStats{Delegations: delegations}
+	for _, d := range delegations {
+		stats.TotalStakingFees += d.StakingFee
+		stats.TotalUnbondingFees += d.UnbondingFee
+		stats.TotalWithdrawalFees += d.WithdrawalFee
+	}
+
+	return stats, nil
+}
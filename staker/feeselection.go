@@ -0,0 +1,162 @@
+package staker
+
+import (
+	"errors"
+	"fmt"
+
+	staking "github.com/babylonlabs-io/babylon/v4/btcstaking"
+	btcstypes "github.com/babylonlabs-io/babylon/v4/x/btcstaking/types"
+	"github.com/babylonlabs-io/btc-staker/stakerdb"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcwallet/wallet/txrules"
+	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
+)
+
+// maxTargetConf is the highest confirmation target btc nodes estimate fees for
+const maxTargetConf = 1008
+
+// FeeSelection selects fee rate of transaction sent for a request. At most one
+// field can be set, zero value uses fee rate estimated for the next block. Fee
+// of unbonding transaction is fixed by Babylon, for unbonding the selection is
+// only the lowest acceptable fee rate.
+type FeeSelection struct {
+	// FeeRate is explicit fee rate in sat/vbyte
+	FeeRate uint64
+	// TargetConf is number of blocks within which transaction should confirm,
+	// fee rate is estimated for it by the fee estimator
+	TargetConf uint32
+}
+
+// IsZero returns true if fee selection does not select anything
+func (f FeeSelection) IsZero() bool {
+	return f.FeeRate == 0 && f.TargetConf == 0
+}
+
+// Validate checks that fee selection is consistent
+func (f FeeSelection) Validate() error {
+	if f.FeeRate > 0 && f.TargetConf > 0 {
+		return errors.New("either fee rate or target confirmation count can be selected, not both")
+	}
+
+	if f.TargetConf > maxTargetConf {
+		return fmt.Errorf("target confirmation count %d is above %d", f.TargetConf, maxTargetConf)
+	}
+
+	return nil
+}
+
+// resolveFeeRate returns fee rate selected by the request. Estimated fee rates
+// are bounded by min and max fee rate of the config and raised to min relay fee
+// of the btc node, explicit fee rate must be within these bounds.
+func (app *App) resolveFeeRate(fee FeeSelection) (chainfee.SatPerKVByte, error) {
+	if err := fee.Validate(); err != nil {
+		return 0, err
+	}
+
+	switch {
+	case fee.FeeRate > 0:
+		minFeeRate := uint64(app.config.BtcNodeBackendConfig.MinFeeRate)
+		maxFeeRate := uint64(app.config.BtcNodeBackendConfig.MaxFeeRate)
+		if fee.FeeRate < minFeeRate || fee.FeeRate > maxFeeRate {
+			return 0, fmt.Errorf("fee rate %d sat/vB is not in range [%d, %d] of the config",
+				fee.FeeRate, minFeeRate, maxFeeRate)
+		}
+		feeRate := chainfee.SatPerKVByte(fee.FeeRate * 1000)
+		if floor := app.feeRateFloor(); feeRate < floor {
+			return 0, fmt.Errorf("fee rate %d sat/vB is below min relay fee %d sat/vB of btc node",
+				fee.FeeRate, floor/1000)
+		}
+		return feeRate, nil
+	case fee.TargetConf > 0:
+		return max(app.feeEstimator.EstimateFeePerKbForTarget(fee.TargetConf), app.feeRateFloor()), nil
+	default:
+		return app.estimatedFeeRate(), nil
+	}
+}
+
+// checkUnbondingFeeRate checks that unbonding transaction, whose fee is fixed
+// by Babylon params, pays at least fee rate selected by the request. Fee
+// selection can't change the fee, so unbonding is rejected instead. Size of
+// unbonding transaction is estimated with the script path witness of staker
+// and covenant quorum signatures.
+func (app *App) checkUnbondingFeeRate(
+	storedTx *stakerdb.StoredTransaction,
+	di *btcstypes.QueryBTCDelegationResponse,
+	fee FeeSelection,
+) error {
+	feeRate, err := app.resolveFeeRate(fee)
+	if err != nil {
+		return err
+	}
+
+	del := di.BtcDelegation
+	if del == nil {
+		return fmt.Errorf("delegation has no btc delegation data")
+	}
+
+	undelegationInfo, err := app.babylonClient.GetUndelegationInfo(di)
+	if err != nil {
+		return fmt.Errorf("failed to get undelegation info from babylon: %w", err)
+	}
+
+	unbondingTx := undelegationInfo.UnbondingTransaction
+	stakingOutputIdx := del.StakingOutputIdx
+	if unbondingTx == nil || len(unbondingTx.TxOut) == 0 || int(stakingOutputIdx) >= len(storedTx.StakingTx.TxOut) {
+		return fmt.Errorf("invalid unbonding transaction")
+	}
+	stakingOutput := storedTx.StakingTx.TxOut[stakingOutputIdx]
+
+	params, err := app.babylonClient.ParamsByVersion(del.ParamsVersion)
+	if err != nil {
+		return fmt.Errorf("error getting params version %d: %w", del.ParamsVersion, err)
+	}
+
+	stakerPk, err := del.BtcPk.ToBTCPK()
+	if err != nil {
+		return fmt.Errorf("failed to parse staker public key: %w", err)
+	}
+
+	fpBtcPubkeys, err := convertFpBtcPkToBtcPk(del.FpBtcPkList)
+	if err != nil {
+		return fmt.Errorf("error converting fpBtcPkList to btcPkList: %w", err)
+	}
+
+	stakingInfo, err := staking.BuildStakingInfo(
+		stakerPk,
+		fpBtcPubkeys,
+		params.CovenantPks,
+		params.CovenantQuruomThreshold,
+		uint16(del.StakingTime),
+		btcutil.Amount(stakingOutput.Value),
+		app.network,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to build staking info: %w", err)
+	}
+
+	unbondingFee := btcutil.Amount(stakingOutput.Value - unbondingTx.TxOut[0].Value)
+	size, err := estimateUnbondingTxSize(
+		storedTx.StakingTx,
+		stakingOutputIdx,
+		stakingInfo,
+		params.CovenantQuruomThreshold,
+		len(params.CovenantPks),
+		unbondingFee,
+	)
+	if err != nil {
+		return err
+	}
+
+	return checkFixedFee("unbonding", unbondingFee, size.VSize, feeRate)
+}
+
+// checkFixedFee checks that transaction of given virtual size paying fixed fee
+// pays at least given fee rate
+func checkFixedFee(kind string, fee btcutil.Amount, vsize int64, feeRate chainfee.SatPerKVByte) error {
+	if requiredFee := txrules.FeeForSerializeSize(btcutil.Amount(feeRate), int(vsize)); fee < requiredFee {
+		return fmt.Errorf("%s transaction pays fee %s fixed by babylon, which is below %s required by selected fee rate %d sat/vB",
+			kind, fee, requiredFee, uint64(feeRate)/1000)
+	}
+
+	return nil
+}
@@ -0,0 +1,143 @@
+package staker
+
+import (
+	"testing"
+
+	"github.com/babylonlabs-io/btc-staker/stakercfg"
+	"github.com/babylonlabs-io/btc-staker/testutil/mocks"
+	"github.com/babylonlabs-io/btc-staker/walletcontroller"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+var _ FeeEstimator = (*mocks.MockFeeEstimator)(nil)
+
+func TestFeeSelectionValidate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		fee     FeeSelection
+		wantErr bool
+	}{
+		{name: "zero", fee: FeeSelection{}},
+		{name: "fee rate", fee: FeeSelection{FeeRate: 10}},
+		{name: "target conf", fee: FeeSelection{TargetConf: 6}},
+		{name: "max target conf", fee: FeeSelection{TargetConf: maxTargetConf}},
+		{name: "both", fee: FeeSelection{FeeRate: 10, TargetConf: 6}, wantErr: true},
+		{name: "target conf above max", fee: FeeSelection{TargetConf: maxTargetConf + 1}, wantErr: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.fee.Validate()
+			if tc.wantErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestResolveFeeRate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		fee       FeeSelection
+		estimated chainfee.SatPerKVByte
+		expected  chainfee.SatPerKVByte
+		wantErr   bool
+	}{
+		{
+			name:      "estimated for next block",
+			estimated: 5000,
+			expected:  5000,
+		},
+		{
+			name:      "estimate raised to min relay fee",
+			estimated: 1000,
+			expected:  2000,
+		},
+		{
+			name:      "estimated for target",
+			fee:       FeeSelection{TargetConf: 6},
+			estimated: 3000,
+			expected:  3000,
+		},
+		{
+			name:      "target estimate raised to min relay fee",
+			fee:       FeeSelection{TargetConf: 6},
+			estimated: 1000,
+			expected:  2000,
+		},
+		{
+			name:     "explicit fee rate",
+			fee:      FeeSelection{FeeRate: 10},
+			expected: 10_000,
+		},
+		{
+			name:    "explicit fee rate below min relay fee",
+			fee:     FeeSelection{FeeRate: 1},
+			wantErr: true,
+		},
+		{
+			name:    "explicit fee rate above max fee rate",
+			fee:     FeeSelection{FeeRate: 101},
+			wantErr: true,
+		},
+		{
+			name:    "invalid selection",
+			fee:     FeeSelection{FeeRate: 10, TargetConf: 6},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := stakercfg.DefaultConfig()
+			cfg.BtcNodeBackendConfig.MinFeeRate = 1
+			cfg.BtcNodeBackendConfig.MaxFeeRate = 100
+
+			ctrl := gomock.NewController(t)
+			wc := mocks.NewMockWalletController(ctrl)
+			wc.EXPECT().RelayFees().Return(&walletcontroller.RelayFees{
+				MinRelayFeePerKb:         btcutil.Amount(2000),
+				IncrementalRelayFeePerKb: walletcontroller.DefaultIncrementalRelayFeePerKb,
+			}, nil).AnyTimes()
+
+			feeEstimator := mocks.NewMockFeeEstimator(ctrl)
+			feeEstimator.EXPECT().EstimateFeePerKb().Return(tc.estimated).AnyTimes()
+			feeEstimator.EXPECT().EstimateFeePerKbForTarget(tc.fee.TargetConf).Return(tc.estimated).AnyTimes()
+
+			app := &App{
+				config:       &cfg,
+				logger:       logrus.New(),
+				network:      &chaincfg.RegressionNetParams,
+				wc:           wc,
+				feeEstimator: feeEstimator,
+			}
+
+			feeRate, err := app.resolveFeeRate(tc.fee)
+			if tc.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, feeRate)
+		})
+	}
+}
+
+func TestCheckFixedFee(t *testing.T) {
+	t.Parallel()
+
+	// 200 vbytes at 10 sat/vB require 2000 sat
+	require.NoError(t, checkFixedFee("unbonding", 2000, 200, 10_000))
+	require.Error(t, checkFixedFee("unbonding", 1999, 200, 10_000))
+}
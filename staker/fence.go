@@ -0,0 +1,80 @@
+package staker
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/babylonlabs-io/btc-staker/cluster"
+	"github.com/babylonlabs-io/btc-staker/stakerdb"
+	"github.com/babylonlabs-io/btc-staker/walletcontroller"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+)
+
+// ErrNotLeader is returned for broadcasts and Babylon submissions attempted
+// by instance which is no longer the leader
+var ErrNotLeader = errors.New("staker instance is no longer the leader")
+
+// leaderFence prevents instance which lost leadership from writing to btc and
+// Babylon. Term of the elected leader is stored in the shared database, so
+// once standby instance takes over, checks of the previous leader fail even
+// if it has not noticed that its session expired yet. Nil fence allows
+// everything.
+type leaderFence struct {
+	elector cluster.LeaderElector
+	store   *stakerdb.TrackedTransactionStore
+}
+
+func newLeaderFence(elector cluster.LeaderElector, store *stakerdb.TrackedTransactionStore) *leaderFence {
+	return &leaderFence{
+		elector: elector,
+		store:   store,
+	}
+}
+
+// acquire stores term of this instance as term of the current leader
+func (f *leaderFence) acquire() error {
+	if f == nil {
+		return nil
+	}
+
+	if err := f.store.AdvanceLeaderTerm(f.elector.Term()); err != nil {
+		return fmt.Errorf("failed to store leader term: %w", err)
+	}
+
+	return nil
+}
+
+// check returns ErrNotLeader if this instance is no longer the leader
+func (f *leaderFence) check() error {
+	if f == nil {
+		return nil
+	}
+
+	select {
+	case <-f.elector.Done():
+		return ErrNotLeader
+	default:
+	}
+
+	err := f.store.CheckLeaderTerm(f.elector.Term())
+	if errors.Is(err, stakerdb.ErrStaleLeaderTerm) {
+		return fmt.Errorf("%w: %w", ErrNotLeader, err)
+	}
+
+	return err
+}
+
+// fencedWalletController checks leadership before every broadcast
+type fencedWalletController struct {
+	walletcontroller.WalletController
+	fence *leaderFence
+}
+
+func (w *fencedWalletController) SendRawTransaction(tx *wire.MsgTx, allowHighFees bool) (*chainhash.Hash, error) {
+	if err := w.fence.check(); err != nil {
+		return nil, err
+	}
+
+	return w.WalletController.SendRawTransaction(tx, allowHighFees)
+}
@@ -0,0 +1,245 @@
+package staker
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	scfg "github.com/babylonlabs-io/btc-staker/stakercfg"
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/sirupsen/logrus"
+)
+
+// ErrFinalityProviderQuotaExceeded is returned when stake would exceed quota
+// of delegations to finality provider
+var ErrFinalityProviderQuotaExceeded = errors.New("finality provider quota exceeded")
+
+// FinalityProviderUsage is number and amount of delegations of the staker to
+// single finality provider, which are not failed, unbonded or expired
+type FinalityProviderUsage struct {
+	// FpBtcPk is hex encoded x-only public key of finality provider
+	FpBtcPk     string
+	Delegations uint64
+	Amount      btcutil.Amount
+	// Quota is nil if finality provider has no quota
+	Quota *scfg.FinalityProviderQuota
+}
+
+// fpQuotas enforces quotas of delegations to finality providers. Stakes which
+// passed the check, but are not stored yet, are counted as pending, so that
+// concurrent stakes can't exceed the quota together.
+type fpQuotas struct {
+	mu      sync.Mutex
+	quotas  map[string]scfg.FinalityProviderQuota
+	pending map[string]*FinalityProviderUsage
+}
+
+// newFpQuotas returns nil if no quota is configured
+func newFpQuotas(cfg *scfg.StakerConfig) (*fpQuotas, error) {
+	quotas, err := cfg.FinalityProviderQuotas()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(quotas) == 0 {
+		return nil, nil
+	}
+
+	return &fpQuotas{
+		quotas:  quotas,
+		pending: make(map[string]*FinalityProviderUsage),
+	}, nil
+}
+
+// quotaOf returns quota of finality provider, nil if it has none
+func (q *fpQuotas) quotaOf(fpBtcPk string) *scfg.FinalityProviderQuota {
+	if quota, ok := q.quotas[fpBtcPk]; ok {
+		return &quota
+	}
+
+	if quota, ok := q.quotas[scfg.AnyFinalityProvider]; ok {
+		return &quota
+	}
+
+	return nil
+}
+
+func fpKeyHex(pk *btcec.PublicKey) string {
+	return hex.EncodeToString(schnorr.SerializePubKey(pk))
+}
+
+func addUsage(usage map[string]*FinalityProviderUsage, fpBtcPk string, amount btcutil.Amount) {
+	u, ok := usage[fpBtcPk]
+	if !ok {
+		u = &FinalityProviderUsage{FpBtcPk: fpBtcPk}
+		usage[fpBtcPk] = u
+	}
+
+	u.Delegations++
+	u.Amount += amount
+}
+
+// finalityProviderUsage returns usage of finality providers by stored
+// delegations. Finality providers are recorded for delegations created by the
+// staker, for the others they are taken from cached Babylon delegation.
+func (app *App) finalityProviderUsage() (map[string]*FinalityProviderUsage, error) {
+	recorded, err := app.txTracker.ListDelegationFinalityProviders()
+	if err != nil {
+		return nil, err
+	}
+
+	finished := func(status *DelegationStatus) bool {
+		state := status.State()
+		return state == BabylonUnbondedStatus || state == BabylonExpiredStatus
+	}
+
+	usage := make(map[string]*FinalityProviderUsage)
+	seen := make(map[chainhash.Hash]struct{}, len(recorded))
+
+	for _, d := range recorded {
+		seen[d.StakingTxHash] = struct{}{}
+
+		if status, ok := app.statuses.get(d.StakingTxHash); ok && finished(status) {
+			continue
+		}
+
+		for _, pk := range d.FpBtcPks {
+			addUsage(usage, hex.EncodeToString(pk), d.StakingAmount)
+		}
+	}
+
+	for hash, status := range app.statuses.snapshot() {
+		if _, ok := seen[hash]; ok || finished(status) {
+			continue
+		}
+
+		del := status.Delegation.BtcDelegation
+		if del == nil {
+			continue
+		}
+		for _, pk := range del.FpBtcPkList {
+			addUsage(usage, pk.MarshalHex(), btcutil.Amount(del.TotalSat))
+		}
+	}
+
+	return usage, nil
+}
+
+// reserveFpQuota checks that stake of the given amount to the given finality
+// providers does not exceed their quotas and counts it as pending until the
+// returned release is called
+func (app *App) reserveFpQuota(fpPks []*btcec.PublicKey, amount btcutil.Amount) (func(), error) {
+	q := app.fpQuotas
+	if q == nil {
+		return func() {}, nil
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	usage, err := app.finalityProviderUsage()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get finality provider usage: %w", err)
+	}
+
+	keys := make([]string, 0, len(fpPks))
+	for _, pk := range fpPks {
+		key := fpKeyHex(pk)
+		keys = append(keys, key)
+
+		quota := q.quotaOf(key)
+		if quota == nil {
+			continue
+		}
+
+		var delegations uint64
+		var total btcutil.Amount
+		for _, u := range []*FinalityProviderUsage{usage[key], q.pending[key]} {
+			if u != nil {
+				delegations += u.Delegations
+				total += u.Amount
+			}
+		}
+
+		if quota.MaxDelegations > 0 && delegations+1 > quota.MaxDelegations {
+			return nil, fmt.Errorf("%w: finality provider %s has %d of at most %d delegations",
+				ErrFinalityProviderQuotaExceeded, key, delegations, quota.MaxDelegations)
+		}
+
+		if quota.MaxAmount > 0 && total+amount > quota.MaxAmount {
+			return nil, fmt.Errorf("%w: finality provider %s has %s staked, stake of %s would exceed quota of %s",
+				ErrFinalityProviderQuotaExceeded, key, total, amount, quota.MaxAmount)
+		}
+	}
+
+	for _, key := range keys {
+		addUsage(q.pending, key, amount)
+	}
+
+	return func() {
+		q.mu.Lock()
+		defer q.mu.Unlock()
+
+		for _, key := range keys {
+			u := q.pending[key]
+			u.Delegations--
+			u.Amount -= amount
+			if u.Delegations == 0 {
+				delete(q.pending, key)
+			}
+		}
+	}, nil
+}
+
+// recordFinalityProviders stores finality providers of delegation created by
+// the staker, so that it is counted in their quotas. Delegation is already sent
+// at this point, so failure is only logged.
+func (app *App) recordFinalityProviders(stakingTxHash *chainhash.Hash, fpPks []*btcec.PublicKey, amount btcutil.Amount) {
+	keys := make([][]byte, 0, len(fpPks))
+	for _, pk := range fpPks {
+		keys = append(keys, schnorr.SerializePubKey(pk))
+	}
+
+	if err := app.txTracker.SetDelegationFinalityProviders(stakingTxHash, keys, amount); err != nil {
+		app.logger.WithFields(logrus.Fields{
+			"stakingTxHash": stakingTxHash,
+			"err":           err,
+		}).Error("Failed to record finality providers of delegation")
+	}
+}
+
+// FinalityProviderQuotas returns usage of finality providers which have
+// quota or delegations of the staker, sorted by key
+func (app *App) FinalityProviderQuotas() ([]FinalityProviderUsage, error) {
+	usage, err := app.finalityProviderUsage()
+	if err != nil {
+		return nil, err
+	}
+
+	if app.fpQuotas != nil {
+		for key := range app.fpQuotas.quotas {
+			if _, ok := usage[key]; !ok && key != scfg.AnyFinalityProvider {
+				usage[key] = &FinalityProviderUsage{FpBtcPk: key}
+			}
+		}
+	}
+
+	result := make([]FinalityProviderUsage, 0, len(usage))
+	for key, u := range usage {
+		if app.fpQuotas != nil {
+			u.Quota = app.fpQuotas.quotaOf(key)
+		}
+		result = append(result, *u)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].FpBtcPk < result[j].FpBtcPk
+	})
+
+	return result, nil
+}
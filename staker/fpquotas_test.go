@@ -0,0 +1,71 @@
+package staker
+
+import (
+	"testing"
+
+	"github.com/babylonlabs-io/btc-staker/stakercfg"
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReserveFpQuota(t *testing.T) {
+	t.Parallel()
+
+	keyA, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+	keyB, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+	fpA, fpB := keyA.PubKey(), keyB.PubKey()
+
+	cfg := stakercfg.DefaultConfig()
+	cfg.StakerConfig.FpQuotas = []string{
+		fpKeyHex(fpA) + ":2:0",
+		"*:0:5000",
+	}
+
+	quotas, err := newFpQuotas(cfg.StakerConfig)
+	require.NoError(t, err)
+
+	store := newArchiveTestStore(t)
+	app := &App{
+		config:    &cfg,
+		logger:    logrus.New(),
+		txTracker: store,
+		statuses:  newDelegationStatusCache(),
+		fpQuotas:  quotas,
+	}
+
+	imported := genReplicaTestTransaction(t, 1000)
+	require.NoError(t, store.AddTransactionSentToBabylon(imported.StakingTx, imported.StakerAddress))
+	stakingTxHash := imported.StakingTx.TxHash()
+	app.recordFinalityProviders(&stakingTxHash, []*btcec.PublicKey{fpA}, 1000)
+
+	// one stored and one pending delegation exhaust quota of fpA
+	release, err := app.reserveFpQuota([]*btcec.PublicKey{fpA}, 100)
+	require.NoError(t, err)
+
+	_, err = app.reserveFpQuota([]*btcec.PublicKey{fpA}, 100)
+	require.ErrorIs(t, err, ErrFinalityProviderQuotaExceeded)
+
+	release()
+	release, err = app.reserveFpQuota([]*btcec.PublicKey{fpA}, 100)
+	require.NoError(t, err)
+	release()
+
+	// fpB falls back to the default quota
+	_, err = app.reserveFpQuota([]*btcec.PublicKey{fpB}, 6000)
+	require.ErrorIs(t, err, ErrFinalityProviderQuotaExceeded)
+	release, err = app.reserveFpQuota([]*btcec.PublicKey{fpB}, 4000)
+	require.NoError(t, err)
+	release()
+
+	usages, err := app.FinalityProviderQuotas()
+	require.NoError(t, err)
+	require.Len(t, usages, 1)
+	require.Equal(t, fpKeyHex(fpA), usages[0].FpBtcPk)
+	require.Equal(t, uint64(1), usages[0].Delegations)
+	require.Equal(t, btcutil.Amount(1000), usages[0].Amount)
+	require.Equal(t, uint64(2), usages[0].Quota.MaxDelegations)
+}
@@ -0,0 +1,98 @@
+package staker
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// heartbeatTimeout bounds single ping of heartbeat url
+const heartbeatTimeout = 10 * time.Second
+
+// handleHeartbeat periodically pings configured heartbeat url while staker is
+// healthy. Monitoring service alerts operator once pings stop, which also
+// covers daemon which is running, but does not make progress.
+func (app *App) handleHeartbeat() {
+	ticker := time.NewTicker(app.config.StakerConfig.HeartbeatInterval)
+	defer ticker.Stop()
+
+	client := &http.Client{Timeout: heartbeatTimeout}
+
+	for {
+		select {
+		case <-ticker.C:
+		case <-app.quit:
+			return
+		}
+
+		if reason := app.unhealthyReason(); reason != "" {
+			app.logger.WithFields(logrus.Fields{
+				"reason": reason,
+			}).Warn("Staker is not healthy, skipping heartbeat")
+			continue
+		}
+
+		// url is not logged, as it usually contains secret check id
+		if err := app.sendHeartbeat(client); err != nil {
+			app.logger.WithFields(logrus.Fields{
+				"err": err,
+			}).Warn("Failed to send heartbeat")
+		}
+	}
+}
+
+// unhealthyReason returns why staker is not healthy or empty string if all
+// subsystems are healthy
+func (app *App) unhealthyReason() string {
+	var reasons []string
+	for _, c := range app.Readiness() {
+		if !c.Ready {
+			reasons = append(reasons, fmt.Sprintf("%s: %s", c.Name, c.Error))
+		}
+	}
+
+	// chain safety check runs in a background loop, so stale result means
+	// background loops stopped making progress
+	safety := app.ChainSafetyStatus()
+	maxAge := 2 * app.config.StakerConfig.ChainSafetyCheckInterval
+	switch {
+	case safety.CheckedAt.IsZero() || time.Since(safety.CheckedAt) > maxAge:
+		reasons = append(reasons, "chain safety was not checked recently")
+	case safety.Paused && !safety.Override:
+		reasons = append(reasons, "chain safety: "+strings.Join(safety.Reasons, "; "))
+	}
+
+	return strings.Join(reasons, ", ")
+}
+
+func (app *App) sendHeartbeat(client *http.Client) error {
+	ctx, cancel := app.appQuitContext()
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, app.config.StakerConfig.HeartbeatURL, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create heartbeat request: %w", err)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		// strip the url from the error
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return fmt.Errorf("heartbeat request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("heartbeat url returned status %d", resp.StatusCode)
+	}
+
+	return nil
+}
@@ -0,0 +1,122 @@
+package staker
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/babylonlabs-io/btc-staker/testutil/mocks"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+// newHeartbeatTestApp returns app with all subsystems healthy, which pings
+// the url
+func newHeartbeatTestApp(t *testing.T, url string) *App {
+	ctrl := gomock.NewController(t)
+	bc := mocks.NewMockBabylonClient(ctrl)
+	wc := mocks.NewMockWalletController(ctrl)
+	bc.EXPECT().GetLatestBlockHeight().Return(uint64(100), nil).AnyTimes()
+	wc.EXPECT().PassphraseAvailable().Return(nil).AnyTimes()
+
+	app, _ := newStartupSyncTestApp(t, bc, wc)
+	app.quit = make(chan struct{})
+	app.config.StakerConfig.HeartbeatURL = url
+	app.config.StakerConfig.HeartbeatInterval = 10 * time.Millisecond
+	app.config.StakerConfig.ChainSafetyCheckInterval = time.Minute
+	app.startup.status.Done = true
+	app.currentBestBlockHeight.Store(200)
+	app.safety = &chainSafety{status: ChainSafetyStatus{CheckedAt: time.Now()}}
+
+	return app
+}
+
+func TestHeartbeatIsSentWhileHealthy(t *testing.T) {
+	t.Parallel()
+
+	var pings atomic.Int32
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodGet, r.Method)
+		pings.Add(1)
+	}))
+	t.Cleanup(s.Close)
+
+	app := newHeartbeatTestApp(t, s.URL+"/ping/check-id")
+	require.Empty(t, app.unhealthyReason())
+
+	app.wg.Add(1)
+	go func() {
+		defer app.wg.Done()
+		app.handleHeartbeat()
+	}()
+	t.Cleanup(func() {
+		close(app.quit)
+		app.wg.Wait()
+	})
+
+	require.Eventually(t, func() bool {
+		return pings.Load() >= 2
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// pings stop while chain safety pauses params sensitive operations
+	app.safety.mu.Lock()
+	app.safety.status.Paused = true
+	app.safety.status.Reasons = []string{"babylon height did not change"}
+	app.safety.mu.Unlock()
+	require.Equal(t, "chain safety: babylon height did not change", app.unhealthyReason())
+
+	// wait for a ping which could be in flight when the status changed
+	time.Sleep(50 * time.Millisecond)
+	paused := pings.Load()
+	time.Sleep(100 * time.Millisecond)
+	require.Equal(t, paused, pings.Load())
+
+	// and resume once operator overrides the pause
+	require.NoError(t, app.SetChainSafetyOverride(true))
+	require.Eventually(t, func() bool {
+		return pings.Load() > paused
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestHeartbeatUnhealthyReason(t *testing.T) {
+	t.Parallel()
+
+	app := newHeartbeatTestApp(t, "")
+	app.startup.status.Done = false
+	app.startup.status.Total = 3
+	app.safety.status.CheckedAt = time.Now().Add(-3 * time.Minute)
+
+	require.Equal(t,
+		"startup_sync: startup reconciliation is in progress: reconciled 0 of 3 delegations, "+
+			"chain safety was not checked recently",
+		app.unhealthyReason(),
+	)
+}
+
+func TestSendHeartbeat(t *testing.T) {
+	t.Parallel()
+
+	var status atomic.Int32
+	status.Store(http.StatusOK)
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(int(status.Load()))
+	}))
+	t.Cleanup(s.Close)
+
+	url := s.URL + "/ping/secret-check-id"
+	app := newHeartbeatTestApp(t, url)
+	client := &http.Client{Timeout: time.Second}
+
+	require.NoError(t, app.sendHeartbeat(client))
+
+	status.Store(http.StatusServiceUnavailable)
+	require.EqualError(t, app.sendHeartbeat(client), "heartbeat url returned status 503")
+
+	// url, which contains the check id, is not part of the error
+	s.Close()
+	err := app.sendHeartbeat(client)
+	require.ErrorContains(t, err, "heartbeat request failed")
+	require.NotContains(t, err.Error(), "secret-check-id")
+}
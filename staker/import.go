@@ -0,0 +1,132 @@
+package staker
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	bbntypes "github.com/babylonlabs-io/babylon/v4/types"
+	btcstypes "github.com/babylonlabs-io/babylon/v4/x/btcstaking/types"
+	"github.com/babylonlabs-io/btc-staker/stakerdb"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/sirupsen/logrus"
+)
+
+// ErrDelegationAlreadyTracked is returned when imported delegation is already
+// tracked by this staker
+var ErrDelegationAlreadyTracked = errors.New("delegation is already tracked")
+
+// ImportDelegation starts tracking delegation exported from other staker
+// instance. Delegation must be registered on Babylon with the same staking
+// transaction and its staker key must be controlled by the wallet of this
+// staker, as otherwise staker could not unbond or withdraw it.
+func (app *App) ImportDelegation(stakerAddress string, imported *stakerdb.ImportedTransaction) (string, error) {
+	if err := app.checkStartupSync(); err != nil {
+		return "", err
+	}
+
+	stakingTxHash := imported.StakingTx.TxHash()
+
+	addr, err := btcutil.DecodeAddress(stakerAddress, app.network)
+	if err != nil {
+		return "", fmt.Errorf("invalid staker address %s: %w", stakerAddress, err)
+	}
+
+	if !addr.IsForNet(app.network) {
+		return "", fmt.Errorf("staker address %s is not for network %s", stakerAddress, app.network.Name)
+	}
+	imported.StakerAddress = addr
+
+	stakerPubKey, err := app.wc.AddressPublicKey(addr)
+	if err != nil {
+		return "", fmt.Errorf("staker address %s is not controlled by the wallet: %w", stakerAddress, err)
+	}
+
+	di, err := app.babylonClient.QueryBTCDelegation(&stakingTxHash)
+	if err != nil {
+		return "", fmt.Errorf("failed to get delegation from babylon: %w", err)
+	}
+	del := di.BtcDelegation
+
+	babylonStakingTx, _, err := bbntypes.NewBTCTxFromHex(del.StakingTxHex)
+	if err != nil {
+		return "", fmt.Errorf("invalid staking transaction returned by babylon: %w", err)
+	}
+
+	if babylonStakingTx.TxHash() != stakingTxHash {
+		return "", fmt.Errorf("staking transaction does not match delegation on babylon")
+	}
+
+	delegatorPubKey, err := del.BtcPk.ToBTCPK()
+	if err != nil {
+		return "", fmt.Errorf("invalid staker public key returned by babylon: %w", err)
+	}
+
+	if !bytes.Equal(schnorr.SerializePubKey(delegatorPubKey), schnorr.SerializePubKey(stakerPubKey)) {
+		return "", fmt.Errorf("delegation on babylon is not owned by staker address %s", stakerAddress)
+	}
+
+	if int(del.StakingOutputIdx) >= len(imported.StakingTx.TxOut) {
+		return "", fmt.Errorf("staking output index %d out of range", del.StakingOutputIdx)
+	}
+
+	stakingScript, err := app.delegationStakingScript(del)
+	if err != nil {
+		return "", err
+	}
+
+	if !bytes.Equal(stakingScript, imported.StakingTx.TxOut[del.StakingOutputIdx].PkScript) {
+		return "", fmt.Errorf("staking output does not match delegation parameters on babylon")
+	}
+
+	if err := app.txTracker.ImportTransaction(imported); err != nil {
+		if errors.Is(err, stakerdb.ErrDuplicateTransaction) {
+			return "", ErrDelegationAlreadyTracked
+		}
+		return "", fmt.Errorf("failed to import delegation: %w", err)
+	}
+
+	app.logger.WithFields(logrus.Fields{
+		"stakingTxHash": stakingTxHash,
+		"stakerAddress": stakerAddress,
+		"state":         del.GetStatusDesc(),
+	}).Info("Imported delegation")
+
+	// imported delegation is watched the same way as delegations found on start
+	switch del.GetStatusDesc() {
+	case BabylonPendingStatus:
+		app.handlePendingTransaction(&stakingTxHash)
+	case BabylonVerifiedStatus:
+		app.handleVerifiedTransaction(&stakingTxHash, del.StakingOutputIdx)
+	case BabylonActiveStatus:
+		if err := app.handleImportedActiveTransaction(&stakingTxHash, di); err != nil {
+			// delegation is imported, so it is picked up again on next start
+			app.logger.WithFields(logrus.Fields{
+				"stakingTxHash": stakingTxHash,
+				"err":           err,
+			}).Error("Failed to handle imported active delegation")
+		}
+	}
+
+	return del.GetStatusDesc(), nil
+}
+
+func (app *App) handleImportedActiveTransaction(stakingTxHash *chainhash.Hash, di *btcstypes.QueryBTCDelegationResponse) error {
+	udi, err := app.babylonClient.GetUndelegationInfo(di)
+	if err != nil {
+		return fmt.Errorf("failed to get undelegation info: %w", err)
+	}
+
+	spent, err := app.wc.OutputsSpent([]wire.OutPoint{{
+		Hash:  *stakingTxHash,
+		Index: di.BtcDelegation.StakingOutputIdx,
+	}})
+	if err != nil {
+		return fmt.Errorf("failed to check staking output spentness: %w", err)
+	}
+
+	return app.handleActiveTransaction(stakingTxHash, spent[0], udi)
+}
@@ -0,0 +1,94 @@
+package staker
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// maxMaintenanceLookahead bounds merging of overlapping recurring windows,
+// which may cover all time
+const maxMaintenanceLookahead = 7 * 24 * time.Hour
+
+// ErrMaintenanceWindow is returned for non urgent operations requested during
+// maintenance window
+var ErrMaintenanceWindow = errors.New("maintenance window is active")
+
+// MaintenanceStatus describes maintenance windows at the time of the query
+type MaintenanceStatus struct {
+	// Active is true if any window is active, EndsAt is then the end of the
+	// latest ending active window
+	Active bool
+	EndsAt time.Time
+	// NextStart is zero if no window starts again
+	NextStart time.Time
+	Windows   []string
+}
+
+// maintenanceWindowEnd returns end of active maintenance window, if any.
+// Overlapping windows are merged, so that the returned end is the time at
+// which deferred work resumes.
+func (app *App) maintenanceWindowEnd(t time.Time) (time.Time, bool) {
+	var end time.Time
+	active := false
+
+	for changed := true; changed && end.Sub(t) < maxMaintenanceLookahead; {
+		changed = false
+		for i := range app.maintenance {
+			at := t
+			if active {
+				// window starting exactly when the other ends continues it
+				at = end
+			}
+
+			windowEnd, ok := app.maintenance[i].Active(at)
+			if ok && windowEnd.After(end) {
+				end = windowEnd
+				active = true
+				changed = true
+			}
+		}
+	}
+
+	return end, active
+}
+
+// checkMaintenanceWindow returns ErrMaintenanceWindow if maintenance window is
+// active
+func (app *App) checkMaintenanceWindow() error {
+	end, active := app.maintenanceWindowEnd(time.Now())
+	if !active {
+		return nil
+	}
+
+	return fmt.Errorf("%w until %s", ErrMaintenanceWindow, end.UTC().Format(time.RFC3339))
+}
+
+// MaintenanceWindowsEnabled returns true if maintenance windows are
+// configured
+func (app *App) MaintenanceWindowsEnabled() bool {
+	return len(app.maintenance) > 0
+}
+
+// MaintenanceStatus returns whether maintenance window is active and when the
+// next one starts
+func (app *App) MaintenanceStatus() MaintenanceStatus {
+	now := time.Now()
+
+	status := MaintenanceStatus{
+		Windows: make([]string, 0, len(app.maintenance)),
+	}
+	status.EndsAt, status.Active = app.maintenanceWindowEnd(now)
+
+	for i := range app.maintenance {
+		w := &app.maintenance[i]
+		status.Windows = append(status.Windows, w.Spec)
+
+		next := w.NextStart(now)
+		if !next.IsZero() && (status.NextStart.IsZero() || next.Before(status.NextStart)) {
+			status.NextStart = next
+		}
+	}
+
+	return status
+}
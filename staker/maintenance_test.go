@@ -0,0 +1,65 @@
+package staker
+
+import (
+	"testing"
+	"time"
+
+	"github.com/babylonlabs-io/btc-staker/stakercfg"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMaintenanceWindows(t *testing.T) {
+	t.Parallel()
+
+	for _, spec := range []string{
+		"02:00/1h",
+		"daily 02:00",
+		"daily 25:00/1h",
+		"someday 02:00/1h",
+		"daily 02:00/24h",
+		"sat 02:00/0s",
+	} {
+		_, err := stakercfg.ParseMaintenanceWindow(spec)
+		require.Error(t, err, spec)
+	}
+
+	cfg := stakercfg.DefaultConfig()
+	cfg.StakerConfig.MaintenanceWindows = []string{
+		"daily 23:00/2h",
+		// continues the daily window on saturdays
+		"sat 01:00/1h",
+		"2026-03-10T12:00:00Z/30m",
+	}
+
+	windows, err := cfg.StakerConfig.MaintenanceSchedule()
+	require.NoError(t, err)
+	app := &App{maintenance: windows}
+
+	// 2026-03-06 is friday
+	fri := time.Date(2026, 3, 6, 0, 0, 0, 0, time.UTC)
+
+	// daily window crosses midnight
+	end, active := app.maintenanceWindowEnd(fri.Add(30 * time.Minute))
+	require.True(t, active)
+	require.Equal(t, fri.Add(time.Hour), end)
+
+	_, active = app.maintenanceWindowEnd(fri.Add(time.Hour))
+	require.False(t, active)
+
+	// saturday window extends the daily one
+	sat := fri.Add(24 * time.Hour)
+	end, active = app.maintenanceWindowEnd(sat.Add(-30 * time.Minute))
+	require.True(t, active)
+	require.Equal(t, sat.Add(2*time.Hour), end)
+
+	// one-off window
+	oneOff := time.Date(2026, 3, 10, 12, 10, 0, 0, time.UTC)
+	end, active = app.maintenanceWindowEnd(oneOff)
+	require.True(t, active)
+	require.Equal(t, time.Date(2026, 3, 10, 12, 30, 0, 0, time.UTC), end)
+
+	next := windows[2].NextStart(oneOff)
+	require.True(t, next.IsZero())
+	next = windows[1].NextStart(fri)
+	require.Equal(t, sat.Add(time.Hour), next)
+}
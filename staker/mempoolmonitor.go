@@ -0,0 +1,215 @@
+package staker
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/babylonlabs-io/btc-staker/stakerdb"
+	"github.com/babylonlabs-io/btc-staker/walletcontroller"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// maxRebroadcasts is the number of times transaction which disappeared from
+	// mempool is rebroadcast before staker stops watching it
+	maxRebroadcasts = 10
+
+	FeePositionCompetitive   = "competitive"
+	FeePositionBelowEstimate = "below_estimate"
+)
+
+// MempoolTxStatus describes mempool state of a transaction broadcast by staker
+type MempoolTxStatus struct {
+	*stakerdb.WatchedTransaction
+	InMempool bool
+	// fee rate of the transaction in sat/vB, zero if transaction is not in mempool
+	FeeRate float64
+	// fee rate currently estimated for new transactions in sat/vB
+	EstimatedFeeRate float64
+}
+
+// FeePosition tells whether transaction fee rate can compete with currently
+// estimated fee rate
+func (s *MempoolTxStatus) FeePosition() string {
+	if s.FeeRate >= s.EstimatedFeeRate {
+		return FeePositionCompetitive
+	}
+	return FeePositionBelowEstimate
+}
+
+// watchMempoolTx starts watching transaction broadcast by staker until it is
+// confirmed. Watching is best effort, so failure is only logged.
+func (app *App) watchMempoolTx(stakingTxHash *chainhash.Hash, kind stakerdb.WatchedTxKind, tx *wire.MsgTx) {
+	err := app.txTracker.PutWatchedTransaction(&stakerdb.WatchedTransaction{
+		Tx:            tx,
+		StakingTxHash: *stakingTxHash,
+		Kind:          kind,
+		BroadcastAt:   time.Now(),
+	})
+	if err != nil {
+		app.logger.WithFields(logrus.Fields{
+			"stakingTxHash": stakingTxHash,
+			"kind":          kind,
+			"err":           err,
+		}).Error("Failed to start watching transaction in mempool")
+	}
+
+	// broadcast transaction changes state of the delegation, do not serve
+	// status from before it
+	app.statuses.remove(*stakingTxHash)
+}
+
+// unwatchMempoolTxs stops watching all transactions related to given staking
+// transaction
+func (app *App) unwatchMempoolTxs(stakingTxHash *chainhash.Hash) error {
+	watched, err := app.txTracker.WatchedTransactionsOf(stakingTxHash)
+	if err != nil {
+		return err
+	}
+
+	for _, w := range watched {
+		txHash := w.Tx.TxHash()
+		if err := app.txTracker.DeleteWatchedTransaction(&txHash); err != nil {
+			return fmt.Errorf("failed to stop watching transaction %s: %w", txHash, err)
+		}
+	}
+
+	return nil
+}
+
+// checkWatchedTransactions checks whether transactions broadcast by staker are
+// still in mempool. Transactions evicted from mempool are rebroadcast.
+func (app *App) checkWatchedTransactions() error {
+	watched, err := app.txTracker.ListWatchedTransactions()
+	if err != nil {
+		return err
+	}
+
+	for _, w := range watched {
+		if err := app.checkWatchedTransaction(w); err != nil {
+			app.logger.WithFields(logrus.Fields{
+				"txHash":        w.Tx.TxHash(),
+				"stakingTxHash": w.StakingTxHash,
+				"err":           err,
+			}).Warn("Failed to check mempool status of transaction")
+		}
+	}
+
+	return nil
+}
+
+func (app *App) checkWatchedTransaction(w *stakerdb.WatchedTransaction) error {
+	txHash := w.Tx.TxHash()
+
+	if len(w.Tx.TxOut) == 0 {
+		return app.txTracker.DeleteWatchedTransaction(&txHash)
+	}
+
+	_, status, err := app.wc.TxDetails(&txHash, w.Tx.TxOut[0].PkScript)
+	if err != nil {
+		return fmt.Errorf("failed to get tx details: %w", err)
+	}
+
+	switch status {
+	case walletcontroller.TxInChain:
+		return app.txTracker.DeleteWatchedTransaction(&txHash)
+	case walletcontroller.TxInMemPool:
+		if !w.SeenInMempoolAt.IsZero() && w.EvictedAt.IsZero() {
+			return nil
+		}
+
+		if w.SeenInMempoolAt.IsZero() {
+			w.SeenInMempoolAt = time.Now()
+			if entry, err := app.wc.GetMempoolEntry(txHash.String()); err == nil && entry.Time > 0 {
+				w.SeenInMempoolAt = time.Unix(entry.Time, 0)
+			}
+		}
+		w.EvictedAt = time.Time{}
+
+		return app.txTracker.PutWatchedTransaction(w)
+	}
+
+	if w.Rebroadcasts >= maxRebroadcasts {
+		app.logger.WithFields(logrus.Fields{
+			"txHash":        txHash,
+			"stakingTxHash": w.StakingTxHash,
+			"kind":          w.Kind,
+			"rebroadcasts":  w.Rebroadcasts,
+		}).Error("Transaction still not in mempool after rebroadcasts, stopping watching it")
+		return app.txTracker.DeleteWatchedTransaction(&txHash)
+	}
+
+	if w.EvictedAt.IsZero() {
+		w.EvictedAt = time.Now()
+
+		if !w.SeenInMempoolAt.IsZero() {
+			app.m.TransactionsEvictedFromMempool.Inc()
+		}
+
+		app.logger.WithFields(logrus.Fields{
+			"txHash":        txHash,
+			"stakingTxHash": w.StakingTxHash,
+			"kind":          w.Kind,
+		}).Warn("Transaction not found in mempool, rebroadcasting it")
+	}
+
+	w.Rebroadcasts++
+
+	if _, err := app.wc.SendRawTransaction(w.Tx, true); err != nil {
+		app.logger.WithFields(logrus.Fields{
+			"txHash":        txHash,
+			"stakingTxHash": w.StakingTxHash,
+			"kind":          w.Kind,
+			"err":           err,
+		}).Warn("Failed to rebroadcast transaction")
+	}
+
+	return app.txTracker.PutWatchedTransaction(w)
+}
+
+// MempoolTransactions returns mempool state of unconfirmed transactions
+// broadcast by staker for given staking transaction
+func (app *App) MempoolTransactions(stakingTxHash *chainhash.Hash) ([]MempoolTxStatus, error) {
+	watched, err := app.txTracker.WatchedTransactionsOf(stakingTxHash)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(watched) == 0 {
+		return nil, nil
+	}
+
+	estimatedFeeRate := float64(app.feeEstimator.EstimateFeePerKb()) / 1000
+
+	statuses := make([]MempoolTxStatus, len(watched))
+	for i, w := range watched {
+		statuses[i] = MempoolTxStatus{
+			WatchedTransaction: w,
+			EstimatedFeeRate:   estimatedFeeRate,
+		}
+
+		entry, err := app.wc.GetMempoolEntry(w.Tx.TxHash().String())
+		if err != nil || entry.VSize <= 0 {
+			// not in mempool
+			continue
+		}
+
+		fee := entry.Fees.Base
+		if fee == 0 {
+			fee = entry.Fee
+		}
+
+		feeAmount, err := btcutil.NewAmount(fee)
+		if err != nil {
+			continue
+		}
+
+		statuses[i].InMempool = true
+		statuses[i].FeeRate = float64(feeAmount) / float64(entry.VSize)
+	}
+
+	return statuses, nil
+}
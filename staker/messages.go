@@ -0,0 +1,50 @@
+package staker
+
+import (
+	"fmt"
+
+	"github.com/babylonlabs-io/babylon/v4/crypto/bip322"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/sirupsen/logrus"
+)
+
+// SignMessage signs arbitrary message with the key of the wallet address using
+// bip322 simple signature scheme. Returns serialized signature witness.
+// Only native segwit and taproot addresses are supported.
+func (app *App) SignMessage(address btcutil.Address, msg []byte) ([]byte, error) {
+	if err := app.wc.UnlockWallet(defaultWalletUnlockTimeout); err != nil {
+		return nil, fmt.Errorf("failed to unlock wallet: %w", err)
+	}
+
+	witness, err := app.wc.SignBip322Signature(msg, address)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign bip322 message: %w", err)
+	}
+
+	sig, err := bip322.SerializeWitness(witness)
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize bip322 signature: %w", err)
+	}
+
+	app.logger.WithFields(logrus.Fields{
+		"address": address,
+	}).Info("Signed bip322 message")
+
+	return sig, nil
+}
+
+// VerifyMessage verifies bip322 simple signature of the message by the
+// address. Address does not have to be controlled by the wallet.
+func (app *App) VerifyMessage(address btcutil.Address, msg []byte, sig []byte) error {
+	witness, err := bip322.SimpleSigToWitness(sig)
+	if err != nil {
+		return fmt.Errorf("failed to decode bip322 signature: %w", err)
+	}
+
+	// script errors of taproot signatures have no description
+	if err := bip322.Verify(msg, witness, address, app.network); err != nil {
+		return fmt.Errorf("invalid bip322 signature: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,993 @@
+package staker
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	staking "github.com/babylonlabs-io/babylon/v4/btcstaking"
+	bbn "github.com/babylonlabs-io/babylon/v4/types"
+	btcstktypes "github.com/babylonlabs-io/babylon/v4/x/btcstaking/types"
+	"github.com/babylonlabs-io/btc-staker/stakerdb"
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr/musig2"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/sirupsen/logrus"
+)
+
+// MuSig2SessionKind is the kind of message signed by MuSig2 staker key
+type MuSig2SessionKind string
+
+const (
+	// MuSig2SessionPop is the proof of possession of the staker key, signed
+	// over Babylon address of the staker daemon
+	MuSig2SessionPop MuSig2SessionKind = "pop"
+	// MuSig2SessionStaking is the staking transaction funded from taproot
+	// key path output of the MuSig2 key, sent once signed
+	MuSig2SessionStaking MuSig2SessionKind = "staking"
+	// MuSig2SessionUnbonding is the unbonding transaction registered on
+	// Babylon, sent once signed
+	MuSig2SessionUnbonding MuSig2SessionKind = "unbonding"
+	// MuSig2SessionWithdrawal is the transaction spending expired staking or
+	// unbonding output, sent once signed
+	MuSig2SessionWithdrawal MuSig2SessionKind = "withdrawal"
+)
+
+const (
+	minMuSig2Signers     = 2
+	maxMuSig2KeyNameLen  = 64
+	muSig2PartialSigSize = 32
+)
+
+// ParseMuSig2SessionKind parses MuSig2 session kind
+func ParseMuSig2SessionKind(s string) (MuSig2SessionKind, error) {
+	switch k := MuSig2SessionKind(s); k {
+	case MuSig2SessionPop, MuSig2SessionStaking, MuSig2SessionUnbonding, MuSig2SessionWithdrawal:
+		return k, nil
+	default:
+		return "", fmt.Errorf("unknown musig2 session kind: %s. Allowed: %s, %s, %s, %s",
+			s, MuSig2SessionPop, MuSig2SessionStaking, MuSig2SessionUnbonding, MuSig2SessionWithdrawal)
+	}
+}
+
+// keyPath returns true if the session signs taproot key path spend, in which
+// case the aggregate key is tweaked as specified by BIP86
+func (k MuSig2SessionKind) keyPath() bool {
+	return k == MuSig2SessionStaking
+}
+
+// MuSig2SessionRequest describes message signed in MuSig2 signing session
+type MuSig2SessionRequest struct {
+	KeyName string
+	Kind    MuSig2SessionKind
+	// StakingTxHash is the delegation whose unbonding or withdrawal
+	// transaction is signed
+	StakingTxHash *chainhash.Hash
+	// StakingTx is the unsigned staking transaction signed in staking session
+	StakingTx *wire.MsgTx
+	// DestAddress and Fee select output of the withdrawal transaction
+	DestAddress btcutil.Address
+	Fee         FeeSelection
+}
+
+// muSig2Spend is a transaction spending staking or unbonding output through
+// a script path whose staker key is MuSig2 aggregate key, or spending taproot
+// output of the MuSig2 key through key path if spend info is nil
+type muSig2Spend struct {
+	tx            *wire.MsgTx
+	fundingOutput *wire.TxOut
+	spendInfo     *staking.SpendInfo
+}
+
+// sigHash returns BIP341 sighash of the spend signed by the staker key
+func (s *muSig2Spend) sigHash() ([32]byte, error) {
+	var (
+		msg  [32]byte
+		hash []byte
+		err  error
+	)
+
+	fetcher := txscript.NewCannedPrevOutputFetcher(s.fundingOutput.PkScript, s.fundingOutput.Value)
+	sigHashes := txscript.NewTxSigHashes(s.tx, fetcher)
+
+	if s.spendInfo == nil {
+		hash, err = txscript.CalcTaprootSignatureHash(
+			sigHashes, txscript.SigHashDefault, s.tx, 0, fetcher,
+		)
+	} else {
+		hash, err = txscript.CalcTapscriptSignaturehash(
+			sigHashes, txscript.SigHashDefault, s.tx, 0, fetcher, s.spendInfo.RevealedLeaf,
+		)
+	}
+	if err != nil {
+		return msg, fmt.Errorf("failed to calculate sighash: %w", err)
+	}
+
+	copy(msg[:], hash)
+	return msg, nil
+}
+
+func validateMuSig2KeyName(name string) error {
+	if name == "" {
+		return errors.New("musig2 key name cannot be empty")
+	}
+
+	if len(name) > maxMuSig2KeyNameLen {
+		return fmt.Errorf("musig2 key name cannot be longer than %d characters", maxMuSig2KeyNameLen)
+	}
+
+	for _, r := range name {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		case r == '-' || r == '_' || r == '.':
+		default:
+			return fmt.Errorf("musig2 key name contains invalid character %q", r)
+		}
+	}
+
+	return nil
+}
+
+// RegisterMuSig2Key aggregates public keys of signers into a MuSig2 key which
+// can be used as staker key. Keys are sorted before aggregation, so the order
+// in which they are given does not matter.
+func (app *App) RegisterMuSig2Key(name string, pubKeys []*btcec.PublicKey) (*stakerdb.MuSig2Key, error) {
+	if err := validateMuSig2KeyName(name); err != nil {
+		return nil, err
+	}
+
+	if len(pubKeys) < minMuSig2Signers {
+		return nil, fmt.Errorf("musig2 key requires at least %d signers, got %d", minMuSig2Signers, len(pubKeys))
+	}
+
+	encoded := make([]string, 0, len(pubKeys))
+	seen := make(map[string]struct{}, len(pubKeys))
+	for _, pk := range pubKeys {
+		e := hex.EncodeToString(pk.SerializeCompressed())
+		if _, ok := seen[e]; ok {
+			return nil, fmt.Errorf("duplicate signer public key %s", e)
+		}
+		seen[e] = struct{}{}
+		encoded = append(encoded, e)
+	}
+	sort.Strings(encoded)
+
+	// aggregation sorts keys in place, caller's slice is left as it is
+	aggKey, _, _, err := musig2.AggregateKeys(append([]*btcec.PublicKey(nil), pubKeys...), true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate keys: %w", err)
+	}
+
+	key := &stakerdb.MuSig2Key{
+		Name:         name,
+		PubKeys:      encoded,
+		AggregateKey: hex.EncodeToString(schnorr.SerializePubKey(aggKey.FinalKey)),
+		CreatedAt:    time.Now().UTC(),
+	}
+
+	if err := app.txTracker.PutMuSig2Key(key); err != nil {
+		return nil, err
+	}
+
+	app.logger.WithFields(logrus.Fields{
+		"name":         name,
+		"signers":      len(pubKeys),
+		"aggregateKey": key.AggregateKey,
+	}).Info("Registered musig2 staker key")
+
+	return key, nil
+}
+
+// MuSig2Keys returns all registered MuSig2 keys
+func (app *App) MuSig2Keys() ([]stakerdb.MuSig2Key, error) {
+	return app.txTracker.ListMuSig2Keys()
+}
+
+// MuSig2Sessions returns all MuSig2 signing sessions
+func (app *App) MuSig2Sessions() ([]stakerdb.MuSig2Session, error) {
+	return app.txTracker.ListMuSig2Sessions()
+}
+
+// MuSig2Session returns MuSig2 signing session with given id
+func (app *App) MuSig2Session(id uint64) (*stakerdb.MuSig2Session, error) {
+	return app.txTracker.GetMuSig2Session(id)
+}
+
+// muSig2KeySet returns public keys of signers and aggregate key of stored
+// MuSig2 key. Aggregate key is recomputed, so that it matches the signers.
+func muSig2KeySet(key *stakerdb.MuSig2Key) ([]*btcec.PublicKey, *btcec.PublicKey, error) {
+	pubKeys := make([]*btcec.PublicKey, 0, len(key.PubKeys))
+	for _, e := range key.PubKeys {
+		pk, err := parseCompressedPubKey(e)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid signer key of musig2 key %s: %w", key.Name, err)
+		}
+		pubKeys = append(pubKeys, pk)
+	}
+
+	aggKey, _, _, err := musig2.AggregateKeys(pubKeys, true)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to aggregate keys of musig2 key %s: %w", key.Name, err)
+	}
+
+	return pubKeys, aggKey.FinalKey, nil
+}
+
+func parseCompressedPubKey(e string) (*btcec.PublicKey, error) {
+	b, err := hex.DecodeString(e)
+	if err != nil {
+		return nil, err
+	}
+	return btcec.ParsePubKey(b)
+}
+
+// StartMuSig2Session creates signing session of given kind for MuSig2 key.
+// Staking session signs staking transaction spending taproot key path output
+// of the MuSig2 key. Unbonding and withdrawal sessions sign transactions of
+// delegation with staking tx hash whose staker key is the MuSig2 key.
+// Withdrawal is sent to destination address and pays fee rate resolved from
+// fee selection.
+func (app *App) StartMuSig2Session(req *MuSig2SessionRequest) (*stakerdb.MuSig2Session, error) {
+	key, err := app.txTracker.GetMuSig2Key(req.KeyName)
+	if err != nil {
+		return nil, err
+	}
+
+	_, aggKey, err := muSig2KeySet(key)
+	if err != nil {
+		return nil, err
+	}
+
+	session := &stakerdb.MuSig2Session{
+		KeyName:     req.KeyName,
+		Kind:        string(req.Kind),
+		Nonces:      make(map[string]string),
+		PartialSigs: make(map[string]string),
+		CreatedAt:   time.Now().UTC(),
+	}
+
+	var (
+		msg   [32]byte
+		spend *muSig2Spend
+	)
+	switch req.Kind {
+	case MuSig2SessionPop:
+		// Babylon verifies BIP340 pop over sha256 of its address
+		msg = sha256.Sum256(app.babylonClient.GetKeyAddress().Bytes())
+	case MuSig2SessionStaking:
+		if req.StakingTx == nil {
+			return nil, fmt.Errorf("staking transaction is required for %s session", req.Kind)
+		}
+		spend, err = app.muSig2StakingSpend(aggKey, req.StakingTx)
+	case MuSig2SessionUnbonding, MuSig2SessionWithdrawal:
+		if req.StakingTxHash == nil {
+			return nil, fmt.Errorf("staking transaction hash is required for %s session", req.Kind)
+		}
+
+		if req.Kind == MuSig2SessionUnbonding {
+			spend, err = app.muSig2UnbondingSpend(aggKey, req.StakingTxHash)
+		} else {
+			if req.DestAddress == nil {
+				return nil, fmt.Errorf("destination address is required for %s session", req.Kind)
+			}
+			spend, err = app.muSig2WithdrawalSpend(aggKey, req.StakingTxHash, req.DestAddress, req.Fee)
+		}
+	default:
+		return nil, fmt.Errorf("unknown musig2 session kind: %s", req.Kind)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var stakingTxHash *chainhash.Hash
+	if spend != nil {
+		msg, err = spend.sigHash()
+		if err != nil {
+			return nil, err
+		}
+
+		var txBuf bytes.Buffer
+		if err := spend.tx.Serialize(&txBuf); err != nil {
+			return nil, fmt.Errorf("failed to serialize %s transaction: %w", req.Kind, err)
+		}
+
+		stakingTxHash = req.StakingTxHash
+		if req.Kind == MuSig2SessionStaking {
+			txHash := spend.tx.TxHash()
+			stakingTxHash = &txHash
+		}
+
+		session.StakingTxHash = stakingTxHash.String()
+		session.TxHex = hex.EncodeToString(txBuf.Bytes())
+	}
+
+	session.Message = hex.EncodeToString(msg[:])
+
+	id, err := app.txTracker.AddMuSig2Session(session)
+	if err != nil {
+		return nil, err
+	}
+	session.ID = id
+
+	app.logger.WithFields(logrus.Fields{
+		"id":            id,
+		"key":           req.KeyName,
+		"kind":          req.Kind,
+		"stakingTxHash": stakingTxHash,
+	}).Info("Started musig2 signing session")
+
+	return session, nil
+}
+
+// muSig2StakingSpend checks that staking transaction has single input, which
+// spends BIP86 taproot output of the MuSig2 key
+func (app *App) muSig2StakingSpend(aggKey *btcec.PublicKey, stakingTx *wire.MsgTx) (*muSig2Spend, error) {
+	if len(stakingTx.TxIn) != 1 {
+		return nil, fmt.Errorf("staking transaction signed by musig2 key must have exactly 1 input, got %d", len(stakingTx.TxIn))
+	}
+
+	if len(stakingTx.TxOut) == 0 {
+		return nil, errors.New("staking transaction must have outputs")
+	}
+
+	for _, in := range stakingTx.TxIn {
+		if len(in.Witness) > 0 || len(in.SignatureScript) > 0 {
+			return nil, errors.New("staking transaction must not be signed")
+		}
+	}
+
+	prevOuts, err := app.prevOutputs(stakingTx)
+	if err != nil {
+		return nil, err
+	}
+
+	keyPathScript, err := muSig2KeyPathScript(aggKey)
+	if err != nil {
+		return nil, err
+	}
+
+	if !bytes.Equal(prevOuts[0].PkScript, keyPathScript) {
+		return nil, fmt.Errorf("input %s does not spend taproot output of the musig2 key",
+			stakingTx.TxIn[0].PreviousOutPoint)
+	}
+
+	return &muSig2Spend{
+		tx:            stakingTx,
+		fundingOutput: prevOuts[0],
+	}, nil
+}
+
+// muSig2KeyPathScript returns pk script of BIP86 taproot output of the MuSig2
+// key, spendable only through key path
+func muSig2KeyPathScript(aggKey *btcec.PublicKey) ([]byte, error) {
+	return txscript.PayToTaprootScript(txscript.ComputeTaprootKeyNoScript(aggKey))
+}
+
+// muSig2Delegation returns delegation whose staker key must be the MuSig2
+// aggregate key, together with its staking transaction and params
+func (app *App) muSig2Delegation(
+	aggKey *btcec.PublicKey,
+	stakingTxHash *chainhash.Hash,
+) (*btcstktypes.QueryBTCDelegationResponse, *wire.MsgTx, []*btcec.PublicKey, error) {
+	di, err := app.babylonClient.QueryBTCDelegation(stakingTxHash)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("error getting delegation info: %w", err)
+	}
+	del := di.BtcDelegation
+
+	if del == nil || !bbn.NewBIP340PubKeyFromBTCPK(aggKey).Equals(del.BtcPk) {
+		return nil, nil, nil, fmt.Errorf("staker key of delegation %s is not the musig2 key", stakingTxHash)
+	}
+
+	stakingTx, _, err := bbn.NewBTCTxFromHex(del.StakingTxHex)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to decode staking transaction: %w", err)
+	}
+
+	if int(del.StakingOutputIdx) >= len(stakingTx.TxOut) {
+		return nil, nil, nil, fmt.Errorf("staking output index %d out of range", del.StakingOutputIdx)
+	}
+
+	fpBtcPubkeys, err := convertFpBtcPkToBtcPk(del.FpBtcPkList)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("error converting fpBtcPkList to btcPkList: %w", err)
+	}
+
+	return di, stakingTx, fpBtcPubkeys, nil
+}
+
+// muSig2UnbondingSpend returns unbonding transaction registered on Babylon for
+// delegation of the MuSig2 key
+func (app *App) muSig2UnbondingSpend(aggKey *btcec.PublicKey, stakingTxHash *chainhash.Hash) (*muSig2Spend, error) {
+	di, stakingTx, fpBtcPubkeys, err := app.muSig2Delegation(aggKey, stakingTxHash)
+	if err != nil {
+		return nil, err
+	}
+	del := di.BtcDelegation
+
+	params, err := app.babylonClient.ParamsByVersion(del.ParamsVersion)
+	if err != nil {
+		return nil, fmt.Errorf("error getting params version %d: %w", del.ParamsVersion, err)
+	}
+
+	undelegationInfo, err := app.babylonClient.GetUndelegationInfo(di)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get undelegation info from babylon: %w", err)
+	}
+
+	if len(undelegationInfo.CovenantUnbondingSignatures) < int(params.CovenantQuruomThreshold) {
+		return nil, fmt.Errorf("not enough covenant unbonding signatures: have %d, need %d",
+			len(undelegationInfo.CovenantUnbondingSignatures), params.CovenantQuruomThreshold)
+	}
+
+	stakingOutput := stakingTx.TxOut[del.StakingOutputIdx]
+	stakingInfo, err := staking.BuildStakingInfo(
+		aggKey,
+		fpBtcPubkeys,
+		params.CovenantPks,
+		params.CovenantQuruomThreshold,
+		uint16(del.StakingTime),
+		btcutil.Amount(stakingOutput.Value),
+		app.network,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build staking info: %w", err)
+	}
+
+	if !bytes.Equal(stakingInfo.StakingOutput.PkScript, stakingOutput.PkScript) {
+		return nil, fmt.Errorf("staking output script does not match delegation data")
+	}
+
+	spendInfo, err := stakingInfo.UnbondingPathSpendInfo()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build unbonding path spend info: %w", err)
+	}
+
+	return &muSig2Spend{
+		tx:            undelegationInfo.UnbondingTransaction,
+		fundingOutput: stakingOutput,
+		spendInfo:     spendInfo,
+	}, nil
+}
+
+// muSig2WithdrawalSpend builds transaction spending timelock path of
+// unbonding output if unbonding transaction is confirmed, or of staking
+// output otherwise
+func (app *App) muSig2WithdrawalSpend(
+	aggKey *btcec.PublicKey,
+	stakingTxHash *chainhash.Hash,
+	destAddress btcutil.Address,
+	fee FeeSelection,
+) (*muSig2Spend, error) {
+	di, stakingTx, fpBtcPubkeys, err := app.muSig2Delegation(aggKey, stakingTxHash)
+	if err != nil {
+		return nil, err
+	}
+	del := di.BtcDelegation
+
+	params, err := app.babylonClient.ParamsByVersion(del.ParamsVersion)
+	if err != nil {
+		return nil, fmt.Errorf("error getting params version %d: %w", del.ParamsVersion, err)
+	}
+
+	destAddressScript, err := txscript.PayToAddrScript(destAddress)
+	if err != nil {
+		return nil, fmt.Errorf("cannot build destination script: %w", err)
+	}
+
+	feeRate, err := app.resolveFeeRate(fee)
+	if err != nil {
+		return nil, err
+	}
+
+	undelegationInfo, err := app.babylonClient.GetUndelegationInfo(di)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get undelegation info from babylon: %w", err)
+	}
+
+	unbondingTxHash := undelegationInfo.UnbondingTransaction.TxHash()
+	confirmation, _, err := app.wc.TxDetails(&unbondingTxHash, undelegationInfo.UnbondingTransaction.TxOut[0].PkScript)
+	if err != nil {
+		return nil, fmt.Errorf("error getting unbonding transaction confirmation info from btc: %w", err)
+	}
+
+	var info *spendStakeTxInfo
+	if confirmation != nil && confirmation.BlockHash != nil && confirmation.BlockHeight > 0 {
+		info, err = createSpendStakeTxUnbondingConfirmed(
+			aggKey,
+			fpBtcPubkeys,
+			params.CovenantPks,
+			params.CovenantQuruomThreshold,
+			destAddressScript,
+			feeRate,
+			app.minSpendValue(),
+			undelegationInfo,
+			app.network,
+		)
+	} else {
+		info, err = createSpendStakeTxUnbondingNotConfirmed(
+			aggKey,
+			del.StakingOutputIdx,
+			uint16(del.StakingTime),
+			fpBtcPubkeys,
+			params.CovenantPks,
+			params.CovenantQuruomThreshold,
+			&stakerdb.StoredTransaction{StakingTx: stakingTx},
+			destAddressScript,
+			feeRate,
+			app.minSpendValue(),
+			app.network,
+		)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to build withdrawal transaction: %w", err)
+	}
+
+	return &muSig2Spend{
+		tx:            info.spendStakeTx,
+		fundingOutput: info.fundingOutput,
+		spendInfo:     info.fundingOutputSpendInfo,
+	}, nil
+}
+
+// SubmitMuSig2Nonce stores public nonce of a signer of the session. Once all
+// signers submitted nonces, the aggregate nonce is computed.
+func (app *App) SubmitMuSig2Nonce(id uint64, signer *btcec.PublicKey, nonce []byte) (*stakerdb.MuSig2Session, error) {
+	if len(nonce) != musig2.PubNonceSize {
+		return nil, fmt.Errorf("public nonce must have %d bytes, got %d", musig2.PubNonceSize, len(nonce))
+	}
+
+	session, err := app.txTracker.GetMuSig2Session(id)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := app.txTracker.GetMuSig2Key(session.KeyName)
+	if err != nil {
+		return nil, err
+	}
+
+	signerHex := hex.EncodeToString(signer.SerializeCompressed())
+	if !isMuSig2Signer(key, signerHex) {
+		return nil, fmt.Errorf("%s is not a signer of musig2 key %s", signerHex, key.Name)
+	}
+
+	return app.txTracker.UpdateMuSig2Session(id, func(s *stakerdb.MuSig2Session) error {
+		if s.AggregateNonce != "" {
+			return errors.New("all nonces of the session were already submitted")
+		}
+
+		if _, ok := s.Nonces[signerHex]; ok {
+			return fmt.Errorf("nonce of signer %s was already submitted", signerHex)
+		}
+
+		s.Nonces[signerHex] = hex.EncodeToString(nonce)
+		if len(s.Nonces) < len(key.PubKeys) {
+			return nil
+		}
+
+		pubNonces := make([][musig2.PubNonceSize]byte, 0, len(s.Nonces))
+		for _, e := range s.Nonces {
+			var n [musig2.PubNonceSize]byte
+			b, err := hex.DecodeString(e)
+			if err != nil || len(b) != musig2.PubNonceSize {
+				return fmt.Errorf("invalid stored nonce")
+			}
+			copy(n[:], b)
+			pubNonces = append(pubNonces, n)
+		}
+
+		aggNonce, err := musig2.AggregateNonces(pubNonces)
+		if err != nil {
+			return fmt.Errorf("failed to aggregate nonces: %w", err)
+		}
+
+		s.AggregateNonce = hex.EncodeToString(aggNonce[:])
+		return nil
+	})
+}
+
+// SubmitMuSig2PartialSig verifies and stores partial signature of a signer of
+// the session. Once all signers submitted partial signatures, they are
+// combined into schnorr signature of the aggregate key. Signed unbonding and
+// withdrawal transactions are then sent to btc.
+func (app *App) SubmitMuSig2PartialSig(id uint64, signer *btcec.PublicKey, partialSig []byte) (*stakerdb.MuSig2Session, error) {
+	if len(partialSig) != muSig2PartialSigSize {
+		return nil, fmt.Errorf("partial signature must have %d bytes, got %d", muSig2PartialSigSize, len(partialSig))
+	}
+
+	var sig musig2.PartialSignature
+	if err := sig.Decode(bytes.NewReader(partialSig)); err != nil {
+		return nil, fmt.Errorf("invalid partial signature: %w", err)
+	}
+
+	session, err := app.txTracker.GetMuSig2Session(id)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := app.txTracker.GetMuSig2Key(session.KeyName)
+	if err != nil {
+		return nil, err
+	}
+
+	pubKeys, aggKey, err := muSig2KeySet(key)
+	if err != nil {
+		return nil, err
+	}
+
+	signerHex := hex.EncodeToString(signer.SerializeCompressed())
+	if !isMuSig2Signer(key, signerHex) {
+		return nil, fmt.Errorf("%s is not a signer of musig2 key %s", signerHex, key.Name)
+	}
+
+	updated, err := app.txTracker.UpdateMuSig2Session(id, func(s *stakerdb.MuSig2Session) error {
+		if s.AggregateNonce == "" {
+			return errors.New("partial signatures can be submitted only after nonces of all signers")
+		}
+
+		if s.Signature != "" {
+			return errors.New("session is already completed")
+		}
+
+		if _, ok := s.PartialSigs[signerHex]; ok {
+			return fmt.Errorf("partial signature of signer %s was already submitted", signerHex)
+		}
+
+		msg, aggNonce, signerNonce, err := decodeMuSig2SessionInputs(s, signerHex)
+		if err != nil {
+			return err
+		}
+
+		signOpts := []musig2.SignOption{musig2.WithSortedKeys()}
+		if MuSig2SessionKind(s.Kind).keyPath() {
+			signOpts = append(signOpts, musig2.WithBip86SignTweak())
+		}
+
+		if !sig.Verify(signerNonce, aggNonce, pubKeys, signer, msg, signOpts...) {
+			return fmt.Errorf("invalid partial signature of signer %s", signerHex)
+		}
+
+		s.PartialSigs[signerHex] = hex.EncodeToString(partialSig)
+		if len(s.PartialSigs) < len(key.PubKeys) {
+			return nil
+		}
+
+		finalSig, err := combineMuSig2PartialSigs(s, pubKeys, aggKey, msg, aggNonce)
+		if err != nil {
+			return err
+		}
+
+		s.Signature = hex.EncodeToString(finalSig.Serialize())
+		s.CompletedAt = time.Now().UTC()
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if updated.Signature == "" || updated.BroadcastTxHash != "" {
+		return updated, nil
+	}
+
+	app.logger.WithFields(logrus.Fields{
+		"id":   id,
+		"kind": updated.Kind,
+	}).Info("Musig2 signing session completed")
+
+	switch MuSig2SessionKind(updated.Kind) {
+	case MuSig2SessionStaking, MuSig2SessionUnbonding, MuSig2SessionWithdrawal:
+		return app.sendMuSig2SignedTx(updated, aggKey)
+	default:
+		return updated, nil
+	}
+}
+
+func isMuSig2Signer(key *stakerdb.MuSig2Key, signerHex string) bool {
+	for _, pk := range key.PubKeys {
+		if pk == signerHex {
+			return true
+		}
+	}
+	return false
+}
+
+func decodeMuSig2SessionInputs(
+	s *stakerdb.MuSig2Session,
+	signerHex string,
+) ([32]byte, [musig2.PubNonceSize]byte, [musig2.PubNonceSize]byte, error) {
+	var (
+		msg         [32]byte
+		aggNonce    [musig2.PubNonceSize]byte
+		signerNonce [musig2.PubNonceSize]byte
+	)
+
+	for _, f := range []struct {
+		dst []byte
+		src string
+	}{
+		{msg[:], s.Message},
+		{aggNonce[:], s.AggregateNonce},
+		{signerNonce[:], s.Nonces[signerHex]},
+	} {
+		b, err := hex.DecodeString(f.src)
+		if err != nil || len(b) != len(f.dst) {
+			return msg, aggNonce, signerNonce, errors.New("invalid stored session data")
+		}
+		copy(f.dst, b)
+	}
+
+	return msg, aggNonce, signerNonce, nil
+}
+
+// combineMuSig2PartialSigs combines partial signatures of all signers and
+// verifies resulting signature against the aggregate key, tweaked as
+// specified by BIP86 if the session signs taproot key path spend
+func combineMuSig2PartialSigs(
+	s *stakerdb.MuSig2Session,
+	pubKeys []*btcec.PublicKey,
+	aggKey *btcec.PublicKey,
+	msg [32]byte,
+	aggNonce [musig2.PubNonceSize]byte,
+) (*schnorr.Signature, error) {
+	signingKey := aggKey
+	var combineOpts []musig2.CombineOption
+	if MuSig2SessionKind(s.Kind).keyPath() {
+		signingKey = txscript.ComputeTaprootKeyNoScript(aggKey)
+		combineOpts = append(combineOpts, musig2.WithBip86TweakedCombine(msg, pubKeys, true))
+	}
+
+	finalNonce, err := muSig2FinalNonce(aggNonce, signingKey, msg)
+	if err != nil {
+		return nil, err
+	}
+
+	partialSigs := make([]*musig2.PartialSignature, 0, len(s.PartialSigs))
+	for _, e := range s.PartialSigs {
+		b, err := hex.DecodeString(e)
+		if err != nil {
+			return nil, errors.New("invalid stored partial signature")
+		}
+
+		var ps musig2.PartialSignature
+		if err := ps.Decode(bytes.NewReader(b)); err != nil {
+			return nil, fmt.Errorf("invalid stored partial signature: %w", err)
+		}
+		partialSigs = append(partialSigs, &ps)
+	}
+
+	finalSig := musig2.CombineSigs(finalNonce, partialSigs, combineOpts...)
+	if !finalSig.Verify(msg[:], signingKey) {
+		return nil, errors.New("combined signature is not valid for the aggregate key")
+	}
+
+	return finalSig, nil
+}
+
+// muSig2FinalNonce computes nonce R = R1 + b*R2 of the final signature, where
+// b = H(aggregate nonce || aggregate key || msg), as specified by BIP327
+func muSig2FinalNonce(
+	aggNonce [musig2.PubNonceSize]byte,
+	aggKey *btcec.PublicKey,
+	msg [32]byte,
+) (*btcec.PublicKey, error) {
+	var buf bytes.Buffer
+	buf.Write(aggNonce[:])
+	buf.Write(schnorr.SerializePubKey(aggKey))
+	buf.Write(msg[:])
+
+	var b btcec.ModNScalar
+	blindHash := chainhash.TaggedHash(musig2.NonceBlindTag, buf.Bytes())
+	b.SetByteSlice(blindHash[:])
+
+	r1, err := btcec.ParseJacobian(aggNonce[:btcec.PubKeyBytesLenCompressed])
+	if err != nil {
+		return nil, fmt.Errorf("invalid aggregate nonce: %w", err)
+	}
+	r2, err := btcec.ParseJacobian(aggNonce[btcec.PubKeyBytesLenCompressed:])
+	if err != nil {
+		return nil, fmt.Errorf("invalid aggregate nonce: %w", err)
+	}
+
+	var r btcec.JacobianPoint
+	btcec.ScalarMultNonConst(&b, &r2, &r2)
+	btcec.AddNonConst(&r1, &r2, &r)
+
+	// point at infinity is replaced by generator
+	if (r.X.IsZero() && r.Y.IsZero()) || r.Z.IsZero() {
+		btcec.Generator().AsJacobian(&r)
+	}
+
+	r.ToAffine()
+	return btcec.NewPublicKey(&r.X, &r.Y), nil
+}
+
+// sendMuSig2SignedTx builds witness of the transaction signed in the session
+// and sends it to btc. Failure to send is stored in the session, signature
+// stays available.
+func (app *App) sendMuSig2SignedTx(session *stakerdb.MuSig2Session, aggKey *btcec.PublicKey) (*stakerdb.MuSig2Session, error) {
+	stakingTxHash, err := chainhash.NewHashFromStr(session.StakingTxHash)
+	if err != nil {
+		return nil, fmt.Errorf("invalid staking tx hash of session: %w", err)
+	}
+
+	tx, witness, err := app.muSig2SignedTx(session, aggKey, stakingTxHash)
+	var txHash *chainhash.Hash
+	if err == nil {
+		tx.TxIn[0].Witness = witness
+		txHash, err = app.wc.SendRawTransaction(tx, true)
+	}
+
+	return app.txTracker.UpdateMuSig2Session(session.ID, func(s *stakerdb.MuSig2Session) error {
+		if err != nil {
+			app.logger.WithFields(logrus.Fields{
+				"id":   session.ID,
+				"kind": session.Kind,
+				"err":  err,
+			}).Error("Failed to send transaction signed by musig2 key")
+			s.Error = err.Error()
+			return nil
+		}
+
+		app.logger.WithFields(logrus.Fields{
+			"id":     session.ID,
+			"kind":   session.Kind,
+			"txHash": txHash,
+		}).Info("Sent transaction signed by musig2 key")
+		s.BroadcastTxHash = txHash.String()
+		s.Error = ""
+		return nil
+	})
+}
+
+// muSig2SignedTx returns transaction of completed session together with its
+// witness. Spend path is rebuilt from delegation data, the transaction is
+// the one whose sighash was signed.
+func (app *App) muSig2SignedTx(
+	session *stakerdb.MuSig2Session,
+	aggKey *btcec.PublicKey,
+	stakingTxHash *chainhash.Hash,
+) (*wire.MsgTx, wire.TxWitness, error) {
+	tx, _, err := bbn.NewBTCTxFromHex(session.TxHex)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid transaction of session: %w", err)
+	}
+
+	sigBytes, err := hex.DecodeString(session.Signature)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid signature of session: %w", err)
+	}
+
+	sig, err := schnorr.ParseSignature(sigBytes)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid signature of session: %w", err)
+	}
+
+	switch MuSig2SessionKind(session.Kind) {
+	case MuSig2SessionStaking:
+		// key path spend of BIP86 output is witnessed by signature alone
+		return tx, wire.TxWitness{sig.Serialize()}, nil
+	case MuSig2SessionUnbonding:
+		spend, err := app.muSig2UnbondingSpend(aggKey, stakingTxHash)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if spend.tx.TxHash() != tx.TxHash() {
+			return nil, nil, errors.New("unbonding transaction registered on babylon changed")
+		}
+
+		di, err := app.babylonClient.QueryBTCDelegation(stakingTxHash)
+		if err != nil {
+			return nil, nil, fmt.Errorf("error getting delegation info: %w", err)
+		}
+
+		params, err := app.babylonClient.ParamsByVersion(di.BtcDelegation.ParamsVersion)
+		if err != nil {
+			return nil, nil, fmt.Errorf("error getting params version %d: %w", di.BtcDelegation.ParamsVersion, err)
+		}
+
+		undelegationInfo, err := app.babylonClient.GetUndelegationInfo(di)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get undelegation info from babylon: %w", err)
+		}
+
+		covenantSigs, err := createWitnessSignaturesForPubKeys(
+			params.CovenantPks,
+			params.CovenantQuruomThreshold,
+			undelegationInfo.CovenantUnbondingSignatures,
+		)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		witness, err := spend.spendInfo.CreateUnbondingPathWitness(covenantSigs, sig)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to build unbonding witness: %w", err)
+		}
+
+		return tx, witness, nil
+	case MuSig2SessionWithdrawal:
+		spendInfo, err := app.muSig2WithdrawalSpendInfo(aggKey, stakingTxHash, tx)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		witness, err := spendInfo.CreateTimeLockPathWitness(sig)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to build withdrawal witness: %w", err)
+		}
+
+		return tx, witness, nil
+	default:
+		return nil, nil, fmt.Errorf("session of kind %s does not sign a transaction", session.Kind)
+	}
+}
+
+// muSig2WithdrawalSpendInfo returns timelock spend path of the output spent
+// by withdrawal transaction, which is either staking or unbonding output
+func (app *App) muSig2WithdrawalSpendInfo(
+	aggKey *btcec.PublicKey,
+	stakingTxHash *chainhash.Hash,
+	withdrawalTx *wire.MsgTx,
+) (*staking.SpendInfo, error) {
+	di, stakingTx, fpBtcPubkeys, err := app.muSig2Delegation(aggKey, stakingTxHash)
+	if err != nil {
+		return nil, err
+	}
+	del := di.BtcDelegation
+
+	params, err := app.babylonClient.ParamsByVersion(del.ParamsVersion)
+	if err != nil {
+		return nil, fmt.Errorf("error getting params version %d: %w", del.ParamsVersion, err)
+	}
+
+	spent := withdrawalTx.TxIn[0].PreviousOutPoint
+	if spent.Hash == *stakingTxHash {
+		stakingInfo, err := staking.BuildStakingInfo(
+			aggKey,
+			fpBtcPubkeys,
+			params.CovenantPks,
+			params.CovenantQuruomThreshold,
+			uint16(del.StakingTime),
+			btcutil.Amount(stakingTx.TxOut[del.StakingOutputIdx].Value),
+			app.network,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to build staking info: %w", err)
+		}
+		return stakingInfo.TimeLockPathSpendInfo()
+	}
+
+	undelegationInfo, err := app.babylonClient.GetUndelegationInfo(di)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get undelegation info from babylon: %w", err)
+	}
+
+	if spent.Hash != undelegationInfo.UnbondingTransaction.TxHash() {
+		return nil, errors.New("withdrawal transaction spends neither staking nor unbonding output")
+	}
+
+	unbondingInfo, err := staking.BuildUnbondingInfo(
+		aggKey,
+		fpBtcPubkeys,
+		params.CovenantPks,
+		params.CovenantQuruomThreshold,
+		undelegationInfo.UnbondingTime,
+		btcutil.Amount(undelegationInfo.UnbondingTransaction.TxOut[0].Value),
+		app.network,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build unbonding info: %w", err)
+	}
+
+	return unbondingInfo.TimeLockPathSpendInfo()
+}
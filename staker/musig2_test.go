@@ -0,0 +1,282 @@
+package staker_test
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+
+	"github.com/babylonlabs-io/btc-staker/staker"
+	"github.com/babylonlabs-io/btc-staker/stakercfg"
+	"github.com/babylonlabs-io/btc-staker/stakerdb"
+	"github.com/babylonlabs-io/btc-staker/testutil/simulation"
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr/musig2"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+)
+
+// muSig2Signer is a signer of MuSig2 key holding its private key outside of
+// the staker, as co-signers of shared custody do
+type muSig2Signer struct {
+	privKey *btcec.PrivateKey
+	nonces  *musig2.Nonces
+}
+
+func newMuSig2Signers(t *testing.T, n int) []*muSig2Signer {
+	signers := make([]*muSig2Signer, n)
+	for i := range signers {
+		privKey, err := btcec.NewPrivateKey()
+		require.NoError(t, err)
+		signers[i] = &muSig2Signer{privKey: privKey}
+	}
+	return signers
+}
+
+func signerPubKeys(signers []*muSig2Signer) []*btcec.PublicKey {
+	pubKeys := make([]*btcec.PublicKey, len(signers))
+	for i, s := range signers {
+		pubKeys[i] = s.privKey.PubKey()
+	}
+	return pubKeys
+}
+
+// newSimulatedApp creates app backed by simulation, config of the simulation
+// can be adjusted by configure functions
+func newSimulatedApp(t *testing.T, configure ...func(cfg *stakercfg.Config)) (*simulation.Simulation, *staker.App) {
+	sim, err := simulation.New([]byte("musig2"))
+	require.NoError(t, err)
+
+	dbCfg := stakercfg.DefaultDBConfig()
+	dbCfg.DBPath = t.TempDir()
+	dbCfg.NoSync = true
+
+	db, err := stakercfg.GetDBBackend(&dbCfg)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = db.Close()
+	})
+
+	logger := logrus.New()
+	logger.SetLevel(logrus.WarnLevel)
+
+	cfg := sim.Config()
+	for _, c := range configure {
+		c(cfg)
+	}
+
+	app, err := sim.NewApp(cfg, db, logger)
+	require.NoError(t, err)
+
+	return sim, app
+}
+
+// submitNonces generates fresh nonce of every signer and submits it
+func submitNonces(t *testing.T, app *staker.App, id uint64, signers []*muSig2Signer) *stakerdb.MuSig2Session {
+	var session *stakerdb.MuSig2Session
+	for _, s := range signers {
+		nonces, err := musig2.GenNonces(musig2.WithPublicKey(s.privKey.PubKey()))
+		require.NoError(t, err)
+		s.nonces = nonces
+
+		session, err = app.SubmitMuSig2Nonce(id, s.privKey.PubKey(), nonces.PubNonce[:])
+		require.NoError(t, err)
+	}
+	return session
+}
+
+func partialSig(
+	t *testing.T,
+	s *muSig2Signer,
+	session *stakerdb.MuSig2Session,
+	pubKeys []*btcec.PublicKey,
+	opts ...musig2.SignOption,
+) []byte {
+	var msg [32]byte
+	msgBytes, err := hex.DecodeString(session.Message)
+	require.NoError(t, err)
+	copy(msg[:], msgBytes)
+
+	var aggNonce [musig2.PubNonceSize]byte
+	aggNonceBytes, err := hex.DecodeString(session.AggregateNonce)
+	require.NoError(t, err)
+	copy(aggNonce[:], aggNonceBytes)
+
+	// signing with sorted keys sorts the keys in place, callers' slice must
+	// keep its order
+	keys := append([]*btcec.PublicKey(nil), pubKeys...)
+
+	opts = append([]musig2.SignOption{musig2.WithSortedKeys()}, opts...)
+	sig, err := musig2.Sign(s.nonces.SecNonce, s.privKey, aggNonce, keys, msg, opts...)
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, sig.Encode(&buf))
+	return buf.Bytes()
+}
+
+func TestMuSig2PopSessionCombinesPartialSignatures(t *testing.T) {
+	t.Parallel()
+	_, app := newSimulatedApp(t)
+
+	signers := newMuSig2Signers(t, 2)
+	pubKeys := signerPubKeys(signers)
+
+	key, err := app.RegisterMuSig2Key("custody", pubKeys)
+	require.NoError(t, err)
+
+	session, err := app.StartMuSig2Session(&staker.MuSig2SessionRequest{
+		KeyName: "custody",
+		Kind:    staker.MuSig2SessionPop,
+	})
+	require.NoError(t, err)
+
+	session = submitNonces(t, app, session.ID, signers)
+	require.NotEmpty(t, session.AggregateNonce)
+
+	// partial signature of other signer is rejected
+	_, err = app.SubmitMuSig2PartialSig(session.ID, signers[1].privKey.PubKey(), partialSig(t, signers[0], session, pubKeys))
+	require.Error(t, err)
+
+	for _, s := range signers {
+		session, err = app.SubmitMuSig2PartialSig(session.ID, s.privKey.PubKey(), partialSig(t, s, session, pubKeys))
+		require.NoError(t, err)
+	}
+	require.NotEmpty(t, session.Signature)
+
+	sigBytes, err := hex.DecodeString(session.Signature)
+	require.NoError(t, err)
+	sig, err := schnorr.ParseSignature(sigBytes)
+	require.NoError(t, err)
+
+	aggKeyBytes, err := hex.DecodeString(key.AggregateKey)
+	require.NoError(t, err)
+	aggKey, err := schnorr.ParsePubKey(aggKeyBytes)
+	require.NoError(t, err)
+
+	msg, err := hex.DecodeString(session.Message)
+	require.NoError(t, err)
+	require.True(t, sig.Verify(msg, aggKey))
+
+	_, err = app.SubmitMuSig2PartialSig(session.ID, pubKeys[0], partialSig(t, signers[0], session, pubKeys))
+	require.Error(t, err)
+}
+
+func TestMuSig2NonceCannotBeReused(t *testing.T) {
+	t.Parallel()
+	_, app := newSimulatedApp(t)
+
+	signers := newMuSig2Signers(t, 2)
+	_, err := app.RegisterMuSig2Key("custody", signerPubKeys(signers))
+	require.NoError(t, err)
+
+	first, err := app.StartMuSig2Session(&staker.MuSig2SessionRequest{KeyName: "custody", Kind: staker.MuSig2SessionPop})
+	require.NoError(t, err)
+	second, err := app.StartMuSig2Session(&staker.MuSig2SessionRequest{KeyName: "custody", Kind: staker.MuSig2SessionPop})
+	require.NoError(t, err)
+
+	nonces, err := musig2.GenNonces(musig2.WithPublicKey(signers[0].privKey.PubKey()))
+	require.NoError(t, err)
+
+	_, err = app.SubmitMuSig2Nonce(first.ID, signers[0].privKey.PubKey(), nonces.PubNonce[:])
+	require.NoError(t, err)
+
+	// same signer cannot replace its nonce in the session
+	other, err := musig2.GenNonces(musig2.WithPublicKey(signers[0].privKey.PubKey()))
+	require.NoError(t, err)
+	_, err = app.SubmitMuSig2Nonce(first.ID, signers[0].privKey.PubKey(), other.PubNonce[:])
+	require.Error(t, err)
+
+	// nonce used in one session is rejected in any other, even for other signer
+	_, err = app.SubmitMuSig2Nonce(second.ID, signers[0].privKey.PubKey(), nonces.PubNonce[:])
+	require.ErrorIs(t, err, stakerdb.ErrMuSig2NonceReused)
+	_, err = app.SubmitMuSig2Nonce(second.ID, signers[1].privKey.PubKey(), nonces.PubNonce[:])
+	require.ErrorIs(t, err, stakerdb.ErrMuSig2NonceReused)
+
+	stored, err := app.MuSig2Session(second.ID)
+	require.NoError(t, err)
+	require.Empty(t, stored.Nonces)
+}
+
+func TestMuSig2StakingSessionSendsKeyPathSpend(t *testing.T) {
+	t.Parallel()
+	sim, app := newSimulatedApp(t)
+
+	signers := newMuSig2Signers(t, 2)
+	pubKeys := signerPubKeys(signers)
+
+	_, err := app.RegisterMuSig2Key("custody", pubKeys)
+	require.NoError(t, err)
+
+	aggKey, _, _, err := musig2.AggregateKeys(pubKeys, true)
+	require.NoError(t, err)
+	custodyScript, err := txscript.PayToTaprootScript(txscript.ComputeTaprootKeyNoScript(aggKey.PreTweakedKey))
+	require.NoError(t, err)
+
+	const fundedAmount = btcutil.Amount(1000000)
+	sim.Chain.Fund(custodyScript, fundedAmount)
+	coinbase := sim.Chain.MineBlocks(1)[0].Transactions[0]
+
+	var funding *wire.OutPoint
+	for i, out := range coinbase.TxOut {
+		if bytes.Equal(out.PkScript, custodyScript) {
+			funding = wire.NewOutPoint(&chainhash.Hash{}, uint32(i))
+			funding.Hash = coinbase.TxHash()
+		}
+	}
+	require.NotNil(t, funding)
+
+	stakingTx := wire.NewMsgTx(2)
+	stakingTx.AddTxIn(wire.NewTxIn(funding, nil, nil))
+	stakingTx.AddTxOut(wire.NewTxOut(int64(fundedAmount)-2000, custodyScript))
+
+	// staking transaction funded by other output is rejected
+	otherTx := stakingTx.Copy()
+	otherTx.TxIn[0].PreviousOutPoint.Index = 0
+	_, err = app.StartMuSig2Session(&staker.MuSig2SessionRequest{
+		KeyName:   "custody",
+		Kind:      staker.MuSig2SessionStaking,
+		StakingTx: otherTx,
+	})
+	require.Error(t, err)
+
+	session, err := app.StartMuSig2Session(&staker.MuSig2SessionRequest{
+		KeyName:   "custody",
+		Kind:      staker.MuSig2SessionStaking,
+		StakingTx: stakingTx,
+	})
+	require.NoError(t, err)
+	require.Equal(t, stakingTx.TxHash().String(), session.StakingTxHash)
+
+	session = submitNonces(t, app, session.ID, signers)
+
+	// partial signature without BIP86 tweak does not verify
+	_, err = app.SubmitMuSig2PartialSig(session.ID, pubKeys[0], partialSig(t, signers[0], session, pubKeys))
+	require.Error(t, err)
+
+	for _, s := range signers {
+		session, err = app.SubmitMuSig2PartialSig(
+			session.ID, s.privKey.PubKey(), partialSig(t, s, session, pubKeys, musig2.WithBip86SignTweak()),
+		)
+		require.NoError(t, err)
+	}
+	require.Empty(t, session.Error)
+	require.Equal(t, stakingTx.TxHash().String(), session.BroadcastTxHash)
+
+	mempool := sim.Chain.MempoolTxs()
+	require.Len(t, mempool, 1)
+	signed := mempool[0]
+
+	prevOut := coinbase.TxOut[funding.Index]
+	fetcher := txscript.NewCannedPrevOutputFetcher(prevOut.PkScript, prevOut.Value)
+	engine, err := txscript.NewEngine(
+		prevOut.PkScript, signed, 0, txscript.StandardVerifyFlags, nil,
+		txscript.NewTxSigHashes(signed, fetcher), prevOut.Value, fetcher,
+	)
+	require.NoError(t, err)
+	require.NoError(t, engine.Execute())
+}
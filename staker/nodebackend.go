@@ -38,11 +38,16 @@ func NewNodeBackend(
 ) (*NodeBackend, error) {
 	switch cfg.ActiveNodeBackend {
 	case types.BitcoindNodeBackend:
+		rpcUser, rpcPass, err := cfg.Bitcoind.RPCCredentials()
+		if err != nil {
+			return nil, fmt.Errorf("unable to get bitcoind rpc credentials: %w", err)
+		}
+
 		bitcoindCfg := &chain.BitcoindConfig{
 			ChainParams:        params,
 			Host:               cfg.Bitcoind.RPCHost,
-			User:               cfg.Bitcoind.RPCUser,
-			Pass:               cfg.Bitcoind.RPCPass,
+			User:               rpcUser,
+			Pass:               rpcPass,
 			Dialer:             BuildDialer(cfg.Bitcoind.RPCHost),
 			PrunedModeMaxPeers: cfg.Bitcoind.PrunedNodeMaxPeers,
 		}
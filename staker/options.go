@@ -0,0 +1,351 @@
+package staker
+
+import (
+	"context"
+	"fmt"
+
+	cl "github.com/babylonlabs-io/btc-staker/babylonclient"
+	"github.com/babylonlabs-io/btc-staker/cluster"
+	"github.com/babylonlabs-io/btc-staker/metrics"
+	scfg "github.com/babylonlabs-io/btc-staker/stakercfg"
+	"github.com/babylonlabs-io/btc-staker/stakerdb"
+	"github.com/babylonlabs-io/btc-staker/types"
+	"github.com/babylonlabs-io/btc-staker/walletcontroller"
+	"github.com/babylonlabs-io/btc-staker/walletcontroller/thresholdsigner"
+	notifier "github.com/lightningnetwork/lnd/chainntnfs"
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/kvdb"
+	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
+	"github.com/sirupsen/logrus"
+	"go.uber.org/zap"
+)
+
+// Option customizes staker app created by New
+type Option func(*options)
+
+type options struct {
+	config          *scfg.Config
+	logger          *logrus.Logger
+	rpcClientLogger *zap.Logger
+	db              kvdb.Backend
+	metrics         *metrics.StakerMetrics
+	wallet          walletcontroller.WalletController
+	babylonClient   cl.BabylonClient
+	notifier        notifier.ChainNotifier
+	feeEstimator    FeeEstimator
+	leaderElector   cluster.LeaderElector
+	thresholdSigner thresholdsigner.Signer
+	replicaSource   ReplicaSource
+}
+
+// WithConfig sets config of the app. Default config is used if not provided.
+func WithConfig(config *scfg.Config) Option {
+	return func(o *options) {
+		o.config = config
+	}
+}
+
+// WithLogger sets logger of the app
+func WithLogger(logger *logrus.Logger) Option {
+	return func(o *options) {
+		o.logger = logger
+	}
+}
+
+// WithRPCClientLogger sets logger of the babylon rpc client
+func WithRPCClientLogger(logger *zap.Logger) Option {
+	return func(o *options) {
+		o.rpcClientLogger = logger
+	}
+}
+
+// WithDB sets database backend of the app. Database provided this way is not
+// closed when app stops. If not provided, database configured in the config is
+// opened and closed by the app.
+func WithDB(db kvdb.Backend) Option {
+	return func(o *options) {
+		o.db = db
+	}
+}
+
+// WithMetrics sets metrics of the app
+func WithMetrics(m *metrics.StakerMetrics) Option {
+	return func(o *options) {
+		o.metrics = m
+	}
+}
+
+// WithWalletController sets btc wallet used by the app instead of rpc wallet
+// created from the config
+func WithWalletController(wc walletcontroller.WalletController) Option {
+	return func(o *options) {
+		o.wallet = wc
+	}
+}
+
+// WithBabylonClient sets babylon client used by the app instead of babylon
+// controller created from the config
+func WithBabylonClient(bc cl.BabylonClient) Option {
+	return func(o *options) {
+		o.babylonClient = bc
+	}
+}
+
+// WithNotifier sets btc chain notifier used by the app instead of notifier of
+// node backend created from the config
+func WithNotifier(n notifier.ChainNotifier) Option {
+	return func(o *options) {
+		o.notifier = n
+	}
+}
+
+// WithFeeEstimator sets fee estimator used by the app instead of estimator
+// created from the config
+func WithFeeEstimator(fe FeeEstimator) Option {
+	return func(o *options) {
+		o.feeEstimator = fe
+	}
+}
+
+// WithLeaderElector sets elector which elected this instance as the leader.
+// App then stops broadcasting and submitting delegations to Babylon as soon as
+// it loses leadership.
+func WithLeaderElector(e cluster.LeaderElector) Option {
+	return func(o *options) {
+		o.leaderElector = e
+	}
+}
+
+// WithThresholdSigner sets threshold signing service holding the staker key
+// instead of service configured in the config. Its BIP86 taproot address is
+// then signed by the signer and the wallet only funds transactions.
+func WithThresholdSigner(s thresholdsigner.Signer) Option {
+	return func(o *options) {
+		o.thresholdSigner = s
+	}
+}
+
+// WithReplicaSource sets primary followed by the app when it runs as read only
+// replica
+func WithReplicaSource(src ReplicaSource) Option {
+	return func(o *options) {
+		o.replicaSource = src
+	}
+}
+
+// New creates staker app which can be embedded in other programs. Dependencies
+// not provided through options are created from the config, the same way as
+// stakerd does.
+func New(opts ...Option) (*App, error) {
+	o := &options{}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	if o.config == nil {
+		defaultConfig := scfg.DefaultConfig()
+		o.config = &defaultConfig
+	}
+
+	if o.logger == nil {
+		o.logger = logrus.New()
+	}
+
+	if o.rpcClientLogger == nil {
+		o.rpcClientLogger = zap.NewNop()
+	}
+
+	if o.metrics == nil {
+		o.metrics = metrics.NewStakerMetrics()
+	}
+
+	ownsDB := false
+	if o.db == nil {
+		db, err := scfg.GetDBBackend(o.config.DBConfig)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load db backend: %w", err)
+		}
+		o.db = db
+		ownsDB = true
+
+		if o.config.DBConfig.Backend == scfg.BoltBackend {
+			if err := o.metrics.RegisterDBStats(db); err != nil {
+				o.logger.WithError(err).Warn("Failed to register db stats metrics")
+			}
+		}
+	}
+
+	app, err := newFromOptions(o)
+	if err != nil {
+		if ownsDB {
+			_ = o.db.Close()
+		}
+		return nil, err
+	}
+
+	if ownsDB {
+		app.db = o.db
+	}
+
+	return app, nil
+}
+
+func newFromOptions(o *options) (*App, error) {
+	config := o.config
+
+	if o.wallet == nil {
+		// TODO: If we want to support multiple wallet types, this is most probably the place to decide
+		// on concrete implementation
+		walletClient, err := walletcontroller.NewRPCWalletController(config)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create wallet controller: %w", err)
+		}
+		o.wallet = walletClient
+	}
+
+	if o.thresholdSigner == nil && config.ThresholdSignerConfig.URL != "" {
+		signer, err := newThresholdSigner(config.ThresholdSignerConfig)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create threshold signer: %w", err)
+		}
+		o.thresholdSigner = signer
+	}
+
+	if o.thresholdSigner != nil {
+		wc, err := thresholdsigner.NewWalletController(
+			context.Background(),
+			o.wallet,
+			o.thresholdSigner,
+			&config.ActiveNetParams,
+			config.ThresholdSignerConfig.Timeout,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get staker key of threshold signer: %w", err)
+		}
+
+		o.logger.WithFields(logrus.Fields{
+			"stakerAddress": wc.Address().EncodeAddress(),
+		}).Info("Staker key is held by threshold signer")
+		o.wallet = wc
+	}
+
+	tracker, err := stakerdb.NewTrackedTransactionStore(o.db)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create tracked transaction store: %w", err)
+	}
+
+	if err := tracker.BindNetwork(&config.ActiveNetParams); err != nil {
+		return nil, err
+	}
+
+	integrityKey, err := config.DBConfig.IntegrityPrivateKey()
+	if err != nil {
+		return nil, err
+	}
+
+	if integrityKey != nil {
+		if err := tracker.SetIntegrityKey(integrityKey); err != nil {
+			return nil, err
+		}
+	}
+
+	if o.babylonClient == nil {
+		babylonClient, err := cl.NewBabylonController(config.BabylonConfig, &config.ActiveNetParams, o.logger, o.rpcClientLogger)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create Babylon controller: %w", err)
+		}
+		o.babylonClient = babylonClient
+	}
+
+	if o.notifier == nil {
+		hintCache, err := channeldb.NewHeightHintCache(
+			channeldb.CacheConfig{
+				// TODO: Investigate this option. Lighting docs mention that this is necessary for some edge case
+				QueryDisable: false,
+			}, o.db,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("unable to create height hint cache: %w", err)
+		}
+
+		nodeNotifier, err := NewNodeBackend(config.BtcNodeBackendConfig, &config.ActiveNetParams, hintCache)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create node backend with notifier: %w", err)
+		}
+		o.notifier = nodeNotifier
+	}
+
+	if o.feeEstimator == nil {
+		switch config.BtcNodeBackendConfig.EstimationMode {
+		case types.StaticFeeEstimation:
+			o.feeEstimator = NewStaticBtcFeeEstimator(chainfee.SatPerKVByte(config.BtcNodeBackendConfig.MaxFeeRate * 1000))
+		case types.DynamicFeeEstimation:
+			feeEstimator, err := NewDynamicBtcFeeEstimator(config.BtcNodeBackendConfig, &config.ActiveNetParams, o.logger)
+			if err != nil {
+				return nil, fmt.Errorf("failed to create dynamic fee estimator: %w", err)
+			}
+			o.feeEstimator = feeEstimator
+		default:
+			return nil, fmt.Errorf("unknown fee estimation mode: %d", config.BtcNodeBackendConfig.EstimationMode)
+		}
+	}
+
+	var fence *leaderFence
+	if o.leaderElector != nil {
+		fence = newLeaderFence(o.leaderElector, tracker)
+		o.wallet = &fencedWalletController{
+			WalletController: o.wallet,
+			fence:            fence,
+		}
+	}
+
+	babylonMsgSender := cl.NewBabylonMsgSender(o.babylonClient, o.logger, config.StakerConfig.MaxConcurrentTransactions)
+
+	app, err := NewStakerAppFromDeps(
+		config,
+		o.logger,
+		o.babylonClient,
+		o.wallet,
+		o.notifier,
+		o.feeEstimator,
+		tracker,
+		babylonMsgSender,
+		o.metrics,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	app.fence = fence
+	app.replicaSource = o.replicaSource
+	return app, nil
+}
+
+// Config returns config of the app
+func (app *App) Config() *scfg.Config {
+	return app.config
+}
+
+// Logger returns logger of the app
+func (app *App) Logger() *logrus.Logger {
+	return app.logger
+}
+
+// Metrics returns metrics of the app
+func (app *App) Metrics() *metrics.StakerMetrics {
+	return app.m
+}
+
+// newThresholdSigner creates http client of threshold signing service
+// configured in the config
+func newThresholdSigner(cfg *scfg.ThresholdSignerConfig) (*thresholdsigner.HTTPSigner, error) {
+	return thresholdsigner.NewHTTPSigner(&thresholdsigner.HTTPSignerConfig{
+		URL:        cfg.URL,
+		KeyID:      cfg.KeyID,
+		Token:      cfg.Token,
+		CACert:     cfg.CACert,
+		ClientCert: cfg.ClientCert,
+		ClientKey:  cfg.ClientKey,
+		Timeout:    cfg.Timeout,
+	})
+}
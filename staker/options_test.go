@@ -0,0 +1,92 @@
+package staker_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/babylonlabs-io/btc-staker/staker"
+	"github.com/babylonlabs-io/btc-staker/stakercfg"
+	"github.com/babylonlabs-io/btc-staker/stakerdb"
+	"github.com/babylonlabs-io/btc-staker/testutil/simulation"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+)
+
+// newEmbeddedApp creates app with simulated dependencies, using database from
+// the config unless other options provide one
+func newEmbeddedApp(t *testing.T, sim *simulation.Simulation, dbCfg *stakercfg.DBConfig, opts ...staker.Option) *staker.App {
+	cfg := sim.Config()
+	cfg.DBConfig = dbCfg
+
+	logger := logrus.New()
+	logger.SetLevel(logrus.WarnLevel)
+
+	app, err := staker.New(append([]staker.Option{
+		staker.WithConfig(cfg),
+		staker.WithLogger(logger),
+		staker.WithWalletController(sim.Wallet),
+		staker.WithBabylonClient(sim.Babylon),
+		staker.WithNotifier(sim.Notifier),
+	}, opts...)...)
+	require.NoError(t, err)
+
+	require.Same(t, cfg, app.Config())
+	require.Same(t, logger, app.Logger())
+
+	return app
+}
+
+func TestNewClosesOwnedDatabase(t *testing.T) {
+	t.Parallel()
+
+	sim, err := simulation.New([]byte("options"))
+	require.NoError(t, err)
+
+	dbCfg := stakercfg.DefaultDBConfig()
+	dbCfg.DBPath = t.TempDir()
+	dbCfg.NoSync = true
+	dbCfg.DBTimeout = time.Second
+
+	app := newEmbeddedApp(t, sim, &dbCfg)
+	require.NoError(t, app.Start())
+	<-app.StartupSyncDone()
+	require.NoError(t, app.Stop())
+
+	// database opened by the app is closed on stop, so it can be opened
+	// again
+	db, err := stakercfg.GetDBBackend(&dbCfg)
+	require.NoError(t, err)
+	require.NoError(t, db.Close())
+}
+
+func TestNewKeepsProvidedDatabaseOpen(t *testing.T) {
+	t.Parallel()
+
+	sim, err := simulation.New([]byte("options"))
+	require.NoError(t, err)
+
+	dbCfg := stakercfg.DefaultDBConfig()
+	dbCfg.DBPath = t.TempDir()
+	dbCfg.NoSync = true
+
+	db, err := stakercfg.GetDBBackend(&dbCfg)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = db.Close()
+	})
+
+	// config points to other, not existing database, which is not used
+	otherDBCfg := dbCfg
+	otherDBCfg.DBPath = t.TempDir() + "/not-used"
+	app := newEmbeddedApp(t, sim, &otherDBCfg, staker.WithDB(db))
+	require.NoError(t, app.Start())
+	<-app.StartupSyncDone()
+	require.NoError(t, app.Stop())
+
+	// database provided by the caller is still usable after app stops
+	store, err := stakerdb.NewTrackedTransactionStore(db)
+	require.NoError(t, err)
+	_, err = store.GetAllStoredTransactions()
+	require.NoError(t, err)
+	require.NoDirExists(t, otherDBCfg.DBPath)
+}
@@ -0,0 +1,254 @@
+package staker
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	sdkmath "cosmossdk.io/math"
+	cl "github.com/babylonlabs-io/btc-staker/babylonclient"
+	"github.com/babylonlabs-io/btc-staker/stakerdb"
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/sirupsen/logrus"
+)
+
+// archivedStakingParams is encoding of btc staking params in params archive
+type archivedStakingParams struct {
+	CovenantPks               [][]byte `json:"covenant_pks"`
+	CovenantQuorum            uint32   `json:"covenant_quorum"`
+	SlashingPkScript          []byte   `json:"slashing_pk_script"`
+	SlashingRate              string   `json:"slashing_rate"`
+	MinSlashingTxFeeSat       int64    `json:"min_slashing_tx_fee_sat"`
+	UnbondingTime             uint16   `json:"unbonding_time"`
+	UnbondingFeeSat           int64    `json:"unbonding_fee_sat"`
+	MinStakingTime            uint16   `json:"min_staking_time"`
+	MaxStakingTime            uint16   `json:"max_staking_time"`
+	MinStakingValueSat        int64    `json:"min_staking_value_sat"`
+	MaxStakingValueSat        int64    `json:"max_staking_value_sat"`
+	AllowListExpirationHeight uint64   `json:"allow_list_expiration_height"`
+}
+
+func encodeArchivedParams(params *cl.BtcStakingParams) (*stakerdb.ArchivedParams, error) {
+	a := archivedStakingParams{
+		CovenantQuorum:            params.CovenantQuruomThreshold,
+		SlashingPkScript:          params.SlashingPkScript,
+		SlashingRate:              params.SlashingRate.String(),
+		MinSlashingTxFeeSat:       int64(params.MinSlashingTxFeeSat),
+		UnbondingTime:             params.UnbondingTime,
+		UnbondingFeeSat:           int64(params.UnbondingFee),
+		MinStakingTime:            params.MinStakingTime,
+		MaxStakingTime:            params.MaxStakingTime,
+		MinStakingValueSat:        int64(params.MinStakingValue),
+		MaxStakingValueSat:        int64(params.MaxStakingValue),
+		AllowListExpirationHeight: params.AllowListExpirationHeight,
+	}
+	for _, pk := range params.CovenantPks {
+		a.CovenantPks = append(a.CovenantPks, pk.SerializeCompressed())
+	}
+
+	encoded, err := json.Marshal(a)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode params version %d: %w", params.Version, err)
+	}
+
+	return &stakerdb.ArchivedParams{
+		Version:             params.Version,
+		BtcActivationHeight: params.BtcActivationHeight,
+		Params:              encoded,
+		ArchivedAt:          time.Now(),
+	}, nil
+}
+
+func decodeArchivedParams(archived *stakerdb.ArchivedParams) (*cl.BtcStakingParams, error) {
+	var a archivedStakingParams
+	if err := json.Unmarshal(archived.Params, &a); err != nil {
+		return nil, fmt.Errorf("failed to decode archived params version %d: %w", archived.Version, err)
+	}
+
+	slashingRate, err := sdkmath.LegacyNewDecFromStr(a.SlashingRate)
+	if err != nil {
+		return nil, fmt.Errorf("invalid slashing rate of archived params version %d: %w", archived.Version, err)
+	}
+
+	params := &cl.BtcStakingParams{
+		MinSlashingTxFeeSat:       btcutil.Amount(a.MinSlashingTxFeeSat),
+		SlashingPkScript:          a.SlashingPkScript,
+		SlashingRate:              slashingRate,
+		CovenantQuruomThreshold:   a.CovenantQuorum,
+		UnbondingTime:             a.UnbondingTime,
+		UnbondingFee:              btcutil.Amount(a.UnbondingFeeSat),
+		MinStakingTime:            a.MinStakingTime,
+		MaxStakingTime:            a.MaxStakingTime,
+		MinStakingValue:           btcutil.Amount(a.MinStakingValueSat),
+		MaxStakingValue:           btcutil.Amount(a.MaxStakingValueSat),
+		AllowListExpirationHeight: a.AllowListExpirationHeight,
+		Version:                   archived.Version,
+		BtcActivationHeight:       archived.BtcActivationHeight,
+	}
+	for _, encodedPk := range a.CovenantPks {
+		pk, err := btcec.ParsePubKey(encodedPk)
+		if err != nil {
+			return nil, fmt.Errorf("invalid covenant key of archived params version %d: %w", archived.Version, err)
+		}
+		params.CovenantPks = append(params.CovenantPks, pk)
+	}
+
+	return params, nil
+}
+
+// paramsArchiveClient is Babylon client which archives every params version
+// it receives. Params are served from the archive if Babylon fails to return
+// them, e.g. because the node pruned historical state.
+type paramsArchiveClient struct {
+	cl.BabylonClient
+	store  *stakerdb.TrackedTransactionStore
+	logger *logrus.Logger
+}
+
+var _ cl.BabylonClient = (*paramsArchiveClient)(nil)
+
+func newParamsArchiveClient(
+	client cl.BabylonClient,
+	store *stakerdb.TrackedTransactionStore,
+	logger *logrus.Logger,
+) *paramsArchiveClient {
+	return &paramsArchiveClient{
+		BabylonClient: client,
+		store:         store,
+		logger:        logger,
+	}
+}
+
+// archive stores params in the archive. Failure is only logged, as archive is
+// a fallback.
+func (c *paramsArchiveClient) archive(params *cl.BtcStakingParams) {
+	archived, err := encodeArchivedParams(params)
+	if err == nil {
+		var stored bool
+		stored, err = c.store.ArchiveParams(archived)
+		if stored {
+			c.logger.WithFields(logrus.Fields{
+				"version":             params.Version,
+				"btcActivationHeight": params.BtcActivationHeight,
+			}).Info("Archived babylon params version")
+		}
+	}
+
+	if err != nil {
+		c.logger.WithFields(logrus.Fields{
+			"version": params.Version,
+			"err":     err,
+		}).Error("Failed to archive babylon params")
+	}
+}
+
+func (c *paramsArchiveClient) logFallback(what string, err error) {
+	c.logger.WithFields(logrus.Fields{
+		"params": what,
+		"err":    err,
+	}).Warn("Failed to query babylon params, using params archive")
+}
+
+// ParamsByVersion returns params of the given version from Babylon, or from
+// the archive if Babylon fails
+func (c *paramsArchiveClient) ParamsByVersion(version uint32) (*cl.BtcStakingParams, error) {
+	params, err := c.BabylonClient.ParamsByVersion(version)
+	if err == nil {
+		archived := *params
+		archived.Version = version
+		c.archive(&archived)
+		return params, nil
+	}
+
+	archived, archiveErr := c.store.GetArchivedParams(version)
+	if archiveErr != nil {
+		return nil, err
+	}
+
+	c.logFallback(fmt.Sprintf("version %d", version), err)
+	return decodeArchivedParams(archived)
+}
+
+// ParamsByBtcHeight returns params which apply at the given btc height from
+// Babylon, or from the archive if Babylon fails. Checkpoint params are not
+// versioned, so current ones are used with archived params.
+func (c *paramsArchiveClient) ParamsByBtcHeight(btcHeight uint32) (*cl.StakingParams, error) {
+	params, err := c.BabylonClient.ParamsByBtcHeight(btcHeight)
+	if err == nil {
+		c.archive(&params.BtcStakingParams)
+		return params, nil
+	}
+
+	archived, archiveErr := c.store.GetArchivedParamsByBtcHeight(btcHeight)
+	if archiveErr != nil {
+		return nil, err
+	}
+
+	checkpointParams, checkpointErr := c.BabylonClient.BTCCheckpointParams()
+	if checkpointErr != nil {
+		return nil, err
+	}
+
+	c.logFallback(fmt.Sprintf("btc height %d", btcHeight), err)
+
+	stakingParams, err := decodeArchivedParams(archived)
+	if err != nil {
+		return nil, err
+	}
+
+	return &cl.StakingParams{
+		BTCCheckpointParams: *checkpointParams,
+		BtcStakingParams:    *stakingParams,
+	}, nil
+}
+
+// ParamsVersions returns all params versions from Babylon and archives them
+func (c *paramsArchiveClient) ParamsVersions() ([]cl.BtcStakingParams, error) {
+	versions, err := c.BabylonClient.ParamsVersions()
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range versions {
+		c.archive(&versions[i])
+	}
+
+	return versions, nil
+}
+
+// syncParamsArchive archives all params versions known to Babylon, so that
+// the archive holds versions the daemon did not query itself
+func (app *App) syncParamsArchive() {
+	versions, err := app.babylonClient.ParamsVersions()
+	if err != nil {
+		app.logger.WithFields(logrus.Fields{
+			"err": err,
+		}).Error("Failed to sync params archive with babylon")
+		return
+	}
+
+	app.logger.WithFields(logrus.Fields{
+		"versions": len(versions),
+	}).Info("Params archive synced with babylon")
+}
+
+// ArchivedParams returns all params versions stored in params archive ordered
+// by version
+func (app *App) ArchivedParams() ([]*cl.BtcStakingParams, error) {
+	archived, err := app.txTracker.ListArchivedParams()
+	if err != nil {
+		return nil, err
+	}
+
+	params := make([]*cl.BtcStakingParams, 0, len(archived))
+	for _, a := range archived {
+		p, err := decodeArchivedParams(a)
+		if err != nil {
+			return nil, err
+		}
+		params = append(params, p)
+	}
+
+	return params, nil
+}
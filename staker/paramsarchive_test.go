@@ -0,0 +1,168 @@
+package staker
+
+import (
+	"errors"
+	"testing"
+
+	sdkmath "cosmossdk.io/math"
+	cl "github.com/babylonlabs-io/btc-staker/babylonclient"
+	"github.com/babylonlabs-io/btc-staker/stakercfg"
+	"github.com/babylonlabs-io/btc-staker/stakerdb"
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+)
+
+var errBabylonUnavailable = errors.New("babylon unavailable")
+
+// archiveTestClient serves params by version until it is made unavailable
+type archiveTestClient struct {
+	cl.BabylonClient
+	versions    map[uint32]cl.BtcStakingParams
+	unavailable bool
+}
+
+func (c *archiveTestClient) ParamsByVersion(version uint32) (*cl.BtcStakingParams, error) {
+	if c.unavailable {
+		return nil, errBabylonUnavailable
+	}
+	p := c.versions[version]
+	p.Version = 0
+	return &p, nil
+}
+
+func (c *archiveTestClient) ParamsByBtcHeight(btcHeight uint32) (*cl.StakingParams, error) {
+	if c.unavailable {
+		return nil, errBabylonUnavailable
+	}
+
+	var found *cl.BtcStakingParams
+	for _, p := range c.versions {
+		if p.BtcActivationHeight <= btcHeight && (found == nil || p.Version > found.Version) {
+			p := p
+			found = &p
+		}
+	}
+	return &cl.StakingParams{BtcStakingParams: *found}, nil
+}
+
+func (c *archiveTestClient) ParamsVersions() ([]cl.BtcStakingParams, error) {
+	if c.unavailable {
+		return nil, errBabylonUnavailable
+	}
+
+	var versions []cl.BtcStakingParams
+	for _, p := range c.versions {
+		versions = append(versions, p)
+	}
+	return versions, nil
+}
+
+func (c *archiveTestClient) BTCCheckpointParams() (*cl.BTCCheckpointParams, error) {
+	return &cl.BTCCheckpointParams{ConfirmationTimeBlocks: 6, FinalizationTimeoutBlocks: 20}, nil
+}
+
+func newArchiveTestStore(t *testing.T) *stakerdb.TrackedTransactionStore {
+	cfg := stakercfg.DefaultDBConfig()
+	cfg.DBPath = t.TempDir()
+	cfg.NoSync = true
+
+	backend, err := stakercfg.GetDBBackend(&cfg)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		backend.Close()
+	})
+
+	store, err := stakerdb.NewTrackedTransactionStore(backend)
+	require.NoError(t, err)
+
+	return store
+}
+
+func genArchiveTestParams(t *testing.T, version, activationHeight uint32) cl.BtcStakingParams {
+	key, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+
+	return cl.BtcStakingParams{
+		MinSlashingTxFeeSat:     1000,
+		CovenantPks:             []*btcec.PublicKey{key.PubKey()},
+		SlashingPkScript:        []byte{0x51},
+		SlashingRate:            sdkmath.LegacyNewDecWithPrec(1, 1),
+		CovenantQuruomThreshold: 1,
+		UnbondingTime:           uint16(100 + version),
+		UnbondingFee:            500,
+		MinStakingTime:          10,
+		MaxStakingTime:          1000,
+		MinStakingValue:         10_000,
+		MaxStakingValue:         1_000_000,
+		Version:                 version,
+		BtcActivationHeight:     activationHeight,
+	}
+}
+
+func requireEqualParams(t *testing.T, expected, actual *cl.BtcStakingParams) {
+	require.True(t, expected.SlashingRate.Equal(actual.SlashingRate))
+	require.Len(t, actual.CovenantPks, len(expected.CovenantPks))
+	for i := range expected.CovenantPks {
+		require.True(t, expected.CovenantPks[i].IsEqual(actual.CovenantPks[i]))
+	}
+
+	e, a := *expected, *actual
+	e.SlashingRate, a.SlashingRate = sdkmath.LegacyDec{}, sdkmath.LegacyDec{}
+	e.CovenantPks, a.CovenantPks = nil, nil
+	require.Equal(t, e, a)
+}
+
+func TestParamsArchiveClient(t *testing.T) {
+	t.Parallel()
+
+	v0 := genArchiveTestParams(t, 0, 10)
+	v1 := genArchiveTestParams(t, 1, 100)
+	v2 := genArchiveTestParams(t, 2, 200)
+
+	inner := &archiveTestClient{versions: map[uint32]cl.BtcStakingParams{0: v0, 1: v1, 2: v2}}
+	store := newArchiveTestStore(t)
+	client := newParamsArchiveClient(inner, store, logrus.New())
+
+	// queried params are archived under the requested version
+	_, err := client.ParamsByVersion(1)
+	require.NoError(t, err)
+	_, err = client.ParamsByBtcHeight(250)
+	require.NoError(t, err)
+
+	inner.unavailable = true
+
+	archived, err := client.ParamsByVersion(1)
+	require.NoError(t, err)
+	requireEqualParams(t, &v1, archived)
+
+	atHeight, err := client.ParamsByBtcHeight(250)
+	require.NoError(t, err)
+	requireEqualParams(t, &v2, &atHeight.BtcStakingParams)
+	require.Equal(t, uint32(6), atHeight.ConfirmationTimeBlocks)
+
+	// archive has no gap between version 1 and the latest archived version
+	atHeight, err = client.ParamsByBtcHeight(150)
+	require.NoError(t, err)
+	requireEqualParams(t, &v1, &atHeight.BtcStakingParams)
+
+	// version 0 was never seen, error of babylon is returned
+	_, err = client.ParamsByBtcHeight(50)
+	require.ErrorIs(t, err, errBabylonUnavailable)
+	_, err = client.ParamsByVersion(0)
+	require.ErrorIs(t, err, errBabylonUnavailable)
+
+	// sync archives all versions
+	inner.unavailable = false
+	_, err = client.ParamsVersions()
+	require.NoError(t, err)
+	inner.unavailable = true
+
+	atHeight, err = client.ParamsByBtcHeight(50)
+	require.NoError(t, err)
+	requireEqualParams(t, &v0, &atHeight.BtcStakingParams)
+
+	all, err := store.ListArchivedParams()
+	require.NoError(t, err)
+	require.Len(t, all, 3)
+}
@@ -0,0 +1,67 @@
+package staker
+
+import (
+	"errors"
+	"fmt"
+
+	staking "github.com/babylonlabs-io/babylon/v4/btcstaking"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+)
+
+// ErrPhase1TxNotRegistrable is returned when phase-1 staking transaction would
+// be rejected by Babylon
+var ErrPhase1TxNotRegistrable = errors.New("phase-1 staking transaction cannot be registered on babylon")
+
+// checkPhase1Registration checks phase-1 staking transaction against Babylon
+// params of its inclusion height and against the allow list, so that caller
+// gets actionable error instead of rejection of the delegation by Babylon
+func (app *App) checkPhase1Registration(
+	stkTxHash *chainhash.Hash,
+	parsedStakingTx *staking.ParsedV0StakingTx,
+	inclusionHeight uint32,
+) error {
+	params, err := app.babylonClient.ParamsByBtcHeight(inclusionHeight)
+	if err != nil {
+		return fmt.Errorf("failed to get params for btc height %d: %w", inclusionHeight, err)
+	}
+
+	stakingAmount := btcutil.Amount(parsedStakingTx.StakingOutput.Value)
+	if stakingAmount < params.MinStakingValue || stakingAmount > params.MaxStakingValue {
+		return fmt.Errorf("%w: staking amount %d is not in range [%d, %d] allowed by params for btc height %d",
+			ErrPhase1TxNotRegistrable, stakingAmount, params.MinStakingValue, params.MaxStakingValue, inclusionHeight)
+	}
+
+	stakingTime := parsedStakingTx.OpReturnData.StakingTime
+	if stakingTime < params.MinStakingTime || stakingTime > params.MaxStakingTime {
+		return fmt.Errorf("%w: staking time %d is not in range [%d, %d] allowed by params for btc height %d",
+			ErrPhase1TxNotRegistrable, stakingTime, params.MinStakingTime, params.MaxStakingTime, inclusionHeight)
+	}
+
+	if params.AllowListExpirationHeight == 0 {
+		return nil
+	}
+
+	latestBlockHeight, err := app.babylonClient.GetLatestBlockHeight()
+	if err != nil {
+		return fmt.Errorf("failed to get latest block height: %w", err)
+	}
+
+	// delegation is executed in the next block at the earliest, allow list may
+	// expire before that
+	if latestBlockHeight+1 >= params.AllowListExpirationHeight {
+		return nil
+	}
+
+	allowed, err := app.babylonClient.IsStakingTxAllowed(stkTxHash)
+	if err != nil {
+		return fmt.Errorf("failed to check allow list: %w", err)
+	}
+
+	if !allowed {
+		return fmt.Errorf("%w: transaction %s is not in the allow list, which is active until babylon height %d (current height %d). Retry after the allow list expires",
+			ErrPhase1TxNotRegistrable, stkTxHash, params.AllowListExpirationHeight, latestBlockHeight)
+	}
+
+	return nil
+}
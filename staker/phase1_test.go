@@ -0,0 +1,82 @@
+package staker
+
+import (
+	"testing"
+
+	staking "github.com/babylonlabs-io/babylon/v4/btcstaking"
+	cl "github.com/babylonlabs-io/btc-staker/babylonclient"
+	"github.com/babylonlabs-io/btc-staker/stakercfg"
+	"github.com/babylonlabs-io/btc-staker/testutil/mocks"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestCheckPhase1Registration(t *testing.T) {
+	t.Parallel()
+
+	const inclusionHeight = 100
+	stkTxHash := chainhash.Hash{1}
+
+	tests := []struct {
+		name                string
+		value               int64
+		stakingTime         uint16
+		allowListExpiration uint64
+		allowed             bool
+		wantErrContains     string
+	}{
+		{name: "allow list not active", value: 50_000, stakingTime: 1000},
+		{name: "allowed", value: 50_000, stakingTime: 1000, allowListExpiration: 200, allowed: true},
+		// delegation can be executed only after allow list expires
+		{name: "allow list expires in next block", value: 50_000, stakingTime: 1000, allowListExpiration: 151},
+		{
+			name:                "not allowed",
+			value:               50_000,
+			stakingTime:         1000,
+			allowListExpiration: 200,
+			wantErrContains:     "is not in the allow list, which is active until babylon height 200 (current height 150)",
+		},
+		{name: "amount below minimum", value: 9_999, stakingTime: 1000, wantErrContains: "staking amount 9999 is not in range [10000, 100000]"},
+		{name: "amount above maximum", value: 100_001, stakingTime: 1000, wantErrContains: "staking amount 100001 is not in range"},
+		{name: "staking time below minimum", value: 50_000, stakingTime: 9, wantErrContains: "staking time 9 is not in range [10, 10000]"},
+		{name: "staking time above maximum", value: 50_000, stakingTime: 10_001, wantErrContains: "staking time 10001 is not in range"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			bc := mocks.NewMockBabylonClient(ctrl)
+			cfg := stakercfg.DefaultConfig()
+			app := &App{config: &cfg, logger: logrus.New(), babylonClient: bc}
+
+			bc.EXPECT().ParamsByBtcHeight(uint32(inclusionHeight)).Return(&cl.StakingParams{
+				BtcStakingParams: cl.BtcStakingParams{
+					MinStakingValue:           btcutil.Amount(10_000),
+					MaxStakingValue:           btcutil.Amount(100_000),
+					MinStakingTime:            10,
+					MaxStakingTime:            10_000,
+					AllowListExpirationHeight: tc.allowListExpiration,
+				},
+			}, nil)
+			bc.EXPECT().GetLatestBlockHeight().Return(uint64(150), nil).MaxTimes(1)
+			bc.EXPECT().IsStakingTxAllowed(&stkTxHash).Return(tc.allowed, nil).MaxTimes(1)
+
+			err := app.checkPhase1Registration(&stkTxHash, &staking.ParsedV0StakingTx{
+				StakingOutput: wire.NewTxOut(tc.value, nil),
+				OpReturnData:  &staking.V0OpReturnData{StakingTime: tc.stakingTime},
+			}, inclusionHeight)
+			if tc.wantErrContains != "" {
+				require.ErrorIs(t, err, ErrPhase1TxNotRegistrable)
+				require.ErrorContains(t, err, tc.wantErrContains)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
@@ -0,0 +1,68 @@
+package staker
+
+import (
+	"fmt"
+)
+
+// ReadinessCheck is the result of one of the checks deciding whether staker is
+// ready to serve requests
+type ReadinessCheck struct {
+	Name  string
+	Ready bool
+	// Error describes why the check failed
+	Error string
+}
+
+// Readiness checks that stored delegations were reconciled on start or
+// replicated from the primary, database is open, btc chain is synced, Babylon
+// is reachable and wallet can be unlocked
+func (app *App) Readiness() []ReadinessCheck {
+	syncCheck := readinessCheck("startup_sync", app.checkStartupSync())
+	if app.isReplica() {
+		// replica does not reconcile delegations, it is ready once it caught
+		// up with the primary
+		syncCheck = readinessCheck("replication", app.checkReplicaSynced())
+	}
+
+	return []ReadinessCheck{
+		syncCheck,
+		readinessCheck("database", app.txTracker.Ping()),
+		readinessCheck("btc", app.btcSynced()),
+		readinessCheck("babylon", app.babylonReachable()),
+		readinessCheck("wallet", app.wc.PassphraseAvailable()),
+	}
+}
+
+func readinessCheck(name string, err error) ReadinessCheck {
+	if err != nil {
+		return ReadinessCheck{Name: name, Error: err.Error()}
+	}
+
+	return ReadinessCheck{Name: name, Ready: true}
+}
+
+// btcSynced returns error if staker did not receive btc best block yet, or if
+// btc node is behind Babylon btc light client
+func (app *App) btcSynced() error {
+	height := app.currentBestBlockHeight.Load()
+	if height == 0 {
+		return fmt.Errorf("btc best block is not known yet")
+	}
+
+	lcHeight := app.ChainSafetyStatus().BtcLightClientHeight
+	maxBehind := app.config.StakerConfig.MaxBtcHeightDivergence
+	if maxBehind > 0 && lcHeight > height && lcHeight-height > maxBehind {
+		return fmt.Errorf("btc tip %d is %d blocks behind babylon btc light client tip %d",
+			height, lcHeight-height, lcHeight)
+	}
+
+	return nil
+}
+
+func (app *App) babylonReachable() error {
+	if _, err := app.babylonClient.GetLatestBlockHeight(); err != nil {
+		return fmt.Errorf("failed to get babylon height: %w", err)
+	}
+
+	return nil
+}
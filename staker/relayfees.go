@@ -0,0 +1,91 @@
+package staker
+
+import (
+	"sync"
+	"time"
+
+	"github.com/babylonlabs-io/btc-staker/utils"
+	"github.com/babylonlabs-io/btc-staker/walletcontroller"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// relayFeesRefreshInterval is how long relay fees queried from the node
+	// are used before querying them again
+	relayFeesRefreshInterval = 10 * time.Minute
+)
+
+// relayFeesCache keeps the last relay fees reported by the node
+type relayFeesCache struct {
+	mu        sync.Mutex
+	fees      walletcontroller.RelayFees
+	fetchedAt time.Time
+}
+
+// relayFees returns min relay fee and incremental relay fee of the node which
+// sends staker transactions. If the node can not be queried, defaults of the
+// network are used.
+func (app *App) relayFees() walletcontroller.RelayFees {
+	c := &app.relayFeesCache
+
+	c.mu.Lock()
+	if !c.fetchedAt.IsZero() && time.Since(c.fetchedAt) < relayFeesRefreshInterval {
+		fees := c.fees
+		c.mu.Unlock()
+		return fees
+	}
+	c.mu.Unlock()
+
+	// node is queried without holding the lock, so that slow node does not
+	// block other callers. Concurrent callers may query it at the same time.
+	fees, err := app.wc.RelayFees()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err != nil {
+		defaults := walletcontroller.RelayFees{
+			MinRelayFeePerKb:         btcutil.Amount(utils.FeeRateFloor(app.network) * 1000),
+			IncrementalRelayFeePerKb: walletcontroller.DefaultIncrementalRelayFeePerKb,
+		}
+
+		app.logger.WithFields(logrus.Fields{
+			"err":                 err,
+			"minRelayFee":         defaults.MinRelayFeePerKb,
+			"incrementalRelayFee": defaults.IncrementalRelayFeePerKb,
+		}).Warn("Failed to get relay fees from btc node. Using network defaults")
+
+		// previously fetched fees are still better than defaults
+		if c.fetchedAt.IsZero() {
+			return defaults
+		}
+		return c.fees
+	}
+
+	if fees.MinRelayFeePerKb != c.fees.MinRelayFeePerKb ||
+		fees.IncrementalRelayFeePerKb != c.fees.IncrementalRelayFeePerKb {
+		app.logger.WithFields(logrus.Fields{
+			"minRelayFee":         fees.MinRelayFeePerKb,
+			"incrementalRelayFee": fees.IncrementalRelayFeePerKb,
+		}).Info("Using relay fees of connected btc node as fee rate floor")
+	}
+
+	c.fees = *fees
+	c.fetchedAt = time.Now()
+
+	return c.fees
+}
+
+// feeRateFloor returns the lowest fee rate at which the node relays
+// transactions
+func (app *App) feeRateFloor() chainfee.SatPerKVByte {
+	return chainfee.SatPerKVByte(app.relayFees().MinRelayFeePerKb)
+}
+
+// estimatedFeeRate returns fee rate estimated for the next block, raised to
+// the fee rate floor of the node
+func (app *App) estimatedFeeRate() chainfee.SatPerKVByte {
+	return max(app.feeEstimator.EstimateFeePerKb(), app.feeRateFloor())
+}
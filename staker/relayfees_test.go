@@ -0,0 +1,56 @@
+package staker
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/babylonlabs-io/btc-staker/testutil/mocks"
+	"github.com/babylonlabs-io/btc-staker/utils"
+	"github.com/babylonlabs-io/btc-staker/walletcontroller"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestRelayFees(t *testing.T) {
+	t.Parallel()
+
+	nodeFees := &walletcontroller.RelayFees{
+		MinRelayFeePerKb:         btcutil.Amount(2000),
+		IncrementalRelayFeePerKb: btcutil.Amount(3000),
+	}
+	networkDefaults := walletcontroller.RelayFees{
+		MinRelayFeePerKb:         btcutil.Amount(utils.FeeRateFloor(&chaincfg.RegressionNetParams) * 1000),
+		IncrementalRelayFeePerKb: walletcontroller.DefaultIncrementalRelayFeePerKb,
+	}
+	errNode := errors.New("node unavailable")
+
+	wc := mocks.NewMockWalletController(gomock.NewController(t))
+	app := &App{
+		logger:  logrus.New(),
+		network: &chaincfg.RegressionNetParams,
+		wc:      wc,
+	}
+
+	// node which never answered is replaced by network defaults, and queried
+	// again on next call
+	wc.EXPECT().RelayFees().Return(nil, errNode).Times(2)
+	require.Equal(t, networkDefaults, app.relayFees())
+	require.Equal(t, networkDefaults, app.relayFees())
+
+	// fetched fees are cached
+	wc.EXPECT().RelayFees().Return(nodeFees, nil).Times(1)
+	require.Equal(t, *nodeFees, app.relayFees())
+	require.Equal(t, *nodeFees, app.relayFees())
+	require.Equal(t, chainfee.SatPerKVByte(2000), app.feeRateFloor())
+
+	// once cache expires, failing node does not replace fetched fees by
+	// defaults
+	app.relayFeesCache.fetchedAt = time.Now().Add(-relayFeesRefreshInterval)
+	wc.EXPECT().RelayFees().Return(nil, errNode).Times(1)
+	require.Equal(t, *nodeFees, app.relayFees())
+}
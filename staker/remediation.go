@@ -0,0 +1,245 @@
+package staker
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	bct "github.com/babylonlabs-io/babylon/v4/client/babylonclient"
+	cl "github.com/babylonlabs-io/btc-staker/babylonclient"
+	"github.com/babylonlabs-io/btc-staker/walletcontroller"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/sirupsen/logrus"
+)
+
+// RemediationRejectedDelegation is the condition of delegations rejected by
+// Babylon with retryable error
+const RemediationRejectedDelegation = "rejected_delegation"
+
+// remediationEnabled returns true if operator enabled automatic remediation
+// of delegations stuck with given condition
+func (app *App) remediationEnabled(condition string) bool {
+	switch condition {
+	case StuckNotActive:
+		return app.config.StakerConfig.RemediateInclusionProof
+	case StuckUnbondingNotBroadcast:
+		return app.config.StakerConfig.RemediateUnbondingTx
+	default:
+		return false
+	}
+}
+
+// remediateStuckDelegations runs enabled remediation of stuck delegations
+// until it succeeds, fails with error which is not worth retrying or runs out
+// of attempts
+func (app *App) remediateStuckDelegations() {
+	for _, d := range app.StuckDelegations() {
+		if !app.remediationEnabled(d.Condition) || d.remediationDone ||
+			d.RemediationAttempts >= app.config.StakerConfig.MaxRemediationAttempts {
+			continue
+		}
+
+		select {
+		case <-app.quit:
+			return
+		default:
+		}
+
+		var err error
+		switch d.Condition {
+		case StuckNotActive:
+			err = app.submitInclusionProof(&d.StakingTxHash)
+		case StuckUnbondingNotBroadcast:
+			err = app.rebroadcastUnbondingTx(&d.StakingTxHash)
+		}
+
+		app.recordRemediation(&d.StakingTxHash, d.Condition, err)
+
+		app.stuck.mu.Lock()
+		if stuck, ok := app.stuck.delegations[d.StakingTxHash]; ok && stuck.Condition == d.Condition {
+			stuck.RemediationAttempts++
+			stuck.RemediationError = ""
+			if err != nil {
+				stuck.RemediationError = err.Error()
+			}
+			// babylon rejections are final unless babylon is expected to
+			// accept the message later, other errors are retried
+			stuck.remediationDone = err == nil ||
+				(errors.Is(err, cl.ErrInvalidBabylonExecution) && !cl.IsRetryableError(err))
+		}
+		app.stuck.mu.Unlock()
+	}
+}
+
+// submitInclusionProof submits proof of inclusion of k-deep staking
+// transaction, which activates verified delegation
+func (app *App) submitInclusionProof(stakingTxHash *chainhash.Hash) error {
+	status, ok := app.statuses.get(*stakingTxHash)
+	if !ok {
+		return fmt.Errorf("delegation status is not known")
+	}
+
+	storedTx, err := app.txTracker.GetTransaction(stakingTxHash)
+	if err != nil {
+		return err
+	}
+
+	stakingOutputIdx := status.Delegation.BtcDelegation.StakingOutputIdx
+	if int(stakingOutputIdx) >= len(storedTx.StakingTx.TxOut) {
+		return fmt.Errorf("staking output index %d out of range", stakingOutputIdx)
+	}
+
+	conf, txStatus, err := app.wc.TxDetails(stakingTxHash, storedTx.StakingTx.TxOut[stakingOutputIdx].PkScript)
+	if err != nil {
+		return fmt.Errorf("failed to get staking transaction details: %w", err)
+	}
+
+	if txStatus != walletcontroller.TxInChain {
+		return fmt.Errorf("staking transaction is not confirmed, status: %s", txStatus)
+	}
+
+	checkpointParams, err := app.babylonClient.BTCCheckpointParams()
+	if err != nil {
+		return fmt.Errorf("failed to get btc checkpoint params: %w", err)
+	}
+
+	depth, err := app.babylonClient.QueryHeaderDepth(conf.BlockHash)
+	if err != nil {
+		return fmt.Errorf("failed to get depth of staking transaction block: %w", err)
+	}
+
+	if depth < checkpointParams.ConfirmationTimeBlocks {
+		return fmt.Errorf("staking transaction block depth %d is lower than required %d: %w",
+			depth, checkpointParams.ConfirmationTimeBlocks, cl.ErrBabylonBtcLightClientNotReady)
+	}
+
+	if err := app.fence.check(); err != nil {
+		return err
+	}
+
+	resp, err := app.babylonClient.SubmitInclusionProof(stakingTxHash, conf.Block, conf.TxIndex)
+	if err != nil {
+		return fmt.Errorf("failed to submit inclusion proof: %w", err)
+	}
+
+	app.recordBabylonTx(stakingTxHash, BabylonTxInclusionProof, resp, nil)
+
+	app.logger.WithFields(logrus.Fields{
+		"stakingTxHash": stakingTxHash,
+		"blockHash":     conf.BlockHash,
+	}).Info("Inclusion proof of stuck delegation submitted")
+
+	return nil
+}
+
+// rebroadcastUnbondingTx rebuilds unbonding transaction of active delegation
+// and sends it to btc in background
+func (app *App) rebroadcastUnbondingTx(stakingTxHash *chainhash.Hash) error {
+	if _, running := app.unbondingInFlight.Load(*stakingTxHash); running {
+		return fmt.Errorf("unbonding transaction is still being sent")
+	}
+
+	storedTx, err := app.txTracker.GetTransaction(stakingTxHash)
+	if err != nil {
+		return err
+	}
+
+	di, err := app.babylonClient.QueryBTCDelegation(stakingTxHash)
+	if err != nil {
+		return fmt.Errorf("error getting delegation info: %w", err)
+	}
+
+	if !di.BtcDelegation.Active {
+		return fmt.Errorf("delegation is not active")
+	}
+
+	if err := app.fence.check(); err != nil {
+		return err
+	}
+
+	unbondingTxHash, err := app.startUnbonding(stakingTxHash, storedTx, di)
+	if err != nil {
+		return err
+	}
+
+	app.logger.WithFields(logrus.Fields{
+		"stakingTxHash":   stakingTxHash,
+		"unbondingTxHash": unbondingTxHash,
+	}).Info("Unbonding transaction of stuck delegation rebroadcast")
+
+	return nil
+}
+
+// sendDelegation sends delegation to Babylon. If retrying of rejected
+// delegations is enabled, delegation rejected with retryable error is sent
+// again after babylon stalling interval.
+func (app *App) sendDelegation(
+	stakingTxHash *chainhash.Hash,
+	delegation *cl.DelegationData,
+	requiredInclusionBlockDepth uint32,
+) (*bct.RelayerTxResponse, error) {
+	cfg := app.config.StakerConfig
+
+	for attempt := uint32(0); ; attempt++ {
+		resp, err := app.babylonMsgSender.SendDelegation(delegation, requiredInclusionBlockDepth)
+		if err == nil || !cfg.RetryRejectedDelegations || !cl.IsRetryableError(err) {
+			if attempt > 0 {
+				app.recordRemediation(stakingTxHash, RemediationRejectedDelegation, err)
+			}
+			return resp, err
+		}
+
+		if attempt >= cfg.MaxRemediationAttempts {
+			app.recordRemediation(stakingTxHash, RemediationRejectedDelegation, err)
+			return nil, fmt.Errorf("delegation rejected after %d retries: %w", attempt, err)
+		}
+
+		app.logger.WithFields(logrus.Fields{
+			"stakingTxHash": stakingTxHash,
+			"attempt":       attempt + 1,
+			"retryIn":       cfg.BabylonStallingInterval,
+			"err":           err,
+		}).Warn("Delegation rejected by babylon with retryable error, retrying")
+
+		select {
+		case <-time.After(cfg.BabylonStallingInterval):
+		case <-app.quit:
+			return nil, err
+		}
+
+		if err := app.fence.check(); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// recordRemediation logs remediation result, counts it and notifies changelog
+// subscribers
+func (app *App) recordRemediation(stakingTxHash *chainhash.Hash, condition string, remediationErr error) {
+	result := "success"
+	if remediationErr != nil {
+		result = "failure"
+		app.logger.WithFields(logrus.Fields{
+			"stakingTxHash": stakingTxHash,
+			"condition":     condition,
+			"err":           remediationErr,
+		}).Warn("Automatic remediation failed")
+	}
+
+	app.m.RemediationAttempts.WithLabelValues(condition, result).Inc()
+
+	if remediationErr != nil {
+		note := babylonFailureNote(remediationErr)
+		if condition == StuckUnbondingNotBroadcast {
+			note = btcFailureNote(remediationErr)
+		}
+		app.noteFailure(stakingTxHash, note)
+	}
+
+	if err := app.txTracker.RecordRemediation(stakingTxHash, condition, remediationErr); err != nil {
+		app.logger.WithFields(logrus.Fields{
+			"stakingTxHash": stakingTxHash,
+			"err":           err,
+		}).Error("Failed to record remediation")
+	}
+}
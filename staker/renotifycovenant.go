@@ -0,0 +1,155 @@
+package staker
+
+import (
+	"errors"
+	"fmt"
+
+	cl "github.com/babylonlabs-io/btc-staker/babylonclient"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/sirupsen/logrus"
+)
+
+// Actions taken by covenant renotification
+const (
+	// RenotifyActionResubmitted means delegation was not found on Babylon and
+	// was registered again
+	RenotifyActionResubmitted = "resubmitted"
+	// RenotifyActionWatchRestarted means watching of covenant signatures of
+	// pending delegation was restarted
+	RenotifyActionWatchRestarted = "watch_restarted"
+	// RenotifyActionActivationRestarted means activation of verified
+	// delegation was restarted
+	RenotifyActionActivationRestarted = "activation_restarted"
+	// RenotifyActionNone means delegation is already watched, or it does not
+	// wait for covenant signatures
+	RenotifyActionNone = "none"
+)
+
+// ErrRegistrationNotResubmittable is returned when delegation lost by Babylon
+// can't be registered again from stored data
+var ErrRegistrationNotResubmittable = errors.New("delegation can't be registered again")
+
+// CovenantRenotifyResult describes registration state of the delegation and
+// action taken to get its covenant signatures
+type CovenantRenotifyResult struct {
+	// State is Babylon status of the delegation, empty if it was resubmitted
+	State string
+	// CovenantSignatures is the number of covenant unbonding signatures on
+	// Babylon, out of required CovenantQuorum
+	CovenantSignatures int
+	CovenantQuorum     uint32
+	Action             string
+	// BabylonTxHash is hash of Babylon transaction registering the delegation
+	// again, set if it was resubmitted
+	BabylonTxHash string
+}
+
+// RenotifyCovenant re-checks registration of the delegation whose covenant
+// signatures did not arrive. Delegation unknown to Babylon, e.g. because
+// registration message was lost, is registered again with current covenant
+// committee, which is possible only for confirmed staking transactions
+// carrying staking data. For registered delegations, tasks watching covenant
+// signatures and activation are restarted if they are not running, e.g.
+// after they panicked.
+func (app *App) RenotifyCovenant(stakingTxHash *chainhash.Hash) (*CovenantRenotifyResult, error) {
+	if err := app.checkStartupSync(); err != nil {
+		return nil, err
+	}
+
+	storedTx, err := app.txTracker.GetTransaction(stakingTxHash)
+	if err != nil {
+		return nil, err
+	}
+
+	failure, err := app.stakingTxFailure(stakingTxHash)
+	if err != nil {
+		return nil, err
+	}
+	if failure != nil {
+		return nil, fmt.Errorf("%w: registration failed with %s, use retry-registration",
+			ErrRegistrationNotResubmittable, failure.Reason)
+	}
+
+	params, err := app.babylonClient.Params()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get babylon params: %w", err)
+	}
+
+	result := &CovenantRenotifyResult{
+		CovenantQuorum: params.CovenantQuruomThreshold,
+		Action:         RenotifyActionNone,
+	}
+
+	di, err := app.babylonClient.QueryBTCDelegation(stakingTxHash)
+	if errors.Is(err, cl.ErrDelegationNotFound) {
+		babylonTxHash, err := app.resubmitLostRegistration(stakingTxHash)
+		if err != nil {
+			return nil, err
+		}
+
+		result.Action = RenotifyActionResubmitted
+		result.BabylonTxHash = babylonTxHash
+		return result, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to query delegation %s: %w", stakingTxHash, err)
+	}
+
+	result.State = di.BtcDelegation.GetStatusDesc()
+
+	udi, err := app.babylonClient.GetUndelegationInfo(di)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get undelegation info: %w", err)
+	}
+	result.CovenantSignatures = len(udi.CovenantUnbondingSignatures)
+
+	switch result.State {
+	case BabylonPendingStatus:
+		if app.startDelegationTask(covenantSignaturesTask, *stakingTxHash, func() {
+			app.checkForUnbondingTxSignaturesOnBabylon(stakingTxHash)
+		}) {
+			result.Action = RenotifyActionWatchRestarted
+		}
+	case BabylonVerifiedStatus:
+		stakingOutputIndex := di.BtcDelegation.StakingOutputIdx
+		if app.startDelegationTask(activationTask, *stakingTxHash, func() {
+			app.activateVerifiedDelegation(storedTx.StakingTx, stakingOutputIndex, stakingTxHash)
+		}) {
+			result.Action = RenotifyActionActivationRestarted
+		}
+	}
+
+	app.logger.WithFields(logrus.Fields{
+		"stakingTxHash":      stakingTxHash,
+		"state":              result.State,
+		"covenantSignatures": result.CovenantSignatures,
+		"covenantQuorum":     result.CovenantQuorum,
+		"action":             result.Action,
+	}).Info("Covenant signatures of delegation renotified")
+
+	return result, nil
+}
+
+// resubmitLostRegistration registers tracked delegation which is not known to
+// Babylon again, with covenant set of Babylon params for the inclusion height
+// of its staking transaction. Staking transaction must be confirmed, as
+// pre-approval delegations can't be rebuilt without finality providers and
+// staking time, which are not stored.
+func (app *App) resubmitLostRegistration(stakingTxHash *chainhash.Hash) (string, error) {
+	_, blk, err := app.BtcTxAndBlock(stakingTxHash)
+	if err != nil {
+		return "", fmt.Errorf("%w: delegation is not on babylon and its staking transaction is not confirmed, "+
+			"cancel it with cancel-stake and stake again: %w", ErrRegistrationNotResubmittable, err)
+	}
+
+	params, err := app.babylonClient.ParamsByBtcHeight(uint32(blk.Height))
+	if err != nil {
+		return "", fmt.Errorf("failed to get babylon params for height %d: %w", blk.Height, err)
+	}
+
+	app.logger.WithFields(logrus.Fields{
+		"stakingTxHash": stakingTxHash,
+	}).Warn("Delegation is not known to babylon, registering it again")
+
+	return app.RetryDelegationRegistration(stakingTxHash, params.CovenantPks, params.CovenantQuruomThreshold)
+}
@@ -0,0 +1,258 @@
+package staker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/babylonlabs-io/btc-staker/stakerdb"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/sirupsen/logrus"
+)
+
+// replicaMaxRetryInterval bounds interval between retries of failed
+// replication
+const replicaMaxRetryInterval = 5 * time.Minute
+
+var (
+	// ErrReadOnlyReplica is returned for operations changing delegations on
+	// read only replica
+	ErrReadOnlyReplica = errors.New("staker runs as read only replica")
+	// ErrNotTrackedByPrimary is returned by ReplicaSource for delegations
+	// which the primary does not track anymore
+	ErrNotTrackedByPrimary = errors.New("delegation is not tracked by the primary")
+)
+
+// ReplicatedDelegation is delegation as exported by the primary
+type ReplicatedDelegation struct {
+	Transaction *stakerdb.ImportedTransaction
+	// Failure is nil unless delegation failed on the primary
+	Failure *stakerdb.TransactionFailure
+}
+
+// ReplicaChange is a change of the primary database
+type ReplicaChange struct {
+	Seq uint64
+	// StakingTxHash is nil for changes not related to a delegation
+	StakingTxHash *chainhash.Hash
+}
+
+// ReplicaSource provides database changes and delegations of the primary to
+// read only replica
+type ReplicaSource interface {
+	// Address returns address of the primary, used only for reporting
+	Address() string
+	// Changes returns at most limit changes of the primary recorded after
+	// afterSeq. If there are none, it waits up to wait for new ones.
+	Changes(ctx context.Context, afterSeq, limit uint64, wait time.Duration) ([]ReplicaChange, error)
+	// Delegation returns delegation tracked by the primary, or
+	// ErrNotTrackedByPrimary
+	Delegation(ctx context.Context, stakingTxHash *chainhash.Hash) (*ReplicatedDelegation, error)
+}
+
+// ReplicaStatus describes progress of replication of the primary
+type ReplicaStatus struct {
+	Primary string
+	// AppliedSeq is sequence number of the last change of the primary
+	// applied by the replica
+	AppliedSeq uint64
+	// Replicated is number of delegations replicated since start
+	Replicated uint64
+	// CaughtUpAt is zero until replica applied all changes of the primary
+	CaughtUpAt time.Time
+	// LastError is error of the last failed replication attempt, cleared on
+	// success
+	LastError string
+}
+
+type replicaState struct {
+	mu     sync.RWMutex
+	status ReplicaStatus
+}
+
+// isReplica returns true if the app runs as read only replica
+func (app *App) isReplica() bool {
+	return app.config.ReplicaConfig.Enabled()
+}
+
+// ReplicaStatus returns progress of replication, it fails if the app is not a
+// replica
+func (app *App) ReplicaStatus() (*ReplicaStatus, error) {
+	if !app.isReplica() {
+		return nil, fmt.Errorf("staker does not run as replica")
+	}
+
+	app.replica.mu.RLock()
+	defer app.replica.mu.RUnlock()
+
+	status := app.replica.status
+	return &status, nil
+}
+
+// checkReplicaSynced returns error until replica applied all changes of the
+// primary at least once
+func (app *App) checkReplicaSynced() error {
+	app.replica.mu.RLock()
+	defer app.replica.mu.RUnlock()
+
+	if app.replica.status.CaughtUpAt.IsZero() {
+		if app.replica.status.LastError != "" {
+			return fmt.Errorf("replication failed: %s", app.replica.status.LastError)
+		}
+		return fmt.Errorf("replica has not caught up with the primary yet")
+	}
+
+	return nil
+}
+
+// completeReplicaStartup marks startup as done without reconciliation, as
+// replica stores what the primary reconciled
+func (app *App) completeReplicaStartup() {
+	app.startup.mu.Lock()
+	now := time.Now()
+	app.startup.status.Done = true
+	app.startup.status.StartedAt = now
+	app.startup.status.CompletedAt = now
+	app.startup.mu.Unlock()
+	close(app.startup.done)
+}
+
+// handleReplication applies changes of the primary in the order in which they
+// were recorded. Every change of a delegation replaces the replicated
+// delegation with the one currently exported by the primary, so applying the
+// same change twice is harmless. Sequence number of the last applied change is
+// persisted, so that replication continues after restart.
+func (app *App) handleReplication() {
+	cfg := app.config.ReplicaConfig
+	retryInterval := cfg.RetryInterval
+
+	ctx, cancel := app.appQuitContext()
+	defer cancel()
+
+	app.replica.mu.Lock()
+	app.replica.status.Primary = app.replicaSource.Address()
+	app.replica.mu.Unlock()
+
+	for {
+		caughtUp, err := app.replicateChanges(ctx)
+		if err != nil {
+			select {
+			case <-app.quit:
+				return
+			default:
+			}
+
+			app.replica.mu.Lock()
+			app.replica.status.LastError = err.Error()
+			app.replica.mu.Unlock()
+
+			app.logger.WithFields(logrus.Fields{
+				"err":     err,
+				"retryIn": retryInterval,
+			}).Warn("Failed to replicate changes of the primary")
+
+			select {
+			case <-time.After(retryInterval):
+			case <-app.quit:
+				return
+			}
+
+			retryInterval = min(2*retryInterval, replicaMaxRetryInterval)
+			continue
+		}
+		retryInterval = cfg.RetryInterval
+
+		app.replica.mu.Lock()
+		app.replica.status.LastError = ""
+		if caughtUp && app.replica.status.CaughtUpAt.IsZero() {
+			app.replica.status.CaughtUpAt = time.Now()
+			app.logger.WithFields(logrus.Fields{
+				"appliedSeq": app.replica.status.AppliedSeq,
+			}).Info("Replica caught up with the primary")
+		}
+		app.replica.mu.Unlock()
+
+		select {
+		case <-app.quit:
+			return
+		default:
+		}
+	}
+}
+
+// replicateChanges applies one batch of changes of the primary. It returns
+// true if there were no more changes to apply.
+func (app *App) replicateChanges(ctx context.Context) (bool, error) {
+	cfg := app.config.ReplicaConfig
+
+	cursor, err := app.txTracker.ReplicaCursor()
+	if err != nil {
+		return false, err
+	}
+
+	// primary waits only if there are no changes, so that backlog is applied
+	// without delay
+	changes, err := app.replicaSource.Changes(ctx, cursor, cfg.BatchSize, cfg.PollWait)
+	if err != nil {
+		return false, fmt.Errorf("failed to get changes of the primary: %w", err)
+	}
+
+	if len(changes) == 0 {
+		return true, nil
+	}
+
+	// delegation changed multiple times in the batch is replicated once
+	seen := make(map[chainhash.Hash]struct{}, len(changes))
+	for i := range changes {
+		ch := &changes[i]
+		if ch.StakingTxHash == nil {
+			continue
+		}
+		if _, ok := seen[*ch.StakingTxHash]; ok {
+			continue
+		}
+		seen[*ch.StakingTxHash] = struct{}{}
+
+		if err := app.replicateDelegation(ctx, ch.StakingTxHash, ch.Seq); err != nil {
+			return false, err
+		}
+	}
+
+	lastSeq := changes[len(changes)-1].Seq
+	if err := app.txTracker.SetReplicaCursor(lastSeq); err != nil {
+		return false, err
+	}
+
+	app.replica.mu.Lock()
+	app.replica.status.AppliedSeq = lastSeq
+	app.replica.status.Replicated += uint64(len(seen))
+	app.replica.mu.Unlock()
+
+	return uint64(len(changes)) < cfg.BatchSize, nil
+}
+
+// replicateDelegation stores delegation as currently exported by the primary,
+// or removes it if the primary does not track it anymore
+func (app *App) replicateDelegation(ctx context.Context, stakingTxHash *chainhash.Hash, seq uint64) error {
+	del, err := app.replicaSource.Delegation(ctx, stakingTxHash)
+	if errors.Is(err, ErrNotTrackedByPrimary) {
+		if err := app.txTracker.RemoveReplicatedTransaction(stakingTxHash); err != nil {
+			return fmt.Errorf("failed to remove replicated delegation %s: %w", stakingTxHash, err)
+		}
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get delegation %s from the primary: %w", stakingTxHash, err)
+	}
+
+	if err := app.txTracker.ReplicateTransaction(del.Transaction, del.Failure, seq); err != nil {
+		return fmt.Errorf("failed to store replicated delegation %s: %w", stakingTxHash, err)
+	}
+
+	// cached status was computed from the replaced delegation
+	app.statuses.remove(*stakingTxHash)
+
+	return nil
+}
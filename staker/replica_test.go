@@ -0,0 +1,133 @@
+package staker
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/babylonlabs-io/btc-staker/stakercfg"
+	"github.com/babylonlabs-io/btc-staker/stakerdb"
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+)
+
+// replicaTestSource serves changes and delegations of fake primary
+type replicaTestSource struct {
+	changes     []ReplicaChange
+	delegations map[chainhash.Hash]*ReplicatedDelegation
+	exported    map[chainhash.Hash]int
+}
+
+func (s *replicaTestSource) Address() string {
+	return "tcp://primary:15812"
+}
+
+func (s *replicaTestSource) Changes(_ context.Context, afterSeq, limit uint64, _ time.Duration) ([]ReplicaChange, error) {
+	var changes []ReplicaChange
+	for _, ch := range s.changes {
+		if ch.Seq > afterSeq && uint64(len(changes)) < limit {
+			changes = append(changes, ch)
+		}
+	}
+	return changes, nil
+}
+
+func (s *replicaTestSource) Delegation(_ context.Context, stakingTxHash *chainhash.Hash) (*ReplicatedDelegation, error) {
+	s.exported[*stakingTxHash]++
+
+	del, ok := s.delegations[*stakingTxHash]
+	if !ok {
+		return nil, ErrNotTrackedByPrimary
+	}
+	return del, nil
+}
+
+func genReplicaTestTransaction(t *testing.T, value int64) *stakerdb.ImportedTransaction {
+	key, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+
+	addr, err := btcutil.NewAddressTaproot(schnorr.SerializePubKey(key.PubKey()), &chaincfg.RegressionNetParams)
+	require.NoError(t, err)
+
+	tx := wire.NewMsgTx(2)
+	tx.AddTxIn(wire.NewTxIn(&wire.OutPoint{Hash: chainhash.HashH(key.Serialize())}, nil, nil))
+	tx.AddTxOut(wire.NewTxOut(value, []byte{0x51}))
+
+	return &stakerdb.ImportedTransaction{
+		StakingTx:     tx,
+		StakerAddress: addr,
+	}
+}
+
+func TestReplicateChanges(t *testing.T) {
+	t.Parallel()
+
+	cfg := stakercfg.DefaultConfig()
+	cfg.ReplicaConfig.PrimaryAddress = "tcp://primary:15812"
+
+	store := newArchiveTestStore(t)
+	app := &App{
+		config:    &cfg,
+		logger:    logrus.New(),
+		txTracker: store,
+		statuses:  newDelegationStatusCache(),
+	}
+
+	tracked := genReplicaTestTransaction(t, 10_000)
+	trackedHash := tracked.StakingTx.TxHash()
+	removed := genReplicaTestTransaction(t, 20_000)
+	removedHash := removed.StakingTx.TxHash()
+
+	// delegation which the primary stopped tracking was replicated before
+	require.NoError(t, store.ReplicateTransaction(removed, nil, 1))
+
+	src := &replicaTestSource{
+		changes: []ReplicaChange{
+			{Seq: 1, StakingTxHash: &trackedHash},
+			{Seq: 2},
+			{Seq: 3, StakingTxHash: &trackedHash},
+			{Seq: 4, StakingTxHash: &removedHash},
+		},
+		delegations: map[chainhash.Hash]*ReplicatedDelegation{
+			trackedHash: {Transaction: tracked},
+		},
+		exported: make(map[chainhash.Hash]int),
+	}
+	app.replicaSource = src
+
+	caughtUp, err := app.replicateChanges(context.Background())
+	require.NoError(t, err)
+	require.True(t, caughtUp)
+
+	// delegation changed twice in the batch is exported once
+	require.Equal(t, 1, src.exported[trackedHash])
+
+	_, err = store.GetTransaction(&trackedHash)
+	require.NoError(t, err)
+	_, err = store.GetTransaction(&removedHash)
+	require.ErrorIs(t, err, stakerdb.ErrTransactionNotFound)
+
+	cursor, err := store.ReplicaCursor()
+	require.NoError(t, err)
+	require.Equal(t, uint64(4), cursor)
+
+	status, err := app.ReplicaStatus()
+	require.NoError(t, err)
+	require.Equal(t, uint64(4), status.AppliedSeq)
+	require.Equal(t, uint64(2), status.Replicated)
+
+	// replica continues after the stored cursor
+	caughtUp, err = app.replicateChanges(context.Background())
+	require.NoError(t, err)
+	require.True(t, caughtUp)
+	require.Equal(t, 1, src.exported[trackedHash])
+
+	// replica never changes delegations itself
+	require.ErrorIs(t, app.checkStartupSync(), ErrReadOnlyReplica)
+}
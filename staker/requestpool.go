@@ -0,0 +1,94 @@
+package staker
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+
+	"github.com/babylonlabs-io/btc-staker/metrics"
+	"golang.org/x/sync/semaphore"
+)
+
+var (
+	// ErrRequestQueueFull is returned when too many requests are waiting for
+	// processing
+	ErrRequestQueueFull = errors.New("too many requests waiting for processing, try again later")
+	// ErrStakerShuttingDown is returned for requests still waiting for
+	// processing when staker stops
+	ErrStakerShuttingDown = errors.New("staker is shutting down")
+)
+
+// requestPool limits number of stake, unbond and spend requests processed
+// concurrently, so that parallel requests do not contend for the wallet.
+// Requests above the limit wait in a queue of bounded size.
+type requestPool struct {
+	workers   *semaphore.Weighted
+	maxQueued int64
+	queued    atomic.Int64
+	m         *metrics.StakerMetrics
+	quit      <-chan struct{}
+}
+
+func newRequestPool(
+	maxConcurrent uint32,
+	maxQueued uint32,
+	m *metrics.StakerMetrics,
+	quit <-chan struct{},
+) *requestPool {
+	return &requestPool{
+		workers:   semaphore.NewWeighted(int64(max(maxConcurrent, 1))),
+		maxQueued: int64(maxQueued),
+		m:         m,
+		quit:      quit,
+	}
+}
+
+// run executes fn once a worker is free. If queue is full, request is rejected
+// right away with ErrRequestQueueFull.
+func (p *requestPool) run(fn func() error) error {
+	if !p.workers.TryAcquire(1) {
+		if err := p.wait(); err != nil {
+			return err
+		}
+	}
+
+	p.m.InFlightRequests.Inc()
+	defer func() {
+		p.m.InFlightRequests.Dec()
+		p.workers.Release(1)
+	}()
+
+	return fn()
+}
+
+// wait blocks in queue until a worker is acquired
+func (p *requestPool) wait() error {
+	if p.queued.Add(1) > p.maxQueued {
+		p.queued.Add(-1)
+		p.m.RejectedRequests.Inc()
+		return ErrRequestQueueFull
+	}
+
+	p.m.QueuedRequests.Inc()
+	defer func() {
+		p.queued.Add(-1)
+		p.m.QueuedRequests.Dec()
+	}()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go func() {
+		select {
+		case <-p.quit:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	if err := p.workers.Acquire(ctx, 1); err != nil {
+		return ErrStakerShuttingDown
+	}
+
+	return nil
+}
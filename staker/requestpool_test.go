@@ -0,0 +1,134 @@
+package staker
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/babylonlabs-io/btc-staker/metrics"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+// occupyWorker runs request blocking until release is closed, and returns
+// once the request holds a worker
+func occupyWorker(t *testing.T, p *requestPool, release <-chan struct{}) <-chan error {
+	started := make(chan struct{})
+	done := make(chan error, 1)
+	go func() {
+		done <- p.run(func() error {
+			close(started)
+			<-release
+			return nil
+		})
+	}()
+
+	select {
+	case <-started:
+	case <-time.After(time.Second):
+		t.Fatal("request did not start")
+	}
+
+	return done
+}
+
+func TestRequestPoolLimitsConcurrency(t *testing.T) {
+	t.Parallel()
+
+	p := newRequestPool(2, 10, metrics.NewStakerMetrics(), make(chan struct{}))
+
+	var inFlight, maxInFlight atomic.Int64
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := p.run(func() error {
+				n := inFlight.Add(1)
+				for {
+					m := maxInFlight.Load()
+					if n <= m || maxInFlight.CompareAndSwap(m, n) {
+						break
+					}
+				}
+				time.Sleep(10 * time.Millisecond)
+				inFlight.Add(-1)
+				return nil
+			})
+			if err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	require.Equal(t, int64(2), maxInFlight.Load())
+	require.Equal(t, float64(0), testutil.ToFloat64(p.m.InFlightRequests))
+	require.Equal(t, float64(0), testutil.ToFloat64(p.m.QueuedRequests))
+}
+
+func TestRequestPoolRejectsWhenQueueIsFull(t *testing.T) {
+	t.Parallel()
+
+	p := newRequestPool(1, 1, metrics.NewStakerMetrics(), make(chan struct{}))
+	release := make(chan struct{})
+	first := occupyWorker(t, p, release)
+
+	queued := make(chan error, 1)
+	go func() {
+		queued <- p.run(func() error { return nil })
+	}()
+	require.Eventually(t, func() bool { return p.queued.Load() == 1 }, time.Second, time.Millisecond)
+
+	err := p.run(func() error {
+		t.Error("rejected request must not run")
+		return nil
+	})
+	require.ErrorIs(t, err, ErrRequestQueueFull)
+	require.Equal(t, float64(1), testutil.ToFloat64(p.m.RejectedRequests))
+	require.Equal(t, float64(1), testutil.ToFloat64(p.m.QueuedRequests))
+
+	// queued request runs once the worker is released
+	close(release)
+	require.NoError(t, <-first)
+	require.NoError(t, <-queued)
+	require.Equal(t, int64(0), p.queued.Load())
+}
+
+func TestRequestPoolStopsQueuedRequestsOnQuit(t *testing.T) {
+	t.Parallel()
+
+	quit := make(chan struct{})
+	p := newRequestPool(1, 10, metrics.NewStakerMetrics(), quit)
+	release := make(chan struct{})
+	first := occupyWorker(t, p, release)
+
+	queued := make(chan error, 1)
+	go func() {
+		queued <- p.run(func() error {
+			t.Error("request waiting on shutdown must not run")
+			return nil
+		})
+	}()
+	require.Eventually(t, func() bool { return p.queued.Load() == 1 }, time.Second, time.Millisecond)
+
+	close(quit)
+	require.ErrorIs(t, <-queued, ErrStakerShuttingDown)
+
+	// request already running is not interrupted
+	close(release)
+	require.NoError(t, <-first)
+}
+
+func TestRequestPoolReleasesWorkerOnError(t *testing.T) {
+	t.Parallel()
+
+	p := newRequestPool(1, 0, metrics.NewStakerMetrics(), make(chan struct{}))
+	errFailed := errors.New("failed")
+
+	require.ErrorIs(t, p.run(func() error { return errFailed }), errFailed)
+	// with no queue, request succeeds only if the worker was released
+	require.NoError(t, p.run(func() error { return nil }))
+}
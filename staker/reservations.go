@@ -0,0 +1,384 @@
+package staker
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	cl "github.com/babylonlabs-io/btc-staker/babylonclient"
+	"github.com/babylonlabs-io/btc-staker/stakerdb"
+	"github.com/babylonlabs-io/btc-staker/walletcontroller"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/sirupsen/logrus"
+)
+
+// handleReservationCleanup releases outpoints reserved by staking transactions
+// which permanently failed. Babylon state is checked periodically, while
+// unconfirmed staking transactions are checked for double spends and expiry on
+// every new block.
+func (app *App) handleReservationCleanup() {
+	release := func() {
+		if err := app.releaseStaleReservations(); err != nil {
+			app.logger.WithFields(logrus.Fields{
+				"err": err,
+			}).Error("Failed to release stale outpoint reservations")
+		}
+	}
+
+	detect := func() {
+		if err := app.detectDoubleSpends(); err != nil {
+			app.logger.WithFields(logrus.Fields{
+				"err": err,
+			}).Error("Failed to check tracked transactions for double spends")
+		}
+
+		if err := app.expireUnconfirmedTransactions(); err != nil {
+			app.logger.WithFields(logrus.Fields{
+				"err": err,
+			}).Error("Failed to check tracked transactions expiry")
+		}
+	}
+
+	// reservations could become stale while staker was down
+	detect()
+
+	var tickerChan <-chan time.Time
+	if interval := app.config.StakerConfig.ReservationCheckInterval; interval > 0 {
+		release()
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		tickerChan = ticker.C
+	}
+
+	for {
+		select {
+		case <-tickerChan:
+			release()
+		case <-app.newBlockChans[reservationCleanupWorker]:
+			detect()
+		case <-app.quit:
+			return
+		}
+	}
+}
+
+// reservedStakingTransactions returns tracked transactions which still hold
+// reservations of their inputs
+func (app *App) reservedStakingTransactions() (map[chainhash.Hash]*stakerdb.StoredTransaction, error) {
+	reserved, err := app.txTracker.ListReservedOutpoints()
+	if err != nil {
+		return nil, err
+	}
+
+	txs := make(map[chainhash.Hash]*stakerdb.StoredTransaction)
+	for _, r := range reserved {
+		if _, ok := txs[r.StakingTxHash]; ok {
+			continue
+		}
+
+		txHash := r.StakingTxHash
+		storedTx, err := app.txTracker.GetTransaction(&txHash)
+		if err != nil {
+			if errors.Is(err, stakerdb.ErrTransactionNotFound) {
+				// reservation without tracked transaction, nothing can use it
+				if err := app.txTracker.ReleaseOutpoints(&txHash); err != nil {
+					return nil, fmt.Errorf("failed to release outpoints of %s: %w", txHash, err)
+				}
+				continue
+			}
+			return nil, fmt.Errorf("failed to get tracked transaction %s: %w", txHash, err)
+		}
+
+		txs[txHash] = storedTx
+	}
+
+	return txs, nil
+}
+
+// failStakingTx marks tracked staking transaction as failed and releases its inputs
+func (app *App) failStakingTx(stakingTxHash *chainhash.Hash, note *stakerdb.FailureNote) error {
+	if err := app.txTracker.MarkTransactionFailed(stakingTxHash, note.Reason); err != nil {
+		return fmt.Errorf("failed to mark transaction %s as failed: %w", stakingTxHash, err)
+	}
+	// failed delegation must not be served with status from before the failure
+	app.statuses.remove(*stakingTxHash)
+
+	app.noteFailure(stakingTxHash, note)
+
+	if err := app.txTracker.ReleaseOutpoints(stakingTxHash); err != nil {
+		return fmt.Errorf("failed to release outpoints of %s: %w", stakingTxHash, err)
+	}
+
+	// failed staking transaction will never confirm, no point in rebroadcasting it
+	if err := app.unwatchMempoolTxs(stakingTxHash); err != nil {
+		return fmt.Errorf("failed to stop watching transactions of %s: %w", stakingTxHash, err)
+	}
+
+	return nil
+}
+
+// releaseStaleReservations releases inputs of every tracked staking transaction
+// whose delegation reached terminal state on babylon before the staking
+// transaction was sent to btc
+func (app *App) releaseStaleReservations() error {
+	txs, err := app.reservedStakingTransactions()
+	if err != nil {
+		return err
+	}
+
+	for txHash, storedTx := range txs {
+		txHash := txHash
+
+		failed, reason, err := app.stakingTxPermanentlyFailed(storedTx)
+		if err != nil {
+			app.logger.WithFields(logrus.Fields{
+				"stakingTxHash": txHash,
+				"err":           err,
+			}).Warn("Failed to check staking transaction status, keeping its outpoints reserved")
+			continue
+		}
+
+		if !failed {
+			continue
+		}
+
+		note := newFailureNote(
+			stakerdb.FailureSourceBabylon,
+			reason,
+			"reserved inputs were released, stake again to create new delegation",
+		)
+		if err := app.failStakingTx(&txHash, note); err != nil {
+			return err
+		}
+
+		app.logger.WithFields(logrus.Fields{
+			"stakingTxHash": txHash,
+			"reason":        reason,
+		}).Info("Released outpoints reserved by failed staking transaction")
+	}
+
+	return nil
+}
+
+// detectDoubleSpends checks whether any input of unconfirmed tracked staking
+// transaction was spent by a different transaction. Such staking transaction
+// can never be confirmed, so it is marked as failed and its inputs are released.
+// Inputs of confirmed staking transactions are released as they can't be used
+// by other transactions anymore.
+func (app *App) detectDoubleSpends() error {
+	txs, err := app.reservedStakingTransactions()
+	if err != nil {
+		return err
+	}
+
+	for txHash, storedTx := range txs {
+		txHash := txHash
+
+		status, err := app.stakingTxStatus(storedTx)
+		if err != nil {
+			app.logger.WithFields(logrus.Fields{
+				"stakingTxHash": txHash,
+				"err":           err,
+			}).Warn("Failed to check staking transaction status")
+			continue
+		}
+
+		switch status {
+		case walletcontroller.TxInChain:
+			if err := app.txTracker.ReleaseOutpoints(&txHash); err != nil {
+				return fmt.Errorf("failed to release outpoints of %s: %w", txHash, err)
+			}
+
+			app.logger.WithFields(logrus.Fields{
+				"stakingTxHash": txHash,
+			}).Debug("Released outpoints reserved by confirmed staking transaction")
+			continue
+		case walletcontroller.TxInMemPool:
+			continue
+		}
+
+		conflict, err := app.stakingTxConflictingInput(storedTx)
+		if err != nil {
+			app.logger.WithFields(logrus.Fields{
+				"stakingTxHash": txHash,
+				"err":           err,
+			}).Warn("Failed to check staking transaction inputs")
+			continue
+		}
+
+		if conflict == nil {
+			continue
+		}
+
+		note := newFailureNote(
+			stakerdb.FailureSourceBtc,
+			fmt.Sprintf("input %s was spent by another transaction", conflict),
+			"staking transaction can never confirm, check whether wallet inputs were spent outside of staker and stake again",
+		)
+		if err := app.failStakingTx(&txHash, note); err != nil {
+			return err
+		}
+
+		app.m.DelegationsDoubleSpent.Inc()
+		app.logger.WithFields(logrus.Fields{
+			"stakingTxHash": txHash,
+			"outpoint":      conflict,
+		}).Error("ALERT: staking transaction input double spent, delegation marked as failed")
+	}
+
+	return nil
+}
+
+// stakingTxStatus returns btc status of tracked staking transaction
+func (app *App) stakingTxStatus(storedTx *stakerdb.StoredTransaction) (walletcontroller.TxStatus, error) {
+	stakingTxHash := storedTx.StakingTx.TxHash()
+
+	if len(storedTx.StakingTx.TxOut) == 0 {
+		return walletcontroller.TxNotFound, fmt.Errorf("staking transaction %s has no outputs", stakingTxHash)
+	}
+
+	// pkScript is only used to build confirmation request, any output will do
+	_, status, err := app.wc.TxDetails(&stakingTxHash, storedTx.StakingTx.TxOut[0].PkScript)
+	if err != nil {
+		return walletcontroller.TxNotFound, fmt.Errorf("failed to get staking tx details: %w", err)
+	}
+
+	return status, nil
+}
+
+// stakingTxConflictingInput returns input of the staking transaction spent by
+// another transaction, or nil if staking transaction can still be confirmed.
+// Staking transaction may be broadcast or confirmed after its status was
+// checked, so inputs spent by the staking transaction itself are not
+// conflicts.
+func (app *App) stakingTxConflictingInput(storedTx *stakerdb.StoredTransaction) (*wire.OutPoint, error) {
+	stakingTxHash := storedTx.StakingTx.TxHash()
+
+	inputs := make([]wire.OutPoint, len(storedTx.StakingTx.TxIn))
+	for i, in := range storedTx.StakingTx.TxIn {
+		inputs[i] = in.PreviousOutPoint
+	}
+
+	spent, err := app.wc.OutputsSpent(inputs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check staking tx inputs: %w", err)
+	}
+
+	var spentInputs []wire.OutPoint
+	for i, s := range spent {
+		if s {
+			spentInputs = append(spentInputs, inputs[i])
+		}
+	}
+
+	if len(spentInputs) == 0 {
+		return nil, nil
+	}
+
+	spenders, err := app.wc.MempoolSpenders(spentInputs)
+	if errors.Is(err, walletcontroller.ErrMempoolSpendersNotSupported) {
+		// spenders are unknown, rely on status of staking transaction only
+		spenders = make([]*chainhash.Hash, len(spentInputs))
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to get spenders of staking tx inputs: %w", err)
+	}
+
+	var confirmedSpend *wire.OutPoint
+	for i, spender := range spenders {
+		if spender == nil {
+			if confirmedSpend == nil {
+				confirmedSpend = &spentInputs[i]
+			}
+			continue
+		}
+
+		if !spender.IsEqual(&stakingTxHash) {
+			return &spentInputs[i], nil
+		}
+	}
+
+	if confirmedSpend == nil {
+		// all spent inputs are spent by staking transaction in mempool
+		return nil, nil
+	}
+
+	// spender of confirmed spend is not known, it is a conflict unless staking
+	// transaction itself got confirmed in the meantime
+	status, err := app.stakingTxStatus(storedTx)
+	if err != nil {
+		return nil, err
+	}
+
+	if status != walletcontroller.TxNotFound {
+		return nil, nil
+	}
+
+	return confirmedSpend, nil
+}
+
+// stakingTxPermanentlyFailed checks whether delegation reached terminal state on
+// babylon while staking transaction was never sent to btc. Returns reason of the
+// failure if it did.
+func (app *App) stakingTxPermanentlyFailed(storedTx *stakerdb.StoredTransaction) (bool, string, error) {
+	stakingTxHash := storedTx.StakingTx.TxHash()
+
+	di, err := app.babylonClient.QueryBTCDelegation(&stakingTxHash)
+	if err != nil {
+		if errors.Is(err, cl.ErrDelegationNotFound) {
+			// either we are connected to wrong babylon network or node is
+			// still syncing, do not touch anything
+			return false, "", nil
+		}
+		return false, "", fmt.Errorf("failed to get delegation info: %w", err)
+	}
+
+	switch di.BtcDelegation.GetStatusDesc() {
+	case BabylonExpiredStatus, BabylonUnbondedStatus:
+	default:
+		return false, "", nil
+	}
+
+	stakingOutputIdx := di.BtcDelegation.StakingOutputIdx
+	if int(stakingOutputIdx) >= len(storedTx.StakingTx.TxOut) {
+		return false, "", fmt.Errorf("staking output index %d out of range", stakingOutputIdx)
+	}
+
+	_, status, err := app.wc.TxDetails(&stakingTxHash, storedTx.StakingTx.TxOut[stakingOutputIdx].PkScript)
+	if err != nil {
+		return false, "", fmt.Errorf("failed to get staking tx details: %w", err)
+	}
+
+	if status != walletcontroller.TxNotFound {
+		return false, "", nil
+	}
+
+	return true, "delegation reached terminal state on babylon before staking transaction was sent to btc", nil
+}
+
+// stakingTxFailure returns failure of the tracked staking transaction, nil
+// if it did not fail
+func (app *App) stakingTxFailure(stakingTxHash *chainhash.Hash) (*stakerdb.TransactionFailure, error) {
+	return app.txTracker.GetTransactionFailure(stakingTxHash)
+}
+
+// UnreserveOutpoint manually releases a reserved outpoint and returns hash of
+// the staking transaction which was using it
+func (app *App) UnreserveOutpoint(op *wire.OutPoint) (*chainhash.Hash, error) {
+	if err := app.checkStartupSync(); err != nil {
+		return nil, err
+	}
+
+	stakingTxHash, err := app.txTracker.UnreserveOutpoint(op)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unreserve outpoint %s: %w", op, err)
+	}
+
+	app.logger.WithFields(logrus.Fields{
+		"outpoint":      op,
+		"stakingTxHash": stakingTxHash,
+	}).Info("Outpoint reservation manually released")
+
+	return stakingTxHash, nil
+}
@@ -0,0 +1,137 @@
+package staker
+
+import (
+	"testing"
+
+	"github.com/babylonlabs-io/btc-staker/stakercfg"
+	"github.com/babylonlabs-io/btc-staker/stakerdb"
+	"github.com/babylonlabs-io/btc-staker/testutil/mocks"
+	"github.com/babylonlabs-io/btc-staker/walletcontroller"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	notifier "github.com/lightningnetwork/lnd/chainntnfs"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestStakingTxConflictingInput(t *testing.T) {
+	t.Parallel()
+
+	imported := genReplicaTestTransaction(t, 10_000)
+	stakingTx := imported.StakingTx
+	stakingTx.AddTxIn(wire.NewTxIn(&wire.OutPoint{Hash: chainhash.Hash{2}, Index: 1}, nil, nil))
+	stakingTxHash := stakingTx.TxHash()
+	storedTx := &stakerdb.StoredTransaction{StakingTx: stakingTx}
+	otherTxHash := chainhash.Hash{3}
+
+	tests := []struct {
+		name     string
+		spent    []bool
+		spenders []*chainhash.Hash
+		// error of spenders lookup
+		spendersErr error
+		// status of staking transaction when it is checked again, nil if it
+		// must not be checked
+		status   *walletcontroller.TxStatus
+		conflict *wire.OutPoint
+	}{
+		{
+			name:  "no input spent",
+			spent: []bool{false, false},
+		},
+		{
+			name:     "inputs spent by staking transaction in mempool",
+			spent:    []bool{true, true},
+			spenders: []*chainhash.Hash{&stakingTxHash, &stakingTxHash},
+		},
+		{
+			name:     "input spent by other transaction in mempool",
+			spent:    []bool{false, true},
+			spenders: []*chainhash.Hash{&otherTxHash},
+			conflict: &stakingTx.TxIn[1].PreviousOutPoint,
+		},
+		{
+			name:     "staking transaction confirmed since its status was checked",
+			spent:    []bool{true, true},
+			spenders: []*chainhash.Hash{nil, nil},
+			status:   statusPtr(walletcontroller.TxInChain),
+		},
+		{
+			name:     "input spent by unknown confirmed transaction",
+			spent:    []bool{true, false},
+			spenders: []*chainhash.Hash{nil},
+			status:   statusPtr(walletcontroller.TxNotFound),
+			conflict: &stakingTx.TxIn[0].PreviousOutPoint,
+		},
+		{
+			name:        "spenders not supported by node",
+			spent:       []bool{true, false},
+			spendersErr: walletcontroller.ErrMempoolSpendersNotSupported,
+			status:      statusPtr(walletcontroller.TxNotFound),
+			conflict:    &stakingTx.TxIn[0].PreviousOutPoint,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			wc := mocks.NewMockWalletController(ctrl)
+			app := &App{wc: wc}
+
+			wc.EXPECT().OutputsSpent(gomock.Any()).Return(tc.spent, nil)
+			if tc.spenders != nil || tc.spendersErr != nil {
+				wc.EXPECT().MempoolSpenders(gomock.Any()).Return(tc.spenders, tc.spendersErr)
+			}
+			if tc.status != nil {
+				wc.EXPECT().TxDetails(gomock.Any(), gomock.Any()).Return(nil, *tc.status, nil)
+			}
+
+			conflict, err := app.stakingTxConflictingInput(storedTx)
+			require.NoError(t, err)
+			require.Equal(t, tc.conflict, conflict)
+		})
+	}
+}
+
+func TestDetectDoubleSpendsReleasesConfirmed(t *testing.T) {
+	t.Parallel()
+
+	cfg := stakercfg.DefaultConfig()
+	store := newArchiveTestStore(t)
+
+	ctrl := gomock.NewController(t)
+	wc := mocks.NewMockWalletController(ctrl)
+	app := &App{
+		config:    &cfg,
+		logger:    logrus.New(),
+		txTracker: store,
+		wc:        wc,
+	}
+
+	confirmed := genReplicaTestTransaction(t, 10_000)
+	require.NoError(t, store.AddTransactionSentToBabylon(confirmed.StakingTx, confirmed.StakerAddress))
+	pending := genReplicaTestTransaction(t, 20_000)
+	require.NoError(t, store.AddTransactionSentToBabylon(pending.StakingTx, pending.StakerAddress))
+	pendingHash := pending.StakingTx.TxHash()
+
+	wc.EXPECT().TxDetails(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(txHash *chainhash.Hash, _ []byte) (*notifier.TxConfirmation, walletcontroller.TxStatus, error) {
+			if txHash.IsEqual(&pendingHash) {
+				return nil, walletcontroller.TxInMemPool, nil
+			}
+			return nil, walletcontroller.TxInChain, nil
+		},
+	).Times(2)
+
+	require.NoError(t, app.detectDoubleSpends())
+
+	reserved, err := store.ListReservedOutpoints()
+	require.NoError(t, err)
+	require.Len(t, reserved, 1)
+	require.Equal(t, pendingHash, reserved[0].StakingTxHash)
+}
+
+func statusPtr(s walletcontroller.TxStatus) *walletcontroller.TxStatus {
+	return &s
+}
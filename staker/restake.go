@@ -0,0 +1,205 @@
+package staker
+
+import (
+	"fmt"
+
+	staking "github.com/babylonlabs-io/babylon/v4/btcstaking"
+	cl "github.com/babylonlabs-io/btc-staker/babylonclient"
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/btcsuite/btcwallet/wallet/txrules"
+	"github.com/btcsuite/btcwallet/wallet/txsizes"
+	"github.com/sirupsen/logrus"
+)
+
+// chainedStakingTxFee returns fee of staking transaction which has a single
+// input paying to the staker address and a single staking output
+func chainedStakingTxFee(
+	stakerAddressScript []byte,
+	stakingOutput *wire.TxOut,
+	feeRate btcutil.Amount,
+) (btcutil.Amount, error) {
+	var numP2TR, numP2WPKH int
+	switch {
+	case txscript.IsPayToTaproot(stakerAddressScript):
+		numP2TR = 1
+	case txscript.IsPayToWitnessPubKeyHash(stakerAddressScript):
+		numP2WPKH = 1
+	default:
+		return 0, fmt.Errorf("unsupported staker address type")
+	}
+
+	txSize := txsizes.EstimateVirtualSize(0, numP2TR, numP2WPKH, 0, []*wire.TxOut{stakingOutput}, 0)
+
+	return txrules.FeeForSerializeSize(feeRate, txSize), nil
+}
+
+// restakeAmount returns amount staked by staking transaction chained to the
+// withdrawal output and its fee. Whole withdrawn value except the fee is
+// staked, which must be within staking value limits of babylon.
+func restakeAmount(
+	withdrawalOutput *wire.TxOut,
+	stakingOutput *wire.TxOut,
+	feeRate btcutil.Amount,
+	params *cl.StakingParams,
+) (btcutil.Amount, btcutil.Amount, error) {
+	stakingFee, err := chainedStakingTxFee(withdrawalOutput.PkScript, stakingOutput, feeRate)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	stakingAmount := btcutil.Amount(withdrawalOutput.Value) - stakingFee
+
+	if stakingAmount < params.MinStakingValue || stakingAmount > params.MaxStakingValue {
+		return 0, 0, fmt.Errorf("staking amount %d is not in range [%d, %d]",
+			stakingAmount, params.MinStakingValue, params.MaxStakingValue)
+	}
+
+	return stakingAmount, stakingFee, nil
+}
+
+// RestakeFromUnbonded withdraws funds of unbonded or expired delegation back to
+// the staker address and immediately stakes them again with a staking
+// transaction spending the withdrawal output. Whole withdrawn value except
+// staking transaction fee is staked. Returns hash of the withdrawal transaction
+// and hash of the new staking transaction.
+func (app *App) RestakeFromUnbonded(
+	stakingTxHash *chainhash.Hash,
+	fpPks []*btcec.PublicKey,
+	stakingTimeBlocks uint16,
+) (*chainhash.Hash, *chainhash.Hash, error) {
+	if err := app.checkStartupSync(); err != nil {
+		return nil, nil, err
+	}
+
+	var withdrawalTxHash, newStakingTxHash *chainhash.Hash
+	err := app.requests.run(func() error {
+		var err error
+		withdrawalTxHash, newStakingTxHash, err = app.restakeFromUnbonded(stakingTxHash, fpPks, stakingTimeBlocks)
+		return err
+	})
+	return withdrawalTxHash, newStakingTxHash, err
+}
+
+func (app *App) restakeFromUnbonded(
+	stakingTxHash *chainhash.Hash,
+	fpPks []*btcec.PublicKey,
+	stakingTimeBlocks uint16,
+) (*chainhash.Hash, *chainhash.Hash, error) {
+	// check we are not shutting down
+	select {
+	case <-app.quit:
+		return nil, nil, ErrStakerShuttingDown
+
+	default:
+	}
+
+	storedTx, err := app.txTracker.GetTransaction(stakingTxHash)
+	if err != nil {
+		return nil, nil, fmt.Errorf("cannot restake. Error getting staking transaction: %w", err)
+	}
+
+	stakerAddress, err := btcutil.DecodeAddress(storedTx.StakerAddress, app.network)
+	if err != nil {
+		return nil, nil, fmt.Errorf("cannot restake. Error decoding staker address: %w", err)
+	}
+
+	// restaked delegation stays with the tenant of the withdrawn one
+	tenant, err := app.txTracker.GetTransactionTenant(stakingTxHash)
+	if err != nil {
+		return nil, nil, fmt.Errorf("cannot restake: %w", err)
+	}
+
+	// withdrawal must go to the staker address, as the new staking transaction
+	// is signed with the staker key
+	spendStakeTxInfo, err := app.buildSpendStakeTx(stakingTxHash, storedTx, stakerAddress, stakerAddress, FeeSelection{})
+	if err != nil {
+		return nil, nil, fmt.Errorf("cannot restake: %w", err)
+	}
+
+	withdrawalOutput := spendStakeTxInfo.spendStakeTx.TxOut[0]
+
+	params, err := app.babylonClient.Params()
+	if err != nil {
+		return nil, nil, fmt.Errorf("cannot restake. Error getting params: %w", err)
+	}
+
+	stakerPubKey, err := app.wc.AddressPublicKey(stakerAddress)
+	if err != nil {
+		return nil, nil, fmt.Errorf("cannot restake. Error getting staker public key: %w", err)
+	}
+
+	// staking output script does not depend on the amount, so it can be used to
+	// estimate the fee before the amount is known
+	stakingInfo, err := staking.BuildStakingInfo(
+		stakerPubKey,
+		fpPks,
+		params.CovenantPks,
+		params.CovenantQuruomThreshold,
+		stakingTimeBlocks,
+		btcutil.Amount(withdrawalOutput.Value),
+		app.network,
+	)
+	if err != nil {
+		return nil, nil, fmt.Errorf("cannot restake. Error building staking info: %w", err)
+	}
+
+	// check what can be checked before withdrawal is sent, funds of failed
+	// restake would otherwise stay idle in the wallet
+	stakingAmount, stakingFee, err := restakeAmount(
+		withdrawalOutput,
+		stakingInfo.StakingOutput,
+		btcutil.Amount(app.estimatedFeeRate()),
+		params,
+	)
+	if err != nil {
+		return nil, nil, fmt.Errorf("cannot restake: %w", err)
+	}
+
+	if stakingTimeBlocks < params.MinStakingTime || stakingTimeBlocks > params.MaxStakingTime {
+		return nil, nil, fmt.Errorf("cannot restake. Staking time %d is not in range [%d, %d]",
+			stakingTimeBlocks, params.MinStakingTime, params.MaxStakingTime)
+	}
+
+	if err := app.policy.checkStakingTime(stakingTimeBlocks); err != nil {
+		return nil, nil, fmt.Errorf("cannot restake: %w", err)
+	}
+
+	withdrawalTxHash, _, err := app.sendSpendStakeTx(stakingTxHash, spendStakeTxInfo, stakerAddress, stakerAddress)
+	if err != nil {
+		return nil, nil, fmt.Errorf("cannot restake: %w", err)
+	}
+
+	newStakingTxHash, err := app.stakeFunds(
+		stakerAddress,
+		stakingAmount,
+		fpPks,
+		stakingTimeBlocks,
+		wire.NewOutPoint(withdrawalTxHash, 0),
+		tenant,
+		0,
+		"",
+		FeeSelection{},
+	)
+	if err == nil && newStakingTxHash == nil {
+		// staking is not started once shutdown begins
+		err = ErrStakerShuttingDown
+	}
+	if err != nil {
+		return withdrawalTxHash, nil, fmt.Errorf("withdrawal transaction %s sent, but staking withdrawn funds failed: %w",
+			withdrawalTxHash, err)
+	}
+
+	app.logger.WithFields(logrus.Fields{
+		"stakingTxHash":    stakingTxHash,
+		"withdrawalTxHash": withdrawalTxHash,
+		"newStakingTxHash": newStakingTxHash,
+		"stakingAmount":    stakingAmount,
+		"stakingFee":       stakingFee,
+	}).Info("Restaked funds withdrawn from unbonded delegation")
+
+	return withdrawalTxHash, newStakingTxHash, nil
+}
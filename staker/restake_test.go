@@ -0,0 +1,135 @@
+package staker
+
+import (
+	"testing"
+
+	cl "github.com/babylonlabs-io/btc-staker/babylonclient"
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/btcsuite/btcwallet/wallet/txrules"
+	"github.com/btcsuite/btcwallet/wallet/txsizes"
+	"github.com/stretchr/testify/require"
+)
+
+func p2wpkhTestScript(t *testing.T) []byte {
+	key, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+
+	addr, err := btcutil.NewAddressWitnessPubKeyHash(
+		btcutil.Hash160(key.PubKey().SerializeCompressed()), &chaincfg.RegressionNetParams,
+	)
+	require.NoError(t, err)
+
+	script, err := txscript.PayToAddrScript(addr)
+	require.NoError(t, err)
+
+	return script
+}
+
+func TestChainedStakingTxFee(t *testing.T) {
+	t.Parallel()
+
+	const feeRate = btcutil.Amount(2000)
+	stakingOutput := wire.NewTxOut(100_000, policyTestScript(t))
+	outputs := []*wire.TxOut{stakingOutput}
+
+	p2trFee, err := chainedStakingTxFee(policyTestScript(t), stakingOutput, feeRate)
+	require.NoError(t, err)
+	require.Equal(t, txrules.FeeForSerializeSize(feeRate, txsizes.EstimateVirtualSize(0, 1, 0, 0, outputs, 0)), p2trFee)
+
+	p2wpkhFee, err := chainedStakingTxFee(p2wpkhTestScript(t), stakingOutput, feeRate)
+	require.NoError(t, err)
+	require.Equal(t, txrules.FeeForSerializeSize(feeRate, txsizes.EstimateVirtualSize(0, 0, 1, 0, outputs, 0)), p2wpkhFee)
+	require.Greater(t, p2wpkhFee, p2trFee)
+
+	_, err = chainedStakingTxFee([]byte{txscript.OP_TRUE}, stakingOutput, feeRate)
+	require.ErrorContains(t, err, "unsupported staker address type")
+}
+
+func TestRestakeAmount(t *testing.T) {
+	t.Parallel()
+
+	const feeRate = btcutil.Amount(2000)
+	stakerScript := policyTestScript(t)
+	stakingOutput := wire.NewTxOut(0, policyTestScript(t))
+
+	fee, err := chainedStakingTxFee(stakerScript, stakingOutput, feeRate)
+	require.NoError(t, err)
+
+	params := &cl.StakingParams{
+		BtcStakingParams: cl.BtcStakingParams{
+			MinStakingValue: 50_000,
+			MaxStakingValue: 100_000,
+		},
+	}
+
+	tests := []struct {
+		name            string
+		withdrawn       int64
+		script          []byte
+		expectedAmount  btcutil.Amount
+		wantErrContains string
+	}{
+		{
+			name:           "whole value except fee is staked",
+			withdrawn:      80_000,
+			script:         stakerScript,
+			expectedAmount: 80_000 - fee,
+		},
+		{
+			name:           "amount after fee at min staking value",
+			withdrawn:      50_000 + int64(fee),
+			script:         stakerScript,
+			expectedAmount: 50_000,
+		},
+		{
+			// withdrawn value is within limits, but not after the fee
+			name:            "amount after fee below min staking value",
+			withdrawn:       50_000,
+			script:          stakerScript,
+			wantErrContains: "is not in range",
+		},
+		{
+			name:            "amount above max staking value",
+			withdrawn:       200_000,
+			script:          stakerScript,
+			wantErrContains: "is not in range",
+		},
+		{
+			name:            "unsupported withdrawal address",
+			withdrawn:       80_000,
+			script:          []byte{txscript.OP_TRUE},
+			wantErrContains: "unsupported staker address type",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			amount, stakingFee, err := restakeAmount(wire.NewTxOut(tc.withdrawn, tc.script), stakingOutput, feeRate, params)
+			if tc.wantErrContains != "" {
+				require.ErrorContains(t, err, tc.wantErrContains)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tc.expectedAmount, amount)
+			require.Equal(t, fee, stakingFee)
+		})
+	}
+}
+
+func TestRestakeFromUnbondedShuttingDown(t *testing.T) {
+	t.Parallel()
+
+	app := &App{quit: make(chan struct{})}
+	close(app.quit)
+
+	withdrawalTxHash, stakingTxHash, err := app.restakeFromUnbonded(&chainhash.Hash{}, nil, 100)
+	require.ErrorIs(t, err, ErrStakerShuttingDown)
+	require.Nil(t, withdrawalTxHash)
+	require.Nil(t, stakingTxHash)
+}
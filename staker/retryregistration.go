@@ -0,0 +1,85 @@
+package staker
+
+import (
+	"errors"
+	"fmt"
+
+	cl "github.com/babylonlabs-io/btc-staker/babylonclient"
+	"github.com/babylonlabs-io/btc-staker/walletcontroller"
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+)
+
+// ErrDelegationRegistered is returned when registration is retried for
+// delegation which is already known to Babylon
+var ErrDelegationRegistered = errors.New("delegation is already registered on babylon")
+
+// RetryDelegationRegistration registers tracked delegation on Babylon again,
+// after its previous registration failed. Staking transaction must be confirmed
+// on btc and is parsed with the given covenant keys and quorum, which allows
+// to correct covenant set used by the failed registration. Failure of the
+// previous registration is removed from the db together with recording the
+// new one.
+func (app *App) RetryDelegationRegistration(
+	stkTxHash *chainhash.Hash,
+	covenantPks []*btcec.PublicKey,
+	covenantQuorum uint32,
+) (babylonBTCDelegationTxHash string, err error) {
+	if err := app.checkStartupSync(); err != nil {
+		return "", err
+	}
+
+	// check we are not shutting down
+	select {
+	case <-app.quit:
+		return "", nil
+	default:
+	}
+
+	storedTx, err := app.txTracker.GetTransaction(stkTxHash)
+	if err != nil {
+		return "", err
+	}
+
+	stakerAddr, err := btcutil.DecodeAddress(storedTx.StakerAddress, app.network)
+	if err != nil {
+		return "", fmt.Errorf("failed to decode staker address %s: %w", storedTx.StakerAddress, err)
+	}
+
+	// delegation known to Babylon can't be registered again, as Babylon rejects
+	// reused staking transactions
+	_, err = app.babylonClient.QueryBTCDelegation(stkTxHash)
+	if err == nil {
+		return "", fmt.Errorf("%w: %s", ErrDelegationRegistered, stkTxHash)
+	}
+	if !errors.Is(err, cl.ErrDelegationNotFound) {
+		return "", fmt.Errorf("failed to query delegation %s: %w", stkTxHash, err)
+	}
+
+	parsedStakingTx, notifierTx, status, err := walletcontroller.StkTxV0ParsedWithBlock(app.wc, app.network, stkTxHash, covenantPks, covenantQuorum)
+	if err != nil {
+		return "", err
+	}
+	if status != walletcontroller.TxInChain {
+		return "", fmt.Errorf("staking transaction %s is not confirmed on btc", stkTxHash)
+	}
+
+	if err := app.checkPhase1Registration(stkTxHash, parsedStakingTx, notifierTx.BlockHeight); err != nil {
+		return "", err
+	}
+
+	if err := app.checkChainSafety(); err != nil {
+		return "", err
+	}
+
+	pop, err := app.unlockAndCreatePop(stakerAddr)
+	if err != nil {
+		return "", err
+	}
+
+	req := newMigrateStakingCmd(stakerAddr, notifierTx, parsedStakingTx, pop)
+	req.retry = true
+
+	return app.sendMigrateStakingCmd(req)
+}
@@ -0,0 +1,26 @@
+package staker_test
+
+import (
+	"testing"
+
+	"github.com/babylonlabs-io/btc-staker/staker"
+	"github.com/babylonlabs-io/btc-staker/stakerdb"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetryRegistrationOfRegisteredDelegation(t *testing.T) {
+	t.Parallel()
+
+	sim, app, addr := startSimulatedApp(t)
+	stakingTxHash, _ := activeDelegation(t, sim, app, addr)
+
+	// babylon rejects reused staking transaction, so registration is not sent
+	_, err := app.RetryDelegationRegistration(stakingTxHash, nil, 0)
+	require.ErrorIs(t, err, staker.ErrDelegationRegistered)
+	require.Empty(t, sim.Babylon.PendingDelegations())
+
+	// only tracked delegations can be registered again
+	_, err = app.RetryDelegationRegistration(&chainhash.Hash{1}, nil, 0)
+	require.ErrorIs(t, err, stakerdb.ErrTransactionNotFound)
+}
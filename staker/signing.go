@@ -25,6 +25,17 @@ type stakeExpSignInfo struct {
 // signStakingTransaction signs a staking transaction, handling both regular staking
 // and stake expansion transactions with different signing requirements
 func (app *App) signStakingTransaction(tx *wire.MsgTx, di *btcstktypes.QueryBTCDelegationResponse) (*wire.MsgTx, error) {
+	// staking output is rebuilt from delegation registered on Babylon, so that
+	// signing policy does not trust the transaction builder
+	var stakingScripts [][]byte
+	if app.policy != nil && di != nil && di.BtcDelegation != nil {
+		script, err := app.delegationStakingScript(di.BtcDelegation)
+		if err != nil {
+			return nil, fmt.Errorf("failed to rebuild staking output: %w", err)
+		}
+		stakingScripts = [][]byte{script}
+	}
+
 	// Check if this is a stake expansion transaction (exactly 2 inputs)
 	if len(tx.TxIn) == 2 {
 		// This is likely a stake expansion transaction
@@ -36,20 +47,24 @@ func (app *App) signStakingTransaction(tx *wire.MsgTx, di *btcstktypes.QueryBTCD
 		// and return proper error if this is not actually a stake expansion
 		// If it is not, it will fall back to regular staking signing
 		// which is what we want for regular staking transactions with 2 inputs.
-		return app.signStakeExpansionTransaction(tx, di)
+		return app.signStakeExpansionTransaction(tx, di, stakingScripts...)
 	}
 
 	// Regular staking transaction - use normal wallet signing
-	return app.signRegularStakingTransaction(tx)
+	return app.signRegularStakingTransaction(tx, stakingScripts...)
 }
 
 // signStakeExpansionTransaction signs a stake expansion transaction with mixed input types
-func (app *App) signStakeExpansionTransaction(tx *wire.MsgTx, di *btcstktypes.QueryBTCDelegationResponse) (*wire.MsgTx, error) {
+func (app *App) signStakeExpansionTransaction(
+	tx *wire.MsgTx,
+	di *btcstktypes.QueryBTCDelegationResponse,
+	stakingScripts ...[]byte,
+) (*wire.MsgTx, error) {
 	// Verify this is a stake expansion transaction
 	if di == nil || di.BtcDelegation == nil || di.BtcDelegation.StkExp == nil {
 		// if it is not a stake expansion delegation, it might be a regular delegation
 		// with 2 inputs, so we sign it as a regular staking transaction
-		return app.signRegularStakingTransaction(tx)
+		return app.signRegularStakingTransaction(tx, stakingScripts...)
 	}
 
 	// Check if we have covenant signatures for stake expansion
@@ -72,14 +87,14 @@ func (app *App) signStakeExpansionTransaction(tx *wire.MsgTx, di *btcstktypes.Qu
 	}
 
 	// Build the unbondWitness for the taproot input
-	unbondWitness, err := app.buildUnbondingPathWitness(tx, di.BtcDelegation.StkExp)
+	unbondWitness, err := app.buildUnbondingPathWitness(tx, di.BtcDelegation.StkExp, stakingScripts...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create unbonding path witness: %w", err)
 	}
 
 	// Add the witness to the taproot spent and sign the staking expansion transaction
 	tx.TxIn[0].Witness = unbondWitness
-	signedTx, err := app.signTx(tx)
+	signedTx, err := app.signTx(tx, stakingScripts...)
 	if err != nil {
 		return nil, fmt.Errorf("staking expansion transaction: %w", err)
 	}
@@ -175,7 +190,11 @@ func (app *App) getUnbondingSpendInfo(params *cl.BtcStakingParams, stakerAddr bt
 // buildUnbondingPathWitness retrieves the witness for the unbonding path of a stake expansion transaction.
 // This is used to sign the taproot input that spends the previous staking output.
 // It handles the covenant signatures and the taproot signature for the staker.
-func (app *App) buildUnbondingPathWitness(tx *wire.MsgTx, stkExp *btcstktypes.StakeExpansionResponse) (wire.TxWitness, error) {
+func (app *App) buildUnbondingPathWitness(
+	tx *wire.MsgTx,
+	stkExp *btcstktypes.StakeExpansionResponse,
+	stakingScripts ...[]byte,
+) (wire.TxWitness, error) {
 	var (
 		fundingOutpoint  = tx.TxIn[1].PreviousOutPoint
 		stakingTxHash    = tx.TxHash()
@@ -192,6 +211,10 @@ func (app *App) buildUnbondingPathWitness(tx *wire.MsgTx, stkExp *btcstktypes.St
 			len(stkExp.PreviousStkCovenantSigs), si.Params.CovenantQuruomThreshold)
 	}
 
+	if err := app.policy.checkProtocolSpend(tx, []*wire.TxOut{si.StakingOutput, si.FundingOutput}, stakingScripts...); err != nil {
+		return nil, err
+	}
+
 	// Use the two-input signing method that matches the covenant signature approach
 	// to get the taproot signature for the staker
 	stakerSig, err := app.wc.SignTwoInputTaprootSpendingTransaction(
@@ -236,8 +259,16 @@ func (app *App) buildUnbondingPathWitness(tx *wire.MsgTx, stkExp *btcstktypes.St
 }
 
 // signRegularStakingTransaction signs a regular staking transaction using wallet signing
-func (app *App) signRegularStakingTransaction(tx *wire.MsgTx) (*wire.MsgTx, error) {
-	signedTx, err := app.signTx(tx)
+func (app *App) signRegularStakingTransaction(tx *wire.MsgTx, stakingScripts ...[]byte) (*wire.MsgTx, error) {
+	if app.policy != nil {
+		extraScripts, err := app.stakingTxExtraScripts()
+		if err != nil {
+			return nil, err
+		}
+		stakingScripts = append(stakingScripts, extraScripts...)
+	}
+
+	signedTx, err := app.signTx(tx, stakingScripts...)
 	if err != nil {
 		return nil, fmt.Errorf("regular staking transaction: %w", err)
 	}
@@ -249,8 +280,13 @@ func (app *App) signRegularStakingTransaction(tx *wire.MsgTx) (*wire.MsgTx, erro
 	return signedTx, nil
 }
 
-// signTx signs a transaction using wallet signing
-func (app *App) signTx(tx *wire.MsgTx) (*wire.MsgTx, error) {
+// signTx signs a transaction using wallet signing. protocolScripts are
+// staking outputs the transaction may create.
+func (app *App) signTx(tx *wire.MsgTx, protocolScripts ...[]byte) (*wire.MsgTx, error) {
+	if err := app.checkSigningPolicy(tx, protocolScripts...); err != nil {
+		return nil, err
+	}
+
 	signedTx, fullySigned, err := app.wc.SignRawTransaction(tx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to sign transaction: %w", err)
@@ -260,6 +296,11 @@ func (app *App) signTx(tx *wire.MsgTx) (*wire.MsgTx, error) {
 		return nil, nil // Return nil to indicate signing failed
 	}
 
+	// fee rate can be checked only once transaction is signed
+	if err := app.checkSigningPolicy(signedTx, protocolScripts...); err != nil {
+		return nil, err
+	}
+
 	return signedTx, nil
 }
 
@@ -0,0 +1,321 @@
+package staker
+
+import (
+	"errors"
+	"fmt"
+
+	staking "github.com/babylonlabs-io/babylon/v4/btcstaking"
+	btcstktypes "github.com/babylonlabs-io/babylon/v4/x/btcstaking/types"
+	cl "github.com/babylonlabs-io/btc-staker/babylonclient"
+	scfg "github.com/babylonlabs-io/btc-staker/stakercfg"
+	"github.com/babylonlabs-io/btc-staker/walletcontroller"
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/mempool"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+)
+
+// ErrSigningPolicyViolation is returned when transaction which staker is about
+// to sign violates configured signing policy
+var ErrSigningPolicyViolation = errors.New("transaction violates signing policy")
+
+// signingPolicy validates transactions before they are signed. Outputs must
+// pay to wallet addresses, allowed addresses or protocol scripts passed by the
+// caller, like staking outputs derived from Babylon params and staker keys and
+// extra outputs of staking transactions, or be zero value OP_RETURN outputs.
+// Nil policy allows everything.
+type signingPolicy struct {
+	cfg     *scfg.SigningPolicyConfig
+	wc      walletcontroller.WalletController
+	net     *chaincfg.Params
+	allowed map[string]struct{}
+}
+
+// newSigningPolicy returns nil if signing policy is not enabled in the config
+func newSigningPolicy(
+	config *scfg.Config,
+	wc walletcontroller.WalletController,
+) (*signingPolicy, error) {
+	cfg := config.SigningPolicyConfig
+	if cfg == nil || !cfg.Enabled {
+		return nil, nil
+	}
+
+	addresses := cfg.AllowedAddresses
+	if config.StakerConfig.ChangeAddressType == scfg.AddressTypeExternal {
+		addresses = append(addresses, config.StakerConfig.ChangeAddress)
+	}
+	if config.StakerConfig.WithdrawalAddressType == scfg.AddressTypeExternal {
+		addresses = append(addresses, config.StakerConfig.WithdrawalAddress)
+	}
+
+	allowed := make(map[string]struct{}, len(addresses))
+	for _, a := range addresses {
+		addr, err := btcutil.DecodeAddress(a, &config.ActiveNetParams)
+		if err != nil {
+			return nil, fmt.Errorf("invalid signing policy address %s: %w", a, err)
+		}
+
+		script, err := txscript.PayToAddrScript(addr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid signing policy address %s: %w", a, err)
+		}
+
+		allowed[string(script)] = struct{}{}
+	}
+
+	return &signingPolicy{
+		cfg:     cfg,
+		wc:      wc,
+		net:     &config.ActiveNetParams,
+		allowed: allowed,
+	}, nil
+}
+
+// checkStakingTime checks staking time of new delegation
+func (p *signingPolicy) checkStakingTime(stakingTime uint16) error {
+	if p == nil {
+		return nil
+	}
+
+	if stakingTime < p.cfg.MinStakingTime {
+		return fmt.Errorf("%w: staking time %d is below %d", ErrSigningPolicyViolation, stakingTime, p.cfg.MinStakingTime)
+	}
+
+	if p.cfg.MaxStakingTime > 0 && stakingTime > p.cfg.MaxStakingTime {
+		return fmt.Errorf("%w: staking time %d is above %d", ErrSigningPolicyViolation, stakingTime, p.cfg.MaxStakingTime)
+	}
+
+	return nil
+}
+
+// check validates transaction funded by the wallet spending given previous
+// outputs. protocolScripts are scripts of staking, unbonding and slashing
+// outputs the transaction is expected to create. Fee rate is checked only for
+// fully signed transactions.
+func (p *signingPolicy) check(tx *wire.MsgTx, prevOuts []*wire.TxOut, protocolScripts ...[]byte) error {
+	return p.checkWithProtocolInputs(tx, prevOuts, 0, protocolScripts)
+}
+
+// checkProtocolSpend validates transaction whose first input spends staking or
+// unbonding output, like unbonding, withdrawal or stake expansion. Value of
+// the spent protocol output does not count towards max spend value, as it
+// only returns funds locked by the staker.
+func (p *signingPolicy) checkProtocolSpend(tx *wire.MsgTx, prevOuts []*wire.TxOut, protocolScripts ...[]byte) error {
+	return p.checkWithProtocolInputs(tx, prevOuts, 1, protocolScripts)
+}
+
+func (p *signingPolicy) checkWithProtocolInputs(
+	tx *wire.MsgTx,
+	prevOuts []*wire.TxOut,
+	protocolInputs int,
+	protocolScripts [][]byte,
+) error {
+	if p == nil {
+		return nil
+	}
+
+	if err := p.checkTx(tx, prevOuts, protocolInputs, protocolScripts); err != nil {
+		return fmt.Errorf("%w: transaction %s: %w", ErrSigningPolicyViolation, tx.TxHash(), err)
+	}
+
+	return nil
+}
+
+// checkTx validates transaction whose first protocolInputs inputs spend
+// staking or unbonding outputs and remaining inputs are funded by the wallet
+func (p *signingPolicy) checkTx(
+	tx *wire.MsgTx,
+	prevOuts []*wire.TxOut,
+	protocolInputs int,
+	protocolScripts [][]byte,
+) error {
+	if len(prevOuts) != len(tx.TxIn) {
+		return fmt.Errorf("got %d previous outputs for %d inputs", len(prevOuts), len(tx.TxIn))
+	}
+
+	if protocolInputs > len(prevOuts) {
+		return fmt.Errorf("got %d protocol inputs for %d inputs", protocolInputs, len(prevOuts))
+	}
+
+	for i, out := range tx.TxOut {
+		// OP_RETURN outputs carrying marker and reference commitment of
+		// staking transactions can't move funds
+		if out.Value == 0 && txscript.GetScriptClass(out.PkScript) == txscript.NullDataTy {
+			continue
+		}
+
+		if !p.isKnownScript(out.PkScript, protocolScripts) {
+			return fmt.Errorf("output %d pays to unknown script %x", i, out.PkScript)
+		}
+	}
+
+	var inputsValue, walletInputsValue, outputsValue int64
+	for i, out := range prevOuts {
+		inputsValue += out.Value
+		if i >= protocolInputs {
+			walletInputsValue += out.Value
+		}
+	}
+	for _, out := range tx.TxOut {
+		outputsValue += out.Value
+	}
+
+	if p.cfg.MaxSpendValue > 0 && walletInputsValue > int64(p.cfg.MaxSpendValue) {
+		return fmt.Errorf("spent wallet value %d exceeds limit %d", walletInputsValue, p.cfg.MaxSpendValue)
+	}
+
+	fee := inputsValue - outputsValue
+	if fee < 0 {
+		return fmt.Errorf("outputs value %d exceeds inputs value %d", outputsValue, inputsValue)
+	}
+
+	if p.cfg.MaxFee > 0 && fee > int64(p.cfg.MaxFee) {
+		return fmt.Errorf("fee %d exceeds limit %d", fee, p.cfg.MaxFee)
+	}
+
+	// size of not fully signed transaction is not known yet
+	if signed, _ := isTransacionFullySigned(tx); signed && p.cfg.MaxFeeRate > 0 {
+		vsize := mempool.GetTxVirtualSize(btcutil.NewTx(tx))
+		if fee > vsize*int64(p.cfg.MaxFeeRate) {
+			return fmt.Errorf("fee rate %d sat/vB exceeds limit %d", fee/vsize, p.cfg.MaxFeeRate)
+		}
+	}
+
+	// staker never uses time based locks
+	if tx.LockTime >= txscript.LockTimeThreshold {
+		return fmt.Errorf("time based lock time %d", tx.LockTime)
+	}
+
+	for i, in := range tx.TxIn {
+		if in.Sequence&wire.SequenceLockTimeDisabled != 0 || tx.Version < 2 {
+			continue
+		}
+
+		if in.Sequence&wire.SequenceLockTimeIsSeconds != 0 {
+			return fmt.Errorf("input %d has time based relative lock time", i)
+		}
+
+		blocks := in.Sequence & wire.SequenceLockTimeMask
+		if p.cfg.MaxStakingTime > 0 && blocks > uint32(p.cfg.MaxStakingTime) {
+			return fmt.Errorf("input %d relative lock time %d exceeds limit %d", i, blocks, p.cfg.MaxStakingTime)
+		}
+	}
+
+	return nil
+}
+
+// isKnownScript returns true for allowed and protocol scripts and scripts of
+// addresses controlled by the wallet
+func (p *signingPolicy) isKnownScript(script []byte, protocolScripts [][]byte) bool {
+	if _, ok := p.allowed[string(script)]; ok {
+		return true
+	}
+
+	for _, s := range protocolScripts {
+		if string(s) == string(script) {
+			return true
+		}
+	}
+
+	_, addrs, _, err := txscript.ExtractPkScriptAddrs(script, p.net)
+	if err != nil || len(addrs) != 1 {
+		return false
+	}
+
+	// wallet knows keys only of its own addresses
+	_, err = p.wc.AddressPublicKey(addrs[0])
+	return err == nil
+}
+
+// checkSigningPolicy checks transaction against signing policy fetching
+// outputs spent by the transaction from the wallet
+func (app *App) checkSigningPolicy(tx *wire.MsgTx, protocolScripts ...[]byte) error {
+	if app.policy == nil {
+		return nil
+	}
+
+	prevOuts, err := app.prevOutputs(tx)
+	if err != nil {
+		return fmt.Errorf("failed to check signing policy: %w", err)
+	}
+
+	return app.policy.check(tx, prevOuts, protocolScripts...)
+}
+
+// slashingScripts returns scripts of outputs of slashing transactions of the
+// staker: slashing output and change output timelocked to the staker
+func slashingScripts(
+	stakerPubKey *btcec.PublicKey,
+	slashingPkScript []byte,
+	unbondingTime uint16,
+	net *chaincfg.Params,
+) ([][]byte, error) {
+	changeInfo, err := staking.BuildRelativeTimelockTaprootScript(stakerPubKey, unbondingTime, net)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build slashing change script: %w", err)
+	}
+
+	return [][]byte{slashingPkScript, changeInfo.PkScript}, nil
+}
+
+// delegationStakingScript returns script of staking output of the delegation
+// rebuilt from delegation data and its params
+func (app *App) delegationStakingScript(del *btcstktypes.BTCDelegationResponse) ([]byte, error) {
+	stakerPubKey, err := del.BtcPk.ToBTCPK()
+	if err != nil {
+		return nil, fmt.Errorf("invalid staker public key: %w", err)
+	}
+
+	fpBtcPubkeys, err := convertFpBtcPkToBtcPk(del.FpBtcPkList)
+	if err != nil {
+		return nil, fmt.Errorf("error converting fpBtcPkList to btcPkList: %w", err)
+	}
+
+	params, err := app.babylonClient.ParamsByVersion(del.ParamsVersion)
+	if err != nil {
+		return nil, fmt.Errorf("error getting params version %d: %w", del.ParamsVersion, err)
+	}
+
+	stakingInfo, err := staking.BuildStakingInfo(
+		stakerPubKey,
+		fpBtcPubkeys,
+		params.CovenantPks,
+		params.CovenantQuruomThreshold,
+		uint16(del.StakingTime),
+		btcutil.Amount(del.TotalSat),
+		app.network,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build staking info: %w", err)
+	}
+
+	return stakingInfo.StakingOutput.PkScript, nil
+}
+
+// unbondingScript returns script of unbonding output of the given value
+func unbondingScript(
+	stakerPubKey *btcec.PublicKey,
+	fpBtcPubkeys []*btcec.PublicKey,
+	params *cl.BtcStakingParams,
+	unbondingTime uint16,
+	value btcutil.Amount,
+	net *chaincfg.Params,
+) ([]byte, error) {
+	unbondingInfo, err := staking.BuildUnbondingInfo(
+		stakerPubKey,
+		fpBtcPubkeys,
+		params.CovenantPks,
+		params.CovenantQuruomThreshold,
+		unbondingTime,
+		value,
+		net,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build unbonding info: %w", err)
+	}
+
+	return unbondingInfo.UnbondingOutput.PkScript, nil
+}
@@ -0,0 +1,173 @@
+package staker
+
+import (
+	"errors"
+	"testing"
+
+	scfg "github.com/babylonlabs-io/btc-staker/stakercfg"
+	"github.com/babylonlabs-io/btc-staker/testutil/mocks"
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func policyTestScript(t *testing.T) []byte {
+	key, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+
+	addr, err := btcutil.NewAddressTaproot(schnorr.SerializePubKey(key.PubKey()), &chaincfg.RegressionNetParams)
+	require.NoError(t, err)
+
+	script, err := txscript.PayToAddrScript(addr)
+	require.NoError(t, err)
+
+	return script
+}
+
+func TestSigningPolicyCheckTx(t *testing.T) {
+	t.Parallel()
+
+	allowedScript := policyTestScript(t)
+	protocolScript := policyTestScript(t)
+	unknownScript := policyTestScript(t)
+
+	opReturn, err := txscript.NullDataScript([]byte("marker"))
+	require.NoError(t, err)
+
+	newTx := func(outs ...*wire.TxOut) *wire.MsgTx {
+		tx := wire.NewMsgTx(2)
+		tx.AddTxIn(wire.NewTxIn(wire.NewOutPoint(&chainhash.Hash{1}, 0), nil, nil))
+		for _, out := range outs {
+			tx.AddTxOut(out)
+		}
+		return tx
+	}
+
+	tests := []struct {
+		name           string
+		tx             *wire.MsgTx
+		prevValue      int64
+		protocolInputs int
+		wantErr        string
+	}{
+		{
+			name:      "allowed and protocol outputs",
+			tx:        newTx(wire.NewTxOut(50_000, allowedScript), wire.NewTxOut(40_000, protocolScript)),
+			prevValue: 91_000,
+		},
+		{
+			name:      "unknown output",
+			tx:        newTx(wire.NewTxOut(90_000, unknownScript)),
+			prevValue: 91_000,
+			wantErr:   "unknown script",
+		},
+		{
+			name:      "zero value OP_RETURN output",
+			tx:        newTx(wire.NewTxOut(90_000, allowedScript), wire.NewTxOut(0, opReturn)),
+			prevValue: 91_000,
+		},
+		{
+			name:      "OP_RETURN output with value",
+			tx:        newTx(wire.NewTxOut(90_000, allowedScript), wire.NewTxOut(1, opReturn)),
+			prevValue: 91_000,
+			wantErr:   "unknown script",
+		},
+		{
+			name:      "fee above limit",
+			tx:        newTx(wire.NewTxOut(80_000, allowedScript)),
+			prevValue: 91_000,
+			wantErr:   "fee 11000 exceeds limit",
+		},
+		{
+			name:      "outputs above inputs",
+			tx:        newTx(wire.NewTxOut(92_000, allowedScript)),
+			prevValue: 91_000,
+			wantErr:   "exceeds inputs value",
+		},
+		{
+			name:      "wallet inputs above max spend value",
+			tx:        newTx(wire.NewTxOut(200_000, allowedScript)),
+			prevValue: 201_000,
+			wantErr:   "spent wallet value",
+		},
+		{
+			name:           "protocol spend above max spend value",
+			tx:             newTx(wire.NewTxOut(200_000, allowedScript)),
+			prevValue:      201_000,
+			protocolInputs: 1,
+		},
+		{
+			name: "time based lock time",
+			tx: func() *wire.MsgTx {
+				tx := newTx(wire.NewTxOut(90_000, allowedScript))
+				tx.LockTime = txscript.LockTimeThreshold
+				return tx
+			}(),
+			prevValue: 91_000,
+			wantErr:   "time based lock time",
+		},
+		{
+			name: "time based relative lock time",
+			tx: func() *wire.MsgTx {
+				tx := newTx(wire.NewTxOut(90_000, allowedScript))
+				tx.TxIn[0].Sequence = wire.SequenceLockTimeIsSeconds | 10
+				return tx
+			}(),
+			prevValue: 91_000,
+			wantErr:   "time based relative lock time",
+		},
+		{
+			name: "relative lock time above max staking time",
+			tx: func() *wire.MsgTx {
+				tx := newTx(wire.NewTxOut(90_000, allowedScript))
+				tx.TxIn[0].Sequence = 1001
+				return tx
+			}(),
+			prevValue: 91_000,
+			wantErr:   "relative lock time 1001 exceeds limit",
+		},
+		{
+			name: "relative lock time within max staking time",
+			tx: func() *wire.MsgTx {
+				tx := newTx(wire.NewTxOut(90_000, allowedScript))
+				tx.TxIn[0].Sequence = 1000
+				return tx
+			}(),
+			prevValue: 91_000,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			wc := mocks.NewMockWalletController(ctrl)
+			wc.EXPECT().AddressPublicKey(gomock.Any()).Return(nil, errors.New("address not in wallet")).AnyTimes()
+
+			p := &signingPolicy{
+				cfg: &scfg.SigningPolicyConfig{
+					Enabled:        true,
+					MaxFee:         10_000,
+					MaxSpendValue:  100_000,
+					MaxStakingTime: 1000,
+				},
+				wc:      wc,
+				net:     &chaincfg.RegressionNetParams,
+				allowed: map[string]struct{}{string(allowedScript): {}},
+			}
+
+			prevOuts := []*wire.TxOut{wire.NewTxOut(tc.prevValue, unknownScript)}
+			err := p.checkTx(tc.tx, prevOuts, tc.protocolInputs, [][]byte{protocolScript})
+			if tc.wantErr != "" {
+				require.ErrorContains(t, err, tc.wantErr)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
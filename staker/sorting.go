@@ -0,0 +1,200 @@
+package staker
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/babylonlabs-io/btc-staker/stakerdb"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+)
+
+// StakingTxSortKey is a key by which stored staking transactions can be sorted
+type StakingTxSortKey string
+
+const (
+	SortByIndex              StakingTxSortKey = "index"
+	SortByAmount             StakingTxSortKey = "amount"
+	SortByConfirmationHeight StakingTxSortKey = "confirmationHeight"
+	SortByTimeRemaining      StakingTxSortKey = "timeRemaining"
+)
+
+// SortDirection is a direction in which stored staking transactions are sorted
+type SortDirection string
+
+const (
+	SortAscending  SortDirection = "asc"
+	SortDescending SortDirection = "desc"
+)
+
+// ParseStakingTxSortKey parses sort key, empty string means sorting by index
+func ParseStakingTxSortKey(key string) (StakingTxSortKey, error) {
+	switch StakingTxSortKey(key) {
+	case "", SortByIndex:
+		return SortByIndex, nil
+	case SortByAmount, SortByConfirmationHeight, SortByTimeRemaining:
+		return StakingTxSortKey(key), nil
+	default:
+		return "", fmt.Errorf("unknown sort key %q, supported keys: %s,%s,%s,%s",
+			key, SortByIndex, SortByAmount, SortByConfirmationHeight, SortByTimeRemaining)
+	}
+}
+
+// ParseSortDirection parses sort direction, empty string means ascending order
+func ParseSortDirection(direction string) (SortDirection, error) {
+	switch SortDirection(direction) {
+	case "", SortAscending:
+		return SortAscending, nil
+	case SortDescending:
+		return SortDescending, nil
+	default:
+		return "", fmt.Errorf("unknown sort direction %q, supported directions: %s,%s",
+			direction, SortAscending, SortDescending)
+	}
+}
+
+// sortableTransaction is stored transaction with its precomputed sort value
+type sortableTransaction struct {
+	tx    stakerdb.StoredTransaction
+	value int64
+}
+
+// SortedStoredTransactions returns a page of stored transactions sorted by the given
+// key. Sorting by index in ascending order is served directly from db, every other
+// combination requires loading all transactions and computing sort values.
+// Sort values are computed from locally stored data and cached delegation
+// statuses, Babylon is not queried. Delegations whose status is not cached
+// yet sort as unconfirmed.
+// Returned transactions have only StakingTxHash set, not StakingTx.
+// If tenant is not empty, only transactions of this tenant are returned.
+func (app *App) SortedStoredTransactions(
+	sortBy StakingTxSortKey,
+	direction SortDirection,
+	limit, offset uint64,
+	tenant string,
+) (*stakerdb.StoredTransactionQueryResult, error) {
+	if sortBy == SortByIndex && direction == SortAscending && tenant == "" {
+		return app.StoredTransactions(limit, offset)
+	}
+
+	query := stakerdb.DefaultStoredTransactionQuery()
+	query.NumMaxTransactions = math.MaxUint64
+	// staking transactions are needed only for their staking output value
+	query.StakingTxHashOnly = sortBy != SortByAmount
+	query.Tenant = tenant
+
+	result, err := app.txTracker.QueryStoredTransactions(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get stored transactions: %w", err)
+	}
+	transactions := result.Transactions
+
+	var amounts map[chainhash.Hash]btcutil.Amount
+	if sortBy == SortByAmount {
+		amounts, err = app.stakingAmounts()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	sortable := make([]sortableTransaction, 0, len(transactions))
+	for _, tx := range transactions {
+		sortable = append(sortable, sortableTransaction{
+			tx:    tx,
+			value: app.sortValue(&tx, sortBy, amounts),
+		})
+	}
+
+	sort.SliceStable(sortable, func(i, j int) bool {
+		if sortable[i].value == sortable[j].value {
+			// ties are always resolved by index to keep pages stable
+			return sortable[i].tx.StoredTransactionIdx < sortable[j].tx.StoredTransactionIdx
+		}
+
+		if direction == SortDescending {
+			return sortable[i].value > sortable[j].value
+		}
+		return sortable[i].value < sortable[j].value
+	})
+
+	total := uint64(len(sortable))
+	start := min(offset, total)
+	end := min(start+limit, total)
+
+	page := make([]stakerdb.StoredTransaction, 0, end-start)
+	for _, st := range sortable[start:end] {
+		st.tx.StakingTx = nil
+		page = append(page, st.tx)
+	}
+
+	return &stakerdb.StoredTransactionQueryResult{
+		Transactions: page,
+		Total:        total,
+	}, nil
+}
+
+// stakingAmounts returns staking amounts recorded for delegations created by
+// the staker
+func (app *App) stakingAmounts() (map[chainhash.Hash]btcutil.Amount, error) {
+	delegations, err := app.txTracker.ListDelegationFinalityProviders()
+	if err != nil {
+		return nil, err
+	}
+
+	amounts := make(map[chainhash.Hash]btcutil.Amount, len(delegations))
+	for _, d := range delegations {
+		amounts[d.StakingTxHash] = d.StakingAmount
+	}
+
+	return amounts, nil
+}
+
+// sortValue computes value of the stored transaction for the given sort key
+func (app *App) sortValue(
+	tx *stakerdb.StoredTransaction,
+	sortBy StakingTxSortKey,
+	amounts map[chainhash.Hash]btcutil.Amount,
+) int64 {
+	switch sortBy {
+	case SortByIndex:
+		return int64(tx.StoredTransactionIdx)
+	case SortByAmount:
+		if amount, ok := amounts[tx.StakingTxHash]; ok {
+			return int64(amount)
+		}
+		// amount is not recorded for delegations tracked before amounts were
+		// recorded or failed ones, staking output of transactions built by
+		// the staker is the first one
+		if len(tx.StakingTx.TxOut) == 0 {
+			return 0
+		}
+		return tx.StakingTx.TxOut[0].Value
+	}
+
+	status, ok := app.statuses.get(tx.StakingTxHash)
+	if !ok {
+		return 0
+	}
+
+	confirmationHeight := status.ConfirmationHeight
+
+	if sortBy == SortByConfirmationHeight {
+		return int64(confirmationHeight)
+	}
+
+	// SortByTimeRemaining, unconfirmed transactions have whole staking time
+	// remaining
+	stakingTime := int64(status.Delegation.BtcDelegation.StakingTime)
+	if confirmationHeight == 0 {
+		return stakingTime
+	}
+
+	nextBlockHeight := int64(app.currentBestBlockHeight.Load()) + 1
+	remaining := int64(confirmationHeight) + stakingTime - nextBlockHeight
+	if remaining < 0 {
+		return 0
+	}
+
+	return remaining
+}
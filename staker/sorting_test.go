@@ -0,0 +1,113 @@
+package staker
+
+import (
+	"testing"
+
+	btcstypes "github.com/babylonlabs-io/babylon/v4/x/btcstaking/types"
+	"github.com/babylonlabs-io/btc-staker/stakercfg"
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+)
+
+// newSortingTestApp returns app without babylon client, so sorting fails
+// the test if it queries Babylon
+func newSortingTestApp(t *testing.T, values ...int64) (*App, []chainhash.Hash) {
+	cfg := stakercfg.DefaultConfig()
+	app := &App{
+		config:    &cfg,
+		logger:    logrus.New(),
+		txTracker: newArchiveTestStore(t),
+		statuses:  newDelegationStatusCache(),
+	}
+
+	hashes := make([]chainhash.Hash, 0, len(values))
+	for _, value := range values {
+		tx := genReplicaTestTransaction(t, value)
+		require.NoError(t, app.txTracker.AddTransactionSentToBabylon(tx.StakingTx, tx.StakerAddress))
+		hashes = append(hashes, tx.StakingTx.TxHash())
+	}
+
+	return app, hashes
+}
+
+func sortedHashes(
+	t *testing.T,
+	app *App,
+	sortBy StakingTxSortKey,
+	direction SortDirection,
+	limit, offset uint64,
+) ([]chainhash.Hash, uint64) {
+	result, err := app.SortedStoredTransactions(sortBy, direction, limit, offset, "")
+	require.NoError(t, err)
+
+	hashes := make([]chainhash.Hash, 0, len(result.Transactions))
+	for _, tx := range result.Transactions {
+		require.Nil(t, tx.StakingTx)
+		hashes = append(hashes, tx.StakingTxHash)
+	}
+
+	return hashes, result.Total
+}
+
+func TestSortByStakingAmount(t *testing.T) {
+	t.Parallel()
+
+	app, h := newSortingTestApp(t, 30_000, 10_000, 20_000, 10_000, 5_000)
+
+	// recorded staking amount is used instead of the first output
+	fpKey, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+	require.NoError(t, app.txTracker.SetDelegationFinalityProviders(
+		&h[4], [][]byte{schnorr.SerializePubKey(fpKey.PubKey())}, btcutil.Amount(50_000),
+	))
+
+	hashes, total := sortedHashes(t, app, SortByAmount, SortAscending, 10, 0)
+	require.Equal(t, uint64(5), total)
+	// ties are resolved by index in both directions
+	require.Equal(t, []chainhash.Hash{h[1], h[3], h[2], h[0], h[4]}, hashes)
+
+	hashes, _ = sortedHashes(t, app, SortByAmount, SortDescending, 10, 0)
+	require.Equal(t, []chainhash.Hash{h[4], h[0], h[2], h[1], h[3]}, hashes)
+
+	hashes, total = sortedHashes(t, app, SortByAmount, SortAscending, 2, 1)
+	require.Equal(t, uint64(5), total)
+	require.Equal(t, []chainhash.Hash{h[3], h[2]}, hashes)
+
+	hashes, total = sortedHashes(t, app, SortByAmount, SortAscending, 2, 10)
+	require.Equal(t, uint64(5), total)
+	require.Empty(t, hashes)
+}
+
+func TestSortByCachedStatus(t *testing.T) {
+	t.Parallel()
+
+	app, h := newSortingTestApp(t, 10_000, 10_000, 10_000, 10_000)
+	app.currentBestBlockHeight.Store(199)
+
+	cache := func(hash chainhash.Hash, confirmationHeight uint32, stakingTime uint32) {
+		app.statuses.set(hash, &DelegationStatus{
+			Delegation: &btcstypes.QueryBTCDelegationResponse{
+				BtcDelegation: &btcstypes.BTCDelegationResponse{StakingTime: stakingTime},
+			},
+			ConfirmationHeight: confirmationHeight,
+		})
+	}
+	// remaining: 100+150-200=50, 180+100-200=80, unconfirmed has whole
+	// staking time, status of the last one is not cached
+	cache(h[0], 100, 150)
+	cache(h[1], 180, 100)
+	cache(h[2], 0, 60)
+
+	hashes, _ := sortedHashes(t, app, SortByConfirmationHeight, SortDescending, 10, 0)
+	require.Equal(t, []chainhash.Hash{h[1], h[0], h[2], h[3]}, hashes)
+
+	hashes, _ = sortedHashes(t, app, SortByTimeRemaining, SortAscending, 10, 0)
+	require.Equal(t, []chainhash.Hash{h[3], h[0], h[2], h[1]}, hashes)
+
+	hashes, _ = sortedHashes(t, app, SortByIndex, SortDescending, 3, 0)
+	require.Equal(t, []chainhash.Hash{h[3], h[2], h[1]}, hashes)
+}
@@ -0,0 +1,63 @@
+package staker
+
+import (
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/sirupsen/logrus"
+)
+
+// recordSpendableHeight stores btc height at which output of unbonding
+// transaction confirmed at the given height becomes spendable. Output is
+// locked for unbondingTime blocks, so withdrawal can be included in the block
+// at height confirmationHeight + unbondingTime. Failure is only logged, as the
+// height is informational.
+func (app *App) recordSpendableHeight(stakingTxHash *chainhash.Hash, confirmationHeight uint32, unbondingTime uint16) {
+	if unbondingTime == 0 {
+		return
+	}
+
+	height := confirmationHeight + uint32(unbondingTime)
+	updated, err := app.txTracker.SetTransactionSpendableHeight(stakingTxHash, height)
+	if err != nil {
+		app.logger.WithFields(logrus.Fields{
+			"stakingTxHash": stakingTxHash,
+			"err":           err,
+		}).Error("Failed to record spendable height of unbonded delegation")
+		return
+	}
+
+	if !updated {
+		return
+	}
+
+	app.logger.WithFields(logrus.Fields{
+		"stakingTxHash":   stakingTxHash,
+		"spendableHeight": height,
+	}).Info("Unbonding transaction confirmed, funds become spendable at height")
+}
+
+// SpendableHeight returns btc height at which unbonded funds of the delegation
+// become spendable. Returns false if unbonding transaction of the delegation
+// was not confirmed yet.
+func (app *App) SpendableHeight(stakingTxHash *chainhash.Hash) (uint32, bool, error) {
+	return app.txTracker.GetTransactionSpendableHeight(stakingTxHash)
+}
+
+// notifySpendableStakes records stake_spendable change for every unbonded
+// delegation whose withdrawal can be included in the next block
+func (app *App) notifySpendableStakes() error {
+	nextBlockHeight := app.currentBestBlockHeight.Load() + 1
+
+	reached, err := app.txTracker.MarkSpendableHeightsReached(nextBlockHeight)
+	if err != nil {
+		return err
+	}
+
+	for _, txHash := range reached {
+		app.logger.WithFields(logrus.Fields{
+			"stakingTxHash": txHash,
+			"btcHeight":     nextBlockHeight,
+		}).Info("Unbonded funds are spendable, delegation can be withdrawn")
+	}
+
+	return nil
+}
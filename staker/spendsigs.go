@@ -0,0 +1,254 @@
+package staker
+
+import (
+	"bytes"
+	"fmt"
+
+	staking "github.com/babylonlabs-io/babylon/v4/btcstaking"
+	bbn "github.com/babylonlabs-io/babylon/v4/types"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/sirupsen/logrus"
+)
+
+// SpendSigKind is the kind of staking spend transaction the staker signs
+type SpendSigKind string
+
+const (
+	// SpendSigUnbonding is the unbonding transaction spending staking output
+	SpendSigUnbonding SpendSigKind = "unbonding"
+	// SpendSigSlashing is the slashing transaction spending staking output
+	SpendSigSlashing SpendSigKind = "slashing"
+	// SpendSigUnbondingSlashing is the slashing transaction spending unbonding output
+	SpendSigUnbondingSlashing SpendSigKind = "unbonding_slashing"
+)
+
+// ParseSpendSigKind parses spend signature kind
+func ParseSpendSigKind(s string) (SpendSigKind, error) {
+	switch k := SpendSigKind(s); k {
+	case SpendSigUnbonding, SpendSigSlashing, SpendSigUnbondingSlashing:
+		return k, nil
+	default:
+		return "", fmt.Errorf("unknown spend signature kind: %s. Allowed: %s, %s, %s",
+			s, SpendSigUnbonding, SpendSigSlashing, SpendSigUnbondingSlashing)
+	}
+}
+
+// SignSpendTx produces staker Schnorr signature over the given unbonding or
+// slashing transaction of the delegation identified by stakingTxHash.
+// To prevent the staker key from signing arbitrary spends, the transaction
+// must be exactly the one registered on Babylon for the delegation.
+func (app *App) SignSpendTx(
+	stakingTxHash *chainhash.Hash,
+	kind SpendSigKind,
+	tx *wire.MsgTx,
+) (*schnorr.Signature, error) {
+	var sig *schnorr.Signature
+	err := app.requests.run(func() error {
+		var err error
+		sig, err = app.signSpendTx(stakingTxHash, kind, tx)
+		return err
+	})
+	return sig, err
+}
+
+func (app *App) signSpendTx(
+	stakingTxHash *chainhash.Hash,
+	kind SpendSigKind,
+	tx *wire.MsgTx,
+) (*schnorr.Signature, error) {
+	storedTx, err := app.txTracker.GetTransaction(stakingTxHash)
+	if err != nil {
+		return nil, fmt.Errorf("cannot sign %s transaction: %w", kind, err)
+	}
+
+	stakerAddress, err := btcutil.DecodeAddress(storedTx.StakerAddress, app.network)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding staker address: %s. Err: %w", storedTx.StakerAddress, err)
+	}
+
+	di, err := app.babylonClient.QueryBTCDelegation(stakingTxHash)
+	if err != nil {
+		return nil, fmt.Errorf("error getting delegation info: %w", err)
+	}
+	del := di.BtcDelegation
+
+	var registeredTxHex string
+	switch kind {
+	case SpendSigSlashing:
+		registeredTxHex = del.SlashingTxHex
+	case SpendSigUnbonding, SpendSigUnbondingSlashing:
+		if del.UndelegationResponse == nil {
+			return nil, fmt.Errorf("delegation %s has no unbonding data", stakingTxHash)
+		}
+		if kind == SpendSigUnbonding {
+			registeredTxHex = del.UndelegationResponse.UnbondingTxHex
+		} else {
+			registeredTxHex = del.UndelegationResponse.SlashingTxHex
+		}
+	default:
+		return nil, fmt.Errorf("unknown spend signature kind: %s", kind)
+	}
+
+	registeredTx, _, err := bbn.NewBTCTxFromHex(registeredTxHex)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode %s transaction registered on babylon: %w", kind, err)
+	}
+
+	if tx.TxHash() != registeredTx.TxHash() {
+		return nil, fmt.Errorf("%s transaction %s does not match transaction %s registered on babylon",
+			kind, tx.TxHash(), registeredTx.TxHash())
+	}
+
+	if len(tx.TxIn) != 1 {
+		return nil, fmt.Errorf("%s transaction must have exactly one input, got %d", kind, len(tx.TxIn))
+	}
+
+	if int(del.StakingOutputIdx) >= len(storedTx.StakingTx.TxOut) {
+		return nil, fmt.Errorf("staking output index %d out of range", del.StakingOutputIdx)
+	}
+
+	stakerPubKey, err := app.wc.AddressPublicKey(stakerAddress)
+	if err != nil {
+		return nil, fmt.Errorf("error getting staker public key: %w", err)
+	}
+
+	fpBtcPubkeys, err := convertFpBtcPkToBtcPk(del.FpBtcPkList)
+	if err != nil {
+		return nil, fmt.Errorf("error converting fpBtcPkList to btcPkList: %w", err)
+	}
+
+	params, err := app.babylonClient.ParamsByVersion(del.ParamsVersion)
+	if err != nil {
+		return nil, fmt.Errorf("error getting params version %d: %w", del.ParamsVersion, err)
+	}
+
+	stakingOutput := storedTx.StakingTx.TxOut[del.StakingOutputIdx]
+	stakingInfo, err := staking.BuildStakingInfo(
+		stakerPubKey,
+		fpBtcPubkeys,
+		params.CovenantPks,
+		params.CovenantQuruomThreshold,
+		uint16(del.StakingTime),
+		btcutil.Amount(stakingOutput.Value),
+		app.network,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build staking info: %w", err)
+	}
+
+	var (
+		fundingOutput    *wire.TxOut
+		expectedOutPoint wire.OutPoint
+		spendInfo        *staking.SpendInfo
+	)
+
+	switch kind {
+	case SpendSigUnbonding, SpendSigSlashing:
+		if !bytes.Equal(stakingInfo.StakingOutput.PkScript, stakingOutput.PkScript) {
+			return nil, fmt.Errorf("staking output script does not match delegation data")
+		}
+
+		fundingOutput = stakingOutput
+		expectedOutPoint = *wire.NewOutPoint(stakingTxHash, del.StakingOutputIdx)
+
+		if kind == SpendSigUnbonding {
+			spendInfo, err = stakingInfo.UnbondingPathSpendInfo()
+		} else {
+			spendInfo, err = stakingInfo.SlashingPathSpendInfo()
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to build %s spend info: %w", kind, err)
+		}
+	case SpendSigUnbondingSlashing:
+		unbondingTx, _, err := bbn.NewBTCTxFromHex(del.UndelegationResponse.UnbondingTxHex)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode unbonding transaction registered on babylon: %w", err)
+		}
+
+		if len(unbondingTx.TxOut) == 0 {
+			return nil, fmt.Errorf("unbonding transaction has no outputs")
+		}
+
+		unbondingInfo, err := staking.BuildUnbondingInfo(
+			stakerPubKey,
+			fpBtcPubkeys,
+			params.CovenantPks,
+			params.CovenantQuruomThreshold,
+			uint16(del.UnbondingTime),
+			btcutil.Amount(unbondingTx.TxOut[0].Value),
+			app.network,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to build unbonding info: %w", err)
+		}
+
+		if !bytes.Equal(unbondingInfo.UnbondingOutput.PkScript, unbondingTx.TxOut[0].PkScript) {
+			return nil, fmt.Errorf("unbonding output script does not match delegation data")
+		}
+
+		unbondingTxHash := unbondingTx.TxHash()
+		fundingOutput = unbondingTx.TxOut[0]
+		expectedOutPoint = *wire.NewOutPoint(&unbondingTxHash, 0)
+		spendInfo, err = unbondingInfo.SlashingPathSpendInfo()
+		if err != nil {
+			return nil, fmt.Errorf("failed to build slashing path info: %w", err)
+		}
+	}
+
+	if tx.TxIn[0].PreviousOutPoint != expectedOutPoint {
+		return nil, fmt.Errorf("%s transaction spends %s, expected %s",
+			kind, tx.TxIn[0].PreviousOutPoint, expectedOutPoint)
+	}
+
+	var protocolScripts [][]byte
+	if kind == SpendSigUnbonding {
+		if len(tx.TxOut) == 0 {
+			return nil, fmt.Errorf("unbonding transaction has no outputs")
+		}
+
+		script, err := unbondingScript(
+			stakerPubKey,
+			fpBtcPubkeys,
+			params,
+			uint16(del.UnbondingTime),
+			btcutil.Amount(tx.TxOut[0].Value),
+			app.network,
+		)
+		if err != nil {
+			return nil, err
+		}
+		protocolScripts = [][]byte{script}
+	} else {
+		protocolScripts, err = slashingScripts(stakerPubKey, params.SlashingPkScript, uint16(del.UnbondingTime), app.network)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	res, err := app.signTaprootScriptSpendUsingWallet(
+		tx,
+		fundingOutput,
+		stakerAddress,
+		&spendInfo.RevealedLeaf,
+		&spendInfo.ControlBlock,
+		protocolScripts...,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign %s transaction: %w", kind, err)
+	}
+
+	if res.Signature == nil {
+		return nil, fmt.Errorf("failed to receive %s transaction signature", kind)
+	}
+
+	app.logger.WithFields(logrus.Fields{
+		"stakingTxHash": stakingTxHash,
+		"kind":          kind,
+		"txHash":        tx.TxHash(),
+	}).Info("Signed staking spend transaction")
+
+	return res.Signature, nil
+}
@@ -0,0 +1,191 @@
+package staker
+
+import (
+	"encoding/hex"
+	"testing"
+
+	staking "github.com/babylonlabs-io/babylon/v4/btcstaking"
+	bbn "github.com/babylonlabs-io/babylon/v4/types"
+	btcstypes "github.com/babylonlabs-io/babylon/v4/x/btcstaking/types"
+	cl "github.com/babylonlabs-io/btc-staker/babylonclient"
+	"github.com/babylonlabs-io/btc-staker/stakercfg"
+	"github.com/babylonlabs-io/btc-staker/testutil/mocks"
+	"github.com/babylonlabs-io/btc-staker/utils"
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+const testStakingTime = 1000
+
+// spendSigsTestDelegation is tracked delegation whose staking output matches
+// the delegation registered on babylon
+type spendSigsTestDelegation struct {
+	app           *App
+	bc            *mocks.MockBabylonClient
+	stakingTxHash chainhash.Hash
+	resp          *btcstypes.QueryBTCDelegationResponse
+}
+
+func newSpendSigsTestDelegation(t *testing.T) *spendSigsTestDelegation {
+	ctrl := gomock.NewController(t)
+	bc := mocks.NewMockBabylonClient(ctrl)
+	wc := mocks.NewMockWalletController(ctrl)
+
+	cfg := stakercfg.DefaultConfig()
+	store := newArchiveTestStore(t)
+	app := &App{
+		config:        &cfg,
+		logger:        logrus.New(),
+		network:       &chaincfg.RegressionNetParams,
+		txTracker:     store,
+		babylonClient: bc,
+		wc:            wc,
+	}
+
+	newKey := func() *btcec.PublicKey {
+		key, err := btcec.NewPrivateKey()
+		require.NoError(t, err)
+		return key.PubKey()
+	}
+
+	stakerKey, fpKey := newKey(), newKey()
+	params := &cl.BtcStakingParams{
+		CovenantPks:             []*btcec.PublicKey{newKey(), newKey(), newKey()},
+		CovenantQuruomThreshold: 2,
+	}
+
+	stakerAddr, err := btcutil.NewAddressTaproot(schnorr.SerializePubKey(stakerKey), app.network)
+	require.NoError(t, err)
+
+	stakingInfo, err := staking.BuildStakingInfo(
+		stakerKey,
+		[]*btcec.PublicKey{fpKey},
+		params.CovenantPks,
+		params.CovenantQuruomThreshold,
+		testStakingTime,
+		100_000,
+		app.network,
+	)
+	require.NoError(t, err)
+
+	stakingTx := wire.NewMsgTx(2)
+	stakingTx.AddTxIn(wire.NewTxIn(wire.NewOutPoint(&chainhash.Hash{1}, 0), nil, nil))
+	stakingTx.AddTxOut(stakingInfo.StakingOutput)
+	require.NoError(t, store.AddTransactionSentToBabylon(stakingTx, stakerAddr))
+
+	wc.EXPECT().AddressPublicKey(gomock.Any()).Return(stakerKey, nil).AnyTimes()
+	bc.EXPECT().ParamsByVersion(gomock.Any()).Return(params, nil).AnyTimes()
+
+	return &spendSigsTestDelegation{
+		app:           app,
+		bc:            bc,
+		stakingTxHash: stakingTx.TxHash(),
+		resp: &btcstypes.QueryBTCDelegationResponse{
+			BtcDelegation: &btcstypes.BTCDelegationResponse{
+				StakingOutputIdx: 0,
+				StakingTime:      testStakingTime,
+				FpBtcPkList:      []bbn.BIP340PubKey{*bbn.NewBIP340PubKeyFromBTCPK(fpKey)},
+			},
+		},
+	}
+}
+
+// spendTx returns transaction spending given outpoint
+func spendTx(outpoints ...wire.OutPoint) *wire.MsgTx {
+	tx := wire.NewMsgTx(2)
+	for i := range outpoints {
+		tx.AddTxIn(wire.NewTxIn(&outpoints[i], nil, nil))
+	}
+	tx.AddTxOut(wire.NewTxOut(90_000, []byte{0x51}))
+	return tx
+}
+
+func txHex(t *testing.T, tx *wire.MsgTx) string {
+	serialized, err := utils.SerializeBtcTransaction(tx)
+	require.NoError(t, err)
+	return hex.EncodeToString(serialized)
+}
+
+func TestSignSpendTxRequiresRegisteredTx(t *testing.T) {
+	t.Parallel()
+
+	d := newSpendSigsTestDelegation(t)
+	stakingOutPoint := *wire.NewOutPoint(&d.stakingTxHash, 0)
+	registered := spendTx(stakingOutPoint)
+	d.resp.BtcDelegation.SlashingTxHex = txHex(t, registered)
+	d.bc.EXPECT().QueryBTCDelegation(&d.stakingTxHash).Return(d.resp, nil).AnyTimes()
+
+	// other transaction spending the same output is not signed
+	other := spendTx(stakingOutPoint)
+	other.TxOut[0].Value--
+	_, err := d.app.signSpendTx(&d.stakingTxHash, SpendSigSlashing, other)
+	require.ErrorContains(t, err, "does not match transaction")
+
+	// registered slashing transaction is not signed as unbonding one
+	_, err = d.app.signSpendTx(&d.stakingTxHash, SpendSigUnbonding, registered)
+	require.ErrorContains(t, err, "has no unbonding data")
+
+	d.resp.BtcDelegation.UndelegationResponse = &btcstypes.BTCUndelegationResponse{
+		UnbondingTxHex: txHex(t, spendTx(stakingOutPoint, *wire.NewOutPoint(&chainhash.Hash{2}, 0))),
+	}
+	_, err = d.app.signSpendTx(&d.stakingTxHash, SpendSigUnbonding, registered)
+	require.ErrorContains(t, err, "does not match transaction")
+
+	_, err = d.app.signSpendTx(&d.stakingTxHash, SpendSigKind("withdrawal"), registered)
+	require.ErrorContains(t, err, "unknown spend signature kind")
+}
+
+func TestSignSpendTxValidatesSpentOutpoint(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name            string
+		outpoints       func(stakingTxHash *chainhash.Hash) []wire.OutPoint
+		wantErrContains string
+	}{
+		{
+			name: "other transaction",
+			outpoints: func(_ *chainhash.Hash) []wire.OutPoint {
+				return []wire.OutPoint{*wire.NewOutPoint(&chainhash.Hash{3}, 0)}
+			},
+			wantErrContains: "slashing transaction spends",
+		},
+		{
+			name: "other output of staking transaction",
+			outpoints: func(stakingTxHash *chainhash.Hash) []wire.OutPoint {
+				return []wire.OutPoint{*wire.NewOutPoint(stakingTxHash, 1)}
+			},
+			wantErrContains: "slashing transaction spends",
+		},
+		{
+			name: "staking output and other input",
+			outpoints: func(stakingTxHash *chainhash.Hash) []wire.OutPoint {
+				return []wire.OutPoint{*wire.NewOutPoint(stakingTxHash, 0), *wire.NewOutPoint(&chainhash.Hash{3}, 0)}
+			},
+			wantErrContains: "must have exactly one input",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			d := newSpendSigsTestDelegation(t)
+			// even transaction registered on babylon is not signed if it
+			// does not spend the staking output
+			registered := spendTx(tc.outpoints(&d.stakingTxHash)...)
+			d.resp.BtcDelegation.SlashingTxHex = txHex(t, registered)
+			d.bc.EXPECT().QueryBTCDelegation(&d.stakingTxHash).Return(d.resp, nil)
+
+			_, err := d.app.signSpendTx(&d.stakingTxHash, SpendSigSlashing, registered)
+			require.ErrorContains(t, err, tc.wantErrContains)
+		})
+	}
+}
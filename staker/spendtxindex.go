@@ -0,0 +1,35 @@
+package staker
+
+import (
+	"github.com/babylonlabs-io/btc-staker/stakerdb"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/sirupsen/logrus"
+)
+
+// indexSpendTx records hash of unbonding or withdrawal transaction of the
+// delegation, so that the delegation can be found by it
+func (app *App) indexSpendTx(stakingTxHash *chainhash.Hash, kind stakerdb.WatchedTxKind, txHash *chainhash.Hash) {
+	if err := app.txTracker.IndexSpendTransaction(stakingTxHash, kind, txHash); err != nil {
+		app.logger.WithFields(logrus.Fields{
+			"stakingTxHash": stakingTxHash,
+			"kind":          kind,
+			"txHash":        txHash,
+			"err":           err,
+		}).Error("Failed to index spend transaction of delegation")
+	}
+}
+
+// FindDelegationByTx returns tracked delegation to which transaction with
+// given hash belongs. Transaction is either staking transaction of the
+// delegation, or its unbonding or withdrawal transaction. Returns
+// stakerdb.ErrTransactionNotFound if transaction is not known.
+func (app *App) FindDelegationByTx(txHash *chainhash.Hash) (*stakerdb.SpendTxDelegation, error) {
+	if _, err := app.txTracker.GetTransaction(txHash); err == nil {
+		return &stakerdb.SpendTxDelegation{
+			StakingTxHash: *txHash,
+			Kind:          stakerdb.WatchedStakingTx,
+		}, nil
+	}
+
+	return app.txTracker.FindDelegationBySpendTx(txHash)
+}
@@ -0,0 +1,337 @@
+package staker
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	cl "github.com/babylonlabs-io/btc-staker/babylonclient"
+	"github.com/babylonlabs-io/btc-staker/stakerdb"
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
+	"github.com/sirupsen/logrus"
+)
+
+// ErrStakingGateClosed is returned when Babylon does not accept new
+// delegations
+var ErrStakingGateClosed = errors.New("babylon does not accept new delegations")
+
+// checkStakingGate checks that Babylon accepts new delegations. Babylon params
+// do not define staking cap, so the only gate is the allow list, which blocks
+// new delegations until its expiration height.
+func (app *App) checkStakingGate(params *cl.StakingParams) error {
+	if params.AllowListExpirationHeight == 0 {
+		return nil
+	}
+
+	latestBlockHeight, err := app.babylonClient.GetLatestBlockHeight()
+	if err != nil {
+		return fmt.Errorf("failed to get latest block height: %w", err)
+	}
+
+	// we add +1 to account for comet bft lazy execution
+	if latestBlockHeight <= params.AllowListExpirationHeight+1 {
+		return fmt.Errorf("%w: allow list is enabled. Latest block height %d is before allow list expiration height %d",
+			ErrStakingGateClosed, latestBlockHeight, params.AllowListExpirationHeight)
+	}
+
+	return nil
+}
+
+// StakingGateStatus returns whether Babylon currently accepts new delegations
+// and the reason if it does not
+func (app *App) StakingGateStatus() (bool, string, error) {
+	params, err := app.babylonClient.Params()
+	if err != nil {
+		return false, "", fmt.Errorf("failed to get params: %w", err)
+	}
+
+	err = app.checkStakingGate(params)
+	switch {
+	case err == nil:
+		return true, "", nil
+	case errors.Is(err, ErrStakingGateClosed):
+		return false, err.Error(), nil
+	default:
+		return false, "", err
+	}
+}
+
+// StakeQueueEnabled returns true if stake requests are queued while Babylon
+// does not accept new delegations
+func (app *App) StakeQueueEnabled() bool {
+	return app.config.StakerConfig.QueueStakesWhenClosed
+}
+
+// QueueStake persists stake request, which is submitted once Babylon accepts
+// new delegations and no maintenance window is active. Created delegation is labeled with given labels and its
+// staking transaction commits to non empty reference. Fee selection is
+// resolved when the request is submitted.
+func (app *App) QueueStake(
+	stakerAddress btcutil.Address,
+	stakingAmount btcutil.Amount,
+	fpPks []*btcec.PublicKey,
+	stakingTimeBlocks uint16,
+	tenant string,
+	maxFeeRate uint64,
+	labels map[string]string,
+	reference string,
+	fee FeeSelection,
+) (uint64, error) {
+	if !app.StakeQueueEnabled() && !app.MaintenanceWindowsEnabled() {
+		return 0, fmt.Errorf("stake queue is disabled")
+	}
+
+	if tenant != "" {
+		if err := stakerdb.ValidateTenant(tenant); err != nil {
+			return 0, fmt.Errorf("invalid tenant: %w", err)
+		}
+	}
+
+	if err := app.validateReference(reference); err != nil {
+		return 0, err
+	}
+
+	if err := fee.Validate(); err != nil {
+		return 0, err
+	}
+
+	if len(fpPks) == 0 {
+		return 0, fmt.Errorf("no finality providers public keys provided")
+	}
+
+	fps := make([]string, len(fpPks))
+	for i, pk := range fpPks {
+		fps[i] = EncodeSchnorrPkToHexString(pk)
+	}
+
+	id, err := app.txTracker.QueueStake(&stakerdb.QueuedStake{
+		StakerAddress:     stakerAddress.EncodeAddress(),
+		Amount:            stakingAmount,
+		FinalityProviders: fps,
+		StakingTime:       stakingTimeBlocks,
+		MaxFeeRate:        maxFeeRate,
+		Tenant:            tenant,
+		Labels:            labels,
+		Reference:         reference,
+		FeeRate:           fee.FeeRate,
+		TargetConf:        fee.TargetConf,
+		QueuedAt:          time.Now(),
+	}, app.config.StakerConfig.MaxQueuedStakes)
+	if err != nil {
+		return 0, err
+	}
+
+	app.logger.WithFields(logrus.Fields{
+		"queuedStakeID": id,
+		"stakerAddress": stakerAddress,
+		"amount":        stakingAmount,
+	}).Info("Stake request queued")
+
+	return id, nil
+}
+
+// QueueStakeFromTemplate persists stake request using parameters of the
+// template. Template is resolved at queue time, so later changes of the
+// template do not affect queued requests.
+func (app *App) QueueStakeFromTemplate(
+	name string,
+	stakerAddress btcutil.Address,
+	stakingAmount btcutil.Amount,
+	reference string,
+	fee FeeSelection,
+) (uint64, error) {
+	template, stakerAddress, fpPks, err := app.resolveTemplate(name, stakerAddress)
+	if err != nil {
+		return 0, err
+	}
+
+	return app.QueueStake(
+		stakerAddress,
+		stakingAmount,
+		fpPks,
+		template.StakingTime,
+		template.Tenant,
+		template.MaxFeeRate,
+		template.Labels,
+		reference,
+		fee,
+	)
+}
+
+// QueuedStakes returns stake requests waiting for Babylon to accept new
+// delegations
+func (app *App) QueuedStakes() ([]stakerdb.QueuedStake, error) {
+	return app.txTracker.ListQueuedStakes()
+}
+
+// CancelQueuedStake removes stake request from the queue
+func (app *App) CancelQueuedStake(id uint64) error {
+	return app.txTracker.CancelQueuedStake(id)
+}
+
+// handleStakeQueue periodically checks whether Babylon accepts new
+// delegations, records changes of the gate and submits queued stake requests
+// once it is open
+func (app *App) handleStakeQueue() {
+	ticker := time.NewTicker(app.config.StakerConfig.StakeQueueCheckInterval)
+	defer ticker.Stop()
+
+	// assume gate is open at start, so that closed gate is recorded right away
+	gateOpen := true
+
+	for {
+		gateOpen = app.processStakeQueue(gateOpen)
+
+		select {
+		case <-ticker.C:
+		case <-app.quit:
+			return
+		}
+	}
+}
+
+// processStakeQueue checks the gate and submits queued stakes if it is open.
+// It returns the observed gate state.
+func (app *App) processStakeQueue(wasOpen bool) bool {
+	open, reason, err := app.StakingGateStatus()
+	if err != nil {
+		app.logger.WithFields(logrus.Fields{
+			"err": err,
+		}).Error("Failed to check whether babylon accepts new delegations")
+		return wasOpen
+	}
+
+	if open != wasOpen {
+		app.recordStakingGateChange(open, reason)
+	}
+
+	if !open {
+		return false
+	}
+
+	// queued stakes are submitted once maintenance window ends
+	if app.checkMaintenanceWindow() != nil {
+		return true
+	}
+
+	queued, err := app.txTracker.ListQueuedStakes()
+	if err != nil {
+		app.logger.WithFields(logrus.Fields{
+			"err": err,
+		}).Error("Failed to list queued stakes")
+		return true
+	}
+
+	for i := range queued {
+		select {
+		case <-app.quit:
+			return true
+		default:
+		}
+
+		if err := app.submitQueuedStake(&queued[i]); err != nil {
+			if errors.Is(err, ErrStakingGateClosed) {
+				// gate closed again, change is recorded on the next check
+				return true
+			}
+
+			if errors.Is(err, ErrRequestQueueFull) || errors.Is(err, ErrStakerShuttingDown) ||
+				errors.Is(err, ErrChainUnsafe) || errors.Is(err, ErrMaintenanceWindow) {
+				// keep the request and retry on the next check
+				return true
+			}
+
+			app.logger.WithFields(logrus.Fields{
+				"queuedStakeID": queued[i].ID,
+				"err":           err,
+			}).Error("Failed to submit queued stake, removing it from the queue")
+
+			if err := app.txTracker.MarkQueuedStakeFailed(queued[i].ID, err.Error()); err != nil {
+				app.logger.WithFields(logrus.Fields{
+					"queuedStakeID": queued[i].ID,
+					"err":           err,
+				}).Error("Failed to remove failed queued stake")
+			}
+		}
+	}
+
+	return true
+}
+
+func (app *App) recordStakingGateChange(open bool, reason string) {
+	if open {
+		app.logger.Info("Babylon accepts new delegations, submitting queued stakes")
+	} else {
+		app.logger.WithFields(logrus.Fields{
+			"reason": reason,
+		}).Warn("Babylon does not accept new delegations, stake requests are queued")
+	}
+
+	if err := app.txTracker.RecordStakingGateChange(open, reason); err != nil {
+		app.logger.WithFields(logrus.Fields{
+			"err": err,
+		}).Error("Failed to record staking gate change")
+	}
+}
+
+// submitQueuedStake sends delegation of the queued stake request and removes
+// it from the queue
+func (app *App) submitQueuedStake(q *stakerdb.QueuedStake) error {
+	stakerAddress, err := btcutil.DecodeAddress(q.StakerAddress, app.network)
+	if err != nil {
+		return fmt.Errorf("error decoding staker address: %w", err)
+	}
+
+	fpPks := make([]*btcec.PublicKey, len(q.FinalityProviders))
+	for i, fp := range q.FinalityProviders {
+		fpPks[i], err = ParseSchnorrPk(fp)
+		if err != nil {
+			return fmt.Errorf("error parsing finality provider public key %s: %w", fp, err)
+		}
+	}
+
+	var stakingTxHash *chainhash.Hash
+	err = app.requests.run(func() error {
+		var err error
+		stakingTxHash, err = app.stakeFunds(
+			stakerAddress,
+			q.Amount,
+			fpPks,
+			q.StakingTime,
+			nil,
+			q.Tenant,
+			chainfee.SatPerKVByte(q.MaxFeeRate*1000),
+			q.Reference,
+			FeeSelection{FeeRate: q.FeeRate, TargetConf: q.TargetConf},
+		)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	// stakeFunds returns nil hash when staker is shutting down
+	if stakingTxHash == nil {
+		return ErrStakerShuttingDown
+	}
+
+	app.recordLabels(stakingTxHash, q.Labels)
+
+	app.logger.WithFields(logrus.Fields{
+		"queuedStakeID": q.ID,
+		"stakingTxHash": stakingTxHash,
+	}).Info("Queued stake submitted")
+
+	if err := app.txTracker.MarkQueuedStakeSubmitted(q.ID, stakingTxHash); err != nil {
+		// delegation is already sent, keeping the request would stake twice
+		app.logger.WithFields(logrus.Fields{
+			"queuedStakeID": q.ID,
+			"err":           err,
+		}).Error("Failed to remove submitted queued stake")
+	}
+
+	return nil
+}
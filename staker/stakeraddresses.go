@@ -0,0 +1,55 @@
+package staker
+
+import (
+	"fmt"
+
+	scfg "github.com/babylonlabs-io/btc-staker/stakercfg"
+	"github.com/babylonlabs-io/btc-staker/stakerdb"
+	"github.com/babylonlabs-io/btc-staker/walletcontroller"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/sirupsen/logrus"
+)
+
+// NewStakerAddress derives new wallet address usable as staker address and
+// registers it. Babylon requires staker address to be either bip86 taproot
+// address (key spend only) or native segwit address, as only those can be used
+// to create bip322 proof of possession.
+func (app *App) NewStakerAddress(addressType string) (btcutil.Address, error) {
+	var walletAddressType walletcontroller.AddressType
+	switch addressType {
+	case scfg.AddressTypeTaproot:
+		walletAddressType = walletcontroller.AddressTypeTaproot
+	case scfg.AddressTypeSegwit:
+		walletAddressType = walletcontroller.AddressTypeSegwit
+	default:
+		return nil, fmt.Errorf("unsupported staker address type: %s, expected %s or %s",
+			addressType, scfg.AddressTypeTaproot, scfg.AddressTypeSegwit)
+	}
+
+	address, err := app.wc.NewAddress(walletAddressType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive new %s address: %w", addressType, err)
+	}
+
+	// wallet must be able to provide public key of the address, which for
+	// taproot also checks it commits to bip86 output key
+	if _, err := app.wc.AddressPublicKey(address); err != nil {
+		return nil, fmt.Errorf("derived address %s cannot be used for staking: %w", address, err)
+	}
+
+	if err := app.txTracker.AddStakerAddress(address.EncodeAddress(), addressType); err != nil {
+		return nil, fmt.Errorf("failed to register staker address: %w", err)
+	}
+
+	app.logger.WithFields(logrus.Fields{
+		"address":     address,
+		"addressType": addressType,
+	}).Info("Registered new staker address")
+
+	return address, nil
+}
+
+// ListStakerAddresses returns staker addresses derived by staker
+func (app *App) ListStakerAddresses() ([]stakerdb.StakerAddress, error) {
+	return app.txTracker.ListStakerAddresses()
+}
@@ -10,6 +10,7 @@ import (
 
 	bct "github.com/babylonlabs-io/babylon/v4/client/babylonclient"
 	bbntypes "github.com/babylonlabs-io/babylon/v4/types"
+	btcstypes "github.com/babylonlabs-io/babylon/v4/x/btcstaking/types"
 
 	"github.com/avast/retry-go/v4"
 	staking "github.com/babylonlabs-io/babylon/v4/btcstaking"
@@ -17,7 +18,6 @@ import (
 	"github.com/babylonlabs-io/btc-staker/metrics"
 	scfg "github.com/babylonlabs-io/btc-staker/stakercfg"
 	"github.com/babylonlabs-io/btc-staker/stakerdb"
-	"github.com/babylonlabs-io/btc-staker/types"
 	"github.com/babylonlabs-io/btc-staker/utils"
 	"github.com/babylonlabs-io/btc-staker/walletcontroller"
 	"github.com/decred/dcrd/dcrec/secp256k1/v4"
@@ -32,7 +32,6 @@ import (
 	"github.com/btcsuite/btcd/wire"
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	notifier "github.com/lightningnetwork/lnd/chainntnfs"
-	"github.com/lightningnetwork/lnd/channeldb"
 	"github.com/lightningnetwork/lnd/kvdb"
 	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
 	"github.com/sirupsen/logrus"
@@ -55,11 +54,12 @@ var (
 	RtyErr            = retry.LastErrorOnly(true)
 )
 
-func longRetryOps(ctx context.Context, fixedDelay time.Duration, onRetryFn retry.OnRetryFunc) []retry.Option {
+func longRetryOps(ctx context.Context, delay time.Duration, maxDelay time.Duration, onRetryFn retry.OnRetryFunc) []retry.Option {
 	return []retry.Option{
 		retry.Context(ctx),
-		retry.DelayType(retry.FixedDelay),
-		retry.Delay(fixedDelay),
+		retry.DelayType(retry.BackOffDelay),
+		retry.Delay(delay),
+		retry.MaxDelay(maxDelay),
 		longRetryAttempts,
 		retry.OnRetry(onRetryFn),
 		RtyErr,
@@ -74,6 +74,8 @@ func (app *App) onLongRetryFunc(stakingTxHash *chainhash.Hash, msg string) retry
 			"error":        err,
 			"txHash":       stakingTxHash,
 		}).Error(msg)
+		// long retried operations are btc operations
+		app.noteFailure(stakingTxHash, btcFailureNote(fmt.Errorf("%s: %w", msg, err)))
 	}
 }
 
@@ -93,9 +95,6 @@ const (
 
 	defaultWalletUnlockTimeout = 15
 
-	// If we fail to send unbonding tx to btc for any reason we will retry in this time
-	unbondingSendRetryTimeout = 1 * time.Minute
-
 	// after this many confirmations we treat unbonding transaction as confirmed on btc
 	// TODO: needs to consolidate what is safe confirmation for different types of transaction
 	// as currently we have different values for different types of transactions
@@ -118,6 +117,8 @@ type App struct {
 	txTracker        *stakerdb.TrackedTransactionStore
 	babylonMsgSender *cl.BabylonMsgSender
 	m                *metrics.StakerMetrics
+	// database opened by New, closed when app stops
+	db kvdb.Backend
 
 	stakingRequestedCmdChan                       chan *stakingRequestCmd
 	migrateStakingCmd                             chan *migrateStakingCmd
@@ -126,95 +127,102 @@ type App struct {
 	unbondingTxConfirmedOnBtcEvChan               chan *unbondingTxConfirmedOnBtcEvent
 	spendStakeTxConfirmedOnBtcEvChan              chan *spendStakeTxConfirmedOnBtcEvent
 	criticalErrorEvChan                           chan *criticalErrorEvent
-	currentBestBlockHeight                        atomic.Uint32
+	// signaled on every new block, keyed by name of worker run on new blocks
+	newBlockChans map[string]chan struct{}
+	// limits concurrency of stake, unbond and spend requests
+	requests *requestPool
+	// delegation statuses served to RPC reads
+	statuses *delegationStatusCache
+	// delegations detected stuck in intermediate states
+	stuck *stuckDelegations
+	// staking tx hashes of delegations whose unbonding tx is being sent
+	unbondingInFlight sync.Map
+	// tasks watching covenant signatures and activation of delegations,
+	// keyed by delegationTaskKey
+	delegationTasks sync.Map
+	// result of the last chain safety check
+	safety *chainSafety
+	// progress of reconciliation of stored delegations on start
+	startup *startupSync
+	// nil unless signing policy is enabled
+	policy *signingPolicy
+	// nil unless instance was elected as leader
+	fence *leaderFence
+	// nil unless app runs as read only replica
+	replicaSource ReplicaSource
+	// progress of replication of the primary
+	replica replicaState
+	// nil unless webhook is enabled
+	alerts *alertLimiter
+	// nil unless finality provider quotas are configured
+	fpQuotas *fpQuotas
+	// windows during which non urgent work is deferred
+	maintenance []scfg.MaintenanceWindow
+	// true once renewal jobs interrupted by restart were resumed
+	renewalJobsResumed atomic.Bool
+	// relay fees of the btc node used as fee rate floor
+	relayFeesCache         relayFeesCache
+	currentBestBlockHeight atomic.Uint32
 }
 
-// NewStakerAppFromConfig creates a new staker app instance from the given config
+// NewStakerAppFromConfig creates a new staker app instance from the given
+// config. Additional options are applied after the given dependencies.
 func NewStakerAppFromConfig(
 	config *scfg.Config,
 	logger *logrus.Logger,
 	rpcClientLogger *zap.Logger,
 	db kvdb.Backend,
 	m *metrics.StakerMetrics,
+	opts ...Option,
 ) (*App, error) {
-	// TODO: If we want to support multiple wallet types, this is most probably the place to decide
-	// on concrete implementation
-	walletClient, err := walletcontroller.NewRPCWalletController(config)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create wallet controller: %w", err)
-	}
-
-	tracker, err := stakerdb.NewTrackedTransactionStore(db)
-
-	if err != nil {
-		return nil, fmt.Errorf("failed to create tracked transaction store: %w", err)
-	}
-
-	babylonClient, err := cl.NewBabylonController(config.BabylonConfig, &config.ActiveNetParams, logger, rpcClientLogger)
+	return New(append([]Option{
+		WithConfig(config),
+		WithLogger(logger),
+		WithRPCClientLogger(rpcClientLogger),
+		WithDB(db),
+		WithMetrics(m),
+	}, opts...)...)
+}
 
+// NewStakerAppFromDeps creates a new staker app instance from the given dependencies
+func NewStakerAppFromDeps(
+	config *scfg.Config,
+	logger *logrus.Logger,
+	cl cl.BabylonClient,
+	walletClient walletcontroller.WalletController,
+	nodeNotifier notifier.ChainNotifier,
+	feeEestimator FeeEstimator,
+	tracker *stakerdb.TrackedTransactionStore,
+	babylonMsgSender *cl.BabylonMsgSender,
+	metrics *metrics.StakerMetrics,
+) (*App, error) {
+	policy, err := newSigningPolicy(config, walletClient)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create Babylon controller: %w", err)
+		return nil, err
 	}
 
-	hintCache, err := channeldb.NewHeightHintCache(
-		channeldb.CacheConfig{
-			// TODO: Investigate this option. Lighting docs mention that this is necessary for some edge case
-			QueryDisable: false,
-		}, db,
-	)
-
+	fpQuotas, err := newFpQuotas(config.StakerConfig)
 	if err != nil {
-		return nil, fmt.Errorf("unable to create height hint cache: %w", err)
+		return nil, err
 	}
 
-	nodeNotifier, err := NewNodeBackend(config.BtcNodeBackendConfig, &config.ActiveNetParams, hintCache)
-
+	maintenance, err := config.StakerConfig.MaintenanceSchedule()
 	if err != nil {
-		return nil, fmt.Errorf("failed to create node backend with notifier: %w", err)
+		return nil, err
 	}
 
-	var feeEstimator FeeEstimator
-	switch config.BtcNodeBackendConfig.EstimationMode {
-	case types.StaticFeeEstimation:
-		feeEstimator = NewStaticBtcFeeEstimator(chainfee.SatPerKVByte(config.BtcNodeBackendConfig.MaxFeeRate * 1000))
-	case types.DynamicFeeEstimation:
-		feeEstimator, err = NewDynamicBtcFeeEstimator(config.BtcNodeBackendConfig, &config.ActiveNetParams, logger)
+	var alerts *alertLimiter
+	if config.WebhookConfig != nil && config.WebhookConfig.Enabled() {
+		alerts, err = newAlertLimiter(config.WebhookConfig)
 		if err != nil {
-			return nil, fmt.Errorf("failed to create dynamic fee estimator: %w", err)
+			return nil, err
 		}
-	default:
-		return nil, fmt.Errorf("unknown fee estimation mode: %d", config.BtcNodeBackendConfig.EstimationMode)
 	}
 
-	babylonMsgSender := cl.NewBabylonMsgSender(babylonClient, logger, config.StakerConfig.MaxConcurrentTransactions)
+	quit := make(chan struct{})
 
-	return NewStakerAppFromDeps(
-		config,
-		logger,
-		babylonClient,
-		walletClient,
-		nodeNotifier,
-		feeEstimator,
-		tracker,
-		babylonMsgSender,
-		m,
-	)
-}
-
-// NewStakerAppFromDeps creates a new staker app instance from the given dependencies
-func NewStakerAppFromDeps(
-	config *scfg.Config,
-	logger *logrus.Logger,
-	cl cl.BabylonClient,
-	walletClient walletcontroller.WalletController,
-	nodeNotifier notifier.ChainNotifier,
-	feeEestimator FeeEstimator,
-	tracker *stakerdb.TrackedTransactionStore,
-	babylonMsgSender *cl.BabylonMsgSender,
-	metrics *metrics.StakerMetrics,
-) (*App, error) {
 	return &App{
-		babylonClient:           cl,
+		babylonClient:           newParamsArchiveClient(cl, tracker, logger),
 		wc:                      walletClient,
 		notifier:                nodeNotifier,
 		feeEstimator:            feeEestimator,
@@ -224,7 +232,7 @@ func NewStakerAppFromDeps(
 		m:                       metrics,
 		config:                  config,
 		logger:                  logger,
-		quit:                    make(chan struct{}),
+		quit:                    quit,
 		stakingRequestedCmdChan: make(chan *stakingRequestCmd),
 		// channel to receive requests of transition of BTC staking tx to consumer BTC delegation
 		migrateStakingCmd: make(chan *migrateStakingCmd),
@@ -241,6 +249,22 @@ func NewStakerAppFromDeps(
 		// how to handle, so we just log them. It is up to user to investigate what had happened
 		// and report the situation
 		criticalErrorEvChan: make(chan *criticalErrorEvent),
+		// channels signaled on every new block to check tracked transactions
+		newBlockChans: newBlockChans(),
+		requests: newRequestPool(
+			config.StakerConfig.MaxConcurrentRequests,
+			config.StakerConfig.MaxQueuedRequests,
+			metrics,
+			quit,
+		),
+		statuses:    newDelegationStatusCache(),
+		stuck:       newStuckDelegations(),
+		safety:      &chainSafety{},
+		startup:     newStartupSync(),
+		policy:      policy,
+		alerts:      alerts,
+		fpQuotas:    fpQuotas,
+		maintenance: maintenance,
 	}, nil
 }
 
@@ -250,6 +274,17 @@ func (app *App) Start() error {
 	app.startOnce.Do(func() {
 		app.logger.Infof("Starting App")
 
+		if app.isReplica() && app.replicaSource == nil {
+			startErr = errors.New("replica source is not set")
+			return
+		}
+
+		// fence other instances before anything is broadcast
+		if err := app.fence.acquire(); err != nil {
+			startErr = err
+			return
+		}
+
 		// TODO: This can take a long time as it connects to node. Maybe make it cancellable?
 		// although staker without node is not very useful
 
@@ -273,6 +308,15 @@ func (app *App) Start() error {
 			return
 		}
 
+		// query relay fees early, so that misconfigured fee rate bounds are
+		// reported on start
+		if floor := app.feeRateFloor(); floor > chainfee.SatPerKVByte(app.config.BtcNodeBackendConfig.MaxFeeRate*1000) {
+			app.logger.WithFields(logrus.Fields{
+				"minRelayFee": floor,
+				"maxFeeRate":  app.config.BtcNodeBackendConfig.MaxFeeRate,
+			}).Warn("Max fee rate of the config is below min relay fee of btc node, transactions will pay min relay fee")
+		}
+
 		// we registered for notifications with `nil`  so we should receive best block
 		// immediately
 		select {
@@ -289,16 +333,51 @@ func (app *App) Start() error {
 
 		app.logger.Infof("Initial btc best block height is: %d", app.currentBestBlockHeight.Load())
 
-		app.babylonMsgSender.Start()
+		// replica never submits anything to Babylon
+		if !app.isReplica() {
+			app.babylonMsgSender.Start()
+		}
 
-		app.wg.Add(3)
-		go app.handleNewBlocks(blockEventNotifier)
-		go app.handleStakingEvents()
-		go app.handleStakingCommands()
+		// block notifications are cancelled only once worker is stopped for
+		// good, restarted worker keeps receiving them
+		app.wg.Add(1)
+		go func() {
+			defer app.wg.Done()
+			defer blockEventNotifier.Cancel()
+			app.superviseWorker("new_blocks", func() {
+				app.handleNewBlocks(blockEventNotifier)
+			})
+		}()
+		if app.isReplica() {
+			// replica only follows the primary, so that staking workers are
+			// not started
+			app.startWorker("replication", app.handleReplication)
+		} else {
+			app.startWorker("staking_events", app.handleStakingEvents)
+			app.startWorker("staking_commands", app.handleStakingCommands)
+		}
 
-		if err := app.checkTransactionsStatus(); err != nil {
-			startErr = err
-			return
+		if app.config.StakerConfig.HeartbeatURL != "" {
+			app.startWorker("heartbeat", app.handleHeartbeat)
+		}
+
+		if app.webhookEnabled() {
+			app.startWorker("webhook", app.handleWebhook)
+		}
+
+		if app.config.EventLogConfig != nil {
+			app.startWorker("event_log_retention", app.handleEventLogRetention)
+		}
+
+		app.startTask("params_archive_sync", app.syncParamsArchive)
+
+		if app.isReplica() {
+			app.completeReplicaStartup()
+		} else {
+			// stored delegations are reconciled in background, so that read
+			// only requests can be served meanwhile
+			app.wg.Add(1)
+			go app.runStartupSync()
 		}
 
 		app.logger.Info("App started")
@@ -310,8 +389,6 @@ func (app *App) Start() error {
 // handleNewBlocks is a goroutine which handles notifications about new
 // best block
 func (app *App) handleNewBlocks(blockNotifier *notifier.BlockEpochEvent) {
-	defer app.wg.Done()
-	defer blockNotifier.Cancel()
 	for {
 		select {
 		case block, ok := <-blockNotifier.Epochs:
@@ -325,6 +402,8 @@ func (app *App) handleNewBlocks(blockNotifier *notifier.BlockEpochEvent) {
 				"btcBlockHeight": block.Height,
 				"btcBlockHash":   block.Hash.String(),
 			}).Debug("Received new best btc block")
+
+			app.signalNewBlock()
 		case <-app.quit:
 			return
 		}
@@ -337,22 +416,30 @@ func (app *App) Stop() error {
 	app.stopOnce.Do(func() {
 		app.logger.Infof("Stopping App")
 		close(app.quit)
+		// background loops and goroutines watching btc and Babylon write to
+		// the database and use components below, so they are stopped first
 		app.wg.Wait()
 
 		app.babylonMsgSender.Stop()
 
-		err := app.feeEstimator.Stop()
+		// remaining components are stopped even if some of them fail, so that
+		// database is always closed
+		var errs []error
+		if err := app.feeEstimator.Stop(); err != nil {
+			errs = append(errs, fmt.Errorf("failed to stop fee estimator: %w", err))
+		}
 
-		if err != nil {
-			stopErr = err
-			return
+		if err := app.notifier.Stop(); err != nil {
+			errs = append(errs, fmt.Errorf("failed to stop btc notifier: %w", err))
 		}
 
-		err = app.notifier.Stop()
-		if err != nil {
-			stopErr = err
-			return
+		if app.db != nil {
+			if err := app.db.Close(); err != nil {
+				errs = append(errs, fmt.Errorf("failed to close database: %w", err))
+			}
 		}
+
+		stopErr = errors.Join(errs...)
 	})
 	return stopErr
 }
@@ -385,6 +472,10 @@ func (app *App) SendPhase1Transaction(
 	covenantPks []*secp256k1.PublicKey,
 	covenantQuorum uint32,
 ) (babylonBTCDelegationTxHash string, err error) {
+	if err := app.checkStartupSync(); err != nil {
+		return "", err
+	}
+
 	// check we are not shutting down
 	select {
 	case <-app.quit:
@@ -402,12 +493,29 @@ func (app *App) SendPhase1Transaction(
 		return "", err
 	}
 
+	if err := app.checkPhase1Registration(stkTxHash, parsedStakingTx, notifierTx.BlockHeight); err != nil {
+		return "", err
+	}
+
+	if err := app.checkChainSafety(); err != nil {
+		return "", err
+	}
+
+	if err := app.checkMaintenanceWindow(); err != nil {
+		return "", err
+	}
+
 	pop, err := app.unlockAndCreatePop(stakerAddr)
 	if err != nil {
 		return "", err
 	}
 
-	req := newMigrateStakingCmd(stakerAddr, notifierTx, parsedStakingTx, pop)
+	return app.sendMigrateStakingCmd(newMigrateStakingCmd(stakerAddr, notifierTx, parsedStakingTx, pop))
+}
+
+// sendMigrateStakingCmd sends migrate staking command to the event loop and
+// waits for the hash of Babylon transaction registering the delegation
+func (app *App) sendMigrateStakingCmd(req *migrateStakingCmd) (string, error) {
 	utils.PushOrQuit[*migrateStakingCmd](
 		app.migrateStakingCmd,
 		req,
@@ -417,7 +525,7 @@ func (app *App) SendPhase1Transaction(
 	select {
 	case reqErr := <-req.errChan:
 		app.logger.WithFields(logrus.Fields{
-			"stakerAddress": stakerAddr,
+			"stakerAddress": req.stakerAddr,
 			"err":           reqErr,
 		}).Debugf("Sending staking tx failed")
 
@@ -448,6 +556,18 @@ func (app *App) checkTransactionsStatus() error {
 		return fmt.Errorf("error while checking and handling stored transactions: %w", err)
 	}
 
+	app.setStartupSyncTotal(len(transactions))
+
+	type activeTransaction struct {
+		txHash chainhash.Hash
+		udi    *cl.UndelegationInfo
+	}
+
+	var (
+		activeTransactions []activeTransaction
+		stakingOutputs     []wire.OutPoint
+	)
+
 	for _, txHash := range transactions {
 		di, err := app.babylonClient.QueryBTCDelegation(&txHash)
 		if err != nil {
@@ -466,10 +586,38 @@ func (app *App) checkTransactionsStatus() error {
 			if err != nil {
 				return fmt.Errorf("failed to get undelegation info: %w", err)
 			}
-			if err := app.handleActiveTransaction(&txHash, stakingOutputIndex, udi.UnbondingTransaction); err != nil {
-				return fmt.Errorf("failed to handle active transaction <%s>: %w", txHash.String(), err)
-			}
+			activeTransactions = append(activeTransactions, activeTransaction{
+				txHash: txHash,
+				udi:    udi,
+			})
+			stakingOutputs = append(stakingOutputs, wire.OutPoint{
+				Hash:  txHash,
+				Index: stakingOutputIndex,
+			})
+			// active delegations are reconciled once their outputs are checked
+			continue
+		}
+
+		app.startupSyncReconciled()
+	}
+
+	if len(activeTransactions) == 0 {
+		return nil
+	}
+
+	// check staking outputs of all active delegations in batches, instead of
+	// one round trip to btc node per delegation
+	stakingOutputsSpent, err := app.wc.OutputsSpent(stakingOutputs)
+	if err != nil {
+		return fmt.Errorf("failed to check staking outputs spentness: %w", err)
+	}
+
+	for i, tx := range activeTransactions {
+		if err := app.handleActiveTransaction(&tx.txHash, stakingOutputsSpent[i], tx.udi); err != nil {
+			return fmt.Errorf("failed to handle active transaction <%s>: %w", tx.txHash.String(), err)
 		}
+
+		app.startupSyncReconciled()
 	}
 
 	return nil
@@ -478,36 +626,40 @@ func (app *App) checkTransactionsStatus() error {
 // handlePendingTransaction handles transactions which status is PENDING in babylon node
 func (app *App) handlePendingTransaction(stakingTxHash *chainhash.Hash) {
 	// we crashed after successful send to babylon, restart checking for unbonding signatures
-	app.wg.Add(1)
-	go app.checkForUnbondingTxSignaturesOnBabylon(stakingTxHash)
+	app.startDelegationTask(covenantSignaturesTask, *stakingTxHash, func() {
+		app.checkForUnbondingTxSignaturesOnBabylon(stakingTxHash)
+	})
 }
 
 // handleVerifiedTransaction handles transactions which status is VERIFIED in babylon node
 func (app *App) handleVerifiedTransaction(stakingTxHash *chainhash.Hash, stakingOutputIndex uint32) {
 	txHashCopy := *stakingTxHash
 	storedTx, _ := app.mustGetTransactionAndStakerAddress(&txHashCopy)
-	app.wg.Add(1)
-	go app.activateVerifiedDelegation(
-		storedTx.StakingTx,
-		stakingOutputIndex,
-		&txHashCopy,
-	)
+	app.startDelegationTask(activationTask, txHashCopy, func() {
+		app.activateVerifiedDelegation(
+			storedTx.StakingTx,
+			stakingOutputIndex,
+			&txHashCopy,
+		)
+	})
 }
 
 // handleActiveTransaction handles transactions which status is ACTIVE in babylon node
-func (app *App) handleActiveTransaction(stakingTxHash *chainhash.Hash, stakingOutputIndex uint32, unbondingTx *wire.MsgTx) error {
+func (app *App) handleActiveTransaction(stakingTxHash *chainhash.Hash, stakingOutputSpent bool, udi *cl.UndelegationInfo) error {
 	// In this status, delegation was sent to Babylon and activated by covenants.
 	// check whether we:
 	// - did not spend tx before restart
 	// - did not send unbonding tx before restart
 	// tx, _ := app.mustGetTransactionAndStakerAddress(stakingTxHash)
 
-	// 1. First check if staking output is still unspent on BTC chain
-	stakingOutputSpent, err := app.wc.OutputSpent(stakingTxHash, stakingOutputIndex)
-	if err != nil {
-		return fmt.Errorf("failed to check staking output spentness: %w", err)
+	// delegations created before spend transactions were indexed get
+	// their unbonding transaction indexed on restart
+	if udi.UnbondingTransaction != nil {
+		unbondingTxHash := udi.UnbondingTransaction.TxHash()
+		app.indexSpendTx(stakingTxHash, stakerdb.WatchedUnbondingTx, &unbondingTxHash)
 	}
 
+	// 1. First check if staking output is still unspent on BTC chain
 	if !stakingOutputSpent {
 		// If the staking output is unspent, then it means that delegation is
 		// sitll considered active. We can move forward without to next transaction
@@ -517,8 +669,8 @@ func (app *App) handleActiveTransaction(stakingTxHash *chainhash.Hash, stakingOu
 
 	// 2. Staking output has been spent, we need to check whether this is unbonding
 	// or withdrawal transaction
-	unbondingTxHash := unbondingTx.TxHash()
-	pkScript := unbondingTx.TxOut[0].PkScript
+	unbondingTxHash := udi.UnbondingTransaction.TxHash()
+	pkScript := udi.UnbondingTransaction.TxOut[0].PkScript
 
 	confirmationInfo, unbondingTxStatus, err := app.wc.TxDetails(
 		&unbondingTxHash,
@@ -570,12 +722,14 @@ func (app *App) handleActiveTransaction(stakingTxHash *chainhash.Hash, stakingOu
 
 	// unbonding tx is in mempool, wait for confirmation and inform event
 	// loop about it
-	app.wg.Add(1)
-	go app.waitForUnbondingTxConfirmation(
-		ev,
-		&unbondingTxHash,
-		stakingTxHash,
-	)
+	app.startTask("unbonding_confirmation", func() {
+		app.waitForUnbondingTxConfirmation(
+			ev,
+			&unbondingTxHash,
+			stakingTxHash,
+			udi.UnbondingTime,
+		)
+	})
 	return nil
 }
 
@@ -643,31 +797,9 @@ func (app *App) retrieveExternalDelegationData(
 	stakerAddress btcutil.Address,
 	inclusionInfo *inclusionInfo,
 ) (*externalDelegationData, error) {
-	var params *cl.StakingParams
-
-	if inclusionInfo == nil {
-		// chose params as babylon would through tip of btc light client
-		tipHeight, err := app.babylonClient.QueryBtcLightClientTipHeight()
-
-		if err != nil {
-			return nil, fmt.Errorf("error getting tip height: %w", err)
-		}
-
-		p, err := app.babylonClient.ParamsByBtcHeight(tipHeight)
-
-		if err != nil {
-			return nil, fmt.Errorf("error getting params: %w", err)
-		}
-
-		params = p
-	} else {
-		p, err := app.babylonClient.ParamsByBtcHeight(inclusionInfo.inclusionBlockBtcHeight)
-
-		if err != nil {
-			return nil, fmt.Errorf("error getting params: %w", err)
-		}
-
-		params = p
+	params, err := app.delegationParams(inclusionInfo)
+	if err != nil {
+		return nil, err
 	}
 
 	stakerPublicKey, err := app.wc.AddressPublicKey(stakerAddress)
@@ -728,12 +860,25 @@ func (app *App) sendUnbondingTxToBtcWithWitness(
 		}).Fatalf("failed to create necessary spend info to send unbonding tx")
 	}
 
+	unbondingOutputScript, err := unbondingScript(
+		stakerPubKey,
+		fpBtcPubkeys,
+		&params.BtcStakingParams,
+		undelegationInfo.UnbondingTime,
+		btcutil.Amount(undelegationInfo.UnbondingTransaction.TxOut[0].Value),
+		app.network,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to send unbondingtx: %w", err)
+	}
+
 	stakerUnbondingSig, err := app.signTaprootScriptSpendUsingWallet(
 		undelegationInfo.UnbondingTransaction,
 		storedTx.StakingTx.TxOut[stakingOutputIndex],
 		stakerAddress,
 		&unbondingSpendInfo.RevealedLeaf,
 		&unbondingSpendInfo.ControlBlock,
+		unbondingOutputScript,
 	)
 
 	if err != nil {
@@ -744,11 +889,26 @@ func (app *App) sendUnbondingTxToBtcWithWitness(
 		return fmt.Errorf("failed to receive stakerUnbondingSig.Signature")
 	}
 
-	covenantSigantures, err := createWitnessSignaturesForPubKeys(
+	// invalid covenant signature would make unbonding transaction rejected,
+	// so only verified ones are used
+	validSigs, rejected, err := verifyCovenantUnbondingSignatures(
+		undelegationInfo.UnbondingTransaction,
+		storedTx.StakingTx.TxOut[stakingOutputIndex],
+		unbondingSpendInfo,
 		params.CovenantPks,
 		params.CovenantQuruomThreshold,
 		undelegationInfo.CovenantUnbondingSignatures,
 	)
+	app.logRejectedCovenantSignatures(stakingTxHash, rejected)
+	if err != nil {
+		return fmt.Errorf("failed to send unbonding tx: %w", err)
+	}
+
+	covenantSigantures, err := createWitnessSignaturesForPubKeys(
+		params.CovenantPks,
+		params.CovenantQuruomThreshold,
+		validSigs,
+	)
 
 	if err != nil {
 		app.logger.WithFields(logrus.Fields{
@@ -780,6 +940,13 @@ func (app *App) sendUnbondingTxToBtcWithWitness(
 		return fmt.Errorf("failed to send unbonding tx. wallet signing error: %w", err)
 	}
 
+	app.watchMempoolTx(stakingTxHash, stakerdb.WatchedUnbondingTx, unbondingTx)
+	app.recordPaidFee(
+		stakingTxHash,
+		stakerdb.WatchedUnbondingTx,
+		btcutil.Amount(storedTx.StakingTx.TxOut[stakingOutputIndex].Value-unbondingTx.TxOut[0].Value),
+	)
+
 	return nil
 }
 
@@ -809,7 +976,8 @@ func (app *App) sendUnbondingTxToBtc(
 	},
 		longRetryOps(
 			ctx,
-			unbondingSendRetryTimeout,
+			app.config.StakerConfig.BtcRetryInterval,
+			app.config.StakerConfig.BtcMaxBackoff,
 			app.onLongRetryFunc(stakingTxHash, "failed to send unbonding tx to btc"),
 		)...,
 	)
@@ -838,7 +1006,8 @@ func (app *App) sendUnbondingTxToBtc(
 	},
 		longRetryOps(
 			ctx,
-			unbondingSendRetryTimeout,
+			app.config.StakerConfig.BtcRetryInterval,
+			app.config.StakerConfig.BtcMaxBackoff,
 			app.onLongRetryFunc(stakingTxHash, "failed to register for unbonding tx confirmation notification"),
 		)...,
 	)
@@ -854,8 +1023,8 @@ func (app *App) waitForUnbondingTxConfirmation(
 	waitEv *notifier.ConfirmationEvent,
 	unbondingTxHash *chainhash.Hash,
 	stakingTxHash *chainhash.Hash,
+	unbondingTime uint16,
 ) {
-	defer app.wg.Done()
 	defer waitEv.Cancel()
 
 	for {
@@ -872,6 +1041,7 @@ func (app *App) waitForUnbondingTxConfirmation(
 				stakingTxHash: *stakingTxHash,
 				blockHash:     *conf.BlockHash,
 				blockHeight:   conf.BlockHeight,
+				unbondingTime: unbondingTime,
 			}
 
 			utils.PushOrQuit[*unbondingTxConfirmedOnBtcEvent](
@@ -903,7 +1073,12 @@ func (app *App) sendUnbondingTxToBtcTask(
 	fpBtcPubkeys []*btcec.PublicKey,
 	undelegationInfo *cl.UndelegationInfo,
 ) {
-	defer app.wg.Done()
+	// remediation may restart unbonding, never send it twice at once
+	if _, running := app.unbondingInFlight.LoadOrStore(*stakingTxHash, struct{}{}); running {
+		return
+	}
+	defer app.unbondingInFlight.Delete(*stakingTxHash)
+
 	quitCtx, cancel := app.appQuitContext()
 	defer cancel()
 
@@ -925,12 +1100,14 @@ func (app *App) sendUnbondingTxToBtcTask(
 
 	unbondingTxHash := undelegationInfo.UnbondingTransaction.TxHash()
 
-	app.wg.Add(1)
-	go app.waitForUnbondingTxConfirmation(
-		waitEv,
-		&unbondingTxHash,
-		stakingTxHash,
-	)
+	app.startTask("unbonding_confirmation", func() {
+		app.waitForUnbondingTxConfirmation(
+			waitEv,
+			&unbondingTxHash,
+			stakingTxHash,
+			undelegationInfo.UnbondingTime,
+		)
+	})
 }
 
 // context which will be cancelled when app is shutting down
@@ -951,25 +1128,35 @@ func (app *App) appQuitContext() (context.Context, func()) {
 	return ctx, cancel
 }
 
-// builds and sends a delegation
+// builds and sends a delegation, returning also the exact message sent to
+// Babylon
 func (app *App) buildAndSendDelegation(
 	req *sendDelegationRequest,
 	stakerAddress btcutil.Address,
 	stakingOutputIndex uint32,
 	stakingTime uint16,
 	storedTx *stakerdb.StoredTransaction,
-) (*bct.RelayerTxResponse, error) {
+) (*bct.RelayerTxResponse, *cl.EncodedMsg, error) {
 	delegation, err := app.buildDelegation(req, stakerAddress, stakingOutputIndex, stakingTime, storedTx)
 	if err != nil {
-		return nil, fmt.Errorf("failed to build delegation: %w", err)
+		return nil, nil, fmt.Errorf("failed to build delegation: %w", err)
 	}
 
-	resp, err := app.babylonMsgSender.SendDelegation(delegation, req.requiredInclusionBlockDepth)
+	msg, err := cl.EncodeDelegationMsg(delegation)
 	if err != nil {
-		return nil, fmt.Errorf("failed to send delegation: %w", err)
+		return nil, nil, fmt.Errorf("failed to encode delegation: %w", err)
 	}
 
-	return resp, nil
+	if err := app.fence.check(); err != nil {
+		return nil, nil, err
+	}
+
+	resp, err := app.sendDelegation(&req.btcTxHash, delegation, req.requiredInclusionBlockDepth)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to send delegation: %w", err)
+	}
+
+	return resp, msg, nil
 }
 
 // handles a send delegation request
@@ -982,6 +1169,8 @@ func (app *App) handleSendDelegationRequest(
 	stakingTx *wire.MsgTx,
 	stakingOutputIdx uint32,
 	inclusionInfo *inclusionInfo,
+	retry bool,
+	tenant string,
 ) (btcTxHash *chainhash.Hash, btcDelTxHash string, err error) {
 	// check pop is not nil
 	if pop == nil {
@@ -1000,8 +1189,7 @@ func (app *App) handleSendDelegationRequest(
 	stakingTxHash := stakingTx.TxHash()
 
 	req := newSendDelegationRequest(&stakingTxHash, inclusionInfo, requiredDepthOnBtcChain, fpBtcPks, pop)
-	// resp, delegationData, err := app.buildAndSendDelegation(
-	resp, err := app.buildAndSendDelegation(
+	resp, msg, err := app.buildAndSendDelegation(
 		req,
 		stakerAddress,
 		stakingOutputIdx,
@@ -1012,17 +1200,36 @@ func (app *App) handleSendDelegationRequest(
 		return nil, btcDelTxHash, fmt.Errorf("failed to build and send delegation: %w", err)
 	}
 
-	if err := app.txTracker.AddTransactionSentToBabylon(
-		stakingTx,
-		// stakingTime,
-		stakerAddress,
-		// delegationData.Ud.UnbondingTxUnbondingTime,
-	); err != nil {
-		return nil, btcDelTxHash, fmt.Errorf("failed to add transaction sent to babylon: %w", err)
+	if retry {
+		// delegation is already tracked, only state of its previous
+		// registration is reset
+		if err := app.txTracker.ResetTransactionRegistration(&stakingTxHash); err != nil {
+			return nil, btcDelTxHash, fmt.Errorf("failed to reset registration of tracked transaction: %w", err)
+		}
+		app.statuses.remove(stakingTxHash)
+	} else {
+		if err := app.txTracker.AddTenantTransactionSentToBabylon(
+			stakingTx,
+			// stakingTime,
+			stakerAddress,
+			// delegationData.Ud.UnbondingTxUnbondingTime,
+			tenant,
+		); err != nil {
+			return nil, btcDelTxHash, fmt.Errorf("failed to add transaction sent to babylon: %w", err)
+		}
+
+		app.recordCreationHeight(&stakingTxHash)
 	}
 
-	app.wg.Add(1)
-	go app.checkForUnbondingTxSignaturesOnBabylon(&stakingTxHash)
+	app.recordRegistration(&stakingTxHash)
+	app.recordBabylonTx(&stakingTxHash, BabylonTxCreateDelegation, resp, msg)
+	if !retry {
+		app.recordActivity(stakerdb.ActivityDelegation, &stakingTxHash, btcutil.Amount(stakingTx.TxOut[stakingOutputIdx].Value))
+	}
+
+	app.startDelegationTask(covenantSignaturesTask, stakingTxHash, func() {
+		app.checkForUnbondingTxSignaturesOnBabylon(&stakingTxHash)
+	})
 
 	return &stakingTxHash, resp.TxHash, nil
 }
@@ -1061,25 +1268,34 @@ func (app *App) handleSendStakeExpansionRequest(cmd *stakingRequestCmd) (btcTxHa
 	)
 
 	// Use the same buildAndSendDelegation method - it already supports expansion via req.isExpansion
-	if _, err = app.buildAndSendDelegation(
+	resp, msg, err := app.buildAndSendDelegation(
 		req,
 		cmd.stakerAddress,
 		0,
 		cmd.stakingTime,
 		fakeStoredTx,
-	); err != nil {
+	)
+	if err != nil {
 		return nil, fmt.Errorf("failed to build and send stake expansion delegation: %w", err)
 	}
 
-	if err := app.txTracker.AddTransactionSentToBabylon(
+	if err := app.txTracker.AddTenantTransactionSentToBabylon(
 		stakingTx,
 		cmd.stakerAddress,
+		cmd.tenant,
 	); err != nil {
 		return nil, fmt.Errorf("failed to add transaction sent to babylon: %w", err)
 	}
 
-	app.wg.Add(1)
-	go app.checkForUnbondingTxSignaturesOnBabylon(&stakingTxHash)
+	app.recordCreationHeight(&stakingTxHash)
+	app.recordRegistration(&stakingTxHash)
+	app.recordBabylonTx(&stakingTxHash, BabylonTxStakeExpansion, resp, msg)
+	app.recordFinalityProviders(&stakingTxHash, cmd.fpBtcPks, btcutil.Amount(stakingTx.TxOut[0].Value))
+	app.recordActivity(stakerdb.ActivityDelegation, &stakingTxHash, btcutil.Amount(stakingTx.TxOut[0].Value))
+
+	app.startDelegationTask(covenantSignaturesTask, stakingTxHash, func() {
+		app.checkForUnbondingTxSignaturesOnBabylon(&stakingTxHash)
+	})
 
 	return &stakingTxHash, nil
 }
@@ -1093,6 +1309,11 @@ func (app *App) buildStakingExpansionTx(cmd *stakingRequestCmd) (*wire.MsgTx, *w
 		return nil, nil, fmt.Errorf("stake expansion in request is nil")
 	}
 
+	changeAddress, err := app.changeAddress(cmd.stakerAddress)
+	if err != nil {
+		return nil, nil, err
+	}
+
 	stakingTx, err := app.wc.CreateTransactionWithInputs(
 		[]wire.OutPoint{{
 			Hash:  *cmd.stakeExpansion.prevActiveStkTxHash,
@@ -1101,7 +1322,7 @@ func (app *App) buildStakingExpansionTx(cmd *stakingRequestCmd) (*wire.MsgTx, *w
 		2,
 		[]*wire.TxOut{cmd.stakingOutput},
 		btcutil.Amount(cmd.feeRate),
-		cmd.stakerAddress,
+		changeAddress,
 		app.filterUtxoFnGen(),
 	)
 
@@ -1139,15 +1360,33 @@ func (app *App) handleStakingCmd(cmd *stakingRequestCmd) (*chainhash.Hash, error
 		return btcTxHash, nil
 	}
 
-	// Create regular staking transaction
-	stakingTx, err = app.wc.CreateTransaction(
-		[]*wire.TxOut{cmd.stakingOutput},
-		btcutil.Amount(cmd.feeRate),
-		cmd.stakerAddress,
-		app.filterUtxoFnGen(),
-	)
-	if err != nil {
-		return nil, fmt.Errorf("failed to build staking transaction: %w", err)
+	var stakingOutputIdx uint32
+	if cmd.fundingOutpoint != nil {
+		// staking transaction chained to the given output, fee was already
+		// accounted for in staking output value, so extra outputs can't be added
+		stakingTx = wire.NewMsgTx(2)
+		stakingTx.AddTxIn(wire.NewTxIn(cmd.fundingOutpoint, nil, nil))
+		stakingTx.AddTxOut(cmd.stakingOutput)
+
+		if err := utils.CheckTransaction(stakingTx); err != nil {
+			return nil, fmt.Errorf("failed to build staking transaction: %w", err)
+		}
+	} else {
+		changeAddress, err := app.changeAddress(cmd.stakerAddress)
+		if err != nil {
+			return nil, err
+		}
+
+		// Create regular staking transaction
+		stakingTx, stakingOutputIdx, err = app.createStakingTx(
+			cmd.stakingOutput,
+			btcutil.Amount(cmd.feeRate),
+			changeAddress,
+			cmd.reference,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to build staking transaction: %w", err)
+		}
 	}
 
 	// Send staking transaction to Babylon node
@@ -1158,21 +1397,23 @@ func (app *App) handleStakingCmd(cmd *stakingRequestCmd) (*chainhash.Hash, error
 		cmd.fpBtcPks,
 		cmd.pop,
 		stakingTx,
-		0,
+		stakingOutputIdx,
 		nil,
+		false,
+		cmd.tenant,
 	)
 
 	if err != nil {
 		return nil, fmt.Errorf("failed to send delegation request: %w", err)
 	}
 
+	app.recordFinalityProviders(btcTxHash, cmd.fpBtcPks, cmd.stakingValue)
+
 	return btcTxHash, nil
 }
 
 // handleStakingCommands handles staking commands
 func (app *App) handleStakingCommands() {
-	defer app.wg.Done()
-
 	for {
 		select {
 		case cmd := <-app.stakingRequestedCmdChan:
@@ -1209,6 +1450,7 @@ func (app *App) handleStakingCommands() {
 				cmd.errChan <- err
 				continue
 			}
+			confirmedAt := time.Now()
 
 			_, btcDelTxHash, err := app.handleSendDelegationRequest(
 				cmd.stakerAddr,
@@ -1219,6 +1461,8 @@ func (app *App) handleStakingCommands() {
 				cmd.notifierTx.Tx,
 				uint32(cmd.parsedStakingTx.StakingOutputIdx),
 				app.newBtcInclusionInfo(cmd.notifierTx),
+				cmd.retry,
+				"",
 			)
 			if err != nil {
 				utils.PushOrQuit(
@@ -1229,9 +1473,13 @@ func (app *App) handleStakingCommands() {
 				app.logger.WithFields(logrus.Fields{
 					"stakingTxHash": stkTxHash,
 				}).WithError(err).Error("BTC delegation transaction failed")
-				return
+				// failed registration can be retried, keep handling events
+				continue
 			}
 
+			// delegation is tracked only once it is registered
+			app.recordActivationPhase(&stkTxHash, stakerdb.ActivationPhaseConfirmed, confirmedAt)
+
 			utils.PushOrQuit(
 				cmd.successChanTxHash,
 				btcDelTxHash,
@@ -1248,8 +1496,6 @@ func (app *App) handleStakingCommands() {
 
 // main event loop for the staker app
 func (app *App) handleStakingEvents() {
-	defer app.wg.Done()
-
 	for {
 		select {
 		case ev := <-app.unbondingTxSignaturesConfirmedOnBabylonEvChan:
@@ -1258,20 +1504,31 @@ func (app *App) handleStakingEvents() {
 			// if the delegation is not active here, it can only mean that statking
 			// is going through pre-approval flow. Fire up task to send staking tx
 			// to btc chain
-			app.wg.Add(1)
-			go app.activateVerifiedDelegation(
-				storedTx.StakingTx,
-				ev.stakingOutputIndex,
-				&ev.stakingTxHash,
-			)
+			stakingOutputIndex, stakingTxHash := ev.stakingOutputIndex, ev.stakingTxHash
+			app.startDelegationTask(activationTask, stakingTxHash, func() {
+				app.activateVerifiedDelegation(
+					storedTx.StakingTx,
+					stakingOutputIndex,
+					&stakingTxHash,
+				)
+			})
 			app.logStakingEventProcessed(ev)
 
 		case ev := <-app.unbondingTxConfirmedOnBtcEvChan:
 			app.logStakingEventReceived(ev)
+			if err := app.txTracker.ClearUnbondRequest(&ev.stakingTxHash); err != nil {
+				app.logger.WithFields(logrus.Fields{
+					"stakingTxHash": ev.stakingTxHash,
+					"err":           err,
+				}).Error("Failed to clear unbond request")
+			}
+			app.recordSpendableHeight(&ev.stakingTxHash, ev.blockHeight, ev.unbondingTime)
+			app.clearFailureNote(&ev.stakingTxHash)
 			app.logStakingEventProcessed(ev)
 
 		case ev := <-app.spendStakeTxConfirmedOnBtcEvChan:
 			app.logStakingEventReceived(ev)
+			app.recordActivity(stakerdb.ActivityWithdrawal, &ev.stakingTxHash, ev.spendTxValue)
 			if err := app.txTracker.DeleteTransactionSentToBabylon(&ev.stakingTxHash); err != nil {
 				app.logger.Fatalf("Error setting state for tx %s: %s", ev.stakingTxHash, err)
 			}
@@ -1279,6 +1536,8 @@ func (app *App) handleStakingEvents() {
 
 		case ev := <-app.delegationActivatedEvChan:
 			app.logStakingEventReceived(ev)
+			app.recordDelegationActivated(&ev.stakingTxHash, time.Now())
+			app.clearFailureNote(&ev.stakingTxHash)
 			app.logStakingEventProcessed(ev)
 
 		case ev := <-app.criticalErrorEvChan:
@@ -1301,9 +1560,14 @@ func (app *App) handleStakingEvents() {
 			}
 			app.logStakingEventReceived(ev)
 
-			// TODO for now we just log it and continue, another options would be to
-			// save error info to db, and additional api to restart delegation/undelegation
-			// procsess from latest state
+			// TODO for now we just log it and note it, another option would be
+			// additional api to restart delegation/undelegation procsess from
+			// latest state
+			app.noteFailure(&ev.stakingTxHash, newFailureNote(
+				stakerdb.FailureSourceStaker,
+				fmt.Sprintf("%s: %s", ev.additionalContext, ev.err),
+				"delegation processing stopped, restart staker to resume it from its latest state",
+			))
 			app.logger.WithFields(logrus.Fields{
 				"stakingTxHash": ev.stakingTxHash,
 				"err":           ev.err,
@@ -1317,11 +1581,80 @@ func (app *App) handleStakingEvents() {
 	}
 }
 
+// recordActivity appends event to the activity log. Activity log is used only
+// for reporting, so failure to record an event is logged and otherwise ignored
+func (app *App) recordActivity(kind stakerdb.ActivityKind, stakingTxHash *chainhash.Hash, amount btcutil.Amount) {
+	ev := &stakerdb.ActivityEvent{
+		Kind:          kind,
+		StakingTxHash: *stakingTxHash,
+		Amount:        amount,
+		Timestamp:     time.Now(),
+		BtcHeight:     app.currentBestBlockHeight.Load(),
+	}
+
+	if err := app.txTracker.AddActivityEvent(ev); err != nil {
+		app.logger.WithFields(logrus.Fields{
+			"stakingTxHash": stakingTxHash,
+			"kind":          kind,
+			"err":           err,
+		}).Error("Failed to record staking activity")
+	}
+}
+
+// StakingActivity returns all recorded staking activity events
+func (app *App) StakingActivity() ([]stakerdb.ActivityEvent, error) {
+	var events []stakerdb.ActivityEvent
+
+	err := app.txTracker.ScanActivityEvents(func(ev *stakerdb.ActivityEvent) error {
+		events = append(events, *ev)
+		return nil
+	}, func() {
+		events = nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan activity events: %w", err)
+	}
+
+	return events, nil
+}
+
+// DBChanges returns at most limit changes of the staker database recorded
+// after change with given sequence number
+func (app *App) DBChanges(afterSeq uint64, limit uint64) ([]stakerdb.Change, error) {
+	return app.txTracker.QueryChanges(afterSeq, limit)
+}
+
+// QuarantinedRecords returns records of tracked transactions which failed to
+// decode or validate and were moved to quarantine
+func (app *App) QuarantinedRecords() ([]stakerdb.QuarantinedRecord, error) {
+	return app.txTracker.QuarantinedRecords()
+}
+
+// DBChangesNotify returns channel which is closed once next database change is
+// recorded
+func (app *App) DBChangesNotify() <-chan struct{} {
+	return app.txTracker.ChangesNotify()
+}
+
 // Wallet returns the wallet controller
 func (app *App) Wallet() walletcontroller.WalletController {
 	return app.wc
 }
 
+// SetWalletPassphrase verifies and caches passphrase used to unlock the wallet.
+// Zero timeout keeps passphrase cached until restart.
+func (app *App) SetWalletPassphrase(passphrase string, timeout time.Duration) error {
+	if err := app.wc.SetPassphrase(passphrase, timeout); err != nil {
+		return err
+	}
+
+	app.logger.WithFields(logrus.Fields{
+		"timeout": timeout,
+	}).Info("Wallet passphrase set")
+
+	return nil
+}
+
 // BabylonController returns the babylon controller
 func (app *App) BabylonController() cl.BabylonClient {
 	return app.babylonClient
@@ -1342,12 +1675,47 @@ func (app *App) filterUtxoFnGen() walletcontroller.UseUtxoFn {
 	}
 }
 
-// StakeFunds stakes funds to the staker address
+// StakeFunds stakes funds to the staker address. Created delegation is assigned
+// to the given tenant, empty tenant means default tenant. Non empty reference
+// is committed to in OP_RETURN output of the staking transaction. Fee rate of
+// the staking transaction is resolved from fee selection.
 func (app *App) StakeFunds(
 	stakerAddress btcutil.Address,
 	stakingAmount btcutil.Amount,
 	fpPks []*btcec.PublicKey,
 	stakingTimeBlocks uint16,
+	tenant string,
+	reference string,
+	fee FeeSelection,
+) (*chainhash.Hash, error) {
+	if err := app.checkStartupSync(); err != nil {
+		return nil, err
+	}
+
+	var stakingTxHash *chainhash.Hash
+	err := app.requests.run(func() error {
+		var err error
+		stakingTxHash, err = app.stakeFunds(stakerAddress, stakingAmount, fpPks, stakingTimeBlocks, nil, tenant, 0, reference, fee)
+		return err
+	})
+	return stakingTxHash, err
+}
+
+// stakeFunds stakes funds to the staker address. If fundingOutpoint is not nil,
+// staking transaction spends only this outpoint instead of wallet selected ones.
+// Non zero maxFeeRate caps estimated fee rate of the staking transaction.
+// Non empty reference is committed to in OP_RETURN output of the staking
+// transaction. Fee rate is resolved from fee selection.
+func (app *App) stakeFunds(
+	stakerAddress btcutil.Address,
+	stakingAmount btcutil.Amount,
+	fpPks []*btcec.PublicKey,
+	stakingTimeBlocks uint16,
+	fundingOutpoint *wire.OutPoint,
+	tenant string,
+	maxFeeRate chainfee.SatPerKVByte,
+	reference string,
+	fee FeeSelection,
 ) (*chainhash.Hash, error) {
 	// check we are not shutting down
 	select {
@@ -1357,6 +1725,24 @@ func (app *App) StakeFunds(
 	default:
 	}
 
+	if tenant != "" {
+		if err := stakerdb.ValidateTenant(tenant); err != nil {
+			return nil, fmt.Errorf("invalid tenant: %w", err)
+		}
+	}
+
+	if err := app.validateReference(reference); err != nil {
+		return nil, err
+	}
+
+	if err := fee.Validate(); err != nil {
+		return nil, err
+	}
+
+	if reference != "" && fundingOutpoint != nil {
+		return nil, fmt.Errorf("reference can't be committed to in staking transaction spending given outpoint")
+	}
+
 	if len(fpPks) == 0 {
 		return nil, fmt.Errorf("no finality providers public keys provided")
 	}
@@ -1371,24 +1757,29 @@ func (app *App) StakeFunds(
 		}
 	}
 
+	// quota is reserved until delegation is stored, so that concurrent stakes
+	// can't exceed it together
+	releaseQuota, err := app.reserveFpQuota(fpPks, stakingAmount)
+	if err != nil {
+		return nil, err
+	}
+	defer releaseQuota()
+
 	params, err := app.babylonClient.Params()
 	if err != nil {
 		return nil, fmt.Errorf("failed to get params: %w", err)
 	}
 
-	// Allow list is enabled, check if we are past the expiration height and we can
-	// create new delegations
-	if params.AllowListExpirationHeight > 0 {
-		latestBlockHeight, err := app.babylonClient.GetLatestBlockHeight()
+	if err := app.checkStakingGate(params); err != nil {
+		return nil, err
+	}
 
-		if err != nil {
-			return nil, fmt.Errorf("failed to get latest block height: %w", err)
-		}
-		// we add +1 to account for comet bft lazy execution
-		if latestBlockHeight <= params.AllowListExpirationHeight+1 {
-			return nil, fmt.Errorf("allow is enabled, cannot create new delegations. Latest block height %d is before allow list expiration height %d",
-				latestBlockHeight, params.AllowListExpirationHeight)
-		}
+	if err := app.checkChainSafety(); err != nil {
+		return nil, err
+	}
+
+	if err := app.checkMaintenanceWindow(); err != nil {
+		return nil, err
 	}
 
 	slashingFee := app.getSlashingFee(params.MinSlashingTxFeeSat)
@@ -1402,6 +1793,10 @@ func (app *App) StakeFunds(
 			stakingTimeBlocks, params.MinStakingTime, params.MaxStakingTime)
 	}
 
+	if err := app.policy.checkStakingTime(stakingTimeBlocks); err != nil {
+		return nil, err
+	}
+
 	if stakingAmount < params.MinStakingValue || stakingAmount > params.MaxStakingValue {
 		return nil, fmt.Errorf("staking amount %d is not in range [%d, %d]",
 			stakingAmount, params.MinStakingValue, params.MaxStakingValue)
@@ -1432,7 +1827,22 @@ func (app *App) StakeFunds(
 		return nil, fmt.Errorf("failed to build staking info: %w", err)
 	}
 
-	feeRate := app.feeEstimator.EstimateFeePerKb()
+	feeRate, err := app.resolveFeeRate(fee)
+	if err != nil {
+		return nil, err
+	}
+
+	if maxFeeRate > 0 && feeRate > maxFeeRate {
+		if fee.FeeRate > 0 {
+			return nil, fmt.Errorf("fee rate %d sat/vB is above fee rate cap %d sat/vB", fee.FeeRate, maxFeeRate/1000)
+		}
+		feeRate = maxFeeRate
+	}
+
+	if floor := app.feeRateFloor(); feeRate < floor {
+		return nil, fmt.Errorf("fee rate cap %d sat/vB is below min relay fee %d sat/vB of btc node",
+			maxFeeRate/1000, floor/1000)
+	}
 
 	app.logger.WithFields(logrus.Fields{
 		"stakerAddress": stakerAddress,
@@ -1451,6 +1861,18 @@ func (app *App) StakeFunds(
 		pop,
 	)
 
+	if fundingOutpoint != nil {
+		req = req.WithFundingOutpoint(*fundingOutpoint)
+	}
+
+	if tenant != "" {
+		req = req.WithTenant(tenant)
+	}
+
+	if reference != "" {
+		req = req.WithReference(reference)
+	}
+
 	utils.PushOrQuit[*stakingRequestCmd](
 		app.stakingRequestedCmdChan,
 		req,
@@ -1473,13 +1895,40 @@ func (app *App) StakeFunds(
 }
 
 // StakeExpand expands an existing stake by consuming the previous staking UTXO
-// and creating a new larger staking transaction with additional funding
+// and creating a new larger staking transaction with additional funding.
+// If tenant is empty, expanded delegation inherits tenant of the previous one.
 func (app *App) StakeExpand(
 	stakerAddress btcutil.Address,
 	stakingAmount btcutil.Amount,
 	fpPks []*btcec.PublicKey,
 	stakingTimeBlocks uint16,
 	prevActiveStkTxHash *chainhash.Hash,
+	tenant string,
+) (*chainhash.Hash, error) {
+	if err := app.checkFeatureEnabled(scfg.FeatureStakeExpansion); err != nil {
+		return nil, err
+	}
+
+	if err := app.checkStartupSync(); err != nil {
+		return nil, err
+	}
+
+	var stakingTxHash *chainhash.Hash
+	err := app.requests.run(func() error {
+		var err error
+		stakingTxHash, err = app.stakeExpand(stakerAddress, stakingAmount, fpPks, stakingTimeBlocks, prevActiveStkTxHash, tenant)
+		return err
+	})
+	return stakingTxHash, err
+}
+
+func (app *App) stakeExpand(
+	stakerAddress btcutil.Address,
+	stakingAmount btcutil.Amount,
+	fpPks []*btcec.PublicKey,
+	stakingTimeBlocks uint16,
+	prevActiveStkTxHash *chainhash.Hash,
+	tenant string,
 ) (*chainhash.Hash, error) {
 	// check we are not shutting down
 	select {
@@ -1489,6 +1938,18 @@ func (app *App) StakeExpand(
 	default:
 	}
 
+	if tenant != "" {
+		if err := stakerdb.ValidateTenant(tenant); err != nil {
+			return nil, fmt.Errorf("invalid tenant: %w", err)
+		}
+	} else {
+		prevTenant, err := app.txTracker.GetTransactionTenant(prevActiveStkTxHash)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get tenant of previous staking transaction: %w", err)
+		}
+		tenant = prevTenant
+	}
+
 	if len(fpPks) == 0 {
 		return nil, fmt.Errorf("no finality providers public keys provided")
 	}
@@ -1508,19 +1969,16 @@ func (app *App) StakeExpand(
 		return nil, fmt.Errorf("failed to get params: %w", err)
 	}
 
-	// Allow list is enabled, check if we are past the expiration height and we can
-	// create new delegations
-	if params.AllowListExpirationHeight > 0 {
-		latestBlockHeight, err := app.babylonClient.GetLatestBlockHeight()
+	if err := app.checkStakingGate(params); err != nil {
+		return nil, err
+	}
 
-		if err != nil {
-			return nil, fmt.Errorf("failed to get latest block height: %w", err)
-		}
-		// we add +1 to account for comet bft lazy execution
-		if latestBlockHeight <= params.AllowListExpirationHeight+1 {
-			return nil, fmt.Errorf("allow is enabled, cannot create new delegations. Latest block height %d is before allow list expiration height %d",
-				latestBlockHeight, params.AllowListExpirationHeight)
-		}
+	if err := app.checkChainSafety(); err != nil {
+		return nil, err
+	}
+
+	if err := app.checkMaintenanceWindow(); err != nil {
+		return nil, err
 	}
 
 	slashingFee := app.getSlashingFee(params.MinSlashingTxFeeSat)
@@ -1534,6 +1992,10 @@ func (app *App) StakeExpand(
 			stakingTimeBlocks, params.MinStakingTime, params.MaxStakingTime)
 	}
 
+	if err := app.policy.checkStakingTime(stakingTimeBlocks); err != nil {
+		return nil, err
+	}
+
 	if stakingAmount < params.MinStakingValue || stakingAmount > params.MaxStakingValue {
 		return nil, fmt.Errorf("staking amount %d is not in range [%d, %d]",
 			stakingAmount, params.MinStakingValue, params.MaxStakingValue)
@@ -1562,7 +2024,7 @@ func (app *App) StakeExpand(
 		return nil, fmt.Errorf("failed to build staking info: %w", err)
 	}
 
-	feeRate := app.feeEstimator.EstimateFeePerKb()
+	feeRate := app.estimatedFeeRate()
 
 	// Step 1: Get the previous staking amount to calculate additional amount needed
 	prevDelegationResult, err := app.babylonClient.QueryBTCDelegation(prevActiveStkTxHash)
@@ -1600,7 +2062,7 @@ func (app *App) StakeExpand(
 	).WithStakeExpansion(
 		prevActiveStkTxHash,
 		prevDel.StakingOutputIdx,
-	)
+	).WithTenant(tenant)
 
 	utils.PushOrQuit[*stakingRequestCmd](
 		app.stakingRequestedCmdChan,
@@ -1626,6 +2088,10 @@ func (app *App) StakeExpand(
 // ConsolidateUTXOs consolidates UTXOs into a single larger UTXO
 // This is a public method that wraps the internal consolidateUTXOs method
 func (app *App) ConsolidateUTXOs(stakerAddress btcutil.Address, targetAmount int64) (*chainhash.Hash, error) {
+	if err := app.checkStartupSync(); err != nil {
+		return nil, err
+	}
+
 	// check we are not shutting down
 	select {
 	case <-app.quit:
@@ -1645,7 +2111,7 @@ func (app *App) ConsolidateUTXOs(stakerAddress btcutil.Address, targetAmount int
 		PkScript: changeScript,
 	}}
 
-	feeRate := app.feeEstimator.EstimateFeePerKb()
+	feeRate := app.estimatedFeeRate()
 
 	// Create the transaction - WalletController will automatically select the best UTXOs
 	tx, err := app.wc.CreateAndSignTx(outputs, btcutil.Amount(feeRate), stakerAddress, app.filterUtxoFnGen())
@@ -1653,6 +2119,12 @@ func (app *App) ConsolidateUTXOs(stakerAddress btcutil.Address, targetAmount int
 		return nil, fmt.Errorf("failed to create consolidation transaction: %w", err)
 	}
 
+	// wallet selects inputs itself, so transaction can be checked only after
+	// it is signed. It is never broadcast if it violates the policy.
+	if err := app.checkSigningPolicy(tx); err != nil {
+		return nil, err
+	}
+
 	// Send the transaction
 	txHash, err := app.wc.SendRawTransaction(tx, true)
 	if err != nil {
@@ -1676,6 +2148,12 @@ func (app *App) StoredTransactions(limit, offset uint64) (*stakerdb.StoredTransa
 		NumMaxTransactions: limit,
 		Reversed:           false,
 	}
+	return app.QueryStoredTransactions(query)
+}
+
+// QueryStoredTransactions returns a slice of stakerdb.StoredTransaction
+// matching the given query
+func (app *App) QueryStoredTransactions(query stakerdb.StoredTransactionQuery) (*stakerdb.StoredTransactionQueryResult, error) {
 	resp, err := app.txTracker.QueryStoredTransactions(query)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query stored transactions: %w", err)
@@ -1685,9 +2163,14 @@ func (app *App) StoredTransactions(limit, offset uint64) (*stakerdb.StoredTransa
 }
 
 // WithdrawableTransactions returns a slice of stakerdb.StoredTransaction
-// that can be withdrawn
-func (app *App) WithdrawableTransactions(limit, offset uint64) (*stakerdb.StoredTransactionQueryResult, error) {
-	transactions, err := app.StoredTransactions(limit, offset)
+// that can be withdrawn. If tenant is not empty, only transactions of this
+// tenant are returned.
+func (app *App) WithdrawableTransactions(limit, offset uint64, tenant string) (*stakerdb.StoredTransactionQueryResult, error) {
+	transactions, err := app.QueryStoredTransactions(stakerdb.StoredTransactionQuery{
+		IndexOffset:        offset,
+		NumMaxTransactions: limit,
+		Tenant:             tenant,
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to query stored transactions: %w", err)
 	}
@@ -1702,7 +2185,13 @@ func (app *App) WithdrawableTransactions(limit, offset uint64) (*stakerdb.Stored
 		}
 
 		babylonStatus := di.BtcDelegation.GetStatusDesc()
-		stakingConfirmation, _, err := app.Wallet().TxDetails(
+		if babylonStatus == BabylonPendingStatus || babylonStatus == BabylonVerifiedStatus {
+			continue
+		}
+
+		// confirmations are always checked against current best chain, as
+		// stored heights may be stale after reorg
+		stakingConfirmation, stakingStatus, err := app.Wallet().TxDetails(
 			&stakingTxHash,
 			tx.StakingTx.TxOut[di.BtcDelegation.StakingOutputIdx].PkScript,
 		)
@@ -1710,16 +2199,17 @@ func (app *App) WithdrawableTransactions(limit, offset uint64) (*stakerdb.Stored
 			return nil, fmt.Errorf("failed to get staking tx details: %w", err)
 		}
 
-		var scriptTimeLock uint16
-		var confirmationHeight uint32
-
-		switch babylonStatus {
-		case BabylonPendingStatus, BabylonVerifiedStatus:
+		if stakingStatus != walletcontroller.TxInChain || stakingConfirmation.BlockHeight == 0 {
+			// staking transaction was reorged out of the best chain
 			continue
-		case BabylonExpiredStatus:
-			withdrawableTransactions = append(withdrawableTransactions, tx)
-			continue
-		default:
+		}
+
+		// babylon expires delegations before their staking timelock expires on
+		// btc, so staking timelock is checked for expired delegations as well
+		scriptTimeLock := uint16(di.BtcDelegation.StakingTime)
+		confirmationHeight := stakingConfirmation.BlockHeight
+
+		if babylonStatus != BabylonExpiredStatus {
 			udi, err := app.babylonClient.GetUndelegationInfo(di)
 			if err != nil {
 				return nil, fmt.Errorf("failed to get undelegation info: %w", err)
@@ -1735,10 +2225,13 @@ func (app *App) WithdrawableTransactions(limit, offset uint64) (*stakerdb.Stored
 			}
 
 			switch {
+			case unbondingStatus == walletcontroller.TxInMemPool:
+				// staking output is spent by unbonding transaction, e.g.
+				// reorged back to mempool, it can be withdrawn only once
+				// unbonding transaction confirms again
+				continue
 			case unbondingStatus == walletcontroller.TxNotFound, unbondingConfirmation.BlockHash == nil || unbondingConfirmation.BlockHeight == 0:
-				// unbonding transaction is not found
-				scriptTimeLock = uint16(di.BtcDelegation.StakingTime)
-				confirmationHeight = stakingConfirmation.BlockHeight
+				// unbonding transaction is not found, staking timelock applies
 			default:
 				// unbonding transaction is confirmed
 				scriptTimeLock = udi.UnbondingTime
@@ -1770,13 +2263,97 @@ func (app *App) GetStoredTransaction(txHash *chainhash.Hash) (*stakerdb.StoredTr
 	return app.txTracker.GetTransaction(txHash)
 }
 
+// prevOutputs returns outputs spent by inputs of the given transaction, in
+// input order. Input transactions are fetched in batches.
+func (app *App) prevOutputs(tx *wire.MsgTx) ([]*wire.TxOut, error) {
+	txHashes := make([]chainhash.Hash, len(tx.TxIn))
+	for i, in := range tx.TxIn {
+		txHashes[i] = in.PreviousOutPoint.Hash
+	}
+
+	prevTxs, err := app.wc.Txs(txHashes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get input transactions: %w", err)
+	}
+
+	prevOuts := make([]*wire.TxOut, len(tx.TxIn))
+	for i, in := range tx.TxIn {
+		outs := prevTxs[i].MsgTx().TxOut
+		if int(in.PreviousOutPoint.Index) >= len(outs) {
+			return nil, fmt.Errorf("input %s does not exist", in.PreviousOutPoint)
+		}
+
+		prevOuts[i] = outs[in.PreviousOutPoint.Index]
+	}
+
+	return prevOuts, nil
+}
+
+// StakingTxAmounts contains amounts related to a tracked staking transaction
+type StakingTxAmounts struct {
+	StakingAmount btcutil.Amount
+	Fee           btcutil.Amount
+	// UnbondingFee is zero if babylon has not yet received unbonding transaction
+	UnbondingFee btcutil.Amount
+}
+
+// StakingTxAmounts returns the staking amount, fee paid by the staking
+// transaction and unbonding fee of the given tracked transaction
+func (app *App) StakingTxAmounts(
+	storedTx *stakerdb.StoredTransaction,
+	di *btcstypes.QueryBTCDelegationResponse,
+) (*StakingTxAmounts, error) {
+	stakingTx := storedTx.StakingTx
+	stakingOutputIdx := di.BtcDelegation.StakingOutputIdx
+
+	if int(stakingOutputIdx) >= len(stakingTx.TxOut) {
+		return nil, fmt.Errorf("staking output index %d out of range for tx %s",
+			stakingOutputIdx, stakingTx.TxHash())
+	}
+
+	stakingAmount := btcutil.Amount(stakingTx.TxOut[stakingOutputIdx].Value)
+
+	fee, err := app.txFee(stakingTx)
+	if err != nil {
+		return nil, err
+	}
+
+	var unbondingFee btcutil.Amount
+	if di.BtcDelegation.UndelegationResponse != nil {
+		udi, err := app.babylonClient.GetUndelegationInfo(di)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get undelegation info: %w", err)
+		}
+
+		if len(udi.UnbondingTransaction.TxOut) > 0 {
+			unbondingFee = stakingAmount - btcutil.Amount(udi.UnbondingTransaction.TxOut[0].Value)
+		}
+	}
+
+	return &StakingTxAmounts{
+		StakingAmount: stakingAmount,
+		Fee:           fee,
+		UnbondingFee:  unbondingFee,
+	}, nil
+}
+
+// ListReservedOutpoints returns wallet outpoints which are used as inputs by
+// tracked staking transactions and thus cannot be used for new stakes
+func (app *App) ListReservedOutpoints() ([]stakerdb.ReservedOutpoint, error) {
+	return app.txTracker.ListReservedOutpoints()
+}
+
 // ListUnspentOutputs returns a slice of walletcontroller.Utxo
 func (app *App) ListUnspentOutputs() ([]walletcontroller.Utxo, error) {
 	return app.wc.ListOutputs(false)
 }
 
 // waitForSpendConfirmation waits for the staking transaction to be confirmed
-func (app *App) waitForSpendConfirmation(stakingTxHash chainhash.Hash, ev *notifier.ConfirmationEvent) {
+func (app *App) waitForSpendConfirmation(
+	stakingTxHash chainhash.Hash,
+	spendTxValue btcutil.Amount,
+	ev *notifier.ConfirmationEvent,
+) {
 	// check we are not shutting down
 	select {
 	case <-app.quit:
@@ -1792,7 +2369,8 @@ func (app *App) waitForSpendConfirmation(stakingTxHash chainhash.Hash, ev *notif
 		select {
 		case <-ev.Confirmed:
 			stakingEvent := &spendStakeTxConfirmedOnBtcEvent{
-				stakingTxHash,
+				stakingTxHash: stakingTxHash,
+				spendTxValue:  spendTxValue,
 			}
 
 			// transaction which spends staking transaction is confirmed on BTC inform
@@ -1817,14 +2395,20 @@ func (app *App) waitForSpendConfirmation(stakingTxHash chainhash.Hash, ev *notif
 	}
 }
 
-// signTaprootScriptSpendUsingWallet signs a taproot script spend using the wallet
+// signTaprootScriptSpendUsingWallet signs a taproot script spend using the wallet.
+// protocolScripts are staking protocol outputs the transaction may create.
 func (app *App) signTaprootScriptSpendUsingWallet(
 	txToSign *wire.MsgTx,
 	fundingOutput *wire.TxOut,
 	signerAddress btcutil.Address,
 	leaf *txscript.TapLeaf,
 	controlBlock *txscript.ControlBlock,
+	protocolScripts ...[]byte,
 ) (*walletcontroller.TaprootSigningResult, error) {
+	if err := app.policy.checkProtocolSpend(txToSign, []*wire.TxOut{fundingOutput}, protocolScripts...); err != nil {
+		return nil, err
+	}
+
 	if err := app.wc.UnlockWallet(defaultWalletUnlockTimeout); err != nil {
 		return nil, fmt.Errorf("failed to unlock wallet before signing: %w", err)
 	}
@@ -1848,14 +2432,38 @@ func (app *App) signTaprootScriptSpendUsingWallet(
 	return resp, nil
 }
 
+// minSpendValue returns minimum value which must remain in unbonding and
+// withdrawal outputs after paying fees
+func (app *App) minSpendValue() btcutil.Amount {
+	return btcutil.Amount(app.config.StakerConfig.MinSpendValue)
+}
+
 // SpendStake spends stake identified by stakingTxHash. Stake can be currently locked in
 // two types of outputs:
 // 1. Staking output - this is output which is created by staking transaction
 // 2. Unbonding output - this is output which is created by unbonding transaction, if user requested
 // unbonding of his stake.
 // We find in which type of output stake is locked by checking state of staking transaction, and build
-// proper spend transaction based on that state.
-func (app *App) SpendStake(stakingTxHash *chainhash.Hash) (*chainhash.Hash, *btcutil.Amount, error) {
+// proper spend transaction based on that state. Fee rate of the spend transaction is resolved
+// from fee selection.
+func (app *App) SpendStake(stakingTxHash *chainhash.Hash, fee FeeSelection) (*chainhash.Hash, *btcutil.Amount, error) {
+	if err := app.checkStartupSync(); err != nil {
+		return nil, nil, err
+	}
+
+	var (
+		spendTxHash  *chainhash.Hash
+		spendTxValue *btcutil.Amount
+	)
+	err := app.requests.run(func() error {
+		var err error
+		spendTxHash, spendTxValue, err = app.spendStake(stakingTxHash, fee)
+		return err
+	})
+	return spendTxHash, spendTxValue, err
+}
+
+func (app *App) spendStake(stakingTxHash *chainhash.Hash, fee FeeSelection) (*chainhash.Hash, *btcutil.Amount, error) {
 	// check we are not shutting down
 	select {
 	case <-app.quit:
@@ -1873,49 +2481,73 @@ func (app *App) SpendStake(stakingTxHash *chainhash.Hash) (*chainhash.Hash, *btc
 	// this coud happen if we stared staker on wrong network.
 	// TODO: consider storing data for different networks in different folders
 	// to avoid this
-	// Currently we spend funds from staking transaction to the same address. This
-	// could be improved by allowing user to specify destination address, although
-	// this destination address would need to control the expcted priv key to sign
-	// transaction
-	destAddress, err := btcutil.DecodeAddress(tx.StakerAddress, app.network)
+	stakerAddress, err := app.decodeStakerAddress(tx)
 
 	if err != nil {
 		return nil, nil, fmt.Errorf("cannot spend staking output. Error decoding staker address: %w", err)
 	}
 
+	// staker address key is still required to sign the spend, only the output
+	// follows configured withdrawal address policy
+	destAddress, err := app.withdrawalAddress(stakerAddress)
+
+	if err != nil {
+		return nil, nil, fmt.Errorf("cannot spend staking output. %w", err)
+	}
+
+	spendStakeTxInfo, err := app.buildSpendStakeTx(stakingTxHash, tx, stakerAddress, destAddress, fee)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return app.sendSpendStakeTx(stakingTxHash, spendStakeTxInfo, stakerAddress, destAddress)
+}
+
+// buildSpendStakeTx builds and signs transaction spending staking output, or
+// unbonding output if delegation was unbonded, to the destination address
+func (app *App) buildSpendStakeTx(
+	stakingTxHash *chainhash.Hash,
+	storedTx *stakerdb.StoredTransaction,
+	stakerAddress btcutil.Address,
+	destAddress btcutil.Address,
+	fee FeeSelection,
+) (*spendStakeTxInfo, error) {
 	destAddressScript, err := txscript.PayToAddrScript(destAddress)
 
 	if err != nil {
-		return nil, nil, fmt.Errorf("cannot spend staking output. Cannot built destination script: %w", err)
+		return nil, fmt.Errorf("cannot spend staking output. Cannot built destination script: %w", err)
 	}
 
 	params, err := app.babylonClient.Params()
 
 	if err != nil {
-		return nil, nil, fmt.Errorf("cannot spend staking output. Error getting params: %w", err)
+		return nil, fmt.Errorf("cannot spend staking output. Error getting params: %w", err)
 	}
 
-	pubKey, err := app.wc.AddressPublicKey(destAddress)
+	pubKey, err := app.wc.AddressPublicKey(stakerAddress)
 
 	if err != nil {
-		return nil, nil, fmt.Errorf("cannot spend staking output. Error getting private key: %w", err)
+		return nil, fmt.Errorf("cannot spend staking output. Error getting private key: %w", err)
 	}
 
-	currentFeeRate := app.feeEstimator.EstimateFeePerKb()
+	currentFeeRate, err := app.resolveFeeRate(fee)
+	if err != nil {
+		return nil, fmt.Errorf("cannot spend staking output. %w", err)
+	}
 
 	di, err := app.babylonClient.QueryBTCDelegation(stakingTxHash)
 	if err != nil {
-		return nil, nil, fmt.Errorf("cannot spend staking output. Error getting delegation info: %w", err)
+		return nil, fmt.Errorf("cannot spend staking output. Error getting delegation info: %w", err)
 	}
 
 	udi, err := app.babylonClient.GetUndelegationInfo(di)
 	if err != nil {
-		return nil, nil, fmt.Errorf("cannot spend staking output. Error getting undelegation info: %w", err)
+		return nil, fmt.Errorf("cannot spend staking output. Error getting undelegation info: %w", err)
 	}
 
 	fpBtcPubkeys, err := convertFpBtcPkToBtcPk(di.BtcDelegation.FpBtcPkList)
 	if err != nil {
-		return nil, nil, fmt.Errorf("cannot spend staking output. Error converting fpBtcPkList to btcPkList: %w", err)
+		return nil, fmt.Errorf("cannot spend staking output. Error converting fpBtcPkList to btcPkList: %w", err)
 	}
 
 	// Since we have already verified that the transaction is ACTIVE
@@ -1927,15 +2559,17 @@ func (app *App) SpendStake(stakingTxHash *chainhash.Hash) (*chainhash.Hash, *btc
 		udi.UnbondingTransaction.TxOut[0].PkScript,
 	)
 	if err != nil {
-		return nil, nil, fmt.Errorf("cannot spend staking output. Error getting confirmation info from btc: %w", err)
+		return nil, fmt.Errorf("cannot spend staking output. Error getting confirmation info from btc: %w", err)
 	}
 
-	if confirmation == nil {
-		return nil, nil, fmt.Errorf("cannot spend staking output. Tx status: %s", txStatus.String())
+	// unbonding transaction which was never sent is not found, staking output
+	// of expired delegation is then spent through the timelock path
+	if confirmation == nil && txStatus != walletcontroller.TxNotFound {
+		return nil, fmt.Errorf("cannot spend staking output. Tx status: %s", txStatus.String())
 	}
 
 	var spendStakeTxInfo *spendStakeTxInfo
-	if confirmation.BlockHash != nil && confirmation.BlockHeight > 0 {
+	if confirmation != nil && confirmation.BlockHash != nil && confirmation.BlockHeight > 0 {
 		unbondingConfirmedTxInfo, err := createSpendStakeTxUnbondingConfirmed(
 			pubKey,
 			fpBtcPubkeys,
@@ -1943,11 +2577,12 @@ func (app *App) SpendStake(stakingTxHash *chainhash.Hash) (*chainhash.Hash, *btc
 			params.CovenantQuruomThreshold,
 			destAddressScript,
 			currentFeeRate,
+			app.minSpendValue(),
 			udi,
 			app.network,
 		)
 		if err != nil {
-			return nil, nil, fmt.Errorf("cannot spend staking output. Error creating spend stake unbonding confirmed tx: %w", err)
+			return nil, fmt.Errorf("cannot spend staking output. Error creating spend stake unbonding confirmed tx: %w", err)
 		}
 		spendStakeTxInfo = unbondingConfirmedTxInfo
 	} else {
@@ -1958,13 +2593,14 @@ func (app *App) SpendStake(stakingTxHash *chainhash.Hash) (*chainhash.Hash, *btc
 			fpBtcPubkeys,
 			params.CovenantPks,
 			params.CovenantQuruomThreshold,
-			tx,
+			storedTx,
 			destAddressScript,
 			currentFeeRate,
+			app.minSpendValue(),
 			app.network,
 		)
 		if err != nil {
-			return nil, nil, fmt.Errorf("cannot spend staking output. Error creating spend stake unbonding confirmed tx: %w", err)
+			return nil, fmt.Errorf("cannot spend staking output. Error creating spend stake unbonding confirmed tx: %w", err)
 		}
 		spendStakeTxInfo = unbondingNotConfirmedTxInfo
 	}
@@ -1972,25 +2608,37 @@ func (app *App) SpendStake(stakingTxHash *chainhash.Hash) (*chainhash.Hash, *btc
 	stakerSig, err := app.signTaprootScriptSpendUsingWallet(
 		spendStakeTxInfo.spendStakeTx,
 		spendStakeTxInfo.fundingOutput,
-		destAddress,
+		stakerAddress,
 		&spendStakeTxInfo.fundingOutputSpendInfo.RevealedLeaf,
 		&spendStakeTxInfo.fundingOutputSpendInfo.ControlBlock,
 	)
 
 	if err != nil {
-		return nil, nil, fmt.Errorf("cannot spend staking output. Error building signature: %w", err)
+		return nil, fmt.Errorf("cannot spend staking output. Error building signature: %w", err)
 	}
 
 	if stakerSig.FullInputWitness == nil {
-		return nil, nil, fmt.Errorf("failed to recevie full witness to spend staking transactions")
+		return nil, fmt.Errorf("failed to recevie full witness to spend staking transactions")
 	}
 
 	if err != nil {
-		return nil, nil, fmt.Errorf("cannot spend staking output. Error building witness: %w", err)
+		return nil, fmt.Errorf("cannot spend staking output. Error building witness: %w", err)
 	}
 
 	spendStakeTxInfo.spendStakeTx.TxIn[0].Witness = stakerSig.FullInputWitness
 
+	return spendStakeTxInfo, nil
+}
+
+// sendSpendStakeTx broadcasts signed transaction spending staking or unbonding
+// output and waits for its confirmation in the background
+func (app *App) sendSpendStakeTx(
+	stakingTxHash *chainhash.Hash,
+	spendStakeTxInfo *spendStakeTxInfo,
+	stakerAddress btcutil.Address,
+	destAddress btcutil.Address,
+) (*chainhash.Hash, *btcutil.Amount, error) {
+
 	// We do not check if transaction is spendable i.e the staking time has passed
 	// as this is validated in mempool so in of not meeting this time requirement
 	// we will receive error here: `transaction's sequence locks on inputs not met`
@@ -2000,6 +2648,9 @@ func (app *App) SpendStake(stakingTxHash *chainhash.Hash) (*chainhash.Hash, *btc
 		return nil, nil, fmt.Errorf("cannot spend staking output. Error sending tx: %w", err)
 	}
 
+	app.watchMempoolTx(stakingTxHash, stakerdb.WatchedWithdrawalTx, spendStakeTxInfo.spendStakeTx)
+	app.recordPaidFee(stakingTxHash, stakerdb.WatchedWithdrawalTx, spendStakeTxInfo.calculatedFee)
+
 	spendTxValue := btcutil.Amount(spendStakeTxInfo.spendStakeTx.TxOut[0].Value)
 
 	app.logger.WithFields(logrus.Fields{
@@ -2007,7 +2658,7 @@ func (app *App) SpendStake(stakingTxHash *chainhash.Hash) (*chainhash.Hash, *btc
 		"spendTxHash":   spendTxHash,
 		"spendTxValue":  spendTxValue,
 		"fee":           spendStakeTxInfo.calculatedFee,
-		"stakerAddress": destAddress,
+		"stakerAddress": stakerAddress,
 		"destAddress":   destAddress,
 	}).Infof("Successfully sent transaction spending staking output")
 
@@ -2027,7 +2678,10 @@ func (app *App) SpendStake(stakingTxHash *chainhash.Hash) (*chainhash.Hash, *btc
 	// tx which will spend this staking output concurrently. In that case the first one
 	// confirmed on btc networks which will mark our staking transaction as spent on BTC network.
 	// TODO: we can reconsider this approach in the future.
-	go app.waitForSpendConfirmation(*stakingTxHash, confEvent)
+	spentStakingTxHash := *stakingTxHash
+	app.startTask("spend_confirmation", func() {
+		app.waitForSpendConfirmation(spentStakingTxHash, spendTxValue, confEvent)
+	})
 
 	return spendTxHash, &spendTxValue, nil
 }
@@ -2045,9 +2699,29 @@ func (app *App) ListActiveFinalityProviders(limit uint64, offset uint64) (*cl.Fi
 // covenant and finality provider
 // 5. After gathering all signatures, unbonding transaction is sent to bitcoin
 // This function returns control to the caller after step 3. Later is up to the caller
-// to check what is state of unbonding transaction
+// to check what is state of unbonding transaction. Fee of unbonding transaction is
+// fixed by Babylon, so non zero fee selection only rejects unbonding paying lower fee rate.
 func (app *App) UnbondStaking(
-	stakingTxHash chainhash.Hash) (*chainhash.Hash, error) {
+	stakingTxHash chainhash.Hash, fee FeeSelection) (*chainhash.Hash, error) {
+	if err := app.checkStartupSync(); err != nil {
+		return nil, err
+	}
+
+	var unbondingTxHash *chainhash.Hash
+	err := app.requests.run(func() error {
+		var err error
+		unbondingTxHash, err = app.unbondStaking(stakingTxHash, fee)
+		return err
+	})
+	if err == nil {
+		// delegation state changed, do not serve stale status
+		app.statuses.remove(stakingTxHash)
+	}
+	return unbondingTxHash, err
+}
+
+func (app *App) unbondStaking(
+	stakingTxHash chainhash.Hash, fee FeeSelection) (*chainhash.Hash, error) {
 	// check we are not shutting down
 	select {
 	case <-app.quit:
@@ -2071,9 +2745,39 @@ func (app *App) UnbondStaking(
 		return nil, fmt.Errorf("cannot unbond transaction which is not active")
 	}
 
-	stakerAddress, err := btcutil.DecodeAddress(tx.StakerAddress, app.network)
+	if !fee.IsZero() {
+		if err := app.checkUnbondingFeeRate(tx, di, fee); err != nil {
+			return nil, err
+		}
+	}
+
+	unbondingTxHash, err := app.startUnbonding(&stakingTxHash, tx, di)
+	if err != nil {
+		return nil, err
+	}
+
+	// unbonding tx is sent in background, request is persisted so that
+	// unbonding which never reaches btc is detected
+	if err := app.txTracker.SetUnbondRequested(&stakingTxHash, time.Now()); err != nil {
+		app.logger.WithFields(logrus.Fields{
+			"stakingTxHash": stakingTxHash,
+			"err":           err,
+		}).Error("Failed to record unbond request")
+	}
+
+	return unbondingTxHash, nil
+}
+
+// startUnbonding starts sending unbonding tx of active delegation to btc in
+// background and returns its hash
+func (app *App) startUnbonding(
+	stakingTxHash *chainhash.Hash,
+	tx *stakerdb.StoredTransaction,
+	di *btcstypes.QueryBTCDelegationResponse,
+) (*chainhash.Hash, error) {
+	stakerAddress, err := app.decodeStakerAddress(tx)
 	if err != nil {
-		return nil, fmt.Errorf("error decoding staker address: %s. Err: %w", tx.StakerAddress, err)
+		return nil, fmt.Errorf("error decoding staker address: %w", err)
 	}
 
 	fpBtcPubkeys, err := convertFpBtcPkToBtcPk(di.BtcDelegation.FpBtcPkList)
@@ -2086,17 +2790,33 @@ func (app *App) UnbondStaking(
 		return nil, fmt.Errorf("failed to get undelegation info from babylon: %w", err)
 	}
 
+	if len(undelegationInfo.UnbondingTransaction.TxOut) == 0 {
+		return nil, fmt.Errorf("unbonding transaction has no outputs")
+	}
+
+	// unbonding output is withdrawn later, check it is still worth it after
+	// paying the withdrawal fee. Withdrawal output is assumed to be of the size
+	// of the unbonding output, as destination is not known yet.
+	unbondingOutput := undelegationInfo.UnbondingTransaction.TxOut[0]
+	withdrawalFee := spendStakeTxFee(unbondingOutput, app.estimatedFeeRate())
+	if err := utils.CheckSpendOutput("unbonding", unbondingOutput, app.minSpendValue()+withdrawalFee); err != nil {
+		return nil, fmt.Errorf("cannot unbond: %w. expected withdrawal fee: %d", err, withdrawalFee)
+	}
+
 	// TODO: Move this to event handler to avoid somebody starting multiple unbonding routines
-	app.wg.Add(1)
-	go app.sendUnbondingTxToBtcTask(
-		&stakingTxHash,
-		stakerAddress,
-		di.BtcDelegation.StakingOutputIdx,
-		uint16(di.BtcDelegation.StakingTime),
-		tx,
-		fpBtcPubkeys,
-		undelegationInfo,
-	)
+	stakingOutputIdx := di.BtcDelegation.StakingOutputIdx
+	stakingTime := uint16(di.BtcDelegation.StakingTime)
+	app.startTask("send_unbonding", func() {
+		app.sendUnbondingTxToBtcTask(
+			stakingTxHash,
+			stakerAddress,
+			stakingOutputIdx,
+			stakingTime,
+			tx,
+			fpBtcPubkeys,
+			undelegationInfo,
+		)
+	})
 
 	unbondingTxHash := undelegationInfo.UnbondingTransaction.TxHash()
 	return &unbondingTxHash, nil
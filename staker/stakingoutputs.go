@@ -0,0 +1,156 @@
+package staker
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+
+	scfg "github.com/babylonlabs-io/btc-staker/stakercfg"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/btcutil/txsort"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+)
+
+// maxReferenceLen is maximum length of internal reference committed to in
+// staking transaction
+const maxReferenceLen = 256
+
+// ReferenceCommitment returns commitment to internal reference embedded in
+// OP_RETURN output of staking transaction. Only the hash is published, so
+// the reference itself, e.g. customer id, stays private.
+func ReferenceCommitment(reference string) []byte {
+	h := sha256.Sum256([]byte(reference))
+	return h[:]
+}
+
+// validateReference checks internal reference of stake request and that its
+// commitment fits into OP_RETURN output together with configured marker
+func (app *App) validateReference(reference string) error {
+	if len(reference) > maxReferenceLen {
+		return fmt.Errorf("reference is longer than %d characters", maxReferenceLen)
+	}
+
+	if reference == "" {
+		return nil
+	}
+
+	marker, err := app.config.StakerConfig.StakingTxMarkerData()
+	if err != nil {
+		return err
+	}
+
+	if len(marker)+scfg.ReferenceCommitmentSize > txscript.MaxDataCarrierSize {
+		return fmt.Errorf("reference commitment does not fit into OP_RETURN output with %d bytes long marker", len(marker))
+	}
+
+	return nil
+}
+
+// stakingTxOpReturn returns OP_RETURN output of staking transaction, made of
+// configured marker followed by commitment to the reference. Returns nil if
+// there is neither marker nor reference.
+func (app *App) stakingTxOpReturn(reference string) (*wire.TxOut, error) {
+	data, err := app.config.StakerConfig.StakingTxMarkerData()
+	if err != nil {
+		return nil, err
+	}
+
+	if reference != "" {
+		data = append(data, ReferenceCommitment(reference)...)
+	}
+
+	if len(data) == 0 {
+		return nil, nil
+	}
+
+	// standard transactions have at most one OP_RETURN output
+	if len(data) > txscript.MaxDataCarrierSize {
+		return nil, fmt.Errorf("staking transaction marker and reference commitment have %d bytes, at most %d are standard",
+			len(data), txscript.MaxDataCarrierSize)
+	}
+
+	script, err := txscript.NullDataScript(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build OP_RETURN output: %w", err)
+	}
+
+	return wire.NewTxOut(0, script), nil
+}
+
+// stakingTxExtraScripts returns scripts of extra outputs added to staking
+// transactions. Signing policy allows them only in staking transactions, so
+// that other transactions can't pay to them.
+func (app *App) stakingTxExtraScripts() ([][]byte, error) {
+	extraOutputs, err := app.config.StakerConfig.ParsedStakingTxExtraOutputs(app.network)
+	if err != nil {
+		return nil, err
+	}
+
+	scripts := make([][]byte, 0, len(extraOutputs))
+	for _, out := range extraOutputs {
+		scripts = append(scripts, out.PkScript)
+	}
+
+	return scripts, nil
+}
+
+// createStakingTx funds staking transaction from the wallet. Extra outputs
+// configured by the operator and OP_RETURN output with marker and reference
+// commitment are added after the staking output, and outputs are ordered
+// according to configured output order. Returns transaction and index of its
+// staking output.
+func (app *App) createStakingTx(
+	stakingOutput *wire.TxOut,
+	feeRate btcutil.Amount,
+	changeAddress btcutil.Address,
+	reference string,
+) (*wire.MsgTx, uint32, error) {
+	extraOutputs, err := app.config.StakerConfig.ParsedStakingTxExtraOutputs(app.network)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	outputs := append([]*wire.TxOut{stakingOutput}, extraOutputs...)
+
+	opReturn, err := app.stakingTxOpReturn(reference)
+	if err != nil {
+		return nil, 0, err
+	}
+	if opReturn != nil {
+		outputs = append(outputs, opReturn)
+	}
+
+	stakingTx, err := app.wc.CreateTransaction(
+		outputs,
+		feeRate,
+		changeAddress,
+		app.filterUtxoFnGen(),
+	)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if app.config.StakerConfig.StakingOutputOrder == scfg.OutputOrderBIP69 {
+		txsort.InPlaceSort(stakingTx)
+	}
+
+	stakingOutputIdx, err := findOutput(stakingTx, stakingOutput)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return stakingTx, stakingOutputIdx, nil
+}
+
+// findOutput returns index of the first output of the transaction equal to
+// given output
+func findOutput(tx *wire.MsgTx, output *wire.TxOut) (uint32, error) {
+	for i, out := range tx.TxOut {
+		if out.Value == output.Value && bytes.Equal(out.PkScript, output.PkScript) {
+			return uint32(i), nil
+		}
+	}
+
+	return 0, fmt.Errorf("transaction %s does not contain staking output", tx.TxHash())
+}
@@ -0,0 +1,168 @@
+package staker
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/babylonlabs-io/btc-staker/stakercfg"
+	"github.com/btcsuite/btcd/btcutil/txsort"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindOutputAfterBIP69Sort(t *testing.T) {
+	t.Parallel()
+
+	stakingOutput := wire.NewTxOut(100_000, policyTestScript(t))
+	extraOutput := wire.NewTxOut(10_000, policyTestScript(t))
+	changeOutput := wire.NewTxOut(50_000, policyTestScript(t))
+	// same value as staking output, differs only by script
+	sameValueOutput := wire.NewTxOut(100_000, policyTestScript(t))
+
+	tx := wire.NewMsgTx(2)
+	tx.AddTxIn(wire.NewTxIn(wire.NewOutPoint(&chainhash.Hash{2}, 1), nil, nil))
+	tx.AddTxIn(wire.NewTxIn(wire.NewOutPoint(&chainhash.Hash{1}, 0), nil, nil))
+	tx.AddTxOut(stakingOutput)
+	tx.AddTxOut(extraOutput)
+	tx.AddTxOut(sameValueOutput)
+	tx.AddTxOut(changeOutput)
+
+	txsort.InPlaceSort(tx)
+	require.True(t, txsort.IsSorted(tx))
+
+	idx, err := findOutput(tx, stakingOutput)
+	require.NoError(t, err)
+	require.Equal(t, stakingOutput.Value, tx.TxOut[idx].Value)
+	require.Equal(t, stakingOutput.PkScript, tx.TxOut[idx].PkScript)
+
+	_, err = findOutput(tx, wire.NewTxOut(100_000, policyTestScript(t)))
+	require.ErrorContains(t, err, "does not contain staking output")
+}
+
+func TestReferenceCommitment(t *testing.T) {
+	t.Parallel()
+
+	commitment := ReferenceCommitment("customer-1")
+	require.Len(t, commitment, stakercfg.ReferenceCommitmentSize)
+
+	expected := sha256.Sum256([]byte("customer-1"))
+	require.Equal(t, expected[:], commitment)
+	require.Equal(t, commitment, ReferenceCommitment("customer-1"))
+	require.NotEqual(t, commitment, ReferenceCommitment("customer-2"))
+}
+
+func TestStakingTxOpReturn(t *testing.T) {
+	t.Parallel()
+
+	maxMarker := strings.Repeat("ab", txscript.MaxDataCarrierSize-stakercfg.ReferenceCommitmentSize)
+
+	tests := []struct {
+		name      string
+		marker    string
+		reference string
+		// expected OP_RETURN data, nil if no output is expected
+		expected []byte
+	}{
+		{
+			name: "no marker and no reference",
+		},
+		{
+			name:     "marker only",
+			marker:   "6175646974",
+			expected: []byte("audit"),
+		},
+		{
+			name:      "reference only",
+			reference: "customer-1",
+			expected:  ReferenceCommitment("customer-1"),
+		},
+		{
+			name:      "marker followed by reference commitment",
+			marker:    "6175646974",
+			reference: "customer-1",
+			expected:  append([]byte("audit"), ReferenceCommitment("customer-1")...),
+		},
+		{
+			name:      "longest marker with reference commitment",
+			marker:    maxMarker,
+			reference: "customer-1",
+			expected: func() []byte {
+				marker, err := hex.DecodeString(maxMarker)
+				require.NoError(t, err)
+				return append(marker, ReferenceCommitment("customer-1")...)
+			}(),
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := stakercfg.DefaultConfig()
+			cfg.StakerConfig.StakingTxMarker = tc.marker
+			app := &App{config: &cfg}
+
+			out, err := app.stakingTxOpReturn(tc.reference)
+			require.NoError(t, err)
+
+			if tc.expected == nil {
+				require.Nil(t, out)
+				return
+			}
+
+			require.Equal(t, int64(0), out.Value)
+			require.Equal(t, txscript.NullDataTy, txscript.GetScriptClass(out.PkScript))
+
+			// marker and commitment share single push of single output
+			pushes, err := txscript.PushedData(out.PkScript)
+			require.NoError(t, err)
+			require.Len(t, pushes, 1)
+			require.Equal(t, tc.expected, pushes[0])
+		})
+	}
+}
+
+func TestValidateReference(t *testing.T) {
+	t.Parallel()
+
+	maxMarker := strings.Repeat("ab", txscript.MaxDataCarrierSize-stakercfg.ReferenceCommitmentSize)
+
+	tests := []struct {
+		name      string
+		marker    string
+		reference string
+		wantErr   string
+	}{
+		{name: "no reference", marker: maxMarker + "ab"},
+		{name: "reference without marker", reference: "customer-1"},
+		{name: "reference with longest marker", marker: maxMarker, reference: "customer-1"},
+		{
+			name:      "reference too long",
+			reference: strings.Repeat("a", maxReferenceLen+1),
+			wantErr:   "reference is longer than",
+		},
+		{
+			name:      "marker leaving no room for commitment",
+			marker:    maxMarker + "ab",
+			reference: "customer-1",
+			wantErr:   "at most 48 fit with reference commitment",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := stakercfg.DefaultConfig()
+			cfg.StakerConfig.StakingTxMarker = tc.marker
+			app := &App{config: &cfg}
+
+			err := app.validateReference(tc.reference)
+			if tc.wantErr != "" {
+				require.ErrorContains(t, err, tc.wantErr)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,184 @@
+package staker
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// startupSyncLogInterval is how often reconciliation progress is logged
+const startupSyncLogInterval = 10 * time.Second
+
+// ErrStartupSyncInProgress is returned for operations changing delegations
+// while stored delegations are still being reconciled after start
+var ErrStartupSyncInProgress = errors.New("startup reconciliation is in progress")
+
+// StartupSyncStatus describes progress of reconciliation of stored delegations
+// with Babylon and btc chain, which runs on start
+type StartupSyncStatus struct {
+	Done       bool
+	Reconciled uint64
+	Total      uint64
+	StartedAt  time.Time
+	// CompletedAt is zero until reconciliation is done
+	CompletedAt time.Time
+	// Error is set if reconciliation failed
+	Error string
+}
+
+type startupSync struct {
+	mu     sync.RWMutex
+	status StartupSyncStatus
+	err    error
+	// done is closed once reconciliation finishes, successfully or not
+	done    chan struct{}
+	lastLog time.Time
+}
+
+func newStartupSync() *startupSync {
+	return &startupSync{
+		done: make(chan struct{}),
+	}
+}
+
+// StartupSyncStatus returns progress of startup reconciliation
+func (app *App) StartupSyncStatus() StartupSyncStatus {
+	app.startup.mu.RLock()
+	defer app.startup.mu.RUnlock()
+
+	return app.startup.status
+}
+
+// StartupSyncDone returns channel which is closed once startup reconciliation
+// finishes
+func (app *App) StartupSyncDone() <-chan struct{} {
+	return app.startup.done
+}
+
+// StartupSyncErr returns error of failed startup reconciliation
+func (app *App) StartupSyncErr() error {
+	app.startup.mu.RLock()
+	defer app.startup.mu.RUnlock()
+
+	return app.startup.err
+}
+
+// checkStartupSync returns ErrStartupSyncInProgress until startup
+// reconciliation is successfully done, and ErrReadOnlyReplica on replica
+func (app *App) checkStartupSync() error {
+	// replica stores delegations of the primary, which are changed only by
+	// the primary
+	if app.isReplica() {
+		return ErrReadOnlyReplica
+	}
+
+	status := app.StartupSyncStatus()
+	if status.Done && status.Error == "" {
+		return nil
+	}
+
+	if status.Error != "" {
+		return fmt.Errorf("startup reconciliation failed: %s", status.Error)
+	}
+
+	return fmt.Errorf("%w: reconciled %d of %d delegations",
+		ErrStartupSyncInProgress, status.Reconciled, status.Total)
+}
+
+// runStartupSync reconciles stored delegations and starts background loops
+// which rely on reconciled state. Read only requests are served meanwhile.
+func (app *App) runStartupSync() {
+	defer app.wg.Done()
+
+	app.startup.mu.Lock()
+	app.startup.status.StartedAt = time.Now()
+	app.startup.mu.Unlock()
+
+	err := app.checkTransactionsStatus()
+
+	app.startup.mu.Lock()
+	app.startup.status.Done = true
+	app.startup.status.CompletedAt = time.Now()
+	if err != nil {
+		app.startup.err = err
+		app.startup.status.Error = err.Error()
+	}
+	status := app.startup.status
+	app.startup.mu.Unlock()
+	close(app.startup.done)
+
+	if err != nil {
+		app.logger.WithFields(logrus.Fields{
+			"err": err,
+		}).Error("Startup reconciliation failed")
+		return
+	}
+
+	app.logger.WithFields(logrus.Fields{
+		"reconciled": status.Reconciled,
+		"duration":   status.CompletedAt.Sub(status.StartedAt).Round(time.Millisecond),
+	}).Info("Startup reconciliation completed")
+
+	select {
+	case <-app.quit:
+		return
+	default:
+	}
+
+	app.startWorker(reservationCleanupWorker, app.handleReservationCleanup)
+	app.startNewBlockWorker(watchedTransactionsWorker, app.checkWatchedTransactions,
+		"Failed to check mempool status of broadcast transactions")
+	app.startNewBlockWorker(spendableStakesWorker, app.notifySpendableStakes,
+		"Failed to check spendable heights of unbonded delegations")
+	app.startNewBlockWorker(autoRenewWorker, app.renewExpiredDelegations,
+		"Failed to renew expired delegations")
+	app.startWorker("status_refresh", app.handleDelegationStatusRefresh)
+	app.startWorker("stuck_delegations", app.handleStuckDelegations)
+	app.startWorker("chain_safety", app.handleChainSafety)
+
+	if app.StakeQueueEnabled() || app.MaintenanceWindowsEnabled() {
+		app.startWorker("stake_queue", app.handleStakeQueue)
+	}
+}
+
+// setStartupSyncTotal records number of delegations to reconcile
+func (app *App) setStartupSyncTotal(total int) {
+	app.startup.mu.Lock()
+	app.startup.status.Total = uint64(total)
+	app.startup.lastLog = time.Now()
+	app.startup.mu.Unlock()
+
+	app.m.StartupSyncTotal.Set(float64(total))
+	app.m.StartupSyncReconciled.Set(0)
+
+	app.logger.WithFields(logrus.Fields{
+		"delegations": total,
+	}).Info("Reconciling stored delegations")
+}
+
+// startupSyncReconciled records that another delegation was reconciled and
+// periodically logs the progress
+func (app *App) startupSyncReconciled() {
+	now := time.Now()
+
+	app.startup.mu.Lock()
+	app.startup.status.Reconciled++
+	reconciled, total := app.startup.status.Reconciled, app.startup.status.Total
+	logProgress := now.Sub(app.startup.lastLog) >= startupSyncLogInterval
+	if logProgress {
+		app.startup.lastLog = now
+	}
+	app.startup.mu.Unlock()
+
+	app.m.StartupSyncReconciled.Set(float64(reconciled))
+
+	if logProgress {
+		app.logger.WithFields(logrus.Fields{
+			"reconciled": reconciled,
+			"total":      total,
+		}).Info("Reconciling stored delegations")
+	}
+}
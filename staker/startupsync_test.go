@@ -0,0 +1,198 @@
+package staker
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	btcstypes "github.com/babylonlabs-io/babylon/v4/x/btcstaking/types"
+	cl "github.com/babylonlabs-io/btc-staker/babylonclient"
+	"github.com/babylonlabs-io/btc-staker/metrics"
+	"github.com/babylonlabs-io/btc-staker/stakercfg"
+	"github.com/babylonlabs-io/btc-staker/stakerdb"
+	"github.com/babylonlabs-io/btc-staker/testutil/mocks"
+	"github.com/babylonlabs-io/btc-staker/walletcontroller"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func newStartupSyncTestApp(
+	t *testing.T,
+	bc *mocks.MockBabylonClient,
+	wc *mocks.MockWalletController,
+) (*App, *stakerdb.TrackedTransactionStore) {
+	cfg := stakercfg.DefaultConfig()
+	store := newArchiveTestStore(t)
+
+	return &App{
+		config:        &cfg,
+		logger:        logrus.New(),
+		txTracker:     store,
+		babylonClient: bc,
+		wc:            wc,
+		m:             metrics.NewStakerMetrics(),
+		statuses:      newDelegationStatusCache(),
+		startup:       &startupSync{},
+	}, store
+}
+
+func delegationResponse(status string, stakingOutputIdx uint32) *btcstypes.QueryBTCDelegationResponse {
+	return &btcstypes.QueryBTCDelegationResponse{
+		BtcDelegation: &btcstypes.BTCDelegationResponse{
+			StatusDesc:       status,
+			StakingOutputIdx: stakingOutputIdx,
+		},
+	}
+}
+
+func TestCheckTransactionsStatusReconcilesActiveDelegations(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	bc := mocks.NewMockBabylonClient(ctrl)
+	wc := mocks.NewMockWalletController(ctrl)
+	app, store := newStartupSyncTestApp(t, bc, wc)
+
+	var hashes []chainhash.Hash
+	for _, value := range []int64{10_000, 20_000, 30_000} {
+		tx := genReplicaTestTransaction(t, value)
+		require.NoError(t, store.AddTransactionSentToBabylon(tx.StakingTx, tx.StakerAddress))
+		hashes = append(hashes, tx.StakingTx.TxHash())
+	}
+	active, withdrawn, unbonded := hashes[0], hashes[1], hashes[2]
+
+	activeResp := delegationResponse(BabylonActiveStatus, 0)
+	withdrawnResp := delegationResponse(BabylonActiveStatus, 1)
+	bc.EXPECT().QueryBTCDelegation(&active).Return(activeResp, nil)
+	bc.EXPECT().QueryBTCDelegation(&withdrawn).Return(withdrawnResp, nil)
+	bc.EXPECT().QueryBTCDelegation(&unbonded).Return(delegationResponse("UNBONDED", 0), nil)
+
+	activeUnbonding := genReplicaTestTransaction(t, 9_000).StakingTx
+	withdrawnUnbonding := genReplicaTestTransaction(t, 19_000).StakingTx
+	bc.EXPECT().GetUndelegationInfo(activeResp).Return(&cl.UndelegationInfo{UnbondingTransaction: activeUnbonding}, nil)
+	bc.EXPECT().GetUndelegationInfo(withdrawnResp).Return(&cl.UndelegationInfo{UnbondingTransaction: withdrawnUnbonding}, nil)
+
+	// staking outputs of active delegations are checked in one batch
+	wc.EXPECT().OutputsSpent([]wire.OutPoint{
+		{Hash: active, Index: 0},
+		{Hash: withdrawn, Index: 1},
+	}).Return([]bool{false, true}, nil)
+
+	// spent staking output without unbonding tx on chain is withdrawn
+	withdrawnUnbondingHash := withdrawnUnbonding.TxHash()
+	wc.EXPECT().TxDetails(&withdrawnUnbondingHash, withdrawnUnbonding.TxOut[0].PkScript).
+		Return(nil, walletcontroller.TxNotFound, nil)
+
+	require.NoError(t, app.checkTransactionsStatus())
+
+	_, err := store.GetTransaction(&active)
+	require.NoError(t, err)
+	_, err = store.GetTransaction(&withdrawn)
+	require.ErrorIs(t, err, stakerdb.ErrTransactionNotFound)
+
+	status := app.StartupSyncStatus()
+	require.Equal(t, uint64(3), status.Total)
+	require.Equal(t, uint64(3), status.Reconciled)
+}
+
+func TestCheckTransactionsStatusFailsOnBabylonError(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	bc := mocks.NewMockBabylonClient(ctrl)
+	wc := mocks.NewMockWalletController(ctrl)
+	app, store := newStartupSyncTestApp(t, bc, wc)
+
+	tx := genReplicaTestTransaction(t, 10_000)
+	require.NoError(t, store.AddTransactionSentToBabylon(tx.StakingTx, tx.StakerAddress))
+
+	bc.EXPECT().QueryBTCDelegation(gomock.Any()).Return(nil, errors.New("babylon unavailable"))
+
+	err := app.checkTransactionsStatus()
+	require.ErrorContains(t, err, "babylon unavailable")
+
+	status := app.StartupSyncStatus()
+	require.Equal(t, uint64(1), status.Total)
+	require.Zero(t, status.Reconciled)
+}
+
+func TestStartupSyncGatesChangesUntilReconciled(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	bc := mocks.NewMockBabylonClient(ctrl)
+	wc := mocks.NewMockWalletController(ctrl)
+	app, store := newStartupSyncTestApp(t, bc, wc)
+	app.startup = newStartupSync()
+	app.quit = make(chan struct{})
+
+	tx := genReplicaTestTransaction(t, 10_000)
+	require.NoError(t, store.AddTransactionSentToBabylon(tx.StakingTx, tx.StakerAddress))
+	txHash := tx.StakingTx.TxHash()
+
+	release := make(chan struct{})
+	bc.EXPECT().QueryBTCDelegation(&txHash).DoAndReturn(
+		func(_ *chainhash.Hash) (*btcstypes.QueryBTCDelegationResponse, error) {
+			<-release
+			return delegationResponse("UNBONDED", 0), nil
+		},
+	)
+
+	app.wg.Add(1)
+	go app.runStartupSync()
+
+	require.Eventually(t, func() bool {
+		return app.StartupSyncStatus().Total == 1
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// changes are refused while delegations are reconciled, reads are served
+	err := app.checkStartupSync()
+	require.ErrorIs(t, err, ErrStartupSyncInProgress)
+	require.ErrorContains(t, err, "reconciled 0 of 1 delegations")
+	_, err = app.GetStoredTransaction(&txHash)
+	require.NoError(t, err)
+	require.False(t, app.StartupSyncStatus().Done)
+
+	// background workers are not started once app quits
+	close(app.quit)
+	close(release)
+	<-app.StartupSyncDone()
+	app.wg.Wait()
+
+	require.NoError(t, app.checkStartupSync())
+	require.NoError(t, app.StartupSyncErr())
+	status := app.StartupSyncStatus()
+	require.True(t, status.Done)
+	require.Equal(t, uint64(1), status.Reconciled)
+	require.False(t, status.CompletedAt.Before(status.StartedAt))
+}
+
+func TestStartupSyncFailure(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	bc := mocks.NewMockBabylonClient(ctrl)
+	wc := mocks.NewMockWalletController(ctrl)
+	app, store := newStartupSyncTestApp(t, bc, wc)
+	app.startup = newStartupSync()
+	app.quit = make(chan struct{})
+
+	tx := genReplicaTestTransaction(t, 10_000)
+	require.NoError(t, store.AddTransactionSentToBabylon(tx.StakingTx, tx.StakerAddress))
+	bc.EXPECT().QueryBTCDelegation(gomock.Any()).Return(nil, errors.New("babylon unavailable"))
+
+	app.wg.Add(1)
+	app.runStartupSync()
+
+	select {
+	case <-app.StartupSyncDone():
+	default:
+		t.Fatal("startup sync not done after failure")
+	}
+	require.ErrorContains(t, app.StartupSyncErr(), "babylon unavailable")
+	require.ErrorContains(t, app.checkStartupSync(), "startup reconciliation failed")
+	require.True(t, app.StartupSyncStatus().Done)
+}
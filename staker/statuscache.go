@@ -0,0 +1,252 @@
+package staker
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	btcstypes "github.com/babylonlabs-io/babylon/v4/x/btcstaking/types"
+	cl "github.com/babylonlabs-io/btc-staker/babylonclient"
+	"github.com/babylonlabs-io/btc-staker/stakerdb"
+	"github.com/babylonlabs-io/btc-staker/walletcontroller"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/sirupsen/logrus"
+)
+
+// DelegationStatus is snapshot of delegation state on Babylon and btc, taken
+// by background refresher so that RPC reads do not wait on Babylon or btc node
+type DelegationStatus struct {
+	Delegation *btcstypes.QueryBTCDelegationResponse
+	// Amounts is nil if they could not be computed, AmountsErr holds the reason
+	Amounts    *StakingTxAmounts
+	AmountsErr error
+	// ConfirmationHeight is zero if staking transaction is not confirmed or
+	// its status is unknown
+	ConfirmationHeight uint32
+	RefreshedAt        time.Time
+	// snapshotAt is the time status was last persisted to status history,
+	// zero if it was not persisted yet
+	snapshotAt time.Time
+	// quorumRecorded is true once time of covenant quorum was persisted
+	quorumRecorded bool
+	// confirmedRecorded and activeRecorded are true once time of btc
+	// confirmation and activation were persisted
+	confirmedRecorded bool
+	activeRecorded    bool
+}
+
+// State returns Babylon status of the delegation
+func (s *DelegationStatus) State() string {
+	return s.Delegation.BtcDelegation.GetStatusDesc()
+}
+
+// final returns true if delegation status can no longer change
+func (s *DelegationStatus) final() bool {
+	state := s.State()
+	return (state == BabylonUnbondedStatus || state == BabylonExpiredStatus) && s.AmountsErr == nil
+}
+
+type delegationStatusCache struct {
+	mu       sync.RWMutex
+	statuses map[chainhash.Hash]*DelegationStatus
+}
+
+func newDelegationStatusCache() *delegationStatusCache {
+	return &delegationStatusCache{
+		statuses: make(map[chainhash.Hash]*DelegationStatus),
+	}
+}
+
+func (c *delegationStatusCache) get(stakingTxHash chainhash.Hash) (*DelegationStatus, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	status, ok := c.statuses[stakingTxHash]
+	return status, ok
+}
+
+func (c *delegationStatusCache) set(stakingTxHash chainhash.Hash, status *DelegationStatus) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.statuses[stakingTxHash] = status
+}
+
+func (c *delegationStatusCache) remove(stakingTxHash chainhash.Hash) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.statuses, stakingTxHash)
+}
+
+// snapshot returns copy of the cached statuses
+func (c *delegationStatusCache) snapshot() map[chainhash.Hash]*DelegationStatus {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	statuses := make(map[chainhash.Hash]*DelegationStatus, len(c.statuses))
+	for hash, status := range c.statuses {
+		statuses[hash] = status
+	}
+	return statuses
+}
+
+// DelegationStatus returns cached status of the delegation. Status of
+// delegation seen for the first time is loaded synchronously, afterwards it is
+// kept up to date by background refresher. Stored transaction may come from
+// query returning only staking transaction hash.
+func (app *App) DelegationStatus(storedTx *stakerdb.StoredTransaction) (*DelegationStatus, error) {
+	stakingTxHash := storedTx.StakingTxHash
+
+	if status, ok := app.statuses.get(stakingTxHash); ok {
+		return status, nil
+	}
+
+	if storedTx.StakingTx == nil {
+		fullTx, err := app.txTracker.GetTransaction(&stakingTxHash)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get stored transaction: %w", err)
+		}
+		storedTx = fullTx
+	}
+
+	status, err := app.loadDelegationStatus(storedTx)
+	if err != nil {
+		return nil, err
+	}
+
+	app.statuses.set(stakingTxHash, status)
+	return status, nil
+}
+
+// loadDelegationStatus queries Babylon and btc node for the current status of
+// the delegation
+func (app *App) loadDelegationStatus(storedTx *stakerdb.StoredTransaction) (*DelegationStatus, error) {
+	stakingTxHash := storedTx.StakingTx.TxHash()
+
+	di, err := app.babylonClient.QueryBTCDelegation(&stakingTxHash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query delegation info from babylon: %w", err)
+	}
+
+	status := &DelegationStatus{
+		Delegation:  di,
+		RefreshedAt: time.Now(),
+	}
+
+	status.Amounts, status.AmountsErr = app.StakingTxAmounts(storedTx, di)
+
+	stakingOutputIdx := di.BtcDelegation.StakingOutputIdx
+	if int(stakingOutputIdx) < len(storedTx.StakingTx.TxOut) {
+		confirmation, txStatus, err := app.wc.TxDetails(
+			&stakingTxHash,
+			storedTx.StakingTx.TxOut[stakingOutputIdx].PkScript,
+		)
+		if err != nil {
+			app.logger.WithFields(logrus.Fields{
+				"stakingTxHash": stakingTxHash,
+				"err":           err,
+			}).Debug("Failed to get staking transaction confirmation")
+		} else if txStatus == walletcontroller.TxInChain && confirmation != nil {
+			status.ConfirmationHeight = confirmation.BlockHeight
+		}
+	}
+
+	return status, nil
+}
+
+// handleDelegationStatusRefresh periodically refreshes cached status of all
+// tracked delegations
+func (app *App) handleDelegationStatusRefresh() {
+	ticker := time.NewTicker(app.config.StakerConfig.StatusRefreshInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := app.refreshDelegationStatuses(); err != nil {
+			app.logger.WithFields(logrus.Fields{
+				"err": err,
+			}).Error("Failed to refresh delegation statuses")
+		}
+
+		select {
+		case <-ticker.C:
+		case <-app.quit:
+			return
+		}
+	}
+}
+
+// refreshDelegationStatuses refreshes cached statuses of tracked delegations.
+// Transactions are listed by hash only, so that staking transactions of
+// delegations in final state, which are skipped, are not deserialized.
+func (app *App) refreshDelegationStatuses() error {
+	query := stakerdb.DefaultStoredTransactionQuery()
+	query.NumMaxTransactions = math.MaxUint64
+	query.StakingTxHashOnly = true
+
+	result, err := app.txTracker.QueryStoredTransactions(query)
+	if err != nil {
+		return fmt.Errorf("failed to query stored transactions: %w", err)
+	}
+
+	confirmationDepth := app.activationConfirmationDepth()
+
+	var awaitingCovenants int
+	for i := range result.Transactions {
+		select {
+		case <-app.quit:
+			return nil
+		default:
+		}
+
+		stakingTxHash := result.Transactions[i].StakingTxHash
+
+		cached, ok := app.statuses.get(stakingTxHash)
+		if ok && cached.final() && !cached.snapshotAt.IsZero() {
+			continue
+		}
+
+		storedTx, err := app.txTracker.GetTransaction(&stakingTxHash)
+		if errors.Is(err, stakerdb.ErrTransactionNotFound) {
+			// deleted since it was listed
+			app.statuses.remove(stakingTxHash)
+			continue
+		}
+		if err != nil {
+			app.logger.WithFields(logrus.Fields{
+				"stakingTxHash": stakingTxHash,
+				"err":           err,
+			}).Warn("Failed to get stored transaction, keeping last known status")
+			continue
+		}
+
+		status, err := app.loadDelegationStatus(storedTx)
+		if errors.Is(err, cl.ErrDelegationNotFound) {
+			// delegation was not sent to Babylon yet
+			app.statuses.remove(stakingTxHash)
+			continue
+		}
+		if err != nil {
+			app.logger.WithFields(logrus.Fields{
+				"stakingTxHash": stakingTxHash,
+				"err":           err,
+			}).Warn("Failed to refresh delegation status, keeping last known status")
+			continue
+		}
+
+		app.snapshotDelegationStatus(&stakingTxHash, status, cached)
+		app.observeCovenantQuorum(&stakingTxHash, status, cached)
+		app.observeActivationPhases(&stakingTxHash, status, cached, confirmationDepth)
+		app.statuses.set(stakingTxHash, status)
+
+		if status.State() == BabylonPendingStatus {
+			awaitingCovenants++
+		}
+	}
+
+	app.m.DelegationsAwaitingCovenants.Set(float64(awaitingCovenants))
+
+	return nil
+}
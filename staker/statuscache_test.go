@@ -0,0 +1,45 @@
+package staker
+
+import (
+	"testing"
+
+	"github.com/babylonlabs-io/btc-staker/stakercfg"
+	"github.com/babylonlabs-io/btc-staker/stakerdb"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStatusInvalidatedOnTransition(t *testing.T) {
+	t.Parallel()
+
+	cfg := stakercfg.DefaultConfig()
+	store := newArchiveTestStore(t)
+	app := &App{
+		config:    &cfg,
+		logger:    logrus.New(),
+		txTracker: store,
+		statuses:  newDelegationStatusCache(),
+	}
+
+	spent := genReplicaTestTransaction(t, 10_000)
+	require.NoError(t, store.AddTransactionSentToBabylon(spent.StakingTx, spent.StakerAddress))
+	spentHash := spent.StakingTx.TxHash()
+
+	failed := genReplicaTestTransaction(t, 20_000)
+	require.NoError(t, store.AddTransactionSentToBabylon(failed.StakingTx, failed.StakerAddress))
+	failedHash := failed.StakingTx.TxHash()
+
+	app.statuses.set(spentHash, &DelegationStatus{})
+	app.statuses.set(failedHash, &DelegationStatus{})
+
+	// broadcast of withdrawal transaction
+	withdrawalTx := genReplicaTestTransaction(t, 9_000).StakingTx
+	app.watchMempoolTx(&spentHash, stakerdb.WatchedWithdrawalTx, withdrawalTx)
+	_, ok := app.statuses.get(spentHash)
+	require.False(t, ok)
+
+	note := newFailureNote(stakerdb.FailureSourceStaker, "cancelled by user", "")
+	require.NoError(t, app.failStakingTx(&failedHash, note))
+	_, ok = app.statuses.get(failedHash)
+	require.False(t, ok)
+}
@@ -0,0 +1,52 @@
+package staker
+
+import (
+	"github.com/babylonlabs-io/btc-staker/stakerdb"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/sirupsen/logrus"
+)
+
+// snapshotDelegationStatus persists refreshed status of the delegation to its
+// status history. Status is persisted when it differs from the previous one or
+// when the previous snapshot is older than snapshot interval, so that
+// transitions which happen while daemon is offline are bounded by the last
+// persisted observation.
+func (app *App) snapshotDelegationStatus(stakingTxHash *chainhash.Hash, status, prev *DelegationStatus) {
+	if prev != nil && !prev.snapshotAt.IsZero() && prev.State() == status.State() &&
+		status.RefreshedAt.Sub(prev.snapshotAt) < app.config.StakerConfig.StatusSnapshotInterval {
+		status.snapshotAt = prev.snapshotAt
+		return
+	}
+
+	btcDel := status.Delegation.BtcDelegation
+	transition, err := app.txTracker.RecordStatusSnapshot(stakingTxHash, &stakerdb.StatusSnapshot{
+		State:       status.State(),
+		FirstSeenAt: status.RefreshedAt,
+		BtcHeight:   app.currentBestBlockHeight.Load(),
+		StartHeight: btcDel.StartHeight,
+		EndHeight:   btcDel.EndHeight,
+	})
+	if err != nil {
+		// retried on the next refresh
+		app.logger.WithFields(logrus.Fields{
+			"stakingTxHash": stakingTxHash,
+			"err":           err,
+		}).Warn("Failed to record delegation status snapshot")
+		return
+	}
+
+	status.snapshotAt = status.RefreshedAt
+
+	if transition {
+		app.logger.WithFields(logrus.Fields{
+			"stakingTxHash": stakingTxHash,
+			"state":         status.State(),
+		}).Debug("Recorded delegation status transition")
+	}
+}
+
+// DelegationStatusHistory returns persisted Babylon statuses of the
+// delegation, oldest first
+func (app *App) DelegationStatusHistory(stakingTxHash *chainhash.Hash) ([]stakerdb.StatusSnapshot, error) {
+	return app.txTracker.GetStatusHistory(stakingTxHash)
+}
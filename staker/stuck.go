@@ -0,0 +1,295 @@
+package staker
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/babylonlabs-io/btc-staker/stakerdb"
+	"github.com/babylonlabs-io/btc-staker/walletcontroller"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/sirupsen/logrus"
+)
+
+// Conditions of stuck delegations
+const (
+	StuckNoCovenantQuorum      = "no_covenant_quorum"
+	StuckNotVerified           = "not_verified"
+	StuckNotActive             = "not_active"
+	StuckUnbondingNotBroadcast = "unbonding_not_broadcast"
+)
+
+// StuckDelegation is a delegation which stays in intermediate state for longer
+// than configured threshold
+type StuckDelegation struct {
+	StakingTxHash chainhash.Hash
+	// Babylon state of the delegation
+	State     string
+	Condition string
+	Reason    string
+	// Since is the time delegation entered the state, zero if not known
+	Since      time.Time
+	DetectedAt time.Time
+	// RemediationAttempts is the number of automatic remediation attempts
+	// since delegation got stuck, RemediationError is the error of the last
+	// one
+	RemediationAttempts uint32
+	RemediationError    string
+	// remediationDone is set when remediation succeeded or failed with error
+	// which is not worth retrying
+	remediationDone bool
+}
+
+type stuckDelegations struct {
+	mu          sync.RWMutex
+	delegations map[chainhash.Hash]*StuckDelegation
+}
+
+func newStuckDelegations() *stuckDelegations {
+	return &stuckDelegations{
+		delegations: make(map[chainhash.Hash]*StuckDelegation),
+	}
+}
+
+// StuckDelegations returns delegations detected stuck by the last check,
+// oldest first
+func (app *App) StuckDelegations() []StuckDelegation {
+	app.stuck.mu.RLock()
+	defer app.stuck.mu.RUnlock()
+
+	stuck := make([]StuckDelegation, 0, len(app.stuck.delegations))
+	for _, d := range app.stuck.delegations {
+		stuck = append(stuck, *d)
+	}
+
+	sort.Slice(stuck, func(i, j int) bool {
+		return stuck[i].DetectedAt.Before(stuck[j].DetectedAt)
+	})
+
+	return stuck
+}
+
+// handleStuckDelegations periodically checks cached delegation statuses for
+// delegations stuck in intermediate states
+func (app *App) handleStuckDelegations() {
+	ticker := time.NewTicker(app.config.StakerConfig.StuckCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := app.checkStuckDelegations(); err != nil {
+				app.logger.WithFields(logrus.Fields{
+					"err": err,
+				}).Error("Failed to check for stuck delegations")
+			}
+		case <-app.quit:
+			return
+		}
+	}
+}
+
+func (app *App) checkStuckDelegations() error {
+	query := stakerdb.DefaultStoredTransactionQuery()
+	query.NumMaxTransactions = math.MaxUint64
+	query.StakingTxHashOnly = true
+
+	result, err := app.txTracker.QueryStoredTransactions(query)
+	if err != nil {
+		return fmt.Errorf("failed to query stored transactions: %w", err)
+	}
+
+	var unbondRequests map[chainhash.Hash]time.Time
+	if app.config.StakerConfig.StuckUnbondingTimeout > 0 {
+		unbondRequests, err = app.txTracker.ListUnbondRequests()
+		if err != nil {
+			return err
+		}
+	}
+
+	now := time.Now()
+	current := make(map[chainhash.Hash]*StuckDelegation)
+
+	for i := range result.Transactions {
+		stakingTxHash := result.Transactions[i].StakingTxHash
+
+		// statuses are refreshed in background, delegations not sent to
+		// babylon yet have no status
+		status, ok := app.statuses.get(stakingTxHash)
+		if !ok {
+			continue
+		}
+
+		condition, reason, since := app.stuckReason(&stakingTxHash, status, unbondRequests, now)
+		if condition == "" {
+			continue
+		}
+
+		current[stakingTxHash] = &StuckDelegation{
+			StakingTxHash: stakingTxHash,
+			State:         status.State(),
+			Condition:     condition,
+			Reason:        reason,
+			Since:         since,
+			DetectedAt:    now,
+		}
+	}
+
+	app.stuck.mu.Lock()
+	previous := app.stuck.delegations
+	for hash, d := range current {
+		if prev, ok := previous[hash]; ok && prev.State == d.State {
+			d.DetectedAt = prev.DetectedAt
+		}
+		if prev, ok := previous[hash]; ok && prev.Condition == d.Condition {
+			d.RemediationAttempts = prev.RemediationAttempts
+			d.RemediationError = prev.RemediationError
+			d.remediationDone = prev.remediationDone
+		}
+	}
+	app.stuck.delegations = current
+	app.stuck.mu.Unlock()
+
+	app.m.StuckDelegations.Set(float64(len(current)))
+
+	for hash, d := range current {
+		if prev, ok := previous[hash]; ok && prev.State == d.State {
+			continue
+		}
+
+		app.logger.WithFields(logrus.Fields{
+			"stakingTxHash": hash,
+			"state":         d.State,
+			"reason":        d.Reason,
+		}).Warn("Delegation is stuck")
+		app.recordDelegationStuck(&hash, true, d.Reason)
+	}
+
+	for hash := range previous {
+		if _, ok := current[hash]; ok {
+			continue
+		}
+
+		app.logger.WithFields(logrus.Fields{
+			"stakingTxHash": hash,
+		}).Info("Delegation is no longer stuck")
+		app.recordDelegationStuck(&hash, false, "")
+	}
+
+	app.remediateStuckDelegations()
+
+	return nil
+}
+
+// stuckReason returns condition and description of why delegation is stuck
+// and since when it is in its current state, empty condition if it is not
+// stuck
+func (app *App) stuckReason(
+	stakingTxHash *chainhash.Hash,
+	status *DelegationStatus,
+	unbondRequests map[chainhash.Hash]time.Time,
+	now time.Time,
+) (string, string, time.Time) {
+	cfg := app.config.StakerConfig
+
+	switch status.State() {
+	case BabylonPendingStatus:
+		if cfg.StuckConfirmedBlocks > 0 && status.ConfirmationHeight > 0 {
+			currentHeight := app.currentBestBlockHeight.Load()
+			if currentHeight >= status.ConfirmationHeight {
+				confirmations := currentHeight - status.ConfirmationHeight + 1
+				if confirmations > cfg.StuckConfirmedBlocks {
+					return StuckNotVerified,
+						fmt.Sprintf("staking transaction has %d confirmations on btc, but delegation is not verified on babylon", confirmations),
+						app.stateSince(stakingTxHash, BabylonPendingStatus)
+				}
+			}
+		}
+
+		if cfg.StuckPendingThreshold > 0 {
+			since := app.stateSince(stakingTxHash, BabylonPendingStatus)
+			if !since.IsZero() && now.Sub(since) > cfg.StuckPendingThreshold {
+				return StuckNoCovenantQuorum,
+					fmt.Sprintf("no quorum of covenant signatures for %s", now.Sub(since).Round(time.Minute)),
+					since
+			}
+		}
+	case BabylonVerifiedStatus:
+		if cfg.StuckVerifiedThreshold > 0 {
+			since := app.stateSince(stakingTxHash, BabylonVerifiedStatus)
+			if !since.IsZero() && now.Sub(since) > cfg.StuckVerifiedThreshold {
+				return StuckNotActive,
+					fmt.Sprintf("delegation has quorum of covenant signatures for %s, but is not active", now.Sub(since).Round(time.Minute)),
+					since
+			}
+		}
+	case BabylonActiveStatus:
+		requestedAt, ok := unbondRequests[*stakingTxHash]
+		if ok && cfg.StuckUnbondingTimeout > 0 && now.Sub(requestedAt) > cfg.StuckUnbondingTimeout &&
+			app.unbondingTxMissing(status) {
+			return StuckUnbondingNotBroadcast,
+				fmt.Sprintf("unbonding was requested %s ago, but unbonding transaction is neither in mempool nor on btc chain", now.Sub(requestedAt).Round(time.Minute)),
+				requestedAt
+		}
+	}
+
+	return "", "", time.Time{}
+}
+
+// unbondingTxMissing returns true if btc node knows neither about unbonding
+// transaction of the delegation in mempool nor on chain
+func (app *App) unbondingTxMissing(status *DelegationStatus) bool {
+	if status.Delegation.BtcDelegation.UndelegationResponse == nil {
+		return false
+	}
+
+	undelegationInfo, err := app.babylonClient.GetUndelegationInfo(status.Delegation)
+	if err != nil || len(undelegationInfo.UnbondingTransaction.TxOut) == 0 {
+		return false
+	}
+
+	unbondingTxHash := undelegationInfo.UnbondingTransaction.TxHash()
+	_, txStatus, err := app.wc.TxDetails(&unbondingTxHash, undelegationInfo.UnbondingTransaction.TxOut[0].PkScript)
+	if err != nil {
+		return false
+	}
+
+	return txStatus == walletcontroller.TxNotFound
+}
+
+// stateSince returns time at which delegation entered given state. Pending
+// delegations are pending since registration on Babylon, other states are
+// looked up in status history. Returns zero time if it is not known.
+func (app *App) stateSince(stakingTxHash *chainhash.Hash, state string) time.Time {
+	if state == BabylonPendingStatus {
+		timing, err := app.txTracker.GetCovenantQuorumTiming(stakingTxHash)
+		if err == nil && timing != nil && !timing.RegisteredAt.IsZero() {
+			return timing.RegisteredAt
+		}
+	}
+
+	history, err := app.txTracker.GetStatusHistory(stakingTxHash)
+	if err != nil || len(history) == 0 {
+		return time.Time{}
+	}
+
+	last := history[len(history)-1]
+	if last.State != state {
+		return time.Time{}
+	}
+
+	return last.FirstSeenAt
+}
+
+// recordDelegationStuck notifies changelog subscribers. Stuck delegations are
+// detected again on the next check, so failure is only logged.
+func (app *App) recordDelegationStuck(stakingTxHash *chainhash.Hash, stuck bool, reason string) {
+	if err := app.txTracker.RecordDelegationStuck(stakingTxHash, stuck, reason); err != nil {
+		app.logger.WithFields(logrus.Fields{
+			"stakingTxHash": stakingTxHash,
+			"err":           err,
+		}).Error("Failed to record stuck delegation")
+	}
+}
@@ -0,0 +1,231 @@
+package staker
+
+import (
+	"errors"
+	"fmt"
+
+	scfg "github.com/babylonlabs-io/btc-staker/stakercfg"
+	"github.com/babylonlabs-io/btc-staker/stakerdb"
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// TemplateSourceConfig is the source of templates defined in config
+	TemplateSourceConfig = "config"
+	// TemplateSourceAPI is the source of templates created through the API
+	TemplateSourceAPI = "api"
+)
+
+// ErrConfigTemplate is returned when template defined in config is modified
+// through the API
+var ErrConfigTemplate = errors.New("template is defined in config")
+
+// DelegationTemplate is a delegation template together with its source
+type DelegationTemplate struct {
+	scfg.DelegationTemplate
+	Source string
+}
+
+// configTemplates returns templates defined in config. Config was validated
+// at startup, so parse errors are unexpected.
+func (app *App) configTemplates() ([]scfg.DelegationTemplate, error) {
+	if app.config.TemplatesConfig == nil {
+		return nil, nil
+	}
+
+	return app.config.TemplatesConfig.Parse(app.network)
+}
+
+// Template returns template with given name. Templates defined in config take
+// precedence over templates created through the API.
+func (app *App) Template(name string) (*DelegationTemplate, error) {
+	configTemplates, err := app.configTemplates()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, t := range configTemplates {
+		if t.Name == name {
+			return &DelegationTemplate{DelegationTemplate: t, Source: TemplateSourceConfig}, nil
+		}
+	}
+
+	t, err := app.txTracker.GetTemplate(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return &DelegationTemplate{DelegationTemplate: *t, Source: TemplateSourceAPI}, nil
+}
+
+// ListTemplates returns templates defined in config followed by templates
+// created through the API
+func (app *App) ListTemplates() ([]DelegationTemplate, error) {
+	configTemplates, err := app.configTemplates()
+	if err != nil {
+		return nil, err
+	}
+
+	storedTemplates, err := app.txTracker.ListTemplates()
+	if err != nil {
+		return nil, err
+	}
+
+	templates := make([]DelegationTemplate, 0, len(configTemplates)+len(storedTemplates))
+	for _, t := range configTemplates {
+		templates = append(templates, DelegationTemplate{DelegationTemplate: t, Source: TemplateSourceConfig})
+	}
+	for _, t := range storedTemplates {
+		templates = append(templates, DelegationTemplate{DelegationTemplate: t, Source: TemplateSourceAPI})
+	}
+
+	return templates, nil
+}
+
+// SetTemplate creates or replaces template. Templates defined in config cannot
+// be replaced.
+func (app *App) SetTemplate(template *scfg.DelegationTemplate) error {
+	if err := template.Validate(app.network); err != nil {
+		return fmt.Errorf("invalid template: %w", err)
+	}
+
+	if template.Tenant != "" {
+		if err := stakerdb.ValidateTenant(template.Tenant); err != nil {
+			return fmt.Errorf("invalid template tenant: %w", err)
+		}
+	}
+
+	if err := app.checkNotConfigTemplate(template.Name); err != nil {
+		return err
+	}
+
+	return app.txTracker.PutTemplate(template)
+}
+
+// DeleteTemplate deletes template created through the API
+func (app *App) DeleteTemplate(name string) error {
+	if err := app.checkNotConfigTemplate(name); err != nil {
+		return err
+	}
+
+	return app.txTracker.DeleteTemplate(name)
+}
+
+func (app *App) checkNotConfigTemplate(name string) error {
+	configTemplates, err := app.configTemplates()
+	if err != nil {
+		return err
+	}
+
+	for _, t := range configTemplates {
+		if t.Name == name {
+			return fmt.Errorf("%w: %s", ErrConfigTemplate, name)
+		}
+	}
+
+	return nil
+}
+
+// StakeFromTemplate stakes given amount using finality providers, staking
+// time, fee rate cap and tenant of the template. Staker address of the
+// template is used if stakerAddress is nil. Created delegation is labeled with
+// template labels. Non empty reference is committed to in OP_RETURN output of
+// the staking transaction. Fee rate resolved from fee selection is still
+// capped by the template.
+func (app *App) StakeFromTemplate(
+	name string,
+	stakerAddress btcutil.Address,
+	stakingAmount btcutil.Amount,
+	reference string,
+	fee FeeSelection,
+) (*chainhash.Hash, error) {
+	if err := app.checkStartupSync(); err != nil {
+		return nil, err
+	}
+
+	template, stakerAddress, fpPks, err := app.resolveTemplate(name, stakerAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	maxFeeRate := chainfee.SatPerKVByte(template.MaxFeeRate * 1000)
+
+	var stakingTxHash *chainhash.Hash
+	err = app.requests.run(func() error {
+		var err error
+		stakingTxHash, err = app.stakeFunds(
+			stakerAddress,
+			stakingAmount,
+			fpPks,
+			template.StakingTime,
+			nil,
+			template.Tenant,
+			maxFeeRate,
+			reference,
+			fee,
+		)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	app.recordLabels(stakingTxHash, template.Labels)
+
+	return stakingTxHash, nil
+}
+
+// resolveTemplate returns template with given name together with staker
+// address and finality provider keys to stake with
+func (app *App) resolveTemplate(
+	name string,
+	stakerAddress btcutil.Address,
+) (*DelegationTemplate, btcutil.Address, []*btcec.PublicKey, error) {
+	template, err := app.Template(name)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	if stakerAddress == nil {
+		if template.StakerAddress == "" {
+			return nil, nil, nil, fmt.Errorf("template %s has no staker address and none was provided", name)
+		}
+
+		stakerAddress, err = btcutil.DecodeAddress(template.StakerAddress, app.network)
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("error decoding template staker address: %w", err)
+		}
+	}
+
+	fpPks, err := template.FinalityProviderKeys()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	return template, stakerAddress, fpPks, nil
+}
+
+// TransactionLabels returns labels of tracked transaction
+func (app *App) TransactionLabels(stakingTxHash *chainhash.Hash) (map[string]string, error) {
+	return app.txTracker.GetTransactionLabels(stakingTxHash)
+}
+
+// recordLabels stores labels of the delegation. Delegation is already sent at
+// this point, so failure is only logged.
+func (app *App) recordLabels(stakingTxHash *chainhash.Hash, labels map[string]string) {
+	if stakingTxHash == nil || len(labels) == 0 {
+		return
+	}
+
+	if err := app.txTracker.SetTransactionLabels(stakingTxHash, labels); err != nil {
+		app.logger.WithFields(logrus.Fields{
+			"stakingTxHash": stakingTxHash,
+			"labels":        labels,
+			"err":           err,
+		}).Error("Failed to record staking transaction labels")
+	}
+}
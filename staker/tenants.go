@@ -0,0 +1,11 @@
+package staker
+
+import (
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+)
+
+// TransactionTenant returns tenant of tracked transaction. Transactions which
+// were never assigned to a tenant belong to stakerdb.DefaultTenant.
+func (app *App) TransactionTenant(stakingTxHash *chainhash.Hash) (string, error) {
+	return app.txTracker.GetTransactionTenant(stakingTxHash)
+}
@@ -189,6 +189,15 @@ func createDelegationData(
 	return &dg
 }
 
+// spendStakeTxFee returns fee of transaction spending staking or unbonding
+// output to given output
+func spendStakeTxFee(output *wire.TxOut, feeRate chainfee.SatPerKVByte) btcutil.Amount {
+	// transaction have 1 P2TR input and does not have any change
+	txSize := txsizes.EstimateVirtualSize(0, 1, 0, 0, []*wire.TxOut{output}, 0)
+
+	return txrules.FeeForSerializeSize(btcutil.Amount(feeRate), txSize)
+}
+
 // createSpendStakeTx creates a spend stake transaction.
 func createSpendStakeTx(
 	destinationScript []byte,
@@ -197,6 +206,7 @@ func createSpendStakeTx(
 	fundingTxHash *chainhash.Hash,
 	lockTime uint16,
 	feeRate chainfee.SatPerKVByte,
+	minValue btcutil.Amount,
 ) (*wire.MsgTx, *btcutil.Amount, error) {
 	newOutput := wire.NewTxOut(fundingOutput.Value, destinationScript)
 
@@ -209,15 +219,12 @@ func createSpendStakeTx(
 	spendTx.AddTxIn(stakingOutputAsInput)
 	spendTx.AddTxOut(newOutput)
 
-	// transaction have 1 P2TR input and does not have any change
-	txSize := txsizes.EstimateVirtualSize(0, 1, 0, 0, []*wire.TxOut{newOutput}, 0)
-
-	fee := txrules.FeeForSerializeSize(btcutil.Amount(feeRate), txSize)
+	fee := spendStakeTxFee(newOutput, feeRate)
 
 	spendTx.TxOut[0].Value -= int64(fee)
 
-	if spendTx.TxOut[0].Value <= 0 {
-		return nil, nil, fmt.Errorf("too big fee rate for spend stake tx. calculated fee: %d. funding output value: %d", fee, fundingOutput.Value)
+	if err := utils.CheckSpendOutput("withdrawal", spendTx.TxOut[0], minValue); err != nil {
+		return nil, nil, fmt.Errorf("%w. calculated fee: %d. funding output value: %d", err, fee, fundingOutput.Value)
 	}
 
 	// sanity check that transaction is standard
@@ -237,6 +244,7 @@ func createSpendStakeTxUnbondingConfirmed(
 	covenantThreshold uint32,
 	destinationScript []byte,
 	feeRate chainfee.SatPerKVByte,
+	minValue btcutil.Amount,
 	undelegationInfo *cl.UndelegationInfo,
 	net *chaincfg.Params,
 ) (*spendStakeTxInfo, error) {
@@ -267,6 +275,7 @@ func createSpendStakeTxUnbondingConfirmed(
 		&unbondingTxHash,
 		undelegationInfo.UnbondingTime,
 		feeRate,
+		minValue,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create spend stake tx while spending unbonding transaction: %w", err)
@@ -292,6 +301,7 @@ func createSpendStakeTxUnbondingNotConfirmed(
 	storedtx *stakerdb.StoredTransaction,
 	destinationScript []byte,
 	feeRate chainfee.SatPerKVByte,
+	minValue btcutil.Amount,
 	net *chaincfg.Params,
 ) (*spendStakeTxInfo, error) {
 	stakingInfo, err := staking.BuildStakingInfo(
@@ -321,6 +331,7 @@ func createSpendStakeTxUnbondingNotConfirmed(
 		&stakingTxHash,
 		stakingTime,
 		feeRate,
+		minValue,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create spend stake tx while spending staking transaction: %w", err)
@@ -388,6 +399,10 @@ func createUndelegationData(
 		return nil, fmt.Errorf("failed to build unbonding data: %w", err)
 	}
 
+	if err := utils.CheckSpendOutput("unbonding", unbondingInfo.UnbondingOutput, 0); err != nil {
+		return nil, fmt.Errorf("failed to build unbonding data: %w", err)
+	}
+
 	unbondingTx := wire.NewMsgTx(2)
 	unbondingTx.AddTxIn(wire.NewTxIn(wire.NewOutPoint(&stakingTxHash, stakingOutputIndex), nil, nil))
 	unbondingTx.AddTxOut(unbondingInfo.UnbondingOutput)
@@ -0,0 +1,36 @@
+package staker
+
+import (
+	"testing"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateSpendStakeTxChecksValueAfterFee(t *testing.T) {
+	t.Parallel()
+
+	destination := policyTestScript(t)
+	feeRate := chainfee.SatPerKVByte(10_000)
+	fundingOutput := wire.NewTxOut(100_000, policyTestScript(t))
+	fee := spendStakeTxFee(wire.NewTxOut(fundingOutput.Value, destination), feeRate)
+	remaining := btcutil.Amount(fundingOutput.Value) - fee
+
+	tx, paidFee, err := createSpendStakeTx(destination, fundingOutput, 0, &chainhash.Hash{1}, 10, feeRate, remaining)
+	require.NoError(t, err)
+	require.Equal(t, fee, *paidFee)
+	require.Equal(t, int64(remaining), tx.TxOut[0].Value)
+	require.Equal(t, uint32(10), tx.TxIn[0].Sequence)
+
+	// minimum is checked against value remaining after fee
+	_, _, err = createSpendStakeTx(destination, fundingOutput, 0, &chainhash.Hash{1}, 10, feeRate, remaining+1)
+	require.ErrorContains(t, err, "below configured minimum")
+
+	// fee leaving only dust is rejected regardless of minimum
+	dustFunding := wire.NewTxOut(int64(fee)+100, fundingOutput.PkScript)
+	_, _, err = createSpendStakeTx(destination, dustFunding, 0, &chainhash.Hash{1}, 10, feeRate, 0)
+	require.ErrorContains(t, err, "withdrawal value 100 sats below dust")
+}
@@ -0,0 +1,118 @@
+package staker
+
+import (
+	"errors"
+	"fmt"
+
+	cl "github.com/babylonlabs-io/btc-staker/babylonclient"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/wire"
+)
+
+// ErrUnbondingParamsMismatch is returned when unbonding transaction built for
+// delegation does not match unbonding fee or unbonding time of params which
+// Babylon applies to the delegation
+var ErrUnbondingParamsMismatch = errors.New("unbonding transaction does not match babylon params")
+
+// delegationParams returns params which Babylon applies to delegation. Params
+// of delegation with inclusion proof are chosen by height of its inclusion
+// block, params of delegation without it through tip of btc light client.
+func (app *App) delegationParams(inclusionInfo *inclusionInfo) (*cl.StakingParams, error) {
+	if inclusionInfo != nil {
+		params, err := app.babylonClient.ParamsByBtcHeight(inclusionInfo.inclusionBlockBtcHeight)
+		if err != nil {
+			return nil, fmt.Errorf("error getting params: %w", err)
+		}
+
+		return params, nil
+	}
+
+	// chose params as babylon would through tip of btc light client
+	tipHeight, err := app.babylonClient.QueryBtcLightClientTipHeight()
+	if err != nil {
+		return nil, fmt.Errorf("error getting tip height: %w", err)
+	}
+
+	params, err := app.babylonClient.ParamsByBtcHeight(tipHeight)
+	if err != nil {
+		return nil, fmt.Errorf("error getting params: %w", err)
+	}
+
+	return params, nil
+}
+
+// CheckUnbondingTime checks that unbonding time requested for new delegation
+// is allowed by current Babylon params. Babylon requires unbonding time of
+// every delegation to equal unbonding time of params, so it is currently the
+// only allowed value.
+func (app *App) CheckUnbondingTime(unbondingTime uint16) error {
+	params, err := app.babylonClient.Params()
+	if err != nil {
+		return fmt.Errorf("failed to get params: %w", err)
+	}
+
+	return unbondingTimeAllowed(unbondingTime, params)
+}
+
+func unbondingTimeAllowed(unbondingTime uint16, params *cl.StakingParams) error {
+	if unbondingTime != params.UnbondingTime {
+		return fmt.Errorf("%w: requested unbonding time %d, babylon params require %d",
+			ErrUnbondingParamsMismatch, unbondingTime, params.UnbondingTime)
+	}
+
+	return nil
+}
+
+// checkUnbondingParams cross-checks unbonding fee and unbonding time of
+// unbonding transaction built for the delegation against params it was built
+// with and against params Babylon applies to the delegation. Babylon requires
+// exact values, so mismatching delegation would be rejected, or would never
+// receive covenant signatures.
+func (app *App) checkUnbondingParams(
+	inclusionInfo *inclusionInfo,
+	stakingOutput *wire.TxOut,
+	unbondingDesc *UnbondingSlashingDesc,
+	params *cl.StakingParams,
+) error {
+	if err := unbondingMatchesParams(stakingOutput, unbondingDesc, params); err != nil {
+		return err
+	}
+
+	// params chosen by inclusion height can not change, params chosen through
+	// light client tip change when tip crosses activation height of new params
+	// version
+	if inclusionInfo != nil {
+		return nil
+	}
+
+	applicable, err := app.delegationParams(nil)
+	if err != nil {
+		return err
+	}
+
+	return unbondingMatchesParams(stakingOutput, unbondingDesc, applicable)
+}
+
+func unbondingMatchesParams(
+	stakingOutput *wire.TxOut,
+	unbondingDesc *UnbondingSlashingDesc,
+	params *cl.StakingParams,
+) error {
+	if len(unbondingDesc.UnbondingTransaction.TxOut) != 1 {
+		return fmt.Errorf("%w: unbonding transaction must have exactly 1 output, got %d",
+			ErrUnbondingParamsMismatch, len(unbondingDesc.UnbondingTransaction.TxOut))
+	}
+
+	fee := btcutil.Amount(stakingOutput.Value - unbondingDesc.UnbondingTransaction.TxOut[0].Value)
+	if fee != params.UnbondingFee {
+		return fmt.Errorf("%w: unbonding fee %d, required %d",
+			ErrUnbondingParamsMismatch, fee, params.UnbondingFee)
+	}
+
+	if unbondingDesc.UnbondingTxUnbondingTime != params.UnbondingTime {
+		return fmt.Errorf("%w: unbonding time %d, required %d",
+			ErrUnbondingParamsMismatch, unbondingDesc.UnbondingTxUnbondingTime, params.UnbondingTime)
+	}
+
+	return nil
+}
@@ -0,0 +1,115 @@
+package staker
+
+import (
+	"testing"
+
+	cl "github.com/babylonlabs-io/btc-staker/babylonclient"
+	"github.com/babylonlabs-io/btc-staker/stakercfg"
+	"github.com/babylonlabs-io/btc-staker/testutil/mocks"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func unbondingTestParams(fee btcutil.Amount, unbondingTime uint16) *cl.StakingParams {
+	return &cl.StakingParams{
+		BtcStakingParams: cl.BtcStakingParams{
+			UnbondingFee:  fee,
+			UnbondingTime: unbondingTime,
+		},
+	}
+}
+
+func TestCheckUnbondingParams(t *testing.T) {
+	t.Parallel()
+
+	const tipHeight = 300
+	stakingOutput := wire.NewTxOut(100_000, nil)
+	params := unbondingTestParams(1000, 50)
+
+	tests := []struct {
+		name          string
+		unbondingFee  int64
+		unbondingTime uint16
+		outputs       int
+		included      bool
+		// params babylon applies to delegation without inclusion proof
+		applicable      *cl.StakingParams
+		wantErrContains string
+	}{
+		{name: "included", unbondingFee: 1000, unbondingTime: 50, outputs: 1, included: true},
+		{name: "applicable params match", unbondingFee: 1000, unbondingTime: 50, outputs: 1, applicable: params},
+		{
+			name: "fee mismatch", unbondingFee: 999, unbondingTime: 50, outputs: 1, included: true,
+			wantErrContains: "unbonding fee 999, required 1000",
+		},
+		{
+			name: "time mismatch", unbondingFee: 1000, unbondingTime: 49, outputs: 1, included: true,
+			wantErrContains: "unbonding time 49, required 50",
+		},
+		{
+			name: "too many outputs", unbondingFee: 1000, unbondingTime: 50, outputs: 2, included: true,
+			wantErrContains: "unbonding transaction must have exactly 1 output, got 2",
+		},
+		// new params version activated after the transaction was built
+		{
+			name: "applicable params changed", unbondingFee: 1000, unbondingTime: 50, outputs: 1,
+			applicable:      unbondingTestParams(2000, 50),
+			wantErrContains: "unbonding fee 1000, required 2000",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			bc := mocks.NewMockBabylonClient(ctrl)
+			cfg := stakercfg.DefaultConfig()
+			app := &App{config: &cfg, logger: logrus.New(), babylonClient: bc}
+
+			var inclusion *inclusionInfo
+			if tc.included {
+				inclusion = &inclusionInfo{inclusionBlockBtcHeight: 100}
+			}
+			if tc.applicable != nil {
+				bc.EXPECT().QueryBtcLightClientTipHeight().Return(uint32(tipHeight), nil)
+				bc.EXPECT().ParamsByBtcHeight(uint32(tipHeight)).Return(tc.applicable, nil)
+			}
+
+			unbondingTx := wire.NewMsgTx(2)
+			unbondingTx.AddTxOut(wire.NewTxOut(stakingOutput.Value-tc.unbondingFee, nil))
+			for i := 1; i < tc.outputs; i++ {
+				unbondingTx.AddTxOut(wire.NewTxOut(0, nil))
+			}
+
+			err := app.checkUnbondingParams(inclusion, stakingOutput, &UnbondingSlashingDesc{
+				UnbondingTransaction:     unbondingTx,
+				UnbondingTxUnbondingTime: tc.unbondingTime,
+			}, params)
+			if tc.wantErrContains != "" {
+				require.ErrorIs(t, err, ErrUnbondingParamsMismatch)
+				require.ErrorContains(t, err, tc.wantErrContains)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestCheckUnbondingTime(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	bc := mocks.NewMockBabylonClient(ctrl)
+	cfg := stakercfg.DefaultConfig()
+	app := &App{config: &cfg, logger: logrus.New(), babylonClient: bc}
+	bc.EXPECT().Params().Return(unbondingTestParams(1000, 50), nil).Times(2)
+
+	require.NoError(t, app.CheckUnbondingTime(50))
+	err := app.CheckUnbondingTime(100)
+	require.ErrorIs(t, err, ErrUnbondingParamsMismatch)
+	require.ErrorContains(t, err, "requested unbonding time 100, babylon params require 50")
+}
@@ -0,0 +1,274 @@
+package staker
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/babylonlabs-io/btc-staker/stakerdb"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/sirupsen/logrus"
+)
+
+// Headers of webhook requests
+const (
+	// WebhookEventIDHeader holds id of the event, ids increase monotonically
+	WebhookEventIDHeader = "X-Staker-Event-Id"
+	// WebhookTimestampHeader holds unix time at which the request was signed
+	WebhookTimestampHeader = "X-Staker-Timestamp"
+	// WebhookSignatureHeader holds signature returned by SignWebhookPayload
+	WebhookSignatureHeader = "X-Staker-Signature"
+)
+
+// webhookMaxRetryInterval bounds interval between retries of failed delivery
+const webhookMaxRetryInterval = 5 * time.Minute
+
+var (
+	// ErrWebhookDisabled is returned when events are replayed while webhook
+	// is not configured
+	ErrWebhookDisabled = errors.New("webhook is not configured")
+	// ErrInvalidWebhookSignature is returned by VerifyWebhookPayload for
+	// requests which were not signed with the shared secret or are too old
+	ErrInvalidWebhookSignature = errors.New("invalid webhook signature")
+)
+
+// WebhookEvent is payload POSTed to the webhook for every database change
+type WebhookEvent struct {
+	// ID is sequence number of the change in the changelog
+	ID            uint64 `json:"id"`
+	Kind          string `json:"kind"`
+	StakingTxHash string `json:"staking_tx_hash,omitempty"`
+	Detail        string `json:"detail,omitempty"`
+	Timestamp     string `json:"timestamp"`
+	// Replay is true if event is sent again by replay_events
+	Replay bool `json:"replay,omitempty"`
+	// Suppressed is number of identical alerts which were not delivered
+	// since the previous one because of deduplication or rate limit
+	Suppressed uint64 `json:"suppressed,omitempty"`
+}
+
+func newWebhookEvent(ch *stakerdb.Change, replay bool) *WebhookEvent {
+	ev := &WebhookEvent{
+		ID:        ch.Seq,
+		Kind:      ch.Kind.String(),
+		Detail:    ch.Detail,
+		Timestamp: ch.Timestamp.UTC().Format(time.RFC3339Nano),
+		Replay:    replay,
+	}
+
+	if ch.StakingTxHash != (chainhash.Hash{}) {
+		ev.StakingTxHash = ch.StakingTxHash.String()
+	}
+
+	return ev
+}
+
+// SignWebhookPayload returns signature of webhook payload signed at the given
+// unix time. Time is signed together with the body, so that captured request
+// can't be sent again with fresh time.
+func SignWebhookPayload(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifyWebhookPayload checks signature of webhook payload and rejects payloads
+// signed more than maxAge before now. Consumers should also reject events
+// whose id is not greater than id of the last processed event.
+func VerifyWebhookPayload(secret string, timestamp int64, body []byte, signature string, maxAge time.Duration, now time.Time) error {
+	expected := SignWebhookPayload(secret, timestamp, body)
+	if !hmac.Equal([]byte(expected), []byte(signature)) {
+		return ErrInvalidWebhookSignature
+	}
+
+	signedAt := time.Unix(timestamp, 0)
+	if now.Sub(signedAt) > maxAge || signedAt.Sub(now) > maxAge {
+		return fmt.Errorf("%w: signed at %s", ErrInvalidWebhookSignature, signedAt.UTC().Format(time.RFC3339))
+	}
+
+	return nil
+}
+
+// webhookEnabled returns true if database changes are delivered to webhook
+func (app *App) webhookEnabled() bool {
+	return app.config.WebhookConfig != nil && app.config.WebhookConfig.Enabled()
+}
+
+func (app *App) newWebhookClient() *http.Client {
+	return &http.Client{Timeout: app.config.WebhookConfig.Timeout}
+}
+
+// handleWebhook delivers database changes to the webhook in the order in which
+// they were recorded. Sequence number of the last delivered change is
+// persisted, so that delivery continues after restart and no change is
+// skipped. Failed delivery is retried with growing interval.
+func (app *App) handleWebhook() {
+	cfg := app.config.WebhookConfig
+	client := app.newWebhookClient()
+	retryInterval := cfg.RetryInterval
+
+	for {
+		// subscribe before delivering, so that change committed in between is
+		// not missed
+		notify := app.DBChangesNotify()
+
+		if err := app.deliverPendingWebhookEvents(client); err != nil {
+			app.logger.WithFields(logrus.Fields{
+				"err":     err,
+				"retryIn": retryInterval,
+			}).Warn("Failed to deliver webhook event")
+
+			select {
+			case <-time.After(retryInterval):
+			case <-app.quit:
+				return
+			}
+
+			retryInterval = min(2*retryInterval, webhookMaxRetryInterval)
+			continue
+		}
+
+		retryInterval = cfg.RetryInterval
+
+		select {
+		case <-notify:
+		case <-app.quit:
+			return
+		}
+	}
+}
+
+// deliverPendingWebhookEvents delivers all changes recorded after the last
+// delivered one
+func (app *App) deliverPendingWebhookEvents(client *http.Client) error {
+	cursor, err := app.txTracker.WebhookCursor()
+	if err != nil {
+		return err
+	}
+
+	for {
+		changes, err := app.txTracker.QueryChanges(cursor, app.config.WebhookConfig.BatchSize)
+		if err != nil {
+			return err
+		}
+
+		if len(changes) == 0 {
+			return nil
+		}
+
+		for i := range changes {
+			select {
+			case <-app.quit:
+				return nil
+			default:
+			}
+
+			if app.alerts.allow(&changes[i]) {
+				ev := newWebhookEvent(&changes[i], false)
+				ev.Suppressed = app.alerts.pendingSuppressed(&changes[i])
+
+				if err := app.sendWebhookEvent(client, ev); err != nil {
+					return fmt.Errorf("event %d: %w", changes[i].Seq, err)
+				}
+				app.alerts.delivered(&changes[i])
+			} else {
+				app.logger.WithFields(logrus.Fields{
+					"eventId": changes[i].Seq,
+					"kind":    changes[i].Kind.String(),
+				}).Debug("Alert suppressed by deduplication or rate limit")
+			}
+
+			cursor = changes[i].Seq
+			if err := app.txTracker.SetWebhookCursor(cursor); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func (app *App) sendWebhookEvent(client *http.Client, ev *WebhookEvent) error {
+	body, err := json.Marshal(ev)
+	if err != nil {
+		return fmt.Errorf("failed to encode webhook event: %w", err)
+	}
+
+	ctx, cancel := app.appQuitContext()
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, app.config.WebhookConfig.URL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create webhook request: %w", err)
+	}
+
+	timestamp := time.Now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(WebhookEventIDHeader, strconv.FormatUint(ev.ID, 10))
+	req.Header.Set(WebhookTimestampHeader, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(WebhookSignatureHeader, SignWebhookPayload(app.config.WebhookConfig.Secret, timestamp, body))
+
+	resp, err := client.Do(req)
+	if err != nil {
+		// strip the url from the error, it may contain credentials
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return fmt.Errorf("webhook request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// ReplayWebhookEvents sends again at most limit events with id greater than
+// afterID, so that consumer can recover events missed while it was down. Only
+// events already delivered are replayed, later ones are delivered by the
+// webhook worker. Replay is requested explicitly, so suppressed alerts are
+// replayed as well. Returns number of replayed events and id of the last one.
+func (app *App) ReplayWebhookEvents(afterID, limit uint64) (uint64, uint64, error) {
+	if !app.webhookEnabled() {
+		return 0, 0, ErrWebhookDisabled
+	}
+
+	cursor, err := app.txTracker.WebhookCursor()
+	if err != nil {
+		return 0, 0, err
+	}
+
+	changes, err := app.txTracker.QueryChanges(afterID, limit)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	client := app.newWebhookClient()
+
+	var replayed, lastID uint64
+	for i := range changes {
+		if changes[i].Seq > cursor {
+			break
+		}
+
+		if err := app.sendWebhookEvent(client, newWebhookEvent(&changes[i], true)); err != nil {
+			return replayed, lastID, fmt.Errorf("failed to replay event %d: %w", changes[i].Seq, err)
+		}
+
+		replayed++
+		lastID = changes[i].Seq
+	}
+
+	return replayed, lastID, nil
+}
@@ -0,0 +1,49 @@
+package staker_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/babylonlabs-io/btc-staker/staker"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifyWebhookPayload(t *testing.T) {
+	t.Parallel()
+
+	const secret = "0123456789abcdef"
+	body := []byte(`{"id":1,"kind":"transaction_added"}`)
+	now := time.Unix(1_700_000_000, 0)
+	signature := staker.SignWebhookPayload(secret, now.Unix(), body)
+
+	tests := []struct {
+		name      string
+		secret    string
+		timestamp int64
+		body      []byte
+		now       time.Time
+		valid     bool
+	}{
+		{"valid", secret, now.Unix(), body, now, true},
+		{"valid within max age", secret, now.Unix(), body, now.Add(4 * time.Minute), true},
+		{"other secret", "fedcba9876543210", now.Unix(), body, now, false},
+		{"tampered body", secret, now.Unix(), []byte(`{"id":2,"kind":"transaction_added"}`), now, false},
+		// signature covers timestamp, so captured request can't be refreshed
+		{"tampered timestamp", secret, now.Unix() + 600, body, now.Add(10 * time.Minute), false},
+		{"too old", secret, now.Unix(), body, now.Add(10 * time.Minute), false},
+		{"from future", secret, now.Unix(), body, now.Add(-10 * time.Minute), false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := staker.VerifyWebhookPayload(tc.secret, tc.timestamp, tc.body, signature, 5*time.Minute, tc.now)
+			if tc.valid {
+				require.NoError(t, err)
+			} else {
+				require.ErrorIs(t, err, staker.ErrInvalidWebhookSignature)
+			}
+		})
+	}
+}
@@ -0,0 +1,167 @@
+package staker_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/babylonlabs-io/btc-staker/staker"
+	"github.com/babylonlabs-io/btc-staker/stakercfg"
+	"github.com/babylonlabs-io/btc-staker/testutil/simulation"
+	"github.com/babylonlabs-io/btc-staker/walletcontroller"
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	withdrawableTestStakingTime = 20
+	// unbonding time of the simulated babylon params
+	withdrawableTestUnbondingTime = 10
+)
+
+// startSimulatedApp starts simulated app with funded staker address
+func startSimulatedApp(
+	t *testing.T,
+	configure ...func(cfg *stakercfg.Config),
+) (*simulation.Simulation, *staker.App, btcutil.Address) {
+	sim, app := newSimulatedApp(t, configure...)
+
+	addr, err := sim.Wallet.NewAddress(walletcontroller.AddressTypeTaproot)
+	require.NoError(t, err)
+	require.NoError(t, sim.Wallet.FundAddress(addr, btcutil.SatoshiPerBitcoin))
+	sim.Chain.MineBlocks(1)
+
+	require.NoError(t, app.Start())
+	t.Cleanup(func() {
+		_ = app.Stop()
+	})
+	<-app.StartupSyncDone()
+	require.NoError(t, app.StartupSyncErr())
+
+	return sim, app, addr
+}
+
+// activeDelegation stakes funds and drives the delegation through covenant
+// signatures and activation. Returns staking tx hash and its confirmation
+// height.
+func activeDelegation(
+	t *testing.T,
+	sim *simulation.Simulation,
+	app *staker.App,
+	addr btcutil.Address,
+) (*chainhash.Hash, int32) {
+	fp := sim.Babylon.AddFinalityProvider()
+	stakingTxHash, err := app.StakeFunds(
+		addr, 100_000, []*btcec.PublicKey{fp}, withdrawableTestStakingTime, "", "", staker.FeeSelection{},
+	)
+	require.NoError(t, err)
+
+	require.NoError(t, sim.Babylon.SignDelegation(stakingTxHash))
+	// staker broadcasts staking transaction once delegation is verified
+	require.Eventually(t, func() bool {
+		return sim.Chain.InMempool(stakingTxHash)
+	}, 10*time.Second, 50*time.Millisecond)
+
+	sim.Chain.MineBlocks(3)
+	require.NoError(t, sim.Babylon.ActivateDelegation(stakingTxHash))
+	_, height, _ := sim.Chain.Confirmation(stakingTxHash)
+
+	return stakingTxHash, height
+}
+
+// mineToHeight mines blocks until chain tip is at given height
+func mineToHeight(t *testing.T, sim *simulation.Simulation, height int32) {
+	_, tip := sim.Chain.BestBlock()
+	require.LessOrEqual(t, tip, height)
+	sim.Chain.MineBlocks(int(height - tip))
+}
+
+func isWithdrawable(t *testing.T, app *staker.App, stakingTxHash *chainhash.Hash) bool {
+	result, err := app.WithdrawableTransactions(100, 0, "")
+	require.NoError(t, err)
+
+	for _, tx := range result.Transactions {
+		if tx.StakingTx.TxHash() == *stakingTxHash {
+			return true
+		}
+	}
+	return false
+}
+
+// requireWithdrawableAt checks that delegation is not withdrawable until chain
+// tip reaches given height. App learns about new blocks asynchronously, but
+// its height never exceeds the chain tip, so delegation must not be
+// withdrawable while the tip is below the height.
+func requireWithdrawableAt(
+	t *testing.T,
+	sim *simulation.Simulation,
+	app *staker.App,
+	stakingTxHash *chainhash.Hash,
+	height int32,
+) {
+	mineToHeight(t, sim, height-1)
+	require.False(t, isWithdrawable(t, app, stakingTxHash))
+
+	sim.Chain.MineBlocks(1)
+	require.Eventually(t, func() bool {
+		return isWithdrawable(t, app, stakingTxHash)
+	}, 10*time.Second, 50*time.Millisecond)
+}
+
+func TestPendingDelegationIsNotWithdrawable(t *testing.T) {
+	t.Parallel()
+
+	sim, app, addr := startSimulatedApp(t)
+	fp := sim.Babylon.AddFinalityProvider()
+	stakingTxHash, err := app.StakeFunds(
+		addr, 100_000, []*btcec.PublicKey{fp}, withdrawableTestStakingTime, "", "", staker.FeeSelection{},
+	)
+	require.NoError(t, err)
+
+	sim.Chain.MineBlocks(2 * withdrawableTestStakingTime)
+	require.False(t, isWithdrawable(t, app, stakingTxHash))
+}
+
+func TestExpiredDelegationWithdrawableAfterStakingTimelock(t *testing.T) {
+	t.Parallel()
+
+	sim, app, addr := startSimulatedApp(t)
+	stakingTxHash, confirmationHeight := activeDelegation(t, sim, app, addr)
+
+	// babylon expires delegation unbonding time before its staking timelock
+	// expires on btc
+	mineToHeight(t, sim, confirmationHeight+withdrawableTestStakingTime-withdrawableTestUnbondingTime)
+	status, err := sim.Babylon.DelegationStatus(stakingTxHash)
+	require.NoError(t, err)
+	require.Equal(t, simulation.BabylonExpiredStatus, status)
+	require.False(t, isWithdrawable(t, app, stakingTxHash))
+
+	// withdrawal can be included in block at confirmation height + staking
+	// time, so it is withdrawable once the tip is one block before it
+	requireWithdrawableAt(t, sim, app, stakingTxHash, confirmationHeight+withdrawableTestStakingTime-1)
+}
+
+func TestUnbondedDelegationWithdrawableAfterUnbondingTimelock(t *testing.T) {
+	t.Parallel()
+
+	sim, app, addr := startSimulatedApp(t)
+	stakingTxHash, _ := activeDelegation(t, sim, app, addr)
+
+	unbondingTxHash, err := app.UnbondStaking(*stakingTxHash, staker.FeeSelection{})
+	require.NoError(t, err)
+	require.Eventually(t, func() bool {
+		return sim.Chain.InMempool(unbondingTxHash)
+	}, 10*time.Second, 50*time.Millisecond)
+
+	// staking output is spent by unbonding transaction in mempool
+	require.False(t, isWithdrawable(t, app, stakingTxHash))
+
+	sim.Chain.MineBlocks(1)
+	_, unbondingHeight, _ := sim.Chain.Confirmation(unbondingTxHash)
+	status, err := sim.Babylon.DelegationStatus(stakingTxHash)
+	require.NoError(t, err)
+	require.Equal(t, simulation.BabylonUnbondedStatus, status)
+
+	requireWithdrawableAt(t, sim, app, stakingTxHash, unbondingHeight+withdrawableTestUnbondingTime-1)
+}
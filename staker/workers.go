@@ -0,0 +1,195 @@
+package staker
+
+import (
+	"fmt"
+	"runtime/debug"
+	"time"
+
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	workerRestartMinBackoff = time.Second
+	workerRestartMaxBackoff = time.Minute
+)
+
+// startWorker runs long running worker in background. If worker panics, panic
+// is reported and worker is restarted with exponential backoff until app quits.
+func (app *App) startWorker(name string, worker func()) {
+	app.wg.Add(1)
+	go func() {
+		defer app.wg.Done()
+		app.superviseWorker(name, worker)
+	}()
+}
+
+// startTask runs one-off task in background. Panic of task is reported, but
+// task is not restarted, as it may have already consumed its notifications.
+// Delegation is picked up again on next start.
+func (app *App) startTask(name string, task func()) {
+	app.wg.Add(1)
+	go func() {
+		defer app.wg.Done()
+		app.runRecovered(name, task)
+	}()
+}
+
+// Names of tasks watching a single delegation, which are started at most
+// once per delegation
+const (
+	covenantSignaturesTask = "unbonding_signatures"
+	activationTask         = "activation"
+)
+
+// delegationTaskKey identifies task watching a single delegation
+type delegationTaskKey struct {
+	name          string
+	stakingTxHash chainhash.Hash
+}
+
+// startDelegationTask runs task watching a single delegation in background,
+// unless task of the same name is already running for the delegation. Tasks
+// poll Babylon for the delegation state, as after restart of the daemon, so
+// panicked task is restarted like a worker. Returns false if task was not
+// started.
+func (app *App) startDelegationTask(name string, stakingTxHash chainhash.Hash, task func()) bool {
+	key := delegationTaskKey{name: name, stakingTxHash: stakingTxHash}
+	if _, running := app.delegationTasks.LoadOrStore(key, struct{}{}); running {
+		return false
+	}
+
+	app.wg.Add(1)
+	go func() {
+		defer app.wg.Done()
+		defer app.delegationTasks.Delete(key)
+		app.superviseWorker(name, task)
+	}()
+
+	return true
+}
+
+// Names of workers run on every new block. Every worker is signaled on its own
+// channel, so that slow check does not delay the others.
+const (
+	reservationCleanupWorker  = "reservation_cleanup"
+	watchedTransactionsWorker = "watched_transactions"
+	spendableStakesWorker     = "spendable_stakes"
+	autoRenewWorker           = "auto_renew"
+)
+
+var newBlockWorkers = []string{
+	reservationCleanupWorker,
+	watchedTransactionsWorker,
+	spendableStakesWorker,
+	autoRenewWorker,
+}
+
+// newBlockChans returns channels signaling new blocks to workers, buffered so
+// that block handling never blocks on them
+func newBlockChans() map[string]chan struct{} {
+	chans := make(map[string]chan struct{}, len(newBlockWorkers))
+	for _, name := range newBlockWorkers {
+		chans[name] = make(chan struct{}, 1)
+	}
+	return chans
+}
+
+// signalNewBlock signals new block to all new block workers. Blocks received
+// while worker is still handling the previous one are coalesced.
+func (app *App) signalNewBlock() {
+	for _, ch := range app.newBlockChans {
+		select {
+		case ch <- struct{}{}:
+		default:
+			// previous check is still pending
+		}
+	}
+}
+
+// startNewBlockWorker runs check on start and on every new block until app
+// quits
+func (app *App) startNewBlockWorker(name string, check func() error, errMsg string) {
+	run := func() {
+		if err := check(); err != nil {
+			app.logger.WithFields(logrus.Fields{
+				"err": err,
+			}).Error(errMsg)
+		}
+	}
+
+	app.startWorker(name, func() {
+		run()
+
+		for {
+			select {
+			case <-app.newBlockChans[name]:
+				run()
+			case <-app.quit:
+				return
+			}
+		}
+	})
+}
+
+// superviseWorker runs worker until it returns without panic or app quits
+func (app *App) superviseWorker(name string, worker func()) {
+	backoff := workerRestartMinBackoff
+
+	for {
+		started := time.Now()
+		if !app.runRecovered(name, worker) {
+			return
+		}
+
+		// worker which ran long enough is considered healthy again
+		if time.Since(started) > workerRestartMaxBackoff {
+			backoff = workerRestartMinBackoff
+		}
+
+		app.logger.WithFields(logrus.Fields{
+			"worker":    name,
+			"restartIn": backoff,
+		}).Warn("Restarting background worker after panic")
+
+		select {
+		case <-time.After(backoff):
+		case <-app.quit:
+			return
+		}
+
+		backoff = min(backoff*2, workerRestartMaxBackoff)
+	}
+}
+
+// runRecovered runs fn and returns true if it panicked
+func (app *App) runRecovered(name string, fn func()) (panicked bool) {
+	defer func() {
+		if r := recover(); r != nil {
+			panicked = true
+			app.reportPanic(name, r, debug.Stack())
+		}
+	}()
+
+	fn()
+	return false
+}
+
+// reportPanic logs panic with stack, counts it and notifies changelog
+// subscribers
+func (app *App) reportPanic(name string, r interface{}, stack []byte) {
+	app.logger.WithFields(logrus.Fields{
+		"worker": name,
+		"panic":  r,
+		"stack":  string(stack),
+	}).Error("Background worker panicked")
+
+	app.m.WorkerPanics.WithLabelValues(name).Inc()
+
+	if err := app.txTracker.RecordWorkerPanic(name, fmt.Sprint(r)); err != nil {
+		app.logger.WithFields(logrus.Fields{
+			"worker": name,
+			"err":    err,
+		}).Error("Failed to record worker panic")
+	}
+}
@@ -0,0 +1,195 @@
+package staker
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/babylonlabs-io/btc-staker/metrics"
+	"github.com/babylonlabs-io/btc-staker/stakerdb"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+)
+
+func newWorkersTestApp(t *testing.T) *App {
+	return &App{
+		logger:    logrus.New(),
+		m:         metrics.NewStakerMetrics(),
+		txTracker: newArchiveTestStore(t),
+		quit:      make(chan struct{}),
+	}
+}
+
+// panickingWorker returns worker panicking on its first n runs and function
+// returning start times of its runs
+func panickingWorker(n int) (func(), func() []time.Time) {
+	var mu sync.Mutex
+	var runs []time.Time
+
+	worker := func() {
+		mu.Lock()
+		runs = append(runs, time.Now())
+		run := len(runs)
+		mu.Unlock()
+
+		if run <= n {
+			panic("boom")
+		}
+	}
+
+	return worker, func() []time.Time {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]time.Time(nil), runs...)
+	}
+}
+
+func TestStartDelegationTask(t *testing.T) {
+	t.Parallel()
+
+	app := &App{}
+	hash := chainhash.Hash{1}
+	otherHash := chainhash.Hash{2}
+
+	release := make(chan struct{})
+	task := func() { <-release }
+
+	require.True(t, app.startDelegationTask(covenantSignaturesTask, hash, task))
+	// the same task is not started twice for the delegation
+	require.False(t, app.startDelegationTask(covenantSignaturesTask, hash, task))
+	// but runs for other delegations and other tasks of the delegation
+	require.True(t, app.startDelegationTask(covenantSignaturesTask, otherHash, task))
+	require.True(t, app.startDelegationTask(activationTask, hash, task))
+
+	close(release)
+	app.wg.Wait()
+
+	// finished task can be started again
+	require.True(t, app.startDelegationTask(covenantSignaturesTask, hash, func() {}))
+	app.wg.Wait()
+}
+
+func TestRunRecovered(t *testing.T) {
+	t.Parallel()
+
+	app := newWorkersTestApp(t)
+
+	require.False(t, app.runRecovered("healthy", func() {}))
+	require.True(t, app.runRecovered("failing", func() { panic("boom") }))
+
+	require.Equal(t, float64(0), testutil.ToFloat64(app.m.WorkerPanics.WithLabelValues("healthy")))
+	require.Equal(t, float64(1), testutil.ToFloat64(app.m.WorkerPanics.WithLabelValues("failing")))
+
+	changes, err := app.txTracker.QueryChanges(0, 10)
+	require.NoError(t, err)
+	require.Len(t, changes, 1)
+	require.Equal(t, stakerdb.ChangeWorkerPanicked, changes[0].Kind)
+	require.Equal(t, "failing: boom", changes[0].Detail)
+}
+
+func TestSuperviseWorkerRestartsWithBackoff(t *testing.T) {
+	t.Parallel()
+
+	app := newWorkersTestApp(t)
+	worker, runs := panickingWorker(2)
+
+	app.superviseWorker("worker", worker)
+
+	started := runs()
+	require.Len(t, started, 3)
+	// backoff doubles after every panic
+	require.GreaterOrEqual(t, started[1].Sub(started[0]), workerRestartMinBackoff)
+	require.GreaterOrEqual(t, started[2].Sub(started[1]), 2*workerRestartMinBackoff)
+	require.Equal(t, float64(2), testutil.ToFloat64(app.m.WorkerPanics.WithLabelValues("worker")))
+}
+
+func TestSuperviseWorkerStopsOnQuit(t *testing.T) {
+	t.Parallel()
+
+	app := newWorkersTestApp(t)
+	worker, runs := panickingWorker(1)
+	close(app.quit)
+
+	done := make(chan struct{})
+	go func() {
+		app.superviseWorker("worker", worker)
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(workerRestartMinBackoff / 2):
+		t.Fatal("worker was not stopped while waiting for restart")
+	}
+
+	require.Len(t, runs(), 1)
+}
+
+func TestStartDelegationTaskRestartsPanickedTask(t *testing.T) {
+	t.Parallel()
+
+	app := newWorkersTestApp(t)
+	hash := chainhash.Hash{1}
+	task, runs := panickingWorker(1)
+
+	require.True(t, app.startDelegationTask(activationTask, hash, task))
+	app.wg.Wait()
+
+	require.Len(t, runs(), 2)
+	// task is released once it finishes without panic
+	require.True(t, app.startDelegationTask(activationTask, hash, func() {}))
+	app.wg.Wait()
+}
+
+func TestSlowNewBlockWorkerDoesNotDelayOthers(t *testing.T) {
+	t.Parallel()
+
+	app := newWorkersTestApp(t)
+	app.newBlockChans = newBlockChans()
+	defer func() {
+		close(app.quit)
+		app.wg.Wait()
+	}()
+
+	release := make(chan struct{})
+	var slowRuns int
+	var mu sync.Mutex
+	app.startNewBlockWorker(autoRenewWorker, func() error {
+		mu.Lock()
+		slowRuns++
+		mu.Unlock()
+		<-release
+		return nil
+	}, "slow check failed")
+
+	checked := make(chan struct{}, 10)
+	app.startNewBlockWorker(spendableStakesWorker, func() error {
+		checked <- struct{}{}
+		return nil
+	}, "check failed")
+
+	// both workers check on start
+	<-checked
+	for i := 0; i < 3; i++ {
+		app.signalNewBlock()
+		select {
+		case <-checked:
+		case <-time.After(5 * time.Second):
+			t.Fatal("worker not run on new block while other worker is blocked")
+		}
+	}
+
+	// blocks received while slow worker was busy are coalesced into one run
+	close(release)
+	require.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return slowRuns == 2
+	}, 5*time.Second, 10*time.Millisecond)
+	time.Sleep(100 * time.Millisecond)
+	mu.Lock()
+	require.Equal(t, 2, slowRuns)
+	mu.Unlock()
+}
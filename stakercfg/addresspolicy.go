@@ -0,0 +1,43 @@
+package stakercfg
+
+import (
+	"fmt"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg"
+)
+
+const (
+	// AddressTypeStaker sends funds back to the staker address used in the
+	// staking transaction
+	AddressTypeStaker = "staker"
+	// AddressTypeTaproot sends funds to a fresh taproot address of the wallet
+	AddressTypeTaproot = "taproot"
+	// AddressTypeSegwit sends funds to a fresh native segwit address of the wallet
+	AddressTypeSegwit = "segwit"
+	// AddressTypeExternal sends funds to a fixed, configured address
+	AddressTypeExternal = "external"
+)
+
+// validateAddressPolicy checks that address type is known and that fixed address
+// is provided, and valid for given network, only for external address type
+func validateAddressPolicy(addressType string, address string, net *chaincfg.Params) error {
+	switch addressType {
+	case AddressTypeStaker, AddressTypeTaproot, AddressTypeSegwit:
+		if address != "" {
+			return fmt.Errorf("address can be set only for %s address type", AddressTypeExternal)
+		}
+		return nil
+	case AddressTypeExternal:
+		if address == "" {
+			return fmt.Errorf("address is required for %s address type", AddressTypeExternal)
+		}
+
+		if _, err := btcutil.DecodeAddress(address, net); err != nil {
+			return fmt.Errorf("invalid address %s: %w", address, err)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown address type: %s", addressType)
+	}
+}
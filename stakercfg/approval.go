@@ -0,0 +1,67 @@
+package stakercfg
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const defaultApprovalExpiry = time.Hour
+
+// ApprovalConfig defines two-person approval of large operations. Stake and
+// spend requests above threshold wait until a principal other than the
+// requester approves them.
+type ApprovalConfig struct {
+	Enabled   bool          `long:"enabled" description:"Require approval of second principal for stake and spend requests above threshold"`
+	Threshold uint64        `long:"threshold" description:"Amount in satoshis above which stake and spend requests require approval"`
+	Expiry    time.Duration `long:"expiry" description:"Time after which not approved operation expires"`
+	Approvers []string      `long:"approver" default-mask:"-" description:"Credentials user:password of principal allowed to call the daemon in addition to the operator. Password can be a secret reference. Can be specified multiple times"`
+}
+
+func DefaultApprovalConfig() ApprovalConfig {
+	return ApprovalConfig{
+		Enabled: false,
+		Expiry:  defaultApprovalExpiry,
+	}
+}
+
+func (cfg *ApprovalConfig) Validate() error {
+	if !cfg.Enabled {
+		return nil
+	}
+
+	if cfg.Expiry <= 0 {
+		return errors.New("approval expiry must be positive")
+	}
+
+	if len(cfg.Approvers) == 0 {
+		return errors.New("at least one approver is required")
+	}
+
+	if _, err := cfg.Principals(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Principals returns password of every approver by user name
+func (cfg *ApprovalConfig) Principals() (map[string]string, error) {
+	principals := make(map[string]string, len(cfg.Approvers))
+
+	for _, a := range cfg.Approvers {
+		user, pass, ok := strings.Cut(a, ":")
+		if !ok || user == "" || pass == "" {
+			return nil, fmt.Errorf("approver must be in format user:password")
+		}
+
+		if _, ok := principals[user]; ok {
+			return nil, fmt.Errorf("duplicate approver %s", user)
+		}
+
+		principals[user] = pass
+	}
+
+	return principals, nil
+}
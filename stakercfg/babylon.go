@@ -1,6 +1,7 @@
 package stakercfg
 
 import (
+	"fmt"
 	"time"
 
 	bbncfg "github.com/babylonlabs-io/babylon/v4/client/config"
@@ -24,6 +25,19 @@ type BBNConfig struct {
 	BlockTimeout   time.Duration `long:"block-timeout" description:"block timeout when waiting for block events"`
 	OutputFormat   string        `long:"output-format" description:"default output when printint responses"`
 	SignModeStr    string        `long:"sign-mode" description:"sign mode to use"`
+	// KeyArmor allows to keep babylon key outside of key directory, e.g. in
+	// vault, it is imported to the keyring on startup
+	KeyArmor           string `long:"key-armor" default-mask:"-" description:"ASCII armored private key imported to the keyring under key name on startup. Usually a secret reference"`
+	KeyArmorPassphrase string `long:"key-armor-passphrase" default-mask:"-" description:"passphrase of the armored private key"`
+	// RemoteSigner* options move babylon key to external signing service,
+	// keyring then holds only its public key
+	RemoteSignerURL        string        `long:"remote-signer-url" description:"base url of remote signing service holding the babylon key. If set, messages are signed remotely instead of with the local keyring"`
+	RemoteSignerKeyID      string        `long:"remote-signer-key-id" description:"id of the babylon key in the remote signing service"`
+	RemoteSignerToken      string        `long:"remote-signer-token" default-mask:"-" description:"bearer token used to authenticate to the remote signing service"`
+	RemoteSignerCACert     string        `long:"remote-signer-ca-cert" description:"path to PEM encoded CA certificate of the remote signing service"`
+	RemoteSignerClientCert string        `long:"remote-signer-client-cert" description:"path to PEM encoded client certificate used for mutual tls with the remote signing service"`
+	RemoteSignerClientKey  string        `long:"remote-signer-client-key" description:"path to PEM encoded client key used for mutual tls with the remote signing service"`
+	RemoteSignerTimeout    time.Duration `long:"remote-signer-timeout" description:"timeout of requests to the remote signing service"`
 }
 
 func DefaultBBNConfig() BBNConfig {
@@ -43,12 +57,39 @@ func DefaultBBNConfig() BBNConfig {
 		Timeout:        dc.Timeout,
 		// Setting this to relatively low value, out currnet babylon client (lens) will
 		// block for this amout of time to wait for transaction inclusion in block
-		BlockTimeout: 1 * time.Minute,
-		OutputFormat: dc.OutputFormat,
-		SignModeStr:  dc.SignModeStr,
+		BlockTimeout:        1 * time.Minute,
+		OutputFormat:        dc.OutputFormat,
+		SignModeStr:         dc.SignModeStr,
+		RemoteSignerTimeout: 10 * time.Second,
 	}
 }
 
+// Validate checks babylon key options which are not validated by babylon
+// client config
+func (bc *BBNConfig) Validate() error {
+	if bc.RemoteSignerURL == "" {
+		return nil
+	}
+
+	if bc.KeyArmor != "" {
+		return fmt.Errorf("key-armor and remote-signer-url are mutually exclusive")
+	}
+
+	if bc.RemoteSignerKeyID == "" {
+		return fmt.Errorf("remote-signer-key-id must be set when remote-signer-url is set")
+	}
+
+	if bc.RemoteSignerTimeout <= 0 {
+		return fmt.Errorf("remote-signer-timeout must be greater than 0")
+	}
+
+	if (bc.RemoteSignerClientCert == "") != (bc.RemoteSignerClientKey == "") {
+		return fmt.Errorf("remote-signer-client-cert and remote-signer-client-key must be set together")
+	}
+
+	return nil
+}
+
 func BBNConfigToBabylonConfig(bc *BBNConfig) bbncfg.BabylonConfig {
 	return bbncfg.BabylonConfig{
 		Key:            bc.Key,
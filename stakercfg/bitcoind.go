@@ -1,6 +1,10 @@
 package stakercfg
 
 import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
 	"time"
 )
 
@@ -28,6 +32,7 @@ type Bitcoind struct {
 	RPCHost              string        `long:"rpchost" description:"The daemon's rpc listening address"`
 	RPCUser              string        `long:"rpcuser" description:"Username for RPC connections"`
 	RPCPass              string        `long:"rpcpass" default-mask:"-" description:"Password for RPC connections"`
+	RPCCookie            string        `long:"rpccookie" description:"Path to the bitcoind .cookie file used for RPC authentication instead of rpcuser and rpcpass"`
 	ZMQPubRawBlock       string        `long:"zmqpubrawblock" description:"The address listening for ZMQ connections to deliver raw block notifications"`
 	ZMQPubRawTx          string        `long:"zmqpubrawtx" description:"The address listening for ZMQ connections to deliver raw transaction notifications"`
 	ZMQReadDeadline      time.Duration `long:"zmqreaddeadline" description:"The read deadline for reading ZMQ messages from both the block and tx subscriptions"`
@@ -56,3 +61,36 @@ func DefaultBitcoindConfig() Bitcoind {
 		DisableTLS:           true,
 	}
 }
+
+// RPCCredentials returns user and password for RPC connections, read from the
+// cookie file if one is configured
+func (b *Bitcoind) RPCCredentials() (string, string, error) {
+	if b.RPCCookie == "" {
+		return b.RPCUser, b.RPCPass, nil
+	}
+
+	return ReadCookieFile(b.RPCCookie)
+}
+
+// ReadCookieFile reads user and password from bitcoind cookie file, which holds
+// single line in format user:password
+func ReadCookieFile(path string) (string, string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to open cookie file: %w", err)
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Scan()
+	if err := scanner.Err(); err != nil {
+		return "", "", fmt.Errorf("failed to read cookie file: %w", err)
+	}
+
+	user, pass, found := strings.Cut(scanner.Text(), ":")
+	if !found {
+		return "", "", fmt.Errorf("malformed cookie file %s", path)
+	}
+
+	return user, pass, nil
+}
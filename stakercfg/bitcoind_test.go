@@ -0,0 +1,35 @@
+package stakercfg
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBitcoindRPCCredentials(t *testing.T) {
+	t.Parallel()
+
+	cfg := DefaultBitcoindConfig()
+	user, pass, err := cfg.RPCCredentials()
+	require.NoError(t, err)
+	require.Equal(t, defaultBitcoindRPCUser, user)
+	require.Equal(t, defaultBitcoindRPCPass, pass)
+
+	// cookie takes precedence over user and password
+	cfg.RPCCookie = filepath.Join(t.TempDir(), ".cookie")
+	require.NoError(t, os.WriteFile(cfg.RPCCookie, []byte("__cookie__:pass:with:colons\n"), 0600))
+	user, pass, err = cfg.RPCCredentials()
+	require.NoError(t, err)
+	require.Equal(t, "__cookie__", user)
+	require.Equal(t, "pass:with:colons", pass)
+
+	require.NoError(t, os.WriteFile(cfg.RPCCookie, []byte("no separator"), 0600))
+	_, _, err = cfg.RPCCredentials()
+	require.ErrorContains(t, err, "malformed cookie file")
+
+	require.NoError(t, os.Remove(cfg.RPCCookie))
+	_, _, err = cfg.RPCCredentials()
+	require.ErrorContains(t, err, "failed to open cookie file")
+}
@@ -0,0 +1,68 @@
+package stakercfg
+
+import (
+	"errors"
+	"fmt"
+	"os"
+)
+
+const (
+	defaultEtcdElectionPrefix = "/leader/"
+	defaultPostgresLockName   = "btcstaker"
+	defaultLeaderSessionTTL   = 60
+)
+
+// ClusterConfig defines leader election between staker instances sharing
+// replicated database. Only the leader runs staker, so that delegations are
+// never broadcast or submitted to Babylon twice.
+type ClusterConfig struct {
+	EnableLeaderElection bool   `long:"enable-leader-election" description:"Enables leader election. Only elected instance runs staker, others wait until leader goes away. Requires etcd or postgres db backend."`
+	LeaderElector        string `long:"leader-elector" choice:"etcd" choice:"postgres" description:"Leader elector to use. Must match the db backend."`
+	EtcdElectionPrefix   string `long:"etcd-election-prefix" description:"Election key prefix in etcd namespace of the database."`
+	PostgresLockName     string `long:"postgres-lock-name" description:"Name of postgres advisory lock held by the leader."`
+	ID                   string `long:"id" description:"Identifier of this instance in the cluster. Defaults to hostname."`
+	LeaderSessionTTL     int    `long:"leader-session-ttl" description:"Time to live of leader session in seconds. Leader which did not refresh its session in this time loses leadership."`
+}
+
+func (cfg *ClusterConfig) Validate(dbCfg *DBConfig) error {
+	if !cfg.EnableLeaderElection {
+		return nil
+	}
+
+	switch cfg.LeaderElector {
+	case EtcdBackend, PostgresBackend:
+	default:
+		return fmt.Errorf("invalid leader elector %q", cfg.LeaderElector)
+	}
+
+	// database must be shared by all instances, and it also stores fencing
+	// token of the current leader
+	if dbCfg.Backend != cfg.LeaderElector {
+		return fmt.Errorf("%s leader elector requires %s db backend", cfg.LeaderElector, cfg.LeaderElector)
+	}
+
+	if cfg.ID == "" {
+		return errors.New("cluster id must be set")
+	}
+
+	if cfg.LeaderSessionTTL <= 0 {
+		return errors.New("leader session ttl must be greater than 0")
+	}
+
+	return nil
+}
+
+func DefaultClusterConfig() ClusterConfig {
+	// hostname is unique enough identifier for instances running on different
+	// machines, otherwise id must be set explicitly
+	id, _ := os.Hostname()
+
+	return ClusterConfig{
+		EnableLeaderElection: false,
+		LeaderElector:        EtcdBackend,
+		EtcdElectionPrefix:   defaultEtcdElectionPrefix,
+		PostgresLockName:     defaultPostgresLockName,
+		ID:                   id,
+		LeaderSessionTTL:     defaultLeaderSessionTTL,
+	}
+}
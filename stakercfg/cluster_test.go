@@ -0,0 +1,87 @@
+package stakercfg
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateClusterConfig(t *testing.T) {
+	t.Parallel()
+
+	etcdDB := DefaultDBConfig()
+	etcdDB.Backend = EtcdBackend
+	boltDB := DefaultDBConfig()
+
+	tests := []struct {
+		name            string
+		modify          func(cfg *ClusterConfig)
+		dbCfg           *DBConfig
+		wantErrContains string
+	}{
+		{
+			name:   "leader election disabled",
+			modify: func(_ *ClusterConfig) {},
+			dbCfg:  &boltDB,
+		},
+		{
+			name: "etcd leader elector with etcd backend",
+			modify: func(cfg *ClusterConfig) {
+				cfg.EnableLeaderElection = true
+			},
+			dbCfg: &etcdDB,
+		},
+		{
+			name: "etcd leader elector with bolt backend",
+			modify: func(cfg *ClusterConfig) {
+				cfg.EnableLeaderElection = true
+			},
+			dbCfg:           &boltDB,
+			wantErrContains: "etcd leader elector requires etcd db backend",
+		},
+		{
+			name: "unknown leader elector",
+			modify: func(cfg *ClusterConfig) {
+				cfg.EnableLeaderElection = true
+				cfg.LeaderElector = "zookeeper"
+			},
+			dbCfg:           &etcdDB,
+			wantErrContains: `invalid leader elector "zookeeper"`,
+		},
+		{
+			name: "missing id",
+			modify: func(cfg *ClusterConfig) {
+				cfg.EnableLeaderElection = true
+				cfg.ID = ""
+			},
+			dbCfg:           &etcdDB,
+			wantErrContains: "cluster id must be set",
+		},
+		{
+			name: "zero session ttl",
+			modify: func(cfg *ClusterConfig) {
+				cfg.EnableLeaderElection = true
+				cfg.LeaderSessionTTL = 0
+			},
+			dbCfg:           &etcdDB,
+			wantErrContains: "leader session ttl must be greater than 0",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			cfg := DefaultClusterConfig()
+			cfg.ID = "staker-1"
+			tc.modify(&cfg)
+
+			err := cfg.Validate(tc.dbCfg)
+			if tc.wantErrContains != "" {
+				require.ErrorContains(t, err, tc.wantErrContains)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
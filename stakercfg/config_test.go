@@ -0,0 +1,90 @@
+package stakercfg
+
+import (
+	"testing"
+
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/stretchr/testify/require"
+)
+
+// validTestConfig returns default config using bitcoind node and wallet, with
+// all directories in temp dir
+func validTestConfig(t *testing.T, network string) Config {
+	cfg := DefaultConfig()
+	cfg.StakerdDir = t.TempDir()
+	cfg.ChainConfig.Network = network
+	cfg.BtcNodeBackendConfig.Nodetype = "bitcoind"
+	cfg.BtcNodeBackendConfig.WalletType = "bitcoind"
+
+	return cfg
+}
+
+func TestValidateConfigNetworkDefaults(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		network    string
+		net        string
+		rpcPort    string
+		minFeeRate int64
+	}{
+		{network: "mainnet", net: chaincfg.MainNetParams.Name, rpcPort: "8332", minFeeRate: 2},
+		{network: "testnet", net: chaincfg.TestNet3Params.Name, rpcPort: "18332", minFeeRate: 2},
+		{network: "testnet4", net: "testnet4", rpcPort: "48332", minFeeRate: 1},
+		{network: "signet", net: chaincfg.SigNetParams.Name, rpcPort: "38332", minFeeRate: 1},
+		{network: "regtest", net: chaincfg.RegressionNetParams.Name, rpcPort: "18443", minFeeRate: 1},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.network, func(t *testing.T) {
+			t.Parallel()
+
+			cfg := validTestConfig(t, tc.network)
+			cfg.BtcNodeBackendConfig.Bitcoind.RPCHost = "127.0.0.1"
+			cfg.WalletRPCConfig.Host = "bitcoind.local"
+
+			validated, err := ValidateConfig(cfg)
+			require.NoError(t, err)
+			require.Equal(t, tc.net, validated.ActiveNetParams.Name)
+			require.Equal(t, "127.0.0.1:"+tc.rpcPort, validated.BtcNodeBackendConfig.Bitcoind.RPCHost)
+			require.Equal(t, "bitcoind.local:"+tc.rpcPort, validated.WalletRPCConfig.Host)
+			require.Equal(t, tc.minFeeRate, validated.BtcNodeBackendConfig.MinFeeRate)
+		})
+	}
+}
+
+func TestValidateConfigExplicitNetworkSettings(t *testing.T) {
+	t.Parallel()
+
+	// explicit port and fee rate are kept
+	cfg := validTestConfig(t, "testnet4")
+	cfg.BtcNodeBackendConfig.Bitcoind.RPCHost = "127.0.0.1:1234"
+	cfg.BtcNodeBackendConfig.MinFeeRate = 5
+	validated, err := ValidateConfig(cfg)
+	require.NoError(t, err)
+	require.Equal(t, "127.0.0.1:1234", validated.BtcNodeBackendConfig.Bitcoind.RPCHost)
+	require.Equal(t, int64(5), validated.BtcNodeBackendConfig.MinFeeRate)
+
+	cfg = validTestConfig(t, "testnet4")
+	cfg.BtcNodeBackendConfig.MinFeeRate = -1
+	_, err = ValidateConfig(cfg)
+	require.ErrorContains(t, err, "minfeerate rate must not be negative")
+}
+
+func TestValidateConfigFeatureFlags(t *testing.T) {
+	t.Parallel()
+
+	cfg := validTestConfig(t, "regtest")
+	cfg.FeatureFlagsConfig.Enable = []string{FeatureStakeExpansion}
+	validated, err := ValidateConfig(cfg)
+	require.NoError(t, err)
+	require.True(t, validated.FeatureFlagsConfig.Enabled(FeatureStakeExpansion))
+
+	// experimental features are disabled by default
+	require.False(t, validTestConfig(t, "regtest").FeatureFlagsConfig.Enabled(FeatureStakeExpansion))
+
+	cfg = validTestConfig(t, "regtest")
+	cfg.FeatureFlagsConfig.Enable = []string{"stake-splitting"}
+	_, err = ValidateConfig(cfg)
+	require.ErrorContains(t, err, "unknown experimental feature stake-splitting, supported features: stake-expansion")
+}
@@ -1,16 +1,47 @@
 package stakercfg
 
 import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"path/filepath"
 	"time"
 
+	"github.com/babylonlabs-io/btc-staker/stakerdb/boltdb"
+	"github.com/btcsuite/btcd/btcec/v2"
 	"github.com/lightningnetwork/lnd/kvdb"
+	"github.com/lightningnetwork/lnd/kvdb/etcd"
+	"github.com/lightningnetwork/lnd/kvdb/postgres"
 )
 
 const (
 	defaultDBName = "staker.db"
+
+	// BoltBackend stores the database in local bbolt file
+	BoltBackend = "bolt"
+	// EtcdBackend stores the database in etcd cluster. Requires stakerd built
+	// with kvdb_etcd tag.
+	EtcdBackend = "etcd"
+	// PostgresBackend stores the database in postgres. Requires stakerd built
+	// with kvdb_postgres tag.
+	PostgresBackend = "postgres"
+
+	defaultEtcdNamespace = "btcstaker"
+	// prefix of postgres tables created by the staker
+	postgresTablePrefix = "btcstaker"
 )
 
 type DBConfig struct {
+	// Backend is the type of database backend.
+	Backend string `long:"backend" description:"The database backend to use {bolt, etcd, postgres}. Etcd and postgres require stakerd built with kvdb_etcd and kvdb_postgres tags."`
+
+	// Etcd holds configuration of etcd backend.
+	Etcd *etcd.Config `group:"etcd" namespace:"etcd"`
+
+	// Postgres holds configuration of postgres backend.
+	Postgres *postgres.Config `group:"postgres" namespace:"postgres"`
+
 	// DBPath is the directory path in which the database file should be
 	// stored.
 	DBPath string `long:"dbpath" description:"The directory path in which the database file should be stored."`
@@ -38,16 +69,48 @@ type DBConfig struct {
 	// DBTimeout specifies the timeout value to use when opening the wallet
 	// database.
 	DBTimeout time.Duration `long:"dbtimeout" description:"Specifies the timeout value to use when opening the wallet database."`
+
+	// FreelistType is the type of bolt freelist.
+	FreelistType string `long:"freelisttype" description:"Type of bolt freelist {hashmap, array}. Hashmap is faster for large databases with fragmented free space."`
+
+	// NoSync skips fsync after every commit.
+	NoSync bool `long:"nosync" description:"Skips fsync after every database commit. Data can be lost on crash, use only for tests."`
+
+	// InitialMmapSize is the initial size of bolt memory map in bytes.
+	InitialMmapSize int `long:"initialmmapsize" description:"Initial size of database memory map in bytes. Long read transactions block writes once database grows over it. 0 uses bolt default."`
+
+	// MaxBatchDelay is the maximum delay before batched write transaction
+	// is started.
+	MaxBatchDelay time.Duration `long:"maxbatchdelay" description:"Maximum delay before batched database write transaction is started. Higher values combine more writes into one transaction at the expense of write latency."`
+
+	// MaxBatchSize is the maximum number of writes combined in one batch.
+	MaxBatchSize int `long:"maxbatchsize" description:"Maximum number of database writes combined in one batch. 0 disables batching."`
+
+	// IntegrityKey is the hex encoded private key of daemon identity, which
+	// signs checksums of tracked transaction records.
+	IntegrityKey string `long:"integritykey" description:"Hex encoded 32 byte private key signing checksums of stored transaction records, so that their tampering is detected. Once set, the database can be written only with the same key. Can be a secret reference."`
 }
 
 func DefaultDBConfig() DBConfig {
 	return DBConfig{
+		Backend: BoltBackend,
+		Etcd: &etcd.Config{
+			Namespace: defaultEtcdNamespace,
+		},
+		Postgres: &postgres.Config{
+			Timeout: kvdb.DefaultDBTimeout,
+		},
 		DBPath:            defaultDataDir,
 		DBFileName:        defaultDBName,
 		NoFreelistSync:    true,
 		AutoCompact:       false,
 		AutoCompactMinAge: kvdb.DefaultBoltAutoCompactMinAge,
 		DBTimeout:         kvdb.DefaultDBTimeout,
+		FreelistType:      boltdb.FreelistHashmap,
+		NoSync:            false,
+		InitialMmapSize:   0,
+		MaxBatchDelay:     boltdb.DefaultMaxBatchDelay,
+		MaxBatchSize:      boltdb.DefaultMaxBatchSize,
 	}
 }
 
@@ -62,7 +125,112 @@ func DBConfigToBoltBackenCondfig(db *DBConfig) kvdb.BoltBackendConfig {
 	}
 }
 
+// DBConfigToBoltOptions converts db config to bolt options
+func DBConfigToBoltOptions(db *DBConfig) *boltdb.Options {
+	return &boltdb.Options{
+		NoFreelistSync:  db.NoFreelistSync,
+		FreelistType:    db.FreelistType,
+		NoSync:          db.NoSync,
+		InitialMmapSize: db.InitialMmapSize,
+		MaxBatchDelay:   db.MaxBatchDelay,
+		MaxBatchSize:    db.MaxBatchSize,
+		Timeout:         db.DBTimeout,
+	}
+}
+
+// IntegrityPrivateKey returns key signing checksums of stored records, nil if
+// it is not configured
+func (cfg *DBConfig) IntegrityPrivateKey() (*btcec.PrivateKey, error) {
+	if cfg.IntegrityKey == "" {
+		return nil, nil
+	}
+
+	b, err := hex.DecodeString(cfg.IntegrityKey)
+	if err != nil || len(b) != btcec.PrivKeyBytesLen {
+		return nil, fmt.Errorf("integrity key must be hex encoded %d byte private key", btcec.PrivKeyBytesLen)
+	}
+
+	key, _ := btcec.PrivKeyFromBytes(b)
+	return key, nil
+}
+
+// Validate checks options of the selected backend
+func (cfg *DBConfig) Validate() error {
+	switch cfg.Backend {
+	case BoltBackend:
+		return DBConfigToBoltOptions(cfg).Validate()
+	case EtcdBackend:
+		if !kvdb.EtcdBackend {
+			return errors.New("etcd backend not available, stakerd must be built with kvdb_etcd tag")
+		}
+		if cfg.Etcd == nil || cfg.Etcd.Host == "" {
+			return errors.New("etcd host must be set when etcd backend is used")
+		}
+		if cfg.Etcd.Embedded {
+			return errors.New("embedded etcd is not supported")
+		}
+		return nil
+	case PostgresBackend:
+		if !kvdb.PostgresBackend {
+			return errors.New("postgres backend not available, stakerd must be built with kvdb_postgres tag")
+		}
+		if cfg.Postgres == nil || cfg.Postgres.Dsn == "" {
+			return errors.New("postgres dsn must be set when postgres backend is used")
+		}
+		return nil
+	default:
+		return fmt.Errorf("invalid db backend %q, supported backends: %s, %s, %s",
+			cfg.Backend, BoltBackend, EtcdBackend, PostgresBackend)
+	}
+}
+
 func GetDBBackend(cfg *DBConfig) (kvdb.Backend, error) {
-	boltConfig := DBConfigToBoltBackenCondfig(cfg)
-	return kvdb.GetBoltBackend(&boltConfig)
+	switch cfg.Backend {
+	case EtcdBackend:
+		return getEtcdBackend(cfg)
+	case PostgresBackend:
+		return getPostgresBackend(cfg)
+	}
+
+	// compaction is implemented only by kvdb bolt backend, which does not
+	// support tuning options, so it is only used to compact the database
+	if cfg.AutoCompact {
+		boltConfig := DBConfigToBoltBackenCondfig(cfg)
+		backend, err := kvdb.GetBoltBackend(&boltConfig)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := backend.Close(); err != nil {
+			return nil, fmt.Errorf("failed to close compacted database: %w", err)
+		}
+	}
+
+	return boltdb.Open(filepath.Join(cfg.DBPath, cfg.DBFileName), DBConfigToBoltOptions(cfg))
+}
+
+// getEtcdBackend connects to etcd cluster. Staker is the only writer of its
+// namespace, which allows etcd backend to serialize writes locally.
+func getEtcdBackend(cfg *DBConfig) (kvdb.Backend, error) {
+	backend, err := kvdb.Open(
+		kvdb.EtcdBackendName, context.Background(),
+		cfg.Etcd.CloneWithSingleWriter(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open etcd backend: %w", err)
+	}
+
+	return backend, nil
+}
+
+func getPostgresBackend(cfg *DBConfig) (kvdb.Backend, error) {
+	backend, err := kvdb.Open(
+		kvdb.PostgresBackendName, context.Background(),
+		cfg.Postgres, postgresTablePrefix,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open postgres backend: %w", err)
+	}
+
+	return backend, nil
 }
@@ -0,0 +1,66 @@
+//go:build kvdb_etcd
+
+package stakercfg
+
+import (
+	"testing"
+
+	"github.com/lightningnetwork/lnd/kvdb"
+	"github.com/lightningnetwork/lnd/kvdb/etcd"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEtcdDBBackend(t *testing.T) {
+	etcdCfg, cleanup := etcd.NewTestEtcdInstance(t, t.TempDir())
+	t.Cleanup(cleanup)
+	etcdCfg.DisableTLS = true
+
+	cfg := DefaultDBConfig()
+	cfg.Backend = EtcdBackend
+	cfg.Etcd = etcdCfg
+	require.NoError(t, cfg.Validate())
+
+	bucket, key, value := []byte("bucket"), []byte("key"), []byte("value")
+
+	backend, err := GetDBBackend(&cfg)
+	require.NoError(t, err)
+	require.NoError(t, kvdb.Update(backend, func(tx kvdb.RwTx) error {
+		b, err := tx.CreateTopLevelBucket(bucket)
+		if err != nil {
+			return err
+		}
+		return b.Put(key, value)
+	}, func() {}))
+	require.NoError(t, backend.Close())
+
+	// state is kept in etcd, so other instance sharing the namespace sees it
+	backend, err = GetDBBackend(&cfg)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = backend.Close()
+	})
+
+	var stored []byte
+	require.NoError(t, kvdb.View(backend, func(tx kvdb.RTx) error {
+		stored = tx.ReadBucket(bucket).Get(key)
+		return nil
+	}, func() {
+		stored = nil
+	}))
+	require.Equal(t, value, stored)
+
+	// instance using other namespace does not see it
+	otherCfg := cfg
+	otherCfg.Etcd = etcdCfg.CloneWithSingleWriter()
+	otherCfg.Etcd.Namespace = "other"
+	other, err := GetDBBackend(&otherCfg)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = other.Close()
+	})
+
+	require.NoError(t, kvdb.View(other, func(tx kvdb.RTx) error {
+		require.Nil(t, tx.ReadBucket(bucket))
+		return nil
+	}, func() {}))
+}
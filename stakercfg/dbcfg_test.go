@@ -0,0 +1,37 @@
+package stakercfg
+
+import (
+	"testing"
+
+	"github.com/lightningnetwork/lnd/kvdb"
+	"github.com/lightningnetwork/lnd/kvdb/etcd"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateEtcdDBConfig(t *testing.T) {
+	t.Parallel()
+
+	cfg := DefaultDBConfig()
+	cfg.Backend = EtcdBackend
+
+	if !kvdb.EtcdBackend {
+		require.ErrorContains(t, cfg.Validate(), "must be built with kvdb_etcd tag")
+		return
+	}
+
+	require.ErrorContains(t, cfg.Validate(), "etcd host must be set")
+
+	cfg.Etcd.Host = "localhost:2379"
+	require.NoError(t, cfg.Validate())
+
+	cfg.Etcd = &etcd.Config{Embedded: true, Host: "localhost:2379"}
+	require.ErrorContains(t, cfg.Validate(), "embedded etcd is not supported")
+}
+
+func TestValidateUnknownDBBackend(t *testing.T) {
+	t.Parallel()
+
+	cfg := DefaultDBConfig()
+	cfg.Backend = "leveldb"
+	require.ErrorContains(t, cfg.Validate(), `invalid db backend "leveldb"`)
+}
@@ -0,0 +1,46 @@
+package stakercfg
+
+import (
+	"errors"
+	"time"
+)
+
+const (
+	defaultEventLogMaxEvents     = 1_000_000
+	defaultEventLogCompactAge    = 30 * 24 * time.Hour
+	defaultEventLogPruneInterval = time.Hour
+)
+
+// EventLogConfig defines retention of the persisted changelog, which backs db
+// change stream and webhook events. Changes not delivered to the webhook yet
+// are never removed.
+type EventLogConfig struct {
+	MaxEvents     uint64        `long:"maxevents" description:"Maximum number of kept events, oldest events are removed first. 0 keeps all events"`
+	MaxAge        time.Duration `long:"maxage" description:"Maximum age of kept events. 0 keeps events of any age"`
+	CompactAge    time.Duration `long:"compactage" description:"Age after which events of delegations which are no longer tracked are removed, except for their deletion event. 0 disables compaction"`
+	PruneInterval time.Duration `long:"pruneinterval" description:"The interval in which retention is applied"`
+}
+
+func DefaultEventLogConfig() EventLogConfig {
+	return EventLogConfig{
+		MaxEvents:     defaultEventLogMaxEvents,
+		CompactAge:    defaultEventLogCompactAge,
+		PruneInterval: defaultEventLogPruneInterval,
+	}
+}
+
+func (cfg *EventLogConfig) Validate() error {
+	if cfg.MaxAge < 0 {
+		return errors.New("event log max age must not be negative")
+	}
+
+	if cfg.CompactAge < 0 {
+		return errors.New("event log compact age must not be negative")
+	}
+
+	if cfg.PruneInterval <= 0 {
+		return errors.New("event log prune interval must be positive")
+	}
+
+	return nil
+}
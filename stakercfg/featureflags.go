@@ -0,0 +1,45 @@
+package stakercfg
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Experimental features, disabled unless enabled in feature flags config
+const (
+	// FeatureStakeExpansion allows to expand active delegation with new
+	// staking transaction spending its staking output
+	FeatureStakeExpansion = "stake-expansion"
+)
+
+// ExperimentalFeatures are all features which can be enabled by feature flags
+var ExperimentalFeatures = []string{
+	FeatureStakeExpansion,
+}
+
+// FeatureFlagsConfig selects experimental features enabled in the daemon.
+// Experimental features may change or be removed between releases.
+type FeatureFlagsConfig struct {
+	Enable []string `long:"enable" description:"Experimental feature to enable. Can be specified multiple times. Supported: stake-expansion"`
+}
+
+func DefaultFeatureFlagsConfig() FeatureFlagsConfig {
+	return FeatureFlagsConfig{}
+}
+
+func (cfg *FeatureFlagsConfig) Validate() error {
+	for _, f := range cfg.Enable {
+		if !slices.Contains(ExperimentalFeatures, f) {
+			return fmt.Errorf("unknown experimental feature %s, supported features: %s",
+				f, strings.Join(ExperimentalFeatures, ", "))
+		}
+	}
+
+	return nil
+}
+
+// Enabled returns true if the experimental feature was enabled
+func (cfg *FeatureFlagsConfig) Enabled(feature string) bool {
+	return cfg != nil && slices.Contains(cfg.Enable, feature)
+}
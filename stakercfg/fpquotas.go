@@ -0,0 +1,71 @@
+package stakercfg
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+	"github.com/btcsuite/btcd/btcutil"
+)
+
+// AnyFinalityProvider is key of quota applied to finality providers without
+// own quota
+const AnyFinalityProvider = "*"
+
+// FinalityProviderQuota limits delegations of the staker to single finality
+// provider. Zero limit means no limit.
+type FinalityProviderQuota struct {
+	MaxDelegations uint64
+	MaxAmount      btcutil.Amount
+}
+
+// FinalityProviderQuotas returns quotas by hex encoded x-only public key of
+// finality provider, quota of AnyFinalityProvider applies to the others
+func (cfg *StakerConfig) FinalityProviderQuotas() (map[string]FinalityProviderQuota, error) {
+	quotas := make(map[string]FinalityProviderQuota, len(cfg.FpQuotas))
+
+	for _, q := range cfg.FpQuotas {
+		parts := strings.Split(q, ":")
+		if len(parts) != 3 {
+			return nil, fmt.Errorf("finality provider quota %q must be in format fp_btc_pk:max_delegations:max_total_sats", q)
+		}
+
+		fp := strings.ToLower(parts[0])
+		if fp != AnyFinalityProvider {
+			pk, err := hex.DecodeString(fp)
+			if err != nil {
+				return nil, fmt.Errorf("invalid finality provider key of quota %q: %w", q, err)
+			}
+			if _, err := schnorr.ParsePubKey(pk); err != nil {
+				return nil, fmt.Errorf("invalid finality provider key of quota %q: %w", q, err)
+			}
+		}
+
+		maxDelegations, err := strconv.ParseUint(parts[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid max delegations of quota %q: %w", q, err)
+		}
+
+		maxAmount, err := strconv.ParseInt(parts[2], 10, 64)
+		if err != nil || maxAmount < 0 {
+			return nil, fmt.Errorf("invalid max total sats of quota %q", q)
+		}
+
+		if maxDelegations == 0 && maxAmount == 0 {
+			return nil, fmt.Errorf("finality provider quota %q does not limit anything", q)
+		}
+
+		if _, ok := quotas[fp]; ok {
+			return nil, fmt.Errorf("duplicate quota of finality provider %s", fp)
+		}
+
+		quotas[fp] = FinalityProviderQuota{
+			MaxDelegations: maxDelegations,
+			MaxAmount:      btcutil.Amount(maxAmount),
+		}
+	}
+
+	return quotas, nil
+}
@@ -0,0 +1,148 @@
+package stakercfg
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	day  = 24 * time.Hour
+	week = 7 * day
+)
+
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday,
+	"mon": time.Monday,
+	"tue": time.Tuesday,
+	"wed": time.Wednesday,
+	"thu": time.Thursday,
+	"fri": time.Friday,
+	"sat": time.Saturday,
+}
+
+// MaintenanceWindow is a period during which non urgent broadcasts and Babylon
+// submissions are deferred. Window is either one-off, or repeats every day or
+// every week. Recurring windows are in UTC.
+type MaintenanceWindow struct {
+	// Spec is the window as configured
+	Spec     string
+	Duration time.Duration
+	// start of one-off window, zero for recurring windows
+	start time.Time
+	// period of recurring window, zero for one-off window
+	period time.Duration
+	// weekday of weekly window
+	weekday time.Weekday
+	// start of recurring window since midnight
+	offset time.Duration
+}
+
+// ParseMaintenanceWindow parses window in format <schedule>/<duration>, where
+// schedule is RFC3339 time of one-off window, "daily HH:MM" or "<weekday>
+// HH:MM" with three letter weekday, e.g. "sat 02:00/2h"
+func ParseMaintenanceWindow(spec string) (*MaintenanceWindow, error) {
+	i := strings.LastIndex(spec, "/")
+	if i < 0 {
+		return nil, fmt.Errorf("maintenance window %q must be in format <schedule>/<duration>", spec)
+	}
+
+	duration, err := time.ParseDuration(spec[i+1:])
+	if err != nil || duration <= 0 {
+		return nil, fmt.Errorf("invalid duration of maintenance window %q", spec)
+	}
+
+	w := &MaintenanceWindow{
+		Spec:     spec,
+		Duration: duration,
+	}
+
+	schedule := strings.TrimSpace(spec[:i])
+	if start, err := time.Parse(time.RFC3339, schedule); err == nil {
+		w.start = start
+		return w, nil
+	}
+
+	fields := strings.Fields(strings.ToLower(schedule))
+	if len(fields) != 2 {
+		return nil, fmt.Errorf("invalid schedule of maintenance window %q", spec)
+	}
+
+	if fields[0] == "daily" {
+		w.period = day
+	} else if wd, ok := weekdays[fields[0]]; ok {
+		w.period = week
+		w.weekday = wd
+	} else {
+		return nil, fmt.Errorf("invalid schedule of maintenance window %q, expected daily or weekday", spec)
+	}
+
+	at, err := time.Parse("15:04", fields[1])
+	if err != nil {
+		return nil, fmt.Errorf("invalid time of maintenance window %q: %w", spec, err)
+	}
+	w.offset = time.Duration(at.Hour())*time.Hour + time.Duration(at.Minute())*time.Minute
+
+	if duration >= w.period {
+		return nil, fmt.Errorf("maintenance window %q must be shorter than its period", spec)
+	}
+
+	return w, nil
+}
+
+// starts returns the latest start of the window not after t and the first
+// start after t. Either is zero if there is none.
+func (w *MaintenanceWindow) starts(t time.Time) (time.Time, time.Time) {
+	if w.period == 0 {
+		if t.Before(w.start) {
+			return time.Time{}, w.start
+		}
+		return w.start, time.Time{}
+	}
+
+	t = t.UTC()
+	start := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	if w.period == week {
+		daysSince := (int(t.Weekday()) - int(w.weekday) + 7) % 7
+		start = start.AddDate(0, 0, -daysSince)
+	}
+	start = start.Add(w.offset)
+
+	if start.After(t) {
+		return start.Add(-w.period), start
+	}
+	return start, start.Add(w.period)
+}
+
+// Active returns end of the window if t is within it
+func (w *MaintenanceWindow) Active(t time.Time) (time.Time, bool) {
+	last, _ := w.starts(t)
+	if last.IsZero() {
+		return time.Time{}, false
+	}
+
+	end := last.Add(w.Duration)
+	return end, t.Before(end)
+}
+
+// NextStart returns the first start of the window after t, zero if the window
+// does not start again
+func (w *MaintenanceWindow) NextStart(t time.Time) time.Time {
+	_, next := w.starts(t)
+	return next
+}
+
+// MaintenanceSchedule returns configured maintenance windows
+func (cfg *StakerConfig) MaintenanceSchedule() ([]MaintenanceWindow, error) {
+	windows := make([]MaintenanceWindow, 0, len(cfg.MaintenanceWindows))
+
+	for _, spec := range cfg.MaintenanceWindows {
+		w, err := ParseMaintenanceWindow(spec)
+		if err != nil {
+			return nil, err
+		}
+		windows = append(windows, *w)
+	}
+
+	return windows, nil
+}
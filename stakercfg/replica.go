@@ -0,0 +1,83 @@
+package stakercfg
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+)
+
+const (
+	defaultReplicaPollWait      = 30 * time.Second
+	defaultReplicaRetryInterval = 5 * time.Second
+	defaultReplicaBatchSize     = 100
+
+	// limits of subscribe_db_changes of the primary
+	maxReplicaPollWait  = 60 * time.Second
+	maxReplicaBatchSize = 1000
+)
+
+// ReplicaConfig runs the daemon as read only replica of a primary daemon. Replica
+// follows database changes of the primary and serves read only part of the
+// rpc api, without broadcasting anything or submitting to Babylon.
+type ReplicaConfig struct {
+	PrimaryAddress string        `long:"primaryaddress" description:"RPC address of the primary staker daemon, e.g. tcp://primary:15812. Non empty address runs this daemon as read only replica"`
+	PrimaryUser    string        `long:"primaryuser" description:"Username for the rpc of the primary. Can be a secret reference"`
+	PrimaryPass    string        `long:"primarypass" default-mask:"-" description:"Password for the rpc of the primary. Can be a secret reference"`
+	PollWait       time.Duration `long:"pollwait" description:"How long a single request to the primary waits for new changes"`
+	RetryInterval  time.Duration `long:"retryinterval" description:"Initial interval between retries of failed replication, doubled up to 5 minutes"`
+	BatchSize      uint64        `long:"batchsize" description:"Maximum number of changes of the primary fetched at once"`
+}
+
+func DefaultReplicaConfig() ReplicaConfig {
+	return ReplicaConfig{
+		PollWait:      defaultReplicaPollWait,
+		RetryInterval: defaultReplicaRetryInterval,
+		BatchSize:     defaultReplicaBatchSize,
+	}
+}
+
+// Enabled returns true if the daemon runs as replica
+func (cfg *ReplicaConfig) Enabled() bool {
+	return cfg != nil && cfg.PrimaryAddress != ""
+}
+
+func (cfg *ReplicaConfig) Validate(clusterCfg *ClusterConfig) error {
+	if !cfg.Enabled() {
+		return nil
+	}
+
+	u, err := url.Parse(cfg.PrimaryAddress)
+	if err != nil {
+		return fmt.Errorf("invalid primary address: %w", err)
+	}
+
+	switch u.Scheme {
+	case "tcp", "http", "https", "unix":
+	default:
+		return fmt.Errorf("unsupported primary address scheme: %s", u.Scheme)
+	}
+
+	if u.User != nil {
+		return errors.New("credentials of the primary must be set with primaryuser and primarypass")
+	}
+
+	// replica never becomes leader, it would take over staking of the primary
+	if clusterCfg.EnableLeaderElection {
+		return errors.New("replica can not take part in leader election")
+	}
+
+	if cfg.PollWait <= 0 || cfg.PollWait > maxReplicaPollWait {
+		return fmt.Errorf("poll wait must be between 1s and %s", maxReplicaPollWait)
+	}
+
+	if cfg.RetryInterval <= 0 {
+		return errors.New("replica retry interval must be positive")
+	}
+
+	if cfg.BatchSize == 0 || cfg.BatchSize > maxReplicaBatchSize {
+		return fmt.Errorf("replica batch size must be between 1 and %d", maxReplicaBatchSize)
+	}
+
+	return nil
+}
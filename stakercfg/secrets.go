@@ -0,0 +1,137 @@
+package stakercfg
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/babylonlabs-io/btc-staker/secrets"
+)
+
+const defaultVaultTimeout = 10 * time.Second
+
+// SecretsConfig configures providers of secrets referenced from other options.
+// Secret options (rpc passwords, wallet passphrase, babylon key, db dsn) can
+// hold reference in form <scheme>://<ref> instead of plaintext value, where
+// scheme is one of env, file, vault or awskms.
+type SecretsConfig struct {
+	VaultAddress   string        `long:"vault-address" description:"Address of HashiCorp Vault server used to resolve vault:// references"`
+	VaultToken     string        `long:"vault-token" default-mask:"-" description:"Vault token. Can itself be env:// or file:// reference"`
+	VaultNamespace string        `long:"vault-namespace" description:"Vault enterprise namespace"`
+	VaultCACert    string        `long:"vault-ca-cert" description:"Path to PEM encoded CA certificate of vault server"`
+	VaultTimeout   time.Duration `long:"vault-timeout" description:"Timeout of requests to vault"`
+	KMSRegion      string        `long:"kms-region" description:"AWS region of KMS key used to resolve awskms:// references"`
+	KMSProfile     string        `long:"kms-profile" description:"AWS shared config profile used to access KMS"`
+	KMSEndpoint    string        `long:"kms-endpoint" description:"Overrides AWS KMS endpoint"`
+	KMSKeyID       string        `long:"kms-key-id" description:"Id of KMS key used to encrypt secrets, required only for asymmetric keys"`
+}
+
+func DefaultSecretsConfig() SecretsConfig {
+	return SecretsConfig{
+		VaultTimeout: defaultVaultTimeout,
+	}
+}
+
+func (cfg *SecretsConfig) Validate() error {
+	if cfg.VaultTimeout <= 0 {
+		return fmt.Errorf("vault timeout must be greater than 0")
+	}
+
+	return nil
+}
+
+// newResolver builds resolver with providers enabled by the config. Vault
+// provider is enabled when vault address is set and kms provider is always
+// enabled, as its credentials come from the environment.
+func (cfg *SecretsConfig) newResolver(ctx context.Context) (*secrets.Resolver, error) {
+	resolver := secrets.NewResolver()
+
+	if cfg.VaultAddress != "" {
+		token, err := resolver.Resolve(ctx, cfg.VaultToken)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve vault token: %w", err)
+		}
+
+		vault, err := secrets.NewVaultProvider(&secrets.VaultConfig{
+			Address:   cfg.VaultAddress,
+			Token:     token,
+			Namespace: cfg.VaultNamespace,
+			CACert:    cfg.VaultCACert,
+			Timeout:   cfg.VaultTimeout,
+		})
+		if err != nil {
+			return nil, err
+		}
+		resolver.Register(secrets.VaultScheme, vault)
+	}
+
+	kms, err := secrets.NewAWSKMSProvider(&secrets.AWSKMSConfig{
+		Region:   cfg.KMSRegion,
+		Profile:  cfg.KMSProfile,
+		Endpoint: cfg.KMSEndpoint,
+		KeyID:    cfg.KMSKeyID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	resolver.Register(secrets.AWSKMSScheme, kms)
+
+	return resolver, nil
+}
+
+// ResolveSecrets replaces secret references in config with secrets fetched
+// from configured providers
+func ResolveSecrets(ctx context.Context, cfg *Config) error {
+	resolver, err := cfg.SecretsConfig.newResolver(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to create secrets resolver: %w", err)
+	}
+
+	values := []*string{
+		&cfg.WalletConfig.WalletPass,
+		&cfg.WalletRPCConfig.User,
+		&cfg.WalletRPCConfig.Pass,
+		&cfg.BtcNodeBackendConfig.Bitcoind.RPCUser,
+		&cfg.BtcNodeBackendConfig.Bitcoind.RPCPass,
+		&cfg.BtcNodeBackendConfig.Btcd.RPCUser,
+		&cfg.BtcNodeBackendConfig.Btcd.RPCPass,
+		&cfg.BabylonConfig.KeyArmor,
+		&cfg.BabylonConfig.KeyArmorPassphrase,
+		&cfg.BabylonConfig.RemoteSignerToken,
+		&cfg.ThresholdSignerConfig.Token,
+		&cfg.WebhookConfig.Secret,
+		&cfg.ReplicaConfig.PrimaryUser,
+		&cfg.ReplicaConfig.PrimaryPass,
+		&cfg.DBConfig.IntegrityKey,
+	}
+
+	if cfg.DBConfig.Etcd != nil {
+		values = append(values, &cfg.DBConfig.Etcd.User, &cfg.DBConfig.Etcd.Pass)
+	}
+
+	if cfg.DBConfig.Postgres != nil {
+		values = append(values, &cfg.DBConfig.Postgres.Dsn)
+	}
+
+	if err := resolver.ResolveAll(ctx, values...); err != nil {
+		return fmt.Errorf("failed to resolve secrets: %w", err)
+	}
+
+	// only password part of approver credentials can be a reference
+	for i, a := range cfg.ApprovalConfig.Approvers {
+		user, pass, ok := strings.Cut(a, ":")
+		if !ok {
+			continue
+		}
+
+		resolved, err := resolver.Resolve(ctx, pass)
+		if err != nil {
+			return fmt.Errorf("failed to resolve password of approver %s: %w", user, err)
+		}
+
+		cfg.ApprovalConfig.Approvers[i] = user + ":" + resolved
+	}
+
+	return nil
+}
@@ -0,0 +1,46 @@
+package stakercfg
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg"
+)
+
+// SigningPolicyConfig defines limits every transaction must satisfy before
+// staker signs it. Policy is defense in depth against bugs in transaction
+// building and against malicious RPC callers. Zero limits are not enforced.
+type SigningPolicyConfig struct {
+	Enabled          bool     `long:"enabled" description:"Validate every transaction before signing and refuse to sign transactions violating the policy"`
+	MaxFee           uint64   `long:"maxfee" description:"Maximum fee in satoshis paid by signed transaction. 0 means no limit"`
+	MaxFeeRate       uint64   `long:"maxfeerate" description:"Maximum fee rate in sat/vbyte of fully signed transaction. 0 means no limit"`
+	MaxSpendValue    uint64   `long:"maxspendvalue" description:"Maximum total value in satoshis of wallet inputs spent by signed transaction. Staking and unbonding outputs spent by unbonding, withdrawal and stake expansion do not count. 0 means no limit"`
+	MinStakingTime   uint16   `long:"minstakingtime" description:"Minimum staking time in blocks of new delegations. 0 means no limit"`
+	MaxStakingTime   uint16   `long:"maxstakingtime" description:"Maximum staking time in blocks of new delegations and maximum relative timelock of signed transaction inputs. 0 means no limit"`
+	AllowedAddresses []string `long:"allowedaddress" description:"Address not controlled by the wallet which signed transactions may pay to. External change and withdrawal addresses are always allowed. Can be specified multiple times"`
+}
+
+func DefaultSigningPolicyConfig() SigningPolicyConfig {
+	return SigningPolicyConfig{
+		Enabled: false,
+	}
+}
+
+func (cfg *SigningPolicyConfig) Validate(net *chaincfg.Params) error {
+	if !cfg.Enabled {
+		return nil
+	}
+
+	if cfg.MaxStakingTime > 0 && cfg.MinStakingTime > cfg.MaxStakingTime {
+		return errors.New("min staking time must not be greater than max staking time")
+	}
+
+	for _, addr := range cfg.AllowedAddresses {
+		if _, err := btcutil.DecodeAddress(addr, net); err != nil {
+			return fmt.Errorf("invalid allowed address %s: %w", addr, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,117 @@
+package stakercfg
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/mempool"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+)
+
+const (
+	// OutputOrderStakingFirst puts staking output first, followed by extra
+	// outputs in configured order and change output
+	OutputOrderStakingFirst = "staking-first"
+	// OutputOrderBIP69 sorts inputs and outputs of staking transaction
+	// according to BIP69
+	OutputOrderBIP69 = "bip69"
+
+	// ReferenceCommitmentSize is size of commitment to internal reference
+	// which follows the marker in OP_RETURN output of staking transaction
+	ReferenceCommitmentSize = 32
+)
+
+// ParsedStakingTxExtraOutputs returns address outputs added to staking
+// transactions funded by the wallet
+func (cfg *StakerConfig) ParsedStakingTxExtraOutputs(net *chaincfg.Params) ([]*wire.TxOut, error) {
+	outputs := make([]*wire.TxOut, 0, len(cfg.StakingTxExtraOutputs))
+
+	for _, o := range cfg.StakingTxExtraOutputs {
+		out, err := parseExtraOutput(o, net)
+		if err != nil {
+			return nil, fmt.Errorf("invalid staking transaction extra output %s: %w", o, err)
+		}
+		outputs = append(outputs, out)
+	}
+
+	return outputs, nil
+}
+
+// StakingTxMarkerData returns data of OP_RETURN marker added to staking
+// transactions funded by the wallet, nil if marker is not configured. Marker
+// leaves room for reference commitment, so that both fit into one standard
+// OP_RETURN output.
+func (cfg *StakerConfig) StakingTxMarkerData() ([]byte, error) {
+	if cfg.StakingTxMarker == "" {
+		return nil, nil
+	}
+
+	data, err := hex.DecodeString(cfg.StakingTxMarker)
+	if err != nil {
+		return nil, fmt.Errorf("invalid staking transaction marker: %w", err)
+	}
+
+	if maxLen := txscript.MaxDataCarrierSize - ReferenceCommitmentSize; len(data) > maxLen {
+		return nil, fmt.Errorf("staking transaction marker has %d bytes, at most %d fit with reference commitment",
+			len(data), maxLen)
+	}
+
+	return data, nil
+}
+
+// parseExtraOutput parses output in format address:amount_in_satoshis
+func parseExtraOutput(s string, net *chaincfg.Params) (*wire.TxOut, error) {
+	addrStr, amountStr, found := strings.Cut(s, ":")
+	if !found {
+		return nil, fmt.Errorf("expected format address:amount")
+	}
+
+	addr, err := btcutil.DecodeAddress(addrStr, net)
+	if err != nil {
+		return nil, fmt.Errorf("invalid address: %w", err)
+	}
+
+	// addresses of all networks decode successfully
+	if !addr.IsForNet(net) {
+		return nil, fmt.Errorf("address is not for network %s", net.Name)
+	}
+
+	amount, err := strconv.ParseInt(amountStr, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid amount: %w", err)
+	}
+
+	script, err := txscript.PayToAddrScript(addr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid address: %w", err)
+	}
+
+	out := wire.NewTxOut(amount, script)
+	if mempool.IsDust(out, mempool.DefaultMinRelayTxFee) {
+		return nil, fmt.Errorf("amount %d is dust", amount)
+	}
+
+	return out, nil
+}
+
+// validateStakingOutputs checks output ordering and extra outputs of staking
+// transactions
+func validateStakingOutputs(cfg *StakerConfig, net *chaincfg.Params) error {
+	switch cfg.StakingOutputOrder {
+	case OutputOrderStakingFirst, OutputOrderBIP69:
+	default:
+		return fmt.Errorf("unknown staking output order: %s", cfg.StakingOutputOrder)
+	}
+
+	if _, err := cfg.ParsedStakingTxExtraOutputs(net); err != nil {
+		return err
+	}
+
+	_, err := cfg.StakingTxMarkerData()
+	return err
+}
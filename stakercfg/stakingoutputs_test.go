@@ -0,0 +1,129 @@
+package stakercfg
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/stretchr/testify/require"
+)
+
+func testAddress(t *testing.T, net *chaincfg.Params) btcutil.Address {
+	key, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+
+	addr, err := btcutil.NewAddressTaproot(schnorr.SerializePubKey(key.PubKey()), net)
+	require.NoError(t, err)
+
+	return addr
+}
+
+func TestParseExtraOutput(t *testing.T) {
+	t.Parallel()
+
+	net := &chaincfg.RegressionNetParams
+	addr := testAddress(t, net)
+	mainnetAddr := testAddress(t, &chaincfg.MainNetParams)
+
+	script, err := txscript.PayToAddrScript(addr)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		output  string
+		wantErr string
+	}{
+		{name: "valid", output: addr.EncodeAddress() + ":10000"},
+		{name: "missing amount", output: addr.EncodeAddress(), wantErr: "expected format address:amount"},
+		{name: "invalid address", output: "notanaddress:10000", wantErr: "invalid address"},
+		{name: "address of other network", output: mainnetAddr.EncodeAddress() + ":10000", wantErr: "not for network"},
+		{name: "invalid amount", output: addr.EncodeAddress() + ":ten", wantErr: "invalid amount"},
+		{name: "dust amount", output: addr.EncodeAddress() + ":100", wantErr: "is dust"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			out, err := parseExtraOutput(tc.output, net)
+			if tc.wantErr != "" {
+				require.ErrorContains(t, err, tc.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, int64(10000), out.Value)
+			require.Equal(t, script, out.PkScript)
+		})
+	}
+}
+
+func TestParsedStakingTxExtraOutputs(t *testing.T) {
+	t.Parallel()
+
+	net := &chaincfg.RegressionNetParams
+	first := testAddress(t, net)
+	second := testAddress(t, net)
+
+	cfg := &StakerConfig{
+		StakingTxExtraOutputs: []string{
+			first.EncodeAddress() + ":10000",
+			second.EncodeAddress() + ":20000",
+		},
+	}
+
+	outputs, err := cfg.ParsedStakingTxExtraOutputs(net)
+	require.NoError(t, err)
+	require.Len(t, outputs, 2)
+
+	// configured order is kept
+	firstScript, err := txscript.PayToAddrScript(first)
+	require.NoError(t, err)
+	require.Equal(t, firstScript, outputs[0].PkScript)
+	require.Equal(t, int64(10000), outputs[0].Value)
+	require.Equal(t, int64(20000), outputs[1].Value)
+
+	cfg.StakingTxExtraOutputs = append(cfg.StakingTxExtraOutputs, "invalid")
+	_, err = cfg.ParsedStakingTxExtraOutputs(net)
+	require.ErrorContains(t, err, "invalid staking transaction extra output invalid")
+
+	outputs, err = (&StakerConfig{}).ParsedStakingTxExtraOutputs(net)
+	require.NoError(t, err)
+	require.Empty(t, outputs)
+}
+
+func TestStakingTxMarkerData(t *testing.T) {
+	t.Parallel()
+
+	maxLen := txscript.MaxDataCarrierSize - ReferenceCommitmentSize
+
+	tests := []struct {
+		name     string
+		marker   string
+		expected []byte
+		wantErr  string
+	}{
+		{name: "not configured"},
+		{name: "valid", marker: "6175646974", expected: []byte("audit")},
+		{name: "longest", marker: strings.Repeat("ab", maxLen), expected: []byte(strings.Repeat("\xab", maxLen))},
+		{name: "too long", marker: strings.Repeat("ab", maxLen+1), wantErr: "fit with reference commitment"},
+		{name: "not hex", marker: "marker", wantErr: "invalid staking transaction marker"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := &StakerConfig{StakingTxMarker: tc.marker}
+
+			data, err := cfg.StakingTxMarkerData()
+			if tc.wantErr != "" {
+				require.ErrorContains(t, err, tc.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, data)
+		})
+	}
+}
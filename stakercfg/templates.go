@@ -0,0 +1,205 @@
+package stakercfg
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg"
+)
+
+// maxTemplateNameLen is the maximum length of delegation template name
+const maxTemplateNameLen = 64
+
+// DelegationTemplate holds parameters of routine stakes, so stake requests
+// only need template name and amount
+type DelegationTemplate struct {
+	Name string `json:"name"`
+	// optional, stake request must provide staker address if empty
+	StakerAddress     string   `json:"staker_address,omitempty"`
+	FinalityProviders []string `json:"finality_providers"`
+	StakingTime       uint16   `json:"staking_time"`
+	// cap of estimated fee rate of staking transaction in sat/vbyte, 0 means no cap
+	MaxFeeRate uint64            `json:"max_fee_rate,omitempty"`
+	Tenant     string            `json:"tenant,omitempty"`
+	Labels     map[string]string `json:"labels,omitempty"`
+}
+
+// TemplatesConfig defines delegation templates available to stake requests in
+// addition to templates created through the API
+type TemplatesConfig struct {
+	Templates []string `long:"template" description:"Delegation template in format name;key=value;... Keys are stakeraddress, fp, stakingtime, maxfeerate (sat/vbyte), tenant and label (in format key:value). fp and label can be repeated. Can be specified multiple times"`
+}
+
+func DefaultTemplatesConfig() TemplatesConfig {
+	return TemplatesConfig{}
+}
+
+func (cfg *TemplatesConfig) Validate(net *chaincfg.Params) error {
+	_, err := cfg.Parse(net)
+	return err
+}
+
+// Parse returns templates defined in config
+func (cfg *TemplatesConfig) Parse(net *chaincfg.Params) ([]DelegationTemplate, error) {
+	templates := make([]DelegationTemplate, 0, len(cfg.Templates))
+	names := make(map[string]struct{}, len(cfg.Templates))
+
+	for _, s := range cfg.Templates {
+		t, err := ParseDelegationTemplate(s)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := t.Validate(net); err != nil {
+			return nil, fmt.Errorf("invalid template %s: %w", t.Name, err)
+		}
+
+		if _, ok := names[t.Name]; ok {
+			return nil, fmt.Errorf("duplicate template %s", t.Name)
+		}
+		names[t.Name] = struct{}{}
+
+		templates = append(templates, *t)
+	}
+
+	return templates, nil
+}
+
+// ParseDelegationTemplate parses template in format name;key=value;...
+func ParseDelegationTemplate(s string) (*DelegationTemplate, error) {
+	parts := strings.Split(s, ";")
+
+	t := &DelegationTemplate{
+		Name: strings.TrimSpace(parts[0]),
+	}
+
+	for _, p := range parts[1:] {
+		p = strings.TrimSpace(p)
+		if p == "" {
+			continue
+		}
+
+		key, value, ok := strings.Cut(p, "=")
+		if !ok {
+			return nil, fmt.Errorf("template %s: expected key=value, got %s", t.Name, p)
+		}
+
+		switch key {
+		case "stakeraddress":
+			t.StakerAddress = value
+		case "fp":
+			t.FinalityProviders = append(t.FinalityProviders, value)
+		case "stakingtime":
+			stakingTime, err := strconv.ParseUint(value, 10, 16)
+			if err != nil {
+				return nil, fmt.Errorf("template %s: invalid staking time %s: %w", t.Name, value, err)
+			}
+			t.StakingTime = uint16(stakingTime)
+		case "maxfeerate":
+			maxFeeRate, err := strconv.ParseUint(value, 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("template %s: invalid max fee rate %s: %w", t.Name, value, err)
+			}
+			t.MaxFeeRate = maxFeeRate
+		case "tenant":
+			t.Tenant = value
+		case "label":
+			labelKey, labelValue, ok := strings.Cut(value, ":")
+			if !ok {
+				return nil, fmt.Errorf("template %s: label must be in format key:value, got %s", t.Name, value)
+			}
+			if t.Labels == nil {
+				t.Labels = make(map[string]string)
+			}
+			t.Labels[labelKey] = labelValue
+		default:
+			return nil, fmt.Errorf("template %s: unknown key %s", t.Name, key)
+		}
+	}
+
+	return t, nil
+}
+
+// Validate checks that template is complete and well formed. Tenant is
+// validated by the staker when the template is used.
+func (t *DelegationTemplate) Validate(net *chaincfg.Params) error {
+	if err := validateTemplateName(t.Name); err != nil {
+		return err
+	}
+
+	if t.StakerAddress != "" {
+		if _, err := btcutil.DecodeAddress(t.StakerAddress, net); err != nil {
+			return fmt.Errorf("invalid staker address %s: %w", t.StakerAddress, err)
+		}
+	}
+
+	if len(t.FinalityProviders) == 0 {
+		return errors.New("at least one finality provider is required")
+	}
+
+	if _, err := t.FinalityProviderKeys(); err != nil {
+		return err
+	}
+
+	if t.StakingTime == 0 {
+		return errors.New("staking time must be positive")
+	}
+
+	for k := range t.Labels {
+		if k == "" {
+			return errors.New("label key cannot be empty")
+		}
+	}
+
+	return nil
+}
+
+// FinalityProviderKeys parses hex encoded finality provider public keys
+func (t *DelegationTemplate) FinalityProviderKeys() ([]*btcec.PublicKey, error) {
+	keys := make([]*btcec.PublicKey, 0, len(t.FinalityProviders))
+
+	for _, fp := range t.FinalityProviders {
+		fpBytes, err := hex.DecodeString(fp)
+		if err != nil {
+			return nil, fmt.Errorf("error decoding finality provider public key %s: %w", fp, err)
+		}
+
+		key, err := schnorr.ParsePubKey(fpBytes)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing finality provider public key %s: %w", fp, err)
+		}
+
+		keys = append(keys, key)
+	}
+
+	return keys, nil
+}
+
+// validateTemplateName checks that name consists only of ascii letters, digits,
+// '-', '_' and '.'
+func validateTemplateName(name string) error {
+	if name == "" {
+		return errors.New("template name cannot be empty")
+	}
+
+	if len(name) > maxTemplateNameLen {
+		return fmt.Errorf("template name cannot be longer than %d characters", maxTemplateNameLen)
+	}
+
+	for _, r := range name {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		case r == '-' || r == '_' || r == '.':
+		default:
+			return fmt.Errorf("template name contains invalid character %q", r)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,52 @@
+package stakercfg
+
+import (
+	"fmt"
+	"net/url"
+	"time"
+)
+
+const defaultThresholdSignerTimeout = time.Minute
+
+// ThresholdSignerConfig moves btc staker key to external threshold signing
+// (TSS/MPC) service. Staker address is then BIP86 taproot address of the
+// service key and the wallet only funds transactions.
+type ThresholdSignerConfig struct {
+	URL        string        `long:"url" description:"base url of threshold signing service holding the btc staker key. If set, staking, unbonding and withdrawal transactions of its taproot address are signed by the service instead of the wallet"`
+	KeyID      string        `long:"key-id" description:"id of the staker key in the threshold signing service"`
+	Token      string        `long:"token" default-mask:"-" description:"bearer token used to authenticate to the threshold signing service"`
+	CACert     string        `long:"ca-cert" description:"path to PEM encoded CA certificate of the threshold signing service"`
+	ClientCert string        `long:"client-cert" description:"path to PEM encoded client certificate used for mutual tls with the threshold signing service"`
+	ClientKey  string        `long:"client-key" description:"path to PEM encoded client key used for mutual tls with the threshold signing service"`
+	Timeout    time.Duration `long:"timeout" description:"timeout of signing requests, including the signing ceremony of all parties"`
+}
+
+func DefaultThresholdSignerConfig() ThresholdSignerConfig {
+	return ThresholdSignerConfig{
+		Timeout: defaultThresholdSignerTimeout,
+	}
+}
+
+func (cfg *ThresholdSignerConfig) Validate() error {
+	if cfg.URL == "" {
+		return nil
+	}
+
+	if _, err := url.Parse(cfg.URL); err != nil {
+		return fmt.Errorf("invalid url: %w", err)
+	}
+
+	if cfg.KeyID == "" {
+		return fmt.Errorf("key-id must be set when url is set")
+	}
+
+	if cfg.Timeout <= 0 {
+		return fmt.Errorf("timeout must be greater than 0")
+	}
+
+	if (cfg.ClientCert == "") != (cfg.ClientKey == "") {
+		return fmt.Errorf("client-cert and client-key must be set together")
+	}
+
+	return nil
+}
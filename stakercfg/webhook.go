@@ -0,0 +1,140 @@
+package stakercfg
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	defaultWebhookTimeout       = 10 * time.Second
+	defaultWebhookRetryInterval = 5 * time.Second
+	defaultWebhookBatchSize     = 100
+	defaultAlertDedupWindow     = 10 * time.Minute
+	defaultAlertRateLimit       = "*=20/1h"
+
+	// minWebhookSecretLength is the minimal length of the secret used to sign
+	// webhook payloads
+	minWebhookSecretLength = 16
+)
+
+// WebhookConfig configures delivery of database changes to webhook. Every
+// change is delivered as a separate event, signed with the shared secret.
+type WebhookConfig struct {
+	URL           string        `long:"url" description:"URL to which database changes are POSTed. Empty disables the webhook"`
+	Secret        string        `long:"secret" default-mask:"-" description:"Shared secret used to sign webhook payloads with HMAC-SHA256. Can be a secret reference"`
+	Timeout       time.Duration `long:"timeout" description:"Timeout of single webhook request"`
+	RetryInterval time.Duration `long:"retryinterval" description:"Initial interval between retries of failed delivery, doubled up to 5 minutes"`
+	BatchSize     uint64        `long:"batchsize" description:"Maximum number of changes loaded from the database at once"`
+	// alert events report state of staker and its dependencies, e.g. paused
+	// operations or stuck delegations, and repeat when a dependency flaps
+	AlertDedupWindow time.Duration `long:"alertdedupwindow" description:"Window within which identical alert events are delivered only once. Zero disables deduplication"`
+	AlertRateLimit   []string      `long:"alertratelimit" description:"Maximum number of alert events of a kind delivered per period, in format kind=count/period, e.g. operations_paused=5/1h. Kind * applies to alert kinds without own limit. Can be specified multiple times"`
+}
+
+// AlertRateLimit is maximum number of alert events of a kind delivered per
+// period
+type AlertRateLimit struct {
+	Count  uint64
+	Period time.Duration
+}
+
+func DefaultWebhookConfig() WebhookConfig {
+	return WebhookConfig{
+		Timeout:       defaultWebhookTimeout,
+		RetryInterval: defaultWebhookRetryInterval,
+		BatchSize:     defaultWebhookBatchSize,
+		// alerts of flapping dependency are limited by default, lifecycle
+		// events are never limited
+		AlertDedupWindow: defaultAlertDedupWindow,
+		AlertRateLimit:   []string{defaultAlertRateLimit},
+	}
+}
+
+// AlertRateLimits returns rate limits of alert events by event kind
+func (cfg *WebhookConfig) AlertRateLimits() (map[string]AlertRateLimit, error) {
+	limits := make(map[string]AlertRateLimit, len(cfg.AlertRateLimit))
+
+	for _, l := range cfg.AlertRateLimit {
+		kind, limit, ok := strings.Cut(l, "=")
+		if !ok || kind == "" {
+			return nil, fmt.Errorf("alert rate limit %q must be in format kind=count/period", l)
+		}
+
+		countStr, periodStr, ok := strings.Cut(limit, "/")
+		if !ok {
+			return nil, fmt.Errorf("alert rate limit %q must be in format kind=count/period", l)
+		}
+
+		count, err := strconv.ParseUint(countStr, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid count of alert rate limit %q: %w", l, err)
+		}
+
+		period, err := time.ParseDuration(periodStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid period of alert rate limit %q: %w", l, err)
+		}
+
+		if period <= 0 {
+			return nil, fmt.Errorf("period of alert rate limit %q must be positive", l)
+		}
+
+		if _, ok := limits[kind]; ok {
+			return nil, fmt.Errorf("duplicate alert rate limit of %s", kind)
+		}
+
+		limits[kind] = AlertRateLimit{Count: count, Period: period}
+	}
+
+	return limits, nil
+}
+
+// Enabled returns true if webhook url is configured
+func (cfg *WebhookConfig) Enabled() bool {
+	return cfg.URL != ""
+}
+
+func (cfg *WebhookConfig) Validate() error {
+	if !cfg.Enabled() {
+		return nil
+	}
+
+	u, err := url.Parse(cfg.URL)
+	if err != nil {
+		return fmt.Errorf("invalid webhook url: %w", err)
+	}
+
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("unsupported webhook url scheme: %s", u.Scheme)
+	}
+
+	if len(cfg.Secret) < minWebhookSecretLength {
+		return fmt.Errorf("webhook secret must be at least %d characters long", minWebhookSecretLength)
+	}
+
+	if cfg.Timeout <= 0 {
+		return errors.New("webhook timeout must be positive")
+	}
+
+	if cfg.RetryInterval <= 0 {
+		return errors.New("webhook retry interval must be positive")
+	}
+
+	if cfg.BatchSize == 0 {
+		return errors.New("webhook batch size must be positive")
+	}
+
+	if cfg.AlertDedupWindow < 0 {
+		return errors.New("alert dedup window must not be negative")
+	}
+
+	if _, err := cfg.AlertRateLimits(); err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,243 @@
+package stakerdb
+
+import (
+	"encoding/binary"
+	"fmt"
+	"time"
+
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/lightningnetwork/lnd/kvdb"
+)
+
+var (
+	// mapping txHash -> bigendian(int64) broadcast at || bigendian(int64) confirmed at || bigendian(int64) active at
+	// It holds times at which staker observed btc phases of the delegation
+	// activation, zero if not known. Registration and covenant quorum times
+	// are held in covenant quorum bucket.
+	activationPhasesBucketName = []byte("activationPhases")
+)
+
+const activationPhasesSize = 8 + 8 + 8
+
+// ActivationPhase is a phase of the delegation activation whose time is
+// stored in activation phases bucket
+type ActivationPhase uint8
+
+const (
+	// ActivationPhaseBroadcast is reached when staker sends staking
+	// transaction to btc
+	ActivationPhaseBroadcast ActivationPhase = iota
+	// ActivationPhaseConfirmed is reached when staking transaction is deep
+	// enough to be accepted by Babylon
+	ActivationPhaseConfirmed
+	// ActivationPhaseActive is reached when delegation becomes active on
+	// Babylon
+	ActivationPhaseActive
+)
+
+// String returns a string representation of the activation phase
+func (p ActivationPhase) String() string {
+	switch p {
+	case ActivationPhaseBroadcast:
+		return "broadcast"
+	case ActivationPhaseConfirmed:
+		return "confirmed"
+	case ActivationPhaseActive:
+		return "active"
+	default:
+		return "unknown"
+	}
+}
+
+// ActivationTimeline holds times at which staker observed phases of the
+// delegation activation. Each time is zero if the phase was not reached yet,
+// was not observed by staker, e.g. staking transaction broadcast by other
+// party, or was reached before timings were recorded.
+type ActivationTimeline struct {
+	BroadcastAt      time.Time
+	ConfirmedAt      time.Time
+	RegisteredAt     time.Time
+	CovenantQuorumAt time.Time
+	ActiveAt         time.Time
+}
+
+type activationPhases [3]time.Time
+
+func (p *activationPhases) serialize() []byte {
+	b := make([]byte, activationPhasesSize)
+	for i, t := range p {
+		binary.BigEndian.PutUint64(b[i*8:(i+1)*8], timeToUnixNano(t))
+	}
+	return b
+}
+
+func deserializeActivationPhases(b []byte) (*activationPhases, error) {
+	if len(b) != activationPhasesSize {
+		return nil, fmt.Errorf("invalid activation phases size: %d", len(b))
+	}
+
+	var p activationPhases
+	for i := range p {
+		p[i] = unixNanoToTime(binary.BigEndian.Uint64(b[i*8 : (i+1)*8]))
+	}
+	return &p, nil
+}
+
+// SetActivationPhaseAt stores time at which tracked delegation reached the
+// activation phase. Time is stored only once, returns false if it was already
+// stored.
+func (c *TrackedTransactionStore) SetActivationPhaseAt(
+	txHash *chainhash.Hash,
+	phase ActivationPhase,
+	reachedAt time.Time,
+) (bool, error) {
+	if phase > ActivationPhaseActive {
+		return false, fmt.Errorf("unknown activation phase: %d", phase)
+	}
+
+	var stored bool
+	err := c.update(func(tx kvdb.RwTx) error {
+		transactionIdxBucket := tx.ReadBucket(transactionIndexName)
+		if transactionIdxBucket == nil {
+			return ErrCorruptedTransactionsDB
+		}
+
+		if transactionIdxBucket.Get(txHash[:]) == nil {
+			return ErrTransactionNotFound
+		}
+
+		phasesBucket := tx.ReadWriteBucket(activationPhasesBucketName)
+		if phasesBucket == nil {
+			return ErrCorruptedTransactionsDB
+		}
+
+		phases := &activationPhases{}
+		if v := phasesBucket.Get(txHash[:]); v != nil {
+			var err error
+			phases, err = deserializeActivationPhases(v)
+			if err != nil {
+				return err
+			}
+		}
+
+		if !phases[phase].IsZero() {
+			return nil
+		}
+		phases[phase] = reachedAt
+
+		if err := phasesBucket.Put(txHash.CloneBytes(), phases.serialize()); err != nil {
+			return err
+		}
+
+		stored = true
+		return appendChange(tx, ChangeActivationPhaseReached, txHash, phase.String())
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return stored, nil
+}
+
+func readActivationTimeline(tx kvdb.RTx, txHash []byte) (*ActivationTimeline, error) {
+	phasesBucket := tx.ReadBucket(activationPhasesBucketName)
+	if phasesBucket == nil {
+		return nil, ErrCorruptedTransactionsDB
+	}
+
+	quorumBucket := tx.ReadBucket(covenantQuorumBucketName)
+	if quorumBucket == nil {
+		return nil, ErrCorruptedTransactionsDB
+	}
+
+	phasesBytes := phasesBucket.Get(txHash)
+	quorumBytes := quorumBucket.Get(txHash)
+	if phasesBytes == nil && quorumBytes == nil {
+		return nil, nil
+	}
+
+	var timeline ActivationTimeline
+
+	if phasesBytes != nil {
+		phases, err := deserializeActivationPhases(phasesBytes)
+		if err != nil {
+			return nil, err
+		}
+		timeline.BroadcastAt = phases[ActivationPhaseBroadcast]
+		timeline.ConfirmedAt = phases[ActivationPhaseConfirmed]
+		timeline.ActiveAt = phases[ActivationPhaseActive]
+	}
+
+	if quorumBytes != nil {
+		timing, err := deserializeCovenantQuorumTiming(quorumBytes)
+		if err != nil {
+			return nil, err
+		}
+		timeline.RegisteredAt = timing.RegisteredAt
+		timeline.CovenantQuorumAt = timing.QuorumReachedAt
+	}
+
+	return &timeline, nil
+}
+
+// GetActivationTimeline returns activation timeline of tracked delegation,
+// nil if none of its phases was recorded
+func (c *TrackedTransactionStore) GetActivationTimeline(txHash *chainhash.Hash) (*ActivationTimeline, error) {
+	var timeline *ActivationTimeline
+
+	err := c.db.View(func(tx kvdb.RTx) error {
+		var err error
+		timeline, err = readActivationTimeline(tx, txHash[:])
+		return err
+	}, func() {
+		timeline = nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get activation timeline: %w", err)
+	}
+
+	return timeline, nil
+}
+
+// ListActivationTimelines returns activation timelines of all tracked
+// delegations with at least one recorded phase
+func (c *TrackedTransactionStore) ListActivationTimelines() (map[chainhash.Hash]*ActivationTimeline, error) {
+	timelines := make(map[chainhash.Hash]*ActivationTimeline)
+
+	err := c.db.View(func(tx kvdb.RTx) error {
+		transactionIdxBucket := tx.ReadBucket(transactionIndexName)
+		if transactionIdxBucket == nil {
+			return ErrCorruptedTransactionsDB
+		}
+
+		return transactionIdxBucket.ForEach(func(k, _ []byte) error {
+			// skip transaction count
+			if len(k) != chainhash.HashSize {
+				return nil
+			}
+
+			timeline, err := readActivationTimeline(tx, k)
+			if err != nil {
+				return err
+			}
+			if timeline == nil {
+				return nil
+			}
+
+			hash, err := chainhash.NewHash(k)
+			if err != nil {
+				return err
+			}
+
+			timelines[*hash] = timeline
+			return nil
+		})
+	}, func() {
+		timelines = make(map[chainhash.Hash]*ActivationTimeline)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list activation timelines: %w", err)
+	}
+
+	return timelines, nil
+}
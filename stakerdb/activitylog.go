@@ -0,0 +1,140 @@
+package stakerdb
+
+import (
+	"encoding/binary"
+	"fmt"
+	"time"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/lightningnetwork/lnd/kvdb"
+)
+
+var (
+	// mapping uint64 -> activity event
+	// events are append only and survive deletion of tracked transactions, so
+	// they can be used to show staking activity over time
+	activityBucketName = []byte("activity")
+
+	// key for next activity event
+	nextActivityKey = []byte("nak")
+)
+
+// kind(1) || timestamp(8) || btc height(4) || amount(8) || staking tx hash(32)
+const activityEventSize = 1 + 8 + 4 + 8 + chainhash.HashSize
+
+// ActivityKind is a kind of recorded staking activity
+type ActivityKind uint8
+
+const (
+	// ActivityDelegation is recorded when delegation is sent to babylon
+	ActivityDelegation ActivityKind = iota + 1
+	// ActivityWithdrawal is recorded when transaction withdrawing staked funds
+	// is confirmed on btc
+	ActivityWithdrawal
+)
+
+// String returns a string representation of the activity kind
+func (k ActivityKind) String() string {
+	switch k {
+	case ActivityDelegation:
+		return "delegation"
+	case ActivityWithdrawal:
+		return "withdrawal"
+	default:
+		return "unknown"
+	}
+}
+
+// ActivityEvent is a single staking activity record
+type ActivityEvent struct {
+	Kind          ActivityKind
+	StakingTxHash chainhash.Hash
+	Amount        btcutil.Amount
+	Timestamp     time.Time
+	BtcHeight     uint32
+}
+
+// ActivityEventScanFn is a function which is called for each stored activity event
+type ActivityEventScanFn func(ev *ActivityEvent) error
+
+func (ev *ActivityEvent) serialize() []byte {
+	b := make([]byte, activityEventSize)
+	b[0] = byte(ev.Kind)
+	binary.BigEndian.PutUint64(b[1:9], uint64(ev.Timestamp.Unix()))
+	binary.BigEndian.PutUint32(b[9:13], ev.BtcHeight)
+	binary.BigEndian.PutUint64(b[13:21], uint64(ev.Amount))
+	copy(b[21:], ev.StakingTxHash[:])
+	return b
+}
+
+func deserializeActivityEvent(b []byte) (*ActivityEvent, error) {
+	if len(b) != activityEventSize {
+		return nil, fmt.Errorf("invalid activity event size: %d", len(b))
+	}
+
+	var hash chainhash.Hash
+	copy(hash[:], b[21:])
+
+	return &ActivityEvent{
+		Kind:          ActivityKind(b[0]),
+		Timestamp:     time.Unix(int64(binary.BigEndian.Uint64(b[1:9])), 0),
+		BtcHeight:     binary.BigEndian.Uint32(b[9:13]),
+		Amount:        btcutil.Amount(binary.BigEndian.Uint64(b[13:21])),
+		StakingTxHash: hash,
+	}, nil
+}
+
+// AddActivityEvent appends new event to the activity log
+func (c *TrackedTransactionStore) AddActivityEvent(ev *ActivityEvent) error {
+	if ev == nil {
+		return fmt.Errorf("cannot save nil activity event")
+	}
+
+	return c.update(func(tx kvdb.RwTx) error {
+		activityBucket := tx.ReadWriteBucket(activityBucketName)
+		if activityBucket == nil {
+			return ErrCorruptedTransactionsDB
+		}
+
+		var nextKey uint64
+		if keyBytes := activityBucket.Get(nextActivityKey); keyBytes != nil {
+			nextKey = binary.BigEndian.Uint64(keyBytes)
+		}
+
+		if err := activityBucket.Put(uint64KeyToBytes(nextKey), ev.serialize()); err != nil {
+			return fmt.Errorf("failed to save activity event: %w", err)
+		}
+
+		if err := activityBucket.Put(nextActivityKey, uint64KeyToBytes(nextKey+1)); err != nil {
+			return err
+		}
+
+		return appendChange(tx, ChangeActivityRecorded, &ev.StakingTxHash, ev.Kind.String())
+	})
+}
+
+// ScanActivityEvents calls scanFunc for every stored activity event in the
+// order in which they were recorded
+func (c *TrackedTransactionStore) ScanActivityEvents(scanFunc ActivityEventScanFn, reset func()) error {
+	return kvdb.View(c.db, func(tx kvdb.RTx) error {
+		activityBucket := tx.ReadBucket(activityBucketName)
+		if activityBucket == nil {
+			return ErrCorruptedTransactionsDB
+		}
+
+		return activityBucket.ForEach(func(k, v []byte) error {
+			// skip the counter key
+			if len(k) != 8 {
+				return nil
+			}
+
+			ev, err := deserializeActivityEvent(v)
+			if err != nil {
+				return err
+			}
+
+			return scanFunc(ev)
+		})
+	}, reset)
+}
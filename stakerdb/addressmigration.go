@@ -0,0 +1,109 @@
+package stakerdb
+
+import (
+	"fmt"
+
+	"github.com/babylonlabs-io/btc-staker/proto"
+	"github.com/babylonlabs-io/btc-staker/utils"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/lightningnetwork/lnd/kvdb"
+	pm "google.golang.org/protobuf/proto"
+)
+
+// MigrateStakerAddressesToNet converts staker addresses of tracked
+// transactions, which were stored while staker ran on a different network, to
+// the given network. Converted address pays to the same output script.
+// Addresses of unknown networks are counted as errors and left unchanged.
+// Database is bound to the given network if all addresses belong to it after
+// the migration.
+func (c *TrackedTransactionStore) MigrateStakerAddressesToNet(params *chaincfg.Params) (*MigrationResult, error) {
+	result := &MigrationResult{}
+
+	err := c.update(func(tx kvdb.RwTx) error {
+		*result = MigrationResult{}
+
+		txBucket := tx.ReadWriteBucket(transactionBucketName)
+		if txBucket == nil {
+			return ErrCorruptedTransactionsDB
+		}
+
+		type migratedTx struct {
+			key  []byte
+			ttx  *proto.TrackedTransaction
+			from string
+		}
+
+		// bucket can't be modified while iterating over it
+		var migrated []migratedTx
+		err := txBucket.ForEach(func(k, v []byte) error {
+			result.ProcessedCount++
+
+			var ttx proto.TrackedTransaction
+			if err := pm.Unmarshal(v, &ttx); err != nil {
+				return err
+			}
+
+			if _, err := utils.DecodeAddressForNet(ttx.StakerAddress, params); err == nil {
+				result.SkippedCount++
+				return nil
+			}
+
+			addr, err := utils.ConvertAddressToNet(ttx.StakerAddress, params)
+			if err != nil {
+				result.ErrorCount++
+				return nil
+			}
+
+			from := ttx.StakerAddress
+			ttx.StakerAddress = addr.EncodeAddress()
+			migrated = append(migrated, migratedTx{
+				key:  append([]byte(nil), k...),
+				ttx:  &ttx,
+				from: from,
+			})
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, m := range migrated {
+			storedTx, err := protoTxToStoredTransaction(m.ttx)
+			if err != nil {
+				return err
+			}
+
+			marshalled, err := pm.Marshal(m.ttx)
+			if err != nil {
+				return fmt.Errorf("failed to marshal tracked transaction: %w", err)
+			}
+
+			if err := putTrackedTransaction(tx, txBucket, m.key, marshalled, c.integrityKey); err != nil {
+				return fmt.Errorf("failed to update transaction at key %x: %w", m.key, err)
+			}
+
+			txHash := storedTx.StakingTx.TxHash()
+			if err := appendChange(tx, ChangeStakerAddressMigrated, &txHash, m.from+" -> "+m.ttx.StakerAddress); err != nil {
+				return err
+			}
+
+			result.MigratedCount++
+		}
+
+		if result.ErrorCount > 0 {
+			return nil
+		}
+
+		networkBucket := tx.ReadWriteBucket(networkBucketName)
+		if networkBucket == nil {
+			return ErrCorruptedTransactionsDB
+		}
+
+		return putNetwork(networkBucket, networkOf(params))
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to migrate staker addresses: %w", err)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,307 @@
+package stakerdb
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/lightningnetwork/lnd/kvdb"
+)
+
+var (
+	// mapping txHash -> bigendian(uint16) staking time of renewed delegation
+	// It holds delegations which are restaked once their staking timelock
+	// expires. Zero staking time keeps staking time of the delegation.
+	autoRenewBucketName = []byte("autoRenew")
+
+	// mapping txHash -> json encoded renewal job
+	// It holds renewals of expired delegations, so that renewal interrupted by
+	// restart is resumed
+	renewalJobsBucketName = []byte("renewalJobs")
+)
+
+// States of renewal jobs
+const (
+	// RenewalJobPending job waits to be run, either for the first time or
+	// after failed attempt
+	RenewalJobPending = "pending"
+	// RenewalJobRunning job is withdrawing and restaking funds
+	RenewalJobRunning = "running"
+	RenewalJobDone    = "done"
+	RenewalJobFailed  = "failed"
+)
+
+// RenewalJob is renewal of expired delegation with enabled auto renew
+type RenewalJob struct {
+	StakingTxHash chainhash.Hash `json:"-"`
+	// StakingTime of the renewed delegation, zero keeps staking time of the
+	// expired delegation
+	StakingTime uint16 `json:"staking_time"`
+	State       string `json:"state"`
+	Attempts    uint32 `json:"attempts"`
+	// WithdrawalTxHash and NewStakingTxHash are set once the transactions are
+	// sent
+	WithdrawalTxHash string `json:"withdrawal_tx_hash,omitempty"`
+	NewStakingTxHash string `json:"new_staking_tx_hash,omitempty"`
+	// Error of the last failed attempt
+	Error     string    `json:"error,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// SetAutoRenew enables renewal of tracked delegation once its staking timelock
+// expires, replacing previous renewal settings
+func (c *TrackedTransactionStore) SetAutoRenew(txHash *chainhash.Hash, stakingTime uint16) error {
+	return c.update(func(tx kvdb.RwTx) error {
+		transactionIdxBucket := tx.ReadBucket(transactionIndexName)
+		if transactionIdxBucket == nil {
+			return ErrCorruptedTransactionsDB
+		}
+
+		if transactionIdxBucket.Get(txHash[:]) == nil {
+			return ErrTransactionNotFound
+		}
+
+		renewBucket := tx.ReadWriteBucket(autoRenewBucketName)
+		if renewBucket == nil {
+			return ErrCorruptedTransactionsDB
+		}
+
+		var v [2]byte
+		binary.BigEndian.PutUint16(v[:], stakingTime)
+
+		if err := renewBucket.Put(txHash.CloneBytes(), v[:]); err != nil {
+			return err
+		}
+
+		return appendChange(tx, ChangeAutoRenewSet, txHash, strconv.FormatUint(uint64(stakingTime), 10))
+	})
+}
+
+// ClearAutoRenew disables renewal of tracked delegation. Returns false if
+// renewal was not enabled.
+func (c *TrackedTransactionStore) ClearAutoRenew(txHash *chainhash.Hash) (bool, error) {
+	var cleared bool
+
+	err := c.update(func(tx kvdb.RwTx) error {
+		cleared = false
+
+		renewBucket := tx.ReadWriteBucket(autoRenewBucketName)
+		if renewBucket == nil {
+			return ErrCorruptedTransactionsDB
+		}
+
+		if renewBucket.Get(txHash[:]) == nil {
+			return nil
+		}
+
+		if err := renewBucket.Delete(txHash[:]); err != nil {
+			return err
+		}
+
+		cleared = true
+		return appendChange(tx, ChangeAutoRenewCleared, txHash, "")
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return cleared, nil
+}
+
+// GetAutoRenew returns staking time of renewed delegation. Returns false if
+// renewal of the delegation is not enabled.
+func (c *TrackedTransactionStore) GetAutoRenew(txHash *chainhash.Hash) (uint16, bool, error) {
+	var (
+		stakingTime uint16
+		found       bool
+	)
+
+	err := c.db.View(func(tx kvdb.RTx) error {
+		renewBucket := tx.ReadBucket(autoRenewBucketName)
+		if renewBucket == nil {
+			return ErrCorruptedTransactionsDB
+		}
+
+		v := renewBucket.Get(txHash[:])
+		if v == nil {
+			return nil
+		}
+
+		if len(v) != 2 {
+			return ErrCorruptedTransactionsDB
+		}
+
+		stakingTime = binary.BigEndian.Uint16(v)
+		found = true
+		return nil
+	}, func() {
+		stakingTime = 0
+		found = false
+	})
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to get auto renew: %w", err)
+	}
+
+	return stakingTime, found, nil
+}
+
+// ListAutoRenew returns staking time of every delegation whose renewal is
+// enabled
+func (c *TrackedTransactionStore) ListAutoRenew() (map[chainhash.Hash]uint16, error) {
+	renewals := make(map[chainhash.Hash]uint16)
+
+	err := c.db.View(func(tx kvdb.RTx) error {
+		renewBucket := tx.ReadBucket(autoRenewBucketName)
+		if renewBucket == nil {
+			return ErrCorruptedTransactionsDB
+		}
+
+		return renewBucket.ForEach(func(k, v []byte) error {
+			if len(v) != 2 {
+				return ErrCorruptedTransactionsDB
+			}
+
+			hash, err := chainhash.NewHash(k)
+			if err != nil {
+				return err
+			}
+
+			renewals[*hash] = binary.BigEndian.Uint16(v)
+			return nil
+		})
+	}, func() {
+		renewals = make(map[chainhash.Hash]uint16)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list auto renewals: %w", err)
+	}
+
+	return renewals, nil
+}
+
+// CreateRenewalJob starts renewal of expired delegation. Auto renew of the
+// delegation is disabled in the same db transaction, so renewal is started
+// only once. Returns false if auto renew of the delegation is not enabled.
+func (c *TrackedTransactionStore) CreateRenewalJob(txHash *chainhash.Hash, now time.Time) (*RenewalJob, bool, error) {
+	var (
+		job     *RenewalJob
+		created bool
+	)
+
+	err := c.update(func(tx kvdb.RwTx) error {
+		job = nil
+		created = false
+
+		renewBucket := tx.ReadWriteBucket(autoRenewBucketName)
+		if renewBucket == nil {
+			return ErrCorruptedTransactionsDB
+		}
+
+		jobsBucket := tx.ReadWriteBucket(renewalJobsBucketName)
+		if jobsBucket == nil {
+			return ErrCorruptedTransactionsDB
+		}
+
+		v := renewBucket.Get(txHash[:])
+		if v == nil {
+			return nil
+		}
+
+		if len(v) != 2 {
+			return ErrCorruptedTransactionsDB
+		}
+
+		job = &RenewalJob{
+			StakingTxHash: *txHash,
+			StakingTime:   binary.BigEndian.Uint16(v),
+			State:         RenewalJobPending,
+			CreatedAt:     now,
+			UpdatedAt:     now,
+		}
+
+		encoded, err := json.Marshal(job)
+		if err != nil {
+			return fmt.Errorf("failed to encode renewal job: %w", err)
+		}
+
+		if err := renewBucket.Delete(txHash[:]); err != nil {
+			return err
+		}
+
+		if err := jobsBucket.Put(txHash.CloneBytes(), encoded); err != nil {
+			return err
+		}
+
+		created = true
+		return appendChange(tx, ChangeRenewalJobUpdated, txHash, RenewalJobPending)
+	})
+	if err != nil {
+		return nil, false, err
+	}
+
+	return job, created, nil
+}
+
+// UpdateRenewalJob stores new state of existing renewal job
+func (c *TrackedTransactionStore) UpdateRenewalJob(job *RenewalJob) error {
+	encoded, err := json.Marshal(job)
+	if err != nil {
+		return fmt.Errorf("failed to encode renewal job: %w", err)
+	}
+
+	return c.update(func(tx kvdb.RwTx) error {
+		jobsBucket := tx.ReadWriteBucket(renewalJobsBucketName)
+		if jobsBucket == nil {
+			return ErrCorruptedTransactionsDB
+		}
+
+		if jobsBucket.Get(job.StakingTxHash[:]) == nil {
+			return ErrTransactionNotFound
+		}
+
+		if err := jobsBucket.Put(job.StakingTxHash.CloneBytes(), encoded); err != nil {
+			return err
+		}
+
+		return appendChange(tx, ChangeRenewalJobUpdated, &job.StakingTxHash, job.State)
+	})
+}
+
+// ListRenewalJobs returns renewal jobs of all delegations
+func (c *TrackedTransactionStore) ListRenewalJobs() ([]RenewalJob, error) {
+	var jobs []RenewalJob
+
+	err := c.db.View(func(tx kvdb.RTx) error {
+		jobsBucket := tx.ReadBucket(renewalJobsBucketName)
+		if jobsBucket == nil {
+			return ErrCorruptedTransactionsDB
+		}
+
+		return jobsBucket.ForEach(func(k, v []byte) error {
+			hash, err := chainhash.NewHash(k)
+			if err != nil {
+				return err
+			}
+
+			var job RenewalJob
+			if err := json.Unmarshal(v, &job); err != nil {
+				return err
+			}
+
+			job.StakingTxHash = *hash
+			jobs = append(jobs, job)
+			return nil
+		})
+	}, func() {
+		jobs = nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list renewal jobs: %w", err)
+	}
+
+	return jobs, nil
+}
@@ -0,0 +1,99 @@
+package stakerdb
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/lightningnetwork/lnd/kvdb"
+)
+
+var (
+	// mapping txHash -> json encoded list of Babylon transactions
+	// It holds Babylon transactions staker sent for the delegation
+	babylonTxsBucketName = []byte("babylonTxs")
+)
+
+// BabylonTx is a Babylon transaction sent by staker for the delegation
+type BabylonTx struct {
+	// Kind is the purpose of the transaction e.g. create_delegation
+	Kind   string    `json:"kind"`
+	TxHash string    `json:"tx_hash"`
+	Height int64     `json:"height"`
+	SentAt time.Time `json:"sent_at"`
+	// MsgTypeURL and Msg are type and protobuf encoding of the message sent
+	// in the transaction, empty if it was not recorded
+	MsgTypeURL string `json:"msg_type_url,omitempty"`
+	Msg        []byte `json:"msg,omitempty"`
+}
+
+// RecordBabylonTx appends Babylon transaction sent for tracked delegation
+func (c *TrackedTransactionStore) RecordBabylonTx(txHash *chainhash.Hash, babylonTx *BabylonTx) error {
+	if babylonTx == nil {
+		return fmt.Errorf("cannot save nil babylon transaction")
+	}
+
+	return c.update(func(tx kvdb.RwTx) error {
+		transactionIdxBucket := tx.ReadBucket(transactionIndexName)
+		if transactionIdxBucket == nil {
+			return ErrCorruptedTransactionsDB
+		}
+
+		if transactionIdxBucket.Get(txHash[:]) == nil {
+			return ErrTransactionNotFound
+		}
+
+		babylonTxsBucket := tx.ReadWriteBucket(babylonTxsBucketName)
+		if babylonTxsBucket == nil {
+			return ErrCorruptedTransactionsDB
+		}
+
+		var txs []BabylonTx
+		if v := babylonTxsBucket.Get(txHash[:]); v != nil {
+			if err := json.Unmarshal(v, &txs); err != nil {
+				return err
+			}
+		}
+
+		txs = append(txs, *babylonTx)
+
+		encoded, err := json.Marshal(txs)
+		if err != nil {
+			return fmt.Errorf("failed to encode babylon transactions: %w", err)
+		}
+
+		if err := babylonTxsBucket.Put(txHash.CloneBytes(), encoded); err != nil {
+			return err
+		}
+
+		return appendChange(tx, ChangeBabylonTxRecorded, txHash, babylonTx.Kind+": "+babylonTx.TxHash)
+	})
+}
+
+// GetBabylonTxs returns Babylon transactions sent for tracked delegation,
+// oldest first. Returns nil if none was recorded.
+func (c *TrackedTransactionStore) GetBabylonTxs(txHash *chainhash.Hash) ([]BabylonTx, error) {
+	var txs []BabylonTx
+
+	err := c.db.View(func(tx kvdb.RTx) error {
+		babylonTxsBucket := tx.ReadBucket(babylonTxsBucketName)
+		if babylonTxsBucket == nil {
+			return ErrCorruptedTransactionsDB
+		}
+
+		v := babylonTxsBucket.Get(txHash[:])
+		if v == nil {
+			return nil
+		}
+
+		return json.Unmarshal(v, &txs)
+	}, func() {
+		txs = nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get babylon transactions: %w", err)
+	}
+
+	return txs, nil
+}
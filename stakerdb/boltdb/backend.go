@@ -0,0 +1,159 @@
+// Package boltdb provides bbolt backed walletdb.DB, which unlike the default
+// kvdb bolt backend allows tuning of all bbolt options.
+package boltdb
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/btcsuite/btcwallet/walletdb"
+	"go.etcd.io/bbolt"
+)
+
+const (
+	// FreelistHashmap is freelist type which is fast for large databases with
+	// fragmented free space
+	FreelistHashmap = string(bbolt.FreelistMapType)
+	// FreelistArray is original bbolt freelist type
+	FreelistArray = string(bbolt.FreelistArrayType)
+
+	// DefaultMaxBatchDelay is default maximum delay before batched write
+	// transaction is started, same as bbolt default
+	DefaultMaxBatchDelay = 10 * time.Millisecond
+	// DefaultMaxBatchSize is default maximum size of write batch, same as
+	// bbolt default
+	DefaultMaxBatchSize = 1000
+)
+
+// Options are bbolt options used to open the database
+type Options struct {
+	// NoFreelistSync prevents syncing freelist to disk, improving write
+	// performance at the expense of startup time
+	NoFreelistSync bool
+	// FreelistType is either FreelistHashmap or FreelistArray
+	FreelistType string
+	// NoSync skips fsync after every commit. Unsafe, meant only for tests.
+	NoSync bool
+	// InitialMmapSize is initial size of memory map in bytes. Read
+	// transactions do not block writes only while database fits into it.
+	InitialMmapSize int
+	// MaxBatchDelay is maximum delay before batched write transaction is
+	// started
+	MaxBatchDelay time.Duration
+	// MaxBatchSize is maximum number of calls combined in one batch
+	MaxBatchSize int
+	// Timeout is how long to wait for file lock when opening the database
+	Timeout time.Duration
+}
+
+// Validate checks that options have valid values
+func (o *Options) Validate() error {
+	switch o.FreelistType {
+	case FreelistHashmap, FreelistArray:
+	default:
+		return fmt.Errorf("invalid freelist type %q, supported types: %s, %s",
+			o.FreelistType, FreelistHashmap, FreelistArray)
+	}
+
+	if o.InitialMmapSize < 0 {
+		return errors.New("initial mmap size must not be negative")
+	}
+
+	if o.MaxBatchDelay < 0 {
+		return errors.New("max batch delay must not be negative")
+	}
+
+	if o.MaxBatchSize < 0 {
+		return errors.New("max batch size must not be negative")
+	}
+
+	return nil
+}
+
+// Open opens database at given path, creating it with parent directories if
+// it does not exist
+func Open(dbPath string, opts *Options) (walletdb.DB, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(dbPath), 0700); err != nil {
+		return nil, err
+	}
+
+	boltDB, err := bbolt.Open(dbPath, 0600, &bbolt.Options{
+		Timeout:         opts.Timeout,
+		NoFreelistSync:  opts.NoFreelistSync,
+		FreelistType:    bbolt.FreelistType(opts.FreelistType),
+		InitialMmapSize: opts.InitialMmapSize,
+		NoSync:          opts.NoSync,
+	})
+	if err != nil {
+		return nil, convertErr(err)
+	}
+
+	boltDB.MaxBatchDelay = opts.MaxBatchDelay
+	boltDB.MaxBatchSize = opts.MaxBatchSize
+
+	return (*db)(boltDB), nil
+}
+
+// Stats are size and page statistics of the database
+type Stats struct {
+	FileSize int64
+	PageSize int
+	// number of free pages on the freelist
+	FreePages int
+	// number of pending pages on the freelist, freed by transactions still
+	// used by readers
+	PendingPages int
+	// total bytes allocated in free pages
+	FreeAlloc int
+	// total bytes used by the freelist
+	FreelistInuse int
+	OpenReadTxs   int
+}
+
+func (s *Stats) String() string {
+	return fmt.Sprintf(
+		"file size: %d, page size: %d, free pages: %d, pending pages: %d, "+
+			"free alloc: %d, freelist in use: %d, open read txs: %d",
+		s.FileSize, s.PageSize, s.FreePages, s.PendingPages,
+		s.FreeAlloc, s.FreelistInuse, s.OpenReadTxs,
+	)
+}
+
+// Stats returns current statistics of the database
+func (db *db) Stats() (*Stats, error) {
+	boltDB := (*bbolt.DB)(db)
+
+	fileInfo, err := os.Stat(boltDB.Path())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get database file size: %w", err)
+	}
+
+	stats := boltDB.Stats()
+
+	return &Stats{
+		FileSize:      fileInfo.Size(),
+		PageSize:      boltDB.Info().PageSize,
+		FreePages:     stats.FreePageN,
+		PendingPages:  stats.PendingPageN,
+		FreeAlloc:     stats.FreeAlloc,
+		FreelistInuse: stats.FreelistInuse,
+		OpenReadTxs:   stats.OpenTxN,
+	}, nil
+}
+
+// GetStats returns statistics of database opened by Open
+func GetStats(backend walletdb.DB) (*Stats, error) {
+	boltDB, ok := backend.(*db)
+	if !ok {
+		return nil, fmt.Errorf("database backend %T does not provide stats", backend)
+	}
+
+	return boltDB.Stats()
+}
@@ -0,0 +1,65 @@
+package boltdb_test
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/babylonlabs-io/btc-staker/stakerdb/boltdb"
+	"github.com/btcsuite/btcwallet/walletdb"
+	"github.com/stretchr/testify/require"
+)
+
+func testOptions() *boltdb.Options {
+	return &boltdb.Options{
+		FreelistType:  boltdb.FreelistHashmap,
+		NoSync:        true,
+		MaxBatchDelay: boltdb.DefaultMaxBatchDelay,
+		MaxBatchSize:  boltdb.DefaultMaxBatchSize,
+		Timeout:       time.Second,
+	}
+}
+
+func TestOpenWithOptionsAndStats(t *testing.T) {
+	opts := testOptions()
+	opts.InitialMmapSize = 1 << 20
+
+	db, err := boltdb.Open(filepath.Join(t.TempDir(), "nested", "staker.db"), opts)
+	require.NoError(t, err)
+	defer db.Close()
+
+	bucketKey := []byte("bucket")
+	err = walletdb.Update(db, func(tx walletdb.ReadWriteTx) error {
+		b, err := tx.CreateTopLevelBucket(bucketKey)
+		if err != nil {
+			return err
+		}
+		return b.Put([]byte("key"), []byte("value"))
+	})
+	require.NoError(t, err)
+
+	err = walletdb.View(db, func(tx walletdb.ReadTx) error {
+		require.Equal(t, []byte("value"), tx.ReadBucket(bucketKey).Get([]byte("key")))
+		return nil
+	})
+	require.NoError(t, err)
+
+	stats, err := boltdb.GetStats(db)
+	require.NoError(t, err)
+	require.Positive(t, stats.FileSize)
+	require.Positive(t, stats.PageSize)
+	require.Zero(t, stats.OpenReadTxs)
+}
+
+func TestInvalidOptions(t *testing.T) {
+	opts := testOptions()
+	opts.FreelistType = "list"
+	require.Error(t, opts.Validate())
+
+	opts = testOptions()
+	opts.MaxBatchSize = -1
+	require.Error(t, opts.Validate())
+
+	_, err := boltdb.Open(filepath.Join(t.TempDir(), "staker.db"), opts)
+	require.Error(t, err)
+}
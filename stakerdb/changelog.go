@@ -0,0 +1,542 @@
+package stakerdb
+
+import (
+	"encoding/binary"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/lightningnetwork/lnd/kvdb"
+)
+
+var (
+	// mapping bigendian(uint64) sequence number -> change
+	// every mutation of the store appends a change in the same db transaction,
+	// so the changelog is ordered the same way as mutations are applied
+	changelogBucketName = []byte("changelog")
+
+	// key for the last used sequence number
+	lastChangeSeqKey = []byte("lcs")
+)
+
+// kind(1) || timestamp(8) || staking tx hash(32) || detail(variable)
+const changeHeaderSize = 1 + 8 + chainhash.HashSize
+
+// ChangeKind is a kind of store mutation
+type ChangeKind uint8
+
+const (
+	// ChangeTransactionAdded is recorded when new transaction starts to be tracked
+	ChangeTransactionAdded ChangeKind = iota + 1
+	// ChangeTransactionDeleted is recorded when tracked transaction is deleted
+	ChangeTransactionDeleted
+	// ChangeTransactionFailed is recorded when tracked transaction is marked
+	// as permanently failed, detail holds the reason
+	ChangeTransactionFailed
+	// ChangeCreationHeightSet is recorded when creation height of tracked
+	// transaction is stored, detail holds the height
+	ChangeCreationHeightSet
+	// ChangeFeePaid is recorded when fee paid by transaction of the delegation
+	// is stored, detail holds transaction kind and fee
+	ChangeFeePaid
+	// ChangeWatchedTxUpdated is recorded when state of transaction broadcast
+	// for the delegation changes, detail holds transaction kind and hash
+	ChangeWatchedTxUpdated
+	// ChangeWatchedTxRemoved is recorded when transaction broadcast for the
+	// delegation stops being watched, detail holds transaction kind and hash
+	ChangeWatchedTxRemoved
+	// ChangeOutpointsReleased is recorded when all outpoints reserved by
+	// tracked transaction are released
+	ChangeOutpointsReleased
+	// ChangeOutpointUnreserved is recorded when single outpoint is released,
+	// detail holds the outpoint
+	ChangeOutpointUnreserved
+	// ChangeActivityRecorded is recorded when staking activity event is
+	// stored, detail holds activity kind
+	ChangeActivityRecorded
+	// ChangeStakerAddressAdded is recorded when new staker address is
+	// registered, detail holds the address. Staking tx hash is zero.
+	ChangeStakerAddressAdded
+	// ChangeTenantSet is recorded when tracked transaction is assigned to
+	// a tenant, detail holds the tenant id
+	ChangeTenantSet
+	// ChangeStakeQueued is recorded when stake request is queued until Babylon
+	// accepts new delegations, detail holds the queued stake id. Staking tx
+	// hash is zero.
+	ChangeStakeQueued
+	// ChangeQueuedStakeSubmitted is recorded when queued stake request is
+	// submitted, detail holds the queued stake id
+	ChangeQueuedStakeSubmitted
+	// ChangeQueuedStakeFailed is recorded when queued stake request fails and
+	// is dropped from the queue, detail holds the queued stake id and the
+	// reason. Staking tx hash is zero.
+	ChangeQueuedStakeFailed
+	// ChangeQueuedStakeCancelled is recorded when queued stake request is
+	// cancelled, detail holds the queued stake id. Staking tx hash is zero.
+	ChangeQueuedStakeCancelled
+	// ChangeStakingGateClosed is recorded when staker observes that Babylon
+	// stopped accepting new delegations, detail holds the reason. Staking tx
+	// hash is zero.
+	ChangeStakingGateClosed
+	// ChangeStakingGateOpened is recorded when staker observes that Babylon
+	// accepts new delegations again. Staking tx hash is zero.
+	ChangeStakingGateOpened
+	// ChangeStatusSnapshotRecorded is recorded when Babylon status of tracked
+	// delegation is persisted, detail holds the status
+	ChangeStatusSnapshotRecorded
+	// ChangeDelegationRegistered is recorded when registration time of
+	// tracked delegation on Babylon is stored
+	ChangeDelegationRegistered
+	// ChangeCovenantQuorumReached is recorded when staker observes quorum of
+	// covenant signatures of tracked delegation
+	ChangeCovenantQuorumReached
+	// ChangeDelegationStuck is recorded when tracked delegation is detected
+	// stuck in intermediate state, detail holds the reason
+	ChangeDelegationStuck
+	// ChangeDelegationUnstuck is recorded when stuck delegation moves on or
+	// stops being tracked
+	ChangeDelegationUnstuck
+	// ChangeUnbondRequested is recorded when staker starts sending unbonding
+	// transaction of tracked delegation
+	ChangeUnbondRequested
+	// ChangeRemediationAttempted is recorded when staker runs automatic
+	// remediation of stuck delegation, detail holds the condition
+	ChangeRemediationAttempted
+	// ChangeRemediationFailed is recorded when automatic remediation of stuck
+	// delegation fails, detail holds the condition and the error
+	ChangeRemediationFailed
+	// ChangeOperationsPaused is recorded when staker pauses params sensitive
+	// operations because chain is not safe, detail holds the reason. Staking
+	// tx hash is zero.
+	ChangeOperationsPaused
+	// ChangeOperationsResumed is recorded when chain is safe again. Staking tx
+	// hash is zero.
+	ChangeOperationsResumed
+	// ChangeChainSafetyOverrideSet is recorded when operator changes chain
+	// safety override, detail holds the new value. Staking tx hash is zero.
+	ChangeChainSafetyOverrideSet
+	// ChangeWorkerPanicked is recorded when background worker of staker
+	// panics, detail holds the worker name and the panic. Staking tx hash is
+	// zero.
+	ChangeWorkerPanicked
+	// ChangeBabylonTxRecorded is recorded when staker sends Babylon
+	// transaction for the delegation, detail holds its kind and hash
+	ChangeBabylonTxRecorded
+	// ChangeTransactionImported is recorded when delegation exported from
+	// other staker instance starts to be tracked, detail holds staker address
+	ChangeTransactionImported
+	// ChangeMuSig2SessionCreated is recorded when MuSig2 signing session is
+	// created, detail holds the session id and kind
+	ChangeMuSig2SessionCreated
+	// ChangeMuSig2SessionCompleted is recorded when all signers of MuSig2
+	// signing session provided partial signatures, detail holds the session id
+	ChangeMuSig2SessionCompleted
+	// ChangeSpendableHeightSet is recorded when unbonding transaction of the
+	// delegation is confirmed, or confirmed in other block after reorg, detail
+	// holds btc height at which its output becomes spendable
+	ChangeSpendableHeightSet
+	// ChangeStakeSpendable is recorded when btc chain reaches height at which
+	// unbonded funds of the delegation become spendable, detail holds the height
+	ChangeStakeSpendable
+	// ChangeFailureNoteSet is recorded when failure or problem of the
+	// delegation is noted, detail holds its source and reason
+	ChangeFailureNoteSet
+	// ChangeFailureNoteCleared is recorded when delegation recovered from
+	// noted problem
+	ChangeFailureNoteCleared
+	// ChangeRegistrationRetried is recorded when registration of tracked
+	// delegation on Babylon is retried successfully
+	ChangeRegistrationRetried
+	// ChangeStakerAddressMigrated is recorded when staker address of tracked
+	// delegation is converted to the active network
+	ChangeStakerAddressMigrated
+	// ChangeAutoRenewSet is recorded when renewal of delegation on expiry is
+	// enabled
+	ChangeAutoRenewSet
+	// ChangeAutoRenewCleared is recorded when renewal of delegation on expiry
+	// is disabled
+	ChangeAutoRenewCleared
+	// ChangeRenewalJobUpdated is recorded when renewal job of expired
+	// delegation is created or changes its state
+	ChangeRenewalJobUpdated
+	// ChangeActivationPhaseReached is recorded when staker observes tracked
+	// delegation reaching a phase of its activation
+	ChangeActivationPhaseReached
+	// ChangeUnbondingSignaturesReceived is recorded when verified covenant
+	// signatures of unbonding transaction are stored
+	ChangeUnbondingSignaturesReceived
+	// ChangeUnbondingSignaturesCleaned is recorded when duplicate signatures
+	// or signatures of keys outside of covenant committee are removed from
+	// stored unbonding signatures
+	ChangeUnbondingSignaturesCleaned
+	// ChangeTransactionReplicated is recorded when read only replica stores
+	// delegation as exported by the primary, detail is sequence number of the
+	// change of the primary
+	ChangeTransactionReplicated
+	// ChangeFinalityProvidersSet is recorded when finality providers of
+	// delegation created by the staker are stored, detail is list of their
+	// keys
+	ChangeFinalityProvidersSet
+	// ChangeTransactionQuarantined is recorded when tracked transaction
+	// record fails to decode or validate and is moved to quarantine, detail
+	// holds the reason. Staking tx hash is zero if the record was not indexed.
+	ChangeTransactionQuarantined
+	// ChangeBroadcastHeightSet is recorded when staking transaction of tracked
+	// delegation is broadcast for the first time, detail holds the height
+	ChangeBroadcastHeightSet
+)
+
+// String returns a string representation of the change kind
+func (k ChangeKind) String() string {
+	switch k {
+	case ChangeTransactionAdded:
+		return "transaction_added"
+	case ChangeTransactionDeleted:
+		return "transaction_deleted"
+	case ChangeTransactionFailed:
+		return "transaction_failed"
+	case ChangeCreationHeightSet:
+		return "creation_height_set"
+	case ChangeFeePaid:
+		return "fee_paid"
+	case ChangeWatchedTxUpdated:
+		return "watched_tx_updated"
+	case ChangeWatchedTxRemoved:
+		return "watched_tx_removed"
+	case ChangeOutpointsReleased:
+		return "outpoints_released"
+	case ChangeOutpointUnreserved:
+		return "outpoint_unreserved"
+	case ChangeActivityRecorded:
+		return "activity_recorded"
+	case ChangeStakerAddressAdded:
+		return "staker_address_added"
+	case ChangeTenantSet:
+		return "tenant_set"
+	case ChangeStakeQueued:
+		return "stake_queued"
+	case ChangeQueuedStakeSubmitted:
+		return "queued_stake_submitted"
+	case ChangeQueuedStakeFailed:
+		return "queued_stake_failed"
+	case ChangeQueuedStakeCancelled:
+		return "queued_stake_cancelled"
+	case ChangeStakingGateClosed:
+		return "staking_gate_closed"
+	case ChangeStakingGateOpened:
+		return "staking_gate_opened"
+	case ChangeStatusSnapshotRecorded:
+		return "status_snapshot_recorded"
+	case ChangeDelegationRegistered:
+		return "delegation_registered"
+	case ChangeCovenantQuorumReached:
+		return "covenant_quorum_reached"
+	case ChangeDelegationStuck:
+		return "delegation_stuck"
+	case ChangeDelegationUnstuck:
+		return "delegation_unstuck"
+	case ChangeUnbondRequested:
+		return "unbond_requested"
+	case ChangeRemediationAttempted:
+		return "remediation_attempted"
+	case ChangeRemediationFailed:
+		return "remediation_failed"
+	case ChangeOperationsPaused:
+		return "operations_paused"
+	case ChangeOperationsResumed:
+		return "operations_resumed"
+	case ChangeChainSafetyOverrideSet:
+		return "chain_safety_override_set"
+	case ChangeWorkerPanicked:
+		return "worker_panicked"
+	case ChangeBabylonTxRecorded:
+		return "babylon_tx_recorded"
+	case ChangeTransactionImported:
+		return "transaction_imported"
+	case ChangeMuSig2SessionCreated:
+		return "musig2_session_created"
+	case ChangeMuSig2SessionCompleted:
+		return "musig2_session_completed"
+	case ChangeSpendableHeightSet:
+		return "spendable_height_set"
+	case ChangeStakeSpendable:
+		return "stake_spendable"
+	case ChangeFailureNoteSet:
+		return "failure_note_set"
+	case ChangeFailureNoteCleared:
+		return "failure_note_cleared"
+	case ChangeRegistrationRetried:
+		return "registration_retried"
+	case ChangeStakerAddressMigrated:
+		return "staker_address_migrated"
+	case ChangeAutoRenewSet:
+		return "auto_renew_set"
+	case ChangeAutoRenewCleared:
+		return "auto_renew_cleared"
+	case ChangeRenewalJobUpdated:
+		return "renewal_job_updated"
+	case ChangeActivationPhaseReached:
+		return "activation_phase_reached"
+	case ChangeUnbondingSignaturesReceived:
+		return "unbonding_signatures_received"
+	case ChangeUnbondingSignaturesCleaned:
+		return "unbonding_signatures_cleaned"
+	case ChangeTransactionReplicated:
+		return "transaction_replicated"
+	case ChangeFinalityProvidersSet:
+		return "finality_providers_set"
+	case ChangeTransactionQuarantined:
+		return "transaction_quarantined"
+	case ChangeBroadcastHeightSet:
+		return "broadcast_height_set"
+	default:
+		return "unknown"
+	}
+}
+
+// Change is a single record of the changelog
+type Change struct {
+	// Seq is sequence number of the change, starting from 1
+	Seq           uint64
+	Kind          ChangeKind
+	StakingTxHash chainhash.Hash
+	Detail        string
+	Timestamp     time.Time
+}
+
+func (ch *Change) serialize() []byte {
+	b := make([]byte, changeHeaderSize+len(ch.Detail))
+	b[0] = byte(ch.Kind)
+	binary.BigEndian.PutUint64(b[1:9], uint64(ch.Timestamp.UnixNano()))
+	copy(b[9:changeHeaderSize], ch.StakingTxHash[:])
+	copy(b[changeHeaderSize:], ch.Detail)
+	return b
+}
+
+func deserializeChange(seq uint64, b []byte) (*Change, error) {
+	if len(b) < changeHeaderSize {
+		return nil, fmt.Errorf("invalid change size: %d", len(b))
+	}
+
+	var hash chainhash.Hash
+	copy(hash[:], b[9:changeHeaderSize])
+
+	return &Change{
+		Seq:           seq,
+		Kind:          ChangeKind(b[0]),
+		Timestamp:     time.Unix(0, int64(binary.BigEndian.Uint64(b[1:9]))),
+		StakingTxHash: hash,
+		Detail:        string(b[changeHeaderSize:]),
+	}, nil
+}
+
+// appendChange records change in the db transaction performing the mutation
+func appendChange(tx kvdb.RwTx, kind ChangeKind, stakingTxHash *chainhash.Hash, detail string) error {
+	changelogBucket := tx.ReadWriteBucket(changelogBucketName)
+	if changelogBucket == nil {
+		return ErrCorruptedTransactionsDB
+	}
+
+	var lastSeq uint64
+	if seqBytes := changelogBucket.Get(lastChangeSeqKey); seqBytes != nil {
+		lastSeq = binary.BigEndian.Uint64(seqBytes)
+	}
+
+	ch := &Change{
+		Kind:      kind,
+		Detail:    detail,
+		Timestamp: time.Now(),
+	}
+	if stakingTxHash != nil {
+		ch.StakingTxHash = *stakingTxHash
+	}
+
+	seq := lastSeq + 1
+	if err := changelogBucket.Put(uint64KeyToBytes(seq), ch.serialize()); err != nil {
+		return fmt.Errorf("failed to save change: %w", err)
+	}
+
+	return changelogBucket.Put(lastChangeSeqKey, uint64KeyToBytes(seq))
+}
+
+// update runs mutation of the store and wakes up changelog subscribers once it
+// is committed
+func (c *TrackedTransactionStore) update(f func(tx kvdb.RwTx) error) error {
+	if err := batch(c.db, f); err != nil {
+		return err
+	}
+
+	c.changes.notify()
+	return nil
+}
+
+// RecordDelegationStuck records that tracked delegation got stuck in
+// intermediate state or moved on, so that changelog subscribers are notified.
+// Stuck delegations are detected from live state, so nothing else is stored.
+func (c *TrackedTransactionStore) RecordDelegationStuck(txHash *chainhash.Hash, stuck bool, reason string) error {
+	kind := ChangeDelegationUnstuck
+	if stuck {
+		kind = ChangeDelegationStuck
+	}
+
+	return c.update(func(tx kvdb.RwTx) error {
+		return appendChange(tx, kind, txHash, reason)
+	})
+}
+
+// RecordRemediation records automatic remediation of stuck delegation and
+// its failure, if any, so that changelog subscribers are notified
+func (c *TrackedTransactionStore) RecordRemediation(txHash *chainhash.Hash, condition string, remediationErr error) error {
+	kind := ChangeRemediationAttempted
+	detail := condition
+	if remediationErr != nil {
+		kind = ChangeRemediationFailed
+		detail = fmt.Sprintf("%s: %s", condition, remediationErr)
+	}
+
+	return c.update(func(tx kvdb.RwTx) error {
+		return appendChange(tx, kind, txHash, detail)
+	})
+}
+
+// RecordChainSafetyChange records that params sensitive operations were paused
+// or resumed, so that changelog subscribers are notified
+func (c *TrackedTransactionStore) RecordChainSafetyChange(paused bool, reason string) error {
+	kind := ChangeOperationsResumed
+	if paused {
+		kind = ChangeOperationsPaused
+	}
+
+	return c.update(func(tx kvdb.RwTx) error {
+		return appendChange(tx, kind, nil, reason)
+	})
+}
+
+// RecordChainSafetyOverride records that operator changed chain safety
+// override
+func (c *TrackedTransactionStore) RecordChainSafetyOverride(override bool) error {
+	return c.update(func(tx kvdb.RwTx) error {
+		return appendChange(tx, ChangeChainSafetyOverrideSet, nil, strconv.FormatBool(override))
+	})
+}
+
+// RecordWorkerPanic records that background worker panicked, so that
+// changelog subscribers are notified
+func (c *TrackedTransactionStore) RecordWorkerPanic(worker string, panicMsg string) error {
+	return c.update(func(tx kvdb.RwTx) error {
+		return appendChange(tx, ChangeWorkerPanicked, nil, fmt.Sprintf("%s: %s", worker, panicMsg))
+	})
+}
+
+// QueryChanges returns at most limit changes with sequence number greater
+// than afterSeq, in the order in which they were recorded
+func (c *TrackedTransactionStore) QueryChanges(afterSeq uint64, limit uint64) ([]Change, error) {
+	var changes []Change
+
+	err := c.db.View(func(tx kvdb.RTx) error {
+		changelogBucket := tx.ReadBucket(changelogBucketName)
+		if changelogBucket == nil {
+			return ErrCorruptedTransactionsDB
+		}
+
+		cursor := changelogBucket.ReadCursor()
+		for k, v := cursor.Seek(uint64KeyToBytes(afterSeq + 1)); k != nil; k, v = cursor.Next() {
+			if uint64(len(changes)) >= limit {
+				break
+			}
+
+			// skip the sequence key
+			if len(k) != 8 {
+				continue
+			}
+
+			ch, err := deserializeChange(binary.BigEndian.Uint64(k), v)
+			if err != nil {
+				return err
+			}
+			changes = append(changes, *ch)
+		}
+
+		return nil
+	}, func() {
+		changes = nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query changes: %w", err)
+	}
+
+	return changes, nil
+}
+
+// ChangesOf returns all changes of tracked delegation, in the order in which
+// they were recorded
+func (c *TrackedTransactionStore) ChangesOf(txHash *chainhash.Hash) ([]Change, error) {
+	var changes []Change
+
+	err := c.db.View(func(tx kvdb.RTx) error {
+		changelogBucket := tx.ReadBucket(changelogBucketName)
+		if changelogBucket == nil {
+			return ErrCorruptedTransactionsDB
+		}
+
+		return changelogBucket.ForEach(func(k, v []byte) error {
+			// skip the sequence key
+			if len(k) != 8 {
+				return nil
+			}
+
+			ch, err := deserializeChange(binary.BigEndian.Uint64(k), v)
+			if err != nil {
+				return err
+			}
+
+			if ch.StakingTxHash == *txHash {
+				changes = append(changes, *ch)
+			}
+			return nil
+		})
+	}, func() {
+		changes = nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query changes of transaction: %w", err)
+	}
+
+	return changes, nil
+}
+
+// ChangesNotify returns channel which is closed once any change is committed
+// after this call. Callers should query changes after receiving the channel,
+// so that no change is missed.
+func (c *TrackedTransactionStore) ChangesNotify() <-chan struct{} {
+	return c.changes.wait()
+}
+
+// changeNotifier wakes up all waiters on every committed change
+type changeNotifier struct {
+	mu sync.Mutex
+	ch chan struct{}
+}
+
+func newChangeNotifier() *changeNotifier {
+	return &changeNotifier{
+		ch: make(chan struct{}),
+	}
+}
+
+func (n *changeNotifier) wait() <-chan struct{} {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	return n.ch
+}
+
+func (n *changeNotifier) notify() {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	close(n.ch)
+	n.ch = make(chan struct{})
+}
@@ -0,0 +1,200 @@
+package stakerdb
+
+import (
+	"encoding/binary"
+	"fmt"
+	"time"
+
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/lightningnetwork/lnd/kvdb"
+)
+
+// maxPrunedPerTx bounds number of changes removed in one db transaction, so
+// that pruning of large changelog does not block writers for long
+const maxPrunedPerTx = 1000
+
+// ChangelogRetention selects changes removed from the changelog. Zero value
+// keeps all changes.
+type ChangelogRetention struct {
+	// MaxChanges is the maximum number of kept changes, zero is unlimited
+	MaxChanges uint64
+	// MaxAge is the maximum age of kept changes, zero is unlimited
+	MaxAge time.Duration
+	// CompactAge is the age after which changes of delegations which are no
+	// longer tracked are compacted to their deletion record, zero disables
+	// compaction
+	CompactAge time.Duration
+	// KeepFromSeq protects changes with this or greater sequence number, e.g.
+	// changes not delivered to consumers yet. Zero protects nothing.
+	KeepFromSeq uint64
+}
+
+// ChangelogStats describes size of the changelog
+type ChangelogStats struct {
+	Changes   uint64
+	SizeBytes uint64
+	// OldestSeq and OldestTimestamp are zero if changelog is empty
+	OldestSeq       uint64
+	OldestTimestamp time.Time
+	// LastSeq is sequence number of the last recorded change, it is kept
+	// when changes are removed
+	LastSeq uint64
+}
+
+// PruneChangelog removes changes selected by retention. Sequence numbers are
+// never reused, so consumers can detect removed changes by a gap between
+// their resume token and the oldest change. Removal is not recorded in the
+// changelog. Returns number of removed changes and stats of the changelog
+// after pruning.
+func (c *TrackedTransactionStore) PruneChangelog(r ChangelogRetention, now time.Time) (uint64, *ChangelogStats, error) {
+	var toRemove [][]byte
+
+	err := c.db.View(func(tx kvdb.RTx) error {
+		changelogBucket := tx.ReadBucket(changelogBucketName)
+		if changelogBucket == nil {
+			return ErrCorruptedTransactionsDB
+		}
+
+		transactionIdxBucket := tx.ReadBucket(transactionIndexName)
+		if transactionIdxBucket == nil {
+			return ErrCorruptedTransactionsDB
+		}
+
+		var total uint64
+		err := changelogBucket.ForEach(func(k, _ []byte) error {
+			if len(k) == 8 {
+				total++
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		var overLimit uint64
+		if r.MaxChanges > 0 && total > r.MaxChanges {
+			overLimit = total - r.MaxChanges
+		}
+
+		cursor := changelogBucket.ReadCursor()
+		var idx uint64
+		for k, v := cursor.First(); k != nil; k, v = cursor.Next() {
+			// skip the sequence key
+			if len(k) != 8 {
+				continue
+			}
+
+			seq := binary.BigEndian.Uint64(k)
+			if r.KeepFromSeq > 0 && seq >= r.KeepFromSeq {
+				break
+			}
+
+			ch, err := deserializeChange(seq, v)
+			if err != nil {
+				return err
+			}
+
+			age := now.Sub(ch.Timestamp)
+			remove := idx < overLimit ||
+				(r.MaxAge > 0 && age > r.MaxAge) ||
+				(r.CompactAge > 0 && age > r.CompactAge && compactable(ch, transactionIdxBucket))
+			idx++
+
+			if remove {
+				toRemove = append(toRemove, append([]byte(nil), k...))
+			}
+		}
+
+		return nil
+	}, func() {
+		toRemove = nil
+	})
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to select pruned changes: %w", err)
+	}
+
+	var removed uint64
+	for len(toRemove) > 0 {
+		chunk := toRemove[:min(len(toRemove), maxPrunedPerTx)]
+		toRemove = toRemove[len(chunk):]
+
+		err := batch(c.db, func(tx kvdb.RwTx) error {
+			changelogBucket := tx.ReadWriteBucket(changelogBucketName)
+			if changelogBucket == nil {
+				return ErrCorruptedTransactionsDB
+			}
+
+			for _, k := range chunk {
+				if err := changelogBucket.Delete(k); err != nil {
+					return err
+				}
+			}
+
+			return nil
+		})
+		if err != nil {
+			return removed, nil, fmt.Errorf("failed to prune changes: %w", err)
+		}
+
+		removed += uint64(len(chunk))
+	}
+
+	stats, err := c.ChangelogStats()
+	if err != nil {
+		return removed, nil, err
+	}
+
+	return removed, stats, nil
+}
+
+// compactable returns true for changes of delegations which are no longer
+// tracked, except for their deletion record
+func compactable(ch *Change, transactionIdxBucket kvdb.RBucket) bool {
+	if ch.StakingTxHash == (chainhash.Hash{}) || ch.Kind == ChangeTransactionDeleted {
+		return false
+	}
+
+	return transactionIdxBucket.Get(ch.StakingTxHash[:]) == nil
+}
+
+// ChangelogStats returns number and size of changes in the changelog
+func (c *TrackedTransactionStore) ChangelogStats() (*ChangelogStats, error) {
+	var stats ChangelogStats
+
+	err := c.db.View(func(tx kvdb.RTx) error {
+		changelogBucket := tx.ReadBucket(changelogBucketName)
+		if changelogBucket == nil {
+			return ErrCorruptedTransactionsDB
+		}
+
+		if seqBytes := changelogBucket.Get(lastChangeSeqKey); seqBytes != nil {
+			stats.LastSeq = binary.BigEndian.Uint64(seqBytes)
+		}
+
+		return changelogBucket.ForEach(func(k, v []byte) error {
+			if len(k) != 8 {
+				return nil
+			}
+
+			if stats.Changes == 0 {
+				ch, err := deserializeChange(binary.BigEndian.Uint64(k), v)
+				if err != nil {
+					return err
+				}
+				stats.OldestSeq = ch.Seq
+				stats.OldestTimestamp = ch.Timestamp
+			}
+
+			stats.Changes++
+			stats.SizeBytes += uint64(len(k) + len(v))
+			return nil
+		})
+	}, func() {
+		stats = ChangelogStats{}
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get changelog stats: %w", err)
+	}
+
+	return &stats, nil
+}
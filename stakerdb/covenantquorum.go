@@ -0,0 +1,169 @@
+package stakerdb
+
+import (
+	"encoding/binary"
+	"fmt"
+	"time"
+
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/lightningnetwork/lnd/kvdb"
+)
+
+var (
+	// mapping txHash -> bigendian(int64) registered at || bigendian(int64) quorum reached at
+	// It holds time delegation was registered on Babylon and time staker
+	// observed quorum of covenant signatures, zero if not known
+	covenantQuorumBucketName = []byte("covenantQuorum")
+)
+
+const covenantQuorumTimingSize = 8 + 8
+
+// CovenantQuorumTiming holds times used to measure how long covenant
+// committee takes to sign the delegation
+type CovenantQuorumTiming struct {
+	// RegisteredAt is zero for delegations registered before timings were
+	// recorded
+	RegisteredAt time.Time
+	// QuorumReachedAt is zero until quorum of covenant signatures is observed
+	QuorumReachedAt time.Time
+}
+
+// Latency returns time between registration and quorum of covenant
+// signatures. Returns false if either of them is not known.
+func (t *CovenantQuorumTiming) Latency() (time.Duration, bool) {
+	if t.RegisteredAt.IsZero() || t.QuorumReachedAt.IsZero() {
+		return 0, false
+	}
+	return t.QuorumReachedAt.Sub(t.RegisteredAt), true
+}
+
+func timeToUnixNano(t time.Time) uint64 {
+	if t.IsZero() {
+		return 0
+	}
+	return uint64(t.UnixNano())
+}
+
+func unixNanoToTime(v uint64) time.Time {
+	if v == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, int64(v))
+}
+
+func (t *CovenantQuorumTiming) serialize() []byte {
+	b := make([]byte, covenantQuorumTimingSize)
+	binary.BigEndian.PutUint64(b[0:8], timeToUnixNano(t.RegisteredAt))
+	binary.BigEndian.PutUint64(b[8:16], timeToUnixNano(t.QuorumReachedAt))
+	return b
+}
+
+func deserializeCovenantQuorumTiming(b []byte) (*CovenantQuorumTiming, error) {
+	if len(b) != covenantQuorumTimingSize {
+		return nil, fmt.Errorf("invalid covenant quorum timing size: %d", len(b))
+	}
+
+	return &CovenantQuorumTiming{
+		RegisteredAt:    unixNanoToTime(binary.BigEndian.Uint64(b[0:8])),
+		QuorumReachedAt: unixNanoToTime(binary.BigEndian.Uint64(b[8:16])),
+	}, nil
+}
+
+// SetDelegationRegisteredAt stores time tracked delegation was registered on
+// Babylon
+func (c *TrackedTransactionStore) SetDelegationRegisteredAt(txHash *chainhash.Hash, registeredAt time.Time) error {
+	return c.updateCovenantQuorumTiming(txHash, func(t *CovenantQuorumTiming) bool {
+		t.RegisteredAt = registeredAt
+		return true
+	}, ChangeDelegationRegistered)
+}
+
+// SetCovenantQuorumReachedAt stores time staker observed quorum of covenant
+// signatures of tracked delegation. Time is stored only once, returns false if
+// it was already stored.
+func (c *TrackedTransactionStore) SetCovenantQuorumReachedAt(txHash *chainhash.Hash, reachedAt time.Time) (bool, error) {
+	var stored bool
+	err := c.updateCovenantQuorumTiming(txHash, func(t *CovenantQuorumTiming) bool {
+		if !t.QuorumReachedAt.IsZero() {
+			return false
+		}
+		t.QuorumReachedAt = reachedAt
+		stored = true
+		return true
+	}, ChangeCovenantQuorumReached)
+	if err != nil {
+		return false, err
+	}
+
+	return stored, nil
+}
+
+func (c *TrackedTransactionStore) updateCovenantQuorumTiming(
+	txHash *chainhash.Hash,
+	f func(t *CovenantQuorumTiming) bool,
+	kind ChangeKind,
+) error {
+	return c.update(func(tx kvdb.RwTx) error {
+		transactionIdxBucket := tx.ReadBucket(transactionIndexName)
+		if transactionIdxBucket == nil {
+			return ErrCorruptedTransactionsDB
+		}
+
+		if transactionIdxBucket.Get(txHash[:]) == nil {
+			return ErrTransactionNotFound
+		}
+
+		quorumBucket := tx.ReadWriteBucket(covenantQuorumBucketName)
+		if quorumBucket == nil {
+			return ErrCorruptedTransactionsDB
+		}
+
+		timing := &CovenantQuorumTiming{}
+		if v := quorumBucket.Get(txHash[:]); v != nil {
+			var err error
+			timing, err = deserializeCovenantQuorumTiming(v)
+			if err != nil {
+				return err
+			}
+		}
+
+		if !f(timing) {
+			return nil
+		}
+
+		if err := quorumBucket.Put(txHash.CloneBytes(), timing.serialize()); err != nil {
+			return err
+		}
+
+		return appendChange(tx, kind, txHash, "")
+	})
+}
+
+// GetCovenantQuorumTiming returns registration and covenant quorum times of
+// tracked delegation, nil if none of them was recorded
+func (c *TrackedTransactionStore) GetCovenantQuorumTiming(txHash *chainhash.Hash) (*CovenantQuorumTiming, error) {
+	var timing *CovenantQuorumTiming
+
+	err := c.db.View(func(tx kvdb.RTx) error {
+		quorumBucket := tx.ReadBucket(covenantQuorumBucketName)
+		if quorumBucket == nil {
+			return ErrCorruptedTransactionsDB
+		}
+
+		v := quorumBucket.Get(txHash[:])
+		if v == nil {
+			return nil
+		}
+
+		var err error
+		timing, err = deserializeCovenantQuorumTiming(v)
+		return err
+	}, func() {
+		timing = nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get covenant quorum timing: %w", err)
+	}
+
+	return timing, nil
+}
@@ -0,0 +1,171 @@
+package stakerdb
+
+import (
+	"encoding/binary"
+	"fmt"
+	"strconv"
+
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/lightningnetwork/lnd/kvdb"
+)
+
+var (
+	// mapping txHash -> bigendian(uint32) btc height
+	// It holds btc best block height at the time transaction started to be tracked
+	creationHeightsBucketName = []byte("creationHeights")
+
+	// mapping txHash -> bigendian(uint32) btc height
+	// It holds btc best block height at the time staking transaction was
+	// broadcast for the first time
+	broadcastHeightsBucketName = []byte("broadcastHeights")
+)
+
+// SetTransactionCreationHeight stores btc height at which tracked transaction
+// was created
+func (c *TrackedTransactionStore) SetTransactionCreationHeight(txHash *chainhash.Hash, height uint32) error {
+	return c.update(func(tx kvdb.RwTx) error {
+		transactionIdxBucket := tx.ReadBucket(transactionIndexName)
+		if transactionIdxBucket == nil {
+			return ErrCorruptedTransactionsDB
+		}
+
+		if transactionIdxBucket.Get(txHash[:]) == nil {
+			return ErrTransactionNotFound
+		}
+
+		heightsBucket := tx.ReadWriteBucket(creationHeightsBucketName)
+		if heightsBucket == nil {
+			return ErrCorruptedTransactionsDB
+		}
+
+		var heightBytes [4]byte
+		binary.BigEndian.PutUint32(heightBytes[:], height)
+
+		if err := heightsBucket.Put(txHash.CloneBytes(), heightBytes[:]); err != nil {
+			return err
+		}
+
+		return appendChange(tx, ChangeCreationHeightSet, txHash, strconv.FormatUint(uint64(height), 10))
+	})
+}
+
+// GetTransactionCreationHeight returns btc height at which tracked transaction
+// was created. Returns false if height is not known, which is the case for
+// transactions tracked before heights were recorded.
+func (c *TrackedTransactionStore) GetTransactionCreationHeight(txHash *chainhash.Hash) (uint32, bool, error) {
+	var (
+		height uint32
+		found  bool
+	)
+
+	err := c.db.View(func(tx kvdb.RTx) error {
+		heightsBucket := tx.ReadBucket(creationHeightsBucketName)
+		if heightsBucket == nil {
+			return ErrCorruptedTransactionsDB
+		}
+
+		v := heightsBucket.Get(txHash[:])
+		if v == nil {
+			return nil
+		}
+
+		if len(v) != 4 {
+			return ErrCorruptedTransactionsDB
+		}
+
+		height = binary.BigEndian.Uint32(v)
+		found = true
+		return nil
+	}, func() {
+		height = 0
+		found = false
+	})
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to get transaction creation height: %w", err)
+	}
+
+	return height, found, nil
+}
+
+// SetTransactionBroadcastHeight stores btc height at which staking transaction
+// of tracked delegation was broadcast. Height of the first broadcast is kept,
+// returns false if height is already stored.
+func (c *TrackedTransactionStore) SetTransactionBroadcastHeight(txHash *chainhash.Hash, height uint32) (bool, error) {
+	var updated bool
+
+	err := c.update(func(tx kvdb.RwTx) error {
+		updated = false
+
+		transactionIdxBucket := tx.ReadBucket(transactionIndexName)
+		if transactionIdxBucket == nil {
+			return ErrCorruptedTransactionsDB
+		}
+
+		if transactionIdxBucket.Get(txHash[:]) == nil {
+			return ErrTransactionNotFound
+		}
+
+		heightsBucket := tx.ReadWriteBucket(broadcastHeightsBucketName)
+		if heightsBucket == nil {
+			return ErrCorruptedTransactionsDB
+		}
+
+		if heightsBucket.Get(txHash[:]) != nil {
+			return nil
+		}
+
+		var heightBytes [4]byte
+		binary.BigEndian.PutUint32(heightBytes[:], height)
+
+		if err := heightsBucket.Put(txHash.CloneBytes(), heightBytes[:]); err != nil {
+			return err
+		}
+
+		updated = true
+		return appendChange(tx, ChangeBroadcastHeightSet, txHash, strconv.FormatUint(uint64(height), 10))
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return updated, nil
+}
+
+// GetTransactionBroadcastHeight returns btc height at which staking
+// transaction of tracked delegation was broadcast for the first time. Returns
+// false if it was not broadcast yet, or was broadcast before heights were
+// recorded.
+func (c *TrackedTransactionStore) GetTransactionBroadcastHeight(txHash *chainhash.Hash) (uint32, bool, error) {
+	var (
+		height uint32
+		found  bool
+	)
+
+	err := c.db.View(func(tx kvdb.RTx) error {
+		heightsBucket := tx.ReadBucket(broadcastHeightsBucketName)
+		if heightsBucket == nil {
+			return ErrCorruptedTransactionsDB
+		}
+
+		v := heightsBucket.Get(txHash[:])
+		if v == nil {
+			return nil
+		}
+
+		if len(v) != 4 {
+			return ErrCorruptedTransactionsDB
+		}
+
+		height = binary.BigEndian.Uint32(v)
+		found = true
+		return nil
+	}, func() {
+		height = 0
+		found = false
+	})
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to get transaction broadcast height: %w", err)
+	}
+
+	return height, found, nil
+}
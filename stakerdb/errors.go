@@ -11,4 +11,61 @@ var (
 
 	// ErrDuplicateTransaction The transaction we try to add already exists in db
 	ErrDuplicateTransaction = errors.New("transaction already exists")
+
+	// ErrOutpointNotReserved The outpoint we try to release is not used by any tracked transaction
+	ErrOutpointNotReserved = errors.New("outpoint not reserved")
+
+	// ErrStaleLeaderTerm Leader with newer term was elected
+	ErrStaleLeaderTerm = errors.New("stale leader term")
+
+	// ErrTemplateNotFound The delegation template is not stored in db
+	ErrTemplateNotFound = errors.New("delegation template not found")
+
+	// ErrQueuedStakeNotFound The queued stake request is not stored in db
+	ErrQueuedStakeNotFound = errors.New("queued stake not found")
+
+	// ErrStakeQueueFull Too many stake requests are already queued
+	ErrStakeQueueFull = errors.New("stake queue is full")
+
+	// ErrMuSig2KeyNotFound The MuSig2 key is not stored in db
+	ErrMuSig2KeyNotFound = errors.New("musig2 key not found")
+
+	// ErrMuSig2KeyExists MuSig2 key with the same name is already stored in db
+	ErrMuSig2KeyExists = errors.New("musig2 key already exists")
+
+	// ErrMuSig2SessionNotFound The MuSig2 signing session is not stored in db
+	ErrMuSig2SessionNotFound = errors.New("musig2 session not found")
+
+	// ErrMuSig2NonceReused The MuSig2 public nonce was already used in some session
+	ErrMuSig2NonceReused = errors.New("musig2 nonce was already used")
+
+	// ErrNetworkMismatch The db is bound to other btc network than the configured one
+	ErrNetworkMismatch = errors.New("database network mismatch")
+
+	// ErrCovenantQuorumNotMet Fewer covenant signatures than quorum were stored
+	ErrCovenantQuorumNotMet = errors.New("covenant signatures do not meet quorum")
+
+	// ErrDuplicateCovenantSignature More than one covenant signature of the same key was stored
+	ErrDuplicateCovenantSignature = errors.New("duplicate covenant signature")
+
+	// ErrCovenantNotInCommittee Covenant signature of key outside of covenant committee was stored
+	ErrCovenantNotInCommittee = errors.New("covenant key is not in committee")
+
+	// ErrParamsNotArchived The params version is not stored in params archive
+	ErrParamsNotArchived = errors.New("params not archived")
+
+	// ErrSnapshotCorrupted Snapshot files do not match its manifest
+	ErrSnapshotCorrupted = errors.New("snapshot is corrupted")
+
+	// ErrIntegrityViolation Stored record does not match its checksum or signature
+	ErrIntegrityViolation = errors.New("record integrity violation")
+
+	// ErrIntegrityKeyRequired Records are signed, but the store has no integrity key to sign written record
+	ErrIntegrityKeyRequired = errors.New("integrity key required to write signed records")
+
+	// ErrIntegrityKeyMismatch Records are signed by other integrity key
+	ErrIntegrityKeyMismatch = errors.New("integrity key mismatch")
+
+	// ErrTransactionQuarantined Stored record of the transaction failed to decode or validate and was moved to quarantine
+	ErrTransactionQuarantined = errors.New("transaction record quarantined")
 )
@@ -0,0 +1,141 @@
+package stakerdb
+
+import (
+	"encoding/binary"
+	"fmt"
+	"time"
+
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/lightningnetwork/lnd/kvdb"
+)
+
+var (
+	// mapping txHash -> timestamp(8) || failure reason
+	// It holds tracked transactions which will never be included in btc chain
+	failedTransactionsBucketName = []byte("failedTransactions")
+)
+
+// TransactionFailure describes why tracked transaction permanently failed
+type TransactionFailure struct {
+	StakingTxHash chainhash.Hash
+	Reason        string
+	Timestamp     time.Time
+}
+
+func serializeTransactionFailure(f *TransactionFailure) []byte {
+	b := make([]byte, 8+len(f.Reason))
+	binary.BigEndian.PutUint64(b[:8], uint64(f.Timestamp.Unix()))
+	copy(b[8:], f.Reason)
+	return b
+}
+
+func deserializeTransactionFailure(txHash, b []byte) (*TransactionFailure, error) {
+	if len(b) < 8 {
+		return nil, ErrCorruptedTransactionsDB
+	}
+
+	hash, err := chainhash.NewHash(txHash)
+	if err != nil {
+		return nil, ErrCorruptedTransactionsDB
+	}
+
+	return &TransactionFailure{
+		StakingTxHash: *hash,
+		Timestamp:     time.Unix(int64(binary.BigEndian.Uint64(b[:8])), 0),
+		Reason:        string(b[8:]),
+	}, nil
+}
+
+// MarkTransactionFailed marks tracked transaction as permanently failed. Marking
+// already failed transaction overwrites previous failure.
+func (c *TrackedTransactionStore) MarkTransactionFailed(txHash *chainhash.Hash, reason string) error {
+	failure := &TransactionFailure{
+		StakingTxHash: *txHash,
+		Reason:        reason,
+		Timestamp:     time.Now(),
+	}
+
+	return c.update(func(tx kvdb.RwTx) error {
+		transactionIdxBucket := tx.ReadBucket(transactionIndexName)
+		if transactionIdxBucket == nil {
+			return ErrCorruptedTransactionsDB
+		}
+
+		if transactionIdxBucket.Get(txHash[:]) == nil {
+			return ErrTransactionNotFound
+		}
+
+		failedBucket := tx.ReadWriteBucket(failedTransactionsBucketName)
+		if failedBucket == nil {
+			return ErrCorruptedTransactionsDB
+		}
+
+		if err := failedBucket.Put(txHash.CloneBytes(), serializeTransactionFailure(failure)); err != nil {
+			return err
+		}
+
+		return appendChange(tx, ChangeTransactionFailed, txHash, reason)
+	})
+}
+
+// GetTransactionFailure returns failure of tracked transaction or nil if
+// transaction did not fail
+func (c *TrackedTransactionStore) GetTransactionFailure(txHash *chainhash.Hash) (*TransactionFailure, error) {
+	var failure *TransactionFailure
+
+	err := c.db.View(func(tx kvdb.RTx) error {
+		failedBucket := tx.ReadBucket(failedTransactionsBucketName)
+		if failedBucket == nil {
+			return ErrCorruptedTransactionsDB
+		}
+
+		v := failedBucket.Get(txHash[:])
+		if v == nil {
+			return nil
+		}
+
+		f, err := deserializeTransactionFailure(txHash[:], v)
+		if err != nil {
+			return err
+		}
+
+		failure = f
+		return nil
+	}, func() {
+		failure = nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transaction failure: %w", err)
+	}
+
+	return failure, nil
+}
+
+// ListTransactionFailures returns all failed tracked transactions
+func (c *TrackedTransactionStore) ListTransactionFailures() ([]TransactionFailure, error) {
+	var failures []TransactionFailure
+
+	err := c.db.View(func(tx kvdb.RTx) error {
+		failedBucket := tx.ReadBucket(failedTransactionsBucketName)
+		if failedBucket == nil {
+			return ErrCorruptedTransactionsDB
+		}
+
+		return failedBucket.ForEach(func(k, v []byte) error {
+			f, err := deserializeTransactionFailure(k, v)
+			if err != nil {
+				return err
+			}
+
+			failures = append(failures, *f)
+			return nil
+		})
+	}, func() {
+		failures = nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list transaction failures: %w", err)
+	}
+
+	return failures, nil
+}
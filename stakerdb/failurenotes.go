@@ -0,0 +1,128 @@
+package stakerdb
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/lightningnetwork/lnd/kvdb"
+)
+
+var (
+	// mapping txHash -> json encoded failure note
+	// It holds the last failure or problem of the delegation together with
+	// suggested remediation
+	failureNotesBucketName = []byte("failureNotes")
+)
+
+// Sources of failure notes
+const (
+	FailureSourceBabylon = "babylon"
+	FailureSourceBtc     = "btc"
+	FailureSourceStaker  = "staker"
+)
+
+// FailureNote is a structured cause of delegation failure or problem
+type FailureNote struct {
+	// Source is the component which reported the failure, one of babylon,
+	// btc or staker
+	Source string `json:"source"`
+	// Codespace and Code identify Babylon error, Code is rpc error code of the
+	// btc node for btc failures. Both are empty if not known.
+	Codespace string `json:"codespace,omitempty"`
+	Code      int64  `json:"code,omitempty"`
+	Reason    string `json:"reason"`
+	// Remediation is a suggested action, empty if there is none
+	Remediation string    `json:"remediation,omitempty"`
+	RecordedAt  time.Time `json:"recorded_at"`
+}
+
+// SetFailureNote stores failure note of tracked delegation, replacing previous
+// one
+func (c *TrackedTransactionStore) SetFailureNote(txHash *chainhash.Hash, note *FailureNote) error {
+	if note == nil {
+		return fmt.Errorf("cannot save nil failure note")
+	}
+
+	encoded, err := json.Marshal(note)
+	if err != nil {
+		return fmt.Errorf("failed to encode failure note: %w", err)
+	}
+
+	return c.update(func(tx kvdb.RwTx) error {
+		transactionIdxBucket := tx.ReadBucket(transactionIndexName)
+		if transactionIdxBucket == nil {
+			return ErrCorruptedTransactionsDB
+		}
+
+		if transactionIdxBucket.Get(txHash[:]) == nil {
+			return ErrTransactionNotFound
+		}
+
+		notesBucket := tx.ReadWriteBucket(failureNotesBucketName)
+		if notesBucket == nil {
+			return ErrCorruptedTransactionsDB
+		}
+
+		if err := notesBucket.Put(txHash.CloneBytes(), encoded); err != nil {
+			return err
+		}
+
+		return appendChange(tx, ChangeFailureNoteSet, txHash, note.Source+": "+note.Reason)
+	})
+}
+
+// ClearFailureNote removes failure note of tracked delegation once it
+// recovered from the problem
+func (c *TrackedTransactionStore) ClearFailureNote(txHash *chainhash.Hash) error {
+	return c.update(func(tx kvdb.RwTx) error {
+		notesBucket := tx.ReadWriteBucket(failureNotesBucketName)
+		if notesBucket == nil {
+			return ErrCorruptedTransactionsDB
+		}
+
+		if notesBucket.Get(txHash[:]) == nil {
+			return nil
+		}
+
+		if err := notesBucket.Delete(txHash[:]); err != nil {
+			return err
+		}
+
+		return appendChange(tx, ChangeFailureNoteCleared, txHash, "")
+	})
+}
+
+// GetFailureNote returns failure note of tracked delegation or nil if there is
+// none
+func (c *TrackedTransactionStore) GetFailureNote(txHash *chainhash.Hash) (*FailureNote, error) {
+	var note *FailureNote
+
+	err := c.db.View(func(tx kvdb.RTx) error {
+		notesBucket := tx.ReadBucket(failureNotesBucketName)
+		if notesBucket == nil {
+			return ErrCorruptedTransactionsDB
+		}
+
+		v := notesBucket.Get(txHash[:])
+		if v == nil {
+			return nil
+		}
+
+		var n FailureNote
+		if err := json.Unmarshal(v, &n); err != nil {
+			return err
+		}
+
+		note = &n
+		return nil
+	}, func() {
+		note = nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get failure note: %w", err)
+	}
+
+	return note, nil
+}
@@ -0,0 +1,149 @@
+package stakerdb
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/lightningnetwork/lnd/kvdb"
+)
+
+// fpKeyLen is length of x-only public key of finality provider
+const fpKeyLen = 32
+
+var (
+	// mapping txHash -> bigendian(uint64) staking amount || 32 byte x-only
+	// public keys of finality providers
+	// It holds finality providers of delegations created by the staker
+	finalityProvidersBucketName = []byte("finalityProviders")
+)
+
+// DelegationFinalityProviders are finality providers to which delegation
+// stakes
+type DelegationFinalityProviders struct {
+	StakingTxHash chainhash.Hash
+	// FpBtcPks are 32 byte x-only public keys of finality providers
+	FpBtcPks      [][]byte
+	StakingAmount btcutil.Amount
+}
+
+func serializeDelegationFinalityProviders(d *DelegationFinalityProviders) []byte {
+	b := make([]byte, 8, 8+fpKeyLen*len(d.FpBtcPks))
+	binary.BigEndian.PutUint64(b, uint64(d.StakingAmount))
+	for _, pk := range d.FpBtcPks {
+		b = append(b, pk...)
+	}
+	return b
+}
+
+func deserializeDelegationFinalityProviders(txHash, b []byte) (*DelegationFinalityProviders, error) {
+	if len(b) < 8 || (len(b)-8)%fpKeyLen != 0 {
+		return nil, ErrCorruptedTransactionsDB
+	}
+
+	hash, err := chainhash.NewHash(txHash)
+	if err != nil {
+		return nil, ErrCorruptedTransactionsDB
+	}
+
+	d := &DelegationFinalityProviders{
+		StakingTxHash: *hash,
+		StakingAmount: btcutil.Amount(binary.BigEndian.Uint64(b[:8])),
+	}
+	for keys := b[8:]; len(keys) > 0; keys = keys[fpKeyLen:] {
+		d.FpBtcPks = append(d.FpBtcPks, append([]byte(nil), keys[:fpKeyLen]...))
+	}
+
+	return d, nil
+}
+
+// SetDelegationFinalityProviders stores finality providers and staking amount
+// of tracked delegation
+func (c *TrackedTransactionStore) SetDelegationFinalityProviders(
+	txHash *chainhash.Hash,
+	fpBtcPks [][]byte,
+	stakingAmount btcutil.Amount,
+) error {
+	if len(fpBtcPks) == 0 {
+		return fmt.Errorf("delegation must have at least one finality provider")
+	}
+
+	detail := make([]string, 0, len(fpBtcPks))
+	for _, pk := range fpBtcPks {
+		if len(pk) != fpKeyLen {
+			return fmt.Errorf("finality provider key must have %d bytes, got %d", fpKeyLen, len(pk))
+		}
+		detail = append(detail, hex.EncodeToString(pk))
+	}
+
+	d := &DelegationFinalityProviders{
+		StakingTxHash: *txHash,
+		FpBtcPks:      fpBtcPks,
+		StakingAmount: stakingAmount,
+	}
+
+	return c.update(func(tx kvdb.RwTx) error {
+		transactionIdxBucket := tx.ReadBucket(transactionIndexName)
+		if transactionIdxBucket == nil {
+			return ErrCorruptedTransactionsDB
+		}
+
+		if transactionIdxBucket.Get(txHash[:]) == nil {
+			return ErrTransactionNotFound
+		}
+
+		fpsBucket := tx.ReadWriteBucket(finalityProvidersBucketName)
+		if fpsBucket == nil {
+			return ErrCorruptedTransactionsDB
+		}
+
+		if err := fpsBucket.Put(txHash.CloneBytes(), serializeDelegationFinalityProviders(d)); err != nil {
+			return err
+		}
+
+		return appendChange(tx, ChangeFinalityProvidersSet, txHash, strings.Join(detail, ","))
+	})
+}
+
+// ListDelegationFinalityProviders returns finality providers of tracked
+// delegations which did not fail. Delegations tracked before finality
+// providers were recorded, or not created by the staker, are not returned.
+func (c *TrackedTransactionStore) ListDelegationFinalityProviders() ([]DelegationFinalityProviders, error) {
+	var delegations []DelegationFinalityProviders
+
+	err := c.db.View(func(tx kvdb.RTx) error {
+		fpsBucket := tx.ReadBucket(finalityProvidersBucketName)
+		if fpsBucket == nil {
+			return ErrCorruptedTransactionsDB
+		}
+
+		failedBucket := tx.ReadBucket(failedTransactionsBucketName)
+		if failedBucket == nil {
+			return ErrCorruptedTransactionsDB
+		}
+
+		return fpsBucket.ForEach(func(k, v []byte) error {
+			if failedBucket.Get(k) != nil {
+				return nil
+			}
+
+			d, err := deserializeDelegationFinalityProviders(k, v)
+			if err != nil {
+				return err
+			}
+
+			delegations = append(delegations, *d)
+			return nil
+		})
+	}, func() {
+		delegations = nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list delegation finality providers: %w", err)
+	}
+
+	return delegations, nil
+}
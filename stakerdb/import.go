@@ -0,0 +1,170 @@
+package stakerdb
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+
+	"github.com/babylonlabs-io/btc-staker/proto"
+	"github.com/babylonlabs-io/btc-staker/utils"
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/kvdb"
+)
+
+// ImportedTransaction is a delegation tracked by other staker instance,
+// together with data recorded about it by that instance
+type ImportedTransaction struct {
+	StakingTx     *wire.MsgTx
+	StakerAddress btcutil.Address
+	// Tenant is empty for DefaultTenant
+	Tenant string
+	Labels map[string]string
+	// CreationHeight is zero if not known
+	CreationHeight uint32
+	StakingFee     btcutil.Amount
+	UnbondingFee   btcutil.Amount
+	WithdrawalFee  btcutil.Amount
+	Timing         CovenantQuorumTiming
+	StatusHistory  []StatusSnapshot
+	BabylonTxs     []BabylonTx
+}
+
+// ImportTransaction starts tracking delegation exported from other staker
+// instance. Transaction and all its data are stored atomically, returns
+// ErrDuplicateTransaction if transaction is already tracked.
+func (c *TrackedTransactionStore) ImportTransaction(imported *ImportedTransaction) error {
+	if imported == nil || imported.StakingTx == nil {
+		return fmt.Errorf("cannot import nil transaction")
+	}
+
+	if imported.Tenant != "" {
+		if err := ValidateTenant(imported.Tenant); err != nil {
+			return err
+		}
+	}
+
+	txHash := imported.StakingTx.TxHash()
+
+	return c.update(func(tx kvdb.RwTx) error {
+		transactionIdxBucket := tx.ReadWriteBucket(transactionIndexName)
+		if transactionIdxBucket == nil {
+			return ErrCorruptedTransactionsDB
+		}
+
+		if transactionIdxBucket.Get(txHash[:]) != nil {
+			return ErrDuplicateTransaction
+		}
+
+		if err := saveImportedTransaction(tx, imported, c.integrityKey); err != nil {
+			return err
+		}
+
+		return appendChange(tx, ChangeTransactionImported, &txHash, imported.StakerAddress.EncodeAddress())
+	})
+}
+
+// saveImportedTransaction stores transaction and all its imported data in the
+// given db transaction. Transaction must not be tracked yet.
+func saveImportedTransaction(tx kvdb.RwTx, imported *ImportedTransaction, key *btcec.PrivateKey) error {
+	txHash := imported.StakingTx.TxHash()
+	serializedTx, err := utils.SerializeBtcTransaction(imported.StakingTx)
+	if err != nil {
+		return fmt.Errorf("failed to serialize Bitcoin transaction: %w", err)
+	}
+
+	inputData, err := getInputData(imported.StakingTx)
+	if err != nil {
+		return fmt.Errorf("failed to get input data: %w", err)
+	}
+
+	msg := proto.TrackedTransaction{
+		StakingTransaction: serializedTx,
+		StakerAddress:      imported.StakerAddress.EncodeAddress(),
+	}
+
+	transactionIdxBucket := tx.ReadWriteBucket(transactionIndexName)
+	if transactionIdxBucket == nil {
+		return ErrCorruptedTransactionsDB
+	}
+
+	transactionsBucket := tx.ReadWriteBucket(transactionBucketName)
+	if transactionsBucket == nil {
+		return ErrCorruptedTransactionsDB
+	}
+
+	if err := saveTrackedTransaction(tx, transactionIdxBucket, transactionsBucket, txHash[:], &msg, inputData, key); err != nil {
+		return err
+	}
+
+	if imported.Tenant != "" && imported.Tenant != DefaultTenant {
+		if err := putImported(tx, tenantsBucketName, txHash[:], []byte(imported.Tenant)); err != nil {
+			return err
+		}
+	}
+
+	if len(imported.Labels) > 0 {
+		if err := putImportedJSON(tx, labelsBucketName, txHash[:], imported.Labels); err != nil {
+			return err
+		}
+	}
+
+	if imported.CreationHeight > 0 {
+		var heightBytes [4]byte
+		binary.BigEndian.PutUint32(heightBytes[:], imported.CreationHeight)
+		if err := putImported(tx, creationHeightsBucketName, txHash[:], heightBytes[:]); err != nil {
+			return err
+		}
+	}
+
+	fees := &DelegationFees{
+		StakingTxHash: txHash,
+		StakingFee:    imported.StakingFee,
+		UnbondingFee:  imported.UnbondingFee,
+		WithdrawalFee: imported.WithdrawalFee,
+	}
+	if fees.Total() > 0 {
+		if err := putImported(tx, paidFeesBucketName, txHash[:], fees.serialize()); err != nil {
+			return err
+		}
+	}
+
+	if !imported.Timing.RegisteredAt.IsZero() || !imported.Timing.QuorumReachedAt.IsZero() {
+		if err := putImported(tx, covenantQuorumBucketName, txHash[:], imported.Timing.serialize()); err != nil {
+			return err
+		}
+	}
+
+	if len(imported.StatusHistory) > 0 {
+		if err := putImportedJSON(tx, statusHistoryBucketName, txHash[:], imported.StatusHistory); err != nil {
+			return err
+		}
+	}
+
+	if len(imported.BabylonTxs) > 0 {
+		if err := putImportedJSON(tx, babylonTxsBucketName, txHash[:], imported.BabylonTxs); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func putImported(tx kvdb.RwTx, bucketName []byte, key []byte, value []byte) error {
+	bucket := tx.ReadWriteBucket(bucketName)
+	if bucket == nil {
+		return ErrCorruptedTransactionsDB
+	}
+
+	return bucket.Put(key, value)
+}
+
+func putImportedJSON(tx kvdb.RwTx, bucketName []byte, key []byte, value interface{}) error {
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s: %w", bucketName, err)
+	}
+
+	return putImported(tx, bucketName, key, encoded)
+}
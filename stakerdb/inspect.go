@@ -0,0 +1,228 @@
+package stakerdb
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"sort"
+
+	"github.com/babylonlabs-io/btc-staker/proto"
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/lightningnetwork/lnd/kvdb"
+	pm "google.golang.org/protobuf/proto"
+)
+
+// BucketStats describes size of a top level bucket
+type BucketStats struct {
+	Name string
+	// number of keys in the bucket, including nested buckets
+	Keys int
+	// total size of keys and values in bytes, not including nested buckets
+	Bytes int
+}
+
+// IndexHealth is result of consistency check of tracked transactions and
+// their indexes
+type IndexHealth struct {
+	TrackedTransactions uint64
+	IndexEntries        uint64
+	// number of records moved to quarantine, they keep their keys so the
+	// index counter counts them
+	QuarantinedTransactions uint64
+	// number of transactions according to the index counter
+	NumTxCounter uint64
+	// index entries pointing to transactions which do not exist
+	MissingTransactions []string
+	// keys of stored transactions which can't be decoded
+	UndecodableTransactions []string
+	// hashes of stored transactions without index entry, or indexed under
+	// different key
+	UnindexedTransactions []string
+	// reserved outpoints pointing to transactions which are not tracked
+	DanglingInputs []string
+	// keys of stored transactions which do not match their checksum or
+	// signature
+	IntegrityFailures []string
+}
+
+// Healthy returns true if no inconsistency was found
+func (h *IndexHealth) Healthy() bool {
+	return h.TrackedTransactions+h.QuarantinedTransactions == h.NumTxCounter &&
+		h.TrackedTransactions == h.IndexEntries &&
+		len(h.MissingTransactions) == 0 &&
+		len(h.UndecodableTransactions) == 0 &&
+		len(h.UnindexedTransactions) == 0 &&
+		len(h.DanglingInputs) == 0 &&
+		len(h.IntegrityFailures) == 0
+}
+
+// NewInspectionStore returns a store backed by db which does not create
+// missing buckets, so that inspecting a database never modifies it. Only
+// read methods should be used on the returned store.
+func NewInspectionStore(db kvdb.Backend) *TrackedTransactionStore {
+	return &TrackedTransactionStore{
+		db:      db,
+		changes: newChangeNotifier(),
+	}
+}
+
+// BucketStats returns stats of all top level buckets sorted by name
+func (c *TrackedTransactionStore) BucketStats() ([]BucketStats, error) {
+	var stats []BucketStats
+
+	err := c.db.View(func(tx kvdb.RTx) error {
+		return tx.ForEachBucket(func(name []byte) error {
+			bucket := tx.ReadBucket(name)
+			if bucket == nil {
+				return nil
+			}
+
+			s := BucketStats{Name: string(name)}
+			err := bucket.ForEach(func(k, v []byte) error {
+				s.Keys++
+				s.Bytes += len(k) + len(v)
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+
+			stats = append(stats, s)
+			return nil
+		})
+	}, func() {
+		stats = nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect bucket stats: %w", err)
+	}
+
+	sort.Slice(stats, func(i, j int) bool {
+		return stats[i].Name < stats[j].Name
+	})
+
+	return stats, nil
+}
+
+// CheckIndexHealth checks that tracked transactions, transaction index and
+// reserved inputs are consistent with each other
+func (c *TrackedTransactionStore) CheckIndexHealth() (*IndexHealth, error) {
+	var health *IndexHealth
+
+	err := c.db.View(func(tx kvdb.RTx) error {
+		health = &IndexHealth{}
+
+		transactionsBucket := tx.ReadBucket(transactionBucketName)
+		if transactionsBucket == nil {
+			return ErrCorruptedTransactionsDB
+		}
+
+		transactionIdxBucket := tx.ReadBucket(transactionIndexName)
+		if transactionIdxBucket == nil {
+			return ErrCorruptedTransactionsDB
+		}
+
+		inputsBucket := tx.ReadBucket(inputsDataBucketName)
+		if inputsBucket == nil {
+			return ErrCorruptedTransactionsDB
+		}
+
+		// checksums bucket is missing in databases not opened since
+		// checksums were introduced
+		var identity *btcec.PublicKey
+		checksumsBucket := tx.ReadBucket(txChecksumsBucketName)
+		if checksumsBucket != nil {
+			var err error
+			if identity, err = identityKey(checksumsBucket); err != nil {
+				return err
+			}
+		}
+
+		health.NumTxCounter = getNumTx(transactionIdxBucket)
+
+		// quarantine bucket is missing in databases of older versions
+		if quarantineBucket := tx.ReadBucket(quarantineBucketName); quarantineBucket != nil {
+			err := quarantineBucket.ForEach(func(_, _ []byte) error {
+				health.QuarantinedTransactions++
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		err := transactionIdxBucket.ForEach(func(txHash, txKey []byte) error {
+			if bytes.Equal(txHash, numTxKey) {
+				return nil
+			}
+
+			health.IndexEntries++
+			if transactionsBucket.Get(txKey) == nil {
+				health.MissingTransactions = append(health.MissingTransactions, hashString(txHash))
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		err = transactionsBucket.ForEach(func(txKey, v []byte) error {
+			health.TrackedTransactions++
+
+			if checksumsBucket != nil {
+				if err := verifyChecksum(checksumsBucket.Get(txKey), txKey, v, identity); err != nil {
+					health.IntegrityFailures = append(health.IntegrityFailures, hex.EncodeToString(txKey))
+				}
+			}
+
+			var storedTxProto proto.TrackedTransaction
+			if err := pm.Unmarshal(v, &storedTxProto); err != nil {
+				health.UndecodableTransactions = append(health.UndecodableTransactions, hex.EncodeToString(txKey))
+				return nil
+			}
+
+			storedTx, err := protoTxToStoredTransaction(&storedTxProto)
+			if err != nil {
+				health.UndecodableTransactions = append(health.UndecodableTransactions, hex.EncodeToString(txKey))
+				return nil
+			}
+
+			txHash := storedTx.StakingTx.TxHash()
+			if !bytes.Equal(transactionIdxBucket.Get(txHash[:]), txKey) {
+				health.UnindexedTransactions = append(health.UnindexedTransactions, txHash.String())
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		return inputsBucket.ForEach(func(outpoint, txHash []byte) error {
+			if transactionIdxBucket.Get(txHash) == nil {
+				op, err := outpointFromBytes(outpoint)
+				if err != nil {
+					health.DanglingInputs = append(health.DanglingInputs, hex.EncodeToString(outpoint))
+					return nil
+				}
+				health.DanglingInputs = append(health.DanglingInputs, op.String())
+			}
+			return nil
+		})
+	}, func() {
+		health = nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to check index health: %w", err)
+	}
+
+	return health, nil
+}
+
+func hashString(b []byte) string {
+	hash, err := chainhash.NewHash(b)
+	if err != nil {
+		return hex.EncodeToString(b)
+	}
+	return hash.String()
+}
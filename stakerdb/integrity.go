@@ -0,0 +1,272 @@
+package stakerdb
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+	"github.com/lightningnetwork/lnd/kvdb"
+)
+
+const (
+	checksumLen  = sha256.Size
+	signatureLen = schnorr.SignatureSize
+)
+
+var (
+	// mapping txKey -> sha256(txKey || tracked transaction record) ||
+	// optional bip340 signature of the checksum by the daemon identity key
+	// It holds checksums of records of the transactions bucket
+	txChecksumsBucketName = []byte("txChecksums")
+
+	// key for x-only public key of the daemon identity key, set once records
+	// are signed. Keys of the bucket which are not 8 bytes long, like this
+	// one, never collide with tx keys.
+	identityKeyKey = []byte("identityKey")
+
+	// key marking that checksums of records stored before checksums were
+	// introduced are backfilled
+	checksumsBackfilledKey = []byte("backfilled")
+)
+
+func recordChecksum(txKey, record []byte) [checksumLen]byte {
+	h := sha256.New()
+	h.Write(txKey)
+	h.Write(record)
+
+	var checksum [checksumLen]byte
+	copy(checksum[:], h.Sum(nil))
+	return checksum
+}
+
+// signedChecksum returns checksum of the record signed by the key, or only
+// checksum if the key is nil
+func signedChecksum(txKey, record []byte, key *btcec.PrivateKey) ([]byte, error) {
+	checksum := recordChecksum(txKey, record)
+	if key == nil {
+		return checksum[:], nil
+	}
+
+	sig, err := schnorr.Sign(key, checksum[:])
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign record checksum: %w", err)
+	}
+
+	return append(checksum[:], sig.Serialize()...), nil
+}
+
+// putTrackedTransaction stores record of tracked transaction together with
+// its checksum. Records of database whose records are signed can be written
+// only with the identity key.
+func putTrackedTransaction(
+	tx kvdb.RwTx,
+	txBucket kvdb.RwBucket,
+	txKey, record []byte,
+	key *btcec.PrivateKey,
+) error {
+	checksumsBucket := tx.ReadWriteBucket(txChecksumsBucketName)
+	if checksumsBucket == nil {
+		return ErrCorruptedTransactionsDB
+	}
+
+	if key == nil && checksumsBucket.Get(identityKeyKey) != nil {
+		return ErrIntegrityKeyRequired
+	}
+
+	checksum, err := signedChecksum(txKey, record, key)
+	if err != nil {
+		return err
+	}
+
+	if err := txBucket.Put(txKey, record); err != nil {
+		return err
+	}
+
+	return checksumsBucket.Put(txKey, checksum)
+}
+
+// deleteChecksum deletes checksum of deleted record
+func deleteChecksum(tx kvdb.RwTx, txKey []byte) error {
+	checksumsBucket := tx.ReadWriteBucket(txChecksumsBucketName)
+	if checksumsBucket == nil {
+		return ErrCorruptedTransactionsDB
+	}
+
+	return checksumsBucket.Delete(txKey)
+}
+
+// identityKey returns identity key signing records, nil if records are not
+// signed
+func identityKey(checksumsBucket kvdb.RBucket) (*btcec.PublicKey, error) {
+	v := checksumsBucket.Get(identityKeyKey)
+	if v == nil {
+		return nil, nil
+	}
+
+	pk, err := schnorr.ParsePubKey(v)
+	if err != nil {
+		return nil, ErrCorruptedTransactionsDB
+	}
+
+	return pk, nil
+}
+
+// verifyRecord checks that record of tracked transaction matches its checksum
+// and, if records are signed, that checksum is signed by the identity key
+func verifyRecord(tx kvdb.RTx, txKey, record []byte) error {
+	checksumsBucket := tx.ReadBucket(txChecksumsBucketName)
+	if checksumsBucket == nil {
+		return ErrCorruptedTransactionsDB
+	}
+
+	identity, err := identityKey(checksumsBucket)
+	if err != nil {
+		return err
+	}
+
+	return verifyChecksum(checksumsBucket.Get(txKey), txKey, record, identity)
+}
+
+func verifyChecksum(stored, txKey, record []byte, identity *btcec.PublicKey) error {
+	if len(stored) < checksumLen {
+		return fmt.Errorf("%w: record %x has no checksum", ErrIntegrityViolation, txKey)
+	}
+
+	checksum := recordChecksum(txKey, record)
+	if !bytes.Equal(stored[:checksumLen], checksum[:]) {
+		return fmt.Errorf("%w: record %x does not match its checksum", ErrIntegrityViolation, txKey)
+	}
+
+	if identity == nil {
+		return nil
+	}
+
+	if len(stored) != checksumLen+signatureLen {
+		return fmt.Errorf("%w: record %x is not signed", ErrIntegrityViolation, txKey)
+	}
+
+	sig, err := schnorr.ParseSignature(stored[checksumLen:])
+	if err != nil || !sig.Verify(checksum[:], identity) {
+		return fmt.Errorf("%w: record %x has invalid signature", ErrIntegrityViolation, txKey)
+	}
+
+	return nil
+}
+
+// backfillChecksums stores checksums of records written before checksums
+// were introduced, which are trusted as they are. It runs once, afterwards
+// record without checksum is an integrity violation.
+func (c *TrackedTransactionStore) backfillChecksums() error {
+	done := false
+
+	err := c.db.View(func(tx kvdb.RTx) error {
+		checksumsBucket := tx.ReadBucket(txChecksumsBucketName)
+		if checksumsBucket == nil {
+			return ErrCorruptedTransactionsDB
+		}
+
+		done = checksumsBucket.Get(checksumsBackfilledKey) != nil
+		return nil
+	}, func() {
+		done = false
+	})
+	if err != nil {
+		return fmt.Errorf("failed to check record checksums: %w", err)
+	}
+
+	if done {
+		return nil
+	}
+
+	return kvdb.Batch(c.db, func(tx kvdb.RwTx) error {
+		txBucket := tx.ReadBucket(transactionBucketName)
+		checksumsBucket := tx.ReadWriteBucket(txChecksumsBucketName)
+		if txBucket == nil || checksumsBucket == nil {
+			return ErrCorruptedTransactionsDB
+		}
+
+		if checksumsBucket.Get(checksumsBackfilledKey) != nil {
+			return nil
+		}
+
+		err := txBucket.ForEach(func(k, v []byte) error {
+			if checksumsBucket.Get(k) != nil {
+				return nil
+			}
+
+			checksum := recordChecksum(k, v)
+			return checksumsBucket.Put(k, checksum[:])
+		})
+		if err != nil {
+			return fmt.Errorf("failed to backfill record checksums: %w", err)
+		}
+
+		return checksumsBucket.Put(checksumsBackfilledKey, []byte{1})
+	})
+}
+
+// SetIntegrityKey makes the store sign checksums of records with the daemon
+// identity key. When used for the first time, records with valid checksum
+// are signed and the key is bound to the database, afterwards records must be
+// signed by the same key. Returns ErrIntegrityKeyMismatch if database is
+// bound to other key.
+func (c *TrackedTransactionStore) SetIntegrityKey(key *btcec.PrivateKey) error {
+	pk := schnorr.SerializePubKey(key.PubKey())
+
+	err := batch(c.db, func(tx kvdb.RwTx) error {
+		txBucket := tx.ReadBucket(transactionBucketName)
+		checksumsBucket := tx.ReadWriteBucket(txChecksumsBucketName)
+		if txBucket == nil || checksumsBucket == nil {
+			return ErrCorruptedTransactionsDB
+		}
+
+		bound := checksumsBucket.Get(identityKeyKey)
+		if bound != nil {
+			if !bytes.Equal(bound, pk) {
+				return fmt.Errorf("%w: database is bound to key %s", ErrIntegrityKeyMismatch, hex.EncodeToString(bound))
+			}
+			return nil
+		}
+
+		type signedRecord struct {
+			key      []byte
+			checksum []byte
+		}
+
+		// bucket can't be modified while iterating over it
+		var signed []signedRecord
+		err := txBucket.ForEach(func(k, v []byte) error {
+			if err := verifyChecksum(checksumsBucket.Get(k), k, v, nil); err != nil {
+				return err
+			}
+
+			checksum, err := signedChecksum(k, v, key)
+			if err != nil {
+				return err
+			}
+
+			signed = append(signed, signedRecord{key: append([]byte(nil), k...), checksum: checksum})
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, s := range signed {
+			if err := checksumsBucket.Put(s.key, s.checksum); err != nil {
+				return err
+			}
+		}
+
+		return checksumsBucket.Put(identityKeyKey, pk)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to set integrity key: %w", err)
+	}
+
+	c.integrityKey = key
+	return nil
+}
@@ -0,0 +1,83 @@
+package stakerdb
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/lightningnetwork/lnd/kvdb"
+)
+
+var (
+	// bucket holding single key leaderTermKey -> bigendian(uint64) term of
+	// the latest elected leader. It is used as fencing token, so that
+	// instance which lost leadership stops writing to btc and Babylon.
+	leaderTermBucketName = []byte("leaderTerm")
+
+	leaderTermKey = []byte("term")
+)
+
+func getLeaderTerm(bucket kvdb.RBucket) (uint64, error) {
+	v := bucket.Get(leaderTermKey)
+	if v == nil {
+		return 0, nil
+	}
+
+	if len(v) != 8 {
+		return 0, ErrCorruptedTransactionsDB
+	}
+
+	return binary.BigEndian.Uint64(v), nil
+}
+
+// AdvanceLeaderTerm stores term of newly elected leader. Returns
+// ErrStaleLeaderTerm if leader with newer term was already elected.
+func (c *TrackedTransactionStore) AdvanceLeaderTerm(term uint64) error {
+	return batch(c.db, func(tx kvdb.RwTx) error {
+		bucket := tx.ReadWriteBucket(leaderTermBucketName)
+		if bucket == nil {
+			return ErrCorruptedTransactionsDB
+		}
+
+		current, err := getLeaderTerm(bucket)
+		if err != nil {
+			return err
+		}
+
+		if current > term {
+			return fmt.Errorf("%w: term %d, current term %d", ErrStaleLeaderTerm, term, current)
+		}
+
+		var termBytes [8]byte
+		binary.BigEndian.PutUint64(termBytes[:], term)
+
+		return bucket.Put(leaderTermKey, termBytes[:])
+	})
+}
+
+// CheckLeaderTerm returns ErrStaleLeaderTerm if leader with different term
+// than the given one was elected
+func (c *TrackedTransactionStore) CheckLeaderTerm(term uint64) error {
+	var current uint64
+
+	err := c.db.View(func(tx kvdb.RTx) error {
+		bucket := tx.ReadBucket(leaderTermBucketName)
+		if bucket == nil {
+			return ErrCorruptedTransactionsDB
+		}
+
+		var err error
+		current, err = getLeaderTerm(bucket)
+		return err
+	}, func() {
+		current = 0
+	})
+	if err != nil {
+		return fmt.Errorf("failed to get leader term: %w", err)
+	}
+
+	if current != term {
+		return fmt.Errorf("%w: term %d, current term %d", ErrStaleLeaderTerm, term, current)
+	}
+
+	return nil
+}
@@ -0,0 +1,214 @@
+package stakerdb
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"time"
+
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/kvdb"
+)
+
+var (
+	// mapping txHash -> watched transaction
+	// It holds transactions broadcast by staker which are not yet confirmed
+	mempoolWatchBucketName = []byte("mempoolWatch")
+)
+
+// staking tx hash(32) || kind(1) || broadcast at(8) || seen at(8) ||
+// evicted at(8) || rebroadcasts(4) || serialized tx
+const watchedTransactionHeaderSize = chainhash.HashSize + 1 + 8 + 8 + 8 + 4
+
+// WatchedTxKind is a kind of transaction broadcast by staker
+type WatchedTxKind uint8
+
+const (
+	WatchedStakingTx WatchedTxKind = iota + 1
+	WatchedUnbondingTx
+	WatchedWithdrawalTx
+)
+
+// String returns a string representation of the watched transaction kind
+func (k WatchedTxKind) String() string {
+	switch k {
+	case WatchedStakingTx:
+		return "staking"
+	case WatchedUnbondingTx:
+		return "unbonding"
+	case WatchedWithdrawalTx:
+		return "withdrawal"
+	default:
+		return "unknown"
+	}
+}
+
+// WatchedTransaction is a transaction broadcast by staker, watched until it is
+// confirmed on btc
+type WatchedTransaction struct {
+	Tx            *wire.MsgTx
+	StakingTxHash chainhash.Hash
+	Kind          WatchedTxKind
+	BroadcastAt   time.Time
+	// zero if transaction was not yet seen in mempool
+	SeenInMempoolAt time.Time
+	// zero if transaction was not evicted from mempool
+	EvictedAt    time.Time
+	Rebroadcasts uint32
+}
+
+func unixOrZero(t time.Time) uint64 {
+	if t.IsZero() {
+		return 0
+	}
+	return uint64(t.Unix())
+}
+
+func timeOrZero(v uint64) time.Time {
+	if v == 0 {
+		return time.Time{}
+	}
+	return time.Unix(int64(v), 0)
+}
+
+func (w *WatchedTransaction) serialize() ([]byte, error) {
+	var txBuf bytes.Buffer
+	if err := w.Tx.Serialize(&txBuf); err != nil {
+		return nil, err
+	}
+
+	b := make([]byte, watchedTransactionHeaderSize, watchedTransactionHeaderSize+txBuf.Len())
+	copy(b[:32], w.StakingTxHash[:])
+	b[32] = byte(w.Kind)
+	binary.BigEndian.PutUint64(b[33:41], unixOrZero(w.BroadcastAt))
+	binary.BigEndian.PutUint64(b[41:49], unixOrZero(w.SeenInMempoolAt))
+	binary.BigEndian.PutUint64(b[49:57], unixOrZero(w.EvictedAt))
+	binary.BigEndian.PutUint32(b[57:61], w.Rebroadcasts)
+
+	return append(b, txBuf.Bytes()...), nil
+}
+
+func deserializeWatchedTransaction(b []byte) (*WatchedTransaction, error) {
+	if len(b) < watchedTransactionHeaderSize {
+		return nil, ErrCorruptedTransactionsDB
+	}
+
+	var tx wire.MsgTx
+	if err := tx.Deserialize(bytes.NewReader(b[watchedTransactionHeaderSize:])); err != nil {
+		return nil, ErrCorruptedTransactionsDB
+	}
+
+	var stakingTxHash chainhash.Hash
+	copy(stakingTxHash[:], b[:32])
+
+	return &WatchedTransaction{
+		Tx:              &tx,
+		StakingTxHash:   stakingTxHash,
+		Kind:            WatchedTxKind(b[32]),
+		BroadcastAt:     timeOrZero(binary.BigEndian.Uint64(b[33:41])),
+		SeenInMempoolAt: timeOrZero(binary.BigEndian.Uint64(b[41:49])),
+		EvictedAt:       timeOrZero(binary.BigEndian.Uint64(b[49:57])),
+		Rebroadcasts:    binary.BigEndian.Uint32(b[57:61]),
+	}, nil
+}
+
+// PutWatchedTransaction stores watched transaction, overwriting previous state
+// of the same transaction. Unbonding and withdrawal transactions are also
+// indexed by their hash.
+func (c *TrackedTransactionStore) PutWatchedTransaction(w *WatchedTransaction) error {
+	v, err := w.serialize()
+	if err != nil {
+		return fmt.Errorf("failed to serialize watched transaction: %w", err)
+	}
+
+	txHash := w.Tx.TxHash()
+
+	return c.update(func(tx kvdb.RwTx) error {
+		watchBucket := tx.ReadWriteBucket(mempoolWatchBucketName)
+		if watchBucket == nil {
+			return ErrCorruptedTransactionsDB
+		}
+
+		if err := watchBucket.Put(txHash[:], v); err != nil {
+			return err
+		}
+
+		if w.Kind == WatchedUnbondingTx || w.Kind == WatchedWithdrawalTx {
+			if err := putSpendTxIndex(tx, &txHash, &w.StakingTxHash, w.Kind); err != nil {
+				return err
+			}
+		}
+
+		return appendChange(tx, ChangeWatchedTxUpdated, &w.StakingTxHash, fmt.Sprintf("%s %s", w.Kind, txHash))
+	})
+}
+
+// DeleteWatchedTransaction stops watching transaction with given hash
+func (c *TrackedTransactionStore) DeleteWatchedTransaction(txHash *chainhash.Hash) error {
+	return c.update(func(tx kvdb.RwTx) error {
+		watchBucket := tx.ReadWriteBucket(mempoolWatchBucketName)
+		if watchBucket == nil {
+			return ErrCorruptedTransactionsDB
+		}
+
+		v := watchBucket.Get(txHash[:])
+		if v == nil {
+			return nil
+		}
+
+		w, err := deserializeWatchedTransaction(v)
+		if err != nil {
+			return err
+		}
+
+		if err := watchBucket.Delete(txHash[:]); err != nil {
+			return err
+		}
+
+		return appendChange(tx, ChangeWatchedTxRemoved, &w.StakingTxHash, fmt.Sprintf("%s %s", w.Kind, txHash))
+	})
+}
+
+// ListWatchedTransactions returns all watched transactions
+func (c *TrackedTransactionStore) ListWatchedTransactions() ([]*WatchedTransaction, error) {
+	return c.listWatchedTransactions(nil)
+}
+
+// WatchedTransactionsOf returns watched transactions related to given staking
+// transaction
+func (c *TrackedTransactionStore) WatchedTransactionsOf(stakingTxHash *chainhash.Hash) ([]*WatchedTransaction, error) {
+	return c.listWatchedTransactions(stakingTxHash)
+}
+
+func (c *TrackedTransactionStore) listWatchedTransactions(stakingTxHash *chainhash.Hash) ([]*WatchedTransaction, error) {
+	var watched []*WatchedTransaction
+
+	err := c.db.View(func(tx kvdb.RTx) error {
+		watchBucket := tx.ReadBucket(mempoolWatchBucketName)
+		if watchBucket == nil {
+			return ErrCorruptedTransactionsDB
+		}
+
+		return watchBucket.ForEach(func(_, v []byte) error {
+			w, err := deserializeWatchedTransaction(v)
+			if err != nil {
+				return err
+			}
+
+			if stakingTxHash != nil && !w.StakingTxHash.IsEqual(stakingTxHash) {
+				return nil
+			}
+
+			watched = append(watched, w)
+			return nil
+		})
+	}, func() {
+		watched = nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list watched transactions: %w", err)
+	}
+
+	return watched, nil
+}
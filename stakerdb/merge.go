@@ -0,0 +1,326 @@
+package stakerdb
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"github.com/babylonlabs-io/btc-staker/proto"
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcwallet/walletdb"
+	"github.com/lightningnetwork/lnd/kvdb"
+	pm "google.golang.org/protobuf/proto"
+)
+
+// perTransactionBuckets are buckets keyed by staking tx hash, whose entries
+// are moved together with tracked transaction when databases are merged
+var perTransactionBuckets = [][]byte{
+	failedTransactionsBucketName,
+	creationHeightsBucketName,
+	broadcastHeightsBucketName,
+	paidFeesBucketName,
+	tenantsBucketName,
+	labelsBucketName,
+	statusHistoryBucketName,
+	covenantQuorumBucketName,
+	unbondRequestsBucketName,
+	babylonTxsBucketName,
+	spendableHeightsBucketName,
+	failureNotesBucketName,
+	autoRenewBucketName,
+	renewalJobsBucketName,
+	activationPhasesBucketName,
+	unbondingSignaturesBucketName,
+	finalityProvidersBucketName,
+}
+
+// errMergeDryRun rolls back merge transaction of dry run
+var errMergeDryRun = errors.New("merge dry run")
+
+// MergeResult describes outcome of merging other database into the store
+type MergeResult struct {
+	// Merged are transactions which started to be tracked
+	Merged []chainhash.Hash
+	// AlreadyTracked are transactions which were tracked by both databases,
+	// data of the store is kept for them
+	AlreadyTracked []chainhash.Hash
+	// Conflicting are transactions spending the same outpoint as other
+	// transaction tracked by the store, they are not merged
+	Conflicting []chainhash.Hash
+	// Undecodable are keys of source transactions which can't be decoded
+	Undecodable []string
+}
+
+// mergedTransaction is tracked transaction of source database together with
+// all its data
+type mergedTransaction struct {
+	hash    chainhash.Hash
+	tx      *proto.TrackedTransaction
+	inputs  *inputData
+	perTx   map[string][]byte
+	watched map[chainhash.Hash][]byte
+	events  [][]byte
+}
+
+// MergeFrom merges tracked transactions of src database into the store, in a
+// single db transaction. Merged transactions get new indexes of the store,
+// reserved inputs are rebuilt from staking transactions. Watched transactions
+// and activity events of merged transactions are copied as well, while
+// changelog, templates and stake queue of src are not. If dryRun is true,
+// nothing is written, but result describes what would be merged.
+func (c *TrackedTransactionStore) MergeFrom(src *TrackedTransactionStore, dryRun bool) (*MergeResult, error) {
+	if err := c.checkSameNetwork(src); err != nil {
+		return nil, err
+	}
+
+	transactions, undecodable, err := src.transactionsToMerge()
+	if err != nil {
+		return nil, err
+	}
+
+	var result *MergeResult
+	err = c.update(func(tx kvdb.RwTx) error {
+		result = &MergeResult{Undecodable: undecodable}
+
+		transactionIdxBucket := tx.ReadWriteBucket(transactionIndexName)
+		if transactionIdxBucket == nil {
+			return ErrCorruptedTransactionsDB
+		}
+
+		transactionsBucket := tx.ReadWriteBucket(transactionBucketName)
+		if transactionsBucket == nil {
+			return ErrCorruptedTransactionsDB
+		}
+
+		inputsBucket := tx.ReadWriteBucket(inputsDataBucketName)
+		if inputsBucket == nil {
+			return ErrCorruptedTransactionsDB
+		}
+
+		for _, m := range transactions {
+			if transactionIdxBucket.Get(m.hash[:]) != nil {
+				result.AlreadyTracked = append(result.AlreadyTracked, m.hash)
+				continue
+			}
+
+			if spendsReservedInput(inputsBucket, m.inputs) {
+				result.Conflicting = append(result.Conflicting, m.hash)
+				continue
+			}
+
+			if err := m.save(tx, transactionIdxBucket, transactionsBucket, c.integrityKey); err != nil {
+				return fmt.Errorf("failed to merge transaction %s: %w", m.hash, err)
+			}
+			result.Merged = append(result.Merged, m.hash)
+		}
+
+		if dryRun {
+			return errMergeDryRun
+		}
+
+		return nil
+	})
+	if err != nil && !errors.Is(err, errMergeDryRun) {
+		return nil, fmt.Errorf("failed to merge databases: %w", err)
+	}
+
+	return result, nil
+}
+
+// checkSameNetwork returns ErrNetworkMismatch if both databases are bound to
+// different networks
+func (c *TrackedTransactionStore) checkSameNetwork(src *TrackedTransactionStore) error {
+	network, err := c.Network()
+	if err != nil {
+		return err
+	}
+
+	srcNetwork, err := src.Network()
+	if err != nil {
+		return err
+	}
+
+	if network != nil && srcNetwork != nil && *network != *srcNetwork {
+		return fmt.Errorf("%w: database is bound to %s, merged database to %s",
+			ErrNetworkMismatch, network, srcNetwork)
+	}
+
+	return nil
+}
+
+func spendsReservedInput(inputsBucket walletdb.ReadBucket, inputs *inputData) bool {
+	for _, input := range inputs.inputs {
+		if inputsBucket.Get(input) != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// save stores transaction under new index of the store together with its data
+func (m *mergedTransaction) save(
+	tx kvdb.RwTx,
+	txIdxBucket, txBucket walletdb.ReadWriteBucket,
+	key *btcec.PrivateKey,
+) error {
+	if err := saveTrackedTransaction(tx, txIdxBucket, txBucket, m.hash[:], m.tx, m.inputs, key); err != nil {
+		return err
+	}
+
+	for name, v := range m.perTx {
+		if err := putImported(tx, []byte(name), m.hash[:], v); err != nil {
+			return err
+		}
+	}
+
+	for watchedHash, v := range m.watched {
+		if err := putImported(tx, mempoolWatchBucketName, watchedHash[:], v); err != nil {
+			return err
+		}
+	}
+
+	if len(m.events) > 0 {
+		activityBucket := tx.ReadWriteBucket(activityBucketName)
+		if activityBucket == nil {
+			return ErrCorruptedTransactionsDB
+		}
+
+		var nextKey uint64
+		if keyBytes := activityBucket.Get(nextActivityKey); keyBytes != nil {
+			nextKey = binary.BigEndian.Uint64(keyBytes)
+		}
+
+		for _, ev := range m.events {
+			if err := activityBucket.Put(uint64KeyToBytes(nextKey), ev); err != nil {
+				return fmt.Errorf("failed to save activity event: %w", err)
+			}
+			nextKey++
+		}
+
+		if err := activityBucket.Put(nextActivityKey, uint64KeyToBytes(nextKey)); err != nil {
+			return err
+		}
+	}
+
+	return appendChange(tx, ChangeTransactionImported, &m.hash, m.tx.StakerAddress)
+}
+
+// transactionsToMerge reads tracked transactions of the store with all their
+// data, ordered by their index. Buckets missing in older databases are
+// treated as empty.
+func (c *TrackedTransactionStore) transactionsToMerge() ([]*mergedTransaction, []string, error) {
+	var (
+		transactions []*mergedTransaction
+		undecodable  []string
+	)
+
+	err := c.db.View(func(tx kvdb.RTx) error {
+		transactionsBucket := tx.ReadBucket(transactionBucketName)
+		if transactionsBucket == nil {
+			return ErrCorruptedTransactionsDB
+		}
+
+		byHash := make(map[chainhash.Hash]*mergedTransaction)
+		err := transactionsBucket.ForEach(func(txKey, v []byte) error {
+			// records failing integrity check are not merged, like the
+			// undecodable ones
+			if err := verifyRecord(tx, txKey, v); err != nil {
+				undecodable = append(undecodable, hex.EncodeToString(txKey))
+				return nil
+			}
+
+			var storedTxProto proto.TrackedTransaction
+			if err := pm.Unmarshal(v, &storedTxProto); err != nil {
+				undecodable = append(undecodable, hex.EncodeToString(txKey))
+				return nil
+			}
+
+			storedTx, err := protoTxToStoredTransaction(&storedTxProto)
+			if err != nil {
+				undecodable = append(undecodable, hex.EncodeToString(txKey))
+				return nil
+			}
+
+			inputs, err := getInputData(storedTx.StakingTx)
+			if err != nil {
+				return err
+			}
+
+			m := &mergedTransaction{
+				hash:    storedTx.StakingTx.TxHash(),
+				tx:      &storedTxProto,
+				inputs:  inputs,
+				perTx:   make(map[string][]byte),
+				watched: make(map[chainhash.Hash][]byte),
+			}
+
+			for _, name := range perTransactionBuckets {
+				bucket := tx.ReadBucket(name)
+				if bucket == nil {
+					continue
+				}
+				if v := bucket.Get(m.hash[:]); v != nil {
+					m.perTx[string(name)] = bytes.Clone(v)
+				}
+			}
+
+			transactions = append(transactions, m)
+			byHash[m.hash] = m
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		if watchBucket := tx.ReadBucket(mempoolWatchBucketName); watchBucket != nil {
+			err := watchBucket.ForEach(func(k, v []byte) error {
+				w, err := deserializeWatchedTransaction(v)
+				if err != nil {
+					return err
+				}
+
+				if m, ok := byHash[w.StakingTxHash]; ok {
+					m.watched[w.Tx.TxHash()] = bytes.Clone(v)
+				}
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		if activityBucket := tx.ReadBucket(activityBucketName); activityBucket != nil {
+			err := activityBucket.ForEach(func(k, v []byte) error {
+				if bytes.Equal(k, nextActivityKey) {
+					return nil
+				}
+
+				ev, err := deserializeActivityEvent(v)
+				if err != nil {
+					return err
+				}
+
+				if m, ok := byHash[ev.StakingTxHash]; ok {
+					m.events = append(m.events, bytes.Clone(v))
+				}
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}, func() {
+		transactions = nil
+		undecodable = nil
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read transactions to merge: %w", err)
+	}
+
+	return transactions, undecodable, nil
+}
@@ -55,7 +55,7 @@ func (c *TrackedTransactionStore) MigrateTrackedTransactions() (*MigrationResult
 				continue
 			}
 
-			if err := bucket.Put(k, newData); err != nil {
+			if err := putTrackedTransaction(tx, bucket, k, newData, c.integrityKey); err != nil {
 				return fmt.Errorf("failed to update transaction at key %x: %w", k, err)
 			}
 
@@ -3,6 +3,8 @@ package stakerdb_test
 import (
 	"bytes"
 	"encoding/binary"
+	"encoding/json"
+	"errors"
 	"math/rand"
 	"reflect"
 	"testing"
@@ -14,6 +16,7 @@ import (
 	"github.com/babylonlabs-io/btc-staker/stakerdb"
 	"github.com/btcsuite/btcd/btcutil"
 	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
 	"github.com/btcsuite/btcd/wire"
 	"github.com/lightningnetwork/lnd/kvdb"
 	"github.com/stretchr/testify/require"
@@ -496,3 +499,145 @@ func verifyMigratedTransactions(t *testing.T, store *stakerdb.TrackedTransaction
 		require.Equal(t, original.StakingTransaction, buf.Bytes())
 	}
 }
+
+// TestMigrateStakerAddressesToNet tests conversion of staker addresses stored
+// while staker ran on other network
+func TestMigrateStakerAddressesToNet(t *testing.T) {
+	t.Parallel()
+	r := rand.New(rand.NewSource(time.Now().UnixNano()))
+	store := MakeTestStore(t)
+
+	regtestTx := genStoredTransaction(t, r)
+	regtestAddr, err := btcutil.NewAddressWitnessPubKeyHash(datagen.GenRandomByteArray(r, 20), &chaincfg.RegressionNetParams)
+	require.NoError(t, err)
+	require.NoError(t, store.AddTransactionSentToBabylon(regtestTx.StakingTx, regtestAddr))
+
+	signetTx := genStoredTransaction(t, r)
+	signetAddr, err := btcutil.NewAddressTaproot(datagen.GenRandomByteArray(r, 32), &chaincfg.SigNetParams)
+	require.NoError(t, err)
+	require.NoError(t, store.AddTransactionSentToBabylon(signetTx.StakingTx, signetAddr))
+
+	result, err := store.MigrateStakerAddressesToNet(&chaincfg.SigNetParams)
+	require.NoError(t, err)
+	require.Equal(t, 2, result.ProcessedCount)
+	require.Equal(t, 1, result.MigratedCount)
+	require.Equal(t, 1, result.SkippedCount)
+	require.Equal(t, 0, result.ErrorCount)
+
+	regtestTxHash := regtestTx.StakingTx.TxHash()
+	migrated, err := store.GetTransaction(&regtestTxHash)
+	require.NoError(t, err)
+	migratedAddr, err := btcutil.DecodeAddress(migrated.StakerAddress, &chaincfg.SigNetParams)
+	require.NoError(t, err)
+	require.True(t, migratedAddr.IsForNet(&chaincfg.SigNetParams))
+	require.Equal(t, regtestAddr.ScriptAddress(), migratedAddr.ScriptAddress())
+
+	signetTxHash := signetTx.StakingTx.TxHash()
+	unchanged, err := store.GetTransaction(&signetTxHash)
+	require.NoError(t, err)
+	require.Equal(t, signetAddr.EncodeAddress(), unchanged.StakerAddress)
+
+	// database is rebound to the network of migrated addresses
+	network, err := store.Network()
+	require.NoError(t, err)
+	require.NotNil(t, network)
+	require.Equal(t, chaincfg.SigNetParams.Name, network.Name)
+	require.NoError(t, store.BindNetwork(&chaincfg.SigNetParams))
+
+	// migration is idempotent
+	result, err = store.MigrateStakerAddressesToNet(&chaincfg.SigNetParams)
+	require.NoError(t, err)
+	require.Equal(t, 0, result.MigratedCount)
+	require.Equal(t, 2, result.SkippedCount)
+}
+
+func TestCleanUnbondingSignatures(t *testing.T) {
+	t.Parallel()
+	r := rand.New(rand.NewSource(time.Now().UnixNano()))
+	store := MakeTestStore(t)
+
+	committee := [][]byte{{1}, {2}, {3}}
+	sig := func(pk byte) stakerdb.CovenantSignature {
+		return stakerdb.CovenantSignature{CovenantPk: []byte{pk}, Signature: []byte{pk, pk}}
+	}
+
+	seed := func(sigs *stakerdb.UnbondingSignatures) chainhash.Hash {
+		storedTx := genStoredTransaction(t, r)
+		txHash := storedTx.StakingTx.TxHash()
+		stakerAddr, err := btcutil.DecodeAddress(storedTx.StakerAddress, &chaincfg.MainNetParams)
+		require.NoError(t, err)
+		require.NoError(t, store.AddTransactionSentToBabylon(storedTx.StakingTx, stakerAddr))
+
+		// written directly, as store rejects invalid signatures
+		encoded, err := json.Marshal(sigs)
+		require.NoError(t, err)
+		require.NoError(t, kvdb.Update(getDBFromStore(store), func(tx kvdb.RwTx) error {
+			return tx.ReadWriteBucket([]byte("unbondingSignatures")).Put(txHash[:], encoded)
+		}, func() {}))
+
+		return txHash
+	}
+
+	// valid signatures with committee are left unchanged
+	validHash := seed(&stakerdb.UnbondingSignatures{
+		ParamsVersion: 1, Quorum: 2, Committee: committee,
+		Signatures: []stakerdb.CovenantSignature{sig(1), sig(2)},
+	})
+	// duplicate and outsider signatures are removed, committee is looked up
+	dirtyHash := seed(&stakerdb.UnbondingSignatures{
+		ParamsVersion: 1, Quorum: 2,
+		Signatures: []stakerdb.CovenantSignature{sig(1), sig(1), sig(9), sig(3)},
+	})
+	// signatures below quorum after cleaning are removed
+	belowQuorumHash := seed(&stakerdb.UnbondingSignatures{
+		ParamsVersion: 1, Quorum: 2, Committee: committee,
+		Signatures: []stakerdb.CovenantSignature{sig(2), sig(2)},
+	})
+	// committee of unknown params version can't be resolved
+	unknownHash := seed(&stakerdb.UnbondingSignatures{
+		ParamsVersion: 7, Quorum: 1,
+		Signatures: []stakerdb.CovenantSignature{sig(1), sig(1)},
+	})
+
+	lookups := 0
+	lookup := func(paramsVersion uint32) ([][]byte, error) {
+		lookups++
+		if paramsVersion != 1 {
+			return nil, errors.New("unknown params version")
+		}
+		return committee, nil
+	}
+
+	result, err := store.CleanUnbondingSignatures(lookup)
+	require.NoError(t, err)
+	require.Equal(t, 4, result.ProcessedCount)
+	require.Equal(t, 2, result.MigratedCount)
+	require.Equal(t, 1, result.SkippedCount)
+	require.Equal(t, 1, result.ErrorCount)
+	require.Equal(t, 2, lookups)
+
+	valid, err := store.GetTxUnbondingSignatures(&validHash)
+	require.NoError(t, err)
+	require.Len(t, valid.Signatures, 2)
+
+	cleaned, err := store.GetTxUnbondingSignatures(&dirtyHash)
+	require.NoError(t, err)
+	require.Equal(t, committee, cleaned.Committee)
+	require.Equal(t, []stakerdb.CovenantSignature{sig(1), sig(3)}, cleaned.Signatures)
+	require.NoError(t, cleaned.Validate())
+
+	removed, err := store.GetTxUnbondingSignatures(&belowQuorumHash)
+	require.NoError(t, err)
+	require.Nil(t, removed)
+
+	unknown, err := store.GetTxUnbondingSignatures(&unknownHash)
+	require.NoError(t, err)
+	require.Len(t, unknown.Signatures, 2)
+
+	// migration is idempotent
+	result, err = store.CleanUnbondingSignatures(nil)
+	require.NoError(t, err)
+	require.Equal(t, 0, result.MigratedCount)
+	require.Equal(t, 2, result.SkippedCount)
+	require.Equal(t, 1, result.ErrorCount)
+}
@@ -0,0 +1,355 @@
+package stakerdb
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/lightningnetwork/lnd/kvdb"
+)
+
+var (
+	// mapping key name -> json encoded musig2 key
+	// It holds MuSig2 aggregate keys used as staker keys
+	musig2KeysBucketName = []byte("musig2Keys")
+
+	// mapping uint64 -> json encoded musig2 signing session
+	musig2SessionsBucketName = []byte("musig2Sessions")
+
+	// mapping hex encoded public nonce -> uint64 session id
+	// Public nonces can never be reused, as signing two messages with the same
+	// nonce reveals the signer private key
+	musig2NoncesBucketName = []byte("musig2Nonces")
+
+	// key for next musig2 session id
+	nextMuSig2SessionKey = []byte("nms")
+)
+
+// MuSig2Key is a staker key aggregated from public keys of several signers
+type MuSig2Key struct {
+	Name string `json:"name"`
+	// hex encoded compressed public keys of signers in sorted order
+	PubKeys []string `json:"pub_keys"`
+	// hex encoded x-only aggregate key
+	AggregateKey string    `json:"aggregate_key"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// MuSig2Session collects nonces and partial signatures of signers of MuSig2
+// key over a single message
+type MuSig2Session struct {
+	ID      uint64 `json:"-"`
+	KeyName string `json:"key_name"`
+	Kind    string `json:"kind"`
+	// hex encoded staking tx hash, empty if message is not related to delegation
+	StakingTxHash string `json:"staking_tx_hash,omitempty"`
+	// hex encoded transaction signed in the session, empty if message is not
+	// a transaction sighash
+	TxHex string `json:"tx_hex,omitempty"`
+	// hex encoded 32 byte message
+	Message string `json:"message"`
+	// hex encoded signer public key -> hex encoded public nonce
+	Nonces map[string]string `json:"nonces"`
+	// hex encoded aggregate of all public nonces
+	AggregateNonce string `json:"aggregate_nonce,omitempty"`
+	// hex encoded signer public key -> hex encoded partial signature
+	PartialSigs map[string]string `json:"partial_sigs"`
+	// hex encoded schnorr signature of the aggregate key
+	Signature string `json:"signature,omitempty"`
+	// hash of transaction sent with the signature
+	BroadcastTxHash string `json:"broadcast_tx_hash,omitempty"`
+	// reason why signed transaction could not be sent
+	Error       string    `json:"error,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+	CompletedAt time.Time `json:"completed_at,omitempty"`
+}
+
+// stakingTxHash returns staking tx hash of the session, nil if there is none
+func (s *MuSig2Session) stakingTxHash() *chainhash.Hash {
+	if s.StakingTxHash == "" {
+		return nil
+	}
+
+	hash, err := chainhash.NewHashFromStr(s.StakingTxHash)
+	if err != nil {
+		return nil
+	}
+	return hash
+}
+
+// PutMuSig2Key stores new MuSig2 key. Keys can not be replaced, as delegations
+// depend on them.
+func (c *TrackedTransactionStore) PutMuSig2Key(key *MuSig2Key) error {
+	encoded, err := json.Marshal(key)
+	if err != nil {
+		return fmt.Errorf("failed to encode musig2 key: %w", err)
+	}
+
+	return c.update(func(tx kvdb.RwTx) error {
+		keysBucket := tx.ReadWriteBucket(musig2KeysBucketName)
+		if keysBucket == nil {
+			return ErrCorruptedTransactionsDB
+		}
+
+		if keysBucket.Get([]byte(key.Name)) != nil {
+			return ErrMuSig2KeyExists
+		}
+
+		return keysBucket.Put([]byte(key.Name), encoded)
+	})
+}
+
+// GetMuSig2Key returns MuSig2 key with given name
+func (c *TrackedTransactionStore) GetMuSig2Key(name string) (*MuSig2Key, error) {
+	var key *MuSig2Key
+
+	err := c.db.View(func(tx kvdb.RTx) error {
+		keysBucket := tx.ReadBucket(musig2KeysBucketName)
+		if keysBucket == nil {
+			return ErrCorruptedTransactionsDB
+		}
+
+		v := keysBucket.Get([]byte(name))
+		if v == nil {
+			return ErrMuSig2KeyNotFound
+		}
+
+		key = new(MuSig2Key)
+		return json.Unmarshal(v, key)
+	}, func() {
+		key = nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get musig2 key %s: %w", name, err)
+	}
+
+	return key, nil
+}
+
+// ListMuSig2Keys returns all MuSig2 keys sorted by name
+func (c *TrackedTransactionStore) ListMuSig2Keys() ([]MuSig2Key, error) {
+	var keys []MuSig2Key
+
+	err := c.db.View(func(tx kvdb.RTx) error {
+		keysBucket := tx.ReadBucket(musig2KeysBucketName)
+		if keysBucket == nil {
+			return ErrCorruptedTransactionsDB
+		}
+
+		return keysBucket.ForEach(func(_, v []byte) error {
+			var key MuSig2Key
+			if err := json.Unmarshal(v, &key); err != nil {
+				return err
+			}
+
+			keys = append(keys, key)
+			return nil
+		})
+	}, func() {
+		keys = nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list musig2 keys: %w", err)
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].Name < keys[j].Name
+	})
+
+	return keys, nil
+}
+
+// AddMuSig2Session persists new signing session and returns its id
+func (c *TrackedTransactionStore) AddMuSig2Session(session *MuSig2Session) (uint64, error) {
+	if session == nil {
+		return 0, fmt.Errorf("cannot add nil musig2 session")
+	}
+
+	encoded, err := json.Marshal(session)
+	if err != nil {
+		return 0, fmt.Errorf("failed to encode musig2 session: %w", err)
+	}
+
+	var id uint64
+	err = c.update(func(tx kvdb.RwTx) error {
+		sessionsBucket := tx.ReadWriteBucket(musig2SessionsBucketName)
+		if sessionsBucket == nil {
+			return ErrCorruptedTransactionsDB
+		}
+
+		var nextID uint64
+		if keyBytes := sessionsBucket.Get(nextMuSig2SessionKey); keyBytes != nil {
+			nextID = binary.BigEndian.Uint64(keyBytes)
+		}
+
+		if err := sessionsBucket.Put(uint64KeyToBytes(nextID), encoded); err != nil {
+			return fmt.Errorf("failed to save musig2 session: %w", err)
+		}
+
+		if err := sessionsBucket.Put(nextMuSig2SessionKey, uint64KeyToBytes(nextID+1)); err != nil {
+			return err
+		}
+
+		id = nextID
+		return appendChange(tx, ChangeMuSig2SessionCreated, session.stakingTxHash(),
+			fmt.Sprintf("%d: %s", nextID, session.Kind))
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return id, nil
+}
+
+// GetMuSig2Session returns signing session with given id
+func (c *TrackedTransactionStore) GetMuSig2Session(id uint64) (*MuSig2Session, error) {
+	var session *MuSig2Session
+
+	err := c.db.View(func(tx kvdb.RTx) error {
+		sessionsBucket := tx.ReadBucket(musig2SessionsBucketName)
+		if sessionsBucket == nil {
+			return ErrCorruptedTransactionsDB
+		}
+
+		v := sessionsBucket.Get(uint64KeyToBytes(id))
+		if v == nil {
+			return ErrMuSig2SessionNotFound
+		}
+
+		session = new(MuSig2Session)
+		if err := json.Unmarshal(v, session); err != nil {
+			return err
+		}
+		session.ID = id
+		return nil
+	}, func() {
+		session = nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return session, nil
+}
+
+// ListMuSig2Sessions returns all signing sessions in the order in which they
+// were created
+func (c *TrackedTransactionStore) ListMuSig2Sessions() ([]MuSig2Session, error) {
+	var sessions []MuSig2Session
+
+	err := c.db.View(func(tx kvdb.RTx) error {
+		sessionsBucket := tx.ReadBucket(musig2SessionsBucketName)
+		if sessionsBucket == nil {
+			return ErrCorruptedTransactionsDB
+		}
+
+		return sessionsBucket.ForEach(func(k, v []byte) error {
+			// skip the counter key
+			if len(k) != 8 {
+				return nil
+			}
+
+			var session MuSig2Session
+			if err := json.Unmarshal(v, &session); err != nil {
+				return err
+			}
+			session.ID = binary.BigEndian.Uint64(k)
+
+			sessions = append(sessions, session)
+			return nil
+		})
+	}, func() {
+		sessions = nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list musig2 sessions: %w", err)
+	}
+
+	return sessions, nil
+}
+
+// UpdateMuSig2Session atomically applies updateFn to the signing session. If
+// updateFn returns an error, the session is not modified. Public nonces added
+// by updateFn must not have been used in any session before, otherwise
+// ErrMuSig2NonceReused is returned.
+func (c *TrackedTransactionStore) UpdateMuSig2Session(
+	id uint64,
+	updateFn func(session *MuSig2Session) error,
+) (*MuSig2Session, error) {
+	var updated *MuSig2Session
+
+	err := c.update(func(tx kvdb.RwTx) error {
+		sessionsBucket := tx.ReadWriteBucket(musig2SessionsBucketName)
+		if sessionsBucket == nil {
+			return ErrCorruptedTransactionsDB
+		}
+
+		key := uint64KeyToBytes(id)
+		v := sessionsBucket.Get(key)
+		if v == nil {
+			return ErrMuSig2SessionNotFound
+		}
+
+		var session MuSig2Session
+		if err := json.Unmarshal(v, &session); err != nil {
+			return err
+		}
+		session.ID = id
+		wasCompleted := session.Signature != ""
+
+		prevNonces := make(map[string]string, len(session.Nonces))
+		for signer, nonce := range session.Nonces {
+			prevNonces[signer] = nonce
+		}
+
+		if err := updateFn(&session); err != nil {
+			return err
+		}
+
+		noncesBucket := tx.ReadWriteBucket(musig2NoncesBucketName)
+		if noncesBucket == nil {
+			return ErrCorruptedTransactionsDB
+		}
+
+		for signer, nonce := range session.Nonces {
+			if prevNonces[signer] == nonce {
+				continue
+			}
+
+			if noncesBucket.Get([]byte(nonce)) != nil {
+				return ErrMuSig2NonceReused
+			}
+
+			if err := noncesBucket.Put([]byte(nonce), key); err != nil {
+				return err
+			}
+		}
+
+		encoded, err := json.Marshal(&session)
+		if err != nil {
+			return fmt.Errorf("failed to encode musig2 session: %w", err)
+		}
+
+		if err := sessionsBucket.Put(key, encoded); err != nil {
+			return err
+		}
+
+		updated = &session
+
+		if !wasCompleted && session.Signature != "" {
+			return appendChange(tx, ChangeMuSig2SessionCompleted, session.stakingTxHash(),
+				strconv.FormatUint(id, 10))
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return updated, nil
+}
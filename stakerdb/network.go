@@ -0,0 +1,111 @@
+package stakerdb
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/kvdb"
+)
+
+var (
+	// bucket holding single key networkKey -> bigendian(uint32) network magic
+	// || network name. It holds btc network the database was first used on.
+	networkBucketName = []byte("network")
+
+	networkKey = []byte("network")
+)
+
+// Network is btc network the database is bound to
+type Network struct {
+	Name string
+	Net  wire.BitcoinNet
+}
+
+// String returns a string representation of the network
+func (n Network) String() string {
+	return fmt.Sprintf("%s (magic %08x)", n.Name, uint32(n.Net))
+}
+
+func networkOf(params *chaincfg.Params) Network {
+	return Network{Name: params.Name, Net: params.Net}
+}
+
+func getNetwork(bucket kvdb.RBucket) (*Network, error) {
+	v := bucket.Get(networkKey)
+	if v == nil {
+		return nil, nil
+	}
+
+	if len(v) < 4 {
+		return nil, ErrCorruptedTransactionsDB
+	}
+
+	return &Network{
+		Name: string(v[4:]),
+		Net:  wire.BitcoinNet(binary.BigEndian.Uint32(v[:4])),
+	}, nil
+}
+
+func putNetwork(bucket kvdb.RwBucket, network Network) error {
+	v := make([]byte, 4+len(network.Name))
+	binary.BigEndian.PutUint32(v[:4], uint32(network.Net))
+	copy(v[4:], network.Name)
+
+	return bucket.Put(networkKey, v)
+}
+
+// BindNetwork binds the database to the given btc network on its first use.
+// Returns ErrNetworkMismatch if the database is bound to other network, so
+// that database of test network is never used on mainnet and vice versa.
+func (c *TrackedTransactionStore) BindNetwork(params *chaincfg.Params) error {
+	network := networkOf(params)
+
+	return batch(c.db, func(tx kvdb.RwTx) error {
+		bucket := tx.ReadWriteBucket(networkBucketName)
+		if bucket == nil {
+			return ErrCorruptedTransactionsDB
+		}
+
+		bound, err := getNetwork(bucket)
+		if err != nil {
+			return err
+		}
+
+		if bound == nil {
+			return putNetwork(bucket, network)
+		}
+
+		if *bound != network {
+			return fmt.Errorf("%w: database is bound to %s, configured network is %s",
+				ErrNetworkMismatch, bound, network)
+		}
+
+		return nil
+	})
+}
+
+// Network returns btc network the database is bound to, or nil if it was not
+// used by staker yet
+func (c *TrackedTransactionStore) Network() (*Network, error) {
+	var network *Network
+
+	err := c.db.View(func(tx kvdb.RTx) error {
+		bucket := tx.ReadBucket(networkBucketName)
+		if bucket == nil {
+			return ErrCorruptedTransactionsDB
+		}
+
+		var err error
+		network, err = getNetwork(bucket)
+		return err
+	}, func() {
+		network = nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get database network: %w", err)
+	}
+
+	return network, nil
+}
@@ -0,0 +1,166 @@
+package stakerdb
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/lightningnetwork/lnd/kvdb"
+)
+
+var (
+	// mapping staking txHash -> staking fee(8) || unbonding fee(8) || withdrawal fee(8)
+	// It holds fees paid by transactions broadcast for the delegation. Like
+	// activity, fees survive deletion of tracked transactions.
+	paidFeesBucketName = []byte("paidFees")
+)
+
+const delegationFeesSize = 3 * 8
+
+// DelegationFees are fees paid by btc transactions of a single delegation
+type DelegationFees struct {
+	StakingTxHash chainhash.Hash
+	StakingFee    btcutil.Amount
+	UnbondingFee  btcutil.Amount
+	WithdrawalFee btcutil.Amount
+}
+
+// Total returns sum of all fees paid for the delegation
+func (f *DelegationFees) Total() btcutil.Amount {
+	return f.StakingFee + f.UnbondingFee + f.WithdrawalFee
+}
+
+func (f *DelegationFees) serialize() []byte {
+	b := make([]byte, delegationFeesSize)
+	binary.BigEndian.PutUint64(b[0:8], uint64(f.StakingFee))
+	binary.BigEndian.PutUint64(b[8:16], uint64(f.UnbondingFee))
+	binary.BigEndian.PutUint64(b[16:24], uint64(f.WithdrawalFee))
+	return b
+}
+
+func deserializeDelegationFees(txHash, b []byte) (*DelegationFees, error) {
+	if len(b) != delegationFeesSize {
+		return nil, ErrCorruptedTransactionsDB
+	}
+
+	hash, err := chainhash.NewHash(txHash)
+	if err != nil {
+		return nil, ErrCorruptedTransactionsDB
+	}
+
+	return &DelegationFees{
+		StakingTxHash: *hash,
+		StakingFee:    btcutil.Amount(binary.BigEndian.Uint64(b[0:8])),
+		UnbondingFee:  btcutil.Amount(binary.BigEndian.Uint64(b[8:16])),
+		WithdrawalFee: btcutil.Amount(binary.BigEndian.Uint64(b[16:24])),
+	}, nil
+}
+
+// RecordPaidFee stores fee paid by transaction of given kind broadcast for the
+// delegation. Recording fee of the same kind again overwrites previous value,
+// so that rebroadcasting transaction is not counted twice.
+func (c *TrackedTransactionStore) RecordPaidFee(
+	stakingTxHash *chainhash.Hash,
+	kind WatchedTxKind,
+	fee btcutil.Amount,
+) error {
+	if fee < 0 {
+		return fmt.Errorf("invalid negative fee %d", fee)
+	}
+
+	return c.update(func(tx kvdb.RwTx) error {
+		feesBucket := tx.ReadWriteBucket(paidFeesBucketName)
+		if feesBucket == nil {
+			return ErrCorruptedTransactionsDB
+		}
+
+		fees := &DelegationFees{StakingTxHash: *stakingTxHash}
+		if v := feesBucket.Get(stakingTxHash[:]); v != nil {
+			stored, err := deserializeDelegationFees(stakingTxHash[:], v)
+			if err != nil {
+				return err
+			}
+			fees = stored
+		}
+
+		switch kind {
+		case WatchedStakingTx:
+			fees.StakingFee = fee
+		case WatchedUnbondingTx:
+			fees.UnbondingFee = fee
+		case WatchedWithdrawalTx:
+			fees.WithdrawalFee = fee
+		default:
+			return fmt.Errorf("unknown transaction kind %d", kind)
+		}
+
+		if err := feesBucket.Put(stakingTxHash.CloneBytes(), fees.serialize()); err != nil {
+			return err
+		}
+
+		return appendChange(tx, ChangeFeePaid, stakingTxHash, fmt.Sprintf("%s %d", kind, int64(fee)))
+	})
+}
+
+// GetDelegationFees returns fees paid for the delegation. Zero fees are
+// returned if nothing was paid yet.
+func (c *TrackedTransactionStore) GetDelegationFees(stakingTxHash *chainhash.Hash) (*DelegationFees, error) {
+	fees := &DelegationFees{StakingTxHash: *stakingTxHash}
+
+	err := c.db.View(func(tx kvdb.RTx) error {
+		feesBucket := tx.ReadBucket(paidFeesBucketName)
+		if feesBucket == nil {
+			return ErrCorruptedTransactionsDB
+		}
+
+		v := feesBucket.Get(stakingTxHash[:])
+		if v == nil {
+			return nil
+		}
+
+		stored, err := deserializeDelegationFees(stakingTxHash[:], v)
+		if err != nil {
+			return err
+		}
+
+		fees = stored
+		return nil
+	}, func() {
+		fees = &DelegationFees{StakingTxHash: *stakingTxHash}
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get delegation fees: %w", err)
+	}
+
+	return fees, nil
+}
+
+// ListDelegationFees returns fees paid for every delegation
+func (c *TrackedTransactionStore) ListDelegationFees() ([]DelegationFees, error) {
+	var allFees []DelegationFees
+
+	err := c.db.View(func(tx kvdb.RTx) error {
+		feesBucket := tx.ReadBucket(paidFeesBucketName)
+		if feesBucket == nil {
+			return ErrCorruptedTransactionsDB
+		}
+
+		return feesBucket.ForEach(func(k, v []byte) error {
+			fees, err := deserializeDelegationFees(k, v)
+			if err != nil {
+				return err
+			}
+
+			allFees = append(allFees, *fees)
+			return nil
+		})
+	}, func() {
+		allFees = nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list delegation fees: %w", err)
+	}
+
+	return allFees, nil
+}
@@ -0,0 +1,184 @@
+package stakerdb
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/lightningnetwork/lnd/kvdb"
+)
+
+var (
+	// mapping bigendian(uint32) params version -> json encoded archived params
+	// It holds every Babylon params version seen by staker, so past
+	// delegations can be validated without Babylon historical state
+	paramsArchiveBucketName = []byte("paramsArchive")
+)
+
+// ArchivedParams is a Babylon params version stored in params archive
+type ArchivedParams struct {
+	Version uint32 `json:"version"`
+	// BtcActivationHeight is btc height from which the params apply
+	BtcActivationHeight uint32 `json:"btc_activation_height"`
+	// Params are encoded params of the version, store does not interpret them
+	Params     json.RawMessage `json:"params"`
+	ArchivedAt time.Time       `json:"archived_at"`
+}
+
+func paramsVersionKey(version uint32) []byte {
+	key := make([]byte, 4)
+	binary.BigEndian.PutUint32(key, version)
+	return key
+}
+
+// ArchiveParams stores params version in params archive. Params versions are
+// immutable, so version which is already archived is kept. Returns true if
+// the version was stored.
+func (c *TrackedTransactionStore) ArchiveParams(params *ArchivedParams) (bool, error) {
+	if params == nil {
+		return false, fmt.Errorf("cannot archive nil params")
+	}
+
+	encoded, err := json.Marshal(params)
+	if err != nil {
+		return false, fmt.Errorf("failed to encode archived params: %w", err)
+	}
+
+	var stored bool
+	err = batch(c.db, func(tx kvdb.RwTx) error {
+		stored = false
+
+		archiveBucket := tx.ReadWriteBucket(paramsArchiveBucketName)
+		if archiveBucket == nil {
+			return ErrCorruptedTransactionsDB
+		}
+
+		key := paramsVersionKey(params.Version)
+		if archiveBucket.Get(key) != nil {
+			return nil
+		}
+
+		if err := archiveBucket.Put(key, encoded); err != nil {
+			return err
+		}
+
+		stored = true
+		return nil
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to archive params version %d: %w", params.Version, err)
+	}
+
+	return stored, nil
+}
+
+// GetArchivedParams returns archived params of the given version, or
+// ErrParamsNotArchived if the version is not archived
+func (c *TrackedTransactionStore) GetArchivedParams(version uint32) (*ArchivedParams, error) {
+	var params *ArchivedParams
+
+	err := c.db.View(func(tx kvdb.RTx) error {
+		archiveBucket := tx.ReadBucket(paramsArchiveBucketName)
+		if archiveBucket == nil {
+			return ErrCorruptedTransactionsDB
+		}
+
+		v := archiveBucket.Get(paramsVersionKey(version))
+		if v == nil {
+			return ErrParamsNotArchived
+		}
+
+		var p ArchivedParams
+		if err := json.Unmarshal(v, &p); err != nil {
+			return err
+		}
+
+		params = &p
+		return nil
+	}, func() {
+		params = nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get archived params version %d: %w", version, err)
+	}
+
+	return params, nil
+}
+
+// GetArchivedParamsByBtcHeight returns archived params which apply at the
+// given btc height, i.e. the latest version activated at or below it. Newer
+// versions missing from the archive could apply instead, so the archive must
+// hold every version from the found one up to the latest archived one,
+// otherwise ErrParamsNotArchived is returned.
+func (c *TrackedTransactionStore) GetArchivedParamsByBtcHeight(btcHeight uint32) (*ArchivedParams, error) {
+	var params *ArchivedParams
+
+	err := c.db.View(func(tx kvdb.RTx) error {
+		archiveBucket := tx.ReadBucket(paramsArchiveBucketName)
+		if archiveBucket == nil {
+			return ErrCorruptedTransactionsDB
+		}
+
+		var (
+			cursor = archiveBucket.ReadCursor()
+			next   *uint32
+		)
+		for k, v := cursor.Last(); k != nil; k, v = cursor.Prev() {
+			var p ArchivedParams
+			if err := json.Unmarshal(v, &p); err != nil {
+				return err
+			}
+
+			if next != nil && p.Version+1 != *next {
+				return ErrParamsNotArchived
+			}
+
+			if p.BtcActivationHeight <= btcHeight {
+				params = &p
+				return nil
+			}
+
+			version := p.Version
+			next = &version
+		}
+
+		return ErrParamsNotArchived
+	}, func() {
+		params = nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get archived params at btc height %d: %w", btcHeight, err)
+	}
+
+	return params, nil
+}
+
+// ListArchivedParams returns all archived params versions ordered by version
+func (c *TrackedTransactionStore) ListArchivedParams() ([]*ArchivedParams, error) {
+	var archived []*ArchivedParams
+
+	err := c.db.View(func(tx kvdb.RTx) error {
+		archiveBucket := tx.ReadBucket(paramsArchiveBucketName)
+		if archiveBucket == nil {
+			return ErrCorruptedTransactionsDB
+		}
+
+		return archiveBucket.ForEach(func(_, v []byte) error {
+			var p ArchivedParams
+			if err := json.Unmarshal(v, &p); err != nil {
+				return err
+			}
+
+			archived = append(archived, &p)
+			return nil
+		})
+	}, func() {
+		archived = nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list archived params: %w", err)
+	}
+
+	return archived, nil
+}
@@ -0,0 +1,177 @@
+package stakerdb
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/lightningnetwork/lnd/kvdb"
+)
+
+var (
+	// mapping txKey -> json encoded quarantined record
+	// It holds tracked transaction records which failed to decode or
+	// validate, moved out of the transactions bucket
+	quarantineBucketName = []byte("quarantine")
+)
+
+// QuarantinedRecord is tracked transaction record which failed to decode or
+// validate when read. It is not served anymore, but kept for inspection.
+type QuarantinedRecord struct {
+	// TxIdx is key of the record in the transactions bucket
+	TxIdx uint64 `json:"tx_idx"`
+	// StakingTxHash is hash under which the record was indexed, nil if no
+	// index entry pointed to the record
+	StakingTxHash *chainhash.Hash `json:"staking_tx_hash,omitempty"`
+	Record        []byte          `json:"record"`
+	// Checksum is checksum stored with the record, empty if there was none
+	Checksum      []byte    `json:"checksum,omitempty"`
+	Reason        string    `json:"reason"`
+	QuarantinedAt time.Time `json:"quarantined_at"`
+}
+
+// badRecord is record of transactions bucket found invalid while reading
+type badRecord struct {
+	key    []byte
+	reason string
+}
+
+func newBadRecord(key []byte, err error) badRecord {
+	return badRecord{
+		key:    append([]byte(nil), key...),
+		reason: err.Error(),
+	}
+}
+
+// quarantine moves records found invalid while reading to the quarantine
+// bucket, so that reads of other records are not failed by them. Index
+// entries pointing to the records are removed, reserved inputs and other data
+// of the delegation are kept.
+func (c *TrackedTransactionStore) quarantine(bad []badRecord) error {
+	if len(bad) == 0 {
+		return nil
+	}
+
+	err := c.update(func(tx kvdb.RwTx) error {
+		transactionsBucket := tx.ReadWriteBucket(transactionBucketName)
+		if transactionsBucket == nil {
+			return ErrCorruptedTransactionsDB
+		}
+
+		transactionIdxBucket := tx.ReadWriteBucket(transactionIndexName)
+		if transactionIdxBucket == nil {
+			return ErrCorruptedTransactionsDB
+		}
+
+		checksumsBucket := tx.ReadWriteBucket(txChecksumsBucketName)
+		if checksumsBucket == nil {
+			return ErrCorruptedTransactionsDB
+		}
+
+		quarantineBucket := tx.ReadWriteBucket(quarantineBucketName)
+		if quarantineBucket == nil {
+			return ErrCorruptedTransactionsDB
+		}
+
+		for _, b := range bad {
+			record := transactionsBucket.Get(b.key)
+			if record == nil {
+				// quarantined by concurrent read
+				continue
+			}
+
+			q := QuarantinedRecord{
+				TxIdx:         binary.BigEndian.Uint64(b.key),
+				Record:        append([]byte(nil), record...),
+				Checksum:      append([]byte(nil), checksumsBucket.Get(b.key)...),
+				Reason:        b.reason,
+				QuarantinedAt: time.Now(),
+			}
+
+			// index can't be modified while iterating over it
+			var indexed [][]byte
+			err := transactionIdxBucket.ForEach(func(txHash, txKey []byte) error {
+				if !bytes.Equal(txHash, numTxKey) && bytes.Equal(txKey, b.key) {
+					indexed = append(indexed, append([]byte(nil), txHash...))
+				}
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+
+			for _, txHash := range indexed {
+				if err := transactionIdxBucket.Delete(txHash); err != nil {
+					return err
+				}
+
+				hash, err := chainhash.NewHash(txHash)
+				if err != nil {
+					return ErrCorruptedTransactionsDB
+				}
+				q.StakingTxHash = hash
+			}
+
+			v, err := json.Marshal(&q)
+			if err != nil {
+				return fmt.Errorf("failed to marshal quarantined record: %w", err)
+			}
+
+			if err := quarantineBucket.Put(b.key, v); err != nil {
+				return err
+			}
+
+			if err := transactionsBucket.Delete(b.key); err != nil {
+				return err
+			}
+
+			if err := checksumsBucket.Delete(b.key); err != nil {
+				return err
+			}
+
+			if err := appendChange(tx, ChangeTransactionQuarantined, q.StakingTxHash, b.reason); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to quarantine invalid records: %w", err)
+	}
+
+	return nil
+}
+
+// QuarantinedRecords returns quarantined tracked transaction records ordered
+// by their key
+func (c *TrackedTransactionStore) QuarantinedRecords() ([]QuarantinedRecord, error) {
+	var records []QuarantinedRecord
+
+	err := c.db.View(func(tx kvdb.RTx) error {
+		quarantineBucket := tx.ReadBucket(quarantineBucketName)
+		if quarantineBucket == nil {
+			return ErrCorruptedTransactionsDB
+		}
+
+		return quarantineBucket.ForEach(func(_, v []byte) error {
+			var q QuarantinedRecord
+			if err := json.Unmarshal(v, &q); err != nil {
+				return ErrCorruptedTransactionsDB
+			}
+
+			records = append(records, q)
+			return nil
+		})
+	}, func() {
+		records = nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list quarantined records: %w", err)
+	}
+
+	return records, nil
+}
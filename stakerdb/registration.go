@@ -0,0 +1,40 @@
+package stakerdb
+
+import (
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/lightningnetwork/lnd/kvdb"
+)
+
+// ResetTransactionRegistration resets tracked delegation after it was
+// registered on Babylon again. Failure, failure note and covenant quorum
+// timing of the previous registration are removed in a single db transaction,
+// so delegation is never seen as both failed and registered.
+func (c *TrackedTransactionStore) ResetTransactionRegistration(txHash *chainhash.Hash) error {
+	return c.update(func(tx kvdb.RwTx) error {
+		transactionIdxBucket := tx.ReadBucket(transactionIndexName)
+		if transactionIdxBucket == nil {
+			return ErrCorruptedTransactionsDB
+		}
+
+		if transactionIdxBucket.Get(txHash[:]) == nil {
+			return ErrTransactionNotFound
+		}
+
+		for _, bucketName := range [][]byte{
+			failedTransactionsBucketName,
+			failureNotesBucketName,
+			covenantQuorumBucketName,
+		} {
+			bucket := tx.ReadWriteBucket(bucketName)
+			if bucket == nil {
+				return ErrCorruptedTransactionsDB
+			}
+
+			if err := bucket.Delete(txHash[:]); err != nil {
+				return err
+			}
+		}
+
+		return appendChange(tx, ChangeRegistrationRetried, txHash, "")
+	})
+}
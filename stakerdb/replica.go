@@ -0,0 +1,156 @@
+package stakerdb
+
+import (
+	"encoding/binary"
+	"fmt"
+	"strconv"
+
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/lightningnetwork/lnd/kvdb"
+)
+
+var (
+	// holds replication state of read only replica
+	replicaBucketName = []byte("replica")
+
+	// key for sequence number of the last change of the primary applied by
+	// the replica
+	replicaCursorKey = []byte("cursor")
+)
+
+// ReplicaCursor returns sequence number of the last change of the primary
+// applied by the replica, zero if none was applied yet
+func (c *TrackedTransactionStore) ReplicaCursor() (uint64, error) {
+	var cursor uint64
+
+	err := c.db.View(func(tx kvdb.RTx) error {
+		bucket := tx.ReadBucket(replicaBucketName)
+		if bucket == nil {
+			return ErrCorruptedTransactionsDB
+		}
+
+		v := bucket.Get(replicaCursorKey)
+		if v == nil {
+			return nil
+		}
+
+		if len(v) != 8 {
+			return ErrCorruptedTransactionsDB
+		}
+
+		cursor = binary.BigEndian.Uint64(v)
+		return nil
+	}, func() {
+		cursor = 0
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to get replica cursor: %w", err)
+	}
+
+	return cursor, nil
+}
+
+// SetReplicaCursor stores sequence number of the last change of the primary
+// applied by the replica. Changes are applied idempotently, so the cursor is
+// stored separately from the replicated data.
+func (c *TrackedTransactionStore) SetReplicaCursor(seq uint64) error {
+	return batch(c.db, func(tx kvdb.RwTx) error {
+		bucket := tx.ReadWriteBucket(replicaBucketName)
+		if bucket == nil {
+			return ErrCorruptedTransactionsDB
+		}
+
+		return bucket.Put(replicaCursorKey, uint64KeyToBytes(seq))
+	})
+}
+
+// ReplicateTransaction stores delegation as exported by the primary, replacing
+// everything the replica stored about it before. Failure is nil if delegation
+// did not fail on the primary. primarySeq is the change of the primary which
+// triggered replication.
+func (c *TrackedTransactionStore) ReplicateTransaction(
+	imported *ImportedTransaction,
+	failure *TransactionFailure,
+	primarySeq uint64,
+) error {
+	if imported == nil || imported.StakingTx == nil {
+		return fmt.Errorf("cannot replicate nil transaction")
+	}
+
+	if imported.Tenant != "" {
+		if err := ValidateTenant(imported.Tenant); err != nil {
+			return err
+		}
+	}
+
+	txHash := imported.StakingTx.TxHash()
+
+	return c.update(func(tx kvdb.RwTx) error {
+		if err := deleteReplicatedTransaction(tx, &txHash); err != nil {
+			return err
+		}
+
+		if err := saveImportedTransaction(tx, imported, c.integrityKey); err != nil {
+			return err
+		}
+
+		if failure != nil {
+			if err := putImported(tx, failedTransactionsBucketName, txHash[:], serializeTransactionFailure(failure)); err != nil {
+				return err
+			}
+		}
+
+		return appendChange(tx, ChangeTransactionReplicated, &txHash, strconv.FormatUint(primarySeq, 10))
+	})
+}
+
+// RemoveReplicatedTransaction removes delegation which is no longer tracked by
+// the primary. Removing delegation unknown to the replica is a no-op.
+func (c *TrackedTransactionStore) RemoveReplicatedTransaction(txHash *chainhash.Hash) error {
+	return c.update(func(tx kvdb.RwTx) error {
+		transactionIdxBucket := tx.ReadBucket(transactionIndexName)
+		if transactionIdxBucket == nil {
+			return ErrCorruptedTransactionsDB
+		}
+
+		if transactionIdxBucket.Get(txHash[:]) == nil {
+			return nil
+		}
+
+		if err := deleteReplicatedTransaction(tx, txHash); err != nil {
+			return err
+		}
+
+		return appendChange(tx, ChangeTransactionDeleted, txHash, "")
+	})
+}
+
+// deleteReplicatedTransaction deletes transaction and all its data if it is
+// tracked
+func deleteReplicatedTransaction(tx kvdb.RwTx, txHash *chainhash.Hash) error {
+	transactionIdxBucket := tx.ReadWriteBucket(transactionIndexName)
+	if transactionIdxBucket == nil {
+		return ErrCorruptedTransactionsDB
+	}
+
+	if transactionIdxBucket.Get(txHash[:]) == nil {
+		return nil
+	}
+
+	transactionsBucket := tx.ReadWriteBucket(transactionBucketName)
+	if transactionsBucket == nil {
+		return ErrCorruptedTransactionsDB
+	}
+
+	if err := deleteTrackedTransaction(tx, transactionIdxBucket, transactionsBucket, txHash[:]); err != nil {
+		return err
+	}
+
+	// paid fees are kept for deleted transactions, but replaced on replication
+	feesBucket := tx.ReadWriteBucket(paidFeesBucketName)
+	if feesBucket == nil {
+		return ErrCorruptedTransactionsDB
+	}
+
+	return feesBucket.Delete(txHash[:])
+}
@@ -0,0 +1,264 @@
+package stakerdb
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// SnapshotFormatVersion is version of snapshot layout written by
+// ExportSnapshot
+const SnapshotFormatVersion = 1
+
+// Files of the snapshot directory
+const (
+	SnapshotManifestFile    = "manifest.json"
+	SnapshotDBFile          = "staker.db"
+	SnapshotParamsFile      = "params_archive.json"
+	SnapshotDescriptorsFile = "wallet_descriptors.json"
+)
+
+const (
+	snapshotFilePermissions  = 0600
+	snapshotDirPermissions   = 0700
+	snapshotManifestMaxBytes = 1 << 20
+)
+
+// SnapshotFile is a file of the snapshot with its integrity hash
+type SnapshotFile struct {
+	Name   string `json:"name"`
+	Size   int64  `json:"size"`
+	Sha256 string `json:"sha256"`
+}
+
+// SnapshotManifest describes snapshot of staker database. It is written last,
+// so snapshot without manifest is incomplete.
+type SnapshotManifest struct {
+	FormatVersion uint32    `json:"format_version"`
+	Network       string    `json:"network"`
+	CreatedAt     time.Time `json:"created_at"`
+	// TrackedTransactions and LastChangeSeq are state of the database at the
+	// time of the snapshot, restored database must match them
+	TrackedTransactions uint64         `json:"tracked_transactions"`
+	LastChangeSeq       uint64         `json:"last_change_seq"`
+	Files               []SnapshotFile `json:"files"`
+}
+
+// writeSnapshotFile writes file of the snapshot and returns its integrity
+// hash
+func writeSnapshotFile(dir, name string, write func(w io.Writer) error) (*SnapshotFile, error) {
+	f, err := os.OpenFile(filepath.Join(dir, name), os.O_CREATE|os.O_EXCL|os.O_WRONLY, snapshotFilePermissions)
+	if err != nil {
+		return nil, err
+	}
+
+	hasher := sha256.New()
+	counter := &countingWriter{}
+	if err := write(io.MultiWriter(f, hasher, counter)); err != nil {
+		f.Close()
+		return nil, fmt.Errorf("failed to write snapshot file %s: %w", name, err)
+	}
+
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	if err := f.Close(); err != nil {
+		return nil, err
+	}
+
+	return &SnapshotFile{
+		Name:   name,
+		Size:   counter.n,
+		Sha256: hex.EncodeToString(hasher.Sum(nil)),
+	}, nil
+}
+
+type countingWriter struct {
+	n int64
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	w.n += int64(len(p))
+	return len(p), nil
+}
+
+// ExportSnapshot writes snapshot of the database into a new directory. The
+// snapshot holds consistent copy of the database, params archive and extra
+// files, e.g. wallet descriptors, together with manifest of their integrity
+// hashes.
+func (c *TrackedTransactionStore) ExportSnapshot(dir string, extra map[string][]byte) (*SnapshotManifest, error) {
+	network, err := c.Network()
+	if err != nil {
+		return nil, err
+	}
+	if network == nil {
+		return nil, fmt.Errorf("database is not bound to any network")
+	}
+
+	health, err := c.CheckIndexHealth()
+	if err != nil {
+		return nil, err
+	}
+	if !health.Healthy() {
+		return nil, fmt.Errorf("database index is not healthy, check it with db stats")
+	}
+
+	changelog, err := c.ChangelogStats()
+	if err != nil {
+		return nil, err
+	}
+
+	params, err := c.ListArchivedParams()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := os.Mkdir(dir, snapshotDirPermissions); err != nil {
+		return nil, fmt.Errorf("failed to create snapshot directory: %w", err)
+	}
+
+	manifest := &SnapshotManifest{
+		FormatVersion:       SnapshotFormatVersion,
+		Network:             network.Name,
+		CreatedAt:           time.Now().UTC(),
+		TrackedTransactions: health.TrackedTransactions,
+		LastChangeSeq:       changelog.LastSeq,
+	}
+
+	files := []struct {
+		name  string
+		write func(w io.Writer) error
+	}{
+		{SnapshotDBFile, c.db.Copy},
+		{SnapshotParamsFile, func(w io.Writer) error {
+			return json.NewEncoder(w).Encode(params)
+		}},
+	}
+	for name, content := range extra {
+		content := content
+		files = append(files, struct {
+			name  string
+			write func(w io.Writer) error
+		}{name, func(w io.Writer) error {
+			_, err := w.Write(content)
+			return err
+		}})
+	}
+
+	for _, file := range files {
+		written, err := writeSnapshotFile(dir, file.name, file.write)
+		if err != nil {
+			return nil, err
+		}
+		manifest.Files = append(manifest.Files, *written)
+	}
+
+	if _, err := writeSnapshotFile(dir, SnapshotManifestFile, func(w io.Writer) error {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(manifest)
+	}); err != nil {
+		return nil, err
+	}
+
+	return manifest, nil
+}
+
+// ReadSnapshotManifest reads manifest of the snapshot directory and returns
+// it together with its sha256 hash
+func ReadSnapshotManifest(dir string) (*SnapshotManifest, string, error) {
+	f, err := os.Open(filepath.Join(dir, SnapshotManifestFile))
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to open snapshot manifest: %w", err)
+	}
+	defer f.Close()
+
+	raw, err := io.ReadAll(io.LimitReader(f, snapshotManifestMaxBytes))
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read snapshot manifest: %w", err)
+	}
+
+	var manifest SnapshotManifest
+	if err := json.Unmarshal(raw, &manifest); err != nil {
+		return nil, "", fmt.Errorf("%w: invalid manifest: %w", ErrSnapshotCorrupted, err)
+	}
+
+	hash := sha256.Sum256(raw)
+	return &manifest, hex.EncodeToString(hash[:]), nil
+}
+
+// VerifySnapshot checks that every file listed in snapshot manifest has the
+// recorded size and sha256 hash. Database file must be listed.
+func VerifySnapshot(dir string, manifest *SnapshotManifest) error {
+	if manifest.FormatVersion != SnapshotFormatVersion {
+		return fmt.Errorf("unsupported snapshot format version %d", manifest.FormatVersion)
+	}
+
+	hasDB := false
+	for _, file := range manifest.Files {
+		// files must be inside of the snapshot directory
+		if file.Name != filepath.Base(file.Name) {
+			return fmt.Errorf("%w: invalid file name %s", ErrSnapshotCorrupted, file.Name)
+		}
+
+		if file.Name == SnapshotDBFile {
+			hasDB = true
+		}
+
+		f, err := os.Open(filepath.Join(dir, file.Name))
+		if err != nil {
+			return fmt.Errorf("%w: %w", ErrSnapshotCorrupted, err)
+		}
+
+		hasher := sha256.New()
+		size, err := io.Copy(hasher, f)
+		f.Close()
+		if err != nil {
+			return fmt.Errorf("failed to read snapshot file %s: %w", file.Name, err)
+		}
+
+		if size != file.Size || hex.EncodeToString(hasher.Sum(nil)) != file.Sha256 {
+			return fmt.Errorf("%w: file %s does not match its hash", ErrSnapshotCorrupted, file.Name)
+		}
+	}
+
+	if !hasDB {
+		return fmt.Errorf("%w: database file is missing", ErrSnapshotCorrupted)
+	}
+
+	return nil
+}
+
+// RestoreSnapshotParams archives params of the snapshot params archive which
+// are missing from the database, returns number of archived versions
+func (c *TrackedTransactionStore) RestoreSnapshotParams(dir string) (int, error) {
+	raw, err := os.ReadFile(filepath.Join(dir, SnapshotParamsFile))
+	if err != nil {
+		return 0, fmt.Errorf("failed to read snapshot params archive: %w", err)
+	}
+
+	var params []*ArchivedParams
+	if err := json.Unmarshal(raw, &params); err != nil {
+		return 0, fmt.Errorf("%w: invalid params archive: %w", ErrSnapshotCorrupted, err)
+	}
+
+	restored := 0
+	for _, p := range params {
+		stored, err := c.ArchiveParams(p)
+		if err != nil {
+			return restored, err
+		}
+		if stored {
+			restored++
+		}
+	}
+
+	return restored, nil
+}
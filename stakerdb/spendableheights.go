@@ -0,0 +1,171 @@
+package stakerdb
+
+import (
+	"encoding/binary"
+	"fmt"
+	"strconv"
+
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/lightningnetwork/lnd/kvdb"
+)
+
+var (
+	// mapping txHash -> bigendian(uint32) btc height || reached(1)
+	// It holds btc height at which output of confirmed unbonding transaction
+	// of the delegation becomes spendable, and whether that height was reached
+	spendableHeightsBucketName = []byte("spendableHeights")
+)
+
+const spendableHeightSize = 4 + 1
+
+// SetTransactionSpendableHeight stores btc height at which funds of unbonded
+// delegation become spendable. Returns false if the same height is already
+// stored. Height differs only if unbonding transaction was reorged into other
+// block, it is then overwritten and reported again once reached.
+func (c *TrackedTransactionStore) SetTransactionSpendableHeight(txHash *chainhash.Hash, height uint32) (bool, error) {
+	var updated bool
+
+	err := c.update(func(tx kvdb.RwTx) error {
+		updated = false
+
+		transactionIdxBucket := tx.ReadBucket(transactionIndexName)
+		if transactionIdxBucket == nil {
+			return ErrCorruptedTransactionsDB
+		}
+
+		if transactionIdxBucket.Get(txHash[:]) == nil {
+			return ErrTransactionNotFound
+		}
+
+		heightsBucket := tx.ReadWriteBucket(spendableHeightsBucketName)
+		if heightsBucket == nil {
+			return ErrCorruptedTransactionsDB
+		}
+
+		if v := heightsBucket.Get(txHash[:]); len(v) == spendableHeightSize && binary.BigEndian.Uint32(v[:4]) == height {
+			return nil
+		}
+
+		var v [spendableHeightSize]byte
+		binary.BigEndian.PutUint32(v[:4], height)
+
+		if err := heightsBucket.Put(txHash.CloneBytes(), v[:]); err != nil {
+			return err
+		}
+
+		updated = true
+		return appendChange(tx, ChangeSpendableHeightSet, txHash, strconv.FormatUint(uint64(height), 10))
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return updated, nil
+}
+
+// GetTransactionSpendableHeight returns btc height at which funds of unbonded
+// delegation become spendable. Returns false if unbonding transaction of the
+// delegation was not confirmed yet.
+func (c *TrackedTransactionStore) GetTransactionSpendableHeight(txHash *chainhash.Hash) (uint32, bool, error) {
+	var (
+		height uint32
+		found  bool
+	)
+
+	err := c.db.View(func(tx kvdb.RTx) error {
+		heightsBucket := tx.ReadBucket(spendableHeightsBucketName)
+		if heightsBucket == nil {
+			return ErrCorruptedTransactionsDB
+		}
+
+		v := heightsBucket.Get(txHash[:])
+		if v == nil {
+			return nil
+		}
+
+		if len(v) != spendableHeightSize {
+			return ErrCorruptedTransactionsDB
+		}
+
+		height = binary.BigEndian.Uint32(v[:4])
+		found = true
+		return nil
+	}, func() {
+		height = 0
+		found = false
+	})
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to get transaction spendable height: %w", err)
+	}
+
+	return height, found, nil
+}
+
+// MarkSpendableHeightsReached marks spendable heights which are lower or equal
+// to the given btc height as reached and returns staking tx hashes of
+// delegations which became spendable. Every delegation is returned only once.
+func (c *TrackedTransactionStore) MarkSpendableHeightsReached(height uint32) ([]chainhash.Hash, error) {
+	var reached []chainhash.Hash
+
+	err := c.update(func(tx kvdb.RwTx) error {
+		reached = nil
+
+		heightsBucket := tx.ReadWriteBucket(spendableHeightsBucketName)
+		if heightsBucket == nil {
+			return ErrCorruptedTransactionsDB
+		}
+
+		type pendingHeight struct {
+			hash   chainhash.Hash
+			height uint32
+		}
+
+		// bucket can't be modified while iterating over it
+		var pending []pendingHeight
+		err := heightsBucket.ForEach(func(k, v []byte) error {
+			if len(v) != spendableHeightSize {
+				return ErrCorruptedTransactionsDB
+			}
+
+			spendableHeight := binary.BigEndian.Uint32(v[:4])
+			if v[4] != 0 || spendableHeight > height {
+				return nil
+			}
+
+			hash, err := chainhash.NewHash(k)
+			if err != nil {
+				return err
+			}
+
+			pending = append(pending, pendingHeight{hash: *hash, height: spendableHeight})
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, p := range pending {
+			var v [spendableHeightSize]byte
+			binary.BigEndian.PutUint32(v[:4], p.height)
+			v[4] = 1
+
+			if err := heightsBucket.Put(p.hash.CloneBytes(), v[:]); err != nil {
+				return err
+			}
+
+			hash := p.hash
+			if err := appendChange(tx, ChangeStakeSpendable, &hash, strconv.FormatUint(uint64(p.height), 10)); err != nil {
+				return err
+			}
+
+			reached = append(reached, p.hash)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to mark spendable heights: %w", err)
+	}
+
+	return reached, nil
+}
@@ -0,0 +1,125 @@
+package stakerdb
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/lightningnetwork/lnd/kvdb"
+)
+
+var (
+	// mapping spend tx hash -> staking tx hash(32) || kind(1)
+	// It holds unbonding and withdrawal transactions of tracked delegations,
+	// so that transaction seen on btc can be mapped back to its delegation
+	spendTxIndexBucketName = []byte("spendTxIndex")
+)
+
+// SpendTxDelegation is tracked delegation found by hash of transaction
+// spending it
+type SpendTxDelegation struct {
+	StakingTxHash chainhash.Hash
+	Kind          WatchedTxKind
+}
+
+func putSpendTxIndex(tx kvdb.RwTx, txHash, stakingTxHash *chainhash.Hash, kind WatchedTxKind) error {
+	indexBucket := tx.ReadWriteBucket(spendTxIndexBucketName)
+	if indexBucket == nil {
+		return ErrCorruptedTransactionsDB
+	}
+
+	v := make([]byte, chainhash.HashSize+1)
+	copy(v, stakingTxHash[:])
+	v[chainhash.HashSize] = byte(kind)
+
+	return indexBucket.Put(txHash[:], v)
+}
+
+// deleteSpendTxIndex deletes index entries of spend transactions of deleted
+// delegation
+func deleteSpendTxIndex(tx kvdb.RwTx, stakingTxHash []byte) error {
+	indexBucket := tx.ReadWriteBucket(spendTxIndexBucketName)
+	if indexBucket == nil {
+		return ErrCorruptedTransactionsDB
+	}
+
+	// bucket can't be modified while iterating over it
+	var indexed [][]byte
+	err := indexBucket.ForEach(func(k, v []byte) error {
+		if len(v) > chainhash.HashSize && bytes.Equal(v[:chainhash.HashSize], stakingTxHash) {
+			indexed = append(indexed, bytes.Clone(k))
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, k := range indexed {
+		if err := indexBucket.Delete(k); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// IndexSpendTransaction records that transaction with given hash is unbonding
+// or withdrawal transaction of tracked delegation. Indexing the same
+// transaction again overwrites the entry.
+func (c *TrackedTransactionStore) IndexSpendTransaction(
+	stakingTxHash *chainhash.Hash,
+	kind WatchedTxKind,
+	txHash *chainhash.Hash,
+) error {
+	if kind != WatchedUnbondingTx && kind != WatchedWithdrawalTx {
+		return fmt.Errorf("cannot index %s transaction as spend transaction", kind)
+	}
+
+	return batch(c.db, func(tx kvdb.RwTx) error {
+		transactionIdxBucket := tx.ReadBucket(transactionIndexName)
+		if transactionIdxBucket == nil {
+			return ErrCorruptedTransactionsDB
+		}
+
+		if transactionIdxBucket.Get(stakingTxHash[:]) == nil {
+			return ErrTransactionNotFound
+		}
+
+		return putSpendTxIndex(tx, txHash, stakingTxHash, kind)
+	})
+}
+
+// FindDelegationBySpendTx returns tracked delegation spent by unbonding or
+// withdrawal transaction with given hash. Returns ErrTransactionNotFound if
+// transaction is not indexed.
+func (c *TrackedTransactionStore) FindDelegationBySpendTx(txHash *chainhash.Hash) (*SpendTxDelegation, error) {
+	var found *SpendTxDelegation
+
+	err := c.db.View(func(tx kvdb.RTx) error {
+		indexBucket := tx.ReadBucket(spendTxIndexBucketName)
+		if indexBucket == nil {
+			return ErrCorruptedTransactionsDB
+		}
+
+		v := indexBucket.Get(txHash[:])
+		if v == nil {
+			return ErrTransactionNotFound
+		}
+
+		if len(v) != chainhash.HashSize+1 {
+			return ErrCorruptedTransactionsDB
+		}
+
+		found = &SpendTxDelegation{Kind: WatchedTxKind(v[chainhash.HashSize])}
+		copy(found.StakingTxHash[:], v[:chainhash.HashSize])
+		return nil
+	}, func() {
+		found = nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return found, nil
+}
@@ -0,0 +1,190 @@
+package stakerdb
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/lightningnetwork/lnd/kvdb"
+)
+
+var (
+	// mapping uint64 -> json encoded queued stake
+	// It holds stake requests received while Babylon did not accept new
+	// delegations, in the order in which they were received
+	stakeQueueBucketName = []byte("stakeQueue")
+
+	// key for next queued stake id
+	nextQueuedStakeKey = []byte("nqs")
+)
+
+// QueuedStake is a stake request waiting for Babylon to accept new delegations
+type QueuedStake struct {
+	ID            uint64         `json:"-"`
+	StakerAddress string         `json:"staker_address"`
+	Amount        btcutil.Amount `json:"amount"`
+	// hex encoded schnorr public keys
+	FinalityProviders []string `json:"finality_providers"`
+	StakingTime       uint16   `json:"staking_time"`
+	// cap of estimated fee rate in sat/vbyte, 0 means no cap
+	MaxFeeRate uint64            `json:"max_fee_rate,omitempty"`
+	Tenant     string            `json:"tenant,omitempty"`
+	Labels     map[string]string `json:"labels,omitempty"`
+	// reference committed to in staking transaction, empty means none
+	Reference string `json:"reference,omitempty"`
+	// explicit fee rate in sat/vbyte or confirmation target, at most one is set
+	FeeRate    uint64    `json:"fee_rate,omitempty"`
+	TargetConf uint32    `json:"target_conf,omitempty"`
+	QueuedAt   time.Time `json:"queued_at"`
+}
+
+// QueueStake persists stake request and returns its id. At most maxQueued
+// requests can be queued at once.
+func (c *TrackedTransactionStore) QueueStake(stake *QueuedStake, maxQueued uint32) (uint64, error) {
+	if stake == nil {
+		return 0, fmt.Errorf("cannot queue nil stake")
+	}
+
+	encoded, err := json.Marshal(stake)
+	if err != nil {
+		return 0, fmt.Errorf("failed to encode queued stake: %w", err)
+	}
+
+	var id uint64
+	err = c.update(func(tx kvdb.RwTx) error {
+		queueBucket := tx.ReadWriteBucket(stakeQueueBucketName)
+		if queueBucket == nil {
+			return ErrCorruptedTransactionsDB
+		}
+
+		var queued uint32
+		if err := queueBucket.ForEach(func(k, _ []byte) error {
+			if len(k) == 8 {
+				queued++
+			}
+			return nil
+		}); err != nil {
+			return err
+		}
+
+		if queued >= maxQueued {
+			return fmt.Errorf("%w: %d stake requests are already queued", ErrStakeQueueFull, queued)
+		}
+
+		var nextID uint64
+		if keyBytes := queueBucket.Get(nextQueuedStakeKey); keyBytes != nil {
+			nextID = binary.BigEndian.Uint64(keyBytes)
+		}
+
+		if err := queueBucket.Put(uint64KeyToBytes(nextID), encoded); err != nil {
+			return fmt.Errorf("failed to save queued stake: %w", err)
+		}
+
+		if err := queueBucket.Put(nextQueuedStakeKey, uint64KeyToBytes(nextID+1)); err != nil {
+			return err
+		}
+
+		id = nextID
+		return appendChange(tx, ChangeStakeQueued, nil, strconv.FormatUint(nextID, 10))
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return id, nil
+}
+
+// ListQueuedStakes returns queued stake requests in the order in which they
+// were queued
+func (c *TrackedTransactionStore) ListQueuedStakes() ([]QueuedStake, error) {
+	var stakes []QueuedStake
+
+	err := c.db.View(func(tx kvdb.RTx) error {
+		queueBucket := tx.ReadBucket(stakeQueueBucketName)
+		if queueBucket == nil {
+			return ErrCorruptedTransactionsDB
+		}
+
+		return queueBucket.ForEach(func(k, v []byte) error {
+			// skip the counter key
+			if len(k) != 8 {
+				return nil
+			}
+
+			var stake QueuedStake
+			if err := json.Unmarshal(v, &stake); err != nil {
+				return err
+			}
+			stake.ID = binary.BigEndian.Uint64(k)
+
+			stakes = append(stakes, stake)
+			return nil
+		})
+	}, func() {
+		stakes = nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list queued stakes: %w", err)
+	}
+
+	return stakes, nil
+}
+
+// MarkQueuedStakeSubmitted removes queued stake request which was submitted
+// as delegation with given staking transaction
+func (c *TrackedTransactionStore) MarkQueuedStakeSubmitted(id uint64, stakingTxHash *chainhash.Hash) error {
+	return c.removeQueuedStake(id, ChangeQueuedStakeSubmitted, stakingTxHash, strconv.FormatUint(id, 10))
+}
+
+// MarkQueuedStakeFailed removes queued stake request which could not be
+// submitted
+func (c *TrackedTransactionStore) MarkQueuedStakeFailed(id uint64, reason string) error {
+	return c.removeQueuedStake(id, ChangeQueuedStakeFailed, nil, fmt.Sprintf("%d: %s", id, reason))
+}
+
+// CancelQueuedStake removes queued stake request before it is submitted
+func (c *TrackedTransactionStore) CancelQueuedStake(id uint64) error {
+	return c.removeQueuedStake(id, ChangeQueuedStakeCancelled, nil, strconv.FormatUint(id, 10))
+}
+
+func (c *TrackedTransactionStore) removeQueuedStake(
+	id uint64,
+	kind ChangeKind,
+	stakingTxHash *chainhash.Hash,
+	detail string,
+) error {
+	return c.update(func(tx kvdb.RwTx) error {
+		queueBucket := tx.ReadWriteBucket(stakeQueueBucketName)
+		if queueBucket == nil {
+			return ErrCorruptedTransactionsDB
+		}
+
+		key := uint64KeyToBytes(id)
+		if queueBucket.Get(key) == nil {
+			return ErrQueuedStakeNotFound
+		}
+
+		if err := queueBucket.Delete(key); err != nil {
+			return err
+		}
+
+		return appendChange(tx, kind, stakingTxHash, detail)
+	})
+}
+
+// RecordStakingGateChange records that Babylon started or stopped accepting
+// new delegations, so that changelog subscribers are notified
+func (c *TrackedTransactionStore) RecordStakingGateChange(open bool, reason string) error {
+	kind := ChangeStakingGateClosed
+	if open {
+		kind = ChangeStakingGateOpened
+	}
+
+	return c.update(func(tx kvdb.RwTx) error {
+		return appendChange(tx, kind, nil, reason)
+	})
+}
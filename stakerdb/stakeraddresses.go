@@ -0,0 +1,80 @@
+package stakerdb
+
+import (
+	"encoding/binary"
+	"fmt"
+	"time"
+
+	"github.com/lightningnetwork/lnd/kvdb"
+)
+
+var (
+	// mapping encoded address -> timestamp(8) || address type
+	// It holds staker addresses derived from the wallet by staker
+	stakerAddressesBucketName = []byte("stakerAddresses")
+)
+
+// StakerAddress is a wallet address registered for staking
+type StakerAddress struct {
+	Address   string
+	Type      string
+	Timestamp time.Time
+}
+
+// AddStakerAddress registers staker address. Registering already registered
+// address is a no-op.
+func (c *TrackedTransactionStore) AddStakerAddress(address string, addressType string) error {
+	return c.update(func(tx kvdb.RwTx) error {
+		addressesBucket := tx.ReadWriteBucket(stakerAddressesBucketName)
+		if addressesBucket == nil {
+			return ErrCorruptedTransactionsDB
+		}
+
+		key := []byte(address)
+		if addressesBucket.Get(key) != nil {
+			return nil
+		}
+
+		v := make([]byte, 8+len(addressType))
+		binary.BigEndian.PutUint64(v[:8], uint64(time.Now().Unix()))
+		copy(v[8:], addressType)
+
+		if err := addressesBucket.Put(key, v); err != nil {
+			return err
+		}
+
+		return appendChange(tx, ChangeStakerAddressAdded, nil, address)
+	})
+}
+
+// ListStakerAddresses returns all registered staker addresses
+func (c *TrackedTransactionStore) ListStakerAddresses() ([]StakerAddress, error) {
+	var addresses []StakerAddress
+
+	err := c.db.View(func(tx kvdb.RTx) error {
+		addressesBucket := tx.ReadBucket(stakerAddressesBucketName)
+		if addressesBucket == nil {
+			return ErrCorruptedTransactionsDB
+		}
+
+		return addressesBucket.ForEach(func(k, v []byte) error {
+			if len(v) < 8 {
+				return ErrCorruptedTransactionsDB
+			}
+
+			addresses = append(addresses, StakerAddress{
+				Address:   string(k),
+				Type:      string(v[8:]),
+				Timestamp: time.Unix(int64(binary.BigEndian.Uint64(v[:8])), 0),
+			})
+			return nil
+		})
+	}, func() {
+		addresses = nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list staker addresses: %w", err)
+	}
+
+	return addresses, nil
+}
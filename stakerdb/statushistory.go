@@ -0,0 +1,130 @@
+package stakerdb
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/lightningnetwork/lnd/kvdb"
+)
+
+var (
+	// mapping txHash -> json encoded list of status snapshots
+	// It holds history of Babylon statuses of the delegation, one entry per
+	// observed status
+	statusHistoryBucketName = []byte("statusHistory")
+)
+
+// StatusSnapshot is a Babylon status of the delegation together with the
+// period in which staker observed it
+type StatusSnapshot struct {
+	State string `json:"state"`
+	// FirstSeenAt is the time of the first observation of the state
+	FirstSeenAt time.Time `json:"first_seen_at"`
+	// LastSeenAt is the time of the last persisted observation of the state
+	LastSeenAt time.Time `json:"last_seen_at"`
+	// PreviousSeenAt is the time of the last persisted observation of the
+	// previous state, so the transition happened between PreviousSeenAt and
+	// FirstSeenAt. Zero for the first state.
+	PreviousSeenAt time.Time `json:"previous_seen_at,omitempty"`
+	// BtcHeight is btc best block height at the first observation
+	BtcHeight uint32 `json:"btc_height"`
+	// StartHeight and EndHeight are btc heights of the staking period as
+	// reported by Babylon, zero if delegation is not active yet
+	StartHeight uint32 `json:"start_height,omitempty"`
+	EndHeight   uint32 `json:"end_height,omitempty"`
+}
+
+// RecordStatusSnapshot records observed status of tracked delegation. New
+// history entry is created if the state differs from the last recorded one,
+// otherwise last seen time and heights of the last entry are updated. Returns
+// true if new entry was created.
+func (c *TrackedTransactionStore) RecordStatusSnapshot(txHash *chainhash.Hash, snapshot *StatusSnapshot) (bool, error) {
+	if snapshot == nil {
+		return false, fmt.Errorf("cannot save nil status snapshot")
+	}
+
+	var transition bool
+	err := c.update(func(tx kvdb.RwTx) error {
+		transactionIdxBucket := tx.ReadBucket(transactionIndexName)
+		if transactionIdxBucket == nil {
+			return ErrCorruptedTransactionsDB
+		}
+
+		if transactionIdxBucket.Get(txHash[:]) == nil {
+			return ErrTransactionNotFound
+		}
+
+		historyBucket := tx.ReadWriteBucket(statusHistoryBucketName)
+		if historyBucket == nil {
+			return ErrCorruptedTransactionsDB
+		}
+
+		var history []StatusSnapshot
+		if v := historyBucket.Get(txHash[:]); v != nil {
+			if err := json.Unmarshal(v, &history); err != nil {
+				return err
+			}
+		}
+
+		transition = len(history) == 0 || history[len(history)-1].State != snapshot.State
+
+		if transition {
+			entry := *snapshot
+			entry.LastSeenAt = snapshot.FirstSeenAt
+			if len(history) > 0 {
+				entry.PreviousSeenAt = history[len(history)-1].LastSeenAt
+			}
+			history = append(history, entry)
+		} else {
+			last := &history[len(history)-1]
+			last.LastSeenAt = snapshot.FirstSeenAt
+			last.StartHeight = snapshot.StartHeight
+			last.EndHeight = snapshot.EndHeight
+		}
+
+		encoded, err := json.Marshal(history)
+		if err != nil {
+			return fmt.Errorf("failed to encode status history: %w", err)
+		}
+
+		if err := historyBucket.Put(txHash.CloneBytes(), encoded); err != nil {
+			return err
+		}
+
+		return appendChange(tx, ChangeStatusSnapshotRecorded, txHash, snapshot.State)
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return transition, nil
+}
+
+// GetStatusHistory returns recorded statuses of tracked delegation, oldest
+// first. Returns nil if no status was recorded yet.
+func (c *TrackedTransactionStore) GetStatusHistory(txHash *chainhash.Hash) ([]StatusSnapshot, error) {
+	var history []StatusSnapshot
+
+	err := c.db.View(func(tx kvdb.RTx) error {
+		historyBucket := tx.ReadBucket(statusHistoryBucketName)
+		if historyBucket == nil {
+			return ErrCorruptedTransactionsDB
+		}
+
+		v := historyBucket.Get(txHash[:])
+		if v == nil {
+			return nil
+		}
+
+		return json.Unmarshal(v, &history)
+	}, func() {
+		history = nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get status history: %w", err)
+	}
+
+	return history, nil
+}
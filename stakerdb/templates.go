@@ -0,0 +1,167 @@
+package stakerdb
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	scfg "github.com/babylonlabs-io/btc-staker/stakercfg"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/lightningnetwork/lnd/kvdb"
+)
+
+var (
+	// mapping template name -> json encoded template
+	// It holds delegation templates created through the API
+	templatesBucketName = []byte("delegationTemplates")
+
+	// mapping txHash -> json encoded labels
+	// It holds labels of delegations created from templates
+	labelsBucketName = []byte("labels")
+)
+
+// PutTemplate stores delegation template, replacing template with the same name
+func (c *TrackedTransactionStore) PutTemplate(template *scfg.DelegationTemplate) error {
+	encoded, err := json.Marshal(template)
+	if err != nil {
+		return fmt.Errorf("failed to encode template: %w", err)
+	}
+
+	return c.update(func(tx kvdb.RwTx) error {
+		templatesBucket := tx.ReadWriteBucket(templatesBucketName)
+		if templatesBucket == nil {
+			return ErrCorruptedTransactionsDB
+		}
+
+		return templatesBucket.Put([]byte(template.Name), encoded)
+	})
+}
+
+// DeleteTemplate deletes delegation template with given name
+func (c *TrackedTransactionStore) DeleteTemplate(name string) error {
+	return c.update(func(tx kvdb.RwTx) error {
+		templatesBucket := tx.ReadWriteBucket(templatesBucketName)
+		if templatesBucket == nil {
+			return ErrCorruptedTransactionsDB
+		}
+
+		if templatesBucket.Get([]byte(name)) == nil {
+			return ErrTemplateNotFound
+		}
+
+		return templatesBucket.Delete([]byte(name))
+	})
+}
+
+// GetTemplate returns delegation template with given name
+func (c *TrackedTransactionStore) GetTemplate(name string) (*scfg.DelegationTemplate, error) {
+	var template *scfg.DelegationTemplate
+
+	err := c.db.View(func(tx kvdb.RTx) error {
+		templatesBucket := tx.ReadBucket(templatesBucketName)
+		if templatesBucket == nil {
+			return ErrCorruptedTransactionsDB
+		}
+
+		v := templatesBucket.Get([]byte(name))
+		if v == nil {
+			return ErrTemplateNotFound
+		}
+
+		template = new(scfg.DelegationTemplate)
+		return json.Unmarshal(v, template)
+	}, func() {
+		template = nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get template %s: %w", name, err)
+	}
+
+	return template, nil
+}
+
+// ListTemplates returns all stored delegation templates sorted by name
+func (c *TrackedTransactionStore) ListTemplates() ([]scfg.DelegationTemplate, error) {
+	var templates []scfg.DelegationTemplate
+
+	err := c.db.View(func(tx kvdb.RTx) error {
+		templatesBucket := tx.ReadBucket(templatesBucketName)
+		if templatesBucket == nil {
+			return ErrCorruptedTransactionsDB
+		}
+
+		return templatesBucket.ForEach(func(_, v []byte) error {
+			var template scfg.DelegationTemplate
+			if err := json.Unmarshal(v, &template); err != nil {
+				return err
+			}
+
+			templates = append(templates, template)
+			return nil
+		})
+	}, func() {
+		templates = nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list templates: %w", err)
+	}
+
+	sort.Slice(templates, func(i, j int) bool {
+		return templates[i].Name < templates[j].Name
+	})
+
+	return templates, nil
+}
+
+// SetTransactionLabels stores labels of tracked transaction
+func (c *TrackedTransactionStore) SetTransactionLabels(txHash *chainhash.Hash, labels map[string]string) error {
+	encoded, err := json.Marshal(labels)
+	if err != nil {
+		return fmt.Errorf("failed to encode labels: %w", err)
+	}
+
+	return c.update(func(tx kvdb.RwTx) error {
+		transactionIdxBucket := tx.ReadBucket(transactionIndexName)
+		if transactionIdxBucket == nil {
+			return ErrCorruptedTransactionsDB
+		}
+
+		if transactionIdxBucket.Get(txHash[:]) == nil {
+			return ErrTransactionNotFound
+		}
+
+		labelsBucket := tx.ReadWriteBucket(labelsBucketName)
+		if labelsBucket == nil {
+			return ErrCorruptedTransactionsDB
+		}
+
+		return labelsBucket.Put(txHash.CloneBytes(), encoded)
+	})
+}
+
+// GetTransactionLabels returns labels of tracked transaction, nil if the
+// transaction has no labels
+func (c *TrackedTransactionStore) GetTransactionLabels(txHash *chainhash.Hash) (map[string]string, error) {
+	var labels map[string]string
+
+	err := c.db.View(func(tx kvdb.RTx) error {
+		labelsBucket := tx.ReadBucket(labelsBucketName)
+		if labelsBucket == nil {
+			return ErrCorruptedTransactionsDB
+		}
+
+		v := labelsBucket.Get(txHash[:])
+		if v == nil {
+			return nil
+		}
+
+		return json.Unmarshal(v, &labels)
+	}, func() {
+		labels = nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transaction labels: %w", err)
+	}
+
+	return labels, nil
+}
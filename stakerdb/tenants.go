@@ -0,0 +1,137 @@
+package stakerdb
+
+import (
+	"fmt"
+
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/lightningnetwork/lnd/kvdb"
+)
+
+// DefaultTenant is the tenant of transactions which were not explicitly
+// assigned to any tenant, including transactions tracked before tenants were
+// introduced
+const DefaultTenant = "default"
+
+// maxTenantLen is the maximum length of tenant id
+const maxTenantLen = 64
+
+var (
+	// mapping txHash -> tenant id
+	// Transactions without entry belong to DefaultTenant
+	tenantsBucketName = []byte("tenants")
+)
+
+// ValidateTenant checks that tenant id is well formed. Tenant id must be
+// non-empty, at most 64 characters long and consist only of ascii letters,
+// digits, '-', '_' and '.'.
+func ValidateTenant(tenant string) error {
+	if len(tenant) == 0 {
+		return fmt.Errorf("tenant id cannot be empty")
+	}
+
+	if len(tenant) > maxTenantLen {
+		return fmt.Errorf("tenant id cannot be longer than %d characters", maxTenantLen)
+	}
+
+	for _, r := range tenant {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		case r == '-' || r == '_' || r == '.':
+		default:
+			return fmt.Errorf("tenant id contains invalid character %q", r)
+		}
+	}
+
+	return nil
+}
+
+// SetTransactionTenant assigns tracked transaction to the given tenant
+func (c *TrackedTransactionStore) SetTransactionTenant(txHash *chainhash.Hash, tenant string) error {
+	if err := ValidateTenant(tenant); err != nil {
+		return err
+	}
+
+	return c.update(func(tx kvdb.RwTx) error {
+		transactionIdxBucket := tx.ReadBucket(transactionIndexName)
+		if transactionIdxBucket == nil {
+			return ErrCorruptedTransactionsDB
+		}
+
+		if transactionIdxBucket.Get(txHash[:]) == nil {
+			return ErrTransactionNotFound
+		}
+
+		tenantsBucket := tx.ReadWriteBucket(tenantsBucketName)
+		if tenantsBucket == nil {
+			return ErrCorruptedTransactionsDB
+		}
+
+		if err := tenantsBucket.Put(txHash.CloneBytes(), []byte(tenant)); err != nil {
+			return err
+		}
+
+		return appendChange(tx, ChangeTenantSet, txHash, tenant)
+	})
+}
+
+// GetTransactionTenant returns tenant of tracked transaction. Transactions
+// which were never assigned to a tenant belong to DefaultTenant.
+func (c *TrackedTransactionStore) GetTransactionTenant(txHash *chainhash.Hash) (string, error) {
+	var tenant string
+
+	err := c.db.View(func(tx kvdb.RTx) error {
+		tenantsBucket := tx.ReadBucket(tenantsBucketName)
+		if tenantsBucket == nil {
+			return ErrCorruptedTransactionsDB
+		}
+
+		tenant = tenantOf(tenantsBucket, txHash[:])
+		return nil
+	}, func() {
+		tenant = ""
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to get transaction tenant: %w", err)
+	}
+
+	return tenant, nil
+}
+
+// tenantOf returns tenant of the transaction with given hash
+func tenantOf(tenantsBucket kvdb.RBucket, txHash []byte) string {
+	v := tenantsBucket.Get(txHash)
+	if v == nil {
+		return DefaultTenant
+	}
+
+	return string(v)
+}
+
+// countTenantTransactions returns number of tracked transactions belonging to
+// the given tenant. numTransactions is the total number of tracked
+// transactions, used to count transactions of DefaultTenant which mostly have
+// no entry in tenants bucket.
+func countTenantTransactions(tenantsBucket kvdb.RBucket, tenant string, numTransactions uint64) (uint64, error) {
+	var matching, assigned uint64
+
+	err := tenantsBucket.ForEach(func(_, v []byte) error {
+		assigned++
+		if string(v) == tenant {
+			matching++
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	if tenant != DefaultTenant {
+		return matching, nil
+	}
+
+	if assigned > numTransactions {
+		return 0, ErrCorruptedTransactionsDB
+	}
+
+	return numTransactions - assigned + matching, nil
+}
@@ -4,10 +4,13 @@ import (
 	"bytes"
 	"encoding/binary"
 	"fmt"
+	"io"
 	"math"
 
 	"github.com/babylonlabs-io/btc-staker/proto"
 	"github.com/babylonlabs-io/btc-staker/utils"
+	"github.com/babylonlabs-io/btc-staker/utils/faults"
+	"github.com/btcsuite/btcd/btcec/v2"
 	"github.com/btcsuite/btcd/btcutil"
 	"github.com/btcsuite/btcd/chaincfg/chainhash"
 	"github.com/btcsuite/btcd/wire"
@@ -38,13 +41,17 @@ type StoredTransactionScanFn func(tx *StoredTransaction) error
 
 // TrackedTransactionStore is a store which stores transactions which are being tracked
 type TrackedTransactionStore struct {
-	db kvdb.Backend
+	db      kvdb.Backend
+	changes *changeNotifier
+	// integrityKey signs checksums of records, nil if records are not signed
+	integrityKey *btcec.PrivateKey
 }
 
 // StoredTransaction is a struct which contains the information about a
 type StoredTransaction struct {
 	StoredTransactionIdx uint64
 	StakingTx            *wire.MsgTx
+	StakingTxHash        chainhash.Hash
 	StakerAddress        string // Returning address as string, to avoid having to know how to decode address which requires knowing the network we are on
 }
 
@@ -53,6 +60,13 @@ type StoredTransactionQuery struct {
 	IndexOffset        uint64
 	NumMaxTransactions uint64
 	Reversed           bool
+	// StakingTxHashOnly if true, staking transactions are not deserialized,
+	// only their hash is computed. StakingTx field of returned transactions is
+	// nil, while StakingTxHash is set
+	StakingTxHashOnly bool
+	// Tenant if not empty, only transactions assigned to this tenant are
+	// returned and Total counts only transactions of this tenant
+	Tenant string
 }
 
 // StoredTransactionQueryResult is a struct which contains a slice of
@@ -74,14 +88,31 @@ func DefaultStoredTransactionQuery() StoredTransactionQuery {
 // NewTrackedTransactionStore returns a new store backed by db
 func NewTrackedTransactionStore(db kvdb.Backend) (*TrackedTransactionStore,
 	error) {
-	store := &TrackedTransactionStore{db}
+	store := &TrackedTransactionStore{
+		db:      db,
+		changes: newChangeNotifier(),
+	}
 	if err := store.initBuckets(); err != nil {
 		return nil, err
 	}
 
+	if err := store.backfillChecksums(); err != nil {
+		return nil, err
+	}
+
 	return store, nil
 }
 
+// batch runs write transaction on db. Write can be failed by fault injection
+// in builds with the faults build tag.
+func batch(db kvdb.Backend, f func(tx kvdb.RwTx) error) error {
+	if err := faults.Inject(faults.DBWrite); err != nil {
+		return err
+	}
+
+	return kvdb.Batch(db, f)
+}
+
 // initBuckets creates the buckets needed by the store
 func (c *TrackedTransactionStore) initBuckets() error {
 	return kvdb.Batch(c.db, func(tx kvdb.RwTx) error {
@@ -100,6 +131,176 @@ func (c *TrackedTransactionStore) initBuckets() error {
 			return fmt.Errorf("failed to create inputs data bucket: %w", err)
 		}
 
+		_, err = tx.CreateTopLevelBucket(activityBucketName)
+		if err != nil {
+			return fmt.Errorf("failed to create activity bucket: %w", err)
+		}
+
+		_, err = tx.CreateTopLevelBucket(failedTransactionsBucketName)
+		if err != nil {
+			return fmt.Errorf("failed to create failed transactions bucket: %w", err)
+		}
+
+		_, err = tx.CreateTopLevelBucket(creationHeightsBucketName)
+		if err != nil {
+			return fmt.Errorf("failed to create creation heights bucket: %w", err)
+		}
+
+		_, err = tx.CreateTopLevelBucket(broadcastHeightsBucketName)
+		if err != nil {
+			return fmt.Errorf("failed to create broadcast heights bucket: %w", err)
+		}
+
+		_, err = tx.CreateTopLevelBucket(stakerAddressesBucketName)
+		if err != nil {
+			return fmt.Errorf("failed to create staker addresses bucket: %w", err)
+		}
+
+		_, err = tx.CreateTopLevelBucket(mempoolWatchBucketName)
+		if err != nil {
+			return fmt.Errorf("failed to create mempool watch bucket: %w", err)
+		}
+
+		_, err = tx.CreateTopLevelBucket(paidFeesBucketName)
+		if err != nil {
+			return fmt.Errorf("failed to create paid fees bucket: %w", err)
+		}
+
+		_, err = tx.CreateTopLevelBucket(leaderTermBucketName)
+		if err != nil {
+			return fmt.Errorf("failed to create leader term bucket: %w", err)
+		}
+
+		_, err = tx.CreateTopLevelBucket(changelogBucketName)
+		if err != nil {
+			return fmt.Errorf("failed to create changelog bucket: %w", err)
+		}
+
+		_, err = tx.CreateTopLevelBucket(tenantsBucketName)
+		if err != nil {
+			return fmt.Errorf("failed to create tenants bucket: %w", err)
+		}
+
+		_, err = tx.CreateTopLevelBucket(templatesBucketName)
+		if err != nil {
+			return fmt.Errorf("failed to create templates bucket: %w", err)
+		}
+
+		_, err = tx.CreateTopLevelBucket(labelsBucketName)
+		if err != nil {
+			return fmt.Errorf("failed to create labels bucket: %w", err)
+		}
+
+		_, err = tx.CreateTopLevelBucket(stakeQueueBucketName)
+		if err != nil {
+			return fmt.Errorf("failed to create stake queue bucket: %w", err)
+		}
+
+		_, err = tx.CreateTopLevelBucket(statusHistoryBucketName)
+		if err != nil {
+			return fmt.Errorf("failed to create status history bucket: %w", err)
+		}
+
+		_, err = tx.CreateTopLevelBucket(covenantQuorumBucketName)
+		if err != nil {
+			return fmt.Errorf("failed to create covenant quorum bucket: %w", err)
+		}
+
+		_, err = tx.CreateTopLevelBucket(unbondRequestsBucketName)
+		if err != nil {
+			return fmt.Errorf("failed to create unbond requests bucket: %w", err)
+		}
+
+		_, err = tx.CreateTopLevelBucket(babylonTxsBucketName)
+		if err != nil {
+			return fmt.Errorf("failed to create babylon transactions bucket: %w", err)
+		}
+
+		_, err = tx.CreateTopLevelBucket(musig2KeysBucketName)
+		if err != nil {
+			return fmt.Errorf("failed to create musig2 keys bucket: %w", err)
+		}
+
+		_, err = tx.CreateTopLevelBucket(musig2SessionsBucketName)
+		if err != nil {
+			return fmt.Errorf("failed to create musig2 sessions bucket: %w", err)
+		}
+
+		_, err = tx.CreateTopLevelBucket(musig2NoncesBucketName)
+		if err != nil {
+			return fmt.Errorf("failed to create musig2 nonces bucket: %w", err)
+		}
+
+		_, err = tx.CreateTopLevelBucket(spendableHeightsBucketName)
+		if err != nil {
+			return fmt.Errorf("failed to create spendable heights bucket: %w", err)
+		}
+
+		_, err = tx.CreateTopLevelBucket(failureNotesBucketName)
+		if err != nil {
+			return fmt.Errorf("failed to create failure notes bucket: %w", err)
+		}
+
+		_, err = tx.CreateTopLevelBucket(networkBucketName)
+		if err != nil {
+			return fmt.Errorf("failed to create network bucket: %w", err)
+		}
+
+		_, err = tx.CreateTopLevelBucket(autoRenewBucketName)
+		if err != nil {
+			return fmt.Errorf("failed to create auto renew bucket: %w", err)
+		}
+
+		_, err = tx.CreateTopLevelBucket(renewalJobsBucketName)
+		if err != nil {
+			return fmt.Errorf("failed to create renewal jobs bucket: %w", err)
+		}
+
+		_, err = tx.CreateTopLevelBucket(activationPhasesBucketName)
+		if err != nil {
+			return fmt.Errorf("failed to create activation phases bucket: %w", err)
+		}
+
+		_, err = tx.CreateTopLevelBucket(unbondingSignaturesBucketName)
+		if err != nil {
+			return fmt.Errorf("failed to create unbonding signatures bucket: %w", err)
+		}
+
+		_, err = tx.CreateTopLevelBucket(finalityProvidersBucketName)
+		if err != nil {
+			return fmt.Errorf("failed to create finality providers bucket: %w", err)
+		}
+
+		_, err = tx.CreateTopLevelBucket(paramsArchiveBucketName)
+		if err != nil {
+			return fmt.Errorf("failed to create params archive bucket: %w", err)
+		}
+
+		_, err = tx.CreateTopLevelBucket(webhookBucketName)
+		if err != nil {
+			return fmt.Errorf("failed to create webhook bucket: %w", err)
+		}
+
+		_, err = tx.CreateTopLevelBucket(txChecksumsBucketName)
+		if err != nil {
+			return fmt.Errorf("failed to create transaction checksums bucket: %w", err)
+		}
+
+		_, err = tx.CreateTopLevelBucket(quarantineBucketName)
+		if err != nil {
+			return fmt.Errorf("failed to create quarantine bucket: %w", err)
+		}
+
+		_, err = tx.CreateTopLevelBucket(replicaBucketName)
+		if err != nil {
+			return fmt.Errorf("failed to create replica bucket: %w", err)
+		}
+
+		_, err = tx.CreateTopLevelBucket(spendTxIndexBucketName)
+		if err != nil {
+			return fmt.Errorf("failed to create spend tx index bucket: %w", err)
+		}
+
 		return nil
 	})
 }
@@ -114,10 +315,108 @@ func protoTxToStoredTransaction(ttx *proto.TrackedTransaction) (*StoredTransacti
 	return &StoredTransaction{
 		StoredTransactionIdx: ttx.TrackedTransactionIdx,
 		StakingTx:            &stakingTx,
+		StakingTxHash:        stakingTx.TxHash(),
 		StakerAddress:        ttx.StakerAddress,
 	}, nil
 }
 
+// serializedTxHash computes hash of serialized transaction without
+// deserializing it. Witness data, which is not part of the hash, is skipped by
+// walking over inputs, outputs and witnesses.
+func serializedTxHash(serializedTx []byte) (chainhash.Hash, error) {
+	const (
+		versionLen  = 4
+		lockTimeLen = 4
+		outpointLen = chainhash.HashSize + 4
+		sequenceLen = 4
+		valueLen    = 8
+	)
+
+	if len(serializedTx) < versionLen+lockTimeLen+2 {
+		return chainhash.Hash{}, fmt.Errorf("serialized transaction too short")
+	}
+
+	// witness transactions have marker 0x00 and flag 0x01 after version
+	if serializedTx[versionLen] != 0x00 || serializedTx[versionLen+1] != 0x01 {
+		return chainhash.DoubleHashH(serializedTx), nil
+	}
+
+	r := bytes.NewReader(serializedTx[versionLen+2:])
+
+	skip := func(n uint64) error {
+		if n > uint64(r.Len()) {
+			return io.ErrUnexpectedEOF
+		}
+		_, err := r.Seek(int64(n), io.SeekCurrent)
+		return err
+	}
+
+	skipScript := func() error {
+		scriptLen, err := wire.ReadVarInt(r, 0)
+		if err != nil {
+			return err
+		}
+		return skip(scriptLen)
+	}
+
+	numInputs, err := wire.ReadVarInt(r, 0)
+	if err != nil {
+		return chainhash.Hash{}, fmt.Errorf("failed to read number of inputs: %w", err)
+	}
+
+	for i := uint64(0); i < numInputs; i++ {
+		if err := skip(outpointLen); err != nil {
+			return chainhash.Hash{}, fmt.Errorf("failed to read input %d: %w", i, err)
+		}
+		if err := skipScript(); err != nil {
+			return chainhash.Hash{}, fmt.Errorf("failed to read input %d: %w", i, err)
+		}
+		if err := skip(sequenceLen); err != nil {
+			return chainhash.Hash{}, fmt.Errorf("failed to read input %d: %w", i, err)
+		}
+	}
+
+	numOutputs, err := wire.ReadVarInt(r, 0)
+	if err != nil {
+		return chainhash.Hash{}, fmt.Errorf("failed to read number of outputs: %w", err)
+	}
+
+	for i := uint64(0); i < numOutputs; i++ {
+		if err := skip(valueLen); err != nil {
+			return chainhash.Hash{}, fmt.Errorf("failed to read output %d: %w", i, err)
+		}
+		if err := skipScript(); err != nil {
+			return chainhash.Hash{}, fmt.Errorf("failed to read output %d: %w", i, err)
+		}
+	}
+
+	outputsEnd := len(serializedTx) - r.Len()
+
+	for i := uint64(0); i < numInputs; i++ {
+		numItems, err := wire.ReadVarInt(r, 0)
+		if err != nil {
+			return chainhash.Hash{}, fmt.Errorf("failed to read witness %d: %w", i, err)
+		}
+
+		for j := uint64(0); j < numItems; j++ {
+			if err := skipScript(); err != nil {
+				return chainhash.Hash{}, fmt.Errorf("failed to read witness %d: %w", i, err)
+			}
+		}
+	}
+
+	if r.Len() != lockTimeLen {
+		return chainhash.Hash{}, fmt.Errorf("invalid lock time length %d", r.Len())
+	}
+
+	noWitnessTx := make([]byte, 0, versionLen+(outputsEnd-versionLen-2)+lockTimeLen)
+	noWitnessTx = append(noWitnessTx, serializedTx[:versionLen]...)
+	noWitnessTx = append(noWitnessTx, serializedTx[versionLen+2:outputsEnd]...)
+	noWitnessTx = append(noWitnessTx, serializedTx[len(serializedTx)-lockTimeLen:]...)
+
+	return chainhash.DoubleHashH(noWitnessTx), nil
+}
+
 // uint64KeyToBytes converts a uint64 to a byte slice
 func uint64KeyToBytes(key uint64) []byte {
 	var keyBytes = make([]byte, 8)
@@ -176,6 +475,7 @@ func saveTrackedTransaction(
 	txHashBytes []byte,
 	tx *proto.TrackedTransaction,
 	id *inputData,
+	key *btcec.PrivateKey,
 ) error {
 	if tx == nil {
 		return fmt.Errorf("cannot save nil tracked transactions")
@@ -191,7 +491,7 @@ func saveTrackedTransaction(
 
 	nextTxKeyBytes := uint64KeyToBytes(nextTxKey)
 
-	if err := txBucket.Put(nextTxKeyBytes, marshalled); err != nil {
+	if err := putTrackedTransaction(rwTx, txBucket, nextTxKeyBytes, marshalled, key); err != nil {
 		return fmt.Errorf("failed to save transaction: %w", err)
 	}
 
@@ -222,8 +522,9 @@ func (c *TrackedTransactionStore) addTransactionInternal(
 	txHashBytes []byte,
 	tt *proto.TrackedTransaction,
 	id *inputData,
+	tenant string,
 ) error {
-	return kvdb.Batch(c.db, func(tx kvdb.RwTx) error {
+	return c.update(func(tx kvdb.RwTx) error {
 		transactionsBucketIdxBucket := tx.ReadWriteBucket(transactionIndexName)
 
 		if transactionsBucketIdxBucket == nil {
@@ -241,7 +542,33 @@ func (c *TrackedTransactionStore) addTransactionInternal(
 			return ErrCorruptedTransactionsDB
 		}
 
-		return saveTrackedTransaction(tx, transactionsBucketIdxBucket, transactionsBucket, txHashBytes, tt, id)
+		if err := saveTrackedTransaction(tx, transactionsBucketIdxBucket, transactionsBucket, txHashBytes, tt, id, c.integrityKey); err != nil {
+			return err
+		}
+
+		txHash, err := chainhash.NewHash(txHashBytes)
+		if err != nil {
+			return err
+		}
+
+		if err := appendChange(tx, ChangeTransactionAdded, txHash, tt.StakerAddress); err != nil {
+			return err
+		}
+
+		if tenant == "" {
+			return nil
+		}
+
+		tenantsBucket := tx.ReadWriteBucket(tenantsBucketName)
+		if tenantsBucket == nil {
+			return ErrCorruptedTransactionsDB
+		}
+
+		if err := tenantsBucket.Put(txHashBytes, []byte(tenant)); err != nil {
+			return err
+		}
+
+		return appendChange(tx, ChangeTenantSet, txHash, tenant)
 	})
 }
 
@@ -269,6 +596,10 @@ func deleteTrackedTransaction(
 		return fmt.Errorf("failed to delete transaction data: %w", err)
 	}
 
+	if err := deleteChecksum(rwTx, indexBytes); err != nil {
+		return fmt.Errorf("failed to delete transaction checksum: %w", err)
+	}
+
 	if err := txIdxBucket.Delete(txHashBytes); err != nil {
 		return fmt.Errorf("failed to delete transaction index: %w", err)
 	}
@@ -288,6 +619,154 @@ func deleteTrackedTransaction(
 		}
 	}
 
+	failedBucket := rwTx.ReadWriteBucket(failedTransactionsBucketName)
+	if failedBucket == nil {
+		return ErrCorruptedTransactionsDB
+	}
+
+	if err := failedBucket.Delete(txHashBytes); err != nil {
+		return fmt.Errorf("failed to delete transaction failure: %w", err)
+	}
+
+	heightsBucket := rwTx.ReadWriteBucket(creationHeightsBucketName)
+	if heightsBucket == nil {
+		return ErrCorruptedTransactionsDB
+	}
+
+	if err := heightsBucket.Delete(txHashBytes); err != nil {
+		return fmt.Errorf("failed to delete transaction creation height: %w", err)
+	}
+
+	broadcastHeightsBucket := rwTx.ReadWriteBucket(broadcastHeightsBucketName)
+	if broadcastHeightsBucket == nil {
+		return ErrCorruptedTransactionsDB
+	}
+
+	if err := broadcastHeightsBucket.Delete(txHashBytes); err != nil {
+		return fmt.Errorf("failed to delete transaction broadcast height: %w", err)
+	}
+
+	tenantsBucket := rwTx.ReadWriteBucket(tenantsBucketName)
+	if tenantsBucket == nil {
+		return ErrCorruptedTransactionsDB
+	}
+
+	if err := tenantsBucket.Delete(txHashBytes); err != nil {
+		return fmt.Errorf("failed to delete transaction tenant: %w", err)
+	}
+
+	labelsBucket := rwTx.ReadWriteBucket(labelsBucketName)
+	if labelsBucket == nil {
+		return ErrCorruptedTransactionsDB
+	}
+
+	if err := labelsBucket.Delete(txHashBytes); err != nil {
+		return fmt.Errorf("failed to delete transaction labels: %w", err)
+	}
+
+	historyBucket := rwTx.ReadWriteBucket(statusHistoryBucketName)
+	if historyBucket == nil {
+		return ErrCorruptedTransactionsDB
+	}
+
+	if err := historyBucket.Delete(txHashBytes); err != nil {
+		return fmt.Errorf("failed to delete transaction status history: %w", err)
+	}
+
+	quorumBucket := rwTx.ReadWriteBucket(covenantQuorumBucketName)
+	if quorumBucket == nil {
+		return ErrCorruptedTransactionsDB
+	}
+
+	if err := quorumBucket.Delete(txHashBytes); err != nil {
+		return fmt.Errorf("failed to delete transaction covenant quorum timing: %w", err)
+	}
+
+	unbondRequestsBucket := rwTx.ReadWriteBucket(unbondRequestsBucketName)
+	if unbondRequestsBucket == nil {
+		return ErrCorruptedTransactionsDB
+	}
+
+	if err := unbondRequestsBucket.Delete(txHashBytes); err != nil {
+		return fmt.Errorf("failed to delete transaction unbond request: %w", err)
+	}
+
+	babylonTxsBucket := rwTx.ReadWriteBucket(babylonTxsBucketName)
+	if babylonTxsBucket == nil {
+		return ErrCorruptedTransactionsDB
+	}
+
+	if err := babylonTxsBucket.Delete(txHashBytes); err != nil {
+		return fmt.Errorf("failed to delete transaction babylon transactions: %w", err)
+	}
+
+	spendableHeightsBucket := rwTx.ReadWriteBucket(spendableHeightsBucketName)
+	if spendableHeightsBucket == nil {
+		return ErrCorruptedTransactionsDB
+	}
+
+	if err := spendableHeightsBucket.Delete(txHashBytes); err != nil {
+		return fmt.Errorf("failed to delete transaction spendable height: %w", err)
+	}
+
+	failureNotesBucket := rwTx.ReadWriteBucket(failureNotesBucketName)
+	if failureNotesBucket == nil {
+		return ErrCorruptedTransactionsDB
+	}
+
+	if err := failureNotesBucket.Delete(txHashBytes); err != nil {
+		return fmt.Errorf("failed to delete transaction failure note: %w", err)
+	}
+
+	autoRenewBucket := rwTx.ReadWriteBucket(autoRenewBucketName)
+	if autoRenewBucket == nil {
+		return ErrCorruptedTransactionsDB
+	}
+
+	if err := autoRenewBucket.Delete(txHashBytes); err != nil {
+		return fmt.Errorf("failed to delete transaction auto renew: %w", err)
+	}
+
+	renewalJobsBucket := rwTx.ReadWriteBucket(renewalJobsBucketName)
+	if renewalJobsBucket == nil {
+		return ErrCorruptedTransactionsDB
+	}
+
+	if err := renewalJobsBucket.Delete(txHashBytes); err != nil {
+		return fmt.Errorf("failed to delete transaction renewal job: %w", err)
+	}
+
+	activationPhasesBucket := rwTx.ReadWriteBucket(activationPhasesBucketName)
+	if activationPhasesBucket == nil {
+		return ErrCorruptedTransactionsDB
+	}
+
+	if err := activationPhasesBucket.Delete(txHashBytes); err != nil {
+		return fmt.Errorf("failed to delete transaction activation phases: %w", err)
+	}
+
+	unbondingSignaturesBucket := rwTx.ReadWriteBucket(unbondingSignaturesBucketName)
+	if unbondingSignaturesBucket == nil {
+		return ErrCorruptedTransactionsDB
+	}
+
+	if err := unbondingSignaturesBucket.Delete(txHashBytes); err != nil {
+		return fmt.Errorf("failed to delete transaction unbonding signatures: %w", err)
+	}
+
+	finalityProvidersBucket := rwTx.ReadWriteBucket(finalityProvidersBucketName)
+	if finalityProvidersBucket == nil {
+		return ErrCorruptedTransactionsDB
+	}
+
+	if err := finalityProvidersBucket.Delete(txHashBytes); err != nil {
+		return fmt.Errorf("failed to delete transaction finality providers: %w", err)
+	}
+
+	if err := deleteSpendTxIndex(rwTx, txHashBytes); err != nil {
+		return fmt.Errorf("failed to delete spend tx index: %w", err)
+	}
+
 	// Update number of transactions
 	if currentNumTx > 0 {
 		if err := txIdxBucket.Put(numTxKey, uint64KeyToBytes(currentNumTx-1)); err != nil {
@@ -300,7 +779,7 @@ func deleteTrackedTransaction(
 
 // deleteTransasctionInternal deletes a transaction from the database
 func (c *TrackedTransactionStore) deleteTransasctionInternal(txHash []byte) error {
-	return kvdb.Batch(c.db, func(tx kvdb.RwTx) error {
+	return c.update(func(tx kvdb.RwTx) error {
 		transactionsBucketIdxBucket := tx.ReadWriteBucket(transactionIndexName)
 		if transactionsBucketIdxBucket == nil {
 			return ErrCorruptedTransactionsDB
@@ -316,7 +795,16 @@ func (c *TrackedTransactionStore) deleteTransasctionInternal(txHash []byte) erro
 			return ErrCorruptedTransactionsDB
 		}
 
-		return deleteTrackedTransaction(tx, transactionsBucketIdxBucket, transactionsBucket, txHash)
+		if err := deleteTrackedTransaction(tx, transactionsBucketIdxBucket, transactionsBucket, txHash); err != nil {
+			return err
+		}
+
+		hash, err := chainhash.NewHash(txHash)
+		if err != nil {
+			return err
+		}
+
+		return appendChange(tx, ChangeTransactionDeleted, hash, "")
 	})
 }
 
@@ -387,6 +875,23 @@ func (c *TrackedTransactionStore) AddTransactionSentToBabylon(
 	btcTx *wire.MsgTx,
 	stakerAddress btcutil.Address,
 ) error {
+	return c.AddTenantTransactionSentToBabylon(btcTx, stakerAddress, "")
+}
+
+// AddTenantTransactionSentToBabylon adds a transaction sent to Babylon and
+// assigns it to the given tenant in the same db transaction. Empty tenant
+// means default tenant, which is not stored.
+func (c *TrackedTransactionStore) AddTenantTransactionSentToBabylon(
+	btcTx *wire.MsgTx,
+	stakerAddress btcutil.Address,
+	tenant string,
+) error {
+	if tenant != "" {
+		if err := ValidateTenant(tenant); err != nil {
+			return err
+		}
+	}
+
 	txHash := btcTx.TxHash()
 	txHashBytes := txHash[:]
 	serializedTx, err := utils.SerializeBtcTransaction(btcTx)
@@ -407,7 +912,7 @@ func (c *TrackedTransactionStore) AddTransactionSentToBabylon(
 	}
 
 	return c.addTransactionInternal(
-		txHashBytes, &msg, inputData,
+		txHashBytes, &msg, inputData, tenant,
 	)
 }
 
@@ -421,9 +926,24 @@ func (c *TrackedTransactionStore) DeleteTransactionSentToBabylon(txHash *chainha
 	return c.deleteTransasctionInternal(txHashBytes)
 }
 
-// GetTransaction retrieves a transaction by its hash
+// Ping checks that database is open and readable
+func (c *TrackedTransactionStore) Ping() error {
+	return c.db.View(func(tx kvdb.RTx) error {
+		if tx.ReadBucket(transactionBucketName) == nil {
+			return ErrCorruptedTransactionsDB
+		}
+		return nil
+	}, func() {})
+}
+
+// GetTransaction retrieves a transaction by its hash.
+// Record which fails to decode or validate is quarantined and
+// ErrTransactionQuarantined is returned.
 func (c *TrackedTransactionStore) GetTransaction(txHash *chainhash.Hash) (*StoredTransaction, error) {
-	var storedTx *StoredTransaction
+	var (
+		storedTx *StoredTransaction
+		bad      []badRecord
+	)
 	txHashBytes := txHash.CloneBytes()
 
 	if err := c.db.View(func(tx kvdb.RTx) error {
@@ -438,28 +958,33 @@ func (c *TrackedTransactionStore) GetTransaction(txHash *chainhash.Hash) (*Store
 			return ErrCorruptedTransactionsDB
 		}
 
-		maybeTx, _, err := getTxByHash(txHashBytes, transactionIdxBucket, transactionsBucket)
+		maybeTx, txKey, err := getTxByHash(txHashBytes, transactionIdxBucket, transactionsBucket)
 		if err != nil {
 			return fmt.Errorf("failed to get transaction by hash: %w", err)
 		}
 
-		var storedTxProto proto.TrackedTransaction
-		err = pm.Unmarshal(maybeTx, &storedTxProto)
+		txFromDB, err := decodeTrackedTransaction(tx, txKey, maybeTx)
 		if err != nil {
-			return fmt.Errorf("failed to unmarshal transaction: %w", err)
-		}
-
-		txFromDB, err := protoTxToStoredTransaction(&storedTxProto)
-		if err != nil {
-			return fmt.Errorf("failed to convert transaction to stored transaction: %w", err)
+			bad = append(bad, newBadRecord(txKey, err))
+			return nil
 		}
 
 		storedTx = txFromDB
 		return nil
-	}, func() {}); err != nil {
+	}, func() {
+		storedTx = nil
+		bad = nil
+	}); err != nil {
 		return nil, fmt.Errorf("failed to get transaction: %w", err)
 	}
 
+	if len(bad) > 0 {
+		if err := c.quarantine(bad); err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("%w: %s", ErrTransactionQuarantined, bad[0].reason)
+	}
+
 	return storedTx, nil
 }
 
@@ -477,9 +1002,13 @@ func (c *TrackedTransactionStore) GetAllStoredTransactions() ([]StoredTransactio
 	return resp.Transactions, nil
 }
 
-// QueryStoredTransactions queries stored transactions
+// QueryStoredTransactions queries stored transactions.
+// Records which fail to decode or validate are quarantined and skipped.
 func (c *TrackedTransactionStore) QueryStoredTransactions(q StoredTransactionQuery) (StoredTransactionQueryResult, error) {
-	var resp StoredTransactionQueryResult
+	var (
+		resp StoredTransactionQueryResult
+		bad  []badRecord
+	)
 
 	if err := c.db.View(func(tx kvdb.RTx) error {
 		transactionsBucket := tx.ReadBucket(transactionBucketName)
@@ -499,21 +1028,67 @@ func (c *TrackedTransactionStore) QueryStoredTransactions(q StoredTransactionQue
 
 		resp.Total = numTransactions
 
+		var tenantsBucket kvdb.RBucket
+		if q.Tenant != "" {
+			tenantsBucket = tx.ReadBucket(tenantsBucketName)
+			if tenantsBucket == nil {
+				return ErrCorruptedTransactionsDB
+			}
+
+			total, err := countTenantTransactions(tenantsBucket, q.Tenant, numTransactions)
+			if err != nil {
+				return fmt.Errorf("failed to count tenant transactions: %w", err)
+			}
+			resp.Total = total
+		}
+
 		paginator := newPaginator(
 			transactionsBucket.ReadCursor(), q.Reversed, q.IndexOffset,
 			q.NumMaxTransactions,
 		)
 
-		accumulateTransactions := func(_, transaction []byte) (bool, error) {
+		accumulateTransactions := func(txKey, transaction []byte) (bool, error) {
+			if err := verifyRecord(tx, txKey, transaction); err != nil {
+				bad = append(bad, newBadRecord(txKey, err))
+				return false, nil
+			}
+
 			protoTx := proto.TrackedTransaction{}
 
 			if err := pm.Unmarshal(transaction, &protoTx); err != nil {
-				return false, fmt.Errorf("failed to unmarshal transaction: %w", err)
+				bad = append(bad, newBadRecord(txKey, fmt.Errorf("failed to unmarshal transaction: %w", err)))
+				return false, nil
+			}
+
+			if tenantsBucket != nil {
+				stakingTxHash, err := serializedTxHash(protoTx.StakingTransaction)
+				if err != nil {
+					return false, fmt.Errorf("failed to compute staking transaction hash: %w", err)
+				}
+
+				if tenantOf(tenantsBucket, stakingTxHash[:]) != q.Tenant {
+					return false, nil
+				}
+			}
+
+			if q.StakingTxHashOnly {
+				stakingTxHash, err := serializedTxHash(protoTx.StakingTransaction)
+				if err != nil {
+					return false, fmt.Errorf("failed to compute staking transaction hash: %w", err)
+				}
+
+				resp.Transactions = append(resp.Transactions, StoredTransaction{
+					StoredTransactionIdx: protoTx.TrackedTransactionIdx,
+					StakingTxHash:        stakingTxHash,
+					StakerAddress:        protoTx.StakerAddress,
+				})
+				return true, nil
 			}
 
 			txFromDB, err := protoTxToStoredTransaction(&protoTx)
 			if err != nil {
-				return false, fmt.Errorf("failed to convert transaction to stored transaction: %w", err)
+				bad = append(bad, newBadRecord(txKey, fmt.Errorf("failed to convert transaction to stored transaction: %w", err)))
+				return false, nil
 			}
 
 			resp.Transactions = append(resp.Transactions, *txFromDB)
@@ -536,36 +1111,67 @@ func (c *TrackedTransactionStore) QueryStoredTransactions(q StoredTransactionQue
 		return nil
 	}, func() {
 		resp = StoredTransactionQueryResult{}
+		bad = nil
 	}); err != nil {
 		return resp, fmt.Errorf("failed to query stored transactions: %w", err)
 	}
 
+	if err := c.quarantine(bad); err != nil {
+		return StoredTransactionQueryResult{}, err
+	}
+
 	return resp, nil
 }
 
-// ScanTrackedTransactions iterates over all stored transactions
+// ScanTrackedTransactions iterates over all stored transactions. Records which
+// fail to decode or validate are quarantined and skipped.
 func (c *TrackedTransactionStore) ScanTrackedTransactions(scanFunc StoredTransactionScanFn, reset func()) error {
-	return kvdb.View(c.db, func(tx kvdb.RTx) error {
+	var bad []badRecord
+
+	err := kvdb.View(c.db, func(tx kvdb.RTx) error {
 		transactionsBucket := tx.ReadBucket(transactionBucketName)
 
 		if transactionsBucket == nil {
 			return ErrCorruptedTransactionsDB
 		}
 
-		return transactionsBucket.ForEach(func(_, v []byte) error {
-			var storedTxProto proto.TrackedTransaction
-			if err := pm.Unmarshal(v, &storedTxProto); err != nil {
-				return ErrCorruptedTransactionsDB
-			}
-
-			txFromDB, err := protoTxToStoredTransaction(&storedTxProto)
+		return transactionsBucket.ForEach(func(k, v []byte) error {
+			txFromDB, err := decodeTrackedTransaction(tx, k, v)
 			if err != nil {
-				return fmt.Errorf("failed to convert proto transaction to stored transaction: %w", err)
+				bad = append(bad, newBadRecord(k, err))
+				return nil
 			}
 
 			return scanFunc(txFromDB)
 		})
-	}, reset)
+	}, func() {
+		bad = nil
+		reset()
+	})
+	if err != nil {
+		return err
+	}
+
+	return c.quarantine(bad)
+}
+
+// decodeTrackedTransaction validates and decodes record of tracked transaction
+func decodeTrackedTransaction(tx kvdb.RTx, txKey, record []byte) (*StoredTransaction, error) {
+	if err := verifyRecord(tx, txKey, record); err != nil {
+		return nil, err
+	}
+
+	var storedTxProto proto.TrackedTransaction
+	if err := pm.Unmarshal(record, &storedTxProto); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal transaction: %w", err)
+	}
+
+	txFromDB, err := protoTxToStoredTransaction(&storedTxProto)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert transaction to stored transaction: %w", err)
+	}
+
+	return txFromDB, nil
 }
 
 // OutpointUsed checks if an outpoint is used by a tracked transaction
@@ -593,3 +1199,127 @@ func (c *TrackedTransactionStore) OutpointUsed(op *wire.OutPoint) (bool, error)
 
 	return used, err
 }
+
+// ReservedOutpoint is a wallet outpoint which is used as an input by a tracked
+// staking transaction
+type ReservedOutpoint struct {
+	Outpoint      wire.OutPoint
+	StakingTxHash chainhash.Hash
+}
+
+// outpointFromBytes converts bytes created by outpointBytes back to an outpoint
+func outpointFromBytes(b []byte) (*wire.OutPoint, error) {
+	if len(b) != chainhash.HashSize+4 {
+		return nil, fmt.Errorf("invalid outpoint length: %d", len(b))
+	}
+
+	var hash chainhash.Hash
+	copy(hash[:], b[:chainhash.HashSize])
+
+	return wire.NewOutPoint(&hash, binary.BigEndian.Uint32(b[chainhash.HashSize:])), nil
+}
+
+// ListReservedOutpoints returns all outpoints used as inputs by tracked transactions
+func (c *TrackedTransactionStore) ListReservedOutpoints() ([]ReservedOutpoint, error) {
+	var reserved []ReservedOutpoint
+
+	err := c.db.View(func(tx kvdb.RTx) error {
+		inputsBucket := tx.ReadBucket(inputsDataBucketName)
+		if inputsBucket == nil {
+			return ErrCorruptedTransactionsDB
+		}
+
+		return inputsBucket.ForEach(func(k, v []byte) error {
+			op, err := outpointFromBytes(k)
+			if err != nil {
+				return ErrCorruptedTransactionsDB
+			}
+
+			stakingTxHash, err := chainhash.NewHash(v)
+			if err != nil {
+				return ErrCorruptedTransactionsDB
+			}
+
+			reserved = append(reserved, ReservedOutpoint{
+				Outpoint:      *op,
+				StakingTxHash: *stakingTxHash,
+			})
+			return nil
+		})
+	}, func() {
+		reserved = nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list reserved outpoints: %w", err)
+	}
+
+	return reserved, nil
+}
+
+// ReleaseOutpoints removes reservations of all inputs of the tracked transaction
+// with given hash. Transaction itself stays tracked.
+func (c *TrackedTransactionStore) ReleaseOutpoints(txHash *chainhash.Hash) error {
+	txHashBytes := txHash.CloneBytes()
+
+	return c.update(func(tx kvdb.RwTx) error {
+		inputsBucket := tx.ReadWriteBucket(inputsDataBucketName)
+		if inputsBucket == nil {
+			return ErrCorruptedTransactionsDB
+		}
+
+		var toDelete [][]byte
+		cursor := inputsBucket.ReadCursor()
+		for k, v := cursor.First(); k != nil; k, v = cursor.Next() {
+			if bytes.Equal(v, txHashBytes) {
+				toDelete = append(toDelete, bytes.Clone(k))
+			}
+		}
+
+		for _, k := range toDelete {
+			if err := inputsBucket.Delete(k); err != nil {
+				return fmt.Errorf("failed to delete input data: %w", err)
+			}
+		}
+
+		return appendChange(tx, ChangeOutpointsReleased, txHash, "")
+	})
+}
+
+// UnreserveOutpoint removes reservation of a single outpoint and returns hash of
+// the tracked transaction which was using it
+func (c *TrackedTransactionStore) UnreserveOutpoint(op *wire.OutPoint) (*chainhash.Hash, error) {
+	opBytes, err := outpointBytes(op)
+	if err != nil {
+		return nil, fmt.Errorf("invalid outpoint provided: %w", err)
+	}
+
+	var stakingTxHash *chainhash.Hash
+	err = c.update(func(tx kvdb.RwTx) error {
+		inputsBucket := tx.ReadWriteBucket(inputsDataBucketName)
+		if inputsBucket == nil {
+			return ErrCorruptedTransactionsDB
+		}
+
+		txHashBytes := inputsBucket.Get(opBytes)
+		if txHashBytes == nil {
+			return ErrOutpointNotReserved
+		}
+
+		hash, err := chainhash.NewHash(txHashBytes)
+		if err != nil {
+			return ErrCorruptedTransactionsDB
+		}
+		stakingTxHash = hash
+
+		if err := inputsBucket.Delete(opBytes); err != nil {
+			return err
+		}
+
+		return appendChange(tx, ChangeOutpointUnreserved, hash, op.String())
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return stakingTxHash, nil
+}
@@ -1,16 +1,23 @@
 package stakerdb_test
 
 import (
+	"crypto/sha256"
 	"errors"
 	"math/rand"
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
 	"github.com/babylonlabs-io/babylon/v4/testutil/datagen"
 	"github.com/babylonlabs-io/btc-staker/stakercfg"
 	"github.com/babylonlabs-io/btc-staker/stakerdb"
+	"github.com/btcsuite/btcd/btcec/v2"
 	"github.com/btcsuite/btcd/btcutil"
 	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/kvdb"
 	"github.com/stretchr/testify/require"
 )
 
@@ -22,6 +29,7 @@ func MakeTestStore(t *testing.T) *stakerdb.TrackedTransactionStore {
 	cfg := stakercfg.DefaultDBConfig()
 
 	cfg.DBPath = tempDirName
+	cfg.NoSync = true
 
 	backend, err := stakercfg.GetDBBackend(&cfg)
 	require.NoError(t, err)
@@ -209,6 +217,20 @@ func FuzzTrackInputs(f *testing.F) {
 			}
 		}
 
+		reserved, err := s.ListReservedOutpoints()
+		require.NoError(t, err)
+		reservedMap := make(map[wire.OutPoint]chainhash.Hash)
+		for _, ro := range reserved {
+			reservedMap[ro.Outpoint] = ro.StakingTxHash
+		}
+		for _, storedTx := range generatedStoredTxs {
+			for _, inp := range storedTx.StakingTx.TxIn {
+				stakingTxHash, ok := reservedMap[inp.PreviousOutPoint]
+				require.True(t, ok)
+				require.Equal(t, storedTx.StakingTx.TxHash(), stakingTxHash)
+			}
+		}
+
 		// generate few not saved transactions
 		notSaved := genNStoredTransactions(t, r, 20)
 
@@ -285,3 +307,2060 @@ func FuzzStoringAndRemovingTxs(f *testing.F) {
 		require.Equal(t, len(generatedStoredTxs)-1, int(storedResultAfterDel.Total))
 	})
 }
+
+func TestActivityEvents(t *testing.T) {
+	t.Parallel()
+	r := rand.New(rand.NewSource(time.Now().UnixNano()))
+	s := MakeTestStore(t)
+
+	events := []*stakerdb.ActivityEvent{
+		{
+			Kind:          stakerdb.ActivityDelegation,
+			StakingTxHash: datagen.GenRandomBtcdHash(r),
+			Amount:        btcutil.Amount(r.Int63n(1000000)),
+			Timestamp:     time.Unix(1700000000, 0),
+			BtcHeight:     100,
+		},
+		{
+			Kind:          stakerdb.ActivityWithdrawal,
+			StakingTxHash: datagen.GenRandomBtcdHash(r),
+			Amount:        btcutil.Amount(r.Int63n(1000000)),
+			Timestamp:     time.Unix(1700000100, 0),
+			BtcHeight:     101,
+		},
+	}
+
+	for _, ev := range events {
+		require.NoError(t, s.AddActivityEvent(ev))
+	}
+
+	var scanned []*stakerdb.ActivityEvent
+	err := s.ScanActivityEvents(func(ev *stakerdb.ActivityEvent) error {
+		scanned = append(scanned, ev)
+		return nil
+	}, func() {
+		scanned = nil
+	})
+	require.NoError(t, err)
+	require.Len(t, scanned, len(events))
+
+	for i, ev := range events {
+		require.Equal(t, ev.Kind, scanned[i].Kind)
+		require.Equal(t, ev.StakingTxHash, scanned[i].StakingTxHash)
+		require.Equal(t, ev.Amount, scanned[i].Amount)
+		require.True(t, ev.Timestamp.Equal(scanned[i].Timestamp))
+		require.Equal(t, ev.BtcHeight, scanned[i].BtcHeight)
+	}
+}
+
+func TestReleaseOutpoints(t *testing.T) {
+	t.Parallel()
+	r := rand.New(rand.NewSource(time.Now().UnixNano()))
+	s := MakeTestStore(t)
+
+	generatedStoredTxs := genNStoredTransactions(t, r, 2)
+	for _, storedTx := range generatedStoredTxs {
+		stakerAddr, err := btcutil.DecodeAddress(storedTx.StakerAddress, &chaincfg.MainNetParams)
+		require.NoError(t, err)
+		err = s.AddTransactionSentToBabylon(
+			storedTx.StakingTx,
+			stakerAddr,
+		)
+		require.NoError(t, err)
+	}
+
+	first := generatedStoredTxs[0].StakingTx
+	firstHash := first.TxHash()
+	require.NoError(t, s.ReleaseOutpoints(&firstHash))
+
+	for _, inp := range first.TxIn {
+		used, err := s.OutpointUsed(&inp.PreviousOutPoint)
+		require.NoError(t, err)
+		require.False(t, used)
+	}
+
+	// transaction itself is still tracked
+	_, err := s.GetTransaction(&firstHash)
+	require.NoError(t, err)
+
+	second := generatedStoredTxs[1].StakingTx
+	op := second.TxIn[0].PreviousOutPoint
+	stakingTxHash, err := s.UnreserveOutpoint(&op)
+	require.NoError(t, err)
+	require.Equal(t, second.TxHash(), *stakingTxHash)
+
+	used, err := s.OutpointUsed(&op)
+	require.NoError(t, err)
+	require.False(t, used)
+
+	_, err = s.UnreserveOutpoint(&op)
+	require.ErrorIs(t, err, stakerdb.ErrOutpointNotReserved)
+}
+
+func TestTransactionFailures(t *testing.T) {
+	t.Parallel()
+	r := rand.New(rand.NewSource(time.Now().UnixNano()))
+	s := MakeTestStore(t)
+
+	storedTx := genStoredTransaction(t, r)
+	txHash := storedTx.StakingTx.TxHash()
+
+	// cannot fail untracked transaction
+	err := s.MarkTransactionFailed(&txHash, "double spent")
+	require.ErrorIs(t, err, stakerdb.ErrTransactionNotFound)
+
+	stakerAddr, err := btcutil.DecodeAddress(storedTx.StakerAddress, &chaincfg.MainNetParams)
+	require.NoError(t, err)
+	require.NoError(t, s.AddTransactionSentToBabylon(storedTx.StakingTx, stakerAddr))
+
+	failure, err := s.GetTransactionFailure(&txHash)
+	require.NoError(t, err)
+	require.Nil(t, failure)
+
+	require.NoError(t, s.MarkTransactionFailed(&txHash, "double spent"))
+
+	failure, err = s.GetTransactionFailure(&txHash)
+	require.NoError(t, err)
+	require.NotNil(t, failure)
+	require.Equal(t, "double spent", failure.Reason)
+	require.Equal(t, txHash, failure.StakingTxHash)
+
+	failures, err := s.ListTransactionFailures()
+	require.NoError(t, err)
+	require.Len(t, failures, 1)
+
+	// deleting transaction removes its failure
+	require.NoError(t, s.DeleteTransactionSentToBabylon(&txHash))
+	failures, err = s.ListTransactionFailures()
+	require.NoError(t, err)
+	require.Empty(t, failures)
+}
+
+func TestTransactionCreationHeight(t *testing.T) {
+	t.Parallel()
+	r := rand.New(rand.NewSource(time.Now().UnixNano()))
+	s := MakeTestStore(t)
+
+	storedTx := genStoredTransaction(t, r)
+	txHash := storedTx.StakingTx.TxHash()
+
+	err := s.SetTransactionCreationHeight(&txHash, 100)
+	require.ErrorIs(t, err, stakerdb.ErrTransactionNotFound)
+
+	stakerAddr, err := btcutil.DecodeAddress(storedTx.StakerAddress, &chaincfg.MainNetParams)
+	require.NoError(t, err)
+	require.NoError(t, s.AddTransactionSentToBabylon(storedTx.StakingTx, stakerAddr))
+
+	_, found, err := s.GetTransactionCreationHeight(&txHash)
+	require.NoError(t, err)
+	require.False(t, found)
+
+	require.NoError(t, s.SetTransactionCreationHeight(&txHash, 100))
+
+	height, found, err := s.GetTransactionCreationHeight(&txHash)
+	require.NoError(t, err)
+	require.True(t, found)
+	require.Equal(t, uint32(100), height)
+}
+
+func TestTransactionBroadcastHeight(t *testing.T) {
+	t.Parallel()
+	r := rand.New(rand.NewSource(time.Now().UnixNano()))
+	s := MakeTestStore(t)
+
+	storedTx := genStoredTransaction(t, r)
+	txHash := storedTx.StakingTx.TxHash()
+
+	_, err := s.SetTransactionBroadcastHeight(&txHash, 100)
+	require.ErrorIs(t, err, stakerdb.ErrTransactionNotFound)
+
+	stakerAddr, err := btcutil.DecodeAddress(storedTx.StakerAddress, &chaincfg.MainNetParams)
+	require.NoError(t, err)
+	require.NoError(t, s.AddTransactionSentToBabylon(storedTx.StakingTx, stakerAddr))
+
+	_, found, err := s.GetTransactionBroadcastHeight(&txHash)
+	require.NoError(t, err)
+	require.False(t, found)
+
+	updated, err := s.SetTransactionBroadcastHeight(&txHash, 100)
+	require.NoError(t, err)
+	require.True(t, updated)
+
+	// height of the first broadcast is kept
+	updated, err = s.SetTransactionBroadcastHeight(&txHash, 120)
+	require.NoError(t, err)
+	require.False(t, updated)
+
+	height, found, err := s.GetTransactionBroadcastHeight(&txHash)
+	require.NoError(t, err)
+	require.True(t, found)
+	require.Equal(t, uint32(100), height)
+
+	require.NoError(t, s.DeleteTransactionSentToBabylon(&txHash))
+	require.NoError(t, s.AddTransactionSentToBabylon(storedTx.StakingTx, stakerAddr))
+	_, found, err = s.GetTransactionBroadcastHeight(&txHash)
+	require.NoError(t, err)
+	require.False(t, found)
+}
+
+func TestStakerAddresses(t *testing.T) {
+	t.Parallel()
+	s := MakeTestStore(t)
+
+	addresses, err := s.ListStakerAddresses()
+	require.NoError(t, err)
+	require.Empty(t, addresses)
+
+	require.NoError(t, s.AddStakerAddress("bc1paddress", "taproot"))
+	require.NoError(t, s.AddStakerAddress("bc1qaddress", "segwit"))
+	// registering again keeps original entry
+	require.NoError(t, s.AddStakerAddress("bc1paddress", "segwit"))
+
+	addresses, err = s.ListStakerAddresses()
+	require.NoError(t, err)
+	require.Len(t, addresses, 2)
+
+	types := make(map[string]string)
+	for _, a := range addresses {
+		types[a.Address] = a.Type
+	}
+	require.Equal(t, "taproot", types["bc1paddress"])
+	require.Equal(t, "segwit", types["bc1qaddress"])
+}
+
+func TestWatchedTransactions(t *testing.T) {
+	t.Parallel()
+	r := rand.New(rand.NewSource(time.Now().UnixNano()))
+	s := MakeTestStore(t)
+
+	storedTx := genStoredTransaction(t, r)
+	stakingTxHash := storedTx.StakingTx.TxHash()
+	otherTx := genStoredTransaction(t, r)
+
+	watched := &stakerdb.WatchedTransaction{
+		Tx:            storedTx.StakingTx,
+		StakingTxHash: stakingTxHash,
+		Kind:          stakerdb.WatchedStakingTx,
+		BroadcastAt:   time.Unix(1000, 0),
+	}
+	require.NoError(t, s.PutWatchedTransaction(watched))
+	require.NoError(t, s.PutWatchedTransaction(&stakerdb.WatchedTransaction{
+		Tx:            otherTx.StakingTx,
+		StakingTxHash: otherTx.StakingTx.TxHash(),
+		Kind:          stakerdb.WatchedStakingTx,
+		BroadcastAt:   time.Unix(1000, 0),
+	}))
+
+	all, err := s.ListWatchedTransactions()
+	require.NoError(t, err)
+	require.Len(t, all, 2)
+
+	watched.SeenInMempoolAt = time.Unix(2000, 0)
+	watched.Rebroadcasts = 2
+	require.NoError(t, s.PutWatchedTransaction(watched))
+
+	own, err := s.WatchedTransactionsOf(&stakingTxHash)
+	require.NoError(t, err)
+	require.Len(t, own, 1)
+	require.Equal(t, stakingTxHash, own[0].Tx.TxHash())
+	require.Equal(t, stakerdb.WatchedStakingTx, own[0].Kind)
+	require.Equal(t, time.Unix(2000, 0), own[0].SeenInMempoolAt)
+	require.True(t, own[0].EvictedAt.IsZero())
+	require.Equal(t, uint32(2), own[0].Rebroadcasts)
+
+	require.NoError(t, s.DeleteWatchedTransaction(&stakingTxHash))
+
+	own, err = s.WatchedTransactionsOf(&stakingTxHash)
+	require.NoError(t, err)
+	require.Empty(t, own)
+}
+
+func TestDelegationFees(t *testing.T) {
+	t.Parallel()
+	r := rand.New(rand.NewSource(time.Now().UnixNano()))
+	s := MakeTestStore(t)
+
+	stakingTxHash := genStoredTransaction(t, r).StakingTx.TxHash()
+
+	fees, err := s.GetDelegationFees(&stakingTxHash)
+	require.NoError(t, err)
+	require.Equal(t, btcutil.Amount(0), fees.Total())
+
+	require.NoError(t, s.RecordPaidFee(&stakingTxHash, stakerdb.WatchedStakingTx, 1000))
+	require.NoError(t, s.RecordPaidFee(&stakingTxHash, stakerdb.WatchedUnbondingTx, 500))
+	// recording the same kind again overwrites previous fee
+	require.NoError(t, s.RecordPaidFee(&stakingTxHash, stakerdb.WatchedStakingTx, 1200))
+	require.Error(t, s.RecordPaidFee(&stakingTxHash, stakerdb.WatchedWithdrawalTx, -1))
+
+	fees, err = s.GetDelegationFees(&stakingTxHash)
+	require.NoError(t, err)
+	require.Equal(t, btcutil.Amount(1200), fees.StakingFee)
+	require.Equal(t, btcutil.Amount(500), fees.UnbondingFee)
+	require.Equal(t, btcutil.Amount(0), fees.WithdrawalFee)
+	require.Equal(t, btcutil.Amount(1700), fees.Total())
+
+	all, err := s.ListDelegationFees()
+	require.NoError(t, err)
+	require.Len(t, all, 1)
+	require.Equal(t, stakingTxHash, all[0].StakingTxHash)
+}
+
+func TestInspectionOfHealthyStore(t *testing.T) {
+	t.Parallel()
+	r := rand.New(rand.NewSource(time.Now().UnixNano()))
+	s := MakeTestStore(t)
+
+	for _, storedTx := range genNStoredTransactions(t, r, 5) {
+		stakerAddr, err := btcutil.DecodeAddress(storedTx.StakerAddress, &chaincfg.MainNetParams)
+		require.NoError(t, err)
+		require.NoError(t, s.AddTransactionSentToBabylon(storedTx.StakingTx, stakerAddr))
+	}
+
+	health, err := s.CheckIndexHealth()
+	require.NoError(t, err)
+	require.True(t, health.Healthy())
+	require.Equal(t, uint64(5), health.TrackedTransactions)
+	require.Equal(t, uint64(5), health.IndexEntries)
+
+	stats, err := s.BucketStats()
+	require.NoError(t, err)
+
+	keys := make(map[string]int)
+	for _, bucket := range stats {
+		keys[bucket.Name] = bucket.Keys
+	}
+	require.Equal(t, 5, keys["transactions"])
+	// index also stores transaction counter
+	require.Equal(t, 6, keys["transactionIdx"])
+}
+
+func TestQueryStakingTxHashOnly(t *testing.T) {
+	t.Parallel()
+	r := rand.New(rand.NewSource(time.Now().UnixNano()))
+	s := MakeTestStore(t)
+
+	generatedStoredTxs := genNStoredTransactions(t, r, 10)
+	for i, storedTx := range generatedStoredTxs {
+		// half of the transactions carry witness data, which is not part of
+		// the hash
+		if i%2 == 0 {
+			for _, in := range storedTx.StakingTx.TxIn {
+				in.Witness = [][]byte{datagen.GenRandomByteArray(r, 64)}
+			}
+		}
+
+		stakerAddr, err := btcutil.DecodeAddress(storedTx.StakerAddress, &chaincfg.MainNetParams)
+		require.NoError(t, err)
+		require.NoError(t, s.AddTransactionSentToBabylon(storedTx.StakingTx, stakerAddr))
+	}
+
+	query := stakerdb.DefaultStoredTransactionQuery()
+	query.StakingTxHashOnly = true
+	result, err := s.QueryStoredTransactions(query)
+	require.NoError(t, err)
+	require.Len(t, result.Transactions, len(generatedStoredTxs))
+
+	for i, storedTx := range generatedStoredTxs {
+		require.Nil(t, result.Transactions[i].StakingTx)
+		require.Equal(t, storedTx.StakingTx.TxHash(), result.Transactions[i].StakingTxHash)
+		require.Equal(t, storedTx.StakerAddress, result.Transactions[i].StakerAddress)
+	}
+}
+
+func TestLeaderTerm(t *testing.T) {
+	s := MakeTestStore(t)
+
+	// no leader was elected yet
+	err := s.CheckLeaderTerm(1)
+	require.ErrorIs(t, err, stakerdb.ErrStaleLeaderTerm)
+
+	require.NoError(t, s.AdvanceLeaderTerm(1))
+	require.NoError(t, s.CheckLeaderTerm(1))
+
+	// standby instance takes over
+	require.NoError(t, s.AdvanceLeaderTerm(5))
+	err = s.CheckLeaderTerm(1)
+	require.ErrorIs(t, err, stakerdb.ErrStaleLeaderTerm)
+	require.NoError(t, s.CheckLeaderTerm(5))
+
+	// previous leader cannot take leadership back with its old term
+	err = s.AdvanceLeaderTerm(1)
+	require.ErrorIs(t, err, stakerdb.ErrStaleLeaderTerm)
+	require.NoError(t, s.CheckLeaderTerm(5))
+}
+
+func TestChangelog(t *testing.T) {
+	t.Parallel()
+	r := rand.New(rand.NewSource(time.Now().UnixNano()))
+	s := MakeTestStore(t)
+
+	changes, err := s.QueryChanges(0, 10)
+	require.NoError(t, err)
+	require.Empty(t, changes)
+
+	notify := s.ChangesNotify()
+
+	storedTx := genStoredTransaction(t, r)
+	stakerAddr, err := btcutil.DecodeAddress(storedTx.StakerAddress, &chaincfg.MainNetParams)
+	require.NoError(t, err)
+	require.NoError(t, s.AddTransactionSentToBabylon(storedTx.StakingTx, stakerAddr))
+
+	select {
+	case <-notify:
+	default:
+		t.Fatal("subscribers were not notified about the change")
+	}
+
+	txHash := storedTx.StakingTx.TxHash()
+	require.NoError(t, s.SetTransactionCreationHeight(&txHash, 100))
+	require.NoError(t, s.MarkTransactionFailed(&txHash, "failure"))
+	require.NoError(t, s.DeleteTransactionSentToBabylon(&txHash))
+
+	changes, err = s.QueryChanges(0, 10)
+	require.NoError(t, err)
+	require.Len(t, changes, 4)
+
+	expectedKinds := []stakerdb.ChangeKind{
+		stakerdb.ChangeTransactionAdded,
+		stakerdb.ChangeCreationHeightSet,
+		stakerdb.ChangeTransactionFailed,
+		stakerdb.ChangeTransactionDeleted,
+	}
+	for i, ch := range changes {
+		require.Equal(t, uint64(i+1), ch.Seq)
+		require.Equal(t, expectedKinds[i], ch.Kind)
+		require.Equal(t, txHash, ch.StakingTxHash)
+	}
+	require.Equal(t, storedTx.StakerAddress, changes[0].Detail)
+	require.Equal(t, "100", changes[1].Detail)
+	require.Equal(t, "failure", changes[2].Detail)
+
+	// resume after the second change
+	changes, err = s.QueryChanges(2, 1)
+	require.NoError(t, err)
+	require.Len(t, changes, 1)
+	require.Equal(t, uint64(3), changes[0].Seq)
+
+	changes, err = s.QueryChanges(4, 10)
+	require.NoError(t, err)
+	require.Empty(t, changes)
+
+	// failed mutation is not recorded
+	require.Error(t, s.MarkTransactionFailed(&txHash, "failure"))
+	changes, err = s.QueryChanges(4, 10)
+	require.NoError(t, err)
+	require.Empty(t, changes)
+}
+
+func TestPruneChangelog(t *testing.T) {
+	t.Parallel()
+	r := rand.New(rand.NewSource(time.Now().UnixNano()))
+	s := MakeTestStore(t)
+
+	generatedStoredTxs := genNStoredTransactions(t, r, 2)
+	for _, storedTx := range generatedStoredTxs {
+		stakerAddr, err := btcutil.DecodeAddress(storedTx.StakerAddress, &chaincfg.MainNetParams)
+		require.NoError(t, err)
+		require.NoError(t, s.AddTransactionSentToBabylon(storedTx.StakingTx, stakerAddr))
+		txHash := storedTx.StakingTx.TxHash()
+		require.NoError(t, s.SetTransactionCreationHeight(&txHash, 100))
+	}
+
+	// changes 1-2 and 5 belong to the deleted delegation, 3-4 to the tracked one
+	deletedHash := generatedStoredTxs[0].StakingTx.TxHash()
+	require.NoError(t, s.DeleteTransactionSentToBabylon(&deletedHash))
+
+	stats, err := s.ChangelogStats()
+	require.NoError(t, err)
+	require.Equal(t, uint64(5), stats.Changes)
+	require.Equal(t, uint64(1), stats.OldestSeq)
+	require.Equal(t, uint64(5), stats.LastSeq)
+	require.NotZero(t, stats.SizeBytes)
+
+	// nothing is old enough
+	removed, _, err := s.PruneChangelog(stakerdb.ChangelogRetention{
+		MaxAge:     time.Hour,
+		CompactAge: time.Hour,
+	}, time.Now())
+	require.NoError(t, err)
+	require.Zero(t, removed)
+
+	// compaction keeps only deletion record of untracked delegation
+	later := time.Now().Add(2 * time.Hour)
+	removed, stats, err = s.PruneChangelog(stakerdb.ChangelogRetention{CompactAge: time.Hour}, later)
+	require.NoError(t, err)
+	require.Equal(t, uint64(2), removed)
+	require.Equal(t, uint64(3), stats.Changes)
+	require.Equal(t, uint64(3), stats.OldestSeq)
+
+	changes, err := s.QueryChanges(0, 10)
+	require.NoError(t, err)
+	require.Len(t, changes, 3)
+	require.Equal(t, stakerdb.ChangeTransactionDeleted, changes[2].Kind)
+
+	// count limit does not remove protected changes
+	removed, stats, err = s.PruneChangelog(stakerdb.ChangelogRetention{
+		MaxChanges:  1,
+		KeepFromSeq: 4,
+	}, later)
+	require.NoError(t, err)
+	require.Equal(t, uint64(1), removed)
+	require.Equal(t, uint64(2), stats.Changes)
+	require.Equal(t, uint64(4), stats.OldestSeq)
+
+	removed, stats, err = s.PruneChangelog(stakerdb.ChangelogRetention{MaxAge: time.Hour}, later)
+	require.NoError(t, err)
+	require.Equal(t, uint64(2), removed)
+	require.Zero(t, stats.Changes)
+	require.Zero(t, stats.OldestSeq)
+	require.Equal(t, uint64(5), stats.LastSeq)
+
+	// sequence numbers are not reused after pruning
+	trackedHash := generatedStoredTxs[1].StakingTx.TxHash()
+	require.NoError(t, s.MarkTransactionFailed(&trackedHash, "failure"))
+	changes, err = s.QueryChanges(0, 10)
+	require.NoError(t, err)
+	require.Len(t, changes, 1)
+	require.Equal(t, uint64(6), changes[0].Seq)
+}
+
+func TestTransactionTenants(t *testing.T) {
+	t.Parallel()
+	r := rand.New(rand.NewSource(time.Now().UnixNano()))
+	s := MakeTestStore(t)
+
+	generatedStoredTxs := genNStoredTransactions(t, r, 6)
+	for i, storedTx := range generatedStoredTxs {
+		stakerAddr, err := btcutil.DecodeAddress(storedTx.StakerAddress, &chaincfg.MainNetParams)
+		require.NoError(t, err)
+
+		// every other transaction belongs to tenant, rest stays in default
+		if i%2 == 0 {
+			require.NoError(t, s.AddTenantTransactionSentToBabylon(storedTx.StakingTx, stakerAddr, "desk-a"))
+		} else {
+			require.NoError(t, s.AddTransactionSentToBabylon(storedTx.StakingTx, stakerAddr))
+		}
+	}
+
+	firstHash := generatedStoredTxs[0].StakingTx.TxHash()
+	tenant, err := s.GetTransactionTenant(&firstHash)
+	require.NoError(t, err)
+	require.Equal(t, "desk-a", tenant)
+
+	// tenant is written together with the transaction
+	changes, err := s.ChangesOf(&firstHash)
+	require.NoError(t, err)
+	require.Len(t, changes, 2)
+	require.Equal(t, stakerdb.ChangeTransactionAdded, changes[0].Kind)
+	require.Equal(t, stakerdb.ChangeTenantSet, changes[1].Kind)
+	require.Equal(t, "desk-a", changes[1].Detail)
+
+	secondHash := generatedStoredTxs[1].StakingTx.TxHash()
+	tenant, err = s.GetTransactionTenant(&secondHash)
+	require.NoError(t, err)
+	require.Equal(t, stakerdb.DefaultTenant, tenant)
+
+	query := stakerdb.DefaultStoredTransactionQuery()
+	query.StakingTxHashOnly = true
+	query.Tenant = "desk-a"
+	result, err := s.QueryStoredTransactions(query)
+	require.NoError(t, err)
+	require.Equal(t, uint64(3), result.Total)
+	require.Len(t, result.Transactions, 3)
+	for i, tx := range result.Transactions {
+		require.Equal(t, generatedStoredTxs[2*i].StakingTx.TxHash(), tx.StakingTxHash)
+	}
+
+	query.Tenant = stakerdb.DefaultTenant
+	result, err = s.QueryStoredTransactions(query)
+	require.NoError(t, err)
+	require.Equal(t, uint64(3), result.Total)
+	require.Len(t, result.Transactions, 3)
+	for i, tx := range result.Transactions {
+		require.Equal(t, generatedStoredTxs[2*i+1].StakingTx.TxHash(), tx.StakingTxHash)
+	}
+
+	query.Tenant = "unknown"
+	result, err = s.QueryStoredTransactions(query)
+	require.NoError(t, err)
+	require.Zero(t, result.Total)
+	require.Empty(t, result.Transactions)
+
+	// tenant is forgotten together with the transaction
+	require.NoError(t, s.DeleteTransactionSentToBabylon(&firstHash))
+	tenant, err = s.GetTransactionTenant(&firstHash)
+	require.NoError(t, err)
+	require.Equal(t, stakerdb.DefaultTenant, tenant)
+
+	require.Error(t, s.SetTransactionTenant(&firstHash, "desk-a"))
+	require.Error(t, s.SetTransactionTenant(&secondHash, "invalid tenant"))
+
+	// transaction with invalid tenant is not added
+	invalidTx := genNStoredTransactions(t, r, 1)[0]
+	invalidAddr, err := btcutil.DecodeAddress(invalidTx.StakerAddress, &chaincfg.MainNetParams)
+	require.NoError(t, err)
+	require.Error(t, s.AddTenantTransactionSentToBabylon(invalidTx.StakingTx, invalidAddr, "invalid tenant"))
+	invalidHash := invalidTx.StakingTx.TxHash()
+	_, err = s.GetTransaction(&invalidHash)
+	require.ErrorIs(t, err, stakerdb.ErrTransactionNotFound)
+}
+
+func TestDelegationTemplates(t *testing.T) {
+	t.Parallel()
+	s := MakeTestStore(t)
+
+	templates, err := s.ListTemplates()
+	require.NoError(t, err)
+	require.Empty(t, templates)
+
+	treasury := &stakercfg.DelegationTemplate{
+		Name:              "treasury-default",
+		FinalityProviders: []string{"fp1", "fp2"},
+		StakingTime:       64000,
+		MaxFeeRate:        20,
+		Tenant:            "treasury",
+		Labels:            map[string]string{"desk": "treasury"},
+	}
+	require.NoError(t, s.PutTemplate(treasury))
+	require.NoError(t, s.PutTemplate(&stakercfg.DelegationTemplate{
+		Name:              "a-short",
+		FinalityProviders: []string{"fp1"},
+		StakingTime:       1000,
+	}))
+
+	stored, err := s.GetTemplate("treasury-default")
+	require.NoError(t, err)
+	require.Equal(t, treasury, stored)
+
+	templates, err = s.ListTemplates()
+	require.NoError(t, err)
+	require.Len(t, templates, 2)
+	require.Equal(t, "a-short", templates[0].Name)
+
+	require.NoError(t, s.DeleteTemplate("a-short"))
+	require.ErrorIs(t, s.DeleteTemplate("a-short"), stakerdb.ErrTemplateNotFound)
+	_, err = s.GetTemplate("a-short")
+	require.ErrorIs(t, err, stakerdb.ErrTemplateNotFound)
+}
+
+func TestTransactionLabels(t *testing.T) {
+	t.Parallel()
+	r := rand.New(rand.NewSource(time.Now().UnixNano()))
+	s := MakeTestStore(t)
+
+	storedTx := genStoredTransaction(t, r)
+	txHash := storedTx.StakingTx.TxHash()
+	labels := map[string]string{"desk": "treasury"}
+
+	require.ErrorIs(t, s.SetTransactionLabels(&txHash, labels), stakerdb.ErrTransactionNotFound)
+
+	stakerAddr, err := btcutil.DecodeAddress(storedTx.StakerAddress, &chaincfg.MainNetParams)
+	require.NoError(t, err)
+	require.NoError(t, s.AddTransactionSentToBabylon(storedTx.StakingTx, stakerAddr))
+
+	stored, err := s.GetTransactionLabels(&txHash)
+	require.NoError(t, err)
+	require.Nil(t, stored)
+
+	require.NoError(t, s.SetTransactionLabels(&txHash, labels))
+	stored, err = s.GetTransactionLabels(&txHash)
+	require.NoError(t, err)
+	require.Equal(t, labels, stored)
+
+	// labels are forgotten together with the transaction
+	require.NoError(t, s.DeleteTransactionSentToBabylon(&txHash))
+	stored, err = s.GetTransactionLabels(&txHash)
+	require.NoError(t, err)
+	require.Nil(t, stored)
+}
+
+func TestStakeQueue(t *testing.T) {
+	t.Parallel()
+	s := MakeTestStore(t)
+
+	queued, err := s.ListQueuedStakes()
+	require.NoError(t, err)
+	require.Empty(t, queued)
+
+	first := &stakerdb.QueuedStake{
+		StakerAddress:     "addr1",
+		Amount:            btcutil.Amount(100000),
+		FinalityProviders: []string{"fp1"},
+		StakingTime:       1000,
+		Tenant:            "treasury",
+		Labels:            map[string]string{"desk": "treasury"},
+		QueuedAt:          time.Unix(1000, 0).UTC(),
+	}
+	firstID, err := s.QueueStake(first, 2)
+	require.NoError(t, err)
+
+	secondID, err := s.QueueStake(&stakerdb.QueuedStake{
+		StakerAddress:     "addr2",
+		Amount:            btcutil.Amount(200000),
+		FinalityProviders: []string{"fp2"},
+		StakingTime:       2000,
+		QueuedAt:          time.Unix(2000, 0).UTC(),
+	}, 2)
+	require.NoError(t, err)
+	require.Greater(t, secondID, firstID)
+
+	_, err = s.QueueStake(&stakerdb.QueuedStake{StakerAddress: "addr3"}, 2)
+	require.ErrorIs(t, err, stakerdb.ErrStakeQueueFull)
+
+	queued, err = s.ListQueuedStakes()
+	require.NoError(t, err)
+	require.Len(t, queued, 2)
+	first.ID = firstID
+	require.Equal(t, *first, queued[0])
+	require.Equal(t, secondID, queued[1].ID)
+
+	hash := chainhash.Hash{1}
+	require.NoError(t, s.MarkQueuedStakeSubmitted(firstID, &hash))
+	require.ErrorIs(t, s.CancelQueuedStake(firstID), stakerdb.ErrQueuedStakeNotFound)
+	require.NoError(t, s.MarkQueuedStakeFailed(secondID, "amount too low"))
+
+	queued, err = s.ListQueuedStakes()
+	require.NoError(t, err)
+	require.Empty(t, queued)
+
+	// ids are not reused after removal
+	thirdID, err := s.QueueStake(&stakerdb.QueuedStake{StakerAddress: "addr3"}, 2)
+	require.NoError(t, err)
+	require.Greater(t, thirdID, secondID)
+
+	require.NoError(t, s.RecordStakingGateChange(false, "allow list is enabled"))
+	require.NoError(t, s.RecordStakingGateChange(true, ""))
+
+	changes, err := s.QueryChanges(0, 10)
+	require.NoError(t, err)
+
+	kinds := make([]stakerdb.ChangeKind, len(changes))
+	for i, ch := range changes {
+		kinds[i] = ch.Kind
+	}
+	require.Equal(t, []stakerdb.ChangeKind{
+		stakerdb.ChangeStakeQueued,
+		stakerdb.ChangeStakeQueued,
+		stakerdb.ChangeQueuedStakeSubmitted,
+		stakerdb.ChangeQueuedStakeFailed,
+		stakerdb.ChangeStakeQueued,
+		stakerdb.ChangeStakingGateClosed,
+		stakerdb.ChangeStakingGateOpened,
+	}, kinds)
+	require.Equal(t, hash, changes[2].StakingTxHash)
+	require.Equal(t, "allow list is enabled", changes[5].Detail)
+}
+
+func TestStatusHistory(t *testing.T) {
+	t.Parallel()
+	r := rand.New(rand.NewSource(time.Now().UnixNano()))
+	s := MakeTestStore(t)
+
+	storedTx := genStoredTransaction(t, r)
+	txHash := storedTx.StakingTx.TxHash()
+	start := time.Unix(1000, 0).UTC()
+
+	_, err := s.RecordStatusSnapshot(&txHash, &stakerdb.StatusSnapshot{State: "PENDING", FirstSeenAt: start})
+	require.ErrorIs(t, err, stakerdb.ErrTransactionNotFound)
+
+	stakerAddr, err := btcutil.DecodeAddress(storedTx.StakerAddress, &chaincfg.MainNetParams)
+	require.NoError(t, err)
+	require.NoError(t, s.AddTransactionSentToBabylon(storedTx.StakingTx, stakerAddr))
+
+	history, err := s.GetStatusHistory(&txHash)
+	require.NoError(t, err)
+	require.Nil(t, history)
+
+	transition, err := s.RecordStatusSnapshot(&txHash, &stakerdb.StatusSnapshot{
+		State:       "PENDING",
+		FirstSeenAt: start,
+		BtcHeight:   100,
+	})
+	require.NoError(t, err)
+	require.True(t, transition)
+
+	transition, err = s.RecordStatusSnapshot(&txHash, &stakerdb.StatusSnapshot{
+		State:       "PENDING",
+		FirstSeenAt: start.Add(10 * time.Minute),
+		BtcHeight:   101,
+	})
+	require.NoError(t, err)
+	require.False(t, transition)
+
+	// daemon was offline while delegation became active
+	transition, err = s.RecordStatusSnapshot(&txHash, &stakerdb.StatusSnapshot{
+		State:       "ACTIVE",
+		FirstSeenAt: start.Add(5 * time.Hour),
+		BtcHeight:   130,
+		StartHeight: 102,
+		EndHeight:   1102,
+	})
+	require.NoError(t, err)
+	require.True(t, transition)
+
+	history, err = s.GetStatusHistory(&txHash)
+	require.NoError(t, err)
+	require.Len(t, history, 2)
+
+	require.Equal(t, "PENDING", history[0].State)
+	require.True(t, history[0].PreviousSeenAt.IsZero())
+	require.Equal(t, start, history[0].FirstSeenAt)
+	require.Equal(t, start.Add(10*time.Minute), history[0].LastSeenAt)
+	require.Equal(t, uint32(100), history[0].BtcHeight)
+
+	require.Equal(t, "ACTIVE", history[1].State)
+	require.Equal(t, start.Add(10*time.Minute), history[1].PreviousSeenAt)
+	require.Equal(t, start.Add(5*time.Hour), history[1].FirstSeenAt)
+	require.Equal(t, uint32(102), history[1].StartHeight)
+	require.Equal(t, uint32(1102), history[1].EndHeight)
+
+	// history is forgotten together with the transaction
+	require.NoError(t, s.DeleteTransactionSentToBabylon(&txHash))
+	history, err = s.GetStatusHistory(&txHash)
+	require.NoError(t, err)
+	require.Nil(t, history)
+}
+
+func TestCovenantQuorumTiming(t *testing.T) {
+	t.Parallel()
+	r := rand.New(rand.NewSource(time.Now().UnixNano()))
+	s := MakeTestStore(t)
+
+	storedTx := genStoredTransaction(t, r)
+	txHash := storedTx.StakingTx.TxHash()
+	registeredAt := time.Unix(1000, 0)
+
+	require.ErrorIs(t, s.SetDelegationRegisteredAt(&txHash, registeredAt), stakerdb.ErrTransactionNotFound)
+
+	stakerAddr, err := btcutil.DecodeAddress(storedTx.StakerAddress, &chaincfg.MainNetParams)
+	require.NoError(t, err)
+	require.NoError(t, s.AddTransactionSentToBabylon(storedTx.StakingTx, stakerAddr))
+
+	timing, err := s.GetCovenantQuorumTiming(&txHash)
+	require.NoError(t, err)
+	require.Nil(t, timing)
+
+	require.NoError(t, s.SetDelegationRegisteredAt(&txHash, registeredAt))
+	timing, err = s.GetCovenantQuorumTiming(&txHash)
+	require.NoError(t, err)
+	_, ok := timing.Latency()
+	require.False(t, ok)
+
+	stored, err := s.SetCovenantQuorumReachedAt(&txHash, registeredAt.Add(90*time.Second))
+	require.NoError(t, err)
+	require.True(t, stored)
+
+	// quorum time is stored only once
+	stored, err = s.SetCovenantQuorumReachedAt(&txHash, registeredAt.Add(time.Hour))
+	require.NoError(t, err)
+	require.False(t, stored)
+
+	timing, err = s.GetCovenantQuorumTiming(&txHash)
+	require.NoError(t, err)
+	latency, ok := timing.Latency()
+	require.True(t, ok)
+	require.Equal(t, 90*time.Second, latency)
+
+	require.NoError(t, s.DeleteTransactionSentToBabylon(&txHash))
+	timing, err = s.GetCovenantQuorumTiming(&txHash)
+	require.NoError(t, err)
+	require.Nil(t, timing)
+}
+
+func TestActivationTimeline(t *testing.T) {
+	t.Parallel()
+	r := rand.New(rand.NewSource(time.Now().UnixNano()))
+	s := MakeTestStore(t)
+
+	storedTx := genStoredTransaction(t, r)
+	txHash := storedTx.StakingTx.TxHash()
+	start := time.Unix(1000, 0).UTC()
+
+	_, err := s.SetActivationPhaseAt(&txHash, stakerdb.ActivationPhaseBroadcast, start)
+	require.ErrorIs(t, err, stakerdb.ErrTransactionNotFound)
+
+	stakerAddr, err := btcutil.DecodeAddress(storedTx.StakerAddress, &chaincfg.MainNetParams)
+	require.NoError(t, err)
+	require.NoError(t, s.AddTransactionSentToBabylon(storedTx.StakingTx, stakerAddr))
+
+	timeline, err := s.GetActivationTimeline(&txHash)
+	require.NoError(t, err)
+	require.Nil(t, timeline)
+
+	require.NoError(t, s.SetDelegationRegisteredAt(&txHash, start))
+	_, err = s.SetCovenantQuorumReachedAt(&txHash, start.Add(time.Minute))
+	require.NoError(t, err)
+
+	stored, err := s.SetActivationPhaseAt(&txHash, stakerdb.ActivationPhaseBroadcast, start.Add(2*time.Minute))
+	require.NoError(t, err)
+	require.True(t, stored)
+
+	// phase time is stored only once
+	stored, err = s.SetActivationPhaseAt(&txHash, stakerdb.ActivationPhaseBroadcast, start.Add(3*time.Minute))
+	require.NoError(t, err)
+	require.False(t, stored)
+
+	stored, err = s.SetActivationPhaseAt(&txHash, stakerdb.ActivationPhaseActive, start.Add(time.Hour))
+	require.NoError(t, err)
+	require.True(t, stored)
+
+	expected := &stakerdb.ActivationTimeline{
+		BroadcastAt:      start.Add(2 * time.Minute),
+		RegisteredAt:     start,
+		CovenantQuorumAt: start.Add(time.Minute),
+		ActiveAt:         start.Add(time.Hour),
+	}
+
+	timeline, err = s.GetActivationTimeline(&txHash)
+	require.NoError(t, err)
+	require.Equal(t, expected.BroadcastAt.UnixNano(), timeline.BroadcastAt.UnixNano())
+	require.True(t, timeline.ConfirmedAt.IsZero())
+	require.Equal(t, expected.RegisteredAt.UnixNano(), timeline.RegisteredAt.UnixNano())
+	require.Equal(t, expected.CovenantQuorumAt.UnixNano(), timeline.CovenantQuorumAt.UnixNano())
+	require.Equal(t, expected.ActiveAt.UnixNano(), timeline.ActiveAt.UnixNano())
+
+	timelines, err := s.ListActivationTimelines()
+	require.NoError(t, err)
+	require.Len(t, timelines, 1)
+	require.Equal(t, timeline, timelines[txHash])
+
+	changes, err := s.QueryChanges(0, 100)
+	require.NoError(t, err)
+	require.Equal(t, stakerdb.ChangeActivationPhaseReached, changes[len(changes)-1].Kind)
+	require.Equal(t, "active", changes[len(changes)-1].Detail)
+
+	require.NoError(t, s.DeleteTransactionSentToBabylon(&txHash))
+	timelines, err = s.ListActivationTimelines()
+	require.NoError(t, err)
+	require.Empty(t, timelines)
+}
+
+func TestSnapshot(t *testing.T) {
+	t.Parallel()
+	r := rand.New(rand.NewSource(time.Now().UnixNano()))
+	s := MakeTestStore(t)
+	require.NoError(t, s.BindNetwork(&chaincfg.MainNetParams))
+
+	storedTx := genStoredTransaction(t, r)
+	stakerAddr, err := btcutil.DecodeAddress(storedTx.StakerAddress, &chaincfg.MainNetParams)
+	require.NoError(t, err)
+	require.NoError(t, s.AddTransactionSentToBabylon(storedTx.StakingTx, stakerAddr))
+
+	params := &stakerdb.ArchivedParams{
+		Version:             1,
+		BtcActivationHeight: 100,
+		Params:              []byte(`{"unbonding_time":100}`),
+		ArchivedAt:          time.Unix(1000, 0).UTC(),
+	}
+	_, err = s.ArchiveParams(params)
+	require.NoError(t, err)
+
+	dir := filepath.Join(t.TempDir(), "snapshot")
+	exported, err := s.ExportSnapshot(dir, map[string][]byte{stakerdb.SnapshotDescriptorsFile: []byte(`{}`)})
+	require.NoError(t, err)
+	require.Equal(t, chaincfg.MainNetParams.Name, exported.Network)
+	require.Equal(t, uint64(1), exported.TrackedTransactions)
+	require.Len(t, exported.Files, 3)
+
+	// snapshot directory is never reused
+	_, err = s.ExportSnapshot(dir, nil)
+	require.Error(t, err)
+
+	manifest, manifestHash, err := stakerdb.ReadSnapshotManifest(dir)
+	require.NoError(t, err)
+	require.Equal(t, exported.Files, manifest.Files)
+	require.Len(t, manifestHash, 64)
+	require.NoError(t, stakerdb.VerifySnapshot(dir, manifest))
+
+	// restored database holds the tracked transaction
+	backend, err := kvdb.Open(kvdb.BoltBackendName, filepath.Join(dir, stakerdb.SnapshotDBFile), true, time.Second)
+	require.NoError(t, err)
+	restored, err := stakerdb.NewTrackedTransactionStore(backend)
+	require.NoError(t, err)
+	txHash := storedTx.StakingTx.TxHash()
+	_, err = restored.GetTransaction(&txHash)
+	require.NoError(t, err)
+	require.NoError(t, backend.Close())
+
+	// params archive is restored into database which lacks it
+	fresh := MakeTestStore(t)
+	n, err := fresh.RestoreSnapshotParams(dir)
+	require.NoError(t, err)
+	require.Equal(t, 1, n)
+	archived, err := fresh.GetArchivedParams(1)
+	require.NoError(t, err)
+	require.Equal(t, params, archived)
+
+	// files outside of snapshot directory are rejected
+	outside := *manifest
+	outside.Files = []stakerdb.SnapshotFile{{Name: "../" + stakerdb.SnapshotDBFile}}
+	require.ErrorIs(t, stakerdb.VerifySnapshot(dir, &outside), stakerdb.ErrSnapshotCorrupted)
+
+	// tampered file is detected
+	require.NoError(t, os.WriteFile(filepath.Join(dir, stakerdb.SnapshotDescriptorsFile), []byte(`{"x":1}`), 0600))
+	require.ErrorIs(t, stakerdb.VerifySnapshot(dir, manifest), stakerdb.ErrSnapshotCorrupted)
+}
+
+func TestParamsArchive(t *testing.T) {
+	t.Parallel()
+	s := MakeTestStore(t)
+
+	v0 := &stakerdb.ArchivedParams{
+		Version:             0,
+		BtcActivationHeight: 10,
+		Params:              []byte(`{"unbonding_time":100}`),
+		ArchivedAt:          time.Unix(1000, 0).UTC(),
+	}
+	v2 := &stakerdb.ArchivedParams{
+		Version:             2,
+		BtcActivationHeight: 200,
+		Params:              []byte(`{"unbonding_time":300}`),
+		ArchivedAt:          time.Unix(1000, 0).UTC(),
+	}
+
+	for _, p := range []*stakerdb.ArchivedParams{v2, v0} {
+		stored, err := s.ArchiveParams(p)
+		require.NoError(t, err)
+		require.True(t, stored)
+	}
+
+	// archived version is immutable
+	changed := *v2
+	changed.Params = []byte(`{"unbonding_time":1}`)
+	stored, err := s.ArchiveParams(&changed)
+	require.NoError(t, err)
+	require.False(t, stored)
+
+	archived, err := s.GetArchivedParams(2)
+	require.NoError(t, err)
+	require.Equal(t, v2, archived)
+
+	_, err = s.GetArchivedParams(1)
+	require.ErrorIs(t, err, stakerdb.ErrParamsNotArchived)
+
+	archived, err = s.GetArchivedParamsByBtcHeight(250)
+	require.NoError(t, err)
+	require.Equal(t, v2, archived)
+
+	archived, err = s.GetArchivedParamsByBtcHeight(200)
+	require.NoError(t, err)
+	require.Equal(t, v2, archived)
+
+	// version 1 could apply at the height, but is not archived
+	_, err = s.GetArchivedParamsByBtcHeight(150)
+	require.ErrorIs(t, err, stakerdb.ErrParamsNotArchived)
+
+	_, err = s.GetArchivedParamsByBtcHeight(5)
+	require.ErrorIs(t, err, stakerdb.ErrParamsNotArchived)
+
+	v1 := &stakerdb.ArchivedParams{
+		Version:             1,
+		BtcActivationHeight: 100,
+		Params:              []byte(`{"unbonding_time":200}`),
+		ArchivedAt:          time.Unix(1000, 0).UTC(),
+	}
+	_, err = s.ArchiveParams(v1)
+	require.NoError(t, err)
+
+	archived, err = s.GetArchivedParamsByBtcHeight(150)
+	require.NoError(t, err)
+	require.Equal(t, v1, archived)
+
+	archived, err = s.GetArchivedParamsByBtcHeight(50)
+	require.NoError(t, err)
+	require.Equal(t, v0, archived)
+
+	all, err := s.ListArchivedParams()
+	require.NoError(t, err)
+	require.Equal(t, []*stakerdb.ArchivedParams{v0, v1, v2}, all)
+}
+
+func TestUnbondingSignatures(t *testing.T) {
+	t.Parallel()
+	r := rand.New(rand.NewSource(time.Now().UnixNano()))
+	s := MakeTestStore(t)
+
+	storedTx := genStoredTransaction(t, r)
+	txHash := storedTx.StakingTx.TxHash()
+
+	sigs := &stakerdb.UnbondingSignatures{
+		ParamsVersion: 3,
+		Quorum:        2,
+		Committee:     [][]byte{{1}, {3}, {5}},
+		Signatures: []stakerdb.CovenantSignature{
+			{CovenantPk: []byte{1}, Signature: []byte{2}},
+			{CovenantPk: []byte{3}, Signature: []byte{4}},
+		},
+		ReceivedAt: time.Unix(1000, 0).UTC(),
+	}
+
+	require.ErrorIs(t, s.SetTxUnbondingSignaturesReceived(&txHash, sigs), stakerdb.ErrTransactionNotFound)
+
+	stakerAddr, err := btcutil.DecodeAddress(storedTx.StakerAddress, &chaincfg.MainNetParams)
+	require.NoError(t, err)
+	require.NoError(t, s.AddTransactionSentToBabylon(storedTx.StakingTx, stakerAddr))
+
+	stored, err := s.GetTxUnbondingSignatures(&txHash)
+	require.NoError(t, err)
+	require.Nil(t, stored)
+
+	// update below quorum is rejected
+	belowQuorum := *sigs
+	belowQuorum.Signatures = sigs.Signatures[:1]
+	require.ErrorIs(t, s.SetTxUnbondingSignaturesReceived(&txHash, &belowQuorum), stakerdb.ErrCovenantQuorumNotMet)
+
+	// signatures must be made by distinct committee members
+	duplicate := *sigs
+	duplicate.Signatures = []stakerdb.CovenantSignature{sigs.Signatures[0], sigs.Signatures[0]}
+	require.ErrorIs(t, s.SetTxUnbondingSignaturesReceived(&txHash, &duplicate), stakerdb.ErrDuplicateCovenantSignature)
+
+	outsider := *sigs
+	outsider.Signatures = []stakerdb.CovenantSignature{sigs.Signatures[0], {CovenantPk: []byte{9}, Signature: []byte{9}}}
+	require.ErrorIs(t, s.SetTxUnbondingSignaturesReceived(&txHash, &outsider), stakerdb.ErrCovenantNotInCommittee)
+
+	stored, err = s.GetTxUnbondingSignatures(&txHash)
+	require.NoError(t, err)
+	require.Nil(t, stored)
+
+	require.NoError(t, s.SetTxUnbondingSignaturesReceived(&txHash, sigs))
+
+	stored, err = s.GetTxUnbondingSignatures(&txHash)
+	require.NoError(t, err)
+	require.Equal(t, sigs, stored)
+
+	changes, err := s.QueryChanges(0, 100)
+	require.NoError(t, err)
+	require.Equal(t, stakerdb.ChangeUnbondingSignaturesReceived, changes[len(changes)-1].Kind)
+	require.Equal(t, "2/2", changes[len(changes)-1].Detail)
+
+	require.NoError(t, s.DeleteTransactionSentToBabylon(&txHash))
+	stored, err = s.GetTxUnbondingSignatures(&txHash)
+	require.NoError(t, err)
+	require.Nil(t, stored)
+}
+
+func TestRecordDelegationStuck(t *testing.T) {
+	t.Parallel()
+	s := MakeTestStore(t)
+
+	hash := chainhash.Hash{2}
+	require.NoError(t, s.RecordDelegationStuck(&hash, true, "no quorum of covenant signatures for 24h0m0s"))
+	require.NoError(t, s.RecordDelegationStuck(&hash, false, ""))
+
+	changes, err := s.QueryChanges(0, 10)
+	require.NoError(t, err)
+	require.Len(t, changes, 2)
+	require.Equal(t, stakerdb.ChangeDelegationStuck, changes[0].Kind)
+	require.Equal(t, hash, changes[0].StakingTxHash)
+	require.Equal(t, "no quorum of covenant signatures for 24h0m0s", changes[0].Detail)
+	require.Equal(t, stakerdb.ChangeDelegationUnstuck, changes[1].Kind)
+}
+
+func TestUnbondRequests(t *testing.T) {
+	t.Parallel()
+	r := rand.New(rand.NewSource(time.Now().UnixNano()))
+	s := MakeTestStore(t)
+
+	storedTx := genStoredTransaction(t, r)
+	txHash := storedTx.StakingTx.TxHash()
+	requestedAt := time.Unix(1000, 0)
+
+	require.ErrorIs(t, s.SetUnbondRequested(&txHash, requestedAt), stakerdb.ErrTransactionNotFound)
+
+	stakerAddr, err := btcutil.DecodeAddress(storedTx.StakerAddress, &chaincfg.MainNetParams)
+	require.NoError(t, err)
+	require.NoError(t, s.AddTransactionSentToBabylon(storedTx.StakingTx, stakerAddr))
+
+	require.NoError(t, s.SetUnbondRequested(&txHash, requestedAt))
+	// time of the first request is kept
+	require.NoError(t, s.SetUnbondRequested(&txHash, requestedAt.Add(time.Hour)))
+
+	requests, err := s.ListUnbondRequests()
+	require.NoError(t, err)
+	require.Len(t, requests, 1)
+	require.True(t, requestedAt.Equal(requests[txHash]))
+
+	require.NoError(t, s.ClearUnbondRequest(&txHash))
+	requests, err = s.ListUnbondRequests()
+	require.NoError(t, err)
+	require.Empty(t, requests)
+
+	require.NoError(t, s.SetUnbondRequested(&txHash, requestedAt))
+	require.NoError(t, s.DeleteTransactionSentToBabylon(&txHash))
+	requests, err = s.ListUnbondRequests()
+	require.NoError(t, err)
+	require.Empty(t, requests)
+}
+
+func TestRecordRemediation(t *testing.T) {
+	t.Parallel()
+	s := MakeTestStore(t)
+
+	hash := chainhash.Hash{3}
+	require.NoError(t, s.RecordRemediation(&hash, "not_active", errors.New("header not known")))
+	require.NoError(t, s.RecordRemediation(&hash, "not_active", nil))
+
+	changes, err := s.QueryChanges(0, 10)
+	require.NoError(t, err)
+	require.Len(t, changes, 2)
+	require.Equal(t, stakerdb.ChangeRemediationFailed, changes[0].Kind)
+	require.Equal(t, "not_active: header not known", changes[0].Detail)
+	require.Equal(t, stakerdb.ChangeRemediationAttempted, changes[1].Kind)
+	require.Equal(t, "not_active", changes[1].Detail)
+}
+
+func TestRecordChainSafetyChanges(t *testing.T) {
+	t.Parallel()
+	s := MakeTestStore(t)
+
+	require.NoError(t, s.RecordChainSafetyChange(true, "babylon did not produce a block after height 10 for 6m0s"))
+	require.NoError(t, s.RecordChainSafetyOverride(true))
+	require.NoError(t, s.RecordChainSafetyChange(false, ""))
+
+	changes, err := s.QueryChanges(0, 10)
+	require.NoError(t, err)
+	require.Len(t, changes, 3)
+	require.Equal(t, stakerdb.ChangeOperationsPaused, changes[0].Kind)
+	require.Equal(t, "babylon did not produce a block after height 10 for 6m0s", changes[0].Detail)
+	require.Equal(t, stakerdb.ChangeChainSafetyOverrideSet, changes[1].Kind)
+	require.Equal(t, "true", changes[1].Detail)
+	require.Equal(t, stakerdb.ChangeOperationsResumed, changes[2].Kind)
+}
+
+func TestRecordWorkerPanic(t *testing.T) {
+	t.Parallel()
+	s := MakeTestStore(t)
+
+	require.NoError(t, s.RecordWorkerPanic("chain_safety", "runtime error: index out of range [1] with length 1"))
+
+	changes, err := s.QueryChanges(0, 10)
+	require.NoError(t, err)
+	require.Len(t, changes, 1)
+	require.Equal(t, stakerdb.ChangeWorkerPanicked, changes[0].Kind)
+	require.Equal(t, "worker_panicked", changes[0].Kind.String())
+	require.Equal(t, "chain_safety: runtime error: index out of range [1] with length 1", changes[0].Detail)
+}
+
+func TestBabylonTxs(t *testing.T) {
+	t.Parallel()
+	r := rand.New(rand.NewSource(time.Now().UnixNano()))
+	s := MakeTestStore(t)
+
+	storedTx := genStoredTransaction(t, r)
+	txHash := storedTx.StakingTx.TxHash()
+	sentAt := time.Unix(1000, 0).UTC()
+
+	err := s.RecordBabylonTx(&txHash, &stakerdb.BabylonTx{Kind: "create_delegation", TxHash: "AA", Height: 10, SentAt: sentAt})
+	require.ErrorIs(t, err, stakerdb.ErrTransactionNotFound)
+
+	stakerAddr, err := btcutil.DecodeAddress(storedTx.StakerAddress, &chaincfg.MainNetParams)
+	require.NoError(t, err)
+	require.NoError(t, s.AddTransactionSentToBabylon(storedTx.StakingTx, stakerAddr))
+
+	txs, err := s.GetBabylonTxs(&txHash)
+	require.NoError(t, err)
+	require.Nil(t, txs)
+
+	msg := []byte{0x0a, 0x02, 0xaa, 0xbb}
+	require.NoError(t, s.RecordBabylonTx(&txHash, &stakerdb.BabylonTx{
+		Kind:       "create_delegation",
+		TxHash:     "AA",
+		Height:     10,
+		SentAt:     sentAt,
+		MsgTypeURL: "/babylon.btcstaking.v1.MsgCreateBTCDelegation",
+		Msg:        msg,
+	}))
+	require.NoError(t, s.RecordBabylonTx(&txHash, &stakerdb.BabylonTx{Kind: "inclusion_proof", TxHash: "BB", Height: 20, SentAt: sentAt.Add(time.Hour)}))
+
+	txs, err = s.GetBabylonTxs(&txHash)
+	require.NoError(t, err)
+	require.Len(t, txs, 2)
+	require.Equal(t, "create_delegation", txs[0].Kind)
+	require.Equal(t, "AA", txs[0].TxHash)
+	require.Equal(t, int64(10), txs[0].Height)
+	require.True(t, sentAt.Equal(txs[0].SentAt))
+	require.Equal(t, "/babylon.btcstaking.v1.MsgCreateBTCDelegation", txs[0].MsgTypeURL)
+	require.Equal(t, msg, txs[0].Msg)
+	require.Equal(t, "inclusion_proof", txs[1].Kind)
+	require.Empty(t, txs[1].Msg)
+
+	changes, err := s.ChangesOf(&txHash)
+	require.NoError(t, err)
+	require.Len(t, changes, 3)
+	require.Equal(t, stakerdb.ChangeTransactionAdded, changes[0].Kind)
+	require.Equal(t, stakerdb.ChangeBabylonTxRecorded, changes[1].Kind)
+	require.Equal(t, "create_delegation: AA", changes[1].Detail)
+	require.Equal(t, "inclusion_proof: BB", changes[2].Detail)
+
+	// changes of other transactions are not returned
+	require.NoError(t, s.RecordWorkerPanic("chain_safety", "boom"))
+	changes, err = s.ChangesOf(&txHash)
+	require.NoError(t, err)
+	require.Len(t, changes, 3)
+}
+
+func TestImportTransaction(t *testing.T) {
+	t.Parallel()
+	r := rand.New(rand.NewSource(time.Now().UnixNano()))
+	s := MakeTestStore(t)
+
+	storedTx := genStoredTransaction(t, r)
+	txHash := storedTx.StakingTx.TxHash()
+	stakerAddr, err := btcutil.DecodeAddress(storedTx.StakerAddress, &chaincfg.MainNetParams)
+	require.NoError(t, err)
+	seenAt := time.Unix(1000, 0).UTC()
+
+	imported := &stakerdb.ImportedTransaction{
+		StakingTx:      storedTx.StakingTx,
+		StakerAddress:  stakerAddr,
+		Tenant:         "desk-a",
+		Labels:         map[string]string{"client": "acme"},
+		CreationHeight: 100,
+		StakingFee:     1500,
+		Timing:         stakerdb.CovenantQuorumTiming{RegisteredAt: seenAt},
+		StatusHistory:  []stakerdb.StatusSnapshot{{State: "ACTIVE", FirstSeenAt: seenAt, LastSeenAt: seenAt, BtcHeight: 110}},
+		BabylonTxs:     []stakerdb.BabylonTx{{Kind: "create_delegation", TxHash: "AA", Height: 10, SentAt: seenAt}},
+	}
+	require.NoError(t, s.ImportTransaction(imported))
+	require.ErrorIs(t, s.ImportTransaction(imported), stakerdb.ErrDuplicateTransaction)
+
+	tx, err := s.GetTransaction(&txHash)
+	require.NoError(t, err)
+	require.Equal(t, storedTx.StakerAddress, tx.StakerAddress)
+
+	tenant, err := s.GetTransactionTenant(&txHash)
+	require.NoError(t, err)
+	require.Equal(t, "desk-a", tenant)
+
+	labels, err := s.GetTransactionLabels(&txHash)
+	require.NoError(t, err)
+	require.Equal(t, imported.Labels, labels)
+
+	height, found, err := s.GetTransactionCreationHeight(&txHash)
+	require.NoError(t, err)
+	require.True(t, found)
+	require.Equal(t, uint32(100), height)
+
+	fees, err := s.GetDelegationFees(&txHash)
+	require.NoError(t, err)
+	require.Equal(t, btcutil.Amount(1500), fees.StakingFee)
+
+	timing, err := s.GetCovenantQuorumTiming(&txHash)
+	require.NoError(t, err)
+	require.True(t, seenAt.Equal(timing.RegisteredAt))
+	require.True(t, timing.QuorumReachedAt.IsZero())
+
+	history, err := s.GetStatusHistory(&txHash)
+	require.NoError(t, err)
+	require.Len(t, history, 1)
+	require.Equal(t, "ACTIVE", history[0].State)
+
+	babylonTxs, err := s.GetBabylonTxs(&txHash)
+	require.NoError(t, err)
+	require.Len(t, babylonTxs, 1)
+
+	changes, err := s.ChangesOf(&txHash)
+	require.NoError(t, err)
+	require.Len(t, changes, 1)
+	require.Equal(t, stakerdb.ChangeTransactionImported, changes[0].Kind)
+}
+
+func TestReplicateTransaction(t *testing.T) {
+	t.Parallel()
+	r := rand.New(rand.NewSource(time.Now().UnixNano()))
+	s := MakeTestStore(t)
+
+	cursor, err := s.ReplicaCursor()
+	require.NoError(t, err)
+	require.Zero(t, cursor)
+
+	storedTx := genStoredTransaction(t, r)
+	txHash := storedTx.StakingTx.TxHash()
+	stakerAddr, err := btcutil.DecodeAddress(storedTx.StakerAddress, &chaincfg.MainNetParams)
+	require.NoError(t, err)
+
+	imported := &stakerdb.ImportedTransaction{
+		StakingTx:     storedTx.StakingTx,
+		StakerAddress: stakerAddr,
+		Labels:        map[string]string{"client": "acme"},
+		StakingFee:    1500,
+	}
+	require.NoError(t, s.ReplicateTransaction(imported, nil, 5))
+
+	// replicating again replaces previous data instead of failing as duplicate
+	failedAt := time.Unix(2000, 0)
+	imported.Labels = nil
+	imported.StakingFee = 0
+	require.NoError(t, s.ReplicateTransaction(imported, &stakerdb.TransactionFailure{
+		StakingTxHash: txHash,
+		Reason:        "double spent",
+		Timestamp:     failedAt,
+	}, 7))
+
+	all, err := s.GetAllStoredTransactions()
+	require.NoError(t, err)
+	require.Len(t, all, 1)
+
+	labels, err := s.GetTransactionLabels(&txHash)
+	require.NoError(t, err)
+	require.Empty(t, labels)
+
+	fees, err := s.GetDelegationFees(&txHash)
+	require.NoError(t, err)
+	require.Zero(t, fees.Total())
+
+	failure, err := s.GetTransactionFailure(&txHash)
+	require.NoError(t, err)
+	require.NotNil(t, failure)
+	require.Equal(t, "double spent", failure.Reason)
+	require.True(t, failedAt.Equal(failure.Timestamp))
+
+	changes, err := s.ChangesOf(&txHash)
+	require.NoError(t, err)
+	require.Len(t, changes, 2)
+	require.Equal(t, stakerdb.ChangeTransactionReplicated, changes[1].Kind)
+	require.Equal(t, "7", changes[1].Detail)
+
+	require.NoError(t, s.SetReplicaCursor(7))
+	cursor, err = s.ReplicaCursor()
+	require.NoError(t, err)
+	require.Equal(t, uint64(7), cursor)
+
+	require.NoError(t, s.RemoveReplicatedTransaction(&txHash))
+	_, err = s.GetTransaction(&txHash)
+	require.ErrorIs(t, err, stakerdb.ErrTransactionNotFound)
+
+	failure, err = s.GetTransactionFailure(&txHash)
+	require.NoError(t, err)
+	require.Nil(t, failure)
+
+	// removing delegation unknown to replica is a no-op
+	require.NoError(t, s.RemoveReplicatedTransaction(&txHash))
+}
+
+func TestMergeFrom(t *testing.T) {
+	t.Parallel()
+	r := rand.New(rand.NewSource(time.Now().UnixNano()))
+	dst := MakeTestStore(t)
+	src := MakeTestStore(t)
+
+	importTx := func(s *stakerdb.TrackedTransactionStore, storedTx *stakerdb.StoredTransaction, tenant string) {
+		stakerAddr, err := btcutil.DecodeAddress(storedTx.StakerAddress, &chaincfg.MainNetParams)
+		require.NoError(t, err)
+		require.NoError(t, s.ImportTransaction(&stakerdb.ImportedTransaction{
+			StakingTx:     storedTx.StakingTx,
+			StakerAddress: stakerAddr,
+			Tenant:        tenant,
+			StakingFee:    1000,
+		}))
+	}
+
+	shared := genStoredTransaction(t, r)
+	importTx(dst, shared, "")
+	importTx(dst, genStoredTransaction(t, r), "")
+
+	importTx(src, shared, "")
+	merged := genNStoredTransactions(t, r, 2)
+	for _, tx := range merged {
+		importTx(src, tx, "desk-b")
+	}
+
+	result, err := dst.MergeFrom(src, true)
+	require.NoError(t, err)
+	require.Len(t, result.Merged, 2)
+	require.Len(t, result.AlreadyTracked, 1)
+
+	all, err := dst.GetAllStoredTransactions()
+	require.NoError(t, err)
+	require.Len(t, all, 2)
+
+	result, err = dst.MergeFrom(src, false)
+	require.NoError(t, err)
+	require.Len(t, result.Merged, 2)
+	require.Equal(t, shared.StakingTx.TxHash(), result.AlreadyTracked[0])
+	require.Empty(t, result.Conflicting)
+
+	for i, storedTx := range merged {
+		txHash := storedTx.StakingTx.TxHash()
+		tx, err := dst.GetTransaction(&txHash)
+		require.NoError(t, err)
+		require.Equal(t, uint64(i+3), tx.StoredTransactionIdx)
+
+		tenant, err := dst.GetTransactionTenant(&txHash)
+		require.NoError(t, err)
+		require.Equal(t, "desk-b", tenant)
+
+		fees, err := dst.GetDelegationFees(&txHash)
+		require.NoError(t, err)
+		require.Equal(t, btcutil.Amount(1000), fees.StakingFee)
+	}
+
+	health, err := dst.CheckIndexHealth()
+	require.NoError(t, err)
+	require.True(t, health.Healthy())
+	require.Equal(t, uint64(4), health.TrackedTransactions)
+
+	result, err = dst.MergeFrom(src, false)
+	require.NoError(t, err)
+	require.Empty(t, result.Merged)
+	require.Len(t, result.AlreadyTracked, 3)
+}
+
+func TestMuSig2KeysAndSessions(t *testing.T) {
+	t.Parallel()
+	s := MakeTestStore(t)
+
+	key := &stakerdb.MuSig2Key{
+		Name:         "custody",
+		PubKeys:      []string{"02aa", "03bb"},
+		AggregateKey: "cc",
+		CreatedAt:    time.Unix(1000, 0).UTC(),
+	}
+	require.NoError(t, s.PutMuSig2Key(key))
+	require.ErrorIs(t, s.PutMuSig2Key(key), stakerdb.ErrMuSig2KeyExists)
+
+	_, err := s.GetMuSig2Key("other")
+	require.ErrorIs(t, err, stakerdb.ErrMuSig2KeyNotFound)
+
+	stored, err := s.GetMuSig2Key("custody")
+	require.NoError(t, err)
+	require.Equal(t, key, stored)
+
+	keys, err := s.ListMuSig2Keys()
+	require.NoError(t, err)
+	require.Len(t, keys, 1)
+
+	stakingTxHash := chainhash.Hash{1}
+	id, err := s.AddMuSig2Session(&stakerdb.MuSig2Session{
+		KeyName:       "custody",
+		Kind:          "unbonding",
+		StakingTxHash: stakingTxHash.String(),
+		Message:       "dd",
+		Nonces:        map[string]string{},
+		PartialSigs:   map[string]string{},
+		CreatedAt:     time.Unix(2000, 0).UTC(),
+	})
+	require.NoError(t, err)
+
+	_, err = s.GetMuSig2Session(id + 1)
+	require.ErrorIs(t, err, stakerdb.ErrMuSig2SessionNotFound)
+
+	// failed update does not modify the session
+	_, err = s.UpdateMuSig2Session(id, func(session *stakerdb.MuSig2Session) error {
+		session.Nonces["02aa"] = "ee"
+		return errors.New("rejected")
+	})
+	require.Error(t, err)
+
+	session, err := s.GetMuSig2Session(id)
+	require.NoError(t, err)
+	require.Empty(t, session.Nonces)
+
+	updated, err := s.UpdateMuSig2Session(id, func(session *stakerdb.MuSig2Session) error {
+		session.Nonces["02aa"] = "ee"
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, id, updated.ID)
+	require.Equal(t, "ee", updated.Nonces["02aa"])
+
+	_, err = s.UpdateMuSig2Session(id, func(session *stakerdb.MuSig2Session) error {
+		session.Signature = "ff"
+		return nil
+	})
+	require.NoError(t, err)
+
+	sessions, err := s.ListMuSig2Sessions()
+	require.NoError(t, err)
+	require.Len(t, sessions, 1)
+	require.Equal(t, id, sessions[0].ID)
+	require.Equal(t, "ff", sessions[0].Signature)
+
+	changes, err := s.ChangesOf(&stakingTxHash)
+	require.NoError(t, err)
+	require.Len(t, changes, 2)
+	require.Equal(t, stakerdb.ChangeMuSig2SessionCreated, changes[0].Kind)
+	require.Equal(t, stakerdb.ChangeMuSig2SessionCompleted, changes[1].Kind)
+}
+
+func TestFailureNotes(t *testing.T) {
+	t.Parallel()
+	r := rand.New(rand.NewSource(time.Now().UnixNano()))
+	s := MakeTestStore(t)
+
+	storedTx := genStoredTransaction(t, r)
+	txHash := storedTx.StakingTx.TxHash()
+	note := &stakerdb.FailureNote{
+		Source:      stakerdb.FailureSourceBabylon,
+		Codespace:   "btcstaking",
+		Code:        1106,
+		Reason:      "the BTC staking tx is already used",
+		Remediation: "check delegation status",
+		RecordedAt:  time.Unix(1000, 0).UTC(),
+	}
+
+	require.ErrorIs(t, s.SetFailureNote(&txHash, note), stakerdb.ErrTransactionNotFound)
+
+	stakerAddr, err := btcutil.DecodeAddress(storedTx.StakerAddress, &chaincfg.MainNetParams)
+	require.NoError(t, err)
+	require.NoError(t, s.AddTransactionSentToBabylon(storedTx.StakingTx, stakerAddr))
+
+	stored, err := s.GetFailureNote(&txHash)
+	require.NoError(t, err)
+	require.Nil(t, stored)
+
+	require.NoError(t, s.SetFailureNote(&txHash, note))
+	stored, err = s.GetFailureNote(&txHash)
+	require.NoError(t, err)
+	require.Equal(t, note, stored)
+
+	require.NoError(t, s.ClearFailureNote(&txHash))
+	// clearing missing note does not record a change
+	require.NoError(t, s.ClearFailureNote(&txHash))
+	stored, err = s.GetFailureNote(&txHash)
+	require.NoError(t, err)
+	require.Nil(t, stored)
+
+	changes, err := s.QueryChanges(0, 10)
+	require.NoError(t, err)
+	require.Equal(t, stakerdb.ChangeFailureNoteSet, changes[len(changes)-2].Kind)
+	require.Equal(t, "babylon: the BTC staking tx is already used", changes[len(changes)-2].Detail)
+	require.Equal(t, stakerdb.ChangeFailureNoteCleared, changes[len(changes)-1].Kind)
+
+	require.NoError(t, s.SetFailureNote(&txHash, note))
+	require.NoError(t, s.DeleteTransactionSentToBabylon(&txHash))
+	stored, err = s.GetFailureNote(&txHash)
+	require.NoError(t, err)
+	require.Nil(t, stored)
+}
+
+func TestAutoRenew(t *testing.T) {
+	t.Parallel()
+	r := rand.New(rand.NewSource(time.Now().UnixNano()))
+	s := MakeTestStore(t)
+
+	storedTx := genStoredTransaction(t, r)
+	txHash := storedTx.StakingTx.TxHash()
+
+	require.ErrorIs(t, s.SetAutoRenew(&txHash, 100), stakerdb.ErrTransactionNotFound)
+
+	stakerAddr, err := btcutil.DecodeAddress(storedTx.StakerAddress, &chaincfg.MainNetParams)
+	require.NoError(t, err)
+	require.NoError(t, s.AddTransactionSentToBabylon(storedTx.StakingTx, stakerAddr))
+
+	_, found, err := s.GetAutoRenew(&txHash)
+	require.NoError(t, err)
+	require.False(t, found)
+
+	require.NoError(t, s.SetAutoRenew(&txHash, 100))
+	stakingTime, found, err := s.GetAutoRenew(&txHash)
+	require.NoError(t, err)
+	require.True(t, found)
+	require.Equal(t, uint16(100), stakingTime)
+
+	// zero staking time keeps staking time of the delegation
+	require.NoError(t, s.SetAutoRenew(&txHash, 0))
+	renewals, err := s.ListAutoRenew()
+	require.NoError(t, err)
+	require.Equal(t, map[chainhash.Hash]uint16{txHash: 0}, renewals)
+
+	cleared, err := s.ClearAutoRenew(&txHash)
+	require.NoError(t, err)
+	require.True(t, cleared)
+	cleared, err = s.ClearAutoRenew(&txHash)
+	require.NoError(t, err)
+	require.False(t, cleared)
+
+	changes, err := s.QueryChanges(0, 10)
+	require.NoError(t, err)
+	require.Equal(t, stakerdb.ChangeAutoRenewSet, changes[len(changes)-2].Kind)
+	require.Equal(t, "0", changes[len(changes)-2].Detail)
+	require.Equal(t, stakerdb.ChangeAutoRenewCleared, changes[len(changes)-1].Kind)
+
+	require.NoError(t, s.SetAutoRenew(&txHash, 100))
+	require.NoError(t, s.DeleteTransactionSentToBabylon(&txHash))
+	renewals, err = s.ListAutoRenew()
+	require.NoError(t, err)
+	require.Empty(t, renewals)
+}
+
+func TestRenewalJobs(t *testing.T) {
+	t.Parallel()
+	r := rand.New(rand.NewSource(time.Now().UnixNano()))
+	s := MakeTestStore(t)
+
+	storedTx := genStoredTransaction(t, r)
+	txHash := storedTx.StakingTx.TxHash()
+	stakerAddr, err := btcutil.DecodeAddress(storedTx.StakerAddress, &chaincfg.MainNetParams)
+	require.NoError(t, err)
+	require.NoError(t, s.AddTransactionSentToBabylon(storedTx.StakingTx, stakerAddr))
+
+	now := time.Unix(1000, 0).UTC()
+
+	// job is created only for delegation with enabled auto renew
+	_, created, err := s.CreateRenewalJob(&txHash, now)
+	require.NoError(t, err)
+	require.False(t, created)
+
+	require.NoError(t, s.SetAutoRenew(&txHash, 100))
+	job, created, err := s.CreateRenewalJob(&txHash, now)
+	require.NoError(t, err)
+	require.True(t, created)
+	require.Equal(t, stakerdb.RenewalJobPending, job.State)
+	require.Equal(t, uint16(100), job.StakingTime)
+
+	// auto renew is disabled once job is created
+	_, found, err := s.GetAutoRenew(&txHash)
+	require.NoError(t, err)
+	require.False(t, found)
+	_, created, err = s.CreateRenewalJob(&txHash, now)
+	require.NoError(t, err)
+	require.False(t, created)
+
+	job.State = stakerdb.RenewalJobDone
+	job.Attempts = 1
+	job.NewStakingTxHash = "new"
+	require.NoError(t, s.UpdateRenewalJob(job))
+
+	jobs, err := s.ListRenewalJobs()
+	require.NoError(t, err)
+	require.Equal(t, []stakerdb.RenewalJob{*job}, jobs)
+
+	changes, err := s.QueryChanges(0, 10)
+	require.NoError(t, err)
+	require.Equal(t, stakerdb.ChangeRenewalJobUpdated, changes[len(changes)-1].Kind)
+	require.Equal(t, stakerdb.RenewalJobDone, changes[len(changes)-1].Detail)
+
+	require.NoError(t, s.DeleteTransactionSentToBabylon(&txHash))
+	jobs, err = s.ListRenewalJobs()
+	require.NoError(t, err)
+	require.Empty(t, jobs)
+	require.ErrorIs(t, s.UpdateRenewalJob(job), stakerdb.ErrTransactionNotFound)
+}
+
+func TestNetworkBinding(t *testing.T) {
+	t.Parallel()
+	s := MakeTestStore(t)
+
+	network, err := s.Network()
+	require.NoError(t, err)
+	require.Nil(t, network)
+
+	require.NoError(t, s.BindNetwork(&chaincfg.TestNet3Params))
+	// binding to the same network again is a no-op
+	require.NoError(t, s.BindNetwork(&chaincfg.TestNet3Params))
+
+	network, err = s.Network()
+	require.NoError(t, err)
+	require.Equal(t, &stakerdb.Network{Name: chaincfg.TestNet3Params.Name, Net: chaincfg.TestNet3Params.Net}, network)
+
+	err = s.BindNetwork(&chaincfg.MainNetParams)
+	require.ErrorIs(t, err, stakerdb.ErrNetworkMismatch)
+
+	// custom signet has the same name, but different magic
+	customSigNet := chaincfg.CustomSignetParams([]byte{0x51}, nil)
+	signetStore := MakeTestStore(t)
+	require.NoError(t, signetStore.BindNetwork(&chaincfg.SigNetParams))
+	require.ErrorIs(t, signetStore.BindNetwork(&customSigNet), stakerdb.ErrNetworkMismatch)
+
+	// databases of different networks can't be merged
+	_, err = signetStore.MergeFrom(s, true)
+	require.ErrorIs(t, err, stakerdb.ErrNetworkMismatch)
+}
+
+func TestDelegationFinalityProviders(t *testing.T) {
+	t.Parallel()
+	r := rand.New(rand.NewSource(time.Now().UnixNano()))
+	s := MakeTestStore(t)
+
+	fpPk := datagen.GenRandomByteArray(r, 32)
+
+	storedTx := genStoredTransaction(t, r)
+	txHash := storedTx.StakingTx.TxHash()
+
+	require.ErrorIs(t, s.SetDelegationFinalityProviders(&txHash, [][]byte{fpPk}, 1000), stakerdb.ErrTransactionNotFound)
+
+	stakerAddr, err := btcutil.DecodeAddress(storedTx.StakerAddress, &chaincfg.MainNetParams)
+	require.NoError(t, err)
+	require.NoError(t, s.AddTransactionSentToBabylon(storedTx.StakingTx, stakerAddr))
+
+	require.Error(t, s.SetDelegationFinalityProviders(&txHash, nil, 1000))
+	require.Error(t, s.SetDelegationFinalityProviders(&txHash, [][]byte{fpPk[:31]}, 1000))
+	require.NoError(t, s.SetDelegationFinalityProviders(&txHash, [][]byte{fpPk}, 1000))
+
+	failedTx := genStoredTransaction(t, r)
+	failedTxHash := failedTx.StakingTx.TxHash()
+	require.NoError(t, s.AddTransactionSentToBabylon(failedTx.StakingTx, stakerAddr))
+	require.NoError(t, s.SetDelegationFinalityProviders(&failedTxHash, [][]byte{fpPk}, 2000))
+	require.NoError(t, s.MarkTransactionFailed(&failedTxHash, "double spent"))
+
+	// failed delegations do not count
+	delegations, err := s.ListDelegationFinalityProviders()
+	require.NoError(t, err)
+	require.Len(t, delegations, 1)
+	require.Equal(t, txHash, delegations[0].StakingTxHash)
+	require.Equal(t, [][]byte{fpPk}, delegations[0].FpBtcPks)
+	require.Equal(t, btcutil.Amount(1000), delegations[0].StakingAmount)
+
+	changes, err := s.ChangesOf(&txHash)
+	require.NoError(t, err)
+	require.Equal(t, stakerdb.ChangeFinalityProvidersSet, changes[len(changes)-1].Kind)
+}
+
+func updateRawRecord(t *testing.T, s *stakerdb.TrackedTransactionStore, bucket string, key uint64, update func([]byte) []byte) {
+	err := kvdb.Batch(getDBFromStore(s), func(tx kvdb.RwTx) error {
+		b := tx.ReadWriteBucket([]byte(bucket))
+		k := uint64KeyToBytes(key)
+		return b.Put(k, update(append([]byte(nil), b.Get(k)...)))
+	})
+	require.NoError(t, err)
+}
+
+func TestRecordIntegrity(t *testing.T) {
+	t.Parallel()
+	r := rand.New(rand.NewSource(time.Now().UnixNano()))
+	s := MakeTestStore(t)
+
+	txs := genNStoredTransactions(t, r, 2)
+	for _, storedTx := range txs {
+		stakerAddr, err := btcutil.DecodeAddress(storedTx.StakerAddress, &chaincfg.MainNetParams)
+		require.NoError(t, err)
+		require.NoError(t, s.AddTransactionSentToBabylon(storedTx.StakingTx, stakerAddr))
+	}
+	corruptedHash := txs[0].StakingTx.TxHash()
+	validHash := txs[1].StakingTx.TxHash()
+
+	_, err := s.GetTransaction(&corruptedHash)
+	require.NoError(t, err)
+
+	// flip a bit of the first record, keeping it decodable
+	updateRawRecord(t, s, "transactions", 1, func(v []byte) []byte {
+		v[len(v)-1] ^= 1
+		return v
+	})
+
+	health, err := s.CheckIndexHealth()
+	require.NoError(t, err)
+	require.False(t, health.Healthy())
+	require.Equal(t, []string{"0000000000000001"}, health.IntegrityFailures)
+
+	// corrupted records are not signed
+	key, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+	require.ErrorIs(t, s.SetIntegrityKey(key), stakerdb.ErrIntegrityViolation)
+
+	// corrupted record is quarantined on read
+	_, err = s.GetTransaction(&corruptedHash)
+	require.ErrorIs(t, err, stakerdb.ErrTransactionQuarantined)
+	_, err = s.GetTransaction(&validHash)
+	require.NoError(t, err)
+}
+
+func TestRecordSignatures(t *testing.T) {
+	t.Parallel()
+	r := rand.New(rand.NewSource(time.Now().UnixNano()))
+	s := MakeTestStore(t)
+
+	txs := genNStoredTransactions(t, r, 2)
+	stakerAddr, err := btcutil.DecodeAddress(txs[0].StakerAddress, &chaincfg.MainNetParams)
+	require.NoError(t, err)
+	require.NoError(t, s.AddTransactionSentToBabylon(txs[0].StakingTx, stakerAddr))
+
+	key, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+	require.NoError(t, s.SetIntegrityKey(key))
+	// setting the same key again is a no-op
+	require.NoError(t, s.SetIntegrityKey(key))
+
+	otherKey, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+	require.ErrorIs(t, s.SetIntegrityKey(otherKey), stakerdb.ErrIntegrityKeyMismatch)
+
+	require.NoError(t, s.AddTransactionSentToBabylon(txs[1].StakingTx, stakerAddr))
+
+	stored, err := s.GetAllStoredTransactions()
+	require.NoError(t, err)
+	require.Len(t, stored, 2)
+
+	// signed records can be read without the key, but not written
+	unsigned, err := stakerdb.NewTrackedTransactionStore(getDBFromStore(s))
+	require.NoError(t, err)
+	stored, err = unsigned.GetAllStoredTransactions()
+	require.NoError(t, err)
+	require.Len(t, stored, 2)
+
+	newTx := genStoredTransaction(t, r)
+	err = unsigned.AddTransactionSentToBabylon(newTx.StakingTx, stakerAddr)
+	require.ErrorIs(t, err, stakerdb.ErrIntegrityKeyRequired)
+
+	// checksum recomputed without the key does not pass
+	updateRawRecord(t, s, "txChecksums", 2, func(v []byte) []byte {
+		return v[:32]
+	})
+
+	txHash := txs[1].StakingTx.TxHash()
+	_, err = s.GetTransaction(&txHash)
+	require.ErrorIs(t, err, stakerdb.ErrTransactionQuarantined)
+}
+
+func TestQuarantine(t *testing.T) {
+	t.Parallel()
+	r := rand.New(rand.NewSource(time.Now().UnixNano()))
+	s := MakeTestStore(t)
+
+	txs := genNStoredTransactions(t, r, 3)
+	for _, storedTx := range txs {
+		stakerAddr, err := btcutil.DecodeAddress(storedTx.StakerAddress, &chaincfg.MainNetParams)
+		require.NoError(t, err)
+		require.NoError(t, s.AddTransactionSentToBabylon(storedTx.StakingTx, stakerAddr))
+	}
+
+	// record with valid checksum, which can't be decoded
+	garbage := []byte{0xff, 0xfe, 0xfd}
+	updateRawRecord(t, s, "transactions", 2, func([]byte) []byte {
+		return garbage
+	})
+	updateRawRecord(t, s, "txChecksums", 2, func([]byte) []byte {
+		checksum := sha256.Sum256(append(uint64KeyToBytes(2), garbage...))
+		return checksum[:]
+	})
+
+	// other records are still served
+	result, err := s.QueryStoredTransactions(stakerdb.DefaultStoredTransactionQuery())
+	require.NoError(t, err)
+	require.Len(t, result.Transactions, 2)
+	require.Equal(t, txs[0].StakingTx.TxHash(), result.Transactions[0].StakingTx.TxHash())
+	require.Equal(t, txs[2].StakingTx.TxHash(), result.Transactions[1].StakingTx.TxHash())
+
+	quarantined, err := s.QuarantinedRecords()
+	require.NoError(t, err)
+	require.Len(t, quarantined, 1)
+	require.Equal(t, uint64(2), quarantined[0].TxIdx)
+	require.Equal(t, garbage, quarantined[0].Record)
+	require.NotEmpty(t, quarantined[0].Reason)
+
+	quarantinedHash := txs[1].StakingTx.TxHash()
+	require.Equal(t, &quarantinedHash, quarantined[0].StakingTxHash)
+
+	// quarantined transaction is not tracked anymore
+	_, err = s.GetTransaction(&quarantinedHash)
+	require.ErrorIs(t, err, stakerdb.ErrTransactionNotFound)
+
+	scanned := 0
+	err = s.ScanTrackedTransactions(func(*stakerdb.StoredTransaction) error {
+		scanned++
+		return nil
+	}, func() {
+		scanned = 0
+	})
+	require.NoError(t, err)
+	require.Equal(t, 2, scanned)
+
+	health, err := s.CheckIndexHealth()
+	require.NoError(t, err)
+	require.Equal(t, uint64(1), health.QuarantinedTransactions)
+	require.Equal(t, health.NumTxCounter, health.TrackedTransactions+health.QuarantinedTransactions)
+	require.Empty(t, health.MissingTransactions)
+	// inputs of quarantined transaction stay reserved
+	require.NotEmpty(t, health.DanglingInputs)
+
+	changes, err := s.ChangesOf(&quarantinedHash)
+	require.NoError(t, err)
+	require.Equal(t, stakerdb.ChangeTransactionQuarantined, changes[len(changes)-1].Kind)
+}
+
+func TestSpendTxIndex(t *testing.T) {
+	t.Parallel()
+	r := rand.New(rand.NewSource(time.Now().UnixNano()))
+	s := MakeTestStore(t)
+
+	storedTx := genStoredTransaction(t, r)
+	txHash := storedTx.StakingTx.TxHash()
+	unbondingTx := genStoredTransaction(t, r).StakingTx
+	unbondingTxHash := unbondingTx.TxHash()
+	withdrawalTx := genStoredTransaction(t, r).StakingTx
+	withdrawalTxHash := withdrawalTx.TxHash()
+
+	require.ErrorIs(t, s.IndexSpendTransaction(&txHash, stakerdb.WatchedUnbondingTx, &unbondingTxHash), stakerdb.ErrTransactionNotFound)
+
+	stakerAddr, err := btcutil.DecodeAddress(storedTx.StakerAddress, &chaincfg.MainNetParams)
+	require.NoError(t, err)
+	require.NoError(t, s.AddTransactionSentToBabylon(storedTx.StakingTx, stakerAddr))
+
+	require.Error(t, s.IndexSpendTransaction(&txHash, stakerdb.WatchedStakingTx, &unbondingTxHash))
+	require.NoError(t, s.IndexSpendTransaction(&txHash, stakerdb.WatchedUnbondingTx, &unbondingTxHash))
+
+	// watched withdrawal transactions are indexed when they start to be
+	// watched and stay indexed once confirmed
+	require.NoError(t, s.PutWatchedTransaction(&stakerdb.WatchedTransaction{
+		Tx:            withdrawalTx,
+		StakingTxHash: txHash,
+		Kind:          stakerdb.WatchedWithdrawalTx,
+		BroadcastAt:   time.Unix(1000, 0),
+	}))
+	require.NoError(t, s.DeleteWatchedTransaction(&withdrawalTxHash))
+
+	found, err := s.FindDelegationBySpendTx(&unbondingTxHash)
+	require.NoError(t, err)
+	require.Equal(t, txHash, found.StakingTxHash)
+	require.Equal(t, stakerdb.WatchedUnbondingTx, found.Kind)
+
+	found, err = s.FindDelegationBySpendTx(&withdrawalTxHash)
+	require.NoError(t, err)
+	require.Equal(t, txHash, found.StakingTxHash)
+	require.Equal(t, stakerdb.WatchedWithdrawalTx, found.Kind)
+
+	_, err = s.FindDelegationBySpendTx(&txHash)
+	require.ErrorIs(t, err, stakerdb.ErrTransactionNotFound)
+
+	require.NoError(t, s.DeleteTransactionSentToBabylon(&txHash))
+
+	_, err = s.FindDelegationBySpendTx(&unbondingTxHash)
+	require.ErrorIs(t, err, stakerdb.ErrTransactionNotFound)
+	_, err = s.FindDelegationBySpendTx(&withdrawalTxHash)
+	require.ErrorIs(t, err, stakerdb.ErrTransactionNotFound)
+}
+
+func TestResetTransactionRegistration(t *testing.T) {
+	t.Parallel()
+	r := rand.New(rand.NewSource(time.Now().UnixNano()))
+	s := MakeTestStore(t)
+
+	storedTx := genStoredTransaction(t, r)
+	txHash := storedTx.StakingTx.TxHash()
+
+	require.ErrorIs(t, s.ResetTransactionRegistration(&txHash), stakerdb.ErrTransactionNotFound)
+
+	stakerAddr, err := btcutil.DecodeAddress(storedTx.StakerAddress, &chaincfg.MainNetParams)
+	require.NoError(t, err)
+	require.NoError(t, s.AddTransactionSentToBabylon(storedTx.StakingTx, stakerAddr))
+
+	// previous registration failed
+	require.NoError(t, s.SetDelegationRegisteredAt(&txHash, time.Unix(1000, 0)))
+	require.NoError(t, s.MarkTransactionFailed(&txHash, "invalid covenant signature"))
+	require.NoError(t, s.SetFailureNote(&txHash, &stakerdb.FailureNote{
+		Source:     stakerdb.FailureSourceBabylon,
+		Reason:     "invalid covenant signature",
+		RecordedAt: time.Unix(1000, 0).UTC(),
+	}))
+
+	require.NoError(t, s.ResetTransactionRegistration(&txHash))
+
+	failure, err := s.GetTransactionFailure(&txHash)
+	require.NoError(t, err)
+	require.Nil(t, failure)
+	note, err := s.GetFailureNote(&txHash)
+	require.NoError(t, err)
+	require.Nil(t, note)
+	timing, err := s.GetCovenantQuorumTiming(&txHash)
+	require.NoError(t, err)
+	require.Nil(t, timing)
+
+	// delegation itself stays tracked
+	_, err = s.GetTransaction(&txHash)
+	require.NoError(t, err)
+
+	changes, err := s.ChangesOf(&txHash)
+	require.NoError(t, err)
+	require.Equal(t, stakerdb.ChangeRegistrationRetried, changes[len(changes)-1].Kind)
+}
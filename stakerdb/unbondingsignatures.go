@@ -0,0 +1,300 @@
+package stakerdb
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/lightningnetwork/lnd/kvdb"
+)
+
+var (
+	// mapping txHash -> json encoded unbonding signatures
+	// It holds covenant signatures of unbonding transaction of the delegation
+	// which were verified by staker
+	unbondingSignaturesBucketName = []byte("unbondingSignatures")
+)
+
+// CovenantSignature is a signature of covenant committee member
+type CovenantSignature struct {
+	// CovenantPk is BIP340 public key of the committee member
+	CovenantPk []byte `json:"covenant_pk"`
+	// Signature is BIP340 signature of unbonding path sighash
+	Signature []byte `json:"signature"`
+}
+
+// UnbondingSignatures are verified covenant signatures of unbonding
+// transaction of the delegation
+type UnbondingSignatures struct {
+	// ParamsVersion is version of Babylon params whose covenant committee
+	// signed the unbonding transaction
+	ParamsVersion uint32 `json:"params_version"`
+	// Quorum is the number of signatures required by the params version
+	Quorum uint32 `json:"quorum"`
+	// Committee are BIP340 public keys of covenant committee of the params
+	// version. It is empty for signatures stored before committee was
+	// recorded.
+	Committee  [][]byte            `json:"committee,omitempty"`
+	Signatures []CovenantSignature `json:"signatures"`
+	ReceivedAt time.Time           `json:"received_at"`
+}
+
+// Validate checks that signatures are made by distinct members of covenant
+// committee and meet the quorum
+func (s *UnbondingSignatures) Validate() error {
+	if len(s.Committee) == 0 {
+		return fmt.Errorf("covenant committee of params version %d is empty", s.ParamsVersion)
+	}
+
+	seen := make(map[string]struct{}, len(s.Signatures))
+	for _, sig := range s.Signatures {
+		if !s.inCommittee(sig.CovenantPk) {
+			return fmt.Errorf("%w: %x", ErrCovenantNotInCommittee, sig.CovenantPk)
+		}
+
+		key := hex.EncodeToString(sig.CovenantPk)
+		if _, ok := seen[key]; ok {
+			return fmt.Errorf("%w: %x", ErrDuplicateCovenantSignature, sig.CovenantPk)
+		}
+		seen[key] = struct{}{}
+	}
+
+	if len(s.Signatures) < int(s.Quorum) {
+		return fmt.Errorf("%w: have %d, need %d", ErrCovenantQuorumNotMet, len(s.Signatures), s.Quorum)
+	}
+
+	return nil
+}
+
+func (s *UnbondingSignatures) inCommittee(pk []byte) bool {
+	for _, member := range s.Committee {
+		if bytes.Equal(member, pk) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// clean removes duplicate signatures, keeping the first one of each key, and
+// signatures of keys outside of committee. Returns number of removed
+// signatures.
+func (s *UnbondingSignatures) clean() int {
+	seen := make(map[string]struct{}, len(s.Signatures))
+	kept := make([]CovenantSignature, 0, len(s.Signatures))
+	for _, sig := range s.Signatures {
+		key := hex.EncodeToString(sig.CovenantPk)
+		if _, ok := seen[key]; ok || !s.inCommittee(sig.CovenantPk) {
+			continue
+		}
+
+		seen[key] = struct{}{}
+		kept = append(kept, sig)
+	}
+
+	removed := len(s.Signatures) - len(kept)
+	s.Signatures = kept
+
+	return removed
+}
+
+// SetTxUnbondingSignaturesReceived stores verified covenant signatures of
+// unbonding transaction of tracked delegation, replacing previously stored
+// ones. Signatures must be made by distinct committee members and meet
+// quorum, otherwise nothing is stored.
+func (c *TrackedTransactionStore) SetTxUnbondingSignaturesReceived(txHash *chainhash.Hash, sigs *UnbondingSignatures) error {
+	if sigs == nil {
+		return fmt.Errorf("cannot save nil unbonding signatures")
+	}
+
+	if err := sigs.Validate(); err != nil {
+		return err
+	}
+
+	encoded, err := json.Marshal(sigs)
+	if err != nil {
+		return fmt.Errorf("failed to encode unbonding signatures: %w", err)
+	}
+
+	return c.update(func(tx kvdb.RwTx) error {
+		transactionIdxBucket := tx.ReadBucket(transactionIndexName)
+		if transactionIdxBucket == nil {
+			return ErrCorruptedTransactionsDB
+		}
+
+		if transactionIdxBucket.Get(txHash[:]) == nil {
+			return ErrTransactionNotFound
+		}
+
+		sigsBucket := tx.ReadWriteBucket(unbondingSignaturesBucketName)
+		if sigsBucket == nil {
+			return ErrCorruptedTransactionsDB
+		}
+
+		if err := sigsBucket.Put(txHash.CloneBytes(), encoded); err != nil {
+			return err
+		}
+
+		return appendChange(tx, ChangeUnbondingSignaturesReceived, txHash,
+			fmt.Sprintf("%d/%d", len(sigs.Signatures), sigs.Quorum))
+	})
+}
+
+// GetTxUnbondingSignatures returns verified covenant signatures of unbonding
+// transaction of tracked delegation, nil if they were not received yet
+func (c *TrackedTransactionStore) GetTxUnbondingSignatures(txHash *chainhash.Hash) (*UnbondingSignatures, error) {
+	var sigs *UnbondingSignatures
+
+	err := c.db.View(func(tx kvdb.RTx) error {
+		sigsBucket := tx.ReadBucket(unbondingSignaturesBucketName)
+		if sigsBucket == nil {
+			return ErrCorruptedTransactionsDB
+		}
+
+		v := sigsBucket.Get(txHash[:])
+		if v == nil {
+			return nil
+		}
+
+		var s UnbondingSignatures
+		if err := json.Unmarshal(v, &s); err != nil {
+			return err
+		}
+
+		sigs = &s
+		return nil
+	}, func() {
+		sigs = nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get unbonding signatures: %w", err)
+	}
+
+	return sigs, nil
+}
+
+// CommitteeLookup returns BIP340 public keys of covenant committee of the
+// given params version
+type CommitteeLookup func(paramsVersion uint32) ([][]byte, error)
+
+// CleanUnbondingSignatures removes duplicate covenant signatures and
+// signatures of keys outside of covenant committee from stored unbonding
+// signatures. Committee of signatures stored without it is resolved with
+// lookup and stored with them, such signatures are counted as errors and left
+// unchanged if lookup is nil or fails. Signatures which no longer meet quorum
+// after cleaning are removed.
+func (c *TrackedTransactionStore) CleanUnbondingSignatures(lookup CommitteeLookup) (*MigrationResult, error) {
+	result := &MigrationResult{}
+
+	committees := make(map[uint32][][]byte)
+	committeeOf := func(paramsVersion uint32) ([][]byte, error) {
+		if committee, ok := committees[paramsVersion]; ok {
+			return committee, nil
+		}
+
+		if lookup == nil {
+			return nil, fmt.Errorf("covenant committee of params version %d is not known", paramsVersion)
+		}
+
+		committee, err := lookup(paramsVersion)
+		if err != nil {
+			return nil, err
+		}
+
+		committees[paramsVersion] = committee
+		return committee, nil
+	}
+
+	err := c.update(func(tx kvdb.RwTx) error {
+		*result = MigrationResult{}
+
+		sigsBucket := tx.ReadWriteBucket(unbondingSignaturesBucketName)
+		if sigsBucket == nil {
+			return ErrCorruptedTransactionsDB
+		}
+
+		type cleanedSigs struct {
+			txHash  chainhash.Hash
+			sigs    *UnbondingSignatures
+			removed int
+		}
+
+		// bucket can't be modified while iterating over it
+		var cleaned []cleanedSigs
+		err := sigsBucket.ForEach(func(k, v []byte) error {
+			result.ProcessedCount++
+
+			txHash, err := chainhash.NewHash(k)
+			if err != nil {
+				return err
+			}
+
+			var sigs UnbondingSignatures
+			if err := json.Unmarshal(v, &sigs); err != nil {
+				return err
+			}
+
+			committeeAdded := false
+			if len(sigs.Committee) == 0 {
+				committee, err := committeeOf(sigs.ParamsVersion)
+				if err != nil {
+					result.ErrorCount++
+					return nil
+				}
+
+				sigs.Committee = committee
+				committeeAdded = true
+			}
+
+			removed := sigs.clean()
+			if removed == 0 && !committeeAdded {
+				result.SkippedCount++
+				return nil
+			}
+
+			cleaned = append(cleaned, cleanedSigs{txHash: *txHash, sigs: &sigs, removed: removed})
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, entry := range cleaned {
+			if len(entry.sigs.Signatures) < int(entry.sigs.Quorum) {
+				if err := sigsBucket.Delete(entry.txHash[:]); err != nil {
+					return err
+				}
+			} else {
+				encoded, err := json.Marshal(entry.sigs)
+				if err != nil {
+					return fmt.Errorf("failed to encode unbonding signatures: %w", err)
+				}
+
+				if err := sigsBucket.Put(entry.txHash[:], encoded); err != nil {
+					return err
+				}
+			}
+
+			result.MigratedCount++
+
+			if entry.removed == 0 {
+				continue
+			}
+
+			detail := fmt.Sprintf("removed %d, kept %d/%d", entry.removed, len(entry.sigs.Signatures), entry.sigs.Quorum)
+			if err := appendChange(tx, ChangeUnbondingSignaturesCleaned, &entry.txHash, detail); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to clean unbonding signatures: %w", err)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,95 @@
+package stakerdb
+
+import (
+	"encoding/binary"
+	"fmt"
+	"time"
+
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/lightningnetwork/lnd/kvdb"
+)
+
+var (
+	// mapping txHash -> bigendian(int64) requested at
+	// It holds delegations whose unbonding was requested, until unbonding
+	// transaction is confirmed
+	unbondRequestsBucketName = []byte("unbondRequests")
+)
+
+// SetUnbondRequested stores time at which unbonding of tracked delegation was
+// requested. Time of the first request is kept.
+func (c *TrackedTransactionStore) SetUnbondRequested(txHash *chainhash.Hash, requestedAt time.Time) error {
+	return c.update(func(tx kvdb.RwTx) error {
+		transactionIdxBucket := tx.ReadBucket(transactionIndexName)
+		if transactionIdxBucket == nil {
+			return ErrCorruptedTransactionsDB
+		}
+
+		if transactionIdxBucket.Get(txHash[:]) == nil {
+			return ErrTransactionNotFound
+		}
+
+		requestsBucket := tx.ReadWriteBucket(unbondRequestsBucketName)
+		if requestsBucket == nil {
+			return ErrCorruptedTransactionsDB
+		}
+
+		if requestsBucket.Get(txHash[:]) != nil {
+			return nil
+		}
+
+		if err := requestsBucket.Put(txHash.CloneBytes(), uint64KeyToBytes(timeToUnixNano(requestedAt))); err != nil {
+			return err
+		}
+
+		return appendChange(tx, ChangeUnbondRequested, txHash, "")
+	})
+}
+
+// ClearUnbondRequest removes unbond request of tracked delegation once its
+// unbonding transaction is confirmed
+func (c *TrackedTransactionStore) ClearUnbondRequest(txHash *chainhash.Hash) error {
+	return batch(c.db, func(tx kvdb.RwTx) error {
+		requestsBucket := tx.ReadWriteBucket(unbondRequestsBucketName)
+		if requestsBucket == nil {
+			return ErrCorruptedTransactionsDB
+		}
+
+		return requestsBucket.Delete(txHash[:])
+	})
+}
+
+// ListUnbondRequests returns times at which unbonding of tracked delegations
+// was requested, for delegations whose unbonding transaction is not confirmed
+// yet
+func (c *TrackedTransactionStore) ListUnbondRequests() (map[chainhash.Hash]time.Time, error) {
+	requests := make(map[chainhash.Hash]time.Time)
+
+	err := c.db.View(func(tx kvdb.RTx) error {
+		requestsBucket := tx.ReadBucket(unbondRequestsBucketName)
+		if requestsBucket == nil {
+			return ErrCorruptedTransactionsDB
+		}
+
+		return requestsBucket.ForEach(func(k, v []byte) error {
+			hash, err := chainhash.NewHash(k)
+			if err != nil {
+				return err
+			}
+
+			if len(v) != 8 {
+				return fmt.Errorf("invalid unbond request size: %d", len(v))
+			}
+
+			requests[*hash] = unixNanoToTime(binary.BigEndian.Uint64(v))
+			return nil
+		})
+	}, func() {
+		requests = make(map[chainhash.Hash]time.Time)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list unbond requests: %w", err)
+	}
+
+	return requests, nil
+}
@@ -0,0 +1,62 @@
+package stakerdb
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/lightningnetwork/lnd/kvdb"
+)
+
+var (
+	// holds delivery state of the webhook
+	webhookBucketName = []byte("webhook")
+
+	// key for sequence number of the last change delivered to the webhook
+	webhookCursorKey = []byte("cursor")
+)
+
+// WebhookCursor returns sequence number of the last change delivered to the
+// webhook, zero if none was delivered yet
+func (c *TrackedTransactionStore) WebhookCursor() (uint64, error) {
+	var cursor uint64
+
+	err := c.db.View(func(tx kvdb.RTx) error {
+		bucket := tx.ReadBucket(webhookBucketName)
+		if bucket == nil {
+			return ErrCorruptedTransactionsDB
+		}
+
+		v := bucket.Get(webhookCursorKey)
+		if v == nil {
+			return nil
+		}
+
+		if len(v) != 8 {
+			return ErrCorruptedTransactionsDB
+		}
+
+		cursor = binary.BigEndian.Uint64(v)
+		return nil
+	}, func() {
+		cursor = 0
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to get webhook cursor: %w", err)
+	}
+
+	return cursor, nil
+}
+
+// SetWebhookCursor stores sequence number of the last change delivered to the
+// webhook. Delivery is not recorded in the changelog, as it would produce a
+// new change to deliver.
+func (c *TrackedTransactionStore) SetWebhookCursor(seq uint64) error {
+	return batch(c.db, func(tx kvdb.RwTx) error {
+		bucket := tx.ReadWriteBucket(webhookBucketName)
+		if bucket == nil {
+			return ErrCorruptedTransactionsDB
+		}
+
+		return bucket.Put(webhookCursorKey, uint64KeyToBytes(seq))
+	})
+}
@@ -0,0 +1,87 @@
+package stakerservice
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	str "github.com/babylonlabs-io/btc-staker/staker"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	rpctypes "github.com/cometbft/cometbft/rpc/jsonrpc/types"
+)
+
+// activationLatency returns activation latency breakdown of the delegation,
+// or statistics across active delegations if no delegation is given
+func (s *StakerService) activationLatency(_ *rpctypes.Context, stakingTxHash *string) (*ActivationLatencyResponse, error) {
+	if stakingTxHash == nil || *stakingTxHash == "" {
+		summary, err := s.staker.ActivationLatencySummary()
+		if err != nil {
+			return nil, err
+		}
+
+		resp := &ActivationLatencyResponse{
+			ActiveDelegations: summary.Delegations,
+			Transitions:       []ActivationLatencyStatsDetail{},
+		}
+		if summary.Total != nil {
+			total := activationLatencyStatsDetail(summary.Total)
+			resp.Total = &total
+		}
+		for i := range summary.Transitions {
+			resp.Transitions = append(resp.Transitions, activationLatencyStatsDetail(&summary.Transitions[i]))
+		}
+
+		return resp, nil
+	}
+
+	txHash, err := chainhash.NewHashFromStr(*stakingTxHash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse string type of hash to chainhash.Hash: %w", err)
+	}
+
+	latency, err := s.staker.DelegationActivationLatency(txHash)
+	if err != nil {
+		return nil, err
+	}
+
+	detail := &DelegationActivationLatency{
+		StakingTxHash: txHash.String(),
+		Phases:        []ActivationPhaseDetail{},
+		Transitions:   []ActivationTransitionDetail{},
+	}
+
+	if latency != nil {
+		for _, p := range latency.Phases {
+			detail.Phases = append(detail.Phases, ActivationPhaseDetail{
+				Phase: p.Phase,
+				At:    formatOptionalTime(p.At),
+			})
+		}
+		for i := range latency.Breakdown {
+			detail.Transitions = append(detail.Transitions, ActivationTransitionDetail{
+				Transition: latency.Breakdown[i].Name(),
+				Secs:       formatSecs(latency.Breakdown[i].Latency),
+			})
+		}
+		if latency.Total > 0 {
+			detail.TotalSecs = formatSecs(latency.Total)
+		}
+	}
+
+	return &ActivationLatencyResponse{Delegation: detail}, nil
+}
+
+func activationLatencyStatsDetail(stats *str.ActivationLatencyStats) ActivationLatencyStatsDetail {
+	return ActivationLatencyStatsDetail{
+		Name:     stats.Name,
+		Count:    stats.Count,
+		MeanSecs: formatSecs(stats.Mean),
+		P50Secs:  formatSecs(stats.P50),
+		P90Secs:  formatSecs(stats.P90),
+		MaxSecs:  formatSecs(stats.Max),
+	}
+}
+
+func formatSecs(d time.Duration) string {
+	return strconv.FormatInt(int64(d.Seconds()), 10)
+}
@@ -0,0 +1,112 @@
+package stakerservice
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/babylonlabs-io/btc-staker/stakerdb"
+	"github.com/btcsuite/btcd/btcutil"
+)
+
+const (
+	ActivityPeriodDay   = "day"
+	ActivityPeriodWeek  = "week"
+	ActivityPeriodMonth = "month"
+)
+
+// activityBucket accumulates activity events which happened in one period
+type activityBucket struct {
+	period          string
+	delegations     uint64
+	delegatedAmount btcutil.Amount
+	withdrawals     uint64
+	withdrawnAmount btcutil.Amount
+	firstBtcHeight  uint32
+	lastBtcHeight   uint32
+}
+
+// parseActivityPeriod parses optional period parameter, month is the default
+func parseActivityPeriod(periodPtr *string) (string, error) {
+	if periodPtr == nil || *periodPtr == "" {
+		return ActivityPeriodMonth, nil
+	}
+
+	switch *periodPtr {
+	case ActivityPeriodDay, ActivityPeriodWeek, ActivityPeriodMonth:
+		return *periodPtr, nil
+	default:
+		return "", fmt.Errorf("unknown period %q, supported periods: %s,%s,%s",
+			*periodPtr, ActivityPeriodDay, ActivityPeriodWeek, ActivityPeriodMonth)
+	}
+}
+
+// periodLabel returns label of the period which contains given time. Labels
+// sort lexicographically in chronological order.
+func periodLabel(t time.Time, period string) string {
+	t = t.UTC()
+	switch period {
+	case ActivityPeriodDay:
+		return t.Format("2006-01-02")
+	case ActivityPeriodWeek:
+		year, week := t.ISOWeek()
+		return fmt.Sprintf("%d-W%02d", year, week)
+	default:
+		return t.Format("2006-01")
+	}
+}
+
+// bucketActivityEvents groups activity events by period, buckets are returned
+// in chronological order
+func bucketActivityEvents(events []stakerdb.ActivityEvent, period string) []StakingActivityBucket {
+	buckets := make(map[string]*activityBucket)
+
+	for _, ev := range events {
+		label := periodLabel(ev.Timestamp, period)
+
+		b, ok := buckets[label]
+		if !ok {
+			b = &activityBucket{
+				period:         label,
+				firstBtcHeight: ev.BtcHeight,
+				lastBtcHeight:  ev.BtcHeight,
+			}
+			buckets[label] = b
+		}
+
+		switch ev.Kind {
+		case stakerdb.ActivityDelegation:
+			b.delegations++
+			b.delegatedAmount += ev.Amount
+		case stakerdb.ActivityWithdrawal:
+			b.withdrawals++
+			b.withdrawnAmount += ev.Amount
+		}
+
+		b.firstBtcHeight = min(b.firstBtcHeight, ev.BtcHeight)
+		b.lastBtcHeight = max(b.lastBtcHeight, ev.BtcHeight)
+	}
+
+	labels := make([]string, 0, len(buckets))
+	for label := range buckets {
+		labels = append(labels, label)
+	}
+	sort.Strings(labels)
+
+	result := make([]StakingActivityBucket, 0, len(labels))
+	for _, label := range labels {
+		b := buckets[label]
+		result = append(result, StakingActivityBucket{
+			Period:          b.period,
+			Delegations:     strconv.FormatUint(b.delegations, 10),
+			DelegatedAmount: b.delegatedAmount.String(),
+			Withdrawals:     strconv.FormatUint(b.withdrawals, 10),
+			WithdrawnAmount: b.withdrawnAmount.String(),
+			FirstBtcHeight:  strconv.FormatUint(uint64(b.firstBtcHeight), 10),
+			LastBtcHeight:   strconv.FormatUint(uint64(b.lastBtcHeight), 10),
+		})
+	}
+
+	return result
+}
@@ -0,0 +1,287 @@
+package stakerservice
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	scfg "github.com/babylonlabs-io/btc-staker/stakercfg"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	rpctypes "github.com/cometbft/cometbft/rpc/jsonrpc/types"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	OperationStatusPending  = "pending_approval"
+	OperationStatusExecuted = "executed"
+	OperationStatusFailed   = "failed"
+	OperationStatusExpired  = "expired"
+	// operation is being executed after approval
+	operationStatusExecuting = "executing"
+
+	OperationStake       = "stake"
+	OperationStakeExpand = "stake_expand"
+	OperationSpendStake  = "spend_stake"
+	OperationRestake     = "restake_from_unbonded"
+	OperationAutoRenew   = "set_auto_renew"
+)
+
+var (
+	ErrOperationNotFound = errors.New("operation not found")
+	ErrSelfApproval      = errors.New("operation must be approved by principal other than the requester")
+)
+
+type principalKey struct{}
+
+// withPrincipal stores authenticated principal in request context
+func withPrincipal(r *http.Request, principal string) *http.Request {
+	return r.WithContext(context.WithValue(r.Context(), principalKey{}, principal))
+}
+
+// principal returns authenticated principal which made the request
+func principal(ctx *rpctypes.Context) string {
+	if ctx == nil || ctx.HTTPReq == nil {
+		return ""
+	}
+
+	p, _ := ctx.HTTPReq.Context().Value(principalKey{}).(string)
+	return p
+}
+
+// pendingOperation is stake or spend request waiting for approval. execute
+// performs the request and returns hash of the broadcast transaction.
+type pendingOperation struct {
+	id          string
+	kind        string
+	requester   string
+	amount      btcutil.Amount
+	description string
+	createdAt   time.Time
+	expiresAt   time.Time
+	status      string
+	approvedBy  string
+	txHash      string
+	err         string
+	execute     func() (string, error)
+}
+
+func (op *pendingOperation) details() OperationDetails {
+	return OperationDetails{
+		ID:          op.id,
+		Kind:        op.kind,
+		Requester:   op.requester,
+		Amount:      int64(op.amount),
+		Description: op.description,
+		CreatedAt:   op.createdAt.UTC().Format(time.RFC3339),
+		ExpiresAt:   op.expiresAt.UTC().Format(time.RFC3339),
+		Status:      op.status,
+		ApprovedBy:  op.approvedBy,
+		TxHash:      op.txHash,
+		Error:       op.err,
+	}
+}
+
+// operationQueue holds operations requiring approval in memory, so pending
+// operations are dropped on restart. Nil queue requires no approvals.
+type operationQueue struct {
+	mu        sync.Mutex
+	threshold btcutil.Amount
+	expiry    time.Duration
+	ops       map[string]*pendingOperation
+	now       func() time.Time
+}
+
+func newOperationQueue(cfg *scfg.ApprovalConfig) *operationQueue {
+	if cfg == nil || !cfg.Enabled {
+		return nil
+	}
+
+	return &operationQueue{
+		threshold: btcutil.Amount(cfg.Threshold),
+		expiry:    cfg.Expiry,
+		ops:       make(map[string]*pendingOperation),
+		now:       time.Now,
+	}
+}
+
+// requiresApproval returns true if operation moving given amount must be approved
+func (q *operationQueue) requiresApproval(amount btcutil.Amount) bool {
+	return q != nil && amount > q.threshold
+}
+
+// submit stores operation until it is approved or expires
+func (q *operationQueue) submit(
+	kind string,
+	requester string,
+	amount btcutil.Amount,
+	description string,
+	execute func() (string, error),
+) (OperationDetails, error) {
+	var idBytes [16]byte
+	if _, err := rand.Read(idBytes[:]); err != nil {
+		return OperationDetails{}, fmt.Errorf("failed to generate operation id: %w", err)
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.prune()
+
+	now := q.now()
+	op := &pendingOperation{
+		id:          hex.EncodeToString(idBytes[:]),
+		kind:        kind,
+		requester:   requester,
+		amount:      amount,
+		description: description,
+		createdAt:   now,
+		expiresAt:   now.Add(q.expiry),
+		status:      OperationStatusPending,
+		execute:     execute,
+	}
+	q.ops[op.id] = op
+
+	return op.details(), nil
+}
+
+// approve executes pending operation on behalf of approver
+func (q *operationQueue) approve(id string, approver string) (OperationDetails, error) {
+	q.mu.Lock()
+	q.prune()
+
+	op, ok := q.ops[id]
+	if !ok {
+		q.mu.Unlock()
+		return OperationDetails{}, ErrOperationNotFound
+	}
+
+	if op.status != OperationStatusPending {
+		q.mu.Unlock()
+		return op.details(), fmt.Errorf("operation %s is %s", id, op.status)
+	}
+
+	// user names are matched case insensitively by authentication
+	if approver == "" || strings.EqualFold(approver, op.requester) {
+		q.mu.Unlock()
+		return op.details(), ErrSelfApproval
+	}
+
+	op.status = operationStatusExecuting
+	op.approvedBy = approver
+	q.mu.Unlock()
+
+	txHash, err := op.execute()
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if err != nil {
+		op.status = OperationStatusFailed
+		op.err = err.Error()
+	} else {
+		op.status = OperationStatusExecuted
+		op.txHash = txHash
+	}
+	op.execute = nil
+
+	return op.details(), err
+}
+
+// list returns operations sorted by creation time
+func (q *operationQueue) list() []OperationDetails {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.prune()
+
+	ops := make([]*pendingOperation, 0, len(q.ops))
+	for _, op := range q.ops {
+		ops = append(ops, op)
+	}
+	sort.Slice(ops, func(i, j int) bool {
+		return ops[i].createdAt.Before(ops[j].createdAt)
+	})
+
+	details := make([]OperationDetails, len(ops))
+	for i, op := range ops {
+		details[i] = op.details()
+	}
+
+	return details
+}
+
+// prune marks not approved operations as expired and forgets operations one
+// more expiry period later. Must be called with lock held.
+func (q *operationQueue) prune() {
+	now := q.now()
+
+	for id, op := range q.ops {
+		if op.status == OperationStatusPending && now.After(op.expiresAt) {
+			op.status = OperationStatusExpired
+			op.execute = nil
+		}
+
+		if op.status != operationStatusExecuting && now.After(op.expiresAt.Add(q.expiry)) {
+			delete(q.ops, id)
+		}
+	}
+}
+
+// stakingAmount returns amount locked by tracked staking transaction
+func (s *StakerService) stakingAmount(txHash *chainhash.Hash) (btcutil.Amount, error) {
+	storedTx, err := s.staker.GetStoredTransaction(txHash)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get stored transaction from hash %s: %w", txHash, err)
+	}
+
+	status, err := s.staker.DelegationStatus(storedTx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get delegation status: %w", err)
+	}
+
+	if status.AmountsErr != nil {
+		return 0, fmt.Errorf("failed to get staking transaction amounts: %w", status.AmountsErr)
+	}
+
+	return status.Amounts.StakingAmount, nil
+}
+
+// approveOperation approves and executes operation requested by other principal
+func (s *StakerService) approveOperation(ctx *rpctypes.Context, operationID string) (*OperationDetails, error) {
+	if s.approvals == nil {
+		return nil, errors.New("approval of operations is not enabled")
+	}
+
+	approver := principal(ctx)
+	op, err := s.approvals.approve(operationID, approver)
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"operationID": op.ID,
+		"kind":        op.Kind,
+		"requester":   op.Requester,
+		"approver":    approver,
+		"txHash":      op.TxHash,
+	}).Info("Executed approved operation")
+
+	return &op, nil
+}
+
+// listOperations returns operations which required approval
+func (s *StakerService) listOperations(_ *rpctypes.Context) (*OperationsResponse, error) {
+	if s.approvals == nil {
+		return &OperationsResponse{Operations: []OperationDetails{}}, nil
+	}
+
+	return &OperationsResponse{Operations: s.approvals.list()}, nil
+}
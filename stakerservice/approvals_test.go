@@ -0,0 +1,82 @@
+package stakerservice
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	scfg "github.com/babylonlabs-io/btc-staker/stakercfg"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOperationQueueApproval(t *testing.T) {
+	t.Parallel()
+
+	cfg := scfg.DefaultApprovalConfig()
+	cfg.Enabled = true
+	cfg.Threshold = 1000
+	q := newOperationQueue(&cfg)
+
+	require.False(t, q.requiresApproval(1000))
+	require.True(t, q.requiresApproval(1001))
+
+	executed := 0
+	op, err := q.submit(OperationStake, "operator", 2000, "stake", func() (string, error) {
+		executed++
+		return "txhash", nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, OperationStatusPending, op.Status)
+
+	_, err = q.approve(op.ID, "Operator")
+	require.ErrorIs(t, err, ErrSelfApproval)
+	require.Equal(t, 0, executed)
+
+	approved, err := q.approve(op.ID, "alice")
+	require.NoError(t, err)
+	require.Equal(t, OperationStatusExecuted, approved.Status)
+	require.Equal(t, "txhash", approved.TxHash)
+	require.Equal(t, "alice", approved.ApprovedBy)
+	require.Equal(t, 1, executed)
+
+	// operation is executed only once
+	_, err = q.approve(op.ID, "alice")
+	require.Error(t, err)
+	require.Equal(t, 1, executed)
+
+	_, err = q.approve("unknown", "alice")
+	require.ErrorIs(t, err, ErrOperationNotFound)
+}
+
+func TestOperationQueueExpiry(t *testing.T) {
+	t.Parallel()
+
+	cfg := scfg.DefaultApprovalConfig()
+	cfg.Enabled = true
+	q := newOperationQueue(&cfg)
+
+	now := time.Now()
+	q.now = func() time.Time { return now }
+
+	op, err := q.submit(OperationSpendStake, "operator", 1, "spend", func() (string, error) {
+		return "", errors.New("must not be executed")
+	})
+	require.NoError(t, err)
+
+	now = now.Add(cfg.Expiry + time.Second)
+	_, err = q.approve(op.ID, "alice")
+	require.Error(t, err)
+	require.Equal(t, OperationStatusExpired, q.list()[0].Status)
+
+	now = now.Add(cfg.Expiry)
+	require.Empty(t, q.list())
+}
+
+func TestDisabledOperationQueue(t *testing.T) {
+	t.Parallel()
+
+	cfg := scfg.DefaultApprovalConfig()
+	q := newOperationQueue(&cfg)
+	require.Nil(t, q)
+	require.False(t, q.requiresApproval(1_000_000_000))
+}
@@ -0,0 +1,131 @@
+package stakerservice
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	rpctypes "github.com/cometbft/cometbft/rpc/jsonrpc/types"
+)
+
+// expiringDelegations returns active delegations whose staking timelock
+// expires within the given number of blocks
+func (s *StakerService) expiringDelegations(_ *rpctypes.Context, withinBlocks int64) (*ExpiringDelegationsResponse, error) {
+	if withinBlocks < 0 || withinBlocks > math.MaxUint32 {
+		return nil, fmt.Errorf("number of blocks must be non negative and lower than %d", uint32(math.MaxUint32))
+	}
+
+	expiring, err := s.staker.ExpiringDelegations(uint32(withinBlocks))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get expiring delegations: %w", err)
+	}
+
+	details := make([]ExpiringDelegationDetail, len(expiring))
+	for i, d := range expiring {
+		details[i] = ExpiringDelegationDetail{
+			StakingTxHash:    d.StakingTxHash.String(),
+			State:            d.State,
+			ExpiryHeight:     d.ExpiryHeight,
+			BlocksLeft:       d.BlocksLeft,
+			AutoRenew:        d.AutoRenew,
+			RenewStakingTime: d.RenewStakingTime,
+		}
+	}
+
+	return &ExpiringDelegationsResponse{Delegations: details}, nil
+}
+
+// setAutoRenew enables or disables restaking of the delegation once its
+// staking timelock expires. Enabling auto renew authorizes restake in advance,
+// so it requires approval the same way as restake does.
+func (s *StakerService) setAutoRenew(
+	ctx *rpctypes.Context,
+	stakingTxHash string,
+	enabled bool,
+	stakingTimeBlocks *int64,
+) (*SetAutoRenewResponse, error) {
+	txHash, err := chainhash.NewHashFromStr(stakingTxHash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse string type of hash to chainhash.Hash: %w", err)
+	}
+
+	if !enabled {
+		if _, err := s.staker.DisableAutoRenew(txHash); err != nil {
+			return nil, fmt.Errorf("failed to disable auto renew: %w", err)
+		}
+
+		return &SetAutoRenewResponse{Enabled: false}, nil
+	}
+
+	// zero staking time keeps staking time of the delegation
+	var stakingTime uint16
+	if stakingTimeBlocks != nil && *stakingTimeBlocks != 0 {
+		stakingTime, err = parseStakingTime(*stakingTimeBlocks)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if s.approvals != nil {
+		amount, err := s.stakingAmount(txHash)
+		if err != nil {
+			return nil, err
+		}
+
+		if s.approvals.requiresApproval(amount) {
+			op, err := s.approvals.submit(
+				OperationAutoRenew,
+				principal(ctx),
+				amount,
+				fmt.Sprintf("auto renew %s for %d blocks", txHash, stakingTime),
+				func() (string, error) {
+					if err := s.staker.SetAutoRenew(txHash, stakingTime); err != nil {
+						return "", fmt.Errorf("failed to enable auto renew: %w", err)
+					}
+					return txHash.String(), nil
+				},
+			)
+			if err != nil {
+				return nil, err
+			}
+
+			return &SetAutoRenewResponse{OperationID: op.ID, Status: op.Status}, nil
+		}
+	}
+
+	if err := s.staker.SetAutoRenew(txHash, stakingTime); err != nil {
+		return nil, fmt.Errorf("failed to enable auto renew: %w", err)
+	}
+
+	return &SetAutoRenewResponse{
+		Enabled:           true,
+		StakingTimeBlocks: stakingTime,
+	}, nil
+}
+
+// renewalJobs returns renewal jobs of expired delegations with enabled auto
+// renew
+func (s *StakerService) renewalJobs(_ *rpctypes.Context) (*RenewalJobsResponse, error) {
+	jobs, err := s.staker.RenewalJobs()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get renewal jobs: %w", err)
+	}
+
+	details := make([]RenewalJobDetail, len(jobs))
+	for i, j := range jobs {
+		details[i] = RenewalJobDetail{
+			StakingTxHash:    j.StakingTxHash.String(),
+			StakingTime:      j.StakingTime,
+			State:            j.State,
+			Attempts:         j.Attempts,
+			WithdrawalTxHash: j.WithdrawalTxHash,
+			NewStakingTxHash: j.NewStakingTxHash,
+			Error:            j.Error,
+			CreatedAt:        j.CreatedAt.UTC().Format(time.RFC3339),
+			UpdatedAt:        j.UpdatedAt.UTC().Format(time.RFC3339),
+		}
+	}
+
+	return &RenewalJobsResponse{Jobs: details}, nil
+}
@@ -0,0 +1,31 @@
+package stakerservice
+
+import (
+	rpctypes "github.com/cometbft/cometbft/rpc/jsonrpc/types"
+)
+
+// chainSafety returns whether params sensitive operations are paused and why
+func (s *StakerService) chainSafety(_ *rpctypes.Context) (*ChainSafetyResponse, error) {
+	status := s.staker.ChainSafetyStatus()
+
+	return &ChainSafetyResponse{
+		Paused:                 status.Paused,
+		Reasons:                status.Reasons,
+		Override:               status.Override,
+		BabylonHeight:          status.BabylonHeight,
+		BabylonHeightChangedAt: formatOptionalTime(status.BabylonHeightChangedAt),
+		BtcHeight:              status.BtcHeight,
+		BtcLightClientHeight:   status.BtcLightClientHeight,
+		CheckedAt:              formatOptionalTime(status.CheckedAt),
+	}, nil
+}
+
+// setChainSafetyOverride allows or disallows params sensitive operations
+// while chain safety checks fail
+func (s *StakerService) setChainSafetyOverride(ctx *rpctypes.Context, override bool) (*ChainSafetyResponse, error) {
+	if err := s.staker.SetChainSafetyOverride(override); err != nil {
+		return nil, err
+	}
+
+	return s.chainSafety(ctx)
+}
@@ -35,6 +35,32 @@ func (c *StakerServiceJSONRPCClient) Health(ctx context.Context) (*service.Resul
 	return result, nil
 }
 
+// Version returns version and enabled features of the staker daemon
+func (c *StakerServiceJSONRPCClient) Version(ctx context.Context) (*service.VersionResponse, error) {
+	result := new(service.VersionResponse)
+	_, err := c.client.Call(ctx, "version", map[string]interface{}{}, result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call version: %w", err)
+	}
+	return result, nil
+}
+
+// Stats returns fees paid by the staker daemon
+func (c *StakerServiceJSONRPCClient) Stats(ctx context.Context, tenant string) (*service.StatsResponse, error) {
+	result := new(service.StatsResponse)
+
+	params := make(map[string]interface{})
+	if tenant != "" {
+		params["tenant"] = tenant
+	}
+
+	_, err := c.client.Call(ctx, "stats", params, result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call stats: %w", err)
+	}
+	return result, nil
+}
+
 // ListOutputs returns a list of outputs
 func (c *StakerServiceJSONRPCClient) ListOutputs(ctx context.Context) (*service.OutputsResponse, error) {
 	result := new(service.OutputsResponse)
@@ -45,6 +71,123 @@ func (c *StakerServiceJSONRPCClient) ListOutputs(ctx context.Context) (*service.
 	return result, nil
 }
 
+// ListReservedOutpoints returns a list of outpoints used by tracked staking transactions
+func (c *StakerServiceJSONRPCClient) ListReservedOutpoints(ctx context.Context) (*service.ReservedOutpointsResponse, error) {
+	result := new(service.ReservedOutpointsResponse)
+	_, err := c.client.Call(ctx, "list_reserved_outpoints", map[string]interface{}{}, result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call list_reserved_outpoints: %w", err)
+	}
+	return result, nil
+}
+
+// UnlockWallet provides passphrase used by stakerd to unlock the wallet
+func (c *StakerServiceJSONRPCClient) UnlockWallet(ctx context.Context, passphrase string, timeoutSecs *int) (*service.UnlockWalletResponse, error) {
+	result := new(service.UnlockWalletResponse)
+
+	params := make(map[string]interface{})
+	params["passphrase"] = passphrase
+
+	if timeoutSecs != nil {
+		params["timeoutSecs"] = timeoutSecs
+	}
+
+	_, err := c.client.Call(ctx, "unlock_wallet", params, result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call unlock_wallet: %w", err)
+	}
+	return result, nil
+}
+
+// SignMessage signs message with the key of the wallet address using bip322
+func (c *StakerServiceJSONRPCClient) SignMessage(ctx context.Context, address, message string) (*service.SignMessageResponse, error) {
+	result := new(service.SignMessageResponse)
+
+	params := make(map[string]interface{})
+	params["address"] = address
+	params["message"] = message
+
+	_, err := c.client.Call(ctx, "sign_message", params, result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call sign_message: %w", err)
+	}
+	return result, nil
+}
+
+// VerifyMessage verifies bip322 signature of the message by the address
+func (c *StakerServiceJSONRPCClient) VerifyMessage(ctx context.Context, address, message, signature string) (*service.VerifyMessageResponse, error) {
+	result := new(service.VerifyMessageResponse)
+
+	params := make(map[string]interface{})
+	params["address"] = address
+	params["message"] = message
+	params["signature"] = signature
+
+	_, err := c.client.Call(ctx, "verify_message", params, result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call verify_message: %w", err)
+	}
+	return result, nil
+}
+
+// SignSpendTx returns staker schnorr signature over unbonding or slashing
+// transaction of the delegation
+func (c *StakerServiceJSONRPCClient) SignSpendTx(ctx context.Context, stakingTxHash, kind, txHex string) (*service.SignSpendTxResponse, error) {
+	result := new(service.SignSpendTxResponse)
+
+	params := make(map[string]interface{})
+	params["stakingTxHash"] = stakingTxHash
+	params["kind"] = kind
+	params["txHex"] = txHex
+
+	_, err := c.client.Call(ctx, "sign_spend_tx", params, result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call sign_spend_tx: %w", err)
+	}
+	return result, nil
+}
+
+// NewStakerAddress derives and registers new wallet address usable for staking
+func (c *StakerServiceJSONRPCClient) NewStakerAddress(ctx context.Context, addressType *string) (*service.StakerAddressDetail, error) {
+	result := new(service.StakerAddressDetail)
+
+	params := make(map[string]interface{})
+
+	if addressType != nil {
+		params["addressType"] = addressType
+	}
+
+	_, err := c.client.Call(ctx, "new_staker_address", params, result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call new_staker_address: %w", err)
+	}
+	return result, nil
+}
+
+// ListStakerAddresses returns staker addresses registered by the staker
+func (c *StakerServiceJSONRPCClient) ListStakerAddresses(ctx context.Context) (*service.StakerAddressesResponse, error) {
+	result := new(service.StakerAddressesResponse)
+	_, err := c.client.Call(ctx, "list_staker_addresses", map[string]interface{}{}, result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call list_staker_addresses: %w", err)
+	}
+	return result, nil
+}
+
+// UnreserveOutpoint releases outpoint reserved by a tracked staking transaction
+func (c *StakerServiceJSONRPCClient) UnreserveOutpoint(ctx context.Context, outpoint string) (*service.UnreserveOutpointResponse, error) {
+	result := new(service.UnreserveOutpointResponse)
+
+	params := make(map[string]interface{})
+	params["outpoint"] = outpoint
+
+	_, err := c.client.Call(ctx, "unreserve_outpoint", params, result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call unreserve_outpoint: %w", err)
+	}
+	return result, nil
+}
+
 // BabylonFinalityProviders returns a list of finality providers
 func (c *StakerServiceJSONRPCClient) BabylonFinalityProviders(ctx context.Context, offset *int, limit *int) (*service.FinalityProvidersResponse, error) {
 	result := new(service.FinalityProvidersResponse)
@@ -73,14 +216,37 @@ func (c *StakerServiceJSONRPCClient) Stake(
 	stakingAmount int64,
 	fpPks []string,
 	stakingTimeBlocks int64,
+	tenant string,
+	template string,
+	reference string,
+	feeRate int64,
+	targetConf int64,
+	unbondingTime int64,
 ) (*service.ResultStake, error) {
 	result := new(service.ResultStake)
 
 	params := make(map[string]interface{})
 	params["stakerAddress"] = stakerAddress
 	params["stakingAmount"] = stakingAmount
-	params["fpBtcPks"] = fpPks
-	params["stakingTimeBlocks"] = stakingTimeBlocks
+	if len(fpPks) > 0 {
+		params["fpBtcPks"] = fpPks
+	}
+	if stakingTimeBlocks != 0 {
+		params["stakingTimeBlocks"] = stakingTimeBlocks
+	}
+	if tenant != "" {
+		params["tenant"] = tenant
+	}
+	if template != "" {
+		params["template"] = template
+	}
+	if reference != "" {
+		params["reference"] = reference
+	}
+	addFeeSelection(params, feeRate, targetConf)
+	if unbondingTime != 0 {
+		params["unbondingTime"] = unbondingTime
+	}
 
 	_, err := c.client.Call(ctx, "stake", params, result)
 	if err != nil {
@@ -89,6 +255,37 @@ func (c *StakerServiceJSONRPCClient) Stake(
 	return result, nil
 }
 
+// EstimateStake returns expected sizes and fees of transactions of stake
+// request without sending them
+func (c *StakerServiceJSONRPCClient) EstimateStake(
+	ctx context.Context,
+	stakerAddress string,
+	stakingAmount int64,
+	fpPks []string,
+	stakingTimeBlocks int64,
+	reference string,
+	feeRate int64,
+	targetConf int64,
+) (*service.StakeEstimateResponse, error) {
+	result := new(service.StakeEstimateResponse)
+
+	params := make(map[string]interface{})
+	params["stakerAddress"] = stakerAddress
+	params["stakingAmount"] = stakingAmount
+	params["fpBtcPks"] = fpPks
+	params["stakingTimeBlocks"] = stakingTimeBlocks
+	if reference != "" {
+		params["reference"] = reference
+	}
+	addFeeSelection(params, feeRate, targetConf)
+
+	_, err := c.client.Call(ctx, "estimate_stake", params, result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call estimate_stake: %w", err)
+	}
+	return result, nil
+}
+
 // StakeExpand expand a previous active stake transaction
 func (c *StakerServiceJSONRPCClient) StakeExpand(
 	ctx context.Context,
@@ -97,6 +294,7 @@ func (c *StakerServiceJSONRPCClient) StakeExpand(
 	fpPks []string,
 	stakingTimeBlocks int64,
 	prevActiveStkTxHashHex string,
+	tenant string,
 ) (*service.ResultStake, error) {
 	result := new(service.ResultStake)
 
@@ -106,6 +304,9 @@ func (c *StakerServiceJSONRPCClient) StakeExpand(
 	params["fpBtcPks"] = fpPks
 	params["stakingTimeBlocks"] = stakingTimeBlocks
 	params["prevActiveStkTxHashHex"] = prevActiveStkTxHashHex
+	if tenant != "" {
+		params["tenant"] = tenant
+	}
 
 	_, err := c.client.Call(ctx, "stake_expand", params, result)
 	if err != nil {
@@ -156,6 +357,43 @@ func (c *StakerServiceJSONRPCClient) BtcDelegationFromBtcStakingTx(
 	return result, nil
 }
 
+// RetryDelegationRegistration registers tracked delegation on babylon again
+// with the given covenant set
+func (c *StakerServiceJSONRPCClient) RetryDelegationRegistration(
+	ctx context.Context,
+	stakingTxHash string,
+	covPksHex []string,
+	covenantQuorum uint32,
+) (*service.ResultBtcDelegationFromBtcStakingTx, error) {
+	result := new(service.ResultBtcDelegationFromBtcStakingTx)
+
+	params := make(map[string]interface{})
+	params["stakingTxHash"] = stakingTxHash
+	params["covenantPksHex"] = covPksHex
+	params["covenantQuorum"] = covenantQuorum
+
+	_, err := c.client.Call(ctx, "retry_delegation_registration", params, result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call retry_delegation_registration: %w", err)
+	}
+	return result, nil
+}
+
+// RenotifyCovenant re-checks registration of the delegation whose covenant
+// signatures did not arrive
+func (c *StakerServiceJSONRPCClient) RenotifyCovenant(ctx context.Context, stakingTxHash string) (*service.RenotifyCovenantResponse, error) {
+	result := new(service.RenotifyCovenantResponse)
+
+	params := make(map[string]interface{})
+	params["stakingTxHash"] = stakingTxHash
+
+	_, err := c.client.Call(ctx, "renotify_covenant", params, result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call renotify_covenant: %w", err)
+	}
+	return result, nil
+}
+
 // BtcTxDetails returns a btc transaction and block details
 func (c *StakerServiceJSONRPCClient) BtcTxDetails(
 	ctx context.Context,
@@ -174,7 +412,15 @@ func (c *StakerServiceJSONRPCClient) BtcTxDetails(
 }
 
 // ListStakingTransactions returns a list of staking transactions
-func (c *StakerServiceJSONRPCClient) ListStakingTransactions(ctx context.Context, offset *int, limit *int) (*service.ListStakingTransactionsResponse, error) {
+func (c *StakerServiceJSONRPCClient) ListStakingTransactions(
+	ctx context.Context,
+	offset *int,
+	limit *int,
+	fields *string,
+	sortBy *string,
+	sortDirection *string,
+	tenant *string,
+) (*service.ListStakingTransactionsResponse, error) {
 	result := new(service.ListStakingTransactionsResponse)
 
 	params := make(map[string]interface{})
@@ -187,6 +433,22 @@ func (c *StakerServiceJSONRPCClient) ListStakingTransactions(ctx context.Context
 		params["offset"] = offset
 	}
 
+	if fields != nil {
+		params["fields"] = fields
+	}
+
+	if sortBy != nil {
+		params["sortBy"] = sortBy
+	}
+
+	if sortDirection != nil {
+		params["sortDirection"] = sortDirection
+	}
+
+	if tenant != nil {
+		params["tenant"] = tenant
+	}
+
 	_, err := c.client.Call(ctx, "list_staking_transactions", params, result)
 	if err != nil {
 		return nil, fmt.Errorf("failed to call list_staking_transactions: %w", err)
@@ -194,8 +456,9 @@ func (c *StakerServiceJSONRPCClient) ListStakingTransactions(ctx context.Context
 	return result, nil
 }
 
-// WithdrawableTransactions returns a list of withdrawable transactions
-func (c *StakerServiceJSONRPCClient) WithdrawableTransactions(ctx context.Context, offset *int, limit *int) (*service.WithdrawableTransactionsResponse, error) {
+// WithdrawableTransactions returns a list of withdrawable transactions. If
+// tenant is not empty, only transactions of this tenant are returned.
+func (c *StakerServiceJSONRPCClient) WithdrawableTransactions(ctx context.Context, offset *int, limit *int, fields *string, tenant string) (*service.WithdrawableTransactionsResponse, error) {
 	result := new(service.WithdrawableTransactionsResponse)
 
 	params := make(map[string]interface{})
@@ -208,6 +471,14 @@ func (c *StakerServiceJSONRPCClient) WithdrawableTransactions(ctx context.Contex
 		params["offset"] = offset
 	}
 
+	if fields != nil {
+		params["fields"] = fields
+	}
+
+	if tenant != "" {
+		params["tenant"] = tenant
+	}
+
 	_, err := c.client.Call(ctx, "withdrawable_transactions", params, result)
 	if err != nil {
 		return nil, fmt.Errorf("failed to call withdrawable_transactions: %w", err)
@@ -215,12 +486,76 @@ func (c *StakerServiceJSONRPCClient) WithdrawableTransactions(ctx context.Contex
 	return result, nil
 }
 
-// StakingDetails returns a staking details
-func (c *StakerServiceJSONRPCClient) StakingDetails(ctx context.Context, txHash string) (*service.StakingDetails, error) {
+// StakingActivity returns delegations and withdrawals grouped by time period
+func (c *StakerServiceJSONRPCClient) StakingActivity(ctx context.Context, period *string) (*service.StakingActivityResponse, error) {
+	result := new(service.StakingActivityResponse)
+
+	params := make(map[string]interface{})
+
+	if period != nil {
+		params["period"] = period
+	}
+
+	_, err := c.client.Call(ctx, "staking_activity", params, result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call staking_activity: %w", err)
+	}
+	return result, nil
+}
+
+// SubscribeDBChanges returns changes of the staker database recorded after
+// the resume token, waiting up to waitSecs if there are none
+func (c *StakerServiceJSONRPCClient) SubscribeDBChanges(
+	ctx context.Context,
+	resumeToken string,
+	limit int,
+	waitSecs int,
+) (*service.DBChangesResponse, error) {
+	result := new(service.DBChangesResponse)
+
+	params := make(map[string]interface{})
+	params["resumeToken"] = resumeToken
+	if limit > 0 {
+		params["limit"] = limit
+	}
+	if waitSecs > 0 {
+		params["waitSecs"] = waitSecs
+	}
+
+	_, err := c.client.Call(ctx, "subscribe_db_changes", params, result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call subscribe_db_changes: %w", err)
+	}
+	return result, nil
+}
+
+// ReplayEvents sends again webhook events with id greater than afterEventID
+func (c *StakerServiceJSONRPCClient) ReplayEvents(ctx context.Context, afterEventID uint64, limit int) (*service.ReplayEventsResponse, error) {
+	result := new(service.ReplayEventsResponse)
+
+	params := make(map[string]interface{})
+	params["afterEventId"] = afterEventID
+	if limit > 0 {
+		params["limit"] = limit
+	}
+
+	_, err := c.client.Call(ctx, "replay_events", params, result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call replay_events: %w", err)
+	}
+	return result, nil
+}
+
+// StakingDetails returns a staking details. If tenant is not empty, staking
+// transaction must belong to it.
+func (c *StakerServiceJSONRPCClient) StakingDetails(ctx context.Context, txHash string, tenant string) (*service.StakingDetails, error) {
 	result := new(service.StakingDetails)
 
 	params := make(map[string]interface{})
 	params["stakingTxHash"] = txHash
+	if tenant != "" {
+		params["tenant"] = tenant
+	}
 
 	_, err := c.client.Call(ctx, "staking_details", params, result)
 	if err != nil {
@@ -229,12 +564,59 @@ func (c *StakerServiceJSONRPCClient) StakingDetails(ctx context.Context, txHash
 	return result, nil
 }
 
-// SpendStakingTransaction returns a spend staking transaction details
-func (c *StakerServiceJSONRPCClient) SpendStakingTransaction(ctx context.Context, txHash string) (*service.SpendTxDetails, error) {
+// StakingDetailsBatch returns details of multiple staking transactions,
+// transactions whose details can't be returned are listed as failed
+func (c *StakerServiceJSONRPCClient) StakingDetailsBatch(ctx context.Context, txHashes []string, tenant string) (*service.StakingDetailsBatchResponse, error) {
+	result := new(service.StakingDetailsBatchResponse)
+
+	params := make(map[string]interface{})
+	params["stakingTxHashes"] = txHashes
+	if tenant != "" {
+		params["tenant"] = tenant
+	}
+
+	_, err := c.client.Call(ctx, "staking_details_batch", params, result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call staking_details_batch: %w", err)
+	}
+	return result, nil
+}
+
+// FindDelegationByTx returns tracked delegation to which staking, unbonding or
+// withdrawal transaction with given hash belongs
+func (c *StakerServiceJSONRPCClient) FindDelegationByTx(ctx context.Context, txHash string, tenant string) (*service.FindDelegationByTxResponse, error) {
+	result := new(service.FindDelegationByTxResponse)
+
+	params := make(map[string]interface{})
+	params["txHash"] = txHash
+	if tenant != "" {
+		params["tenant"] = tenant
+	}
+
+	_, err := c.client.Call(ctx, "find_delegation_by_tx", params, result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call find_delegation_by_tx: %w", err)
+	}
+	return result, nil
+}
+
+// SpendStakingTransaction returns a spend staking transaction details. Zero
+// fee rate, target confirmation count and tenant are not sent.
+func (c *StakerServiceJSONRPCClient) SpendStakingTransaction(
+	ctx context.Context,
+	txHash string,
+	feeRate int64,
+	targetConf int64,
+	tenant string,
+) (*service.SpendTxDetails, error) {
 	result := new(service.SpendTxDetails)
 
 	params := make(map[string]interface{})
 	params["stakingTxHash"] = txHash
+	addFeeSelection(params, feeRate, targetConf)
+	if tenant != "" {
+		params["tenant"] = tenant
+	}
 
 	_, err := c.client.Call(ctx, "spend_stake", params, result)
 	if err != nil {
@@ -243,12 +625,58 @@ func (c *StakerServiceJSONRPCClient) SpendStakingTransaction(ctx context.Context
 	return result, nil
 }
 
-// UnbondStaking returns an unbond staking transaction details
-func (c *StakerServiceJSONRPCClient) UnbondStaking(ctx context.Context, txHash string) (*service.UnbondingResponse, error) {
+// RestakeFromUnbonded withdraws funds of unbonded stake and stakes them again
+func (c *StakerServiceJSONRPCClient) RestakeFromUnbonded(
+	ctx context.Context,
+	txHash string,
+	fpPks []string,
+	stakingTimeBlocks int64,
+) (*service.RestakeResponse, error) {
+	result := new(service.RestakeResponse)
+
+	params := make(map[string]interface{})
+	params["stakingTxHash"] = txHash
+	params["fpBtcPks"] = fpPks
+	params["stakingTimeBlocks"] = stakingTimeBlocks
+
+	_, err := c.client.Call(ctx, "restake_from_unbonded", params, result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call restake_from_unbonded: %w", err)
+	}
+	return result, nil
+}
+
+// CancelStake cancels staking transaction not yet confirmed on btc
+func (c *StakerServiceJSONRPCClient) CancelStake(ctx context.Context, txHash string) (*service.CancelStakeResponse, error) {
+	result := new(service.CancelStakeResponse)
+
+	params := make(map[string]interface{})
+	params["stakingTxHash"] = txHash
+
+	_, err := c.client.Call(ctx, "cancel_stake", params, result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call cancel_stake: %w", err)
+	}
+	return result, nil
+}
+
+// UnbondStaking returns an unbond staking transaction details. Empty tenant
+// is not sent.
+func (c *StakerServiceJSONRPCClient) UnbondStaking(
+	ctx context.Context,
+	txHash string,
+	feeRate int64,
+	targetConf int64,
+	tenant string,
+) (*service.UnbondingResponse, error) {
 	result := new(service.UnbondingResponse)
 
 	params := make(map[string]interface{})
 	params["stakingTxHash"] = txHash
+	addFeeSelection(params, feeRate, targetConf)
+	if tenant != "" {
+		params["tenant"] = tenant
+	}
 
 	_, err := c.client.Call(ctx, "unbond_staking", params, result)
 
@@ -271,3 +699,454 @@ func (c *StakerServiceJSONRPCClient) BtcStakingParamByBtcHeight(ctx context.Cont
 	}
 	return result, nil
 }
+
+// ArchivedParams returns Babylon params versions stored in params archive of the daemon
+func (c *StakerServiceJSONRPCClient) ArchivedParams(ctx context.Context) (*service.ArchivedParamsResponse, error) {
+	result := new(service.ArchivedParamsResponse)
+
+	params := make(map[string]interface{})
+
+	_, err := c.client.Call(ctx, "archived_params", params, result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call archived_params: %w", err)
+	}
+	return result, nil
+}
+
+// ReplicaStatus returns progress of replication of daemon running as read only
+// replica
+func (c *StakerServiceJSONRPCClient) ReplicaStatus(ctx context.Context) (*service.ReplicaStatusResponse, error) {
+	result := new(service.ReplicaStatusResponse)
+
+	params := make(map[string]interface{})
+
+	_, err := c.client.Call(ctx, "replica_status", params, result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call replica_status: %w", err)
+	}
+	return result, nil
+}
+
+// FinalityProviderQuotas returns usage and quotas of finality providers
+func (c *StakerServiceJSONRPCClient) FinalityProviderQuotas(ctx context.Context) (*service.FinalityProviderQuotasResponse, error) {
+	result := new(service.FinalityProviderQuotasResponse)
+
+	params := make(map[string]interface{})
+
+	_, err := c.client.Call(ctx, "finality_provider_quotas", params, result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call finality_provider_quotas: %w", err)
+	}
+	return result, nil
+}
+
+// QuarantinedRecords returns records of tracked transactions moved to
+// quarantine
+func (c *StakerServiceJSONRPCClient) QuarantinedRecords(ctx context.Context) (*service.QuarantinedRecordsResponse, error) {
+	result := new(service.QuarantinedRecordsResponse)
+
+	params := make(map[string]interface{})
+
+	_, err := c.client.Call(ctx, "quarantined_records", params, result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call quarantined_records: %w", err)
+	}
+	return result, nil
+}
+
+// ApproveOperation approves and executes operation waiting for approval
+func (c *StakerServiceJSONRPCClient) ApproveOperation(ctx context.Context, operationID string) (*service.OperationDetails, error) {
+	result := new(service.OperationDetails)
+
+	params := make(map[string]interface{})
+	params["operationId"] = operationID
+
+	_, err := c.client.Call(ctx, "approve_operation", params, result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call approve_operation: %w", err)
+	}
+	return result, nil
+}
+
+// ListOperations returns operations which required approval
+func (c *StakerServiceJSONRPCClient) ListOperations(ctx context.Context) (*service.OperationsResponse, error) {
+	result := new(service.OperationsResponse)
+
+	_, err := c.client.Call(ctx, "list_operations", map[string]interface{}{}, result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call list_operations: %w", err)
+	}
+	return result, nil
+}
+
+// DelegationTemplates returns delegation templates defined in config and created through the API
+func (c *StakerServiceJSONRPCClient) DelegationTemplates(ctx context.Context) (*service.DelegationTemplatesResponse, error) {
+	result := new(service.DelegationTemplatesResponse)
+
+	_, err := c.client.Call(ctx, "delegation_templates", map[string]interface{}{}, result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call delegation_templates: %w", err)
+	}
+	return result, nil
+}
+
+// SetDelegationTemplate creates or replaces delegation template. Labels are in format key:value.
+func (c *StakerServiceJSONRPCClient) SetDelegationTemplate(
+	ctx context.Context,
+	name string,
+	stakerAddress string,
+	fpPks []string,
+	stakingTimeBlocks int64,
+	maxFeeRate int64,
+	tenant string,
+	labels []string,
+) (*service.DelegationTemplateDetails, error) {
+	result := new(service.DelegationTemplateDetails)
+
+	params := make(map[string]interface{})
+	params["name"] = name
+	params["fpBtcPks"] = fpPks
+	params["stakingTimeBlocks"] = stakingTimeBlocks
+	params["labels"] = labels
+	if stakerAddress != "" {
+		params["stakerAddress"] = stakerAddress
+	}
+	if maxFeeRate != 0 {
+		params["maxFeeRate"] = maxFeeRate
+	}
+	if tenant != "" {
+		params["tenant"] = tenant
+	}
+
+	_, err := c.client.Call(ctx, "set_delegation_template", params, result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call set_delegation_template: %w", err)
+	}
+	return result, nil
+}
+
+// DeleteDelegationTemplate deletes delegation template created through the API
+func (c *StakerServiceJSONRPCClient) DeleteDelegationTemplate(ctx context.Context, name string) (*service.DelegationTemplatesResponse, error) {
+	result := new(service.DelegationTemplatesResponse)
+
+	params := make(map[string]interface{})
+	params["name"] = name
+
+	_, err := c.client.Call(ctx, "delete_delegation_template", params, result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call delete_delegation_template: %w", err)
+	}
+	return result, nil
+}
+
+// QueuedStakes returns stake requests waiting for Babylon to accept new
+// delegations
+func (c *StakerServiceJSONRPCClient) QueuedStakes(ctx context.Context) (*service.QueuedStakesResponse, error) {
+	result := new(service.QueuedStakesResponse)
+
+	_, err := c.client.Call(ctx, "queued_stakes", map[string]interface{}{}, result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call queued_stakes: %w", err)
+	}
+	return result, nil
+}
+
+// CancelQueuedStake removes stake request from the queue
+func (c *StakerServiceJSONRPCClient) CancelQueuedStake(ctx context.Context, id string) (*service.QueuedStakesResponse, error) {
+	result := new(service.QueuedStakesResponse)
+
+	params := make(map[string]interface{})
+	params["id"] = id
+
+	_, err := c.client.Call(ctx, "cancel_queued_stake", params, result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call cancel_queued_stake: %w", err)
+	}
+	return result, nil
+}
+
+// DelegationHistory returns persisted Babylon statuses of the delegation
+func (c *StakerServiceJSONRPCClient) DelegationHistory(ctx context.Context, stakingTxHash string) (*service.DelegationHistoryResponse, error) {
+	result := new(service.DelegationHistoryResponse)
+
+	params := make(map[string]interface{})
+	params["stakingTxHash"] = stakingTxHash
+
+	_, err := c.client.Call(ctx, "delegation_history", params, result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call delegation_history: %w", err)
+	}
+	return result, nil
+}
+
+// ExportDelegation returns everything staker knows about the delegation
+func (c *StakerServiceJSONRPCClient) ExportDelegation(ctx context.Context, stakingTxHash string) (*service.DelegationExportResponse, error) {
+	result := new(service.DelegationExportResponse)
+
+	params := make(map[string]interface{})
+	params["stakingTxHash"] = stakingTxHash
+
+	_, err := c.client.Call(ctx, "export_delegation", params, result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call export_delegation: %w", err)
+	}
+	return result, nil
+}
+
+// ImportDelegation starts tracking delegation from bundle returned by
+// ExportDelegation of other staker instance
+func (c *StakerServiceJSONRPCClient) ImportDelegation(ctx context.Context, bundle string) (*service.DelegationImportResponse, error) {
+	result := new(service.DelegationImportResponse)
+
+	params := make(map[string]interface{})
+	params["bundle"] = bundle
+
+	_, err := c.client.Call(ctx, "import_delegation", params, result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call import_delegation: %w", err)
+	}
+	return result, nil
+}
+
+// ListStuckDelegations returns delegations stuck in intermediate states
+func (c *StakerServiceJSONRPCClient) ListStuckDelegations(ctx context.Context) (*service.StuckDelegationsResponse, error) {
+	result := new(service.StuckDelegationsResponse)
+
+	_, err := c.client.Call(ctx, "list_stuck_delegations", map[string]interface{}{}, result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call list_stuck_delegations: %w", err)
+	}
+	return result, nil
+}
+
+// ActivationLatency returns activation latency breakdown of the delegation,
+// or statistics across active delegations if staking tx hash is empty
+func (c *StakerServiceJSONRPCClient) ActivationLatency(ctx context.Context, stakingTxHash string) (*service.ActivationLatencyResponse, error) {
+	result := new(service.ActivationLatencyResponse)
+
+	params := make(map[string]interface{})
+	if stakingTxHash != "" {
+		params["stakingTxHash"] = stakingTxHash
+	}
+
+	_, err := c.client.Call(ctx, "activation_latency", params, result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call activation_latency: %w", err)
+	}
+	return result, nil
+}
+
+// ExpiringDelegations returns active delegations whose staking timelock
+// expires within the given number of blocks
+func (c *StakerServiceJSONRPCClient) ExpiringDelegations(ctx context.Context, withinBlocks int64) (*service.ExpiringDelegationsResponse, error) {
+	result := new(service.ExpiringDelegationsResponse)
+
+	params := make(map[string]interface{})
+	params["withinBlocks"] = withinBlocks
+
+	_, err := c.client.Call(ctx, "expiring_delegations", params, result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call expiring_delegations: %w", err)
+	}
+	return result, nil
+}
+
+// SetAutoRenew enables or disables restaking of the delegation on expiry, zero
+// staking time keeps staking time of the delegation
+func (c *StakerServiceJSONRPCClient) SetAutoRenew(ctx context.Context, stakingTxHash string, enabled bool, stakingTimeBlocks int64) (*service.SetAutoRenewResponse, error) {
+	result := new(service.SetAutoRenewResponse)
+
+	params := make(map[string]interface{})
+	params["stakingTxHash"] = stakingTxHash
+	params["enabled"] = enabled
+	params["stakingTimeBlocks"] = stakingTimeBlocks
+
+	_, err := c.client.Call(ctx, "set_auto_renew", params, result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call set_auto_renew: %w", err)
+	}
+	return result, nil
+}
+
+// RenewalJobs returns renewal jobs of expired delegations with enabled auto
+// renew
+func (c *StakerServiceJSONRPCClient) RenewalJobs(ctx context.Context) (*service.RenewalJobsResponse, error) {
+	result := new(service.RenewalJobsResponse)
+
+	_, err := c.client.Call(ctx, "renewal_jobs", map[string]interface{}{}, result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call renewal_jobs: %w", err)
+	}
+	return result, nil
+}
+
+func (c *StakerServiceJSONRPCClient) ChainSafety(ctx context.Context) (*service.ChainSafetyResponse, error) {
+	result := new(service.ChainSafetyResponse)
+
+	_, err := c.client.Call(ctx, "chain_safety", map[string]interface{}{}, result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call chain_safety: %w", err)
+	}
+	return result, nil
+}
+
+func (c *StakerServiceJSONRPCClient) MaintenanceStatus(ctx context.Context) (*service.MaintenanceStatusResponse, error) {
+	result := new(service.MaintenanceStatusResponse)
+
+	_, err := c.client.Call(ctx, "maintenance_status", map[string]interface{}{}, result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call maintenance_status: %w", err)
+	}
+	return result, nil
+}
+
+func (c *StakerServiceJSONRPCClient) SetChainSafetyOverride(ctx context.Context, override bool) (*service.ChainSafetyResponse, error) {
+	result := new(service.ChainSafetyResponse)
+
+	params := make(map[string]interface{})
+	params["override"] = override
+
+	_, err := c.client.Call(ctx, "set_chain_safety_override", params, result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call set_chain_safety_override: %w", err)
+	}
+	return result, nil
+}
+
+// RegisterMuSig2Key aggregates hex encoded public keys of signers into MuSig2
+// staker key
+func (c *StakerServiceJSONRPCClient) RegisterMuSig2Key(ctx context.Context, name string, pubKeys []string) (*service.MuSig2KeyDetail, error) {
+	result := new(service.MuSig2KeyDetail)
+
+	params := make(map[string]interface{})
+	params["name"] = name
+	params["pubKeys"] = pubKeys
+
+	_, err := c.client.Call(ctx, "register_musig2_key", params, result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call register_musig2_key: %w", err)
+	}
+	return result, nil
+}
+
+func (c *StakerServiceJSONRPCClient) MuSig2Keys(ctx context.Context) (*service.MuSig2KeysResponse, error) {
+	result := new(service.MuSig2KeysResponse)
+
+	_, err := c.client.Call(ctx, "musig2_keys", map[string]interface{}{}, result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call musig2_keys: %w", err)
+	}
+	return result, nil
+}
+
+// StartMuSig2Session creates signing session of MuSig2 key. Staking tx hash,
+// staking tx hex and destination address are not sent if empty.
+func (c *StakerServiceJSONRPCClient) StartMuSig2Session(
+	ctx context.Context,
+	keyName string,
+	kind string,
+	stakingTxHash string,
+	stakingTxHex string,
+	destAddress string,
+	feeRate int64,
+	targetConf int64,
+) (*service.MuSig2SessionDetail, error) {
+	result := new(service.MuSig2SessionDetail)
+
+	params := make(map[string]interface{})
+	params["keyName"] = keyName
+	params["kind"] = kind
+	if stakingTxHash != "" {
+		params["stakingTxHash"] = stakingTxHash
+	}
+	if stakingTxHex != "" {
+		params["stakingTxHex"] = stakingTxHex
+	}
+	if destAddress != "" {
+		params["destAddress"] = destAddress
+	}
+	addFeeSelection(params, feeRate, targetConf)
+
+	_, err := c.client.Call(ctx, "start_musig2_session", params, result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call start_musig2_session: %w", err)
+	}
+	return result, nil
+}
+
+func (c *StakerServiceJSONRPCClient) MuSig2Sessions(ctx context.Context) (*service.MuSig2SessionsResponse, error) {
+	result := new(service.MuSig2SessionsResponse)
+
+	_, err := c.client.Call(ctx, "musig2_sessions", map[string]interface{}{}, result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call musig2_sessions: %w", err)
+	}
+	return result, nil
+}
+
+func (c *StakerServiceJSONRPCClient) MuSig2Session(ctx context.Context, id uint64) (*service.MuSig2SessionDetail, error) {
+	result := new(service.MuSig2SessionDetail)
+
+	params := make(map[string]interface{})
+	params["id"] = id
+
+	_, err := c.client.Call(ctx, "musig2_session", params, result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call musig2_session: %w", err)
+	}
+	return result, nil
+}
+
+// SubmitMuSig2Nonce submits hex encoded public nonce of signer of the session
+func (c *StakerServiceJSONRPCClient) SubmitMuSig2Nonce(
+	ctx context.Context,
+	id uint64,
+	signerPk string,
+	nonce string,
+) (*service.MuSig2SessionDetail, error) {
+	result := new(service.MuSig2SessionDetail)
+
+	params := make(map[string]interface{})
+	params["id"] = id
+	params["signerPk"] = signerPk
+	params["nonce"] = nonce
+
+	_, err := c.client.Call(ctx, "submit_musig2_nonce", params, result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call submit_musig2_nonce: %w", err)
+	}
+	return result, nil
+}
+
+// SubmitMuSig2PartialSig submits hex encoded partial signature of signer of
+// the session
+func (c *StakerServiceJSONRPCClient) SubmitMuSig2PartialSig(
+	ctx context.Context,
+	id uint64,
+	signerPk string,
+	partialSig string,
+) (*service.MuSig2SessionDetail, error) {
+	result := new(service.MuSig2SessionDetail)
+
+	params := make(map[string]interface{})
+	params["id"] = id
+	params["signerPk"] = signerPk
+	params["partialSig"] = partialSig
+
+	_, err := c.client.Call(ctx, "submit_musig2_partial_sig", params, result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call submit_musig2_partial_sig: %w", err)
+	}
+	return result, nil
+}
+
+// addFeeSelection adds fee rate in sat/vbyte or target confirmation count to
+// request params, zero values are not sent
+func addFeeSelection(params map[string]interface{}, feeRate int64, targetConf int64) {
+	if feeRate != 0 {
+		params["feeRate"] = feeRate
+	}
+	if targetConf != 0 {
+		params["targetConf"] = targetConf
+	}
+}
@@ -0,0 +1,139 @@
+package stakerservice
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/babylonlabs-io/btc-staker/stakerdb"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	rpctypes "github.com/cometbft/cometbft/rpc/jsonrpc/types"
+)
+
+const (
+	defaultDBChangesLimit = 100
+	maxDBChangesLimit     = 1000
+	// maximum long poll wait, kept below default rpc write timeout
+	maxDBChangesWait = 60 * time.Second
+)
+
+// parseResumeToken parses resume token returned by previous call. Empty token
+// means the start of the changelog.
+func parseResumeToken(token *string) (uint64, error) {
+	if token == nil || *token == "" {
+		return 0, nil
+	}
+
+	seq, err := strconv.ParseUint(*token, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid resume token %q", *token)
+	}
+
+	return seq, nil
+}
+
+func toDBChange(ch *stakerdb.Change) DBChange {
+	c := DBChange{
+		Seq:       ch.Seq,
+		Kind:      ch.Kind.String(),
+		Detail:    ch.Detail,
+		Timestamp: ch.Timestamp.UTC().Format(time.RFC3339Nano),
+	}
+
+	if ch.StakingTxHash != (chainhash.Hash{}) {
+		c.StakingTxHash = ch.StakingTxHash.String()
+	}
+
+	return c
+}
+
+// subscribeDBChanges returns changes of the staker database recorded after
+// the resume token. If there are none, it waits up to waitSecs for new ones.
+func (s *StakerService) subscribeDBChanges(
+	ctx *rpctypes.Context,
+	resumeToken *string,
+	limit *int,
+	waitSecs *int,
+) (*DBChangesResponse, error) {
+	afterSeq, err := parseResumeToken(resumeToken)
+	if err != nil {
+		return nil, err
+	}
+
+	numChanges := uint64(defaultDBChangesLimit)
+	if limit != nil {
+		if *limit <= 0 || *limit > maxDBChangesLimit {
+			return nil, fmt.Errorf("limit must be between 1 and %d", maxDBChangesLimit)
+		}
+		numChanges = uint64(*limit)
+	}
+
+	var wait time.Duration
+	if waitSecs != nil {
+		if *waitSecs < 0 {
+			return nil, fmt.Errorf("wait must not be negative")
+		}
+		wait = min(time.Duration(*waitSecs)*time.Second, maxDBChangesWait)
+	}
+
+	// subscribe before querying, so that change committed in between is not
+	// missed
+	notify := s.staker.DBChangesNotify()
+
+	changes, err := s.staker.DBChanges(afterSeq, numChanges)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(changes) == 0 && wait > 0 {
+		timer := time.NewTimer(wait)
+		defer timer.Stop()
+
+		select {
+		case <-notify:
+			changes, err = s.staker.DBChanges(afterSeq, numChanges)
+			if err != nil {
+				return nil, err
+			}
+		case <-timer.C:
+		case <-ctx.Context().Done():
+		}
+	}
+
+	resp := &DBChangesResponse{
+		Changes:     []DBChange{},
+		ResumeToken: strconv.FormatUint(afterSeq, 10),
+	}
+
+	for i := range changes {
+		resp.Changes = append(resp.Changes, toDBChange(&changes[i]))
+	}
+
+	if len(changes) > 0 {
+		resp.ResumeToken = strconv.FormatUint(changes[len(changes)-1].Seq, 10)
+	}
+
+	return resp, nil
+}
+
+// replayEvents sends again webhook events with id greater than afterEventId,
+// so that webhook consumer can recover events missed while it was down
+func (s *StakerService) replayEvents(_ *rpctypes.Context, afterEventID uint64, limit *int) (*ReplayEventsResponse, error) {
+	numEvents := uint64(defaultDBChangesLimit)
+	if limit != nil {
+		if *limit <= 0 || *limit > maxDBChangesLimit {
+			return nil, fmt.Errorf("limit must be between 1 and %d", maxDBChangesLimit)
+		}
+		numEvents = uint64(*limit)
+	}
+
+	replayed, lastID, err := s.staker.ReplayWebhookEvents(afterEventID, numEvents)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ReplayEventsResponse{
+		Replayed:    replayed,
+		LastEventID: lastID,
+	}, nil
+}
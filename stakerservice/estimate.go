@@ -0,0 +1,77 @@
+package stakerservice
+
+import (
+	"strconv"
+
+	str "github.com/babylonlabs-io/btc-staker/staker"
+	rpctypes "github.com/cometbft/cometbft/rpc/jsonrpc/types"
+)
+
+// TxSizeDetails is expected size and fee of signed transaction
+type TxSizeDetails struct {
+	VSize      int64  `json:"vsize"`
+	Weight     int64  `json:"weight"`
+	NumInputs  int    `json:"num_inputs"`
+	NumOutputs int    `json:"num_outputs"`
+	Fee        string `json:"fee"`
+	// fee rate in sat/vB
+	FeeRate string `json:"fee_rate"`
+}
+
+// StakeEstimateResponse previews transactions of a stake request
+type StakeEstimateResponse struct {
+	// fee rate selected for staking transaction in sat/vB
+	FeeRate string `json:"fee_rate"`
+	// unbonding transaction fee is fixed by babylon params
+	StakingTx   TxSizeDetails `json:"staking_tx"`
+	UnbondingTx TxSizeDetails `json:"unbonding_tx"`
+}
+
+func txSizeDetails(e *str.TxSizeEstimate) TxSizeDetails {
+	return TxSizeDetails{
+		VSize:      e.VSize,
+		Weight:     e.Weight,
+		NumInputs:  e.NumInputs,
+		NumOutputs: e.NumOutputs,
+		Fee:        e.Fee.String(),
+		FeeRate:    strconv.FormatFloat(e.FeeRate(), 'f', 2, 64),
+	}
+}
+
+// estimateStake builds transactions of stake request without sending them and
+// returns their expected sizes and fees
+func (s *StakerService) estimateStake(_ *rpctypes.Context,
+	stakerAddress string,
+	stakingAmount int64,
+	fpBtcPks []string,
+	stakingTimeBlocks int64,
+	reference *string,
+	feeRate *int64,
+	targetConf *int64,
+) (*StakeEstimateResponse, error) {
+	var ref string
+	if reference != nil {
+		ref = *reference
+	}
+
+	fee, err := parseFeeSelection(feeRate, targetConf)
+	if err != nil {
+		return nil, err
+	}
+
+	amount, stakerAddr, fpPubKeys, stakingTime, err := parseStkParams(stakerAddress, &s.config.ActiveNetParams, stakingAmount, fpBtcPks, stakingTimeBlocks)
+	if err != nil {
+		return nil, err
+	}
+
+	estimate, err := s.staker.EstimateStake(stakerAddr, amount, fpPubKeys, stakingTime, ref, fee)
+	if err != nil {
+		return nil, err
+	}
+
+	return &StakeEstimateResponse{
+		FeeRate:     strconv.FormatFloat(float64(estimate.FeeRate)/1000, 'f', 2, 64),
+		StakingTx:   txSizeDetails(&estimate.StakingTx),
+		UnbondingTx: txSizeDetails(&estimate.UnbondingTx),
+	}, nil
+}
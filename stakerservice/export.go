@@ -0,0 +1,125 @@
+package stakerservice
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/babylonlabs-io/btc-staker/utils"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	rpctypes "github.com/cometbft/cometbft/rpc/jsonrpc/types"
+)
+
+// exportDelegation returns single bundle with everything staker knows about
+// the delegation, to be attached to support tickets and audits
+func (s *StakerService) exportDelegation(_ *rpctypes.Context, stakingTxHash string) (*DelegationExportResponse, error) {
+	txHash, err := chainhash.NewHashFromStr(stakingTxHash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse string type of hash to chainhash.Hash: %w", err)
+	}
+
+	export, err := s.staker.ExportDelegation(txHash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to export delegation %s: %w", stakingTxHash, err)
+	}
+
+	stakingTxHex, err := txToHex(export.StoredTx.StakingTx)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &DelegationExportResponse{
+		StakingTxHash:   txHash.String(),
+		StakerAddress:   export.StoredTx.StakerAddress,
+		StakingTxHex:    stakingTxHex,
+		Tenant:          export.Tenant,
+		Labels:          export.Labels,
+		CreatedHeight:   export.CreatedHeight,
+		StakingFee:      export.Fees.StakingFee.String(),
+		UnbondingFee:    export.Fees.UnbondingFee.String(),
+		WithdrawalFee:   export.Fees.WithdrawalFee.String(),
+		BtcTransactions: []ExportedBtcTx{},
+		BabylonTxs:      []ExportedBabylonTx{},
+		Statuses:        toStatusSnapshots(export.StatusHistory),
+		Events:          []DBChange{},
+		ExportedAt:      time.Now().UTC().Format(time.RFC3339),
+	}
+
+	if export.Failure != nil {
+		resp.FailureReason = export.Failure.Reason
+		resp.FailedAt = export.Failure.Timestamp.UTC().Format(time.RFC3339)
+	}
+
+	if export.Timing != nil {
+		resp.RegisteredAt = formatOptionalTime(export.Timing.RegisteredAt)
+		resp.CovenantQuorumAt = formatOptionalTime(export.Timing.QuorumReachedAt)
+	}
+	resp.UnbondRequestedAt = formatOptionalTime(export.UnbondRequestedAt)
+
+	for _, w := range export.WatchedTxs {
+		txHex, err := txToHex(w.Tx)
+		if err != nil {
+			return nil, err
+		}
+
+		resp.BtcTransactions = append(resp.BtcTransactions, ExportedBtcTx{
+			TxHash:          w.Tx.TxHash().String(),
+			Kind:            w.Kind.String(),
+			TxHex:           txHex,
+			BroadcastAt:     formatOptionalTime(w.BroadcastAt),
+			SeenInMempoolAt: formatOptionalTime(w.SeenInMempoolAt),
+			EvictedAt:       formatOptionalTime(w.EvictedAt),
+			Rebroadcasts:    w.Rebroadcasts,
+		})
+	}
+
+	if export.StakingTxConfirmation != nil {
+		resp.Confirmation = &ExportedConfirmation{
+			BlockHash:         export.StakingTxConfirmation.BlockHash.String(),
+			BlockHeight:       export.StakingTxConfirmation.BlockHeight,
+			TxIndex:           export.StakingTxConfirmation.TxIndex,
+			InclusionProofHex: hex.EncodeToString(export.StakingTxConfirmation.InclusionProof),
+		}
+	}
+	if export.ConfirmationErr != nil {
+		resp.ConfirmationError = export.ConfirmationErr.Error()
+	}
+
+	for _, tx := range export.BabylonTxs {
+		exported := ExportedBabylonTx{
+			Kind:   tx.Kind,
+			TxHash: tx.TxHash,
+			Height: tx.Height,
+			SentAt: tx.SentAt.UTC().Format(time.RFC3339),
+		}
+		if len(tx.Msg) > 0 {
+			msgHash := sha256.Sum256(tx.Msg)
+			exported.MsgTypeURL = tx.MsgTypeURL
+			exported.MsgHex = hex.EncodeToString(tx.Msg)
+			exported.MsgSha256 = hex.EncodeToString(msgHash[:])
+		}
+		resp.BabylonTxs = append(resp.BabylonTxs, exported)
+	}
+
+	resp.BabylonDelegation = export.BabylonDelegation
+	if export.BabylonErr != nil {
+		resp.BabylonError = export.BabylonErr.Error()
+	}
+
+	for i := range export.Changes {
+		resp.Events = append(resp.Events, toDBChange(&export.Changes[i]))
+	}
+
+	return resp, nil
+}
+
+func txToHex(tx *wire.MsgTx) (string, error) {
+	serialized, err := utils.SerializeBtcTransaction(tx)
+	if err != nil {
+		return "", fmt.Errorf("failed to serialize transaction: %w", err)
+	}
+
+	return hex.EncodeToString(serialized), nil
+}
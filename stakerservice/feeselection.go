@@ -0,0 +1,30 @@
+package stakerservice
+
+import (
+	"fmt"
+	"math"
+
+	str "github.com/babylonlabs-io/btc-staker/staker"
+)
+
+// parseFeeSelection converts optional fee rate in sat/vbyte and confirmation
+// target of request to fee selection
+func parseFeeSelection(feeRate *int64, targetConf *int64) (str.FeeSelection, error) {
+	var fee str.FeeSelection
+
+	if feeRate != nil {
+		if *feeRate <= 0 {
+			return fee, fmt.Errorf("fee rate must be positive")
+		}
+		fee.FeeRate = uint64(*feeRate)
+	}
+
+	if targetConf != nil {
+		if *targetConf <= 0 || *targetConf > math.MaxUint32 {
+			return fee, fmt.Errorf("invalid target confirmation count %d", *targetConf)
+		}
+		fee.TargetConf = uint32(*targetConf)
+	}
+
+	return fee, fee.Validate()
+}
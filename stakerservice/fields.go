@@ -0,0 +1,91 @@
+package stakerservice
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	FieldStakingTxHash  = "stakingTxHash"
+	FieldStakerAddress  = "stakerAddress"
+	FieldState          = "state"
+	FieldTransactionIdx = "transactionIdx"
+	FieldStakingAmount  = "stakingAmount"
+	FieldFee            = "fee"
+	FieldUnbondingFee   = "unbondingFee"
+	// FieldSpendableHeight is btc height at which unbonded funds become
+	// spendable, set once unbonding transaction is confirmed
+	FieldSpendableHeight = "spendableHeight"
+)
+
+var allStakingDetailsFields = []string{
+	FieldStakingTxHash,
+	FieldStakerAddress,
+	FieldState,
+	FieldTransactionIdx,
+	FieldStakingAmount,
+	FieldFee,
+	FieldUnbondingFee,
+	FieldSpendableHeight,
+}
+
+// FieldSelection is a set of StakingDetails fields requested by the caller
+type FieldSelection map[string]struct{}
+
+// AllFields returns a selection which contains every StakingDetails field
+func AllFields() FieldSelection {
+	sel := make(FieldSelection, len(allStakingDetailsFields))
+	for _, f := range allStakingDetailsFields {
+		sel[f] = struct{}{}
+	}
+	return sel
+}
+
+// ParseFieldSelection parses comma separated list of field names. Empty string
+// selects all fields.
+func ParseFieldSelection(fields string) (FieldSelection, error) {
+	if strings.TrimSpace(fields) == "" {
+		return AllFields(), nil
+	}
+
+	known := AllFields()
+	sel := make(FieldSelection)
+	for _, f := range strings.Split(fields, ",") {
+		f = strings.TrimSpace(f)
+		if f == "" {
+			continue
+		}
+
+		if _, ok := known[f]; !ok {
+			return nil, fmt.Errorf("unknown field %q, supported fields: %s", f, strings.Join(allStakingDetailsFields, ","))
+		}
+
+		sel[f] = struct{}{}
+	}
+
+	if len(sel) == 0 {
+		return nil, fmt.Errorf("no valid fields provided")
+	}
+
+	return sel, nil
+}
+
+// Has returns true if field was selected
+func (s FieldSelection) Has(field string) bool {
+	_, ok := s[field]
+	return ok
+}
+
+// needsAmounts returns true if any of the amount fields was selected
+func (s FieldSelection) needsAmounts() bool {
+	return s.Has(FieldStakingAmount) || s.Has(FieldFee) || s.Has(FieldUnbondingFee)
+}
+
+// getFieldSelection parses optional fields parameter of list endpoints
+func getFieldSelection(fieldsPtr *string) (FieldSelection, error) {
+	if fieldsPtr == nil {
+		return AllFields(), nil
+	}
+
+	return ParseFieldSelection(*fieldsPtr)
+}
@@ -0,0 +1,34 @@
+package stakerservice
+
+import (
+	"fmt"
+
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	rpctypes "github.com/cometbft/cometbft/rpc/jsonrpc/types"
+)
+
+// findDelegationByTx returns tracked delegation to which staking, unbonding
+// or withdrawal transaction with given hash belongs, together with its
+// details. If tenant is given, delegation must belong to it.
+func (s *StakerService) findDelegationByTx(ctx *rpctypes.Context, txHash string, tenant *string) (*FindDelegationByTxResponse, error) {
+	hash, err := chainhash.NewHashFromStr(txHash)
+	if err != nil {
+		return nil, fmt.Errorf("invalid transaction hash: %w", err)
+	}
+
+	found, err := s.staker.FindDelegationByTx(hash)
+	if err != nil {
+		return nil, err
+	}
+
+	details, err := s.stakingDetails(ctx, found.StakingTxHash.String(), tenant)
+	if err != nil {
+		return nil, err
+	}
+
+	return &FindDelegationByTxResponse{
+		TxHash:         hash.String(),
+		Kind:           found.Kind.String(),
+		StakingDetails: *details,
+	}, nil
+}
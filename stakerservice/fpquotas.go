@@ -0,0 +1,34 @@
+package stakerservice
+
+import (
+	rpctypes "github.com/cometbft/cometbft/rpc/jsonrpc/types"
+)
+
+// finalityProviderQuotas returns usage of finality providers by delegations
+// of the staker together with their configured quotas
+func (s *StakerService) finalityProviderQuotas(_ *rpctypes.Context) (*FinalityProviderQuotasResponse, error) {
+	usages, err := s.staker.FinalityProviderQuotas()
+	if err != nil {
+		return nil, err
+	}
+
+	providers := make([]FinalityProviderQuotaDetails, 0, len(usages))
+	for _, u := range usages {
+		details := FinalityProviderQuotaDetails{
+			FpBtcPk:     u.FpBtcPk,
+			Delegations: u.Delegations,
+			AmountSat:   int64(u.Amount),
+		}
+
+		if u.Quota != nil {
+			details.MaxDelegations = u.Quota.MaxDelegations
+			details.MaxAmountSat = int64(u.Quota.MaxAmount)
+		}
+
+		providers = append(providers, details)
+	}
+
+	return &FinalityProviderQuotasResponse{
+		FinalityProviders: providers,
+	}, nil
+}
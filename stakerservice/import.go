@@ -0,0 +1,198 @@
+package stakerservice
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/babylonlabs-io/btc-staker/stakerdb"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/wire"
+	rpctypes "github.com/cometbft/cometbft/rpc/jsonrpc/types"
+	"github.com/sirupsen/logrus"
+)
+
+// importDelegation starts tracking delegation from bundle returned by
+// export_delegation of other staker instance
+func (s *StakerService) importDelegation(_ *rpctypes.Context, bundle string) (*DelegationImportResponse, error) {
+	var export DelegationExportResponse
+	if err := json.Unmarshal([]byte(bundle), &export); err != nil {
+		return nil, fmt.Errorf("failed to decode delegation bundle: %w", err)
+	}
+
+	imported, err := toImportedTransaction(&export)
+	if err != nil {
+		return nil, err
+	}
+
+	state, err := s.staker.ImportDelegation(export.StakerAddress, imported)
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"stakingTxHash": export.StakingTxHash,
+		"state":         state,
+	}).Info("Delegation imported over rpc")
+
+	return &DelegationImportResponse{
+		StakingTxHash: export.StakingTxHash,
+		StakingState:  state,
+	}, nil
+}
+
+// toImportedTransaction validates exported bundle and converts it to data
+// stored by the staker
+func toImportedTransaction(export *DelegationExportResponse) (*stakerdb.ImportedTransaction, error) {
+	if export.FailureReason != "" {
+		return nil, fmt.Errorf("delegation failed on exporting staker: %s", export.FailureReason)
+	}
+
+	return decodeDelegationBundle(export)
+}
+
+// decodeDelegationBundle converts exported bundle to data stored by the
+// staker, failure of the delegation is not part of the result
+func decodeDelegationBundle(export *DelegationExportResponse) (*stakerdb.ImportedTransaction, error) {
+	txBytes, err := hex.DecodeString(export.StakingTxHex)
+	if err != nil {
+		return nil, fmt.Errorf("invalid staking transaction hex: %w", err)
+	}
+
+	var stakingTx wire.MsgTx
+	if err := stakingTx.Deserialize(bytes.NewReader(txBytes)); err != nil {
+		return nil, fmt.Errorf("invalid staking transaction: %w", err)
+	}
+
+	if stakingTx.TxHash().String() != export.StakingTxHash {
+		return nil, fmt.Errorf("staking transaction hash %s does not match staking_tx_hash %s",
+			stakingTx.TxHash(), export.StakingTxHash)
+	}
+
+	imported := &stakerdb.ImportedTransaction{
+		StakingTx:      &stakingTx,
+		Tenant:         export.Tenant,
+		Labels:         export.Labels,
+		CreationHeight: export.CreatedHeight,
+	}
+
+	if export.Tenant != "" {
+		if err := stakerdb.ValidateTenant(export.Tenant); err != nil {
+			return nil, err
+		}
+	}
+
+	fees := []struct {
+		name  string
+		value string
+		dst   *btcutil.Amount
+	}{
+		{"staking_fee", export.StakingFee, &imported.StakingFee},
+		{"unbonding_fee", export.UnbondingFee, &imported.UnbondingFee},
+		{"withdrawal_fee", export.WithdrawalFee, &imported.WithdrawalFee},
+	}
+	for _, fee := range fees {
+		if *fee.dst, err = parseBtcAmount(fee.value); err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", fee.name, err)
+		}
+	}
+
+	if imported.Timing.RegisteredAt, err = parseOptionalTime(export.RegisteredAt); err != nil {
+		return nil, fmt.Errorf("invalid registered_at: %w", err)
+	}
+
+	if imported.Timing.QuorumReachedAt, err = parseOptionalTime(export.CovenantQuorumAt); err != nil {
+		return nil, fmt.Errorf("invalid covenant_quorum_at: %w", err)
+	}
+
+	for _, st := range export.Statuses {
+		snapshot := stakerdb.StatusSnapshot{
+			State:       st.State,
+			BtcHeight:   st.BtcHeight,
+			StartHeight: st.StartHeight,
+			EndHeight:   st.EndHeight,
+		}
+
+		if snapshot.FirstSeenAt, err = parseOptionalTime(st.EnteredBy); err != nil {
+			return nil, fmt.Errorf("invalid entered_by of status %s: %w", st.State, err)
+		}
+
+		if snapshot.LastSeenAt, err = parseOptionalTime(st.LastSeenAt); err != nil {
+			return nil, fmt.Errorf("invalid last_seen_at of status %s: %w", st.State, err)
+		}
+
+		if snapshot.PreviousSeenAt, err = parseOptionalTime(st.EnteredAfter); err != nil {
+			return nil, fmt.Errorf("invalid entered_after of status %s: %w", st.State, err)
+		}
+
+		imported.StatusHistory = append(imported.StatusHistory, snapshot)
+	}
+
+	for _, tx := range export.BabylonTxs {
+		sentAt, err := parseOptionalTime(tx.SentAt)
+		if err != nil {
+			return nil, fmt.Errorf("invalid sent_at of babylon transaction %s: %w", tx.TxHash, err)
+		}
+
+		msg, err := hex.DecodeString(tx.MsgHex)
+		if err != nil {
+			return nil, fmt.Errorf("invalid msg_hex of babylon transaction %s: %w", tx.TxHash, err)
+		}
+
+		if tx.MsgSha256 != "" {
+			msgHash := sha256.Sum256(msg)
+			if hex.EncodeToString(msgHash[:]) != strings.ToLower(tx.MsgSha256) {
+				return nil, fmt.Errorf("msg_sha256 of babylon transaction %s does not match its message", tx.TxHash)
+			}
+		}
+
+		imported.BabylonTxs = append(imported.BabylonTxs, stakerdb.BabylonTx{
+			Kind:       tx.Kind,
+			TxHash:     tx.TxHash,
+			Height:     tx.Height,
+			SentAt:     sentAt,
+			MsgTypeURL: tx.MsgTypeURL,
+			Msg:        msg,
+		})
+	}
+
+	return imported, nil
+}
+
+// parseBtcAmount parses amount formatted by btcutil.Amount.String, empty
+// string is zero amount
+func parseBtcAmount(s string) (btcutil.Amount, error) {
+	if s == "" {
+		return 0, nil
+	}
+
+	value, err := strconv.ParseFloat(strings.TrimSuffix(s, " BTC"), 64)
+	if err != nil {
+		return 0, err
+	}
+
+	amount, err := btcutil.NewAmount(value)
+	if err != nil {
+		return 0, err
+	}
+
+	if amount < 0 {
+		return 0, fmt.Errorf("negative amount %s", s)
+	}
+
+	return amount, nil
+}
+
+// parseOptionalTime parses time formatted by formatOptionalTime
+func parseOptionalTime(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+
+	return time.Parse(time.RFC3339, s)
+}
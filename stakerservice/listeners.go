@@ -0,0 +1,215 @@
+package stakerservice
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/cometbft/cometbft/libs/log"
+	rpc "github.com/cometbft/cometbft/rpc/jsonrpc/server"
+	"github.com/sirupsen/logrus"
+)
+
+// rpcDrainTimeout bounds waiting for in-flight rpc requests on shutdown
+const rpcDrainTimeout = 30 * time.Second
+
+// ErrServiceNotRunning is returned when listeners are reloaded before service
+// started serving rpc requests
+var ErrServiceNotRunning = errors.New("staker service is not running")
+
+// rpcServer serves JSON-RPC handler on listeners which can be added and
+// removed while service runs
+type rpcServer struct {
+	handler http.Handler
+	logger  log.Logger
+	// listeners by network://address
+	listeners map[string]net.Listener
+
+	mu sync.Mutex
+	// set once shutdown started, new requests are rejected
+	draining bool
+	inflight sync.WaitGroup
+}
+
+// track counts in-flight requests, so that shutdown can wait for them. Closing
+// listeners is not enough, as kept alive connections still deliver requests.
+func (r *rpcServer) track(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		r.mu.Lock()
+		if r.draining {
+			r.mu.Unlock()
+			http.Error(w, "staker is shutting down", http.StatusServiceUnavailable)
+			return
+		}
+		r.inflight.Add(1)
+		r.mu.Unlock()
+		defer r.inflight.Done()
+
+		next.ServeHTTP(w, req)
+	})
+}
+
+// drain rejects new requests and waits for in-flight ones
+func (r *rpcServer) drain(timeout time.Duration) error {
+	r.mu.Lock()
+	r.draining = true
+	r.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		r.inflight.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-time.After(timeout):
+		return fmt.Errorf("in-flight rpc requests did not finish in %s", timeout)
+	}
+}
+
+func listenAddress(addr net.Addr) string {
+	return addr.Network() + "://" + addr.String()
+}
+
+// ReloadRPCListeners starts listening on new addresses and closes listeners of
+// addresses which are not given anymore. Listeners of unchanged addresses keep
+// serving without interruption, so staker does not need to be restarted.
+func (s *StakerService) ReloadRPCListeners(addrs []net.Addr) error {
+	s.rpcMu.Lock()
+	defer s.rpcMu.Unlock()
+
+	if s.rpc == nil {
+		return ErrServiceNotRunning
+	}
+
+	if len(addrs) == 0 {
+		return fmt.Errorf("at least one rpc listener is required")
+	}
+
+	wanted := make(map[string]struct{}, len(addrs))
+	for _, addr := range addrs {
+		wanted[listenAddress(addr)] = struct{}{}
+	}
+
+	// open new listeners first, so that failure leaves current ones intact
+	var added []string
+	for address := range wanted {
+		if _, ok := s.rpc.listeners[address]; ok {
+			continue
+		}
+
+		if err := s.startRPCListener(address); err != nil {
+			for _, a := range added {
+				s.stopRPCListener(a)
+			}
+			return err
+		}
+		added = append(added, address)
+	}
+
+	var removed []string
+	for address := range s.rpc.listeners {
+		if _, ok := wanted[address]; !ok {
+			s.stopRPCListener(address)
+			removed = append(removed, address)
+		}
+	}
+
+	s.config.RPCListeners = addrs
+
+	if len(added) > 0 || len(removed) > 0 {
+		sort.Strings(added)
+		sort.Strings(removed)
+		s.logger.WithFields(logrus.Fields{
+			"added":   added,
+			"removed": removed,
+		}).Info("Rpc listeners reloaded")
+	}
+
+	return nil
+}
+
+// startRPCListener starts serving rpc requests on given address, rpcMu must be
+// held
+func (s *StakerService) startRPCListener(address string) error {
+	listener, err := rpc.Listen(
+		address,
+		s.config.JSONRPCServerConfig.MaxOpenConnections,
+	)
+	if err != nil {
+		return fmt.Errorf("unable to listen on %s: %w", address, err)
+	}
+
+	s.rpc.listeners[address] = listener
+
+	// TODO: Add additional middleware, like CORS, TLS, etc.
+	go func() {
+		s.logger.Debug("Starting Json RPC HTTP server ", "address: ", address)
+
+		if err := rpc.Serve(
+			listener,
+			s.rpc.handler,
+			s.rpc.logger,
+			s.config.JSONRPCServerConfig.Config(),
+		); err != nil && !errors.Is(err, net.ErrClosed) {
+			s.logger.WithError(err).Error("problem at JSON RPC HTTP server")
+		}
+		s.logger.Info("Json RPC HTTP server stopped ", "address: ", address)
+	}()
+
+	return nil
+}
+
+// stopRPCListener closes listener of given address, rpcMu must be held
+func (s *StakerService) stopRPCListener(address string) {
+	listener, ok := s.rpc.listeners[address]
+	if !ok {
+		return
+	}
+
+	if err := listener.Close(); err != nil {
+		s.logger.Error("Error closing listener", "err", err)
+	}
+	delete(s.rpc.listeners, address)
+}
+
+// serveRPC starts serving handler on configured listeners
+func (s *StakerService) serveRPC(handler http.Handler, logger log.Logger) error {
+	r := &rpcServer{
+		logger:    logger,
+		listeners: make(map[string]net.Listener),
+	}
+	r.handler = r.track(handler)
+
+	s.rpcMu.Lock()
+	s.rpc = r
+	s.rpcMu.Unlock()
+
+	return s.ReloadRPCListeners(s.config.RPCListeners)
+}
+
+// stopRPC closes all listeners and waits for in-flight requests, so that
+// no request is processed once staker stops
+func (s *StakerService) stopRPC() error {
+	s.rpcMu.Lock()
+	r := s.rpc
+	if r != nil {
+		for address := range r.listeners {
+			s.stopRPCListener(address)
+		}
+		s.rpc = nil
+	}
+	s.rpcMu.Unlock()
+
+	if r == nil {
+		return nil
+	}
+
+	return r.drain(rpcDrainTimeout)
+}
@@ -0,0 +1,18 @@
+package stakerservice
+
+import (
+	rpctypes "github.com/cometbft/cometbft/rpc/jsonrpc/types"
+)
+
+// maintenanceStatus returns whether maintenance window is active and when the
+// next one starts
+func (s *StakerService) maintenanceStatus(_ *rpctypes.Context) (*MaintenanceStatusResponse, error) {
+	status := s.staker.MaintenanceStatus()
+
+	return &MaintenanceStatusResponse{
+		Active:    status.Active,
+		EndsAt:    formatOptionalTime(status.EndsAt),
+		NextStart: formatOptionalTime(status.NextStart),
+		Windows:   status.Windows,
+	}, nil
+}
@@ -0,0 +1,238 @@
+package stakerservice
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+
+	str "github.com/babylonlabs-io/btc-staker/staker"
+	"github.com/babylonlabs-io/btc-staker/stakerdb"
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	rpctypes "github.com/cometbft/cometbft/rpc/jsonrpc/types"
+)
+
+// registerMuSig2Key aggregates hex encoded public keys of signers into
+// MuSig2 staker key
+func (s *StakerService) registerMuSig2Key(_ *rpctypes.Context, name string, pubKeys []string) (*MuSig2KeyDetail, error) {
+	keys := make([]*btcec.PublicKey, 0, len(pubKeys))
+	for _, e := range pubKeys {
+		pk, err := parseSignerPubKey(e)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, pk)
+	}
+
+	key, err := s.staker.RegisterMuSig2Key(name, keys)
+	if err != nil {
+		return nil, err
+	}
+
+	detail := toMuSig2KeyDetail(key)
+	return &detail, nil
+}
+
+// listMuSig2Keys returns all registered MuSig2 staker keys
+func (s *StakerService) listMuSig2Keys(_ *rpctypes.Context) (*MuSig2KeysResponse, error) {
+	keys, err := s.staker.MuSig2Keys()
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &MuSig2KeysResponse{Keys: make([]MuSig2KeyDetail, 0, len(keys))}
+	for i := range keys {
+		resp.Keys = append(resp.Keys, toMuSig2KeyDetail(&keys[i]))
+	}
+
+	return resp, nil
+}
+
+// startMuSig2Session creates signing session of MuSig2 key. Staking session
+// requires hex encoded unsigned staking transaction. Unbonding and withdrawal
+// sessions require staking tx hash of the delegation, withdrawal additionally
+// requires destination address.
+func (s *StakerService) startMuSig2Session(
+	_ *rpctypes.Context,
+	keyName string,
+	kind string,
+	stakingTxHash *string,
+	stakingTxHex *string,
+	destAddress *string,
+	feeRate *int64,
+	targetConf *int64,
+) (*MuSig2SessionDetail, error) {
+	sessionKind, err := str.ParseMuSig2SessionKind(kind)
+	if err != nil {
+		return nil, err
+	}
+
+	req := &str.MuSig2SessionRequest{
+		KeyName: keyName,
+		Kind:    sessionKind,
+	}
+
+	if stakingTxHash != nil && *stakingTxHash != "" {
+		req.StakingTxHash, err = chainhash.NewHashFromStr(*stakingTxHash)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse string type of hash to chainhash.Hash: %w", err)
+		}
+	}
+
+	if stakingTxHex != nil && *stakingTxHex != "" {
+		txBytes, err := hex.DecodeString(*stakingTxHex)
+		if err != nil {
+			return nil, fmt.Errorf("staking transaction must be hex encoded: %w", err)
+		}
+
+		req.StakingTx = new(wire.MsgTx)
+		if err := req.StakingTx.Deserialize(bytes.NewReader(txBytes)); err != nil {
+			return nil, fmt.Errorf("error decoding staking transaction: %w", err)
+		}
+	}
+
+	if destAddress != nil && *destAddress != "" {
+		req.DestAddress, err = btcutil.DecodeAddress(*destAddress, &s.config.ActiveNetParams)
+		if err != nil {
+			return nil, fmt.Errorf("invalid destination address: %w", err)
+		}
+	}
+
+	req.Fee, err = parseFeeSelection(feeRate, targetConf)
+	if err != nil {
+		return nil, err
+	}
+
+	session, err := s.staker.StartMuSig2Session(req)
+	if err != nil {
+		return nil, err
+	}
+
+	detail := toMuSig2SessionDetail(session)
+	return &detail, nil
+}
+
+// listMuSig2Sessions returns all MuSig2 signing sessions
+func (s *StakerService) listMuSig2Sessions(_ *rpctypes.Context) (*MuSig2SessionsResponse, error) {
+	sessions, err := s.staker.MuSig2Sessions()
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &MuSig2SessionsResponse{Sessions: make([]MuSig2SessionDetail, 0, len(sessions))}
+	for i := range sessions {
+		resp.Sessions = append(resp.Sessions, toMuSig2SessionDetail(&sessions[i]))
+	}
+
+	return resp, nil
+}
+
+// muSig2Session returns MuSig2 signing session with given id
+func (s *StakerService) muSig2Session(_ *rpctypes.Context, id int64) (*MuSig2SessionDetail, error) {
+	if id < 0 {
+		return nil, fmt.Errorf("invalid session id %d", id)
+	}
+
+	session, err := s.staker.MuSig2Session(uint64(id))
+	if err != nil {
+		return nil, err
+	}
+
+	detail := toMuSig2SessionDetail(session)
+	return &detail, nil
+}
+
+// submitMuSig2Nonce stores hex encoded public nonce of signer of the session
+func (s *StakerService) submitMuSig2Nonce(_ *rpctypes.Context, id int64, signerPk string, nonce string) (*MuSig2SessionDetail, error) {
+	if id < 0 {
+		return nil, fmt.Errorf("invalid session id %d", id)
+	}
+
+	signer, err := parseSignerPubKey(signerPk)
+	if err != nil {
+		return nil, err
+	}
+
+	nonceBytes, err := hex.DecodeString(nonce)
+	if err != nil {
+		return nil, fmt.Errorf("nonce must be hex encoded: %w", err)
+	}
+
+	session, err := s.staker.SubmitMuSig2Nonce(uint64(id), signer, nonceBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	detail := toMuSig2SessionDetail(session)
+	return &detail, nil
+}
+
+// submitMuSig2PartialSig stores hex encoded partial signature of signer of
+// the session
+func (s *StakerService) submitMuSig2PartialSig(_ *rpctypes.Context, id int64, signerPk string, partialSig string) (*MuSig2SessionDetail, error) {
+	if id < 0 {
+		return nil, fmt.Errorf("invalid session id %d", id)
+	}
+
+	signer, err := parseSignerPubKey(signerPk)
+	if err != nil {
+		return nil, err
+	}
+
+	sigBytes, err := hex.DecodeString(partialSig)
+	if err != nil {
+		return nil, fmt.Errorf("partial signature must be hex encoded: %w", err)
+	}
+
+	session, err := s.staker.SubmitMuSig2PartialSig(uint64(id), signer, sigBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	detail := toMuSig2SessionDetail(session)
+	return &detail, nil
+}
+
+func parseSignerPubKey(e string) (*btcec.PublicKey, error) {
+	b, err := hex.DecodeString(e)
+	if err != nil {
+		return nil, fmt.Errorf("signer public key must be hex encoded: %w", err)
+	}
+
+	pk, err := btcec.ParsePubKey(b)
+	if err != nil {
+		return nil, fmt.Errorf("invalid signer public key %s: %w", e, err)
+	}
+
+	return pk, nil
+}
+
+func toMuSig2KeyDetail(key *stakerdb.MuSig2Key) MuSig2KeyDetail {
+	return MuSig2KeyDetail{
+		Name:         key.Name,
+		PubKeys:      key.PubKeys,
+		AggregateKey: key.AggregateKey,
+		CreatedAt:    formatOptionalTime(key.CreatedAt),
+	}
+}
+
+func toMuSig2SessionDetail(session *stakerdb.MuSig2Session) MuSig2SessionDetail {
+	return MuSig2SessionDetail{
+		ID:              session.ID,
+		KeyName:         session.KeyName,
+		Kind:            session.Kind,
+		StakingTxHash:   session.StakingTxHash,
+		TxHex:           session.TxHex,
+		Message:         session.Message,
+		Nonces:          session.Nonces,
+		AggregateNonce:  session.AggregateNonce,
+		PartialSigs:     session.PartialSigs,
+		Signature:       session.Signature,
+		BroadcastTxHash: session.BroadcastTxHash,
+		Error:           session.Error,
+		CreatedAt:       formatOptionalTime(session.CreatedAt),
+		CompletedAt:     formatOptionalTime(session.CompletedAt),
+	}
+}
@@ -0,0 +1,53 @@
+package stakerservice
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/babylonlabs-io/btc-staker/staker"
+)
+
+// ReadinessCheckResponse is the result of one readiness check
+type ReadinessCheckResponse struct {
+	Name  string `json:"name"`
+	Ready bool   `json:"ready"`
+	Error string `json:"error,omitempty"`
+}
+
+// ReadinessResponse is the body of readiness probe
+type ReadinessResponse struct {
+	Ready  bool                     `json:"ready"`
+	Checks []ReadinessCheckResponse `json:"checks"`
+}
+
+// RegisterProbes registers liveness probe on /healthz and readiness probe on
+// /readyz. Probes do not require authentication, so that they can be used by
+// orchestrators like Kubernetes.
+func RegisterProbes(mux *http.ServeMux, readiness func() []staker.ReadinessCheck) {
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte("ok"))
+	})
+
+	mux.HandleFunc("/readyz", func(w http.ResponseWriter, _ *http.Request) {
+		resp := ReadinessResponse{Ready: true}
+		for _, c := range readiness() {
+			resp.Checks = append(resp.Checks, ReadinessCheckResponse{
+				Name:  c.Name,
+				Ready: c.Ready,
+				Error: c.Error,
+			})
+			resp.Ready = resp.Ready && c.Ready
+		}
+
+		status := http.StatusOK
+		if !resp.Ready {
+			status = http.StatusServiceUnavailable
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		_ = json.NewEncoder(w).Encode(resp)
+	})
+}
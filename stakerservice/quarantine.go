@@ -0,0 +1,37 @@
+package stakerservice
+
+import (
+	"encoding/hex"
+	"time"
+
+	rpctypes "github.com/cometbft/cometbft/rpc/jsonrpc/types"
+)
+
+// quarantinedRecords returns records of tracked transactions which failed to
+// decode or validate and are no longer served
+func (s *StakerService) quarantinedRecords(_ *rpctypes.Context) (*QuarantinedRecordsResponse, error) {
+	records, err := s.staker.QuarantinedRecords()
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &QuarantinedRecordsResponse{
+		Records: make([]QuarantinedRecordDetails, 0, len(records)),
+	}
+	for _, r := range records {
+		details := QuarantinedRecordDetails{
+			TxIdx:         r.TxIdx,
+			RecordHex:     hex.EncodeToString(r.Record),
+			ChecksumHex:   hex.EncodeToString(r.Checksum),
+			Reason:        r.Reason,
+			QuarantinedAt: r.QuarantinedAt.UTC().Format(time.RFC3339),
+		}
+		if r.StakingTxHash != nil {
+			details.StakingTxHash = r.StakingTxHash.String()
+		}
+
+		resp.Records = append(resp.Records, details)
+	}
+
+	return resp, nil
+}
@@ -0,0 +1,193 @@
+package stakerservice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	str "github.com/babylonlabs-io/btc-staker/staker"
+	scfg "github.com/babylonlabs-io/btc-staker/stakercfg"
+	"github.com/babylonlabs-io/btc-staker/stakerdb"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	jsonrpcclient "github.com/cometbft/cometbft/rpc/jsonrpc/client"
+	rpctypes "github.com/cometbft/cometbft/rpc/jsonrpc/types"
+)
+
+// mutatingRoutes are routes changing delegations, wallet or staker settings,
+// which are rejected by read only replica
+var mutatingRoutes = []string{
+	"stake",
+	"stake_expand",
+	"consolidate_utxos",
+	"btc_delegation_from_btc_staking_tx",
+	"retry_delegation_registration",
+	"renotify_covenant",
+	"spend_stake",
+	"restake_from_unbonded",
+	"cancel_stake",
+	"unbond_staking",
+	"replay_events",
+	"approve_operation",
+	"set_delegation_template",
+	"delete_delegation_template",
+	"cancel_queued_stake",
+	"set_auto_renew",
+	"set_chain_safety_override",
+	"import_delegation",
+	"register_musig2_key",
+	"start_musig2_session",
+	"submit_musig2_nonce",
+	"submit_musig2_partial_sig",
+	"unreserve_outpoint",
+	"new_staker_address",
+	"unlock_wallet",
+	"sign_message",
+	"sign_spend_tx",
+}
+
+// readOnlyRoutes replaces mutating routes with route rejecting every call, so
+// that clients get a clear error instead of unknown method
+func readOnlyRoutes(routes RoutesMap) RoutesMap {
+	for _, name := range mutatingRoutes {
+		if _, ok := routes[name]; ok {
+			routes[name] = NewRPCFunc(rejectOnReplica, "")
+		}
+	}
+
+	return routes
+}
+
+func rejectOnReplica(_ *rpctypes.Context) (*ResultHealth, error) {
+	return nil, str.ErrReadOnlyReplica
+}
+
+// replicaStatus returns progress of replication of the primary
+func (s *StakerService) replicaStatus(_ *rpctypes.Context) (*ReplicaStatusResponse, error) {
+	status, err := s.staker.ReplicaStatus()
+	if err != nil {
+		return nil, err
+	}
+
+	return &ReplicaStatusResponse{
+		Primary:    status.Primary,
+		AppliedSeq: status.AppliedSeq,
+		Replicated: status.Replicated,
+		CaughtUpAt: formatOptionalTime(status.CaughtUpAt),
+		LastError:  status.LastError,
+	}, nil
+}
+
+// primaryClient follows the primary daemon over its rpc api
+type primaryClient struct {
+	address string
+	client  *jsonrpcclient.Client
+	network *chaincfg.Params
+}
+
+var _ str.ReplicaSource = (*primaryClient)(nil)
+
+func newPrimaryClient(cfg *scfg.ReplicaConfig, network *chaincfg.Params) (*primaryClient, error) {
+	u, err := url.Parse(cfg.PrimaryAddress)
+	if err != nil {
+		return nil, fmt.Errorf("invalid primary address: %w", err)
+	}
+
+	// rpc client takes credentials from the address
+	if cfg.PrimaryUser != "" {
+		u.User = url.UserPassword(cfg.PrimaryUser, cfg.PrimaryPass)
+	}
+
+	client, err := jsonrpcclient.New(u.String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to create rpc client of the primary: %w", err)
+	}
+
+	return &primaryClient{
+		address: cfg.PrimaryAddress,
+		client:  client,
+		network: network,
+	}, nil
+}
+
+func (c *primaryClient) Address() string {
+	return c.address
+}
+
+func (c *primaryClient) Changes(ctx context.Context, afterSeq, limit uint64, wait time.Duration) ([]str.ReplicaChange, error) {
+	result := new(DBChangesResponse)
+
+	params := make(map[string]interface{})
+	params["resumeToken"] = strconv.FormatUint(afterSeq, 10)
+	params["limit"] = limit
+	params["waitSecs"] = int(wait.Seconds())
+
+	if _, err := c.client.Call(ctx, "subscribe_db_changes", params, result); err != nil {
+		return nil, fmt.Errorf("failed to call subscribe_db_changes: %w", err)
+	}
+
+	changes := make([]str.ReplicaChange, 0, len(result.Changes))
+	for _, ch := range result.Changes {
+		change := str.ReplicaChange{Seq: ch.Seq}
+
+		if ch.StakingTxHash != "" {
+			hash, err := chainhash.NewHashFromStr(ch.StakingTxHash)
+			if err != nil {
+				return nil, fmt.Errorf("invalid staking tx hash of change %d: %w", ch.Seq, err)
+			}
+			change.StakingTxHash = hash
+		}
+
+		changes = append(changes, change)
+	}
+
+	return changes, nil
+}
+
+func (c *primaryClient) Delegation(ctx context.Context, stakingTxHash *chainhash.Hash) (*str.ReplicatedDelegation, error) {
+	export := new(DelegationExportResponse)
+
+	params := make(map[string]interface{})
+	params["stakingTxHash"] = stakingTxHash.String()
+
+	if _, err := c.client.Call(ctx, "export_delegation", params, export); err != nil {
+		// rpc errors carry only the message of the error
+		var rpcErr *rpctypes.RPCError
+		if errors.As(err, &rpcErr) && strings.Contains(rpcErr.Data, stakerdb.ErrTransactionNotFound.Error()) {
+			return nil, str.ErrNotTrackedByPrimary
+		}
+		return nil, fmt.Errorf("failed to call export_delegation: %w", err)
+	}
+
+	imported, err := decodeDelegationBundle(export)
+	if err != nil {
+		return nil, err
+	}
+
+	imported.StakerAddress, err = btcutil.DecodeAddress(export.StakerAddress, c.network)
+	if err != nil {
+		return nil, fmt.Errorf("invalid staker address %s: %w", export.StakerAddress, err)
+	}
+
+	del := &str.ReplicatedDelegation{Transaction: imported}
+
+	if export.FailureReason != "" {
+		failedAt, err := parseOptionalTime(export.FailedAt)
+		if err != nil {
+			return nil, fmt.Errorf("invalid failed_at: %w", err)
+		}
+
+		del.Failure = &stakerdb.TransactionFailure{
+			StakingTxHash: *stakingTxHash,
+			Reason:        export.FailureReason,
+			Timestamp:     failedAt,
+		}
+	}
+
+	return del, nil
+}
@@ -11,7 +11,9 @@ import (
 	"regexp"
 	"sort"
 	"strings"
+	"time"
 
+	"github.com/babylonlabs-io/btc-staker/metrics"
 	cmtjson "github.com/cometbft/cometbft/libs/json"
 	"github.com/cometbft/cometbft/libs/log"
 	"github.com/cometbft/cometbft/rpc/jsonrpc/server"
@@ -20,7 +22,8 @@ import (
 
 // https://github.com/cometbft/cometbft/blob/38a4caeac0551c188af8a3e209e48380cd514e2d/rpc/jsonrpc/server/rpc_func.go#L1
 // This file was mostly coppied from cometbft just to add a middleware in the RPC routes.
-// The middleware used was to add basic auth to routes with user and password
+// The middleware used was to add basic auth to routes with user and password.
+// Calls of route functions are instrumented with per method metrics.
 
 var reInt = regexp.MustCompile(`^-?[0-9]+$`)
 
@@ -40,16 +43,34 @@ type Option func(*RPCFunc)
 // RegisterRPCFuncs adds a route for each function in the funcMap, as well as
 // general jsonrpc and websocket handlers for all functions. "result" is the
 // interface on which the result objects are registered, and is popualted with
-// every RPCResponse
-func RegisterRPCFuncs(mux *http.ServeMux, funcMap map[string]*RPCFunc, logger log.Logger, middleware func(http.HandlerFunc) http.HandlerFunc) {
+// every RPCResponse. Requests are recorded in m, nil disables metrics.
+func RegisterRPCFuncs(
+	mux *http.ServeMux,
+	funcMap map[string]*RPCFunc,
+	logger log.Logger,
+	middleware func(http.HandlerFunc) http.HandlerFunc,
+	m *metrics.RPCMetrics,
+) {
 	// HTTP endpoints
 	for funcName, rpcFunc := range funcMap {
-		rpcHandler := makeHTTPHandler(rpcFunc, logger)
+		rpcHandler := makeHTTPHandler(funcName, rpcFunc, logger, m)
 		mux.HandleFunc("/"+funcName, middleware(rpcHandler))
 	}
 
 	// JSONRPC endpoints
-	mux.HandleFunc("/", handleInvalidJSONRPCPaths(middleware(makeJSONRPCHandler(funcMap, logger))))
+	mux.HandleFunc("/", handleInvalidJSONRPCPaths(middleware(makeJSONRPCHandler(funcMap, logger, m))))
+}
+
+// call runs the function and records the request in m
+func (f *RPCFunc) call(method string, args []reflect.Value, m *metrics.RPCMetrics) []reflect.Value {
+	start := time.Now()
+	returns := f.f.Call(args)
+
+	// last return value is the error
+	failed := len(returns) > 0 && !returns[len(returns)-1].IsNil()
+	m.Observe(method, time.Since(start), failed)
+
+	return returns
 }
 
 func handleInvalidJSONRPCPaths(next http.HandlerFunc) http.HandlerFunc {
@@ -66,7 +87,7 @@ func handleInvalidJSONRPCPaths(next http.HandlerFunc) http.HandlerFunc {
 }
 
 // convert from a function name to the http handler
-func makeHTTPHandler(rpcFunc *RPCFunc, logger log.Logger) func(http.ResponseWriter, *http.Request) {
+func makeHTTPHandler(funcName string, rpcFunc *RPCFunc, logger log.Logger, m *metrics.RPCMetrics) func(http.ResponseWriter, *http.Request) {
 	// Always return -1 as there's no ID here.
 	dummyID := types.JSONRPCIntID(-1) // URIClientRequestID
 
@@ -89,6 +110,7 @@ func makeHTTPHandler(rpcFunc *RPCFunc, logger log.Logger) func(http.ResponseWrit
 
 		fnArgs, err := httpParamsToArgs(rpcFunc, r)
 		if err != nil {
+			m.ObserveRejected(funcName)
 			res := types.RPCInvalidParamsError(dummyID,
 				fmt.Errorf("error converting http params to arguments: %w", err),
 			)
@@ -99,7 +121,7 @@ func makeHTTPHandler(rpcFunc *RPCFunc, logger log.Logger) func(http.ResponseWrit
 		}
 		args = append(args, fnArgs...)
 
-		returns := rpcFunc.f.Call(args)
+		returns := rpcFunc.call(funcName, args, m)
 
 		logger.Debug("HTTPRestRPC", "method", r.URL.Path, "args", args, "returns", returns)
 		result, err := unreflectResult(returns)
@@ -352,7 +374,7 @@ func getParam(r *http.Request, param string) string {
 }
 
 // jsonrpc calls grab the given method's function info and runs reflect.Call
-func makeJSONRPCHandler(funcMap map[string]*RPCFunc, logger log.Logger) http.HandlerFunc {
+func makeJSONRPCHandler(funcMap map[string]*RPCFunc, logger log.Logger, m *metrics.RPCMetrics) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		b, err := io.ReadAll(r.Body)
 		if err != nil {
@@ -426,6 +448,7 @@ func makeJSONRPCHandler(funcMap map[string]*RPCFunc, logger log.Logger) http.Han
 			if len(request.Params) > 0 {
 				fnArgs, err := jsonParamsToArgs(rpcFunc, request.Params)
 				if err != nil {
+					m.ObserveRejected(request.Method)
 					responses = append(
 						responses,
 						types.RPCInvalidParamsError(request.ID, fmt.Errorf("error converting json params to arguments: %w", err)),
@@ -440,7 +463,7 @@ func makeJSONRPCHandler(funcMap map[string]*RPCFunc, logger log.Logger) http.Han
 				cache = false
 			}
 
-			returns := rpcFunc.f.Call(args)
+			returns := rpcFunc.call(request.Method, args, m)
 			result, err := unreflectResult(returns)
 			if err != nil {
 				responses = append(responses, types.RPCInternalError(request.ID, err))
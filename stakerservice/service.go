@@ -1,27 +1,31 @@
 package stakerservice
 
 import (
+	"bytes"
 	"context"
+	"encoding/base64"
 	"encoding/hex"
 	"fmt"
 	"math"
-	"net"
 	"net/http"
 	"strconv"
 	"strings"
+	"sync"
 	"sync/atomic"
+	"time"
 
 	"github.com/babylonlabs-io/btc-staker/metrics"
 	str "github.com/babylonlabs-io/btc-staker/staker"
 	scfg "github.com/babylonlabs-io/btc-staker/stakercfg"
 	"github.com/babylonlabs-io/btc-staker/stakerdb"
+	"github.com/babylonlabs-io/btc-staker/utils"
 	"github.com/btcsuite/btcd/btcec/v2"
 	"github.com/btcsuite/btcd/btcec/v2/schnorr"
 	"github.com/btcsuite/btcd/btcutil"
 	"github.com/btcsuite/btcd/chaincfg"
 	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
 	"github.com/cometbft/cometbft/libs/log"
-	rpc "github.com/cometbft/cometbft/rpc/jsonrpc/server"
 	rpctypes "github.com/cometbft/cometbft/rpc/jsonrpc/types"
 	"go.uber.org/zap"
 
@@ -42,10 +46,16 @@ type RoutesMap map[string]*RPCFunc
 type StakerService struct {
 	started int32
 
+	rpcMu sync.Mutex
+	// nil until service starts serving rpc requests
+	rpc *rpcServer
+
 	config *scfg.Config
 	staker *str.App
 	logger *logrus.Logger
 	db     kvdb.Backend
+	// nil unless approval of large operations is enabled
+	approvals *operationQueue
 }
 
 // NewStakerServiceFromConfig creates a new staker service instance from config
@@ -55,14 +65,36 @@ func NewStakerServiceFromConfig(
 	z *zap.Logger,
 	db kvdb.Backend,
 	m *metrics.StakerMetrics,
+	opts ...str.Option,
 ) (*StakerService, error) {
-	s, err := str.NewStakerAppFromConfig(c, l, z, db, m)
+	if c.ReplicaConfig.Enabled() {
+		primary, err := newPrimaryClient(c.ReplicaConfig, &c.ActiveNetParams)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, str.WithReplicaSource(primary))
+	}
+
+	s, err := str.NewStakerAppFromConfig(c, l, z, db, m, opts...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create staker app: %w", err)
 	}
 	return NewStakerService(c, s, l, db), nil
 }
 
+// New creates staker service with staker app created by staker.New from given
+// options. Service listens on rpc listeners from the app config.
+func New(opts ...str.Option) (*StakerService, error) {
+	s, err := str.New(opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create staker app: %w", err)
+	}
+
+	// database is owned either by the app or by the caller, service does not
+	// close it
+	return NewStakerService(s.Config(), s, s.Logger(), nil), nil
+}
+
 // NewStakerService creates a new staker service instance
 func NewStakerService(
 	c *scfg.Config,
@@ -71,21 +103,64 @@ func NewStakerService(
 	db kvdb.Backend,
 ) *StakerService {
 	return &StakerService{
-		config: c,
-		staker: s,
-		logger: l,
-		db:     db,
+		config:    c,
+		staker:    s,
+		logger:    l,
+		db:        db,
+		approvals: newOperationQueue(c.ApprovalConfig),
 	}
 }
 
 // stakingDetails converts a stakerdb.StoredTransaction to a StakingDetails
-func storedTxToStakingDetails(storedTx *stakerdb.StoredTransaction, state string) StakingDetails {
-	return StakingDetails{
-		StakingTxHash:  storedTx.StakingTx.TxHash().String(),
-		StakerAddress:  storedTx.StakerAddress,
-		StakingState:   state,
-		TransactionIdx: strconv.FormatUint(storedTx.StoredTransactionIdx, 10),
+func storedTxToStakingDetails(
+	storedTx *stakerdb.StoredTransaction,
+	state string,
+	amounts *str.StakingTxAmounts,
+) StakingDetails {
+	return storedTxToSelectedStakingDetails(storedTx, state, amounts, AllFields())
+}
+
+// storedTxToSelectedStakingDetails converts a stakerdb.StoredTransaction to a StakingDetails
+// filling only the selected fields
+func storedTxToSelectedStakingDetails(
+	storedTx *stakerdb.StoredTransaction,
+	state string,
+	amounts *str.StakingTxAmounts,
+	sel FieldSelection,
+) StakingDetails {
+	var details StakingDetails
+
+	if sel.Has(FieldStakingTxHash) {
+		details.StakingTxHash = storedTx.StakingTxHash.String()
+	}
+
+	if sel.Has(FieldStakerAddress) {
+		details.StakerAddress = storedTx.StakerAddress
 	}
+
+	if sel.Has(FieldState) {
+		details.StakingState = state
+	}
+
+	if sel.Has(FieldTransactionIdx) {
+		details.TransactionIdx = strconv.FormatUint(storedTx.StoredTransactionIdx, 10)
+	}
+
+	if amounts != nil {
+		if sel.Has(FieldStakingAmount) {
+			details.StakingAmount = amounts.StakingAmount.String()
+		}
+
+		if sel.Has(FieldFee) {
+			details.Fee = amounts.Fee.String()
+		}
+
+		if sel.Has(FieldUnbondingFee) {
+			details.UnbondingFee = amounts.UnbondingFee.String()
+		}
+	}
+
+	return details
 }
 
 // health returns a health check response
@@ -93,47 +168,160 @@ func (s *StakerService) health(_ *rpctypes.Context) (*ResultHealth, error) {
 	return &ResultHealth{}, nil
 }
 
+// stats returns fees paid by the staker daemon, per delegation and in total.
+// If tenant is given, only delegations of this tenant are counted.
+func (s *StakerService) stats(_ *rpctypes.Context, tenant *string) (*StatsResponse, error) {
+	tenantID, err := getTenant(tenant)
+	if err != nil {
+		return nil, err
+	}
+
+	feeStats, err := s.staker.FeeStats(tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get fee stats: %w", err)
+	}
+
+	delegations := make([]DelegationFeesDetail, 0, len(feeStats.Delegations))
+	for _, d := range feeStats.Delegations {
+		delegations = append(delegations, DelegationFeesDetail{
+			StakingTxHash: d.StakingTxHash.String(),
+			StakingFee:    d.StakingFee.String(),
+			UnbondingFee:  d.UnbondingFee.String(),
+			WithdrawalFee: d.WithdrawalFee.String(),
+			TotalFee:      d.Total().String(),
+		})
+	}
+
+	return &StatsResponse{
+		TotalStakingFees:    feeStats.TotalStakingFees.String(),
+		TotalUnbondingFees:  feeStats.TotalUnbondingFees.String(),
+		TotalWithdrawalFees: feeStats.TotalWithdrawalFees.String(),
+		TotalFees:           feeStats.TotalFees().String(),
+		Delegations:         delegations,
+		Tenant:              tenantID,
+	}, nil
+}
+
 // stake stakes staker's requested amount of BTC
-func (s *StakerService) stake(_ *rpctypes.Context,
+func (s *StakerService) stake(ctx *rpctypes.Context,
 	stakerAddress string,
 	stakingAmount int64,
 	fpBtcPks []string,
 	stakingTimeBlocks int64,
+	tenant *string,
+	template *string,
+	reference *string,
+	feeRate *int64,
+	targetConf *int64,
+	unbondingTime *int64,
 ) (*ResultStake, error) {
+	var ref string
+	if reference != nil {
+		ref = *reference
+	}
+
+	fee, err := parseFeeSelection(feeRate, targetConf)
+	if err != nil {
+		return nil, err
+	}
+
+	if unbondingTime != nil {
+		if err := s.checkUnbondingTime(*unbondingTime); err != nil {
+			return nil, err
+		}
+	}
+
+	if template != nil {
+		return s.stakeFromTemplate(ctx, *template, stakerAddress, stakingAmount, fpBtcPks, stakingTimeBlocks, tenant, ref, fee)
+	}
+
 	amount, stakerAddr, fpPubKeys, stakingTime, err := parseStkParams(stakerAddress, &s.config.ActiveNetParams, stakingAmount, fpBtcPks, stakingTimeBlocks)
 	if err != nil {
 		return nil, err
 	}
 
-	stakingTxHash, err := s.staker.StakeFunds(stakerAddr, amount, fpPubKeys, stakingTime)
+	tenantID, err := getTenant(tenant)
 	if err != nil {
-		return nil, fmt.Errorf("error staking funds: %w", err)
+		return nil, err
 	}
 
-	return &ResultStake{
-		TxHash: stakingTxHash.String(),
-	}, nil
+	stakeFn := func() (*chainhash.Hash, error) {
+		return s.staker.StakeFunds(stakerAddr, amount, fpPubKeys, stakingTime, tenantID, ref, fee)
+	}
+	queueFn := func() (uint64, error) {
+		return s.staker.QueueStake(stakerAddr, amount, fpPubKeys, stakingTime, tenantID, 0, nil, ref, fee)
+	}
+
+	if s.approvals.requiresApproval(amount) {
+		op, err := s.approvals.submit(
+			OperationStake,
+			principal(ctx),
+			amount,
+			fmt.Sprintf("stake from %s for %d blocks", stakerAddr, stakingTime),
+			func() (string, error) {
+				res, err := s.stakeOrQueue(stakeFn, queueFn)
+				if err != nil {
+					return "", err
+				}
+				return res.result(), nil
+			},
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		return &ResultStake{OperationID: op.ID, Status: op.Status}, nil
+	}
+
+	return s.stakeOrQueue(stakeFn, queueFn)
 }
 
 // stakeExpand stakes staker's requested amount of BTC
-func (s *StakerService) stakeExpand(_ *rpctypes.Context,
+func (s *StakerService) stakeExpand(ctx *rpctypes.Context,
 	stakerAddress string,
 	stakingAmount int64,
 	fpBtcPks []string,
 	stakingTimeBlocks int64,
 	prevActiveStkTxHashHex string,
+	tenant *string,
 ) (*ResultStake, error) {
 	amount, stakerAddr, fpPubKeys, stakingTime, err := parseStkParams(stakerAddress, &s.config.ActiveNetParams, stakingAmount, fpBtcPks, stakingTimeBlocks)
 	if err != nil {
 		return nil, err
 	}
 
+	tenantID, err := getTenant(tenant)
+	if err != nil {
+		return nil, err
+	}
+
 	prevActiveStkTxHash, err := chainhash.NewHashFromStr(prevActiveStkTxHashHex)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse previous staking transaction hash hex %s: %w", prevActiveStkTxHashHex, err)
 	}
 
-	stakingTxHash, err := s.staker.StakeExpand(stakerAddr, amount, fpPubKeys, stakingTime, prevActiveStkTxHash)
+	if s.approvals.requiresApproval(amount) {
+		op, err := s.approvals.submit(
+			OperationStakeExpand,
+			principal(ctx),
+			amount,
+			fmt.Sprintf("expand stake %s from %s for %d blocks", prevActiveStkTxHash, stakerAddr, stakingTime),
+			func() (string, error) {
+				stakingTxHash, err := s.staker.StakeExpand(stakerAddr, amount, fpPubKeys, stakingTime, prevActiveStkTxHash, tenantID)
+				if err != nil {
+					return "", fmt.Errorf("error stake expand funds: %w", err)
+				}
+				return stakingTxHash.String(), nil
+			},
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		return &ResultStake{OperationID: op.ID, Status: op.Status}, nil
+	}
+
+	stakingTxHash, err := s.staker.StakeExpand(stakerAddr, amount, fpPubKeys, stakingTime, prevActiveStkTxHash, tenantID)
 	if err != nil {
 		return nil, fmt.Errorf("error stake expand funds: %w", err)
 	}
@@ -191,27 +379,57 @@ func parseStkParams(
 		return amount, nil, nil, 0, fmt.Errorf("error decoding staker address: %w", err)
 	}
 
-	fpPubKeys = make([]*btcec.PublicKey, 0)
+	fpPubKeys, err = parseFpPks(fpBtcPks)
+	if err != nil {
+		return amount, nil, nil, 0, err
+	}
+
+	stakingTime, err = parseStakingTime(stakingTimeBlocks)
+	if err != nil {
+		return amount, nil, nil, 0, err
+	}
+
+	return amount, stakerAddr, fpPubKeys, stakingTime, nil
+}
+
+// parseFpPks parses hex encoded finality provider public keys
+func parseFpPks(fpBtcPks []string) ([]*btcec.PublicKey, error) {
+	fpPubKeys := make([]*btcec.PublicKey, 0)
 
 	for _, fpPk := range fpBtcPks {
 		fpPkBytes, err := hex.DecodeString(fpPk)
 		if err != nil {
-			return amount, nil, nil, 0, fmt.Errorf("error decoding finality provider public key: %w", err)
+			return nil, fmt.Errorf("error decoding finality provider public key: %w", err)
 		}
 
 		fpSchnorrKey, err := schnorr.ParsePubKey(fpPkBytes)
 		if err != nil {
-			return amount, nil, nil, 0, fmt.Errorf("error parsing finality provider public key: %w", err)
+			return nil, fmt.Errorf("error parsing finality provider public key: %w", err)
 		}
 
 		fpPubKeys = append(fpPubKeys, fpSchnorrKey)
 	}
 
+	return fpPubKeys, nil
+}
+
+func parseStakingTime(stakingTimeBlocks int64) (uint16, error) {
 	if stakingTimeBlocks <= 0 || stakingTimeBlocks > math.MaxUint16 {
-		return amount, nil, nil, 0, fmt.Errorf("staking time must be positive and lower than %d", math.MaxUint16)
+		return 0, fmt.Errorf("staking time must be positive and lower than %d", math.MaxUint16)
+	}
+
+	return uint16(stakingTimeBlocks), nil
+}
+
+// checkUnbondingTime checks unbonding time requested for new delegation
+// against Babylon params, the delegation is always created with unbonding time
+// of params
+func (s *StakerService) checkUnbondingTime(unbondingTimeBlocks int64) error {
+	if unbondingTimeBlocks <= 0 || unbondingTimeBlocks > math.MaxUint16 {
+		return fmt.Errorf("unbonding time must be positive and lower than %d", math.MaxUint16)
 	}
 
-	return amount, stakerAddr, fpPubKeys, uint16(stakingTimeBlocks), nil
+	return s.staker.CheckUnbondingTime(uint16(unbondingTimeBlocks))
 }
 
 // btcDelegationFromBtcStakingTx returns a btc delegation from a btc staking transaction
@@ -251,6 +469,59 @@ func (s *StakerService) btcDelegationFromBtcStakingTx(
 	}, nil
 }
 
+// retryDelegationRegistration registers tracked delegation on babylon again,
+// with the given covenant set, after its previous registration failed
+func (s *StakerService) retryDelegationRegistration(
+	_ *rpctypes.Context,
+	stakingTxHash string,
+	covenantPksHex []string,
+	covenantQuorum uint32,
+) (*ResultBtcDelegationFromBtcStakingTx, error) {
+	txHash, err := chainhash.NewHashFromStr(stakingTxHash)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing tx hash: %w", err)
+	}
+
+	covenantPks, err := parseCovenantsPubKeyFromHex(covenantPksHex...)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding covenant public keys: %w", err)
+	}
+
+	babylonBTCDelegationTxHash, err := s.staker.RetryDelegationRegistration(txHash, covenantPks, covenantQuorum)
+	if err != nil {
+		s.logger.WithError(err).Info("err to retry delegation registration")
+		return nil, fmt.Errorf("error retrying delegation registration: %w", err)
+	}
+
+	return &ResultBtcDelegationFromBtcStakingTx{
+		BabylonBTCDelegationTxHash: babylonBTCDelegationTxHash,
+	}, nil
+}
+
+// renotifyCovenant re-checks registration of the delegation whose covenant
+// signatures did not arrive, registers it again if Babylon lost it and
+// restarts watching of its covenant signatures
+func (s *StakerService) renotifyCovenant(_ *rpctypes.Context, stakingTxHash string) (*RenotifyCovenantResponse, error) {
+	txHash, err := chainhash.NewHashFromStr(stakingTxHash)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing tx hash: %w", err)
+	}
+
+	result, err := s.staker.RenotifyCovenant(txHash)
+	if err != nil {
+		return nil, fmt.Errorf("error renotifying covenant: %w", err)
+	}
+
+	return &RenotifyCovenantResponse{
+		StakingTxHash:      txHash.String(),
+		State:              result.State,
+		CovenantSignatures: result.CovenantSignatures,
+		CovenantQuorum:     result.CovenantQuorum,
+		Action:             result.Action,
+		BabylonTxHash:      result.BabylonTxHash,
+	}, nil
+}
+
 // parseCovenantsPubKeyFromHex parses a slice of covenant public keys from hex strings
 func parseCovenantsPubKeyFromHex(covenantPksHex ...string) ([]*btcec.PublicKey, error) {
 	covenantPks := make([]*btcec.PublicKey, len(covenantPksHex))
@@ -306,36 +577,255 @@ func (s *StakerService) btcTxBlkDetails(
 func (s *StakerService) stakingDetails(
 	_ *rpctypes.Context,
 	stakingTxHash string,
+	tenant *string,
 ) (*StakingDetails, error) {
 	txHash, err := chainhash.NewHashFromStr(stakingTxHash)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse string type of hash to chainhash.Hash: %w", err)
 	}
 
+	if err := s.checkTenant(txHash, tenant); err != nil {
+		return nil, err
+	}
+
 	storedTx, err := s.staker.GetStoredTransaction(txHash)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get stored transaction from hash %s: %w", stakingTxHash, err)
 	}
 
-	di, err := s.staker.BabylonController().QueryBTCDelegation(txHash)
+	status, err := s.staker.DelegationStatus(storedTx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get delegation status: %w", err)
+	}
+
+	if status.AmountsErr != nil {
+		return nil, fmt.Errorf("failed to get staking transaction amounts: %w", status.AmountsErr)
+	}
+
+	details := storedTxToStakingDetails(storedTx, status.State(), status.Amounts)
+
+	details.SpendableHeight, err = s.spendableHeight(txHash)
+	if err != nil {
+		return nil, err
+	}
+
+	mempoolTxs, err := s.staker.MempoolTransactions(txHash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get mempool transactions: %w", err)
+	}
+
+	for i := range mempoolTxs {
+		details.MempoolTransactions = append(details.MempoolTransactions, mempoolTxDetail(&mempoolTxs[i]))
+	}
+
+	details.Labels, err = s.staker.TransactionLabels(txHash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get labels: %w", err)
+	}
+
+	timing, err := s.staker.CovenantQuorumTiming(txHash)
+	if err != nil {
+		return nil, err
+	}
+
+	if timing != nil {
+		details.RegisteredAt = formatOptionalTime(timing.RegisteredAt)
+		details.CovenantQuorumAt = formatOptionalTime(timing.QuorumReachedAt)
+		if latency, ok := timing.Latency(); ok {
+			details.CovenantQuorumSecs = strconv.FormatInt(int64(latency.Seconds()), 10)
+		}
+	}
+
+	unbondingSigs, err := s.staker.UnbondingSignatures(txHash)
 	if err != nil {
-		return nil, fmt.Errorf("failed to query delegation info from babylon: %w", err)
+		return nil, err
+	}
+
+	if unbondingSigs != nil {
+		for _, sig := range unbondingSigs.Signatures {
+			details.CovenantUnbondingSigners = append(details.CovenantUnbondingSigners, hex.EncodeToString(sig.CovenantPk))
+		}
+	}
+
+	note, err := s.staker.FailureNote(txHash)
+	if err != nil {
+		return nil, err
+	}
+
+	if note != nil {
+		details.FailureNote = failureNoteDetail(note)
 	}
 
-	details := storedTxToStakingDetails(storedTx, di.BtcDelegation.GetStatusDesc())
 	return &details, nil
 }
 
+func failureNoteDetail(note *stakerdb.FailureNote) *FailureNoteDetail {
+	detail := &FailureNoteDetail{
+		Source:      note.Source,
+		Codespace:   note.Codespace,
+		Reason:      note.Reason,
+		Remediation: note.Remediation,
+		RecordedAt:  formatOptionalTime(note.RecordedAt),
+	}
+
+	if note.Code != 0 {
+		detail.Code = strconv.FormatInt(note.Code, 10)
+	}
+
+	return detail
+}
+
+// spendableHeight returns btc height at which unbonded funds of the delegation
+// become spendable, or empty string if unbonding is not confirmed yet
+func (s *StakerService) spendableHeight(txHash *chainhash.Hash) (string, error) {
+	height, found, err := s.staker.SpendableHeight(txHash)
+	if err != nil {
+		return "", fmt.Errorf("failed to get spendable height: %w", err)
+	}
+
+	if !found {
+		return "", nil
+	}
+
+	return strconv.FormatUint(uint64(height), 10), nil
+}
+
+func formatOptionalTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+func mempoolTxDetail(status *str.MempoolTxStatus) MempoolTxDetail {
+	detail := MempoolTxDetail{
+		TxHash:           status.Tx.TxHash().String(),
+		Kind:             status.Kind.String(),
+		BroadcastAt:      formatOptionalTime(status.BroadcastAt),
+		SeenInMempoolAt:  formatOptionalTime(status.SeenInMempoolAt),
+		InMempool:        status.InMempool,
+		EvictedAt:        formatOptionalTime(status.EvictedAt),
+		Rebroadcasts:     strconv.FormatUint(uint64(status.Rebroadcasts), 10),
+		EstimatedFeeRate: strconv.FormatFloat(status.EstimatedFeeRate, 'f', 2, 64),
+	}
+
+	if status.InMempool {
+		detail.FeeRate = strconv.FormatFloat(status.FeeRate, 'f', 2, 64)
+		detail.FeePosition = status.FeePosition()
+	}
+
+	return detail
+}
+
+// restakeFromUnbonded withdraws funds of unbonded delegation and stakes them
+// again in a staking transaction chained to the withdrawal
+func (s *StakerService) restakeFromUnbonded(
+	ctx *rpctypes.Context,
+	stakingTxHash string,
+	fpBtcPks []string,
+	stakingTimeBlocks int64,
+) (*RestakeResponse, error) {
+	txHash, err := chainhash.NewHashFromStr(stakingTxHash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse string type of hash to chainhash.Hash: %w", err)
+	}
+
+	fpPubKeys, err := parseFpPks(fpBtcPks)
+	if err != nil {
+		return nil, err
+	}
+
+	stakingTime, err := parseStakingTime(stakingTimeBlocks)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.approvals != nil {
+		amount, err := s.stakingAmount(txHash)
+		if err != nil {
+			return nil, err
+		}
+
+		if s.approvals.requiresApproval(amount) {
+			op, err := s.approvals.submit(
+				OperationRestake,
+				principal(ctx),
+				amount,
+				fmt.Sprintf("restake %s for %d blocks", txHash, stakingTime),
+				func() (string, error) {
+					_, newStakingTxHash, err := s.staker.RestakeFromUnbonded(txHash, fpPubKeys, stakingTime)
+					if err != nil {
+						return "", fmt.Errorf("failed to restake: %w", err)
+					}
+					return newStakingTxHash.String(), nil
+				},
+			)
+			if err != nil {
+				return nil, err
+			}
+
+			return &RestakeResponse{OperationID: op.ID, Status: op.Status}, nil
+		}
+	}
+
+	withdrawalTxHash, newStakingTxHash, err := s.staker.RestakeFromUnbonded(txHash, fpPubKeys, stakingTime)
+	if err != nil {
+		return nil, fmt.Errorf("failed to restake: %w", err)
+	}
+
+	return &RestakeResponse{
+		WithdrawalTxHash: withdrawalTxHash.String(),
+		StakingTxHash:    newStakingTxHash.String(),
+	}, nil
+}
+
 // spendStake initiates a spend stake transaction
-func (s *StakerService) spendStake(_ *rpctypes.Context,
-	stakingTxHash string) (*SpendTxDetails, error) {
+func (s *StakerService) spendStake(ctx *rpctypes.Context,
+	stakingTxHash string, feeRate *int64, targetConf *int64, tenant *string) (*SpendTxDetails, error) {
 	txHash, err := chainhash.NewHashFromStr(stakingTxHash)
 
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse string type of hash to chainhash.Hash: %w", err)
 	}
 
-	spendTxHash, value, err := s.staker.SpendStake(txHash)
+	if err := s.checkTenant(txHash, tenant); err != nil {
+		return nil, err
+	}
+
+	fee, err := parseFeeSelection(feeRate, targetConf)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.approvals != nil {
+		amount, err := s.stakingAmount(txHash)
+		if err != nil {
+			return nil, err
+		}
+
+		if s.approvals.requiresApproval(amount) {
+			op, err := s.approvals.submit(
+				OperationSpendStake,
+				principal(ctx),
+				amount,
+				fmt.Sprintf("spend stake %s", txHash),
+				func() (string, error) {
+					spendTxHash, _, err := s.staker.SpendStake(txHash, fee)
+					if err != nil {
+						return "", fmt.Errorf("failed to spend stake: %w", err)
+					}
+					return spendTxHash.String(), nil
+				},
+			)
+			if err != nil {
+				return nil, err
+			}
+
+			return &SpendTxDetails{OperationID: op.ID, Status: op.Status}, nil
+		}
+	}
+
+	spendTxHash, value, err := s.staker.SpendStake(txHash, fee)
 
 	if err != nil {
 		return nil, fmt.Errorf("failed to spend stake: %w", err)
@@ -371,6 +861,189 @@ func (s *StakerService) listOutputs(_ *rpctypes.Context) (*OutputsResponse, erro
 	}, nil
 }
 
+// listReservedOutpoints returns wallet outpoints already used by tracked staking transactions
+func (s *StakerService) listReservedOutpoints(_ *rpctypes.Context) (*ReservedOutpointsResponse, error) {
+	reserved, err := s.staker.ListReservedOutpoints()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list reserved outpoints: %w", err)
+	}
+
+	var outpoints []ReservedOutpointDetail
+
+	for _, r := range reserved {
+		outpoints = append(outpoints, ReservedOutpointDetail{
+			Outpoint:      r.Outpoint.String(),
+			StakingTxHash: r.StakingTxHash.String(),
+		})
+	}
+
+	return &ReservedOutpointsResponse{
+		Outpoints: outpoints,
+	}, nil
+}
+
+// unreserveOutpoint releases outpoint reserved by a tracked staking transaction
+func (s *StakerService) unreserveOutpoint(_ *rpctypes.Context, outpoint string) (*UnreserveOutpointResponse, error) {
+	op, err := wire.NewOutPointFromString(outpoint)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse outpoint: %w", err)
+	}
+
+	stakingTxHash, err := s.staker.UnreserveOutpoint(op)
+	if err != nil {
+		return nil, err
+	}
+
+	return &UnreserveOutpointResponse{
+		Outpoint:      op.String(),
+		StakingTxHash: stakingTxHash.String(),
+	}, nil
+}
+
+// unlockWallet provides passphrase used by stakerd to unlock the wallet. Passphrase
+// is kept in memory for timeoutSecs seconds, or until restart if not provided.
+func (s *StakerService) unlockWallet(_ *rpctypes.Context, passphrase string, timeoutSecs *int) (*UnlockWalletResponse, error) {
+	var timeout time.Duration
+	if timeoutSecs != nil {
+		if *timeoutSecs < 0 {
+			return nil, fmt.Errorf("timeout must be non-negative")
+		}
+		timeout = time.Duration(*timeoutSecs) * time.Second
+	}
+
+	if err := s.staker.SetWalletPassphrase(passphrase, timeout); err != nil {
+		return nil, fmt.Errorf("failed to set wallet passphrase: %w", err)
+	}
+
+	if timeout == 0 {
+		return &UnlockWalletResponse{}, nil
+	}
+
+	return &UnlockWalletResponse{
+		ExpiresAt: time.Now().Add(timeout).UTC().Format(time.RFC3339),
+	}, nil
+}
+
+// signMessage signs message with the key of the wallet address using bip322
+// simple signature scheme
+func (s *StakerService) signMessage(_ *rpctypes.Context, address string, message string) (*SignMessageResponse, error) {
+	addr, err := utils.DecodeAddressForNet(address, &s.config.ActiveNetParams)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding address: %w", err)
+	}
+
+	sig, err := s.staker.SignMessage(addr, []byte(message))
+	if err != nil {
+		return nil, err
+	}
+
+	return &SignMessageResponse{
+		Address:   addr.EncodeAddress(),
+		Message:   message,
+		Signature: base64.StdEncoding.EncodeToString(sig),
+	}, nil
+}
+
+// verifyMessage verifies bip322 simple signature of the message. Invalid
+// signature is not an error, it is reported in the response.
+func (s *StakerService) verifyMessage(_ *rpctypes.Context, address string, message string, signature string) (*VerifyMessageResponse, error) {
+	addr, err := utils.DecodeAddressForNet(address, &s.config.ActiveNetParams)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding address: %w", err)
+	}
+
+	sig, err := base64.StdEncoding.DecodeString(signature)
+	if err != nil {
+		return nil, fmt.Errorf("signature must be base64 encoded: %w", err)
+	}
+
+	if err := s.staker.VerifyMessage(addr, []byte(message), sig); err != nil {
+		return &VerifyMessageResponse{
+			Valid: false,
+			Error: err.Error(),
+		}, nil
+	}
+
+	return &VerifyMessageResponse{Valid: true}, nil
+}
+
+// signSpendTx produces staker Schnorr signature over unbonding or slashing
+// transaction of the delegation. Transaction must match the one registered
+// on Babylon.
+func (s *StakerService) signSpendTx(_ *rpctypes.Context, stakingTxHash string, kind string, txHex string) (*SignSpendTxResponse, error) {
+	txHash, err := chainhash.NewHashFromStr(stakingTxHash)
+	if err != nil {
+		return nil, err
+	}
+
+	sigKind, err := str.ParseSpendSigKind(kind)
+	if err != nil {
+		return nil, err
+	}
+
+	txBytes, err := hex.DecodeString(txHex)
+	if err != nil {
+		return nil, fmt.Errorf("transaction must be hex encoded: %w", err)
+	}
+
+	var tx wire.MsgTx
+	if err := tx.Deserialize(bytes.NewReader(txBytes)); err != nil {
+		return nil, fmt.Errorf("error decoding transaction: %w", err)
+	}
+
+	sig, err := s.staker.SignSpendTx(txHash, sigKind, &tx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SignSpendTxResponse{
+		StakingTxHash: txHash.String(),
+		Kind:          string(sigKind),
+		TxHash:        tx.TxHash().String(),
+		Signature:     hex.EncodeToString(sig.Serialize()),
+	}, nil
+}
+
+// newStakerAddress derives and registers new wallet address usable for staking
+func (s *StakerService) newStakerAddress(_ *rpctypes.Context, addressType *string) (*StakerAddressDetail, error) {
+	addrType := scfg.AddressTypeTaproot
+	if addressType != nil && *addressType != "" {
+		addrType = *addressType
+	}
+
+	address, err := s.staker.NewStakerAddress(addrType)
+	if err != nil {
+		return nil, err
+	}
+
+	return &StakerAddressDetail{
+		Address:     address.EncodeAddress(),
+		AddressType: addrType,
+	}, nil
+}
+
+// listStakerAddresses returns staker addresses registered by the staker
+func (s *StakerService) listStakerAddresses(_ *rpctypes.Context) (*StakerAddressesResponse, error) {
+	addresses, err := s.staker.ListStakerAddresses()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list staker addresses: %w", err)
+	}
+
+	var details []StakerAddressDetail
+
+	for _, a := range addresses {
+		details = append(details, StakerAddressDetail{
+			Address:     a.Address,
+			AddressType: a.Type,
+			CreatedAt:   a.Timestamp.UTC().Format(time.RFC3339),
+		})
+	}
+
+	return &StakerAddressesResponse{
+		Addresses: details,
+	}, nil
+}
+
 // PageParams is a page params
 type PageParams struct {
 	Offset uint64
@@ -409,6 +1082,67 @@ func getPageParams(offsetPtr *int, limitPtr *int) (*PageParams, error) {
 	}, nil
 }
 
+// getSortParams parses optional sort parameters of list endpoints
+func getSortParams(sortByPtr, directionPtr *string) (str.StakingTxSortKey, str.SortDirection, error) {
+	var sortBy, direction string
+	if sortByPtr != nil {
+		sortBy = *sortByPtr
+	}
+	if directionPtr != nil {
+		direction = *directionPtr
+	}
+
+	sortKey, err := str.ParseStakingTxSortKey(sortBy)
+	if err != nil {
+		return "", "", err
+	}
+
+	sortDirection, err := str.ParseSortDirection(direction)
+	if err != nil {
+		return "", "", err
+	}
+
+	return sortKey, sortDirection, nil
+}
+
+// getTenant parses optional tenant parameter. Empty string is returned if
+// tenant was not given.
+func getTenant(tenantPtr *string) (string, error) {
+	if tenantPtr == nil || *tenantPtr == "" {
+		return "", nil
+	}
+
+	if err := stakerdb.ValidateTenant(*tenantPtr); err != nil {
+		return "", fmt.Errorf("invalid tenant: %w", err)
+	}
+
+	return *tenantPtr, nil
+}
+
+// checkTenant returns an error if tenant is given and the staking transaction
+// does not belong to it
+func (s *StakerService) checkTenant(stakingTxHash *chainhash.Hash, tenantPtr *string) error {
+	tenant, err := getTenant(tenantPtr)
+	if err != nil {
+		return err
+	}
+
+	if tenant == "" {
+		return nil
+	}
+
+	txTenant, err := s.staker.TransactionTenant(stakingTxHash)
+	if err != nil {
+		return err
+	}
+
+	if txTenant != tenant {
+		return fmt.Errorf("staking transaction %s does not belong to tenant %s", stakingTxHash, tenant)
+	}
+
+	return nil
+}
+
 // providers returns a list of finality providers
 func (s *StakerService) providers(_ *rpctypes.Context, offset, limit *int) (*FinalityProvidersResponse, error) {
 	pageParams, err := getPageParams(offset, limit)
@@ -442,28 +1176,84 @@ func (s *StakerService) providers(_ *rpctypes.Context, offset, limit *int) (*Fin
 }
 
 // listStakingTransactions returns a list of staking transactions
-func (s *StakerService) listStakingTransactions(_ *rpctypes.Context, offset, limit *int) (*ListStakingTransactionsResponse, error) {
+func (s *StakerService) listStakingTransactions(
+	_ *rpctypes.Context,
+	offset, limit *int,
+	fields *string,
+	sortBy, sortDirection *string,
+	tenant *string,
+) (*ListStakingTransactionsResponse, error) {
 	pageParams, err := getPageParams(offset, limit)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get page params: %w", err)
 	}
 
-	txResult, err := s.staker.StoredTransactions(pageParams.Limit, pageParams.Offset)
+	sel, err := getFieldSelection(fields)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse fields: %w", err)
+	}
+
+	sortKey, direction, err := getSortParams(sortBy, sortDirection)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse sort params: %w", err)
+	}
+
+	tenantID, err := getTenant(tenant)
+	if err != nil {
+		return nil, err
+	}
+
+	// offset of tenant view is position within the view, not transaction
+	// index, so it is served by the sorted path
+	var txResult *stakerdb.StoredTransactionQueryResult
+	if sortKey == str.SortByIndex && direction == str.SortAscending && tenantID == "" {
+		txResult, err = s.staker.QueryStoredTransactions(stakerdb.StoredTransactionQuery{
+			IndexOffset:        pageParams.Offset,
+			NumMaxTransactions: pageParams.Limit,
+			StakingTxHashOnly:  true,
+		})
+	} else {
+		txResult, err = s.staker.SortedStoredTransactions(sortKey, direction, pageParams.Limit, pageParams.Offset, tenantID)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to get stored transactions: %w", err)
 	}
 
 	var stakingDetails []StakingDetails
-	bc := s.staker.BabylonController()
 
 	for _, tx := range txResult.Transactions {
 		tx := tx
-		stakingTxHash := tx.StakingTx.TxHash()
-		di, err := bc.QueryBTCDelegation(&stakingTxHash)
-		if err != nil {
-			return nil, fmt.Errorf("failed to query delegation info from babylon: %w", err)
+
+		var (
+			state   string
+			amounts *str.StakingTxAmounts
+		)
+		// statuses are served from cache refreshed in background, only
+		// delegations not seen before are queried from babylon
+		if sel.Has(FieldState) || sel.needsAmounts() {
+			status, err := s.staker.DelegationStatus(&tx)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get delegation status: %w", err)
+			}
+			state = status.State()
+
+			if sel.needsAmounts() {
+				if status.AmountsErr != nil {
+					return nil, fmt.Errorf("failed to get staking transaction amounts: %w", status.AmountsErr)
+				}
+				amounts = status.Amounts
+			}
 		}
-		stakingDetails = append(stakingDetails, storedTxToStakingDetails(&tx, di.BtcDelegation.GetStatusDesc()))
+
+		details := storedTxToSelectedStakingDetails(&tx, state, amounts, sel)
+		if sel.Has(FieldSpendableHeight) {
+			details.SpendableHeight, err = s.spendableHeight(&tx.StakingTxHash)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		stakingDetails = append(stakingDetails, details)
 	}
 
 	totalCount := strconv.FormatUint(txResult.Total, 10)
@@ -475,13 +1265,23 @@ func (s *StakerService) listStakingTransactions(_ *rpctypes.Context, offset, lim
 }
 
 // withdrawableTransactions returns a list of staking transactions that are not yet confirmed in btc
-func (s *StakerService) withdrawableTransactions(_ *rpctypes.Context, offset, limit *int) (*WithdrawableTransactionsResponse, error) {
+func (s *StakerService) withdrawableTransactions(_ *rpctypes.Context, offset, limit *int, fields *string, tenant *string) (*WithdrawableTransactionsResponse, error) {
 	pageParams, err := getPageParams(offset, limit)
 	if err != nil {
 		return nil, err
 	}
 
-	txResult, err := s.staker.WithdrawableTransactions(pageParams.Limit, pageParams.Offset)
+	sel, err := getFieldSelection(fields)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse fields: %w", err)
+	}
+
+	tenantID, err := getTenant(tenant)
+	if err != nil {
+		return nil, err
+	}
+
+	txResult, err := s.staker.WithdrawableTransactions(pageParams.Limit, pageParams.Offset, tenantID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get withdrawable transactions: %w", err)
 	}
@@ -489,17 +1289,40 @@ func (s *StakerService) withdrawableTransactions(_ *rpctypes.Context, offset, li
 	var stakingDetails []StakingDetails
 
 	for _, tx := range txResult.Transactions {
+		tx := tx
+
 		// Since withdrawable transactions are always confirmed in btc and activated in babylon,
-		// no need to query babylon for delegation info
-		stakingDetails = append(stakingDetails, storedTxToStakingDetails(&tx, str.BabylonActiveStatus))
+		// no need to get delegation status unless amounts were requested
+		var amounts *str.StakingTxAmounts
+		if sel.needsAmounts() {
+			status, err := s.staker.DelegationStatus(&tx)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get delegation status: %w", err)
+			}
+
+			if status.AmountsErr != nil {
+				return nil, fmt.Errorf("failed to get staking transaction amounts: %w", status.AmountsErr)
+			}
+			amounts = status.Amounts
+		}
+
+		details := storedTxToSelectedStakingDetails(&tx, str.BabylonActiveStatus, amounts, sel)
+		if sel.Has(FieldSpendableHeight) {
+			details.SpendableHeight, err = s.spendableHeight(&tx.StakingTxHash)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		stakingDetails = append(stakingDetails, details)
 	}
 
 	lastIdx := "0"
-	if len(stakingDetails) > 0 {
+	if len(txResult.Transactions) > 0 {
 		// this should ease up pagination i.e in case when whe have 1000 transactions, and we limit query to 50
 		// due to filetring we can retrun  response with 50 transactions when last one have index 400,
 		// then caller can specify offset=400 and get next withdrawable transactions.
-		lastIdx = stakingDetails[len(stakingDetails)-1].TransactionIdx
+		lastIdx = strconv.FormatUint(txResult.Transactions[len(txResult.Transactions)-1].StoredTransactionIdx, 10)
 	}
 
 	totalCount := strconv.FormatUint(txResult.Total, 10)
@@ -511,15 +1334,70 @@ func (s *StakerService) withdrawableTransactions(_ *rpctypes.Context, offset, li
 	}, nil
 }
 
+// stakingActivity returns delegations and withdrawals grouped by time period
+func (s *StakerService) stakingActivity(_ *rpctypes.Context, period *string) (*StakingActivityResponse, error) {
+	p, err := parseActivityPeriod(period)
+	if err != nil {
+		return nil, err
+	}
+
+	events, err := s.staker.StakingActivity()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get staking activity: %w", err)
+	}
+
+	return &StakingActivityResponse{
+		Period:  p,
+		Buckets: bucketActivityEvents(events, p),
+	}, nil
+}
+
+// cancelStake cancels staking transaction not yet confirmed on btc
+func (s *StakerService) cancelStake(_ *rpctypes.Context, stakingTxHash string) (*CancelStakeResponse, error) {
+	txHash, err := chainhash.NewHashFromStr(stakingTxHash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse staking tx hash: %w", err)
+	}
+
+	cancelTxHash, fee, err := s.staker.CancelStake(txHash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to cancel stake: %w", err)
+	}
+
+	if cancelTxHash == nil {
+		return &CancelStakeResponse{}, nil
+	}
+
+	return &CancelStakeResponse{
+		CancelTxHash: cancelTxHash.String(),
+		Fee:          fee.String(),
+	}, nil
+}
+
 // unbondStaking unbonds a staking transaction
-func (s *StakerService) unbondStaking(_ *rpctypes.Context, stakingTxHash string) (*UnbondingResponse, error) {
+func (s *StakerService) unbondStaking(
+	_ *rpctypes.Context,
+	stakingTxHash string,
+	feeRate *int64,
+	targetConf *int64,
+	tenant *string,
+) (*UnbondingResponse, error) {
 	txHash, err := chainhash.NewHashFromStr(stakingTxHash)
 
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse staking tx hash: %w", err)
 	}
 
-	unbondingTxHash, err := s.staker.UnbondStaking(*txHash)
+	if err := s.checkTenant(txHash, tenant); err != nil {
+		return nil, err
+	}
+
+	fee, err := parseFeeSelection(feeRate, targetConf)
+	if err != nil {
+		return nil, err
+	}
+
+	unbondingTxHash, err := s.staker.UnbondStaking(*txHash, fee)
 
 	if err != nil {
 		return nil, fmt.Errorf("failed to unbond staking: %w", err)
@@ -545,30 +1423,112 @@ func (s *StakerService) btcStakingParamsByBtcHeight(_ *rpctypes.Context, btcHeig
 	}, nil
 }
 
+func (s *StakerService) archivedParams(_ *rpctypes.Context) (*ArchivedParamsResponse, error) {
+	archived, err := s.staker.ArchivedParams()
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &ArchivedParamsResponse{Params: make([]ArchivedParams, 0, len(archived))}
+	for _, p := range archived {
+		resp.Params = append(resp.Params, ArchivedParams{
+			Version:             p.Version,
+			BtcActivationHeight: p.BtcActivationHeight,
+			CovenantPkHex:       ParseCovenantsPubKeyToHex(p.CovenantPks...),
+			CovenantQuorum:      p.CovenantQuruomThreshold,
+			UnbondingTime:       p.UnbondingTime,
+			UnbondingFee:        strconv.FormatInt(int64(p.UnbondingFee), 10),
+			MinStakingTime:      p.MinStakingTime,
+			MaxStakingTime:      p.MaxStakingTime,
+			MinStakingValue:     strconv.FormatInt(int64(p.MinStakingValue), 10),
+			MaxStakingValue:     strconv.FormatInt(int64(p.MaxStakingValue), 10),
+		})
+	}
+
+	return resp, nil
+}
+
 // GetRoutes returns a list of routes this service handles
 func (s *StakerService) GetRoutes() RoutesMap {
-	return RoutesMap{
+	routes := RoutesMap{
 		// info AP
-		"health": NewRPCFunc(s.health, ""),
+		"health":  NewRPCFunc(s.health, ""),
+		"version": NewRPCFunc(s.version, ""),
+		"stats":   NewRPCFunc(s.stats, "tenant"),
 		// staking API
-		"stake":                              NewRPCFunc(s.stake, "stakerAddress,stakingAmount,fpBtcPks,stakingTimeBlocks"),
-		"stake_expand":                       NewRPCFunc(s.stakeExpand, "stakerAddress,stakingAmount,fpBtcPks,stakingTimeBlocks,prevActiveStkTxHashHex"),
+		"stake":                              NewRPCFunc(s.stake, "stakerAddress,stakingAmount,fpBtcPks,stakingTimeBlocks,tenant,template,reference,feeRate,targetConf,unbondingTime"),
+		"estimate_stake":                     NewRPCFunc(s.estimateStake, "stakerAddress,stakingAmount,fpBtcPks,stakingTimeBlocks,reference,feeRate,targetConf"),
+		"stake_expand":                       NewRPCFunc(s.stakeExpand, "stakerAddress,stakingAmount,fpBtcPks,stakingTimeBlocks,prevActiveStkTxHashHex,tenant"),
 		"consolidate_utxos":                  NewRPCFunc(s.consolidateUTXOs, "stakerAddress,targetAmount"),
 		"btc_delegation_from_btc_staking_tx": NewRPCFunc(s.btcDelegationFromBtcStakingTx, "stakerAddress,btcStkTxHash,covenantPksHex,covenantQuorum"),
-		"staking_details":                    NewRPCFunc(s.stakingDetails, "stakingTxHash"),
-		"spend_stake":                        NewRPCFunc(s.spendStake, "stakingTxHash"),
-		"list_staking_transactions":          NewRPCFunc(s.listStakingTransactions, "offset,limit"),
-		"unbond_staking":                     NewRPCFunc(s.unbondStaking, "stakingTxHash"),
+		"retry_delegation_registration":      NewRPCFunc(s.retryDelegationRegistration, "stakingTxHash,covenantPksHex,covenantQuorum"),
+		"renotify_covenant":                  NewRPCFunc(s.renotifyCovenant, "stakingTxHash"),
+		"staking_details":                    NewRPCFunc(s.stakingDetails, "stakingTxHash,tenant"),
+		"staking_details_batch":              NewRPCFunc(s.stakingDetailsBatch, "stakingTxHashes,tenant"),
+		"find_delegation_by_tx":              NewRPCFunc(s.findDelegationByTx, "txHash,tenant"),
+		"spend_stake":                        NewRPCFunc(s.spendStake, "stakingTxHash,feeRate,targetConf,tenant"),
+		"restake_from_unbonded":              NewRPCFunc(s.restakeFromUnbonded, "stakingTxHash,fpBtcPks,stakingTimeBlocks"),
+		"cancel_stake":                       NewRPCFunc(s.cancelStake, "stakingTxHash"),
+		"list_staking_transactions":          NewRPCFunc(s.listStakingTransactions, "offset,limit,fields,sortBy,sortDirection,tenant"),
+		"unbond_staking":                     NewRPCFunc(s.unbondStaking, "stakingTxHash,feeRate,targetConf,tenant"),
 		"btc_staking_param_by_btc_height":    NewRPCFunc(s.btcStakingParamsByBtcHeight, "btcHeight"),
-		"withdrawable_transactions":          NewRPCFunc(s.withdrawableTransactions, "offset,limit"),
+		"archived_params":                    NewRPCFunc(s.archivedParams, ""),
+		"withdrawable_transactions":          NewRPCFunc(s.withdrawableTransactions, "offset,limit,fields,tenant"),
 		"btc_tx_blk_details":                 NewRPCFunc(s.btcTxBlkDetails, "txHashStr"),
+		"staking_activity":                   NewRPCFunc(s.stakingActivity, "period"),
+		"subscribe_db_changes":               NewRPCFunc(s.subscribeDBChanges, "resumeToken,limit,waitSecs"),
+		"quarantined_records":                NewRPCFunc(s.quarantinedRecords, ""),
+		"replay_events":                      NewRPCFunc(s.replayEvents, "afterEventId,limit"),
+		"approve_operation":                  NewRPCFunc(s.approveOperation, "operationId"),
+		"list_operations":                    NewRPCFunc(s.listOperations, ""),
+		"delegation_templates":               NewRPCFunc(s.delegationTemplates, ""),
+		"set_delegation_template":            NewRPCFunc(s.setDelegationTemplate, "name,stakerAddress,fpBtcPks,stakingTimeBlocks,maxFeeRate,tenant,labels"),
+		"delete_delegation_template":         NewRPCFunc(s.deleteDelegationTemplate, "name"),
+		"queued_stakes":                      NewRPCFunc(s.queuedStakes, ""),
+		"cancel_queued_stake":                NewRPCFunc(s.cancelQueuedStake, "id"),
+		"delegation_history":                 NewRPCFunc(s.delegationHistory, "stakingTxHash"),
+		"activation_latency":                 NewRPCFunc(s.activationLatency, "stakingTxHash"),
+		"list_stuck_delegations":             NewRPCFunc(s.listStuckDelegations, ""),
+		"expiring_delegations":               NewRPCFunc(s.expiringDelegations, "withinBlocks"),
+		"set_auto_renew":                     NewRPCFunc(s.setAutoRenew, "stakingTxHash,enabled,stakingTimeBlocks"),
+		"renewal_jobs":                       NewRPCFunc(s.renewalJobs, ""),
+		"chain_safety":                       NewRPCFunc(s.chainSafety, ""),
+		"maintenance_status":                 NewRPCFunc(s.maintenanceStatus, ""),
+		"set_chain_safety_override":          NewRPCFunc(s.setChainSafetyOverride, "override"),
+		"export_delegation":                  NewRPCFunc(s.exportDelegation, "stakingTxHash"),
+		"import_delegation":                  NewRPCFunc(s.importDelegation, "bundle"),
+		"register_musig2_key":                NewRPCFunc(s.registerMuSig2Key, "name,pubKeys"),
+		"musig2_keys":                        NewRPCFunc(s.listMuSig2Keys, ""),
+		"start_musig2_session":               NewRPCFunc(s.startMuSig2Session, "keyName,kind,stakingTxHash,stakingTxHex,destAddress,feeRate,targetConf"),
+		"musig2_sessions":                    NewRPCFunc(s.listMuSig2Sessions, ""),
+		"musig2_session":                     NewRPCFunc(s.muSig2Session, "id"),
+		"submit_musig2_nonce":                NewRPCFunc(s.submitMuSig2Nonce, "id,signerPk,nonce"),
+		"submit_musig2_partial_sig":          NewRPCFunc(s.submitMuSig2PartialSig, "id,signerPk,partialSig"),
 
 		// Wallet api
-		"list_outputs": NewRPCFunc(s.listOutputs, ""),
+		"list_outputs":            NewRPCFunc(s.listOutputs, ""),
+		"list_reserved_outpoints": NewRPCFunc(s.listReservedOutpoints, ""),
+		"unreserve_outpoint":      NewRPCFunc(s.unreserveOutpoint, "outpoint"),
+		"new_staker_address":      NewRPCFunc(s.newStakerAddress, "addressType"),
+		"unlock_wallet":           NewRPCFunc(s.unlockWallet, "passphrase,timeoutSecs"),
+		"list_staker_addresses":   NewRPCFunc(s.listStakerAddresses, ""),
+		"sign_message":            NewRPCFunc(s.signMessage, "address,message"),
+		"sign_spend_tx":           NewRPCFunc(s.signSpendTx, "stakingTxHash,kind,txHex"),
+		"verify_message":          NewRPCFunc(s.verifyMessage, "address,message,signature"),
 
 		// Babylon api
 		"babylon_finality_providers": NewRPCFunc(s.providers, "offset,limit"),
+		"finality_provider_quotas":   NewRPCFunc(s.finalityProviderQuotas, ""),
+
+		// Replica api
+		"replica_status": NewRPCFunc(s.replicaStatus, ""),
+	}
+
+	if s.config.ReplicaConfig.Enabled() {
+		return readOnlyRoutes(routes)
 	}
+
+	return routes
 }
 
 // RunUntilShutdown runs the service until the context is canceled
@@ -577,18 +1537,22 @@ func (s *StakerService) RunUntilShutdown(ctx context.Context, expUser, expPwd st
 		return nil
 	}
 
+	// components are stopped in dependency order instead of reverse order of
+	// start, so that database is closed only once nothing writes to it
+	shutdown := &shutdownSequence{logger: s.logger}
 	defer func() {
+		if err := shutdown.run(); err != nil {
+			s.logger.WithError(err).Error("Shutdown completed with errors")
+			return
+		}
 		s.logger.Info("Shutdown complete")
 	}()
 
-	defer func() {
-		s.logger.Info("Closing database...")
-		if err := s.db.Close(); err != nil {
-			s.logger.Errorf("Error closing database: %v", err)
-		} else {
-			s.logger.Info("Database closed")
-		}
-	}()
+	if s.db != nil {
+		// database changes and activity are written synchronously, so there
+		// is no event log to flush before closing it
+		shutdown.add(stageDB, s.db.Close)
+	}
 
 	mkErr := func(format string, args ...interface{}) error {
 		logFormat := strings.ReplaceAll(format, "%w", "%v")
@@ -602,69 +1566,55 @@ func (s *StakerService) RunUntilShutdown(ctx context.Context, expUser, expPwd st
 		return mkErr("error starting staker: %w", err)
 	}
 
-	defer func() {
-		err := s.staker.Stop()
+	shutdown.add(stageStaker, s.staker.Stop)
+
+	principals := map[string]string{expUser: expPwd}
+	if s.config.ApprovalConfig != nil && s.config.ApprovalConfig.Enabled {
+		approvers, err := s.config.ApprovalConfig.Principals()
 		if err != nil {
-			s.logger.WithError(err).Info("staker stop with error")
+			return mkErr("invalid approvers: %w", err)
 		}
-		s.logger.Info("staker stop complete")
-	}()
+
+		for user, pass := range approvers {
+			if strings.EqualFold(user, expUser) {
+				return mkErr("approver %s must differ from the operator", user)
+			}
+			principals[user] = pass
+		}
+	}
 
 	routes := s.GetRoutes()
 	// This way logger will log to stdout and file
 	// TODO: investigate if we can use logrus directly to pass it to rpcserver
 	rpcLogger := log.NewTMLogger(s.logger.Writer())
 
-	listeners := make([]net.Listener, len(s.config.RPCListeners))
-	for i, listenAddr := range s.config.RPCListeners {
-		listenAddressStr := listenAddr.Network() + "://" + listenAddr.String()
-		mux := http.NewServeMux()
+	mux := http.NewServeMux()
+	authMiddleware := PrincipalsAuthMiddleware(principals)
+	RegisterRPCFuncs(mux, routes, rpcLogger, authMiddleware, s.staker.Metrics().RPC)
+	RegisterProbes(mux, s.staker.Readiness)
 
-		authMiddleware := BasicAuthMiddleware(expUser, expPwd)
-		RegisterRPCFuncs(mux, routes, rpcLogger, authMiddleware)
+	// listeners are not tied to this function, so that they can be reloaded
+	// while service runs
+	shutdown.add(stageRPCIntake, s.stopRPC)
+	if err := s.serveRPC(mux, rpcLogger); err != nil {
+		return mkErr("error starting rpc listeners: %w", err)
+	}
 
-		listener, err := rpc.Listen(
-			listenAddressStr,
-			s.config.JSONRPCServerConfig.MaxOpenConnections,
-		)
+	s.logger.Info("Staker Service started, reconciling stored delegations")
 
-		if err != nil {
-			return mkErr("unable to listen on %s: %v",
-				listenAddressStr, err)
+	select {
+	case <-s.staker.StartupSyncDone():
+		if err := s.staker.StartupSyncErr(); err != nil {
+			return mkErr("error reconciling stored delegations: %w", err)
 		}
 
-		defer func() {
-			err := listener.Close()
-			if err != nil {
-				s.logger.Error("Error closing listener", "err", err)
-			}
-		}()
-
-		// Start standard HTTP server serving json-rpc
-		// TODO: Add additional middleware, like CORS, TLS, etc.
-		// TODO: Consider we need some websockets for some notications
-		go func() {
-			s.logger.Debug("Starting Json RPC HTTP server ", "address: ", listenAddressStr)
-
-			if err := rpc.Serve(
-				listener,
-				mux,
-				rpcLogger,
-				s.config.JSONRPCServerConfig.Config(),
-			); err != nil {
-				s.logger.WithError(err).Error("problem at JSON RPC HTTP server")
-			}
-			s.logger.Info("Json RPC HTTP server stopped ")
-		}()
+		s.logger.Info("Staker Service fully started")
 
-		listeners[i] = listener
+		// Wait for shutdown signal from either a graceful service stop or from cancel()
+		<-ctx.Done()
+	case <-ctx.Done():
 	}
 
-	s.logger.Info("Staker Service fully started")
-
-	// Wait for shutdown signal from either a graceful service stop or from cancel()
-	<-ctx.Done()
-
 	s.logger.Info("Received shutdown signal. Stopping...")
 
 	return nil
@@ -673,15 +1623,26 @@ func (s *StakerService) RunUntilShutdown(ctx context.Context, expUser, expPwd st
 // BasicAuthMiddleware handles the authentication of username and password
 // of this router
 func BasicAuthMiddleware(expUsername, expPwd string) func(http.HandlerFunc) http.HandlerFunc {
+	return PrincipalsAuthMiddleware(map[string]string{expUsername: expPwd})
+}
+
+// PrincipalsAuthMiddleware authenticates any of the given principals, passwords
+// by user name, and stores the authenticated user in the request context
+func PrincipalsAuthMiddleware(principals map[string]string) func(http.HandlerFunc) http.HandlerFunc {
 	return func(next http.HandlerFunc) http.HandlerFunc {
 		return func(w http.ResponseWriter, r *http.Request) {
 			user, pass, ok := r.BasicAuth()
-			if !ok || !strings.EqualFold(user, expUsername) || !strings.EqualFold(pass, expPwd) {
-				w.Header().Set("WWW-Authenticate", `Basic realm="Restricted"`)
-				http.Error(w, "Unauthorized", http.StatusUnauthorized)
-				return
+			if ok {
+				for expUsername, expPwd := range principals {
+					if strings.EqualFold(user, expUsername) && strings.EqualFold(pass, expPwd) {
+						next(w, withPrincipal(r, expUsername))
+						return
+					}
+				}
 			}
-			next(w, r)
+
+			w.Header().Set("WWW-Authenticate", `Basic realm="Restricted"`)
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
 		}
 	}
 }
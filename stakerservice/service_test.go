@@ -1,16 +1,143 @@
 package stakerservice_test
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"testing"
+	"time"
 
+	"github.com/babylonlabs-io/btc-staker/metrics"
+	"github.com/babylonlabs-io/btc-staker/staker"
+	"github.com/babylonlabs-io/btc-staker/stakercfg"
+	"github.com/babylonlabs-io/btc-staker/stakerdb"
 	"github.com/babylonlabs-io/btc-staker/stakerservice"
+	"github.com/babylonlabs-io/btc-staker/testutil/simulation"
+	"github.com/babylonlabs-io/btc-staker/walletcontroller"
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
 	"github.com/cometbft/cometbft/libs/log"
 	rpctypes "github.com/cometbft/cometbft/rpc/jsonrpc/types"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
 )
 
+// simulatedService is staker service backed by simulated chain, wallet and
+// babylon, serving rpc routes without authentication
+type simulatedService struct {
+	sim   *simulation.Simulation
+	app   *staker.App
+	store *stakerdb.TrackedTransactionStore
+	mux   *http.ServeMux
+}
+
+func newSimulatedService(t *testing.T, configure ...func(cfg *stakercfg.Config)) *simulatedService {
+	sim, err := simulation.New([]byte("stakerservice"))
+	require.NoError(t, err)
+
+	dbCfg := stakercfg.DefaultDBConfig()
+	dbCfg.DBPath = t.TempDir()
+	dbCfg.NoSync = true
+
+	db, err := stakercfg.GetDBBackend(&dbCfg)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = db.Close()
+	})
+
+	logger := logrus.New()
+	logger.SetLevel(logrus.WarnLevel)
+
+	cfg := sim.Config()
+	for _, c := range configure {
+		c(cfg)
+	}
+	app, err := sim.NewApp(cfg, db, logger)
+	require.NoError(t, err)
+
+	store, err := stakerdb.NewTrackedTransactionStore(db)
+	require.NoError(t, err)
+
+	mux := http.NewServeMux()
+	noAuth := func(next http.HandlerFunc) http.HandlerFunc { return next }
+	routes := stakerservice.NewStakerService(cfg, app, logger, db).GetRoutes()
+	stakerservice.RegisterRPCFuncs(mux, routes, log.NewNopLogger(), noAuth, nil)
+
+	return &simulatedService{sim: sim, app: app, store: store, mux: mux}
+}
+
+// call calls rpc method with given uri params and returns the response
+func (s *simulatedService) call(t *testing.T, method string, params url.Values) rpctypes.RPCResponse {
+	rr := httptest.NewRecorder()
+	s.mux.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/"+method+"?"+params.Encode(), nil))
+
+	var resp rpctypes.RPCResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+	return resp
+}
+
+// addTrackedTransaction adds staking transaction of the given tenant to the
+// store, without sending it to babylon
+func (s *simulatedService) addTrackedTransaction(t *testing.T, tenant string) *chainhash.Hash {
+	addr, err := s.sim.Wallet.NewAddress(walletcontroller.AddressTypeTaproot)
+	require.NoError(t, err)
+	pkScript, err := txscript.PayToAddrScript(addr)
+	require.NoError(t, err)
+
+	tx := wire.NewMsgTx(2)
+	tx.AddTxIn(wire.NewTxIn(wire.NewOutPoint(&chainhash.Hash{byte(len(tenant))}, 0), nil, nil))
+	tx.AddTxOut(wire.NewTxOut(100_000, pkScript))
+	require.NoError(t, s.store.AddTenantTransactionSentToBabylon(tx, addr, tenant))
+
+	txHash := tx.TxHash()
+	return &txHash
+}
+
+// activeDelegation starts the app and stakes funds of a new wallet address,
+// driving the delegation through covenant signatures and activation
+func (s *simulatedService) activeDelegation(t *testing.T, amount btcutil.Amount) *chainhash.Hash {
+	addr, err := s.sim.Wallet.NewAddress(walletcontroller.AddressTypeTaproot)
+	require.NoError(t, err)
+	require.NoError(t, s.sim.Wallet.FundAddress(addr, btcutil.SatoshiPerBitcoin))
+	s.sim.Chain.MineBlocks(1)
+
+	require.NoError(t, s.app.Start())
+	t.Cleanup(func() {
+		_ = s.app.Stop()
+	})
+	<-s.app.StartupSyncDone()
+	require.NoError(t, s.app.StartupSyncErr())
+
+	fp := s.sim.Babylon.AddFinalityProvider()
+	stakingTxHash, err := s.app.StakeFunds(
+		addr, amount, []*btcec.PublicKey{fp}, 100, "", "", staker.FeeSelection{},
+	)
+	require.NoError(t, err)
+
+	require.NoError(t, s.sim.Babylon.SignDelegation(stakingTxHash))
+	require.Eventually(t, func() bool {
+		return s.sim.Chain.InMempool(stakingTxHash)
+	}, 10*time.Second, 50*time.Millisecond)
+
+	s.sim.Chain.MineBlocks(3)
+	require.NoError(t, s.sim.Babylon.ActivateDelegation(stakingTxHash))
+
+	return stakingTxHash
+}
+
+// quoted returns string rpc uri parameter
+func quoted(s string) string {
+	return `"` + s + `"`
+}
+
 // TestRegisterRPCFuncs verifies that routes are protected by Basic Auth.
 func TestRegisterRPCFuncs(t *testing.T) {
 	t.Parallel()
@@ -27,7 +154,7 @@ func TestRegisterRPCFuncs(t *testing.T) {
 		}, ""),
 	}
 
-	stakerservice.RegisterRPCFuncs(mux, funcMap, log.NewNopLogger(), stakerservice.BasicAuthMiddleware(username, password))
+	stakerservice.RegisterRPCFuncs(mux, funcMap, log.NewNopLogger(), stakerservice.BasicAuthMiddleware(username, password), nil)
 
 	t.Run("Valid Authenticated Request", func(t *testing.T) {
 		t.Parallel()
@@ -67,3 +194,430 @@ func TestRegisterRPCFuncs(t *testing.T) {
 		}
 	})
 }
+
+// TestRPCMetrics verifies that requests are counted per method
+func TestRPCMetrics(t *testing.T) {
+	t.Parallel()
+	m := metrics.NewStakerMetrics().RPC
+	mux := http.NewServeMux()
+
+	funcMap := map[string]*stakerservice.RPCFunc{
+		"health": stakerservice.NewRPCFunc(func(_ *rpctypes.Context) (*stakerservice.ResultHealth, error) {
+			return &stakerservice.ResultHealth{}, nil
+		}, ""),
+		"failing": stakerservice.NewRPCFunc(func(_ *rpctypes.Context) (*stakerservice.ResultHealth, error) {
+			return nil, errors.New("failed")
+		}, ""),
+		"with_arg": stakerservice.NewRPCFunc(func(_ *rpctypes.Context, _ int) (*stakerservice.ResultHealth, error) {
+			return &stakerservice.ResultHealth{}, nil
+		}, "limit"),
+	}
+
+	noAuth := func(next http.HandlerFunc) http.HandlerFunc { return next }
+	stakerservice.RegisterRPCFuncs(mux, funcMap, log.NewNopLogger(), noAuth, m)
+
+	for _, path := range []string{"/health", "/health", "/failing", "/with_arg?limit=abc"} {
+		mux.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	require.Equal(t, 2.0, testutil.ToFloat64(m.Requests.WithLabelValues("health")))
+	require.Equal(t, 0.0, testutil.ToFloat64(m.Errors.WithLabelValues("health")))
+	require.Equal(t, 1.0, testutil.ToFloat64(m.Requests.WithLabelValues("failing")))
+	require.Equal(t, 1.0, testutil.ToFloat64(m.Errors.WithLabelValues("failing")))
+	// invalid params are counted as errors
+	require.Equal(t, 1.0, testutil.ToFloat64(m.Errors.WithLabelValues("with_arg")))
+	require.Equal(t, 2, testutil.CollectAndCount(m.Latency))
+}
+
+func TestParseFieldSelection(t *testing.T) {
+	t.Parallel()
+
+	sel, err := stakerservice.ParseFieldSelection("")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(sel) != len(stakerservice.AllFields()) {
+		t.Errorf("Expected all fields to be selected, got %d", len(sel))
+	}
+
+	sel, err = stakerservice.ParseFieldSelection("stakingTxHash, state")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !sel.Has(stakerservice.FieldStakingTxHash) || !sel.Has(stakerservice.FieldState) {
+		t.Errorf("Expected stakingTxHash and state to be selected")
+	}
+	if sel.Has(stakerservice.FieldStakerAddress) || sel.Has(stakerservice.FieldTransactionIdx) {
+		t.Errorf("Expected stakerAddress and transactionIdx not to be selected")
+	}
+
+	if _, err := stakerservice.ParseFieldSelection("stakingTxHash,unknown"); err == nil {
+		t.Errorf("Expected error for unknown field")
+	}
+
+	if _, err := stakerservice.ParseFieldSelection(" , "); err == nil {
+		t.Errorf("Expected error for empty field list")
+	}
+}
+
+// TestProbes verifies that probes do not require authentication and that
+// readiness probe fails if any check fails
+func TestProbes(t *testing.T) {
+	t.Parallel()
+	checks := []staker.ReadinessCheck{
+		{Name: "database", Ready: true},
+		{Name: "babylon", Error: "unreachable"},
+	}
+
+	mux := http.NewServeMux()
+	stakerservice.RegisterProbes(mux, func() []staker.ReadinessCheck { return checks })
+
+	rr := httptest.NewRecorder()
+	mux.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	require.Equal(t, http.StatusOK, rr.Code)
+
+	rr = httptest.NewRecorder()
+	mux.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	require.Equal(t, http.StatusServiceUnavailable, rr.Code)
+
+	var resp stakerservice.ReadinessResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+	require.False(t, resp.Ready)
+	require.Len(t, resp.Checks, 2)
+	require.Equal(t, "unreachable", resp.Checks[1].Error)
+
+	checks[1] = staker.ReadinessCheck{Name: "babylon", Ready: true}
+	rr = httptest.NewRecorder()
+	mux.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	require.Equal(t, http.StatusOK, rr.Code)
+}
+
+// TestTenantIsEnforced verifies that transactions of other tenants can't be
+// seen or spent when tenant is given
+func TestTenantIsEnforced(t *testing.T) {
+	t.Parallel()
+	s := newSimulatedService(t)
+	txHash := s.addTrackedTransaction(t, "desk-a")
+
+	for _, method := range []string{"staking_details", "spend_stake", "unbond_staking"} {
+		resp := s.call(t, method, url.Values{
+			"stakingTxHash": {quoted(txHash.String())},
+			"tenant":        {quoted("desk-b")},
+		})
+		require.NotNil(t, resp.Error, method)
+		require.Contains(t, resp.Error.Data, "does not belong to tenant desk-b", method)
+
+		resp = s.call(t, method, url.Values{
+			"stakingTxHash": {quoted(txHash.String())},
+			"tenant":        {quoted("invalid tenant")},
+		})
+		require.NotNil(t, resp.Error, method)
+		require.Contains(t, resp.Error.Data, "invalid tenant", method)
+	}
+
+	resp := s.call(t, "staking_details_batch", url.Values{
+		"stakingTxHashes": {`["` + txHash.String() + `"]`},
+		"tenant":          {quoted("desk-b")},
+	})
+	require.Nil(t, resp.Error)
+	var batch stakerservice.StakingDetailsBatchResponse
+	require.NoError(t, json.Unmarshal(resp.Result, &batch))
+	require.Empty(t, batch.Details)
+	require.Len(t, batch.Failed, 1)
+	require.Contains(t, batch.Failed[0].Error, "does not belong to tenant desk-b")
+
+	// transactions of other tenants are not listed, so babylon is not queried
+	// for them
+	resp = s.call(t, "withdrawable_transactions", url.Values{"tenant": {quoted("desk-b")}})
+	require.Nil(t, resp.Error)
+	var withdrawable stakerservice.WithdrawableTransactionsResponse
+	require.NoError(t, json.Unmarshal(resp.Result, &withdrawable))
+	require.Empty(t, withdrawable.Transactions)
+}
+
+// TestSignSpendTxParams verifies that sign_spend_tx takes camel case params
+// like other routes
+func TestSignSpendTxParams(t *testing.T) {
+	t.Parallel()
+	s := newSimulatedService(t)
+
+	resp := s.call(t, "sign_spend_tx", url.Values{
+		"stakingTxHash": {quoted(chainhash.Hash{1}.String())},
+		"kind":          {quoted("unbonding")},
+		"txHex":         {quoted("00")},
+	})
+	require.NotNil(t, resp.Error)
+	require.Contains(t, resp.Error.Data, "error decoding transaction")
+}
+
+// bip322 test vectors signed by the p2wpkh key of the mainnet address
+// bc1q9vza2e8x573nczrlzms0wvx3gsqjx7vavgkx0l, see
+// https://github.com/bitcoin/bips/blob/master/bip-0322.mediawiki#message-signing
+var bip322TestVectors = []struct {
+	message   string
+	signature string
+}{
+	{
+		message:   "",
+		signature: "AkcwRAIgM2gBAQqvZX15ZiysmKmQpDrG83avLIT492QBzLnQIxYCIBaTpOaD20qRlEylyxFSeEA2ba9YOixpX8z46TSDtS40ASECx/EgAxlkQpQ9hYjgGu6EBCPMVPwVIVJqO4XCsMvViHI=",
+	},
+	{
+		message:   "Hello World",
+		signature: "AkcwRAIgZRfIY3p7/DoVTty6YZbWS71bc5Vct9p9Fia83eRmw2QCICK/ENGfwLtptFluMGs2KsqoNSk89pO7F29zJLUx9a/sASECx/EgAxlkQpQ9hYjgGu6EBCPMVPwVIVJqO4XCsMvViHI=",
+	},
+}
+
+// verifyMessage calls verify_message and returns its response
+func (s *simulatedService) verifyMessage(t *testing.T, address, message, signature string) stakerservice.VerifyMessageResponse {
+	resp := s.call(t, "verify_message", url.Values{
+		"address":   {quoted(address)},
+		"message":   {quoted(message)},
+		"signature": {quoted(signature)},
+	})
+	require.Nil(t, resp.Error)
+
+	var verified stakerservice.VerifyMessageResponse
+	require.NoError(t, json.Unmarshal(resp.Result, &verified))
+	return verified
+}
+
+// TestVerifyMessageTestVectors verifies bip322 test vectors, with the address
+// of the vectors encoded for the network of the service
+func TestVerifyMessageTestVectors(t *testing.T) {
+	t.Parallel()
+	s := newSimulatedService(t)
+
+	mainnetAddr, err := btcutil.DecodeAddress("bc1q9vza2e8x573nczrlzms0wvx3gsqjx7vavgkx0l", &chaincfg.MainNetParams)
+	require.NoError(t, err)
+	addr, err := btcutil.NewAddressWitnessPubKeyHash(mainnetAddr.ScriptAddress(), s.sim.Chain.Params())
+	require.NoError(t, err)
+
+	for _, vector := range bip322TestVectors {
+		verified := s.verifyMessage(t, addr.EncodeAddress(), vector.message, vector.signature)
+		require.True(t, verified.Valid, verified.Error)
+	}
+
+	// signature of one vector is not valid for the message of the other
+	verified := s.verifyMessage(t, addr.EncodeAddress(), bip322TestVectors[0].message, bip322TestVectors[1].signature)
+	require.False(t, verified.Valid)
+	require.NotEmpty(t, verified.Error)
+}
+
+// TestSignAndVerifyMessage verifies that message signed by wallet address is
+// valid only for that address and message
+func TestSignAndVerifyMessage(t *testing.T) {
+	t.Parallel()
+	s := newSimulatedService(t)
+
+	const message = "staker owns this address"
+	for _, addressType := range []walletcontroller.AddressType{
+		walletcontroller.AddressTypeSegwit,
+		walletcontroller.AddressTypeTaproot,
+	} {
+		addr, err := s.sim.Wallet.NewAddress(addressType)
+		require.NoError(t, err)
+		otherAddr, err := s.sim.Wallet.NewAddress(addressType)
+		require.NoError(t, err)
+
+		resp := s.call(t, "sign_message", url.Values{
+			"address": {quoted(addr.EncodeAddress())},
+			"message": {quoted(message)},
+		})
+		require.Nil(t, resp.Error, addressType)
+		var signed stakerservice.SignMessageResponse
+		require.NoError(t, json.Unmarshal(resp.Result, &signed))
+		require.Equal(t, addr.EncodeAddress(), signed.Address)
+		require.Equal(t, message, signed.Message)
+
+		verified := s.verifyMessage(t, addr.EncodeAddress(), message, signed.Signature)
+		require.True(t, verified.Valid, verified.Error)
+
+		verified = s.verifyMessage(t, otherAddr.EncodeAddress(), message, signed.Signature)
+		require.False(t, verified.Valid, addressType)
+		require.NotEmpty(t, verified.Error, addressType)
+
+		verified = s.verifyMessage(t, addr.EncodeAddress(), message+".", signed.Signature)
+		require.False(t, verified.Valid, addressType)
+		require.NotEmpty(t, verified.Error, addressType)
+	}
+}
+
+// TestSignMessageErrors verifies that message is signed only by valid wallet
+// addresses and that malformed signatures are rejected
+func TestSignMessageErrors(t *testing.T) {
+	t.Parallel()
+	s := newSimulatedService(t)
+
+	// address of mainnet is not valid on the network of the service
+	resp := s.call(t, "sign_message", url.Values{
+		"address": {quoted("bc1q9vza2e8x573nczrlzms0wvx3gsqjx7vavgkx0l")},
+		"message": {quoted("Hello World")},
+	})
+	require.NotNil(t, resp.Error)
+	require.Contains(t, resp.Error.Data, "is not for network")
+
+	key, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+	foreignAddr, err := btcutil.NewAddressWitnessPubKeyHash(
+		btcutil.Hash160(key.PubKey().SerializeCompressed()), s.sim.Chain.Params(),
+	)
+	require.NoError(t, err)
+
+	resp = s.call(t, "sign_message", url.Values{
+		"address": {quoted(foreignAddr.EncodeAddress())},
+		"message": {quoted("Hello World")},
+	})
+	require.NotNil(t, resp.Error)
+	require.Contains(t, resp.Error.Data, "not under wallet control")
+
+	resp = s.call(t, "verify_message", url.Values{
+		"address":   {quoted(foreignAddr.EncodeAddress())},
+		"message":   {quoted("Hello World")},
+		"signature": {quoted("not base64")},
+	})
+	require.NotNil(t, resp.Error)
+	require.Contains(t, resp.Error.Data, "signature must be base64 encoded")
+}
+
+// TestStakingDetailsAmounts verifies that staking details contain staking
+// amount, fee paid by staking transaction and unbonding fee
+func TestStakingDetailsAmounts(t *testing.T) {
+	t.Parallel()
+	s := newSimulatedService(t)
+	stakingTxHash := s.activeDelegation(t, 100_000)
+
+	stakingTx, err := s.sim.Chain.Tx(stakingTxHash)
+	require.NoError(t, err)
+	var fee btcutil.Amount
+	for _, in := range stakingTx.TxIn {
+		prevTx, err := s.sim.Chain.Tx(&in.PreviousOutPoint.Hash)
+		require.NoError(t, err)
+		fee += btcutil.Amount(prevTx.TxOut[in.PreviousOutPoint.Index].Value)
+	}
+	for _, out := range stakingTx.TxOut {
+		fee -= btcutil.Amount(out.Value)
+	}
+	require.Positive(t, fee)
+
+	params, err := s.sim.Babylon.Params()
+	require.NoError(t, err)
+
+	resp := s.call(t, "staking_details", url.Values{"stakingTxHash": {quoted(stakingTxHash.String())}})
+	require.Nil(t, resp.Error)
+	var details stakerservice.StakingDetails
+	require.NoError(t, json.Unmarshal(resp.Result, &details))
+	require.Equal(t, btcutil.Amount(100_000).String(), details.StakingAmount)
+	require.Equal(t, fee.String(), details.Fee)
+	require.Equal(t, params.UnbondingFee.String(), details.UnbondingFee)
+
+	// amounts are returned only when selected
+	resp = s.call(t, "list_staking_transactions", url.Values{"fields": {quoted("stakingTxHash,stakingAmount")}})
+	require.Nil(t, resp.Error)
+	var list stakerservice.ListStakingTransactionsResponse
+	require.NoError(t, json.Unmarshal(resp.Result, &list))
+	require.Len(t, list.Transactions, 1)
+	require.Equal(t, stakingTxHash.String(), list.Transactions[0].StakingTxHash)
+	require.Equal(t, btcutil.Amount(100_000).String(), list.Transactions[0].StakingAmount)
+	require.Empty(t, list.Transactions[0].Fee)
+	require.Empty(t, list.Transactions[0].UnbondingFee)
+}
+
+func TestVersion(t *testing.T) {
+	t.Parallel()
+
+	version := func(t *testing.T, s *simulatedService) stakerservice.VersionResponse {
+		resp := s.call(t, "version", url.Values{})
+		require.Nil(t, resp.Error)
+
+		var result stakerservice.VersionResponse
+		require.NoError(t, json.Unmarshal(resp.Result, &result))
+		return result
+	}
+
+	result := version(t, newSimulatedService(t))
+	require.NotEmpty(t, result.Version)
+	require.Equal(t, uint32(1), result.APIVersion)
+	require.Equal(t, chaincfg.RegressionNetParams.Name, result.Network)
+	require.Equal(t, map[string]bool{
+		stakerservice.FeatureWebsockets:      false,
+		stakerservice.FeatureGrpc:            false,
+		stakerservice.FeatureMetrics:         false,
+		stakerservice.FeatureLeaderElection:  false,
+		stakerservice.FeatureApproval:        false,
+		stakerservice.FeatureSigningPolicy:   false,
+		stakerservice.FeatureThresholdSigner: false,
+		stakerservice.FeatureWebhook:         false,
+	}, result.Features)
+
+	result = version(t, newSimulatedService(t, func(cfg *stakercfg.Config) {
+		cfg.MetricsConfig.Enabled = true
+		cfg.ApprovalConfig.Enabled = true
+	}))
+	require.True(t, result.Features[stakerservice.FeatureMetrics])
+	require.True(t, result.Features[stakerservice.FeatureApproval])
+	require.False(t, result.Features[stakerservice.FeatureSigningPolicy])
+}
+
+func TestVersionExperimentalFeatures(t *testing.T) {
+	t.Parallel()
+
+	experimental := func(t *testing.T, s *simulatedService) map[string]bool {
+		resp := s.call(t, "version", url.Values{})
+		require.Nil(t, resp.Error)
+
+		var result stakerservice.VersionResponse
+		require.NoError(t, json.Unmarshal(resp.Result, &result))
+		return result.ExperimentalFeatures
+	}
+
+	require.Equal(t, map[string]bool{stakercfg.FeatureStakeExpansion: false}, experimental(t, newSimulatedService(t)))
+	require.Equal(t, map[string]bool{stakercfg.FeatureStakeExpansion: true}, experimental(t, newSimulatedService(t, func(cfg *stakercfg.Config) {
+		cfg.FeatureFlagsConfig.Enable = []string{stakercfg.FeatureStakeExpansion}
+	})))
+}
+
+func TestStakingDetailsBatch(t *testing.T) {
+	t.Parallel()
+	s := newSimulatedService(t)
+	first := s.activeDelegation(t, 100_000)
+	second := s.activeDelegation(t, 200_000)
+	unknown := chainhash.Hash{1}
+
+	batch := func(t *testing.T, hashes ...string) rpctypes.RPCResponse {
+		encoded, err := json.Marshal(hashes)
+		require.NoError(t, err)
+		return s.call(t, "staking_details_batch", url.Values{"stakingTxHashes": {string(encoded)}})
+	}
+
+	// one failing hash does not fail the whole batch, duplicates are returned
+	// once
+	resp := batch(t, second.String(), unknown.String(), first.String(), "not-a-hash", second.String())
+	require.Nil(t, resp.Error)
+	var result stakerservice.StakingDetailsBatchResponse
+	require.NoError(t, json.Unmarshal(resp.Result, &result))
+
+	require.Len(t, result.Details, 2)
+	require.Equal(t, second.String(), result.Details[0].StakingTxHash)
+	require.Equal(t, btcutil.Amount(200_000).String(), result.Details[0].StakingAmount)
+	require.Equal(t, first.String(), result.Details[1].StakingTxHash)
+	require.Equal(t, btcutil.Amount(100_000).String(), result.Details[1].StakingAmount)
+
+	require.Len(t, result.Failed, 2)
+	require.Equal(t, unknown.String(), result.Failed[0].StakingTxHash)
+	require.True(t, result.Failed[0].NotFound)
+	require.Equal(t, "not-a-hash", result.Failed[1].StakingTxHash)
+	require.False(t, result.Failed[1].NotFound)
+	require.NotEmpty(t, result.Failed[1].Error)
+
+	resp = batch(t)
+	require.NotNil(t, resp.Error)
+	require.Contains(t, resp.Error.Data, "at least one staking transaction hash is required")
+
+	tooMany := make([]string, 101)
+	for i := range tooMany {
+		tooMany[i] = first.String()
+	}
+	resp = batch(t, tooMany...)
+	require.NotNil(t, resp.Error)
+	require.Contains(t, resp.Error.Data, "at most 100 staking transaction hashes can be requested, got 101")
+}
@@ -0,0 +1,79 @@
+package stakerservice
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/sirupsen/logrus"
+)
+
+// shutdownStage orders shutdown steps. Steps of earlier stage are finished
+// before steps of later stage start, regardless of the order in which they
+// were registered.
+type shutdownStage int
+
+const (
+	// stop accepting rpc requests and wait for in-flight ones
+	stageRPCIntake shutdownStage = iota
+	// stop staker app, its background loops and btc and Babylon watchers
+	stageStaker
+	// close database, once nothing writes to it anymore
+	stageDB
+)
+
+func (s shutdownStage) String() string {
+	switch s {
+	case stageRPCIntake:
+		return "rpc_intake"
+	case stageStaker:
+		return "staker"
+	case stageDB:
+		return "db"
+	default:
+		return fmt.Sprintf("unknown(%d)", int(s))
+	}
+}
+
+type shutdownStep struct {
+	stage shutdownStage
+	run   func() error
+}
+
+// shutdownSequence stops service components in dependency order. Components
+// register their step once they are started, so that only started components
+// are stopped.
+type shutdownSequence struct {
+	logger *logrus.Logger
+	steps  []shutdownStep
+}
+
+func (q *shutdownSequence) add(stage shutdownStage, run func() error) {
+	q.steps = append(q.steps, shutdownStep{stage: stage, run: run})
+}
+
+// run runs registered steps ordered by stage. Failed step does not stop the
+// sequence, as later steps still have to release their resources.
+func (q *shutdownSequence) run() error {
+	sort.SliceStable(q.steps, func(i, j int) bool {
+		return q.steps[i].stage < q.steps[j].stage
+	})
+
+	var errs []error
+	for _, step := range q.steps {
+		q.logger.WithFields(logrus.Fields{
+			"stage": step.stage,
+		}).Info("Shutting down")
+
+		if err := step.run(); err != nil {
+			q.logger.WithFields(logrus.Fields{
+				"stage": step.stage,
+				"err":   err,
+			}).Error("Shutdown step failed")
+			errs = append(errs, fmt.Errorf("%s: %w", step.stage, err))
+		}
+	}
+	q.steps = nil
+
+	return errors.Join(errs...)
+}
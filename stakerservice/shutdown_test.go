@@ -0,0 +1,74 @@
+package stakerservice
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+)
+
+func TestShutdownSequenceOrder(t *testing.T) {
+	t.Parallel()
+
+	var stopped []string
+	step := func(name string, err error) func() error {
+		return func() error {
+			stopped = append(stopped, name)
+			return err
+		}
+	}
+
+	// steps are registered in order of start, as service does
+	shutdown := &shutdownSequence{logger: logrus.New()}
+	shutdown.add(stageDB, step("db", nil))
+	shutdown.add(stageStaker, step("staker", errors.New("notifier failed")))
+	shutdown.add(stageRPCIntake, step("grpc", nil))
+	shutdown.add(stageRPCIntake, step("rpc", nil))
+
+	// failed step does not prevent closing the database
+	err := shutdown.run()
+	require.Equal(t, []string{"grpc", "rpc", "staker", "db"}, stopped)
+	require.ErrorContains(t, err, "staker: notifier failed")
+
+	// steps run only once
+	require.NoError(t, shutdown.run())
+	require.Len(t, stopped, 4)
+}
+
+func TestRPCServerDrain(t *testing.T) {
+	t.Parallel()
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	r := &rpcServer{}
+	handler := r.track(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		close(started)
+		<-release
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	inflight := httptest.NewRecorder()
+	served := make(chan struct{})
+	go func() {
+		handler.ServeHTTP(inflight, httptest.NewRequest(http.MethodGet, "/", nil))
+		close(served)
+	}()
+	<-started
+
+	// drain waits for in-flight request
+	require.ErrorContains(t, r.drain(50*time.Millisecond), "in-flight rpc requests did not finish")
+
+	// new requests are rejected while draining
+	rejected := httptest.NewRecorder()
+	handler.ServeHTTP(rejected, httptest.NewRequest(http.MethodGet, "/", nil))
+	require.Equal(t, http.StatusServiceUnavailable, rejected.Code)
+
+	close(release)
+	require.NoError(t, r.drain(5*time.Second))
+	<-served
+	require.Equal(t, http.StatusOK, inflight.Code)
+}
@@ -0,0 +1,105 @@
+package stakerservice
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	str "github.com/babylonlabs-io/btc-staker/staker"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	rpctypes "github.com/cometbft/cometbft/rpc/jsonrpc/types"
+)
+
+// StakeStatusQueued is the status of stake request queued until babylon
+// accepts new delegations
+const StakeStatusQueued = "queued"
+
+// result returns tx hash of the stake or id of the queued stake request
+func (r *ResultStake) result() string {
+	if r.QueuedStakeID != "" {
+		return fmt.Sprintf("queued stake %s", r.QueuedStakeID)
+	}
+	return r.TxHash
+}
+
+// stakeOrQueue runs stakeFn. If babylon does not accept new delegations and
+// stake queue is enabled, or maintenance window is active, the request is
+// queued with queueFn instead.
+func (s *StakerService) stakeOrQueue(
+	stakeFn func() (*chainhash.Hash, error),
+	queueFn func() (uint64, error),
+) (*ResultStake, error) {
+	stakingTxHash, err := stakeFn()
+	if err == nil {
+		return &ResultStake{TxHash: stakingTxHash.String()}, nil
+	}
+
+	gateClosed := errors.Is(err, str.ErrStakingGateClosed) && s.staker.StakeQueueEnabled()
+	if !gateClosed && !errors.Is(err, str.ErrMaintenanceWindow) {
+		return nil, fmt.Errorf("error staking funds: %w", err)
+	}
+
+	id, err := queueFn()
+	if err != nil {
+		return nil, fmt.Errorf("error queuing stake: %w", err)
+	}
+
+	return &ResultStake{
+		QueuedStakeID: strconv.FormatUint(id, 10),
+		Status:        StakeStatusQueued,
+	}, nil
+}
+
+// queuedStakes returns whether babylon accepts new delegations together with
+// stake requests waiting for it
+func (s *StakerService) queuedStakes(_ *rpctypes.Context) (*QueuedStakesResponse, error) {
+	open, reason, err := s.staker.StakingGateStatus()
+	if err != nil {
+		return nil, err
+	}
+
+	stakes, err := s.staker.QueuedStakes()
+	if err != nil {
+		return nil, err
+	}
+
+	details := make([]QueuedStakeDetails, len(stakes))
+	for i, q := range stakes {
+		details[i] = QueuedStakeDetails{
+			ID:                strconv.FormatUint(q.ID, 10),
+			StakerAddress:     q.StakerAddress,
+			Amount:            q.Amount.String(),
+			FinalityProviders: q.FinalityProviders,
+			StakingTime:       q.StakingTime,
+			MaxFeeRate:        q.MaxFeeRate,
+			Tenant:            q.Tenant,
+			Labels:            q.Labels,
+			Reference:         q.Reference,
+			QueuedAt:          q.QueuedAt.UTC().Format(time.RFC3339),
+		}
+	}
+
+	return &QueuedStakesResponse{
+		StakingGateOpen:   open,
+		StakingGateReason: reason,
+		QueuedStakes:      details,
+	}, nil
+}
+
+// cancelQueuedStake removes stake request from the queue and returns remaining
+// queued requests
+func (s *StakerService) cancelQueuedStake(ctx *rpctypes.Context, id string) (*QueuedStakesResponse, error) {
+	queuedStakeID, err := strconv.ParseUint(id, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid queued stake id %s: %w", id, err)
+	}
+
+	if err := s.staker.CancelQueuedStake(queuedStakeID); err != nil {
+		return nil, fmt.Errorf("failed to cancel queued stake: %w", err)
+	}
+
+	s.logger.WithField("queuedStakeID", queuedStakeID).Info("Queued stake cancelled")
+
+	return s.queuedStakes(ctx)
+}
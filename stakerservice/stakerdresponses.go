@@ -1,24 +1,111 @@
 package stakerservice
 
 import (
+	"encoding/json"
+
 	"github.com/btcsuite/btcd/btcjson"
 )
 
 type ResultHealth struct{}
 
+type VersionResponse struct {
+	Version    string `json:"version"`
+	Commit     string `json:"commit,omitempty"`
+	APIVersion uint32 `json:"api_version"`
+	Network    string `json:"network"`
+	// Features maps optional feature name to whether it is enabled
+	Features map[string]bool `json:"features"`
+	// ExperimentalFeatures maps experimental feature name to whether it was
+	// enabled by feature flags
+	ExperimentalFeatures map[string]bool `json:"experimental_features"`
+}
+
+type DelegationFeesDetail struct {
+	StakingTxHash string `json:"staking_tx_hash"`
+	StakingFee    string `json:"staking_fee"`
+	UnbondingFee  string `json:"unbonding_fee"`
+	WithdrawalFee string `json:"withdrawal_fee"`
+	TotalFee      string `json:"total_fee"`
+}
+
+type StatsResponse struct {
+	TotalStakingFees    string                 `json:"total_staking_fees"`
+	TotalUnbondingFees  string                 `json:"total_unbonding_fees"`
+	TotalWithdrawalFees string                 `json:"total_withdrawal_fees"`
+	TotalFees           string                 `json:"total_fees"`
+	Delegations         []DelegationFeesDetail `json:"delegations"`
+	Tenant              string                 `json:"tenant,omitempty"`
+}
+
 type ResultBtcDelegationFromBtcStakingTx struct {
 	BabylonBTCDelegationTxHash string `json:"babylon_btc_delegation_tx_hash"`
 }
 
 type ResultStake struct {
 	TxHash string `json:"tx_hash"`
+	// set instead of tx hash when request waits for approval
+	OperationID string `json:"operation_id,omitempty"`
+	Status      string `json:"status,omitempty"`
+	// set instead of tx hash when request is queued until babylon accepts new
+	// delegations
+	QueuedStakeID string `json:"queued_stake_id,omitempty"`
 }
 
+// StakingDetails fields are omitted from the response when they were not
+// requested through the `fields` parameter of list endpoints
 type StakingDetails struct {
-	StakingTxHash  string `json:"staking_tx_hash"`
-	StakerAddress  string `json:"staker_address"`
-	StakingState   string `json:"staking_state"`
-	TransactionIdx string `json:"transaction_idx"`
+	StakingTxHash  string `json:"staking_tx_hash,omitempty"`
+	StakerAddress  string `json:"staker_address,omitempty"`
+	StakingState   string `json:"staking_state,omitempty"`
+	TransactionIdx string `json:"transaction_idx,omitempty"`
+	StakingAmount  string `json:"staking_amount,omitempty"`
+	Fee            string `json:"fee,omitempty"`
+	UnbondingFee   string `json:"unbonding_fee,omitempty"`
+	// btc height at which withdrawal of unbonded funds can be included in a
+	// block, empty until unbonding transaction is confirmed
+	SpendableHeight string `json:"spendable_height,omitempty"`
+	// unconfirmed transactions broadcast by staker, only returned by staking_details
+	MempoolTransactions []MempoolTxDetail `json:"mempool_transactions,omitempty"`
+	// labels of delegations created from templates, only returned by staking_details
+	Labels map[string]string `json:"labels,omitempty"`
+	// registration on babylon and quorum of covenant signatures, only
+	// returned by staking_details
+	RegisteredAt       string `json:"registered_at,omitempty"`
+	CovenantQuorumAt   string `json:"covenant_quorum_at,omitempty"`
+	CovenantQuorumSecs string `json:"covenant_quorum_secs,omitempty"`
+	// covenant committee members whose signatures of unbonding transaction
+	// were verified, only returned by staking_details
+	CovenantUnbondingSigners []string `json:"covenant_unbonding_signers,omitempty"`
+	// last failure or problem of the delegation with suggested remediation,
+	// only returned by staking_details
+	FailureNote *FailureNoteDetail `json:"failure_note,omitempty"`
+}
+
+type FailureNoteDetail struct {
+	// one of babylon, btc, staker
+	Source string `json:"source"`
+	// babylon error codespace and code, or rpc error code of btc node
+	Codespace   string `json:"codespace,omitempty"`
+	Code        string `json:"code,omitempty"`
+	Reason      string `json:"reason"`
+	Remediation string `json:"remediation,omitempty"`
+	RecordedAt  string `json:"recorded_at"`
+}
+
+type MempoolTxDetail struct {
+	TxHash string `json:"tx_hash"`
+	// one of staking, unbonding, withdrawal
+	Kind            string `json:"kind"`
+	BroadcastAt     string `json:"broadcast_at"`
+	SeenInMempoolAt string `json:"seen_in_mempool_at,omitempty"`
+	InMempool       bool   `json:"in_mempool"`
+	EvictedAt       string `json:"evicted_at,omitempty"`
+	Rebroadcasts    string `json:"rebroadcasts"`
+	// fee rates in sat/vB
+	FeeRate          string `json:"fee_rate,omitempty"`
+	EstimatedFeeRate string `json:"estimated_fee_rate"`
+	// competitive or below_estimate, empty if transaction is not in mempool
+	FeePosition string `json:"fee_position,omitempty"`
 }
 
 type OutputDetail struct {
@@ -29,9 +116,90 @@ type OutputDetail struct {
 type OutputsResponse struct {
 	Outputs []OutputDetail `json:"outputs"`
 }
+type ReservedOutpointDetail struct {
+	// outpoint in format <tx_hash>:<output_index>
+	Outpoint      string `json:"outpoint"`
+	StakingTxHash string `json:"staking_tx_hash"`
+}
+
+type ReservedOutpointsResponse struct {
+	Outpoints []ReservedOutpointDetail `json:"outpoints"`
+}
+
+type StakerAddressDetail struct {
+	Address     string `json:"address"`
+	AddressType string `json:"address_type"`
+	CreatedAt   string `json:"created_at,omitempty"`
+}
+
+type StakerAddressesResponse struct {
+	Addresses []StakerAddressDetail `json:"addresses"`
+}
+
+type UnlockWalletResponse struct {
+	// empty if passphrase is kept until restart
+	ExpiresAt string `json:"expires_at,omitempty"`
+}
+
+type SignMessageResponse struct {
+	Address string `json:"address"`
+	Message string `json:"message"`
+	// base64 encoded bip322 simple signature
+	Signature string `json:"signature"`
+}
+
+type VerifyMessageResponse struct {
+	Valid bool `json:"valid"`
+	// reason why signature is not valid
+	Error string `json:"error,omitempty"`
+}
+
+type SignSpendTxResponse struct {
+	StakingTxHash string `json:"staking_tx_hash"`
+	Kind          string `json:"kind"`
+	TxHash        string `json:"tx_hash"`
+	// hex encoded 64 byte bip340 schnorr signature
+	Signature string `json:"signature"`
+}
+
+type UnreserveOutpointResponse struct {
+	Outpoint      string `json:"outpoint"`
+	StakingTxHash string `json:"staking_tx_hash"`
+}
+
 type SpendTxDetails struct {
 	TxHash  string `json:"tx_hash"`
 	TxValue string `json:"tx_value"`
+	// set instead of tx hash when request waits for approval
+	OperationID string `json:"operation_id,omitempty"`
+	Status      string `json:"status,omitempty"`
+}
+
+type OperationDetails struct {
+	ID          string `json:"id"`
+	Kind        string `json:"kind"`
+	Requester   string `json:"requester"`
+	Amount      int64  `json:"amount"`
+	Description string `json:"description"`
+	CreatedAt   string `json:"created_at"`
+	ExpiresAt   string `json:"expires_at"`
+	Status      string `json:"status"`
+	ApprovedBy  string `json:"approved_by,omitempty"`
+	// hash of broadcast transaction, new staking transaction for restake
+	TxHash string `json:"tx_hash,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+type OperationsResponse struct {
+	Operations []OperationDetails `json:"operations"`
+}
+
+type RestakeResponse struct {
+	WithdrawalTxHash string `json:"withdrawal_tx_hash"`
+	StakingTxHash    string `json:"staking_tx_hash"`
+	// set instead of tx hashes when request waits for approval
+	OperationID string `json:"operation_id,omitempty"`
+	Status      string `json:"status,omitempty"`
 }
 
 type FinalityProviderInfoResponse struct {
@@ -51,6 +219,12 @@ type ListStakingTransactionsResponse struct {
 	TotalTransactionCount string           `json:"total_transaction_count"`
 }
 
+type CancelStakeResponse struct {
+	// empty if staking transaction was never broadcast and nothing had to be replaced
+	CancelTxHash string `json:"cancel_tx_hash,omitempty"`
+	Fee          string `json:"fee,omitempty"`
+}
+
 type UnbondingResponse struct {
 	UnbondingTxHash string `json:"unbonding_tx_hash"`
 }
@@ -74,3 +248,422 @@ type BtcStakingParams struct {
 	CovenantPkHex  []string
 	CovenantQuorum uint32
 }
+
+// ArchivedParams is a Babylon params version stored in params archive of the
+// daemon
+type ArchivedParams struct {
+	Version             uint32   `json:"version"`
+	BtcActivationHeight uint32   `json:"btc_activation_height"`
+	CovenantPkHex       []string `json:"covenant_pks"`
+	CovenantQuorum      uint32   `json:"covenant_quorum"`
+	UnbondingTime       uint16   `json:"unbonding_time"`
+	UnbondingFee        string   `json:"unbonding_fee"`
+	MinStakingTime      uint16   `json:"min_staking_time"`
+	MaxStakingTime      uint16   `json:"max_staking_time"`
+	MinStakingValue     string   `json:"min_staking_value"`
+	MaxStakingValue     string   `json:"max_staking_value"`
+}
+
+type ArchivedParamsResponse struct {
+	Params []ArchivedParams `json:"params"`
+}
+
+type StakingActivityBucket struct {
+	// period label i.e 2024-01 for month, 2024-W05 for week, 2024-01-31 for day
+	Period          string `json:"period"`
+	Delegations     string `json:"delegations"`
+	DelegatedAmount string `json:"delegated_amount"`
+	Withdrawals     string `json:"withdrawals"`
+	WithdrawnAmount string `json:"withdrawn_amount"`
+	FirstBtcHeight  string `json:"first_btc_height"`
+	LastBtcHeight   string `json:"last_btc_height"`
+}
+
+type StakingActivityResponse struct {
+	Period  string                  `json:"period"`
+	Buckets []StakingActivityBucket `json:"buckets"`
+}
+
+type DBChange struct {
+	Seq           uint64 `json:"seq"`
+	Kind          string `json:"kind"`
+	StakingTxHash string `json:"staking_tx_hash,omitempty"`
+	Detail        string `json:"detail,omitempty"`
+	Timestamp     string `json:"timestamp"`
+}
+
+type DBChangesResponse struct {
+	Changes []DBChange `json:"changes"`
+	// ResumeToken is passed to the next call to receive following changes
+	ResumeToken string `json:"resume_token"`
+}
+
+type ReplicaStatusResponse struct {
+	// rpc address of the primary
+	Primary string `json:"primary"`
+	// sequence number of the last change of the primary applied by the
+	// replica
+	AppliedSeq uint64 `json:"applied_seq"`
+	// number of delegations replicated since start
+	Replicated uint64 `json:"replicated"`
+	// empty until replica caught up with the primary
+	CaughtUpAt string `json:"caught_up_at,omitempty"`
+	LastError  string `json:"last_error,omitempty"`
+}
+
+type FinalityProviderQuotaDetails struct {
+	FpBtcPk string `json:"fp_btc_pk"`
+	// number and amount of delegations which are not failed, unbonded or
+	// expired
+	Delegations uint64 `json:"delegations"`
+	AmountSat   int64  `json:"amount_sat"`
+	// zero if not limited
+	MaxDelegations uint64 `json:"max_delegations"`
+	MaxAmountSat   int64  `json:"max_amount_sat"`
+}
+
+type FinalityProviderQuotasResponse struct {
+	FinalityProviders []FinalityProviderQuotaDetails `json:"finality_providers"`
+}
+
+type StakingDetailsFailure struct {
+	StakingTxHash string `json:"staking_tx_hash"`
+	// true if the staker does not track the transaction
+	NotFound bool   `json:"not_found"`
+	Error    string `json:"error"`
+}
+
+type StakingDetailsBatchResponse struct {
+	// details of requested transactions, in order of the request, duplicates
+	// are returned once
+	Details []StakingDetails        `json:"details"`
+	Failed  []StakingDetailsFailure `json:"failed,omitempty"`
+}
+
+type FindDelegationByTxResponse struct {
+	TxHash string `json:"tx_hash"`
+	// staking, unbonding or withdrawal
+	Kind           string         `json:"kind"`
+	StakingDetails StakingDetails `json:"staking_details"`
+}
+
+type QuarantinedRecordDetails struct {
+	TxIdx uint64 `json:"tx_idx"`
+	// empty if no index entry pointed to the record
+	StakingTxHash string `json:"staking_tx_hash,omitempty"`
+	RecordHex     string `json:"record_hex"`
+	ChecksumHex   string `json:"checksum_hex,omitempty"`
+	Reason        string `json:"reason"`
+	QuarantinedAt string `json:"quarantined_at"`
+}
+
+type QuarantinedRecordsResponse struct {
+	Records []QuarantinedRecordDetails `json:"records"`
+}
+
+type ReplayEventsResponse struct {
+	Replayed uint64 `json:"replayed"`
+	// id of the last replayed event, zero if none was replayed
+	LastEventID uint64 `json:"last_event_id"`
+}
+
+type DelegationTemplateDetails struct {
+	Name              string   `json:"name"`
+	Source            string   `json:"source"`
+	StakerAddress     string   `json:"staker_address,omitempty"`
+	FinalityProviders []string `json:"finality_providers"`
+	StakingTime       uint16   `json:"staking_time"`
+	// in sat/vB, omitted if fee rate is not capped
+	MaxFeeRate uint64            `json:"max_fee_rate,omitempty"`
+	Tenant     string            `json:"tenant,omitempty"`
+	Labels     map[string]string `json:"labels,omitempty"`
+}
+
+type DelegationTemplatesResponse struct {
+	Templates []DelegationTemplateDetails `json:"templates"`
+}
+
+type QueuedStakeDetails struct {
+	ID                string   `json:"id"`
+	StakerAddress     string   `json:"staker_address"`
+	Amount            string   `json:"amount"`
+	FinalityProviders []string `json:"finality_providers"`
+	StakingTime       uint16   `json:"staking_time"`
+	// in sat/vB, omitted if fee rate is not capped
+	MaxFeeRate uint64            `json:"max_fee_rate,omitempty"`
+	Tenant     string            `json:"tenant,omitempty"`
+	Labels     map[string]string `json:"labels,omitempty"`
+	Reference  string            `json:"reference,omitempty"`
+	QueuedAt   string            `json:"queued_at"`
+}
+
+type QueuedStakesResponse struct {
+	// whether babylon currently accepts new delegations
+	StakingGateOpen bool `json:"staking_gate_open"`
+	// reason why babylon does not accept new delegations
+	StakingGateReason string               `json:"staking_gate_reason,omitempty"`
+	QueuedStakes      []QueuedStakeDetails `json:"queued_stakes"`
+}
+
+type DelegationStatusSnapshot struct {
+	State string `json:"state"`
+	// delegation entered the state after entered_after (omitted for the first
+	// state) and at the latest at entered_by
+	EnteredAfter string `json:"entered_after,omitempty"`
+	EnteredBy    string `json:"entered_by"`
+	LastSeenAt   string `json:"last_seen_at"`
+	// btc best block height when the state was first observed
+	BtcHeight uint32 `json:"btc_height"`
+	// btc heights of the staking period reported by babylon
+	StartHeight uint32 `json:"start_height,omitempty"`
+	EndHeight   uint32 `json:"end_height,omitempty"`
+}
+
+type DelegationHistoryResponse struct {
+	StakingTxHash string                     `json:"staking_tx_hash"`
+	Statuses      []DelegationStatusSnapshot `json:"statuses"`
+}
+
+type ExportedBtcTx struct {
+	TxHash string `json:"tx_hash"`
+	// one of staking, unbonding, withdrawal
+	Kind            string `json:"kind"`
+	TxHex           string `json:"tx_hex"`
+	BroadcastAt     string `json:"broadcast_at,omitempty"`
+	SeenInMempoolAt string `json:"seen_in_mempool_at,omitempty"`
+	EvictedAt       string `json:"evicted_at,omitempty"`
+	Rebroadcasts    uint32 `json:"rebroadcasts"`
+}
+
+type ExportedConfirmation struct {
+	BlockHash   string `json:"block_hash"`
+	BlockHeight uint32 `json:"block_height"`
+	TxIndex     uint32 `json:"tx_index"`
+	// merkle nodes proving inclusion of staking transaction in the block
+	InclusionProofHex string `json:"inclusion_proof_hex"`
+}
+
+type ExportedBabylonTx struct {
+	Kind   string `json:"kind"`
+	TxHash string `json:"tx_hash"`
+	Height int64  `json:"height"`
+	SentAt string `json:"sent_at"`
+	// type, protobuf encoding and its sha256 of the message sent in the
+	// transaction, empty if it was not recorded
+	MsgTypeURL string `json:"msg_type_url,omitempty"`
+	MsgHex     string `json:"msg_hex,omitempty"`
+	MsgSha256  string `json:"msg_sha256,omitempty"`
+}
+
+type DelegationExportResponse struct {
+	StakingTxHash string            `json:"staking_tx_hash"`
+	StakerAddress string            `json:"staker_address"`
+	StakingTxHex  string            `json:"staking_tx_hex"`
+	Tenant        string            `json:"tenant,omitempty"`
+	Labels        map[string]string `json:"labels,omitempty"`
+	// btc height at which staker created the delegation, 0 if not known
+	CreatedHeight     uint32 `json:"created_height,omitempty"`
+	FailureReason     string `json:"failure_reason,omitempty"`
+	FailedAt          string `json:"failed_at,omitempty"`
+	StakingFee        string `json:"staking_fee"`
+	UnbondingFee      string `json:"unbonding_fee"`
+	WithdrawalFee     string `json:"withdrawal_fee"`
+	RegisteredAt      string `json:"registered_at,omitempty"`
+	CovenantQuorumAt  string `json:"covenant_quorum_at,omitempty"`
+	UnbondRequestedAt string `json:"unbond_requested_at,omitempty"`
+	// btc transactions broadcast by staker for the delegation
+	BtcTransactions []ExportedBtcTx `json:"btc_transactions"`
+	// omitted if staking transaction is not confirmed
+	Confirmation      *ExportedConfirmation `json:"confirmation,omitempty"`
+	ConfirmationError string                `json:"confirmation_error,omitempty"`
+	BabylonTxs        []ExportedBabylonTx   `json:"babylon_txs"`
+	// delegation as returned by babylon, including covenant signatures
+	BabylonDelegation json.RawMessage            `json:"babylon_delegation,omitempty"`
+	BabylonError      string                     `json:"babylon_error,omitempty"`
+	Statuses          []DelegationStatusSnapshot `json:"statuses"`
+	// changes of the delegation recorded in staker database
+	Events     []DBChange `json:"events"`
+	ExportedAt string     `json:"exported_at"`
+}
+
+type DelegationImportResponse struct {
+	StakingTxHash string `json:"staking_tx_hash"`
+	// babylon state of the imported delegation
+	StakingState string `json:"staking_state"`
+}
+
+type StuckDelegationDetail struct {
+	StakingTxHash string `json:"staking_tx_hash"`
+	// babylon state of the delegation
+	State     string `json:"state"`
+	Condition string `json:"condition"`
+	Reason    string `json:"reason"`
+	// time delegation entered the state, omitted if not known
+	Since      string `json:"since,omitempty"`
+	DetectedAt string `json:"detected_at"`
+	// number of automatic remediation attempts and error of the last one
+	RemediationAttempts uint32 `json:"remediation_attempts,omitempty"`
+	RemediationError    string `json:"remediation_error,omitempty"`
+}
+
+type StuckDelegationsResponse struct {
+	Delegations []StuckDelegationDetail `json:"delegations"`
+}
+
+type ExpiringDelegationDetail struct {
+	StakingTxHash string `json:"staking_tx_hash"`
+	// babylon state of the delegation
+	State string `json:"state"`
+	// btc height of the first block which can include withdrawal
+	ExpiryHeight uint32 `json:"expiry_height"`
+	BlocksLeft   uint32 `json:"blocks_left"`
+	AutoRenew    bool   `json:"auto_renew"`
+	// staking time of the renewed delegation, omitted if auto renew is disabled
+	RenewStakingTime uint16 `json:"renew_staking_time,omitempty"`
+}
+
+type ExpiringDelegationsResponse struct {
+	Delegations []ExpiringDelegationDetail `json:"delegations"`
+}
+
+type RenewalJobDetail struct {
+	StakingTxHash string `json:"staking_tx_hash"`
+	// staking time of the renewed delegation, zero if it is kept
+	StakingTime      uint16 `json:"staking_time,omitempty"`
+	State            string `json:"state"`
+	Attempts         uint32 `json:"attempts"`
+	WithdrawalTxHash string `json:"withdrawal_tx_hash,omitempty"`
+	NewStakingTxHash string `json:"new_staking_tx_hash,omitempty"`
+	Error            string `json:"error,omitempty"`
+	CreatedAt        string `json:"created_at"`
+	UpdatedAt        string `json:"updated_at"`
+}
+
+type RenewalJobsResponse struct {
+	Jobs []RenewalJobDetail `json:"jobs"`
+}
+
+type SetAutoRenewResponse struct {
+	Enabled bool `json:"enabled"`
+	// zero if staking time of the delegation is kept
+	StakingTimeBlocks uint16 `json:"staking_time_blocks,omitempty"`
+	// set instead of the result when request waits for approval
+	OperationID string `json:"operation_id,omitempty"`
+	Status      string `json:"status,omitempty"`
+}
+
+type RenotifyCovenantResponse struct {
+	StakingTxHash string `json:"staking_tx_hash"`
+	// babylon status of the delegation, empty if it was registered again
+	State              string `json:"state,omitempty"`
+	CovenantSignatures int    `json:"covenant_signatures"`
+	CovenantQuorum     uint32 `json:"covenant_quorum"`
+	// one of resubmitted, watch_restarted, activation_restarted, none
+	Action string `json:"action"`
+	// babylon transaction registering the delegation again
+	BabylonTxHash string `json:"babylon_tx_hash,omitempty"`
+}
+
+type ActivationPhaseDetail struct {
+	// one of broadcast, confirmed, registered, covenant_quorum, active
+	Phase string `json:"phase"`
+	At    string `json:"at"`
+}
+
+type ActivationTransitionDetail struct {
+	// e.g. registered_to_covenant_quorum
+	Transition string `json:"transition"`
+	Secs       string `json:"secs"`
+}
+
+type DelegationActivationLatency struct {
+	StakingTxHash string `json:"staking_tx_hash"`
+	// observed phases in chronological order
+	Phases      []ActivationPhaseDetail      `json:"phases"`
+	Transitions []ActivationTransitionDetail `json:"transitions"`
+	// time between the first observed phase and activation, omitted if
+	// delegation is not active yet
+	TotalSecs string `json:"total_secs,omitempty"`
+}
+
+type ActivationLatencyStatsDetail struct {
+	// transition name, or total for the whole activation
+	Name     string `json:"name"`
+	Count    int    `json:"count"`
+	MeanSecs string `json:"mean_secs"`
+	P50Secs  string `json:"p50_secs"`
+	P90Secs  string `json:"p90_secs"`
+	MaxSecs  string `json:"max_secs"`
+}
+
+type ActivationLatencyResponse struct {
+	// set if latency of single delegation was requested, other fields are
+	// set otherwise
+	Delegation        *DelegationActivationLatency   `json:"delegation,omitempty"`
+	ActiveDelegations int                            `json:"active_delegations,omitempty"`
+	Total             *ActivationLatencyStatsDetail  `json:"total,omitempty"`
+	Transitions       []ActivationLatencyStatsDetail `json:"transitions,omitempty"`
+}
+
+type ChainSafetyResponse struct {
+	// params sensitive operations are paused if any of the checks failed
+	Paused  bool     `json:"paused"`
+	Reasons []string `json:"reasons,omitempty"`
+	// operator allowed params sensitive operations while paused
+	Override               bool   `json:"override"`
+	BabylonHeight          uint64 `json:"babylon_height"`
+	BabylonHeightChangedAt string `json:"babylon_height_changed_at,omitempty"`
+	BtcHeight              uint32 `json:"btc_height"`
+	BtcLightClientHeight   uint32 `json:"btc_light_client_height"`
+	CheckedAt              string `json:"checked_at,omitempty"`
+}
+
+type MaintenanceStatusResponse struct {
+	// new stakes are queued and non urgent broadcasts and Babylon submissions
+	// are deferred while maintenance window is active
+	Active bool   `json:"active"`
+	EndsAt string `json:"ends_at,omitempty"`
+	// empty if no window starts again
+	NextStart string   `json:"next_start,omitempty"`
+	Windows   []string `json:"windows"`
+}
+
+type MuSig2KeyDetail struct {
+	Name string `json:"name"`
+	// hex encoded compressed public keys of signers in sorted order
+	PubKeys []string `json:"pub_keys"`
+	// hex encoded x-only key used as staker key of delegations
+	AggregateKey string `json:"aggregate_key"`
+	CreatedAt    string `json:"created_at"`
+}
+
+type MuSig2KeysResponse struct {
+	Keys []MuSig2KeyDetail `json:"keys"`
+}
+
+type MuSig2SessionDetail struct {
+	ID            uint64 `json:"id"`
+	KeyName       string `json:"key_name"`
+	Kind          string `json:"kind"`
+	StakingTxHash string `json:"staking_tx_hash,omitempty"`
+	// hex encoded transaction whose sighash is signed
+	TxHex string `json:"tx_hex,omitempty"`
+	// hex encoded 32 byte message signers must sign
+	Message string `json:"message"`
+	// signer public key -> public nonce
+	Nonces map[string]string `json:"nonces"`
+	// set once all signers submitted nonces
+	AggregateNonce string `json:"aggregate_nonce,omitempty"`
+	// signer public key -> partial signature
+	PartialSigs map[string]string `json:"partial_sigs"`
+	// schnorr signature of the aggregate key, set once session is completed
+	Signature       string `json:"signature,omitempty"`
+	BroadcastTxHash string `json:"broadcast_tx_hash,omitempty"`
+	Error           string `json:"error,omitempty"`
+	CreatedAt       string `json:"created_at"`
+	CompletedAt     string `json:"completed_at,omitempty"`
+}
+
+type MuSig2SessionsResponse struct {
+	Sessions []MuSig2SessionDetail `json:"sessions"`
+}
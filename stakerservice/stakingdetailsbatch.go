@@ -0,0 +1,62 @@
+package stakerservice
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/babylonlabs-io/btc-staker/stakerdb"
+	rpctypes "github.com/cometbft/cometbft/rpc/jsonrpc/types"
+)
+
+// maxStakingDetailsBatch is the maximum number of staking transactions whose
+// details are returned in one call
+const maxStakingDetailsBatch = 100
+
+// stakingDetailsBatch returns details of multiple staking transactions.
+// Transactions whose details can't be returned are reported as failed, so
+// that one unknown hash does not fail the whole batch. If tenant is given,
+// transactions of other tenants are reported as failed.
+func (s *StakerService) stakingDetailsBatch(
+	ctx *rpctypes.Context,
+	stakingTxHashes []string,
+	tenant *string,
+) (*StakingDetailsBatchResponse, error) {
+	if len(stakingTxHashes) == 0 {
+		return nil, fmt.Errorf("at least one staking transaction hash is required")
+	}
+
+	if len(stakingTxHashes) > maxStakingDetailsBatch {
+		return nil, fmt.Errorf("at most %d staking transaction hashes can be requested, got %d",
+			maxStakingDetailsBatch, len(stakingTxHashes))
+	}
+
+	if _, err := getTenant(tenant); err != nil {
+		return nil, err
+	}
+
+	resp := &StakingDetailsBatchResponse{
+		Details: make([]StakingDetails, 0, len(stakingTxHashes)),
+	}
+
+	seen := make(map[string]struct{}, len(stakingTxHashes))
+	for _, hash := range stakingTxHashes {
+		if _, ok := seen[hash]; ok {
+			continue
+		}
+		seen[hash] = struct{}{}
+
+		details, err := s.stakingDetails(ctx, hash, tenant)
+		if err != nil {
+			resp.Failed = append(resp.Failed, StakingDetailsFailure{
+				StakingTxHash: hash,
+				NotFound:      errors.Is(err, stakerdb.ErrTransactionNotFound),
+				Error:         err.Error(),
+			})
+			continue
+		}
+
+		resp.Details = append(resp.Details, *details)
+	}
+
+	return resp, nil
+}
@@ -0,0 +1,51 @@
+package stakerservice
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/babylonlabs-io/btc-staker/stakerdb"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	rpctypes "github.com/cometbft/cometbft/rpc/jsonrpc/types"
+)
+
+// delegationHistory returns persisted Babylon statuses of the delegation
+// together with time ranges in which they were entered
+func (s *StakerService) delegationHistory(_ *rpctypes.Context, stakingTxHash string) (*DelegationHistoryResponse, error) {
+	txHash, err := chainhash.NewHashFromStr(stakingTxHash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse string type of hash to chainhash.Hash: %w", err)
+	}
+
+	// fail on unknown transaction instead of returning empty history
+	if _, err := s.staker.GetStoredTransaction(txHash); err != nil {
+		return nil, fmt.Errorf("failed to get stored transaction from hash %s: %w", stakingTxHash, err)
+	}
+
+	history, err := s.staker.DelegationStatusHistory(txHash)
+	if err != nil {
+		return nil, err
+	}
+
+	return &DelegationHistoryResponse{
+		StakingTxHash: txHash.String(),
+		Statuses:      toStatusSnapshots(history),
+	}, nil
+}
+
+func toStatusSnapshots(history []stakerdb.StatusSnapshot) []DelegationStatusSnapshot {
+	statuses := make([]DelegationStatusSnapshot, len(history))
+	for i, h := range history {
+		statuses[i] = DelegationStatusSnapshot{
+			State:        h.State,
+			EnteredAfter: formatOptionalTime(h.PreviousSeenAt),
+			EnteredBy:    h.FirstSeenAt.UTC().Format(time.RFC3339),
+			LastSeenAt:   h.LastSeenAt.UTC().Format(time.RFC3339),
+			BtcHeight:    h.BtcHeight,
+			StartHeight:  h.StartHeight,
+			EndHeight:    h.EndHeight,
+		}
+	}
+
+	return statuses
+}
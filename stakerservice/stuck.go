@@ -0,0 +1,29 @@
+package stakerservice
+
+import (
+	"time"
+
+	rpctypes "github.com/cometbft/cometbft/rpc/jsonrpc/types"
+)
+
+// listStuckDelegations returns delegations stuck in intermediate states for
+// longer than configured thresholds, as detected by the last check
+func (s *StakerService) listStuckDelegations(_ *rpctypes.Context) (*StuckDelegationsResponse, error) {
+	stuck := s.staker.StuckDelegations()
+
+	details := make([]StuckDelegationDetail, len(stuck))
+	for i, d := range stuck {
+		details[i] = StuckDelegationDetail{
+			StakingTxHash:       d.StakingTxHash.String(),
+			State:               d.State,
+			Condition:           d.Condition,
+			Reason:              d.Reason,
+			Since:               formatOptionalTime(d.Since),
+			DetectedAt:          d.DetectedAt.UTC().Format(time.RFC3339),
+			RemediationAttempts: d.RemediationAttempts,
+			RemediationError:    d.RemediationError,
+		}
+	}
+
+	return &StuckDelegationsResponse{Delegations: details}, nil
+}
@@ -0,0 +1,181 @@
+package stakerservice
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	str "github.com/babylonlabs-io/btc-staker/staker"
+	scfg "github.com/babylonlabs-io/btc-staker/stakercfg"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	rpctypes "github.com/cometbft/cometbft/rpc/jsonrpc/types"
+)
+
+// stakeFromTemplate stakes given amount using parameters of the template.
+// Parameters defined by the template cannot be overridden by the request.
+func (s *StakerService) stakeFromTemplate(
+	ctx *rpctypes.Context,
+	template string,
+	stakerAddress string,
+	stakingAmount int64,
+	fpBtcPks []string,
+	stakingTimeBlocks int64,
+	tenant *string,
+	reference string,
+	fee str.FeeSelection,
+) (*ResultStake, error) {
+	if len(fpBtcPks) > 0 || stakingTimeBlocks != 0 || tenant != nil {
+		return nil, errors.New("finality providers, staking time and tenant are defined by the template")
+	}
+
+	if stakingAmount <= 0 {
+		return nil, fmt.Errorf("staking amount must be positive")
+	}
+	amount := btcutil.Amount(stakingAmount)
+
+	var stakerAddr btcutil.Address
+	if stakerAddress != "" {
+		var err error
+		stakerAddr, err = btcutil.DecodeAddress(stakerAddress, &s.config.ActiveNetParams)
+		if err != nil {
+			return nil, fmt.Errorf("error decoding staker address: %w", err)
+		}
+	}
+
+	// fail early on unknown template instead of after approval
+	if _, err := s.staker.Template(template); err != nil {
+		return nil, err
+	}
+
+	stakeFn := func() (*chainhash.Hash, error) {
+		return s.staker.StakeFromTemplate(template, stakerAddr, amount, reference, fee)
+	}
+	queueFn := func() (uint64, error) {
+		return s.staker.QueueStakeFromTemplate(template, stakerAddr, amount, reference, fee)
+	}
+
+	if s.approvals.requiresApproval(amount) {
+		op, err := s.approvals.submit(
+			OperationStake,
+			principal(ctx),
+			amount,
+			fmt.Sprintf("stake from template %s", template),
+			func() (string, error) {
+				res, err := s.stakeOrQueue(stakeFn, queueFn)
+				if err != nil {
+					return "", err
+				}
+				return res.result(), nil
+			},
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		return &ResultStake{OperationID: op.ID, Status: op.Status}, nil
+	}
+
+	return s.stakeOrQueue(stakeFn, queueFn)
+}
+
+func templateDetails(t *str.DelegationTemplate) DelegationTemplateDetails {
+	return DelegationTemplateDetails{
+		Name:              t.Name,
+		Source:            t.Source,
+		StakerAddress:     t.StakerAddress,
+		FinalityProviders: t.FinalityProviders,
+		StakingTime:       t.StakingTime,
+		MaxFeeRate:        t.MaxFeeRate,
+		Tenant:            t.Tenant,
+		Labels:            t.Labels,
+	}
+}
+
+// delegationTemplates returns templates defined in config and created through the API
+func (s *StakerService) delegationTemplates(_ *rpctypes.Context) (*DelegationTemplatesResponse, error) {
+	templates, err := s.staker.ListTemplates()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list templates: %w", err)
+	}
+
+	details := make([]DelegationTemplateDetails, len(templates))
+	for i := range templates {
+		details[i] = templateDetails(&templates[i])
+	}
+
+	return &DelegationTemplatesResponse{Templates: details}, nil
+}
+
+// setDelegationTemplate creates or replaces template. Labels are in format key:value.
+func (s *StakerService) setDelegationTemplate(
+	_ *rpctypes.Context,
+	name string,
+	stakerAddress *string,
+	fpBtcPks []string,
+	stakingTimeBlocks int64,
+	maxFeeRate *int64,
+	tenant *string,
+	labels []string,
+) (*DelegationTemplateDetails, error) {
+	stakingTime, err := parseStakingTime(stakingTimeBlocks)
+	if err != nil {
+		return nil, err
+	}
+
+	template := &scfg.DelegationTemplate{
+		Name:              name,
+		FinalityProviders: fpBtcPks,
+		StakingTime:       stakingTime,
+	}
+
+	if stakerAddress != nil {
+		template.StakerAddress = *stakerAddress
+	}
+
+	if maxFeeRate != nil {
+		if *maxFeeRate < 0 {
+			return nil, fmt.Errorf("max fee rate must not be negative")
+		}
+		template.MaxFeeRate = uint64(*maxFeeRate)
+	}
+
+	if tenant != nil {
+		template.Tenant = *tenant
+	}
+
+	for _, l := range labels {
+		key, value, ok := strings.Cut(l, ":")
+		if !ok {
+			return nil, fmt.Errorf("label must be in format key:value, got %s", l)
+		}
+		if template.Labels == nil {
+			template.Labels = make(map[string]string)
+		}
+		template.Labels[key] = value
+	}
+
+	if err := s.staker.SetTemplate(template); err != nil {
+		return nil, fmt.Errorf("failed to set template: %w", err)
+	}
+
+	s.logger.WithField("template", name).Info("Delegation template set")
+
+	details := templateDetails(&str.DelegationTemplate{
+		DelegationTemplate: *template,
+		Source:             str.TemplateSourceAPI,
+	})
+	return &details, nil
+}
+
+// deleteDelegationTemplate deletes template created through the API and
+// returns remaining templates
+func (s *StakerService) deleteDelegationTemplate(ctx *rpctypes.Context, name string) (*DelegationTemplatesResponse, error) {
+	if err := s.staker.DeleteTemplate(name); err != nil {
+		return nil, fmt.Errorf("failed to delete template: %w", err)
+	}
+
+	s.logger.WithField("template", name).Info("Delegation template deleted")
+
+	return s.delegationTemplates(ctx)
+}
@@ -0,0 +1,60 @@
+package stakerservice
+
+import (
+	scfg "github.com/babylonlabs-io/btc-staker/stakercfg"
+	"github.com/babylonlabs-io/btc-staker/version"
+	rpctypes "github.com/cometbft/cometbft/rpc/jsonrpc/types"
+)
+
+// Optional features reported by the version endpoint
+const (
+	FeatureWebsockets      = "websockets"
+	FeatureGrpc            = "grpc"
+	FeatureMetrics         = "metrics"
+	FeatureLeaderElection  = "leader_election"
+	FeatureApproval        = "approval"
+	FeatureSigningPolicy   = "signing_policy"
+	FeatureThresholdSigner = "threshold_signer"
+	FeatureWebhook         = "webhook"
+)
+
+// version returns version of the daemon, its API and enabled optional
+// features, so that clients can detect what is supported
+func (s *StakerService) version(_ *rpctypes.Context) (*VersionResponse, error) {
+	return &VersionResponse{
+		Version:    version.Version(),
+		Commit:     version.Commit(),
+		APIVersion: version.APIVersion,
+		Network:    s.config.ActiveNetParams.Name,
+		Features:   s.features(),
+		// experimental features may change between releases, so they are
+		// reported separately
+		ExperimentalFeatures: s.experimentalFeatures(),
+	}, nil
+}
+
+// features returns whether each optional feature is enabled in the daemon.
+// Websocket and grpc endpoints are not served by this daemon.
+func (s *StakerService) features() map[string]bool {
+	return map[string]bool{
+		FeatureWebsockets:      false,
+		FeatureGrpc:            false,
+		FeatureMetrics:         s.config.MetricsConfig != nil && s.config.MetricsConfig.Enabled,
+		FeatureLeaderElection:  s.config.ClusterConfig != nil && s.config.ClusterConfig.EnableLeaderElection,
+		FeatureApproval:        s.config.ApprovalConfig != nil && s.config.ApprovalConfig.Enabled,
+		FeatureSigningPolicy:   s.config.SigningPolicyConfig != nil && s.config.SigningPolicyConfig.Enabled,
+		FeatureThresholdSigner: s.config.ThresholdSignerConfig != nil && s.config.ThresholdSignerConfig.URL != "",
+		FeatureWebhook:         s.config.WebhookConfig != nil && s.config.WebhookConfig.Enabled(),
+	}
+}
+
+// experimentalFeatures returns whether each experimental feature is enabled by
+// feature flags
+func (s *StakerService) experimentalFeatures() map[string]bool {
+	features := make(map[string]bool, len(scfg.ExperimentalFeatures))
+	for _, f := range scfg.ExperimentalFeatures {
+		features[f] = s.config.FeatureFlagsConfig.Enabled(f)
+	}
+
+	return features
+}
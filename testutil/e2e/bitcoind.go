@@ -1,4 +1,4 @@
-package e2etest
+package e2e
 
 import (
 	"encoding/json"
@@ -11,7 +11,7 @@ import (
 
 	"github.com/ory/dockertest/v3"
 
-	"github.com/babylonlabs-io/btc-staker/itest/containers"
+	"github.com/babylonlabs-io/btc-staker/testutil/e2e/containers"
 	"github.com/stretchr/testify/require"
 )
 
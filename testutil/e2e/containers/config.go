@@ -3,7 +3,7 @@ package containers
 import (
 	"testing"
 
-	"github.com/babylonlabs-io/btc-staker/itest/testutil"
+	"github.com/babylonlabs-io/btc-staker/testutil"
 	"github.com/test-go/testify/require"
 )
 
@@ -12,14 +12,19 @@ import (
 type ImageConfig struct {
 	BitcoindRepository string
 	BitcoindVersion    string
-	BabylonRepository  string
-	BabylonVersion     string
+	// BitcoindChain is the chain bitcoind runs on {regtest, signet, testnet4}.
+	// Empty value means regtest. Only regtest allows generating blocks on demand,
+	// testnet4 requires bitcoind v28 or later.
+	BitcoindChain     string
+	BabylonRepository string
+	BabylonVersion    string
 }
 
 //nolint:deadcode
 const (
 	dockerBitcoindRepository = "lncm/bitcoind"
 	dockerBitcoindVersionTag = "v26.0"
+	dockerBitcoindChain      = "regtest"
 	dockerBabylondRepository = "babylonlabs/babylond"
 )
 
@@ -31,6 +36,7 @@ func NewImageConfig(t *testing.T) ImageConfig {
 	return ImageConfig{
 		BitcoindRepository: dockerBitcoindRepository,
 		BitcoindVersion:    dockerBitcoindVersionTag,
+		BitcoindChain:      dockerBitcoindChain,
 		BabylonRepository:  dockerBabylondRepository,
 		BabylonVersion:     babylondVersion,
 	}
@@ -11,8 +11,10 @@ import (
 	"time"
 
 	bbn "github.com/babylonlabs-io/babylon/v4/types"
-	"github.com/babylonlabs-io/btc-staker/itest/testutil"
+	"github.com/babylonlabs-io/btc-staker/testutil"
+	"github.com/babylonlabs-io/btc-staker/utils"
 	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/chaincfg"
 	cmtjson "github.com/cometbft/cometbft/libs/json"
 	coretypes "github.com/cometbft/cometbft/rpc/core/types"
 
@@ -44,8 +46,15 @@ type Manager struct {
 // NewManager creates a new Manager instance and initializes
 // all Docker specific utilities. Returns an error if initialization fails.
 func NewManager(t *testing.T) (docker *Manager, err error) {
+	return NewManagerWithConfig(NewImageConfig(t))
+}
+
+// NewManagerWithConfig creates a new Manager instance running given images.
+// It allows projects which do not share go.mod with btc-staker to pin
+// bitcoind and babylond versions.
+func NewManagerWithConfig(cfg ImageConfig) (docker *Manager, err error) {
 	docker = &Manager{
-		cfg:       NewImageConfig(t),
+		cfg:       cfg,
 		resources: make(map[string]*dockertest.Resource),
 	}
 	docker.pool, err = dockertest.NewPool("")
@@ -55,9 +64,33 @@ func NewManager(t *testing.T) (docker *Manager, err error) {
 	return docker, nil
 }
 
+// BitcoindChain returns the chain bitcoind container runs on
+func (m *Manager) BitcoindChain() string {
+	if m.cfg.BitcoindChain == "" {
+		return dockerBitcoindChain
+	}
+	return m.cfg.BitcoindChain
+}
+
+// BitcoindChainParams returns network parameters of the chain bitcoind
+// container runs on
+func (m *Manager) BitcoindChainParams() (*chaincfg.Params, error) {
+	return utils.GetBtcNetworkParams(m.BitcoindChain())
+}
+
+// BitcoindRPCPort returns container port on which bitcoind listens for rpc
+// connections, in format accepted by dockertest.Resource.GetPort
+func (m *Manager) BitcoindRPCPort() (string, error) {
+	params, err := m.BitcoindChainParams()
+	if err != nil {
+		return "", err
+	}
+	return utils.BitcoindRPCPort(params) + "/tcp", nil
+}
+
 func (m *Manager) ExecBitcoindCliCmd(t *testing.T, command []string) (bytes.Buffer, bytes.Buffer, error) {
-	// this is currently hardcoded, as it will be the same for all tests
-	cmd := []string{"bitcoin-cli", "-chain=regtest", "-rpcuser=user", "-rpcpassword=pass"}
+	// credentials are currently hardcoded, as they will be the same for all tests
+	cmd := []string{"bitcoin-cli", "-chain=" + m.BitcoindChain(), "-rpcuser=user", "-rpcpassword=pass"}
 	cmd = append(cmd, command...)
 	return m.ExecCmd(t, bitcoindContainerName, cmd)
 }
@@ -139,6 +172,11 @@ func (m *Manager) RunBitcoindResource(
 	t *testing.T,
 	bitcoindCfgPath string,
 ) (*dockertest.Resource, error) {
+	rpcPort, err := m.BitcoindRPCPort()
+	if err != nil {
+		return nil, err
+	}
+
 	bitcoindResource, err := m.pool.RunWithOptions(
 		&dockertest.RunOptions{
 			Name:       fmt.Sprintf("%s-%s", bitcoindContainerName, t.Name()),
@@ -152,10 +190,10 @@ func (m *Manager) RunBitcoindResource(
 				"e2e": "bitcoind",
 			},
 			ExposedPorts: []string{
-				"18443/tcp",
+				rpcPort,
 			},
 			Cmd: []string{
-				"-regtest",
+				"-chain=" + m.BitcoindChain(),
 				"-txindex",
 				"-rpcuser=user",
 				"-rpcpassword=pass",
@@ -165,7 +203,7 @@ func (m *Manager) RunBitcoindResource(
 		},
 		func(config *docker.HostConfig) {
 			config.PortBindings = map[docker.Port][]docker.PortBinding{
-				"18443/tcp": {{HostIP: "", HostPort: strconv.Itoa(testutil.AllocateUniquePort(t))}}, // only expose what we need
+				docker.Port(rpcPort): {{HostIP: "", HostPort: strconv.Itoa(testutil.AllocateUniquePort(t))}}, // only expose what we need
 			}
 			config.PublishAllPorts = false // because in dockerfile they already expose them
 		},
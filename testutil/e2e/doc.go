@@ -0,0 +1,14 @@
+// Package e2e provides harness for end-to-end tests of btc-staker. It starts
+// bitcoind and babylond in docker containers, funds the test wallet and runs
+// staker app with its rpc service, so that projects integrating btc-staker can
+// exercise the full staking flow in their own integration tests.
+//
+// Typical usage:
+//
+//	tm := e2e.StartManager(t, ctx, 200)
+//	defer tm.Stop(t, cancel)
+//	tm.InsertAllMinedBlocksToBabylon(t)
+//
+// Projects with their own go.mod should use StartManagerWithImageConfig to pin
+// bitcoind and babylond images.
+package e2e
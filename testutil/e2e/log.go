@@ -1,7 +1,4 @@
-//go:build e2e
-// +build e2e
-
-package e2etest
+package e2e
 
 import (
 	"github.com/babylonlabs-io/btc-staker/stakercfg"
@@ -1,7 +1,4 @@
-//go:build e2e
-// +build e2e
-
-package e2etest
+package e2e
 
 import (
 	"bytes"
@@ -21,8 +18,8 @@ import (
 
 	btcctypes "github.com/babylonlabs-io/babylon/v4/x/btccheckpoint/types"
 	"github.com/babylonlabs-io/btc-staker/cmd/stakercli/daemon"
-	"github.com/babylonlabs-io/btc-staker/itest/containers"
-	"github.com/babylonlabs-io/btc-staker/itest/testutil"
+	"github.com/babylonlabs-io/btc-staker/testutil"
+	"github.com/babylonlabs-io/btc-staker/testutil/e2e/containers"
 	"github.com/ory/dockertest/v3"
 
 	staking "github.com/babylonlabs-io/babylon/v4/btcstaking"
@@ -38,6 +35,7 @@ import (
 	service "github.com/babylonlabs-io/btc-staker/stakerservice"
 	dc "github.com/babylonlabs-io/btc-staker/stakerservice/client"
 	"github.com/babylonlabs-io/btc-staker/types"
+	"github.com/babylonlabs-io/btc-staker/utils"
 	"github.com/babylonlabs-io/btc-staker/walletcontroller"
 	"github.com/btcsuite/btcd/btcec/v2"
 	"github.com/btcsuite/btcd/btcec/v2/schnorr"
@@ -62,11 +60,11 @@ import (
 var (
 	r = rand.New(rand.NewSource(time.Now().Unix()))
 
-	regtestParams = &chaincfg.RegressionNetParams
+	RegtestParams = &chaincfg.RegressionNetParams
 
-	eventuallyWaitTimeOut = 10 * time.Second
-	eventuallyPollTime    = 250 * time.Millisecond
-	eventuallyTimeout     = 5 * time.Minute
+	EventuallyWaitTimeOut = 10 * time.Second
+	EventuallyPollTime    = 250 * time.Millisecond
+	EventuallyTimeout     = 5 * time.Minute
 
 	bitcoindUser = "user"
 	bitcoindPass = "pass"
@@ -85,20 +83,21 @@ func keyToAddr(key *btcec.PrivateKey, net *chaincfg.Params) (btcutil.Address, er
 	return pubKeyAddr.AddressPubKeyHash(), nil
 }
 
-func defaultStakerConfigAndBtc(t *testing.T, walletName, passphrase, bitcoindHost string) (*stakercfg.Config, *rpcclient.Client) {
-	return defaultStakerConfig(t, walletName, passphrase, bitcoindHost), btcRpcTestClient(t, bitcoindHost)
+func DefaultStakerConfigAndBtc(t *testing.T, walletName, passphrase, bitcoindHost string) (*stakercfg.Config, *rpcclient.Client) {
+	return DefaultStakerConfig(t, walletName, passphrase, bitcoindHost), BtcRpcTestClient(t, bitcoindHost)
 }
 
-func defaultStakerConfig(t *testing.T, walletName, passphrase, bitcoindHost string) *stakercfg.Config {
+func DefaultStakerConfig(t *testing.T, walletName, passphrase, bitcoindHost string) *stakercfg.Config {
 	defaultConfig := stakercfg.DefaultConfig()
 
 	// both wallet and node are bicoind
 	defaultConfig.BtcNodeBackendConfig.ActiveWalletBackend = types.BitcoindWalletBackend
 	defaultConfig.BtcNodeBackendConfig.ActiveNodeBackend = types.BitcoindNodeBackend
-	defaultConfig.ActiveNetParams = *regtestParams
+	defaultConfig.ActiveNetParams = *RegtestParams
 
 	// Fees configuration
 	defaultConfig.BtcNodeBackendConfig.FeeMode = "dynamic"
+	defaultConfig.BtcNodeBackendConfig.MinFeeRate = utils.FeeRateFloor(RegtestParams)
 	defaultConfig.BtcNodeBackendConfig.EstimationMode = types.DynamicFeeEstimation
 
 	// Wallet configuration
@@ -123,6 +122,10 @@ func defaultStakerConfig(t *testing.T, walletName, passphrase, bitcoindHost stri
 	defaultConfig.StakerConfig.BabylonStallingInterval = 1 * time.Second
 	defaultConfig.StakerConfig.UnbondingTxCheckInterval = 1 * time.Second
 	defaultConfig.StakerConfig.CheckActiveInterval = 1 * time.Second
+	defaultConfig.StakerConfig.StatusRefreshInterval = 1 * time.Second
+
+	// e2e tests cover experimental features as well
+	defaultConfig.FeatureFlagsConfig.Enable = stakercfg.ExperimentalFeatures
 
 	// TODO: After bumping relayer version sending transactions concurrently fails wih
 	// fatal error: concurrent map writes
@@ -132,7 +135,7 @@ func defaultStakerConfig(t *testing.T, walletName, passphrase, bitcoindHost stri
 	return &defaultConfig
 }
 
-func btcRpcTestClient(t *testing.T, bitcoindHost string) *rpcclient.Client {
+func BtcRpcTestClient(t *testing.T, bitcoindHost string) *rpcclient.Client {
 	testRpcBtcClient, err := rpcclient.New(&rpcclient.ConnConfig{
 		Host:                 bitcoindHost,
 		User:                 bitcoindUser,
@@ -149,7 +152,7 @@ func btcRpcTestClient(t *testing.T, bitcoindHost string) *rpcclient.Client {
 }
 
 type TestManager struct {
-	manager *containers.Manager
+	Manager *containers.Manager
 	TestManagerStakerApp
 	TestManagerBTC
 }
@@ -160,7 +163,7 @@ type TestManagerStakerApp struct {
 	Sa               *staker.App
 	BabylonClient    *babylonclient.BabylonController
 	wg               *sync.WaitGroup
-	serviceAddress   string
+	ServiceAddress   string
 	StakerClient     *dc.StakerServiceJSONRPCClient
 	CovenantPrivKeys []*btcec.PrivateKey
 }
@@ -177,7 +180,7 @@ type TestManagerBTC struct {
 	TestRpcBtcClient *rpcclient.Client
 }
 
-type testStakingData struct {
+type TestStakingData struct {
 	StakerKey                       *btcec.PublicKey
 	StakerBabylonAddr               sdk.AccAddress
 	FinalityProviderBabylonPrivKeys []*secp256k1.PrivKey
@@ -188,17 +191,17 @@ type testStakingData struct {
 	StakingAmount                   int64
 }
 
-func (d *testStakingData) GetNumRestakedFPs() int {
+func (d *TestStakingData) GetNumRestakedFPs() int {
 	return len(d.FinalityProviderBabylonPrivKeys)
 }
 
-func (tm *TestManager) getTestStakingData(
+func (tm *TestManager) GetTestStakingData(
 	t *testing.T,
 	stakerKey *btcec.PublicKey,
 	stakingTime uint16,
 	stakingAmount int64,
 	numRestakedFPs int,
-) *testStakingData {
+) *TestStakingData {
 	stkData := GetTestStakingData(t, stakerKey, stakingTime, stakingAmount, numRestakedFPs, tm.BabylonClient.GetKeyAddress())
 
 	strAddrs := make([]string, numRestakedFPs)
@@ -206,7 +209,7 @@ func (tm *TestManager) getTestStakingData(
 		strAddrs[i] = stkData.FinalityProviderBabylonAddrs[i].String()
 	}
 
-	_, _, err := tm.manager.BabylondTxBankMultiSend(t, "node0", "1000000ubbn", strAddrs...)
+	_, _, err := tm.Manager.BabylondTxBankMultiSend(t, "node0", "1000000ubbn", strAddrs...)
 	require.NoError(t, err)
 	return stkData
 }
@@ -218,7 +221,7 @@ func GetTestStakingData(
 	stakingAmount int64,
 	numRestakedFPs int,
 	stakerBabylonAddr sdk.AccAddress,
-) *testStakingData {
+) *TestStakingData {
 	fpBTCSKs, fpBTCPKs, err := datagen.GenRandomBTCKeyPairs(r, numRestakedFPs)
 	require.NoError(t, err)
 
@@ -231,7 +234,7 @@ func GetTestStakingData(
 		fpBBNAddrs[i] = fpAddr
 	}
 
-	return &testStakingData{
+	return &TestStakingData{
 		StakerKey: stakerKey,
 		// the staker babylon addr needs to be the same one that is going to sign
 		// the transaction in the end
@@ -245,13 +248,13 @@ func GetTestStakingData(
 	}
 }
 
-func (td *testStakingData) withStakingTime(time uint16) *testStakingData {
+func (td *TestStakingData) WithStakingTime(time uint16) *TestStakingData {
 	tdCopy := *td
 	tdCopy.StakingTime = time
 	return &tdCopy
 }
 
-func (td *testStakingData) withStakingAmout(amout int64) *testStakingData {
+func (td *TestStakingData) WithStakingAmount(amout int64) *TestStakingData {
 	tdCopy := *td
 	tdCopy.StakingAmount = int64(amout)
 	return &tdCopy
@@ -271,11 +274,17 @@ func StartManagerBtc(
 	// only outputs which are 100 deep are mature
 	br := bitcoindHandler.GenerateBlocks(int(numMatureOutputsInWallet) + 100)
 
-	minerAddressDecoded, err := btcutil.DecodeAddress(br.Address, regtestParams)
+	chainParams, err := manager.BitcoindChainParams()
+	require.NoError(t, err)
+
+	minerAddressDecoded, err := btcutil.DecodeAddress(br.Address, chainParams)
+	require.NoError(t, err)
+
+	rpcPort, err := manager.BitcoindRPCPort()
 	require.NoError(t, err)
 
-	bitcoindHost := fmt.Sprintf("127.0.0.1:%s", bitcoind.GetPort("18443/tcp"))
-	rpcBtc := btcRpcTestClient(t, bitcoindHost)
+	bitcoindHost := fmt.Sprintf("127.0.0.1:%s", bitcoind.GetPort(rpcPort))
+	rpcBtc := BtcRpcTestClient(t, bitcoindHost)
 
 	err = rpcBtc.WalletPassphrase(passphrase, 20)
 	require.NoError(t, err)
@@ -303,25 +312,38 @@ func StartManagerBtc(
 	}
 }
 
+// StartManager starts bitcoind and babylond containers together with staker
+// app and its rpc service. Image versions are taken from btc-staker go.mod.
 func StartManager(
 	t *testing.T,
 	ctx context.Context,
 	numMatureOutputsInWallet uint32,
+) *TestManager {
+	return StartManagerWithImageConfig(t, ctx, numMatureOutputsInWallet, containers.NewImageConfig(t))
+}
+
+// StartManagerWithImageConfig is like StartManager, but runs containers from
+// given images
+func StartManagerWithImageConfig(
+	t *testing.T,
+	ctx context.Context,
+	numMatureOutputsInWallet uint32,
+	imageCfg containers.ImageConfig,
 ) *TestManager {
 	os.Setenv(service.EnvRouteAuthUser, daemonRouteUser)
 	os.Setenv(service.EnvRouteAuthPwd, daemonRoutePwd)
 
-	manager, err := containers.NewManager(t)
+	manager, err := containers.NewManagerWithConfig(imageCfg)
 	require.NoError(t, err)
 
 	tmBTC := StartManagerBtc(t, ctx, numMatureOutputsInWallet, manager)
 
 	quorum := 2
-	coventantPrivKeys := genCovenants(t, 3)
+	coventantPrivKeys := GenCovenants(t, 3)
 	tmStakerApp := StartManagerStakerApp(t, ctx, tmBTC, manager, quorum, coventantPrivKeys)
 
 	return &TestManager{
-		manager:              manager,
+		Manager:              manager,
 		TestManagerStakerApp: *tmStakerApp,
 		TestManagerBTC:       *tmBTC,
 	}
@@ -344,7 +366,7 @@ func StartManagerStakerApp(
 	}
 
 	var buff bytes.Buffer
-	err := regtestParams.GenesisBlock.Header.Serialize(&buff)
+	err := RegtestParams.GenesisBlock.Header.Serialize(&buff)
 	require.NoError(t, err)
 	baseHeaderHex := hex.EncodeToString(buff.Bytes())
 
@@ -363,7 +385,7 @@ func StartManagerStakerApp(
 	)
 	require.NoError(t, err)
 
-	cfg := defaultStakerConfig(t, tmBTC.WalletName, tmBTC.WalletPassphrase, tmBTC.BitcoindHost)
+	cfg := DefaultStakerConfig(t, tmBTC.WalletName, tmBTC.WalletPassphrase, tmBTC.BitcoindHost)
 	// update port with the dynamically allocated one from docker
 	cfg.BabylonConfig.RPCAddr = fmt.Sprintf("http://localhost:%s", babylond.GetPort("26657/tcp"))
 	cfg.BabylonConfig.GRPCAddr = fmt.Sprintf("https://localhost:%s", babylond.GetPort("9090/tcp"))
@@ -418,7 +440,7 @@ func StartManagerStakerApp(
 		defer wg.Done()
 		err := stakerService.RunUntilShutdown(ctx, daemonRouteUser, daemonRoutePwd)
 		if err != nil {
-			t.Fatalf("Error running server: %v", err)
+			t.Errorf("Error running server: %v", err)
 		}
 	}()
 	// Wait for the server to start
@@ -435,13 +457,13 @@ func StartManagerStakerApp(
 		Sa:               stakerApp,
 		BabylonClient:    bl,
 		wg:               &wg,
-		serviceAddress:   addressString,
+		ServiceAddress:   addressString,
 		StakerClient:     stakerClient,
 		CovenantPrivKeys: coventantPrivKeys,
 	}
 }
 
-func genCovenants(t *testing.T, numCovenants int) []*btcec.PrivateKey {
+func GenCovenants(t *testing.T, numCovenants int) []*btcec.PrivateKey {
 	var coventantPrivKeys []*btcec.PrivateKey
 	for i := 0; i < numCovenants; i++ {
 		covenantPrivKey, err := btcec.NewPrivateKey()
@@ -454,7 +476,7 @@ func genCovenants(t *testing.T, numCovenants int) []*btcec.PrivateKey {
 func (tm *TestManager) Stop(t *testing.T, cancelFunc context.CancelFunc) {
 	cancelFunc()
 	tm.wg.Wait()
-	err := tm.manager.ClearResources()
+	err := tm.Manager.ClearResources()
 	require.NoError(t, err)
 	err = os.RemoveAll(tm.Config.DBConfig.DBPath)
 	require.NoError(t, err)
@@ -502,7 +524,7 @@ func (tm *TestManager) RestartAppWithAction(t *testing.T, ctx context.Context, c
 		defer wg.Done()
 		err := service.RunUntilShutdown(ctx, daemonRouteUser, daemonRoutePwd)
 		if err != nil {
-			t.Fatalf("Error running server: %v", err)
+			t.Errorf("Error running server: %v", err)
 		}
 	}()
 	// Wait for the server to start
@@ -511,12 +533,12 @@ func (tm *TestManager) RestartAppWithAction(t *testing.T, ctx context.Context, c
 	tm.wg = &wg
 	tm.Db = dbbackend
 	tm.Sa = stakerApp
-	stakerClient, err := daemon.NewStakerServiceJSONRPCClient("tcp://" + tm.serviceAddress)
+	stakerClient, err := daemon.NewStakerServiceJSONRPCClient("tcp://" + tm.ServiceAddress)
 	require.NoError(t, err)
 	tm.StakerClient = stakerClient
 }
 
-func retrieveTransactionFromMempool(t *testing.T, client *rpcclient.Client, hashes []*chainhash.Hash) []*btcutil.Tx {
+func RetrieveTransactionFromMempool(t *testing.T, client *rpcclient.Client, hashes []*chainhash.Hash) []*btcutil.Tx {
 	var txes []*btcutil.Tx
 	for _, txHash := range hashes {
 		tx, err := client.GetRawTransaction(txHash)
@@ -564,7 +586,7 @@ func txToBytes(tx *wire.MsgTx) []byte {
 	return buf.Bytes()
 }
 
-func txsToBytes(txs []*wire.MsgTx) [][]byte {
+func TxsToBytes(txs []*wire.MsgTx) [][]byte {
 	var txsBytes [][]byte
 	for _, tx := range txs {
 		txsBytes = append(txsBytes, txToBytes(tx))
@@ -668,9 +690,9 @@ func (tm *TestManager) FinalizeUntilEpoch(t *testing.T, epoch uint64) {
 		header1Bytes := bbntypes.NewBTCHeaderBytesFromBlockHeader(&block1.Header)
 		header2Bytes := bbntypes.NewBTCHeaderBytesFromBlockHeader(&block2.Header)
 
-		proof1, err := btcctypes.SpvProofFromHeaderAndTransactions(&header1Bytes, txsToBytes(block1.Transactions), 1)
+		proof1, err := btcctypes.SpvProofFromHeaderAndTransactions(&header1Bytes, TxsToBytes(block1.Transactions), 1)
 		require.NoError(t, err)
-		proof2, err := btcctypes.SpvProofFromHeaderAndTransactions(&header2Bytes, txsToBytes(block2.Transactions), 1)
+		proof2, err := btcctypes.SpvProofFromHeaderAndTransactions(&header2Bytes, TxsToBytes(block2.Transactions), 1)
 		require.NoError(t, err)
 
 		_, err = tm.BabylonClient.InsertSpvProofs(submitter.String(), []*btcctypes.BTCSpvProof{
@@ -684,10 +706,10 @@ func (tm *TestManager) FinalizeUntilEpoch(t *testing.T, epoch uint64) {
 			ckpt, err := bbnClient.RawCheckpoint(checkpoint.Ckpt.EpochNum)
 			require.NoError(t, err)
 			return ckpt.RawCheckpoint.Status == ckpttypes.Submitted
-		}, eventuallyWaitTimeOut, eventuallyPollTime)
+		}, EventuallyWaitTimeOut, EventuallyPollTime)
 	}
 
-	tm.mineNEmptyBlocks(t, uint32(ckptParams.Params.CheckpointFinalizationTimeout), true)
+	tm.MineNEmptyBlocks(t, uint32(ckptParams.Params.CheckpointFinalizationTimeout), true)
 
 	// // wait until the checkpoint of this epoch is finalised
 	require.Eventually(t, func() bool {
@@ -697,17 +719,17 @@ func (tm *TestManager) FinalizeUntilEpoch(t *testing.T, epoch uint64) {
 			return false
 		}
 		return epoch <= lastFinalizedCkpt.RawCheckpoint.EpochNum
-	}, eventuallyWaitTimeOut, 1*time.Second)
+	}, EventuallyWaitTimeOut, 1*time.Second)
 
 	t.Logf("epoch %d is finalised", epoch)
 }
 
-func (tm *TestManager) createAndRegisterFinalityProviders(t *testing.T, stkData *testStakingData) {
+func (tm *TestManager) CreateAndRegisterFinalityProviders(t *testing.T, stkData *TestStakingData) {
 	params, err := tm.BabylonClient.QueryStakingTracker()
 	require.NoError(t, err)
 
 	for i := 0; i < stkData.GetNumRestakedFPs(); i++ {
-		// ensure the finality provider in testStakingData does not exist yet
+		// ensure the finality provider in TestStakingData does not exist yet
 		fpResp, err := tm.BabylonClient.QueryFinalityProvider(stkData.FinalityProviderBtcKeys[i])
 		require.Nil(t, fpResp)
 		require.Error(t, err)
@@ -744,12 +766,12 @@ func (tm *TestManager) createAndRegisterFinalityProviders(t *testing.T, stkData
 	}
 }
 
-func (tm *TestManager) sendHeadersToBabylon(t *testing.T, headers []*wire.BlockHeader) {
+func (tm *TestManager) SendHeadersToBabylon(t *testing.T, headers []*wire.BlockHeader) {
 	_, err := tm.BabylonClient.InsertBtcBlockHeaders(headers)
 	require.NoError(t, err)
 }
 
-func (tm *TestManager) mineNEmptyBlocks(t *testing.T, numHeaders uint32, sendToBabylon bool) []*wire.BlockHeader {
+func (tm *TestManager) MineNEmptyBlocks(t *testing.T, numHeaders uint32, sendToBabylon bool) []*wire.BlockHeader {
 	resp := tm.BitcoindHandler.GenerateBlocks(int(numHeaders))
 
 	var minedHeaders []*wire.BlockHeader
@@ -762,13 +784,13 @@ func (tm *TestManager) mineNEmptyBlocks(t *testing.T, numHeaders uint32, sendToB
 	}
 
 	if sendToBabylon {
-		tm.sendHeadersToBabylon(t, minedHeaders)
+		tm.SendHeadersToBabylon(t, minedHeaders)
 	}
 
 	return minedHeaders
 }
 
-func (tm *TestManager) mineBlock(t *testing.T) *wire.MsgBlock {
+func (tm *TestManager) MineBlock(t *testing.T) *wire.MsgBlock {
 	resp := tm.BitcoindHandler.GenerateBlocks(1)
 	hash, err := chainhash.NewHashFromStr(resp.Blocks[0])
 	require.NoError(t, err)
@@ -777,11 +799,11 @@ func (tm *TestManager) mineBlock(t *testing.T) *wire.MsgBlock {
 	return header
 }
 
-// sendStakingTxBTC sends a staking transaction to Babylon
+// SendStakingTxBTC sends a staking transaction to Babylon
 // TODO: modify function name to be more descriptive
-func (tm *TestManager) sendStakingTxBTC(
+func (tm *TestManager) SendStakingTxBTC(
 	t *testing.T,
-	stkData *testStakingData,
+	stkData *TestStakingData,
 ) *chainhash.Hash {
 	fpBTCPKs := []string{}
 	for i := 0; i < stkData.GetNumRestakedFPs(); i++ {
@@ -794,6 +816,12 @@ func (tm *TestManager) sendStakingTxBTC(
 		stkData.StakingAmount,
 		fpBTCPKs,
 		int64(stkData.StakingTime),
+		"",
+		"",
+		"",
+		0,
+		0,
+		0,
 	)
 	require.NoError(t, err)
 	txHash := res.TxHash
@@ -803,19 +831,19 @@ func (tm *TestManager) sendStakingTxBTC(
 	return hashFromString
 }
 
-func (tm *TestManager) sendMultipleStakingTxBTC(t *testing.T, tStkData []*testStakingData) []*chainhash.Hash {
+func (tm *TestManager) SendMultipleStakingTxBTC(t *testing.T, tStkData []*TestStakingData) []*chainhash.Hash {
 	var hashes []*chainhash.Hash
 	for _, data := range tStkData {
-		txHash := tm.sendStakingTxBTC(t, data)
+		txHash := tm.SendStakingTxBTC(t, data)
 		hashes = append(hashes, txHash)
 	}
 
 	return hashes
 }
 
-// spendStakingTxWithHash sends a spend transaction to Babylon
-func (tm *TestManager) spendStakingTxWithHash(t *testing.T, stakingTxHash *chainhash.Hash) (*chainhash.Hash, *btcutil.Amount) {
-	res, err := tm.StakerClient.SpendStakingTransaction(context.Background(), stakingTxHash.String())
+// SpendStakingTxWithHash sends a spend transaction to Babylon
+func (tm *TestManager) SpendStakingTxWithHash(t *testing.T, stakingTxHash *chainhash.Hash) (*chainhash.Hash, *btcutil.Amount) {
+	res, err := tm.StakerClient.SpendStakingTransaction(context.Background(), stakingTxHash.String(), 0, 0, "")
 	require.NoError(t, err)
 	spendTxHash, err := chainhash.NewHashFromStr(res.TxHash)
 	require.NoError(t, err)
@@ -825,11 +853,11 @@ func (tm *TestManager) spendStakingTxWithHash(t *testing.T, stakingTxHash *chain
 	spendTxValue := btcutil.Amount(iAmount)
 
 	require.Eventually(t, func() bool {
-		txFromMempool := retrieveTransactionFromMempool(t, tm.TestRpcBtcClient, []*chainhash.Hash{spendTxHash})
+		txFromMempool := RetrieveTransactionFromMempool(t, tm.TestRpcBtcClient, []*chainhash.Hash{spendTxHash})
 		return len(txFromMempool) == 1
-	}, eventuallyWaitTimeOut, eventuallyPollTime)
+	}, EventuallyWaitTimeOut, EventuallyPollTime)
 
-	sendTx := retrieveTransactionFromMempool(t, tm.TestRpcBtcClient, []*chainhash.Hash{spendTxHash})[0]
+	sendTx := RetrieveTransactionFromMempool(t, tm.TestRpcBtcClient, []*chainhash.Hash{spendTxHash})[0]
 
 	// Tx is in mempool
 	txDetails, txState, err := tm.Sa.Wallet().TxDetails(spendTxHash, sendTx.MsgTx().TxOut[0].PkScript)
@@ -838,7 +866,7 @@ func (tm *TestManager) spendStakingTxWithHash(t *testing.T, stakingTxHash *chain
 	require.Equal(t, txState, walletcontroller.TxInMemPool)
 
 	// Block with spend is mined
-	mBlock1 := tm.mineBlock(t)
+	mBlock1 := tm.MineBlock(t)
 	require.Equal(t, 2, len(mBlock1.Transactions))
 
 	// Tx is in chain
@@ -850,30 +878,30 @@ func (tm *TestManager) spendStakingTxWithHash(t *testing.T, stakingTxHash *chain
 	return spendTxHash, &spendTxValue
 }
 
-// waitForStakingTxState waits for the staking transaction to reach the expected state
+// WaitForStakingTxState waits for the staking transaction to reach the expected state
 // queried from the babylon node directly
-func (tm *TestManager) waitForStakingTxState(t *testing.T, txHash *chainhash.Hash, expectedState string) {
+func (tm *TestManager) WaitForStakingTxState(t *testing.T, txHash *chainhash.Hash, expectedState string) {
 	require.Eventually(t, func() bool {
-		detailResult, err := tm.StakerClient.StakingDetails(context.Background(), txHash.String())
+		detailResult, err := tm.StakerClient.StakingDetails(context.Background(), txHash.String(), "")
 		if err != nil {
 			return false
 		}
 		return detailResult.StakingState == expectedState
-	}, eventuallyTimeout, eventuallyPollTime)
+	}, EventuallyTimeout, EventuallyPollTime)
 }
 
-func (tm *TestManager) waitForTxOutputSpent(t *testing.T, unbondingTxHash *chainhash.Hash) {
+func (tm *TestManager) WaitForTxOutputSpent(t *testing.T, unbondingTxHash *chainhash.Hash) {
 	require.Eventually(t, func() bool {
 		unbondingOutputSpent, err := tm.Sa.Wallet().OutputSpent(unbondingTxHash, 0)
 		if err != nil {
 			return false
 		}
 		return unbondingOutputSpent
-	}, eventuallyTimeout, eventuallyPollTime)
+	}, EventuallyTimeout, EventuallyPollTime)
 }
 
-// waitForUnbondingTxConfirmedOnBtc waits for the unbonding transaction to be confirmed on the bitcoin network
-func (tm *TestManager) waitForUnbondingTxConfirmedOnBtc(t *testing.T, txHash, unbondingTxHash *chainhash.Hash) {
+// WaitForUnbondingTxConfirmedOnBtc waits for the unbonding transaction to be confirmed on the bitcoin network
+func (tm *TestManager) WaitForUnbondingTxConfirmedOnBtc(t *testing.T, txHash, unbondingTxHash *chainhash.Hash) {
 	require.Eventually(t, func() bool {
 		// First check if the delegation exists and has unbonding info
 		di, err := tm.Sa.BabylonController().QueryBTCDelegation(txHash)
@@ -900,18 +928,18 @@ func (tm *TestManager) waitForUnbondingTxConfirmedOnBtc(t *testing.T, txHash, un
 
 		// Check if we have the required number of confirmations
 		return res.Confirmations >= staker.UnbondingTxConfirmations
-	}, eventuallyTimeout, eventuallyPollTime)
+	}, EventuallyTimeout, EventuallyPollTime)
 }
 
-// insertAllMinedBlocksToBabylon inserts all mined blocks to Babylon
-func (tm *TestManager) insertAllMinedBlocksToBabylon(t *testing.T) {
+// InsertAllMinedBlocksToBabylon inserts all mined blocks to Babylon
+func (tm *TestManager) InsertAllMinedBlocksToBabylon(t *testing.T) {
 	headers := GetAllMinedBtcHeadersSinceGenesis(t, tm.TestRpcBtcClient)
 	_, err := tm.BabylonClient.InsertBtcBlockHeaders(headers)
 	require.NoError(t, err)
 }
 
-// signStakeExpansionTx creates covenant signature for stake expansion transaction
-func (tm *TestManager) signStakeExpansionTx(t *testing.T, covenantSK *btcec.PrivateKey, del *btcstypes.BTCDelegationResponse, params *babylonclient.StakingParams) *bbntypes.BIP340Signature {
+// SignStakeExpansionTx creates covenant signature for stake expansion transaction
+func (tm *TestManager) SignStakeExpansionTx(t *testing.T, covenantSK *btcec.PrivateKey, del *btcstypes.BTCDelegationResponse, params *babylonclient.StakingParams) *bbntypes.BIP340Signature {
 	require.NotNil(t, del.StkExp, "delegation should be a stake expansion")
 
 	stakingTx := del.StakingTxHex
@@ -945,7 +973,7 @@ func (tm *TestManager) signStakeExpansionTx(t *testing.T, covenantSK *btcec.Priv
 		params.CovenantQuruomThreshold,
 		uint16(prevDel.EndHeight-prevDel.StartHeight),
 		btcutil.Amount(prevDel.TotalSat),
-		regtestParams,
+		RegtestParams,
 	)
 	require.NoError(t, err)
 
@@ -964,8 +992,8 @@ func (tm *TestManager) signStakeExpansionTx(t *testing.T, covenantSK *btcec.Priv
 	return bbntypes.NewBIP340SignatureFromBTCSig(sig)
 }
 
-// insertCovenantSigForDelegation inserts a covenant signature for a delegation
-func (tm *TestManager) insertCovenantSigForDelegation(
+// InsertCovenantSigForDelegation inserts a covenant signature for a delegation
+func (tm *TestManager) InsertCovenantSigForDelegation(
 	t *testing.T,
 	btcDel *btcstypes.BTCDelegationResponse,
 ) {
@@ -990,7 +1018,7 @@ func (tm *TestManager) insertCovenantSigForDelegation(
 		params.CovenantQuruomThreshold,
 		uint16(btcDel.EndHeight-btcDel.StartHeight),
 		btcutil.Amount(btcDel.TotalSat),
-		regtestParams,
+		RegtestParams,
 	)
 	require.NoError(t, err)
 
@@ -1017,7 +1045,7 @@ func (tm *TestManager) insertCovenantSigForDelegation(
 		params.CovenantQuruomThreshold,
 		uint16(btcDel.UnbondingTime),
 		btcutil.Amount(unbondingMsgTx.TxOut[0].Value),
-		regtestParams,
+		RegtestParams,
 	)
 	unbondingSlashingPathInfo, err := unbondingInfo.SlashingPathSpendInfo()
 	require.NoError(t, err)
@@ -1055,7 +1083,7 @@ func (tm *TestManager) insertCovenantSigForDelegation(
 		var stakeExpansionSig *bbntypes.BIP340Signature
 		if isStakeExpansion {
 			// Generate stake expansion signature
-			stakeExpansionSig = tm.signStakeExpansionTx(t, tm.CovenantPrivKeys[i], btcDel, params)
+			stakeExpansionSig = tm.SignStakeExpansionTx(t, tm.CovenantPrivKeys[i], btcDel, params)
 		}
 
 		msg := tm.BabylonClient.CreateCovenantMessage(
@@ -0,0 +1,24 @@
+// Package mocks contains gomock mocks of the staker app dependencies, so that
+// App logic can be unit tested without live btc and babylon nodes. Mocks are
+// generated, run `make mocks` after changing any of the mocked interfaces.
+//
+// Package must not import staker, as in-package staker tests use the mocks.
+// MockFeeEstimator is checked against staker.FeeEstimator in staker tests.
+package mocks
+
+import (
+	"github.com/babylonlabs-io/btc-staker/babylonclient"
+	"github.com/babylonlabs-io/btc-staker/walletcontroller"
+	notifier "github.com/lightningnetwork/lnd/chainntnfs"
+)
+
+//go:generate mockgen -destination=walletcontroller.go -package=mocks github.com/babylonlabs-io/btc-staker/walletcontroller WalletController
+//go:generate mockgen -destination=babylonclient.go -package=mocks github.com/babylonlabs-io/btc-staker/babylonclient BabylonClient
+//go:generate mockgen -destination=chainnotifier.go -package=mocks github.com/lightningnetwork/lnd/chainntnfs ChainNotifier
+//go:generate mockgen -destination=feeestimator.go -package=mocks github.com/babylonlabs-io/btc-staker/staker FeeEstimator
+
+var (
+	_ walletcontroller.WalletController = (*MockWalletController)(nil)
+	_ babylonclient.BabylonClient       = (*MockBabylonClient)(nil)
+	_ notifier.ChainNotifier            = (*MockChainNotifier)(nil)
+)
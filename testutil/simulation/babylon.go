@@ -0,0 +1,670 @@
+package simulation
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"sync"
+
+	sdkmath "cosmossdk.io/math"
+	staking "github.com/babylonlabs-io/babylon/v4/btcstaking"
+	bct "github.com/babylonlabs-io/babylon/v4/client/babylonclient"
+	bbntypes "github.com/babylonlabs-io/babylon/v4/types"
+	btcstypes "github.com/babylonlabs-io/babylon/v4/x/btcstaking/types"
+	"github.com/babylonlabs-io/btc-staker/babylonclient"
+	"github.com/babylonlabs-io/btc-staker/staker"
+	"github.com/babylonlabs-io/btc-staker/utils"
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+const (
+	// BabylonUnbondedStatus and BabylonExpiredStatus are statuses of
+	// delegations which are no longer active, as reported by babylon
+	BabylonUnbondedStatus = "UNBONDED"
+	BabylonExpiredStatus  = "EXPIRED"
+
+	defaultCovenantMembers = 3
+	defaultCovenantQuorum  = 2
+)
+
+// delegation is delegation stored by simulated babylon
+type delegation struct {
+	data             *babylonclient.DelegationData
+	stakingOutputIdx uint32
+	status           string
+	// btc height at which delegation became active, 0 if it is not active yet
+	startHeight uint32
+	// signatures of covenant members over unbonding transaction
+	covenantSigs []*btcstypes.SignatureInfo
+	// transaction which spent staking output, if different than unbonding
+	// transaction
+	spendStakeTx *wire.MsgTx
+}
+
+// Babylon implements babylonclient.BabylonClient as in-memory babylon node
+// which follows simulated btc chain. Covenant committee and vigilantes are
+// simulated as well, but they act only when asked to by SignDelegation and
+// ActivateDelegation, so tests control when delegation moves between statuses.
+// Unbonding and expiry of active delegations are detected when blocks are
+// mined.
+type Babylon struct {
+	chain *Chain
+
+	mu           sync.Mutex
+	params       *babylonclient.StakingParams
+	covenantKeys []*btcec.PrivateKey
+	babylonKey   *secp256k1.PrivKey
+	fps          []babylonclient.FinalityProviderInfo
+	fpIndex      uint32
+	seed         []byte
+	// staking tx hash -> delegation
+	delegations map[chainhash.Hash]*delegation
+}
+
+var _ babylonclient.BabylonClient = (*Babylon)(nil)
+
+// NewBabylon creates babylon following given chain. Covenant and babylon keys
+// are derived from the seed.
+func NewBabylon(chain *Chain, seed []byte) (*Babylon, error) {
+	covenantKeys := make([]*btcec.PrivateKey, defaultCovenantMembers)
+	covenantPks := make([]*btcec.PublicKey, defaultCovenantMembers)
+	for i := range covenantKeys {
+		covenantKeys[i] = deriveKey(seed, fmt.Sprintf("covenant-%d", i))
+		covenantPks[i] = covenantKeys[i].PubKey()
+	}
+
+	slashingAddress, err := segwitAddress(deriveKey(seed, "slashing").PubKey(), chain.Params())
+	if err != nil {
+		return nil, err
+	}
+
+	slashingPkScript, err := txscript.PayToAddrScript(slashingAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	b := &Babylon{
+		chain: chain,
+		params: &babylonclient.StakingParams{
+			BTCCheckpointParams: babylonclient.BTCCheckpointParams{
+				ConfirmationTimeBlocks:    2,
+				FinalizationTimeoutBlocks: 5,
+			},
+			BtcStakingParams: babylonclient.BtcStakingParams{
+				MinSlashingTxFeeSat:     btcutil.Amount(1000),
+				CovenantPks:             covenantPks,
+				SlashingPkScript:        slashingPkScript,
+				SlashingRate:            sdkmath.LegacyNewDecWithPrec(1, 1), // 0.1
+				CovenantQuruomThreshold: defaultCovenantQuorum,
+				UnbondingTime:           10,
+				UnbondingFee:            btcutil.Amount(1000),
+				MinStakingTime:          10,
+				MaxStakingTime:          10000,
+				MinStakingValue:         btcutil.Amount(10000),
+				MaxStakingValue:         btcutil.Amount(10 * btcutil.SatoshiPerBitcoin),
+			},
+		},
+		covenantKeys: covenantKeys,
+		babylonKey:   secp256k1.GenPrivKeyFromSecret(append(append([]byte{}, seed...), []byte("babylon")...)),
+		seed:         seed,
+		delegations:  make(map[chainhash.Hash]*delegation),
+	}
+
+	chain.subscribe(b.onBlock)
+
+	return b, nil
+}
+
+// deriveKey derives btc private key from the seed and the purpose of the key
+func deriveKey(seed []byte, purpose string) *btcec.PrivateKey {
+	keyBytes := sha256.Sum256(append(append([]byte{}, seed...), []byte(purpose)...))
+	privKey, _ := btcec.PrivKeyFromBytes(keyBytes[:])
+	return privKey
+}
+
+// SetParams replaces staking params. Covenant keys are kept, as simulated
+// covenant committee must be able to sign delegations.
+func (b *Babylon) SetParams(params babylonclient.StakingParams) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	params.CovenantPks = b.params.CovenantPks
+	params.CovenantQuruomThreshold = b.params.CovenantQuruomThreshold
+	b.params = &params
+}
+
+// AddFinalityProvider registers new finality provider with key derived from
+// the seed and returns its btc public key
+func (b *Babylon) AddFinalityProvider() *btcec.PublicKey {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	fpKey := deriveKey(b.seed, fmt.Sprintf("fp-%d", b.fpIndex))
+	bbnKey := secp256k1.GenPrivKeyFromSecret(fpKey.Serialize())
+	b.fpIndex++
+
+	b.fps = append(b.fps, babylonclient.FinalityProviderInfo{
+		BabylonAddr: sdk.AccAddress(bbnKey.PubKey().Address()),
+		BtcPk:       *fpKey.PubKey(),
+	})
+
+	return fpKey.PubKey()
+}
+
+func (b *Babylon) Params() (*babylonclient.StakingParams, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	params := *b.params
+	return &params, nil
+}
+
+func (b *Babylon) ParamsByBtcHeight(_ uint32) (*babylonclient.StakingParams, error) {
+	return b.Params()
+}
+
+func (b *Babylon) ParamsByVersion(_ uint32) (*babylonclient.BtcStakingParams, error) {
+	params, err := b.Params()
+	if err != nil {
+		return nil, err
+	}
+	return &params.BtcStakingParams, nil
+}
+
+func (b *Babylon) ParamsVersions() ([]babylonclient.BtcStakingParams, error) {
+	params, err := b.Params()
+	if err != nil {
+		return nil, err
+	}
+	return []babylonclient.BtcStakingParams{params.BtcStakingParams}, nil
+}
+
+func (b *Babylon) BTCCheckpointParams() (*babylonclient.BTCCheckpointParams, error) {
+	params, err := b.Params()
+	if err != nil {
+		return nil, err
+	}
+	return &params.BTCCheckpointParams, nil
+}
+
+func (b *Babylon) Sign(msg []byte) ([]byte, error) {
+	return b.babylonKey.Sign(msg)
+}
+
+func (b *Babylon) GetKeyAddress() sdk.AccAddress {
+	return sdk.AccAddress(b.babylonKey.PubKey().Address())
+}
+
+func (b *Babylon) GetPubKey() *secp256k1.PubKey {
+	pk, ok := b.babylonKey.PubKey().(*secp256k1.PubKey)
+	if !ok {
+		panic("Unsupported key type in keyring")
+	}
+	return pk
+}
+
+// Delegate validates delegation and stores it as pending delegation
+func (b *Babylon) Delegate(dg *babylonclient.DelegationData) (*bct.RelayerTxResponse, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	stakingTxHash := dg.StakingTransaction.TxHash()
+	if _, ok := b.delegations[stakingTxHash]; ok {
+		return b.invalidExecution(fmt.Errorf("delegation with staking tx %s already exists", stakingTxHash))
+	}
+
+	if dg.Ud == nil || dg.Ud.UnbondingTransaction == nil {
+		return b.invalidExecution(fmt.Errorf("delegation without unbonding transaction"))
+	}
+
+	if len(dg.FinalityProvidersBtcPks) == 0 {
+		return b.invalidExecution(fmt.Errorf("delegation without finality providers"))
+	}
+
+	for _, fpPk := range dg.FinalityProvidersBtcPks {
+		if _, err := b.finalityProvider(fpPk); err != nil {
+			return b.invalidExecution(err)
+		}
+	}
+
+	if dg.StakingValue < b.params.MinStakingValue || dg.StakingValue > b.params.MaxStakingValue {
+		return b.invalidExecution(fmt.Errorf("staking value %d is not in range [%d, %d]",
+			dg.StakingValue, b.params.MinStakingValue, b.params.MaxStakingValue))
+	}
+
+	if dg.StakingTime < b.params.MinStakingTime || dg.StakingTime > b.params.MaxStakingTime {
+		return b.invalidExecution(fmt.Errorf("staking time %d is not in range [%d, %d]",
+			dg.StakingTime, b.params.MinStakingTime, b.params.MaxStakingTime))
+	}
+
+	stakingInfo, err := b.stakingInfo(dg)
+	if err != nil {
+		return b.invalidExecution(err)
+	}
+
+	stakingOutputIdx, err := findOutput(dg.StakingTransaction, stakingInfo.StakingOutput)
+	if err != nil {
+		return b.invalidExecution(err)
+	}
+
+	unbondingTx := dg.Ud.UnbondingTransaction
+	if len(unbondingTx.TxIn) != 1 ||
+		unbondingTx.TxIn[0].PreviousOutPoint != *wire.NewOutPoint(&stakingTxHash, stakingOutputIdx) {
+		return b.invalidExecution(fmt.Errorf("unbonding transaction must spend staking output"))
+	}
+
+	del := &delegation{
+		data:             dg,
+		stakingOutputIdx: stakingOutputIdx,
+		status:           staker.BabylonPendingStatus,
+	}
+
+	if dg.StakingTransactionInclusionInfo != nil {
+		if err := b.checkInclusion(dg.StakingTransactionInclusionInfo, &stakingTxHash); err != nil {
+			return b.invalidExecution(err)
+		}
+	}
+
+	b.delegations[stakingTxHash] = del
+
+	return &bct.RelayerTxResponse{Code: 0}, nil
+}
+
+// invalidExecution returns response of message rejected by babylon
+func (b *Babylon) invalidExecution(err error) (*bct.RelayerTxResponse, error) {
+	return &bct.RelayerTxResponse{Code: 1}, fmt.Errorf("%s: %w", err.Error(), babylonclient.ErrInvalidBabylonExecution)
+}
+
+func (b *Babylon) ExpandDelegation(_ *babylonclient.DelegationData) (*bct.RelayerTxResponse, error) {
+	return nil, fmt.Errorf("stake expansion is not supported in simulation")
+}
+
+func (b *Babylon) stakingInfo(dg *babylonclient.DelegationData) (*staking.StakingInfo, error) {
+	return staking.BuildStakingInfo(
+		dg.StakerBtcPk,
+		dg.FinalityProvidersBtcPks,
+		b.params.CovenantPks,
+		b.params.CovenantQuruomThreshold,
+		dg.StakingTime,
+		dg.StakingValue,
+		b.chain.Params(),
+	)
+}
+
+func findOutput(tx *wire.MsgTx, out *wire.TxOut) (uint32, error) {
+	for i, o := range tx.TxOut {
+		if o.Value == out.Value && string(o.PkScript) == string(out.PkScript) {
+			return uint32(i), nil
+		}
+	}
+	return 0, fmt.Errorf("staking transaction %s does not contain staking output", tx.TxHash())
+}
+
+// checkInclusion checks that inclusion info points to confirmed staking
+// transaction which is deep enough
+func (b *Babylon) checkInclusion(info *babylonclient.StakingTransactionInclusionInfo, stakingTxHash *chainhash.Hash) error {
+	block, height, index := b.chain.Confirmation(stakingTxHash)
+	if block == nil {
+		return fmt.Errorf("staking transaction %s is not confirmed", stakingTxHash)
+	}
+
+	if block.BlockHash() != *info.StakingTransactionInclusionBlockHash || index != info.StakingTransactionIdx {
+		return fmt.Errorf("invalid inclusion proof of staking transaction %s", stakingTxHash)
+	}
+
+	_, tipHeight := b.chain.BestBlock()
+	if uint32(tipHeight-height) < b.params.ConfirmationTimeBlocks {
+		return fmt.Errorf("staking transaction %s is not k-deep", stakingTxHash)
+	}
+
+	return nil
+}
+
+// SignDelegation makes covenant committee sign unbonding transaction of
+// pending delegation. Delegation becomes active if it was sent with inclusion
+// proof, verified otherwise.
+func (b *Babylon) SignDelegation(stakingTxHash *chainhash.Hash) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	del, ok := b.delegations[*stakingTxHash]
+	if !ok {
+		return babylonclient.ErrDelegationNotFound
+	}
+
+	if del.status != staker.BabylonPendingStatus {
+		return fmt.Errorf("delegation %s is not pending, status: %s", stakingTxHash, del.status)
+	}
+
+	stakingInfo, err := b.stakingInfo(del.data)
+	if err != nil {
+		return err
+	}
+
+	unbondingPath, err := stakingInfo.UnbondingPathSpendInfo()
+	if err != nil {
+		return err
+	}
+
+	sigs := make([]*btcstypes.SignatureInfo, 0, len(b.covenantKeys))
+	for _, key := range b.covenantKeys {
+		sig, err := staking.SignTxWithOneScriptSpendInputFromTapLeaf(
+			del.data.Ud.UnbondingTransaction,
+			stakingInfo.StakingOutput,
+			key,
+			unbondingPath.RevealedLeaf,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to sign unbonding transaction: %w", err)
+		}
+
+		sigs = append(sigs, &btcstypes.SignatureInfo{
+			Pk:  bbntypes.NewBIP340PubKeyFromBTCPK(key.PubKey()),
+			Sig: bbntypes.NewBIP340SignatureFromBTCSig(sig),
+		})
+	}
+	del.covenantSigs = sigs
+
+	if del.data.StakingTransactionInclusionInfo != nil {
+		_, height, _ := b.chain.Confirmation(stakingTxHash)
+		b.activate(del, uint32(height))
+	} else {
+		del.status = staker.BabylonVerifiedStatus
+	}
+
+	return nil
+}
+
+// ActivateDelegation activates verified delegation whose staking transaction
+// is k-deep, as vigilante submitting inclusion proof would
+func (b *Babylon) ActivateDelegation(stakingTxHash *chainhash.Hash) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	del, ok := b.delegations[*stakingTxHash]
+	if !ok {
+		return babylonclient.ErrDelegationNotFound
+	}
+
+	if del.status != staker.BabylonVerifiedStatus {
+		return fmt.Errorf("delegation %s is not verified, status: %s", stakingTxHash, del.status)
+	}
+
+	block, height, _ := b.chain.Confirmation(stakingTxHash)
+	if block == nil {
+		return fmt.Errorf("staking transaction %s is not confirmed", stakingTxHash)
+	}
+
+	_, tipHeight := b.chain.BestBlock()
+	if uint32(tipHeight-height) < b.params.ConfirmationTimeBlocks {
+		return fmt.Errorf("staking transaction %s is not k-deep", stakingTxHash)
+	}
+
+	b.activate(del, uint32(height))
+
+	return nil
+}
+
+// SubmitInclusionProof activates verified delegation, rejecting the message
+// if the staking transaction is not k-deep in given block
+func (b *Babylon) SubmitInclusionProof(stakingTxHash *chainhash.Hash, inclusionBlock *wire.MsgBlock, _ uint32) (*bct.RelayerTxResponse, error) {
+	block, _, _ := b.chain.Confirmation(stakingTxHash)
+	if block == nil || block.BlockHash() != inclusionBlock.BlockHash() {
+		return b.invalidExecution(fmt.Errorf("staking transaction %s is not included in block %s", stakingTxHash, inclusionBlock.BlockHash()))
+	}
+
+	if err := b.ActivateDelegation(stakingTxHash); err != nil {
+		return b.invalidExecution(err)
+	}
+
+	return &bct.RelayerTxResponse{Code: 0}, nil
+}
+
+func (b *Babylon) activate(del *delegation, height uint32) {
+	del.status = staker.BabylonActiveStatus
+	del.startHeight = height
+}
+
+// SetDelegationStatus forces status of the delegation, to simulate babylon
+// behaviour not covered by the simulation
+func (b *Babylon) SetDelegationStatus(stakingTxHash *chainhash.Hash, status string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	del, ok := b.delegations[*stakingTxHash]
+	if !ok {
+		return babylonclient.ErrDelegationNotFound
+	}
+
+	del.status = status
+	return nil
+}
+
+// DelegationStatus returns status of the delegation
+func (b *Babylon) DelegationStatus(stakingTxHash *chainhash.Hash) (string, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	del, ok := b.delegations[*stakingTxHash]
+	if !ok {
+		return "", babylonclient.ErrDelegationNotFound
+	}
+
+	return del.status, nil
+}
+
+// PendingDelegations returns hashes of staking transactions of pending
+// delegations, sorted by hash
+func (b *Babylon) PendingDelegations() []chainhash.Hash {
+	return b.delegationsWithStatus(staker.BabylonPendingStatus)
+}
+
+// VerifiedDelegations returns hashes of staking transactions of verified
+// delegations, sorted by hash
+func (b *Babylon) VerifiedDelegations() []chainhash.Hash {
+	return b.delegationsWithStatus(staker.BabylonVerifiedStatus)
+}
+
+func (b *Babylon) delegationsWithStatus(status string) []chainhash.Hash {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var hashes []chainhash.Hash
+	for h, del := range b.delegations {
+		if del.status == status {
+			hashes = append(hashes, h)
+		}
+	}
+
+	sort.Slice(hashes, func(i, j int) bool {
+		return hashes[i].String() < hashes[j].String()
+	})
+
+	return hashes
+}
+
+// onBlock unbonds active delegations whose staking output was spent and
+// expires the ones which reached end of their staking time
+func (b *Babylon) onBlock(_ *wire.MsgBlock, height int32) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for h, del := range b.delegations {
+		if del.status != staker.BabylonActiveStatus {
+			continue
+		}
+
+		spender, _, _ := b.chain.Spender(*wire.NewOutPoint(&h, del.stakingOutputIdx))
+		if spender != nil {
+			del.status = BabylonUnbondedStatus
+			if spender.TxHash() != del.data.Ud.UnbondingTransaction.TxHash() {
+				del.spendStakeTx = spender
+			}
+			continue
+		}
+
+		endHeight := del.startHeight + uint32(del.data.StakingTime)
+		if uint32(height)+uint32(b.params.UnbondingTime) >= endHeight {
+			del.status = BabylonExpiredStatus
+		}
+	}
+}
+
+func (b *Babylon) finalityProvider(btcPubKey *btcec.PublicKey) (*babylonclient.FinalityProviderInfo, error) {
+	for i := range b.fps {
+		if b.fps[i].BtcPk.IsEqual(btcPubKey) {
+			return &b.fps[i], nil
+		}
+	}
+
+	return nil, babylonclient.ErrFinalityProviderDoesNotExist
+}
+
+func (b *Babylon) QueryFinalityProviders(limit uint64, offset uint64) (*babylonclient.FinalityProvidersClientResponse, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	total := uint64(len(b.fps))
+	start := min(offset, total)
+	end := total
+	if limit > 0 {
+		end = min(start+limit, total)
+	}
+
+	fps := make([]babylonclient.FinalityProviderInfo, end-start)
+	copy(fps, b.fps[start:end])
+
+	return &babylonclient.FinalityProvidersClientResponse{
+		FinalityProviders: fps,
+		Total:             total,
+	}, nil
+}
+
+func (b *Babylon) QueryFinalityProvider(btcPubKey *btcec.PublicKey) (*babylonclient.FinalityProviderClientResponse, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	fp, err := b.finalityProvider(btcPubKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return &babylonclient.FinalityProviderClientResponse{
+		FinalityProvider: *fp,
+	}, nil
+}
+
+// QueryHeaderDepth returns depth of the header in simulated chain, babylon
+// light client is always in sync with the chain
+func (b *Babylon) QueryHeaderDepth(headerHash *chainhash.Hash) (uint32, error) {
+	_, height, err := b.chain.BlockByHash(headerHash)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", err.Error(), babylonclient.ErrHeaderNotKnownToBabylon)
+	}
+
+	_, tipHeight := b.chain.BestBlock()
+	return uint32(tipHeight - height), nil
+}
+
+func (b *Babylon) IsTxAlreadyPartOfDelegation(stakingTxHash *chainhash.Hash) (bool, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	_, ok := b.delegations[*stakingTxHash]
+	return ok, nil
+}
+
+func (b *Babylon) QueryBTCDelegation(stakingTxHash *chainhash.Hash) (*btcstypes.QueryBTCDelegationResponse, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	del, ok := b.delegations[*stakingTxHash]
+	if !ok {
+		return nil, fmt.Errorf("failed to get delegation info: %w", babylonclient.ErrDelegationNotFound)
+	}
+
+	stakingTxHex, err := txHex(del.data.StakingTransaction)
+	if err != nil {
+		return nil, err
+	}
+
+	unbondingTxHex, err := txHex(del.data.Ud.UnbondingTransaction)
+	if err != nil {
+		return nil, err
+	}
+
+	fpPks := make([]bbntypes.BIP340PubKey, len(del.data.FinalityProvidersBtcPks))
+	for i, fpPk := range del.data.FinalityProvidersBtcPks {
+		fpPks[i] = *bbntypes.NewBIP340PubKeyFromBTCPK(fpPk)
+	}
+
+	undelegation := &btcstypes.BTCUndelegationResponse{
+		UnbondingTxHex:           unbondingTxHex,
+		CovenantUnbondingSigList: del.covenantSigs,
+	}
+
+	if del.spendStakeTx != nil {
+		spendStakeTxHex, err := txHex(del.spendStakeTx)
+		if err != nil {
+			return nil, err
+		}
+		undelegation.DelegatorUnbondingInfoResponse = &btcstypes.DelegatorUnbondingInfoResponse{
+			SpendStakeTxHex: spendStakeTxHex,
+		}
+	}
+
+	resp := &btcstypes.BTCDelegationResponse{
+		StakerAddr:           del.data.BabylonStakerAddr.String(),
+		BtcPk:                bbntypes.NewBIP340PubKeyFromBTCPK(del.data.StakerBtcPk),
+		FpBtcPkList:          fpPks,
+		StakingTime:          uint32(del.data.StakingTime),
+		TotalSat:             uint64(del.data.StakingValue),
+		StakingTxHex:         stakingTxHex,
+		StakingOutputIdx:     del.stakingOutputIdx,
+		Active:               del.status == staker.BabylonActiveStatus,
+		StatusDesc:           del.status,
+		UnbondingTime:        uint32(del.data.Ud.UnbondingTxUnbondingTime),
+		UndelegationResponse: undelegation,
+	}
+
+	if del.startHeight > 0 {
+		resp.StartHeight = del.startHeight
+		resp.EndHeight = del.startHeight + uint32(del.data.StakingTime)
+	}
+
+	return &btcstypes.QueryBTCDelegationResponse{BtcDelegation: resp}, nil
+}
+
+func txHex(tx *wire.MsgTx) (string, error) {
+	serialized, err := utils.SerializeBtcTransaction(tx)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(serialized), nil
+}
+
+func (b *Babylon) GetUndelegationInfo(resp *btcstypes.QueryBTCDelegationResponse) (*babylonclient.UndelegationInfo, error) {
+	return babylonclient.UndelegationInfoFromResponse(resp)
+}
+
+// GetLatestBlockHeight returns height of the btc chain tip, as simulated
+// babylon does not produce its own blocks
+func (b *Babylon) GetLatestBlockHeight() (uint64, error) {
+	_, tipHeight := b.chain.BestBlock()
+	return uint64(tipHeight), nil
+}
+
+func (b *Babylon) QueryBtcLightClientTipHeight() (uint32, error) {
+	_, tipHeight := b.chain.BestBlock()
+	return uint32(tipHeight), nil
+}
+
+// IsStakingTxAllowed returns true, as simulated babylon has no allow list
+func (b *Babylon) IsStakingTxAllowed(_ *chainhash.Hash) (bool, error) {
+	return true, nil
+}
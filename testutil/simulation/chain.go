@@ -0,0 +1,555 @@
+package simulation
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/btcsuite/btcd/blockchain"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+)
+
+const (
+	// blockInterval is the simulated time between two blocks
+	blockInterval = 10 * time.Minute
+
+	// blockSubsidy is paid by every coinbase transaction to anyone can spend
+	// script, it only makes coinbase transactions unique
+	blockSubsidy = 50 * btcutil.SatoshiPerBitcoin
+)
+
+var (
+	// anyoneCanSpendScript is pk script of outputs not owned by anybody
+	anyoneCanSpendScript = []byte{txscript.OP_TRUE}
+)
+
+// txLocation is position of confirmed transaction in the chain
+type txLocation struct {
+	height int32
+	index  uint32
+}
+
+// blockListener is called with every block added to the chain
+type blockListener func(block *wire.MsgBlock, height int32)
+
+// Chain is in-memory btc chain. Blocks are produced only by MineBlocks, so
+// callers fully control when transactions confirm. Scripts are not validated,
+// chain only checks that spent outputs exist, are not double spent and that
+// transactions do not create value.
+type Chain struct {
+	mu sync.Mutex
+
+	params *chaincfg.Params
+	blocks []*wire.MsgBlock
+	// block hash -> height
+	blockIndex map[chainhash.Hash]int32
+	// confirmed transaction hash -> location
+	txIndex map[chainhash.Hash]txLocation
+	// mempool transactions in order of arrival
+	mempool      map[chainhash.Hash]*wire.MsgTx
+	mempoolOrder []chainhash.Hash
+	// outputs of confirmed and mempool transactions
+	outputs map[wire.OutPoint]*wire.TxOut
+	// outpoint -> hash of confirmed or mempool transaction spending it
+	spentBy map[wire.OutPoint]chainhash.Hash
+	// outputs added to coinbase transaction of the next block
+	pendingFunding []*wire.TxOut
+
+	listeners []blockListener
+}
+
+// NewChain creates chain which contains only the genesis block of given
+// network
+func NewChain(params *chaincfg.Params) *Chain {
+	c := &Chain{
+		params:     params,
+		blocks:     []*wire.MsgBlock{params.GenesisBlock},
+		blockIndex: map[chainhash.Hash]int32{*params.GenesisHash: 0},
+		txIndex:    make(map[chainhash.Hash]txLocation),
+		mempool:    make(map[chainhash.Hash]*wire.MsgTx),
+		outputs:    make(map[wire.OutPoint]*wire.TxOut),
+		spentBy:    make(map[wire.OutPoint]chainhash.Hash),
+	}
+
+	return c
+}
+
+// Params returns network parameters of the chain
+func (c *Chain) Params() *chaincfg.Params {
+	return c.params
+}
+
+func (c *Chain) subscribe(l blockListener) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.listeners = append(c.listeners, l)
+}
+
+// Fund adds output paying given amount to pkScript to the coinbase transaction
+// of the next mined block. Coinbase maturity is not enforced.
+func (c *Chain) Fund(pkScript []byte, amount btcutil.Amount) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.pendingFunding = append(c.pendingFunding, wire.NewTxOut(int64(amount), pkScript))
+}
+
+// BestBlock returns hash and height of the chain tip
+func (c *Chain) BestBlock() (*chainhash.Hash, int32) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.bestBlock()
+}
+
+func (c *Chain) bestBlock() (*chainhash.Hash, int32) {
+	height := int32(len(c.blocks) - 1)
+	hash := c.blocks[height].BlockHash()
+	return &hash, height
+}
+
+// BlockByHash returns block with given hash together with its height
+func (c *Chain) BlockByHash(hash *chainhash.Hash) (*wire.MsgBlock, int32, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	height, ok := c.blockIndex[*hash]
+	if !ok {
+		return nil, 0, fmt.Errorf("block %s not found", hash)
+	}
+
+	return c.blocks[height], height, nil
+}
+
+// BlockByHeight returns block at given height
+func (c *Chain) BlockByHeight(height int32) (*wire.MsgBlock, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if height < 0 || int(height) >= len(c.blocks) {
+		return nil, fmt.Errorf("block at height %d not found", height)
+	}
+
+	return c.blocks[height], nil
+}
+
+// Tx returns transaction from the chain or mempool
+func (c *Chain) Tx(txHash *chainhash.Hash) (*wire.MsgTx, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if tx, ok := c.mempool[*txHash]; ok {
+		return tx, nil
+	}
+
+	if loc, ok := c.txIndex[*txHash]; ok {
+		return c.blocks[loc.height].Transactions[loc.index], nil
+	}
+
+	return nil, fmt.Errorf("transaction %s not found", txHash)
+}
+
+// InMempool returns true if transaction is waiting in mempool
+func (c *Chain) InMempool(txHash *chainhash.Hash) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	_, ok := c.mempool[*txHash]
+	return ok
+}
+
+// Confirmation returns block containing confirmed transaction, its height and
+// index of transaction in the block. Returned block is nil if transaction is
+// not confirmed.
+func (c *Chain) Confirmation(txHash *chainhash.Hash) (*wire.MsgBlock, int32, uint32) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	loc, ok := c.txIndex[*txHash]
+	if !ok {
+		return nil, 0, 0
+	}
+
+	return c.blocks[loc.height], loc.height, loc.index
+}
+
+// Output returns output of confirmed or mempool transaction
+func (c *Chain) Output(outpoint wire.OutPoint) (*wire.TxOut, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	out, ok := c.outputs[outpoint]
+	return out, ok
+}
+
+// OutputSpent returns true if output is spent by confirmed or mempool
+// transaction, or if it does not exist at all
+func (c *Chain) OutputSpent(outpoint wire.OutPoint) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, ok := c.outputs[outpoint]; !ok {
+		return true
+	}
+
+	_, spent := c.spentBy[outpoint]
+	return spent
+}
+
+// MempoolSpender returns hash of mempool transaction spending given output,
+// nil if output is not spent in mempool
+func (c *Chain) MempoolSpender(outpoint wire.OutPoint) *chainhash.Hash {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	spenderHash, ok := c.spentBy[outpoint]
+	if !ok {
+		return nil
+	}
+
+	if _, confirmed := c.txIndex[spenderHash]; confirmed {
+		return nil
+	}
+
+	return &spenderHash
+}
+
+// Spender returns confirmed transaction spending given output, its height and
+// index of the spending input. Returned transaction is nil if output is not
+// spent by confirmed transaction.
+func (c *Chain) Spender(outpoint wire.OutPoint) (*wire.MsgTx, int32, uint32) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	spenderHash, ok := c.spentBy[outpoint]
+	if !ok {
+		return nil, 0, 0
+	}
+
+	loc, ok := c.txIndex[spenderHash]
+	if !ok {
+		return nil, 0, 0
+	}
+
+	spender := c.blocks[loc.height].Transactions[loc.index]
+	for i, in := range spender.TxIn {
+		if in.PreviousOutPoint == outpoint {
+			return spender, loc.height, uint32(i)
+		}
+	}
+
+	return nil, 0, 0
+}
+
+// UnspentOutputs returns confirmed outputs paying to given scripts which are
+// not spent by confirmed or mempool transactions
+func (c *Chain) UnspentOutputs(pkScripts map[string]struct{}) map[wire.OutPoint]*wire.TxOut {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	unspent := make(map[wire.OutPoint]*wire.TxOut)
+	for op, out := range c.outputs {
+		if _, ok := pkScripts[string(out.PkScript)]; !ok {
+			continue
+		}
+
+		if _, ok := c.txIndex[op.Hash]; !ok {
+			continue
+		}
+
+		if _, spent := c.spentBy[op]; spent {
+			continue
+		}
+
+		unspent[op] = out
+	}
+
+	return unspent
+}
+
+// SendTransaction adds transaction to mempool. Transaction conflicting with
+// mempool transactions replaces them, together with their descendants, if it
+// pays higher fee.
+func (c *Chain) SendTransaction(tx *wire.MsgTx) (*chainhash.Hash, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	txHash := tx.TxHash()
+
+	if _, ok := c.mempool[txHash]; ok {
+		return nil, fmt.Errorf("transaction %s already in mempool", txHash)
+	}
+
+	if _, ok := c.txIndex[txHash]; ok {
+		return nil, fmt.Errorf("transaction %s already in block chain", txHash)
+	}
+
+	if len(tx.TxIn) == 0 || len(tx.TxOut) == 0 {
+		return nil, fmt.Errorf("transaction %s must have inputs and outputs", txHash)
+	}
+
+	var (
+		inputsValue int64
+		conflicts   = make(map[chainhash.Hash]struct{})
+	)
+	for _, in := range tx.TxIn {
+		out, ok := c.outputs[in.PreviousOutPoint]
+		if !ok {
+			return nil, fmt.Errorf("transaction %s spends missing output %s", txHash, in.PreviousOutPoint)
+		}
+		inputsValue += out.Value
+
+		spender, spent := c.spentBy[in.PreviousOutPoint]
+		if !spent {
+			continue
+		}
+
+		if _, inMempool := c.mempool[spender]; !inMempool {
+			return nil, fmt.Errorf("transaction %s spends output %s already spent in block chain", txHash, in.PreviousOutPoint)
+		}
+		conflicts[spender] = struct{}{}
+	}
+
+	var outputsValue int64
+	for _, out := range tx.TxOut {
+		outputsValue += out.Value
+	}
+
+	if outputsValue > inputsValue {
+		return nil, fmt.Errorf("transaction %s outputs value %d exceeds inputs value %d", txHash, outputsValue, inputsValue)
+	}
+
+	if len(conflicts) > 0 {
+		replaced := c.withDescendants(conflicts)
+
+		var replacedFees int64
+		for h := range replaced {
+			replacedFees += c.fee(c.mempool[h])
+		}
+
+		if inputsValue-outputsValue <= replacedFees {
+			return nil, fmt.Errorf("transaction %s fee %d does not exceed fee %d of replaced transactions",
+				txHash, inputsValue-outputsValue, replacedFees)
+		}
+
+		for h := range replaced {
+			c.removeFromMempool(h)
+		}
+	}
+
+	c.addToMempool(tx)
+
+	return &txHash, nil
+}
+
+// MempoolFee returns fee paid by mempool transaction
+func (c *Chain) MempoolFee(txHash *chainhash.Hash) (btcutil.Amount, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	tx, ok := c.mempool[*txHash]
+	if !ok {
+		return 0, false
+	}
+
+	return btcutil.Amount(c.fee(tx)), true
+}
+
+// fee returns fee of transaction whose inputs are all known to the chain
+func (c *Chain) fee(tx *wire.MsgTx) int64 {
+	var fee int64
+	for _, in := range tx.TxIn {
+		if out, ok := c.outputs[in.PreviousOutPoint]; ok {
+			fee += out.Value
+		}
+	}
+	for _, out := range tx.TxOut {
+		fee -= out.Value
+	}
+	return fee
+}
+
+// withDescendants returns given mempool transactions together with all
+// mempool transactions spending their outputs
+func (c *Chain) withDescendants(txs map[chainhash.Hash]struct{}) map[chainhash.Hash]struct{} {
+	result := make(map[chainhash.Hash]struct{})
+	queue := make([]chainhash.Hash, 0, len(txs))
+	for h := range txs {
+		queue = append(queue, h)
+	}
+
+	for len(queue) > 0 {
+		h := queue[0]
+		queue = queue[1:]
+
+		if _, seen := result[h]; seen {
+			continue
+		}
+		result[h] = struct{}{}
+
+		for i := range c.mempool[h].TxOut {
+			if spender, ok := c.spentBy[wire.OutPoint{Hash: h, Index: uint32(i)}]; ok {
+				queue = append(queue, spender)
+			}
+		}
+	}
+
+	return result
+}
+
+func (c *Chain) addToMempool(tx *wire.MsgTx) {
+	txHash := tx.TxHash()
+	c.mempool[txHash] = tx
+	c.mempoolOrder = append(c.mempoolOrder, txHash)
+	c.addOutputs(tx)
+}
+
+func (c *Chain) removeFromMempool(txHash chainhash.Hash) {
+	tx := c.mempool[txHash]
+	delete(c.mempool, txHash)
+
+	for i, h := range c.mempoolOrder {
+		if h == txHash {
+			c.mempoolOrder = append(c.mempoolOrder[:i], c.mempoolOrder[i+1:]...)
+			break
+		}
+	}
+
+	for _, in := range tx.TxIn {
+		delete(c.spentBy, in.PreviousOutPoint)
+	}
+
+	for i := range tx.TxOut {
+		delete(c.outputs, wire.OutPoint{Hash: txHash, Index: uint32(i)})
+	}
+}
+
+func (c *Chain) addOutputs(tx *wire.MsgTx) {
+	txHash := tx.TxHash()
+
+	if !blockchain.IsCoinBaseTx(tx) {
+		for _, in := range tx.TxIn {
+			c.spentBy[in.PreviousOutPoint] = txHash
+		}
+	}
+
+	for i, out := range tx.TxOut {
+		c.outputs[wire.OutPoint{Hash: txHash, Index: uint32(i)}] = out
+	}
+}
+
+// MineBlocks mines n blocks. First block includes all mempool transactions,
+// in order in which they arrived. Block listeners are notified after each
+// block, outside of the chain lock.
+func (c *Chain) MineBlocks(n int) []*wire.MsgBlock {
+	blocks := make([]*wire.MsgBlock, 0, n)
+	for range n {
+		blocks = append(blocks, c.mineBlock())
+	}
+	return blocks
+}
+
+func (c *Chain) mineBlock() *wire.MsgBlock {
+	c.mu.Lock()
+
+	prevHash, prevHeight := c.bestBlock()
+	height := prevHeight + 1
+
+	coinbase := c.coinbaseTx(height)
+	txs := []*wire.MsgTx{coinbase}
+	for _, h := range c.mempoolOrder {
+		txs = append(txs, c.mempool[h])
+	}
+
+	utilTxs := make([]*btcutil.Tx, len(txs))
+	for i, tx := range txs {
+		utilTxs[i] = btcutil.NewTx(tx)
+	}
+	merkles := blockchain.BuildMerkleTreeStore(utilTxs, false)
+
+	block := &wire.MsgBlock{
+		Header: wire.BlockHeader{
+			Version:    4,
+			PrevBlock:  *prevHash,
+			MerkleRoot: *merkles[len(merkles)-1],
+			Timestamp:  c.params.GenesisBlock.Header.Timestamp.Add(time.Duration(height) * blockInterval),
+			Bits:       c.params.PowLimitBits,
+			Nonce:      uint32(height),
+		},
+		Transactions: txs,
+	}
+
+	c.blocks = append(c.blocks, block)
+	c.blockIndex[block.BlockHash()] = height
+
+	c.addOutputs(coinbase)
+	for i, tx := range txs {
+		c.txIndex[tx.TxHash()] = txLocation{height: height, index: uint32(i)}
+	}
+
+	c.mempool = make(map[chainhash.Hash]*wire.MsgTx)
+	c.mempoolOrder = nil
+	c.pendingFunding = nil
+
+	listeners := make([]blockListener, len(c.listeners))
+	copy(listeners, c.listeners)
+
+	c.mu.Unlock()
+
+	for _, l := range listeners {
+		l(block, height)
+	}
+
+	return block
+}
+
+func (c *Chain) coinbaseTx(height int32) *wire.MsgTx {
+	// height in signature script makes every coinbase unique, as required by
+	// BIP34
+	sigScript, err := txscript.NewScriptBuilder().AddInt64(int64(height)).AddInt64(0).Script()
+	if err != nil {
+		panic(err)
+	}
+
+	tx := wire.NewMsgTx(2)
+	tx.AddTxIn(&wire.TxIn{
+		PreviousOutPoint: wire.OutPoint{Index: wire.MaxPrevOutIndex},
+		SignatureScript:  sigScript,
+		Sequence:         wire.MaxTxInSequenceNum,
+	})
+	tx.AddTxOut(wire.NewTxOut(blockSubsidy, anyoneCanSpendScript))
+	tx.TxOut = append(tx.TxOut, c.pendingFunding...)
+
+	return tx
+}
+
+// MempoolTxs returns mempool transactions in order of arrival
+func (c *Chain) MempoolTxs() []*wire.MsgTx {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	txs := make([]*wire.MsgTx, len(c.mempoolOrder))
+	for i, h := range c.mempoolOrder {
+		txs[i] = c.mempool[h]
+	}
+	return txs
+}
+
+// sortedOutpoints returns outpoints sorted by hash and index, so that
+// iteration over outputs is deterministic
+func sortedOutpoints[T any](m map[wire.OutPoint]T) []wire.OutPoint {
+	ops := make([]wire.OutPoint, 0, len(m))
+	for op := range m {
+		ops = append(ops, op)
+	}
+
+	sort.Slice(ops, func(i, j int) bool {
+		if ops[i].Hash != ops[j].Hash {
+			return ops[i].Hash.String() < ops[j].Hash.String()
+		}
+		return ops[i].Index < ops[j].Index
+	})
+
+	return ops
+}
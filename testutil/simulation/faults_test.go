@@ -0,0 +1,50 @@
+//go:build faults
+
+package simulation_test
+
+import (
+	"testing"
+
+	"github.com/babylonlabs-io/btc-staker/testutil/simulation"
+	"github.com/babylonlabs-io/btc-staker/utils/faults"
+	"github.com/babylonlabs-io/btc-staker/walletcontroller"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBtcNodeTimeoutFailsSendOnce(t *testing.T) {
+	faults.Reset()
+	t.Cleanup(faults.Reset)
+
+	sim, err := simulation.New([]byte("seed"))
+	require.NoError(t, err)
+
+	addr, err := sim.Wallet.NewAddress(walletcontroller.AddressTypeSegwit)
+	require.NoError(t, err)
+	require.NoError(t, sim.Wallet.FundAddress(addr, btcutil.SatoshiPerBitcoin))
+	sim.Chain.MineBlocks(1)
+
+	pkScript, err := txscript.PayToAddrScript(addr)
+	require.NoError(t, err)
+
+	tx, err := sim.Wallet.CreateAndSignTx(
+		[]*wire.TxOut{wire.NewTxOut(100000, pkScript)},
+		btcutil.Amount(2000),
+		addr,
+		nil,
+	)
+	require.NoError(t, err)
+
+	faults.Arm(faults.BtcNodeTimeout, 1)
+
+	_, err = sim.Wallet.SendRawTransaction(tx, false)
+	require.ErrorIs(t, err, faults.ErrInjected)
+	require.Empty(t, sim.Chain.MempoolTxs())
+
+	_, err = sim.Wallet.SendRawTransaction(tx, false)
+	require.NoError(t, err)
+	require.Len(t, sim.Chain.MempoolTxs(), 1)
+	require.Equal(t, 1, faults.Triggered(faults.BtcNodeTimeout))
+}
@@ -0,0 +1,349 @@
+package simulation
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	notifier "github.com/lightningnetwork/lnd/chainntnfs"
+)
+
+type confRegistration struct {
+	// guards against sending confirmation twice, when block is mined while
+	// registering
+	mu       sync.Mutex
+	done     bool
+	txHash   chainhash.Hash
+	numConfs uint32
+	event    *notifier.ConfirmationEvent
+	// last number of confirmations sent as update
+	lastUpdate uint32
+}
+
+type spendRegistration struct {
+	mu       sync.Mutex
+	done     bool
+	outpoint wire.OutPoint
+	event    *notifier.SpendEvent
+}
+
+type epochRegistration struct {
+	mu sync.Mutex
+	// height of the last sent block, to not send the same block twice
+	lastHeight int32
+	epochs     chan *notifier.BlockEpoch
+	cancel     chan struct{}
+}
+
+// Notifier implements chainntnfs.ChainNotifier on top of simulated chain.
+// Notifications are delivered synchronously when block is mined, so after
+// MineBlocks returns every block epoch was already received by subscribers.
+type Notifier struct {
+	chain *Chain
+
+	mu        sync.Mutex
+	nextID    uint64
+	confs     map[uint64]*confRegistration
+	spends    map[uint64]*spendRegistration
+	epochs    map[uint64]*epochRegistration
+	started   bool
+	quit      chan struct{}
+	startOnce sync.Once
+	stopOnce  sync.Once
+}
+
+var _ notifier.ChainNotifier = (*Notifier)(nil)
+
+// NewNotifier creates notifier of blocks mined on given chain
+func NewNotifier(chain *Chain) *Notifier {
+	n := &Notifier{
+		chain:  chain,
+		confs:  make(map[uint64]*confRegistration),
+		spends: make(map[uint64]*spendRegistration),
+		epochs: make(map[uint64]*epochRegistration),
+		quit:   make(chan struct{}),
+	}
+
+	chain.subscribe(n.onBlock)
+
+	return n
+}
+
+func (n *Notifier) Start() error {
+	n.startOnce.Do(func() {
+		n.mu.Lock()
+		n.started = true
+		n.mu.Unlock()
+	})
+	return nil
+}
+
+func (n *Notifier) Started() bool {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return n.started
+}
+
+func (n *Notifier) Stop() error {
+	n.stopOnce.Do(func() {
+		close(n.quit)
+	})
+	return nil
+}
+
+func (n *Notifier) RegisterConfirmationsNtfn(
+	txid *chainhash.Hash,
+	_ []byte,
+	numConfs, _ uint32,
+	_ ...notifier.NotifierOption,
+) (*notifier.ConfirmationEvent, error) {
+	if txid == nil {
+		return nil, fmt.Errorf("simulation notifier supports only confirmations of transactions")
+	}
+
+	if numConfs == 0 {
+		return nil, fmt.Errorf("number of confirmations must be greater than 0")
+	}
+
+	n.mu.Lock()
+	id := n.nextID
+	n.nextID++
+
+	reg := &confRegistration{
+		txHash:   *txid,
+		numConfs: numConfs,
+	}
+	reg.event = notifier.NewConfirmationEvent(numConfs, func() {
+		n.mu.Lock()
+		delete(n.confs, id)
+		n.mu.Unlock()
+	})
+	n.confs[id] = reg
+	n.mu.Unlock()
+
+	// transaction could be confirmed already
+	_, tipHeight := n.chain.BestBlock()
+	if n.checkConf(reg, tipHeight) {
+		n.mu.Lock()
+		delete(n.confs, id)
+		n.mu.Unlock()
+	}
+
+	return reg.event, nil
+}
+
+// checkConf sends updates and confirmation of registered transaction, returns
+// true if registration is done
+func (n *Notifier) checkConf(reg *confRegistration, tipHeight int32) bool {
+	reg.mu.Lock()
+	defer reg.mu.Unlock()
+
+	if reg.done {
+		return true
+	}
+
+	block, height, index := n.chain.Confirmation(&reg.txHash)
+	if block == nil {
+		return false
+	}
+
+	confs := uint32(tipHeight-height) + 1
+	if confs > reg.numConfs {
+		confs = reg.numConfs
+	}
+
+	for c := reg.lastUpdate + 1; c <= confs; c++ {
+		select {
+		case reg.event.Updates <- reg.numConfs - c:
+		default:
+		}
+	}
+	reg.lastUpdate = confs
+
+	if confs < reg.numConfs {
+		return false
+	}
+
+	reg.done = true
+	blockHash := block.BlockHash()
+	reg.event.Confirmed <- &notifier.TxConfirmation{
+		BlockHash:   &blockHash,
+		BlockHeight: uint32(height),
+		TxIndex:     index,
+		Tx:          block.Transactions[index],
+		Block:       block,
+	}
+
+	return true
+}
+
+func (n *Notifier) RegisterSpendNtfn(
+	outpoint *wire.OutPoint,
+	_ []byte,
+	_ uint32,
+) (*notifier.SpendEvent, error) {
+	if outpoint == nil {
+		return nil, fmt.Errorf("simulation notifier supports only spends of outpoints")
+	}
+
+	n.mu.Lock()
+	id := n.nextID
+	n.nextID++
+
+	reg := &spendRegistration{
+		outpoint: *outpoint,
+		event: &notifier.SpendEvent{
+			Spend: make(chan *notifier.SpendDetail, 1),
+			Reorg: make(chan struct{}, 1),
+			Done:  make(chan struct{}, 1),
+			Cancel: func() {
+				n.mu.Lock()
+				delete(n.spends, id)
+				n.mu.Unlock()
+			},
+		},
+	}
+	n.spends[id] = reg
+	n.mu.Unlock()
+
+	if n.checkSpend(reg) {
+		n.mu.Lock()
+		delete(n.spends, id)
+		n.mu.Unlock()
+	}
+
+	return reg.event, nil
+}
+
+// checkSpend sends spend details if registered output is spent by confirmed
+// transaction, returns true if registration is done
+func (n *Notifier) checkSpend(reg *spendRegistration) bool {
+	reg.mu.Lock()
+	defer reg.mu.Unlock()
+
+	if reg.done {
+		return true
+	}
+
+	spender, height, inputIdx := n.chain.Spender(reg.outpoint)
+	if spender == nil {
+		return false
+	}
+
+	reg.done = true
+	spenderHash := spender.TxHash()
+	reg.event.Spend <- &notifier.SpendDetail{
+		SpentOutPoint:     &reg.outpoint,
+		SpenderTxHash:     &spenderHash,
+		SpendingTx:        spender,
+		SpenderInputIndex: inputIdx,
+		SpendingHeight:    height,
+	}
+
+	return true
+}
+
+func (n *Notifier) RegisterBlockEpochNtfn(bestBlock *notifier.BlockEpoch) (*notifier.BlockEpochEvent, error) {
+	reg := &epochRegistration{
+		lastHeight: -1,
+		// buffered, so that the best block can be sent before caller starts
+		// reading
+		epochs: make(chan *notifier.BlockEpoch, 1),
+		cancel: make(chan struct{}),
+	}
+
+	if bestBlock != nil {
+		reg.lastHeight = bestBlock.Height
+	}
+
+	n.mu.Lock()
+	id := n.nextID
+	n.nextID++
+	n.epochs[id] = reg
+	n.mu.Unlock()
+
+	var cancelOnce sync.Once
+	event := &notifier.BlockEpochEvent{
+		Epochs: reg.epochs,
+		Cancel: func() {
+			cancelOnce.Do(func() {
+				n.mu.Lock()
+				delete(n.epochs, id)
+				n.mu.Unlock()
+				close(reg.cancel)
+			})
+		},
+	}
+
+	// as lnd notifiers, send the best block immediately if caller does not
+	// know it
+	if bestBlock == nil {
+		_, tipHeight := n.chain.BestBlock()
+		block, err := n.chain.BlockByHeight(tipHeight)
+		if err != nil {
+			return nil, err
+		}
+		n.sendEpoch(reg, block, tipHeight)
+	}
+
+	return event, nil
+}
+
+func (n *Notifier) sendEpoch(reg *epochRegistration, block *wire.MsgBlock, height int32) {
+	reg.mu.Lock()
+	defer reg.mu.Unlock()
+
+	if height <= reg.lastHeight {
+		return
+	}
+	reg.lastHeight = height
+
+	blockHash := block.BlockHash()
+	select {
+	case reg.epochs <- &notifier.BlockEpoch{
+		Hash:        &blockHash,
+		Height:      height,
+		BlockHeader: &block.Header,
+	}:
+	case <-reg.cancel:
+	case <-n.quit:
+	}
+}
+
+func (n *Notifier) onBlock(block *wire.MsgBlock, height int32) {
+	n.mu.Lock()
+	confs := make(map[uint64]*confRegistration, len(n.confs))
+	for id, reg := range n.confs {
+		confs[id] = reg
+	}
+	spends := make(map[uint64]*spendRegistration, len(n.spends))
+	for id, reg := range n.spends {
+		spends[id] = reg
+	}
+	epochs := make([]*epochRegistration, 0, len(n.epochs))
+	for _, reg := range n.epochs {
+		epochs = append(epochs, reg)
+	}
+	n.mu.Unlock()
+
+	for id, reg := range confs {
+		if n.checkConf(reg, height) {
+			n.mu.Lock()
+			delete(n.confs, id)
+			n.mu.Unlock()
+		}
+	}
+
+	for id, reg := range spends {
+		if n.checkSpend(reg) {
+			n.mu.Lock()
+			delete(n.spends, id)
+			n.mu.Unlock()
+		}
+	}
+
+	for _, reg := range epochs {
+		n.sendEpoch(reg, block, height)
+	}
+}
@@ -0,0 +1,92 @@
+// Package simulation provides deterministic in-memory backends of the staker
+// app: bitcoin chain with wallet and chain notifier, and babylon node. Blocks
+// are produced only when test asks for them and all keys are derived from a
+// seed, so delegation lifecycle can be tested, and fuzzed, without docker.
+package simulation
+
+import (
+	"time"
+
+	"github.com/babylonlabs-io/btc-staker/staker"
+	"github.com/babylonlabs-io/btc-staker/stakercfg"
+	"github.com/babylonlabs-io/btc-staker/types"
+	"github.com/babylonlabs-io/btc-staker/utils"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/lightningnetwork/lnd/kvdb"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// checkInterval is interval in which staker polls simulated babylon
+	checkInterval = 100 * time.Millisecond
+
+	// simulationFeeRate is fee rate in sat/vbyte used by static fee estimator
+	simulationFeeRate = 2
+)
+
+// Simulation groups simulated backends sharing the same chain
+type Simulation struct {
+	Chain    *Chain
+	Notifier *Notifier
+	Wallet   *Wallet
+	Babylon  *Babylon
+}
+
+// New creates simulation on regtest network with keys derived from given
+// seed
+func New(seed []byte) (*Simulation, error) {
+	return NewWithParams(&chaincfg.RegressionNetParams, seed)
+}
+
+// NewWithParams creates simulation on given network with keys derived from
+// given seed
+func NewWithParams(params *chaincfg.Params, seed []byte) (*Simulation, error) {
+	chain := NewChain(params)
+
+	babylon, err := NewBabylon(chain, seed)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Simulation{
+		Chain:    chain,
+		Notifier: NewNotifier(chain),
+		Wallet:   NewWallet(chain, seed),
+		Babylon:  babylon,
+	}, nil
+}
+
+// Config returns staker config matching the simulation, with check intervals
+// short enough for tests
+func (s *Simulation) Config() *stakercfg.Config {
+	cfg := stakercfg.DefaultConfig()
+
+	cfg.ActiveNetParams = *s.Chain.Params()
+	cfg.BtcNodeBackendConfig.FeeMode = "static"
+	cfg.BtcNodeBackendConfig.EstimationMode = types.StaticFeeEstimation
+	cfg.BtcNodeBackendConfig.MinFeeRate = utils.FeeRateFloor(s.Chain.Params())
+	cfg.BtcNodeBackendConfig.MaxFeeRate = simulationFeeRate
+	cfg.StakerConfig.BabylonStallingInterval = checkInterval
+	cfg.StakerConfig.UnbondingTxCheckInterval = checkInterval
+	cfg.StakerConfig.CheckActiveInterval = checkInterval
+	cfg.StakerConfig.StatusRefreshInterval = checkInterval
+
+	return &cfg
+}
+
+// NewApp creates staker app using simulated backends. Transactions are
+// tracked in given database.
+func (s *Simulation) NewApp(
+	cfg *stakercfg.Config,
+	db kvdb.Backend,
+	logger *logrus.Logger,
+) (*staker.App, error) {
+	return staker.New(
+		staker.WithConfig(cfg),
+		staker.WithLogger(logger),
+		staker.WithDB(db),
+		staker.WithWalletController(s.Wallet),
+		staker.WithBabylonClient(s.Babylon),
+		staker.WithNotifier(s.Notifier),
+	)
+}
@@ -0,0 +1,166 @@
+package simulation_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/babylonlabs-io/btc-staker/testutil/simulation"
+	"github.com/babylonlabs-io/btc-staker/walletcontroller"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransactionConfirmationIsNotified(t *testing.T) {
+	sim, err := simulation.New([]byte("seed"))
+	require.NoError(t, err)
+
+	addr, err := sim.Wallet.NewAddress(walletcontroller.AddressTypeTaproot)
+	require.NoError(t, err)
+	require.NoError(t, sim.Wallet.FundAddress(addr, btcutil.SatoshiPerBitcoin))
+	sim.Chain.MineBlocks(1)
+
+	pkScript, err := txscript.PayToAddrScript(addr)
+	require.NoError(t, err)
+
+	tx, err := sim.Wallet.CreateAndSignTx(
+		[]*wire.TxOut{wire.NewTxOut(100000, pkScript)},
+		btcutil.Amount(2000),
+		addr,
+		nil,
+	)
+	require.NoError(t, err)
+
+	txHash, err := sim.Wallet.SendRawTransaction(tx, false)
+	require.NoError(t, err)
+
+	confEvent, err := sim.Notifier.RegisterConfirmationsNtfn(txHash, pkScript, 2, 0)
+	require.NoError(t, err)
+
+	_, status, err := sim.Wallet.TxDetails(txHash, pkScript)
+	require.NoError(t, err)
+	require.Equal(t, walletcontroller.TxInMemPool, status)
+
+	sim.Chain.MineBlocks(1)
+	require.Empty(t, confEvent.Confirmed)
+
+	sim.Chain.MineBlocks(1)
+	conf := <-confEvent.Confirmed
+	require.Equal(t, uint32(2), conf.BlockHeight)
+
+	spent, err := sim.Wallet.OutputSpent(txHash, 0)
+	require.NoError(t, err)
+	require.False(t, spent)
+}
+
+func TestSameSeedGivesSameChain(t *testing.T) {
+	mine := func() *wire.MsgBlock {
+		sim, err := simulation.New([]byte("seed"))
+		require.NoError(t, err)
+
+		addr, err := sim.Wallet.NewAddress(walletcontroller.AddressTypeSegwit)
+		require.NoError(t, err)
+		require.NoError(t, sim.Wallet.FundAddress(addr, btcutil.SatoshiPerBitcoin))
+
+		return sim.Chain.MineBlocks(1)[0]
+	}
+
+	require.Equal(t, mine().BlockHash(), mine().BlockHash())
+}
+
+// fundedOutput funds new wallet address and returns the funding output
+func fundedOutput(t *testing.T, sim *simulation.Simulation, amount btcutil.Amount) (wire.OutPoint, []byte) {
+	addr, err := sim.Wallet.NewAddress(walletcontroller.AddressTypeTaproot)
+	require.NoError(t, err)
+	require.NoError(t, sim.Wallet.FundAddress(addr, amount))
+	block := sim.Chain.MineBlocks(1)[0]
+
+	pkScript, err := txscript.PayToAddrScript(addr)
+	require.NoError(t, err)
+
+	coinbase := block.Transactions[0]
+	for i, out := range coinbase.TxOut {
+		if bytes.Equal(out.PkScript, pkScript) {
+			return wire.OutPoint{Hash: coinbase.TxHash(), Index: uint32(i)}, pkScript
+		}
+	}
+
+	t.Fatalf("funding output not found")
+	return wire.OutPoint{}, nil
+}
+
+func spendTx(outpoint wire.OutPoint, value int64, pkScript []byte) *wire.MsgTx {
+	tx := wire.NewMsgTx(2)
+	tx.AddTxIn(wire.NewTxIn(&outpoint, nil, nil))
+	tx.AddTxOut(wire.NewTxOut(value, pkScript))
+	return tx
+}
+
+func TestHigherFeeTransactionReplacesConflicts(t *testing.T) {
+	sim, err := simulation.New([]byte("seed"))
+	require.NoError(t, err)
+
+	funding, pkScript := fundedOutput(t, sim, 100000)
+
+	parent := spendTx(funding, 99000, pkScript)
+	parentHash, err := sim.Chain.SendTransaction(parent)
+	require.NoError(t, err)
+
+	child := spendTx(wire.OutPoint{Hash: *parentHash, Index: 0}, 98000, pkScript)
+	childHash, err := sim.Chain.SendTransaction(child)
+	require.NoError(t, err)
+
+	// fee of parent and child together is 2000
+	_, err = sim.Chain.SendTransaction(spendTx(funding, 98000, pkScript))
+	require.ErrorContains(t, err, "does not exceed fee")
+	require.True(t, sim.Chain.InMempool(parentHash))
+	require.True(t, sim.Chain.InMempool(childHash))
+
+	replacement := spendTx(funding, 97000, pkScript)
+	replacementHash, err := sim.Chain.SendTransaction(replacement)
+	require.NoError(t, err)
+
+	require.False(t, sim.Chain.InMempool(parentHash))
+	require.False(t, sim.Chain.InMempool(childHash))
+	require.Equal(t, replacementHash, sim.Chain.MempoolSpender(funding))
+
+	fee, ok := sim.Chain.MempoolFee(replacementHash)
+	require.True(t, ok)
+	require.Equal(t, btcutil.Amount(3000), fee)
+
+	sim.Chain.MineBlocks(1)
+	require.Nil(t, sim.Chain.MempoolSpender(funding))
+
+	_, err = sim.Chain.SendTransaction(spendTx(funding, 50000, pkScript))
+	require.ErrorContains(t, err, "already spent in block chain")
+}
+
+func TestSpendIsNotifiedOnceConfirmed(t *testing.T) {
+	sim, err := simulation.New([]byte("seed"))
+	require.NoError(t, err)
+
+	funding, pkScript := fundedOutput(t, sim, 100000)
+
+	spendEvent, err := sim.Notifier.RegisterSpendNtfn(&funding, pkScript, 0)
+	require.NoError(t, err)
+
+	spender := spendTx(funding, 99000, pkScript)
+	spenderHash, err := sim.Chain.SendTransaction(spender)
+	require.NoError(t, err)
+	require.Empty(t, spendEvent.Spend)
+
+	_, height := sim.Chain.BestBlock()
+	sim.Chain.MineBlocks(1)
+
+	spend := <-spendEvent.Spend
+	require.Equal(t, spenderHash, spend.SpenderTxHash)
+	require.Equal(t, funding, *spend.SpentOutPoint)
+	require.Equal(t, uint32(0), spend.SpenderInputIndex)
+	require.Equal(t, height+1, spend.SpendingHeight)
+
+	// output spent before registration is notified immediately
+	lateEvent, err := sim.Notifier.RegisterSpendNtfn(&funding, pkScript, 0)
+	require.NoError(t, err)
+	require.Equal(t, spenderHash, (<-lateEvent.Spend).SpenderTxHash)
+}
@@ -0,0 +1,716 @@
+package simulation
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	staking "github.com/babylonlabs-io/babylon/v4/btcstaking"
+	"github.com/babylonlabs-io/babylon/v4/crypto/bip322"
+	"github.com/babylonlabs-io/btc-staker/utils"
+	"github.com/babylonlabs-io/btc-staker/utils/faults"
+	"github.com/babylonlabs-io/btc-staker/walletcontroller"
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+	"github.com/btcsuite/btcd/btcjson"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/mempool"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/btcsuite/btcwallet/wallet/txauthor"
+	notifier "github.com/lightningnetwork/lnd/chainntnfs"
+)
+
+// Wallet implements walletcontroller.WalletController on top of simulated
+// chain. Keys are derived deterministically from the seed, so the same seed
+// always produces the same addresses. Wallet is always unlocked.
+type Wallet struct {
+	chain *Chain
+	seed  []byte
+
+	mu       sync.Mutex
+	keyIndex uint32
+	// pk script -> private key
+	keys map[string]*btcec.PrivateKey
+}
+
+var _ walletcontroller.WalletController = (*Wallet)(nil)
+
+// NewWallet creates wallet with keys derived from given seed
+func NewWallet(chain *Chain, seed []byte) *Wallet {
+	return &Wallet{
+		chain: chain,
+		seed:  seed,
+		keys:  make(map[string]*btcec.PrivateKey),
+	}
+}
+
+// nextKey returns next private key derived from the seed
+func (w *Wallet) nextKey() *btcec.PrivateKey {
+	var idx [4]byte
+	binary.BigEndian.PutUint32(idx[:], w.keyIndex)
+	w.keyIndex++
+
+	keyBytes := sha256.Sum256(append(append([]byte{}, w.seed...), idx[:]...))
+	privKey, _ := btcec.PrivKeyFromBytes(keyBytes[:])
+	return privKey
+}
+
+// addKey makes wallet control p2wpkh and p2tr addresses of the key
+func (w *Wallet) addKey(privKey *btcec.PrivateKey) error {
+	segwit, err := segwitAddress(privKey.PubKey(), w.chain.Params())
+	if err != nil {
+		return err
+	}
+
+	taproot, err := taprootAddress(privKey.PubKey(), w.chain.Params())
+	if err != nil {
+		return err
+	}
+
+	for _, addr := range []btcutil.Address{segwit, taproot} {
+		pkScript, err := txscript.PayToAddrScript(addr)
+		if err != nil {
+			return err
+		}
+		w.keys[string(pkScript)] = privKey
+	}
+
+	return nil
+}
+
+// FundAddress funds address with given amount in the coinbase transaction of
+// the next mined block
+func (w *Wallet) FundAddress(addr btcutil.Address, amount btcutil.Amount) error {
+	pkScript, err := txscript.PayToAddrScript(addr)
+	if err != nil {
+		return err
+	}
+
+	w.chain.Fund(pkScript, amount)
+	return nil
+}
+
+func (w *Wallet) privateKey(pkScript []byte) (*btcec.PrivateKey, bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	key, ok := w.keys[string(pkScript)]
+	return key, ok
+}
+
+func (w *Wallet) addressKey(address btcutil.Address) (*btcec.PrivateKey, error) {
+	pkScript, err := txscript.PayToAddrScript(address)
+	if err != nil {
+		return nil, err
+	}
+
+	key, ok := w.privateKey(pkScript)
+	if !ok {
+		return nil, fmt.Errorf("address %s is not under wallet control", address)
+	}
+
+	return key, nil
+}
+
+func (w *Wallet) ownedScripts() map[string]struct{} {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	scripts := make(map[string]struct{}, len(w.keys))
+	for s := range w.keys {
+		scripts[s] = struct{}{}
+	}
+	return scripts
+}
+
+func (w *Wallet) UnlockWallet(_ int64) error {
+	return nil
+}
+
+func (w *Wallet) SetPassphrase(_ string, _ time.Duration) error {
+	return nil
+}
+
+func (w *Wallet) PassphraseAvailable() error {
+	return nil
+}
+
+func (w *Wallet) AddressPublicKey(address btcutil.Address) (*btcec.PublicKey, error) {
+	key, err := w.addressKey(address)
+	if err != nil {
+		return nil, err
+	}
+
+	return key.PubKey(), nil
+}
+
+func (w *Wallet) ImportPrivKey(privKeyWIF *btcutil.WIF) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.addKey(privKeyWIF.PrivKey)
+}
+
+func (w *Wallet) NetworkName() string {
+	return w.chain.Params().Name
+}
+
+func (w *Wallet) NewAddress(addressType walletcontroller.AddressType) (btcutil.Address, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	privKey := w.nextKey()
+	if err := w.addKey(privKey); err != nil {
+		return nil, err
+	}
+
+	switch addressType {
+	case walletcontroller.AddressTypeSegwit:
+		return segwitAddress(privKey.PubKey(), w.chain.Params())
+	case walletcontroller.AddressTypeTaproot:
+		return taprootAddress(privKey.PubKey(), w.chain.Params())
+	default:
+		return nil, fmt.Errorf("unsupported address type %s", addressType)
+	}
+}
+
+// spendableUtxos returns confirmed unspent wallet outputs sorted by amount
+// from highest to lowest, ties are broken by outpoint to keep order
+// deterministic
+func (w *Wallet) spendableUtxos(useUtxoFn walletcontroller.UseUtxoFn) []walletcontroller.Utxo {
+	unspent := w.chain.UnspentOutputs(w.ownedScripts())
+
+	utxos := make([]walletcontroller.Utxo, 0, len(unspent))
+	for _, op := range sortedOutpoints(unspent) {
+		out := unspent[op]
+		utxo := walletcontroller.Utxo{
+			Amount:   btcutil.Amount(out.Value),
+			OutPoint: op,
+			PkScript: out.PkScript,
+			Address:  scriptAddress(out.PkScript, w.chain.Params()),
+		}
+
+		if useUtxoFn != nil && !useUtxoFn(utxo) {
+			continue
+		}
+
+		utxos = append(utxos, utxo)
+	}
+
+	sort.SliceStable(utxos, func(i, j int) bool {
+		return utxos[i].Amount > utxos[j].Amount
+	})
+
+	return utxos
+}
+
+func (w *Wallet) CreateTransaction(
+	outputs []*wire.TxOut,
+	feeRatePerKb btcutil.Amount,
+	changeAddress btcutil.Address,
+	usedUtxoFilter walletcontroller.UseUtxoFn,
+) (*wire.MsgTx, error) {
+	return w.buildTx(w.spendableUtxos(usedUtxoFilter), outputs, feeRatePerKb, changeAddress)
+}
+
+func (w *Wallet) CreateTransactionWithInputs(
+	requiredInputs []wire.OutPoint,
+	inputsCount int,
+	outputs []*wire.TxOut,
+	feeRatePerKb btcutil.Amount,
+	changeAddress btcutil.Address,
+	useUtxoFn walletcontroller.UseUtxoFn,
+) (*wire.MsgTx, error) {
+	if len(requiredInputs) > inputsCount {
+		return nil, fmt.Errorf("number of required inputs (%d) exceeds desired input count (%d)", len(requiredInputs), inputsCount)
+	}
+
+	required := make(map[wire.OutPoint]struct{}, len(requiredInputs))
+	orderedUtxos := make([]walletcontroller.Utxo, 0, inputsCount)
+	for _, op := range requiredInputs {
+		out, ok := w.chain.Output(op)
+		if !ok {
+			return nil, fmt.Errorf("required input %s not found", op)
+		}
+
+		utxo := walletcontroller.Utxo{
+			Amount:   btcutil.Amount(out.Value),
+			OutPoint: op,
+			PkScript: out.PkScript,
+		}
+
+		if useUtxoFn != nil && !useUtxoFn(utxo) {
+			return nil, fmt.Errorf("required input %s is filtered out by useUtxoFn", op)
+		}
+
+		required[op] = struct{}{}
+		orderedUtxos = append(orderedUtxos, utxo)
+	}
+
+	for _, utxo := range w.spendableUtxos(useUtxoFn) {
+		if len(orderedUtxos) == inputsCount {
+			break
+		}
+
+		if _, ok := required[utxo.OutPoint]; ok {
+			continue
+		}
+
+		orderedUtxos = append(orderedUtxos, utxo)
+	}
+
+	if len(orderedUtxos) < inputsCount {
+		return nil, fmt.Errorf("not enough UTXOs available: need %d inputs, only %d available", inputsCount, len(orderedUtxos))
+	}
+
+	tx, err := w.buildTx(orderedUtxos, outputs, feeRatePerKb, changeAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(tx.TxIn) != inputsCount {
+		return nil, fmt.Errorf("transaction must have exactly %d inputs, got %d", inputsCount, len(tx.TxIn))
+	}
+
+	return tx, nil
+}
+
+func (w *Wallet) buildTx(
+	utxos []walletcontroller.Utxo,
+	outputs []*wire.TxOut,
+	feeRatePerKb btcutil.Amount,
+	changeAddress btcutil.Address,
+) (*wire.MsgTx, error) {
+	if len(utxos) == 0 {
+		return nil, fmt.Errorf("there must be at least 1 usable UTXO to build transaction")
+	}
+
+	changeScript, err := txscript.PayToAddrScript(changeAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	inputSource := func(target btcutil.Amount) (btcutil.Amount, []*wire.TxIn, []btcutil.Amount, [][]byte, error) {
+		var (
+			total  btcutil.Amount
+			inputs []*wire.TxIn
+			values []btcutil.Amount
+			script [][]byte
+		)
+		for _, u := range utxos {
+			if total >= target {
+				break
+			}
+			total += u.Amount
+			inputs = append(inputs, wire.NewTxIn(&u.OutPoint, nil, nil))
+			values = append(values, u.Amount)
+			script = append(script, u.PkScript)
+		}
+		return total, inputs, values, script, nil
+	}
+
+	authoredTx, err := txauthor.NewUnsignedTransaction(
+		outputs,
+		feeRatePerKb,
+		inputSource,
+		&txauthor.ChangeSource{
+			NewScript: func() ([]byte, error) {
+				return changeScript, nil
+			},
+			ScriptSize: len(changeScript),
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := utils.CheckTransaction(authoredTx.Tx); err != nil {
+		return nil, fmt.Errorf("transaction is not standard: %w", err)
+	}
+
+	return authoredTx.Tx, nil
+}
+
+// SignRawTransaction signs all inputs spending p2wpkh and p2tr outputs
+// controlled by the wallet. Inputs which already have witness are left
+// untouched.
+func (w *Wallet) SignRawTransaction(tx *wire.MsgTx) (*wire.MsgTx, bool, error) {
+	signed := tx.Copy()
+
+	prevOuts := make(map[wire.OutPoint]*wire.TxOut, len(signed.TxIn))
+	for _, in := range signed.TxIn {
+		out, ok := w.chain.Output(in.PreviousOutPoint)
+		if !ok {
+			return nil, false, fmt.Errorf("input %s not found", in.PreviousOutPoint)
+		}
+		prevOuts[in.PreviousOutPoint] = out
+	}
+
+	fullySigned := true
+	for i, in := range signed.TxIn {
+		if len(in.Witness) > 0 {
+			continue
+		}
+
+		witness, err := w.signInput(signed, i, prevOuts)
+		if err != nil {
+			return nil, false, err
+		}
+
+		if witness == nil {
+			fullySigned = false
+			continue
+		}
+
+		in.Witness = witness
+	}
+
+	return signed, fullySigned, nil
+}
+
+// signInput returns witness of input spending output controlled by the
+// wallet, nil if wallet does not control spent output
+func (w *Wallet) signInput(tx *wire.MsgTx, idx int, prevOuts map[wire.OutPoint]*wire.TxOut) (wire.TxWitness, error) {
+	prevOut := prevOuts[tx.TxIn[idx].PreviousOutPoint]
+
+	key, ok := w.privateKey(prevOut.PkScript)
+	if !ok {
+		return nil, nil
+	}
+
+	sigHashes := txscript.NewTxSigHashes(tx, txscript.NewMultiPrevOutFetcher(prevOuts))
+
+	switch {
+	case txscript.IsPayToWitnessPubKeyHash(prevOut.PkScript):
+		return txscript.WitnessSignature(
+			tx, sigHashes, idx, prevOut.Value, prevOut.PkScript, txscript.SigHashAll, key, true,
+		)
+	case txscript.IsPayToTaproot(prevOut.PkScript):
+		return txscript.TaprootWitnessSignature(
+			tx, sigHashes, idx, prevOut.Value, prevOut.PkScript, txscript.SigHashDefault, key,
+		)
+	default:
+		return nil, nil
+	}
+}
+
+func (w *Wallet) CreateAndSignTx(
+	outputs []*wire.TxOut,
+	feeRatePerKb btcutil.Amount,
+	changeAddress btcutil.Address,
+	usedUtxoFilter walletcontroller.UseUtxoFn,
+) (*wire.MsgTx, error) {
+	tx, err := w.CreateTransaction(outputs, feeRatePerKb, changeAddress, usedUtxoFilter)
+	if err != nil {
+		return nil, err
+	}
+
+	signed, fullySigned, err := w.SignRawTransaction(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	if !fullySigned {
+		return nil, fmt.Errorf("not all transactions inputs could be signed")
+	}
+
+	return signed, nil
+}
+
+func (w *Wallet) SendRawTransaction(tx *wire.MsgTx, _ bool) (*chainhash.Hash, error) {
+	if err := faults.Inject(faults.BtcNodeTimeout); err != nil {
+		return nil, err
+	}
+
+	return w.chain.SendTransaction(tx)
+}
+
+func (w *Wallet) ListOutputs(_ bool) ([]walletcontroller.Utxo, error) {
+	return w.spendableUtxos(nil), nil
+}
+
+func (w *Wallet) TxDetails(txHash *chainhash.Hash, _ []byte) (*notifier.TxConfirmation, walletcontroller.TxStatus, error) {
+	if err := faults.Inject(faults.BtcNodeTimeout); err != nil {
+		return nil, walletcontroller.TxNotFound, err
+	}
+
+	block, height, index := w.chain.Confirmation(txHash)
+	if block != nil {
+		blockHash := block.BlockHash()
+		return &notifier.TxConfirmation{
+			BlockHash:   &blockHash,
+			BlockHeight: uint32(height),
+			TxIndex:     index,
+			Tx:          block.Transactions[index],
+			Block:       block,
+		}, walletcontroller.TxInChain, nil
+	}
+
+	if w.chain.InMempool(txHash) {
+		return nil, walletcontroller.TxInMemPool, nil
+	}
+
+	return nil, walletcontroller.TxNotFound, nil
+}
+
+func (w *Wallet) Tx(txHash *chainhash.Hash) (*btcutil.Tx, error) {
+	tx, err := w.chain.Tx(txHash)
+	if err != nil {
+		return nil, err
+	}
+
+	return btcutil.NewTx(tx), nil
+}
+
+func (w *Wallet) Txs(txHashes []chainhash.Hash) ([]*btcutil.Tx, error) {
+	txs := make([]*btcutil.Tx, len(txHashes))
+	for i := range txHashes {
+		tx, err := w.Tx(&txHashes[i])
+		if err != nil {
+			return nil, err
+		}
+		txs[i] = tx
+	}
+
+	return txs, nil
+}
+
+func (w *Wallet) TxVerbose(txHash *chainhash.Hash) (*btcjson.TxRawResult, error) {
+	tx, err := w.chain.Tx(txHash)
+	if err != nil {
+		return nil, err
+	}
+
+	serialized, err := utils.SerializeBtcTransaction(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &btcjson.TxRawResult{
+		Hex:      hex.EncodeToString(serialized),
+		Txid:     txHash.String(),
+		Hash:     tx.WitnessHash().String(),
+		Size:     int32(tx.SerializeSize()),
+		Vsize:    int32(mempool.GetTxVirtualSize(btcutil.NewTx(tx))),
+		Version:  uint32(tx.Version),
+		LockTime: tx.LockTime,
+	}
+
+	block, height, _ := w.chain.Confirmation(txHash)
+	if block != nil {
+		_, tipHeight := w.chain.BestBlock()
+		result.BlockHash = block.BlockHash().String()
+		result.Confirmations = uint64(tipHeight-height) + 1
+		result.Time = block.Header.Timestamp.Unix()
+		result.Blocktime = block.Header.Timestamp.Unix()
+	}
+
+	return result, nil
+}
+
+func (w *Wallet) GetMempoolEntry(txHash string) (*btcjson.GetMempoolEntryResult, error) {
+	hash, err := chainhash.NewHashFromStr(txHash)
+	if err != nil {
+		return nil, err
+	}
+
+	fee, ok := w.chain.MempoolFee(hash)
+	if !ok {
+		return nil, fmt.Errorf("transaction %s not in mempool", txHash)
+	}
+
+	tx, err := w.chain.Tx(hash)
+	if err != nil {
+		return nil, err
+	}
+
+	tipHash, tipHeight := w.chain.BestBlock()
+	tip, _, err := w.chain.BlockByHash(tipHash)
+	if err != nil {
+		return nil, err
+	}
+
+	return &btcjson.GetMempoolEntryResult{
+		VSize:  int32(mempool.GetTxVirtualSize(btcutil.NewTx(tx))),
+		Fee:    fee.ToBTC(),
+		Time:   tip.Header.Timestamp.Unix(),
+		Height: int64(tipHeight),
+		Fees: btcjson.MempoolFees{
+			Base: fee.ToBTC(),
+		},
+	}, nil
+}
+
+func (w *Wallet) BlockHeaderVerbose(blockHash *chainhash.Hash) (*btcjson.GetBlockHeaderVerboseResult, error) {
+	block, height, err := w.chain.BlockByHash(blockHash)
+	if err != nil {
+		return nil, err
+	}
+
+	_, tipHeight := w.chain.BestBlock()
+
+	result := &btcjson.GetBlockHeaderVerboseResult{
+		Hash:          blockHash.String(),
+		Confirmations: int64(tipHeight-height) + 1,
+		Height:        height,
+		Version:       block.Header.Version,
+		MerkleRoot:    block.Header.MerkleRoot.String(),
+		Time:          block.Header.Timestamp.Unix(),
+		Nonce:         uint64(block.Header.Nonce),
+		Bits:          fmt.Sprintf("%08x", block.Header.Bits),
+		PreviousHash:  block.Header.PrevBlock.String(),
+	}
+
+	if next, err := w.chain.BlockByHeight(height + 1); err == nil {
+		result.NextHash = next.BlockHash().String()
+	}
+
+	return result, nil
+}
+
+func (w *Wallet) SignBip322Signature(msg []byte, address btcutil.Address) (wire.TxWitness, error) {
+	toSpend, err := bip322.GetToSpendTx(msg, address)
+	if err != nil {
+		return nil, fmt.Errorf("failed to bip322 to spend tx: %w", err)
+	}
+
+	toSign := bip322.GetToSignTx(toSpend)
+
+	witness, err := w.signInput(toSign, 0, map[wire.OutPoint]*wire.TxOut{
+		toSign.TxIn[0].PreviousOutPoint: toSpend.TxOut[0],
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create bip322 signature: %w", err)
+	}
+
+	if witness == nil {
+		return nil, fmt.Errorf("failed to create bip322 signature, address %s is not under wallet control", address)
+	}
+
+	return witness, nil
+}
+
+func (w *Wallet) SignOneInputTaprootSpendingTransaction(
+	request *walletcontroller.TaprootSigningRequest,
+) (*walletcontroller.TaprootSigningResult, error) {
+	key, err := w.addressKey(request.SignerAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	leaf := request.SpendDescription.ScriptLeaf
+	sig, err := staking.SignTxWithOneScriptSpendInputFromTapLeaf(
+		request.TxToSign,
+		request.FundingOutput,
+		key,
+		*leaf,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &walletcontroller.TaprootSigningResult{Signature: sig}
+
+	// as bitcoind, wallet finalizes spends of timelock leaves, which need only
+	// signature of the wallet key
+	if request.SpendDescription.ControlBlock != nil && isSingleKeyLeaf(leaf.Script, key) {
+		controlBlock, err := request.SpendDescription.ControlBlock.ToBytes()
+		if err != nil {
+			return nil, err
+		}
+		result.FullInputWitness = wire.TxWitness{sig.Serialize(), leaf.Script, controlBlock}
+	}
+
+	return result, nil
+}
+
+// isSingleKeyLeaf returns true if script can be satisfied by signature of the
+// key alone, e.g. timelock script
+func isSingleKeyLeaf(script []byte, key *btcec.PrivateKey) bool {
+	tokenizer := txscript.MakeScriptTokenizer(0, script)
+	keys := 0
+	for tokenizer.Next() {
+		if tokenizer.Opcode() == txscript.OP_DATA_32 {
+			if !bytes.Equal(tokenizer.Data(), schnorr.SerializePubKey(key.PubKey())) {
+				return false
+			}
+			keys++
+		}
+	}
+	return tokenizer.Err() == nil && keys == 1
+}
+
+func (w *Wallet) SignTwoInputTaprootSpendingTransaction(
+	request *walletcontroller.TwoInputTaprootSigningRequest,
+) (*walletcontroller.TaprootSigningResult, error) {
+	key, err := w.addressKey(request.SignerAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	sig, err := staking.SignTxForFirstScriptSpendWithTwoInputsFromTapLeaf(
+		request.TxToSign,
+		request.StakingOutput,
+		request.FundingOutput,
+		key,
+		*request.SpendDescription.ScriptLeaf,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &walletcontroller.TaprootSigningResult{Signature: sig}, nil
+}
+
+func (w *Wallet) OutputSpent(txHash *chainhash.Hash, outputIdx uint32) (bool, error) {
+	return w.chain.OutputSpent(wire.OutPoint{Hash: *txHash, Index: outputIdx}), nil
+}
+
+func (w *Wallet) OutputsSpent(outpoints []wire.OutPoint) ([]bool, error) {
+	spent := make([]bool, len(outpoints))
+	for i, op := range outpoints {
+		spent[i] = w.chain.OutputSpent(op)
+	}
+	return spent, nil
+}
+
+func (w *Wallet) MempoolSpenders(outpoints []wire.OutPoint) ([]*chainhash.Hash, error) {
+	spenders := make([]*chainhash.Hash, len(outpoints))
+	for i, op := range outpoints {
+		spenders[i] = w.chain.MempoolSpender(op)
+	}
+	return spenders, nil
+}
+
+func (w *Wallet) RelayFees() (*walletcontroller.RelayFees, error) {
+	return &walletcontroller.RelayFees{
+		MinRelayFeePerKb:         btcutil.Amount(1000),
+		IncrementalRelayFeePerKb: walletcontroller.DefaultIncrementalRelayFeePerKb,
+	}, nil
+}
+
+func segwitAddress(pubKey *btcec.PublicKey, params *chaincfg.Params) (btcutil.Address, error) {
+	return btcutil.NewAddressWitnessPubKeyHash(btcutil.Hash160(pubKey.SerializeCompressed()), params)
+}
+
+func taprootAddress(pubKey *btcec.PublicKey, params *chaincfg.Params) (btcutil.Address, error) {
+	addr, err := bip322.PubKeyToP2TrSpendAddress(pubKey, params)
+	if err != nil {
+		return nil, err
+	}
+	return addr, nil
+}
+
+func scriptAddress(pkScript []byte, params *chaincfg.Params) string {
+	_, addrs, _, err := txscript.ExtractPkScriptAddrs(pkScript, params)
+	if err != nil || len(addrs) == 0 {
+		return ""
+	}
+	return addrs[0].EncodeAddress()
+}
@@ -3,6 +3,7 @@ package utils
 import (
 	"fmt"
 
+	"github.com/btcsuite/btcd/btcutil"
 	"github.com/btcsuite/btcd/mempool"
 	"github.com/btcsuite/btcd/txscript"
 	"github.com/btcsuite/btcd/wire"
@@ -36,3 +37,18 @@ func CheckTransaction(tx *wire.MsgTx) error {
 
 	return nil
 }
+
+// CheckSpendOutput checks whether output remaining after paying fees is worth
+// sending, i.e. it is not dust and its value is at least minValue. Name describes
+// the output in returned error.
+func CheckSpendOutput(name string, out *wire.TxOut, minValue btcutil.Amount) error {
+	if out.Value <= 0 || mempool.IsDust(out, mempool.DefaultMinRelayTxFee) {
+		return fmt.Errorf("%s value %d sats below dust", name, out.Value)
+	}
+
+	if btcutil.Amount(out.Value) < minValue {
+		return fmt.Errorf("%s value %d sats below configured minimum %d sats", name, out.Value, int64(minValue))
+	}
+
+	return nil
+}
@@ -0,0 +1,42 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckSpendOutput(t *testing.T) {
+	t.Parallel()
+
+	// P2TR script, dust below 330 sats
+	p2trScript := append([]byte{0x51, 0x20}, make([]byte, 32)...)
+
+	tests := []struct {
+		name     string
+		value    int64
+		minValue btcutil.Amount
+		wantErr  string
+	}{
+		{name: "above minimum", value: 10_000, minValue: 5_000},
+		{name: "equal to minimum", value: 5_000, minValue: 5_000},
+		{name: "no minimum", value: 330},
+		{name: "below minimum", value: 4_999, minValue: 5_000, wantErr: "withdrawal value 4999 sats below configured minimum 5000 sats"},
+		{name: "dust", value: 329, wantErr: "withdrawal value 329 sats below dust"},
+		{name: "dust below minimum", value: 300, minValue: 5_000, wantErr: "below dust"},
+		{name: "negative", value: -1, wantErr: "below dust"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := CheckSpendOutput("withdrawal", wire.NewTxOut(tc.value, p2trScript), tc.minValue)
+			if tc.wantErr != "" {
+				require.ErrorContains(t, err, tc.wantErr)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,88 @@
+//go:build faults
+
+package faults
+
+import (
+	"fmt"
+	"sync"
+)
+
+type armedPoint struct {
+	// number of calls which will still fail, negative means every call fails
+	remaining int
+	err       error
+}
+
+var (
+	mu        sync.Mutex
+	armed     = make(map[Point]*armedPoint)
+	triggered = make(map[Point]int)
+)
+
+// Arm makes next times calls going through the point fail. Negative times
+// makes every call fail until the point is disarmed.
+func Arm(p Point, times int) {
+	ArmWithError(p, times, nil)
+}
+
+// ArmWithError works as Arm, but failing calls return given error instead of
+// ErrInjected
+func ArmWithError(p Point, times int, err error) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if times == 0 {
+		delete(armed, p)
+		return
+	}
+
+	armed[p] = &armedPoint{remaining: times, err: err}
+}
+
+// Disarm stops failing calls going through the point
+func Disarm(p Point) {
+	mu.Lock()
+	defer mu.Unlock()
+	delete(armed, p)
+}
+
+// Triggered returns how many times the point failed a call
+func Triggered(p Point) int {
+	mu.Lock()
+	defer mu.Unlock()
+	return triggered[p]
+}
+
+// Reset disarms all points and clears their counters
+func Reset() {
+	mu.Lock()
+	defer mu.Unlock()
+	armed = make(map[Point]*armedPoint)
+	triggered = make(map[Point]int)
+}
+
+// Inject returns error if the point is armed
+func Inject(p Point) error {
+	mu.Lock()
+	defer mu.Unlock()
+
+	a, ok := armed[p]
+	if !ok {
+		return nil
+	}
+
+	if a.remaining > 0 {
+		a.remaining--
+		if a.remaining == 0 {
+			delete(armed, p)
+		}
+	}
+
+	triggered[p]++
+
+	if a.err != nil {
+		return a.err
+	}
+
+	return fmt.Errorf("%s: %w", p, ErrInjected)
+}
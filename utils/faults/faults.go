@@ -0,0 +1,22 @@
+// Package faults provides fault injection points used in chaos testing. In
+// regular builds Inject never fails and compiles to a no-op. When built with
+// the faults build tag, tests can arm injection points to make the next calls
+// going through them fail.
+package faults
+
+import "errors"
+
+// Point identifies place in the code where fault can be injected
+type Point string
+
+const (
+	// BabylonBroadcast fails sending of delegation to babylon
+	BabylonBroadcast Point = "babylon-broadcast"
+	// BtcNodeTimeout fails btc node requests as if they timed out
+	BtcNodeTimeout Point = "btc-node-timeout"
+	// DBWrite fails write transactions of the staker database
+	DBWrite Point = "db-write"
+)
+
+// ErrInjected is returned, wrapped, by armed injection points
+var ErrInjected = errors.New("injected fault")
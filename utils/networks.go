@@ -0,0 +1,157 @@
+package utils
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+)
+
+const (
+	// TestNet4 is the magic of testnet4 network, it is not yet defined in
+	// btcd wire package
+	TestNet4 wire.BitcoinNet = 0x283f161c
+
+	// fee rate floors in sat/vbyte. Public networks with real fee market use
+	// 2 sat/vbyte, as size estimates of staker transactions are not exact and
+	// 1 sat/vbyte transactions risk being rejected. Test networks have mostly
+	// empty mempools, so minimum relay fee is enough there.
+	mainNetFeeFloor = 2
+	testNetFeeFloor = 1
+)
+
+// TestNet4Params defines the network parameters for the test Bitcoin network
+// (version 4). Only fields used by staker differ from testnet3, so the genesis
+// block carries just the header.
+var TestNet4Params = newTestNet4Params()
+
+func newTestNet4Params() chaincfg.Params {
+	genesisHash := mustHash("00000000da84f2bafbbc53dee25a72ae507ff4914b867c565be350b0da8bf043")
+	merkleRoot := mustHash("7aa0a7ae1e223414cb807e40cd57e667b718e42aaf9306db9102fe28912b7b4e")
+
+	params := chaincfg.TestNet3Params
+	params.Name = "testnet4"
+	params.Net = TestNet4
+	params.DefaultPort = "48333"
+	params.DNSSeeds = []chaincfg.DNSSeed{
+		{Host: "seed.testnet4.bitcoin.sprovoost.nl", HasFiltering: true},
+		{Host: "seed.testnet4.wiz.biz", HasFiltering: true},
+	}
+	params.GenesisBlock = &wire.MsgBlock{
+		Header: wire.BlockHeader{
+			Version:    1,
+			PrevBlock:  chainhash.Hash{},
+			MerkleRoot: *merkleRoot,
+			Timestamp:  time.Unix(1714777860, 0),
+			Bits:       0x1d00ffff,
+			Nonce:      393743547,
+		},
+	}
+	params.GenesisHash = genesisHash
+	params.Checkpoints = nil
+	// all soft forks are active from the first block
+	params.BIP0034Height = 1
+	params.BIP0065Height = 1
+	params.BIP0066Height = 1
+
+	return params
+}
+
+func mustHash(s string) *chainhash.Hash {
+	h, err := chainhash.NewHashFromStr(s)
+	if err != nil {
+		panic(err)
+	}
+	return h
+}
+
+// BitcoindRPCPort returns port on which bitcoind listens for rpc connections
+// by default on given network
+func BitcoindRPCPort(params *chaincfg.Params) string {
+	switch params.Net {
+	case wire.MainNet:
+		return "8332"
+	case wire.TestNet3:
+		return "18332"
+	case TestNet4:
+		return "48332"
+	case wire.TestNet:
+		// regtest
+		return "18443"
+	case wire.SimNet:
+		// bitcoind does not support simnet, use btcd port
+		return "18556"
+	default:
+		// signet, including custom ones which use different magic
+		return "38332"
+	}
+}
+
+// FeeRateFloor returns default minimum fee rate in sat/vbyte of transactions
+// sent on given network
+func FeeRateFloor(params *chaincfg.Params) int64 {
+	switch params.Net {
+	case wire.MainNet, wire.TestNet3:
+		return mainNetFeeFloor
+	default:
+		return testNetFeeFloor
+	}
+}
+
+// knownNetworks are networks whose addresses can be converted by
+// ConvertAddressToNet
+var knownNetworks = []*chaincfg.Params{
+	&chaincfg.MainNetParams,
+	&chaincfg.TestNet3Params,
+	&TestNet4Params,
+	&chaincfg.SigNetParams,
+	&chaincfg.RegressionNetParams,
+	&chaincfg.SimNetParams,
+}
+
+// DecodeAddressForNet decodes address and checks it belongs to given network.
+// btcutil.DecodeAddress alone accepts legacy addresses of every registered
+// network.
+func DecodeAddressForNet(address string, params *chaincfg.Params) (btcutil.Address, error) {
+	addr, err := btcutil.DecodeAddress(address, params)
+	if err != nil {
+		return nil, err
+	}
+
+	if !addr.IsForNet(params) {
+		return nil, fmt.Errorf("address %s is not for network %s", address, params.Name)
+	}
+
+	return addr, nil
+}
+
+// ConvertAddressToNet encodes address of any known network for given network.
+// Converted address pays to the same output script as the original one.
+func ConvertAddressToNet(address string, params *chaincfg.Params) (btcutil.Address, error) {
+	for _, known := range knownNetworks {
+		addr, err := DecodeAddressForNet(address, known)
+		if err != nil {
+			continue
+		}
+
+		switch addr.(type) {
+		case *btcutil.AddressPubKeyHash:
+			return btcutil.NewAddressPubKeyHash(addr.ScriptAddress(), params)
+		case *btcutil.AddressScriptHash:
+			return btcutil.NewAddressScriptHashFromHash(addr.ScriptAddress(), params)
+		case *btcutil.AddressWitnessPubKeyHash:
+			return btcutil.NewAddressWitnessPubKeyHash(addr.ScriptAddress(), params)
+		case *btcutil.AddressWitnessScriptHash:
+			return btcutil.NewAddressWitnessScriptHash(addr.ScriptAddress(), params)
+		case *btcutil.AddressTaproot:
+			return btcutil.NewAddressTaproot(addr.ScriptAddress(), params)
+		default:
+			return nil, fmt.Errorf("unsupported type %T of address %s", addr, address)
+		}
+	}
+
+	return nil, fmt.Errorf("address %s does not belong to any known network", address)
+}
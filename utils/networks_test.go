@@ -0,0 +1,72 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTestNet4Params(t *testing.T) {
+	t.Parallel()
+
+	params, err := GetBtcNetworkParams("testnet4")
+	require.NoError(t, err)
+	require.Equal(t, "testnet4", params.Name)
+	require.Equal(t, TestNet4, params.Net)
+	require.Equal(t, *params.GenesisHash, params.GenesisBlock.BlockHash())
+
+	// testnet4 shares address encoding with testnet3
+	addr, err := DecodeAddressForNet("tb1q9vza2e8x573nczrlzms0wvx3gsqjx7vaxwd45v", params)
+	require.NoError(t, err)
+	require.True(t, addr.IsForNet(&chaincfg.TestNet3Params))
+}
+
+func TestNetworkDefaults(t *testing.T) {
+	t.Parallel()
+
+	customSigNet := chaincfg.CustomSignetParams([]byte{0x51}, nil)
+
+	tests := []struct {
+		params       *chaincfg.Params
+		rpcPort      string
+		feeRateFloor int64
+	}{
+		{params: &chaincfg.MainNetParams, rpcPort: "8332", feeRateFloor: 2},
+		{params: &chaincfg.TestNet3Params, rpcPort: "18332", feeRateFloor: 2},
+		{params: &TestNet4Params, rpcPort: "48332", feeRateFloor: 1},
+		{params: &chaincfg.SigNetParams, rpcPort: "38332", feeRateFloor: 1},
+		{params: &customSigNet, rpcPort: "38332", feeRateFloor: 1},
+		{params: &chaincfg.RegressionNetParams, rpcPort: "18443", feeRateFloor: 1},
+	}
+
+	for _, tc := range tests {
+		require.Equal(t, tc.rpcPort, BitcoindRPCPort(tc.params), tc.params.Name)
+		require.Equal(t, tc.feeRateFloor, FeeRateFloor(tc.params), tc.params.Name)
+	}
+}
+
+func TestConvertAddressToNet(t *testing.T) {
+	t.Parallel()
+
+	const mainNetAddr = "bc1q9vza2e8x573nczrlzms0wvx3gsqjx7vavgkx0l"
+
+	_, err := DecodeAddressForNet(mainNetAddr, &chaincfg.RegressionNetParams)
+	require.ErrorContains(t, err, "is not for network regtest")
+
+	mainNet, err := DecodeAddressForNet(mainNetAddr, &chaincfg.MainNetParams)
+	require.NoError(t, err)
+
+	// converted address pays to the same witness program
+	addr, err := ConvertAddressToNet(mainNetAddr, &chaincfg.RegressionNetParams)
+	require.NoError(t, err)
+	require.True(t, addr.IsForNet(&chaincfg.RegressionNetParams))
+	require.Equal(t, mainNet.ScriptAddress(), addr.ScriptAddress())
+
+	addr, err = ConvertAddressToNet(addr.EncodeAddress(), &TestNet4Params)
+	require.NoError(t, err)
+	require.Equal(t, "tb1q9vza2e8x573nczrlzms0wvx3gsqjx7vaxwd45v", addr.EncodeAddress())
+
+	_, err = ConvertAddressToNet("not an address", &TestNet4Params)
+	require.ErrorContains(t, err, "does not belong to any known network")
+}
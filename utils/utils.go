@@ -12,6 +12,8 @@ func GetBtcNetworkParams(network string) (*chaincfg.Params, error) {
 	switch network {
 	case "testnet3":
 		return &chaincfg.TestNet3Params, nil
+	case "testnet4":
+		return &TestNet4Params, nil
 	case "mainnet":
 		return &chaincfg.MainNetParams, nil
 	case "regtest":
@@ -0,0 +1,49 @@
+package version
+
+import "runtime/debug"
+
+// APIVersion is version of the staker daemon JSON-RPC API. It is increased on
+// incompatible changes of existing endpoints, new endpoints and fields are
+// discovered through features instead.
+const APIVersion = 1
+
+// version and commit are set at build time:
+//
+//	-ldflags "-X github.com/babylonlabs-io/btc-staker/version.version=1.0.0
+//	 -X github.com/babylonlabs-io/btc-staker/version.commit=<hash>"
+var (
+	version = ""
+	commit  = ""
+)
+
+// Version returns semantic version of the binary, or "dev" if it was not
+// built from a tagged release
+func Version() string {
+	if version != "" {
+		return version
+	}
+
+	if info, ok := debug.ReadBuildInfo(); ok && info.Main.Version != "" && info.Main.Version != "(devel)" {
+		return info.Main.Version
+	}
+
+	return "dev"
+}
+
+// Commit returns hash of the commit the binary was built from, or empty string
+// if it is not known
+func Commit() string {
+	if commit != "" {
+		return commit
+	}
+
+	if info, ok := debug.ReadBuildInfo(); ok {
+		for _, s := range info.Settings {
+			if s.Key == "vcs.revision" {
+				return s.Value
+			}
+		}
+	}
+
+	return ""
+}
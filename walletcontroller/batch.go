@@ -0,0 +1,113 @@
+package walletcontroller
+
+import (
+	"fmt"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/rpcclient"
+	"github.com/btcsuite/btcd/wire"
+)
+
+// maxBatchSize limits number of requests sent in a single json-rpc batch, so
+// that single response does not grow too large
+const maxBatchSize = 100
+
+// newBatchClient creates client which queues requests and sends all of them in
+// a single json-rpc batch on Send
+func (w *RPCWalletController) newBatchClient() (*rpcclient.Client, error) {
+	// batch client must not share cookie cache with the main client
+	cfg := *w.connCfg
+	return rpcclient.NewBatch(&cfg)
+}
+
+// runBatches calls queue for each item, sending queued requests in batches of at
+// most maxBatchSize items, and then calls receive for each queued item
+func (w *RPCWalletController) runBatches(
+	count int,
+	queue func(c *rpcclient.Client, i int),
+	receive func(i int) error,
+) error {
+	for start := 0; start < count; start += maxBatchSize {
+		end := min(start+maxBatchSize, count)
+
+		c, err := w.newBatchClient()
+		if err != nil {
+			return fmt.Errorf("failed to create batch client: %w", err)
+		}
+
+		for i := start; i < end; i++ {
+			queue(c, i)
+		}
+
+		if err := c.Send(); err != nil {
+			c.Shutdown()
+			return fmt.Errorf("failed to send batch request: %w", err)
+		}
+
+		for i := start; i < end; i++ {
+			if err := receive(i); err != nil {
+				c.Shutdown()
+				return err
+			}
+		}
+
+		c.Shutdown()
+	}
+
+	return nil
+}
+
+// Txs returns transactions with given hashes, in the same order, using batched
+// requests
+func (w *RPCWalletController) Txs(txHashes []chainhash.Hash) ([]*btcutil.Tx, error) {
+	futures := make([]rpcclient.FutureGetRawTransactionResult, len(txHashes))
+	txs := make([]*btcutil.Tx, len(txHashes))
+
+	err := w.runBatches(
+		len(txHashes),
+		func(c *rpcclient.Client, i int) {
+			futures[i] = c.GetRawTransactionAsync(&txHashes[i])
+		},
+		func(i int) error {
+			tx, err := futures[i].Receive()
+			if err != nil {
+				return fmt.Errorf("failed to get transaction %s: %w", txHashes[i], err)
+			}
+			txs[i] = tx
+			return nil
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return txs, nil
+}
+
+// OutputsSpent returns whether given outputs are spent, in the same order, using
+// batched requests. Outputs spent by mempool transactions are considered spent.
+func (w *RPCWalletController) OutputsSpent(outpoints []wire.OutPoint) ([]bool, error) {
+	futures := make([]rpcclient.FutureGetTxOutResult, len(outpoints))
+	spent := make([]bool, len(outpoints))
+
+	err := w.runBatches(
+		len(outpoints),
+		func(c *rpcclient.Client, i int) {
+			futures[i] = c.GetTxOutAsync(&outpoints[i].Hash, outpoints[i].Index, true)
+		},
+		func(i int) error {
+			res, err := futures[i].Receive()
+			if err != nil {
+				return fmt.Errorf("failed to get output %s: %w", outpoints[i], err)
+			}
+			spent[i] = res == nil
+			return nil
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return spent, nil
+}
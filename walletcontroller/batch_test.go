@@ -0,0 +1,108 @@
+package walletcontroller
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcjson"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/stretchr/testify/require"
+)
+
+// stringParam returns string json-rpc param at given index
+func stringParam(t *testing.T, params []json.RawMessage, i int) string {
+	var s string
+	require.NoError(t, json.Unmarshal(params[i], &s))
+	return s
+}
+
+func TestTxsAreBatched(t *testing.T) {
+	t.Parallel()
+
+	const count = maxBatchSize + maxBatchSize/2
+	txs := make(map[string]string)
+	hashes := make([]chainhash.Hash, 0, count)
+	for i := 0; i < count; i++ {
+		tx := wire.NewMsgTx(2)
+		tx.AddTxIn(wire.NewTxIn(wire.NewOutPoint(&chainhash.Hash{}, uint32(i)), nil, nil))
+		tx.AddTxOut(wire.NewTxOut(int64(i), nil))
+		var buf bytes.Buffer
+		require.NoError(t, tx.Serialize(&buf))
+
+		hash := tx.TxHash()
+		txs[hash.String()] = hex.EncodeToString(buf.Bytes())
+		hashes = append(hashes, hash)
+	}
+
+	s := newTestRPCServer(t, func(method string, params []json.RawMessage) (interface{}, *btcjson.RPCError) {
+		require.Equal(t, "getrawtransaction", method)
+		txHex, ok := txs[stringParam(t, params, 0)]
+		if !ok {
+			return nil, btcjson.NewRPCError(btcjson.ErrRPCNoTxInfo, "No such mempool or blockchain transaction")
+		}
+		return txHex, nil
+	})
+	wc := newTestWalletController(t, s, "")
+
+	result, err := wc.Txs(hashes)
+	require.NoError(t, err)
+	require.Len(t, result, count)
+	for i, tx := range result {
+		require.Equal(t, hashes[i], *tx.Hash())
+	}
+
+	// requests are sent in two batches instead of one request per transaction
+	reqs := s.received()
+	require.Len(t, reqs, count)
+	for i, req := range reqs {
+		if i < maxBatchSize {
+			require.Equal(t, maxBatchSize, req.batchSize)
+		} else {
+			require.Equal(t, count-maxBatchSize, req.batchSize)
+		}
+	}
+
+	_, err = wc.Txs([]chainhash.Hash{hashes[0], {1}})
+	require.ErrorContains(t, err, "failed to get transaction "+chainhash.Hash{1}.String())
+}
+
+func TestOutputsSpent(t *testing.T) {
+	t.Parallel()
+
+	txHash := chainhash.Hash{1}
+	s := newTestRPCServer(t, func(method string, params []json.RawMessage) (interface{}, *btcjson.RPCError) {
+		require.Equal(t, "gettxout", method)
+		require.Equal(t, txHash.String(), stringParam(t, params, 0))
+
+		var index uint32
+		require.NoError(t, json.Unmarshal(params[1], &index))
+		// outputs spent in mempool are reported as spent
+		var includeMempool bool
+		require.NoError(t, json.Unmarshal(params[2], &includeMempool))
+		require.True(t, includeMempool)
+
+		// odd outputs are spent, bitcoind returns null for them
+		if index%2 == 1 {
+			return nil, nil
+		}
+		return &btcjson.GetTxOutResult{Value: 0.001}, nil
+	})
+	wc := newTestWalletController(t, s, "")
+
+	outpoints := []wire.OutPoint{
+		{Hash: txHash, Index: 0},
+		{Hash: txHash, Index: 1},
+		{Hash: txHash, Index: 2},
+		{Hash: txHash, Index: 3},
+	}
+	spent, err := wc.OutputsSpent(outpoints)
+	require.NoError(t, err)
+	require.Equal(t, []bool{false, true, false, true}, spent)
+
+	reqs := s.received()
+	require.Len(t, reqs, len(outpoints))
+	require.Equal(t, len(outpoints), reqs[0].batchSize)
+}
@@ -0,0 +1,130 @@
+package walletcontroller
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcjson"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/stretchr/testify/require"
+)
+
+// sendRawTxHandler answers requests of rpcclient sending raw transaction to
+// bitcoind, rejecting the transaction if reject is true
+func sendRawTxHandler(t *testing.T, tx *wire.MsgTx, reject bool) testRPCHandler {
+	return func(method string, params []json.RawMessage) (interface{}, *btcjson.RPCError) {
+		switch method {
+		case "getinfo":
+			return nil, btcjson.NewRPCError(btcjson.ErrRPCMethodNotFound.Code, "method not found")
+		case "getnetworkinfo":
+			return &btcjson.GetNetworkInfoResult{SubVersion: "/Satoshi:27.0.0/"}, nil
+		case "sendrawtransaction":
+			if reject {
+				return nil, btcjson.NewRPCError(btcjson.ErrRPCVerify, "mempool full")
+			}
+			require.Equal(t, txHexOf(t, tx), stringParam(t, params, 0))
+			return tx.TxHash().String(), nil
+		default:
+			t.Fatalf("unexpected method %s", method)
+			return nil, nil
+		}
+	}
+}
+
+func txHexOf(t *testing.T, tx *wire.MsgTx) string {
+	var buf bytes.Buffer
+	require.NoError(t, tx.Serialize(&buf))
+	return hex.EncodeToString(buf.Bytes())
+}
+
+// esploraServer is fake esplora api, rejecting transactions if reject is true
+func esploraServer(t *testing.T, tx *wire.MsgTx, reject bool, received *[]string) *httptest.Server {
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodPost, r.Method)
+		require.Equal(t, "/api/tx", r.URL.Path)
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		*received = append(*received, string(body))
+
+		if reject {
+			http.Error(w, "sendrawtransaction RPC error: bad-txns-inputs-missingorspent", http.StatusBadRequest)
+			return
+		}
+		_, _ = w.Write([]byte(tx.TxHash().String()))
+	}))
+	t.Cleanup(s.Close)
+
+	return s
+}
+
+func testBroadcastTx() *wire.MsgTx {
+	tx := wire.NewMsgTx(2)
+	tx.AddTxIn(wire.NewTxIn(wire.NewOutPoint(&chainhash.Hash{1}, 0), nil, nil))
+	tx.AddTxOut(wire.NewTxOut(10_000, []byte{0x51}))
+	return tx
+}
+
+func TestSendRawTransactionFallback(t *testing.T) {
+	t.Parallel()
+
+	tx := testBroadcastTx()
+	wc := newTestWalletController(t, newTestRPCServer(t, sendRawTxHandler(t, tx, true)), "")
+
+	var esploraReceived []string
+	esplora, err := NewBroadcaster(esploraServer(t, tx, true, &esploraReceived).URL + "/api/")
+	require.NoError(t, err)
+
+	secondNode := newTestRPCServer(t, sendRawTxHandler(t, tx, false))
+	bitcoind, err := NewBroadcaster("bitcoind://second:secret@" + secondNode.host())
+	require.NoError(t, err)
+
+	// broadcasters are tried in order until one accepts the transaction
+	wc.SetFallbackBroadcasters([]Broadcaster{esplora, bitcoind})
+	hash, err := wc.SendRawTransaction(tx, true)
+	require.NoError(t, err)
+	require.Equal(t, tx.TxHash(), *hash)
+
+	require.Equal(t, []string{txHexOf(t, tx)}, esploraReceived)
+	var sent []testRPCRequest
+	for _, req := range secondNode.received() {
+		if req.method == "sendrawtransaction" {
+			sent = append(sent, req)
+		}
+	}
+	require.Len(t, sent, 1)
+	require.Equal(t, "second", sent[0].user)
+	require.Equal(t, "secret", sent[0].pass)
+}
+
+func TestSendRawTransactionFallbackErrors(t *testing.T) {
+	t.Parallel()
+
+	tx := testBroadcastTx()
+	var esploraReceived []string
+	esplora, err := NewBroadcaster(esploraServer(t, tx, true, &esploraReceived).URL + "/api")
+	require.NoError(t, err)
+
+	// fallback is not used when wallet node sends the transaction
+	wc := newTestWalletController(t, newTestRPCServer(t, sendRawTxHandler(t, tx, false)), "")
+	wc.SetFallbackBroadcasters([]Broadcaster{esplora})
+	_, err = wc.SendRawTransaction(tx, true)
+	require.NoError(t, err)
+	require.Empty(t, esploraReceived)
+
+	// errors of the wallet node and all broadcasters are reported
+	wc = newTestWalletController(t, newTestRPCServer(t, sendRawTxHandler(t, tx, true)), "")
+	wc.SetFallbackBroadcasters([]Broadcaster{esplora})
+	_, err = wc.SendRawTransaction(tx, true)
+	require.ErrorContains(t, err, "mempool full")
+	require.ErrorContains(t, err, "fallback broadcaster "+esplora.Name())
+	require.ErrorContains(t, err, "bad-txns-inputs-missingorspent")
+
+	_, err = NewBroadcaster("ftp://mempool.space")
+	require.ErrorContains(t, err, "unsupported broadcaster url scheme")
+}
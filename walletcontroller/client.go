@@ -7,11 +7,13 @@ import (
 	"fmt"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/babylonlabs-io/babylon/v4/crypto/bip322"
 	"github.com/babylonlabs-io/btc-staker/stakercfg"
 	"github.com/babylonlabs-io/btc-staker/types"
 	"github.com/babylonlabs-io/btc-staker/utils"
+	"github.com/babylonlabs-io/btc-staker/utils/faults"
 	"github.com/btcsuite/btcd/btcec/v2"
 	"github.com/btcsuite/btcd/btcec/v2/schnorr"
 	"github.com/btcsuite/btcd/btcjson"
@@ -27,9 +29,13 @@ import (
 
 type RPCWalletController struct {
 	*rpcclient.Client
-	walletPassphrase string
-	network          string
-	backend          types.SupportedWalletBackend
+	passphrase *passphraseCache
+	network    string
+	backend    types.SupportedWalletBackend
+	// kept to create batch clients
+	connCfg *rpcclient.ConnConfig
+	// used when wallet node fails to send transaction
+	fallbackBroadcasters []Broadcaster
 }
 
 var _ WalletController = (*RPCWalletController)(nil)
@@ -40,19 +46,40 @@ const (
 )
 
 func NewRPCWalletController(scfg *stakercfg.Config) (*RPCWalletController, error) {
-	return NewRPCWalletControllerFromArgs(
+	passphrase, err := walletPassphraseCache(scfg.WalletConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	wc, err := newRPCWalletController(
 		scfg.WalletRPCConfig.Host,
 		scfg.WalletRPCConfig.User,
 		scfg.WalletRPCConfig.Pass,
+		scfg.WalletRPCConfig.RPCCookie,
 		scfg.ActiveNetParams.Name,
 		scfg.WalletConfig.WalletName,
-		scfg.WalletConfig.WalletPass,
+		"",
 		scfg.BtcNodeBackendConfig.ActiveWalletBackend,
 		&scfg.ActiveNetParams,
 		scfg.WalletRPCConfig.DisableTLS,
 		scfg.WalletRPCConfig.RawRPCWalletCert,
 		scfg.WalletRPCConfig.RPCWalletCert,
 	)
+	if err != nil {
+		return nil, err
+	}
+
+	wc.passphrase = passphrase
+
+	for _, rawURL := range scfg.BtcNodeBackendConfig.FallbackBroadcasters {
+		b, err := NewBroadcaster(rawURL)
+		if err != nil {
+			return nil, err
+		}
+		wc.fallbackBroadcasters = append(wc.fallbackBroadcasters, b)
+	}
+
+	return wc, nil
 }
 
 func NewRPCWalletControllerFromArgs(
@@ -67,10 +94,49 @@ func NewRPCWalletControllerFromArgs(
 	disableTLS bool,
 	rawWalletCert string, walletCertFilePath string,
 ) (*RPCWalletController, error) {
+	return newRPCWalletController(
+		host,
+		user,
+		pass,
+		"",
+		network,
+		walletName,
+		walletPassphrase,
+		nodeBackend,
+		params,
+		disableTLS,
+		rawWalletCert,
+		walletCertFilePath,
+	)
+}
+
+// newRPCWalletController creates wallet controller authenticating either with
+// user and password or, if cookiePath is not empty, with bitcoind cookie file.
+// Cookie is re-read whenever bitcoind re-creates it.
+func newRPCWalletController(
+	host string,
+	user string,
+	pass string,
+	cookiePath string,
+	network string,
+	walletName string,
+	walletPassphrase string,
+	nodeBackend types.SupportedWalletBackend,
+	params *chaincfg.Params,
+	disableTLS bool,
+	rawWalletCert string, walletCertFilePath string,
+) (*RPCWalletController, error) {
+	if cookiePath != "" {
+		// rpcclient uses cookie only if user and password are empty
+		user = ""
+		pass = ""
+	}
+
 	connCfg := &rpcclient.ConnConfig{
 		Host:                 rpcHostURL(host, walletName),
 		User:                 user,
 		Pass:                 pass,
+		CookiePath:           cookiePath,
 		DisableTLS:           disableTLS,
 		DisableConnectOnNew:  true,
 		DisableAutoReconnect: false,
@@ -93,10 +159,11 @@ func NewRPCWalletControllerFromArgs(
 	}
 
 	return &RPCWalletController{
-		Client:           rpcclient,
-		walletPassphrase: walletPassphrase,
-		network:          params.Name,
-		backend:          nodeBackend,
+		Client:     rpcclient,
+		passphrase: newPassphraseCache(walletPassphrase),
+		connCfg:    connCfg,
+		network:    params.Name,
+		backend:    nodeBackend,
 	}, nil
 }
 
@@ -108,7 +175,31 @@ func rpcHostURL(host, walletName string) string {
 }
 
 func (w *RPCWalletController) UnlockWallet(timoutSec int64) error {
-	return w.WalletPassphrase(w.walletPassphrase, timoutSec)
+	passphrase, err := w.passphrase.get()
+	if err != nil {
+		return err
+	}
+
+	return w.WalletPassphrase(passphrase, timoutSec)
+}
+
+// SetPassphrase verifies passphrase and caches it. Passphrase is verified by
+// changing it to itself, which fails for wrong passphrase and, unlike
+// walletpassphrase, leaves the wallet locked, or unlocked until the same time,
+// as it was before.
+func (w *RPCWalletController) SetPassphrase(passphrase string, timeout time.Duration) error {
+	if err := w.WalletPassphraseChange(passphrase, passphrase); err != nil {
+		return fmt.Errorf("failed to verify wallet passphrase: %w", err)
+	}
+
+	w.passphrase.store(passphrase, timeout)
+
+	return nil
+}
+
+func (w *RPCWalletController) PassphraseAvailable() error {
+	_, err := w.passphrase.get()
+	return err
 }
 
 // Extracts public key from the descriptor in format:
@@ -212,6 +303,10 @@ func (w *RPCWalletController) NetworkName() string {
 	return w.network
 }
 
+func (w *RPCWalletController) NewAddress(addressType AddressType) (btcutil.Address, error) {
+	return w.GetNewAddressType("", string(addressType))
+}
+
 func (w *RPCWalletController) CreateTransaction(
 	outputs []*wire.TxOut,
 	feeRatePerKb btcutil.Amount,
@@ -428,7 +523,16 @@ func (w *RPCWalletController) SignRawTransaction(tx *wire.MsgTx) (*wire.MsgTx, b
 }
 
 func (w *RPCWalletController) SendRawTransaction(tx *wire.MsgTx, allowHighFees bool) (*chainhash.Hash, error) {
-	return w.Client.SendRawTransaction(tx, allowHighFees)
+	if err := faults.Inject(faults.BtcNodeTimeout); err != nil {
+		return nil, err
+	}
+
+	hash, err := w.Client.SendRawTransaction(tx, allowHighFees)
+	if err != nil && len(w.fallbackBroadcasters) > 0 {
+		return w.sendWithFallback(tx, err)
+	}
+
+	return hash, err
 }
 
 func (w *RPCWalletController) ListOutputs(onlySpendable bool) ([]Utxo, error) {
@@ -491,6 +595,10 @@ func (w *RPCWalletController) BlockHeaderVerbose(blockHash *chainhash.Hash) (*bt
 
 // Fetch info about transaction from mempool or blockchain, requires node to have enabled  transaction index
 func (w *RPCWalletController) TxDetails(txHash *chainhash.Hash, pkScript []byte) (*notifier.TxConfirmation, TxStatus, error) {
+	if err := faults.Inject(faults.BtcNodeTimeout); err != nil {
+		return nil, TxNotFound, err
+	}
+
 	req, err := notifier.NewConfRequest(txHash, pkScript)
 
 	if err != nil {
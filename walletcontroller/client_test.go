@@ -0,0 +1,162 @@
+package walletcontroller
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/babylonlabs-io/btc-staker/types"
+	"github.com/btcsuite/btcd/btcjson"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/stretchr/testify/require"
+)
+
+// testRPCRequest is json-rpc request received by testRPCServer
+type testRPCRequest struct {
+	path   string
+	user   string
+	pass   string
+	method string
+	params []json.RawMessage
+	// number of requests in the http request this one was sent in
+	batchSize int
+}
+
+type testRPCHandler func(method string, params []json.RawMessage) (interface{}, *btcjson.RPCError)
+
+// testRPCServer is fake bitcoind answering json-rpc requests, also batched
+// ones, using handler
+type testRPCServer struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	requests []testRPCRequest
+}
+
+func newTestRPCServer(t *testing.T, handler testRPCHandler) *testRPCServer {
+	s := &testRPCServer{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+
+		batch := strings.HasPrefix(strings.TrimSpace(string(body)), "[")
+		var reqs []btcjson.Request
+		if batch {
+			require.NoError(t, json.Unmarshal(body, &reqs))
+		} else {
+			var req btcjson.Request
+			require.NoError(t, json.Unmarshal(body, &req))
+			reqs = []btcjson.Request{req}
+		}
+
+		user, pass, _ := r.BasicAuth()
+		resps := make([]btcjson.Response, 0, len(reqs))
+		for _, req := range reqs {
+			s.mu.Lock()
+			s.requests = append(s.requests, testRPCRequest{
+				path:      r.URL.Path,
+				user:      user,
+				pass:      pass,
+				method:    req.Method,
+				params:    req.Params,
+				batchSize: len(reqs),
+			})
+			s.mu.Unlock()
+
+			result, rpcErr := handler(req.Method, req.Params)
+			marshalled, err := json.Marshal(result)
+			require.NoError(t, err)
+			resp, err := btcjson.NewResponse(btcjson.RpcVersion1, req.ID, marshalled, rpcErr)
+			require.NoError(t, err)
+			resps = append(resps, *resp)
+		}
+
+		if batch {
+			require.NoError(t, json.NewEncoder(w).Encode(resps))
+		} else {
+			require.NoError(t, json.NewEncoder(w).Encode(resps[0]))
+		}
+	}))
+	t.Cleanup(s.Close)
+
+	return s
+}
+
+func (s *testRPCServer) host() string {
+	return strings.TrimPrefix(s.URL, "http://")
+}
+
+func (s *testRPCServer) received() []testRPCRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]testRPCRequest(nil), s.requests...)
+}
+
+func newTestWalletController(t *testing.T, s *testRPCServer, cookiePath string) *RPCWalletController {
+	wc, err := newRPCWalletController(
+		s.host(),
+		"user",
+		"pass",
+		cookiePath,
+		chaincfg.RegressionNetParams.Name,
+		"staker",
+		"",
+		types.BitcoindWalletBackend,
+		&chaincfg.RegressionNetParams,
+		true,
+		"",
+		"",
+	)
+	require.NoError(t, err)
+	t.Cleanup(wc.Shutdown)
+
+	return wc
+}
+
+func blockCountHandler(method string, _ []json.RawMessage) (interface{}, *btcjson.RPCError) {
+	if method != "getblockcount" {
+		return nil, btcjson.NewRPCError(btcjson.ErrRPCMethodNotFound.Code, "method not found")
+	}
+	return 100, nil
+}
+
+func TestWalletRPCAuthentication(t *testing.T) {
+	t.Parallel()
+
+	s := newTestRPCServer(t, blockCountHandler)
+
+	wc := newTestWalletController(t, s, "")
+	_, err := wc.GetBlockCount()
+	require.NoError(t, err)
+
+	reqs := s.received()
+	require.Len(t, reqs, 1)
+	require.Equal(t, "/wallet/staker", reqs[0].path)
+	require.Equal(t, "user", reqs[0].user)
+	require.Equal(t, "pass", reqs[0].pass)
+}
+
+func TestWalletRPCCookieAuthentication(t *testing.T) {
+	t.Parallel()
+
+	s := newTestRPCServer(t, blockCountHandler)
+	cookiePath := filepath.Join(t.TempDir(), ".cookie")
+	require.NoError(t, os.WriteFile(cookiePath, []byte("__cookie__:secret"), 0600))
+
+	// cookie takes precedence over configured user and password
+	wc := newTestWalletController(t, s, cookiePath)
+	_, err := wc.GetBlockCount()
+	require.NoError(t, err)
+
+	reqs := s.received()
+	require.Len(t, reqs, 1)
+	require.Equal(t, "/wallet/staker", reqs[0].path)
+	require.Equal(t, "__cookie__", reqs[0].user)
+	require.Equal(t, "secret", reqs[0].pass)
+}
@@ -0,0 +1,109 @@
+package walletcontroller
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/babylonlabs-io/btc-staker/types"
+)
+
+// WalletDescriptor is a public output descriptor of the wallet as reported
+// by bitcoind listdescriptors
+type WalletDescriptor struct {
+	Desc      string          `json:"desc"`
+	Timestamp int64           `json:"timestamp"`
+	Active    bool            `json:"active"`
+	Internal  *bool           `json:"internal,omitempty"`
+	Range     json.RawMessage `json:"range,omitempty"`
+	Next      *int64          `json:"next,omitempty"`
+}
+
+// WalletDescriptors are public output descriptors of the wallet
+type WalletDescriptors struct {
+	WalletName  string             `json:"wallet_name"`
+	Descriptors []WalletDescriptor `json:"descriptors"`
+}
+
+// ListDescriptors returns public output descriptors of the wallet. Only
+// descriptor wallets of bitcoind are supported.
+func (w *RPCWalletController) ListDescriptors() (*WalletDescriptors, error) {
+	if w.backend != types.BitcoindWalletBackend {
+		return nil, fmt.Errorf("listing wallet descriptors is supported only by bitcoind wallets")
+	}
+
+	raw, err := w.Client.RawRequest("listdescriptors", nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list wallet descriptors: %w", err)
+	}
+
+	var descriptors WalletDescriptors
+	if err := json.Unmarshal(raw, &descriptors); err != nil {
+		return nil, fmt.Errorf("failed to decode wallet descriptors: %w", err)
+	}
+
+	return &descriptors, nil
+}
+
+type importDescriptorResult struct {
+	Success bool `json:"success"`
+	Error   *struct {
+		Message string `json:"message"`
+	} `json:"error,omitempty"`
+}
+
+// ImportDescriptors imports public output descriptors into the wallet, which
+// rescans the chain from their timestamps. Wallets with private keys enabled
+// reject descriptors without private keys.
+func (w *RPCWalletController) ImportDescriptors(descriptors []WalletDescriptor) error {
+	if w.backend != types.BitcoindWalletBackend {
+		return fmt.Errorf("importing wallet descriptors is supported only by bitcoind wallets")
+	}
+
+	requests := make([]map[string]interface{}, 0, len(descriptors))
+	for _, d := range descriptors {
+		req := map[string]interface{}{
+			"desc":      d.Desc,
+			"timestamp": d.Timestamp,
+			"active":    d.Active,
+		}
+		if d.Internal != nil {
+			req["internal"] = *d.Internal
+		}
+		if len(d.Range) > 0 {
+			req["range"] = d.Range
+		}
+		if d.Next != nil {
+			req["next_index"] = *d.Next
+		}
+		requests = append(requests, req)
+	}
+
+	encoded, err := json.Marshal(requests)
+	if err != nil {
+		return err
+	}
+
+	raw, err := w.Client.RawRequest("importdescriptors", []json.RawMessage{encoded})
+	if err != nil {
+		return fmt.Errorf("failed to import wallet descriptors: %w", err)
+	}
+
+	var results []importDescriptorResult
+	if err := json.Unmarshal(raw, &results); err != nil {
+		return fmt.Errorf("failed to decode import result: %w", err)
+	}
+
+	for i, r := range results {
+		if r.Success || i >= len(descriptors) {
+			continue
+		}
+
+		reason := "unknown error"
+		if r.Error != nil {
+			reason = r.Error.Message
+		}
+		return fmt.Errorf("failed to import descriptor %s: %s", descriptors[i].Desc, reason)
+	}
+
+	return nil
+}
@@ -2,6 +2,7 @@ package walletcontroller
 
 import (
 	"fmt"
+	"time"
 
 	staking "github.com/babylonlabs-io/babylon/v4/btcstaking"
 	"github.com/btcsuite/btcd/btcec/v2"
@@ -38,6 +39,15 @@ func (ts TxStatus) String() string {
 	}
 }
 
+// AddressType is the type of new wallet address, named as in bitcoind
+// getnewaddress rpc
+type AddressType string
+
+const (
+	AddressTypeTaproot AddressType = "bech32m"
+	AddressTypeSegwit  AddressType = "bech32"
+)
+
 type SpendPathDescription struct {
 	ControlBlock *txscript.ControlBlock
 	ScriptLeaf   *txscript.TapLeaf
@@ -68,11 +78,21 @@ type TaprootSigningResult struct {
 // Function to filer utxos that should be used in transaction creation
 type UseUtxoFn func(utxo Utxo) bool
 
+// WalletController is the btc wallet used by the staker app. Mock is
+// available in testutil/mocks.
 type WalletController interface {
 	UnlockWallet(timeoutSecs int64) error
+	// SetPassphrase verifies and caches wallet passphrase used by UnlockWallet.
+	// Zero timeout keeps passphrase cached until restart.
+	SetPassphrase(passphrase string, timeout time.Duration) error
+	// PassphraseAvailable returns ErrWalletPassphraseNotSet if wallet can not
+	// be unlocked because passphrase is not set or expired
+	PassphraseAvailable() error
 	AddressPublicKey(address btcutil.Address) (*btcec.PublicKey, error)
 	ImportPrivKey(privKeyWIF *btcutil.WIF) error
 	NetworkName() string
+	// NewAddress returns fresh wallet address of given type
+	NewAddress(addressType AddressType) (btcutil.Address, error)
 	// passing nil usedUtxoFilter will use all possible spendable utxos to choose
 	// inputs
 	CreateTransaction(
@@ -105,7 +125,10 @@ type WalletController interface {
 	ListOutputs(onlySpendable bool) ([]Utxo, error)
 	TxDetails(txHash *chainhash.Hash, pkScript []byte) (*notifier.TxConfirmation, TxStatus, error)
 	Tx(txHash *chainhash.Hash) (*btcutil.Tx, error)
+	// Txs returns transactions with given hashes using batched requests
+	Txs(txHashes []chainhash.Hash) ([]*btcutil.Tx, error)
 	TxVerbose(txHash *chainhash.Hash) (*btcjson.TxRawResult, error)
+	GetMempoolEntry(txHash string) (*btcjson.GetMempoolEntryResult, error)
 	BlockHeaderVerbose(blockHash *chainhash.Hash) (*btcjson.GetBlockHeaderVerboseResult, error)
 	// SignBip322Signature signs arbitrary message using bip322 signing scheme.
 	// Works only for:
@@ -122,6 +145,13 @@ type WalletController interface {
 		txHash *chainhash.Hash,
 		outputIdx uint32,
 	) (bool, error)
+	// OutputsSpent checks whether given outputs are spent using batched requests
+	OutputsSpent(outpoints []wire.OutPoint) ([]bool, error)
+	// MempoolSpenders returns hashes of mempool transactions spending given
+	// outputs, nil for outputs not spent in mempool
+	MempoolSpenders(outpoints []wire.OutPoint) ([]*chainhash.Hash, error)
+	// RelayFees returns min relay fee and incremental relay fee of the node
+	RelayFees() (*RelayFees, error)
 }
 
 func StkTxV0ParsedWithBlock(
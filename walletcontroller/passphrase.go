@@ -0,0 +1,96 @@
+package walletcontroller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/99designs/keyring"
+	"github.com/babylonlabs-io/btc-staker/secrets"
+	"github.com/babylonlabs-io/btc-staker/stakercfg"
+)
+
+var ErrWalletPassphraseNotSet = errors.New("wallet passphrase not set or expired, provide it using unlock_wallet")
+
+// passphraseCache holds wallet passphrase in memory, optionally only until
+// given deadline
+type passphraseCache struct {
+	mu         sync.Mutex
+	passphrase string
+	set        bool
+	// zero value means passphrase never expires
+	expiresAt time.Time
+}
+
+func newPassphraseCache(passphrase string) *passphraseCache {
+	return &passphraseCache{
+		passphrase: passphrase,
+		set:        true,
+	}
+}
+
+func (c *passphraseCache) get() (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.set {
+		return "", ErrWalletPassphraseNotSet
+	}
+
+	if !c.expiresAt.IsZero() && time.Now().After(c.expiresAt) {
+		c.passphrase = ""
+		c.set = false
+		return "", ErrWalletPassphraseNotSet
+	}
+
+	return c.passphrase, nil
+}
+
+func (c *passphraseCache) store(passphrase string, timeout time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.passphrase = passphrase
+	c.set = true
+
+	if timeout > 0 {
+		c.expiresAt = time.Now().Add(timeout)
+	} else {
+		c.expiresAt = time.Time{}
+	}
+}
+
+// walletPassphraseCache creates passphrase cache from the configured passphrase
+// source. For rpc source, cache is empty until passphrase is provided through
+// SetPassphrase.
+func walletPassphraseCache(cfg *stakercfg.WalletConfig) (*passphraseCache, error) {
+	switch cfg.WalletPassSource {
+	case stakercfg.WalletPassSourceConfig, "":
+		return newPassphraseCache(cfg.WalletPass), nil
+	case stakercfg.WalletPassSourceEnv:
+		passphrase, err := secrets.EnvProvider{}.Fetch(context.Background(), cfg.WalletPassEnv)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get wallet passphrase: %w", err)
+		}
+		return newPassphraseCache(passphrase), nil
+	case stakercfg.WalletPassSourceKeyring:
+		kr, err := keyring.Open(keyring.Config{
+			ServiceName: cfg.WalletPassKeyringService,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to open keyring: %w", err)
+		}
+
+		item, err := kr.Get(cfg.WalletName)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get wallet passphrase from keyring service %s: %w", cfg.WalletPassKeyringService, err)
+		}
+		return newPassphraseCache(string(item.Data)), nil
+	case stakercfg.WalletPassSourceRPC:
+		return &passphraseCache{}, nil
+	default:
+		return nil, fmt.Errorf("unknown wallet passphrase source: %s", cfg.WalletPassSource)
+	}
+}
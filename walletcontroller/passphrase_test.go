@@ -0,0 +1,84 @@
+package walletcontroller
+
+import (
+	"testing"
+	"time"
+
+	"github.com/babylonlabs-io/btc-staker/stakercfg"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPassphraseCacheExpiry(t *testing.T) {
+	t.Parallel()
+
+	c := &passphraseCache{}
+	_, err := c.get()
+	require.ErrorIs(t, err, ErrWalletPassphraseNotSet)
+
+	c.store("pass", 50*time.Millisecond)
+	passphrase, err := c.get()
+	require.NoError(t, err)
+	require.Equal(t, "pass", passphrase)
+
+	time.Sleep(100 * time.Millisecond)
+	_, err = c.get()
+	require.ErrorIs(t, err, ErrWalletPassphraseNotSet)
+
+	// expired passphrase is dropped, not only hidden
+	require.Empty(t, c.passphrase)
+
+	// zero timeout never expires, even if previous passphrase had one
+	c.store("pass", time.Millisecond)
+	c.store("other", 0)
+	time.Sleep(10 * time.Millisecond)
+	passphrase, err = c.get()
+	require.NoError(t, err)
+	require.Equal(t, "other", passphrase)
+}
+
+func TestWalletPassphraseCacheSource(t *testing.T) {
+	const envName = "BTCSTAKER_TEST_WALLET_PASSPHRASE"
+	t.Setenv(envName, "from-env")
+
+	tests := []struct {
+		name     string
+		source   string
+		env      string
+		expected string
+		// notSet is true if passphrase is expected to be provided through rpc
+		notSet  bool
+		wantErr string
+	}{
+		{name: "default source is config", source: "", expected: "from-config"},
+		{name: "config", source: stakercfg.WalletPassSourceConfig, expected: "from-config"},
+		{name: "env takes precedence over config", source: stakercfg.WalletPassSourceEnv, env: envName, expected: "from-env"},
+		{name: "env not set", source: stakercfg.WalletPassSourceEnv, env: "BTCSTAKER_TEST_UNSET", wantErr: "BTCSTAKER_TEST_UNSET is not set"},
+		{name: "rpc ignores config", source: stakercfg.WalletPassSourceRPC, notSet: true},
+		{name: "unknown", source: "prompt", wantErr: "unknown wallet passphrase source"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := &stakercfg.WalletConfig{
+				WalletPass:       "from-config",
+				WalletPassSource: tc.source,
+				WalletPassEnv:    tc.env,
+			}
+
+			c, err := walletPassphraseCache(cfg)
+			if tc.wantErr != "" {
+				require.ErrorContains(t, err, tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+
+			passphrase, err := c.get()
+			if tc.notSet {
+				require.ErrorIs(t, err, ErrWalletPassphraseNotSet)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, passphrase)
+		})
+	}
+}
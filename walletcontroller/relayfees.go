@@ -0,0 +1,66 @@
+package walletcontroller
+
+import (
+	"fmt"
+
+	"github.com/babylonlabs-io/btc-staker/types"
+	"github.com/btcsuite/btcd/btcutil"
+)
+
+// DefaultIncrementalRelayFeePerKb is the default incremental relay fee of
+// bitcoind, used when the node does not report it
+const DefaultIncrementalRelayFeePerKb = btcutil.Amount(1000)
+
+// RelayFees are relay policy fee rates of the node which sends wallet
+// transactions
+type RelayFees struct {
+	// MinRelayFeePerKb is the lowest fee rate of transactions relayed by the node
+	MinRelayFeePerKb btcutil.Amount
+	// IncrementalRelayFeePerKb is the fee rate replacement transaction must pay
+	// for its own size on top of the fee of replaced transaction
+	IncrementalRelayFeePerKb btcutil.Amount
+}
+
+func (w *RPCWalletController) RelayFees() (*RelayFees, error) {
+	switch w.backend {
+	case types.BitcoindWalletBackend:
+		info, err := w.Client.GetNetworkInfo()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get network info: %w", err)
+		}
+
+		return relayFeesFromBtc(info.RelayFee, info.IncrementalFee)
+	case types.BtcwalletWalletBackend:
+		// btcwallet reports only relay fee of the wallet
+		info, err := w.Client.GetInfo()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get wallet info: %w", err)
+		}
+
+		return relayFeesFromBtc(info.RelayFee, 0)
+	default:
+		return nil, fmt.Errorf("invalid bitcoin backend")
+	}
+}
+
+// relayFeesFromBtc converts fee rates reported in BTC/kvB
+func relayFeesFromBtc(relayFee, incrementalFee float64) (*RelayFees, error) {
+	minRelayFee, err := btcutil.NewAmount(relayFee)
+	if err != nil {
+		return nil, fmt.Errorf("invalid relay fee %f: %w", relayFee, err)
+	}
+
+	incrementalRelayFee, err := btcutil.NewAmount(incrementalFee)
+	if err != nil {
+		return nil, fmt.Errorf("invalid incremental relay fee %f: %w", incrementalFee, err)
+	}
+
+	if incrementalRelayFee == 0 {
+		incrementalRelayFee = DefaultIncrementalRelayFeePerKb
+	}
+
+	return &RelayFees{
+		MinRelayFeePerKb:         minRelayFee,
+		IncrementalRelayFeePerKb: incrementalRelayFee,
+	}, nil
+}
@@ -0,0 +1,60 @@
+package walletcontroller
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRelayFeesFromBtc(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		relayFee       float64
+		incrementalFee float64
+		expected       *RelayFees
+		wantErr        string
+	}{
+		{
+			name:           "reported fees",
+			relayFee:       0.00002,
+			incrementalFee: 0.00003,
+			expected:       &RelayFees{MinRelayFeePerKb: 2000, IncrementalRelayFeePerKb: 3000},
+		},
+		{
+			name:     "zero incremental fee uses default",
+			relayFee: 0.00001,
+			expected: &RelayFees{MinRelayFeePerKb: 1000, IncrementalRelayFeePerKb: DefaultIncrementalRelayFeePerKb},
+		},
+		{
+			name:     "zero relay fee is kept",
+			expected: &RelayFees{MinRelayFeePerKb: 0, IncrementalRelayFeePerKb: DefaultIncrementalRelayFeePerKb},
+		},
+		{
+			name:     "invalid relay fee",
+			relayFee: math.NaN(),
+			wantErr:  "invalid relay fee",
+		},
+		{
+			name:           "invalid incremental fee",
+			relayFee:       0.00001,
+			incrementalFee: math.Inf(1),
+			wantErr:        "invalid incremental relay fee",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			fees, err := relayFeesFromBtc(tc.relayFee, tc.incrementalFee)
+			if tc.wantErr != "" {
+				require.ErrorContains(t, err, tc.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, fees)
+		})
+	}
+}
@@ -0,0 +1,74 @@
+package walletcontroller
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/babylonlabs-io/btc-staker/types"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+)
+
+// ErrMempoolSpendersNotSupported is returned by MempoolSpenders if node can't
+// look up spenders of outputs
+var ErrMempoolSpendersNotSupported = errors.New("looking up mempool spenders is supported only by bitcoind")
+
+type spendingPrevout struct {
+	TxID string `json:"txid"`
+	Vout uint32 `json:"vout"`
+}
+
+type spendingPrevoutResult struct {
+	TxID         string `json:"txid"`
+	Vout         uint32 `json:"vout"`
+	SpendingTxID string `json:"spendingtxid,omitempty"`
+}
+
+// MempoolSpenders returns hashes of mempool transactions spending given
+// outputs, in the same order. Hash is nil if output is not spent by mempool
+// transaction. Only bitcoind backend is supported, as btcd has no index of
+// mempool spends.
+func (w *RPCWalletController) MempoolSpenders(outpoints []wire.OutPoint) ([]*chainhash.Hash, error) {
+	if w.backend != types.BitcoindWalletBackend {
+		return nil, ErrMempoolSpendersNotSupported
+	}
+
+	prevouts := make([]spendingPrevout, len(outpoints))
+	for i, op := range outpoints {
+		prevouts[i] = spendingPrevout{TxID: op.Hash.String(), Vout: op.Index}
+	}
+
+	encoded, err := json.Marshal(prevouts)
+	if err != nil {
+		return nil, err
+	}
+
+	raw, err := w.Client.RawRequest("gettxspendingprevout", []json.RawMessage{encoded})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get mempool spenders: %w", err)
+	}
+
+	var results []spendingPrevoutResult
+	if err := json.Unmarshal(raw, &results); err != nil {
+		return nil, fmt.Errorf("failed to decode mempool spenders: %w", err)
+	}
+
+	if len(results) != len(outpoints) {
+		return nil, fmt.Errorf("expected %d mempool spender results, got %d", len(outpoints), len(results))
+	}
+
+	spenders := make([]*chainhash.Hash, len(outpoints))
+	for i, r := range results {
+		if r.SpendingTxID == "" {
+			continue
+		}
+
+		spenders[i], err = chainhash.NewHashFromStr(r.SpendingTxID)
+		if err != nil {
+			return nil, fmt.Errorf("invalid spending transaction id %q: %w", r.SpendingTxID, err)
+		}
+	}
+
+	return spenders, nil
+}
@@ -0,0 +1,28 @@
+// Package thresholdsigner allows to sign staking, unbonding and withdrawal
+// transactions with a btc key held by an external threshold signing (TSS/MPC)
+// service, so that no single host ever holds the staker key.
+package thresholdsigner
+
+import (
+	"context"
+	"errors"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+)
+
+// ErrInvalidSignature is returned when signature returned by threshold signer
+// does not verify against its public key
+var ErrInvalidSignature = errors.New("threshold signer returned invalid signature")
+
+// Signer is an external service holding secp256k1 btc staker key
+type Signer interface {
+	// PubKey returns public key of the signing key
+	PubKey(ctx context.Context) (*btcec.PublicKey, error)
+
+	// SignSchnorr signs 32 bytes BIP341 sighash and returns 64 bytes BIP340
+	// signature. If bip86Tweak is true, the key is first tweaked as specified
+	// by BIP86, which is the case for taproot key path spends of the staker
+	// address. Script path spends, i.e. unbonding and withdrawal, are signed
+	// with untweaked key.
+	SignSchnorr(ctx context.Context, sigHash []byte, bip86Tweak bool) ([]byte, error)
+}
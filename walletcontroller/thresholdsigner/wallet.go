@@ -0,0 +1,346 @@
+package thresholdsigner
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/babylonlabs-io/babylon/v4/crypto/bip322"
+	"github.com/babylonlabs-io/btc-staker/walletcontroller"
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+)
+
+// WalletController is a wallet controller whose staker address is BIP86
+// taproot address of the threshold signer key. Transactions are still funded
+// by the underlying wallet, which is expected to watch the staker address,
+// e.g. through imported tr(<pub key>) descriptor, but inputs spending the
+// staker address, proofs of possession and staker signatures of unbonding and
+// withdrawal are signed by the threshold signer. Requests for other addresses
+// are served by the underlying wallet.
+type WalletController struct {
+	walletcontroller.WalletController
+
+	signer   Signer
+	pubKey   *btcec.PublicKey
+	address  *btcutil.AddressTaproot
+	pkScript []byte
+	timeout  time.Duration
+}
+
+var _ walletcontroller.WalletController = (*WalletController)(nil)
+
+// NewWalletController fetches public key from the threshold signer and
+// returns wallet controller signing for its BIP86 taproot address
+func NewWalletController(
+	ctx context.Context,
+	wc walletcontroller.WalletController,
+	signer Signer,
+	net *chaincfg.Params,
+	timeout time.Duration,
+) (*WalletController, error) {
+	fetchCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	pubKey, err := signer.PubKey(fetchCtx)
+	if err != nil {
+		return nil, err
+	}
+
+	address, err := bip322.PubKeyToP2TrSpendAddress(pubKey, net)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive staker address of threshold signer key: %w", err)
+	}
+
+	pkScript, err := txscript.PayToAddrScript(address)
+	if err != nil {
+		return nil, err
+	}
+
+	return &WalletController{
+		WalletController: wc,
+		signer:           signer,
+		pubKey:           pubKey,
+		address:          address,
+		pkScript:         pkScript,
+		timeout:          timeout,
+	}, nil
+}
+
+// Address returns staker address controlled by the threshold signer
+func (w *WalletController) Address() btcutil.Address {
+	return w.address
+}
+
+func (w *WalletController) isSignerAddress(address btcutil.Address) bool {
+	pkScript, err := txscript.PayToAddrScript(address)
+	return err == nil && bytes.Equal(pkScript, w.pkScript)
+}
+
+// UnlockWallet does nothing, underlying wallet holds no keys used by the
+// staker
+func (w *WalletController) UnlockWallet(_ int64) error {
+	return nil
+}
+
+// SetPassphrase is not supported, staker key is never held by the wallet
+func (w *WalletController) SetPassphrase(_ string, _ time.Duration) error {
+	return errors.New("wallet passphrase is not used when staker key is held by threshold signer")
+}
+
+// PassphraseAvailable always succeeds, as no passphrase is needed to sign
+func (w *WalletController) PassphraseAvailable() error {
+	return nil
+}
+
+// AddressPublicKey returns threshold signer key for the staker address
+func (w *WalletController) AddressPublicKey(address btcutil.Address) (*btcec.PublicKey, error) {
+	if w.isSignerAddress(address) {
+		return w.pubKey, nil
+	}
+
+	return w.WalletController.AddressPublicKey(address)
+}
+
+// SignRawTransaction signs inputs spending the staker address with the
+// threshold signer, other inputs are signed by the underlying wallet
+func (w *WalletController) SignRawTransaction(tx *wire.MsgTx) (*wire.MsgTx, bool, error) {
+	prevOuts, err := w.prevOutputs(tx)
+	if err != nil {
+		return nil, false, err
+	}
+
+	signed := tx.Copy()
+	if w.hasForeignUnsignedInputs(signed, prevOuts) {
+		signed, _, err = w.WalletController.SignRawTransaction(signed)
+		if err != nil {
+			return nil, false, err
+		}
+	}
+
+	fetcher := txscript.NewMultiPrevOutFetcher(nil)
+	for i, in := range signed.TxIn {
+		fetcher.AddPrevOut(in.PreviousOutPoint, prevOuts[i])
+	}
+	sigHashes := txscript.NewTxSigHashes(signed, fetcher)
+
+	for i, in := range signed.TxIn {
+		if len(in.Witness) > 0 || !bytes.Equal(prevOuts[i].PkScript, w.pkScript) {
+			continue
+		}
+
+		sigHash, err := txscript.CalcTaprootSignatureHash(
+			sigHashes, txscript.SigHashDefault, signed, i, fetcher,
+		)
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to calculate sighash of input %d: %w", i, err)
+		}
+
+		sig, err := w.sign(sigHash, true)
+		if err != nil {
+			return nil, false, err
+		}
+
+		in.Witness = wire.TxWitness{sig.Serialize()}
+	}
+
+	return signed, isFullySigned(signed), nil
+}
+
+// CreateAndSignTx funds transaction from the underlying wallet and signs it
+func (w *WalletController) CreateAndSignTx(
+	outputs []*wire.TxOut,
+	feeRatePerKb btcutil.Amount,
+	changeAddress btcutil.Address,
+	useUtxoFn walletcontroller.UseUtxoFn,
+) (*wire.MsgTx, error) {
+	tx, err := w.CreateTransaction(outputs, feeRatePerKb, changeAddress, useUtxoFn)
+	if err != nil {
+		return nil, err
+	}
+
+	signedTx, fullySigned, err := w.SignRawTransaction(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	if !fullySigned {
+		return nil, fmt.Errorf("not all transactions inputs could be signed")
+	}
+
+	return signedTx, nil
+}
+
+// SignBip322Signature signs bip322 message of the staker address with the
+// threshold signer
+func (w *WalletController) SignBip322Signature(msg []byte, address btcutil.Address) (wire.TxWitness, error) {
+	if !w.isSignerAddress(address) {
+		return w.WalletController.SignBip322Signature(msg, address)
+	}
+
+	toSpend, err := bip322.GetToSpendTx(msg, address)
+	if err != nil {
+		return nil, fmt.Errorf("failed to bip322 to spend tx: %w", err)
+	}
+
+	toSign := bip322.GetToSignTx(toSpend)
+
+	fetcher := txscript.NewCannedPrevOutputFetcher(toSpend.TxOut[0].PkScript, 0)
+	sigHash, err := txscript.CalcTaprootSignatureHash(
+		txscript.NewTxSigHashes(toSign, fetcher), txscript.SigHashDefault, toSign, 0, fetcher,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to calculate bip322 sighash: %w", err)
+	}
+
+	sig, err := w.sign(sigHash, true)
+	if err != nil {
+		return nil, err
+	}
+
+	return wire.TxWitness{sig.Serialize()}, nil
+}
+
+// SignOneInputTaprootSpendingTransaction signs script path spend of the only
+// input with the threshold signer, if signer address is the staker address
+func (w *WalletController) SignOneInputTaprootSpendingTransaction(
+	req *walletcontroller.TaprootSigningRequest,
+) (*walletcontroller.TaprootSigningResult, error) {
+	if !w.isSignerAddress(req.SignerAddress) {
+		return w.WalletController.SignOneInputTaprootSpendingTransaction(req)
+	}
+
+	if len(req.TxToSign.TxIn) != 1 {
+		return nil, fmt.Errorf("cannot sign transaction with more than one input")
+	}
+
+	return w.signScriptSpend(req.TxToSign, []*wire.TxOut{req.FundingOutput}, req.SpendDescription)
+}
+
+// SignTwoInputTaprootSpendingTransaction signs script path spend of the first
+// input with the threshold signer, if signer address is the staker address
+func (w *WalletController) SignTwoInputTaprootSpendingTransaction(
+	req *walletcontroller.TwoInputTaprootSigningRequest,
+) (*walletcontroller.TaprootSigningResult, error) {
+	if !w.isSignerAddress(req.SignerAddress) {
+		return w.WalletController.SignTwoInputTaprootSpendingTransaction(req)
+	}
+
+	if len(req.TxToSign.TxIn) != 2 {
+		return nil, fmt.Errorf("transaction must have exactly two inputs, got %d", len(req.TxToSign.TxIn))
+	}
+
+	return w.signScriptSpend(req.TxToSign, []*wire.TxOut{req.StakingOutput, req.FundingOutput}, req.SpendDescription)
+}
+
+// signScriptSpend signs script path spend of the first input of the tx with
+// untweaked key, as staker key is part of spend scripts
+func (w *WalletController) signScriptSpend(
+	tx *wire.MsgTx,
+	prevOuts []*wire.TxOut,
+	spend *walletcontroller.SpendPathDescription,
+) (*walletcontroller.TaprootSigningResult, error) {
+	if !txscript.IsPayToTaproot(prevOuts[0].PkScript) {
+		return nil, fmt.Errorf("input 0 must be a taproot output")
+	}
+
+	fetcher := txscript.NewMultiPrevOutFetcher(nil)
+	for i, in := range tx.TxIn {
+		fetcher.AddPrevOut(in.PreviousOutPoint, prevOuts[i])
+	}
+
+	sigHash, err := txscript.CalcTapscriptSignaturehash(
+		txscript.NewTxSigHashes(tx, fetcher), txscript.SigHashDefault, tx, 0, fetcher, *spend.ScriptLeaf,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to calculate sighash: %w", err)
+	}
+
+	sig, err := w.sign(sigHash, false)
+	if err != nil {
+		return nil, err
+	}
+
+	return &walletcontroller.TaprootSigningResult{
+		Signature: sig,
+	}, nil
+}
+
+// sign requests signature of the sighash and verifies it against the staker
+// key, tweaked as specified by BIP86 for key path spends
+func (w *WalletController) sign(sigHash []byte, bip86Tweak bool) (*schnorr.Signature, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), w.timeout)
+	defer cancel()
+
+	sigBytes, err := w.signer.SignSchnorr(ctx, sigHash, bip86Tweak)
+	if err != nil {
+		return nil, err
+	}
+
+	sig, err := schnorr.ParseSignature(sigBytes)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidSignature, err)
+	}
+
+	key := w.pubKey
+	if bip86Tweak {
+		key = txscript.ComputeTaprootKeyNoScript(w.pubKey)
+	}
+
+	if !sig.Verify(sigHash, key) {
+		return nil, ErrInvalidSignature
+	}
+
+	return sig, nil
+}
+
+func (w *WalletController) prevOutputs(tx *wire.MsgTx) ([]*wire.TxOut, error) {
+	txHashes := make([]chainhash.Hash, len(tx.TxIn))
+	for i, in := range tx.TxIn {
+		txHashes[i] = in.PreviousOutPoint.Hash
+	}
+
+	prevTxs, err := w.Txs(txHashes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get input transactions: %w", err)
+	}
+
+	prevOuts := make([]*wire.TxOut, len(tx.TxIn))
+	for i, in := range tx.TxIn {
+		outs := prevTxs[i].MsgTx().TxOut
+		if int(in.PreviousOutPoint.Index) >= len(outs) {
+			return nil, fmt.Errorf("input %s does not exist", in.PreviousOutPoint)
+		}
+
+		prevOuts[i] = outs[in.PreviousOutPoint.Index]
+	}
+
+	return prevOuts, nil
+}
+
+// hasForeignUnsignedInputs returns true if some unsigned input does not spend
+// the staker address and must be signed by the underlying wallet
+func (w *WalletController) hasForeignUnsignedInputs(tx *wire.MsgTx, prevOuts []*wire.TxOut) bool {
+	for i, in := range tx.TxIn {
+		if len(in.Witness) == 0 && len(in.SignatureScript) == 0 && !bytes.Equal(prevOuts[i].PkScript, w.pkScript) {
+			return true
+		}
+	}
+	return false
+}
+
+func isFullySigned(tx *wire.MsgTx) bool {
+	for _, in := range tx.TxIn {
+		if len(in.Witness) == 0 && len(in.SignatureScript) == 0 {
+			return false
+		}
+	}
+	return true
+}
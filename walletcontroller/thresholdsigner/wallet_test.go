@@ -0,0 +1,208 @@
+package thresholdsigner_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/babylonlabs-io/babylon/v4/crypto/bip322"
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/stretchr/testify/require"
+
+	"github.com/babylonlabs-io/btc-staker/walletcontroller"
+	"github.com/babylonlabs-io/btc-staker/walletcontroller/thresholdsigner"
+)
+
+// newTestSigner starts threshold signing service holding privKey. If corrupt
+// is true, returned signatures are invalid.
+func newTestSigner(t *testing.T, privKey *btcec.PrivateKey, corrupt bool) *thresholdsigner.HTTPSigner {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			_ = json.NewEncoder(w).Encode(map[string]string{"error": "unauthorized"})
+			return
+		}
+
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/v1/keys/staker":
+			_ = json.NewEncoder(w).Encode(map[string][]byte{"pub_key": privKey.PubKey().SerializeCompressed()})
+		case r.Method == http.MethodPost && r.URL.Path == "/v1/keys/staker/sign-schnorr":
+			var req struct {
+				SigHash    []byte `json:"sig_hash"`
+				Bip86Tweak bool   `json:"bip86_tweak"`
+			}
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+
+			key := privKey
+			if req.Bip86Tweak {
+				key = txscript.TweakTaprootPrivKey(*privKey, nil)
+			}
+
+			sig, err := schnorr.Sign(key, req.SigHash)
+			require.NoError(t, err)
+			sigBytes := sig.Serialize()
+			if corrupt {
+				sigBytes[63] ^= 0xff
+			}
+			_ = json.NewEncoder(w).Encode(map[string][]byte{"signature": sigBytes})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	signer, err := thresholdsigner.NewHTTPSigner(&thresholdsigner.HTTPSignerConfig{
+		URL:     server.URL,
+		KeyID:   "staker",
+		Token:   "token",
+		Timeout: time.Second,
+	})
+	require.NoError(t, err)
+
+	return signer
+}
+
+// watchOnlyWallet knows transactions funding the staker address, but holds no
+// keys
+type watchOnlyWallet struct {
+	walletcontroller.WalletController
+	txs map[chainhash.Hash]*wire.MsgTx
+}
+
+func (w *watchOnlyWallet) Txs(txHashes []chainhash.Hash) ([]*btcutil.Tx, error) {
+	txs := make([]*btcutil.Tx, len(txHashes))
+	for i := range txHashes {
+		txs[i] = btcutil.NewTx(w.txs[txHashes[i]])
+	}
+	return txs, nil
+}
+
+func (w *watchOnlyWallet) SignRawTransaction(tx *wire.MsgTx) (*wire.MsgTx, bool, error) {
+	return tx, false, nil
+}
+
+func newTestWallet(t *testing.T, corrupt bool) (*thresholdsigner.WalletController, *btcec.PrivateKey, *watchOnlyWallet) {
+	privKey, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+
+	inner := &watchOnlyWallet{txs: make(map[chainhash.Hash]*wire.MsgTx)}
+	wc, err := thresholdsigner.NewWalletController(
+		context.Background(),
+		inner,
+		newTestSigner(t, privKey, corrupt),
+		&chaincfg.RegressionNetParams,
+		time.Second,
+	)
+	require.NoError(t, err)
+
+	return wc, privKey, inner
+}
+
+func executeScript(t *testing.T, tx *wire.MsgTx, prevOut *wire.TxOut) error {
+	fetcher := txscript.NewCannedPrevOutputFetcher(prevOut.PkScript, prevOut.Value)
+	engine, err := txscript.NewEngine(
+		prevOut.PkScript, tx, 0, txscript.StandardVerifyFlags, nil,
+		txscript.NewTxSigHashes(tx, fetcher), prevOut.Value, fetcher,
+	)
+	require.NoError(t, err)
+	return engine.Execute()
+}
+
+func TestThresholdSignerSignsStakerAddressInputs(t *testing.T) {
+	wc, privKey, inner := newTestWallet(t, false)
+
+	expected, err := bip322.PubKeyToP2TrSpendAddress(privKey.PubKey(), &chaincfg.RegressionNetParams)
+	require.NoError(t, err)
+	require.Equal(t, expected.EncodeAddress(), wc.Address().EncodeAddress())
+
+	pubKey, err := wc.AddressPublicKey(wc.Address())
+	require.NoError(t, err)
+	require.True(t, pubKey.IsEqual(privKey.PubKey()))
+
+	pkScript, err := txscript.PayToAddrScript(wc.Address())
+	require.NoError(t, err)
+
+	funding := wire.NewMsgTx(2)
+	funding.AddTxIn(wire.NewTxIn(&wire.OutPoint{Index: 1}, nil, nil))
+	funding.AddTxOut(wire.NewTxOut(100000, pkScript))
+	fundingHash := funding.TxHash()
+	inner.txs[fundingHash] = funding
+
+	tx := wire.NewMsgTx(2)
+	tx.AddTxIn(wire.NewTxIn(wire.NewOutPoint(&fundingHash, 0), nil, nil))
+	tx.AddTxOut(wire.NewTxOut(90000, pkScript))
+
+	signed, fullySigned, err := wc.SignRawTransaction(tx)
+	require.NoError(t, err)
+	require.True(t, fullySigned)
+	require.Empty(t, tx.TxIn[0].Witness)
+	require.NoError(t, executeScript(t, signed, funding.TxOut[0]))
+}
+
+func TestThresholdSignerSignsScriptPathSpend(t *testing.T) {
+	wc, privKey, _ := newTestWallet(t, false)
+
+	// staking scripts require signature of staker key in leaf script
+	leafScript, err := txscript.NewScriptBuilder().
+		AddData(schnorr.SerializePubKey(privKey.PubKey())).
+		AddOp(txscript.OP_CHECKSIG).
+		Script()
+	require.NoError(t, err)
+
+	leaf := txscript.NewBaseTapLeaf(leafScript)
+	tree := txscript.AssembleTaprootScriptTree(leaf)
+	internalKey, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+	controlBlock := tree.LeafMerkleProofs[0].ToControlBlock(internalKey.PubKey())
+	rootHash := tree.RootNode.TapHash()
+	outputKey := txscript.ComputeTaprootOutputKey(internalKey.PubKey(), rootHash[:])
+	stakingPkScript, err := txscript.PayToTaprootScript(outputKey)
+	require.NoError(t, err)
+
+	stakingOutput := wire.NewTxOut(100000, stakingPkScript)
+	tx := wire.NewMsgTx(2)
+	tx.AddTxIn(wire.NewTxIn(&wire.OutPoint{Index: 0}, nil, nil))
+	tx.AddTxOut(wire.NewTxOut(90000, stakingPkScript))
+
+	res, err := wc.SignOneInputTaprootSpendingTransaction(&walletcontroller.TaprootSigningRequest{
+		FundingOutput: stakingOutput,
+		TxToSign:      tx,
+		SignerAddress: wc.Address(),
+		SpendDescription: &walletcontroller.SpendPathDescription{
+			ControlBlock: &controlBlock,
+			ScriptLeaf:   &leaf,
+		},
+	})
+	require.NoError(t, err)
+	require.NotNil(t, res.Signature)
+
+	controlBlockBytes, err := controlBlock.ToBytes()
+	require.NoError(t, err)
+	tx.TxIn[0].Witness = wire.TxWitness{res.Signature.Serialize(), leafScript, controlBlockBytes}
+	require.NoError(t, executeScript(t, tx, stakingOutput))
+}
+
+func TestThresholdSignerSignsBip322Pop(t *testing.T) {
+	wc, _, _ := newTestWallet(t, false)
+
+	msg := []byte("bbn1address")
+	witness, err := wc.SignBip322Signature(msg, wc.Address())
+	require.NoError(t, err)
+	require.NoError(t, bip322.Verify(msg, witness, wc.Address(), &chaincfg.RegressionNetParams))
+}
+
+func TestThresholdSignerInvalidSignature(t *testing.T) {
+	wc, _, _ := newTestWallet(t, true)
+
+	_, err := wc.SignBip322Signature([]byte("bbn1address"), wc.Address())
+	require.ErrorIs(t, err, thresholdsigner.ErrInvalidSignature)
+}